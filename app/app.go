@@ -0,0 +1,112 @@
+package app
+
+import (
+	"net/url"
+	"sort"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/space"
+	"github.com/xchapter7x/lo"
+)
+
+// App states
+const (
+	StateStarted = "STARTED"
+	StateStopped = "STOPPED"
+)
+
+func NewManager(client CFClient, spaceMgr space.Manager, cfg config.Reader, peek bool) Manager {
+	return &DefaultManager{
+		Cfg:      cfg,
+		SpaceMgr: spaceMgr,
+		Client:   client,
+		Peek:     peek,
+	}
+}
+
+//DefaultManager -
+type DefaultManager struct {
+	Cfg      config.Reader
+	SpaceMgr space.Manager
+	Client   CFClient
+	Peek     bool
+}
+
+// Violation is an app of a managed space that breaks a policy of the
+// configuration, with a detail such as its docker image.
+type Violation struct {
+	Org    string `json:"org"`
+	Space  string `json:"space"`
+	App    string `json:"app"`
+	State  string `json:"state"`
+	Detail string `json:"detail"`
+	GUID   string `json:"guid"`
+}
+
+type managedApp struct {
+	app   cfclient.App
+	space space.ManagedSpace
+}
+
+// managedApps lists the apps of every existing space of the managed orgs
+func (m *DefaultManager) managedApps() ([]managedApp, error) {
+	spaces, err := m.SpaceMgr.ListManagedSpaces()
+	if err != nil {
+		return nil, err
+	}
+	spacesByGUID := make(map[string]space.ManagedSpace)
+	var orgGUIDs []string
+	for _, managed := range spaces {
+		if !containsGUID(orgGUIDs, managed.Space.OrganizationGuid) {
+			orgGUIDs = append(orgGUIDs, managed.Space.OrganizationGuid)
+		}
+		spacesByGUID[managed.Space.Guid] = managed
+	}
+	var apps []managedApp
+	for _, orgGUID := range orgGUIDs {
+		orgApps, err := m.Client.ListAppsByQuery(url.Values{"q": []string{"organization_guid:" + orgGUID}})
+		if err != nil {
+			return nil, err
+		}
+		for _, app := range orgApps {
+			if managed, ok := spacesByGUID[app.SpaceGuid]; ok {
+				apps = append(apps, managedApp{app: app, space: managed})
+			}
+		}
+	}
+	return apps, nil
+}
+
+func containsGUID(guids []string, guid string) bool {
+	for _, g := range guids {
+		if g == guid {
+			return true
+		}
+	}
+	return false
+}
+
+func sortViolations(violations []Violation) {
+	sort.Slice(violations, func(i, j int) bool {
+		a, b := violations[i], violations[j]
+		if a.Org != b.Org {
+			return a.Org < b.Org
+		}
+		if a.Space != b.Space {
+			return a.Space < b.Space
+		}
+		return a.App < b.App
+	})
+}
+
+//StopApp - stops a started app that breaks a policy
+func (m *DefaultManager) StopApp(violation Violation, reason string) error {
+	if m.Peek {
+		lo.G.Infof("[dry-run]: stop %s app %s of space %s in org %s", reason, violation.App, violation.Space, violation.Org)
+		return nil
+	}
+	lo.G.Infof("Stop %s app %s of space %s in org %s", reason, violation.App, violation.Space, violation.Org)
+	_, err := m.Client.UpdateApp(violation.GUID, cfclient.AppUpdateResource{State: StateStopped})
+	return err
+}
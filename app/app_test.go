@@ -0,0 +1,209 @@
+package app_test
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/app"
+	appfakes "github.com/pivotalservices/cf-mgmt/app/fakes"
+	"github.com/pivotalservices/cf-mgmt/config"
+	configfakes "github.com/pivotalservices/cf-mgmt/config/fakes"
+	"github.com/pivotalservices/cf-mgmt/space"
+	spacefakes "github.com/pivotalservices/cf-mgmt/space/fakes"
+)
+
+var _ = Describe("given App Manager", func() {
+	var (
+		fakeReader   *configfakes.FakeReader
+		fakeClient   *appfakes.FakeCFClient
+		fakeSpaceMgr *spacefakes.FakeManager
+		appMgr       *app.DefaultManager
+	)
+
+	BeforeEach(func() {
+		fakeReader = new(configfakes.FakeReader)
+		fakeClient = new(appfakes.FakeCFClient)
+		fakeSpaceMgr = new(spacefakes.FakeManager)
+		appMgr = &app.DefaultManager{
+			Cfg:      fakeReader,
+			Client:   fakeClient,
+			SpaceMgr: fakeSpaceMgr,
+		}
+		fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{}, nil)
+		fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+			config.OrgConfig{Org: "org1"},
+			config.OrgConfig{Org: "org2", AllowDocker: true},
+		}, nil)
+		fakeSpaceMgr.ListManagedSpacesReturns([]space.ManagedSpace{
+			space.ManagedSpace{Org: "org1", Space: cfclient.Space{Name: "docker", Guid: "docker-guid", OrganizationGuid: "org1-guid"}, Config: &config.SpaceConfig{AllowDocker: true}},
+			space.ManagedSpace{Org: "org1", Space: cfclient.Space{Name: "web", Guid: "web-guid", OrganizationGuid: "org1-guid"}, Config: &config.SpaceConfig{}},
+			space.ManagedSpace{Org: "org1", Space: cfclient.Space{Name: "scratch", Guid: "scratch-guid", OrganizationGuid: "org1-guid"}},
+			space.ManagedSpace{Org: "org2", Space: cfclient.Space{Name: "any", Guid: "any-guid", OrganizationGuid: "org2-guid"}},
+		}, nil)
+		fakeClient.ListAppsByQueryStub = func(query url.Values) ([]cfclient.App, error) {
+			if query.Get("q") == "organization_guid:org2-guid" {
+				return []cfclient.App{
+					cfclient.App{Guid: "app5", Name: "allowed-by-org", SpaceGuid: "any-guid", DockerImage: "nginx", State: app.StateStarted},
+				}, nil
+			}
+			return []cfclient.App{
+				cfclient.App{Guid: "app1", Name: "allowed-by-space", SpaceGuid: "docker-guid", DockerImage: "redis", State: app.StateStarted},
+				cfclient.App{Guid: "app2", Name: "buildpack", SpaceGuid: "web-guid", State: app.StateStarted},
+				cfclient.App{Guid: "app3", Name: "web-docker", SpaceGuid: "web-guid", DockerImage: "busybox", State: app.StateStarted},
+				cfclient.App{Guid: "app4", Name: "stopped", SpaceGuid: "scratch-guid", DockerImage: "alpine", State: app.StateStopped},
+			}, nil
+		}
+	})
+
+	Context("DockerViolations", func() {
+		It("reports docker apps of spaces whose org and space do not allow docker", func() {
+			violations, err := appMgr.DockerViolations()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(violations).Should(Equal([]app.Violation{
+				app.Violation{Org: "org1", Space: "scratch", App: "stopped", State: app.StateStopped, Detail: "alpine", GUID: "app4"},
+				app.Violation{Org: "org1", Space: "web", App: "web-docker", State: app.StateStarted, Detail: "busybox", GUID: "app3"},
+			}))
+			Expect(fakeClient.ListAppsByQueryCallCount()).Should(Equal(2))
+		})
+
+		It("errors listing apps", func() {
+			fakeClient.ListAppsByQueryStub = nil
+			fakeClient.ListAppsByQueryReturns(nil, errors.New("error"))
+			_, err := appMgr.DockerViolations()
+			Expect(err).Should(MatchError("error"))
+		})
+	})
+
+	Context("EnforceDockerPolicy", func() {
+		It("only reports violations unless enforced", func() {
+			Expect(appMgr.EnforceDockerPolicy()).Should(Succeed())
+			Expect(fakeClient.UpdateAppCallCount()).Should(Equal(0))
+		})
+
+		It("stops the started apps of violations when enforced", func() {
+			fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{EnforceDockerPolicy: true}, nil)
+			Expect(appMgr.EnforceDockerPolicy()).Should(Succeed())
+			Expect(fakeClient.UpdateAppCallCount()).Should(Equal(1))
+			guid, update := fakeClient.UpdateAppArgsForCall(0)
+			Expect(guid).Should(Equal("app3"))
+			Expect(update).Should(Equal(cfclient.AppUpdateResource{State: app.StateStopped}))
+		})
+
+		It("does not stop apps in peek mode", func() {
+			appMgr.Peek = true
+			fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{EnforceDockerPolicy: true}, nil)
+			Expect(appMgr.EnforceDockerPolicy()).Should(Succeed())
+			Expect(fakeClient.UpdateAppCallCount()).Should(Equal(0))
+		})
+	})
+
+	Context("Stack policy", func() {
+		BeforeEach(func() {
+			fakeClient.ListStacksReturns([]cfclient.Stack{
+				cfclient.Stack{Guid: "fs3-guid", Name: "cflinuxfs3"},
+				cfclient.Stack{Guid: "fs4-guid", Name: "cflinuxfs4"},
+			}, nil)
+			fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+				config.OrgConfig{Org: "org1", DefaultStack: "cflinuxfs4"},
+				config.OrgConfig{Org: "org2"},
+			}, nil)
+			fakeClient.ListAppsByQueryStub = func(query url.Values) ([]cfclient.App, error) {
+				if query.Get("q") == "organization_guid:org2-guid" {
+					return []cfclient.App{
+						cfclient.App{Guid: "app3", Name: "no-policy", SpaceGuid: "any-guid", StackGuid: "fs3-guid", State: app.StateStarted},
+					}, nil
+				}
+				return []cfclient.App{
+					cfclient.App{Guid: "app1", Name: "current", SpaceGuid: "web-guid", StackGuid: "fs4-guid", State: app.StateStarted},
+					cfclient.App{Guid: "app2", Name: "deprecated", SpaceGuid: "web-guid", StackGuid: "fs3-guid", State: app.StateStarted},
+				}, nil
+			}
+		})
+
+		It("reports apps on stacks their org does not allow", func() {
+			violations, err := appMgr.StackViolations()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(violations).Should(Equal([]app.Violation{
+				app.Violation{Org: "org1", Space: "web", App: "deprecated", State: app.StateStarted, Detail: "cflinuxfs3", GUID: "app2"},
+			}))
+		})
+
+		It("does not list apps without a stack policy", func() {
+			fakeReader.GetOrgConfigsReturns([]config.OrgConfig{config.OrgConfig{Org: "org1"}}, nil)
+			violations, err := appMgr.StackViolations()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(violations).Should(BeEmpty())
+			Expect(fakeClient.ListAppsByQueryCallCount()).Should(Equal(0))
+		})
+
+		It("errors for a configured stack the foundation does not have", func() {
+			fakeReader.GetOrgConfigsReturns([]config.OrgConfig{config.OrgConfig{Org: "org1", AllowedStacks: []string{"cflinuxfs5"}}}, nil)
+			_, err := appMgr.StackViolations()
+			Expect(err).Should(MatchError("stack cflinuxfs5 of org org1 does not exist"))
+		})
+
+		It("only warns unless enforced", func() {
+			Expect(appMgr.EnforceStackPolicy()).Should(Succeed())
+			Expect(fakeClient.UpdateAppCallCount()).Should(Equal(0))
+		})
+
+		It("fails when enforced", func() {
+			fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{EnforceStackPolicy: true}, nil)
+			Expect(appMgr.EnforceStackPolicy()).Should(MatchError("1 apps run on stacks their org does not allow"))
+			Expect(fakeClient.UpdateAppCallCount()).Should(Equal(0))
+		})
+	})
+
+	Context("TaskUsage", func() {
+		var since, until time.Time
+		at := func(minute int) time.Time {
+			return time.Date(2026, 10, 1, 10, minute, 0, 0, time.UTC)
+		}
+
+		BeforeEach(func() {
+			since, until = at(0), at(60)
+			fakeClient.ListTasksByQueryStub = func(query url.Values) ([]cfclient.Task, error) {
+				switch query.Get("space_guids") {
+				case "web-guid":
+					return []cfclient.Task{
+						cfclient.Task{State: "RUNNING", MemoryInMb: 512, CreatedAt: at(50)},
+						cfclient.Task{State: app.TaskFailed, MemoryInMb: 256, CreatedAt: at(5), UpdatedAt: at(6)},
+						cfclient.Task{State: app.TaskSucceeded, MemoryInMb: 256, CreatedAt: at(0), UpdatedAt: at(10)},
+						cfclient.Task{State: app.TaskSucceeded, MemoryInMb: 1024, CreatedAt: at(-10), UpdatedAt: at(-5)},
+					}, nil
+				case "docker-guid":
+					return []cfclient.Task{
+						cfclient.Task{State: app.TaskSucceeded, MemoryInMb: 128, CreatedAt: at(5), UpdatedAt: at(16)},
+					}, nil
+				}
+				return nil, nil
+			}
+		})
+
+		It("summarizes the tasks of the period by org and space", func() {
+			usage, err := appMgr.TaskUsage(since, until)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(usage).Should(Equal([]app.TaskUsage{
+				app.TaskUsage{Org: "org1", Tasks: 4, Succeeded: 2, Failed: 1, Running: 1, MaxConcurrent: 3, MemoryMBSeconds: 256*600 + 256*60 + 512*600 + 128*660},
+				app.TaskUsage{Org: "org1", Space: "docker", Tasks: 1, Succeeded: 1, MaxConcurrent: 1, MemoryMBSeconds: 128 * 660},
+				app.TaskUsage{Org: "org1", Space: "scratch"},
+				app.TaskUsage{Org: "org1", Space: "web", Tasks: 3, Succeeded: 1, Failed: 1, Running: 1, MaxConcurrent: 2, MemoryMBSeconds: 256*600 + 256*60 + 512*600},
+				app.TaskUsage{Org: "org2"},
+				app.TaskUsage{Org: "org2", Space: "any"},
+			}))
+			query := fakeClient.ListTasksByQueryArgsForCall(0)
+			Expect(query.Get("order_by")).Should(Equal("-created_at"))
+		})
+
+		It("errors listing tasks", func() {
+			fakeClient.ListTasksByQueryStub = nil
+			fakeClient.ListTasksByQueryReturns(nil, errors.New("error"))
+			_, err := appMgr.TaskUsage(since, until)
+			Expect(err).Should(MatchError("error"))
+		})
+	})
+})
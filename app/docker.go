@@ -0,0 +1,61 @@
+package app
+
+import (
+	"github.com/xchapter7x/lo"
+)
+
+//DockerViolations - lists the docker apps of the spaces of managed orgs that are not allowed to run them, as
+//neither their org nor their space sets allow-docker, including the spaces that are not in the configuration
+func (m *DefaultManager) DockerViolations() ([]Violation, error) {
+	orgConfigs, err := m.Cfg.GetOrgConfigs()
+	if err != nil {
+		return nil, err
+	}
+	dockerOrgs := make(map[string]bool)
+	for _, orgConfig := range orgConfigs {
+		dockerOrgs[orgConfig.Org] = orgConfig.AllowDocker
+	}
+	apps, err := m.managedApps()
+	if err != nil {
+		return nil, err
+	}
+	violations := []Violation{}
+	for _, managed := range apps {
+		if managed.app.DockerImage == "" || dockerOrgs[managed.space.Org] || (managed.space.Config != nil && managed.space.Config.AllowDocker) {
+			continue
+		}
+		violations = append(violations, Violation{
+			Org:    managed.space.Org,
+			Space:  managed.space.Space.Name,
+			App:    managed.app.Name,
+			State:  managed.app.State,
+			Detail: managed.app.DockerImage,
+			GUID:   managed.app.Guid,
+		})
+	}
+	sortViolations(violations)
+	return violations, nil
+}
+
+//EnforceDockerPolicy - stops the started apps of the docker violations when enforce-docker-policy is set in
+//cf-mgmt.yml, otherwise they are only logged
+func (m *DefaultManager) EnforceDockerPolicy() error {
+	globalConfig, err := m.Cfg.GetGlobalConfig()
+	if err != nil {
+		return err
+	}
+	violations, err := m.DockerViolations()
+	if err != nil {
+		return err
+	}
+	for _, violation := range violations {
+		if !globalConfig.EnforceDockerPolicy || violation.State != StateStarted {
+			lo.G.Warningf("Docker app %s (%s) of space %s in org %s is not allowed, set allow-docker: true in the orgConfig.yml or spaceConfig.yml or enforce-docker-policy: true in cf-mgmt.yml to stop it", violation.App, violation.Detail, violation.Space, violation.Org)
+			continue
+		}
+		if err := m.StopApp(violation, "docker"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"net/url"
+	"sync"
+
+	go_cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pivotalservices/cf-mgmt/app"
+)
+
+type FakeCFClient struct {
+	ListAppsByQueryStub        func(query url.Values) ([]go_cfclient.App, error)
+	listAppsByQueryMutex       sync.RWMutex
+	listAppsByQueryArgsForCall []struct {
+		query url.Values
+	}
+	listAppsByQueryReturns struct {
+		result1 []go_cfclient.App
+		result2 error
+	}
+	UpdateAppStub        func(guid string, aur go_cfclient.AppUpdateResource) (go_cfclient.UpdateResponse, error)
+	updateAppMutex       sync.RWMutex
+	updateAppArgsForCall []struct {
+		guid string
+		aur  go_cfclient.AppUpdateResource
+	}
+	updateAppReturns struct {
+		result1 go_cfclient.UpdateResponse
+		result2 error
+	}
+	ListStacksStub        func() ([]go_cfclient.Stack, error)
+	listStacksMutex       sync.RWMutex
+	listStacksArgsForCall []struct{}
+	listStacksReturns     struct {
+		result1 []go_cfclient.Stack
+		result2 error
+	}
+	ListTasksByQueryStub        func(query url.Values) ([]go_cfclient.Task, error)
+	listTasksByQueryMutex       sync.RWMutex
+	listTasksByQueryArgsForCall []struct {
+		query url.Values
+	}
+	listTasksByQueryReturns struct {
+		result1 []go_cfclient.Task
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeCFClient) ListAppsByQuery(query url.Values) ([]go_cfclient.App, error) {
+	fake.listAppsByQueryMutex.Lock()
+	fake.listAppsByQueryArgsForCall = append(fake.listAppsByQueryArgsForCall, struct {
+		query url.Values
+	}{query})
+	fake.recordInvocation("ListAppsByQuery", []interface{}{query})
+	fake.listAppsByQueryMutex.Unlock()
+	if fake.ListAppsByQueryStub != nil {
+		return fake.ListAppsByQueryStub(query)
+	} else {
+		return fake.listAppsByQueryReturns.result1, fake.listAppsByQueryReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) ListAppsByQueryCallCount() int {
+	fake.listAppsByQueryMutex.RLock()
+	defer fake.listAppsByQueryMutex.RUnlock()
+	return len(fake.listAppsByQueryArgsForCall)
+}
+
+func (fake *FakeCFClient) ListAppsByQueryArgsForCall(i int) url.Values {
+	fake.listAppsByQueryMutex.RLock()
+	defer fake.listAppsByQueryMutex.RUnlock()
+	return fake.listAppsByQueryArgsForCall[i].query
+}
+
+func (fake *FakeCFClient) ListAppsByQueryReturns(result1 []go_cfclient.App, result2 error) {
+	fake.ListAppsByQueryStub = nil
+	fake.listAppsByQueryReturns = struct {
+		result1 []go_cfclient.App
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) UpdateApp(guid string, aur go_cfclient.AppUpdateResource) (go_cfclient.UpdateResponse, error) {
+	fake.updateAppMutex.Lock()
+	fake.updateAppArgsForCall = append(fake.updateAppArgsForCall, struct {
+		guid string
+		aur  go_cfclient.AppUpdateResource
+	}{guid, aur})
+	fake.recordInvocation("UpdateApp", []interface{}{guid, aur})
+	fake.updateAppMutex.Unlock()
+	if fake.UpdateAppStub != nil {
+		return fake.UpdateAppStub(guid, aur)
+	} else {
+		return fake.updateAppReturns.result1, fake.updateAppReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) UpdateAppCallCount() int {
+	fake.updateAppMutex.RLock()
+	defer fake.updateAppMutex.RUnlock()
+	return len(fake.updateAppArgsForCall)
+}
+
+func (fake *FakeCFClient) UpdateAppArgsForCall(i int) (string, go_cfclient.AppUpdateResource) {
+	fake.updateAppMutex.RLock()
+	defer fake.updateAppMutex.RUnlock()
+	return fake.updateAppArgsForCall[i].guid, fake.updateAppArgsForCall[i].aur
+}
+
+func (fake *FakeCFClient) UpdateAppReturns(result1 go_cfclient.UpdateResponse, result2 error) {
+	fake.UpdateAppStub = nil
+	fake.updateAppReturns = struct {
+		result1 go_cfclient.UpdateResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) ListStacks() ([]go_cfclient.Stack, error) {
+	fake.listStacksMutex.Lock()
+	fake.listStacksArgsForCall = append(fake.listStacksArgsForCall, struct{}{})
+	fake.recordInvocation("ListStacks", []interface{}{})
+	fake.listStacksMutex.Unlock()
+	if fake.ListStacksStub != nil {
+		return fake.ListStacksStub()
+	} else {
+		return fake.listStacksReturns.result1, fake.listStacksReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) ListStacksCallCount() int {
+	fake.listStacksMutex.RLock()
+	defer fake.listStacksMutex.RUnlock()
+	return len(fake.listStacksArgsForCall)
+}
+
+func (fake *FakeCFClient) ListStacksReturns(result1 []go_cfclient.Stack, result2 error) {
+	fake.ListStacksStub = nil
+	fake.listStacksReturns = struct {
+		result1 []go_cfclient.Stack
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) ListTasksByQuery(query url.Values) ([]go_cfclient.Task, error) {
+	fake.listTasksByQueryMutex.Lock()
+	fake.listTasksByQueryArgsForCall = append(fake.listTasksByQueryArgsForCall, struct {
+		query url.Values
+	}{query})
+	fake.recordInvocation("ListTasksByQuery", []interface{}{query})
+	fake.listTasksByQueryMutex.Unlock()
+	if fake.ListTasksByQueryStub != nil {
+		return fake.ListTasksByQueryStub(query)
+	} else {
+		return fake.listTasksByQueryReturns.result1, fake.listTasksByQueryReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) ListTasksByQueryCallCount() int {
+	fake.listTasksByQueryMutex.RLock()
+	defer fake.listTasksByQueryMutex.RUnlock()
+	return len(fake.listTasksByQueryArgsForCall)
+}
+
+func (fake *FakeCFClient) ListTasksByQueryArgsForCall(i int) url.Values {
+	fake.listTasksByQueryMutex.RLock()
+	defer fake.listTasksByQueryMutex.RUnlock()
+	return fake.listTasksByQueryArgsForCall[i].query
+}
+
+func (fake *FakeCFClient) ListTasksByQueryReturns(result1 []go_cfclient.Task, result2 error) {
+	fake.ListTasksByQueryStub = nil
+	fake.listTasksByQueryReturns = struct {
+		result1 []go_cfclient.Task
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.listAppsByQueryMutex.RLock()
+	defer fake.listAppsByQueryMutex.RUnlock()
+	fake.updateAppMutex.RLock()
+	defer fake.updateAppMutex.RUnlock()
+	fake.listStacksMutex.RLock()
+	defer fake.listStacksMutex.RUnlock()
+	fake.listTasksByQueryMutex.RLock()
+	defer fake.listTasksByQueryMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeCFClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ app.CFClient = new(FakeCFClient)
@@ -0,0 +1,217 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pivotalservices/cf-mgmt/app"
+)
+
+type FakeManager struct {
+	DockerViolationsStub        func() ([]app.Violation, error)
+	dockerViolationsMutex       sync.RWMutex
+	dockerViolationsArgsForCall []struct{}
+	dockerViolationsReturns     struct {
+		result1 []app.Violation
+		result2 error
+	}
+	EnforceDockerPolicyStub        func() error
+	enforceDockerPolicyMutex       sync.RWMutex
+	enforceDockerPolicyArgsForCall []struct{}
+	enforceDockerPolicyReturns     struct {
+		result1 error
+	}
+	StackViolationsStub        func() ([]app.Violation, error)
+	stackViolationsMutex       sync.RWMutex
+	stackViolationsArgsForCall []struct{}
+	stackViolationsReturns     struct {
+		result1 []app.Violation
+		result2 error
+	}
+	EnforceStackPolicyStub        func() error
+	enforceStackPolicyMutex       sync.RWMutex
+	enforceStackPolicyArgsForCall []struct{}
+	enforceStackPolicyReturns     struct {
+		result1 error
+	}
+	TaskUsageStub        func(since, until time.Time) ([]app.TaskUsage, error)
+	taskUsageMutex       sync.RWMutex
+	taskUsageArgsForCall []struct {
+		since time.Time
+		until time.Time
+	}
+	taskUsageReturns struct {
+		result1 []app.TaskUsage
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeManager) DockerViolations() ([]app.Violation, error) {
+	fake.dockerViolationsMutex.Lock()
+	fake.dockerViolationsArgsForCall = append(fake.dockerViolationsArgsForCall, struct{}{})
+	fake.recordInvocation("DockerViolations", []interface{}{})
+	fake.dockerViolationsMutex.Unlock()
+	if fake.DockerViolationsStub != nil {
+		return fake.DockerViolationsStub()
+	} else {
+		return fake.dockerViolationsReturns.result1, fake.dockerViolationsReturns.result2
+	}
+}
+
+func (fake *FakeManager) DockerViolationsCallCount() int {
+	fake.dockerViolationsMutex.RLock()
+	defer fake.dockerViolationsMutex.RUnlock()
+	return len(fake.dockerViolationsArgsForCall)
+}
+
+func (fake *FakeManager) DockerViolationsReturns(result1 []app.Violation, result2 error) {
+	fake.DockerViolationsStub = nil
+	fake.dockerViolationsReturns = struct {
+		result1 []app.Violation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) EnforceDockerPolicy() error {
+	fake.enforceDockerPolicyMutex.Lock()
+	fake.enforceDockerPolicyArgsForCall = append(fake.enforceDockerPolicyArgsForCall, struct{}{})
+	fake.recordInvocation("EnforceDockerPolicy", []interface{}{})
+	fake.enforceDockerPolicyMutex.Unlock()
+	if fake.EnforceDockerPolicyStub != nil {
+		return fake.EnforceDockerPolicyStub()
+	} else {
+		return fake.enforceDockerPolicyReturns.result1
+	}
+}
+
+func (fake *FakeManager) EnforceDockerPolicyCallCount() int {
+	fake.enforceDockerPolicyMutex.RLock()
+	defer fake.enforceDockerPolicyMutex.RUnlock()
+	return len(fake.enforceDockerPolicyArgsForCall)
+}
+
+func (fake *FakeManager) EnforceDockerPolicyReturns(result1 error) {
+	fake.EnforceDockerPolicyStub = nil
+	fake.enforceDockerPolicyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) StackViolations() ([]app.Violation, error) {
+	fake.stackViolationsMutex.Lock()
+	fake.stackViolationsArgsForCall = append(fake.stackViolationsArgsForCall, struct{}{})
+	fake.recordInvocation("StackViolations", []interface{}{})
+	fake.stackViolationsMutex.Unlock()
+	if fake.StackViolationsStub != nil {
+		return fake.StackViolationsStub()
+	} else {
+		return fake.stackViolationsReturns.result1, fake.stackViolationsReturns.result2
+	}
+}
+
+func (fake *FakeManager) StackViolationsCallCount() int {
+	fake.stackViolationsMutex.RLock()
+	defer fake.stackViolationsMutex.RUnlock()
+	return len(fake.stackViolationsArgsForCall)
+}
+
+func (fake *FakeManager) StackViolationsReturns(result1 []app.Violation, result2 error) {
+	fake.StackViolationsStub = nil
+	fake.stackViolationsReturns = struct {
+		result1 []app.Violation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) EnforceStackPolicy() error {
+	fake.enforceStackPolicyMutex.Lock()
+	fake.enforceStackPolicyArgsForCall = append(fake.enforceStackPolicyArgsForCall, struct{}{})
+	fake.recordInvocation("EnforceStackPolicy", []interface{}{})
+	fake.enforceStackPolicyMutex.Unlock()
+	if fake.EnforceStackPolicyStub != nil {
+		return fake.EnforceStackPolicyStub()
+	} else {
+		return fake.enforceStackPolicyReturns.result1
+	}
+}
+
+func (fake *FakeManager) EnforceStackPolicyCallCount() int {
+	fake.enforceStackPolicyMutex.RLock()
+	defer fake.enforceStackPolicyMutex.RUnlock()
+	return len(fake.enforceStackPolicyArgsForCall)
+}
+
+func (fake *FakeManager) EnforceStackPolicyReturns(result1 error) {
+	fake.EnforceStackPolicyStub = nil
+	fake.enforceStackPolicyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) TaskUsage(since time.Time, until time.Time) ([]app.TaskUsage, error) {
+	fake.taskUsageMutex.Lock()
+	fake.taskUsageArgsForCall = append(fake.taskUsageArgsForCall, struct {
+		since time.Time
+		until time.Time
+	}{since, until})
+	fake.recordInvocation("TaskUsage", []interface{}{since, until})
+	fake.taskUsageMutex.Unlock()
+	if fake.TaskUsageStub != nil {
+		return fake.TaskUsageStub(since, until)
+	} else {
+		return fake.taskUsageReturns.result1, fake.taskUsageReturns.result2
+	}
+}
+
+func (fake *FakeManager) TaskUsageCallCount() int {
+	fake.taskUsageMutex.RLock()
+	defer fake.taskUsageMutex.RUnlock()
+	return len(fake.taskUsageArgsForCall)
+}
+
+func (fake *FakeManager) TaskUsageArgsForCall(i int) (time.Time, time.Time) {
+	fake.taskUsageMutex.RLock()
+	defer fake.taskUsageMutex.RUnlock()
+	return fake.taskUsageArgsForCall[i].since, fake.taskUsageArgsForCall[i].until
+}
+
+func (fake *FakeManager) TaskUsageReturns(result1 []app.TaskUsage, result2 error) {
+	fake.TaskUsageStub = nil
+	fake.taskUsageReturns = struct {
+		result1 []app.TaskUsage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.dockerViolationsMutex.RLock()
+	defer fake.dockerViolationsMutex.RUnlock()
+	fake.enforceDockerPolicyMutex.RLock()
+	defer fake.enforceDockerPolicyMutex.RUnlock()
+	fake.stackViolationsMutex.RLock()
+	defer fake.stackViolationsMutex.RUnlock()
+	fake.enforceStackPolicyMutex.RLock()
+	defer fake.enforceStackPolicyMutex.RUnlock()
+	fake.taskUsageMutex.RLock()
+	defer fake.taskUsageMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeManager) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ app.Manager = new(FakeManager)
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/xchapter7x/lo"
+)
+
+// stackPolicies are the org configs with a default-stack or allowed-stacks,
+// by org name, after checking that the foundation has each of their stacks
+func (m *DefaultManager) stackPolicies(stackNames map[string]string) (map[string]config.OrgConfig, error) {
+	orgConfigs, err := m.Cfg.GetOrgConfigs()
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool)
+	for _, name := range stackNames {
+		existing[name] = true
+	}
+	policies := make(map[string]config.OrgConfig)
+	for _, orgConfig := range orgConfigs {
+		for _, stack := range orgConfig.Stacks() {
+			if !existing[stack] {
+				return nil, fmt.Errorf("stack %s of org %s does not exist", stack, orgConfig.Org)
+			}
+		}
+		if len(orgConfig.Stacks()) > 0 {
+			policies[orgConfig.Org] = orgConfig
+		}
+	}
+	return policies, nil
+}
+
+func (m *DefaultManager) stackNames() (map[string]string, error) {
+	stacks, err := m.Client.ListStacks()
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string)
+	for _, stack := range stacks {
+		names[stack.Guid] = stack.Name
+	}
+	return names, nil
+}
+
+//StackViolations - lists the apps of the spaces of managed orgs that run on a stack their org does not allow,
+//erroring when a default-stack or allowed-stacks does not exist on the foundation
+func (m *DefaultManager) StackViolations() ([]Violation, error) {
+	violations, _, err := m.stackViolations()
+	return violations, err
+}
+
+func (m *DefaultManager) stackViolations() ([]Violation, map[string]config.OrgConfig, error) {
+	stackNames, err := m.stackNames()
+	if err != nil {
+		return nil, nil, err
+	}
+	policies, err := m.stackPolicies(stackNames)
+	if err != nil {
+		return nil, nil, err
+	}
+	violations := []Violation{}
+	if len(policies) == 0 {
+		return violations, policies, nil
+	}
+	apps, err := m.managedApps()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, managed := range apps {
+		policy, ok := policies[managed.space.Org]
+		if !ok {
+			continue
+		}
+		stack, ok := stackNames[managed.app.StackGuid]
+		if !ok {
+			stack = managed.app.StackGuid
+		}
+		if policy.StackAllowed(stack) {
+			continue
+		}
+		violations = append(violations, Violation{
+			Org:    managed.space.Org,
+			Space:  managed.space.Space.Name,
+			App:    managed.app.Name,
+			State:  managed.app.State,
+			Detail: stack,
+			GUID:   managed.app.Guid,
+		})
+	}
+	sortViolations(violations)
+	return violations, policies, nil
+}
+
+//EnforceStackPolicy - logs the stack violations and fails when enforce-stack-policy is set in cf-mgmt.yml, so
+//that a pipeline does not silently keep apps on stacks that are being retired
+func (m *DefaultManager) EnforceStackPolicy() error {
+	globalConfig, err := m.Cfg.GetGlobalConfig()
+	if err != nil {
+		return err
+	}
+	violations, policies, err := m.stackViolations()
+	if err != nil {
+		return err
+	}
+	for _, violation := range violations {
+		policy := policies[violation.Org]
+		target := policy.DefaultStack
+		if target == "" {
+			target = policy.Stacks()[0]
+		}
+		lo.G.Warningf("App %s of space %s in org %s runs on stack %s, which is not one of the stacks %v the org allows, restage it on %s", violation.App, violation.Space, violation.Org, violation.Detail, policy.Stacks(), target)
+	}
+	if globalConfig.EnforceStackPolicy && len(violations) > 0 {
+		return fmt.Errorf("%d apps run on stacks their org does not allow", len(violations))
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package app
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pivotalservices/cf-mgmt/space"
+	"github.com/xchapter7x/lo"
+)
+
+// Task states
+const (
+	TaskSucceeded = "SUCCEEDED"
+	TaskFailed    = "FAILED"
+)
+
+// tasksPerPage is the most tasks the cloud controller returns at once, the
+// client does not follow further pages
+const tasksPerPage = 5000
+
+// TaskUsage summarizes the tasks run in a space, or in every space of an org
+// when Space is empty, over a period. MaxConcurrent is the most tasks that
+// ran at the same time, which is what app_task_limit of a quota caps.
+type TaskUsage struct {
+	Org             string `json:"org"`
+	Space           string `json:"space,omitempty"`
+	Tasks           int    `json:"tasks"`
+	Succeeded       int    `json:"succeeded"`
+	Failed          int    `json:"failed"`
+	Running         int    `json:"running"`
+	MaxConcurrent   int    `json:"max_concurrent"`
+	MemoryMBSeconds int64  `json:"memory_mb_seconds"`
+}
+
+type taskRun struct {
+	start, end time.Time
+}
+
+//TaskUsage - summarizes the tasks created between since and until in every existing space of the managed orgs,
+//with a row for each org followed by the rows of its spaces. Tasks still running are counted up to until.
+func (m *DefaultManager) TaskUsage(since, until time.Time) ([]TaskUsage, error) {
+	spaces, err := m.SpaceMgr.ListManagedSpaces()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(spaces, func(i, j int) bool {
+		if spaces[i].Org != spaces[j].Org {
+			return spaces[i].Org < spaces[j].Org
+		}
+		return spaces[i].Space.Name < spaces[j].Space.Name
+	})
+	usages := []TaskUsage{}
+	for i := 0; i < len(spaces); {
+		orgUsage := TaskUsage{Org: spaces[i].Org}
+		var spaceUsages []TaskUsage
+		var orgRuns []taskRun
+		for ; i < len(spaces) && spaces[i].Org == orgUsage.Org; i++ {
+			usage, runs, err := m.spaceTaskUsage(spaces[i], since, until)
+			if err != nil {
+				return nil, err
+			}
+			orgUsage.Tasks += usage.Tasks
+			orgUsage.Succeeded += usage.Succeeded
+			orgUsage.Failed += usage.Failed
+			orgUsage.Running += usage.Running
+			orgUsage.MemoryMBSeconds += usage.MemoryMBSeconds
+			orgRuns = append(orgRuns, runs...)
+			spaceUsages = append(spaceUsages, usage)
+		}
+		orgUsage.MaxConcurrent = maxConcurrent(orgRuns)
+		usages = append(usages, orgUsage)
+		usages = append(usages, spaceUsages...)
+	}
+	return usages, nil
+}
+
+func (m *DefaultManager) spaceTaskUsage(managed space.ManagedSpace, since, until time.Time) (TaskUsage, []taskRun, error) {
+	usage := TaskUsage{Org: managed.Org, Space: managed.Space.Name}
+	tasks, err := m.Client.ListTasksByQuery(url.Values{
+		"space_guids": []string{managed.Space.Guid},
+		"order_by":    []string{"-created_at"},
+		"per_page":    []string{strconv.Itoa(tasksPerPage)},
+	})
+	if err != nil {
+		return usage, nil, err
+	}
+	if len(tasks) >= tasksPerPage {
+		lo.G.Warningf("Space %s in org %s has more than %d tasks, only the latest are summarized", managed.Space.Name, managed.Org, tasksPerPage)
+	}
+	var runs []taskRun
+	for _, task := range tasks {
+		if task.CreatedAt.Before(since) || task.CreatedAt.After(until) {
+			continue
+		}
+		run := taskRun{start: task.CreatedAt, end: until}
+		switch task.State {
+		case TaskSucceeded:
+			usage.Succeeded++
+			run.end = task.UpdatedAt
+		case TaskFailed:
+			usage.Failed++
+			run.end = task.UpdatedAt
+		default:
+			usage.Running++
+		}
+		usage.Tasks++
+		usage.MemoryMBSeconds += int64(task.MemoryInMb) * int64(run.end.Sub(run.start)/time.Second)
+		runs = append(runs, run)
+	}
+	usage.MaxConcurrent = maxConcurrent(runs)
+	return usage, runs, nil
+}
+
+// maxConcurrent is the most runs that overlap, a run ending when another
+// starts not overlapping it
+func maxConcurrent(runs []taskRun) int {
+	type event struct {
+		at    time.Time
+		delta int
+	}
+	var events []event
+	for _, run := range runs {
+		events = append(events, event{run.start, 1}, event{run.end, -1})
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].at.Equal(events[j].at) {
+			return events[i].at.Before(events[j].at)
+		}
+		return events[i].delta < events[j].delta
+	})
+	running, max := 0, 0
+	for _, e := range events {
+		running += e.delta
+		if running > max {
+			max = running
+		}
+	}
+	return max
+}
@@ -0,0 +1,24 @@
+package app
+
+import (
+	"net/url"
+	"time"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+)
+
+//Manager -
+type Manager interface {
+	DockerViolations() ([]Violation, error)
+	EnforceDockerPolicy() error
+	StackViolations() ([]Violation, error)
+	EnforceStackPolicy() error
+	TaskUsage(since, until time.Time) ([]TaskUsage, error)
+}
+
+type CFClient interface {
+	ListAppsByQuery(query url.Values) ([]cfclient.App, error)
+	UpdateApp(guid string, aur cfclient.AppUpdateResource) (cfclient.UpdateResponse, error)
+	ListStacks() ([]cfclient.Stack, error)
+	ListTasksByQuery(query url.Values) ([]cfclient.Task, error)
+}
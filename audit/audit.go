@@ -0,0 +1,240 @@
+// Package audit correlates the audit events of the cloud controller with the
+// runs of cf-mgmt, so that changes made to the managed orgs outside the
+// pipeline can be attributed to whoever made them.
+package audit
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/organization"
+	"github.com/pivotalservices/cf-mgmt/simulator"
+	"github.com/pivotalservices/cf-mgmt/space"
+	"github.com/xchapter7x/lo"
+)
+
+// Attributions of an event
+const (
+	// AttributedToRun is a change cf-mgmt made during a recorded run
+	AttributedToRun = "cf-mgmt run"
+	// AttributedToCredentials is a change made with the credentials of
+	// cf-mgmt outside any recorded run, such as by someone reusing them
+	AttributedToCredentials = "cf-mgmt credentials outside a run"
+	// AttributedToOutOfBand is a change made by anyone else
+	AttributedToOutOfBand = "out-of-band"
+)
+
+// managedEventTypes are the prefixes of the audit events of the entities
+// cf-mgmt manages: orgs, spaces, their roles and their quotas
+var managedEventTypes = []string{"audit.organization.", "audit.space.", "audit.user.", "audit.organization_quota.", "audit.space_quota."}
+
+func NewManager(client CFClient, orgMgr organization.Manager, spaceMgr space.Manager, cfg config.Reader, userID string) Manager {
+	return &DefaultManager{
+		Cfg:      cfg,
+		OrgMgr:   orgMgr,
+		SpaceMgr: spaceMgr,
+		Client:   client,
+		UserID:   userID,
+	}
+}
+
+//DefaultManager -
+type DefaultManager struct {
+	Cfg      config.Reader
+	OrgMgr   organization.Manager
+	SpaceMgr space.Manager
+	Client   CFClient
+	// UserID is the uaa client or user cf-mgmt runs as
+	UserID string
+}
+
+// Run is the time a cf-mgmt run, recorded in its run summary, made changes.
+type Run struct {
+	Command  string
+	Started  time.Time
+	Finished time.Time
+}
+
+// Event is an audit event of a managed org or space, attributed to cf-mgmt
+// or to someone else.
+type Event struct {
+	Time        time.Time `json:"time"`
+	Type        string    `json:"type"`
+	Org         string    `json:"org"`
+	Space       string    `json:"space,omitempty"`
+	Target      string    `json:"target"`
+	Actor       string    `json:"actor"`
+	Attribution string    `json:"attribution"`
+	// Run is the command of the run the event is attributed to
+	Run string `json:"run,omitempty"`
+}
+
+// OutOfBand is whether the change was not made by a run of cf-mgmt.
+func (e Event) OutOfBand() bool {
+	return e.Attribution != AttributedToRun
+}
+
+//Events - lists the audit events of orgs, spaces and roles of the managed orgs that are not protected since
+//the given time, oldest first. Events made with the credentials of cf-mgmt are attributed to the run they happened during. When no
+//runs are given every one of them is attributed to cf-mgmt.
+func (m *DefaultManager) Events(since time.Time, runs []Run) ([]Event, error) {
+	orgConfigs, err := m.Cfg.GetOrgConfigs()
+	if err != nil {
+		return nil, err
+	}
+	orgsConfig, err := m.Cfg.Orgs()
+	if err != nil {
+		return nil, err
+	}
+	managed := make(map[string]bool)
+	for _, orgConfig := range orgConfigs {
+		managed[orgConfig.Org] = !orgsConfig.IsProtected(orgConfig.Org)
+	}
+	orgs, err := m.OrgMgr.ListOrgs()
+	if err != nil {
+		return nil, err
+	}
+	spaces, err := m.SpaceMgr.ListManagedSpaces()
+	if err != nil {
+		return nil, err
+	}
+	spaceNames := make(map[string]string)
+	for _, managedSpace := range spaces {
+		spaceNames[managedSpace.Space.Guid] = managedSpace.Space.Name
+	}
+	events := []Event{}
+	for _, org := range orgs {
+		if !managed[org.Name] {
+			continue
+		}
+		orgEvents, err := m.Client.ListEventsByQuery(url.Values{"q": []string{
+			"organization_guid:" + org.Guid,
+			"timestamp>=" + since.UTC().Format(time.RFC3339),
+		}})
+		if err != nil {
+			return nil, err
+		}
+		for _, orgEvent := range orgEvents {
+			if !managedEventType(orgEvent.Type) {
+				continue
+			}
+			at, err := time.Parse(time.RFC3339, orgEvent.CreatedAt)
+			if err != nil {
+				lo.G.Warningf("Ignoring audit event %s with unreadable time %s", orgEvent.GUID, orgEvent.CreatedAt)
+				continue
+			}
+			if at.Before(since) {
+				continue
+			}
+			actor := orgEvent.ActorUsername
+			if actor == "" {
+				actor = orgEvent.ActorName
+			}
+			spaceName := spaceNames[orgEvent.SpaceGUID]
+			if spaceName == "" && orgEvent.ActeeType == "space" {
+				// a deleted space is no longer listed
+				spaceName = orgEvent.ActeeName
+			}
+			event := Event{
+				Time:   at,
+				Type:   orgEvent.Type,
+				Org:    org.Name,
+				Space:  spaceName,
+				Target: orgEvent.ActeeName,
+				Actor:  actor,
+			}
+			m.attribute(&event, orgEvent.ActorName, runs)
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+	return events, nil
+}
+
+func (m *DefaultManager) attribute(event *Event, actorName string, runs []Run) {
+	if actorName != m.UserID && event.Actor != m.UserID {
+		event.Attribution = AttributedToOutOfBand
+		return
+	}
+	if len(runs) == 0 {
+		event.Attribution = AttributedToRun
+		return
+	}
+	for _, run := range runs {
+		if !event.Time.Before(run.Started) && !event.Time.After(run.Finished) {
+			event.Attribution = AttributedToRun
+			event.Run = run.Command
+			return
+		}
+	}
+	event.Attribution = AttributedToCredentials
+}
+
+func managedEventType(eventType string) bool {
+	for _, prefix := range managedEventTypes {
+		if strings.HasPrefix(eventType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Drift is a change of a plan, which reverts the foundation to the
+// configuration, with the actors of the out-of-band events that may have
+// caused it.
+type Drift struct {
+	simulator.Change
+	SuspectedActors []string `json:"suspected_actors"`
+}
+
+//SuspectedActors - pairs each change of a plan with the actors of the out-of-band events of the same org or
+//space and target, latest first
+func SuspectedActors(changes []simulator.Change, events []Event) []Drift {
+	drifts := []Drift{}
+	for _, change := range changes {
+		drift := Drift{Change: change, SuspectedActors: []string{}}
+		for i := len(events) - 1; i >= 0; i-- {
+			event := events[i]
+			if event.OutOfBand() && event.matches(change) && !contains(drift.SuspectedActors, event.Actor) {
+				drift.SuspectedActors = append(drift.SuspectedActors, event.Actor)
+			}
+		}
+		drifts = append(drifts, drift)
+	}
+	return drifts
+}
+
+// matches is whether the change names the org or space of the event, as
+// org/space, and its target, such as the user of a role. Space quotas are
+// named org/quota and org quotas by their name alone.
+func (e Event) matches(change simulator.Change) bool {
+	scope := e.Org
+	if e.Space != "" {
+		scope = e.Org + "/" + e.Space
+	}
+	orgQuota := strings.HasPrefix(e.Type, "audit.organization_quota.")
+	inScope, named := false, e.Target == e.Org || e.Target == e.Space
+	for _, token := range strings.Fields(change.Name) {
+		if token == scope || token == e.Org+"/"+e.Target || (orgQuota && token == e.Target) {
+			inScope = true
+		}
+		if token == e.Target || token == e.Org+"/"+e.Target {
+			named = true
+		}
+	}
+	return inScope && named
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
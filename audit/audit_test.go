@@ -0,0 +1,124 @@
+package audit_test
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/audit"
+	auditfakes "github.com/pivotalservices/cf-mgmt/audit/fakes"
+	"github.com/pivotalservices/cf-mgmt/config"
+	configfakes "github.com/pivotalservices/cf-mgmt/config/fakes"
+	orgfakes "github.com/pivotalservices/cf-mgmt/organization/fakes"
+	"github.com/pivotalservices/cf-mgmt/simulator"
+	"github.com/pivotalservices/cf-mgmt/space"
+	spacefakes "github.com/pivotalservices/cf-mgmt/space/fakes"
+)
+
+var _ = Describe("given Audit Manager", func() {
+	var (
+		fakeReader   *configfakes.FakeReader
+		fakeClient   *auditfakes.FakeCFClient
+		fakeOrgMgr   *orgfakes.FakeManager
+		fakeSpaceMgr *spacefakes.FakeManager
+		auditMgr     *audit.DefaultManager
+		since        time.Time
+	)
+	at := func(hour int) time.Time {
+		return time.Date(2026, 10, 1, hour, 0, 0, 0, time.UTC)
+	}
+
+	BeforeEach(func() {
+		fakeReader = new(configfakes.FakeReader)
+		fakeClient = new(auditfakes.FakeCFClient)
+		fakeOrgMgr = new(orgfakes.FakeManager)
+		fakeSpaceMgr = new(spacefakes.FakeManager)
+		auditMgr = &audit.DefaultManager{
+			Cfg:      fakeReader,
+			Client:   fakeClient,
+			OrgMgr:   fakeOrgMgr,
+			SpaceMgr: fakeSpaceMgr,
+			UserID:   "cf-mgmt",
+		}
+		since = at(0)
+		fakeReader.GetOrgConfigsReturns([]config.OrgConfig{config.OrgConfig{Org: "org1"}}, nil)
+		fakeReader.OrgsReturns(&config.Orgs{Orgs: []string{"org1"}}, nil)
+		fakeOrgMgr.ListOrgsReturns([]cfclient.Org{
+			cfclient.Org{Name: "org1", Guid: "org1-guid"},
+			cfclient.Org{Name: "unmanaged", Guid: "unmanaged-guid"},
+		}, nil)
+		fakeSpaceMgr.ListManagedSpacesReturns([]space.ManagedSpace{
+			space.ManagedSpace{Org: "org1", Space: cfclient.Space{Name: "dev", Guid: "dev-guid", OrganizationGuid: "org1-guid"}},
+		}, nil)
+		fakeClient.ListEventsByQueryReturns([]cfclient.Event{
+			cfclient.Event{Type: "audit.user.space_developer_add", CreatedAt: "2026-10-01T03:00:00Z", ActorName: "alice", ActorUsername: "alice@example.com", ActeeName: "bob", SpaceGUID: "dev-guid"},
+			cfclient.Event{Type: "audit.app.update", CreatedAt: "2026-10-01T04:00:00Z", ActorName: "alice", ActeeName: "web", SpaceGUID: "dev-guid"},
+			cfclient.Event{Type: "audit.space.update", CreatedAt: "2026-10-01T02:00:00Z", ActorName: "cf-mgmt", ActeeName: "dev", SpaceGUID: "dev-guid"},
+			cfclient.Event{Type: "audit.space.delete-request", CreatedAt: "2026-10-01T05:00:00Z", ActorName: "cf-mgmt", ActeeName: "old", ActeeType: "space", SpaceGUID: "old-guid"},
+			cfclient.Event{Type: "audit.space_quota.update", CreatedAt: "2026-10-01T06:00:00Z", ActorName: "carol", ActeeName: "small", ActeeType: "space_quota"},
+			cfclient.Event{Type: "audit.organization_quota.update", CreatedAt: "2026-10-01T07:00:00Z", ActorName: "carol", ActeeName: "org1", ActeeType: "organization_quota"},
+		}, nil)
+	})
+
+	Context("Events", func() {
+		It("attributes the events of managed orgs to runs of cf-mgmt or to their actors", func() {
+			events, err := auditMgr.Events(since, []audit.Run{
+				audit.Run{Command: "apply", Started: at(1), Finished: at(2)},
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(events).Should(Equal([]audit.Event{
+				audit.Event{Time: at(2), Type: "audit.space.update", Org: "org1", Space: "dev", Target: "dev", Actor: "cf-mgmt", Attribution: audit.AttributedToRun, Run: "apply"},
+				audit.Event{Time: at(3), Type: "audit.user.space_developer_add", Org: "org1", Space: "dev", Target: "bob", Actor: "alice@example.com", Attribution: audit.AttributedToOutOfBand},
+				audit.Event{Time: at(5), Type: "audit.space.delete-request", Org: "org1", Space: "old", Target: "old", Actor: "cf-mgmt", Attribution: audit.AttributedToCredentials},
+				audit.Event{Time: at(6), Type: "audit.space_quota.update", Org: "org1", Target: "small", Actor: "carol", Attribution: audit.AttributedToOutOfBand},
+				audit.Event{Time: at(7), Type: "audit.organization_quota.update", Org: "org1", Target: "org1", Actor: "carol", Attribution: audit.AttributedToOutOfBand},
+			}))
+			Expect(fakeClient.ListEventsByQueryCallCount()).Should(Equal(1))
+			Expect(fakeClient.ListEventsByQueryArgsForCall(0)).Should(Equal(url.Values{"q": []string{
+				"organization_guid:org1-guid",
+				"timestamp>=2026-10-01T00:00:00Z",
+			}}))
+		})
+
+		It("attributes every event of cf-mgmt to a run without recorded runs", func() {
+			events, err := auditMgr.Events(since, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(events[2].Attribution).Should(Equal(audit.AttributedToRun))
+		})
+
+		It("leaves out the events of protected orgs", func() {
+			fakeReader.OrgsReturns(&config.Orgs{Orgs: []string{"org1"}, ProtectedOrgs: []string{"org.*"}}, nil)
+			events, err := auditMgr.Events(since, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(events).Should(BeEmpty())
+			Expect(fakeClient.ListEventsByQueryCallCount()).Should(Equal(0))
+		})
+
+		It("errors listing events", func() {
+			fakeClient.ListEventsByQueryReturns(nil, errors.New("error"))
+			_, err := auditMgr.Events(since, nil)
+			Expect(err).Should(MatchError("error"))
+		})
+	})
+
+	Context("SuspectedActors", func() {
+		It("pairs plan changes with the actors of out-of-band events of the same target", func() {
+			events, err := auditMgr.Events(since, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			drifts := audit.SuspectedActors([]simulator.Change{
+				simulator.Change{Action: simulator.ActionDelete, Kind: "space role", Name: "bob as developer of org1/dev"},
+				simulator.Change{Action: simulator.ActionUpdate, Kind: "space", Name: "org1/dev"},
+				simulator.Change{Action: simulator.ActionUpdate, Kind: "space quota", Name: "org1/small"},
+				simulator.Change{Action: simulator.ActionUpdate, Kind: "org quota", Name: "org1"},
+			}, events)
+			Expect(drifts).Should(HaveLen(4))
+			Expect(drifts[0].SuspectedActors).Should(Equal([]string{"alice@example.com"}))
+			Expect(drifts[1].SuspectedActors).Should(BeEmpty())
+			Expect(drifts[2].SuspectedActors).Should(Equal([]string{"carol"}))
+			Expect(drifts[3].SuspectedActors).Should(Equal([]string{"carol"}))
+		})
+	})
+})
@@ -0,0 +1,80 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"net/url"
+	"sync"
+
+	go_cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pivotalservices/cf-mgmt/audit"
+)
+
+type FakeCFClient struct {
+	ListEventsByQueryStub        func(query url.Values) ([]go_cfclient.Event, error)
+	listEventsByQueryMutex       sync.RWMutex
+	listEventsByQueryArgsForCall []struct {
+		query url.Values
+	}
+	listEventsByQueryReturns struct {
+		result1 []go_cfclient.Event
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeCFClient) ListEventsByQuery(query url.Values) ([]go_cfclient.Event, error) {
+	fake.listEventsByQueryMutex.Lock()
+	fake.listEventsByQueryArgsForCall = append(fake.listEventsByQueryArgsForCall, struct {
+		query url.Values
+	}{query})
+	fake.recordInvocation("ListEventsByQuery", []interface{}{query})
+	fake.listEventsByQueryMutex.Unlock()
+	if fake.ListEventsByQueryStub != nil {
+		return fake.ListEventsByQueryStub(query)
+	} else {
+		return fake.listEventsByQueryReturns.result1, fake.listEventsByQueryReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) ListEventsByQueryCallCount() int {
+	fake.listEventsByQueryMutex.RLock()
+	defer fake.listEventsByQueryMutex.RUnlock()
+	return len(fake.listEventsByQueryArgsForCall)
+}
+
+func (fake *FakeCFClient) ListEventsByQueryArgsForCall(i int) url.Values {
+	fake.listEventsByQueryMutex.RLock()
+	defer fake.listEventsByQueryMutex.RUnlock()
+	return fake.listEventsByQueryArgsForCall[i].query
+}
+
+func (fake *FakeCFClient) ListEventsByQueryReturns(result1 []go_cfclient.Event, result2 error) {
+	fake.ListEventsByQueryStub = nil
+	fake.listEventsByQueryReturns = struct {
+		result1 []go_cfclient.Event
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.listEventsByQueryMutex.RLock()
+	defer fake.listEventsByQueryMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeCFClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ audit.CFClient = new(FakeCFClient)
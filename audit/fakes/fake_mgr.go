@@ -0,0 +1,86 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pivotalservices/cf-mgmt/audit"
+)
+
+type FakeManager struct {
+	EventsStub        func(since time.Time, runs []audit.Run) ([]audit.Event, error)
+	eventsMutex       sync.RWMutex
+	eventsArgsForCall []struct {
+		since time.Time
+		runs  []audit.Run
+	}
+	eventsReturns struct {
+		result1 []audit.Event
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeManager) Events(since time.Time, runs []audit.Run) ([]audit.Event, error) {
+	var runsCopy []audit.Run
+	if runs != nil {
+		runsCopy = make([]audit.Run, len(runs))
+		copy(runsCopy, runs)
+	}
+	fake.eventsMutex.Lock()
+	fake.eventsArgsForCall = append(fake.eventsArgsForCall, struct {
+		since time.Time
+		runs  []audit.Run
+	}{since, runsCopy})
+	fake.recordInvocation("Events", []interface{}{since, runsCopy})
+	fake.eventsMutex.Unlock()
+	if fake.EventsStub != nil {
+		return fake.EventsStub(since, runs)
+	} else {
+		return fake.eventsReturns.result1, fake.eventsReturns.result2
+	}
+}
+
+func (fake *FakeManager) EventsCallCount() int {
+	fake.eventsMutex.RLock()
+	defer fake.eventsMutex.RUnlock()
+	return len(fake.eventsArgsForCall)
+}
+
+func (fake *FakeManager) EventsArgsForCall(i int) (time.Time, []audit.Run) {
+	fake.eventsMutex.RLock()
+	defer fake.eventsMutex.RUnlock()
+	return fake.eventsArgsForCall[i].since, fake.eventsArgsForCall[i].runs
+}
+
+func (fake *FakeManager) EventsReturns(result1 []audit.Event, result2 error) {
+	fake.EventsStub = nil
+	fake.eventsReturns = struct {
+		result1 []audit.Event
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.eventsMutex.RLock()
+	defer fake.eventsMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeManager) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ audit.Manager = new(FakeManager)
@@ -0,0 +1,17 @@
+package audit
+
+import (
+	"net/url"
+	"time"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+)
+
+//Manager -
+type Manager interface {
+	Events(since time.Time, runs []Run) ([]Event, error)
+}
+
+type CFClient interface {
+	ListEventsByQuery(query url.Values) ([]cfclient.Event, error)
+}
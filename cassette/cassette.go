@@ -0,0 +1,82 @@
+// Package cassette records the http interactions of a cf-mgmt run to a file
+// and replays them, so a production run can be reproduced locally against
+// the exact responses the foundation returned.
+package cassette
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Redacted replaces credentials and tokens in recorded interactions.
+const Redacted = "REDACTED"
+
+var redactedParams = []string{"client_secret", "password", "refresh_token"}
+
+var tokenPattern = regexp.MustCompile(`"(access_token|refresh_token|id_token)"\s*:\s*"[^"]*"`)
+
+// Interaction is a single recorded request and the response it received.
+type Interaction struct {
+	Method       string      `json:"method"`
+	URL          string      `json:"url"`
+	RequestBody  string      `json:"request_body,omitempty"`
+	Status       int         `json:"status"`
+	Header       http.Header `json:"header,omitempty"`
+	ResponseBody string      `json:"response_body,omitempty"`
+}
+
+//Load - reads the interactions of a cassette file, one json interaction per line
+func Load(path string) ([]Interaction, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read cassette %s", path)
+	}
+	defer file.Close()
+	interactions := []Interaction{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 256*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		interaction := Interaction{}
+		if err := json.Unmarshal([]byte(line), &interaction); err != nil {
+			return nil, errors.Wrapf(err, "unable to parse cassette %s", path)
+		}
+		interactions = append(interactions, interaction)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "unable to read cassette %s", path)
+	}
+	return interactions, nil
+}
+
+func redactRequestBody(body string) string {
+	values, err := url.ParseQuery(body)
+	if err != nil {
+		return body
+	}
+	redacted := false
+	for _, param := range redactedParams {
+		if _, ok := values[param]; ok {
+			values.Set(param, Redacted)
+			redacted = true
+		}
+	}
+	if !redacted {
+		return body
+	}
+	return values.Encode()
+}
+
+func redactResponseBody(body string) string {
+	return tokenPattern.ReplaceAllString(body, `"$1":"`+Redacted+`"`)
+}
@@ -0,0 +1,108 @@
+package cassette_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/cassette"
+)
+
+var _ = Describe("given cassette", func() {
+	var (
+		dir      string
+		file     string
+		server   *httptest.Server
+		requests int
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "cassette")
+		Expect(err).ShouldNot(HaveOccurred())
+		file = path.Join(dir, "run.cassette")
+		requests = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.URL.Path == "/oauth/token" {
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"access_token":"secret-token","token_type":"bearer","expires_in":3600}`)
+				return
+			}
+			w.Header().Set("Set-Cookie", "session=abc")
+			fmt.Fprintf(w, `{"request":%d}`, requests)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+		os.RemoveAll(dir)
+	})
+
+	get := func(client *http.Client, url string) (int, string) {
+		resp, err := client.Get(url)
+		Expect(err).ShouldNot(HaveOccurred())
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		Expect(err).ShouldNot(HaveOccurred())
+		return resp.StatusCode, string(body)
+	}
+
+	It("records interactions without credentials", func() {
+		recorder, err := cassette.NewRecorder(file)
+		Expect(err).ShouldNot(HaveOccurred())
+		client := &http.Client{Transport: recorder.Wrap(nil)}
+		resp, err := client.Post(server.URL+"/oauth/token", "application/x-www-form-urlencoded", strings.NewReader("client_id=cf-mgmt&client_secret=s3cret&grant_type=client_credentials"))
+		Expect(err).ShouldNot(HaveOccurred())
+		body, err := ioutil.ReadAll(resp.Body)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(body)).Should(ContainSubstring("secret-token"))
+		status, body2 := get(client, server.URL+"/v2/organizations")
+		Expect(status).Should(Equal(http.StatusOK))
+		Expect(body2).Should(Equal(`{"request":2}`))
+
+		interactions, err := cassette.Load(file)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(interactions).Should(HaveLen(2))
+		Expect(interactions[0].Method).Should(Equal("POST"))
+		Expect(interactions[0].RequestBody).Should(Equal("client_id=cf-mgmt&client_secret=REDACTED&grant_type=client_credentials"))
+		Expect(interactions[0].ResponseBody).Should(Equal(`{"access_token":"REDACTED","token_type":"bearer","expires_in":3600}`))
+		Expect(interactions[1].URL).Should(Equal(server.URL + "/v2/organizations"))
+		Expect(interactions[1].ResponseBody).Should(Equal(`{"request":2}`))
+		Expect(interactions[1].Header.Get("Set-Cookie")).Should(BeEmpty())
+	})
+
+	It("replays repeated requests in recorded order", func() {
+		recorder, err := cassette.NewRecorder(file)
+		Expect(err).ShouldNot(HaveOccurred())
+		client := &http.Client{Transport: recorder.Wrap(nil)}
+		get(client, server.URL+"/v2/organizations")
+		get(client, server.URL+"/v2/spaces")
+		get(client, server.URL+"/v2/organizations")
+
+		player, err := cassette.NewPlayer(file)
+		Expect(err).ShouldNot(HaveOccurred())
+		server.Close()
+		client = &http.Client{Transport: player.Wrap(nil)}
+		_, body := get(client, server.URL+"/v2/organizations")
+		Expect(body).Should(Equal(`{"request":1}`))
+		_, body = get(client, server.URL+"/v2/organizations")
+		Expect(body).Should(Equal(`{"request":3}`))
+		Expect(player.Remaining()).Should(Equal(1))
+
+		_, err = client.Get(server.URL + "/v2/organizations")
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("no recorded interaction left for GET " + server.URL + "/v2/organizations"))
+	})
+
+	It("errors when the cassette does not exist", func() {
+		_, err := cassette.NewPlayer(path.Join(dir, "missing"))
+		Expect(err).Should(HaveOccurred())
+	})
+})
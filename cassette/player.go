@@ -0,0 +1,79 @@
+package cassette
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+//Player - answers requests from recorded interactions without contacting the foundation
+type Player struct {
+	interactions []Interaction
+	played       []bool
+	mutex        sync.Mutex
+}
+
+//NewPlayer - loads the cassette to replay
+func NewPlayer(path string) (*Player, error) {
+	interactions, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Player{
+		interactions: interactions,
+		played:       make([]bool, len(interactions)),
+	}, nil
+}
+
+//Wrap - returns a transport that replays interactions, base is never called
+func (p *Player) Wrap(base http.RoundTripper) http.RoundTripper {
+	return p
+}
+
+//Remaining - number of recorded interactions that have not been replayed
+func (p *Player) Remaining() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	remaining := 0
+	for _, played := range p.played {
+		if !played {
+			remaining++
+		}
+	}
+	return remaining
+}
+
+//RoundTrip - responds with the first interaction not yet played for the same method and url,
+//so repeated requests get their responses in the order they were recorded
+func (p *Player) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	requestURL := req.URL.String()
+	for i, interaction := range p.interactions {
+		if p.played[i] || interaction.Method != req.Method || interaction.URL != requestURL {
+			continue
+		}
+		p.played[i] = true
+		header := interaction.Header
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+			StatusCode:    interaction.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header.Clone(),
+			Body:          ioutil.NopCloser(strings.NewReader(interaction.ResponseBody)),
+			ContentLength: int64(len(interaction.ResponseBody)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no recorded interaction left for %s %s", req.Method, requestURL)
+}
@@ -0,0 +1,100 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+//Recorder - appends every interaction made through its transports to a cassette file
+type Recorder struct {
+	path  string
+	mutex sync.Mutex
+}
+
+//NewRecorder - creates the cassette file, replacing any previous recording
+func NewRecorder(path string) (*Recorder, error) {
+	if err := ioutil.WriteFile(path, []byte{}, 0600); err != nil {
+		return nil, errors.Wrapf(err, "unable to create cassette %s", path)
+	}
+	return &Recorder{path: path}, nil
+}
+
+//Wrap - returns a transport that records the interactions made through base
+func (r *Recorder) Wrap(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &recordingTransport{recorder: r, base: base}
+}
+
+// record is written as each interaction completes so that the recording of a
+// failed or aborted run is kept up to the point it stopped.
+func (r *Recorder) record(interaction Interaction) error {
+	bytes, err := json.Marshal(interaction)
+	if err != nil {
+		return err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	file, err := os.OpenFile(r.path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(append(bytes, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+type recordingTransport struct {
+	recorder *Recorder
+	base     http.RoundTripper
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.Body != nil {
+		var err error
+		if requestBody, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(requestBody))
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+
+	header := resp.Header
+	if header != nil {
+		header = header.Clone()
+		header.Del("Set-Cookie")
+		// redaction can change the body length
+		header.Del("Content-Length")
+	}
+	if err := t.recorder.record(Interaction{
+		Method:       req.Method,
+		URL:          req.URL.String(),
+		RequestBody:  redactRequestBody(string(requestBody)),
+		Status:       resp.StatusCode,
+		Header:       header,
+		ResponseBody: redactResponseBody(string(responseBody)),
+	}); err != nil {
+		return nil, errors.Wrap(err, "unable to record interaction")
+	}
+	return resp, nil
+}
@@ -0,0 +1,614 @@
+// Package cfmgmt composes the cf-mgmt managers so that other Go programs can
+// embed cf-mgmt reconciliation without executing the cf-mgmt CLI.
+//
+//	mgmt, err := cfmgmt.New(cfmgmt.Config{
+//		ConfigDirectory: "config",
+//		SystemDomain:    "sys.example.com",
+//		UserID:          "cf-mgmt",
+//		ClientSecret:    secret,
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	return mgmt.Apply(ldapPassword)
+package cfmgmt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pivotalservices/cf-mgmt/app"
+	"github.com/pivotalservices/cf-mgmt/audit"
+	"github.com/pivotalservices/cf-mgmt/cassette"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/configcommands"
+	"github.com/pivotalservices/cf-mgmt/diskcache"
+	"github.com/pivotalservices/cf-mgmt/endpoint"
+	"github.com/pivotalservices/cf-mgmt/httpclient"
+	"github.com/pivotalservices/cf-mgmt/identityprovider"
+	"github.com/pivotalservices/cf-mgmt/isosegment"
+	"github.com/pivotalservices/cf-mgmt/korifi"
+	"github.com/pivotalservices/cf-mgmt/organization"
+	"github.com/pivotalservices/cf-mgmt/plugin"
+	"github.com/pivotalservices/cf-mgmt/privatedomain"
+	"github.com/pivotalservices/cf-mgmt/quota"
+	"github.com/pivotalservices/cf-mgmt/route"
+	"github.com/pivotalservices/cf-mgmt/securitygroup"
+	"github.com/pivotalservices/cf-mgmt/service"
+	"github.com/pivotalservices/cf-mgmt/space"
+	"github.com/pivotalservices/cf-mgmt/stats"
+	"github.com/pivotalservices/cf-mgmt/tokenpolicy"
+	"github.com/pivotalservices/cf-mgmt/uaa"
+	"github.com/pivotalservices/cf-mgmt/user"
+	"github.com/xchapter7x/lo"
+)
+
+// Config holds what is needed to connect cf-mgmt to a foundation.
+type Config struct {
+	ConfigDirectory string
+	SystemDomain    string
+	UserID          string
+	// Password is deprecated, use a uaa client and ClientSecret instead.
+	Password     string
+	ClientSecret string
+	// Peek previews changes without modifying the foundation.
+	Peek bool
+	// Context, when cancelled, aborts any in-flight api requests.
+	Context context.Context
+	// RequestTimeout limits each individual api request, zero for no limit.
+	RequestTimeout time.Duration
+	// RecordTo, when set, records every api interaction to this cassette file.
+	RecordTo string
+	// ReplayFrom, when set, answers api requests from this cassette file
+	// instead of the foundation, so credentials are not needed.
+	ReplayFrom string
+	// UAAUserOrigins, when set, only lists the uaa users of these origins so
+	// that foundations with many users in other origins use less memory.
+	UAAUserOrigins []string
+	// UAALookupMode is uaa.LookupTargeted to only look up the uaa users
+	// referenced by the configuration instead of listing every user.
+	UAALookupMode string
+	// OrgSelector, when set, limits updates to the orgs whose metadata labels
+	// match this label selector, such as team=payments.
+	OrgSelector string
+	// OrgsSelected, when set, receives the orgs matching OrgSelector
+	OrgsSelected func(orgNames []string)
+	// ChangedOrgs, when not nil, limits updates to these orgs, the orgs whose
+	// configuration changed since the last successful run.
+	ChangedOrgs []string
+	// CacheDir, when set, keeps ldap and uaa lookups on disk for CacheTTL so
+	// that runs in quick succession, such as plan then apply, share them.
+	CacheDir string
+	CacheTTL time.Duration
+	// Korifi targets a Korifi foundation, with the client secret as the
+	// Kubernetes bearer token to authenticate with.
+	Korifi bool
+	// SkipLdap never binds to ldap, for plans computed offline, leaving the
+	// ldap users of roles as they are.
+	SkipLdap bool
+}
+
+// CFMgmt holds the managers used to reconcile a foundation with the configuration.
+type CFMgmt struct {
+	// Client is the cloud controller the managers were created on
+	Client                  CFClient
+	UAAManager              uaa.Manager
+	OrgManager              organization.Manager
+	SpaceManager            space.Manager
+	UserManager             user.Manager
+	QuotaManager            quota.Manager
+	PrivateDomainManager    privatedomain.Manager
+	ConfigManager           config.Updater
+	ConfigDirectory         string
+	SystemDomain            string
+	SecurityGroupManager    securitygroup.Manager
+	IsolationSegmentManager isosegment.Manager
+	RouteManager            route.Manager
+	AppManager              app.Manager
+	ServiceManager          service.Manager
+	AuditManager            audit.Manager
+	IdentityProviderManager identityprovider.Manager
+	TokenPolicyManager      tokenpolicy.Manager
+	// OrgScope, when set, is the configuration the managers read, which
+	// ApplyWithCheckpoint limits to one org at a time
+	OrgScope *config.OrgScope
+	// InjectFailure, when set, fails apply steps without running them
+	InjectFailure *FailureInjection
+	// Plugins, when set, runs the plugins of the configuration at the hooks
+	// of apply
+	Plugins *plugin.Runner
+
+	// requests binds the api requests of the managers to the apply in progress
+	requests *requestScope
+}
+
+// New connects to the foundation and creates the managers.
+func New(cfg Config) (*CFMgmt, error) {
+	if cfg.ReplayFrom != "" {
+		if cfg.SystemDomain == "" {
+			return nil, fmt.Errorf("must set system-domain property")
+		}
+		cfg.UserID, cfg.ClientSecret = "cf-mgmt", cassette.Redacted
+	}
+	if cfg.SystemDomain == "" ||
+		cfg.UserID == "" ||
+		cfg.ClientSecret == "" {
+		return nil, fmt.Errorf("must set system-domain, user-id, client-secret properties")
+	}
+
+	requests := &requestScope{}
+	wrapTransport, err := interactionTransport(cfg, requests)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Korifi {
+		cfMgmt, err := newKorifi(cfg, wrapTransport)
+		if err != nil {
+			return nil, err
+		}
+		cfMgmt.requests = requests
+		return cfMgmt, nil
+	}
+	countUAACalls := func(base http.RoundTripper) http.RoundTripper {
+		return stats.Transport(stats.UAA, wrapTransport(base))
+	}
+	uaaMgr, err := uaa.NewDefaultUAAManagerWithTransport(cfg.SystemDomain, cfg.UserID, cfg.ClientSecret, countUAACalls, cfg.Peek)
+	if err != nil {
+		return nil, err
+	}
+
+	var c *cfclient.Config
+	if cfg.Password != "" {
+		lo.G.Warning("Password parameter is deprecated, create uaa client and client-secret instead")
+		c = &cfclient.Config{
+			ApiAddress:        endpoint.API(cfg.SystemDomain),
+			SkipSslValidation: true,
+			Username:          cfg.UserID,
+			Password:          cfg.Password,
+			UserAgent:         fmt.Sprintf("cf-mgmt/%s", configcommands.VERSION),
+		}
+	} else {
+		c = &cfclient.Config{
+			ApiAddress:        endpoint.API(cfg.SystemDomain),
+			SkipSslValidation: true,
+			ClientID:          cfg.UserID,
+			ClientSecret:      cfg.ClientSecret,
+			UserAgent:         fmt.Sprintf("cf-mgmt/%s", configcommands.VERSION),
+		}
+	}
+	// share the connections of uaa requests, the shared transport already skips ssl validation
+	// token requests are sent to the login endpoint when one is configured
+	c.HttpClient = &http.Client{Transport: stats.Transport(stats.CloudController, wrapTransport(endpoint.Transport(httpclient.Transport())))}
+	client, err := cfclient.NewClient(c)
+	if err != nil {
+		return nil, err
+	}
+	cfMgmt, err := NewWithClient(cfg, client, uaaMgr)
+	if err != nil {
+		return nil, err
+	}
+	cfMgmt.requests = requests
+	return cfMgmt, nil
+}
+
+// newKorifi creates the managers on a Korifi foundation, which has no uaa, so
+// users are only looked up by name
+func newKorifi(cfg Config, wrapTransport func(http.RoundTripper) http.RoundTripper) (*CFMgmt, error) {
+	transport := korifi.TokenTransport(cfg.ClientSecret, httpclient.Transport())
+	client := korifi.NewClient(&http.Client{Transport: stats.Transport(stats.CloudController, wrapTransport(transport))}, endpoint.API(cfg.SystemDomain))
+	cfg.UAALookupMode = uaa.LookupTargeted
+	return NewWithClient(cfg, client, korifi.NewUAAManager(client))
+}
+
+func interactionTransport(cfg Config, requests *requestScope) (func(http.RoundTripper) http.RoundTripper, error) {
+	wrapTransport := func(base http.RoundTripper) http.RoundTripper {
+		return newContextTransport(cfg.Context, requests, cfg.RequestTimeout, base)
+	}
+	if cfg.ReplayFrom != "" {
+		player, err := cassette.NewPlayer(cfg.ReplayFrom)
+		if err != nil {
+			return nil, err
+		}
+		lo.G.Warningf("replaying api interactions from %s", cfg.ReplayFrom)
+		return player.Wrap, nil
+	}
+	if cfg.RecordTo != "" {
+		recorder, err := cassette.NewRecorder(cfg.RecordTo)
+		if err != nil {
+			return nil, err
+		}
+		lo.G.Debugf("recording api interactions to %s", cfg.RecordTo)
+		return func(base http.RoundTripper) http.RoundTripper {
+			return recorder.Wrap(wrapTransport(base))
+		}, nil
+	}
+	return wrapTransport, nil
+}
+
+// NewWithClient creates the managers on top of the given clients, which lets
+// cf-mgmt run against something other than a live foundation such as the
+// in-memory simulator.
+func NewWithClient(cfg Config, client CFClient, uaaMgr uaa.Manager) (*CFMgmt, error) {
+	var configReader config.Reader = config.NewManager(cfg.ConfigDirectory)
+	if cfg.OrgSelector != "" {
+		orgNames, err := selectOrgs(client, cfg.OrgSelector)
+		if err != nil {
+			return nil, err
+		}
+		lo.G.Infof("Limiting updates to the %d orgs matching %s: %s", len(orgNames), cfg.OrgSelector, strings.Join(orgNames, ", "))
+		configReader = config.SelectOrgs(configReader, orgNames)
+		if cfg.OrgsSelected != nil {
+			cfg.OrgsSelected(orgNames)
+		}
+	}
+	if cfg.ChangedOrgs != nil {
+		configReader = config.SelectOrgs(configReader, cfg.ChangedOrgs)
+	}
+	orgScope := config.NewOrgScope(configReader)
+	configReader = orgScope
+	cfMgmt := &CFMgmt{Client: client, OrgScope: orgScope}
+	cfMgmt.ConfigDirectory = cfg.ConfigDirectory
+	cfMgmt.SystemDomain = cfg.SystemDomain
+	cfMgmt.ConfigManager = config.NewManager(cfMgmt.ConfigDirectory)
+	var diskCache *diskcache.Cache
+	if cfg.CacheDir != "" {
+		// the cache of each foundation is kept apart
+		diskCache = diskcache.New(filepath.Join(cfg.CacheDir, cfg.SystemDomain), cfg.CacheTTL)
+	}
+	if defaultUAAMgr, ok := uaaMgr.(*uaa.DefaultUAAManager); ok {
+		defaultUAAMgr.UserOrigins = cfg.UAAUserOrigins
+		defaultUAAMgr.Cache = diskCache
+	}
+	cfMgmt.UAAManager = uaaMgr
+	cfMgmt.OrgManager = organization.NewManager(client, configReader, cfg.Peek)
+	cfMgmt.SpaceManager = space.NewManager(client, cfMgmt.UAAManager, cfMgmt.OrgManager, configReader, cfg.Peek)
+	cfMgmt.UserManager = user.NewManager(client, configReader, cfMgmt.SpaceManager, cfMgmt.OrgManager, cfMgmt.UAAManager, cfg.Peek)
+	if defaultUserMgr, ok := cfMgmt.UserManager.(*user.DefaultManager); ok {
+		defaultUserMgr.UAALookupMode = cfg.UAALookupMode
+		defaultUserMgr.DiskCache = diskCache
+		defaultUserMgr.SkipLdap = cfg.SkipLdap
+	}
+	cfMgmt.SecurityGroupManager = securitygroup.NewManager(client, cfMgmt.SpaceManager, configReader, cfg.Peek)
+	if defaultSecurityGroupMgr, ok := cfMgmt.SecurityGroupManager.(*securitygroup.DefaultManager); ok {
+		defaultSecurityGroupMgr.Annotator = annotator(client)
+	}
+	cfMgmt.QuotaManager = quota.NewManager(client, cfMgmt.SpaceManager, cfMgmt.OrgManager, configReader, cfg.Peek)
+	cfMgmt.PrivateDomainManager = privatedomain.NewManager(client, cfMgmt.OrgManager, configReader, cfg.Peek)
+	cfMgmt.RouteManager = route.NewManager(client, cfMgmt.OrgManager, cfMgmt.SpaceManager, configReader, cfg.Peek)
+	cfMgmt.AppManager = app.NewManager(client, cfMgmt.SpaceManager, configReader, cfg.Peek)
+	cfMgmt.ServiceManager = service.NewManager(client, cfMgmt.SpaceManager, configReader, cfg.Peek)
+	cfMgmt.AuditManager = audit.NewManager(client, cfMgmt.OrgManager, cfMgmt.SpaceManager, configReader, cfg.UserID)
+	cfMgmt.IdentityProviderManager = identityprovider.NewManager(cfMgmt.UAAManager, configReader)
+	cfMgmt.TokenPolicyManager = tokenpolicy.NewManager(cfMgmt.UAAManager, configReader)
+	if isoSegmentManager, err := isosegment.NewManager(client, configReader, cfMgmt.OrgManager, cfMgmt.SpaceManager, cfg.Peek); err == nil {
+		cfMgmt.IsolationSegmentManager = isoSegmentManager
+	} else {
+		return nil, err
+	}
+	return cfMgmt, nil
+}
+
+// orgLabelSelector lists orgs by their metadata labels, such as the simulator
+type orgLabelSelector interface {
+	ListOrgNamesByLabelSelector(selector organization.LabelSelector) ([]string, error)
+}
+
+func selectOrgs(client CFClient, selector string) ([]string, error) {
+	labelSelector, err := organization.ParseLabelSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	switch c := client.(type) {
+	case orgLabelSelector:
+		return c.ListOrgNamesByLabelSelector(labelSelector)
+	case *cfclient.Client:
+		return organization.ListOrgNamesByLabelSelector(c.Config.HttpClient, c.Config.ApiAddress, labelSelector)
+	}
+	return nil, fmt.Errorf("org selector is not supported by %T", client)
+}
+
+// Annotator reads and writes the metadata annotations of the orgs and spaces
+// of the foundation, nil when the client cannot annotate them.
+func (m *CFMgmt) Annotator() securitygroup.Annotator {
+	return annotator(m.Client)
+}
+
+// annotator returns how to annotate orgs and spaces with the client, nil
+// when the client does not support annotations
+func annotator(client CFClient) securitygroup.Annotator {
+	switch c := client.(type) {
+	case securitygroup.Annotator:
+		return c
+	case *cfclient.Client:
+		return securitygroup.NewAnnotator(c.Config.HttpClient, c.Config.ApiAddress)
+	}
+	return nil
+}
+
+// Step is a single named stage of reconciliation.
+type Step struct {
+	Name string
+	Run  func() error
+	// PerOrg steps only change the orgs they read the configuration of, so
+	// they can be run one org at a time
+	PerOrg bool
+}
+
+// ApplySteps lists the stages run by Apply, in order.
+func (m *CFMgmt) ApplySteps() []Step {
+	return []Step{
+		{"Creating Orgs", m.OrgManager.CreateOrgs, true},
+		{"Delete Orgs", m.OrgManager.DeleteOrgs, false},
+		{"Update Identity Providers", m.IdentityProviderManager.UpdateIdentityProviders, false},
+		{"Update Token Policy", m.TokenPolicyManager.UpdateTokenPolicy, false},
+		{"Update Org Users", m.UserManager.UpdateOrgUsers, true},
+		{"Create Global Security Groups", m.SecurityGroupManager.CreateGlobalSecurityGroups, false},
+		{"Assign Default Security Groups", m.SecurityGroupManager.AssignDefaultSecurityGroups, false},
+		{"Create Private Domains", m.PrivateDomainManager.CreatePrivateDomains, true},
+		{"Share Private Domains", m.PrivateDomainManager.SharePrivateDomains, false},
+		{"Create Org Quotas", m.QuotaManager.CreateOrgQuotas, true},
+		{"Create Spaces", m.SpaceManager.CreateSpaces, true},
+		{"Delete Spaces", m.SpaceManager.DeleteSpaces, true},
+		{"Update Spaces", m.SpaceManager.UpdateSpaces, true},
+		{"Update Space Users", m.UserManager.UpdateSpaceUsers, true},
+		{"Create Personal Spaces", m.UserManager.CreatePersonalSpaces, true},
+		{"Create Space Quotas", m.QuotaManager.CreateSpaceQuotas, true},
+		{"Create Application Security Groups", m.SecurityGroupManager.CreateApplicationSecurityGroups, true},
+		{"Annotate Egress", m.SecurityGroupManager.AnnotateEgress, true},
+		{"Isolation Segments", m.IsolationSegmentManager.Apply, false},
+		{"Internal Routes", m.RouteManager.EnforceInternalRoutes, true},
+		{"Docker Policy", m.AppManager.EnforceDockerPolicy, true},
+		{"Stack Policy", m.AppManager.EnforceStackPolicy, true},
+		{"Cleanup Org Users", m.UserManager.CleanupOrgUsers, true},
+		{"Update Role Groups", m.UserManager.UpdateRoleGroups, false},
+	}
+}
+
+// Apply reconciles the foundation with the entire configuration, stopping at
+// the first step that fails.
+func (m *CFMgmt) Apply(ldapPassword string) error {
+	return m.ApplyContext(context.Background(), ldapPassword)
+}
+
+// ApplyContext is Apply that stops before starting the next step once ctx is
+// done. The managers make their api requests with ctx, so cancelling it also
+// cancels the requests in flight, unless WithRequestContext gave them another
+// context to let the step in progress complete.
+func (m *CFMgmt) ApplyContext(ctx context.Context, ldapPassword string) error {
+	_, err := m.ApplyWithFailureBudget(ctx, ldapPassword, 1)
+	return err
+}
+
+// Step statuses reported in an ApplyReport.
+const (
+	StepSucceeded = "succeeded"
+	StepFailed    = "failed"
+	StepSkipped   = "skipped"
+	// StepUnsupported is a step for a feature the foundation lacks, such as
+	// quotas on Korifi, which does not count as a failure
+	StepUnsupported = "unsupported"
+	// StepCompleted is a step completed by the interrupted apply resumed from
+	StepCompleted = "completed"
+)
+
+// StepResult is the outcome of a single step of an apply.
+type StepResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ApplyReport lists the outcome of every apply step, in order.
+type ApplyReport struct {
+	Steps []StepResult `json:"steps"`
+}
+
+// Failed returns the steps that failed.
+func (r *ApplyReport) Failed() []StepResult {
+	failed := []StepResult{}
+	for _, step := range r.Steps {
+		if step.Status == StepFailed {
+			failed = append(failed, step)
+		}
+	}
+	return failed
+}
+
+// String formats the report one step per line.
+func (r *ApplyReport) String() string {
+	var buffer bytes.Buffer
+	for _, step := range r.Steps {
+		if step.Error != "" {
+			fmt.Fprintf(&buffer, "%-9s %s: %s\n", step.Status, step.Name, step.Error)
+		} else {
+			fmt.Fprintf(&buffer, "%-9s %s\n", step.Status, step.Name)
+		}
+	}
+	return buffer.String()
+}
+
+// runCacher is a manager that can keep what it lists from the foundation for
+// the steps that follow, rather than listing it again in every step.
+type runCacher interface {
+	StartRunCache()
+	StopRunCache()
+}
+
+// ApplyWithFailureBudget is ApplyContext that keeps running the remaining steps
+// after a step fails, until maxFailures steps have failed. Steps that were not
+// run are reported as skipped. A maxFailures below one stops at the first failure.
+func (m *CFMgmt) ApplyWithFailureBudget(ctx context.Context, ldapPassword string, maxFailures int) (*ApplyReport, error) {
+	report, _, err := m.apply(ctx, ldapPassword, maxFailures, nil)
+	return report, err
+}
+
+// ApplyWithCheckpoint is ApplyWithFailureBudget that runs the per org steps one
+// org at a time, so that once ctx is done it stops after the org in flight
+// rather than at the end of the step, returning the checkpoint to resume from.
+// Given the checkpoint of an interrupted apply, it resumes from there, skipping
+// the steps and orgs that apply completed.
+func (m *CFMgmt) ApplyWithCheckpoint(ctx context.Context, ldapPassword string, maxFailures int, resume *Checkpoint) (*ApplyReport, *Checkpoint, error) {
+	if resume == nil {
+		resume = &Checkpoint{}
+	}
+	return m.apply(ctx, ldapPassword, maxFailures, resume)
+}
+
+// apply runs the steps, one org at a time and from the checkpoint when resume
+// is not nil, returning a checkpoint when ctx stopped it
+func (m *CFMgmt) apply(ctx context.Context, ldapPassword string, maxFailures int, resume *Checkpoint) (*ApplyReport, *Checkpoint, error) {
+	if maxFailures < 1 {
+		maxFailures = 1
+	}
+	report := &ApplyReport{}
+	steps := m.ApplySteps()
+	skipRemaining := func(from int) {
+		for _, step := range steps[from:] {
+			report.Steps = append(report.Steps, StepResult{Name: step.Name, Status: StepSkipped})
+		}
+	}
+	first, err := resume.stepIndex(steps)
+	if err == nil {
+		err = m.InjectFailure.validate(steps)
+	}
+	if err != nil {
+		skipRemaining(0)
+		return report, nil, err
+	}
+	// the managers make their requests with ctx, so those in flight are cancelled along with it
+	defer m.requests.bind(requestContext(ctx))()
+	if err := m.UserManager.InitializeLdap(ldapPassword); err != nil {
+		skipRemaining(0)
+		return report, nil, err
+	}
+	defer m.UserManager.DeinitializeLdap()
+	for _, manager := range []interface{}{m.OrgManager, m.UserManager} {
+		if cacher, ok := manager.(runCacher); ok {
+			cacher.StartRunCache()
+			defer cacher.StopRunCache()
+		}
+	}
+
+	if err := m.Plugins.Run(ctx, plugin.Pre+ApplyHook, "", nil); err != nil {
+		skipRemaining(0)
+		return report, nil, err
+	}
+
+	var errs []error
+	for i, step := range steps {
+		if i < first {
+			report.Steps = append(report.Steps, StepResult{Name: step.Name, Status: StepCompleted})
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			skipRemaining(i)
+			return report, resume.next(step.Name, nil), fmt.Errorf("apply stopped before step [%s]: %s", step.Name, err)
+		}
+		fmt.Println("********* ", step.Name)
+		stopTiming := stats.Time(step.Name)
+		hook := StepHook(step.Name)
+		err := m.InjectFailure.inject(step.Name)
+		if err != nil {
+			lo.G.Warningf("injecting a failure of step [%s] without running it", step.Name)
+		} else if err = m.Plugins.Run(ctx, plugin.Pre+hook, step.Name, nil); err != nil {
+			lo.G.Errorf("not running step [%s] as a plugin failed", step.Name)
+		} else if resume != nil && step.PerOrg && m.OrgScope != nil {
+			var completed []string
+			var stopped bool
+			completed, stopped, err = m.runPerOrg(ctx, step, resume.completedOrgs(step.Name))
+			if stopped {
+				stopTiming()
+				skipRemaining(i)
+				return report, resume.next(step.Name, completed), fmt.Errorf("apply stopped during step [%s] after %d orgs: %s", step.Name, len(completed), ctx.Err())
+			}
+		} else {
+			err = step.Run()
+		}
+		stopTiming()
+		status := StepSucceeded
+		if korifi.IsNotSupported(err) {
+			status = StepUnsupported
+		} else if err != nil {
+			status = StepFailed
+		}
+		if postErr := m.Plugins.Run(ctx, plugin.Post+hook, step.Name, stepResult(status, err)); postErr != nil && status != StepFailed {
+			err = postErr
+		}
+		if korifi.IsNotSupported(err) {
+			lo.G.Warningf("step [%s] is not supported by the foundation: %s", step.Name, err)
+			report.Steps = append(report.Steps, StepResult{Name: step.Name, Status: StepUnsupported, Error: err.Error()})
+			continue
+		}
+		if err != nil {
+			report.Steps = append(report.Steps, StepResult{Name: step.Name, Status: StepFailed, Error: err.Error()})
+			errs = append(errs, err)
+			if len(errs) >= maxFailures {
+				skipRemaining(i + 1)
+				break
+			}
+			lo.G.Errorf("step [%s] failed, continuing with %d of %d failures allowed: %s", step.Name, len(errs), maxFailures, err)
+			continue
+		}
+		report.Steps = append(report.Steps, StepResult{Name: step.Name, Status: StepSucceeded})
+	}
+
+	status := StepSucceeded
+	if len(errs) > 0 {
+		status = StepFailed
+	}
+	if err := m.Plugins.Run(ctx, plugin.Post+ApplyHook, "", &plugin.Result{Status: status}); err != nil {
+		if len(errs) == 0 {
+			return report, nil, err
+		}
+		lo.G.Errorf("%s", err)
+	}
+
+	switch len(errs) {
+	case 0:
+		return report, nil, nil
+	case 1:
+		return report, nil, errs[0]
+	default:
+		messages := []string{}
+		for _, step := range report.Failed() {
+			messages = append(messages, fmt.Sprintf("[%s]: %s", step.Name, step.Error))
+		}
+		return report, nil, fmt.Errorf("%d steps failed: %s", len(errs), strings.Join(messages, "; "))
+	}
+}
+
+// runPerOrg runs the step for each configured org it has not completed, until
+// ctx is done, returning the orgs it completed and whether ctx stopped it
+// before the last org. It stops at the first org the step fails for, as the
+// step would when run for every org.
+func (m *CFMgmt) runPerOrg(ctx context.Context, step Step, completed []string) ([]string, bool, error) {
+	orgConfigs, err := m.OrgScope.Reader.GetOrgConfigs()
+	if err != nil {
+		return completed, false, err
+	}
+	defer m.OrgScope.Select(nil)
+	done := make(map[string]bool)
+	for _, orgName := range completed {
+		done[orgName] = true
+	}
+	for _, orgConfig := range orgConfigs {
+		if done[orgConfig.Org] {
+			continue
+		}
+		if ctx.Err() != nil {
+			return completed, true, nil
+		}
+		m.OrgScope.Select([]string{orgConfig.Org})
+		if err := step.Run(); err != nil {
+			return completed, false, err
+		}
+		completed = append(completed, orgConfig.Org)
+	}
+	return completed, false, nil
+}
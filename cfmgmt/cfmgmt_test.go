@@ -0,0 +1,310 @@
+package cfmgmt_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appfakes "github.com/pivotalservices/cf-mgmt/app/fakes"
+	"github.com/pivotalservices/cf-mgmt/cfmgmt"
+	"github.com/pivotalservices/cf-mgmt/config"
+	configfakes "github.com/pivotalservices/cf-mgmt/config/fakes"
+	identityproviderfakes "github.com/pivotalservices/cf-mgmt/identityprovider/fakes"
+	isosegmentfakes "github.com/pivotalservices/cf-mgmt/isosegment/fakes"
+	"github.com/pivotalservices/cf-mgmt/korifi"
+	orgfakes "github.com/pivotalservices/cf-mgmt/organization/fakes"
+	"github.com/pivotalservices/cf-mgmt/plugin"
+	privatedomainfakes "github.com/pivotalservices/cf-mgmt/privatedomain/fakes"
+	quotafakes "github.com/pivotalservices/cf-mgmt/quota/fakes"
+	routefakes "github.com/pivotalservices/cf-mgmt/route/fakes"
+	securitygroupfakes "github.com/pivotalservices/cf-mgmt/securitygroup/fakes"
+	spacefakes "github.com/pivotalservices/cf-mgmt/space/fakes"
+	tokenpolicyfakes "github.com/pivotalservices/cf-mgmt/tokenpolicy/fakes"
+	userfakes "github.com/pivotalservices/cf-mgmt/user/fakes"
+)
+
+var _ = Describe("CFMgmt", func() {
+	var (
+		orgMgr    *orgfakes.FakeManager
+		spaceMgr  *spacefakes.FakeManager
+		userMgr   *userfakes.FakeManager
+		quotaMgr  *quotafakes.FakeManager
+		domainMgr *privatedomainfakes.FakeManager
+		sgMgr     *securitygroupfakes.FakeManager
+		isoSegMgr *isosegmentfakes.FakeManager
+		routeMgr  *routefakes.FakeManager
+		appMgr    *appfakes.FakeManager
+		idpMgr    *identityproviderfakes.FakeManager
+		tokenMgr  *tokenpolicyfakes.FakeManager
+		cfMgmt    *cfmgmt.CFMgmt
+	)
+
+	BeforeEach(func() {
+		orgMgr = new(orgfakes.FakeManager)
+		spaceMgr = new(spacefakes.FakeManager)
+		userMgr = new(userfakes.FakeManager)
+		quotaMgr = new(quotafakes.FakeManager)
+		domainMgr = new(privatedomainfakes.FakeManager)
+		sgMgr = new(securitygroupfakes.FakeManager)
+		isoSegMgr = new(isosegmentfakes.FakeManager)
+		routeMgr = new(routefakes.FakeManager)
+		appMgr = new(appfakes.FakeManager)
+		idpMgr = new(identityproviderfakes.FakeManager)
+		tokenMgr = new(tokenpolicyfakes.FakeManager)
+		cfMgmt = &cfmgmt.CFMgmt{
+			OrgManager:              orgMgr,
+			SpaceManager:            spaceMgr,
+			UserManager:             userMgr,
+			QuotaManager:            quotaMgr,
+			PrivateDomainManager:    domainMgr,
+			SecurityGroupManager:    sgMgr,
+			IsolationSegmentManager: isoSegMgr,
+			RouteManager:            routeMgr,
+			AppManager:              appMgr,
+			IdentityProviderManager: idpMgr,
+			TokenPolicyManager:      tokenMgr,
+		}
+	})
+
+	Context("New", func() {
+		It("requires connection details", func() {
+			_, err := cfmgmt.New(cfmgmt.Config{ConfigDirectory: "config"})
+			Expect(err).Should(MatchError("must set system-domain, user-id, client-secret properties"))
+		})
+
+		It("replays recorded api interactions without credentials", func() {
+			mgmt, err := cfmgmt.New(cfmgmt.Config{
+				ConfigDirectory: "config",
+				SystemDomain:    "sys.example.com",
+				ReplayFrom:      "./fixtures/list-orgs.cassette",
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			orgs, err := mgmt.OrgManager.ListOrgs()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(orgs).Should(HaveLen(1))
+			Expect(orgs[0].Guid).Should(Equal("org-guid"))
+			Expect(orgs[0].Name).Should(Equal("test"))
+		})
+	})
+
+	Context("Apply", func() {
+		It("runs every step", func() {
+			err := cfMgmt.Apply("ldap-password")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(userMgr.InitializeLdapCallCount()).Should(Equal(1))
+			Expect(userMgr.InitializeLdapArgsForCall(0)).Should(Equal("ldap-password"))
+			Expect(userMgr.DeinitializeLdapCallCount()).Should(Equal(1))
+			Expect(orgMgr.CreateOrgsCallCount()).Should(Equal(1))
+			Expect(spaceMgr.CreateSpacesCallCount()).Should(Equal(1))
+			Expect(idpMgr.UpdateIdentityProvidersCallCount()).Should(Equal(1))
+			Expect(tokenMgr.UpdateTokenPolicyCallCount()).Should(Equal(1))
+			Expect(isoSegMgr.ApplyCallCount()).Should(Equal(1))
+			Expect(routeMgr.EnforceInternalRoutesCallCount()).Should(Equal(1))
+			Expect(appMgr.EnforceDockerPolicyCallCount()).Should(Equal(1))
+			Expect(appMgr.EnforceStackPolicyCallCount()).Should(Equal(1))
+			Expect(userMgr.CleanupOrgUsersCallCount()).Should(Equal(1))
+			Expect(userMgr.UpdateRoleGroupsCallCount()).Should(Equal(1))
+			Expect(userMgr.CreatePersonalSpacesCallCount()).Should(Equal(1))
+			Expect(sgMgr.AnnotateEgressCallCount()).Should(Equal(1))
+			Expect(cfMgmt.ApplySteps()).Should(HaveLen(24))
+		})
+
+		It("names a hook before and after every step", func() {
+			hooks := cfMgmt.Hooks()
+			Expect(hooks).Should(HaveLen(50))
+			Expect(hooks[:3]).Should(Equal([]string{"pre-apply", "pre-org-create", "post-org-create"}))
+			Expect(hooks[49]).Should(Equal("post-apply"))
+		})
+
+		It("stops at the first failing step", func() {
+			orgMgr.DeleteOrgsReturns(errors.New("delete failed"))
+			err := cfMgmt.Apply("")
+			Expect(err).Should(MatchError("delete failed"))
+			Expect(orgMgr.CreateOrgsCallCount()).Should(Equal(1))
+			Expect(userMgr.UpdateOrgUsersCallCount()).Should(Equal(0))
+			Expect(userMgr.DeinitializeLdapCallCount()).Should(Equal(1))
+		})
+
+		It("stops before the next step once the context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			orgMgr.CreateOrgsStub = func() error {
+				cancel()
+				return nil
+			}
+			err := cfMgmt.ApplyContext(ctx, "")
+			Expect(err).Should(MatchError("apply stopped before step [Delete Orgs]: context canceled"))
+			Expect(orgMgr.CreateOrgsCallCount()).Should(Equal(1))
+			Expect(orgMgr.DeleteOrgsCallCount()).Should(Equal(0))
+		})
+	})
+
+	Context("ApplyWithFailureBudget", func() {
+		It("continues past failures until the budget is spent", func() {
+			orgMgr.DeleteOrgsReturns(errors.New("delete failed"))
+			quotaMgr.CreateOrgQuotasReturns(errors.New("token expired"))
+			report, err := cfMgmt.ApplyWithFailureBudget(context.Background(), "", 2)
+			Expect(err).Should(MatchError("2 steps failed: [Delete Orgs]: delete failed; [Create Org Quotas]: token expired"))
+			Expect(userMgr.UpdateOrgUsersCallCount()).Should(Equal(1))
+			Expect(spaceMgr.CreateSpacesCallCount()).Should(Equal(0))
+			Expect(report.Steps).Should(HaveLen(24))
+			Expect(report.Steps[0]).Should(Equal(cfmgmt.StepResult{Name: "Creating Orgs", Status: cfmgmt.StepSucceeded}))
+			Expect(report.Steps[1]).Should(Equal(cfmgmt.StepResult{Name: "Delete Orgs", Status: cfmgmt.StepFailed, Error: "delete failed"}))
+			Expect(report.Steps[9].Status).Should(Equal(cfmgmt.StepFailed))
+			Expect(report.Steps[10]).Should(Equal(cfmgmt.StepResult{Name: "Create Spaces", Status: cfmgmt.StepSkipped}))
+			Expect(report.Failed()).Should(HaveLen(2))
+		})
+
+		It("runs every step when failures stay within the budget", func() {
+			orgMgr.DeleteOrgsReturns(errors.New("delete failed"))
+			report, err := cfMgmt.ApplyWithFailureBudget(context.Background(), "", 3)
+			Expect(err).Should(MatchError("delete failed"))
+			Expect(userMgr.CleanupOrgUsersCallCount()).Should(Equal(1))
+			Expect(report.Steps[22]).Should(Equal(cfmgmt.StepResult{Name: "Cleanup Org Users", Status: cfmgmt.StepSucceeded}))
+			Expect(report.Steps[23]).Should(Equal(cfmgmt.StepResult{Name: "Update Role Groups", Status: cfmgmt.StepSucceeded}))
+			Expect(report.String()).Should(ContainSubstring("failed    Delete Orgs: delete failed\n"))
+		})
+
+		It("reports steps the foundation does not support without failing", func() {
+			quotaMgr.CreateOrgQuotasReturns(&korifi.NotSupportedError{Feature: "org quotas"})
+			report, err := cfMgmt.ApplyWithFailureBudget(context.Background(), "", 1)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(report.Steps).Should(HaveLen(24))
+			Expect(report.Steps[9]).Should(Equal(cfmgmt.StepResult{Name: "Create Org Quotas", Status: cfmgmt.StepUnsupported, Error: "korifi does not support org quotas"}))
+			Expect(report.Failed()).Should(BeEmpty())
+		})
+
+		It("does not run a step once a plugin at its pre hook fails", func() {
+			cfMgmt.Plugins = &plugin.Runner{Plugins: []config.Plugin{{Name: "gate", Command: "false", Hooks: []string{"pre-org-create"}, FailApply: true}}}
+			report, err := cfMgmt.ApplyWithFailureBudget(context.Background(), "", 1)
+			Expect(err).Should(MatchError("plugin [gate] failed at hook [pre-org-create]: exit status 1"))
+			Expect(orgMgr.CreateOrgsCallCount()).Should(Equal(0))
+			Expect(report.Steps[0]).Should(Equal(cfmgmt.StepResult{Name: "Creating Orgs", Status: cfmgmt.StepFailed, Error: err.Error()}))
+		})
+
+		It("fails a step once a plugin at its post hook fails", func() {
+			cfMgmt.Plugins = &plugin.Runner{Plugins: []config.Plugin{{Name: "gate", Command: "false", Hooks: []string{"post-org-user-sync"}, FailApply: true}}}
+			report, err := cfMgmt.ApplyWithFailureBudget(context.Background(), "", 2)
+			Expect(err).Should(MatchError("plugin [gate] failed at hook [post-org-user-sync]: exit status 1"))
+			Expect(userMgr.UpdateOrgUsersCallCount()).Should(Equal(1))
+			Expect(report.Steps[4].Status).Should(Equal(cfmgmt.StepFailed))
+			Expect(report.Failed()).Should(HaveLen(1))
+		})
+
+		It("skips every step when ldap cannot be initialized", func() {
+			userMgr.InitializeLdapReturns(errors.New("ldap down"))
+			report, err := cfMgmt.ApplyWithFailureBudget(context.Background(), "", 5)
+			Expect(err).Should(MatchError("ldap down"))
+			Expect(report.Steps).Should(HaveLen(24))
+			Expect(report.Steps[0].Status).Should(Equal(cfmgmt.StepSkipped))
+		})
+	})
+
+	Context("ApplyWithCheckpoint", func() {
+		var scope *config.OrgScope
+
+		BeforeEach(func() {
+			reader := new(configfakes.FakeReader)
+			reader.GetOrgConfigsReturns([]config.OrgConfig{{Org: "org1"}, {Org: "org2"}, {Org: "org3"}}, nil)
+			scope = config.NewOrgScope(reader)
+			cfMgmt.OrgScope = scope
+		})
+
+		It("runs the per org steps one org at a time", func() {
+			var orgs []string
+			orgMgr.CreateOrgsStub = func() error {
+				orgConfigs, _ := scope.GetOrgConfigs()
+				Expect(orgConfigs).Should(HaveLen(1))
+				orgs = append(orgs, orgConfigs[0].Org)
+				return nil
+			}
+			report, checkpoint, err := cfMgmt.ApplyWithCheckpoint(context.Background(), "", 1, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(checkpoint).Should(BeNil())
+			Expect(orgs).Should(Equal([]string{"org1", "org2", "org3"}))
+			Expect(orgMgr.DeleteOrgsCallCount()).Should(Equal(1))
+			Expect(report.Steps[0].Status).Should(Equal(cfmgmt.StepSucceeded))
+			orgConfigs, _ := scope.GetOrgConfigs()
+			Expect(orgConfigs).Should(HaveLen(3))
+		})
+
+		It("finishes the org in flight once the context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			orgMgr.CreateOrgsStub = func() error {
+				cancel()
+				return nil
+			}
+			report, checkpoint, err := cfMgmt.ApplyWithCheckpoint(ctx, "", 1, nil)
+			Expect(err).Should(MatchError("apply stopped during step [Creating Orgs] after 1 orgs: context canceled"))
+			Expect(checkpoint).Should(Equal(&cfmgmt.Checkpoint{Step: "Creating Orgs", Orgs: []string{"org1"}}))
+			Expect(orgMgr.CreateOrgsCallCount()).Should(Equal(1))
+			Expect(report.Steps[0].Status).Should(Equal(cfmgmt.StepSkipped))
+		})
+
+		It("resumes from the checkpoint", func() {
+			report, checkpoint, err := cfMgmt.ApplyWithCheckpoint(context.Background(), "", 1, &cfmgmt.Checkpoint{Step: "Update Org Users", Orgs: []string{"org1", "org2"}})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(checkpoint).Should(BeNil())
+			Expect(orgMgr.CreateOrgsCallCount()).Should(Equal(0))
+			Expect(orgMgr.DeleteOrgsCallCount()).Should(Equal(0))
+			Expect(userMgr.UpdateOrgUsersCallCount()).Should(Equal(1))
+			Expect(spaceMgr.CreateSpacesCallCount()).Should(Equal(3))
+			Expect(report.Steps[1]).Should(Equal(cfmgmt.StepResult{Name: "Delete Orgs", Status: cfmgmt.StepCompleted}))
+			Expect(report.Steps[4].Status).Should(Equal(cfmgmt.StepSucceeded))
+		})
+
+		It("fails to resume from a step that does not exist", func() {
+			_, _, err := cfMgmt.ApplyWithCheckpoint(context.Background(), "", 1, &cfmgmt.Checkpoint{Step: "Unknown"})
+			Expect(err).Should(MatchError("checkpoint step [Unknown] is not an apply step"))
+			Expect(userMgr.InitializeLdapCallCount()).Should(Equal(0))
+		})
+	})
+
+	Context("InjectFailure", func() {
+		It("fails the named step without running it", func() {
+			cfMgmt.InjectFailure = &cfmgmt.FailureInjection{Step: "create spaces"}
+			report, err := cfMgmt.ApplyWithFailureBudget(context.Background(), "", 2)
+			Expect(err).Should(MatchError("injected failure of step [Create Spaces]"))
+			Expect(spaceMgr.CreateSpacesCallCount()).Should(Equal(0))
+			Expect(spaceMgr.DeleteSpacesCallCount()).Should(Equal(1))
+			Expect(report.Steps[10].Status).Should(Equal(cfmgmt.StepFailed))
+		})
+
+		It("fails steps at the rate", func() {
+			draws := 0
+			cfMgmt.InjectFailure = &cfmgmt.FailureInjection{Rate: 0.5, Rand: func() float64 {
+				draws++
+				if draws == 2 {
+					return 0.4
+				}
+				return 0.6
+			}}
+			err := cfMgmt.Apply("")
+			Expect(err).Should(MatchError("injected failure of step [Delete Orgs] at a rate of 0.5"))
+			Expect(orgMgr.CreateOrgsCallCount()).Should(Equal(1))
+			Expect(orgMgr.DeleteOrgsCallCount()).Should(Equal(0))
+		})
+
+		It("requires the step to exist", func() {
+			cfMgmt.InjectFailure = &cfmgmt.FailureInjection{Step: "Unknown"}
+			Expect(cfMgmt.Apply("")).Should(MatchError("failure injection step [Unknown] is not an apply step"))
+			Expect(orgMgr.CreateOrgsCallCount()).Should(Equal(0))
+		})
+
+		It("parses a rate or a step", func() {
+			injection, err := cfmgmt.ParseFailureInjection("20%")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(injection.Rate).Should(Equal(0.2))
+			injection, err = cfmgmt.ParseFailureInjection("0.5")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(injection.Rate).Should(Equal(0.5))
+			injection, err = cfmgmt.ParseFailureInjection("Create Spaces")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(injection.Step).Should(Equal("Create Spaces"))
+			_, err = cfmgmt.ParseFailureInjection("1.5")
+			Expect(err).Should(MatchError("failure injection rate 1.5 must be above 0 and at most 1 (100%)"))
+			_, err = cfmgmt.ParseFailureInjection("x%")
+			Expect(err).Should(MatchError("failure injection rate x% is not a number"))
+		})
+	})
+})
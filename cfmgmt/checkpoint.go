@@ -0,0 +1,84 @@
+package cfmgmt
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Checkpoint records how far an interrupted apply got, so that the next apply
+// resumes from there instead of starting over.
+type Checkpoint struct {
+	// Step is the first step the apply did not complete
+	Step string `json:"step,omitempty"`
+	// Orgs are the orgs Step completed, when it runs one org at a time
+	Orgs    []string  `json:"orgs,omitempty"`
+	Written time.Time `json:"written"`
+
+	// ConfigHash is the hash of the configuration the interrupted apply ran
+	// with, a resume against changed configuration would skip the changes
+	ConfigHash string `json:"config_hash,omitempty"`
+}
+
+// LoadCheckpoint reads the checkpoint, which is nil when the file does not exist.
+func LoadCheckpoint(checkpointFile string) (*Checkpoint, error) {
+	data, err := ioutil.ReadFile(checkpointFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	checkpoint := &Checkpoint{}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("unable to read checkpoint %s: %s", checkpointFile, err)
+	}
+	return checkpoint, nil
+}
+
+// Save writes the checkpoint, creating the parent directory.
+func (c *Checkpoint) Save(checkpointFile string) error {
+	if err := os.MkdirAll(filepath.Dir(checkpointFile), 0755); err != nil {
+		return err
+	}
+	c.Written = time.Now()
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(checkpointFile, data, 0644)
+}
+
+// stepIndex is the index of the step to resume from, zero for an empty or nil
+// checkpoint
+func (c *Checkpoint) stepIndex(steps []Step) (int, error) {
+	if c == nil || c.Step == "" {
+		return 0, nil
+	}
+	for i, step := range steps {
+		if step.Name == c.Step {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("checkpoint step [%s] is not an apply step", c.Step)
+}
+
+// completedOrgs are the orgs the step completed before the checkpoint
+func (c *Checkpoint) completedOrgs(step string) []string {
+	if c == nil || c.Step != step {
+		return nil
+	}
+	return append([]string{}, c.Orgs...)
+}
+
+// next is the checkpoint to resume from the step, after the orgs it
+// completed, nil when not checkpointing
+func (c *Checkpoint) next(step string, orgs []string) *Checkpoint {
+	if c == nil {
+		return nil
+	}
+	return &Checkpoint{Step: step, Orgs: orgs}
+}
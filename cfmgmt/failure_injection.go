@@ -0,0 +1,82 @@
+package cfmgmt
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FailureInjection fails apply steps without running them, so that platform
+// teams can test how their pipelines alert on and retry failed runs without
+// breaking the foundation.
+type FailureInjection struct {
+	// Step, when set, is the name of the step to fail
+	Step string
+	// Rate is the probability, from 0 to 1, of failing each step
+	Rate float64
+	// Rand returns a number in [0,1), rand.Float64 when nil
+	Rand func() float64
+}
+
+// ParseFailureInjection reads either a rate, such as 0.2 or 20%, or the name
+// of an apply step, such as "Create Spaces".
+func ParseFailureInjection(spec string) (*FailureInjection, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, fmt.Errorf("failure injection must be a rate or a step name")
+	}
+	value, percent := spec, strings.HasSuffix(spec, "%")
+	if percent {
+		value = strings.TrimSpace(strings.TrimSuffix(spec, "%"))
+	}
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		if percent {
+			return nil, fmt.Errorf("failure injection rate %s is not a number", spec)
+		}
+		return &FailureInjection{Step: spec}, nil
+	}
+	if percent {
+		rate = rate / 100
+	}
+	if rate <= 0 || rate > 1 {
+		return nil, fmt.Errorf("failure injection rate %s must be above 0 and at most 1 (100%%)", spec)
+	}
+	return &FailureInjection{Rate: rate, Rand: rand.New(rand.NewSource(time.Now().UnixNano())).Float64}, nil
+}
+
+// validate fails when the step to fail is not an apply step
+func (f *FailureInjection) validate(steps []Step) error {
+	if f == nil || f.Step == "" {
+		return nil
+	}
+	for _, step := range steps {
+		if strings.EqualFold(step.Name, f.Step) {
+			return nil
+		}
+	}
+	return fmt.Errorf("failure injection step [%s] is not an apply step", f.Step)
+}
+
+// inject is the failure of the step, nil when it is to run
+func (f *FailureInjection) inject(step string) error {
+	if f == nil {
+		return nil
+	}
+	if f.Step != "" {
+		if strings.EqualFold(step, f.Step) {
+			return fmt.Errorf("injected failure of step [%s]", step)
+		}
+		return nil
+	}
+	random := f.Rand
+	if random == nil {
+		random = rand.Float64
+	}
+	if random() < f.Rate {
+		return fmt.Errorf("injected failure of step [%s] at a rate of %g", step, f.Rate)
+	}
+	return nil
+}
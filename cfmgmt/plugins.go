@@ -0,0 +1,77 @@
+package cfmgmt
+
+import (
+	"strings"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/plugin"
+)
+
+// ApplyHook is the hook of apply itself, run as pre-apply before the first
+// step and post-apply after the last
+const ApplyHook = "apply"
+
+// stepHooks are the hooks of the apply steps, which plugins run at as pre- and
+// post- followed by the hook of the step
+var stepHooks = map[string]string{
+	"Creating Orgs":                      "org-create",
+	"Delete Orgs":                        "org-delete",
+	"Update Identity Providers":          "identity-provider-update",
+	"Update Token Policy":                "token-policy-update",
+	"Update Org Users":                   "org-user-sync",
+	"Create Global Security Groups":      "global-security-group-create",
+	"Assign Default Security Groups":     "default-security-group-assign",
+	"Create Private Domains":             "private-domain-create",
+	"Share Private Domains":              "private-domain-share",
+	"Create Org Quotas":                  "org-quota-create",
+	"Create Spaces":                      "space-create",
+	"Delete Spaces":                      "space-delete",
+	"Update Spaces":                      "space-update",
+	"Update Space Users":                 "space-user-sync",
+	"Create Personal Spaces":             "personal-space-create",
+	"Create Space Quotas":                "space-quota-create",
+	"Create Application Security Groups": "application-security-group-create",
+	"Annotate Egress":                    "egress-annotate",
+	"Isolation Segments":                 "isolation-segment-update",
+	"Internal Routes":                    "internal-route-enforce",
+	"Docker Policy":                      "docker-policy-enforce",
+	"Stack Policy":                       "stack-policy-enforce",
+	"Cleanup Org Users":                  "org-user-cleanup",
+	"Update Role Groups":                 "role-group-sync",
+}
+
+// StepHook returns the hook of the step, such as org-create for Creating Orgs.
+func StepHook(name string) string {
+	if hook, ok := stepHooks[name]; ok {
+		return hook
+	}
+	return strings.ToLower(strings.Replace(name, " ", "-", -1))
+}
+
+// Hooks lists every hook plugins can run at, in the order apply runs them.
+func (m *CFMgmt) Hooks() []string {
+	hooks := []string{plugin.Pre + ApplyHook}
+	for _, step := range m.ApplySteps() {
+		hooks = append(hooks, plugin.Pre+StepHook(step.Name), plugin.Post+StepHook(step.Name))
+	}
+	return append(hooks, plugin.Post+ApplyHook)
+}
+
+// LoadPlugins registers the plugins of the global configuration, which apply
+// then runs at their hooks.
+func (m *CFMgmt) LoadPlugins(peek bool) error {
+	globalConfig, err := config.NewManager(m.ConfigDirectory).GetGlobalConfig()
+	if err != nil {
+		return err
+	}
+	m.Plugins, err = plugin.NewRunner(globalConfig.Plugins, m.Hooks(), m.ConfigDirectory, m.SystemDomain, peek)
+	return err
+}
+
+func stepResult(status string, err error) *plugin.Result {
+	result := &plugin.Result{Status: status}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
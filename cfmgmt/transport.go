@@ -0,0 +1,121 @@
+package cfmgmt
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type requestsKey struct{}
+
+// WithRequestContext returns ctx for an apply whose managers bind their api
+// requests to requests rather than to ctx, so that once ctx is done the apply
+// stops at the next safe point while the requests in flight complete, and
+// once requests is done those are cancelled as well.
+func WithRequestContext(ctx, requests context.Context) context.Context {
+	return context.WithValue(ctx, requestsKey{}, requests)
+}
+
+// requestContext is the context the managers bind the api requests of an
+// apply to, ctx itself unless WithRequestContext set another.
+func requestContext(ctx context.Context) context.Context {
+	if requests, ok := ctx.Value(requestsKey{}).(context.Context); ok {
+		return requests
+	}
+	return ctx
+}
+
+// requestScope is the context of the apply in progress, which the transports
+// of the managers bind their requests to on top of their own context.
+type requestScope struct {
+	mutex sync.Mutex
+	ctx   context.Context
+}
+
+// bind binds the requests to ctx until the returned release is called.
+func (s *requestScope) bind(ctx context.Context) func() {
+	if s == nil {
+		return func() {}
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.ctx = ctx
+	return func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		s.ctx = nil
+	}
+}
+
+func (s *requestScope) current() context.Context {
+	if s == nil {
+		return nil
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.ctx
+}
+
+// contextTransport binds every request to a context so in-flight calls are
+// cancelled along with it, as well as with the apply in progress, and
+// enforces an optional per request timeout.
+type contextTransport struct {
+	ctx     context.Context
+	scope   *requestScope
+	timeout time.Duration
+	base    http.RoundTripper
+}
+
+func newContextTransport(ctx context.Context, scope *requestScope, timeout time.Duration, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &contextTransport{ctx: ctx, scope: scope, timeout: timeout, base: base}
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := t.ctx
+	cancel := func() {}
+	if applying := t.scope.current(); applying != nil {
+		var cancelApplying context.CancelFunc
+		ctx, cancelApplying = context.WithCancel(ctx)
+		stopAfter := context.AfterFunc(applying, cancelApplying)
+		cancel = func() {
+			stopAfter()
+			cancelApplying()
+		}
+	}
+	if t.timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, t.timeout)
+		cancelApplying := cancel
+		cancel = func() {
+			cancelTimeout()
+			cancelApplying()
+		}
+	}
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// the timeout covers reading the body, so only release it once the body is closed
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel func()
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,73 @@
+package cfmgmt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("contextTransport", func() {
+	var (
+		server   *httptest.Server
+		started  chan struct{}
+		requests *requestScope
+		client   *http.Client
+	)
+
+	BeforeEach(func() {
+		started = make(chan struct{}, 1)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/block" {
+				started <- struct{}{}
+				<-r.Context().Done()
+			}
+		}))
+		requests = &requestScope{}
+		client = &http.Client{Transport: newContextTransport(context.Background(), requests, 0, nil)}
+	})
+
+	AfterEach(func() {
+		server.CloseClientConnections()
+		server.Close()
+	})
+
+	It("cancels the requests in flight once the apply is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer requests.bind(requestContext(ctx))()
+		go func() {
+			<-started
+			cancel()
+		}()
+		_, err := client.Get(server.URL + "/block")
+		Expect(err).Should(MatchError(ContainSubstring("context canceled")))
+	})
+
+	It("binds the requests to the request context of the apply", func() {
+		stop, cancelStop := context.WithCancel(context.Background())
+		abort, cancelAbort := context.WithCancel(context.Background())
+		defer requests.bind(requestContext(WithRequestContext(stop, abort)))()
+		cancelStop()
+		resp, err := client.Get(server.URL)
+		Expect(err).ShouldNot(HaveOccurred())
+		resp.Body.Close()
+
+		go func() {
+			<-started
+			cancelAbort()
+		}()
+		_, err = client.Get(server.URL + "/block")
+		Expect(err).Should(MatchError(ContainSubstring("context canceled")))
+	})
+
+	It("leaves requests made outside an apply alone", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		requests.bind(ctx)()
+		resp, err := client.Get(server.URL)
+		Expect(err).ShouldNot(HaveOccurred())
+		resp.Body.Close()
+	})
+})
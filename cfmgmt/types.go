@@ -0,0 +1,125 @@
+package cfmgmt
+
+import (
+	"net/url"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+)
+
+// CFClient is every cloud controller call made by the cf-mgmt managers. It is
+// satisfied by *cfclient.Client and by the in-memory simulator.Foundation.
+type CFClient interface {
+	ListOrgs() ([]cfclient.Org, error)
+	DeleteOrg(guid string, recursive, async bool) error
+	CreateOrg(req cfclient.OrgRequest) (cfclient.Org, error)
+	GetOrgByGuid(guid string) (cfclient.Org, error)
+	UpdateOrg(orgGUID string, orgRequest cfclient.OrgRequest) (cfclient.Org, error)
+
+	GetSpaceByGuid(spaceGUID string) (cfclient.Space, error)
+	UpdateSpace(spaceGUID string, req cfclient.SpaceRequest) (cfclient.Space, error)
+	ListSpacesByQuery(query url.Values) ([]cfclient.Space, error)
+	CreateSpace(req cfclient.SpaceRequest) (cfclient.Space, error)
+	DeleteSpace(guid string, recursive, async bool) error
+
+	ListDomains() ([]cfclient.Domain, error)
+	CreateDomain(name, orgGuid string) (*cfclient.Domain, error)
+	ShareOrgPrivateDomain(orgGUID, privateDomainGUID string) (*cfclient.Domain, error)
+	ListOrgPrivateDomains(orgGUID string) ([]cfclient.Domain, error)
+	DeleteDomain(guid string) error
+	UnshareOrgPrivateDomain(orgGUID, privateDomainGUID string) error
+	ListSharedDomains() ([]cfclient.SharedDomain, error)
+
+	ListRoutesByQuery(query url.Values) ([]cfclient.Route, error)
+	DeleteRoute(guid string) error
+
+	ListAppsByQuery(query url.Values) ([]cfclient.App, error)
+	UpdateApp(guid string, aur cfclient.AppUpdateResource) (cfclient.UpdateResponse, error)
+	ListStacks() ([]cfclient.Stack, error)
+	ListTasksByQuery(query url.Values) ([]cfclient.Task, error)
+
+	ListServiceInstancesByQuery(query url.Values) ([]cfclient.ServiceInstance, error)
+	ListServiceBrokers() ([]cfclient.ServiceBroker, error)
+	ListServices() ([]cfclient.Service, error)
+	ListServicePlans() ([]cfclient.ServicePlan, error)
+	ListServicePlanVisibilities() ([]cfclient.ServicePlanVisibility, error)
+	ListServiceBindingsByQuery(query url.Values) ([]cfclient.ServiceBinding, error)
+	ListServiceKeysByQuery(query url.Values) ([]cfclient.ServiceKey, error)
+	CreateServiceKey(req cfclient.CreateServiceKeyRequest) (cfclient.ServiceKey, error)
+	DeleteServiceKey(guid string) error
+
+	ListEventsByQuery(query url.Values) ([]cfclient.Event, error)
+
+	ListOrgSpaceQuotas(orgGUID string) ([]cfclient.SpaceQuota, error)
+	UpdateSpaceQuota(spaceQuotaGUID string, spaceQuote cfclient.SpaceQuotaRequest) (*cfclient.SpaceQuota, error)
+	AssignSpaceQuota(quotaGUID, spaceGUID string) error
+	CreateSpaceQuota(spaceQuote cfclient.SpaceQuotaRequest) (*cfclient.SpaceQuota, error)
+	GetSpaceQuotaByName(name string) (cfclient.SpaceQuota, error)
+	ListOrgQuotas() ([]cfclient.OrgQuota, error)
+	CreateOrgQuota(orgQuote cfclient.OrgQuotaRequest) (*cfclient.OrgQuota, error)
+	UpdateOrgQuota(orgQuotaGUID string, orgQuota cfclient.OrgQuotaRequest) (*cfclient.OrgQuota, error)
+	GetOrgQuotaByName(name string) (cfclient.OrgQuota, error)
+
+	ListSecGroups() ([]cfclient.SecGroup, error)
+	CreateSecGroup(name string, rules []cfclient.SecGroupRule, spaceGuids []string) (*cfclient.SecGroup, error)
+	UpdateSecGroup(guid, name string, rules []cfclient.SecGroupRule, spaceGuids []string) (*cfclient.SecGroup, error)
+	BindSecGroup(secGUID, spaceGUID string) error
+	BindStagingSecGroupToSpace(secGUID, spaceGUID string) error
+	BindRunningSecGroup(secGUID string) error
+	BindStagingSecGroup(secGUID string) error
+	UnbindRunningSecGroup(secGUID string) error
+	UnbindStagingSecGroup(secGUID string) error
+	GetSecGroup(guid string) (*cfclient.SecGroup, error)
+	ListSpaceSecGroups(spaceGUID string) ([]cfclient.SecGroup, error)
+
+	ListIsolationSegments() ([]cfclient.IsolationSegment, error)
+	ListIsolationSegmentsByQuery(query url.Values) ([]cfclient.IsolationSegment, error)
+	CreateIsolationSegment(name string) (*cfclient.IsolationSegment, error)
+	DeleteIsolationSegmentByGUID(guid string) error
+	GetIsolationSegmentByGUID(guid string) (*cfclient.IsolationSegment, error)
+	AddIsolationSegmentToOrg(isolationSegmentGUID, orgGUID string) error
+	RemoveIsolationSegmentFromOrg(isolationSegmentGUID, orgGUID string) error
+	AddIsolationSegmentToSpace(isolationSegmentGUID, spaceGUID string) error
+	RemoveIsolationSegmentFromSpace(isolationSegmentGUID, spaceGUID string) error
+	DefaultIsolationSegmentForOrg(orgGUID, isolationSegmentGUID string) error
+	ResetDefaultIsolationSegmentForOrg(orgGUID string) error
+	IsolationSegmentForSpace(spaceGUID, isolationSegmentGUID string) error
+	ResetIsolationSegmentForSpace(spaceGUID string) error
+
+	ListOrgUsers(orgGUID string) ([]cfclient.User, error)
+	ListOrgAuditors(orgGUID string) ([]cfclient.User, error)
+	ListOrgManagers(orgGUID string) ([]cfclient.User, error)
+	ListOrgBillingManagers(orgGUID string) ([]cfclient.User, error)
+	AssociateOrgUserByUsername(orgGUID, userName string) (cfclient.Org, error)
+	AssociateOrgAuditorByUsername(orgGUID, name string) (cfclient.Org, error)
+	AssociateOrgManagerByUsername(orgGUID, name string) (cfclient.Org, error)
+	AssociateOrgBillingManagerByUsername(orgGUID, name string) (cfclient.Org, error)
+	RemoveOrgUserByUsername(orgGUID, name string) error
+	RemoveOrgAuditorByUsername(orgGUID, name string) error
+	RemoveOrgBillingManagerByUsername(orgGUID, name string) error
+	RemoveOrgManagerByUsername(orgGUID, name string) error
+	AssociateOrgUser(orgGUID, userGUID string) (cfclient.Org, error)
+	AssociateOrgAuditor(orgGUID, userGUID string) (cfclient.Org, error)
+	AssociateOrgManager(orgGUID, userGUID string) (cfclient.Org, error)
+	AssociateOrgBillingManager(orgGUID, userGUID string) (cfclient.Org, error)
+	RemoveOrgUser(orgGUID, userGUID string) error
+	RemoveOrgAuditor(orgGUID, userGUID string) error
+	RemoveOrgManager(orgGUID, userGUID string) error
+	RemoveOrgBillingManager(orgGUID, userGUID string) error
+
+	ListSpaceAuditors(spaceGUID string) ([]cfclient.User, error)
+	ListSpaceManagers(spaceGUID string) ([]cfclient.User, error)
+	ListSpaceDevelopers(spaceGUID string) ([]cfclient.User, error)
+	AssociateSpaceAuditorByUsername(spaceGUID, userName string) (cfclient.Space, error)
+	AssociateSpaceDeveloperByUsername(spaceGUID, userName string) (cfclient.Space, error)
+	AssociateSpaceManagerByUsername(spaceGUID, userName string) (cfclient.Space, error)
+	RemoveSpaceAuditorByUsername(spaceGUID, userName string) error
+	RemoveSpaceDeveloperByUsername(spaceGUID, userName string) error
+	RemoveSpaceManagerByUsername(spaceGUID, userName string) error
+	AssociateSpaceAuditor(spaceGUID, userGUID string) (cfclient.Space, error)
+	AssociateSpaceDeveloper(spaceGUID, userGUID string) (cfclient.Space, error)
+	AssociateSpaceManager(spaceGUID, userGUID string) (cfclient.Space, error)
+	RemoveSpaceAuditor(spaceGUID, userGUID string) error
+	RemoveSpaceDeveloper(spaceGUID, userGUID string) error
+	RemoveSpaceManager(spaceGUID, userGUID string) error
+	DeleteUser(userGUID string) error
+}
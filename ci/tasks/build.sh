@@ -26,13 +26,22 @@ mkdir -p ${WORKING_DIR}
 cp -R ${SOURCE_DIR}/* ${WORKING_DIR}/.
 cd ${WORKING_DIR}
 glide install
-CGO_ENABLED=0 GOOS=linux GOARCH=amd64 go build -o ${OUTPUT_DIR}/cf-mgmt-linux -ldflags "-X github.com/pivotalservices/cf-mgmt/configcommands.VERSION=${DRAFT_VERSION}" cmd/cf-mgmt/main.go
-GOOS=darwin GOARCH=amd64 go build -o ${OUTPUT_DIR}/cf-mgmt-osx -ldflags "-X github.com/pivotalservices/cf-mgmt/configcommands.VERSION=${DRAFT_VERSION}" cmd/cf-mgmt/main.go
-GOOS=windows GOARCH=amd64 go build -o ${OUTPUT_DIR}/cf-mgmt.exe -ldflags "-X github.com/pivotalservices/cf-mgmt/configcommands.VERSION=${DRAFT_VERSION}" cmd/cf-mgmt/main.go
-
-CGO_ENABLED=0 GOOS=linux GOARCH=amd64 go build -o ${OUTPUT_DIR}/cf-mgmt-config-linux -ldflags "-X github.com/pivotalservices/cf-mgmt/configcommands.VERSION=${DRAFT_VERSION}" cmd/cf-mgmt-config/main.go
-GOOS=darwin GOARCH=amd64 go build -o ${OUTPUT_DIR}/cf-mgmt-config-osx -ldflags "-X github.com/pivotalservices/cf-mgmt/configcommands.VERSION=${DRAFT_VERSION}" cmd/cf-mgmt-config/main.go
-GOOS=windows GOARCH=amd64 go build -o ${OUTPUT_DIR}/cf-mgmt-config.exe -ldflags "-X github.com/pivotalservices/cf-mgmt/configcommands.VERSION=${DRAFT_VERSION}" cmd/cf-mgmt-config/main.go
+# the embedded assets must match generated/files, go-bindata is not rerun here
+go test ./generated/
+
+LDFLAGS="-X github.com/pivotalservices/cf-mgmt/configcommands.VERSION=${DRAFT_VERSION}"
+# GOOS GOARCH suffix of each released binary
+PLATFORMS="linux amd64 -linux
+darwin amd64 -osx
+windows amd64 .exe
+linux arm64 -linux-arm64"
+
+echo "${PLATFORMS}" | while read goos goarch suffix; do
+  for cmd in cf-mgmt cf-mgmt-config; do
+    echo "building ${cmd}${suffix}"
+    CGO_ENABLED=0 GOOS=${goos} GOARCH=${goarch} go build -o ${OUTPUT_DIR}/${cmd}${suffix} -ldflags "${LDFLAGS}" cmd/${cmd}/main.go
+  done
+done
 
 echo ${DRAFT_VERSION} > ${OUTPUT_DIR}/name
 echo ${DRAFT_VERSION} > ${OUTPUT_DIR}/tag
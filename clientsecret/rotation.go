@@ -0,0 +1,71 @@
+package clientsecret
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/pivotalservices/cf-mgmt/redact"
+	"github.com/pivotalservices/cf-mgmt/secretstore"
+	"github.com/pivotalservices/cf-mgmt/uaa"
+	"github.com/pkg/errors"
+	"github.com/xchapter7x/lo"
+)
+
+//Rotation - rotates the secret of the uaa client cf-mgmt authenticates as
+type Rotation struct {
+	UAAMgr   uaa.Manager
+	ClientID string
+	// Secret is the current secret of the client
+	Secret string
+	// Stores are written the new secret, which is never printed
+	Stores []secretstore.Store
+	// Verify gets a token of the client with a secret
+	Verify func(secret string) error
+	Peek   bool
+}
+
+//Rotate - adds a generated secret to the client next to the current one, verifies a token can be
+//acquired with it, writes it to the stores and then deletes the current secret, so that the client
+//authenticates with the current secret until the new one is known to work and kept
+func (r *Rotation) Rotate() error {
+	if len(r.Stores) == 0 {
+		return fmt.Errorf("the new secret of client %s must be written to credhub, vault or a file", r.ClientID)
+	}
+	secret, err := generateSecret()
+	if err != nil {
+		return err
+	}
+	redact.Secrets(secret)
+	if err := r.UAAMgr.AddClientSecret(r.ClientID, r.Secret, secret); err != nil {
+		return err
+	}
+	if r.Peek {
+		for _, store := range r.Stores {
+			lo.G.Infof("[dry-run]: writing the new secret of client %s to %s", r.ClientID, store)
+		}
+		return r.UAAMgr.DeleteOldClientSecret(r.ClientID)
+	}
+	if err := r.Verify(secret); err != nil {
+		return errors.Wrapf(err, "the new secret of client %s was added but cannot get a token, the current secret was kept", r.ClientID)
+	}
+	for _, store := range r.Stores {
+		if err := store.Put(secret); err != nil {
+			return errors.Wrapf(err, "unable to write the new secret of client %s to %s, the current secret was kept", r.ClientID, store)
+		}
+		lo.G.Infof("new secret of client %s written to %s", r.ClientID, store)
+	}
+	if err := r.UAAMgr.DeleteOldClientSecret(r.ClientID); err != nil {
+		return err
+	}
+	lo.G.Infof("secret of client %s rotated", r.ClientID)
+	return nil
+}
+
+func generateSecret() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
@@ -0,0 +1,94 @@
+package clientsecret_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotalservices/cf-mgmt/clientsecret"
+	"github.com/pivotalservices/cf-mgmt/secretstore"
+	uaafakes "github.com/pivotalservices/cf-mgmt/uaa/fakes"
+)
+
+type memoryStore struct {
+	value string
+	err   error
+}
+
+func (s *memoryStore) Put(value string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.value = value
+	return nil
+}
+
+func (s *memoryStore) String() string {
+	return "memory"
+}
+
+var _ = Describe("given a client secret rotation", func() {
+	var (
+		fakeUaa  *uaafakes.FakeManager
+		store    *memoryStore
+		verified []string
+		rotation *Rotation
+	)
+
+	BeforeEach(func() {
+		fakeUaa = new(uaafakes.FakeManager)
+		store = &memoryStore{}
+		verified = nil
+		rotation = &Rotation{
+			UAAMgr:   fakeUaa,
+			ClientID: "cf-mgmt",
+			Secret:   "current",
+			Stores:   []secretstore.Store{store},
+			Verify: func(secret string) error {
+				verified = append(verified, secret)
+				return nil
+			},
+		}
+	})
+
+	It("adds, verifies and stores a new secret before deleting the current one", func() {
+		Expect(rotation.Rotate()).Should(Succeed())
+		Expect(fakeUaa.AddClientSecretCallCount()).Should(Equal(1))
+		clientID, oldSecret, newSecret := fakeUaa.AddClientSecretArgsForCall(0)
+		Expect(clientID).Should(Equal("cf-mgmt"))
+		Expect(oldSecret).Should(Equal("current"))
+		Expect(newSecret).ShouldNot(BeEmpty())
+		Expect(verified).Should(Equal([]string{newSecret}))
+		Expect(store.value).Should(Equal(newSecret))
+		Expect(fakeUaa.DeleteOldClientSecretCallCount()).Should(Equal(1))
+	})
+
+	It("keeps the current secret when the new one cannot get a token", func() {
+		rotation.Verify = func(string) error { return errors.New("unauthorized") }
+		err := rotation.Rotate()
+		Expect(err).Should(MatchError("the new secret of client cf-mgmt was added but cannot get a token, the current secret was kept: unauthorized"))
+		Expect(store.value).Should(BeEmpty())
+		Expect(fakeUaa.DeleteOldClientSecretCallCount()).Should(Equal(0))
+	})
+
+	It("keeps the current secret when the new one cannot be stored", func() {
+		store.err = errors.New("forbidden")
+		err := rotation.Rotate()
+		Expect(err).Should(MatchError("unable to write the new secret of client cf-mgmt to memory, the current secret was kept: forbidden"))
+		Expect(fakeUaa.DeleteOldClientSecretCallCount()).Should(Equal(0))
+	})
+
+	It("requires a store for the new secret", func() {
+		rotation.Stores = nil
+		Expect(rotation.Rotate()).Should(MatchError("the new secret of client cf-mgmt must be written to credhub, vault or a file"))
+		Expect(fakeUaa.AddClientSecretCallCount()).Should(Equal(0))
+	})
+
+	It("neither verifies nor stores the secret when peeking", func() {
+		rotation.Peek = true
+		Expect(rotation.Rotate()).Should(Succeed())
+		Expect(verified).Should(BeEmpty())
+		Expect(store.value).Should(BeEmpty())
+		Expect(fakeUaa.DeleteOldClientSecretCallCount()).Should(Equal(1))
+	})
+})
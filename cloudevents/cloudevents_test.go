@@ -0,0 +1,140 @@
+package cloudevents_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/cloudevents"
+	"github.com/pivotalservices/cf-mgmt/simulator"
+)
+
+var _ = Describe("given change events", func() {
+	var (
+		changes = []simulator.Change{
+			{Action: simulator.ActionCreate, Kind: "space", Name: "org1/space1"},
+			{Action: simulator.ActionDelete, Kind: "space role", Name: "org1/space1 developer alice"},
+		}
+		events []cloudevents.Event
+	)
+
+	BeforeEach(func() {
+		events = cloudevents.NewEvents("sys.example.com", cloudevents.Performed, changes)
+	})
+
+	It("describes each change as a cloud event", func() {
+		Expect(events).Should(HaveLen(2))
+		Expect(events[0].SpecVersion).Should(Equal("1.0"))
+		Expect(events[0].Source).Should(Equal("/cf-mgmt/sys.example.com"))
+		Expect(events[0].Type).Should(Equal("io.cf-mgmt.space.create.performed"))
+		Expect(events[0].Subject).Should(Equal("org1/space1"))
+		Expect(events[0].Data).Should(Equal(changes[0]))
+		Expect(events[1].Type).Should(Equal("io.cf-mgmt.space-role.delete.performed"))
+		Expect(events[0].ID).ShouldNot(Equal(events[1].ID))
+	})
+
+	It("requires a sink it can send to", func() {
+		_, err := cloudevents.NewSink("ftp://example.com/events", false)
+		Expect(err).Should(MatchError("events sink ftp://example.com/events must be an http, https, kafka+http, kafka+https or nats url"))
+		_, err = cloudevents.NewSink("nats://nats.example.com:4222", false)
+		Expect(err).Should(HaveOccurred())
+		_, err = cloudevents.NewSink("kafka+https://kafka-rest.example.com/cf-mgmt", false)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("posts each event to an http sink", func() {
+		var bodies []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Get("Content-Type")).Should(Equal("application/cloudevents+json"))
+			body, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+		sink, err := cloudevents.NewSink(server.URL+"/events", false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(sink.Send(events)).Should(Succeed())
+		Expect(bodies).Should(HaveLen(2))
+		event := cloudevents.Event{}
+		Expect(json.Unmarshal([]byte(bodies[1]), &event)).Should(Succeed())
+		Expect(event.Type).Should(Equal("io.cf-mgmt.space-role.delete.performed"))
+	})
+
+	It("verifies the certificate of an https sink unless skipping ssl validation", func() {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+		sink, err := cloudevents.NewSink(server.URL+"/events", false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(sink.Send(events)).ShouldNot(Succeed())
+		sink, err = cloudevents.NewSink(server.URL+"/events", true)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(sink.Send(events)).Should(Succeed())
+	})
+
+	It("produces the events to the topic of a kafka rest proxy", func() {
+		var body map[string][]struct {
+			Key   string            `json:"key"`
+			Value cloudevents.Event `json:"value"`
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).Should(Equal("/topics/cf-mgmt"))
+			Expect(r.Header.Get("Content-Type")).Should(Equal("application/vnd.kafka.json.v2+json"))
+			Expect(json.NewDecoder(r.Body).Decode(&body)).Should(Succeed())
+		}))
+		defer server.Close()
+		sink, err := cloudevents.NewSink(strings.Replace(server.URL, "http://", "kafka+http://", 1)+"/topics/cf-mgmt", false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(sink.Send(events)).Should(Succeed())
+		Expect(body["records"]).Should(HaveLen(2))
+		Expect(body["records"][0].Key).Should(Equal("org1/space1"))
+		Expect(body["records"][0].Value.Type).Should(Equal("io.cf-mgmt.space.create.performed"))
+	})
+
+	It("publishes the events to a nats subject", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ShouldNot(HaveOccurred())
+		defer listener.Close()
+		received := make(chan []string, 1)
+		go func() {
+			defer GinkgoRecover()
+			conn, err := listener.Accept()
+			Expect(err).ShouldNot(HaveOccurred())
+			defer conn.Close()
+			conn.Write([]byte("INFO {\"server_id\":\"test\"}\r\n"))
+			reader := bufio.NewReader(conn)
+			var lines []string
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				line = strings.TrimRight(line, "\r\n")
+				if line == "PING" {
+					conn.Write([]byte("PONG\r\n"))
+					if len(lines) > 1 {
+						received <- lines
+						return
+					}
+					continue
+				}
+				lines = append(lines, line)
+			}
+		}()
+		sink, err := cloudevents.NewSink("nats://cf-mgmt:secret@"+listener.Addr().String()+"/cf-mgmt.changes", false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(sink.Send(events)).Should(Succeed())
+		lines := <-received
+		Expect(lines[0]).Should(ContainSubstring(`"user":"cf-mgmt","pass":"secret"`))
+		Expect(lines[1]).Should(HavePrefix("PUB cf-mgmt.changes "))
+		Expect(lines[2]).Should(ContainSubstring(`"type":"io.cf-mgmt.space.create.performed"`))
+		Expect(lines[3]).Should(HavePrefix("PUB cf-mgmt.changes "))
+	})
+})
@@ -6,15 +6,36 @@ import (
 
 	flags "github.com/jessevdk/go-flags"
 	"github.com/pivotalservices/cf-mgmt/commands"
+	"github.com/pivotalservices/cf-mgmt/console"
+	"github.com/pivotalservices/cf-mgmt/redact"
 )
 
 func main() {
 	parser := flags.NewParser(&commands.CfMgmt, flags.HelpFlag)
 	parser.NamespaceDelimiter = "-"
+	parser.CommandHandler = func(command flags.Commander, args []string) error {
+		if command == nil {
+			return nil
+		}
+		console.Configure(commands.CfMgmt.NoColor)
+		if err := commands.UseTarget(command, commands.CfMgmt.Target); err != nil {
+			return err
+		}
+		commands.RequireCommandScopes(parser.Active.Name, command)
+		command, err := commands.WithRedaction(commands.WithDryRunPlan(commands.WithLock(commands.WithChangedOnly(parser.Active.Name, command))), commands.CfMgmt.Redact)
+		if err != nil {
+			return err
+		}
+		if commands.CfMgmt.SummaryFile == "" && !commands.CfMgmt.RecordHistory && !commands.CfMgmt.Telemetry &&
+			!commands.HasCommandHooks(parser.Active.Name, command) {
+			return commands.ExecuteWithStats(command, args)
+		}
+		return commands.ExecuteWithSummary(parser.Active.Name, command, args, commands.CfMgmt.SummaryFile)
+	}
 
 	_, err := parser.Parse()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		fmt.Fprintf(os.Stderr, "error: %s\n", redact.String(err.Error()))
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,91 @@
+// Package commandhook runs the shell commands and webhooks configured, as
+// command-hooks in cf-mgmt.yml, before and after cf-mgmt commands, passing
+// each the run summary of the command as json.
+package commandhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/hookexec"
+	"github.com/xchapter7x/lo"
+)
+
+// defaultTimeout limits a run of a hook that sets no timeout-seconds
+const defaultTimeout = 60 * time.Second
+
+// Payload is the json a shell hook reads from stdin and a webhook is posted.
+type Payload struct {
+	Command string `json:"command"`
+	When    string `json:"when"`
+	// Summary is the run summary of the command, which has only the start
+	// time of the command before it runs
+	Summary interface{} `json:"summary"`
+}
+
+// Run runs the hooks configured for the command when, in order, returning
+// the error of the first failing hook that sets fail-command. Failures of
+// other hooks are logged.
+func Run(hooks []config.CommandHook, configDirectory string, payload Payload) error {
+	for _, hook := range hooks {
+		if !hook.RunsFor(payload.Command, payload.When) {
+			continue
+		}
+		timeout := defaultTimeout
+		if hook.TimeoutSeconds > 0 {
+			timeout = time.Duration(hook.TimeoutSeconds) * time.Second
+		}
+		lo.G.Debugf("running %s hook [%s] of %s", payload.When, hook.Name, payload.Command)
+		var err error
+		if hook.URL != "" {
+			err = post(hook, payload, timeout)
+		} else {
+			err = run(hook, configDirectory, payload, timeout)
+		}
+		if err == nil {
+			continue
+		}
+		if hook.FailCommand {
+			return fmt.Errorf("%s hook [%s] of %s failed: %s", payload.When, hook.Name, payload.Command, err)
+		}
+		lo.G.Warningf("%s hook [%s] of %s failed, continuing: %s", payload.When, hook.Name, payload.Command, err)
+	}
+	return nil
+}
+
+func run(hook config.CommandHook, configDirectory string, payload Payload, timeout time.Duration) error {
+	return hookexec.Run(context.Background(), hook.Name, payload, timeout, func(ctx context.Context) *exec.Cmd {
+		cmd := exec.CommandContext(ctx, "sh", "-c", hook.Run)
+		cmd.Dir = configDirectory
+		cmd.Env = append(os.Environ(), "CF_MGMT_COMMAND="+payload.Command, "CF_MGMT_WHEN="+payload.When)
+		return cmd
+	})
+}
+
+func post(hook config.CommandHook, payload Payload, timeout time.Duration) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: timeout}
+	if hook.SkipSSLValidation {
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+	resp, err := client.Post(hook.URL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %d", hook.URL, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package commandhook_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/commandhook"
+	"github.com/pivotalservices/cf-mgmt/config"
+)
+
+var _ = Describe("given command hooks", func() {
+	var (
+		configDir string
+		payload   commandhook.Payload
+	)
+
+	BeforeEach(func() {
+		var err error
+		configDir, err = ioutil.TempDir("", "cf-mgmt-hooks")
+		Expect(err).ShouldNot(HaveOccurred())
+		payload = commandhook.Payload{Command: "apply", When: config.HookAfter, Summary: map[string]string{"status": "succeeded"}}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(configDir)
+	})
+
+	It("runs shell hooks in the config directory with the payload on stdin", func() {
+		hooks := []config.CommandHook{
+			{Name: "cmdb", When: config.HookAfter, Commands: []string{"apply"}, Run: "cat > payload.json; echo $CF_MGMT_COMMAND > command"},
+			{Name: "warm-cache", When: config.HookBefore, Run: "touch warmed"},
+		}
+		Expect(commandhook.Run(hooks, configDir, payload)).Should(Succeed())
+		data, err := ioutil.ReadFile(filepath.Join(configDir, "payload.json"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(data).Should(MatchJSON(`{"command":"apply","when":"after","summary":{"status":"succeeded"}}`))
+		Expect(ioutil.ReadFile(filepath.Join(configDir, "command"))).Should(Equal([]byte("apply\n")))
+		Expect(filepath.Join(configDir, "warmed")).ShouldNot(BeAnExistingFile())
+	})
+
+	It("posts the payload to webhooks", func() {
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).Should(Equal(http.MethodPost))
+			Expect(r.Header.Get("Content-Type")).Should(Equal("application/json"))
+			body, _ = ioutil.ReadAll(r.Body)
+		}))
+		defer server.Close()
+		hooks := []config.CommandHook{{Name: "smoke-tests", When: config.HookAfter, URL: server.URL}}
+		Expect(commandhook.Run(hooks, configDir, payload)).Should(Succeed())
+		result := commandhook.Payload{}
+		Expect(json.Unmarshal(body, &result)).Should(Succeed())
+		Expect(result.Command).Should(Equal("apply"))
+	})
+
+	It("verifies the certificate of webhooks unless they skip ssl validation", func() {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+		hooks := []config.CommandHook{{Name: "smoke-tests", When: config.HookAfter, URL: server.URL, FailCommand: true}}
+		Expect(commandhook.Run(hooks, configDir, payload)).ShouldNot(Succeed())
+		hooks[0].SkipSSLValidation = true
+		Expect(commandhook.Run(hooks, configDir, payload)).Should(Succeed())
+	})
+
+	It("only fails for failing hooks that set fail-command", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+		hooks := []config.CommandHook{
+			{Name: "best-effort", When: config.HookAfter, Run: "exit 3"},
+			{Name: "smoke-tests", When: config.HookAfter, URL: server.URL, FailCommand: true},
+		}
+		err := commandhook.Run(hooks, configDir, payload)
+		Expect(err).Should(MatchError("after hook [smoke-tests] of apply failed: " + server.URL + " returned 502"))
+	})
+})
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pivotalservices/cf-mgmt/user"
+	"github.com/xchapter7x/lo"
+)
+
+type AdminAccessReportCommand struct {
+	BaseCFConfigCommand
+}
+
+//Execute - reports the members of admin-equivalent uaa groups that are not declared in admin-users of cf-mgmt.yml
+func (c *AdminAccessReportCommand) Execute([]string) error {
+	var cfMgmt *CFMgmt
+	var err error
+	if cfMgmt, err = InitializeManagers(c.BaseCFConfigCommand); err != nil {
+		return err
+	}
+	undeclared, err := cfMgmt.UserManager.ListUndeclaredAdmins()
+	if err != nil {
+		return err
+	}
+	for _, access := range undeclared {
+		if access.Type == "USER" {
+			lo.G.Warningf("user %s has admin-equivalent access through group %s but is not in admin-users", access.UserName, access.Group)
+		} else {
+			lo.G.Warningf("group %s member %s of type %s has admin-equivalent access and is not declared", access.Group, access.UserName, access.Type)
+		}
+	}
+	return writeAdminAccess(os.Stdout, undeclared)
+}
+
+func writeAdminAccess(out io.Writer, undeclared []user.AdminAccess) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "USER\tORIGIN\tGROUP\tTYPE")
+	for _, access := range undeclared {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", access.UserName, access.Origin, access.Group, access.Type)
+	}
+	return w.Flush()
+}
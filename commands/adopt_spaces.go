@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/space"
+	"github.com/xchapter7x/lo"
+)
+
+type AdoptSpacesCommand struct {
+	BaseCFConfigCommand
+	GenerateConfig bool `long:"generate-config" env:"GENERATE_CONFIG" description:"Add a spaceConfig.yml stub to the configuration for every unmanaged space"`
+}
+
+//Execute - reports spaces of configured orgs that are not in the configuration and optionally adopts them
+func (c *AdoptSpacesCommand) Execute([]string) error {
+	var cfMgmt *CFMgmt
+	var err error
+	if cfMgmt, err = InitializeManagers(c.BaseCFConfigCommand); err != nil {
+		return err
+	}
+	unmanaged, err := cfMgmt.SpaceManager.ListUnmanagedSpaces()
+	if err != nil {
+		return err
+	}
+	if c.GenerateConfig {
+		if err := adoptSpaces(config.NewManager(c.ConfigDirectory), unmanaged); err != nil {
+			return err
+		}
+	} else {
+		for _, s := range unmanaged {
+			lo.G.Warningf("space %s in org %s is not in the configuration", s.Space.Name, s.Org)
+		}
+	}
+	return writeUnmanagedSpaces(os.Stdout, unmanaged, c.GenerateConfig)
+}
+
+// adoptSpaces adds stubs that only carry the space's ssh setting, so adopting a
+// space does not change it until its roles, quota and asgs are configured.
+func adoptSpaces(cfg config.Manager, unmanaged []space.UnmanagedSpace) error {
+	for _, s := range unmanaged {
+		lo.G.Infof("adding space %s in org %s to the configuration", s.Space.Name, s.Org)
+		if err := cfg.AddSpaceToConfig(&config.SpaceConfig{
+			Org:      s.Org,
+			Space:    s.Space.Name,
+			AllowSSH: s.Space.AllowSSH,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeUnmanagedSpaces(out io.Writer, unmanaged []space.UnmanagedSpace, adopted bool) error {
+	action := "unmanaged"
+	if adopted {
+		action = "adopted"
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ORG\tSPACE\tSTATUS")
+	for _, s := range unmanaged {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Org, s.Space.Name, action)
+	}
+	return w.Flush()
+}
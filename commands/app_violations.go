@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pivotalservices/cf-mgmt/app"
+)
+
+// writeAppViolations prints the apps breaking a policy as a table or as csv,
+// with the detail of each violation, such as its image, in the last column
+func writeAppViolations(out io.Writer, format, detail string, violations []app.Violation) error {
+	if format == "csv" {
+		return writeAppViolationsCSV(out, detail, violations)
+	}
+	return writeAppViolationsTable(out, detail, violations)
+}
+
+func writeAppViolationsTable(out io.Writer, detail string, violations []app.Violation) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ORG\tSPACE\tAPP\tSTATE\t%s\n", strings.ToUpper(detail))
+	for _, violation := range violations {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", violation.Org, violation.Space, violation.App, violation.State, violation.Detail)
+	}
+	return w.Flush()
+}
+
+func writeAppViolationsCSV(out io.Writer, detail string, violations []app.Violation) error {
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"org", "space", "app", "state", detail}); err != nil {
+		return err
+	}
+	for _, violation := range violations {
+		if err := w.Write([]string{violation.Org, violation.Space, violation.App, violation.State, violation.Detail}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -1,105 +1,204 @@
 package commands
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pivotalservices/cf-mgmt/cfmgmt"
+	"github.com/pivotalservices/cf-mgmt/cloudevents"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/redact"
+	"github.com/pivotalservices/cf-mgmt/simulator"
+	"github.com/xchapter7x/lo"
 )
 
 type ApplyCommand struct {
 	BaseCFConfigCommand
 	BasePeekCommand
 	BaseLDAPCommand
+	BaseLockCommand
+	BaseEventsCommand
+	MaxFailures    int    `long:"max-failures" env:"MAX_FAILURES" default:"1" description:"Number of failed steps after which apply stops and skips the remaining steps, until then a failed step is reported and the next steps still run"`
+	SkipPreflight  bool   `long:"skip-preflight" env:"SKIP_PREFLIGHT" description:"Do not verify the credentials, uaa scopes and ldap bind before applying"`
+	Checkpoint     bool   `long:"checkpoint" env:"CHECKPOINT" description:"Run the steps of each org one org at a time so that, once interrupted, apply finishes the org in flight and writes a checkpoint to resume from"`
+	CheckpointFile string `long:"checkpoint-file" env:"CHECKPOINT_FILE" description:"File the checkpoint is written to, defaults to .cf-mgmt-checkpoint.json in the config directory"`
+	InjectFailure  string `long:"inject-failure" env:"INJECT_FAILURE" hidden:"true" description:"Fail apply steps without running them, at a rate such as 0.2 or 20% or the step with this name, to test pipeline alerting and retries"`
+	Resume         bool   `long:"resume" env:"RESUME" description:"Resume from the checkpoint of an interrupted apply, skipping the steps and orgs it completed, implies --checkpoint"`
 }
 
+// defaultCheckpointFile is the checkpoint file in the config directory
+const defaultCheckpointFile = ".cf-mgmt-checkpoint.json"
+
 //Execute - applies all the config in order
 func (c *ApplyCommand) Execute([]string) error {
-	var cfMgmt *CFMgmt
-	var err error
-	if cfMgmt, err = InitializePeekManagers(c.BaseCFConfigCommand, c.Peek); err != nil {
-		return err
-	}
-	if err = cfMgmt.UserManager.InitializeLdap(c.LdapPassword); err != nil {
-		return err
-	}
-	defer cfMgmt.UserManager.DeinitializeLdap()
-	fmt.Println("*********  Creating Orgs")
-	if err = cfMgmt.OrgManager.CreateOrgs(); err != nil {
-		return err
-	}
+	stop, abort, release := InterruptContexts()
+	defer release()
 
-	fmt.Println("*********  Delete Orgs")
-	if err = cfMgmt.OrgManager.DeleteOrgs(); err != nil {
-		return err
+	// a simulated or replayed foundation has no credentials to verify
+	if !c.SkipPreflight && c.Simulate == "" && c.Replay == "" {
+		if err := RunPreflight(c.BaseCFConfigCommand, c.LdapPassword); err != nil {
+			return err
+		}
+		c.scopesVerified = true
 	}
-
-	fmt.Println("*********  Update Org Users")
-	if err = cfMgmt.UserManager.UpdateOrgUsers(); err != nil {
-		return err
-	}
-
-	fmt.Println("*********  Create Global Security Groups")
-	if err = cfMgmt.SecurityGroupManager.CreateGlobalSecurityGroups(); err != nil {
-		return err
-	}
-
-	fmt.Println("*********  Assign Default Security Groups")
-	if err = cfMgmt.SecurityGroupManager.AssignDefaultSecurityGroups(); err != nil {
-		return err
-	}
-
-	fmt.Println("*********  Create Private Domains")
-	if err = cfMgmt.PrivateDomainManager.CreatePrivateDomains(); err != nil {
+	sink, err := c.sink()
+	if err != nil {
 		return err
 	}
+	var cfMgmt *CFMgmt
+	if cfMgmt, err = InitializeManagersWithContext(abort, c.BaseCFConfigCommand, c.Peek); err != nil {
+		return err
+	}
+	if c.InjectFailure != "" {
+		if cfMgmt.InjectFailure, err = cfmgmt.ParseFailureInjection(c.InjectFailure); err != nil {
+			return err
+		}
+		lo.G.Warningf("Injecting failures of apply steps: %s", c.InjectFailure)
+	}
+	if err := cfMgmt.LoadPlugins(c.Peek); err != nil {
+		return err
+	}
+	// the plan is computed for the plugins passed it and the events sink, from a
+	// snapshot that performed changes are then compared with
+	var before *simulator.Snapshot
+	if cfMgmt.Plugins.NeedsPlan() || sink != nil {
+		if before, err = exportSnapshot(c.ConfigDirectory, cfMgmt); err != nil {
+			return err
+		}
+		plan, err := c.plan(before)
+		if err != nil {
+			return err
+		}
+		if cfMgmt.Plugins != nil {
+			cfMgmt.Plugins.Plan = plan
+		}
+		if err := emitChanges(sink, c.SystemDomain, cloudevents.Planned, plan); err != nil {
+			lo.G.Errorf("Unable to send the planned changes to the events sink: %s", err)
+		}
+	}
+	// the first interrupt lets the requests in flight complete, the second cancels them
+	ctx := cfmgmt.WithRequestContext(stop, abort)
+	var report *cfmgmt.ApplyReport
+	if c.Checkpoint || c.Resume {
+		report, err = c.applyWithCheckpoint(ctx, cfMgmt)
+	} else {
+		report, err = cfMgmt.ApplyWithFailureBudget(ctx, c.LdapPassword, c.MaxFailures)
+		if err == nil && !c.Peek {
+			removeCheckpoint(c.checkpointFile())
+		}
+	}
+	if err != nil {
+		fmt.Println("********* Apply Report")
+		fmt.Print(redact.String(report.String()))
+	}
+	if sink != nil && !c.Peek {
+		c.emitPerformed(sink, cfMgmt, before)
+	}
+	return err
+}
 
-	fmt.Println("*********  Share Private Domains")
-	if err = cfMgmt.PrivateDomainManager.SharePrivateDomains(); err != nil {
-		return err
+// exportSnapshot takes a snapshot of the foundation, with the role groups of
+// the configuration
+func exportSnapshot(configDirectory string, cfMgmt *CFMgmt) (*simulator.Snapshot, error) {
+	globalConfig, err := config.NewManager(configDirectory).GetGlobalConfig()
+	if err != nil {
+		return nil, err
 	}
+	return simulator.Export(cfMgmt.Client, cfMgmt.UAAManager, globalConfig.RoleGroups)
+}
 
-	fmt.Println("*********  Create Org Quotas")
-	if err = cfMgmt.QuotaManager.CreateOrgQuotas(); err != nil {
-		return err
+// emitPerformed sends the changes apply made, the differences between the
+// snapshot taken before apply and the foundation, to the sink. Failing to
+// send them is logged rather than failing the apply that made them.
+func (c *ApplyCommand) emitPerformed(sink cloudevents.Sink, cfMgmt *CFMgmt, before *simulator.Snapshot) {
+	after, err := exportSnapshot(c.ConfigDirectory, cfMgmt)
+	var protectedOrgs []string
+	if err == nil {
+		protectedOrgs, err = configuredProtectedOrgs(c.ConfigDirectory)
 	}
-
-	fmt.Println("*********  Create Spaces")
-	if err = cfMgmt.SpaceManager.CreateSpaces(); err != nil {
-		return err
+	var changes []simulator.Change
+	if err == nil {
+		changes, err = simulator.DiffUnprotected(before, after, protectedOrgs)
 	}
-
-	fmt.Println("*********  Delete Spaces")
-	if err = cfMgmt.SpaceManager.DeleteSpaces(); err != nil {
-		return err
+	if err == nil {
+		err = emitChanges(sink, c.SystemDomain, cloudevents.Performed, changes)
 	}
-
-	fmt.Println("*********  Update Spaces")
-	if err = cfMgmt.SpaceManager.UpdateSpaces(); err != nil {
-		return err
+	if err != nil {
+		lo.G.Errorf("Unable to send the performed changes to the events sink: %s", err)
 	}
+}
 
-	fmt.Println("*********  Update Space Users")
-	if err = cfMgmt.UserManager.UpdateSpaceUsers(); err != nil {
-		return err
+// plan computes the changes apply is about to make by applying the
+// configuration to the snapshot of the foundation
+func (c *ApplyCommand) plan(snapshot *simulator.Snapshot) ([]simulator.Change, error) {
+	changes, report, err := simulatePlan(c.BaseCFConfigCommand, snapshot, c.LdapPassword, false)
+	if report == nil {
+		return nil, err
 	}
-
-	fmt.Println("*********  Create Space Quotas")
-	if err = cfMgmt.QuotaManager.CreateSpaceQuotas(); err != nil {
-		return err
+	if err != nil {
+		lo.G.Warningf("The plan is incomplete, planning failed: %s", err)
 	}
+	lo.G.Noticef("Planned %d changes", len(changes))
+	return changes, nil
+}
 
-	fmt.Println("*********  Create Application Security Groups")
-	if err = cfMgmt.SecurityGroupManager.CreateApplicationSecurityGroups(); err != nil {
-		return err
+func (c *ApplyCommand) checkpointFile() string {
+	if c.CheckpointFile != "" {
+		return c.CheckpointFile
 	}
+	return filepath.Join(config.BaseDirectory(c.ConfigDirectory), defaultCheckpointFile)
+}
 
-	fmt.Println("*********  Isolation Segments")
-	if err = cfMgmt.IsolationSegmentManager.Apply(); err != nil {
-		return err
-	}
+// applyWithCheckpoint writes a checkpoint, along with the hash of the
+// configuration, when apply is interrupted. It only resumes from a checkpoint
+// of the same configuration, and removes the checkpoint once apply ran to the
+// end after resuming or without failures.
+func (c *ApplyCommand) applyWithCheckpoint(ctx context.Context, cfMgmt *CFMgmt) (*cfmgmt.ApplyReport, error) {
+	checkpointFile := c.checkpointFile()
+	// no step ran when the checkpoint cannot be resumed, so the report is empty
+	configHash, err := config.ConfigHash(config.NewManager(c.ConfigDirectory))
+	if err != nil {
+		return &cfmgmt.ApplyReport{}, err
+	}
+	var resume *cfmgmt.Checkpoint
+	if c.Resume {
+		if resume, err = cfmgmt.LoadCheckpoint(checkpointFile); err != nil {
+			return &cfmgmt.ApplyReport{}, err
+		}
+		if resume == nil {
+			lo.G.Warningf("No checkpoint in %s, applying every step", checkpointFile)
+		} else if resume.ConfigHash != configHash {
+			return &cfmgmt.ApplyReport{}, fmt.Errorf("checkpoint %s was written for a different configuration, apply without --resume to run every step", checkpointFile)
+		} else {
+			lo.G.Noticef("Resuming from step [%s] after %d orgs, checkpointed %s", resume.Step, len(resume.Orgs), resume.Written.Format(time.RFC3339))
+		}
+	}
+	report, checkpoint, err := cfMgmt.ApplyWithCheckpoint(ctx, c.LdapPassword, c.MaxFailures, resume)
+	if c.Peek {
+		return report, err
+	}
+	if checkpoint != nil {
+		checkpoint.ConfigHash = configHash
+		if saveErr := checkpoint.Save(checkpointFile); saveErr != nil {
+			lo.G.Errorf("Unable to write checkpoint %s: %s", checkpointFile, saveErr)
+			return report, err
+		}
+		lo.G.Warningf("Wrote checkpoint to %s, run apply with --resume to continue from step [%s]", checkpointFile, checkpoint.Step)
+		return report, err
+	}
+	if resume != nil || err == nil {
+		removeCheckpoint(checkpointFile)
+	}
+	return report, err
+}
 
-	fmt.Println("*********  Cleanup Org Users")
-	if err = cfMgmt.UserManager.CleanupOrgUsers(); err != nil {
-		return err
+// removeCheckpoint removes the checkpoint of an earlier apply that is no
+// longer needed, such as a stale one once apply ran every step
+func removeCheckpoint(checkpointFile string) {
+	if err := os.Remove(checkpointFile); err != nil && !os.IsNotExist(err) {
+		lo.G.Errorf("Unable to remove checkpoint %s: %s", checkpointFile, err)
 	}
-
-	return nil
 }
@@ -0,0 +1,49 @@
+package commands_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/cfmgmt"
+	"github.com/pivotalservices/cf-mgmt/commands"
+	"github.com/pivotalservices/cf-mgmt/config"
+)
+
+var _ = Describe("ApplyCommand", func() {
+	var (
+		dir            string
+		checkpointFile string
+		command        *commands.ApplyCommand
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "cf-mgmt-apply")
+		Expect(err).ShouldNot(HaveOccurred())
+		configManager := config.NewManager(filepath.Join(dir, "config"))
+		Expect(configManager.CreateConfigIfNotExists("ldap")).Should(Succeed())
+		Expect(configManager.AddOrgToConfig(&config.OrgConfig{Org: "org1"})).Should(Succeed())
+		snapshotFile := filepath.Join(dir, "snapshot.json")
+		Expect(ioutil.WriteFile(snapshotFile, []byte("{}"), 0644)).Should(Succeed())
+		checkpointFile = filepath.Join(dir, "checkpoint.json")
+		command = &commands.ApplyCommand{Resume: true, CheckpointFile: checkpointFile, SkipPreflight: true, MaxFailures: 1}
+		command.ConfigDirectory = filepath.Join(dir, "config")
+		command.SystemDomain = "sys.example.com"
+		command.Simulate = snapshotFile
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("refuses to resume from the checkpoint of a different configuration", func() {
+		checkpoint := &cfmgmt.Checkpoint{Step: "Create Spaces", ConfigHash: "other"}
+		Expect(checkpoint.Save(checkpointFile)).Should(Succeed())
+		err := command.Execute(nil)
+		Expect(err).Should(MatchError("checkpoint " + checkpointFile + " was written for a different configuration, apply without --resume to run every step"))
+		Expect(checkpointFile).Should(BeAnExistingFile())
+	})
+})
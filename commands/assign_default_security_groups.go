@@ -3,6 +3,7 @@ package commands
 type AssignDefaultSecurityGroups struct {
 	BaseCFConfigCommand
 	BasePeekCommand
+	BaseLockCommand
 }
 
 //Execute - creates security groups
@@ -0,0 +1,197 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/generated"
+	"github.com/xchapter7x/lo"
+)
+
+// Pipelines bootstrap-repo generates.
+const (
+	pipelineConcourse     = "concourse"
+	pipelineGitHubActions = "github-actions"
+	pipelineGitLab        = "gitlab"
+	pipelineNone          = "none"
+)
+
+type BootstrapRepoCommand struct {
+	BaseConfigCommand
+	Directory string `long:"dir" env:"REPO_DIR" default:"." description:"Directory of the config repo to bootstrap, created when it does not exist"`
+	Pipeline  string `long:"pipeline" env:"PIPELINE" default:"concourse" choice:"concourse" choice:"github-actions" choice:"gitlab" choice:"none" description:"Pipeline to generate to drive cf-mgmt from the repo"`
+	UAAOrigin string `long:"uaa-origin" env:"UAA_ORIGIN" default:"ldap" description:"Origin of the users in the generated ldap.yml"`
+}
+
+// repoFile is a file of the bootstrapped repo, either an embedded asset or content
+type repoFile struct {
+	path    string
+	asset   string
+	content string
+}
+
+//Execute - creates a config repo ready to commit: the configuration, a pipeline, a README, a .gitignore
+//and a pre-commit hook validating the configuration. Files that already exist are kept.
+func (c *BootstrapRepoCommand) Execute([]string) error {
+	if err := os.MkdirAll(c.Directory, 0755); err != nil {
+		return err
+	}
+	configDir := filepath.Join(c.Directory, c.ConfigDirectory)
+	lo.G.Infof("Initializing config in directory %s", configDir)
+	if err := config.NewManager(configDir).CreateConfigIfNotExists(c.UAAOrigin); err != nil {
+		return err
+	}
+
+	files := []repoFile{
+		{path: "README.md", content: fmt.Sprintf(repoReadme, c.ConfigDirectory, c.pipelineUsage())},
+		{path: ".gitignore", content: repoGitignore},
+		{path: filepath.Join("hooks", "pre-commit"), content: fmt.Sprintf(preCommitHook, c.ConfigDirectory)},
+	}
+	files = append(files, c.pipelineFiles()...)
+	for _, file := range files {
+		if err := c.writeFile(file); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Bootstrapped config repo in %s\n", c.Directory)
+	fmt.Println("1) Enable the pre-commit hook with: git config core.hooksPath hooks")
+	fmt.Printf("2) %s\n", c.pipelineUsage())
+	fmt.Println("3) Commit the repo and push it to the git remote the pipeline reads")
+	return nil
+}
+
+func (c *BootstrapRepoCommand) pipelineFiles() []repoFile {
+	switch c.Pipeline {
+	case pipelineConcourse:
+		return []repoFile{
+			{path: "pipeline.yml", asset: "pipeline.yml"},
+			{path: "vars.yml", asset: "vars.yml"},
+			{path: filepath.Join("ci", "tasks", "cf-mgmt.yml"), asset: "cf-mgmt.yml"},
+			{path: filepath.Join("ci", "tasks", "cf-mgmt.sh"), asset: "cf-mgmt.sh"},
+		}
+	case pipelineGitHubActions:
+		return []repoFile{{path: filepath.Join(".github", "workflows", "cf-mgmt.yml"), content: fmt.Sprintf(githubActionsWorkflow, c.ConfigDirectory)}}
+	case pipelineGitLab:
+		return []repoFile{{path: ".gitlab-ci.yml", content: fmt.Sprintf(gitlabPipeline, c.ConfigDirectory)}}
+	}
+	return nil
+}
+
+func (c *BootstrapRepoCommand) pipelineUsage() string {
+	switch c.Pipeline {
+	case pipelineConcourse:
+		return "Fill in vars.yml, which git ignores as it holds credentials, and set the pipeline with: fly -t <target> set-pipeline -p cf-mgmt -c pipeline.yml --load-vars-from=vars.yml"
+	case pipelineGitHubActions:
+		return "Add SYSTEM_DOMAIN, USER_ID, CLIENT_SECRET and LDAP_PASSWORD as secrets of the GitHub repository, .github/workflows/cf-mgmt.yml applies the configuration on every push to main"
+	case pipelineGitLab:
+		return "Add SYSTEM_DOMAIN, USER_ID, CLIENT_SECRET and LDAP_PASSWORD as masked CI/CD variables of the GitLab project, .gitlab-ci.yml applies the configuration on every push to the default branch"
+	}
+	return "Run cf-mgmt apply from the repo with the pipeline of your choice"
+}
+
+func (c *BootstrapRepoCommand) writeFile(file repoFile) error {
+	path := filepath.Join(c.Directory, file.path)
+	if _, err := os.Stat(path); err == nil {
+		lo.G.Warningf("Keeping existing %s", path)
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data := []byte(file.content)
+	if file.asset != "" {
+		var err error
+		if data, err = generated.File(file.asset); err != nil {
+			return err
+		}
+	}
+	perm := os.FileMode(0644)
+	if strings.HasSuffix(path, ".sh") || filepath.Base(path) == "pre-commit" {
+		perm = 0755
+	}
+	lo.G.Debugf("Creating %s", path)
+	return ioutil.WriteFile(path, data, perm)
+}
+
+const repoReadme = `# cf-mgmt configuration
+
+This repository holds the [cf-mgmt](https://github.com/pivotalservices/cf-mgmt) configuration of a Cloud Foundry foundation: the orgs, spaces, quotas, security groups and user roles in the %s directory.
+
+## Making changes
+
+- add an org or a space with cf-mgmt-config add-org and add-space, or edit the yaml files directly
+- preview the changes with cf-mgmt apply --peek
+- commit, the pre-commit hook runs cf-mgmt validate-config first (enable it once with git config core.hooksPath hooks)
+
+## Pipeline
+
+%s.
+`
+
+const repoGitignore = `# credentials of the concourse pipeline
+vars.yml
+# working files of cf-mgmt runs
+.cf-mgmt-checkpoint.json
+*.cassette
+summary.json
+`
+
+const preCommitHook = `#!/usr/bin/env bash
+# validates the cf-mgmt configuration before each commit, enable with: git config core.hooksPath hooks
+set -e
+if ! command -v cf-mgmt >/dev/null 2>&1; then
+  echo "cf-mgmt is not installed, skipping configuration validation" >&2
+  exit 0
+fi
+cf-mgmt validate-config --config-dir "$(git rev-parse --show-toplevel)/%s"
+`
+
+const githubActionsWorkflow = `name: cf-mgmt
+
+on:
+  push:
+    branches: [main]
+  schedule:
+  - cron: "*/30 * * * *"
+
+jobs:
+  apply:
+    runs-on: ubuntu-latest
+    container: pivotalservices/cf-mgmt:latest
+    concurrency: cf-mgmt
+    steps:
+    - uses: actions/checkout@v4
+    - name: validate
+      run: cf-mgmt validate-config --config-dir %[1]s
+    - name: apply
+      run: cf-mgmt apply --config-dir %[1]s
+      env:
+        SYSTEM_DOMAIN: ${{ secrets.SYSTEM_DOMAIN }}
+        USER_ID: ${{ secrets.USER_ID }}
+        CLIENT_SECRET: ${{ secrets.CLIENT_SECRET }}
+        LDAP_PASSWORD: ${{ secrets.LDAP_PASSWORD }}
+`
+
+const gitlabPipeline = `image: pivotalservices/cf-mgmt:latest
+
+stages:
+- validate
+- apply
+
+validate:
+  stage: validate
+  script:
+  - cf-mgmt validate-config --config-dir %[1]s
+
+apply:
+  stage: apply
+  resource_group: cf-mgmt
+  script:
+  - cf-mgmt apply --config-dir %[1]s
+  rules:
+  - if: $CI_COMMIT_BRANCH == $CI_DEFAULT_BRANCH
+`
@@ -0,0 +1,56 @@
+package commands_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/commands"
+)
+
+var _ = Describe("BootstrapRepoCommand", func() {
+	var repoDir string
+
+	BeforeEach(func() {
+		tempDir, err := ioutil.TempDir("", "cf-mgmt")
+		Expect(err).ShouldNot(HaveOccurred())
+		repoDir = filepath.Join(tempDir, "repo")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(filepath.Dir(repoDir))
+	})
+
+	bootstrap := func(pipeline string) {
+		command := &commands.BootstrapRepoCommand{Directory: repoDir, Pipeline: pipeline, UAAOrigin: "ldap"}
+		command.ConfigDirectory = "config"
+		Expect(command.Execute(nil)).Should(Succeed())
+	}
+
+	It("creates a repo with valid configuration and the pipeline of choice", func() {
+		bootstrap("gitlab")
+		for _, file := range []string{"config/orgs.yml", "README.md", ".gitignore", "hooks/pre-commit", ".gitlab-ci.yml"} {
+			Expect(filepath.Join(repoDir, file)).Should(BeAnExistingFile())
+		}
+		Expect(filepath.Join(repoDir, "pipeline.yml")).ShouldNot(BeAnExistingFile())
+		info, err := os.Stat(filepath.Join(repoDir, "hooks", "pre-commit"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(info.Mode().Perm()).Should(Equal(os.FileMode(0755)))
+
+		validate := &commands.ValidateConfigCommand{}
+		validate.ConfigDirectory = filepath.Join(repoDir, "config")
+		Expect(validate.Execute(nil)).Should(Succeed())
+	})
+
+	It("keeps the files that already exist", func() {
+		Expect(os.MkdirAll(repoDir, 0755)).Should(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(repoDir, "README.md"), []byte("# ours\n"), 0644)).Should(Succeed())
+		bootstrap("concourse")
+		readme, err := ioutil.ReadFile(filepath.Join(repoDir, "README.md"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(readme)).Should(Equal("# ours\n"))
+		Expect(filepath.Join(repoDir, "ci", "tasks", "cf-mgmt.sh")).Should(BeAnExistingFile())
+	})
+})
@@ -5,11 +5,24 @@ import (
 )
 
 type CfMgmtCommand struct {
+	SummaryFile                      string                           `long:"summary-file" env:"SUMMARY_FILE" description:"Path to write a json summary of the run (changes, warnings, errors and duration)"`
+	PlanFile                         string                           `long:"plan-file" env:"PLAN_FILE" description:"Path to write the consolidated plan of a --peek run as json, the orgs and spaces to create, update or delete and the roles to grant or revoke"`
+	RecordHistory                    bool                             `long:"record-history" env:"RECORD_HISTORY" description:"Record the version, config git commit, status and change counts of the run in uaa groups on the foundation"`
+	Redact                           string                           `long:"redact" env:"REDACT" choice:"redact" choice:"hash" description:"Replace usernames, emails and ldap dns in logs and reports with REDACTED (redact) or a stable hash (hash), secrets are always redacted"`
+	NoColor                          bool                             `long:"no-color" env:"NO_COLOR" description:"Do not color the CREATE, UPDATE and DELETE tags of changes, such as in CI logs"`
+	Telemetry                        bool                             `long:"telemetry" env:"CF_MGMT_TELEMETRY" description:"Opt in to reporting the command, its outcome, the category of its error and the configuration features used, without names or messages, to --telemetry-endpoint"`
+	TelemetryEndpoint                string                           `long:"telemetry-endpoint" env:"CF_MGMT_TELEMETRY_ENDPOINT" description:"Url anonymous usage reports are posted to with --telemetry"`
+	TelemetrySkipSSLValidation       bool                             `long:"telemetry-skip-ssl-validation" env:"CF_MGMT_TELEMETRY_SKIP_SSL_VALIDATION" description:"Skip verifying the certificate of --telemetry-endpoint"`
+	Target                           string                           `long:"target" env:"CF_MGMT_TARGET" description:"Named target of the targets file to run against, defaults to the target selected with target use"`
 	Version                          configcommands.VersionCommand    `command:"version" description:"Print version information and exit"`
+	TargetCommand                    TargetCommand                    `command:"target" description:"adds, lists and selects the named foundations commands run against"`
 	InitConfigurationCommand         InitConfigurationCommand         `command:"init-config" description:"Initializes folder structure for configuration"`
 	AddOrgToConfigurationCommand     AddOrgToConfigurationCommand     `command:"add-org-to-config" description:"Adds specified org to configuration"`
 	AddSpaceToConfigurationCommand   AddSpaceToConfigurationCommand   `command:"add-space-to-config" description:"Adds specified space to configuration for org"`
+	ShowConfigCommand                ShowConfigCommand                `command:"show-config" description:"shows the configuration applied to an org or space after defaults, org groups, includes and group mappings are merged"`
+	ValidateConfigCommand            ValidateConfigCommand            `command:"validate-config" description:"validates the configuration without contacting a foundation"`
 	GenerateConcoursePipelineCommand GenerateConcoursePipelineCommand `command:"generate-concourse-pipeline" description:"generates a concourse pipline to be used to drive cf-mgmt"`
+	BootstrapRepoCommand             BootstrapRepoCommand             `command:"bootstrap-repo" description:"creates a config repo ready to commit with the configuration, a pipeline, a README, a .gitignore and a pre-commit validation hook"`
 	ExportConfigurationCommand       ExportConfigurationCommand       `command:"export-config" description:"Exports org and space configurations from an existing Cloud Foundry instance. [Warning: This operation will delete existing config folder]"`
 	CreateOrgsCommand                CreateOrgsCommand                `command:"create-orgs" description:"creates organizations for each orgConfig.yml"`
 	CreateSecurityGroupsCommand      CreateSecurityGroupsCommand      `command:"create-security-groups" description:"creates named security groups that can be assigned to spaces"`
@@ -18,16 +31,54 @@ type CfMgmtCommand struct {
 	DeleteOrgsCommand                DeleteOrgsCommand                `command:"delete-orgs" description:"deletes orgs not in the configuration"`
 	UpdateOrgQuotasCommand           UpdateOrgQuotasCommand           `command:"update-org-quotas" description:"updates org quotas"`
 	UpdateOrgUsersCommand            UpdateOrgUsersCommand            `command:"update-org-users" description:"update org user roles"`
+	UpdateUsersCommand               UpdateUsersCommand               `command:"update-users" description:"syncs only the org and space roles granted to an ldap group, with --group"`
+	UpdateIdentityProvidersCommand   UpdateIdentityProvidersCommand   `command:"update-identity-providers" description:"creates and updates the saml and oidc identity providers of identity-providers.yml"`
+	UpdateTokenPolicyCommand         UpdateTokenPolicyCommand         `command:"update-token-policy" description:"updates the token validities and signing keys of the default uaa identity zone to token-policy of cf-mgmt.yml"`
+	RotateClientSecretCommand        RotateClientSecretCommand        `command:"rotate-client-secret" description:"rotates the secret of the uaa client cf-mgmt runs as, writing the new secret to credhub, vault or a file"`
+	UpdateRoleGroupsCommand          UpdateRoleGroupsCommand          `command:"update-role-groups" description:"syncs the uaa groups in role-groups of cf-mgmt.yml with org and space roles"`
 	CleanupOrgUsersCommand           CleanupOrgUsersCommand           `command:"cleanup-org-users" description:"removes any users from org that don't have a role"`
+	RunHistoryCommand                RunHistoryCommand                `command:"run-history" description:"shows the last run and last successful run recorded on the foundation"`
+	MissingUsersCommand              MissingUsersCommand              `command:"missing-users" description:"lists configured internal users that don't exist in uaa"`
+	AdminAccessReportCommand         AdminAccessReportCommand         `command:"admin-access-report" description:"reports users with admin-equivalent uaa group memberships that are not declared in admin-users of cf-mgmt.yml"`
+	VerifyExternalUsersCommand       VerifyExternalUsersCommand       `command:"verify-external-users" description:"checks that configured saml and ldap users can log in with the configured origin"`
+	MigrateUserOriginCommand         MigrateUserOriginCommand         `command:"migrate-user-origin" description:"moves uaa users to another origin keeping their roles"`
+	CleanupOriginUsersCommand        CleanupOriginUsersCommand        `command:"cleanup-origin-users" description:"deletes the users of the old origin after an origin cutover"`
+	DedupeUAAUsersCommand            DedupeUAAUsersCommand            `command:"dedupe-uaa-users" description:"reports uaa users of different origins sharing an email and optionally consolidates them onto one origin"`
 	CreateSpacesCommand              CreateSpacesCommand              `command:"create-spaces" description:"creates spaces in configuration"`
 	DeleteSpacesCommand              DeleteSpacesCommand              `command:"delete-spaces" description:"deletes spaces not in configurtion"`
+	AdoptSpacesCommand               AdoptSpacesCommand               `command:"adopt-spaces" description:"reports spaces not in configuration and optionally adds them to it"`
+	RecycleSpacesCommand             RecycleSpacesCommand             `command:"recycle-spaces" description:"deletes and recreates the ephemeral spaces whose recycle-schedule fired, restoring their roles and security groups"`
 	UpdateSpacesCommand              UpdateSpacesCommand              `command:"update-spaces" description:"enables/disables ssh access at space level"`
 	UpdateSpaceQuotasCommand         UpdateSpaceQuotasCommand         `command:"update-space-quotas" description:"updates spaces quotas"`
 	UpdateSpaceUsersCommand          UpdateSpaceUsersCommand          `command:"update-space-users" description:"update space user roles"`
+	CreatePersonalSpacesCommand      CreatePersonalSpacesCommand      `command:"create-personal-spaces" description:"creates a space for each member of the personal-spaces ldap group of an org, with the member as space developer"`
 	CreateSpaceSecurityGroupsCommand CreateSpaceSecurityGroupsCommand `command:"update-space-security-groups" description:"updates space specific security groups"`
 	IsolationSegmentsCommand         IsolationSegmentsCommand         `command:"isolation-segments" description:"assigns isolations segments to orgs and spaces"`
+	IsolationSegmentReportCommand    IsolationSegmentReportCommand    `command:"isolation-segment-report" description:"reports the orgs configured on and entitled to each isolation segment and the memory of the apps running on it against its cell capacity"`
 	SharePrivateDomainsCommand       SharePrivateDomainsCommand       `command:"share-org-private-domains" description:"shares an existing private domain with the specified org"`
-	ApplyCommand                     ApplyCommand                     `command:"apply" description:"applies the configuration to your target foundation"`
+	EgressReportCommand              EgressReportCommand              `command:"egress-report" description:"reports the destinations each managed space can reach through its security groups"`
+	InternalRoutesCommand            InternalRoutesCommand            `command:"internal-routes" description:"deletes the routes on internal domains of spaces without allow-internal-routes, when enforce-internal-routes is set"`
+	InternalRouteReportCommand       InternalRouteReportCommand       `command:"internal-route-report" description:"reports the routes on internal domains of spaces without allow-internal-routes"`
+	DockerPolicyCommand              DockerPolicyCommand              `command:"docker-policy" description:"stops the started docker apps of spaces without allow-docker, when enforce-docker-policy is set"`
+	DockerReportCommand              DockerReportCommand              `command:"docker-report" description:"reports the docker apps of spaces without allow-docker"`
+	StackPolicyCommand               StackPolicyCommand               `command:"stack-policy" description:"logs the apps on stacks their org does not allow, failing when enforce-stack-policy is set"`
+	StackReportCommand               StackReportCommand               `command:"stack-report" description:"reports the apps on stacks their org does not allow with default-stack or allowed-stacks"`
+	ServiceReportCommand             ServiceReportCommand             `command:"service-report" description:"reports the service instances of services or plans their org does not allow with allowed-services"`
+	QuotaReportCommand               QuotaReportCommand               `command:"quota-report" description:"reports the usage of org quotas against the thresholds of quota-alerts and notifies the org managers of breaches"`
+	TaskReportCommand                TaskReportCommand                `command:"task-report" description:"reports the tasks run in each org and space over the last days, to size app_task_limit of quotas"`
+	ChangeAttributionCommand         ChangeAttributionCommand         `command:"change-attribution" description:"attributes recent changes of the managed orgs to cf-mgmt runs or to whoever made them out-of-band"`
+	DeveloperReportCommand           DeveloperReportCommand           `command:"developer-report" description:"reports the distinct users holding space developer in each org and across the foundation"`
+	PreflightCommand                 PreflightCommand                 `command:"preflight" description:"verifies the credentials, uaa scopes and ldap bind cf-mgmt runs with"`
+	ApplyCommand                     ApplyCommand                     `command:"apply" alias:"update-all" description:"applies the configuration to your target foundation, running every step in dependency order"`
+	WatchCommand                     WatchCommand                     `command:"watch" description:"detects drift and applies the configuration every interval, as a long running controller"`
+	SyncUsersOnEventsCommand         SyncUsersOnEventsCommand         `command:"sync-users-on-events" description:"syncs the users of the orgs affected by the user and group events of an identity provider or HR system, as a long running listener"`
+	PlanCommand                      PlanCommand                      `command:"plan" description:"lists the changes apply would make to a foundation snapshot, without contacting the foundation"`
+	ExportSnapshotCommand            ExportSnapshotCommand            `command:"export-snapshot" description:"exports the state of the foundation to a snapshot file for plan and --simulate"`
+	ExportMarketplaceCommand         ExportMarketplaceCommand         `command:"export-marketplace" description:"exports the service brokers, services, plans and plan visibilities of the foundation to a snapshot file"`
+	ExportServicesCommand            ExportServicesCommand            `command:"export-services" description:"exports the service instances of the managed spaces with their bindings and keys, who created them and their age"`
+	RotateServiceKeysCommand         RotateServiceKeysCommand         `command:"rotate-service-keys" description:"recreates the service keys older than service-key-rotation of cf-mgmt.yml in the spaces that opt in, writing their credentials to credhub"`
+	DiffSnapshotsCommand             DiffSnapshotsCommand             `command:"diff-snapshots" description:"lists the changes between two snapshots, such as marketplace or org and space drift over time or between foundations"`
+	VerifyCommand                    VerifyCommand                    `command:"verify" description:"spot-checks user access and ssh settings of the foundation after an apply"`
 }
 
 var CfMgmt CfMgmtCommand
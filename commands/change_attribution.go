@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pivotalservices/cf-mgmt/audit"
+	"github.com/pivotalservices/cf-mgmt/simulator"
+	"github.com/pkg/errors"
+)
+
+type ChangeAttributionCommand struct {
+	BaseCFConfigCommand
+	Days        int      `long:"days" description:"Number of days, up to now, of audit events to attribute" default:"7"`
+	RunSummary  []string `long:"run-summary" description:"Run summary written by --summary-file of a cf-mgmt run, can be repeated. Without any, every change made with the credentials of cf-mgmt is attributed to it"`
+	Plan        string   `long:"plan" description:"Plan written by plan --format json, whose changes are listed with the actors that may have caused them"`
+	IncludeRuns bool     `long:"include-runs" description:"Also list the changes cf-mgmt runs made"`
+	Format      string   `long:"format" description:"Output format of the report" default:"table" choice:"table" choice:"json"`
+}
+
+type changeAttribution struct {
+	Events []audit.Event `json:"events"`
+	Drift  []audit.Drift `json:"drift,omitempty"`
+}
+
+//Execute - attributes the recent changes of the managed orgs to cf-mgmt runs or to the users that made them out-of-band
+func (c *ChangeAttributionCommand) Execute([]string) error {
+	if c.Days < 1 {
+		return fmt.Errorf("--days must be at least 1, not %d", c.Days)
+	}
+	runs, err := loadRuns(c.RunSummary)
+	if err != nil {
+		return err
+	}
+	var changes []simulator.Change
+	if c.Plan != "" {
+		if changes, err = loadPlan(c.Plan); err != nil {
+			return err
+		}
+	}
+	cfMgmt, err := InitializeManagers(c.BaseCFConfigCommand)
+	if err != nil {
+		return err
+	}
+	events, err := cfMgmt.AuditManager.Events(time.Now().UTC().AddDate(0, 0, -c.Days), runs)
+	if err != nil {
+		return err
+	}
+	report := changeAttribution{Events: []audit.Event{}}
+	for _, event := range events {
+		if c.IncludeRuns || event.OutOfBand() {
+			report.Events = append(report.Events, event)
+		}
+	}
+	if c.Plan != "" {
+		report.Drift = audit.SuspectedActors(changes, events)
+	}
+	if c.Format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	return writeChangeAttribution(os.Stdout, report, c.Plan != "")
+}
+
+// loadPlan reads the changes of a plan, skipping the step headers plan
+// prints to stdout before them
+func loadPlan(planFile string) ([]simulator.Change, error) {
+	data, err := ioutil.ReadFile(planFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read plan %s", planFile)
+	}
+	text := string(data)
+	if i := strings.Index(text, "\n["); i >= 0 && !strings.HasPrefix(text, "[") {
+		text = text[i+1:]
+	}
+	var changes []simulator.Change
+	if err := json.Unmarshal([]byte(text), &changes); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse plan %s", planFile)
+	}
+	return changes, nil
+}
+
+// loadRuns reads the time each run summary was recorded over
+func loadRuns(summaryFiles []string) ([]audit.Run, error) {
+	var runs []audit.Run
+	for _, summaryFile := range summaryFiles {
+		data, err := ioutil.ReadFile(summaryFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read run summary %s", summaryFile)
+		}
+		summary := &RunSummary{}
+		if err := json.Unmarshal(data, summary); err != nil {
+			return nil, errors.Wrapf(err, "unable to parse run summary %s", summaryFile)
+		}
+		runs = append(runs, audit.Run{Command: summary.Command, Started: summary.StartedAt, Finished: summary.FinishedAt})
+	}
+	return runs, nil
+}
+
+func writeChangeAttribution(out io.Writer, report changeAttribution, withDrift bool) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tORG\tSPACE\tEVENT\tTARGET\tACTOR\tATTRIBUTION")
+	for _, event := range report.Events {
+		attribution := event.Attribution
+		if event.Run != "" {
+			attribution += " (" + event.Run + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", event.Time.Format(time.RFC3339), event.Org, event.Space, event.Type, event.Target, event.Actor, attribution)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if !withDrift {
+		return nil
+	}
+	fmt.Fprintln(out)
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DRIFT\tSUSPECTED ACTORS")
+	for _, drift := range report.Drift {
+		fmt.Fprintf(w, "%s\t%s\n", drift.Change.String(), strings.Join(drift.SuspectedActors, ", "))
+	}
+	return w.Flush()
+}
@@ -3,6 +3,7 @@ package commands
 type CleanupOrgUsersCommand struct {
 	BaseCFConfigCommand
 	BasePeekCommand
+	BaseLockCommand
 }
 
 //Execute - removes org users
@@ -0,0 +1,17 @@
+package commands
+
+type CleanupOriginUsersCommand struct {
+	BaseCFConfigCommand
+	BasePeekCommand
+	BaseLockCommand
+}
+
+//Execute - deletes the users of the old origin in origin-migration.yml that have a user in the new origin
+func (c *CleanupOriginUsersCommand) Execute([]string) error {
+	var cfMgmt *CFMgmt
+	var err error
+	if cfMgmt, err = InitializePeekManagers(c.BaseCFConfigCommand, c.Peek); err == nil {
+		err = cfMgmt.UserManager.CleanupOriginUsers()
+	}
+	return err
+}
@@ -1,5 +1,12 @@
 package commands
 
+import (
+	"time"
+
+	"github.com/pivotalservices/cf-mgmt/endpoint"
+	"github.com/pivotalservices/cf-mgmt/httpclient"
+)
+
 //BaseConfigCommand - commmand that specifies config-dir
 type BaseConfigCommand struct {
 	ConfigDirectory string `long:"config-dir" env:"CONFIG_DIR" default:"config" description:"Name of the config directory"`
@@ -8,10 +15,59 @@ type BaseConfigCommand struct {
 //BaseCFConfigCommand - base command that has details to connect to cloud foundry instance
 type BaseCFConfigCommand struct {
 	BaseConfigCommand
-	SystemDomain string `long:"system-domain" env:"SYSTEM_DOMAIN"  description:"system domain"`
-	UserID       string `long:"user-id" env:"USER_ID"  description:"user id that has privileges to create/update/delete users, orgs and spaces"`
-	Password     string `long:"password" env:"PASSWORD"  description:"password for user account [optional if client secret is provided]"`
-	ClientSecret string `long:"client-secret" env:"CLIENT_SECRET" description:"secret for user account that has sufficient privileges to create/update/delete users, orgs and spaces]"`
+	SystemDomain   string   `long:"system-domain" env:"SYSTEM_DOMAIN"  description:"system domain"`
+	UserID         string   `long:"user-id" env:"USER_ID"  description:"user id that has privileges to create/update/delete users, orgs and spaces"`
+	Password       string   `long:"password" env:"PASSWORD"  description:"password for user account [optional if client secret is provided]"`
+	ClientSecret   string   `long:"client-secret" env:"CLIENT_SECRET" description:"secret for user account that has sufficient privileges to create/update/delete users, orgs and spaces]"`
+	RequestTimeout int      `long:"request-timeout" env:"REQUEST_TIMEOUT" description:"Seconds before an individual api request is cancelled, 0 for no timeout"`
+	Simulate       string   `long:"simulate" env:"SIMULATE" description:"Run against an in-memory foundation seeded from this snapshot file instead of the system domain"`
+	Record         string   `long:"record" env:"RECORD" description:"Record every api interaction to this cassette file"`
+	Replay         string   `long:"replay" env:"REPLAY" description:"Replay api interactions from this cassette file instead of contacting the system domain"`
+	UAAUserOrigins []string `long:"uaa-user-origin" env:"UAA_USER_ORIGINS" env-delim:"," description:"Only list uaa users of this origin, can be repeated, users of every origin are listed when not set"`
+	UAALookupMode  string   `long:"uaa-lookup-mode" env:"UAA_LOOKUP_MODE" default:"all" choice:"all" choice:"targeted" description:"List every uaa user up front (all) or only look up the users referenced by the configuration (targeted)"`
+	OrgSelector    string   `long:"org-selector" env:"ORG_SELECTOR" description:"Only update the orgs whose metadata labels match this label selector, such as team=payments or label=team:payments"`
+	ChangedOnly    bool     `long:"changed-only" env:"CHANGED_ONLY" description:"Only update the orgs whose configuration changed since the last successful run of the command recorded in the state file"`
+	StateFile      string   `long:"state-file" env:"STATE_FILE" description:"File recording the configuration of each org at the last successful run, defaults to .cf-mgmt-state.json in the config directory"`
+	CacheDir       string   `long:"cache-dir" env:"CACHE_DIR" description:"Directory to keep ldap and uaa lookups in for --cache-ttl, so runs in quick succession such as plan then apply share them"`
+	CacheTTL       int      `long:"cache-ttl" env:"CACHE_TTL" default:"10" description:"Minutes lookups kept in --cache-dir are reused"`
+	Korifi         bool     `long:"korifi" env:"KORIFI" description:"Target a Korifi foundation, authenticating with the client secret as a Kubernetes bearer token"`
+	BaseHTTPCommand
+	// scopesVerified is set once preflight has verified the scopes of the client
+	scopesVerified bool
+	// commandScopes are the uaa scopes the command needs besides the scopes
+	// every command and the configuration need
+	commandScopes []string
+	// changedOrgs are the orgs to update with --changed-only
+	changedOrgs []string
+	// orgsSelected, when set, receives the orgs matching --org-selector
+	orgsSelected func(orgNames []string)
+}
+
+//BaseHTTPCommand - tunes the connections shared by cloud controller and uaa requests, and the endpoints they are made to
+type BaseHTTPCommand struct {
+	MaxIdleConnsPerHost int    `long:"max-idle-conns-per-host" env:"MAX_IDLE_CONNS_PER_HOST" description:"Connections kept open to each api for reuse, defaults to 20"`
+	IdleConnTimeout     int    `long:"idle-conn-timeout" env:"IDLE_CONN_TIMEOUT" description:"Seconds an idle api connection is kept open, defaults to 90"`
+	DisableKeepAlives   bool   `long:"disable-keep-alives" env:"DISABLE_KEEP_ALIVES" description:"Open a new connection for every api request"`
+	DisableHTTP2        bool   `long:"disable-http2" env:"DISABLE_HTTP2" description:"Use HTTP/1.1 even when an api supports HTTP/2"`
+	APIEndpoint         string `long:"api-endpoint" env:"API_ENDPOINT" description:"Cloud controller endpoint, defaults to https://api.<system-domain>"`
+	UAAEndpoint         string `long:"uaa-endpoint" env:"UAA_ENDPOINT" description:"UAA endpoint, defaults to https://uaa.<system-domain>"`
+	LoginEndpoint       string `long:"login-endpoint" env:"LOGIN_ENDPOINT" description:"Endpoint tokens are requested from, defaults to the uaa endpoint"`
+}
+
+//ConfigureHTTP - applies the connection settings to the transport shared by api requests,
+//and the endpoints to the clients
+func (c BaseHTTPCommand) ConfigureHTTP() error {
+	httpclient.Configure(httpclient.Options{
+		MaxIdleConnsPerHost: c.MaxIdleConnsPerHost,
+		IdleConnTimeout:     time.Duration(c.IdleConnTimeout) * time.Second,
+		DisableKeepAlives:   c.DisableKeepAlives,
+		DisableHTTP2:        c.DisableHTTP2,
+	})
+	return endpoint.Configure(endpoint.Options{
+		API:   c.APIEndpoint,
+		UAA:   c.UAAEndpoint,
+		Login: c.LoginEndpoint,
+	})
 }
 
 //BaseLDAPCommand - base command that has ldap password
@@ -23,3 +79,16 @@ type BaseLDAPCommand struct {
 type BasePeekCommand struct {
 	Peek bool `long:"peek" env:"PEEK"  description:"Preview entities to change without modifying"`
 }
+
+//BaseEventsCommand - base command for emitting changes as CloudEvents
+type BaseEventsCommand struct {
+	EventsSink                  string `long:"events-sink" env:"EVENTS_SINK" description:"Url each planned and performed change is sent to as a CloudEvent, an http(s) endpoint, kafka+http(s)://<rest proxy>/topics/<topic> or nats://<server>/<subject>"`
+	EventsSinkSkipSSLValidation bool   `long:"events-sink-skip-ssl-validation" env:"EVENTS_SINK_SKIP_SSL_VALIDATION" description:"Skip verifying the certificates of --events-sink"`
+}
+
+//BaseLockCommand - base command for holding the advisory run lock
+type BaseLockCommand struct {
+	Lock       bool   `long:"lock" env:"LOCK" description:"Acquire an advisory lock so concurrent cf-mgmt runs against the foundation do not interleave"`
+	LockHolder string `long:"lock-holder" env:"LOCK_HOLDER" description:"Name recorded as the lock holder, defaults to host/pid"`
+	LockTTL    int    `long:"lock-ttl" env:"LOCK_TTL" default:"60" description:"Minutes after which an unreleased lock is considered expired"`
+}
@@ -0,0 +1,35 @@
+package commands
+
+import (
+	flags "github.com/jessevdk/go-flags"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/xchapter7x/lo"
+)
+
+// commandHooks returns the command hooks of the configuration of the command,
+// along with the config directory they run in, none for commands that do not
+// run against a foundation
+func commandHooks(command flags.Commander) ([]config.CommandHook, string) {
+	cfCommand, ok := unwrapCommand(command).(cfConfigCommand)
+	if !ok {
+		return nil, ""
+	}
+	configDirectory := cfCommand.cfConfig().ConfigDirectory
+	globalConfig, err := config.NewManager(configDirectory).GetGlobalConfig()
+	if err != nil {
+		lo.G.Debugf("Not running command hooks, unable to read cf-mgmt.yml: %s", err)
+		return nil, config.BaseDirectory(configDirectory)
+	}
+	return globalConfig.CommandHooks, config.BaseDirectory(configDirectory)
+}
+
+//HasCommandHooks - whether hooks are configured to run before or after the command
+func HasCommandHooks(name string, command flags.Commander) bool {
+	hooks, _ := commandHooks(command)
+	for _, hook := range hooks {
+		if hook.RunsFor(name, config.HookBefore) || hook.RunsFor(name, config.HookAfter) {
+			return true
+		}
+	}
+	return false
+}
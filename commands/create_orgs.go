@@ -3,6 +3,7 @@ package commands
 type CreateOrgsCommand struct {
 	BaseCFConfigCommand
 	BasePeekCommand
+	BaseLockCommand
 }
 
 //Execute - creates organizations
@@ -0,0 +1,21 @@
+package commands
+
+type CreatePersonalSpacesCommand struct {
+	BaseCFConfigCommand
+	BaseLDAPCommand
+	BasePeekCommand
+	BaseLockCommand
+}
+
+//Execute - creates the personal spaces of the members of the personal-spaces ldap group of each org
+func (c *CreatePersonalSpacesCommand) Execute([]string) error {
+	cfMgmt, err := InitializePeekManagers(c.BaseCFConfigCommand, c.Peek)
+	if err != nil {
+		return err
+	}
+	if err := cfMgmt.UserManager.InitializeLdap(c.LdapPassword); err != nil {
+		return err
+	}
+	defer cfMgmt.UserManager.DeinitializeLdap()
+	return cfMgmt.UserManager.CreatePersonalSpaces()
+}
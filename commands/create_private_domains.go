@@ -3,6 +3,7 @@ package commands
 type CreatePrivateDomainsCommand struct {
 	BaseCFConfigCommand
 	BasePeekCommand
+	BaseLockCommand
 }
 
 //Execute - creates private domains
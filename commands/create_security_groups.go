@@ -3,6 +3,7 @@ package commands
 type CreateSecurityGroupsCommand struct {
 	BaseCFConfigCommand
 	BasePeekCommand
+	BaseLockCommand
 }
 
 //Execute - creates security groups
@@ -3,6 +3,7 @@ package commands
 type CreateSpaceSecurityGroupsCommand struct {
 	BaseCFConfigCommand
 	BasePeekCommand
+	BaseLockCommand
 }
 
 //Execute - creates space specific security groups
@@ -10,7 +11,9 @@ func (c *CreateSpaceSecurityGroupsCommand) Execute([]string) error {
 	var cfMgmt *CFMgmt
 	var err error
 	if cfMgmt, err = InitializePeekManagers(c.BaseCFConfigCommand, c.Peek); err == nil {
-		err = cfMgmt.SecurityGroupManager.CreateApplicationSecurityGroups()
+		if err = cfMgmt.SecurityGroupManager.CreateApplicationSecurityGroups(); err == nil {
+			err = cfMgmt.SecurityGroupManager.AnnotateEgress()
+		}
 	}
 	return err
 }
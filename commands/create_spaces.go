@@ -3,6 +3,7 @@ package commands
 type CreateSpacesCommand struct {
 	BaseCFConfigCommand
 	BasePeekCommand
+	BaseLockCommand
 }
 
 //Execute - creates spaces
@@ -0,0 +1,30 @@
+package commands
+
+import (
+	"fmt"
+)
+
+type DedupeUAAUsersCommand struct {
+	BaseCFConfigCommand
+	BasePeekCommand
+	BaseLockCommand
+	PreferredOrigin string `long:"preferred-origin" env:"PREFERRED_ORIGIN" description:"Origin of the user that is kept when users of several origins share an email" required:"true"`
+	Consolidate     bool   `long:"consolidate" env:"CONSOLIDATE" description:"Give the roles of the duplicate users to the user of the preferred origin and delete the duplicates"`
+}
+
+//Execute - reports uaa users of different origins sharing an email, and consolidates them onto the preferred origin
+func (c *DedupeUAAUsersCommand) Execute([]string) error {
+	cfMgmt, err := InitializePeekManagers(c.BaseCFConfigCommand, c.Peek)
+	if err != nil {
+		return err
+	}
+	duplicates, err := cfMgmt.UserManager.DedupeUAAUsers(c.PreferredOrigin, c.Consolidate, c.UserID)
+	if err != nil {
+		return err
+	}
+	for _, duplicate := range duplicates {
+		fmt.Println(duplicate.String())
+	}
+	fmt.Println(fmt.Sprintf("Found %d emails with users in more than one origin", len(duplicates)))
+	return nil
+}
@@ -3,6 +3,7 @@ package commands
 type DeleteOrgsCommand struct {
 	BaseCFConfigCommand
 	BasePeekCommand
+	BaseLockCommand
 }
 
 //Execute - deletes orgs
@@ -3,6 +3,7 @@ package commands
 type DeleteSpacesCommand struct {
 	BaseCFConfigCommand
 	BasePeekCommand
+	BaseLockCommand
 }
 
 //Execute - deletes spaces
@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/pivotalservices/cf-mgmt/user"
+)
+
+type DeveloperReportCommand struct {
+	BaseCFConfigCommand
+	Format string `long:"format" description:"Output format of the report" default:"table" choice:"table" choice:"csv" choice:"json"`
+}
+
+//Execute - reports the distinct space developers of each org and of the foundation
+func (c *DeveloperReportCommand) Execute([]string) error {
+	var cfMgmt *CFMgmt
+	var err error
+	if cfMgmt, err = InitializeManagers(c.BaseCFConfigCommand); err != nil {
+		return err
+	}
+	report, err := cfMgmt.UserManager.DeveloperReport()
+	if err != nil {
+		return err
+	}
+	switch c.Format {
+	case "csv":
+		return writeDeveloperCSV(os.Stdout, report)
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	return writeDeveloperTable(os.Stdout, report)
+}
+
+func writeDeveloperTable(out io.Writer, report *user.DeveloperReport) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ORG\tDEVELOPERS")
+	for _, count := range report.Orgs {
+		fmt.Fprintf(w, "%s\t%d\n", count.Org, count.Developers)
+	}
+	fmt.Fprintf(w, "TOTAL (distinct)\t%d\n", report.Total)
+	return w.Flush()
+}
+
+func writeDeveloperCSV(out io.Writer, report *user.DeveloperReport) error {
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"org", "developers"}); err != nil {
+		return err
+	}
+	for _, count := range report.Orgs {
+		if err := w.Write([]string{count.Org, strconv.Itoa(count.Developers)}); err != nil {
+			return err
+		}
+	}
+	if err := w.Write([]string{"TOTAL (distinct)", strconv.Itoa(report.Total)}); err != nil {
+		return err
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pivotalservices/cf-mgmt/simulator"
+)
+
+type DiffSnapshotsCommand struct {
+	Before     string `long:"before" env:"BEFORE" required:"true" description:"Snapshot, written by export-snapshot or export-marketplace, to compare from"`
+	After      string `long:"after" env:"AFTER" required:"true" description:"Snapshot, written by export-snapshot or export-marketplace, to compare to"`
+	Format     string `long:"format" description:"Output format of the diff" default:"text" choice:"text" choice:"json"`
+	FailOnDiff bool   `long:"fail-on-diff" env:"FAIL_ON_DIFF" description:"Exit with an error when the snapshots differ"`
+}
+
+//Execute - lists the changes between two snapshots, of the same foundation over time or of two foundations
+func (c *DiffSnapshotsCommand) Execute([]string) error {
+	before, err := simulator.LoadSnapshot(c.Before)
+	if err != nil {
+		return err
+	}
+	after, err := simulator.LoadSnapshot(c.After)
+	if err != nil {
+		return err
+	}
+	changes, err := simulator.Diff(before, after)
+	if err != nil {
+		return err
+	}
+	if err := writeChanges(os.Stdout, "Diff", changes, c.Format); err != nil {
+		return err
+	}
+	if c.FailOnDiff && len(changes) > 0 {
+		return fmt.Errorf("%s and %s differ by %d changes", c.Before, c.After, len(changes))
+	}
+	return nil
+}
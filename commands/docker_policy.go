@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"os"
+)
+
+type DockerPolicyCommand struct {
+	BaseCFConfigCommand
+	BasePeekCommand
+	BaseLockCommand
+}
+
+//Execute - stops the started docker apps of spaces that do not allow docker, when enforced
+func (c *DockerPolicyCommand) Execute([]string) error {
+	cfMgmt, err := InitializePeekManagers(c.BaseCFConfigCommand, c.Peek)
+	if err != nil {
+		return err
+	}
+	return cfMgmt.AppManager.EnforceDockerPolicy()
+}
+
+type DockerReportCommand struct {
+	BaseCFConfigCommand
+	Format string `long:"format" description:"Output format of the report" default:"table" choice:"table" choice:"csv"`
+}
+
+//Execute - reports the docker apps of spaces that do not allow docker
+func (c *DockerReportCommand) Execute([]string) error {
+	cfMgmt, err := InitializeManagers(c.BaseCFConfigCommand)
+	if err != nil {
+		return err
+	}
+	violations, err := cfMgmt.AppManager.DockerViolations()
+	if err != nil {
+		return err
+	}
+	return writeAppViolations(os.Stdout, c.Format, "image", violations)
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/pivotalservices/cf-mgmt/dryrun"
+	"github.com/pivotalservices/cf-mgmt/simulator"
+	"github.com/xchapter7x/lo"
+)
+
+// dryRunCommand ends a --peek run with the consolidated plan of the orgs and
+// spaces the managers would create, update or delete and the roles they would
+// grant or revoke.
+type dryRunCommand struct {
+	flags.Commander
+}
+
+func (c *dryRunCommand) Execute(args []string) error {
+	command := unwrapCommand(c.Commander)
+	peeking, ok := command.(peekCommand)
+	if _, plan := command.(*PlanCommand); !ok || !peeking.peek() || plan {
+		return c.Commander.Execute(args)
+	}
+	dryrun.Start()
+	err := c.Commander.Execute(args)
+	changes := dryrun.Stop()
+
+	planned := make([]simulator.Change, len(changes))
+	for i, change := range changes {
+		planned[i] = simulator.Change(change)
+	}
+	if writeErr := writeChanges(os.Stdout, "Dry Run Plan", planned, "text"); writeErr != nil {
+		lo.G.Error(writeErr)
+	}
+	if CfMgmt.PlanFile != "" {
+		if writeErr := writePlanFile(CfMgmt.PlanFile, changes); writeErr != nil {
+			lo.G.Errorf("Unable to write the dry run plan to %s: %s", CfMgmt.PlanFile, writeErr)
+			if err == nil {
+				err = writeErr
+			}
+		}
+	}
+	return err
+}
+
+//WithDryRunPlan - returns the command printing the plan of its changes at the end when it runs with --peek
+func WithDryRunPlan(command flags.Commander) flags.Commander {
+	return &dryRunCommand{Commander: command}
+}
+
+func writePlanFile(planFile string, changes []dryrun.Change) error {
+	if err := os.MkdirAll(filepath.Dir(planFile), 0755); err != nil {
+		return err
+	}
+	bytes, err := json.MarshalIndent(changes, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(planFile, bytes, 0644)
+}
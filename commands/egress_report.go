@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pivotalservices/cf-mgmt/securitygroup"
+)
+
+type EgressReportCommand struct {
+	BaseCFConfigCommand
+	Format string `long:"format" description:"Output format of the report" default:"table" choice:"table" choice:"csv"`
+}
+
+//Execute - reports which destinations each managed space can reach
+func (c *EgressReportCommand) Execute([]string) error {
+	var cfMgmt *CFMgmt
+	var err error
+	if cfMgmt, err = InitializeManagers(c.BaseCFConfigCommand); err != nil {
+		return err
+	}
+	report, err := cfMgmt.SecurityGroupManager.EgressReport()
+	if err != nil {
+		return err
+	}
+	if c.Format == "csv" {
+		return writeEgressCSV(os.Stdout, report)
+	}
+	return writeEgressTable(os.Stdout, report)
+}
+
+func writeEgressTable(out io.Writer, report []securitygroup.EgressRule) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ORG\tSPACE\tLIFECYCLE\tSECURITY GROUP\tPROTOCOL\tDESTINATION\tPORTS")
+	for _, rule := range report {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", rule.Org, rule.Space, rule.Lifecycle, rule.SecurityGroup, rule.Protocol, rule.Destination, rule.Ports)
+	}
+	return w.Flush()
+}
+
+func writeEgressCSV(out io.Writer, report []securitygroup.EgressRule) error {
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"org", "space", "lifecycle", "security_group", "protocol", "destination", "ports"}); err != nil {
+		return err
+	}
+	for _, rule := range report {
+		if err := w.Write([]string{rule.Org, rule.Space, rule.Lifecycle, rule.SecurityGroup, rule.Protocol, rule.Destination, rule.Ports}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
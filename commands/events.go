@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"github.com/pivotalservices/cf-mgmt/cloudevents"
+	"github.com/pivotalservices/cf-mgmt/simulator"
+	"github.com/xchapter7x/lo"
+)
+
+// sink returns the sink of --events-sink, nil when not set
+func (c BaseEventsCommand) sink() (cloudevents.Sink, error) {
+	if c.EventsSink == "" {
+		return nil, nil
+	}
+	return cloudevents.NewSink(c.EventsSink, c.EventsSinkSkipSSLValidation)
+}
+
+// emitChanges sends the changes, in the phase, to the sink as CloudEvents
+func emitChanges(sink cloudevents.Sink, systemDomain, phase string, changes []simulator.Change) error {
+	if sink == nil || len(changes) == 0 {
+		return nil
+	}
+	if err := sink.Send(cloudevents.NewEvents(systemDomain, phase, changes)); err != nil {
+		return err
+	}
+	lo.G.Noticef("Sent %d %s changes to the events sink", len(changes), phase)
+	return nil
+}
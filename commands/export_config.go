@@ -1,14 +1,16 @@
 package commands
 
 import (
+	"github.com/pivotalservices/cf-mgmt/config"
 	"github.com/pivotalservices/cf-mgmt/export"
 	"github.com/xchapter7x/lo"
 )
 
 type ExportConfigurationCommand struct {
 	BaseCFConfigCommand
-	ExcludedOrgs   []string `long:"excluded-org" description:"Org to be excluded from export. Repeat the flag to specify multiple orgs"`
-	ExcludedSpaces []string `long:"excluded-space" description:"Space to be excluded from export. Repeat the flag to specify multiple spaces"`
+	ExcludedOrgs   []string `long:"excluded-org" description:"Org to be excluded from export, a name or a glob such as 'p-*'. Repeat the flag to specify multiple orgs"`
+	ExcludedSpaces []string `long:"excluded-space" description:"Space to be excluded from export, a name or a glob such as 'sandbox-*'. Repeat the flag to specify multiple spaces"`
+	IncludeOnly    []string `long:"include-only" description:"Only export the orgs matching this name or glob, such as 'payments-*'. Repeat the flag to specify multiple orgs"`
 }
 
 //Execute - initializes cf-mgmt configuration
@@ -25,19 +27,26 @@ func (c *ExportConfigurationCommand) Execute([]string) error {
 			cfMgmt.SecurityGroupManager,
 			cfMgmt.IsolationSegmentManager,
 			cfMgmt.PrivateDomainManager)
-		excludedOrgs := make(map[string]string)
-		excludedOrgs["system"] = "system"
-		for _, org := range c.ExcludedOrgs {
-			excludedOrgs[org] = org
+		// the protected orgs of the configuration being replaced stay excluded
+		protectedOrgs := config.ProtectedOrgs(nil)
+		if orgsConfig, err := config.NewManager(c.ConfigDirectory).Orgs(); err == nil {
+			protectedOrgs = config.ProtectedOrgs(orgsConfig.ProtectedOrgs)
+			if importManager, ok := exportManager.(*export.DefaultImportManager); ok {
+				importManager.ProtectedOrgs = orgsConfig.ProtectedOrgs
+			}
 		}
-		excludedSpaces := make(map[string]string)
-		for _, space := range c.ExcludedSpaces {
-			excludedSpaces[space] = space
+		filter := export.Filter{
+			ExcludedOrgs:   c.ExcludedOrgs,
+			ExcludedSpaces: c.ExcludedSpaces,
+			IncludeOnly:    c.IncludeOnly,
+		}
+		lo.G.Infof("Protected orgs excluded from export: %v", protectedOrgs)
+		if len(c.IncludeOnly) > 0 {
+			lo.G.Infof("Orgs included in export by user:  %v ", c.IncludeOnly)
 		}
-		lo.G.Info("Orgs excluded from export by default: [system]")
 		lo.G.Infof("Orgs excluded from export by user:  %v ", c.ExcludedOrgs)
 		lo.G.Infof("Spaces excluded from export by user:  %v ", c.ExcludedSpaces)
-		err = exportManager.ExportConfig(excludedOrgs, excludedSpaces)
+		err = exportManager.ExportConfigWithFilter(filter)
 		if err != nil {
 			lo.G.Errorf("Export failed with error:  %s", err)
 			return err
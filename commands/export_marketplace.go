@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"github.com/pivotalservices/cf-mgmt/simulator"
+	"github.com/xchapter7x/lo"
+)
+
+type ExportMarketplaceCommand struct {
+	BaseCFConfigCommand
+	SnapshotFile string `long:"snapshot-file" env:"SNAPSHOT_FILE" default:"marketplace.json" description:"File to write the marketplace snapshot to"`
+}
+
+//Execute - writes the service brokers, services, plans and plan visibilities of the foundation to a snapshot file
+func (c *ExportMarketplaceCommand) Execute([]string) error {
+	var cfMgmt *CFMgmt
+	var err error
+	if cfMgmt, err = InitializeManagers(c.BaseCFConfigCommand); err != nil {
+		return err
+	}
+	snapshot, err := simulator.ExportMarketplace(cfMgmt.Client)
+	if err != nil {
+		return err
+	}
+	if err := simulator.WriteSnapshot(c.SnapshotFile, snapshot); err != nil {
+		return err
+	}
+	lo.G.Infof("exported %d service brokers, %d services and %d plans to %s", len(snapshot.ServiceBrokers), len(snapshot.Services), len(snapshot.ServicePlans), c.SnapshotFile)
+	return nil
+}
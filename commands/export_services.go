@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/pivotalservices/cf-mgmt/service"
+	"github.com/xchapter7x/lo"
+)
+
+type ExportServicesCommand struct {
+	BaseCFConfigCommand
+	Format string `long:"format" description:"Output format of the inventory" default:"csv" choice:"table" choice:"csv" choice:"json"`
+	File   string `long:"file" env:"SERVICES_FILE" description:"File to write the inventory to, stdout when not set"`
+}
+
+//Execute - exports the service instances of the managed spaces with their bindings and keys, who created them and their age
+func (c *ExportServicesCommand) Execute([]string) error {
+	cfMgmt, err := InitializeManagers(c.BaseCFConfigCommand)
+	if err != nil {
+		return err
+	}
+	items, err := cfMgmt.ServiceManager.ServiceInventory()
+	if err != nil {
+		return err
+	}
+	out := io.Writer(os.Stdout)
+	if c.File != "" {
+		file, err := os.Create(c.File)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+	switch c.Format {
+	case "table":
+		err = writeServiceInventoryTable(out, items)
+	case "json":
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(items)
+	default:
+		err = writeServiceInventoryCSV(out, items)
+	}
+	if err == nil && c.File != "" {
+		lo.G.Noticef("exported %d service instances, bindings and keys to %s", len(items), c.File)
+	}
+	return err
+}
+
+func writeServiceInventoryTable(out io.Writer, items []service.InventoryItem) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ORG\tSPACE\tKIND\tNAME\tINSTANCE\tSERVICE\tPLAN\tCREATED BY\tAGE (DAYS)")
+	for _, item := range items {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\n", item.Org, item.Space, item.Kind, item.Name, item.Instance, item.Service, item.Plan, item.CreatedBy, item.AgeDays)
+	}
+	return w.Flush()
+}
+
+func writeServiceInventoryCSV(out io.Writer, items []service.InventoryItem) error {
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"org", "space", "kind", "name", "instance", "service", "plan", "guid", "created_at", "created_by", "age_days"}); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := w.Write([]string{item.Org, item.Space, item.Kind, item.Name, item.Instance, item.Service, item.Plan, item.GUID,
+			item.CreatedAt, item.CreatedBy, strconv.Itoa(item.AgeDays)}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/simulator"
+	"github.com/xchapter7x/lo"
+)
+
+type ExportSnapshotCommand struct {
+	BaseCFConfigCommand
+	SnapshotFile string `long:"snapshot-file" env:"SNAPSHOT_FILE" default:"snapshot.json" description:"File to write the snapshot to"`
+}
+
+//Execute - writes the orgs, spaces, quotas, domains, security groups, isolation segments, roles and uaa users
+//of the foundation to a snapshot file
+func (c *ExportSnapshotCommand) Execute([]string) error {
+	var cfMgmt *CFMgmt
+	var err error
+	if cfMgmt, err = InitializeManagers(c.BaseCFConfigCommand); err != nil {
+		return err
+	}
+	globalConfig, err := config.NewManager(c.ConfigDirectory).GetGlobalConfig()
+	if err != nil {
+		return err
+	}
+	snapshot, err := simulator.Export(cfMgmt.Client, cfMgmt.UAAManager, globalConfig.RoleGroups)
+	if err != nil {
+		return err
+	}
+	if err := simulator.WriteSnapshot(c.SnapshotFile, snapshot); err != nil {
+		return err
+	}
+	lo.G.Infof("exported %d orgs, %d spaces and %d uaa users to %s", len(snapshot.Orgs), len(snapshot.Spaces), len(snapshot.UAAUsers), c.SnapshotFile)
+	return nil
+}
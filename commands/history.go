@@ -0,0 +1,152 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/configcommands"
+	"github.com/pivotalservices/cf-mgmt/history"
+	"github.com/xchapter7x/lo"
+)
+
+type cfConfigCommand interface {
+	cfConfig() BaseCFConfigCommand
+}
+
+func (c BaseCFConfigCommand) cfConfig() BaseCFConfigCommand {
+	return c
+}
+
+type peekCommand interface {
+	peek() bool
+}
+
+func (c BasePeekCommand) peek() bool {
+	return c.Peek
+}
+
+type RunHistoryCommand struct {
+	BaseCFConfigCommand
+}
+
+//Execute - prints the last run and the last successful run recorded on the foundation
+func (c *RunHistoryCommand) Execute([]string) error {
+	historyMgr, err := newHistoryManager(c.BaseCFConfigCommand, false)
+	if err != nil {
+		return err
+	}
+	last, lastSuccessful, err := historyMgr.Last()
+	if err != nil {
+		return err
+	}
+	return writeRunHistory(os.Stdout, last, lastSuccessful)
+}
+
+func writeRunHistory(out io.Writer, last, lastSuccessful *history.Run) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RUN\tCOMMAND\tSTATUS\tFINISHED\tSECONDS\tVERSION\tCONFIG SHA\tCHANGES\tWARNINGS\tERRORS")
+	for _, entry := range []struct {
+		name string
+		run  *history.Run
+	}{{"last", last}, {"last successful", lastSuccessful}} {
+		if entry.run == nil {
+			fmt.Fprintf(w, "%s\tnone recorded\t\t\t\t\t\t\t\t\n", entry.name)
+			continue
+		}
+		run := entry.run
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\t%d\t%d\t%d\n", entry.name, run.Command, run.Status,
+			run.Finished.Format(time.RFC3339), run.Seconds, run.Version, run.ConfigSHA, run.Changes, run.Warnings, run.Errors)
+	}
+	return w.Flush()
+}
+
+// unwrapCommand returns the command wrapped by WithRedaction, WithDryRunPlan,
+// WithLock and WithChangedOnly
+func unwrapCommand(command flags.Commander) flags.Commander {
+	if redacted, ok := command.(*redactedCommand); ok {
+		command = redacted.Commander
+	}
+	if dryRun, ok := command.(*dryRunCommand); ok {
+		command = dryRun.Commander
+	}
+	if locked, ok := command.(*lockedCommand); ok {
+		command = locked.Commander
+	}
+	if changedOnly, ok := command.(*changedOnlyCommand); ok {
+		command = changedOnly.Commander
+	}
+	return command
+}
+
+// newHistoryManager records runs on the org of run-history in cf-mgmt.yml
+func newHistoryManager(baseCommand BaseCFConfigCommand, peek bool) (history.Manager, error) {
+	globalConfig, err := config.NewManager(baseCommand.ConfigDirectory).GetGlobalConfig()
+	if err != nil {
+		return nil, err
+	}
+	if globalConfig.RunHistory == nil || globalConfig.RunHistory.Org == "" {
+		return nil, fmt.Errorf("run history requires the org of run-history in cf-mgmt.yml")
+	}
+	cfMgmt, err := InitializePeekManagers(baseCommand, peek)
+	if err != nil {
+		return nil, err
+	}
+	annotator := cfMgmt.Annotator()
+	if annotator == nil {
+		return nil, fmt.Errorf("run history cannot be recorded on this foundation, which does not support annotations")
+	}
+	org, err := cfMgmt.OrgManager.FindOrg(globalConfig.RunHistory.Org)
+	if err != nil {
+		return nil, err
+	}
+	return history.NewManager(annotator, org.Guid, peek), nil
+}
+
+// recordHistory stores the run on the foundation the command ran against.
+// Failing to record is logged rather than failing the run.
+func recordHistory(command flags.Commander, summary *RunSummary) {
+	command = unwrapCommand(command)
+	if _, ok := command.(*RunHistoryCommand); ok {
+		return
+	}
+	if _, ok := command.(*PlanCommand); ok {
+		lo.G.Debug("Skipping run history of an offline plan")
+		return
+	}
+	cfCommand, ok := command.(cfConfigCommand)
+	if !ok {
+		lo.G.Debugf("Not recording run history of %s as it does not run against a foundation", summary.Command)
+		return
+	}
+	baseCommand := cfCommand.cfConfig()
+	if baseCommand.Simulate != "" || baseCommand.Replay != "" {
+		lo.G.Debug("Skipping run history while simulating or replaying")
+		return
+	}
+	peek := false
+	if peeking, ok := command.(peekCommand); ok {
+		peek = peeking.peek()
+	}
+	historyMgr, err := newHistoryManager(baseCommand, peek)
+	if err == nil {
+		err = historyMgr.Record(history.Run{
+			Command:   summary.Command,
+			Status:    summary.Status,
+			Finished:  summary.FinishedAt.Truncate(time.Second),
+			Seconds:   int(summary.DurationSeconds),
+			Version:   configcommands.VERSION,
+			ConfigSHA: history.ConfigSHA(config.BaseDirectory(baseCommand.ConfigDirectory)),
+			Changes:   len(summary.Changes),
+			Warnings:  len(summary.Warnings),
+			Errors:    len(summary.Errors),
+		})
+	}
+	if err != nil {
+		lo.G.Errorf("Unable to record run history: %s", err)
+	}
+}
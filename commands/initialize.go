@@ -1,35 +1,18 @@
 package commands
 
 import (
+	"context"
 	"fmt"
+	"time"
 
-	cfclient "github.com/cloudfoundry-community/go-cfclient"
-	"github.com/pivotalservices/cf-mgmt/config"
-	"github.com/pivotalservices/cf-mgmt/configcommands"
-	"github.com/pivotalservices/cf-mgmt/isosegment"
-	"github.com/pivotalservices/cf-mgmt/organization"
-	"github.com/pivotalservices/cf-mgmt/privatedomain"
-	"github.com/pivotalservices/cf-mgmt/quota"
-	"github.com/pivotalservices/cf-mgmt/securitygroup"
-	"github.com/pivotalservices/cf-mgmt/space"
-	"github.com/pivotalservices/cf-mgmt/uaa"
-	"github.com/pivotalservices/cf-mgmt/user"
+	"github.com/pivotalservices/cf-mgmt/cfmgmt"
+	"github.com/pivotalservices/cf-mgmt/preflight"
+	"github.com/pivotalservices/cf-mgmt/redact"
+	"github.com/pivotalservices/cf-mgmt/simulator"
 	"github.com/xchapter7x/lo"
 )
 
-type CFMgmt struct {
-	UAAManager              uaa.Manager
-	OrgManager              organization.Manager
-	SpaceManager            space.Manager
-	UserManager             user.Manager
-	QuotaManager            quota.Manager
-	PrivateDomainManager    privatedomain.Manager
-	ConfigManager           config.Updater
-	ConfigDirectory         string
-	SystemDomain            string
-	SecurityGroupManager    securitygroup.Manager
-	IsolationSegmentManager isosegment.Manager
-}
+type CFMgmt = cfmgmt.CFMgmt
 
 type Initialize struct {
 	ConfigDir, SystemDomain, UserID, Password, ClientSecret, LdapPwd string
@@ -41,58 +24,65 @@ func InitializeManagers(baseCommand BaseCFConfigCommand) (*CFMgmt, error) {
 }
 
 func InitializePeekManagers(baseCommand BaseCFConfigCommand, peek bool) (*CFMgmt, error) {
-	if baseCommand.SystemDomain == "" ||
-		baseCommand.UserID == "" ||
-		baseCommand.ClientSecret == "" {
-		return nil, fmt.Errorf("must set system-domain, user-id, client-secret properties")
-	}
-
-	cfg := config.NewManager(baseCommand.ConfigDirectory)
-	var err error
-	cfMgmt := &CFMgmt{}
-	cfMgmt.ConfigDirectory = baseCommand.ConfigDirectory
-	cfMgmt.SystemDomain = baseCommand.SystemDomain
-	cfMgmt.ConfigManager = config.NewManager(cfMgmt.ConfigDirectory)
+	return InitializeManagersWithContext(context.Background(), baseCommand, peek)
+}
 
-	uaaMgr, err := uaa.NewDefaultUAAManager(cfMgmt.SystemDomain, baseCommand.UserID, baseCommand.ClientSecret, peek)
-	if err != nil {
+//InitializeManagersWithContext - in-flight api requests are aborted when ctx is cancelled
+func InitializeManagersWithContext(ctx context.Context, baseCommand BaseCFConfigCommand, peek bool) (*CFMgmt, error) {
+	redact.Secrets(baseCommand.Password, baseCommand.ClientSecret)
+	if err := baseCommand.ConfigureHTTP(); err != nil {
 		return nil, err
 	}
-	cfMgmt.UAAManager = uaaMgr
-
-	var c *cfclient.Config
-	if baseCommand.Password != "" {
-		lo.G.Warning("Password parameter is deprecated, create uaa client and client-secret instead")
-		c = &cfclient.Config{
-			ApiAddress:        fmt.Sprintf("https://api.%s", cfMgmt.SystemDomain),
-			SkipSslValidation: true,
-			Username:          baseCommand.UserID,
-			Password:          baseCommand.Password,
-			UserAgent:         fmt.Sprintf("cf-mgmt/%s", configcommands.VERSION),
+	cfg := managerConfig(ctx, baseCommand, peek)
+	if baseCommand.Simulate != "" {
+		if baseCommand.Record != "" || baseCommand.Replay != "" {
+			return nil, fmt.Errorf("--simulate cannot be combined with --record or --replay")
 		}
-	} else {
-		c = &cfclient.Config{
-			ApiAddress:        fmt.Sprintf("https://api.%s", cfMgmt.SystemDomain),
-			SkipSslValidation: true,
-			ClientID:          baseCommand.UserID,
-			ClientSecret:      baseCommand.ClientSecret,
-			UserAgent:         fmt.Sprintf("cf-mgmt/%s", configcommands.VERSION),
+		snapshot, err := simulator.LoadSnapshot(baseCommand.Simulate)
+		if err != nil {
+			return nil, err
 		}
+		lo.G.Warningf("simulating foundation from snapshot %s, no changes will be made to %s", baseCommand.Simulate, baseCommand.SystemDomain)
+		foundation, err := simulator.NewFoundation(snapshot)
+		if err != nil {
+			return nil, err
+		}
+		return cfmgmt.NewWithClient(cfg, foundation, foundation.UAAManager(peek))
 	}
-	client, err := cfclient.NewClient(c)
+	cfMgmt, err := cfmgmt.New(cfg)
 	if err != nil {
 		return nil, err
 	}
-	cfMgmt.OrgManager = organization.NewManager(client, cfg, peek)
-	cfMgmt.SpaceManager = space.NewManager(client, cfMgmt.UAAManager, cfMgmt.OrgManager, cfg, peek)
-	cfMgmt.UserManager = user.NewManager(client, cfg, cfMgmt.SpaceManager, cfMgmt.OrgManager, cfMgmt.UAAManager, peek)
-	cfMgmt.SecurityGroupManager = securitygroup.NewManager(client, cfMgmt.SpaceManager, cfg, peek)
-	cfMgmt.QuotaManager = quota.NewManager(client, cfMgmt.SpaceManager, cfMgmt.OrgManager, cfg, peek)
-	cfMgmt.PrivateDomainManager = privatedomain.NewManager(client, cfMgmt.OrgManager, cfg, peek)
-	if isoSegmentManager, err := isosegment.NewManager(client, cfg, cfMgmt.OrgManager, cfMgmt.SpaceManager, peek); err == nil {
-		cfMgmt.IsolationSegmentManager = isoSegmentManager
-	} else {
-		return nil, err
+	// a replayed foundation has no uaa client to verify
+	// a korifi foundation has no uaa client to verify
+	if baseCommand.Replay == "" && !baseCommand.Korifi && !baseCommand.scopesVerified {
+		if err := preflight.VerifyScopes(preflightConfig(baseCommand)); err != nil {
+			return nil, err
+		}
 	}
 	return cfMgmt, nil
 }
+
+// managerConfig is the configuration of the managers the command runs with
+func managerConfig(ctx context.Context, baseCommand BaseCFConfigCommand, peek bool) cfmgmt.Config {
+	return cfmgmt.Config{
+		ConfigDirectory: baseCommand.ConfigDirectory,
+		SystemDomain:    baseCommand.SystemDomain,
+		UserID:          baseCommand.UserID,
+		Password:        baseCommand.Password,
+		ClientSecret:    baseCommand.ClientSecret,
+		Peek:            peek,
+		Context:         ctx,
+		RequestTimeout:  time.Duration(baseCommand.RequestTimeout) * time.Second,
+		RecordTo:        baseCommand.Record,
+		ReplayFrom:      baseCommand.Replay,
+		UAAUserOrigins:  baseCommand.UAAUserOrigins,
+		UAALookupMode:   baseCommand.UAALookupMode,
+		OrgSelector:     baseCommand.OrgSelector,
+		ChangedOrgs:     baseCommand.changedOrgs,
+		OrgsSelected:    baseCommand.orgsSelected,
+		CacheDir:        baseCommand.CacheDir,
+		CacheTTL:        time.Duration(baseCommand.CacheTTL) * time.Minute,
+		Korifi:          baseCommand.Korifi,
+	}
+}
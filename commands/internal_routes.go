@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pivotalservices/cf-mgmt/route"
+)
+
+type InternalRoutesCommand struct {
+	BaseCFConfigCommand
+	BasePeekCommand
+	BaseLockCommand
+}
+
+//Execute - deletes the routes on internal domains of spaces that do not allow them, when enforced
+func (c *InternalRoutesCommand) Execute([]string) error {
+	cfMgmt, err := InitializePeekManagers(c.BaseCFConfigCommand, c.Peek)
+	if err != nil {
+		return err
+	}
+	return cfMgmt.RouteManager.EnforceInternalRoutes()
+}
+
+type InternalRouteReportCommand struct {
+	BaseCFConfigCommand
+	Format string `long:"format" description:"Output format of the report" default:"table" choice:"table" choice:"csv"`
+}
+
+//Execute - reports the routes on internal domains of spaces that do not allow them
+func (c *InternalRouteReportCommand) Execute([]string) error {
+	cfMgmt, err := InitializeManagers(c.BaseCFConfigCommand)
+	if err != nil {
+		return err
+	}
+	violations, err := cfMgmt.RouteManager.InternalRouteViolations()
+	if err != nil {
+		return err
+	}
+	if c.Format == "csv" {
+		return writeInternalRoutesCSV(os.Stdout, violations)
+	}
+	return writeInternalRoutesTable(os.Stdout, violations)
+}
+
+func writeInternalRoutesTable(out io.Writer, violations []route.InternalRouteViolation) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ORG\tSPACE\tROUTE")
+	for _, violation := range violations {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", violation.Org, violation.Space, violation.Route)
+	}
+	return w.Flush()
+}
+
+func writeInternalRoutesCSV(out io.Writer, violations []route.InternalRouteViolation) error {
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"org", "space", "route"}); err != nil {
+		return err
+	}
+	for _, violation := range violations {
+		if err := w.Write([]string{violation.Org, violation.Space, violation.Route}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
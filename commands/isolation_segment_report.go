@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pivotalservices/cf-mgmt/isosegment"
+)
+
+type IsolationSegmentReportCommand struct {
+	BaseCFConfigCommand
+	CellCapacity map[string]int `long:"cell-capacity" description:"Memory in megabytes of the cells of an isolation segment, as segment:MB, specify multiple times"`
+	Format       string         `long:"format" description:"Output format of the report" default:"table" choice:"table" choice:"csv" choice:"json"`
+}
+
+//Execute - reports, for each isolation segment, the managed orgs configured on it and entitled to it and the
+//started apps of the managed spaces running on it
+func (c *IsolationSegmentReportCommand) Execute([]string) error {
+	for segment, capacity := range c.CellCapacity {
+		if capacity < 1 {
+			return fmt.Errorf("--cell-capacity of %s must be at least 1 MB, not %d", segment, capacity)
+		}
+	}
+	cfMgmt, err := InitializeManagers(c.BaseCFConfigCommand)
+	if err != nil {
+		return err
+	}
+	report, err := cfMgmt.IsolationSegmentManager.PlacementReport(c.CellCapacity)
+	if err != nil {
+		return err
+	}
+	switch c.Format {
+	case "csv":
+		return writeSegmentPlacementCSV(os.Stdout, report)
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	return writeSegmentPlacementTable(os.Stdout, report)
+}
+
+func writeSegmentPlacementTable(out io.Writer, report []isosegment.SegmentPlacement) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SEGMENT\tORGS CONFIGURED\tORGS ENTITLED\tSPACES\tSTARTED APPS\tINSTANCES\tMANAGED MEMORY (MB)\tCAPACITY (MB)\tUSED %\tFINDINGS")
+	for _, p := range report {
+		capacity, used := "-", "-"
+		if p.CapacityMB > 0 {
+			capacity, used = strconv.Itoa(p.CapacityMB), strconv.Itoa(p.Utilization())
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%d\t%s\t%s\t%s\n", p.Segment, strings.Join(p.ConfiguredOrgs, ","), strings.Join(p.EntitledOrgs, ","),
+			p.Spaces, p.Apps, p.Instances, p.ManagedMemoryMB, capacity, used, strings.Join(p.Findings, "; "))
+	}
+	return w.Flush()
+}
+
+func writeSegmentPlacementCSV(out io.Writer, report []isosegment.SegmentPlacement) error {
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"segment", "exists", "configured_orgs", "entitled_orgs", "spaces", "apps", "instances", "managed_memory_mb", "capacity_mb", "findings"}); err != nil {
+		return err
+	}
+	for _, p := range report {
+		capacity := ""
+		if p.CapacityMB > 0 {
+			capacity = strconv.Itoa(p.CapacityMB)
+		}
+		if err := w.Write([]string{p.Segment, strconv.FormatBool(p.Exists), strings.Join(p.ConfiguredOrgs, ";"), strings.Join(p.EntitledOrgs, ";"),
+			strconv.Itoa(p.Spaces), strconv.Itoa(p.Apps), strconv.Itoa(p.Instances), strconv.Itoa(p.ManagedMemoryMB), capacity,
+			strings.Join(p.Findings, "; ")}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -3,6 +3,7 @@ package commands
 type IsolationSegmentsCommand struct {
 	BaseCFConfigCommand
 	BasePeekCommand
+	BaseLockCommand
 }
 
 //Execute - updates spaces
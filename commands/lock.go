@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/pivotalservices/cf-mgmt/lock"
+	"github.com/xchapter7x/lo"
+)
+
+//AcquireLock - acquires the advisory run lock when enabled, returning the func that releases it
+func AcquireLock(baseCommand BaseCFConfigCommand, lockCommand BaseLockCommand, peek bool) (func(), error) {
+	if !lockCommand.Lock {
+		return func() {}, nil
+	}
+	if baseCommand.Simulate != "" || baseCommand.Replay != "" {
+		lo.G.Debug("Skipping cf-mgmt lock while simulating or replaying")
+		return func() {}, nil
+	}
+	if baseCommand.Korifi {
+		return nil, fmt.Errorf("--lock is not supported with --korifi, as the lock is kept in uaa")
+	}
+	if err := baseCommand.ConfigureHTTP(); err != nil {
+		return nil, err
+	}
+	lockMgr, err := lock.NewManager(baseCommand.SystemDomain, baseCommand.UserID, baseCommand.ClientSecret, lockCommand.LockHolder, time.Duration(lockCommand.LockTTL)*time.Minute, peek)
+	if err != nil {
+		return nil, err
+	}
+	if err = lockMgr.Acquire(); err != nil {
+		return nil, err
+	}
+	if peek {
+		return func() {}, nil
+	}
+	stopRenewing := renewLock(lockMgr, time.Duration(lockCommand.LockTTL)*time.Minute/3)
+	return func() {
+		stopRenewing()
+		if err := lockMgr.Release(); err != nil {
+			lo.G.Error(err)
+		}
+	}, nil
+}
+
+// renewLock renews the lock every interval, as watch renews its lease, so that
+// a run that outlasts the ttl keeps the lock, until the returned func is called
+func renewLock(lockMgr lock.Manager, interval time.Duration) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(interval):
+			}
+			if err := lockMgr.Renew(); err != nil {
+				lo.G.Errorf("Unable to renew the cf-mgmt lock, another run may take it over: %s", err)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+type lockCommand interface {
+	cfConfigCommand
+	lockConfig() BaseLockCommand
+}
+
+func (c BaseLockCommand) lockConfig() BaseLockCommand {
+	return c
+}
+
+// eachRunLockCommand is a long running command that takes the lock for each
+// run of its own, rather than for as long as it runs
+type eachRunLockCommand interface {
+	locksEachRun()
+}
+
+// lockedCommand holds the lock for as long as a command that changes the
+// foundation runs
+type lockedCommand struct {
+	flags.Commander
+}
+
+func (c *lockedCommand) Execute(args []string) error {
+	command, ok := unwrapCommand(c.Commander).(lockCommand)
+	if _, eachRun := command.(eachRunLockCommand); !ok || eachRun {
+		return c.Commander.Execute(args)
+	}
+	peek := false
+	if peeking, ok := command.(peekCommand); ok {
+		peek = peeking.peek()
+	}
+	releaseLock, err := AcquireLock(command.cfConfig(), command.lockConfig(), peek)
+	if err != nil {
+		return err
+	}
+	defer releaseLock()
+	return c.Commander.Execute(args)
+}
+
+//WithLock - returns the command holding the advisory run lock while it runs, when it changes the foundation and runs with --lock
+func WithLock(command flags.Commander) flags.Commander {
+	return &lockedCommand{Commander: command}
+}
@@ -0,0 +1,46 @@
+package commands_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/commands"
+)
+
+type fakeLockCommand struct {
+	commands.BaseCFConfigCommand
+	commands.BasePeekCommand
+	commands.BaseLockCommand
+	executed bool
+}
+
+func (c *fakeLockCommand) Execute([]string) error {
+	c.executed = true
+	return nil
+}
+
+var _ = Describe("WithLock", func() {
+	var command *fakeLockCommand
+
+	BeforeEach(func() {
+		command = &fakeLockCommand{}
+		command.SystemDomain = "sys.example.com"
+		command.Korifi = true
+	})
+
+	It("runs the command without the lock when --lock is not set", func() {
+		Expect(commands.WithLock(command).Execute(nil)).Should(Succeed())
+		Expect(command.executed).Should(BeTrue())
+	})
+
+	It("takes the lock before the command changes the foundation", func() {
+		command.Lock = true
+		err := commands.WithLock(command).Execute(nil)
+		Expect(err).Should(MatchError("--lock is not supported with --korifi, as the lock is kept in uaa"))
+		Expect(command.executed).Should(BeFalse())
+	})
+
+	It("runs commands that do not change the foundation without the lock", func() {
+		report := &fakeCFCommand{}
+		Expect(commands.WithLock(report).Execute(nil)).Should(Succeed())
+	})
+})
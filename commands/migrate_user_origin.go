@@ -0,0 +1,22 @@
+package commands
+
+type MigrateUserOriginCommand struct {
+	BaseCFConfigCommand
+	BaseLDAPCommand
+	BasePeekCommand
+	BaseLockCommand
+}
+
+//Execute - moves uaa users to another origin as described in origin-migration.yml
+func (c *MigrateUserOriginCommand) Execute([]string) error {
+	var cfMgmt *CFMgmt
+	var err error
+	if cfMgmt, err = InitializePeekManagers(c.BaseCFConfigCommand, c.Peek); err != nil {
+		return err
+	}
+	if err = cfMgmt.UserManager.InitializeLdap(c.LdapPassword); err != nil {
+		return err
+	}
+	defer cfMgmt.UserManager.DeinitializeLdap()
+	return cfMgmt.UserManager.MigrateUserOrigin()
+}
@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pivotalservices/cf-mgmt/user"
+	"github.com/xchapter7x/lo"
+)
+
+type MissingUsersCommand struct {
+	BaseCFConfigCommand
+}
+
+//Execute - reports the internal users in the configuration that do not exist in uaa
+func (c *MissingUsersCommand) Execute([]string) error {
+	var cfMgmt *CFMgmt
+	var err error
+	if cfMgmt, err = InitializeManagers(c.BaseCFConfigCommand); err != nil {
+		return err
+	}
+	missing, err := cfMgmt.UserManager.ListMissingUsers()
+	if err != nil {
+		return err
+	}
+	for _, m := range missing {
+		lo.G.Warningf("user %s doesn't exist in uaa and must be created before it can be given role %s", m.UserName, m.Role)
+	}
+	return writeMissingUsers(os.Stdout, missing)
+}
+
+func writeMissingUsers(out io.Writer, missing []user.MissingUser) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "USER\tORG\tSPACE\tROLE")
+	for _, m := range missing {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", m.UserName, m.Org, m.Space, m.Role)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,144 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pivotalservices/cf-mgmt/cfmgmt"
+	"github.com/pivotalservices/cf-mgmt/cloudevents"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/console"
+	"github.com/pivotalservices/cf-mgmt/dryrun"
+	"github.com/pivotalservices/cf-mgmt/redact"
+	"github.com/pivotalservices/cf-mgmt/simulator"
+	"github.com/xchapter7x/lo"
+)
+
+type PlanCommand struct {
+	BaseCFConfigCommand
+	BaseEventsCommand
+	FromSnapshot string `long:"from-snapshot" env:"FROM_SNAPSHOT" required:"true" description:"Snapshot of the foundation, written by export-snapshot, to compute the plan against"`
+	Format       string `long:"format" description:"Output format of the plan" default:"text" choice:"text" choice:"json"`
+}
+
+// a plan never changes the foundation, nor records the run state of --changed-only
+func (c *PlanCommand) peek() bool {
+	return true
+}
+
+//Execute - applies the configuration to an in-memory copy of the snapshot and lists what changed
+func (c *PlanCommand) Execute([]string) error {
+	sink, err := c.sink()
+	if err != nil {
+		return err
+	}
+	snapshot, err := simulator.LoadSnapshot(c.FromSnapshot)
+	if err != nil {
+		return err
+	}
+	lo.G.Infof("planning against snapshot %s, nothing is changed on %s", c.FromSnapshot, c.SystemDomain)
+	changes, report, applyErr := simulatePlan(c.BaseCFConfigCommand, snapshot, "", true)
+	if report == nil {
+		return applyErr
+	}
+	if applyErr != nil {
+		fmt.Println("********* Plan Report")
+		fmt.Print(redact.String(report.String()))
+	}
+	if err := writeChanges(os.Stdout, "Plan", changes, c.Format); err != nil {
+		return err
+	}
+	if err := emitChanges(sink, c.SystemDomain, cloudevents.Planned, changes); err != nil {
+		return err
+	}
+	return applyErr
+}
+
+// simulatePlan applies the configuration to an in-memory copy of the snapshot,
+// returning what changed along with the report of the simulated apply, which
+// is nil when the apply could not start. An offline plan never binds to ldap,
+// leaving the ldap users of roles as they are in the snapshot.
+func simulatePlan(baseCommand BaseCFConfigCommand, snapshot *simulator.Snapshot, ldapPassword string, offline bool) ([]simulator.Change, *cfmgmt.ApplyReport, error) {
+	protectedOrgs, err := configuredProtectedOrgs(baseCommand.ConfigDirectory)
+	if err != nil {
+		return nil, nil, err
+	}
+	foundation, err := simulator.NewFoundation(snapshot)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg := managerConfig(context.Background(), baseCommand, false)
+	cfg.SkipLdap = offline
+	cfMgmt, err := cfmgmt.NewWithClient(cfg, foundation, foundation.UAAManager(false))
+	if err != nil {
+		return nil, nil, err
+	}
+	// the roles the managers grant are recorded with where they come from
+	dryrun.Start()
+	// every step runs so the plan covers as much of the configuration as it can
+	report, applyErr := cfMgmt.ApplyWithFailureBudget(context.Background(), ldapPassword, len(cfMgmt.ApplySteps()))
+	recorded := dryrun.Stop()
+	after, err := foundation.Snapshot()
+	if err != nil {
+		return nil, nil, err
+	}
+	changes, err := simulator.DiffUnprotected(snapshot, after, protectedOrgs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attributeRoles(changes, recorded), report, applyErr
+}
+
+// attributeRoles gives the roles granted by the plan the source, such as from
+// ldap group devs in org1/orgConfig.yml, the user manager recorded them with
+// when attribute-roles of cf-mgmt.yml is set
+func attributeRoles(changes []simulator.Change, recorded []dryrun.Change) []simulator.Change {
+	sources := make(map[string]string)
+	for _, change := range recorded {
+		if change.Action == dryrun.Create && change.Detail != "" {
+			sources[change.Kind+" "+change.Name] = change.Detail
+		}
+	}
+	for i, change := range changes {
+		if change.Action != simulator.ActionCreate || (change.Kind != dryrun.OrgRole && change.Kind != dryrun.SpaceRole) {
+			continue
+		}
+		// the snapshot names the billing_manager role billing_managers
+		if source, ok := sources[change.Kind+" "+strings.Replace(change.Name, "_", " ", 1)]; ok {
+			changes[i].Detail = source
+		}
+	}
+	return changes
+}
+
+// configuredProtectedOrgs returns the protected_orgs of orgs.yml, whose
+// changes are left out of plans
+func configuredProtectedOrgs(configDirectory string) ([]string, error) {
+	orgsConfig, err := config.NewManager(configDirectory).Orgs()
+	if err != nil {
+		return nil, err
+	}
+	return orgsConfig.ProtectedOrgs, nil
+}
+
+func writeChanges(out io.Writer, title string, changes []simulator.Change, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(changes)
+	}
+	fmt.Fprintln(out, "********* "+title)
+	for _, change := range changes {
+		line := change.Kind + " " + change.Name
+		if change.Detail != "" {
+			line += ": " + change.Detail
+		}
+		fmt.Fprintln(out, console.Tag(strings.ToUpper(change.Action), redact.String(line)))
+	}
+	fmt.Fprintf(out, "%d changes\n", len(changes))
+	return nil
+}
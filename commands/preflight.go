@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"fmt"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/endpoint"
+	"github.com/pivotalservices/cf-mgmt/httpclient"
+	"github.com/pivotalservices/cf-mgmt/preflight"
+	"github.com/pivotalservices/cf-mgmt/redact"
+)
+
+type PreflightCommand struct {
+	BaseCFConfigCommand
+	BaseLDAPCommand
+}
+
+//Execute - verifies the credentials and scopes cf-mgmt runs with
+func (c *PreflightCommand) Execute([]string) error {
+	return RunPreflight(c.BaseCFConfigCommand, c.LdapPassword)
+}
+
+//RunPreflight - verifies the cloud controller and uaa credentials, the uaa
+//scopes and the ldap bind, printing the outcome of each check
+func RunPreflight(baseCommand BaseCFConfigCommand, ldapPassword string) error {
+	if baseCommand.SystemDomain == "" ||
+		baseCommand.UserID == "" ||
+		baseCommand.ClientSecret == "" {
+		return fmt.Errorf("must set system-domain, user-id, client-secret properties")
+	}
+	redact.Secrets(baseCommand.Password, baseCommand.ClientSecret, ldapPassword)
+	if err := baseCommand.ConfigureHTTP(); err != nil {
+		return err
+	}
+	ldapConfig, err := config.NewManager(baseCommand.ConfigDirectory).LdapConfig(ldapPassword)
+	if err != nil {
+		return err
+	}
+	cfg := preflightConfig(baseCommand)
+	cfg.LdapConfig = ldapConfig
+	result := preflight.Run(cfg)
+	fmt.Println("********* Preflight")
+	fmt.Print(redact.String(result.String()))
+	return result.Error()
+}
+
+func preflightConfig(baseCommand BaseCFConfigCommand) preflight.Config {
+	return preflight.Config{
+		APIAddress:   endpoint.API(baseCommand.SystemDomain),
+		UAAAddress:   endpoint.UAA(baseCommand.SystemDomain),
+		UserID:       baseCommand.UserID,
+		Password:     baseCommand.Password,
+		ClientSecret: baseCommand.ClientSecret,
+		Scopes:       append(configScopes(config.NewManager(baseCommand.ConfigDirectory)), baseCommand.commandScopes...),
+		Transport:    httpclient.Transport(),
+	}
+}
+
+// userWriteCommands are the commands that create, update or delete uaa users
+// or groups, which need preflight.UserWriteScope. Preflight verifies the scopes
+// apply needs.
+var userWriteCommands = map[string]bool{
+	"apply":                  true,
+	"cleanup-origin-users":   true,
+	"create-personal-spaces": true,
+	"dedupe-uaa-users":       true,
+	"migrate-user-origin":    true,
+	"preflight":              true,
+	"sync-users-on-events":   true,
+	"update-org-users":       true,
+	"update-role-groups":     true,
+	"update-space-users":     true,
+	"update-users":           true,
+	"watch":                  true,
+}
+
+type scopesCommand interface {
+	requireScopes(scopes ...string)
+}
+
+func (c *BaseCFConfigCommand) requireScopes(scopes ...string) {
+	c.commandScopes = append(c.commandScopes, scopes...)
+}
+
+//RequireCommandScopes - the command named name verifies the uaa scopes it needs besides preflight.RequiredScopes,
+//scim.write when it writes users
+func RequireCommandScopes(name string, command flags.Commander) {
+	if scoped, ok := command.(scopesCommand); ok && userWriteCommands[name] {
+		scoped.requireScopes(preflight.UserWriteScope)
+	}
+}
+
+// configScopes are the uaa scopes the configuration needs besides
+// preflight.RequiredScopes: the idps scopes when identity-providers.yml
+// manages identity providers and the zones scopes when cf-mgmt.yml sets a
+// token policy. A configuration that cannot be read needs none, its error is
+// reported when it is applied.
+func configScopes(reader config.Reader) []string {
+	var scopes []string
+	if providers, err := reader.GetIdentityProviders(); err == nil && providers != nil {
+		scopes = append(scopes, "idps.read", "idps.write")
+	}
+	if globalConfig, err := reader.GetGlobalConfig(); err == nil && globalConfig.TokenPolicy != nil {
+		scopes = append(scopes, "zones.read", "zones.write")
+	}
+	return scopes
+}
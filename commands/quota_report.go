@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/quota"
+)
+
+type QuotaReportCommand struct {
+	BaseCFConfigCommand
+	BasePeekCommand
+	Format     string `long:"format" description:"Output format of the report" default:"table" choice:"table" choice:"csv" choice:"json"`
+	Notify     bool   `long:"notify" env:"NOTIFY" description:"Email the org managers of the orgs that breach a threshold of their quota-alerts, as configured by quota-notifications in cf-mgmt.yml"`
+	Overcommit bool   `long:"overcommit" description:"Report the orgs whose space quotas add up to more than their org quota, beyond the space-quota-tolerance of cf-mgmt.yml, instead of the usage of their quota"`
+}
+
+//Execute - reports the usage of the quota of the orgs that set quota-alerts against their thresholds, and with
+//--notify emails the org managers of the orgs that breach them, or with --overcommit reports the orgs whose
+//space quotas add up to more than their org quota
+func (c *QuotaReportCommand) Execute([]string) error {
+	cfMgmt, err := InitializePeekManagers(c.BaseCFConfigCommand, c.Peek)
+	if err != nil {
+		return err
+	}
+	if c.Overcommit {
+		return c.reportOvercommits(cfMgmt.QuotaManager)
+	}
+	utilizations, err := cfMgmt.QuotaManager.QuotaUtilization()
+	if err != nil {
+		return err
+	}
+	switch c.Format {
+	case "csv":
+		err = writeQuotaUtilizationCSV(os.Stdout, utilizations)
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(utilizations)
+	default:
+		err = writeQuotaUtilizationTable(os.Stdout, utilizations)
+	}
+	if err != nil || !c.Notify {
+		return err
+	}
+	_, err = cfMgmt.QuotaManager.NotifyBreaches(utilizations)
+	return err
+}
+
+func (c *QuotaReportCommand) reportOvercommits(quotaMgr quota.Manager) error {
+	overcommits, err := quotaMgr.SpaceQuotaOvercommits()
+	if err != nil {
+		return err
+	}
+	switch c.Format {
+	case "csv":
+		return writeQuotaOvercommitsCSV(os.Stdout, overcommits)
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(overcommits)
+	default:
+		return writeQuotaOvercommitsTable(os.Stdout, overcommits)
+	}
+}
+
+func writeQuotaOvercommitsTable(out io.Writer, overcommits []config.QuotaOvercommit) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ORG\tLIMIT\tORG QUOTA\tSPACE QUOTAS\tSPACES")
+	for _, o := range overcommits {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\n", o.Org, o.Limit, o.OrgLimit, o.SpaceTotal, strings.Join(o.Spaces, ","))
+	}
+	return w.Flush()
+}
+
+func writeQuotaOvercommitsCSV(out io.Writer, overcommits []config.QuotaOvercommit) error {
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"org", "limit", "org_limit", "space_total", "spaces"}); err != nil {
+		return err
+	}
+	for _, o := range overcommits {
+		if err := w.Write([]string{o.Org, o.Limit, strconv.Itoa(o.OrgLimit), strconv.Itoa(o.SpaceTotal), strings.Join(o.Spaces, ",")}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func writeQuotaUtilizationTable(out io.Writer, utilizations []quota.Utilization) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ORG\tQUOTA\tRESOURCE\tUSED\tLIMIT\tPERCENT\tTHRESHOLD\tBREACHED")
+	for _, u := range utilizations {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%d%%\t%d%%\t%t\n", u.Org, u.Quota, u.Resource, u.Used, formatQuotaLimit(u.Limit), u.Percent, u.Threshold, u.Breached)
+	}
+	return w.Flush()
+}
+
+func writeQuotaUtilizationCSV(out io.Writer, utilizations []quota.Utilization) error {
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"org", "quota", "resource", "used", "limit", "percent", "threshold", "breached"}); err != nil {
+		return err
+	}
+	for _, u := range utilizations {
+		if err := w.Write([]string{u.Org, u.Quota, u.Resource, strconv.Itoa(u.Used), strconv.Itoa(u.Limit),
+			strconv.Itoa(u.Percent), strconv.Itoa(u.Threshold), strconv.FormatBool(u.Breached)}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func formatQuotaLimit(limit int) string {
+	if limit < 0 {
+		return "unlimited"
+	}
+	return strconv.Itoa(limit)
+}
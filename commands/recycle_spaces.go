@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/xchapter7x/lo"
+)
+
+type RecycleSpacesCommand struct {
+	BaseCFConfigCommand
+	BaseLDAPCommand
+	BasePeekCommand
+	BaseLockCommand
+	Force bool `long:"force" env:"FORCE" description:"Recycle every ephemeral space, whether or not its recycle-schedule fired since it was created"`
+}
+
+//Execute - deletes and recreates the ephemeral spaces that are due, restoring their roles, quotas, security groups
+//and isolation segments from the configuration
+func (c *RecycleSpacesCommand) Execute([]string) error {
+	cfMgmt, err := InitializePeekManagers(c.BaseCFConfigCommand, c.Peek)
+	if err != nil {
+		return err
+	}
+	recycled, err := cfMgmt.SpaceManager.RecycleSpaces(c.Force)
+	if err != nil {
+		return err
+	}
+	if len(recycled) == 0 {
+		lo.G.Info("No ephemeral space is due to be recycled")
+		return nil
+	}
+	lo.G.Infof("Restoring the configuration of the recycled spaces %s", strings.Join(recycled, ", "))
+	if err := cfMgmt.SpaceManager.UpdateSpaces(); err != nil {
+		return err
+	}
+	if err := cfMgmt.QuotaManager.CreateSpaceQuotas(); err != nil {
+		return err
+	}
+	if err := cfMgmt.SecurityGroupManager.CreateApplicationSecurityGroups(); err != nil {
+		return err
+	}
+	if err := cfMgmt.IsolationSegmentManager.Apply(); err != nil {
+		return err
+	}
+	if err := cfMgmt.UserManager.InitializeLdap(c.LdapPassword); err != nil {
+		return err
+	}
+	defer cfMgmt.UserManager.DeinitializeLdap()
+	return cfMgmt.UserManager.UpdateSpaceUsers()
+}
@@ -0,0 +1,27 @@
+package commands
+
+import (
+	flags "github.com/jessevdk/go-flags"
+	"github.com/pivotalservices/cf-mgmt/redact"
+)
+
+// redactedCommand logs through a logger that redacts secrets, and usernames
+// and emails when --redact is set. It wraps the summary logger so the run
+// summary is redacted as well.
+type redactedCommand struct {
+	flags.Commander
+}
+
+func (c *redactedCommand) Execute(args []string) error {
+	return redact.WithLogger(func() error {
+		return c.Commander.Execute(args)
+	})
+}
+
+//WithRedaction - returns the command logging through a redacting logger for the redact mode
+func WithRedaction(command flags.Commander, mode string) (flags.Commander, error) {
+	if err := redact.SetMode(mode); err != nil {
+		return nil, err
+	}
+	return &redactedCommand{Commander: command}, nil
+}
@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/pivotalservices/cf-mgmt/clientsecret"
+	"github.com/pivotalservices/cf-mgmt/preflight"
+	"github.com/pivotalservices/cf-mgmt/redact"
+	"github.com/pivotalservices/cf-mgmt/secretstore"
+	"github.com/pivotalservices/cf-mgmt/uaa"
+)
+
+type RotateClientSecretCommand struct {
+	BaseCFConfigCommand
+	BasePeekCommand
+	BaseLockCommand
+	CredHubURL          string `long:"credhub-url" env:"CREDHUB_URL" description:"Url of the credhub to write the new secret to"`
+	CredHubClientID     string `long:"credhub-client-id" env:"CREDHUB_CLIENT_ID" description:"Client that writes to credhub"`
+	CredHubClientSecret string `long:"credhub-client-secret" env:"CREDHUB_CLIENT_SECRET" description:"Secret of the client that writes to credhub"`
+	CredHubName         string `long:"credhub-name" env:"CREDHUB_NAME" description:"Name of the credhub password credential the new secret is written to"`
+	VaultAddr           string `long:"vault-addr" env:"VAULT_ADDR" description:"Address of the vault to write the new secret to"`
+	VaultToken          string `long:"vault-token" env:"VAULT_TOKEN" description:"Token that writes to vault"`
+	VaultPath           string `long:"vault-path" env:"VAULT_PATH" description:"Key/value version 2 secret the new secret is written to, starting with its mount, such as secret/cf-mgmt"`
+	VaultKey            string `long:"vault-key" env:"VAULT_KEY" default:"client-secret" description:"Key of the vault secret the new secret is written to"`
+	SecretFile          string `long:"secret-file" env:"SECRET_FILE" description:"File the new secret is written to"`
+	SkipSSLValidation   bool   `long:"skip-ssl-validation" env:"SKIP_SSL_VALIDATION" description:"Skip verifying the certificates of credhub and vault"`
+}
+
+//Execute - rotates the secret of the client cf-mgmt authenticates as, writing the new secret to credhub, vault or a file
+func (c *RotateClientSecretCommand) Execute([]string) error {
+	if c.SystemDomain == "" || c.UserID == "" || c.ClientSecret == "" {
+		return fmt.Errorf("must set system-domain, user-id, client-secret properties")
+	}
+	redact.Secrets(c.ClientSecret)
+	if err := c.ConfigureHTTP(); err != nil {
+		return err
+	}
+	stores, err := c.stores()
+	if err != nil {
+		return err
+	}
+	cfg := preflightConfig(c.BaseCFConfigCommand)
+	cfg.Scopes = append(cfg.Scopes, "clients.secret")
+	if err := preflight.VerifyScopes(cfg); err != nil {
+		return err
+	}
+	uaaMgr, err := uaa.NewDefaultUAAManager(c.SystemDomain, c.UserID, c.ClientSecret, c.Peek)
+	if err != nil {
+		return err
+	}
+	rotation := &clientsecret.Rotation{
+		UAAMgr:   uaaMgr,
+		ClientID: c.UserID,
+		Secret:   c.ClientSecret,
+		Stores:   stores,
+		Verify: func(secret string) error {
+			return uaa.VerifyClientSecret(c.SystemDomain, c.UserID, secret)
+		},
+		Peek: c.Peek,
+	}
+	return rotation.Rotate()
+}
+
+func (c *RotateClientSecretCommand) stores() ([]secretstore.Store, error) {
+	var stores []secretstore.Store
+	if c.CredHubURL != "" {
+		if c.CredHubName == "" {
+			return nil, fmt.Errorf("--credhub-name is required with --credhub-url")
+		}
+		credHub, err := secretstore.NewCredHub(c.CredHubURL, c.CredHubClientID, c.CredHubClientSecret, c.SkipSSLValidation)
+		if err != nil {
+			return nil, err
+		}
+		stores = append(stores, credHub.Password(c.CredHubName))
+	}
+	if c.VaultAddr != "" {
+		if c.VaultPath == "" {
+			return nil, fmt.Errorf("--vault-path is required with --vault-addr")
+		}
+		vault, err := secretstore.NewVault(c.VaultAddr, c.VaultToken, c.SkipSSLValidation)
+		if err != nil {
+			return nil, err
+		}
+		stores = append(stores, vault.Secret(c.VaultPath, c.VaultKey))
+	}
+	if c.SecretFile != "" {
+		stores = append(stores, secretstore.File(c.SecretFile))
+	}
+	return stores, nil
+}
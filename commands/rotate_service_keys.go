@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/secretstore"
+	"github.com/pivotalservices/cf-mgmt/service"
+)
+
+type RotateServiceKeysCommand struct {
+	BaseCFConfigCommand
+	BasePeekCommand
+	BaseLockCommand
+	Format string `long:"format" description:"Output format of the keys due for rotation" default:"table" choice:"table" choice:"json"`
+}
+
+//Execute - recreates the service keys older than the service-key-rotation policy of the spaces that opted in,
+//writing their new credentials to credhub, and lists every key due for rotation
+func (c *RotateServiceKeysCommand) Execute([]string) error {
+	globalConfig, err := config.NewManager(c.ConfigDirectory).GetGlobalConfig()
+	if err != nil {
+		return err
+	}
+	policy := globalConfig.ServiceKeyRotation
+	if policy == nil {
+		return fmt.Errorf("service-key-rotation must be set in cf-mgmt.yml to rotate service keys")
+	}
+	var credentials service.CredentialWriter
+	if !c.Peek {
+		secret := os.Getenv("CREDHUB_CLIENT_SECRET")
+		if policy.CredHub.URL == "" || policy.CredHub.ClientID == "" || secret == "" {
+			return fmt.Errorf("service-key-rotation requires the url and client-id of credhub in cf-mgmt.yml and CREDHUB_CLIENT_SECRET")
+		}
+		credHub, err := secretstore.NewCredHub(policy.CredHub.URL, policy.CredHub.ClientID, secret, policy.CredHub.SkipSSLValidation)
+		if err != nil {
+			return err
+		}
+		credentials = credHub
+	}
+	cfMgmt, err := InitializePeekManagers(c.BaseCFConfigCommand, c.Peek)
+	if err != nil {
+		return err
+	}
+	rotations, err := cfMgmt.ServiceManager.RotateServiceKeys(*policy, credentials)
+	if err != nil {
+		return err
+	}
+	if c.Format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(rotations)
+	} else {
+		err = writeKeyRotationsTable(os.Stdout, rotations)
+	}
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, rotation := range rotations {
+		if rotation.Status == service.RotationFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d service keys due for rotation failed to rotate", failed, len(rotations))
+	}
+	return nil
+}
+
+func writeKeyRotationsTable(out io.Writer, rotations []service.KeyRotation) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ORG\tSPACE\tINSTANCE\tKEY\tAGE (DAYS)\tSTATUS\tCREDHUB")
+	for _, r := range rotations {
+		status := r.Status
+		if r.Error != "" {
+			status += ": " + r.Error
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", r.Org, r.Space, r.Instance, r.Key, r.AgeDays, status, r.CredHubName)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"path/filepath"
+	"strings"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/xchapter7x/lo"
+)
+
+// defaultStateFile is the run state file in the config directory
+const defaultStateFile = ".cf-mgmt-state.json"
+
+type changedOrgsCommand interface {
+	cfConfigCommand
+	selectChangedOrgs(orgNames []string)
+	onOrgsSelected(selected func(orgNames []string))
+}
+
+func (c *BaseCFConfigCommand) selectChangedOrgs(orgNames []string) {
+	c.changedOrgs = orgNames
+}
+
+func (c *BaseCFConfigCommand) onOrgsSelected(selected func(orgNames []string)) {
+	c.orgsSelected = selected
+}
+
+func (c BaseCFConfigCommand) stateFile() string {
+	if c.StateFile != "" {
+		return c.StateFile
+	}
+	return filepath.Join(config.BaseDirectory(c.ConfigDirectory), defaultStateFile)
+}
+
+// changedOnlyCommand limits the command to the orgs whose configuration
+// changed since its last successful run, and records the configuration of
+// the orgs it processed once it succeeds, every org unless --org-selector
+// limited the run.
+type changedOnlyCommand struct {
+	flags.Commander
+	name string
+}
+
+func (c *changedOnlyCommand) Execute(args []string) error {
+	command, ok := c.Commander.(changedOrgsCommand)
+	if !ok || !command.cfConfig().ChangedOnly {
+		return c.Commander.Execute(args)
+	}
+	baseCommand := command.cfConfig()
+	stateFile := baseCommand.stateFile()
+	state, err := config.LoadRunState(stateFile)
+	if err != nil {
+		return err
+	}
+	hashes, err := config.OrgConfigHashes(config.NewManager(baseCommand.ConfigDirectory))
+	if err != nil {
+		return err
+	}
+	changed := state.ChangedOrgs(c.name, hashes)
+	if changed == nil {
+		changed = []string{}
+	}
+	lo.G.Infof("Limiting %s to the %d orgs changed since its last successful run: %s", c.name, len(changed), strings.Join(changed, ", "))
+	command.selectChangedOrgs(changed)
+	var processed []string
+	if baseCommand.OrgSelector != "" {
+		// the orgs that do not match the selector are not processed, so they stay changed
+		processed = []string{}
+		command.onOrgsSelected(func(orgNames []string) {
+			processed = orgNames
+		})
+	}
+
+	if err := c.Commander.Execute(args); err != nil {
+		return err
+	}
+	if peeking, ok := c.Commander.(peekCommand); ok && peeking.peek() {
+		return nil
+	}
+	if processed == nil {
+		state.Update(c.name, hashes)
+	} else {
+		state.UpdateOrgs(c.name, hashes, processed)
+	}
+	return state.Save(stateFile)
+}
+
+//WithChangedOnly - returns the command limited to the changed orgs when it runs with --changed-only
+func WithChangedOnly(name string, command flags.Commander) flags.Commander {
+	return &changedOnlyCommand{Commander: command, name: name}
+}
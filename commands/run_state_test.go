@@ -0,0 +1,66 @@
+package commands_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/commands"
+	"github.com/pivotalservices/cf-mgmt/config"
+)
+
+type fakeCFCommand struct {
+	commands.BaseCFConfigCommand
+	err error
+}
+
+func (c *fakeCFCommand) Execute([]string) error {
+	return c.err
+}
+
+var _ = Describe("WithChangedOnly", func() {
+	var (
+		dir       string
+		stateFile string
+		command   *fakeCFCommand
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "cf-mgmt-state")
+		Expect(err).ShouldNot(HaveOccurred())
+		configManager := config.NewManager(filepath.Join(dir, "config"))
+		Expect(configManager.CreateConfigIfNotExists("ldap")).Should(Succeed())
+		Expect(configManager.AddOrgToConfig(&config.OrgConfig{Org: "org1"})).Should(Succeed())
+		stateFile = filepath.Join(dir, "config", ".cf-mgmt-state.json")
+		command = &fakeCFCommand{}
+		command.ConfigDirectory = filepath.Join(dir, "config")
+		command.ChangedOnly = true
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("records the config of the orgs after a successful run", func() {
+		Expect(commands.WithChangedOnly("update-spaces", command).Execute(nil)).Should(Succeed())
+		state, err := config.LoadRunState(stateFile)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(state.Commands["update-spaces"]).Should(HaveKey("org1"))
+	})
+
+	It("does not record a failed run", func() {
+		command.err = errors.New("failed")
+		Expect(commands.WithChangedOnly("update-spaces", command).Execute(nil)).Should(MatchError("failed"))
+		Expect(stateFile).ShouldNot(BeAnExistingFile())
+	})
+
+	It("does not record anything without --changed-only", func() {
+		command.ChangedOnly = false
+		Expect(commands.WithChangedOnly("update-spaces", command).Execute(nil)).Should(Succeed())
+		Expect(stateFile).ShouldNot(BeAnExistingFile())
+	})
+})
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pivotalservices/cf-mgmt/service"
+)
+
+type ServiceReportCommand struct {
+	BaseCFConfigCommand
+	Format string `long:"format" description:"Output format of the report" default:"table" choice:"table" choice:"csv" choice:"json"`
+}
+
+//Execute - reports the service instances of services or plans their org does not allow with allowed-services
+func (c *ServiceReportCommand) Execute([]string) error {
+	cfMgmt, err := InitializeManagers(c.BaseCFConfigCommand)
+	if err != nil {
+		return err
+	}
+	violations, err := cfMgmt.ServiceManager.ServiceViolations()
+	if err != nil {
+		return err
+	}
+	switch c.Format {
+	case "csv":
+		return writeServiceViolationsCSV(os.Stdout, violations)
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(violations)
+	}
+	return writeServiceViolationsTable(os.Stdout, violations)
+}
+
+func writeServiceViolationsTable(out io.Writer, violations []service.Violation) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ORG\tSPACE\tINSTANCE\tSERVICE\tPLAN")
+	for _, violation := range violations {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", violation.Org, violation.Space, violation.Instance, violation.Service, violation.Plan)
+	}
+	return w.Flush()
+}
+
+func writeServiceViolationsCSV(out io.Writer, violations []service.Violation) error {
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"org", "space", "instance", "service", "plan", "guid"}); err != nil {
+		return err
+	}
+	for _, violation := range violations {
+		if err := w.Write([]string{violation.Org, violation.Space, violation.Instance, violation.Service, violation.Plan, violation.GUID}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
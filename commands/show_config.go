@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	yaml "gopkg.in/yaml.v2"
+)
+
+type ShowConfigCommand struct {
+	BaseConfigCommand
+	OrgName   string `long:"org" env:"ORG" description:"Org to show the configuration of" required:"true"`
+	SpaceName string `long:"space" env:"SPACE" description:"Space of the org to show the configuration of, the org is shown when not set"`
+}
+
+//Execute - prints the configuration cf-mgmt applies to the org or space once every layer is merged
+func (c *ShowConfigCommand) Execute([]string) error {
+	reader := config.NewManager(c.ConfigDirectory)
+	var effective interface{}
+	var err error
+	if c.SpaceName != "" {
+		effective, err = config.EffectiveSpaceConfig(reader, c.OrgName, c.SpaceName)
+	} else {
+		effective, err = config.EffectiveOrgConfig(reader, c.OrgName)
+	}
+	if err != nil {
+		return err
+	}
+	out, err := yaml.Marshal(effective)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(out))
+	return nil
+}
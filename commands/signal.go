@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/xchapter7x/lo"
+)
+
+//InterruptContexts - the first interrupt cancels stop so work can finish at the next safe point,
+//a second interrupt cancels abort to cancel in-flight api requests
+func InterruptContexts() (stop context.Context, abort context.Context, release func()) {
+	stop, cancelStop := context.WithCancel(context.Background())
+	abort, cancelAbort := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-signals:
+			lo.G.Warning("interrupted, stopping once the current step completes (interrupt again to abort)")
+			cancelStop()
+		case <-done:
+			return
+		}
+		select {
+		case <-signals:
+			lo.G.Warning("interrupted again, aborting in-flight requests")
+			cancelAbort()
+		case <-done:
+		}
+	}()
+	return stop, abort, func() {
+		signal.Stop(signals)
+		close(done)
+		cancelStop()
+		cancelAbort()
+	}
+}
@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"os"
+)
+
+type StackPolicyCommand struct {
+	BaseCFConfigCommand
+}
+
+//Execute - logs the apps on stacks their org does not allow, failing when enforced
+func (c *StackPolicyCommand) Execute([]string) error {
+	cfMgmt, err := InitializeManagers(c.BaseCFConfigCommand)
+	if err != nil {
+		return err
+	}
+	return cfMgmt.AppManager.EnforceStackPolicy()
+}
+
+type StackReportCommand struct {
+	BaseCFConfigCommand
+	Format string `long:"format" description:"Output format of the report" default:"table" choice:"table" choice:"csv"`
+}
+
+//Execute - reports the apps on stacks their org does not allow
+func (c *StackReportCommand) Execute([]string) error {
+	cfMgmt, err := InitializeManagers(c.BaseCFConfigCommand)
+	if err != nil {
+		return err
+	}
+	violations, err := cfMgmt.AppManager.StackViolations()
+	if err != nil {
+		return err
+	}
+	return writeAppViolations(os.Stdout, c.Format, "stack", violations)
+}
@@ -0,0 +1,155 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/pivotalservices/cf-mgmt/commandhook"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/redact"
+	"github.com/pivotalservices/cf-mgmt/stats"
+	"github.com/xchapter7x/lo"
+)
+
+// RunSummary is the machine readable result of a cf-mgmt run, written so
+// that pipelines can publish it and gate downstream jobs on it.
+type RunSummary struct {
+	Command         string    `json:"command"`
+	Status          string    `json:"status"`
+	StartedAt       time.Time `json:"started_at"`
+	FinishedAt      time.Time `json:"finished_at"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	Changes         []string  `json:"changes"`
+	Warnings        []string  `json:"warnings"`
+	Errors          []string  `json:"errors"`
+	// Stats are the api calls, cache hits and phase durations of the run
+	Stats *stats.Stats `json:"stats"`
+}
+
+// summaryLogger records the info level messages, which cf-mgmt uses to report
+// changes it makes (or would make when peeking), and warnings. Messages can be
+// logged concurrently, such as while ldap groups are resolved in parallel.
+type summaryLogger struct {
+	lo.Logger
+	mutex   sync.Mutex
+	summary *RunSummary
+}
+
+func (l *summaryLogger) Info(args ...interface{}) {
+	l.mutex.Lock()
+	l.summary.Changes = append(l.summary.Changes, strings.TrimSpace(fmt.Sprintln(args...)))
+	l.mutex.Unlock()
+	l.Logger.Info(args...)
+}
+
+func (l *summaryLogger) Infof(format string, args ...interface{}) {
+	l.mutex.Lock()
+	l.summary.Changes = append(l.summary.Changes, fmt.Sprintf(format, args...))
+	l.mutex.Unlock()
+	l.Logger.Infof(format, args...)
+}
+
+func (l *summaryLogger) Warning(args ...interface{}) {
+	l.mutex.Lock()
+	l.summary.Warnings = append(l.summary.Warnings, strings.TrimSpace(fmt.Sprintln(args...)))
+	l.mutex.Unlock()
+	l.Logger.Warning(args...)
+}
+
+func (l *summaryLogger) Warningf(format string, args ...interface{}) {
+	l.mutex.Lock()
+	l.summary.Warnings = append(l.summary.Warnings, fmt.Sprintf(format, args...))
+	l.mutex.Unlock()
+	l.Logger.Warningf(format, args...)
+}
+
+//ExecuteWithStats - executes the command and prints the api calls, cache hits and step durations of the run
+func ExecuteWithStats(command flags.Commander, args []string) error {
+	stats.Reset()
+	err := command.Execute(args)
+	printStats(stats.Snapshot())
+	return err
+}
+
+func printStats(runStats *stats.Stats) {
+	if !runStats.Empty() {
+		fmt.Println("********* Statistics")
+		fmt.Print(runStats.String())
+	}
+}
+
+//ExecuteWithSummary - executes the command, between the command hooks of the configuration, and writes a
+//run summary to summaryFile, when set, records the run on the foundation with --record-history and reports
+//it with --telemetry
+func ExecuteWithSummary(name string, command flags.Commander, args []string, summaryFile string) error {
+	summary := &RunSummary{
+		Command:   name,
+		StartedAt: time.Now().UTC(),
+		Changes:   []string{},
+		Warnings:  []string{},
+		Errors:    []string{},
+	}
+	hooks, configDirectory := commandHooks(command)
+	logger := lo.G
+	lo.G = &summaryLogger{Logger: logger, summary: summary}
+	stats.Reset()
+	// a failing before hook that sets fail-command keeps the command from running
+	err := commandhook.Run(hooks, configDirectory, commandhook.Payload{Command: name, When: config.HookBefore, Summary: summary})
+	if err == nil {
+		err = command.Execute(args)
+	}
+	lo.G = logger
+
+	summary.Stats = stats.Snapshot()
+	printStats(summary.Stats)
+
+	summary.FinishedAt = time.Now().UTC()
+	summary.DurationSeconds = summary.FinishedAt.Sub(summary.StartedAt).Seconds()
+	summary.Status = "succeeded"
+	if err != nil {
+		summary.Status = "failed"
+		summary.Errors = append(summary.Errors, redact.String(err.Error()))
+	}
+	if hookErr := commandhook.Run(hooks, configDirectory, commandhook.Payload{Command: name, When: config.HookAfter, Summary: summary}); hookErr != nil {
+		summary.Status = "failed"
+		summary.Errors = append(summary.Errors, redact.String(hookErr.Error()))
+		if err == nil {
+			err = hookErr
+		}
+	}
+	if CfMgmt.RecordHistory {
+		recordHistory(command, summary)
+	}
+	if CfMgmt.Telemetry {
+		sendTelemetry(command, summary, err)
+	}
+	if summaryFile == "" {
+		return err
+	}
+	if writeErr := WriteSummary(summaryFile, summary); writeErr != nil {
+		lo.G.Errorf("Unable to write run summary to %s: %s", summaryFile, writeErr)
+		if err == nil {
+			err = writeErr
+		}
+	}
+	return err
+}
+
+//WriteSummary - writes the run summary as json, creating the parent directory
+func WriteSummary(summaryFile string, summary *RunSummary) error {
+	if err := os.MkdirAll(filepath.Dir(summaryFile), 0755); err != nil {
+		return err
+	}
+	bytes, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(summaryFile, bytes, 0644)
+}
@@ -0,0 +1,171 @@
+package commands_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/commands"
+	"github.com/pivotalservices/cf-mgmt/stats"
+	"github.com/xchapter7x/lo"
+)
+
+type fakeCommand struct {
+	err error
+}
+
+func (c *fakeCommand) Execute([]string) error {
+	stats.Call(stats.CloudController)
+	lo.G.Infof("[dry-run]: create org %s", "org1")
+	lo.G.Warning("skipping user")
+	return c.err
+}
+
+// fakeSummaryCFCommand runs against the foundation of its config directory
+type fakeSummaryCFCommand struct {
+	commands.BaseCFConfigCommand
+	fakeCommand
+	executed bool
+}
+
+func (c *fakeSummaryCFCommand) Execute(args []string) error {
+	c.executed = true
+	return c.fakeCommand.Execute(args)
+}
+
+var _ = Describe("ExecuteWithSummary", func() {
+	var (
+		dir         string
+		summaryFile string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "cf-mgmt-summary")
+		Expect(err).ShouldNot(HaveOccurred())
+		summaryFile = filepath.Join(dir, "run-summary", "summary.json")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	readSummary := func() commands.RunSummary {
+		bytes, err := ioutil.ReadFile(summaryFile)
+		Expect(err).ShouldNot(HaveOccurred())
+		summary := commands.RunSummary{}
+		Expect(json.Unmarshal(bytes, &summary)).Should(Succeed())
+		return summary
+	}
+
+	It("writes changes and warnings for a successful run", func() {
+		err := commands.ExecuteWithSummary("create-orgs", &fakeCommand{}, nil, summaryFile)
+		Expect(err).ShouldNot(HaveOccurred())
+		summary := readSummary()
+		Expect(summary.Command).Should(Equal("create-orgs"))
+		Expect(summary.Status).Should(Equal("succeeded"))
+		Expect(summary.Changes).Should(ConsistOf("[dry-run]: create org org1"))
+		Expect(summary.Warnings).Should(ConsistOf("skipping user"))
+		Expect(summary.Errors).Should(BeEmpty())
+		Expect(summary.Stats.Calls).Should(Equal(map[string]int{stats.CloudController: 1}))
+	})
+
+	It("records the error for a failed run", func() {
+		err := commands.ExecuteWithSummary("create-orgs", &fakeCommand{err: errors.New("boom")}, nil, summaryFile)
+		Expect(err).Should(MatchError("boom"))
+		summary := readSummary()
+		Expect(summary.Status).Should(Equal("failed"))
+		Expect(summary.Errors).Should(ConsistOf("boom"))
+	})
+
+	Context("with command hooks", func() {
+		var command *fakeSummaryCFCommand
+
+		BeforeEach(func() {
+			command = &fakeSummaryCFCommand{}
+			command.ConfigDirectory = dir
+		})
+
+		writeHooks := func(hooks string) {
+			Expect(ioutil.WriteFile(filepath.Join(dir, "cf-mgmt.yml"), []byte("command-hooks:\n"+hooks), 0644)).Should(Succeed())
+		}
+
+		It("runs the hooks of the command before and after it, passing the summary", func() {
+			writeHooks(`- name: before
+  when: before
+  run: cat > before.json
+- name: after
+  commands: [create-orgs]
+  when: after
+  run: cat > after.json
+- name: other-command
+  commands: [apply]
+  when: after
+  run: touch apply
+`)
+			Expect(commands.HasCommandHooks("create-orgs", command)).Should(BeTrue())
+			Expect(commands.ExecuteWithSummary("create-orgs", command, nil, summaryFile)).Should(Succeed())
+			Expect(command.executed).Should(BeTrue())
+			payload := struct {
+				When    string              `json:"when"`
+				Summary commands.RunSummary `json:"summary"`
+			}{}
+			bytes, err := ioutil.ReadFile(filepath.Join(dir, "before.json"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(json.Unmarshal(bytes, &payload)).Should(Succeed())
+			Expect(payload.When).Should(Equal("before"))
+			Expect(payload.Summary.Status).Should(BeEmpty())
+			bytes, err = ioutil.ReadFile(filepath.Join(dir, "after.json"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(json.Unmarshal(bytes, &payload)).Should(Succeed())
+			Expect(payload.Summary.Status).Should(Equal("succeeded"))
+			Expect(payload.Summary.Changes).Should(ConsistOf("[dry-run]: create org org1"))
+			Expect(filepath.Join(dir, "apply")).ShouldNot(BeAnExistingFile())
+		})
+
+		It("does not run the command once a before hook that sets fail-command fails", func() {
+			writeHooks(`- name: gate
+  when: before
+  run: exit 1
+  fail-command: true
+`)
+			err := commands.ExecuteWithSummary("create-orgs", command, nil, summaryFile)
+			Expect(err).Should(MatchError("before hook [gate] of create-orgs failed: exit status 1"))
+			Expect(command.executed).Should(BeFalse())
+			Expect(readSummary().Status).Should(Equal("failed"))
+		})
+
+		It("fails the run once an after hook that sets fail-command fails", func() {
+			writeHooks(`- name: smoke-tests
+  when: after
+  run: exit 1
+  fail-command: true
+`)
+			err := commands.ExecuteWithSummary("create-orgs", command, nil, summaryFile)
+			Expect(err).Should(MatchError("after hook [smoke-tests] of create-orgs failed: exit status 1"))
+			Expect(command.executed).Should(BeTrue())
+			Expect(readSummary().Errors).Should(ConsistOf("after hook [smoke-tests] of create-orgs failed: exit status 1"))
+		})
+	})
+})
+
+var _ = Describe("ExecuteWithStats", func() {
+	It("prints the statistics of a run without a summary", func() {
+		stdout := os.Stdout
+		r, w, err := os.Pipe()
+		Expect(err).ShouldNot(HaveOccurred())
+		os.Stdout = w
+		err = commands.ExecuteWithStats(&fakeCommand{err: errors.New("boom")}, nil)
+		os.Stdout = stdout
+		w.Close()
+		Expect(err).Should(MatchError("boom"))
+		out, err := ioutil.ReadAll(r)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(out)).Should(ContainSubstring("********* Statistics"))
+		Expect(string(out)).Should(ContainSubstring("cc     1 calls"))
+	})
+})
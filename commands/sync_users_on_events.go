@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/eventsync"
+	"github.com/xchapter7x/lo"
+)
+
+type SyncUsersOnEventsCommand struct {
+	BaseCFConfigCommand
+	BaseLDAPCommand
+	BaseLockCommand
+	EventsSource      string        `long:"events-source" env:"EVENTS_SOURCE" required:"true" description:"Url of the user and group events of the identity provider or HR system, such as nats://nats.example.com:4222/idp.users or kafka+https://kafka-rest.example.com/topics/hr-events"`
+	ConsumerGroup     string        `long:"consumer-group" env:"CONSUMER_GROUP" default:"cf-mgmt" description:"Nats queue group or kafka consumer group the events are received as, so that one of several listeners receives each event"`
+	BatchWindow       time.Duration `long:"batch-window" env:"BATCH_WINDOW" default:"1m" description:"Time events are collected before the users of the orgs they affect are synced, such as 1m"`
+	SkipSSLValidation bool          `long:"skip-ssl-validation" env:"SKIP_SSL_VALIDATION" description:"Skip verifying the certificates of the events source"`
+}
+
+//Execute - syncs the users of the orgs affected by each user or group event until interrupted
+func (c *SyncUsersOnEventsCommand) Execute([]string) error {
+	stop, abort, release := InterruptContexts()
+	defer release()
+
+	source, err := eventsync.NewSource(c.EventsSource, c.ConsumerGroup, c.SkipSSLValidation)
+	if err != nil {
+		return err
+	}
+	// the managers looking up the org users of the events peek, so they change nothing
+	cfMgmt, err := InitializeManagersWithContext(abort, c.BaseCFConfigCommand, true)
+	if err != nil {
+		return err
+	}
+	listener := &eventsync.Listener{
+		Source: source,
+		Window: c.BatchWindow,
+		Affected: func(event eventsync.UserEvent) ([]string, error) {
+			return c.affectedOrgs(cfMgmt, event)
+		},
+		Sync: func(orgs []string) error {
+			return c.sync(abort, orgs)
+		},
+	}
+	fmt.Printf("********* Listening for user and group events, syncing every %s\n", c.BatchWindow)
+	return listener.Run(stop)
+}
+
+// locksEachRun takes the lock for each sync, so that other runs can take it
+// while no events are waiting
+func (c *SyncUsersOnEventsCommand) locksEachRun() {}
+
+// affectedOrgs are the configured orgs the event changes, including those a
+// user who left holds an org role in without being configured. Only the
+// configured orgs that do not name the user are looked up.
+func (c *SyncUsersOnEventsCommand) affectedOrgs(cfMgmt *CFMgmt, event eventsync.UserEvent) ([]string, error) {
+	configManager := config.NewManager(c.ConfigDirectory)
+	affected, err := eventsync.AffectedOrgs(configManager, event, nil)
+	if err != nil || event.User == "" {
+		return affected, err
+	}
+	orgConfigs, err := configManager.GetOrgConfigs()
+	if err != nil {
+		return nil, err
+	}
+	holding := affected
+	for _, orgConfig := range orgConfigs {
+		if containsOrg(affected, orgConfig.Org) {
+			continue
+		}
+		org, err := cfMgmt.OrgManager.FindOrg(orgConfig.Org)
+		if err != nil {
+			// an org that is not created yet has no users
+			lo.G.Debugf("Not looking up the users of org %s: %s", orgConfig.Org, err)
+			continue
+		}
+		users, err := cfMgmt.Client.ListOrgUsers(org.Guid)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			if strings.EqualFold(user.Username, event.User) {
+				holding = append(holding, org.Name)
+				break
+			}
+		}
+	}
+	return eventsync.AffectedOrgs(configManager, event, holding)
+}
+
+func containsOrg(orgs []string, orgName string) bool {
+	for _, org := range orgs {
+		if strings.EqualFold(org, orgName) {
+			return true
+		}
+	}
+	return false
+}
+
+// sync updates the org and space users of the orgs and removes the org users
+// left without a role
+func (c *SyncUsersOnEventsCommand) sync(ctx context.Context, orgs []string) error {
+	fmt.Printf("********* Syncing users of %s\n", strings.Join(orgs, ", "))
+	baseCommand := c.BaseCFConfigCommand
+	baseCommand.changedOrgs = orgs
+	cfMgmt, err := InitializeManagersWithContext(ctx, baseCommand, false)
+	if err != nil {
+		return err
+	}
+	releaseLock, err := AcquireLock(baseCommand, c.BaseLockCommand, false)
+	if err != nil {
+		return err
+	}
+	defer releaseLock()
+	if err := cfMgmt.UserManager.InitializeLdap(c.LdapPassword); err != nil {
+		return err
+	}
+	defer cfMgmt.UserManager.DeinitializeLdap()
+	if err := cfMgmt.UserManager.UpdateOrgUsers(); err != nil {
+		return err
+	}
+	if err := cfMgmt.UserManager.UpdateSpaceUsers(); err != nil {
+		return err
+	}
+	if err := cfMgmt.UserManager.CleanupOrgUsers(); err != nil {
+		return err
+	}
+	lo.G.Noticef("Synced the users of %d orgs", len(orgs))
+	return nil
+}
@@ -0,0 +1,190 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/pivotalservices/cf-mgmt/redact"
+	"github.com/pivotalservices/cf-mgmt/target"
+	"github.com/xchapter7x/lo"
+)
+
+type TargetCommand struct {
+	Add  TargetAddCommand  `command:"add" description:"adds or replaces a named target: target add <name>"`
+	List TargetListCommand `command:"list" description:"lists the targets, marking the one in use"`
+	Use  TargetUseCommand  `command:"use" description:"runs the commands against a named target: target use <name>"`
+}
+
+type TargetAddCommand struct {
+	SystemDomain    string `long:"system-domain" description:"system domain" required:"true"`
+	UserID          string `long:"user-id" description:"user id cf-mgmt runs as" required:"true"`
+	ConfigDirectory string `long:"config-dir" description:"Name of the config directory of the foundation"`
+	ClientSecret    string `long:"client-secret" description:"Secret of the user id, env:NAME or file:PATH unless the targets file is encrypted"`
+	Password        string `long:"password" description:"Password of the user id, env:NAME or file:PATH unless the targets file is encrypted"`
+	LdapPassword    string `long:"ldap-password" description:"LDAP password, env:NAME or file:PATH unless the targets file is encrypted"`
+}
+
+//Execute - adds the named target to the targets file
+func (c *TargetAddCommand) Execute(args []string) error {
+	name, err := targetName(args)
+	if err != nil {
+		return err
+	}
+	file, key := target.DefaultFile(), os.Getenv(target.KeyEnv)
+	targets, err := target.Load(file, key)
+	if err != nil {
+		return err
+	}
+	if err := targets.Add(target.Target{
+		Name:            name,
+		SystemDomain:    c.SystemDomain,
+		UserID:          c.UserID,
+		ConfigDirectory: c.ConfigDirectory,
+		ClientSecret:    c.ClientSecret,
+		Password:        c.Password,
+		LdapPassword:    c.LdapPassword,
+	}); err != nil {
+		return err
+	}
+	if targets.Current == "" {
+		targets.Current = name
+	}
+	if err := targets.Save(file, key); err != nil {
+		return err
+	}
+	lo.G.Infof("Added target %s to %s", name, file)
+	return nil
+}
+
+type TargetListCommand struct{}
+
+//Execute - lists the targets of the targets file
+func (c *TargetListCommand) Execute([]string) error {
+	targets, err := target.Load(target.DefaultFile(), os.Getenv(target.KeyEnv))
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tNAME\tSYSTEM DOMAIN\tUSER ID\tCONFIG DIR")
+	for _, t := range targets.Targets {
+		current := ""
+		if t.Name == targets.Current {
+			current = "*"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", current, t.Name, t.SystemDomain, t.UserID, t.ConfigDirectory)
+	}
+	return w.Flush()
+}
+
+type TargetUseCommand struct{}
+
+//Execute - makes the named target the one commands run against
+func (c *TargetUseCommand) Execute(args []string) error {
+	name, err := targetName(args)
+	if err != nil {
+		return err
+	}
+	file, key := target.DefaultFile(), os.Getenv(target.KeyEnv)
+	targets, err := target.Load(file, key)
+	if err != nil {
+		return err
+	}
+	if err := targets.Use(name); err != nil {
+		return err
+	}
+	if err := targets.Save(file, key); err != nil {
+		return err
+	}
+	lo.G.Infof("Using target %s", name)
+	return nil
+}
+
+func targetName(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("expected the name of the target")
+	}
+	return args[0], nil
+}
+
+type cfTargetCommand interface {
+	useCFTarget(t *target.Target) error
+}
+
+type ldapTargetCommand interface {
+	useLDAPTarget(t *target.Target) error
+}
+
+// useCFTarget fills the connection details not set by flags or the environment
+// from the target, the config directory only when it is the default
+func (c *BaseCFConfigCommand) useCFTarget(t *target.Target) error {
+	if c.SystemDomain == "" {
+		c.SystemDomain = t.SystemDomain
+	}
+	if c.UserID == "" {
+		c.UserID = t.UserID
+	}
+	if c.ConfigDirectory == "config" && t.ConfigDirectory != "" {
+		c.ConfigDirectory = t.ConfigDirectory
+	}
+	var err error
+	if c.ClientSecret == "" && t.ClientSecret != "" {
+		if c.ClientSecret, err = target.Resolve(t.ClientSecret); err != nil {
+			return fmt.Errorf("unable to read the client secret of target %s: %v", t.Name, err)
+		}
+	}
+	if c.Password == "" && t.Password != "" {
+		if c.Password, err = target.Resolve(t.Password); err != nil {
+			return fmt.Errorf("unable to read the password of target %s: %v", t.Name, err)
+		}
+	}
+	redact.Secrets(c.ClientSecret, c.Password)
+	return nil
+}
+
+func (c *BaseLDAPCommand) useLDAPTarget(t *target.Target) error {
+	if c.LdapPassword != "" || t.LdapPassword == "" {
+		return nil
+	}
+	var err error
+	if c.LdapPassword, err = target.Resolve(t.LdapPassword); err != nil {
+		return fmt.Errorf("unable to read the ldap password of target %s: %v", t.Name, err)
+	}
+	redact.Secrets(c.LdapPassword)
+	return nil
+}
+
+//UseTarget - fills the connection details of the command from the named target, or from the target in
+//use when name is empty, leaving the values set by flags or the environment
+func UseTarget(command flags.Commander, name string) error {
+	cfCommand, isCF := command.(cfTargetCommand)
+	ldapCommand, isLDAP := command.(ldapTargetCommand)
+	if !isCF && !isLDAP {
+		return nil
+	}
+	targets, err := target.Load(target.DefaultFile(), os.Getenv(target.KeyEnv))
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name = targets.Current
+	}
+	if name == "" {
+		return nil
+	}
+	t := targets.Get(name)
+	if t == nil {
+		return fmt.Errorf("target %s is not in the targets file", name)
+	}
+	lo.G.Debugf("Running against target %s", name)
+	if isCF {
+		if err := cfCommand.useCFTarget(t); err != nil {
+			return err
+		}
+	}
+	if isLDAP {
+		return ldapCommand.useLDAPTarget(t)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package commands_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/commands"
+	"github.com/pivotalservices/cf-mgmt/target"
+)
+
+var _ = Describe("UseTarget", func() {
+	var (
+		dir     string
+		command *fakeCFCommand
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "cf-mgmt-targets")
+		Expect(err).ShouldNot(HaveOccurred())
+		os.Setenv("CF_MGMT_TARGETS", filepath.Join(dir, "targets.yml"))
+		os.Setenv("PROD_CLIENT_SECRET", "prod-secret")
+		targets, err := target.Load(target.DefaultFile(), "")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(targets.Add(target.Target{Name: "prod", SystemDomain: "sys.prod.example.com", UserID: "cf-mgmt", ConfigDirectory: "prod-config", ClientSecret: "env:PROD_CLIENT_SECRET"})).Should(Succeed())
+		Expect(targets.Add(target.Target{Name: "dev", SystemDomain: "sys.dev.example.com", UserID: "cf-mgmt-dev"})).Should(Succeed())
+		Expect(targets.Use("prod")).Should(Succeed())
+		Expect(targets.Save(target.DefaultFile(), "")).Should(Succeed())
+		command = &fakeCFCommand{}
+		command.ConfigDirectory = "config"
+	})
+
+	AfterEach(func() {
+		os.Unsetenv("CF_MGMT_TARGETS")
+		os.Unsetenv("PROD_CLIENT_SECRET")
+		os.RemoveAll(dir)
+	})
+
+	It("fills the connection details from the target in use", func() {
+		Expect(commands.UseTarget(command, "")).Should(Succeed())
+		Expect(command.SystemDomain).Should(Equal("sys.prod.example.com"))
+		Expect(command.UserID).Should(Equal("cf-mgmt"))
+		Expect(command.ConfigDirectory).Should(Equal("prod-config"))
+		Expect(command.ClientSecret).Should(Equal("prod-secret"))
+	})
+
+	It("keeps the values set by flags", func() {
+		command.SystemDomain = "sys.other.example.com"
+		command.ConfigDirectory = "other-config"
+		Expect(commands.UseTarget(command, "")).Should(Succeed())
+		Expect(command.SystemDomain).Should(Equal("sys.other.example.com"))
+		Expect(command.ConfigDirectory).Should(Equal("other-config"))
+		Expect(command.UserID).Should(Equal("cf-mgmt"))
+	})
+
+	It("uses the named target", func() {
+		Expect(commands.UseTarget(command, "dev")).Should(Succeed())
+		Expect(command.SystemDomain).Should(Equal("sys.dev.example.com"))
+		Expect(command.ClientSecret).Should(BeEmpty())
+	})
+
+	It("errors for a target that does not exist", func() {
+		Expect(commands.UseTarget(command, "qa")).Should(MatchError("target qa is not in the targets file"))
+	})
+})
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pivotalservices/cf-mgmt/app"
+)
+
+type TaskReportCommand struct {
+	BaseCFConfigCommand
+	Days   int    `long:"days" description:"Number of days, up to now, to summarize the tasks of" default:"30"`
+	Format string `long:"format" description:"Output format of the report" default:"table" choice:"table" choice:"csv" choice:"json"`
+}
+
+//Execute - reports the task usage of each org and space of the managed orgs over the last days
+func (c *TaskReportCommand) Execute([]string) error {
+	if c.Days < 1 {
+		return fmt.Errorf("--days must be at least 1, not %d", c.Days)
+	}
+	cfMgmt, err := InitializeManagers(c.BaseCFConfigCommand)
+	if err != nil {
+		return err
+	}
+	until := time.Now().UTC()
+	usage, err := cfMgmt.AppManager.TaskUsage(until.AddDate(0, 0, -c.Days), until)
+	if err != nil {
+		return err
+	}
+	switch c.Format {
+	case "csv":
+		return writeTaskUsageCSV(os.Stdout, usage)
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(usage)
+	}
+	return writeTaskUsageTable(os.Stdout, usage)
+}
+
+func writeTaskUsageTable(out io.Writer, usage []app.TaskUsage) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ORG\tSPACE\tTASKS\tSUCCEEDED\tFAILED\tRUNNING\tMAX CONCURRENT\tMEMORY (MB-SECONDS)")
+	for _, u := range usage {
+		space := u.Space
+		if space == "" {
+			space = "(all)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t%d\n", u.Org, space, u.Tasks, u.Succeeded, u.Failed, u.Running, u.MaxConcurrent, u.MemoryMBSeconds)
+	}
+	return w.Flush()
+}
+
+func writeTaskUsageCSV(out io.Writer, usage []app.TaskUsage) error {
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"org", "space", "tasks", "succeeded", "failed", "running", "max_concurrent", "memory_mb_seconds"}); err != nil {
+		return err
+	}
+	for _, u := range usage {
+		if err := w.Write([]string{u.Org, u.Space, strconv.Itoa(u.Tasks), strconv.Itoa(u.Succeeded), strconv.Itoa(u.Failed),
+			strconv.Itoa(u.Running), strconv.Itoa(u.MaxConcurrent), strconv.FormatInt(u.MemoryMBSeconds, 10)}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"time"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/configcommands"
+	"github.com/pivotalservices/cf-mgmt/telemetry"
+	"github.com/xchapter7x/lo"
+)
+
+// sendTelemetry reports the run when the operator opted in with --telemetry,
+// failing to report never fails the run
+func sendTelemetry(command flags.Commander, summary *RunSummary, err error) {
+	if CfMgmt.TelemetryEndpoint == "" {
+		lo.G.Warning("--telemetry requires --telemetry-endpoint, not reporting usage")
+		return
+	}
+	report := telemetry.NewReport(summary.Command, configcommands.VERSION,
+		time.Duration(summary.DurationSeconds*float64(time.Second)), len(summary.Changes), err)
+	if cfCommand, ok := unwrapCommand(command).(cfConfigCommand); ok {
+		report.AddFeatures(config.NewManager(cfCommand.cfConfig().ConfigDirectory))
+	}
+	if sendErr := telemetry.Send(CfMgmt.TelemetryEndpoint, report, CfMgmt.TelemetrySkipSSLValidation); sendErr != nil {
+		lo.G.Debugf("Unable to report usage: %s", sendErr)
+	}
+}
@@ -0,0 +1,16 @@
+package commands
+
+type UpdateIdentityProvidersCommand struct {
+	BaseCFConfigCommand
+	BasePeekCommand
+	BaseLockCommand
+}
+
+//Execute - creates, updates and optionally deletes the saml and oidc identity providers of identity-providers.yml
+func (c *UpdateIdentityProvidersCommand) Execute([]string) error {
+	cfMgmt, err := InitializePeekManagers(c.BaseCFConfigCommand, c.Peek)
+	if err != nil {
+		return err
+	}
+	return cfMgmt.IdentityProviderManager.UpdateIdentityProviders()
+}
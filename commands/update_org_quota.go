@@ -3,6 +3,7 @@ package commands
 type UpdateOrgQuotasCommand struct {
 	BaseCFConfigCommand
 	BasePeekCommand
+	BaseLockCommand
 }
 
 //Execute - updates orgs quotas
@@ -4,6 +4,7 @@ type UpdateOrgUsersCommand struct {
 	BaseCFConfigCommand
 	BaseLDAPCommand
 	BasePeekCommand
+	BaseLockCommand
 }
 
 //Execute - updates orgs quotas
@@ -0,0 +1,16 @@
+package commands
+
+type UpdateRoleGroupsCommand struct {
+	BaseCFConfigCommand
+	BasePeekCommand
+	BaseLockCommand
+}
+
+//Execute - syncs the uaa groups in role-groups of cf-mgmt.yml with the org and space roles
+func (c *UpdateRoleGroupsCommand) Execute([]string) error {
+	cfMgmt, err := InitializePeekManagers(c.BaseCFConfigCommand, c.Peek)
+	if err != nil {
+		return err
+	}
+	return cfMgmt.UserManager.UpdateRoleGroups()
+}
@@ -3,6 +3,7 @@ package commands
 type UpdateSpaceQuotasCommand struct {
 	BaseCFConfigCommand
 	BasePeekCommand
+	BaseLockCommand
 }
 
 //Execute - updates space quotas
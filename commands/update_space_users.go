@@ -4,6 +4,7 @@ type UpdateSpaceUsersCommand struct {
 	BaseCFConfigCommand
 	BaseLDAPCommand
 	BasePeekCommand
+	BaseLockCommand
 }
 
 //Execute - updates space users
@@ -3,6 +3,7 @@ package commands
 type UpdateSpacesCommand struct {
 	BaseCFConfigCommand
 	BasePeekCommand
+	BaseLockCommand
 }
 
 //Execute - updates spaces
@@ -0,0 +1,16 @@
+package commands
+
+type UpdateTokenPolicyCommand struct {
+	BaseCFConfigCommand
+	BasePeekCommand
+	BaseLockCommand
+}
+
+//Execute - updates the token policy of the default uaa identity zone to token-policy of cf-mgmt.yml
+func (c *UpdateTokenPolicyCommand) Execute([]string) error {
+	cfMgmt, err := InitializePeekManagers(c.BaseCFConfigCommand, c.Peek)
+	if err != nil {
+		return err
+	}
+	return cfMgmt.TokenPolicyManager.UpdateTokenPolicy()
+}
@@ -0,0 +1,22 @@
+package commands
+
+type UpdateUsersCommand struct {
+	BaseCFConfigCommand
+	BaseLDAPCommand
+	BasePeekCommand
+	BaseLockCommand
+	Group string `long:"group" env:"LDAP_GROUP" required:"true" description:"Ldap group whose org and space roles are synced, such as after a known change of its members"`
+}
+
+//Execute - syncs the org and space roles granted to an ldap group
+func (c *UpdateUsersCommand) Execute([]string) error {
+	cfMgmt, err := InitializePeekManagers(c.BaseCFConfigCommand, c.Peek)
+	if err != nil {
+		return err
+	}
+	if err := cfMgmt.UserManager.InitializeLdap(c.LdapPassword); err != nil {
+		return err
+	}
+	defer cfMgmt.UserManager.DeinitializeLdap()
+	return cfMgmt.UserManager.UpdateGroupUsers(c.Group)
+}
@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/redact"
+	"github.com/pivotalservices/cf-mgmt/simulator"
+)
+
+type ValidateConfigCommand struct {
+	BaseConfigCommand
+	FromSnapshot string `long:"from-snapshot" env:"FROM_SNAPSHOT" description:"Snapshot of the foundation, written by export-snapshot, to apply the configuration to in memory, failing on destructive changes approvals.yml does not approve"`
+
+	FailOnQuotaOvercommit bool `long:"fail-on-quota-overcommit" env:"FAIL_ON_QUOTA_OVERCOMMIT" description:"Fail, rather than warn, when the space quotas of an org add up to more than its org quota"`
+}
+
+//Execute - reads every part of the configuration without contacting a foundation, failing on the first error,
+//warns when the space quotas of an org add up to more than its org quota, failing instead with
+//--fail-on-quota-overcommit, and, with --from-snapshot, checks that the configuration applies to the snapshot
+//with every destructive change approved
+func (c *ValidateConfigCommand) Execute([]string) error {
+	reader := config.NewManager(c.ConfigDirectory)
+	checks := []struct {
+		name string
+		read func() error
+	}{
+		{"orgs.yml", func() error { _, err := reader.Orgs(); return err }},
+		{"cf-mgmt.yml", func() error { _, err := reader.GetGlobalConfig(); return err }},
+		// a placeholder bind password, as the password is not needed to read ldap.yml
+		{"ldap.yml", func() error { _, err := reader.LdapConfig("unused"); return err }},
+		{"org groups", func() error { _, err := reader.GetOrgGroups(); return err }},
+		{"org configs", func() error { _, err := reader.GetOrgConfigs(); return err }},
+		{"spaces.yml", func() error { _, err := reader.Spaces(); return err }},
+		{"space configs", func() error { _, err := reader.GetSpaceConfigs(); return err }},
+		{"security groups", func() error { _, err := reader.GetASGConfigs(); return err }},
+		{"default security groups", func() error { _, err := reader.GetDefaultASGConfigs(); return err }},
+		{"approvals", func() error { _, err := reader.GetApprovals(); return err }},
+		{"origin migration", func() error { _, err := reader.GetOriginMigration(); return err }},
+		{"identity providers", func() error { _, err := reader.GetIdentityProviders(); return err }},
+	}
+	for _, check := range checks {
+		if err := check.read(); err != nil {
+			return fmt.Errorf("invalid %s in %s: %s", check.name, c.ConfigDirectory, err)
+		}
+	}
+	if err := checkSpaceQuotaOvercommits(reader, c.FailOnQuotaOvercommit); err != nil {
+		return fmt.Errorf("invalid quotas in %s: %s", c.ConfigDirectory, err)
+	}
+	if c.FromSnapshot != "" {
+		if err := c.applyToSnapshot(); err != nil {
+			return fmt.Errorf("configuration in %s does not apply to snapshot %s: %s", c.ConfigDirectory, c.FromSnapshot, err)
+		}
+	}
+	fmt.Printf("Configuration in %s is valid\n", c.ConfigDirectory)
+	return nil
+}
+
+// applyToSnapshot applies the configuration to an in-memory copy of the
+// snapshot, where the managers check the orgs and spaces they delete, the
+// users they remove and the quotas they shrink against approvals.yml as they
+// do against a foundation, failing with the report of the steps that failed
+func (c *ValidateConfigCommand) applyToSnapshot() error {
+	snapshot, err := simulator.LoadSnapshot(c.FromSnapshot)
+	if err != nil {
+		return err
+	}
+	_, report, err := simulatePlan(BaseCFConfigCommand{BaseConfigCommand: c.BaseConfigCommand}, snapshot, "", true)
+	if err != nil && report != nil {
+		fmt.Print(redact.String(report.String()))
+	}
+	return err
+}
+
+// checkSpaceQuotaOvercommits warns, or fails when fail is set, when the
+// configured space quotas of an org add up to more than its configured org
+// quota, beyond the space-quota-tolerance of cf-mgmt.yml
+func checkSpaceQuotaOvercommits(reader config.Reader, fail bool) error {
+	globalConfig, err := reader.GetGlobalConfig()
+	if err != nil {
+		return err
+	}
+	orgConfigs, err := reader.GetOrgConfigs()
+	if err != nil {
+		return err
+	}
+	spaceConfigs, err := reader.GetSpaceConfigs()
+	if err != nil {
+		return err
+	}
+	overcommits := config.SpaceQuotaOvercommits(orgConfigs, spaceConfigs, globalConfig.SpaceQuotaTolerance)
+	if len(overcommits) == 0 {
+		return nil
+	}
+	orgs := []string{}
+	for _, overcommit := range overcommits {
+		if fail {
+			fmt.Println(overcommit)
+		} else {
+			fmt.Printf("WARNING: %s\n", overcommit)
+		}
+		if len(orgs) == 0 || orgs[len(orgs)-1] != overcommit.Org {
+			orgs = append(orgs, overcommit.Org)
+		}
+	}
+	if !fail {
+		return nil
+	}
+	return fmt.Errorf("space quotas overcommit the org quota of %s", strings.Join(orgs, ", "))
+}
@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/redact"
+	"github.com/pivotalservices/cf-mgmt/verify"
+)
+
+type VerifyCommand struct {
+	BaseCFConfigCommand
+	Checks string `long:"checks" env:"VERIFY_CHECKS" description:"Yaml file of the checks to run, defaults to verify.yml in the config directory"`
+}
+
+//Execute - spot-checks user access and ssh settings of the foundation after an apply
+func (c *VerifyCommand) Execute([]string) error {
+	checksFile := c.Checks
+	if checksFile == "" {
+		checksFile = path.Join(config.BaseDirectory(c.ConfigDirectory), "verify.yml")
+	}
+	checks, err := verify.LoadChecks(checksFile)
+	if err != nil {
+		return err
+	}
+	cfMgmt, err := InitializeManagers(c.BaseCFConfigCommand)
+	if err != nil {
+		return err
+	}
+	verifier := &verify.Verifier{
+		OrgMgr:   cfMgmt.OrgManager,
+		SpaceMgr: cfMgmt.SpaceManager,
+		UserMgr:  cfMgmt.UserManager,
+	}
+	results := verifier.Run(checks)
+	fmt.Println("********* Verify")
+	fmt.Print(redact.String(results.String()))
+	return results.Error()
+}
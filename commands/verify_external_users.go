@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pivotalservices/cf-mgmt/user"
+)
+
+type VerifyExternalUsersCommand struct {
+	BaseCFConfigCommand
+	BaseLDAPCommand
+}
+
+//Execute - reports saml and ldap users of the configuration that cannot log in with the configured origin
+func (c *VerifyExternalUsersCommand) Execute([]string) error {
+	cfMgmt, err := InitializeManagers(c.BaseCFConfigCommand)
+	if err != nil {
+		return err
+	}
+	if err := cfMgmt.UserManager.InitializeLdap(c.LdapPassword); err != nil {
+		return err
+	}
+	defer cfMgmt.UserManager.DeinitializeLdap()
+	problems, err := cfMgmt.UserManager.ListExternalUserProblems()
+	if err != nil {
+		return err
+	}
+	if err := writeExternalUserProblems(os.Stdout, problems); err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d external user problems found", len(problems))
+	}
+	return nil
+}
+
+func writeExternalUserProblems(out io.Writer, problems []user.ExternalUserProblem) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "USER\tORIGIN\tPROBLEM")
+	for _, p := range problems {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", p.UserName, p.Origin, p.Problem)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,152 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pivotalservices/cf-mgmt/cfmgmt"
+	"github.com/pivotalservices/cf-mgmt/lock"
+	"github.com/pivotalservices/cf-mgmt/redact"
+	"github.com/pivotalservices/cf-mgmt/watch"
+	"github.com/xchapter7x/lo"
+)
+
+type WatchCommand struct {
+	BaseCFConfigCommand
+	BaseLDAPCommand
+	BaseLockCommand
+	Interval      time.Duration `long:"interval" env:"WATCH_INTERVAL" default:"30m" description:"Time between reconciliations, such as 30m or 1h"`
+	MaxFailures   int           `long:"max-failures" env:"MAX_FAILURES" default:"1" description:"Number of failed steps after which an apply stops and skips the remaining steps, until then a failed step is reported and the next steps still run"`
+	HealthAddress string        `long:"health-address" env:"HEALTH_ADDRESS" description:"Address, such as :8080, to serve /healthz, /readyz and /status on"`
+	SkipPreflight bool          `long:"skip-preflight" env:"SKIP_PREFLIGHT" description:"Do not verify the credentials, uaa scopes and ldap bind before watching"`
+	LeaderElect   bool          `long:"leader-election" env:"LEADER_ELECTION" description:"Only reconcile while holding the leader lease, so that one of several replicas applies at a time"`
+	LeaseDuration time.Duration `long:"lease-duration" env:"LEASE_DURATION" description:"Time after which the leader lease of a replica that stopped renewing it expires, twice the interval when not set"`
+}
+
+//Execute - detects drift and applies the configuration every interval until interrupted
+func (c *WatchCommand) Execute([]string) error {
+	stop, abort, release := InterruptContexts()
+	defer release()
+
+	if !c.SkipPreflight && c.Simulate == "" && c.Replay == "" {
+		if err := RunPreflight(c.BaseCFConfigCommand, c.LdapPassword); err != nil {
+			return err
+		}
+		c.scopesVerified = true
+	}
+	controller := &watch.Controller{
+		Interval: c.Interval,
+		DetectDrift: func(context.Context) (int, error) {
+			return c.detectDrift(abort)
+		},
+		Apply: func(ctx context.Context) error {
+			return c.apply(ctx, abort)
+		},
+	}
+	if c.LeaderElect && c.Simulate == "" && c.Replay == "" {
+		lease, err := c.lease()
+		if err != nil {
+			return err
+		}
+		controller.Lease = lease
+		controller.RenewInterval = lease.TTL / 3
+	}
+	if c.HealthAddress != "" {
+		server := &http.Server{Addr: c.HealthAddress, Handler: controller.Handler()}
+		go func() {
+			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				lo.G.Errorf("Unable to serve health endpoints on %s: %s", c.HealthAddress, err)
+			}
+		}()
+		defer server.Close()
+	}
+	fmt.Printf("********* Watching, reconciling every %s\n", c.Interval)
+	return controller.Run(stop)
+}
+
+// locksEachRun takes the lock for each apply, so that other runs can take it
+// between reconciliations
+func (c *WatchCommand) locksEachRun() {}
+
+// detectDrift counts the changes of the plan of the configuration against a
+// snapshot of the foundation, leaving out the protected orgs of orgs.yml
+func (c *WatchCommand) detectDrift(abort context.Context) (int, error) {
+	fmt.Println("********* Detecting Drift")
+	cfMgmt, err := InitializeManagersWithContext(abort, c.BaseCFConfigCommand, true)
+	if err != nil {
+		return 0, err
+	}
+	snapshot, err := exportSnapshot(c.ConfigDirectory, cfMgmt)
+	if err != nil {
+		return 0, err
+	}
+	changes, report, err := simulatePlan(c.BaseCFConfigCommand, snapshot, c.LdapPassword, false)
+	if report == nil {
+		return 0, err
+	}
+	if err != nil {
+		fmt.Println("********* Drift Report")
+		fmt.Print(redact.String(report.String()))
+		return 0, err
+	}
+	for _, change := range changes {
+		lo.G.Debugf("drift: %s", change)
+	}
+	return len(changes), nil
+}
+
+func (c *WatchCommand) lease() (*lock.DefaultManager, error) {
+	duration := c.LeaseDuration
+	if duration == 0 {
+		duration = 2 * c.Interval
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("lease duration must be positive, not %s", duration)
+	}
+	if err := c.ConfigureHTTP(); err != nil {
+		return nil, err
+	}
+	lease, err := lock.NewLeaseManager(c.SystemDomain, c.UserID, c.ClientSecret, c.LockHolder, duration)
+	if err != nil {
+		return nil, err
+	}
+	return lease.(*lock.DefaultManager), nil
+}
+
+func (c *WatchCommand) apply(ctx, abort context.Context) error {
+	requests, cancel := abortOnLeaseLoss(ctx, abort)
+	defer cancel()
+	cfMgmt, err := InitializeManagersWithContext(requests, c.BaseCFConfigCommand, false)
+	if err != nil {
+		return err
+	}
+	releaseLock, err := AcquireLock(c.BaseCFConfigCommand, c.BaseLockCommand, false)
+	if err != nil {
+		return err
+	}
+	defer releaseLock()
+	report, err := cfMgmt.ApplyWithFailureBudget(cfmgmt.WithRequestContext(ctx, requests), c.LdapPassword, c.MaxFailures)
+	if err != nil {
+		fmt.Println("********* Apply Report")
+		fmt.Print(redact.String(report.String()))
+	}
+	return err
+}
+
+// abortOnLeaseLoss is the context of the requests of an apply, cancelled when
+// the apply is aborted and when the replica loses the leader lease, so that
+// it stops before the replica that takes over starts applying
+func abortOnLeaseLoss(ctx, abort context.Context) (context.Context, context.CancelFunc) {
+	requests, cancel := context.WithCancel(abort)
+	go func() {
+		select {
+		case <-watch.LeaseLost(ctx):
+			lo.G.Warning("lost the lease, aborting the requests in flight")
+			cancel()
+		case <-requests.Done():
+		}
+	}()
+	return requests, cancel
+}
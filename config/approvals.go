@@ -0,0 +1,124 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xchapter7x/lo"
+)
+
+// Kinds of destructive changes that require an approval.
+const (
+	ChangeDeleteOrg   = "delete-org"
+	ChangeDeleteSpace = "delete-space"
+	ChangeRemoveUser  = "remove-user"
+	ChangeShrinkQuota = "shrink-quota"
+	// ChangeDeleteIdentityProvider deletes a uaa identity provider and, with
+	// it, the uaa users of its origin
+	ChangeDeleteIdentityProvider = "delete-identity-provider"
+)
+
+// Approvals lists the change management approvals of destructive changes,
+// read from approvals.yml.
+type Approvals struct {
+	// RequireApprovals fails destructive changes that have no approval
+	RequireApprovals bool       `yaml:"require-approvals"`
+	Approvals        []Approval `yaml:"approvals"`
+}
+
+// Approval records who approved a destructive change and in which ticket.
+// Space and user are optional, an approval without them covers the matching
+// changes in every space of the org or for every user. The deletion of an
+// identity provider names its origin instead of an org.
+type Approval struct {
+	Ticket   string `yaml:"ticket"`
+	Approver string `yaml:"approver"`
+	Change   string `yaml:"change"`
+	Org      string `yaml:"org,omitempty"`
+	Space    string `yaml:"space,omitempty"`
+	User     string `yaml:"user,omitempty"`
+	Origin   string `yaml:"origin,omitempty"`
+}
+
+// Change is a destructive change cf-mgmt is about to make.
+type Change struct {
+	Kind   string
+	Org    string
+	Space  string
+	User   string
+	Origin string
+}
+
+func (c Change) String() string {
+	target := c.Org
+	if c.Space != "" {
+		target = c.Org + "/" + c.Space
+	}
+	if c.Origin != "" {
+		target = c.Origin
+	}
+	if c.User != "" {
+		return fmt.Sprintf("%s [%s] in %s", c.Kind, c.User, target)
+	}
+	return fmt.Sprintf("%s %s", c.Kind, target)
+}
+
+func (a *Approval) matches(change Change) bool {
+	return a.Change == change.Kind &&
+		strings.EqualFold(a.Org, change.Org) &&
+		strings.EqualFold(a.Origin, change.Origin) &&
+		(a.Space == "" || strings.EqualFold(a.Space, change.Space)) &&
+		(a.User == "" || strings.EqualFold(a.User, change.User))
+}
+
+// Check returns an error listing the changes that have no approval when
+// approvals are required, so that none of them are made. Approved changes are
+// logged with their ticket so that the run records who approved them.
+func (a *Approvals) Check(changes ...Change) error {
+	if a == nil || !a.RequireApprovals {
+		return nil
+	}
+	var unapproved []string
+	approvals := make([]*Approval, len(changes))
+	for i, change := range changes {
+		if approvals[i] = a.find(change); approvals[i] == nil {
+			unapproved = append(unapproved, change.String())
+		}
+	}
+	if len(unapproved) > 0 {
+		return fmt.Errorf("approvals.yml has no approval for: %s", strings.Join(unapproved, ", "))
+	}
+	for i, change := range changes {
+		lo.G.Infof("%s approved by %s in %s", change, approvals[i].Approver, approvals[i].Ticket)
+	}
+	return nil
+}
+
+func (a *Approvals) find(change Change) *Approval {
+	for i := range a.Approvals {
+		if a.Approvals[i].matches(change) {
+			return &a.Approvals[i]
+		}
+	}
+	return nil
+}
+
+func (a *Approvals) validate() error {
+	for _, approval := range a.Approvals {
+		if approval.Change == ChangeDeleteIdentityProvider {
+			if approval.Ticket == "" || approval.Approver == "" || approval.Origin == "" {
+				return fmt.Errorf("approval %+v in approvals.yml must have a ticket, approver and origin", approval)
+			}
+			continue
+		}
+		if approval.Ticket == "" || approval.Approver == "" || approval.Org == "" {
+			return fmt.Errorf("approval %+v in approvals.yml must have a ticket, approver and org", approval)
+		}
+		switch approval.Change {
+		case ChangeDeleteOrg, ChangeDeleteSpace, ChangeRemoveUser, ChangeShrinkQuota:
+		default:
+			return fmt.Errorf("change [%s] of approval %s in approvals.yml must be %s, %s, %s, %s or %s", approval.Change, approval.Ticket, ChangeDeleteOrg, ChangeDeleteSpace, ChangeRemoveUser, ChangeShrinkQuota, ChangeDeleteIdentityProvider)
+		}
+	}
+	return nil
+}
@@ -4,4 +4,22 @@ package config
 type ASGConfig struct {
 	Rules string
 	Name  string
+	// Allow lists named endpoints (see GlobalConfig.ASGEndpoints) the rules
+	// are generated from at apply time instead of using Rules.
+	Allow []string
+}
+
+// ASGEndpoint describes a named egress destination whose hosts are resolved
+// to CIDRs when generating security group rules.
+type ASGEndpoint struct {
+	Name        string   `yaml:"name"`
+	Hosts       []string `yaml:"hosts"`
+	Protocol    string   `yaml:"protocol"`
+	Ports       string   `yaml:"ports,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+}
+
+// generatedASG is the yml form of an ASG whose rules come from named endpoints.
+type generatedASG struct {
+	Allow []string `yaml:"allow"`
 }
@@ -0,0 +1,64 @@
+package config
+
+import "fmt"
+
+// When a CommandHook runs
+const (
+	HookBefore = "before"
+	HookAfter  = "after"
+)
+
+// CommandHook is a shell command or webhook run before or after cf-mgmt
+// commands, such as to warm caches, notify a CMDB or trigger downstream smoke
+// tests. It is passed the run summary of the command as json.
+type CommandHook struct {
+	Name string `yaml:"name"`
+	// Commands are the commands the hook runs for, such as apply, every
+	// command when not set
+	Commands []string `yaml:"commands,omitempty"`
+	// When is before or after the command
+	When string `yaml:"when"`
+	// Run is a shell command, run with sh -c in the config directory, and URL
+	// a webhook the summary is posted to, a hook sets one of them
+	Run string `yaml:"run,omitempty"`
+	URL string `yaml:"url,omitempty"`
+	// TimeoutSeconds limits each run of the hook, 60 seconds when not set
+	TimeoutSeconds int `yaml:"timeout-seconds,omitempty"`
+	// FailCommand fails the command when the hook fails, rather than only
+	// logging the failure, and a failing before hook keeps it from running
+	FailCommand bool `yaml:"fail-command,omitempty"`
+	// SkipSSLValidation skips verifying the certificate of the webhook
+	SkipSSLValidation bool `yaml:"skip-ssl-validation,omitempty"`
+}
+
+// RunsFor returns whether the hook runs when for the command.
+func (h CommandHook) RunsFor(command, when string) bool {
+	if h.When != when {
+		return false
+	}
+	if len(h.Commands) == 0 {
+		return true
+	}
+	for _, hookCommand := range h.Commands {
+		if hookCommand == command {
+			return true
+		}
+	}
+	return false
+}
+
+func (h CommandHook) validate() error {
+	if h.Name == "" {
+		return fmt.Errorf("command-hooks require a name")
+	}
+	if h.When != HookBefore && h.When != HookAfter {
+		return fmt.Errorf("when of command hook [%s] must be %s or %s", h.Name, HookBefore, HookAfter)
+	}
+	if (h.Run == "") == (h.URL == "") {
+		return fmt.Errorf("command hook [%s] requires either run or url", h.Name)
+	}
+	if h.TimeoutSeconds < 0 {
+		return fmt.Errorf("timeout-seconds of command hook [%s] must be a positive number of seconds", h.Name)
+	}
+	return nil
+}
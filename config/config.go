@@ -7,7 +7,7 @@ var DefaultProtectedOrgs = []string{
 	"system",
 	"p-spring-cloud-services",
 	"splunk-nozzle-org",
-	"redis-test-ORG*",
+	"redis-test-ORG.*",
 	"appdynamics-org",
 }
 
@@ -36,6 +36,7 @@ type Updater interface {
 
 	SaveOrgs(*Orgs) error
 	SaveGlobalConfig(*GlobalConfig) error
+	SaveOrgGroups(*OrgGroups) error
 }
 
 // Reader is used to read the cf-mgmt configuration.
@@ -50,13 +51,24 @@ type Reader interface {
 	GetGlobalConfig() (*GlobalConfig, error)
 	GetSpaceDefaults() (*SpaceConfig, error)
 	GetOrgConfig(orgName string) (*OrgConfig, error)
+	GetRawOrgConfig(orgName string) (*OrgConfig, error)
 	GetSpaceConfig(orgName, spaceName string) (*SpaceConfig, error)
+	GetRawSpaceConfig(orgName, spaceName string) (*SpaceConfig, error)
 	LdapConfig(bindPassword string) (*LdapConfig, error)
+	GetOriginMigration() (*OriginMigration, error)
+	GetApprovals() (*Approvals, error)
+	GetIdentityProviders() (*IdentityProviders, error)
+	GetOrgGroups() (*OrgGroups, error)
+	GetOrgTemplates() (*OrgTemplates, error)
 }
 
 // NewManager creates a Manager that is backed by a set of YAML
-// files in the specified configuration directory.
+// files in the specified configuration directory, or by a single
+// consolidated YAML file when configDir names a .yml file.
 func NewManager(configDir string) Manager {
+	if IsConsolidated(configDir) {
+		return newConsolidatedManager(configDir)
+	}
 	return &yamlManager{
 		ConfigDir: configDir,
 	}
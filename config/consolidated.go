@@ -0,0 +1,471 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xchapter7x/lo"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// A consolidated config file holds the whole configuration in one yaml file,
+// for teams that prefer to review a single file. It mirrors the config
+// directory: each yml file is a key holding its contents, each json file,
+// such as an asg definition, a key holding its text, and each directory, such
+// as the one of an org or space, a key holding the files within it.
+//
+//   cf-mgmt.yml: {}
+//   orgs.yml:
+//     orgs: [test]
+//   asgs:
+//     all-access.json: '[{"protocol":"all","destination":"0.0.0.0-255.255.255.255"}]'
+//   test:
+//     orgConfig.yml:
+//       org: test
+//     spaces.yml:
+//       org: test
+//       spaces: [dev]
+//     dev:
+//       spaceConfig.yml:
+//         org: test
+//         space: dev
+
+//IsConsolidated - whether configDir names a consolidated config file instead of a directory
+func IsConsolidated(configDir string) bool {
+	ext := strings.ToLower(filepath.Ext(configDir))
+	return ext == ".yml" || ext == ".yaml"
+}
+
+//BaseDirectory - the directory the files kept alongside the configuration,
+//such as the run state and plugins, are relative to: the config directory, or
+//the directory of a consolidated config file
+func BaseDirectory(configDir string) string {
+	if IsConsolidated(configDir) {
+		return filepath.Dir(configDir)
+	}
+	return configDir
+}
+
+// consolidatedManager reads and writes a consolidated config file. Each
+// operation expands the file into a private working directory, read and
+// written by a yamlManager, that is removed once the operation is done, so
+// every operation sees the file as it is then and managers in the same or in
+// other processes do not share any state. Changes are written back to the
+// file.
+type consolidatedManager struct {
+	File string
+}
+
+func newConsolidatedManager(file string) *consolidatedManager {
+	return &consolidatedManager{File: file}
+}
+
+// read runs read with a yamlManager of a private working directory holding
+// the files of the consolidated config file, which does not exist when there
+// is no such file
+func (m *consolidatedManager) read(read func(dir *yamlManager) error) error {
+	tempDir, err := ioutil.TempDir("", "cf-mgmt-config-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+	dir := &yamlManager{ConfigDir: filepath.Join(tempDir, "config"), Consolidated: m.File}
+	if FileOrDirectoryExists(m.File) {
+		lo.G.Debugf("Expanding %s into %s", m.File, dir.ConfigDir)
+		if err := ExpandConsolidated(m.File, dir.ConfigDir); err != nil {
+			return err
+		}
+	}
+	return read(dir)
+}
+
+// update runs update like read, writing the working directory back to the
+// consolidated config file when update succeeds
+func (m *consolidatedManager) update(update func(dir *yamlManager) error) error {
+	return m.read(func(dir *yamlManager) error {
+		if err := update(dir); err != nil {
+			return err
+		}
+		if !FileOrDirectoryExists(dir.ConfigDir) {
+			return nil
+		}
+		return Consolidate(dir.ConfigDir, m.File)
+	})
+}
+
+//ExpandConsolidated - writes the files of the consolidated config file to configDir
+func ExpandConsolidated(file, configDir string) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	document := yaml.MapSlice{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("%s is not a consolidated config file: %s", file, err.Error())
+	}
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return err
+	}
+	for _, item := range document {
+		if err := expandItem(configDir, item.Key, item.Value); err != nil {
+			return fmt.Errorf("%s: %s", file, err.Error())
+		}
+	}
+	return nil
+}
+
+func expandItem(dir string, key, value interface{}) error {
+	name, ok := key.(string)
+	if !ok || name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("[%v] is not a file or directory name", key)
+	}
+	path := filepath.Join(dir, name)
+	ext := strings.ToLower(filepath.Ext(name))
+	if ext == ".yml" || ext == ".yaml" {
+		if value == nil {
+			return WriteFileBytes(path, []byte{})
+		}
+		return WriteFile(path, value)
+	}
+	if text, ok := value.(string); ok {
+		return WriteFileBytes(path, []byte(text))
+	}
+	var entries []yaml.MapItem
+	switch contents := value.(type) {
+	case nil:
+	case yaml.MapSlice:
+		entries = contents
+	case map[interface{}]interface{}:
+		for entryKey, entryValue := range contents {
+			entries = append(entries, yaml.MapItem{Key: entryKey, Value: entryValue})
+		}
+	default:
+		return fmt.Errorf("%s must be the contents of a directory or the text of a file", name)
+	}
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := expandItem(path, entry.Key, entry.Value); err != nil {
+			return fmt.Errorf("%s/%s", name, err.Error())
+		}
+	}
+	return nil
+}
+
+//Consolidate - writes the files of configDir to the consolidated config file
+func Consolidate(configDir, file string) error {
+	document, err := consolidateDir(configDir)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(document)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	return WriteFileBytes(file, data)
+}
+
+// consolidateDir lists the files of dir before its directories
+func consolidateDir(dir string) (yaml.MapSlice, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files, dirs := yaml.MapSlice{}, yaml.MapSlice{}
+	for _, info := range infos {
+		if strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, info.Name())
+		if info.IsDir() {
+			contents, err := consolidateDir(path)
+			if err != nil {
+				return nil, err
+			}
+			dirs = append(dirs, yaml.MapItem{Key: info.Name(), Value: contents})
+			continue
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		ext := strings.ToLower(filepath.Ext(info.Name()))
+		if ext != ".yml" && ext != ".yaml" {
+			files = append(files, yaml.MapItem{Key: info.Name(), Value: string(data)})
+			continue
+		}
+		var contents interface{}
+		document := yaml.MapSlice{}
+		if err := yaml.Unmarshal(data, &document); err == nil {
+			if len(document) > 0 {
+				contents = document
+			}
+		} else if err := yaml.Unmarshal(data, &contents); err != nil {
+			return nil, fmt.Errorf("%s is not valid yaml: %s", path, err.Error())
+		}
+		files = append(files, yaml.MapItem{Key: info.Name(), Value: contents})
+	}
+	return append(files, dirs...), nil
+}
+
+func (m *consolidatedManager) Orgs() (result *Orgs, err error) {
+	err = m.read(func(dir *yamlManager) (err error) {
+		result, err = dir.Orgs()
+		return
+	})
+	return
+}
+
+func (m *consolidatedManager) OrgSpaces(orgName string) (result *Spaces, err error) {
+	err = m.read(func(dir *yamlManager) (err error) {
+		result, err = dir.OrgSpaces(orgName)
+		return
+	})
+	return
+}
+
+func (m *consolidatedManager) Spaces() (result []Spaces, err error) {
+	err = m.read(func(dir *yamlManager) (err error) {
+		result, err = dir.Spaces()
+		return
+	})
+	return
+}
+
+func (m *consolidatedManager) GetOrgConfigs() (result []OrgConfig, err error) {
+	err = m.read(func(dir *yamlManager) (err error) {
+		result, err = dir.GetOrgConfigs()
+		return
+	})
+	return
+}
+
+func (m *consolidatedManager) GetSpaceConfigs() (result []SpaceConfig, err error) {
+	err = m.read(func(dir *yamlManager) (err error) {
+		result, err = dir.GetSpaceConfigs()
+		return
+	})
+	return
+}
+
+func (m *consolidatedManager) GetASGConfigs() (result []ASGConfig, err error) {
+	err = m.read(func(dir *yamlManager) (err error) {
+		result, err = dir.GetASGConfigs()
+		return
+	})
+	return
+}
+
+func (m *consolidatedManager) GetDefaultASGConfigs() (result []ASGConfig, err error) {
+	err = m.read(func(dir *yamlManager) (err error) {
+		result, err = dir.GetDefaultASGConfigs()
+		return
+	})
+	return
+}
+
+func (m *consolidatedManager) GetGlobalConfig() (result *GlobalConfig, err error) {
+	err = m.read(func(dir *yamlManager) (err error) {
+		result, err = dir.GetGlobalConfig()
+		return
+	})
+	return
+}
+
+func (m *consolidatedManager) GetSpaceDefaults() (result *SpaceConfig, err error) {
+	err = m.read(func(dir *yamlManager) (err error) {
+		result, err = dir.GetSpaceDefaults()
+		return
+	})
+	return
+}
+
+func (m *consolidatedManager) GetOrgConfig(orgName string) (result *OrgConfig, err error) {
+	err = m.read(func(dir *yamlManager) (err error) {
+		result, err = dir.GetOrgConfig(orgName)
+		return
+	})
+	return
+}
+
+func (m *consolidatedManager) GetRawOrgConfig(orgName string) (result *OrgConfig, err error) {
+	err = m.read(func(dir *yamlManager) (err error) {
+		result, err = dir.GetRawOrgConfig(orgName)
+		return
+	})
+	return
+}
+
+func (m *consolidatedManager) GetSpaceConfig(orgName, spaceName string) (result *SpaceConfig, err error) {
+	err = m.read(func(dir *yamlManager) (err error) {
+		result, err = dir.GetSpaceConfig(orgName, spaceName)
+		return
+	})
+	return
+}
+
+func (m *consolidatedManager) GetRawSpaceConfig(orgName, spaceName string) (result *SpaceConfig, err error) {
+	err = m.read(func(dir *yamlManager) (err error) {
+		result, err = dir.GetRawSpaceConfig(orgName, spaceName)
+		return
+	})
+	return
+}
+
+func (m *consolidatedManager) LdapConfig(bindPassword string) (result *LdapConfig, err error) {
+	err = m.read(func(dir *yamlManager) (err error) {
+		result, err = dir.LdapConfig(bindPassword)
+		return
+	})
+	return
+}
+
+func (m *consolidatedManager) GetOriginMigration() (result *OriginMigration, err error) {
+	err = m.read(func(dir *yamlManager) (err error) {
+		result, err = dir.GetOriginMigration()
+		return
+	})
+	return
+}
+
+func (m *consolidatedManager) GetApprovals() (result *Approvals, err error) {
+	err = m.read(func(dir *yamlManager) (err error) {
+		result, err = dir.GetApprovals()
+		return
+	})
+	return
+}
+
+func (m *consolidatedManager) GetIdentityProviders() (result *IdentityProviders, err error) {
+	err = m.read(func(dir *yamlManager) (err error) {
+		result, err = dir.GetIdentityProviders()
+		return
+	})
+	return
+}
+
+func (m *consolidatedManager) GetOrgGroups() (result *OrgGroups, err error) {
+	err = m.read(func(dir *yamlManager) (err error) {
+		result, err = dir.GetOrgGroups()
+		return
+	})
+	return
+}
+
+func (m *consolidatedManager) GetOrgTemplates() (result *OrgTemplates, err error) {
+	err = m.read(func(dir *yamlManager) (err error) {
+		result, err = dir.GetOrgTemplates()
+		return
+	})
+	return
+}
+
+func (m *consolidatedManager) AddOrgToConfig(orgConfig *OrgConfig) error {
+	return m.update(func(dir *yamlManager) error {
+		return dir.AddOrgToConfig(orgConfig)
+	})
+}
+
+func (m *consolidatedManager) AddSpaceToConfig(spaceConfig *SpaceConfig) error {
+	return m.update(func(dir *yamlManager) error {
+		return dir.AddSpaceToConfig(spaceConfig)
+	})
+}
+
+func (m *consolidatedManager) AddSecurityGroupToSpace(orgName, spaceName string, securityGroupDefinition []byte) error {
+	return m.update(func(dir *yamlManager) error {
+		return dir.AddSecurityGroupToSpace(orgName, spaceName, securityGroupDefinition)
+	})
+}
+
+func (m *consolidatedManager) AddSecurityGroup(securityGroupName string, securityGroupDefinition []byte) error {
+	return m.update(func(dir *yamlManager) error {
+		return dir.AddSecurityGroup(securityGroupName, securityGroupDefinition)
+	})
+}
+
+func (m *consolidatedManager) AddDefaultSecurityGroup(securityGroupName string, securityGroupDefinition []byte) error {
+	return m.update(func(dir *yamlManager) error {
+		return dir.AddDefaultSecurityGroup(securityGroupName, securityGroupDefinition)
+	})
+}
+
+// CreateConfigIfNotExists initializes a new consolidated config file.
+// If the file already exists, it is left unmodified.
+func (m *consolidatedManager) CreateConfigIfNotExists(uaaOrigin string) error {
+	if FileOrDirectoryExists(m.File) {
+		lo.G.Infof("Config file %s already exists, skipping creation", m.File)
+		return nil
+	}
+	return m.update(func(dir *yamlManager) error {
+		return dir.CreateConfigIfNotExists(uaaOrigin)
+	})
+}
+
+// DeleteConfigIfExists deletes the consolidated config file if it exists.
+func (m *consolidatedManager) DeleteConfigIfExists() error {
+	if !FileOrDirectoryExists(m.File) {
+		lo.G.Infof("%s doesn't exists, nothing to delete", m.File)
+		return nil
+	}
+	if err := os.Remove(m.File); err != nil {
+		return fmt.Errorf("cannot delete %s: %v", m.File, err)
+	}
+	lo.G.Infof("Config file %s deleted", m.File)
+	return nil
+}
+
+func (m *consolidatedManager) SaveOrgSpaces(spaces *Spaces) error {
+	return m.update(func(dir *yamlManager) error {
+		return dir.SaveOrgSpaces(spaces)
+	})
+}
+
+func (m *consolidatedManager) SaveSpaceConfig(spaceConfig *SpaceConfig) error {
+	return m.update(func(dir *yamlManager) error {
+		return dir.SaveSpaceConfig(spaceConfig)
+	})
+}
+
+func (m *consolidatedManager) SaveOrgConfig(orgConfig *OrgConfig) error {
+	return m.update(func(dir *yamlManager) error {
+		return dir.SaveOrgConfig(orgConfig)
+	})
+}
+
+func (m *consolidatedManager) DeleteOrgConfig(orgName string) error {
+	return m.update(func(dir *yamlManager) error {
+		return dir.DeleteOrgConfig(orgName)
+	})
+}
+
+func (m *consolidatedManager) DeleteSpaceConfig(orgName, spaceName string) error {
+	return m.update(func(dir *yamlManager) error {
+		return dir.DeleteSpaceConfig(orgName, spaceName)
+	})
+}
+
+func (m *consolidatedManager) SaveOrgs(orgs *Orgs) error {
+	return m.update(func(dir *yamlManager) error {
+		return dir.SaveOrgs(orgs)
+	})
+}
+
+func (m *consolidatedManager) SaveGlobalConfig(globalConfig *GlobalConfig) error {
+	return m.update(func(dir *yamlManager) error {
+		return dir.SaveGlobalConfig(globalConfig)
+	})
+}
+
+func (m *consolidatedManager) SaveOrgGroups(orgGroups *OrgGroups) error {
+	return m.update(func(dir *yamlManager) error {
+		return dir.SaveOrgGroups(orgGroups)
+	})
+}
@@ -0,0 +1,127 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/config"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func marshal(in interface{}) string {
+	out, err := yaml.Marshal(in)
+	Ω(err).ShouldNot(HaveOccurred())
+	return string(out)
+}
+
+var _ = Describe("Consolidated Config", func() {
+	var (
+		tempDir string
+		file    string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "cf-mgmt")
+		Ω(err).ShouldNot(HaveOccurred())
+		file = path.Join(tempDir, "cf-mgmt.yml")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should only consider yml files consolidated", func() {
+		Ω(config.IsConsolidated("config/cf-mgmt.yml")).Should(BeTrue())
+		Ω(config.IsConsolidated("config")).Should(BeFalse())
+		Ω(config.BaseDirectory("repo/cf-mgmt.yml")).Should(Equal("repo"))
+		Ω(config.BaseDirectory("repo/config")).Should(Equal("repo/config"))
+	})
+
+	It("should read the same configuration as the directory it consolidates", func() {
+		Ω(config.Consolidate("./fixtures/config", file)).Should(Succeed())
+		dirManager := config.NewManager("./fixtures/config")
+		fileManager := config.NewManager(file)
+
+		orgs, err := fileManager.Orgs()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(dirManager.Orgs()).Should(Equal(orgs))
+
+		orgConfigs, err := fileManager.GetOrgConfigs()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(orgConfigs).Should(HaveLen(2))
+		dirOrgConfigs, err := dirManager.GetOrgConfigs()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(marshal(dirOrgConfigs)).Should(MatchYAML(marshal(orgConfigs)))
+		for i := range orgConfigs {
+			Ω(orgConfigs[i].ConfigFile()).Should(Equal(dirOrgConfigs[i].ConfigFile() + " of " + file))
+		}
+
+		spaceConfigs, err := fileManager.GetSpaceConfigs()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(spaceConfigs).ShouldNot(BeEmpty())
+		dirSpaceConfigs, err := dirManager.GetSpaceConfigs()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(marshal(dirSpaceConfigs)).Should(MatchYAML(marshal(spaceConfigs)))
+		for i := range spaceConfigs {
+			Ω(spaceConfigs[i].ConfigFile()).Should(Equal(dirSpaceConfigs[i].ConfigFile() + " of " + file))
+		}
+	})
+
+	It("should write changes back to the file", func() {
+		m := config.NewManager(file)
+		Ω(m.CreateConfigIfNotExists("ldap")).Should(Succeed())
+		Ω(config.FileOrDirectoryExists(file)).Should(BeTrue())
+		Ω(m.AddOrgToConfig(&config.OrgConfig{Org: "org1"})).Should(Succeed())
+		Ω(m.AddSpaceToConfig(&config.SpaceConfig{Org: "org1", Space: "space1"})).Should(Succeed())
+
+		contents, err := ioutil.ReadFile(file)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(contents)).Should(ContainSubstring("orgConfig.yml:"))
+
+		reread := config.NewManager(file)
+		orgs, err := reread.Orgs()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(orgs.Orgs).Should(ConsistOf("org1"))
+		spaceConfig, err := reread.GetSpaceConfig("org1", "space1")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(spaceConfig.Space).Should(Equal("space1"))
+	})
+
+	It("should expand a consolidated file into a config directory", func() {
+		Ω(config.Consolidate("./fixtures/config", file)).Should(Succeed())
+		dir := path.Join(tempDir, "config")
+		Ω(config.ExpandConsolidated(file, dir)).Should(Succeed())
+		Ω(config.FileOrDirectoryExists(path.Join(dir, "test", "space1", "security-group.json"))).Should(BeTrue())
+		orgConfigs, err := config.NewManager("./fixtures/config").GetOrgConfigs()
+		Ω(err).ShouldNot(HaveOccurred())
+		expanded, err := config.NewManager(dir).GetOrgConfigs()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(expanded).Should(Equal(orgConfigs))
+	})
+
+	It("should not share or leave behind a working directory", func() {
+		Ω(config.Consolidate("./fixtures/config", file)).Should(Succeed())
+		other := path.Join(tempDir, "other.yml")
+		Ω(config.NewManager(other).CreateConfigIfNotExists("ldap")).Should(Succeed())
+		before, err := filepath.Glob(filepath.Join(os.TempDir(), "cf-mgmt-config-*"))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fileManager, otherManager := config.NewManager(file), config.NewManager(other)
+		Ω(otherManager.AddOrgToConfig(&config.OrgConfig{Org: "org1"})).Should(Succeed())
+		orgs, err := fileManager.Orgs()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(orgs.Orgs).ShouldNot(ContainElement("org1"))
+		orgs, err = otherManager.Orgs()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(orgs.Orgs).Should(ConsistOf("org1"))
+
+		after, err := filepath.Glob(filepath.Join(os.TempDir(), "cf-mgmt-config-*"))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(after).Should(ConsistOf(before))
+	})
+})
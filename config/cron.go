@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a parsed five field cron expression
+// (minute hour day-of-month month day-of-week).
+type cronSchedule struct {
+	minutes, hours, days, months, weekdays map[int]bool
+	// cron matches either day field when both are restricted
+	daysRestricted, weekdaysRestricted bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronMinute   = cronField{name: "minute", min: 0, max: 59}
+	cronHour     = cronField{name: "hour", min: 0, max: 23}
+	cronDay      = cronField{name: "day-of-month", min: 1, max: 31}
+	cronMonth    = cronField{name: "month", min: 1, max: 12, names: map[string]int{"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}}
+	cronWeekday  = cronField{name: "day-of-week", min: 0, max: 7, names: map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}}
+	cronFieldSet = []cronField{cronMinute, cronHour, cronDay, cronMonth, cronWeekday}
+)
+
+func parseCron(expression string) (*cronSchedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != len(cronFieldSet) {
+		return nil, fmt.Errorf("cron expression [%s] must have 5 fields (minute hour day-of-month month day-of-week)", expression)
+	}
+	values := make([]map[int]bool, len(fields))
+	for i, field := range fields {
+		var err error
+		if values[i], err = cronFieldSet[i].parse(field); err != nil {
+			return nil, fmt.Errorf("cron expression [%s]: %s", expression, err.Error())
+		}
+	}
+	// 7 is also sunday
+	if values[4][7] {
+		values[4][0] = true
+	}
+	return &cronSchedule{
+		minutes:            values[0],
+		hours:              values[1],
+		days:               values[2],
+		months:             values[3],
+		weekdays:           values[4],
+		daysRestricted:     !strings.HasPrefix(fields[2], "*"),
+		weekdaysRestricted: !strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+func (f cronField) parse(field string) (map[int]bool, error) {
+	values := make(map[int]bool)
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid step [%s] in %s", part[i+1:], f.name)
+			}
+			part = part[:i]
+		}
+		start, end := f.min, f.max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if start, err = f.value(bounds[0]); err != nil {
+				return nil, err
+			}
+			end = start
+			if len(bounds) == 2 {
+				if end, err = f.value(bounds[1]); err != nil {
+					return nil, err
+				}
+			} else if step > 1 {
+				end = f.max
+			}
+			if start > end {
+				return nil, fmt.Errorf("invalid range [%s] in %s", part, f.name)
+			}
+		}
+		for value := start; value <= end; value += step {
+			values[value] = true
+		}
+	}
+	return values, nil
+}
+
+func (f cronField) value(text string) (int, error) {
+	if value, ok := f.names[strings.ToLower(text)]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil || value < f.min || value > f.max {
+		return 0, fmt.Errorf("invalid value [%s] in %s, must be between %d and %d", text, f.name, f.min, f.max)
+	}
+	return value, nil
+}
+
+func (c *cronSchedule) matches(t time.Time) bool {
+	if !c.minutes[t.Minute()] || !c.hours[t.Hour()] || !c.months[int(t.Month())] {
+		return false
+	}
+	day, weekday := c.days[t.Day()], c.weekdays[int(t.Weekday())]
+	if c.daysRestricted && c.weekdaysRestricted {
+		return day || weekday
+	}
+	return day && weekday
+}
+
+// within returns whether the schedule started in the duration up to t.
+func (c *cronSchedule) within(t time.Time, duration time.Duration) bool {
+	t = t.Truncate(time.Minute)
+	for start := t; t.Sub(start) < duration; start = start.Add(-time.Minute) {
+		if c.matches(start) {
+			return true
+		}
+	}
+	return false
+}
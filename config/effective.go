@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"sort"
+)
+
+// EffectiveOrgConfig returns the configuration of the org as cf-mgmt applies
+// it, after includes, org groups and group mappings, with the deprecated
+// single group of each role folded into its sorted ldap groups.
+func EffectiveOrgConfig(reader Reader, orgName string) (*OrgConfig, error) {
+	orgConfig, err := reader.GetOrgConfig(orgName)
+	if err != nil {
+		return nil, err
+	}
+	orgConfig.Manager.LDAPGroups = sortedGroups(orgConfig.GetManagerGroups())
+	orgConfig.BillingManager.LDAPGroups = sortedGroups(orgConfig.GetBillingManagerGroups())
+	orgConfig.Auditor.LDAPGroups = sortedGroups(orgConfig.GetAuditorGroups())
+	orgConfig.ManagerGroup, orgConfig.BillingManagerGroup, orgConfig.AuditorGroup = "", "", ""
+	orgConfig.Manager.LDAPGroup, orgConfig.BillingManager.LDAPGroup, orgConfig.Auditor.LDAPGroup = "", "", ""
+	orgConfig.Include = nil
+	return orgConfig, nil
+}
+
+// EffectiveSpaceConfig returns the configuration of the space as cf-mgmt
+// applies it, after includes, space defaults, the all-spaces roles of the org
+// and group mappings, with the security groups of its asg profile resolved.
+// A space without its own configuration gets that of the space pattern, such
+// as team-*, matching it.
+func EffectiveSpaceConfig(reader Reader, orgName, spaceName string) (*SpaceConfig, error) {
+	spaceConfigs, err := reader.GetSpaceConfigs()
+	if err != nil {
+		return nil, err
+	}
+	var spaceConfig *SpaceConfig
+	for i := range spaceConfigs {
+		if spaceConfigs[i].Org != orgName {
+			continue
+		}
+		if spaceConfigs[i].Space == spaceName {
+			spaceConfig = &spaceConfigs[i]
+			break
+		}
+		if match, _ := path.Match(spaceConfigs[i].Space, spaceName); match && spaceConfigs[i].IsPattern() && spaceConfig == nil {
+			spaceConfig = &spaceConfigs[i]
+		}
+	}
+	if spaceConfig == nil {
+		return nil, fmt.Errorf("Space [%s] not found in org [%s] config", spaceName, orgName)
+	}
+	orgConfig, err := reader.GetOrgConfig(orgName)
+	if err != nil {
+		return nil, err
+	}
+	spaceConfig.Space = spaceName
+	if spaceConfig.ASGs, spaceConfig.StagingASGs, err = orgConfig.SpaceASGs(spaceConfig); err != nil {
+		return nil, err
+	}
+	spaceConfig.ASGProfile, spaceConfig.Include = "", nil
+	spaceConfig.Developer.LDAPGroups = sortedGroups(spaceConfig.GetDeveloperGroups())
+	spaceConfig.Manager.LDAPGroups = sortedGroups(spaceConfig.GetManagerGroups())
+	spaceConfig.Auditor.LDAPGroups = sortedGroups(spaceConfig.GetAuditorGroups())
+	spaceConfig.DeveloperGroup, spaceConfig.ManagerGroup, spaceConfig.AuditorGroup = "", "", ""
+	spaceConfig.Developer.LDAPGroup, spaceConfig.Manager.LDAPGroup, spaceConfig.Auditor.LDAPGroup = "", "", ""
+	return spaceConfig, nil
+}
+
+func sortedGroups(groups []string) []string {
+	sort.Strings(groups)
+	return groups
+}
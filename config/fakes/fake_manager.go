@@ -123,6 +123,14 @@ type FakeManager struct {
 	saveGlobalConfigReturns struct {
 		result1 error
 	}
+	SaveOrgGroupsStub        func(*config.OrgGroups) error
+	saveOrgGroupsMutex       sync.RWMutex
+	saveOrgGroupsArgsForCall []struct {
+		arg1 *config.OrgGroups
+	}
+	saveOrgGroupsReturns struct {
+		result1 error
+	}
 	OrgsStub        func() (*config.Orgs, error)
 	orgsMutex       sync.RWMutex
 	orgsArgsForCall []struct{}
@@ -216,6 +224,60 @@ type FakeManager struct {
 		result1 *config.LdapConfig
 		result2 error
 	}
+	GetOriginMigrationStub        func() (*config.OriginMigration, error)
+	getOriginMigrationMutex       sync.RWMutex
+	getOriginMigrationArgsForCall []struct{}
+	getOriginMigrationReturns     struct {
+		result1 *config.OriginMigration
+		result2 error
+	}
+	GetApprovalsStub        func() (*config.Approvals, error)
+	getApprovalsMutex       sync.RWMutex
+	getApprovalsArgsForCall []struct{}
+	getApprovalsReturns     struct {
+		result1 *config.Approvals
+		result2 error
+	}
+	GetOrgGroupsStub        func() (*config.OrgGroups, error)
+	getOrgGroupsMutex       sync.RWMutex
+	getOrgGroupsArgsForCall []struct{}
+	getOrgGroupsReturns     struct {
+		result1 *config.OrgGroups
+		result2 error
+	}
+	GetIdentityProvidersStub        func() (*config.IdentityProviders, error)
+	getIdentityProvidersMutex       sync.RWMutex
+	getIdentityProvidersArgsForCall []struct{}
+	getIdentityProvidersReturns     struct {
+		result1 *config.IdentityProviders
+		result2 error
+	}
+	GetOrgTemplatesStub        func() (*config.OrgTemplates, error)
+	getOrgTemplatesMutex       sync.RWMutex
+	getOrgTemplatesArgsForCall []struct{}
+	getOrgTemplatesReturns     struct {
+		result1 *config.OrgTemplates
+		result2 error
+	}
+	GetRawOrgConfigStub        func(orgName string) (*config.OrgConfig, error)
+	getRawOrgConfigMutex       sync.RWMutex
+	getRawOrgConfigArgsForCall []struct {
+		orgName string
+	}
+	getRawOrgConfigReturns struct {
+		result1 *config.OrgConfig
+		result2 error
+	}
+	GetRawSpaceConfigStub        func(orgName string, spaceName string) (*config.SpaceConfig, error)
+	getRawSpaceConfigMutex       sync.RWMutex
+	getRawSpaceConfigArgsForCall []struct {
+		orgName   string
+		spaceName string
+	}
+	getRawSpaceConfigReturns struct {
+		result1 *config.SpaceConfig
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -694,6 +756,39 @@ func (fake *FakeManager) SaveGlobalConfigReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeManager) SaveOrgGroups(arg1 *config.OrgGroups) error {
+	fake.saveOrgGroupsMutex.Lock()
+	fake.saveOrgGroupsArgsForCall = append(fake.saveOrgGroupsArgsForCall, struct {
+		arg1 *config.OrgGroups
+	}{arg1})
+	fake.recordInvocation("SaveOrgGroups", []interface{}{arg1})
+	fake.saveOrgGroupsMutex.Unlock()
+	if fake.SaveOrgGroupsStub != nil {
+		return fake.SaveOrgGroupsStub(arg1)
+	} else {
+		return fake.saveOrgGroupsReturns.result1
+	}
+}
+
+func (fake *FakeManager) SaveOrgGroupsCallCount() int {
+	fake.saveOrgGroupsMutex.RLock()
+	defer fake.saveOrgGroupsMutex.RUnlock()
+	return len(fake.saveOrgGroupsArgsForCall)
+}
+
+func (fake *FakeManager) SaveOrgGroupsArgsForCall(i int) *config.OrgGroups {
+	fake.saveOrgGroupsMutex.RLock()
+	defer fake.saveOrgGroupsMutex.RUnlock()
+	return fake.saveOrgGroupsArgsForCall[i].arg1
+}
+
+func (fake *FakeManager) SaveOrgGroupsReturns(result1 error) {
+	fake.SaveOrgGroupsStub = nil
+	fake.saveOrgGroupsReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeManager) Orgs() (*config.Orgs, error) {
 	fake.orgsMutex.Lock()
 	fake.orgsArgsForCall = append(fake.orgsArgsForCall, struct{}{})
@@ -1039,6 +1134,205 @@ func (fake *FakeManager) LdapConfigReturns(result1 *config.LdapConfig, result2 e
 	}{result1, result2}
 }
 
+func (fake *FakeManager) GetOriginMigration() (*config.OriginMigration, error) {
+	fake.getOriginMigrationMutex.Lock()
+	fake.getOriginMigrationArgsForCall = append(fake.getOriginMigrationArgsForCall, struct{}{})
+	fake.recordInvocation("GetOriginMigration", []interface{}{})
+	fake.getOriginMigrationMutex.Unlock()
+	if fake.GetOriginMigrationStub != nil {
+		return fake.GetOriginMigrationStub()
+	} else {
+		return fake.getOriginMigrationReturns.result1, fake.getOriginMigrationReturns.result2
+	}
+}
+
+func (fake *FakeManager) GetOriginMigrationCallCount() int {
+	fake.getOriginMigrationMutex.RLock()
+	defer fake.getOriginMigrationMutex.RUnlock()
+	return len(fake.getOriginMigrationArgsForCall)
+}
+
+func (fake *FakeManager) GetOriginMigrationReturns(result1 *config.OriginMigration, result2 error) {
+	fake.GetOriginMigrationStub = nil
+	fake.getOriginMigrationReturns = struct {
+		result1 *config.OriginMigration
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) GetApprovals() (*config.Approvals, error) {
+	fake.getApprovalsMutex.Lock()
+	fake.getApprovalsArgsForCall = append(fake.getApprovalsArgsForCall, struct{}{})
+	fake.recordInvocation("GetApprovals", []interface{}{})
+	fake.getApprovalsMutex.Unlock()
+	if fake.GetApprovalsStub != nil {
+		return fake.GetApprovalsStub()
+	} else {
+		return fake.getApprovalsReturns.result1, fake.getApprovalsReturns.result2
+	}
+}
+
+func (fake *FakeManager) GetApprovalsCallCount() int {
+	fake.getApprovalsMutex.RLock()
+	defer fake.getApprovalsMutex.RUnlock()
+	return len(fake.getApprovalsArgsForCall)
+}
+
+func (fake *FakeManager) GetApprovalsReturns(result1 *config.Approvals, result2 error) {
+	fake.GetApprovalsStub = nil
+	fake.getApprovalsReturns = struct {
+		result1 *config.Approvals
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) GetOrgGroups() (*config.OrgGroups, error) {
+	fake.getOrgGroupsMutex.Lock()
+	fake.getOrgGroupsArgsForCall = append(fake.getOrgGroupsArgsForCall, struct{}{})
+	fake.recordInvocation("GetOrgGroups", []interface{}{})
+	fake.getOrgGroupsMutex.Unlock()
+	if fake.GetOrgGroupsStub != nil {
+		return fake.GetOrgGroupsStub()
+	} else {
+		return fake.getOrgGroupsReturns.result1, fake.getOrgGroupsReturns.result2
+	}
+}
+
+func (fake *FakeManager) GetOrgGroupsCallCount() int {
+	fake.getOrgGroupsMutex.RLock()
+	defer fake.getOrgGroupsMutex.RUnlock()
+	return len(fake.getOrgGroupsArgsForCall)
+}
+
+func (fake *FakeManager) GetOrgGroupsReturns(result1 *config.OrgGroups, result2 error) {
+	fake.GetOrgGroupsStub = nil
+	fake.getOrgGroupsReturns = struct {
+		result1 *config.OrgGroups
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) GetIdentityProviders() (*config.IdentityProviders, error) {
+	fake.getIdentityProvidersMutex.Lock()
+	fake.getIdentityProvidersArgsForCall = append(fake.getIdentityProvidersArgsForCall, struct{}{})
+	fake.recordInvocation("GetIdentityProviders", []interface{}{})
+	fake.getIdentityProvidersMutex.Unlock()
+	if fake.GetIdentityProvidersStub != nil {
+		return fake.GetIdentityProvidersStub()
+	} else {
+		return fake.getIdentityProvidersReturns.result1, fake.getIdentityProvidersReturns.result2
+	}
+}
+
+func (fake *FakeManager) GetIdentityProvidersCallCount() int {
+	fake.getIdentityProvidersMutex.RLock()
+	defer fake.getIdentityProvidersMutex.RUnlock()
+	return len(fake.getIdentityProvidersArgsForCall)
+}
+
+func (fake *FakeManager) GetIdentityProvidersReturns(result1 *config.IdentityProviders, result2 error) {
+	fake.GetIdentityProvidersStub = nil
+	fake.getIdentityProvidersReturns = struct {
+		result1 *config.IdentityProviders
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) GetOrgTemplates() (*config.OrgTemplates, error) {
+	fake.getOrgTemplatesMutex.Lock()
+	fake.getOrgTemplatesArgsForCall = append(fake.getOrgTemplatesArgsForCall, struct{}{})
+	fake.recordInvocation("GetOrgTemplates", []interface{}{})
+	fake.getOrgTemplatesMutex.Unlock()
+	if fake.GetOrgTemplatesStub != nil {
+		return fake.GetOrgTemplatesStub()
+	} else {
+		return fake.getOrgTemplatesReturns.result1, fake.getOrgTemplatesReturns.result2
+	}
+}
+
+func (fake *FakeManager) GetOrgTemplatesCallCount() int {
+	fake.getOrgTemplatesMutex.RLock()
+	defer fake.getOrgTemplatesMutex.RUnlock()
+	return len(fake.getOrgTemplatesArgsForCall)
+}
+
+func (fake *FakeManager) GetOrgTemplatesReturns(result1 *config.OrgTemplates, result2 error) {
+	fake.GetOrgTemplatesStub = nil
+	fake.getOrgTemplatesReturns = struct {
+		result1 *config.OrgTemplates
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) GetRawOrgConfig(orgName string) (*config.OrgConfig, error) {
+	fake.getRawOrgConfigMutex.Lock()
+	fake.getRawOrgConfigArgsForCall = append(fake.getRawOrgConfigArgsForCall, struct {
+		orgName string
+	}{orgName})
+	fake.recordInvocation("GetRawOrgConfig", []interface{}{orgName})
+	fake.getRawOrgConfigMutex.Unlock()
+	if fake.GetRawOrgConfigStub != nil {
+		return fake.GetRawOrgConfigStub(orgName)
+	} else {
+		return fake.getRawOrgConfigReturns.result1, fake.getRawOrgConfigReturns.result2
+	}
+}
+
+func (fake *FakeManager) GetRawOrgConfigCallCount() int {
+	fake.getRawOrgConfigMutex.RLock()
+	defer fake.getRawOrgConfigMutex.RUnlock()
+	return len(fake.getRawOrgConfigArgsForCall)
+}
+
+func (fake *FakeManager) GetRawOrgConfigArgsForCall(i int) string {
+	fake.getRawOrgConfigMutex.RLock()
+	defer fake.getRawOrgConfigMutex.RUnlock()
+	return fake.getRawOrgConfigArgsForCall[i].orgName
+}
+
+func (fake *FakeManager) GetRawOrgConfigReturns(result1 *config.OrgConfig, result2 error) {
+	fake.GetRawOrgConfigStub = nil
+	fake.getRawOrgConfigReturns = struct {
+		result1 *config.OrgConfig
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) GetRawSpaceConfig(orgName string, spaceName string) (*config.SpaceConfig, error) {
+	fake.getRawSpaceConfigMutex.Lock()
+	fake.getRawSpaceConfigArgsForCall = append(fake.getRawSpaceConfigArgsForCall, struct {
+		orgName   string
+		spaceName string
+	}{orgName, spaceName})
+	fake.recordInvocation("GetRawSpaceConfig", []interface{}{orgName, spaceName})
+	fake.getRawSpaceConfigMutex.Unlock()
+	if fake.GetRawSpaceConfigStub != nil {
+		return fake.GetRawSpaceConfigStub(orgName, spaceName)
+	} else {
+		return fake.getRawSpaceConfigReturns.result1, fake.getRawSpaceConfigReturns.result2
+	}
+}
+
+func (fake *FakeManager) GetRawSpaceConfigCallCount() int {
+	fake.getRawSpaceConfigMutex.RLock()
+	defer fake.getRawSpaceConfigMutex.RUnlock()
+	return len(fake.getRawSpaceConfigArgsForCall)
+}
+
+func (fake *FakeManager) GetRawSpaceConfigArgsForCall(i int) (string, string) {
+	fake.getRawSpaceConfigMutex.RLock()
+	defer fake.getRawSpaceConfigMutex.RUnlock()
+	return fake.getRawSpaceConfigArgsForCall[i].orgName, fake.getRawSpaceConfigArgsForCall[i].spaceName
+}
+
+func (fake *FakeManager) GetRawSpaceConfigReturns(result1 *config.SpaceConfig, result2 error) {
+	fake.GetRawSpaceConfigStub = nil
+	fake.getRawSpaceConfigReturns = struct {
+		result1 *config.SpaceConfig
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeManager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -1070,6 +1364,8 @@ func (fake *FakeManager) Invocations() map[string][][]interface{} {
 	defer fake.saveOrgsMutex.RUnlock()
 	fake.saveGlobalConfigMutex.RLock()
 	defer fake.saveGlobalConfigMutex.RUnlock()
+	fake.saveOrgGroupsMutex.RLock()
+	defer fake.saveOrgGroupsMutex.RUnlock()
 	fake.orgsMutex.RLock()
 	defer fake.orgsMutex.RUnlock()
 	fake.orgSpacesMutex.RLock()
@@ -1094,6 +1390,20 @@ func (fake *FakeManager) Invocations() map[string][][]interface{} {
 	defer fake.getSpaceConfigMutex.RUnlock()
 	fake.ldapConfigMutex.RLock()
 	defer fake.ldapConfigMutex.RUnlock()
+	fake.getOriginMigrationMutex.RLock()
+	defer fake.getOriginMigrationMutex.RUnlock()
+	fake.getApprovalsMutex.RLock()
+	defer fake.getApprovalsMutex.RUnlock()
+	fake.getOrgGroupsMutex.RLock()
+	defer fake.getOrgGroupsMutex.RUnlock()
+	fake.getIdentityProvidersMutex.RLock()
+	defer fake.getIdentityProvidersMutex.RUnlock()
+	fake.getOrgTemplatesMutex.RLock()
+	defer fake.getOrgTemplatesMutex.RUnlock()
+	fake.getRawOrgConfigMutex.RLock()
+	defer fake.getRawOrgConfigMutex.RUnlock()
+	fake.getRawSpaceConfigMutex.RLock()
+	defer fake.getRawSpaceConfigMutex.RUnlock()
 	return fake.invocations
 }
 
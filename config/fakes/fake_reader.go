@@ -101,6 +101,60 @@ type FakeReader struct {
 		result1 *config.LdapConfig
 		result2 error
 	}
+	GetOriginMigrationStub        func() (*config.OriginMigration, error)
+	getOriginMigrationMutex       sync.RWMutex
+	getOriginMigrationArgsForCall []struct{}
+	getOriginMigrationReturns     struct {
+		result1 *config.OriginMigration
+		result2 error
+	}
+	GetApprovalsStub        func() (*config.Approvals, error)
+	getApprovalsMutex       sync.RWMutex
+	getApprovalsArgsForCall []struct{}
+	getApprovalsReturns     struct {
+		result1 *config.Approvals
+		result2 error
+	}
+	GetOrgGroupsStub        func() (*config.OrgGroups, error)
+	getOrgGroupsMutex       sync.RWMutex
+	getOrgGroupsArgsForCall []struct{}
+	getOrgGroupsReturns     struct {
+		result1 *config.OrgGroups
+		result2 error
+	}
+	GetIdentityProvidersStub        func() (*config.IdentityProviders, error)
+	getIdentityProvidersMutex       sync.RWMutex
+	getIdentityProvidersArgsForCall []struct{}
+	getIdentityProvidersReturns     struct {
+		result1 *config.IdentityProviders
+		result2 error
+	}
+	GetOrgTemplatesStub        func() (*config.OrgTemplates, error)
+	getOrgTemplatesMutex       sync.RWMutex
+	getOrgTemplatesArgsForCall []struct{}
+	getOrgTemplatesReturns     struct {
+		result1 *config.OrgTemplates
+		result2 error
+	}
+	GetRawOrgConfigStub        func(orgName string) (*config.OrgConfig, error)
+	getRawOrgConfigMutex       sync.RWMutex
+	getRawOrgConfigArgsForCall []struct {
+		orgName string
+	}
+	getRawOrgConfigReturns struct {
+		result1 *config.OrgConfig
+		result2 error
+	}
+	GetRawSpaceConfigStub        func(orgName string, spaceName string) (*config.SpaceConfig, error)
+	getRawSpaceConfigMutex       sync.RWMutex
+	getRawSpaceConfigArgsForCall []struct {
+		orgName   string
+		spaceName string
+	}
+	getRawSpaceConfigReturns struct {
+		result1 *config.SpaceConfig
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -450,6 +504,205 @@ func (fake *FakeReader) LdapConfigReturns(result1 *config.LdapConfig, result2 er
 	}{result1, result2}
 }
 
+func (fake *FakeReader) GetOriginMigration() (*config.OriginMigration, error) {
+	fake.getOriginMigrationMutex.Lock()
+	fake.getOriginMigrationArgsForCall = append(fake.getOriginMigrationArgsForCall, struct{}{})
+	fake.recordInvocation("GetOriginMigration", []interface{}{})
+	fake.getOriginMigrationMutex.Unlock()
+	if fake.GetOriginMigrationStub != nil {
+		return fake.GetOriginMigrationStub()
+	} else {
+		return fake.getOriginMigrationReturns.result1, fake.getOriginMigrationReturns.result2
+	}
+}
+
+func (fake *FakeReader) GetOriginMigrationCallCount() int {
+	fake.getOriginMigrationMutex.RLock()
+	defer fake.getOriginMigrationMutex.RUnlock()
+	return len(fake.getOriginMigrationArgsForCall)
+}
+
+func (fake *FakeReader) GetOriginMigrationReturns(result1 *config.OriginMigration, result2 error) {
+	fake.GetOriginMigrationStub = nil
+	fake.getOriginMigrationReturns = struct {
+		result1 *config.OriginMigration
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeReader) GetApprovals() (*config.Approvals, error) {
+	fake.getApprovalsMutex.Lock()
+	fake.getApprovalsArgsForCall = append(fake.getApprovalsArgsForCall, struct{}{})
+	fake.recordInvocation("GetApprovals", []interface{}{})
+	fake.getApprovalsMutex.Unlock()
+	if fake.GetApprovalsStub != nil {
+		return fake.GetApprovalsStub()
+	} else {
+		return fake.getApprovalsReturns.result1, fake.getApprovalsReturns.result2
+	}
+}
+
+func (fake *FakeReader) GetApprovalsCallCount() int {
+	fake.getApprovalsMutex.RLock()
+	defer fake.getApprovalsMutex.RUnlock()
+	return len(fake.getApprovalsArgsForCall)
+}
+
+func (fake *FakeReader) GetApprovalsReturns(result1 *config.Approvals, result2 error) {
+	fake.GetApprovalsStub = nil
+	fake.getApprovalsReturns = struct {
+		result1 *config.Approvals
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeReader) GetOrgGroups() (*config.OrgGroups, error) {
+	fake.getOrgGroupsMutex.Lock()
+	fake.getOrgGroupsArgsForCall = append(fake.getOrgGroupsArgsForCall, struct{}{})
+	fake.recordInvocation("GetOrgGroups", []interface{}{})
+	fake.getOrgGroupsMutex.Unlock()
+	if fake.GetOrgGroupsStub != nil {
+		return fake.GetOrgGroupsStub()
+	} else {
+		return fake.getOrgGroupsReturns.result1, fake.getOrgGroupsReturns.result2
+	}
+}
+
+func (fake *FakeReader) GetOrgGroupsCallCount() int {
+	fake.getOrgGroupsMutex.RLock()
+	defer fake.getOrgGroupsMutex.RUnlock()
+	return len(fake.getOrgGroupsArgsForCall)
+}
+
+func (fake *FakeReader) GetOrgGroupsReturns(result1 *config.OrgGroups, result2 error) {
+	fake.GetOrgGroupsStub = nil
+	fake.getOrgGroupsReturns = struct {
+		result1 *config.OrgGroups
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeReader) GetIdentityProviders() (*config.IdentityProviders, error) {
+	fake.getIdentityProvidersMutex.Lock()
+	fake.getIdentityProvidersArgsForCall = append(fake.getIdentityProvidersArgsForCall, struct{}{})
+	fake.recordInvocation("GetIdentityProviders", []interface{}{})
+	fake.getIdentityProvidersMutex.Unlock()
+	if fake.GetIdentityProvidersStub != nil {
+		return fake.GetIdentityProvidersStub()
+	} else {
+		return fake.getIdentityProvidersReturns.result1, fake.getIdentityProvidersReturns.result2
+	}
+}
+
+func (fake *FakeReader) GetIdentityProvidersCallCount() int {
+	fake.getIdentityProvidersMutex.RLock()
+	defer fake.getIdentityProvidersMutex.RUnlock()
+	return len(fake.getIdentityProvidersArgsForCall)
+}
+
+func (fake *FakeReader) GetIdentityProvidersReturns(result1 *config.IdentityProviders, result2 error) {
+	fake.GetIdentityProvidersStub = nil
+	fake.getIdentityProvidersReturns = struct {
+		result1 *config.IdentityProviders
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeReader) GetOrgTemplates() (*config.OrgTemplates, error) {
+	fake.getOrgTemplatesMutex.Lock()
+	fake.getOrgTemplatesArgsForCall = append(fake.getOrgTemplatesArgsForCall, struct{}{})
+	fake.recordInvocation("GetOrgTemplates", []interface{}{})
+	fake.getOrgTemplatesMutex.Unlock()
+	if fake.GetOrgTemplatesStub != nil {
+		return fake.GetOrgTemplatesStub()
+	} else {
+		return fake.getOrgTemplatesReturns.result1, fake.getOrgTemplatesReturns.result2
+	}
+}
+
+func (fake *FakeReader) GetOrgTemplatesCallCount() int {
+	fake.getOrgTemplatesMutex.RLock()
+	defer fake.getOrgTemplatesMutex.RUnlock()
+	return len(fake.getOrgTemplatesArgsForCall)
+}
+
+func (fake *FakeReader) GetOrgTemplatesReturns(result1 *config.OrgTemplates, result2 error) {
+	fake.GetOrgTemplatesStub = nil
+	fake.getOrgTemplatesReturns = struct {
+		result1 *config.OrgTemplates
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeReader) GetRawOrgConfig(orgName string) (*config.OrgConfig, error) {
+	fake.getRawOrgConfigMutex.Lock()
+	fake.getRawOrgConfigArgsForCall = append(fake.getRawOrgConfigArgsForCall, struct {
+		orgName string
+	}{orgName})
+	fake.recordInvocation("GetRawOrgConfig", []interface{}{orgName})
+	fake.getRawOrgConfigMutex.Unlock()
+	if fake.GetRawOrgConfigStub != nil {
+		return fake.GetRawOrgConfigStub(orgName)
+	} else {
+		return fake.getRawOrgConfigReturns.result1, fake.getRawOrgConfigReturns.result2
+	}
+}
+
+func (fake *FakeReader) GetRawOrgConfigCallCount() int {
+	fake.getRawOrgConfigMutex.RLock()
+	defer fake.getRawOrgConfigMutex.RUnlock()
+	return len(fake.getRawOrgConfigArgsForCall)
+}
+
+func (fake *FakeReader) GetRawOrgConfigArgsForCall(i int) string {
+	fake.getRawOrgConfigMutex.RLock()
+	defer fake.getRawOrgConfigMutex.RUnlock()
+	return fake.getRawOrgConfigArgsForCall[i].orgName
+}
+
+func (fake *FakeReader) GetRawOrgConfigReturns(result1 *config.OrgConfig, result2 error) {
+	fake.GetRawOrgConfigStub = nil
+	fake.getRawOrgConfigReturns = struct {
+		result1 *config.OrgConfig
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeReader) GetRawSpaceConfig(orgName string, spaceName string) (*config.SpaceConfig, error) {
+	fake.getRawSpaceConfigMutex.Lock()
+	fake.getRawSpaceConfigArgsForCall = append(fake.getRawSpaceConfigArgsForCall, struct {
+		orgName   string
+		spaceName string
+	}{orgName, spaceName})
+	fake.recordInvocation("GetRawSpaceConfig", []interface{}{orgName, spaceName})
+	fake.getRawSpaceConfigMutex.Unlock()
+	if fake.GetRawSpaceConfigStub != nil {
+		return fake.GetRawSpaceConfigStub(orgName, spaceName)
+	} else {
+		return fake.getRawSpaceConfigReturns.result1, fake.getRawSpaceConfigReturns.result2
+	}
+}
+
+func (fake *FakeReader) GetRawSpaceConfigCallCount() int {
+	fake.getRawSpaceConfigMutex.RLock()
+	defer fake.getRawSpaceConfigMutex.RUnlock()
+	return len(fake.getRawSpaceConfigArgsForCall)
+}
+
+func (fake *FakeReader) GetRawSpaceConfigArgsForCall(i int) (string, string) {
+	fake.getRawSpaceConfigMutex.RLock()
+	defer fake.getRawSpaceConfigMutex.RUnlock()
+	return fake.getRawSpaceConfigArgsForCall[i].orgName, fake.getRawSpaceConfigArgsForCall[i].spaceName
+}
+
+func (fake *FakeReader) GetRawSpaceConfigReturns(result1 *config.SpaceConfig, result2 error) {
+	fake.GetRawSpaceConfigStub = nil
+	fake.getRawSpaceConfigReturns = struct {
+		result1 *config.SpaceConfig
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeReader) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -477,6 +730,20 @@ func (fake *FakeReader) Invocations() map[string][][]interface{} {
 	defer fake.getSpaceConfigMutex.RUnlock()
 	fake.ldapConfigMutex.RLock()
 	defer fake.ldapConfigMutex.RUnlock()
+	fake.getOriginMigrationMutex.RLock()
+	defer fake.getOriginMigrationMutex.RUnlock()
+	fake.getApprovalsMutex.RLock()
+	defer fake.getApprovalsMutex.RUnlock()
+	fake.getOrgGroupsMutex.RLock()
+	defer fake.getOrgGroupsMutex.RUnlock()
+	fake.getIdentityProvidersMutex.RLock()
+	defer fake.getIdentityProvidersMutex.RUnlock()
+	fake.getOrgTemplatesMutex.RLock()
+	defer fake.getOrgTemplatesMutex.RUnlock()
+	fake.getRawOrgConfigMutex.RLock()
+	defer fake.getRawOrgConfigMutex.RUnlock()
+	fake.getRawSpaceConfigMutex.RLock()
+	defer fake.getRawSpaceConfigMutex.RUnlock()
 	return fake.invocations
 }
 
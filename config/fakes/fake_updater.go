@@ -123,6 +123,14 @@ type FakeUpdater struct {
 	saveGlobalConfigReturns struct {
 		result1 error
 	}
+	SaveOrgGroupsStub        func(*config.OrgGroups) error
+	saveOrgGroupsMutex       sync.RWMutex
+	saveOrgGroupsArgsForCall []struct {
+		arg1 *config.OrgGroups
+	}
+	saveOrgGroupsReturns struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -601,6 +609,39 @@ func (fake *FakeUpdater) SaveGlobalConfigReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeUpdater) SaveOrgGroups(arg1 *config.OrgGroups) error {
+	fake.saveOrgGroupsMutex.Lock()
+	fake.saveOrgGroupsArgsForCall = append(fake.saveOrgGroupsArgsForCall, struct {
+		arg1 *config.OrgGroups
+	}{arg1})
+	fake.recordInvocation("SaveOrgGroups", []interface{}{arg1})
+	fake.saveOrgGroupsMutex.Unlock()
+	if fake.SaveOrgGroupsStub != nil {
+		return fake.SaveOrgGroupsStub(arg1)
+	} else {
+		return fake.saveOrgGroupsReturns.result1
+	}
+}
+
+func (fake *FakeUpdater) SaveOrgGroupsCallCount() int {
+	fake.saveOrgGroupsMutex.RLock()
+	defer fake.saveOrgGroupsMutex.RUnlock()
+	return len(fake.saveOrgGroupsArgsForCall)
+}
+
+func (fake *FakeUpdater) SaveOrgGroupsArgsForCall(i int) *config.OrgGroups {
+	fake.saveOrgGroupsMutex.RLock()
+	defer fake.saveOrgGroupsMutex.RUnlock()
+	return fake.saveOrgGroupsArgsForCall[i].arg1
+}
+
+func (fake *FakeUpdater) SaveOrgGroupsReturns(result1 error) {
+	fake.SaveOrgGroupsStub = nil
+	fake.saveOrgGroupsReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeUpdater) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -632,6 +673,8 @@ func (fake *FakeUpdater) Invocations() map[string][][]interface{} {
 	defer fake.saveOrgsMutex.RUnlock()
 	fake.saveGlobalConfigMutex.RLock()
 	defer fake.saveGlobalConfigMutex.RUnlock()
+	fake.saveOrgGroupsMutex.RLock()
+	defer fake.saveOrgGroupsMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -39,13 +41,21 @@ func LoadFileBytes(path string) ([]byte, error) {
 	return ioutil.ReadFile(path)
 }
 
-//LoadFile -
+//LoadFile - reads a yaml or json config file, merging in the fragments it includes
 func LoadFile(configFile string, dataType interface{}) error {
 	var data []byte
 	data, err := ioutil.ReadFile(configFile)
 	if err != nil {
 		return err
 	}
+	if isJSON(configFile) {
+		if data, err = yamlFromJSON(data); err != nil {
+			return fmt.Errorf("%s is not valid json: %s", configFile, err.Error())
+		}
+	}
+	if data, err = resolveIncludes(configFile, data); err != nil {
+		return err
+	}
 	return yaml.Unmarshal(data, dataType)
 }
 
@@ -54,11 +64,64 @@ func WriteFileBytes(configFile string, data []byte) error {
 	return ioutil.WriteFile(configFile, data, 0755)
 }
 
-//WriteFile -
+//WriteFile - writes a yaml config file, or a json one when configFile ends with .json, without what
+//the fragments it includes set
 func WriteFile(configFile string, dataType interface{}) error {
 	data, err := yaml.Marshal(dataType)
 	if err != nil {
 		return err
 	}
+	if data, err = withoutIncludedContents(configFile, data); err != nil {
+		return err
+	}
+	if isJSON(configFile) {
+		if data, err = jsonFromYAML(data); err != nil {
+			return err
+		}
+	}
 	return WriteFileBytes(configFile, data)
 }
+
+func isJSON(configFile string) bool {
+	return strings.EqualFold(filepath.Ext(configFile), ".json")
+}
+
+// yamlFromJSON converts json to yaml, so that a json config file is read with
+// the yaml keys of the config structs
+func yamlFromJSON(data []byte) ([]byte, error) {
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(document)
+}
+
+func jsonFromYAML(data []byte) ([]byte, error) {
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(jsonValue(document), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// jsonValue replaces the maps of a yaml document, keyed by interface{}, with
+// maps json can encode
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[fmt.Sprint(key)] = jsonValue(item)
+		}
+		return result
+	case []interface{}:
+		for i, item := range v {
+			v[i] = jsonValue(item)
+		}
+	}
+	return value
+}
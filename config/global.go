@@ -1,9 +1,184 @@
 package config
 
+import (
+	"fmt"
+	"strings"
+)
+
 // GlobalConfig configuration for global settings
 type GlobalConfig struct {
-	EnableDeleteIsolationSegments bool     `yaml:"enable-delete-isolation-segments"`
-	EnableUnassignSecurityGroups  bool     `yaml:"enable-unassign-security-groups"`
-	RunningSecurityGroups         []string `yaml:"running-security-groups"`
-	StagingSecurityGroups         []string `yaml:"staging-security-groups"`
+	EnableDeleteIsolationSegments bool          `yaml:"enable-delete-isolation-segments"`
+	EnableUnassignSecurityGroups  bool          `yaml:"enable-unassign-security-groups"`
+	RunningSecurityGroups         []string      `yaml:"running-security-groups"`
+	StagingSecurityGroups         []string      `yaml:"staging-security-groups"`
+	RemoveRunningSecurityGroups   []string      `yaml:"remove-running-security-groups,omitempty"`
+	RemoveStagingSecurityGroups   []string      `yaml:"remove-staging-security-groups,omitempty"`
+	ASGEndpoints                  []ASGEndpoint `yaml:"asg-endpoints,omitempty"`
+	ASGEndpointTTL                int           `yaml:"asg-endpoint-ttl,omitempty"`
+	CreateInternalUsers           *UserCreation `yaml:"create-internal-users,omitempty"`
+	RoleGroups                    []RoleGroup   `yaml:"role-groups,omitempty"`
+	// FailOnUserCreationErrors fails update-org-users and update-space-users,
+	// after every org or space is updated, when a saml user could not be created
+	FailOnUserCreationErrors bool `yaml:"fail-on-user-creation-errors,omitempty"`
+	// EnforceInternalRoutes deletes the routes on internal domains of the
+	// managed spaces that do not set allow-internal-routes
+	EnforceInternalRoutes bool `yaml:"enforce-internal-routes,omitempty"`
+	// EnforceDockerPolicy stops the started docker apps of the managed spaces
+	// whose org and space do not set allow-docker
+	EnforceDockerPolicy bool `yaml:"enforce-docker-policy,omitempty"`
+	// EnforceStackPolicy fails apply when apps of the managed spaces run on a
+	// stack their org does not allow
+	EnforceStackPolicy bool `yaml:"enforce-stack-policy,omitempty"`
+	// QuotaNotifications emails the org managers of the orgs whose usage
+	// breaches a threshold of their quota-alerts when quota-report --notify runs
+	QuotaNotifications *EmailDelivery `yaml:"quota-notifications,omitempty"`
+	// TokenPolicy is the token policy of the default uaa identity zone
+	TokenPolicy *TokenPolicy `yaml:"token-policy,omitempty"`
+	// AttributeRoles adds to the message of each role granted by
+	// update-org-users and update-space-users where the user comes from, such
+	// as an ldap group, and the configuration file of the org or space
+	AttributeRoles bool `yaml:"attribute-roles,omitempty"`
+	// AdminUsers are the users allowed admin-equivalent access, which
+	// admin-access-report does not report
+	AdminUsers []string `yaml:"admin-users,omitempty"`
+	// AdminGroups are uaa groups reported by admin-access-report in addition
+	// to the admin-equivalent groups it always checks
+	AdminGroups []string `yaml:"admin-groups,omitempty"`
+	// EgressAnnotations writes a summary of the egress allowed to each managed
+	// space, and org, as its cf-mgmt.io/egress metadata annotation
+	EgressAnnotations bool `yaml:"egress-annotations,omitempty"`
+	// Plugins are executables apply runs before and after its steps
+	Plugins []Plugin `yaml:"plugins,omitempty"`
+	// CommandHooks are shell commands and webhooks run before and after commands
+	CommandHooks []CommandHook `yaml:"command-hooks,omitempty"`
+	// SpaceQuotaTolerance is how many percent the space quotas of an org may
+	// add up to beyond its org quota before validate-config and quota-report
+	// --overcommit flag the org
+	SpaceQuotaTolerance int `yaml:"space-quota-tolerance,omitempty"`
+	// ServiceKeyRotation is the opt-in policy of rotate-service-keys
+	ServiceKeyRotation *ServiceKeyRotation `yaml:"service-key-rotation,omitempty"`
+	// RunHistory is where --record-history records runs and run-history reads them
+	RunHistory *RunHistory `yaml:"run-history,omitempty"`
+
+	// Include lists the fragments merged into the file
+	Include []Include `yaml:"include,omitempty"`
+}
+
+// RoleGroup keeps a uaa group in sync with the users of an org or space role,
+// so that tools keyed on uaa groups can reuse the roles cf-mgmt manages. Group
+// names the uaa group, {org} and {space} are replaced with the names of each
+// org and space, such as grafana.org.{org}.viewer.
+type RoleGroup struct {
+	Role  string `yaml:"role"`
+	Group string `yaml:"group"`
+}
+
+// Placeholders of the group of a RoleGroup.
+const (
+	OrgPlaceholder   = "{org}"
+	SpacePlaceholder = "{space}"
+)
+
+// IsSpaceRole returns whether the role group mirrors a space role.
+func (r RoleGroup) IsSpaceRole() bool {
+	return r.Role == RoleSpaceDeveloper || r.Role == RoleSpaceManager || r.Role == RoleSpaceAuditor
+}
+
+// GroupName returns the name of the group for an org, and for a space when
+// the role group mirrors a space role.
+func (r RoleGroup) GroupName(org, space string) string {
+	return strings.NewReplacer(OrgPlaceholder, org, SpacePlaceholder, space).Replace(r.Group)
+}
+
+// validate requires the group of each org to be distinct, and of each space
+// for space roles, so that no two orgs or spaces share a group.
+func (r RoleGroup) validate() error {
+	switch r.Role {
+	case RoleOrgManager, RoleOrgBillingManager, RoleOrgAuditor:
+		if !strings.Contains(r.Group, OrgPlaceholder) {
+			return fmt.Errorf("group [%s] of role-groups must contain %s", r.Group, OrgPlaceholder)
+		}
+		if strings.Contains(r.Group, SpacePlaceholder) {
+			return fmt.Errorf("group [%s] of org role %s cannot contain %s", r.Group, r.Role, SpacePlaceholder)
+		}
+	case RoleSpaceDeveloper, RoleSpaceManager, RoleSpaceAuditor:
+		if !strings.Contains(r.Group, OrgPlaceholder) || !strings.Contains(r.Group, SpacePlaceholder) {
+			return fmt.Errorf("group [%s] of space role %s must contain %s and %s", r.Group, r.Role, OrgPlaceholder, SpacePlaceholder)
+		}
+	default:
+		return fmt.Errorf("role [%s] of role-groups is not one of %s", r.Role, strings.Join([]string{
+			RoleOrgManager, RoleOrgBillingManager, RoleOrgAuditor, RoleSpaceDeveloper, RoleSpaceManager, RoleSpaceAuditor,
+		}, ", "))
+	}
+	return nil
+}
+
+// Ways the generated password of a created internal user is delivered.
+const (
+	DeliverCredHub = "credhub"
+	DeliverEmail   = "email"
+)
+
+// UserCreation creates internal users that are in the configuration but not
+// in uaa, for foundations without single sign on, with a generated password
+// that is delivered by email or written to credhub.
+type UserCreation struct {
+	Delivery string          `yaml:"delivery"`
+	CredHub  CredHubDelivery `yaml:"credhub,omitempty"`
+	Email    EmailDelivery   `yaml:"email,omitempty"`
+
+	// PlaceholderEmailDomain is the domain of the <username>@<domain> email
+	// given to created users whose username is not an email, which uaa
+	// requires, and defaults to DefaultPlaceholderEmailDomain
+	PlaceholderEmailDomain string `yaml:"placeholder-email-domain,omitempty"`
+}
+
+// DefaultPlaceholderEmailDomain is a reserved domain, so placeholder emails are
+// never delivered.
+const DefaultPlaceholderEmailDomain = "cf-mgmt.invalid"
+
+// PlaceholderEmail is the email of a created user whose username is not an
+// email.
+func (c *UserCreation) PlaceholderEmail(userName string) string {
+	domain := c.PlaceholderEmailDomain
+	if domain == "" {
+		domain = DefaultPlaceholderEmailDomain
+	}
+	return userName + "@" + domain
+}
+
+// CredHubDelivery writes passwords to credhub as <path>/<username>. The
+// client secret is read from the CREDHUB_CLIENT_SECRET environment variable.
+type CredHubDelivery struct {
+	URL               string `yaml:"url"`
+	ClientID          string `yaml:"client-id"`
+	Path              string `yaml:"path"`
+	SkipSSLValidation bool   `yaml:"skip-ssl-validation,omitempty"`
+}
+
+// RunHistory records the last run and the last successful run as the
+// cf-mgmt.io/last-run and cf-mgmt.io/last-successful-run annotations of Org,
+// an org of the foundation designated to hold them.
+type RunHistory struct {
+	Org string `yaml:"org"`
+}
+
+// ServiceKeyRotation reports the service keys of the managed spaces older
+// than MaxAgeDays, and recreates those of the spaces that set
+// rotate-service-keys, writing the credentials of each new key to credhub as
+// <path>/<org>/<space>/<instance>/<key>.
+type ServiceKeyRotation struct {
+	MaxAgeDays int             `yaml:"max-age-days"`
+	CredHub    CredHubDelivery `yaml:"credhub,omitempty"`
+}
+
+// EmailDelivery emails users whose username is an email, such as the
+// passwords of created users. The smtp password, if the server requires one,
+// is read from the SMTP_PASSWORD environment variable.
+type EmailDelivery struct {
+	Host     string `yaml:"smtp-host"`
+	Port     int    `yaml:"smtp-port"`
+	Username string `yaml:"smtp-username,omitempty"`
+	From     string `yaml:"from"`
+	Subject  string `yaml:"subject,omitempty"`
 }
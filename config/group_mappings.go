@@ -0,0 +1,113 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Roles a group can be mapped to in group-mappings.yml, named after the role
+// blocks of orgConfig.yml and spaceConfig.yml.
+const (
+	RoleOrgManager        = "org-manager"
+	RoleOrgBillingManager = "org-billingmanager"
+	RoleOrgAuditor        = "org-auditor"
+	RoleSpaceDeveloper    = "space-developer"
+	RoleSpaceManager      = "space-manager"
+	RoleSpaceAuditor      = "space-auditor"
+)
+
+// GroupMappings maps ldap groups to roles across orgs and spaces in one
+// place, read from group-mappings.yml.
+type GroupMappings struct {
+	Mappings []GroupMapping `yaml:"group-mappings"`
+
+	// file is the config file the mappings were read from
+	file string
+}
+
+// GroupMapping gives the members of a group a role in each of its targets.
+type GroupMapping struct {
+	Group   string        `yaml:"group"`
+	Targets []GroupTarget `yaml:"targets"`
+}
+
+// GroupTarget is a role in an org, or in a space when space is set.
+type GroupTarget struct {
+	Org   string `yaml:"org"`
+	Space string `yaml:"space,omitempty"`
+	Role  string `yaml:"role"`
+}
+
+func (m *yamlManager) groupMappings() (*GroupMappings, error) {
+	fp := path.Join(m.ConfigDir, "group-mappings.yml")
+	mappings := &GroupMappings{}
+	if !FileOrDirectoryExists(fp) {
+		return mappings, nil
+	}
+	if err := LoadFile(fp, mappings); err != nil {
+		return nil, err
+	}
+	if err := mappings.validate(); err != nil {
+		return nil, err
+	}
+	mappings.file = m.sourceFile(fp)
+	return mappings, nil
+}
+
+func (g *GroupMappings) validate() error {
+	for _, mapping := range g.Mappings {
+		if mapping.Group == "" {
+			return fmt.Errorf("group mapping in group-mappings.yml is missing its group")
+		}
+		for _, target := range mapping.Targets {
+			if target.Org == "" {
+				return fmt.Errorf("target of group [%s] in group-mappings.yml is missing its org", mapping.Group)
+			}
+			switch target.Role {
+			case RoleOrgManager, RoleOrgBillingManager, RoleOrgAuditor:
+				if target.Space != "" {
+					return fmt.Errorf("org role %s of group [%s] in group-mappings.yml cannot have a space", target.Role, mapping.Group)
+				}
+			case RoleSpaceDeveloper, RoleSpaceManager, RoleSpaceAuditor:
+				if target.Space == "" {
+					return fmt.Errorf("space role %s of group [%s] in group-mappings.yml requires a space", target.Role, mapping.Group)
+				}
+			default:
+				return fmt.Errorf("role [%s] of group [%s] in group-mappings.yml is not an org or space role", target.Role, mapping.Group)
+			}
+		}
+	}
+	return nil
+}
+
+// groups returns the groups mapped to the role in the org, or the space of
+// the org when space is set.
+func (g *GroupMappings) groups(org, space, role string) []string {
+	var groups []string
+	for _, mapping := range g.Mappings {
+		for _, target := range mapping.Targets {
+			if strings.EqualFold(target.Org, org) && strings.EqualFold(target.Space, space) && target.Role == role {
+				groups = append(groups, mapping.Group)
+			}
+		}
+	}
+	return groups
+}
+
+func (g *GroupMappings) applyToOrg(orgConfig *OrgConfig) {
+	g.apply(&orgConfig.Manager, g.groups(orgConfig.Org, "", RoleOrgManager))
+	g.apply(&orgConfig.BillingManager, g.groups(orgConfig.Org, "", RoleOrgBillingManager))
+	g.apply(&orgConfig.Auditor, g.groups(orgConfig.Org, "", RoleOrgAuditor))
+}
+
+func (g *GroupMappings) applyToSpace(spaceConfig *SpaceConfig) {
+	g.apply(&spaceConfig.Developer, g.groups(spaceConfig.Org, spaceConfig.Space, RoleSpaceDeveloper))
+	g.apply(&spaceConfig.Manager, g.groups(spaceConfig.Org, spaceConfig.Space, RoleSpaceManager))
+	g.apply(&spaceConfig.Auditor, g.groups(spaceConfig.Org, spaceConfig.Space, RoleSpaceAuditor))
+}
+
+func (g *GroupMappings) apply(role *UserMgmt, groups []string) {
+	role.inherit(g.file, groups...)
+	role.LDAPGroups = append(role.LDAPGroups, groups...)
+}
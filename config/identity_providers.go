@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Types of the uaa identity providers cf-mgmt manages.
+const (
+	IdentityProviderSAML = "saml"
+	IdentityProviderOIDC = "oidc1.0"
+)
+
+// IdentityProviders lists the saml and oidc identity providers of the uaa,
+// read from identity-providers.yml.
+type IdentityProviders struct {
+	// DeleteUnmanaged deletes the saml and oidc identity providers of the uaa
+	// that are not listed, along with the users of their origin
+	DeleteUnmanaged bool               `yaml:"delete-unmanaged"`
+	Providers       []IdentityProvider `yaml:"identity-providers"`
+}
+
+// IdentityProvider is a saml or oidc identity provider, users log in through
+// the provider of their origin.
+type IdentityProvider struct {
+	Origin string `yaml:"origin"`
+	Name   string `yaml:"name"`
+	Type   string `yaml:"type"`
+	// Inactive keeps the provider but stops users logging in through it
+	Inactive bool `yaml:"inactive,omitempty"`
+	// EmailDomains routes users whose email is in one of these domains to the
+	// provider when they enter their email at login
+	EmailDomains []string `yaml:"email-domains,omitempty"`
+	// AttributeMappings maps uaa user attributes, such as email or
+	// external_groups, to the attributes of the provider's assertions or claims
+	AttributeMappings map[string]string `yaml:"attribute-mappings,omitempty"`
+	// Metadata is the url or the xml of the saml metadata of the provider
+	Metadata string `yaml:"metadata,omitempty"`
+	NameID   string `yaml:"name-id,omitempty"`
+	// DiscoveryURL is the openid configuration url of an oidc provider
+	DiscoveryURL string   `yaml:"discovery-url,omitempty"`
+	ClientID     string   `yaml:"client-id,omitempty"`
+	Scopes       []string `yaml:"scopes,omitempty"`
+	// ClientSecretEnv names the environment variable the oidc client secret
+	// is read from, so that it is not committed with the configuration
+	ClientSecretEnv string `yaml:"client-secret-env,omitempty"`
+}
+
+func (i *IdentityProviders) validate() error {
+	origins := make(map[string]bool)
+	for _, provider := range i.Providers {
+		if provider.Origin == "" {
+			return fmt.Errorf("origin is required for identity providers in identity-providers.yml")
+		}
+		if strings.EqualFold(provider.Origin, "uaa") || strings.EqualFold(provider.Origin, "ldap") {
+			return fmt.Errorf("identity provider [%s] in identity-providers.yml cannot be managed, only saml and oidc providers can", provider.Origin)
+		}
+		if origins[provider.Origin] {
+			return fmt.Errorf("identity provider [%s] is listed more than once in identity-providers.yml", provider.Origin)
+		}
+		origins[provider.Origin] = true
+		switch provider.Type {
+		case IdentityProviderSAML:
+			if provider.Metadata == "" {
+				return fmt.Errorf("saml identity provider [%s] in identity-providers.yml requires metadata", provider.Origin)
+			}
+		case IdentityProviderOIDC:
+			if provider.DiscoveryURL == "" || provider.ClientID == "" {
+				return fmt.Errorf("oidc identity provider [%s] in identity-providers.yml requires discovery-url and client-id", provider.Origin)
+			}
+		default:
+			return fmt.Errorf("type [%s] of identity provider [%s] in identity-providers.yml must be %s or %s", provider.Type, provider.Origin, IdentityProviderSAML, IdentityProviderOIDC)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// includeKey lists the fragments merged into a config file
+const includeKey = "include"
+
+// Include is a config fragment shared by several foundations, fetched from
+// url and pinned to the sha256 checksum of its contents.
+type Include struct {
+	URL    string `yaml:"url"`
+	SHA256 string `yaml:"sha256"`
+}
+
+var (
+	fragmentClient = &http.Client{Timeout: 30 * time.Second}
+	fragmentMutex  sync.Mutex
+	// fragments caches fetched fragments by url and checksum, as config files are read many times a run
+	fragments = make(map[Include][]byte)
+)
+
+// resolveIncludes merges the fragments listed by include into the config file
+// data. What the file sets itself takes precedence over its fragments, lists
+// are combined with the items of the fragments the file does not list. The
+// include itself is kept, so that a config file written back keeps it.
+func resolveIncludes(configFile string, data []byte) ([]byte, error) {
+	document := make(map[interface{}]interface{})
+	includes, err := findIncludes(configFile, data, &document)
+	if err != nil || len(includes) == 0 {
+		return data, err
+	}
+	for _, include := range includes {
+		fragment, err := fetchMapFragment(configFile, include)
+		if err != nil {
+			return nil, err
+		}
+		mergeFragment(document, fragment)
+	}
+	return yaml.Marshal(document)
+}
+
+// withoutIncludedContents removes from the yaml data of a config file what
+// the fragments it includes set, so that a config file read with its
+// fragments merged in is written back with its include instead of a copy of
+// the fragments.
+func withoutIncludedContents(configFile string, data []byte) ([]byte, error) {
+	document := yaml.MapSlice{}
+	includes, err := findIncludes(configFile, data, &document)
+	if err != nil || len(includes) == 0 {
+		return data, err
+	}
+	for _, include := range includes {
+		fragment, err := fetchMapFragment(configFile, include)
+		if err != nil {
+			return nil, err
+		}
+		document = removeFragment(document, fragment)
+	}
+	return yaml.Marshal(document)
+}
+
+// resolveASGIncludes resolves a security group json file that includes its
+// rules from fragments, such as
+// {"include": [{"url": "...", "sha256": "..."}], "rules": [...]}, into the
+// json array of its own rules followed by the rules of the fragments it does
+// not list. A fragment is a json array of rules.
+func resolveASGIncludes(asgFile string, data []byte) ([]byte, error) {
+	if !strings.Contains(string(data), includeKey) {
+		return data, nil
+	}
+	document := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &document); err != nil {
+		return data, nil
+	}
+	raw, ok := document[includeKey]
+	if !ok {
+		return data, nil
+	}
+	var includeList interface{}
+	if err := yaml.Unmarshal(raw, &includeList); err != nil {
+		return nil, fmt.Errorf("include of %s: %s", asgFile, err.Error())
+	}
+	includes, err := parseIncludes(includeList)
+	if err != nil {
+		return nil, fmt.Errorf("include of %s: %s", asgFile, err.Error())
+	}
+	rules := []interface{}{}
+	if ownRules, ok := document["rules"]; ok {
+		if err := json.Unmarshal(ownRules, &rules); err != nil {
+			return nil, fmt.Errorf("rules of %s must be a json array: %s", asgFile, err.Error())
+		}
+	}
+	for _, include := range includes {
+		contents, err := fetchFragment(include)
+		if err != nil {
+			return nil, fmt.Errorf("include of %s: %s", asgFile, err.Error())
+		}
+		var fragmentRules []interface{}
+		if err := json.Unmarshal(contents, &fragmentRules); err != nil {
+			return nil, fmt.Errorf("include of %s: %s is not a json array of rules: %s", asgFile, include.URL, err.Error())
+		}
+		rules = appendMissingItems(rules, fragmentRules)
+	}
+	return json.MarshalIndent(rules, "", "  ")
+}
+
+// findIncludes unmarshals the yaml data of a config file into document and
+// returns the fragments it includes
+func findIncludes(configFile string, data []byte, document interface{}) ([]Include, error) {
+	if !strings.Contains(string(data), includeKey) {
+		return nil, nil
+	}
+	if err := yaml.Unmarshal(data, document); err != nil {
+		return nil, nil
+	}
+	var raw interface{}
+	switch d := document.(type) {
+	case *map[interface{}]interface{}:
+		raw = (*d)[includeKey]
+	case *yaml.MapSlice:
+		for _, item := range *d {
+			if item.Key == includeKey {
+				raw = item.Value
+			}
+		}
+	}
+	if raw == nil {
+		return nil, nil
+	}
+	includes, err := parseIncludes(raw)
+	if err != nil {
+		return nil, fmt.Errorf("include of %s: %s", configFile, err.Error())
+	}
+	return includes, nil
+}
+
+func fetchMapFragment(configFile string, include Include) (map[interface{}]interface{}, error) {
+	contents, err := fetchFragment(include)
+	if err != nil {
+		return nil, fmt.Errorf("include of %s: %s", configFile, err.Error())
+	}
+	fragment := make(map[interface{}]interface{})
+	if err := yaml.Unmarshal(contents, &fragment); err != nil {
+		return nil, fmt.Errorf("include of %s: %s is not a yaml map: %s", configFile, include.URL, err.Error())
+	}
+	if _, ok := fragment[includeKey]; ok {
+		return nil, fmt.Errorf("include of %s: %s cannot include other fragments", configFile, include.URL)
+	}
+	return fragment, nil
+}
+
+func parseIncludes(raw interface{}) ([]Include, error) {
+	data, err := yaml.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var includes []Include
+	if err := yaml.Unmarshal(data, &includes); err != nil {
+		return nil, fmt.Errorf("must be a list of url and sha256")
+	}
+	for _, include := range includes {
+		fragmentURL, err := url.Parse(include.URL)
+		if err != nil || (fragmentURL.Scheme != "https" && fragmentURL.Scheme != "http") {
+			return nil, fmt.Errorf("url [%s] must be an http or https url", include.URL)
+		}
+		if include.SHA256 == "" {
+			return nil, fmt.Errorf("%s must be pinned with its sha256 checksum", include.URL)
+		}
+	}
+	return includes, nil
+}
+
+func fetchFragment(include Include) ([]byte, error) {
+	fragmentMutex.Lock()
+	defer fragmentMutex.Unlock()
+	if contents, ok := fragments[include]; ok {
+		return contents, nil
+	}
+	resp, err := fragmentClient.Get(include.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	contents, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %d", include.URL, resp.StatusCode)
+	}
+	sum := sha256.Sum256(contents)
+	if checksum := hex.EncodeToString(sum[:]); !strings.EqualFold(checksum, include.SHA256) {
+		return nil, fmt.Errorf("%s has checksum %s instead of the pinned %s", include.URL, checksum, include.SHA256)
+	}
+	fragments[include] = contents
+	return contents, nil
+}
+
+// mergeFragment adds what fragment sets that document does not
+func mergeFragment(document, fragment map[interface{}]interface{}) {
+	for key, value := range fragment {
+		existing, ok := document[key]
+		if !ok || existing == nil {
+			document[key] = value
+			continue
+		}
+		switch existingValue := existing.(type) {
+		case map[interface{}]interface{}:
+			if fragmentValue, ok := value.(map[interface{}]interface{}); ok {
+				mergeFragment(existingValue, fragmentValue)
+			}
+		case []interface{}:
+			if fragmentValue, ok := value.([]interface{}); ok {
+				document[key] = appendMissingItems(existingValue, fragmentValue)
+			}
+		}
+	}
+}
+
+// removeFragment removes from document what fragment sets, the values that
+// are the same and the items of lists that the fragment lists, keeping the
+// order of the keys of document
+func removeFragment(document yaml.MapSlice, fragment map[interface{}]interface{}) yaml.MapSlice {
+	result := yaml.MapSlice{}
+	for _, item := range document {
+		fragmentValue, ok := fragment[item.Key]
+		if !ok || item.Key == includeKey {
+			result = append(result, item)
+			continue
+		}
+		switch value := item.Value.(type) {
+		case yaml.MapSlice:
+			if fragmentMap, ok := fragmentValue.(map[interface{}]interface{}); ok {
+				if item.Value = removeFragment(value, fragmentMap); len(item.Value.(yaml.MapSlice)) == 0 {
+					continue
+				}
+			}
+		case []interface{}:
+			if fragmentList, ok := fragmentValue.([]interface{}); ok {
+				var remaining []interface{}
+				for _, listItem := range value {
+					if !containsItem(fragmentList, plainValue(listItem)) {
+						remaining = append(remaining, listItem)
+					}
+				}
+				if len(remaining) == 0 {
+					continue
+				}
+				item.Value = remaining
+			}
+		default:
+			if reflect.DeepEqual(item.Value, fragmentValue) {
+				continue
+			}
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// plainValue replaces the ordered maps of a yaml value with the maps its
+// fragments are read into
+func plainValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		result := make(map[interface{}]interface{}, len(v))
+		for _, item := range v {
+			result[item.Key] = plainValue(item.Value)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = plainValue(item)
+		}
+		return result
+	}
+	return value
+}
+
+func containsItem(list []interface{}, item interface{}) bool {
+	for _, existing := range list {
+		if reflect.DeepEqual(existing, item) {
+			return true
+		}
+	}
+	return false
+}
+
+func appendMissingItems(list, items []interface{}) []interface{} {
+	for _, item := range items {
+		if !containsItem(list, item) {
+			list = append(list, item)
+		}
+	}
+	return list
+}
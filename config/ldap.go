@@ -1,11 +1,28 @@
 package config
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
 //Config -
 type LdapConfig struct {
-	Enabled           bool   `yaml:"enabled"`
-	LdapHost          string `yaml:"ldapHost"`
-	LdapPort          int    `yaml:"ldapPort"`
-	TLS               bool   `yaml:"use_tls"`
+	Enabled       bool   `yaml:"enabled"`
+	LdapHost      string `yaml:"ldapHost"`
+	LdapPort      int    `yaml:"ldapPort"`
+	TLS           bool   `yaml:"use_tls"`
+	StartTLS      bool   `yaml:"use_start_tls,omitempty"`
+	TLSMinVersion string `yaml:"tls_min_version,omitempty"`
+	TLSCACert     string `yaml:"tls_ca_cert,omitempty"`
+	TLSClientCert string `yaml:"tls_client_cert,omitempty"`
+	TLSClientKey  string `yaml:"tls_client_key,omitempty"`
+
+	KerberosKeytab    string `yaml:"kerberos_keytab,omitempty"`
+	KerberosPrincipal string `yaml:"kerberos_principal,omitempty"`
+	KerberosRealm     string `yaml:"kerberos_realm,omitempty"`
+	KerberosConfig    string `yaml:"kerberos_config,omitempty"`
+	KerberosSPN       string `yaml:"kerberos_spn,omitempty"`
 	BindDN            string `yaml:"bindDN"`
 	BindPassword      string `yaml:"bindPwd,omitempty"`
 	UserSearchBase    string `yaml:"userSearchBase"`
@@ -15,4 +32,78 @@ type LdapConfig struct {
 	GroupSearchBase   string `yaml:"groupSearchBase"`
 	GroupAttribute    string `yaml:"groupAttribute"`
 	Origin            string `yaml:"origin"`
+	// GroupLookupConcurrency is how many groups are looked up at a time, 4 when not set
+	GroupLookupConcurrency int `yaml:"group_lookup_concurrency,omitempty"`
+
+	UserNameMapping       *UserNameMapping           `yaml:"userNameMapping,omitempty"`
+	GroupUserNameMappings map[string]UserNameMapping `yaml:"groupUserNameMappings,omitempty"`
+}
+
+// Transformations that can be applied to the value of a user name mapping attribute.
+const (
+	TransformLowercase   = "lowercase"
+	TransformStripDomain = "strip-domain"
+)
+
+// UserNameMapping selects the ldap attribute whose value becomes the cf
+// username and the transformations applied to it, such as sAMAccountName,
+// mail or userPrincipalName depending on how the origin maps usernames.
+type UserNameMapping struct {
+	Attribute  string   `yaml:"attribute"`
+	Transforms []string `yaml:"transforms,omitempty"`
+}
+
+// UserName applies the transformations of the mapping to an attribute value.
+func (m *UserNameMapping) UserName(value string) (string, error) {
+	for _, transform := range m.Transforms {
+		switch transform {
+		case TransformLowercase:
+			value = strings.ToLower(value)
+		case TransformStripDomain:
+			if i := strings.LastIndex(value, "@"); i >= 0 {
+				value = value[:i]
+			}
+			if i := strings.LastIndex(value, "\\"); i >= 0 {
+				value = value[i+1:]
+			}
+		default:
+			return "", fmt.Errorf("unknown user name transform [%s], must be one of %s, %s", transform, TransformLowercase, TransformStripDomain)
+		}
+	}
+	return value, nil
+}
+
+// UserNameMappingForGroup returns the user name mapping for members of an ldap
+// group, falling back to the mapping of the origin, or nil when usernames are
+// derived from the origin as before.
+func (c *LdapConfig) UserNameMappingForGroup(groupName string) *UserNameMapping {
+	if mapping, ok := c.GroupUserNameMappings[groupName]; ok {
+		return &mapping
+	}
+	return c.UserNameMapping
+}
+
+func (c *LdapConfig) validateUserNameMappings() error {
+	mappings := make(map[string]UserNameMapping)
+	if c.UserNameMapping != nil {
+		mappings["userNameMapping"] = *c.UserNameMapping
+	}
+	for groupName, mapping := range c.GroupUserNameMappings {
+		mappings[fmt.Sprintf("groupUserNameMappings[%s]", groupName)] = mapping
+	}
+	names := make([]string, 0, len(mappings))
+	for name := range mappings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		mapping := mappings[name]
+		if mapping.Attribute == "" {
+			return fmt.Errorf("%s in ldap.yml requires an attribute", name)
+		}
+		if _, err := mapping.UserName(""); err != nil {
+			return fmt.Errorf("%s in ldap.yml: %v", name, err)
+		}
+	}
+	return nil
 }
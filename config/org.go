@@ -1,33 +1,99 @@
 package config
 
-import "strings"
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultMaintenanceWindowMinutes is how long a maintenance window lasts when
+// maintenance-window-minutes is not set.
+const DefaultMaintenanceWindowMinutes = 60
 
 // OrgConfig describes configuration for an org.
 type OrgConfig struct {
-	Org                        string   `yaml:"org"`
-	BillingManagerGroup        string   `yaml:"org-billingmanager-group,omitempty"`
-	ManagerGroup               string   `yaml:"org-manager-group,omitempty"`
-	AuditorGroup               string   `yaml:"org-auditor-group,omitempty"`
-	BillingManager             UserMgmt `yaml:"org-billingmanager"`
-	Manager                    UserMgmt `yaml:"org-manager"`
-	Auditor                    UserMgmt `yaml:"org-auditor"`
-	PrivateDomains             []string `yaml:"private-domains"`
-	RemovePrivateDomains       bool     `yaml:"enable-remove-private-domains"`
-	SharedPrivateDomains       []string `yaml:"shared-private-domains"`
-	RemoveSharedPrivateDomains bool     `yaml:"enable-remove-shared-private-domains"`
-	EnableOrgQuota             bool     `yaml:"enable-org-quota"`
-	MemoryLimit                int      `yaml:"memory-limit"`
-	InstanceMemoryLimit        int      `yaml:"instance-memory-limit"`
-	TotalRoutes                int      `yaml:"total-routes"`
-	TotalServices              int      `yaml:"total-services"`
-	PaidServicePlansAllowed    bool     `yaml:"paid-service-plans-allowed"`
-	RemoveUsers                bool     `yaml:"enable-remove-users"`
-	TotalPrivateDomains        int      `yaml:"total_private_domains"`
-	TotalReservedRoutePorts    int      `yaml:"total_reserved_route_ports"`
-	TotalServiceKeys           int      `yaml:"total_service_keys"`
-	AppInstanceLimit           int      `yaml:"app_instance_limit"`
-	AppTaskLimit               int      `yaml:"app_task_limit"`
-	DefaultIsoSegment          string   `yaml:"default_isolation_segment"`
+	Org                        string                `yaml:"org"`
+	BillingManagerGroup        string                `yaml:"org-billingmanager-group,omitempty"`
+	ManagerGroup               string                `yaml:"org-manager-group,omitempty"`
+	AuditorGroup               string                `yaml:"org-auditor-group,omitempty"`
+	BillingManager             UserMgmt              `yaml:"org-billingmanager"`
+	Manager                    UserMgmt              `yaml:"org-manager"`
+	Auditor                    UserMgmt              `yaml:"org-auditor"`
+	PrivateDomains             []string              `yaml:"private-domains"`
+	RemovePrivateDomains       bool                  `yaml:"enable-remove-private-domains"`
+	SharedPrivateDomains       []string              `yaml:"shared-private-domains"`
+	RemoveSharedPrivateDomains bool                  `yaml:"enable-remove-shared-private-domains"`
+	PrivateDomainSharing       map[string][]string   `yaml:"private-domain-sharing,omitempty"`
+	EnableOrgQuota             bool                  `yaml:"enable-org-quota"`
+	MemoryLimit                int                   `yaml:"memory-limit"`
+	InstanceMemoryLimit        int                   `yaml:"instance-memory-limit"`
+	TotalRoutes                int                   `yaml:"total-routes"`
+	TotalServices              int                   `yaml:"total-services"`
+	PaidServicePlansAllowed    bool                  `yaml:"paid-service-plans-allowed"`
+	RemoveUsers                bool                  `yaml:"enable-remove-users"`
+	TotalPrivateDomains        int                   `yaml:"total_private_domains"`
+	TotalReservedRoutePorts    int                   `yaml:"total_reserved_route_ports"`
+	TotalServiceKeys           int                   `yaml:"total_service_keys"`
+	AppInstanceLimit           int                   `yaml:"app_instance_limit"`
+	AppTaskLimit               int                   `yaml:"app_task_limit"`
+	DefaultIsoSegment          string                `yaml:"default_isolation_segment"`
+	ASGProfiles                map[string]ASGProfile `yaml:"asg-profiles,omitempty"`
+	DefaultASGProfile          string                `yaml:"default-asg-profile,omitempty"`
+	MaintenanceWindow          string                `yaml:"maintenance-window,omitempty"`
+	MaintenanceWindowMinutes   int                   `yaml:"maintenance-window-minutes,omitempty"`
+	ExcludeUsers               []string              `yaml:"exclude-users,omitempty"`
+	AllSpaces                  *SpaceRoles           `yaml:"all-spaces,omitempty"`
+	AllowDocker                bool                  `yaml:"allow-docker,omitempty"`
+	DefaultStack               string                `yaml:"default-stack,omitempty"`
+	AllowedStacks              []string              `yaml:"allowed-stacks,omitempty"`
+	QuotaAlerts                *QuotaAlerts          `yaml:"quota-alerts,omitempty"`
+	AllowedServices            []string              `yaml:"allowed-services,omitempty"`
+	PersonalSpaces             *PersonalSpaces       `yaml:"personal-spaces,omitempty"`
+
+	// Include lists the fragments merged into the file
+	Include []Include `yaml:"include,omitempty"`
+
+	// file is the config file the org was read from
+	file string
+}
+
+// ConfigFile returns the path of the configuration file of the org, relative
+// to the config directory and, when it was read from a consolidated config
+// file, within that file.
+func (o *OrgConfig) ConfigFile() string {
+	if o.file != "" {
+		return o.file
+	}
+	return filepath.ToSlash(defaultConfigFile(o.Org, "orgConfig"))
+}
+
+// PersonalSpaces gives each member of an ldap group a space of the org named
+// after them, with the member as its space developer, such as the sandboxes
+// of a developer sandbox org.
+type PersonalSpaces struct {
+	LDAPGroup   string `yaml:"ldap_group"`
+	SpacePrefix string `yaml:"space-prefix,omitempty"`
+}
+
+// SpaceName returns the name of the personal space of a user.
+func (p *PersonalSpaces) SpaceName(userID string) string {
+	return p.SpacePrefix + strings.ToLower(userID)
+}
+
+// SpaceRoles are role blocks of an org that apply to every space of the org,
+// such as a platform SRE group auditing all spaces.
+type SpaceRoles struct {
+	Developer UserMgmt `yaml:"space-developer"`
+	Manager   UserMgmt `yaml:"space-manager"`
+	Auditor   UserMgmt `yaml:"space-auditor"`
+}
+
+// ASGProfile is a named set of ASGs defined on an org that its spaces inherit.
+type ASGProfile struct {
+	ASGs        []string `yaml:"named-security-groups"`
+	StagingASGs []string `yaml:"named-staging-security-groups"`
 }
 
 // Orgs contains cf-mgmt configuration for all orgs.
@@ -35,6 +101,50 @@ type Orgs struct {
 	Orgs             []string `yaml:"orgs"`
 	EnableDeleteOrgs bool     `yaml:"enable-delete-orgs"`
 	ProtectedOrgs    []string `yaml:"protected_orgs"`
+
+	// Include lists the fragments merged into the file
+	Include []Include `yaml:"include,omitempty"`
+}
+
+// IsProtected determines whether an org is excluded from cf-mgmt by
+// protected_orgs or DefaultProtectedOrgs.
+func (o *Orgs) IsProtected(orgName string) bool {
+	return IsProtectedOrg(orgName, o.ProtectedOrgs)
+}
+
+// IsProtectedOrg determines whether an org matches one of the protected orgs
+// or DefaultProtectedOrgs, which are always protected. Each protected org is
+// a regular expression that matches the whole org name, so that system does
+// not protect my-system-org. Protected orgs, such as system, are never
+// deleted, exported or reported as drift.
+func IsProtectedOrg(orgName string, protectedOrgs []string) bool {
+	for _, pattern := range ProtectedOrgs(protectedOrgs) {
+		if match, _ := regexp.MatchString("^(?:"+pattern+")$", orgName); match {
+			return true
+		}
+	}
+	return false
+}
+
+// ProtectedOrgs returns DefaultProtectedOrgs followed by the protected orgs
+// that are not among them.
+func ProtectedOrgs(protectedOrgs []string) []string {
+	result := append([]string{}, DefaultProtectedOrgs...)
+	for _, protectedOrg := range protectedOrgs {
+		if !containsString(result, protectedOrg) {
+			result = append(result, protectedOrg)
+		}
+	}
+	return result
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
 
 // Contains determines whether an org is present in a list of orgs.
@@ -59,3 +169,71 @@ func (o *OrgConfig) GetManagerGroups() []string {
 func (o *OrgConfig) GetAuditorGroups() []string {
 	return o.Auditor.groups(o.AuditorGroup)
 }
+
+// SpaceASGs returns the named running and staging ASGs for a space of the org.
+// A space that references an ASG profile gets the profile's ASGs in addition
+// to its own, a space that names no ASGs at all inherits the org's default
+// profile and a space that names its own ASGs overrides the default.
+func (o *OrgConfig) SpaceASGs(space *SpaceConfig) ([]string, []string, error) {
+	profileName := space.ASGProfile
+	if profileName == "" && len(space.ASGs) == 0 && len(space.StagingASGs) == 0 {
+		profileName = o.DefaultASGProfile
+	}
+	if profileName == "" {
+		return space.ASGs, space.StagingASGs, nil
+	}
+	profile, ok := o.ASGProfiles[profileName]
+	if !ok {
+		return nil, nil, fmt.Errorf("ASG profile [%s] is not defined in org [%s]", profileName, space.Org)
+	}
+	asgs := append(append([]string{}, profile.ASGs...), space.ASGs...)
+	stagingASGs := append(append([]string{}, profile.StagingASGs...), space.StagingASGs...)
+	return asgs, stagingASGs, nil
+}
+
+// InMaintenanceWindow returns whether destructive changes (user removal, space
+// deletion and quota shrinks) can be applied to the org at the given time. An
+// org without a maintenance-window is always in its window, otherwise the
+// window opens whenever the cron expression matches, in the time zone of now,
+// and lasts maintenance-window-minutes.
+func (o *OrgConfig) InMaintenanceWindow(now time.Time) (bool, error) {
+	schedule, err := o.maintenanceSchedule()
+	if err != nil {
+		return false, err
+	}
+	if schedule == nil {
+		return true, nil
+	}
+	minutes := o.MaintenanceWindowMinutes
+	if minutes <= 0 {
+		minutes = DefaultMaintenanceWindowMinutes
+	}
+	return schedule.within(now, time.Duration(minutes)*time.Minute), nil
+}
+
+func (o *OrgConfig) maintenanceSchedule() (*cronSchedule, error) {
+	if o.MaintenanceWindow == "" {
+		return nil, nil
+	}
+	schedule, err := parseCron(o.MaintenanceWindow)
+	if err != nil {
+		return nil, fmt.Errorf("maintenance-window of org [%s]: %s", o.Org, err.Error())
+	}
+	return schedule, nil
+}
+
+// OrgsOutsideMaintenanceWindow returns the names of the orgs whose destructive
+// changes are deferred at the given time.
+func OrgsOutsideMaintenanceWindow(orgConfigs []OrgConfig, now time.Time) (map[string]bool, error) {
+	deferred := make(map[string]bool)
+	for _, orgConfig := range orgConfigs {
+		inWindow, err := orgConfig.InMaintenanceWindow(now)
+		if err != nil {
+			return nil, err
+		}
+		if !inWindow {
+			deferred[orgConfig.Org] = true
+		}
+	}
+	return deferred, nil
+}
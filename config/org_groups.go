@@ -0,0 +1,221 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// OrgGroups groups orgs, such as by business unit, read from org-groups.yml.
+type OrgGroups struct {
+	Groups []OrgGroup `yaml:"org-groups"`
+
+	// file is the config file the groups were read from
+	file string
+}
+
+// OrgGroup holds the defaults inherited by its member orgs and by the orgs of
+// the groups nested under it. A group that sets parent is nested under that
+// group, its own defaults take precedence over the defaults of its parent.
+type OrgGroup struct {
+	Name              string                `yaml:"name"`
+	Parent            string                `yaml:"parent,omitempty"`
+	Orgs              []string              `yaml:"orgs,omitempty"`
+	Quota             *QuotaTier            `yaml:"quota,omitempty"`
+	ASGProfiles       map[string]ASGProfile `yaml:"asg-profiles,omitempty"`
+	DefaultASGProfile string                `yaml:"default-asg-profile,omitempty"`
+	BillingManager    UserMgmt              `yaml:"org-billingmanager,omitempty"`
+	Manager           UserMgmt              `yaml:"org-manager,omitempty"`
+	Auditor           UserMgmt              `yaml:"org-auditor,omitempty"`
+}
+
+// QuotaTier is the org quota of the orgs of a group that do not set
+// enable-org-quota themselves.
+type QuotaTier struct {
+	MemoryLimit             int  `yaml:"memory-limit"`
+	InstanceMemoryLimit     int  `yaml:"instance-memory-limit"`
+	TotalRoutes             int  `yaml:"total-routes"`
+	TotalServices           int  `yaml:"total-services"`
+	PaidServicePlansAllowed bool `yaml:"paid-service-plans-allowed"`
+	TotalPrivateDomains     int  `yaml:"total_private_domains"`
+	TotalReservedRoutePorts int  `yaml:"total_reserved_route_ports"`
+	TotalServiceKeys        int  `yaml:"total_service_keys"`
+	AppInstanceLimit        int  `yaml:"app_instance_limit"`
+	AppTaskLimit            int  `yaml:"app_task_limit"`
+}
+
+// UnmarshalYAML reads quota limits set to unlimited as -1, and defaults the
+// limits that are not set the same way orgConfig.yml does.
+func (q *QuotaTier) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain QuotaTier
+	q.AppTaskLimit = Unlimited
+	q.AppInstanceLimit = Unlimited
+	q.TotalPrivateDomains = Unlimited
+	q.TotalServiceKeys = Unlimited
+	return unmarshalQuotaLimits(unmarshal, (*plain)(q))
+}
+
+func (q *QuotaTier) applyTo(orgConfig *OrgConfig) {
+	orgConfig.EnableOrgQuota = true
+	orgConfig.MemoryLimit = q.MemoryLimit
+	orgConfig.InstanceMemoryLimit = q.InstanceMemoryLimit
+	orgConfig.TotalRoutes = q.TotalRoutes
+	orgConfig.TotalServices = q.TotalServices
+	orgConfig.PaidServicePlansAllowed = q.PaidServicePlansAllowed
+	orgConfig.TotalPrivateDomains = q.TotalPrivateDomains
+	orgConfig.TotalReservedRoutePorts = q.TotalReservedRoutePorts
+	orgConfig.TotalServiceKeys = q.TotalServiceKeys
+	orgConfig.AppInstanceLimit = q.AppInstanceLimit
+	orgConfig.AppTaskLimit = q.AppTaskLimit
+}
+
+func (q *QuotaTier) applyToSpace(spaceConfig *SpaceConfig) {
+	spaceConfig.EnableSpaceQuota = true
+	spaceConfig.MemoryLimit = q.MemoryLimit
+	spaceConfig.InstanceMemoryLimit = q.InstanceMemoryLimit
+	spaceConfig.TotalRoutes = q.TotalRoutes
+	spaceConfig.TotalServices = q.TotalServices
+	spaceConfig.PaidServicePlansAllowed = q.PaidServicePlansAllowed
+	spaceConfig.TotalPrivateDomains = q.TotalPrivateDomains
+	spaceConfig.TotalReservedRoutePorts = q.TotalReservedRoutePorts
+	spaceConfig.TotalServiceKeys = q.TotalServiceKeys
+	spaceConfig.AppInstanceLimit = q.AppInstanceLimit
+	spaceConfig.AppTaskLimit = q.AppTaskLimit
+}
+
+// GetOrgGroups reads the org-groups.yml org groups, with no groups when the
+// file does not exist.
+func (m *yamlManager) GetOrgGroups() (*OrgGroups, error) {
+	fp := path.Join(m.ConfigDir, "org-groups.yml")
+	groups := &OrgGroups{}
+	if !FileOrDirectoryExists(fp) {
+		return groups, nil
+	}
+	if err := LoadFile(fp, groups); err != nil {
+		return nil, err
+	}
+	if err := groups.validate(); err != nil {
+		return nil, err
+	}
+	groups.file = m.sourceFile(fp)
+	return groups, nil
+}
+
+// SaveOrgGroups writes org-groups.yml.
+func (m *yamlManager) SaveOrgGroups(orgGroups *OrgGroups) error {
+	return WriteFile(path.Join(m.ConfigDir, "org-groups.yml"), orgGroups)
+}
+
+// Group returns the group with the name, nil when it is not defined.
+func (g *OrgGroups) Group(name string) *OrgGroup {
+	for i := range g.Groups {
+		if g.Groups[i].Name == name {
+			return &g.Groups[i]
+		}
+	}
+	return nil
+}
+
+func (g *OrgGroups) validate() error {
+	groups := make(map[string]*OrgGroup)
+	memberOf := make(map[string]string)
+	for i, group := range g.Groups {
+		if group.Name == "" {
+			return fmt.Errorf("org group in org-groups.yml is missing its name")
+		}
+		if _, ok := groups[group.Name]; ok {
+			return fmt.Errorf("org group [%s] is defined more than once in org-groups.yml", group.Name)
+		}
+		groups[group.Name] = &g.Groups[i]
+		for _, org := range group.Orgs {
+			if other, ok := memberOf[strings.ToLower(org)]; ok {
+				return fmt.Errorf("org [%s] is a member of both org groups [%s] and [%s] in org-groups.yml", org, other, group.Name)
+			}
+			memberOf[strings.ToLower(org)] = group.Name
+		}
+		if group.Quota != nil {
+			if err := validateQuotaLimits(fmt.Sprintf("org group %s", group.Name),
+				quotaLimit{"memory-limit", group.Quota.MemoryLimit},
+				quotaLimit{"instance-memory-limit", group.Quota.InstanceMemoryLimit},
+				quotaLimit{"total-routes", group.Quota.TotalRoutes},
+				quotaLimit{"total-services", group.Quota.TotalServices},
+				quotaLimit{"total_private_domains", group.Quota.TotalPrivateDomains},
+				quotaLimit{"total_reserved_route_ports", group.Quota.TotalReservedRoutePorts},
+				quotaLimit{"total_service_keys", group.Quota.TotalServiceKeys},
+				quotaLimit{"app_instance_limit", group.Quota.AppInstanceLimit},
+				quotaLimit{"app_task_limit", group.Quota.AppTaskLimit},
+			); err != nil {
+				return err
+			}
+		}
+	}
+	for _, group := range g.Groups {
+		seen := map[string]bool{group.Name: true}
+		for parent := group.Parent; parent != ""; parent = groups[parent].Parent {
+			if _, ok := groups[parent]; !ok {
+				return fmt.Errorf("parent [%s] of org group [%s] is not defined in org-groups.yml", parent, group.Name)
+			}
+			if seen[parent] {
+				return fmt.Errorf("org group [%s] is nested under itself in org-groups.yml", group.Name)
+			}
+			seen[parent] = true
+		}
+	}
+	return nil
+}
+
+// lineage returns the group of the org followed by its parents, nearest first.
+func (g *OrgGroups) lineage(org string) []OrgGroup {
+	var lineage []OrgGroup
+	parent := ""
+	for _, group := range g.Groups {
+		for _, member := range group.Orgs {
+			if strings.EqualFold(member, org) {
+				lineage = append(lineage, group)
+				parent = group.Parent
+			}
+		}
+	}
+	for parent != "" {
+		for _, group := range g.Groups {
+			if group.Name == parent {
+				lineage = append(lineage, group)
+				parent = group.Parent
+				break
+			}
+		}
+	}
+	return lineage
+}
+
+// applyToOrg gives the org the quota tier of its nearest group with one unless
+// the org enables its own quota, adds the asg profiles the org does not define
+// and the default profile when it has none, and adds the admins of every group.
+func (g *OrgGroups) applyToOrg(orgConfig *OrgConfig) {
+	lineage := g.lineage(orgConfig.Org)
+	if len(lineage) == 0 {
+		return
+	}
+	quotaInherited := orgConfig.EnableOrgQuota
+	for _, group := range lineage {
+		if group.Quota != nil && !quotaInherited {
+			group.Quota.applyTo(orgConfig)
+			quotaInherited = true
+		}
+		for name, profile := range group.ASGProfiles {
+			if _, ok := orgConfig.ASGProfiles[name]; ok {
+				continue
+			}
+			if orgConfig.ASGProfiles == nil {
+				orgConfig.ASGProfiles = make(map[string]ASGProfile)
+			}
+			orgConfig.ASGProfiles[name] = profile
+		}
+		if orgConfig.DefaultASGProfile == "" {
+			orgConfig.DefaultASGProfile = group.DefaultASGProfile
+		}
+		orgConfig.Manager.merge(group.Manager, g.file)
+		orgConfig.BillingManager.merge(group.BillingManager, g.file)
+		orgConfig.Auditor.merge(group.Auditor, g.file)
+	}
+}
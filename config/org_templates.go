@@ -0,0 +1,152 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// OrgTemplates are templates of similarly configured orgs, such as the orgs
+// of the students of a training event, read from org-templates.yml.
+type OrgTemplates struct {
+	Templates []OrgTemplate `yaml:"org-templates"`
+}
+
+// OrgTemplate generates an org for each name of the Orgs range, such as
+// student-001..student-200, with the same spaces and quotas. User, in which
+// {org} is replaced with the name of each org, is made manager of the org and
+// developer of its spaces.
+type OrgTemplate struct {
+	Name       string     `yaml:"name"`
+	Orgs       string     `yaml:"orgs"`
+	Spaces     []string   `yaml:"spaces,omitempty"`
+	User       string     `yaml:"user,omitempty"`
+	UserOrigin string     `yaml:"user-origin,omitempty"`
+	Quota      *QuotaTier `yaml:"quota,omitempty"`
+	SpaceQuota *QuotaTier `yaml:"space-quota,omitempty"`
+	AllowSSH   bool       `yaml:"allow-ssh,omitempty"`
+}
+
+// Origins of the user of an org template, uaa being the default.
+const (
+	TemplateUserUAA  = "uaa"
+	TemplateUserLDAP = "ldap"
+	TemplateUserSAML = "saml"
+)
+
+var orgRangePattern = regexp.MustCompile(`^(.*?)(\d+)$`)
+
+// GetOrgTemplates reads the org-templates.yml org templates, with no templates
+// when the file does not exist.
+func (m *yamlManager) GetOrgTemplates() (*OrgTemplates, error) {
+	fp := path.Join(m.ConfigDir, "org-templates.yml")
+	templates := &OrgTemplates{}
+	if !FileOrDirectoryExists(fp) {
+		return templates, nil
+	}
+	if err := LoadFile(fp, templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// Template returns the template with the name, nil when it is not defined.
+func (t *OrgTemplates) Template(name string) *OrgTemplate {
+	for i := range t.Templates {
+		if t.Templates[i].Name == name {
+			return &t.Templates[i]
+		}
+	}
+	return nil
+}
+
+// OrgNames expands the range of the template, such as student-001..student-200,
+// into the names of its orgs. The numbers keep the width of the first one.
+func (t *OrgTemplate) OrgNames() ([]string, error) {
+	bounds := strings.Split(t.Orgs, "..")
+	if len(bounds) != 2 {
+		return nil, fmt.Errorf("orgs [%s] of org template [%s] must be a range such as student-001..student-200", t.Orgs, t.Name)
+	}
+	first := orgRangePattern.FindStringSubmatch(strings.TrimSpace(bounds[0]))
+	last := orgRangePattern.FindStringSubmatch(strings.TrimSpace(bounds[1]))
+	if first == nil || last == nil || first[1] != last[1] {
+		return nil, fmt.Errorf("orgs [%s] of org template [%s] must be a range of names with the same prefix ending in a number", t.Orgs, t.Name)
+	}
+	from, _ := strconv.Atoi(first[2])
+	to, _ := strconv.Atoi(last[2])
+	if from > to {
+		return nil, fmt.Errorf("orgs [%s] of org template [%s] must end with a number greater than it starts with", t.Orgs, t.Name)
+	}
+	var names []string
+	for n := from; n <= to; n++ {
+		names = append(names, fmt.Sprintf("%s%0*d", first[1], len(first[2]), n))
+	}
+	return names, nil
+}
+
+// Generate returns the configuration of every org of the template, and of
+// their spaces.
+func (t *OrgTemplate) Generate() ([]*OrgConfig, []*SpaceConfig, error) {
+	names, err := t.OrgNames()
+	if err != nil {
+		return nil, nil, err
+	}
+	if t.User != "" && !strings.Contains(t.User, OrgPlaceholder) {
+		return nil, nil, fmt.Errorf("user [%s] of org template [%s] must contain %s, so that each org gets its own user", t.User, t.Name, OrgPlaceholder)
+	}
+	var orgConfigs []*OrgConfig
+	var spaceConfigs []*SpaceConfig
+	for _, name := range names {
+		orgConfig := &OrgConfig{
+			Org:                        name,
+			RemoveUsers:                true,
+			RemovePrivateDomains:       true,
+			RemoveSharedPrivateDomains: true,
+		}
+		if t.Quota != nil {
+			t.Quota.applyTo(orgConfig)
+		}
+		user, err := t.user(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		orgConfig.Manager = user
+		orgConfigs = append(orgConfigs, orgConfig)
+		for _, spaceName := range t.Spaces {
+			spaceConfig := &SpaceConfig{
+				Org:         name,
+				Space:       spaceName,
+				AllowSSH:    t.AllowSSH,
+				RemoveUsers: true,
+				Developer:   user,
+			}
+			if t.SpaceQuota != nil {
+				t.SpaceQuota.applyToSpace(spaceConfig)
+			}
+			spaceConfigs = append(spaceConfigs, spaceConfig)
+		}
+	}
+	return orgConfigs, spaceConfigs, nil
+}
+
+// user returns the role block of the user of an org
+func (t *OrgTemplate) user(orgName string) (UserMgmt, error) {
+	user := UserMgmt{}
+	if t.User == "" {
+		return user, nil
+	}
+	userName := strings.Replace(t.User, OrgPlaceholder, orgName, -1)
+	switch t.UserOrigin {
+	case "", TemplateUserUAA:
+		user.Users = []string{userName}
+	case TemplateUserLDAP:
+		user.LDAPUsers = []string{userName}
+	case TemplateUserSAML:
+		user.SamlUsers = []string{userName}
+	default:
+		return user, fmt.Errorf("user-origin [%s] of org template [%s] is not one of %s, %s, %s", t.UserOrigin, t.Name, TemplateUserUAA, TemplateUserLDAP, TemplateUserSAML)
+	}
+	return user, nil
+}
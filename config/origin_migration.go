@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Ways the username of a migrated user is derived in the new origin.
+const (
+	MigrateKeepUserName  = "username"
+	MigrateEmailUserName = "email"
+)
+
+// OriginMigration describes how uaa users are moved from one origin to
+// another, for example from ldap to saml when switching identity providers.
+type OriginMigration struct {
+	FromOrigin string `yaml:"from-origin"`
+	ToOrigin   string `yaml:"to-origin"`
+	// UserName is username (keep the username) or email (use the user's email)
+	UserName string `yaml:"username"`
+	// Users maps the usernames of specific users to their username in the new origin
+	Users map[string]string `yaml:"users,omitempty"`
+	// Cutover keeps users of to-origin in the same roles as their user in
+	// from-origin while both identity providers are in use
+	Cutover bool `yaml:"cutover,omitempty"`
+}
+
+// NewUserName returns the username in the new origin of a user in the old origin.
+func (o *OriginMigration) NewUserName(userName, email string) string {
+	for from, to := range o.Users {
+		if strings.EqualFold(from, userName) {
+			return to
+		}
+	}
+	if o.UserName == MigrateEmailUserName && email != "" {
+		return email
+	}
+	return userName
+}
+
+func (o *OriginMigration) validate() error {
+	if o.FromOrigin == "" || o.ToOrigin == "" {
+		return fmt.Errorf("from-origin and to-origin are required in origin-migration.yml")
+	}
+	if o.FromOrigin == o.ToOrigin {
+		return fmt.Errorf("from-origin and to-origin must be different in origin-migration.yml")
+	}
+	if o.UserName == "" {
+		o.UserName = MigrateKeepUserName
+	}
+	if o.UserName != MigrateKeepUserName && o.UserName != MigrateEmailUserName {
+		return fmt.Errorf("username [%s] in origin-migration.yml must be %s or %s", o.UserName, MigrateKeepUserName, MigrateEmailUserName)
+	}
+	return nil
+}
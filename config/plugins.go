@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Plugin is an executable apply runs at hooks, before and after its steps, so
+// that site-specific integrations, such as registering new orgs with a
+// chargeback system, do not require forking cf-mgmt. Hooks are named pre- or
+// post- followed by the hook of a step, such as pre-org-create and
+// post-org-user-sync, or pre-apply and post-apply.
+type Plugin struct {
+	Name string `yaml:"name"`
+	// Command is the executable, relative to the config directory when it is
+	// a relative path, and Args its arguments
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args,omitempty"`
+	Hooks   []string `yaml:"hooks"`
+	// TimeoutSeconds limits each run of the plugin, 60 seconds when not set
+	TimeoutSeconds int `yaml:"timeout-seconds,omitempty"`
+	// FailApply fails the step the plugin runs before or after when the
+	// plugin fails, rather than only logging the failure
+	FailApply bool `yaml:"fail-apply,omitempty"`
+	// Plan passes the plugin the changes apply is about to make, computed by
+	// applying the configuration to a snapshot of the foundation in memory
+	// before apply runs
+	Plan bool `yaml:"plan,omitempty"`
+}
+
+func (p Plugin) validate() error {
+	if p.Name == "" || p.Command == "" {
+		return fmt.Errorf("plugins require a name and a command")
+	}
+	if len(p.Hooks) == 0 {
+		return fmt.Errorf("plugin [%s] requires hooks to run at", p.Name)
+	}
+	for _, hook := range p.Hooks {
+		if !strings.HasPrefix(hook, "pre-") && !strings.HasPrefix(hook, "post-") {
+			return fmt.Errorf("hook [%s] of plugin [%s] must start with pre- or post-", hook, p.Name)
+		}
+	}
+	if p.TimeoutSeconds < 0 {
+		return fmt.Errorf("timeout-seconds of plugin [%s] must be a positive number of seconds", p.Name)
+	}
+	return nil
+}
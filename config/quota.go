@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Unlimited is how the cloud controller represents a quota limit without a
+// limit, and what a limit set to unlimited in the configuration is read as.
+const Unlimited = -1
+
+// quotaLimits are the quota properties that can be set to unlimited.
+var quotaLimits = []string{
+	"memory-limit",
+	"instance-memory-limit",
+	"total-routes",
+	"total-services",
+	"total_private_domains",
+	"total_reserved_route_ports",
+	"total_service_keys",
+	"app_instance_limit",
+	"app_task_limit",
+}
+
+// quotaLimit is a configured limit, named after its config property.
+type quotaLimit struct {
+	name  string
+	value int
+}
+
+// unmarshalQuotaLimits decodes an org or space config, reading quota limits
+// set to unlimited as -1 rather than having them fail to decode as a number.
+func unmarshalQuotaLimits(unmarshal func(interface{}) error, out interface{}) error {
+	raw := make(map[string]interface{})
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	replaced := false
+	for _, name := range quotaLimits {
+		if value, ok := raw[name].(string); ok && strings.EqualFold(strings.TrimSpace(value), "unlimited") {
+			raw[name] = Unlimited
+			replaced = true
+		}
+	}
+	if !replaced {
+		return unmarshal(out)
+	}
+	data, err := yaml.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, out)
+}
+
+// validateQuotaLimits rejects limits the cloud controller does not accept: an
+// unlimited memory-limit, which must be a number of megabytes, and negative
+// limits other than unlimited.
+func validateQuotaLimits(owner string, limits ...quotaLimit) error {
+	for _, limit := range limits {
+		if limit.name == "memory-limit" && limit.value < 0 {
+			return fmt.Errorf("memory-limit of %s cannot be unlimited, the cloud controller requires a number of megabytes", owner)
+		}
+		if limit.value < Unlimited {
+			return fmt.Errorf("%s of %s must be unlimited or at least 0, not %d", limit.name, owner, limit.value)
+		}
+	}
+	return nil
+}
+
+// UnmarshalYAML reads quota limits set to unlimited as -1.
+func (o *OrgConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain OrgConfig
+	return unmarshalQuotaLimits(unmarshal, (*plain)(o))
+}
+
+// UnmarshalYAML reads quota limits set to unlimited as -1.
+func (s *SpaceConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain SpaceConfig
+	return unmarshalQuotaLimits(unmarshal, (*plain)(s))
+}
+
+// QuotaAlerts are thresholds, in percent of the limits of the quota of an org,
+// at which quota-report notifies the org managers. A threshold of 0 is not
+// checked.
+type QuotaAlerts struct {
+	Memory       int `yaml:"memory,omitempty"`
+	AppInstances int `yaml:"app-instances,omitempty"`
+	Routes       int `yaml:"routes,omitempty"`
+}
+
+// validate requires each threshold to be a percentage.
+func (a *QuotaAlerts) validate(owner string) error {
+	for _, threshold := range []quotaLimit{{"memory", a.Memory}, {"app-instances", a.AppInstances}, {"routes", a.Routes}} {
+		if threshold.value < 0 || threshold.value > 100 {
+			return fmt.Errorf("quota-alerts %s of %s must be a percentage from 0 to 100, not %d", threshold.name, owner, threshold.value)
+		}
+	}
+	return nil
+}
+
+// validateQuota checks the quota alerts, and the limits of the org quota when
+// it is enabled.
+func (o *OrgConfig) validateQuota() error {
+	if o.QuotaAlerts != nil {
+		if err := o.QuotaAlerts.validate(fmt.Sprintf("org %s", o.Org)); err != nil {
+			return err
+		}
+	}
+	if !o.EnableOrgQuota {
+		return nil
+	}
+	return validateQuotaLimits(fmt.Sprintf("org %s", o.Org),
+		quotaLimit{"memory-limit", o.MemoryLimit},
+		quotaLimit{"instance-memory-limit", o.InstanceMemoryLimit},
+		quotaLimit{"total-routes", o.TotalRoutes},
+		quotaLimit{"total-services", o.TotalServices},
+		quotaLimit{"total_private_domains", o.TotalPrivateDomains},
+		quotaLimit{"total_reserved_route_ports", o.TotalReservedRoutePorts},
+		quotaLimit{"total_service_keys", o.TotalServiceKeys},
+		quotaLimit{"app_instance_limit", o.AppInstanceLimit},
+		quotaLimit{"app_task_limit", o.AppTaskLimit},
+	)
+}
+
+// validateQuota checks the limits of the space quota when it is enabled.
+// Space quotas have no private domain limit, so total_private_domains is not checked.
+func (s *SpaceConfig) validateQuota() error {
+	if !s.EnableSpaceQuota {
+		return nil
+	}
+	return validateQuotaLimits(fmt.Sprintf("space %s/%s", s.Org, s.Space),
+		quotaLimit{"memory-limit", s.MemoryLimit},
+		quotaLimit{"instance-memory-limit", s.InstanceMemoryLimit},
+		quotaLimit{"total-routes", s.TotalRoutes},
+		quotaLimit{"total-services", s.TotalServices},
+		quotaLimit{"total_reserved_route_ports", s.TotalReservedRoutePorts},
+		quotaLimit{"total_service_keys", s.TotalServiceKeys},
+		quotaLimit{"app_instance_limit", s.AppInstanceLimit},
+		quotaLimit{"app_task_limit", s.AppTaskLimit},
+	)
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// overcommitLimits are the limits of an org quota that the space quotas of its
+// spaces share. instance-memory-limit is a limit per app instance, which space
+// quotas do not add up to, and space quotas have no private domain limit.
+var overcommitLimits = []string{
+	"memory-limit",
+	"total-routes",
+	"total-services",
+	"total_reserved_route_ports",
+	"total_service_keys",
+	"app_instance_limit",
+	"app_task_limit",
+}
+
+// QuotaOvercommit is a limit of the quota of an org that the space quotas of
+// its spaces add up to more than, beyond the space-quota-tolerance of
+// cf-mgmt.yml. Memory is in megabytes.
+type QuotaOvercommit struct {
+	Org        string   `json:"org"`
+	Limit      string   `json:"limit"`
+	OrgLimit   int      `json:"org_limit"`
+	SpaceTotal int      `json:"space_total"`
+	Spaces     []string `json:"spaces"`
+}
+
+func (o QuotaOvercommit) String() string {
+	return fmt.Sprintf("space quotas of org %s add up to %s %d, over the %d of its org quota", o.Org, o.Limit, o.SpaceTotal, o.OrgLimit)
+}
+
+//QuotaLimits - the limits of a quota by config property
+type QuotaLimits map[string]int
+
+func (o *OrgConfig) quotaLimits() QuotaLimits {
+	return QuotaLimits{
+		"memory-limit":               o.MemoryLimit,
+		"total-routes":               o.TotalRoutes,
+		"total-services":             o.TotalServices,
+		"total_reserved_route_ports": o.TotalReservedRoutePorts,
+		"total_service_keys":         o.TotalServiceKeys,
+		"app_instance_limit":         o.AppInstanceLimit,
+		"app_task_limit":             o.AppTaskLimit,
+	}
+}
+
+func (s *SpaceConfig) quotaLimits() QuotaLimits {
+	return QuotaLimits{
+		"memory-limit":               s.MemoryLimit,
+		"total-routes":               s.TotalRoutes,
+		"total-services":             s.TotalServices,
+		"total_reserved_route_ports": s.TotalReservedRoutePorts,
+		"total_service_keys":         s.TotalServiceKeys,
+		"app_instance_limit":         s.AppInstanceLimit,
+		"app_task_limit":             s.AppTaskLimit,
+	}
+}
+
+//Overcommits - the limits of the org quota that the space quotas, by space name, add up to more than
+//tolerance percent beyond. Unlimited limits of the org are not checked, and unlimited limits of a space,
+//which the org quota bounds, are not added up.
+func Overcommits(org string, orgLimits QuotaLimits, spaceLimits map[string]QuotaLimits, tolerance int) []QuotaOvercommit {
+	spaces := make([]string, 0, len(spaceLimits))
+	for space := range spaceLimits {
+		spaces = append(spaces, space)
+	}
+	sort.Strings(spaces)
+	var overcommits []QuotaOvercommit
+	for _, limit := range overcommitLimits {
+		orgLimit := orgLimits[limit]
+		if orgLimit == Unlimited {
+			continue
+		}
+		overcommit := QuotaOvercommit{Org: org, Limit: limit, OrgLimit: orgLimit}
+		for _, space := range spaces {
+			if spaceLimit := spaceLimits[space][limit]; spaceLimit > 0 {
+				overcommit.SpaceTotal += spaceLimit
+				overcommit.Spaces = append(overcommit.Spaces, space)
+			}
+		}
+		if overcommit.SpaceTotal*100 > orgLimit*(100+tolerance) {
+			overcommits = append(overcommits, overcommit)
+		}
+	}
+	return overcommits
+}
+
+//SpaceQuotaOvercommits - the limits of the configured quota of each org that sets enable-org-quota which
+//the configured space quotas of its spaces that set enable-space-quota add up to more than
+func SpaceQuotaOvercommits(orgConfigs []OrgConfig, spaceConfigs []SpaceConfig, tolerance int) []QuotaOvercommit {
+	spaceLimits := make(map[string]map[string]QuotaLimits)
+	for i := range spaceConfigs {
+		spaceConfig := &spaceConfigs[i]
+		if !spaceConfig.EnableSpaceQuota {
+			continue
+		}
+		if spaceLimits[spaceConfig.Org] == nil {
+			spaceLimits[spaceConfig.Org] = make(map[string]QuotaLimits)
+		}
+		spaceLimits[spaceConfig.Org][spaceConfig.Space] = spaceConfig.quotaLimits()
+	}
+	var overcommits []QuotaOvercommit
+	for i := range orgConfigs {
+		orgConfig := &orgConfigs[i]
+		if !orgConfig.EnableOrgQuota {
+			continue
+		}
+		overcommits = append(overcommits, Overcommits(orgConfig.Org, orgConfig.quotaLimits(), spaceLimits[orgConfig.Org], tolerance)...)
+	}
+	return overcommits
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// RunState records, for each command, the hash of the configuration of every
+// org at the last successful run of the command, so that a later run can
+// process only the orgs whose configuration changed since.
+type RunState struct {
+	Commands map[string]map[string]string `json:"commands"`
+}
+
+// LoadRunState reads the run state, which is empty when the file does not exist.
+func LoadRunState(stateFile string) (*RunState, error) {
+	state := &RunState{Commands: make(map[string]map[string]string)}
+	data, err := ioutil.ReadFile(stateFile)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.Commands == nil {
+		state.Commands = make(map[string]map[string]string)
+	}
+	return state, nil
+}
+
+// Save writes the run state, creating the parent directory.
+func (s *RunState) Save(stateFile string) error {
+	if err := os.MkdirAll(filepath.Dir(stateFile), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(stateFile, data, 0644)
+}
+
+// ChangedOrgs returns the orgs, sorted by name, whose hash differs from their
+// hash at the last successful run of the command, or that it never processed.
+func (s *RunState) ChangedOrgs(command string, hashes map[string]string) []string {
+	var changed []string
+	for orgName, hash := range hashes {
+		if s.Commands[command][orgName] != hash {
+			changed = append(changed, orgName)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// Update records the hashes as those of the last successful run of the command.
+func (s *RunState) Update(command string, hashes map[string]string) {
+	s.Commands[command] = hashes
+}
+
+// UpdateOrgs records the hashes of the given orgs as those of the last
+// successful run of the command, which only processed these orgs. The
+// recorded hashes of the other orgs are kept, and those of the orgs no
+// longer configured are dropped.
+func (s *RunState) UpdateOrgs(command string, hashes map[string]string, orgNames []string) {
+	recorded := make(map[string]string)
+	for orgName := range hashes {
+		if hash, ok := s.Commands[command][orgName]; ok {
+			recorded[orgName] = hash
+		}
+	}
+	for _, orgName := range orgNames {
+		if hash, ok := hashes[orgName]; ok {
+			recorded[orgName] = hash
+		}
+	}
+	s.Commands[command] = recorded
+}
+
+// orgConfigHash is what the hash of an org covers
+type orgConfigHash struct {
+	Shared interface{}   `yaml:"shared"`
+	Org    OrgConfig     `yaml:"org"`
+	Spaces []SpaceConfig `yaml:"spaces"`
+}
+
+// OrgConfigHashes hashes the configuration of each org, keyed by org name. The
+// hash covers the org config and its space configs, which include the space
+// defaults and org group settings they inherit, as well as cf-mgmt.yml,
+// ldap.yml and the security group definitions, so that changing one of
+// those changes every org.
+func OrgConfigHashes(reader Reader) (map[string]string, error) {
+	globalConfig, err := reader.GetGlobalConfig()
+	if err != nil {
+		return nil, err
+	}
+	// a placeholder bind password keeps the hash independent of the real one
+	ldapConfig, err := reader.LdapConfig("unused")
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	asgConfigs, err := reader.GetASGConfigs()
+	if err != nil {
+		return nil, err
+	}
+	defaultASGConfigs, err := reader.GetDefaultASGConfigs()
+	if err != nil {
+		return nil, err
+	}
+	orgConfigs, err := reader.GetOrgConfigs()
+	if err != nil {
+		return nil, err
+	}
+	spaceConfigs, err := reader.GetSpaceConfigs()
+	if err != nil {
+		return nil, err
+	}
+	orgSpaces := make(map[string][]SpaceConfig)
+	for _, spaceConfig := range spaceConfigs {
+		orgSpaces[spaceConfig.Org] = append(orgSpaces[spaceConfig.Org], spaceConfig)
+	}
+
+	shared := []interface{}{globalConfig, ldapConfig, asgConfigs, defaultASGConfigs}
+	hashes := make(map[string]string)
+	for _, orgConfig := range orgConfigs {
+		spaces := orgSpaces[orgConfig.Org]
+		sort.Slice(spaces, func(i, j int) bool { return spaces[i].Space < spaces[j].Space })
+		data, err := yaml.Marshal(orgConfigHash{Shared: shared, Org: orgConfig, Spaces: spaces})
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(data)
+		hashes[orgConfig.Org] = hex.EncodeToString(sum[:])
+	}
+	return hashes, nil
+}
+
+// ConfigHash hashes the configuration of every org, as OrgConfigHashes does, so
+// that changing the configuration of any org, or which orgs are configured,
+// changes it.
+func ConfigHash(reader Reader) (string, error) {
+	hashes, err := OrgConfigHashes(reader)
+	if err != nil {
+		return "", err
+	}
+	orgNames := make([]string, 0, len(hashes))
+	for orgName := range hashes {
+		orgNames = append(orgNames, orgName)
+	}
+	sort.Strings(orgNames)
+	sum := sha256.New()
+	for _, orgName := range orgNames {
+		sum.Write([]byte(orgName + "=" + hashes[orgName] + "\n"))
+	}
+	return hex.EncodeToString(sum.Sum(nil)), nil
+}
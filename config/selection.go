@@ -0,0 +1,108 @@
+package config
+
+import "sync"
+
+// selectedOrgs limits the org and space configuration read to a set of orgs
+type selectedOrgs struct {
+	Reader
+	orgs map[string]bool
+}
+
+// SelectOrgs creates a Reader that only returns the org and space configs of
+// the named orgs, so commands update a subset of the configured orgs. Orgs
+// is not limited, so that the orgs left out are never considered deleted.
+func SelectOrgs(reader Reader, orgNames []string) Reader {
+	orgs := make(map[string]bool)
+	for _, orgName := range orgNames {
+		orgs[orgName] = true
+	}
+	return &selectedOrgs{Reader: reader, orgs: orgs}
+}
+
+func (s *selectedOrgs) Spaces() ([]Spaces, error) {
+	spaces, err := s.Reader.Spaces()
+	if err != nil {
+		return nil, err
+	}
+	var result []Spaces
+	for _, orgSpaces := range spaces {
+		if s.orgs[orgSpaces.Org] {
+			result = append(result, orgSpaces)
+		}
+	}
+	return result, nil
+}
+
+func (s *selectedOrgs) GetOrgConfigs() ([]OrgConfig, error) {
+	orgConfigs, err := s.Reader.GetOrgConfigs()
+	if err != nil {
+		return nil, err
+	}
+	var result []OrgConfig
+	for _, orgConfig := range orgConfigs {
+		if s.orgs[orgConfig.Org] {
+			result = append(result, orgConfig)
+		}
+	}
+	return result, nil
+}
+
+func (s *selectedOrgs) GetSpaceConfigs() ([]SpaceConfig, error) {
+	spaceConfigs, err := s.Reader.GetSpaceConfigs()
+	if err != nil {
+		return nil, err
+	}
+	var result []SpaceConfig
+	for _, spaceConfig := range spaceConfigs {
+		if s.orgs[spaceConfig.Org] {
+			result = append(result, spaceConfig)
+		}
+	}
+	return result, nil
+}
+
+// OrgScope is a Reader whose selection of orgs can change between reads, so
+// that the managers created on it can be run one org at a time.
+type OrgScope struct {
+	Reader
+	mutex    sync.RWMutex
+	selected Reader
+}
+
+// NewOrgScope creates an OrgScope that reads every org until Select is called.
+func NewOrgScope(reader Reader) *OrgScope {
+	return &OrgScope{Reader: reader}
+}
+
+// Select limits the org and space configs read to the named orgs, or lifts
+// the limit when orgNames is nil.
+func (s *OrgScope) Select(orgNames []string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if orgNames == nil {
+		s.selected = nil
+		return
+	}
+	s.selected = SelectOrgs(s.Reader, orgNames)
+}
+
+func (s *OrgScope) reader() Reader {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if s.selected == nil {
+		return s.Reader
+	}
+	return s.selected
+}
+
+func (s *OrgScope) Spaces() ([]Spaces, error) {
+	return s.reader().Spaces()
+}
+
+func (s *OrgScope) GetOrgConfigs() ([]OrgConfig, error) {
+	return s.reader().GetOrgConfigs()
+}
+
+func (s *OrgScope) GetSpaceConfigs() ([]SpaceConfig, error) {
+	return s.reader().GetSpaceConfigs()
+}
@@ -0,0 +1,22 @@
+package config
+
+import "strings"
+
+// ServiceAllowed is whether the org may have instances of the plan of the
+// service. Each of allowed-services is the name of a service, allowing all of
+// its plans, or service:plan. Any service is allowed when the org lists none.
+func (o *OrgConfig) ServiceAllowed(service, plan string) bool {
+	if len(o.AllowedServices) == 0 {
+		return true
+	}
+	for _, allowed := range o.AllowedServices {
+		allowedService, allowedPlan := allowed, ""
+		if i := strings.Index(allowed, ":"); i >= 0 {
+			allowedService, allowedPlan = allowed[:i], allowed[i+1:]
+		}
+		if allowedService == service && (allowedPlan == "" || allowedPlan == plan) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,7 +1,11 @@
 package config
 
 import (
+	"fmt"
+	"path"
+	"path/filepath"
 	"strings"
+	"time"
 )
 
 // Spaces describes cf-mgmt config for all spaces.
@@ -9,6 +13,9 @@ type Spaces struct {
 	Org                string   `yaml:"org"`
 	Spaces             []string `yaml:"spaces"`
 	EnableDeleteSpaces bool     `yaml:"enable-delete-spaces"`
+
+	// Include lists the fragments merged into the file
+	Include []Include `yaml:"include,omitempty"`
 }
 
 // SpaceConfig describes attributes for a space.
@@ -38,6 +45,76 @@ type SpaceConfig struct {
 	AppTaskLimit            int      `yaml:"app_task_limit"`
 	IsoSegment              string   `yaml:"isolation_segment"`
 	ASGs                    []string `yaml:"named-security-groups"`
+	StagingASGs             []string `yaml:"named-staging-security-groups"`
+	ASGProfile              string   `yaml:"asg-profile,omitempty"`
+	ExcludeUsers            []string `yaml:"exclude-users,omitempty"`
+	AllowInternalRoutes     bool     `yaml:"allow-internal-routes,omitempty"`
+	AllowDocker             bool     `yaml:"allow-docker,omitempty"`
+	// RotateServiceKeys opts the space in to the recreation of its service
+	// keys older than the service-key-rotation policy of cf-mgmt.yml
+	RotateServiceKeys bool `yaml:"rotate-service-keys,omitempty"`
+	// Ephemeral spaces are deleted and recreated by recycle-spaces whenever
+	// RecycleSchedule, nightly, weekly or a cron expression, fires
+	Ephemeral       bool   `yaml:"ephemeral,omitempty"`
+	RecycleSchedule string `yaml:"recycle-schedule,omitempty"`
+	// Pattern is the space pattern whose configuration was copied to the
+	// space, when it has none of its own
+	Pattern string `yaml:"-"`
+
+	// Include lists the fragments merged into the file
+	Include []Include `yaml:"include,omitempty"`
+
+	// file is the config file the space, or the space pattern it matched,
+	// was read from
+	file string
+}
+
+// ConfigFile returns the path of the configuration file of the space, or of
+// the space pattern it matched, relative to the config directory and, when it
+// was read from a consolidated config file, within that file.
+func (i *SpaceConfig) ConfigFile() string {
+	if i.file != "" {
+		return i.file
+	}
+	if i.Pattern != "" {
+		return filepath.ToSlash(defaultConfigFile(filepath.Join(i.Org, i.Pattern), "spaceConfig"))
+	}
+	return filepath.ToSlash(defaultConfigFile(filepath.Join(i.Org, i.Space), "spaceConfig"))
+}
+
+// Shorthands of recycle-schedule, which defaults to nightly.
+const (
+	RecycleNightly = "nightly"
+	RecycleWeekly  = "weekly"
+)
+
+// maxRecycleInterval is how far back a recycle schedule is looked up, a
+// monthly schedule being the least frequent
+const maxRecycleInterval = 31 * 24 * time.Hour
+
+// RecycleDue returns whether an ephemeral space created at createdAt is due
+// to be recycled at now, which is when its recycle schedule fired since the
+// space was created.
+func (i *SpaceConfig) RecycleDue(createdAt, now time.Time) (bool, error) {
+	if !i.Ephemeral {
+		return false, nil
+	}
+	expression := i.RecycleSchedule
+	switch expression {
+	case "", RecycleNightly:
+		expression = "0 0 * * *"
+	case RecycleWeekly:
+		expression = "0 0 * * 0"
+	}
+	schedule, err := parseCron(expression)
+	if err != nil {
+		return false, fmt.Errorf("recycle-schedule of space [%s] in org [%s]: %s", i.Space, i.Org, err.Error())
+	}
+	since := now.Sub(createdAt)
+	if since > maxRecycleInterval {
+		since = maxRecycleInterval
+	}
+	return schedule.within(now, since), nil
 }
 
 // Contains determines whether a space is present in a list of spaces.
@@ -51,6 +128,30 @@ func (s *Spaces) Contains(spaceName string) bool {
 	return false
 }
 
+// IsSpacePattern determines whether a configured space name is a glob pattern,
+// such as team-*, that is expanded against the existing spaces of the org.
+func IsSpacePattern(spaceName string) bool {
+	return strings.ContainsAny(spaceName, "*?[")
+}
+
+// MatchesPattern determines whether a space is matched by one of the configured space patterns.
+func (s *Spaces) MatchesPattern(spaceName string) bool {
+	for _, v := range s.Spaces {
+		if !IsSpacePattern(v) {
+			continue
+		}
+		if match, _ := path.Match(v, spaceName); match {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPattern determines whether the config applies to all existing spaces of the org matching a glob pattern.
+func (i *SpaceConfig) IsPattern() bool {
+	return IsSpacePattern(i.Space)
+}
+
 func (i *SpaceConfig) GetDeveloperGroups() []string {
 	return i.Developer.groups(i.DeveloperGroup)
 }
@@ -0,0 +1,38 @@
+package config
+
+import "fmt"
+
+// Stacks are the stacks the apps of the org may run on: the allowed-stacks,
+// or only the default-stack when no allowed-stacks are listed. It is empty
+// when the org has no stack policy.
+func (o *OrgConfig) Stacks() []string {
+	if len(o.AllowedStacks) > 0 {
+		return o.AllowedStacks
+	}
+	if o.DefaultStack != "" {
+		return []string{o.DefaultStack}
+	}
+	return nil
+}
+
+// StackAllowed is whether apps of the org may run on the stack.
+func (o *OrgConfig) StackAllowed(stack string) bool {
+	stacks := o.Stacks()
+	if len(stacks) == 0 {
+		return true
+	}
+	for _, allowed := range stacks {
+		if allowed == stack {
+			return true
+		}
+	}
+	return false
+}
+
+// validateStacks checks that the default-stack is one of the allowed-stacks.
+func (o *OrgConfig) validateStacks() error {
+	if o.DefaultStack == "" || o.StackAllowed(o.DefaultStack) {
+		return nil
+	}
+	return fmt.Errorf("default-stack %s of org %s is not one of its allowed-stacks %v", o.DefaultStack, o.Org, o.AllowedStacks)
+}
@@ -0,0 +1,46 @@
+package config
+
+import "fmt"
+
+// TokenPolicy is the token policy of the default uaa identity zone, part of
+// the security baseline of a foundation. Settings that are not set are left
+// as they are.
+type TokenPolicy struct {
+	// AccessTokenValidity and RefreshTokenValidity are in seconds
+	AccessTokenValidity  int   `yaml:"access-token-validity,omitempty"`
+	RefreshTokenValidity int   `yaml:"refresh-token-validity,omitempty"`
+	JwtRevocable         *bool `yaml:"jwt-revocable,omitempty"`
+	RefreshTokenUnique   *bool `yaml:"refresh-token-unique,omitempty"`
+	// ActiveKeyID is the id of the key tokens are signed with
+	ActiveKeyID string `yaml:"active-key-id,omitempty"`
+	// Keys, when set, replace the signing keys of the zone, so a rotated key
+	// stays listed until the tokens signed with it expire
+	Keys []TokenKey `yaml:"keys,omitempty"`
+}
+
+// TokenKey is a token signing key, read from the environment variable named
+// by SigningKeyEnv so that it is not committed with the configuration.
+type TokenKey struct {
+	ID            string `yaml:"id"`
+	SigningKeyEnv string `yaml:"signing-key-env"`
+}
+
+func (t *TokenPolicy) validate() error {
+	if t.AccessTokenValidity < 0 || t.RefreshTokenValidity < 0 {
+		return fmt.Errorf("access-token-validity and refresh-token-validity of token-policy must be positive numbers of seconds")
+	}
+	if len(t.Keys) == 0 {
+		return nil
+	}
+	active := false
+	for _, key := range t.Keys {
+		if key.ID == "" || key.SigningKeyEnv == "" {
+			return fmt.Errorf("keys of token-policy require an id and a signing-key-env")
+		}
+		active = active || key.ID == t.ActiveKeyID
+	}
+	if !active {
+		return fmt.Errorf("active-key-id [%s] of token-policy must be one of its keys", t.ActiveKeyID)
+	}
+	return nil
+}
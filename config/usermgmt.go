@@ -1,5 +1,7 @@
 package config
 
+import "strings"
+
 // UserMgmt specifies users and groups that can be associated to a particular org or space.
 type UserMgmt struct {
 	LDAPUsers  []string `yaml:"ldap_users"`
@@ -7,6 +9,63 @@ type UserMgmt struct {
 	SamlUsers  []string `yaml:"saml_users"`
 	LDAPGroup  string   `yaml:"ldap_group,omitempty"`
 	LDAPGroups []string `yaml:"ldap_groups"`
+	Clients    []string `yaml:"clients"`
+
+	// inherited maps the lower cased users, clients and ldap groups the org
+	// or space inherits to the config file that grants them the role
+	inherited map[string]string
+}
+
+// InheritedFrom returns the config file that grants the role to a user,
+// client or ldap group the org or space inherits, such as org-groups.yml,
+// and nothing when the config file of the org or space grants it.
+func (u *UserMgmt) InheritedFrom(name string) string {
+	return u.inherited[strings.ToLower(name)]
+}
+
+// inherit records that file grants the role to the names the role block does
+// not grant itself
+func (u *UserMgmt) inherit(file string, names ...string) {
+	for _, name := range names {
+		key := strings.ToLower(name)
+		if key == "" || u.grants(key) {
+			continue
+		}
+		if u.inherited == nil {
+			u.inherited = make(map[string]string)
+		}
+		u.inherited[key] = file
+	}
+}
+
+func (u *UserMgmt) grants(name string) bool {
+	for _, names := range [][]string{u.LDAPUsers, u.Users, u.SamlUsers, u.Clients, u.groups("")} {
+		for _, granted := range names {
+			if strings.ToLower(granted) == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inheritAll records that file grants the role to the users, clients and
+// groups of another role block
+func (u *UserMgmt) inheritAll(other UserMgmt, file string) {
+	for _, names := range [][]string{other.LDAPUsers, other.Users, other.SamlUsers, other.Clients, other.groups("")} {
+		u.inherit(file, names...)
+	}
+}
+
+// merge adds the users, clients and groups of another role block, inherited
+// from file.
+func (u *UserMgmt) merge(other UserMgmt, file string) {
+	u.inheritAll(other, file)
+	u.LDAPUsers = append(u.LDAPUsers, other.LDAPUsers...)
+	u.Users = append(u.Users, other.Users...)
+	u.SamlUsers = append(u.SamlUsers, other.SamlUsers...)
+	u.Clients = append(u.Clients, other.Clients...)
+	u.LDAPGroups = append(u.LDAPGroups, other.groups("")...)
 }
 
 func (u *UserMgmt) groups(groupName string) []string {
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/pivotalservices/cf-mgmt/redact"
 	"github.com/xchapter7x/lo"
 )
 
@@ -16,6 +17,23 @@ import (
 // It is backed by a directory of YAML files.
 type yamlManager struct {
 	ConfigDir string
+
+	// Consolidated is the consolidated config file the config directory was
+	// expanded from, if any
+	Consolidated string
+}
+
+// sourceFile returns the path of the config file f relative to the config
+// directory, within the consolidated config file it was expanded from if any
+func (m *yamlManager) sourceFile(f string) string {
+	file := f
+	if rel, err := filepath.Rel(m.ConfigDir, f); err == nil {
+		file = filepath.ToSlash(rel)
+	}
+	if m.Consolidated != "" {
+		return fmt.Sprintf("%s of %s", file, m.Consolidated)
+	}
+	return file
 }
 
 // Orgs reads the config for all orgs.
@@ -30,45 +48,67 @@ func (m *yamlManager) Orgs() (*Orgs, error) {
 }
 
 func (m *yamlManager) GetDefaultASGConfigs() ([]ASGConfig, error) {
-	files, err := FindFiles(path.Join(m.ConfigDir, "default_asgs"), ".json")
+	return loadASGConfigs(path.Join(m.ConfigDir, "default_asgs"))
+}
+
+// GetASGConfigs reads all ASGs from the cf-mgmt configuration.
+func (m *yamlManager) GetASGConfigs() ([]ASGConfig, error) {
+	return loadASGConfigs(path.Join(m.ConfigDir, "asgs"))
+}
+
+// loadASGConfigs reads the json rule files and the yml endpoint based
+// definitions within the given directory.
+func loadASGConfigs(asgDir string) ([]ASGConfig, error) {
+	files, err := FindFiles(asgDir, ".json")
 	if err != nil {
 		return nil, err
 	}
 	var result []ASGConfig
+	// two files naming the same asg, such as a json and a yml file, would each
+	// overwrite its rules
+	asgFiles := make(map[string]string)
 	for _, securityGroupFile := range files {
 		lo.G.Debug("Loading security group contents", securityGroupFile)
 		bytes, err := ioutil.ReadFile(securityGroupFile)
 		if err != nil {
 			return nil, err
 		}
+		if bytes, err = resolveASGIncludes(securityGroupFile, bytes); err != nil {
+			return nil, err
+		}
 		asgConfig := ASGConfig{}
 		lo.G.Debug("setting security group contents", string(bytes))
 		asgConfig.Rules = string(bytes)
 		asgConfig.Name = strings.Replace(filepath.Base(securityGroupFile), ".json", "", 1)
+		if other, ok := asgFiles[asgConfig.Name]; ok {
+			return nil, fmt.Errorf("security group [%s] is defined by both %s and %s", asgConfig.Name, other, securityGroupFile)
+		}
+		asgFiles[asgConfig.Name] = securityGroupFile
 		result = append(result, asgConfig)
 	}
-	return result, nil
-}
 
-// GetASGConfigs reads all ASGs from the cf-mgmt configuration.
-func (m *yamlManager) GetASGConfigs() ([]ASGConfig, error) {
-
-	files, err := FindFiles(path.Join(m.ConfigDir, "asgs"), ".json")
+	files, err = FindFiles(asgDir, ".yml")
 	if err != nil {
 		return nil, err
 	}
-	var result []ASGConfig
 	for _, securityGroupFile := range files {
-		lo.G.Debug("Loading security group contents", securityGroupFile)
-		bytes, err := ioutil.ReadFile(securityGroupFile)
-		if err != nil {
+		lo.G.Debug("Loading generated security group", securityGroupFile)
+		generated := &generatedASG{}
+		if err := LoadFile(securityGroupFile, generated); err != nil {
 			return nil, err
 		}
-		asgConfig := ASGConfig{}
-		lo.G.Debug("setting security group contents", string(bytes))
-		asgConfig.Rules = string(bytes)
-		asgConfig.Name = strings.Replace(filepath.Base(securityGroupFile), ".json", "", 1)
-		result = append(result, asgConfig)
+		name := strings.Replace(filepath.Base(securityGroupFile), ".yml", "", 1)
+		if len(generated.Allow) == 0 {
+			return nil, fmt.Errorf("security group [%s] in %s must allow at least one endpoint", name, securityGroupFile)
+		}
+		if other, ok := asgFiles[name]; ok {
+			return nil, fmt.Errorf("security group [%s] is defined by both %s and %s", name, other, securityGroupFile)
+		}
+		asgFiles[name] = securityGroupFile
+		result = append(result, ASGConfig{
+			Name:  name,
+			Allow: generated.Allow,
+		})
 	}
 	return result, nil
 }
@@ -76,32 +116,164 @@ func (m *yamlManager) GetASGConfigs() ([]ASGConfig, error) {
 // GetIsolationSegmentConfig reads isolation segment config
 func (m *yamlManager) GetGlobalConfig() (*GlobalConfig, error) {
 	globalConfig := &GlobalConfig{}
-	LoadFile(path.Join(m.ConfigDir, "cf-mgmt.yml"), globalConfig)
+	// cf-mgmt.yml is optional, but a fragment it includes that does not match its checksum is not
+	if err := LoadFile(path.Join(m.ConfigDir, "cf-mgmt.yml"), globalConfig); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, roleGroup := range globalConfig.RoleGroups {
+		if err := roleGroup.validate(); err != nil {
+			return nil, err
+		}
+	}
+	if globalConfig.TokenPolicy != nil {
+		if err := globalConfig.TokenPolicy.validate(); err != nil {
+			return nil, err
+		}
+	}
+	for _, plugin := range globalConfig.Plugins {
+		if err := plugin.validate(); err != nil {
+			return nil, err
+		}
+	}
+	for _, hook := range globalConfig.CommandHooks {
+		if err := hook.validate(); err != nil {
+			return nil, err
+		}
+	}
+	if globalConfig.SpaceQuotaTolerance < 0 {
+		return nil, fmt.Errorf("space-quota-tolerance must be a percentage of at least 0, not %d", globalConfig.SpaceQuotaTolerance)
+	}
+	if rotation := globalConfig.ServiceKeyRotation; rotation != nil && rotation.MaxAgeDays < 1 {
+		return nil, fmt.Errorf("max-age-days of service-key-rotation must be at least 1 day, not %d", rotation.MaxAgeDays)
+	}
 	return globalConfig, nil
 }
 
+// GetOriginMigration reads the origin-migration.yml mapping used to move users between uaa origins.
+// If no migration was configured, a nil migration and a nil error are returned.
+func (m *yamlManager) GetOriginMigration() (*OriginMigration, error) {
+	fp := path.Join(m.ConfigDir, "origin-migration.yml")
+	if !FileOrDirectoryExists(fp) {
+		return nil, nil
+	}
+	migration := &OriginMigration{}
+	if err := LoadFile(fp, migration); err != nil {
+		return nil, err
+	}
+	if err := migration.validate(); err != nil {
+		return nil, err
+	}
+	return migration, nil
+}
+
+// GetApprovals reads the approvals.yml approvals of destructive changes.
+// If no approvals were configured, nil approvals and a nil error are returned.
+func (m *yamlManager) GetApprovals() (*Approvals, error) {
+	fp := path.Join(m.ConfigDir, "approvals.yml")
+	if !FileOrDirectoryExists(fp) {
+		return nil, nil
+	}
+	approvals := &Approvals{}
+	if err := LoadFile(fp, approvals); err != nil {
+		return nil, err
+	}
+	if err := approvals.validate(); err != nil {
+		return nil, err
+	}
+	return approvals, nil
+}
+
+// GetIdentityProviders reads the identity-providers.yml saml and oidc providers of the uaa.
+// If no providers were configured, nil providers and a nil error are returned.
+func (m *yamlManager) GetIdentityProviders() (*IdentityProviders, error) {
+	fp := path.Join(m.ConfigDir, "identity-providers.yml")
+	if !FileOrDirectoryExists(fp) {
+		return nil, nil
+	}
+	providers := &IdentityProviders{}
+	if err := LoadFile(fp, providers); err != nil {
+		return nil, err
+	}
+	if err := providers.validate(); err != nil {
+		return nil, err
+	}
+	return providers, nil
+}
+
 // GetOrgConfigs reads all orgs from the cf-mgmt configuration.
 func (m *yamlManager) GetOrgConfigs() ([]OrgConfig, error) {
-	files, err := FindFiles(m.ConfigDir, "orgConfig.yml")
+	files, err := findConfigFiles(m.ConfigDir, "orgConfig")
+	if err != nil {
+		return nil, err
+	}
+	groupMappings, err := m.groupMappings()
+	if err != nil {
+		return nil, err
+	}
+	orgGroups, err := m.GetOrgGroups()
 	if err != nil {
 		return nil, err
 	}
 	result := make([]OrgConfig, len(files))
 	for i, f := range files {
-		result[i].AppTaskLimit = -1
-		result[i].AppInstanceLimit = -1
-		result[i].TotalReservedRoutePorts = 0
-		result[i].TotalPrivateDomains = -1
-		result[i].TotalServiceKeys = -1
-
-		if err = LoadFile(f, &result[i]); err != nil {
+		if err = loadOrgConfig(f, &result[i]); err != nil {
 			lo.G.Error(err)
 			return nil, err
 		}
+		result[i].file = m.sourceFile(f)
+		if _, err = result[i].maintenanceSchedule(); err != nil {
+			return nil, err
+		}
+		orgGroups.applyToOrg(&result[i])
+		if err = result[i].validateQuota(); err != nil {
+			return nil, err
+		}
+		if err = result[i].validateStacks(); err != nil {
+			return nil, err
+		}
+		groupMappings.applyToOrg(&result[i])
 	}
 	return result, nil
 }
 
+// findConfigFiles finds the org or space config files named name, each
+// either yml or json
+func findConfigFiles(configDir, name string) ([]string, error) {
+	found, err := FindFiles(configDir, name)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0)
+	for _, f := range found {
+		if base := filepath.Base(f); base == name+".yml" || base == name+".json" {
+			files = append(files, f)
+		}
+	}
+	dirs := make(map[string]string)
+	for _, f := range files {
+		dir := filepath.Dir(f)
+		if other, ok := dirs[dir]; ok {
+			return nil, fmt.Errorf("%s and %s configure the same %s, remove one of them", other, f, strings.TrimSuffix(name, "Config"))
+		}
+		dirs[dir] = f
+	}
+	return files, nil
+}
+
+// configFile is the path of the config file named name in dir, json when
+// the json file exists and yml otherwise
+func configFile(dir, name string) string {
+	if jsonFile := filepath.Join(dir, name+".json"); FileOrDirectoryExists(jsonFile) {
+		return jsonFile
+	}
+	return defaultConfigFile(dir, name)
+}
+
+// defaultConfigFile is the path of the yml config file named name in dir
+func defaultConfigFile(dir, name string) string {
+	return filepath.Join(dir, name+".yml")
+}
+
 func (m *yamlManager) SaveOrgSpaces(spaces *Spaces) error {
 	return WriteFile(filepath.Join(m.ConfigDir, spaces.Org, "spaces.yml"), spaces)
 }
@@ -139,42 +311,76 @@ func (m *yamlManager) OrgSpaces(orgName string) (*Spaces, error) {
 func (m *yamlManager) GetSpaceConfigs() ([]SpaceConfig, error) {
 
 	spaceDefaults := SpaceConfig{}
-	LoadFile(filepath.Join(m.ConfigDir, "spaceDefaults.yml"), &spaceDefaults)
+	spaceDefaultsFile := filepath.Join(m.ConfigDir, "spaceDefaults.yml")
+	if err := LoadFile(spaceDefaultsFile, &spaceDefaults); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	spaceDefaultsFile = m.sourceFile(spaceDefaultsFile)
+
+	orgConfigs, err := m.GetOrgConfigs()
+	if err != nil {
+		return nil, err
+	}
+	groupMappings, err := m.groupMappings()
+	if err != nil {
+		return nil, err
+	}
+	allSpaces := make(map[string]*SpaceRoles)
+	orgFiles := make(map[string]string)
+	for _, orgConfig := range orgConfigs {
+		allSpaces[orgConfig.Org] = orgConfig.AllSpaces
+		orgFiles[orgConfig.Org] = orgConfig.ConfigFile()
+	}
 
-	files, err := FindFiles(m.ConfigDir, "spaceConfig.yml")
+	files, err := findConfigFiles(m.ConfigDir, "spaceConfig")
 	if err != nil {
 		return nil, err
 	}
 	result := make([]SpaceConfig, len(files))
 	for i, f := range files {
-		result[i].AppInstanceLimit = -1
-		result[i].AppTaskLimit = -1
-		result[i].TotalReservedRoutePorts = 0
-		result[i].TotalPrivateDomains = -1
-		result[i].TotalServiceKeys = -1
-
-		if err = LoadFile(f, &result[i]); err != nil {
+		if err = loadSpaceConfig(f, &result[i]); err != nil {
 			return nil, err
 		}
-
+		if err = result[i].validateQuota(); err != nil {
+			return nil, err
+		}
+		result[i].file = m.sourceFile(f)
+
+		result[i].Developer.inheritAll(spaceDefaults.Developer, spaceDefaultsFile)
+		result[i].Developer.inherit(spaceDefaultsFile, spaceDefaults.DeveloperGroup)
+		result[i].Auditor.inheritAll(spaceDefaults.Auditor, spaceDefaultsFile)
+		result[i].Auditor.inherit(spaceDefaultsFile, spaceDefaults.AuditorGroup)
+		result[i].Manager.inheritAll(spaceDefaults.Manager, spaceDefaultsFile)
+		result[i].Manager.inherit(spaceDefaultsFile, spaceDefaults.ManagerGroup)
 		result[i].Developer.LDAPUsers = append(result[i].Developer.LDAPUsers, spaceDefaults.Developer.LDAPUsers...)
 		result[i].Developer.Users = append(result[i].Developer.Users, spaceDefaults.Developer.Users...)
 		result[i].Developer.SamlUsers = append(result[i].Developer.SamlUsers, spaceDefaults.Developer.SamlUsers...)
+		result[i].Developer.Clients = append(result[i].Developer.Clients, spaceDefaults.Developer.Clients...)
 
 		result[i].Auditor.LDAPUsers = append(result[i].Auditor.LDAPUsers, spaceDefaults.Auditor.LDAPUsers...)
 		result[i].Auditor.Users = append(result[i].Auditor.Users, spaceDefaults.Auditor.Users...)
 		result[i].Auditor.SamlUsers = append(result[i].Auditor.SamlUsers, spaceDefaults.Auditor.SamlUsers...)
+		result[i].Auditor.Clients = append(result[i].Auditor.Clients, spaceDefaults.Auditor.Clients...)
 
 		result[i].Manager.LDAPUsers = append(result[i].Manager.LDAPUsers, spaceDefaults.Manager.LDAPUsers...)
 		result[i].Manager.Users = append(result[i].Manager.Users, spaceDefaults.Manager.Users...)
 		result[i].Manager.SamlUsers = append(result[i].Manager.SamlUsers, spaceDefaults.Manager.SamlUsers...)
+		result[i].Manager.Clients = append(result[i].Manager.Clients, spaceDefaults.Manager.Clients...)
 
 		result[i].Developer.LDAPGroups = append(result[i].GetDeveloperGroups(), spaceDefaults.GetDeveloperGroups()...)
 		result[i].Auditor.LDAPGroups = append(result[i].GetAuditorGroups(), spaceDefaults.GetAuditorGroups()...)
 		result[i].Manager.LDAPGroups = append(result[i].GetManagerGroups(), spaceDefaults.GetManagerGroups()...)
+		result[i].ExcludeUsers = append(result[i].ExcludeUsers, spaceDefaults.ExcludeUsers...)
+
+		if roles := allSpaces[result[i].Org]; roles != nil {
+			result[i].Developer.merge(roles.Developer, orgFiles[result[i].Org])
+			result[i].Manager.merge(roles.Manager, orgFiles[result[i].Org])
+			result[i].Auditor.merge(roles.Auditor, orgFiles[result[i].Org])
+		}
+		groupMappings.applyToSpace(&result[i])
 
 		if result[i].EnableSecurityGroup {
-			securityGroupFile := strings.Replace(f, "spaceConfig.yml", "security-group.json", -1)
+			securityGroupFile := filepath.Join(filepath.Dir(f), "security-group.json")
 			lo.G.Debug("Loading security group contents", securityGroupFile)
 			bytes, err := ioutil.ReadFile(securityGroupFile)
 			if err != nil {
@@ -200,6 +406,34 @@ func (m *yamlManager) GetOrgConfig(orgName string) (*OrgConfig, error) {
 	return nil, fmt.Errorf("Org [%s] not found in config", orgName)
 }
 
+// GetRawOrgConfig reads the orgConfig of the org as the file sets it, without
+// what the org inherits from its org group or the groups group-mappings.yml
+// maps to it, so that config commands saving it do not write them into the
+// file
+func (m *yamlManager) GetRawOrgConfig(orgName string) (*OrgConfig, error) {
+	f := configFile(filepath.Join(m.ConfigDir, orgName), "orgConfig")
+	if !FileOrDirectoryExists(f) {
+		return nil, fmt.Errorf("Org [%s] not found in config", orgName)
+	}
+	orgConfig := &OrgConfig{}
+	if err := loadOrgConfig(f, orgConfig); err != nil {
+		return nil, err
+	}
+	orgConfig.file = m.sourceFile(f)
+	return orgConfig, nil
+}
+
+// loadOrgConfig reads an orgConfig file, the quota limits it does not set
+// defaulting to unlimited
+func loadOrgConfig(f string, orgConfig *OrgConfig) error {
+	orgConfig.AppTaskLimit = -1
+	orgConfig.AppInstanceLimit = -1
+	orgConfig.TotalReservedRoutePorts = 0
+	orgConfig.TotalPrivateDomains = -1
+	orgConfig.TotalServiceKeys = -1
+	return LoadFile(f, orgConfig)
+}
+
 func (m *yamlManager) SaveOrgConfig(orgConfig *OrgConfig) error {
 	directory := fmt.Sprintf("%s/%s", m.ConfigDir, orgConfig.Org)
 	if _, err := os.Stat(directory); os.IsNotExist(err) {
@@ -208,7 +442,7 @@ func (m *yamlManager) SaveOrgConfig(orgConfig *OrgConfig) error {
 		}
 	}
 
-	return WriteFile(filepath.Join(m.ConfigDir, orgConfig.Org, "orgConfig.yml"), orgConfig)
+	return WriteFile(configFile(filepath.Join(m.ConfigDir, orgConfig.Org), "orgConfig"), orgConfig)
 }
 
 func (m *yamlManager) GetSpaceConfig(orgName, spaceName string) (*SpaceConfig, error) {
@@ -224,11 +458,39 @@ func (m *yamlManager) GetSpaceConfig(orgName, spaceName string) (*SpaceConfig, e
 	return nil, fmt.Errorf("Space [%s] not found in org [%s] config", spaceName, orgName)
 }
 
+// GetRawSpaceConfig reads the spaceConfig of the space as the file sets it,
+// without the groups group-mappings.yml maps to it, the space defaults or the
+// roles of all-spaces of its org, so that config commands saving it do not
+// write them into the file
+func (m *yamlManager) GetRawSpaceConfig(orgName, spaceName string) (*SpaceConfig, error) {
+	f := configFile(filepath.Join(m.ConfigDir, orgName, spaceName), "spaceConfig")
+	if !FileOrDirectoryExists(f) {
+		return nil, fmt.Errorf("Space [%s] not found in org [%s] config", spaceName, orgName)
+	}
+	spaceConfig := &SpaceConfig{}
+	if err := loadSpaceConfig(f, spaceConfig); err != nil {
+		return nil, err
+	}
+	spaceConfig.file = m.sourceFile(f)
+	return spaceConfig, nil
+}
+
+// loadSpaceConfig reads a spaceConfig file, the quota limits it does not set
+// defaulting to unlimited
+func loadSpaceConfig(f string, spaceConfig *SpaceConfig) error {
+	spaceConfig.AppInstanceLimit = -1
+	spaceConfig.AppTaskLimit = -1
+	spaceConfig.TotalReservedRoutePorts = 0
+	spaceConfig.TotalPrivateDomains = -1
+	spaceConfig.TotalServiceKeys = -1
+	return LoadFile(f, spaceConfig)
+}
+
 func (m *yamlManager) SaveSpaceConfig(spaceConfig *SpaceConfig) error {
 	if err := os.MkdirAll(fmt.Sprintf("%s/%s/%s", m.ConfigDir, spaceConfig.Org, spaceConfig.Space), 0755); err != nil {
 		return err
 	}
-	return WriteFile(fmt.Sprintf("%s/%s/%s/spaceConfig.yml", m.ConfigDir, spaceConfig.Org, spaceConfig.Space), spaceConfig)
+	return WriteFile(configFile(filepath.Join(m.ConfigDir, spaceConfig.Org, spaceConfig.Space), "spaceConfig"), spaceConfig)
 }
 
 func (m *yamlManager) DeleteOrgConfig(orgName string) error {
@@ -441,11 +703,15 @@ func (m *yamlManager) LdapConfig(ldapBindPassword string) (*LdapConfig, error) {
 	}
 	if ldapBindPassword != "" {
 		config.BindPassword = ldapBindPassword
-	} else {
+	} else if config.KerberosKeytab == "" {
 		lo.G.Warning("Ldap bind password should be removed from ldap.yml as this will be deprecated in a future release.  Use --ldap-password flag instead.")
 	}
+	redact.Secrets(config.BindPassword)
 	if config.Origin == "" {
 		config.Origin = "ldap"
 	}
+	if err := config.validateUserNameMappings(); err != nil {
+		return nil, err
+	}
 	return config, nil
 }
@@ -1,13 +1,20 @@
 package config_test
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pivotalservices/cf-mgmt/config"
+	"gopkg.in/yaml.v2"
 )
 
 var _ = Describe("CF-Mgmt Config", func() {
@@ -17,13 +24,764 @@ var _ = Describe("CF-Mgmt Config", func() {
 				Ω(config.DefaultProtectedOrgs).Should(ContainElement("system"))
 				Ω(config.DefaultProtectedOrgs).Should(ContainElement("p-spring-cloud-services"))
 				Ω(config.DefaultProtectedOrgs).Should(ContainElement("splunk-nozzle-org"))
-				Ω(config.DefaultProtectedOrgs).Should(ContainElement("redis-test-ORG*"))
+				Ω(config.DefaultProtectedOrgs).Should(ContainElement("redis-test-ORG.*"))
 				Ω(config.DefaultProtectedOrgs).Should(ContainElement("appdynamics-org"))
 				Ω(config.DefaultProtectedOrgs).Should(HaveLen(5))
 			})
 		})
 	})
 
+	Context("Space Patterns", func() {
+		It("should match spaces against glob patterns only", func() {
+			spaces := config.Spaces{Org: "org1", Spaces: []string{"team-*", "sandbox"}}
+			Ω(config.IsSpacePattern("team-*")).Should(BeTrue())
+			Ω(config.IsSpacePattern("sandbox")).Should(BeFalse())
+			Ω(spaces.MatchesPattern("team-a")).Should(BeTrue())
+			Ω(spaces.MatchesPattern("sandbox")).Should(BeFalse())
+		})
+	})
+
+	Context("ASG Profiles", func() {
+		orgConfig := config.OrgConfig{
+			Org:               "org1",
+			DefaultASGProfile: "web",
+			ASGProfiles: map[string]config.ASGProfile{
+				"web":   config.ASGProfile{ASGs: []string{"dns", "proxy"}},
+				"batch": config.ASGProfile{ASGs: []string{"dns"}, StagingASGs: []string{"artifactory"}},
+			},
+		}
+
+		It("should inherit the default profile", func() {
+			asgs, stagingASGs, err := orgConfig.SpaceASGs(&config.SpaceConfig{Org: "org1"})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(asgs).Should(Equal([]string{"dns", "proxy"}))
+			Ω(stagingASGs).Should(BeEmpty())
+		})
+
+		It("should override the default profile with named asgs", func() {
+			asgs, _, err := orgConfig.SpaceASGs(&config.SpaceConfig{Org: "org1", ASGs: []string{"db"}})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(asgs).Should(Equal([]string{"db"}))
+		})
+
+		It("should add named asgs to the referenced profile", func() {
+			asgs, stagingASGs, err := orgConfig.SpaceASGs(&config.SpaceConfig{Org: "org1", ASGProfile: "batch", ASGs: []string{"db"}})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(asgs).Should(Equal([]string{"dns", "db"}))
+			Ω(stagingASGs).Should(Equal([]string{"artifactory"}))
+			Ω(orgConfig.ASGProfiles["batch"].ASGs).Should(Equal([]string{"dns"}))
+		})
+
+		It("should error for an undefined profile", func() {
+			_, _, err := orgConfig.SpaceASGs(&config.SpaceConfig{Org: "org1", ASGProfile: "missing"})
+			Ω(err).Should(MatchError("ASG profile [missing] is not defined in org [org1]"))
+		})
+	})
+
+	Context("JSON Config", func() {
+		var (
+			tempDir string
+			m       config.Manager
+		)
+
+		BeforeEach(func() {
+			var err error
+			tempDir, err = ioutil.TempDir("", "cf-mgmt")
+			Ω(err).ShouldNot(HaveOccurred())
+			m = config.NewManager(path.Join(tempDir, "config"))
+			Ω(m.CreateConfigIfNotExists("ldap")).Should(Succeed())
+			Ω(m.AddOrgToConfig(&config.OrgConfig{Org: "org1"})).Should(Succeed())
+			Ω(m.AddOrgToConfig(&config.OrgConfig{Org: "org2"})).Should(Succeed())
+			Ω(m.AddSpaceToConfig(&config.SpaceConfig{Org: "org2", Space: "space1"})).Should(Succeed())
+			Ω(os.Remove(path.Join(tempDir, "config", "org2", "orgConfig.yml"))).Should(Succeed())
+			Ω(os.Remove(path.Join(tempDir, "config", "org2", "space1", "spaceConfig.yml"))).Should(Succeed())
+			Ω(ioutil.WriteFile(path.Join(tempDir, "config", "org2", "orgConfig.json"),
+				[]byte(`{"org": "org2", "enable-org-quota": true, "memory-limit": 2048, "org-manager": {"ldap_groups": ["managers"]}}`), 0644)).Should(Succeed())
+			Ω(ioutil.WriteFile(path.Join(tempDir, "config", "org2", "space1", "spaceConfig.json"),
+				[]byte("{\n\t\"org\": \"org2\",\n\t\"space\": \"space1\",\n\t\"allow-ssh\": true\n}\n"), 0644)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(tempDir)
+		})
+
+		It("should read orgConfig.json and spaceConfig.json alongside yaml", func() {
+			orgConfigs, err := m.GetOrgConfigs()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(orgConfigs).Should(HaveLen(2))
+			orgConfig, err := m.GetOrgConfig("org2")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(orgConfig.EnableOrgQuota).Should(BeTrue())
+			Ω(orgConfig.MemoryLimit).Should(Equal(2048))
+			Ω(orgConfig.AppInstanceLimit).Should(Equal(-1))
+			Ω(orgConfig.Manager.LDAPGroups).Should(ConsistOf("managers"))
+			Ω(orgConfig.ConfigFile()).Should(Equal("org2/orgConfig.json"))
+			spaceConfig, err := m.GetSpaceConfig("org2", "space1")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(spaceConfig.AllowSSH).Should(BeTrue())
+			Ω(spaceConfig.ConfigFile()).Should(Equal("org2/space1/spaceConfig.json"))
+		})
+
+		It("should save a config read from json as json", func() {
+			orgConfig, err := m.GetOrgConfig("org2")
+			Ω(err).ShouldNot(HaveOccurred())
+			orgConfig.MemoryLimit = 4096
+			Ω(m.SaveOrgConfig(orgConfig)).Should(Succeed())
+			Ω(config.FileOrDirectoryExists(path.Join(tempDir, "config", "org2", "orgConfig.yml"))).Should(BeFalse())
+			orgConfig, err = m.GetOrgConfig("org2")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(orgConfig.MemoryLimit).Should(Equal(4096))
+		})
+
+		It("should ignore files that only start with the name of a config file", func() {
+			Ω(ioutil.WriteFile(path.Join(tempDir, "config", "org2", "orgConfig.yml.bak"), []byte("org: org2\n"), 0644)).Should(Succeed())
+			Ω(ioutil.WriteFile(path.Join(tempDir, "config", "org2", "space1", "spaceConfig.json~"), []byte("{}\n"), 0644)).Should(Succeed())
+			orgConfigs, err := m.GetOrgConfigs()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(orgConfigs).Should(HaveLen(2))
+			spaceConfigs, err := m.GetSpaceConfigs()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(spaceConfigs).ShouldNot(BeEmpty())
+		})
+
+		It("should fail when an org has both yaml and json config", func() {
+			Ω(ioutil.WriteFile(path.Join(tempDir, "config", "org2", "orgConfig.yml"), []byte("org: org2\n"), 0644)).Should(Succeed())
+			_, err := m.GetOrgConfigs()
+			Ω(err).Should(MatchError(ContainSubstring("configure the same org")))
+		})
+
+		It("should fail on malformed json", func() {
+			Ω(ioutil.WriteFile(path.Join(tempDir, "config", "org2", "orgConfig.json"), []byte(`{"org": `), 0644)).Should(Succeed())
+			_, err := m.GetOrgConfigs()
+			Ω(err).Should(MatchError(ContainSubstring("is not valid json")))
+		})
+	})
+
+	Context("Effective Config", func() {
+		var (
+			tempDir string
+			m       config.Manager
+		)
+
+		BeforeEach(func() {
+			var err error
+			tempDir, err = ioutil.TempDir("", "cf-mgmt")
+			Ω(err).ShouldNot(HaveOccurred())
+			m = config.NewManager(path.Join(tempDir, "config"))
+			Ω(m.CreateConfigIfNotExists("ldap")).Should(Succeed())
+			Ω(m.AddOrgToConfig(&config.OrgConfig{Org: "org1"})).Should(Succeed())
+			Ω(m.AddSpaceToConfig(&config.SpaceConfig{Org: "org1", Space: "space1", ASGProfile: "batch", DeveloperGroup: "devs-b", Developer: config.UserMgmt{LDAPGroups: []string{"devs-a"}}})).Should(Succeed())
+			Ω(m.AddSpaceToConfig(&config.SpaceConfig{Org: "org1", Space: "team-*", ASGs: []string{"db"}})).Should(Succeed())
+			Ω(m.SaveOrgConfig(&config.OrgConfig{
+				Org:          "org1",
+				ManagerGroup: "managers-b",
+				Manager:      config.UserMgmt{LDAPGroups: []string{"managers-a"}},
+				ASGProfiles: map[string]config.ASGProfile{
+					"batch": config.ASGProfile{ASGs: []string{"dns"}, StagingASGs: []string{"artifactory"}},
+				},
+			})).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(tempDir)
+		})
+
+		It("should fold the org groups into sorted ldap groups", func() {
+			orgConfig, err := config.EffectiveOrgConfig(m, "org1")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(orgConfig.Manager.LDAPGroups).Should(Equal([]string{"managers-a", "managers-b"}))
+			Ω(orgConfig.ManagerGroup).Should(BeEmpty())
+		})
+
+		It("should resolve the asg profile of a space", func() {
+			spaceConfig, err := config.EffectiveSpaceConfig(m, "org1", "space1")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(spaceConfig.ASGs).Should(Equal([]string{"dns"}))
+			Ω(spaceConfig.StagingASGs).Should(Equal([]string{"artifactory"}))
+			Ω(spaceConfig.ASGProfile).Should(BeEmpty())
+			Ω(spaceConfig.Developer.LDAPGroups).Should(Equal([]string{"devs-a", "devs-b"}))
+		})
+
+		It("should use the config of the space pattern matching a space", func() {
+			spaceConfig, err := config.EffectiveSpaceConfig(m, "org1", "team-a")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(spaceConfig.Space).Should(Equal("team-a"))
+			Ω(spaceConfig.ASGs).Should(Equal([]string{"db"}))
+		})
+
+		It("should error for a space that is not configured", func() {
+			_, err := config.EffectiveSpaceConfig(m, "org1", "sandbox")
+			Ω(err).Should(MatchError("Space [sandbox] not found in org [org1] config"))
+		})
+	})
+
+	Context("Maintenance Windows", func() {
+		// a saturday
+		now := time.Date(2018, time.June, 2, 2, 30, 0, 0, time.UTC)
+
+		It("should always be in the window without one", func() {
+			inWindow, err := (&config.OrgConfig{Org: "org1"}).InMaintenanceWindow(now)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(inWindow).Should(BeTrue())
+		})
+
+		It("should be in the window for its duration", func() {
+			orgConfig := &config.OrgConfig{Org: "org1", MaintenanceWindow: "0 2 * * sat"}
+			Ω(orgConfig.InMaintenanceWindow(now)).Should(BeTrue())
+			Ω(orgConfig.InMaintenanceWindow(now.Add(time.Hour))).Should(BeFalse())
+			orgConfig.MaintenanceWindowMinutes = 20
+			Ω(orgConfig.InMaintenanceWindow(now)).Should(BeFalse())
+		})
+
+		It("should support lists, ranges and steps", func() {
+			Ω((&config.OrgConfig{MaintenanceWindow: "*/15 1-3 * * 1-5,7"}).InMaintenanceWindow(now)).Should(BeFalse())
+			Ω((&config.OrgConfig{MaintenanceWindow: "*/15 1-3 * * 6", MaintenanceWindowMinutes: 1}).InMaintenanceWindow(now)).Should(BeTrue())
+			Ω((&config.OrgConfig{MaintenanceWindow: "0 2 1,15 jun mon", MaintenanceWindowMinutes: 1}).InMaintenanceWindow(now.Add(-30 * time.Minute))).Should(BeFalse())
+			Ω((&config.OrgConfig{MaintenanceWindow: "0 2 2 jun mon", MaintenanceWindowMinutes: 1}).InMaintenanceWindow(now.Add(-30 * time.Minute))).Should(BeTrue())
+		})
+
+		It("should error for invalid expressions", func() {
+			_, err := (&config.OrgConfig{Org: "org1", MaintenanceWindow: "0 25 * * *"}).InMaintenanceWindow(now)
+			Ω(err).Should(MatchError("maintenance-window of org [org1]: cron expression [0 25 * * *]: invalid value [25] in hour, must be between 0 and 23"))
+			_, err = (&config.OrgConfig{Org: "org1", MaintenanceWindow: "0 2 * *"}).InMaintenanceWindow(now)
+			Ω(err).Should(HaveOccurred())
+		})
+
+		It("should list the orgs outside their window", func() {
+			deferred, err := config.OrgsOutsideMaintenanceWindow([]config.OrgConfig{
+				config.OrgConfig{Org: "org1"},
+				config.OrgConfig{Org: "org2", MaintenanceWindow: "0 0 30 2 *"},
+			}, now)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(deferred).Should(Equal(map[string]bool{"org2": true}))
+		})
+	})
+
+	Context("Ephemeral Spaces", func() {
+		// a saturday
+		now := time.Date(2018, time.June, 2, 2, 30, 0, 0, time.UTC)
+
+		It("should not recycle spaces that are not ephemeral", func() {
+			Ω((&config.SpaceConfig{Space: "space1"}).RecycleDue(now.Add(-72*time.Hour), now)).Should(BeFalse())
+		})
+
+		It("should recycle nightly by default", func() {
+			spaceConfig := &config.SpaceConfig{Space: "space1", Ephemeral: true}
+			Ω(spaceConfig.RecycleDue(now.Add(-3*time.Hour), now)).Should(BeTrue())
+			Ω(spaceConfig.RecycleDue(now.Add(-2*time.Hour), now)).Should(BeFalse())
+		})
+
+		It("should recycle weekly on sunday", func() {
+			spaceConfig := &config.SpaceConfig{Space: "space1", Ephemeral: true, RecycleSchedule: config.RecycleWeekly}
+			Ω(spaceConfig.RecycleDue(now.Add(-72*time.Hour), now)).Should(BeFalse())
+			Ω(spaceConfig.RecycleDue(now.Add(-72*time.Hour), now.Add(24*time.Hour))).Should(BeTrue())
+		})
+
+		It("should error for invalid schedules", func() {
+			_, err := (&config.SpaceConfig{Org: "org1", Space: "space1", Ephemeral: true, RecycleSchedule: "hourly"}).RecycleDue(now, now)
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("Org Templates", func() {
+		It("should expand the range of orgs", func() {
+			names, err := (&config.OrgTemplate{Orgs: "student-098..student-101"}).OrgNames()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(names).Should(Equal([]string{"student-098", "student-099", "student-100", "student-101"}))
+		})
+
+		It("should error for invalid ranges", func() {
+			_, err := (&config.OrgTemplate{Name: "workshop", Orgs: "student-001"}).OrgNames()
+			Ω(err).Should(HaveOccurred())
+			_, err = (&config.OrgTemplate{Name: "workshop", Orgs: "student-001..teacher-002"}).OrgNames()
+			Ω(err).Should(HaveOccurred())
+			_, err = (&config.OrgTemplate{Name: "workshop", Orgs: "student-002..student-001"}).OrgNames()
+			Ω(err).Should(HaveOccurred())
+		})
+
+		It("should require a user per org", func() {
+			_, _, err := (&config.OrgTemplate{Name: "workshop", Orgs: "student-1..student-2", User: "student@example.com"}).Generate()
+			Ω(err).Should(MatchError("user [student@example.com] of org template [workshop] must contain {org}, so that each org gets its own user"))
+		})
+	})
+
+	Context("Config Files", func() {
+		It("should name the file of an org, a space and a space matched by a pattern", func() {
+			Ω((&config.OrgConfig{Org: "org1"}).ConfigFile()).Should(Equal("org1/orgConfig.yml"))
+			Ω((&config.SpaceConfig{Org: "org1", Space: "dev"}).ConfigFile()).Should(Equal("org1/dev/spaceConfig.yml"))
+			Ω((&config.SpaceConfig{Org: "org1", Space: "team-a", Pattern: "team-*"}).ConfigFile()).Should(Equal("org1/team-*/spaceConfig.yml"))
+		})
+	})
+
+	Context("Approvals", func() {
+		approvals := &config.Approvals{
+			RequireApprovals: true,
+			Approvals: []config.Approval{
+				config.Approval{Ticket: "CHG-1", Approver: "ops", Change: config.ChangeRemoveUser, Org: "org1", Space: "dev"},
+				config.Approval{Ticket: "CHG-2", Approver: "ops", Change: config.ChangeShrinkQuota, Org: "org1"},
+				config.Approval{Ticket: "CHG-3", Approver: "ops", Change: config.ChangeDeleteIdentityProvider, Origin: "old-saml"},
+			},
+		}
+
+		It("should approve matching changes", func() {
+			Ω(approvals.Check(
+				config.Change{Kind: config.ChangeRemoveUser, Org: "ORG1", Space: "dev", User: "user1"},
+				config.Change{Kind: config.ChangeShrinkQuota, Org: "org1", Space: "dev"},
+				config.Change{Kind: config.ChangeDeleteIdentityProvider, Origin: "old-saml"},
+			)).Should(Succeed())
+			Ω(approvals.Check(config.Change{Kind: config.ChangeDeleteIdentityProvider, Origin: "other-saml"})).Should(MatchError("approvals.yml has no approval for: delete-identity-provider other-saml"))
+		})
+
+		It("should list the changes without an approval", func() {
+			err := approvals.Check(
+				config.Change{Kind: config.ChangeRemoveUser, Org: "org1", Space: "prod", User: "user1"},
+				config.Change{Kind: config.ChangeRemoveUser, Org: "org1", Space: "dev", User: "user1"},
+				config.Change{Kind: config.ChangeDeleteSpace, Org: "org1", Space: "dev"},
+			)
+			Ω(err).Should(MatchError("approvals.yml has no approval for: remove-user [user1] in org1/prod, delete-space org1/dev"))
+		})
+
+		It("should not require approvals unless enabled", func() {
+			var none *config.Approvals
+			Ω(none.Check(config.Change{Kind: config.ChangeDeleteOrg, Org: "org1"})).Should(Succeed())
+			Ω((&config.Approvals{}).Check(config.Change{Kind: config.ChangeDeleteOrg, Org: "org1"})).Should(Succeed())
+		})
+	})
+
+	Context("Identity Providers", func() {
+		var tempDir string
+		BeforeEach(func() {
+			var err error
+			tempDir, err = ioutil.TempDir("", "cf-mgmt")
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+		AfterEach(func() {
+			os.RemoveAll(tempDir)
+		})
+		write := func(contents string) {
+			Ω(ioutil.WriteFile(path.Join(tempDir, "identity-providers.yml"), []byte(contents), 0644)).Should(Succeed())
+		}
+
+		It("should have no providers without identity-providers.yml", func() {
+			providers, err := config.NewManager(tempDir).GetIdentityProviders()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(providers).Should(BeNil())
+		})
+
+		It("should read saml and oidc providers", func() {
+			write(`delete-unmanaged: true
+identity-providers:
+- origin: okta
+  type: saml
+  metadata: https://okta/metadata
+  email-domains: [example.com]
+  attribute-mappings:
+    email: emailAddress
+- origin: azure
+  type: oidc1.0
+  discovery-url: https://azure/.well-known/openid-configuration
+  client-id: cf
+  client-secret-env: AZURE_CLIENT_SECRET
+`)
+			providers, err := config.NewManager(tempDir).GetIdentityProviders()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(providers.DeleteUnmanaged).Should(BeTrue())
+			Ω(providers.Providers).Should(HaveLen(2))
+			Ω(providers.Providers[0].AttributeMappings).Should(Equal(map[string]string{"email": "emailAddress"}))
+			Ω(providers.Providers[1].ClientSecretEnv).Should(Equal("AZURE_CLIENT_SECRET"))
+		})
+
+		It("should require the metadata of saml providers", func() {
+			write(`identity-providers:
+- origin: okta
+  type: saml
+`)
+			_, err := config.NewManager(tempDir).GetIdentityProviders()
+			Ω(err).Should(MatchError("saml identity provider [okta] in identity-providers.yml requires metadata"))
+		})
+
+		It("should not manage the uaa provider", func() {
+			write(`identity-providers:
+- origin: uaa
+  type: saml
+  metadata: https://okta/metadata
+`)
+			_, err := config.NewManager(tempDir).GetIdentityProviders()
+			Ω(err).Should(MatchError("identity provider [uaa] in identity-providers.yml cannot be managed, only saml and oidc providers can"))
+		})
+	})
+
+	Context("Token Policy", func() {
+		var tempDir string
+		BeforeEach(func() {
+			var err error
+			tempDir, err = ioutil.TempDir("", "cf-mgmt")
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+		AfterEach(func() {
+			os.RemoveAll(tempDir)
+		})
+		write := func(contents string) {
+			Ω(ioutil.WriteFile(path.Join(tempDir, "cf-mgmt.yml"), []byte(contents), 0644)).Should(Succeed())
+		}
+
+		It("should read the token policy", func() {
+			write(`token-policy:
+  access-token-validity: 3600
+  jwt-revocable: true
+  active-key-id: key-2
+  keys:
+  - id: key-1
+    signing-key-env: TOKEN_KEY_1
+  - id: key-2
+    signing-key-env: TOKEN_KEY_2
+`)
+			globalConfig, err := config.NewManager(tempDir).GetGlobalConfig()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(globalConfig.TokenPolicy.AccessTokenValidity).Should(Equal(3600))
+			Ω(globalConfig.TokenPolicy.RefreshTokenValidity).Should(Equal(0))
+			Ω(*globalConfig.TokenPolicy.JwtRevocable).Should(BeTrue())
+			Ω(globalConfig.TokenPolicy.RefreshTokenUnique).Should(BeNil())
+			Ω(globalConfig.TokenPolicy.Keys).Should(HaveLen(2))
+		})
+
+		It("should require the active key to be one of the keys", func() {
+			write(`token-policy:
+  active-key-id: key-3
+  keys:
+  - id: key-1
+    signing-key-env: TOKEN_KEY_1
+`)
+			_, err := config.NewManager(tempDir).GetGlobalConfig()
+			Ω(err).Should(MatchError("active-key-id [key-3] of token-policy must be one of its keys"))
+		})
+
+		It("should read the plugins", func() {
+			write(`plugins:
+- name: chargeback
+  command: plugins/chargeback.sh
+  args: [--region, east]
+  hooks: [post-org-create]
+  fail-apply: true
+`)
+			globalConfig, err := config.NewManager(tempDir).GetGlobalConfig()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(globalConfig.Plugins).Should(Equal([]config.Plugin{
+				{Name: "chargeback", Command: "plugins/chargeback.sh", Args: []string{"--region", "east"}, Hooks: []string{"post-org-create"}, FailApply: true},
+			}))
+		})
+
+		It("should require the hooks of plugins to be pre or post hooks", func() {
+			write(`plugins:
+- name: chargeback
+  command: plugins/chargeback.sh
+  hooks: [org-create]
+`)
+			_, err := config.NewManager(tempDir).GetGlobalConfig()
+			Ω(err).Should(MatchError("hook [org-create] of plugin [chargeback] must start with pre- or post-"))
+		})
+
+		It("should read the command hooks", func() {
+			write(`command-hooks:
+- name: smoke-tests
+  commands: [apply]
+  when: after
+  url: https://ci.example.com/smoke-tests
+  fail-command: true
+`)
+			globalConfig, err := config.NewManager(tempDir).GetGlobalConfig()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(globalConfig.CommandHooks).Should(HaveLen(1))
+			hook := globalConfig.CommandHooks[0]
+			Ω(hook.URL).Should(Equal("https://ci.example.com/smoke-tests"))
+			Ω(hook.RunsFor("apply", config.HookAfter)).Should(BeTrue())
+			Ω(hook.RunsFor("apply", config.HookBefore)).Should(BeFalse())
+			Ω(hook.RunsFor("create-orgs", config.HookAfter)).Should(BeFalse())
+		})
+
+		It("should require command hooks to either run a command or post to a url", func() {
+			write(`command-hooks:
+- name: warm-cache
+  when: before
+`)
+			_, err := config.NewManager(tempDir).GetGlobalConfig()
+			Ω(err).Should(MatchError("command hook [warm-cache] requires either run or url"))
+		})
+
+		It("should require the space quota tolerance to be at least 0", func() {
+			write("space-quota-tolerance: -10\n")
+			_, err := config.NewManager(tempDir).GetGlobalConfig()
+			Ω(err).Should(MatchError("space-quota-tolerance must be a percentage of at least 0, not -10"))
+		})
+	})
+
+	Context("Includes", func() {
+		const fragment = `running-security-groups:
+- standard-dns
+- standard-ntp
+enable-delete-isolation-segments: true
+enable-unassign-security-groups: true
+`
+		var (
+			server   *httptest.Server
+			requests int
+			tempDir  string
+			m        config.Manager
+		)
+		BeforeEach(func() {
+			requests = 0
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if r.URL.Path != "/shared.yml" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				fmt.Fprint(w, fragment)
+			}))
+			var err error
+			tempDir, err = ioutil.TempDir("", "cf-mgmt")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(os.MkdirAll(path.Join(tempDir, "config"), 0755)).Should(Succeed())
+			m = config.NewManager(path.Join(tempDir, "config"))
+		})
+		AfterEach(func() {
+			server.Close()
+			os.RemoveAll(tempDir)
+		})
+
+		writeGlobal := func(contents string) {
+			Ω(ioutil.WriteFile(path.Join(tempDir, "config", "cf-mgmt.yml"), []byte(contents), 0644)).Should(Succeed())
+		}
+		checksum := func(contents string) string {
+			sum := sha256.Sum256([]byte(contents))
+			return hex.EncodeToString(sum[:])
+		}
+
+		It("should merge pinned fragments into the file", func() {
+			writeGlobal(fmt.Sprintf(`include:
+- url: %s/shared.yml
+  sha256: %s
+running-security-groups:
+- foundation-only
+- standard-dns
+enable-unassign-security-groups: false
+`, server.URL, checksum(fragment)))
+			globalConfig, err := m.GetGlobalConfig()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(globalConfig.RunningSecurityGroups).Should(Equal([]string{"foundation-only", "standard-dns", "standard-ntp"}))
+			Ω(globalConfig.EnableDeleteIsolationSegments).Should(BeTrue())
+			Ω(globalConfig.EnableUnassignSecurityGroups).Should(BeFalse())
+
+			_, err = m.GetGlobalConfig()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(requests).Should(Equal(1))
+		})
+
+		It("should keep the include when the file is written back", func() {
+			writeGlobal(fmt.Sprintf(`include:
+- url: %s/shared.yml
+  sha256: %s
+running-security-groups:
+- foundation-only
+`, server.URL, checksum(fragment)))
+			globalConfig, err := m.GetGlobalConfig()
+			Ω(err).ShouldNot(HaveOccurred())
+			globalConfig.StagingSecurityGroups = []string{"staging"}
+			Ω(m.SaveGlobalConfig(globalConfig)).Should(Succeed())
+
+			data, err := config.LoadFileBytes(path.Join(tempDir, "config", "cf-mgmt.yml"))
+			Ω(err).ShouldNot(HaveOccurred())
+			written := make(map[string]interface{})
+			Ω(yaml.Unmarshal(data, &written)).Should(Succeed())
+			Ω(written).Should(HaveKey("include"))
+			Ω(written).ShouldNot(HaveKey("enable-delete-isolation-segments"))
+			Ω(written["running-security-groups"]).Should(Equal([]interface{}{"foundation-only"}))
+
+			globalConfig, err = m.GetGlobalConfig()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(globalConfig.RunningSecurityGroups).Should(Equal([]string{"foundation-only", "standard-dns", "standard-ntp"}))
+			Ω(globalConfig.StagingSecurityGroups).Should(Equal([]string{"staging"}))
+			Ω(globalConfig.EnableDeleteIsolationSegments).Should(BeTrue())
+		})
+
+		It("should merge the rules of fragments into json security groups", func() {
+			rules := `[{"protocol": "udp", "destination": "10.0.0.2", "ports": "53"}]`
+			server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, rules)
+			})
+			Ω(os.MkdirAll(path.Join(tempDir, "config", "asgs"), 0755)).Should(Succeed())
+			Ω(ioutil.WriteFile(path.Join(tempDir, "config", "asgs", "dns.json"), []byte(fmt.Sprintf(`{
+  "include": [{"url": "%s/dns.json", "sha256": "%s"}],
+  "rules": [{"protocol": "tcp", "destination": "10.0.0.3", "ports": "53"}]
+}`, server.URL, checksum(rules))), 0644)).Should(Succeed())
+			asgConfigs, err := m.GetASGConfigs()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(asgConfigs).Should(HaveLen(1))
+			Ω(asgConfigs[0].Name).Should(Equal("dns"))
+			Ω(asgConfigs[0].Rules).Should(MatchJSON(`[
+  {"protocol": "tcp", "destination": "10.0.0.3", "ports": "53"},
+  {"protocol": "udp", "destination": "10.0.0.2", "ports": "53"}
+]`))
+		})
+
+		It("should error when the checksum does not match", func() {
+			writeGlobal(fmt.Sprintf(`include:
+- url: %s/shared.yml
+  sha256: %s
+`, server.URL, checksum("something else")))
+			_, err := m.GetGlobalConfig()
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring("instead of the pinned " + checksum("something else")))
+		})
+
+		It("should error for fragments that are not pinned", func() {
+			writeGlobal(fmt.Sprintf(`include:
+- url: %s/shared.yml
+`, server.URL))
+			var globalConfig config.GlobalConfig
+			err := config.LoadFile(path.Join(tempDir, "config", "cf-mgmt.yml"), &globalConfig)
+			Ω(err).Should(MatchError(fmt.Sprintf("include of %s: %s/shared.yml must be pinned with its sha256 checksum", path.Join(tempDir, "config", "cf-mgmt.yml"), server.URL)))
+			Ω(requests).Should(Equal(0))
+		})
+
+		It("should error when the fragment cannot be fetched", func() {
+			writeGlobal(fmt.Sprintf(`include:
+- url: %s/missing.yml
+  sha256: %s
+`, server.URL, checksum(fragment)))
+			var globalConfig config.GlobalConfig
+			err := config.LoadFile(path.Join(tempDir, "config", "cf-mgmt.yml"), &globalConfig)
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring("returned 404"))
+		})
+	})
+
+	Context("Protected Orgs", func() {
+		It("always protects the default protected orgs", func() {
+			orgs := &config.Orgs{ProtectedOrgs: []string{"^sandbox-.*"}}
+			Ω(orgs.IsProtected("system")).Should(BeTrue())
+			Ω(orgs.IsProtected("p-spring-cloud-services")).Should(BeTrue())
+			Ω(orgs.IsProtected("sandbox-alice")).Should(BeTrue())
+			Ω(orgs.IsProtected("payments")).Should(BeFalse())
+		})
+
+		It("matches the whole org name", func() {
+			orgs := &config.Orgs{ProtectedOrgs: []string{"sandbox"}}
+			Ω(orgs.IsProtected("sandbox")).Should(BeTrue())
+			Ω(orgs.IsProtected("my-sandbox")).Should(BeFalse())
+			Ω(orgs.IsProtected("my-system-org")).Should(BeFalse())
+			Ω(orgs.IsProtected("redis-test-ORG-1")).Should(BeTrue())
+		})
+
+		It("lists the default protected orgs once", func() {
+			protectedOrgs := config.ProtectedOrgs([]string{"system", "sandbox"})
+			Ω(protectedOrgs).Should(HaveLen(len(config.DefaultProtectedOrgs) + 1))
+			Ω(protectedOrgs[len(protectedOrgs)-1]).Should(Equal("sandbox"))
+		})
+	})
+
+	Context("Space Quota Overcommits", func() {
+		orgConfigs := []config.OrgConfig{
+			{Org: "payments", EnableOrgQuota: true, MemoryLimit: 10240, TotalRoutes: 100, TotalServices: -1, AppInstanceLimit: 50},
+			{Org: "no-quota", MemoryLimit: 1024},
+		}
+		spaceConfigs := []config.SpaceConfig{
+			{Org: "payments", Space: "prod", EnableSpaceQuota: true, MemoryLimit: 8192, TotalRoutes: 60, TotalServices: 20, AppInstanceLimit: -1},
+			{Org: "payments", Space: "dev", EnableSpaceQuota: true, MemoryLimit: 3072, TotalRoutes: 40, TotalServices: 20, AppInstanceLimit: 10},
+			{Org: "payments", Space: "sandbox", MemoryLimit: 8192},
+			{Org: "no-quota", Space: "dev", EnableSpaceQuota: true, MemoryLimit: 4096},
+		}
+
+		It("flags the limits of org quotas that the space quotas add up to more than", func() {
+			overcommits := config.SpaceQuotaOvercommits(orgConfigs, spaceConfigs, 0)
+			Ω(overcommits).Should(Equal([]config.QuotaOvercommit{
+				{Org: "payments", Limit: "memory-limit", OrgLimit: 10240, SpaceTotal: 11264, Spaces: []string{"dev", "prod"}},
+			}))
+			Ω(overcommits[0].String()).Should(Equal("space quotas of org payments add up to memory-limit 11264, over the 10240 of its org quota"))
+		})
+
+		It("allows the space quotas to add up to the tolerance beyond the org quota", func() {
+			Ω(config.SpaceQuotaOvercommits(orgConfigs, spaceConfigs, 10)).Should(BeEmpty())
+		})
+	})
+
+	Context("RunState", func() {
+		var tempDir string
+		var m config.Manager
+		BeforeEach(func() {
+			var err error
+			tempDir, err = ioutil.TempDir("", "cf-mgmt")
+			Ω(err).ShouldNot(HaveOccurred())
+			m = config.NewManager(path.Join(tempDir, "config"))
+			Ω(m.CreateConfigIfNotExists("ldap")).Should(Succeed())
+			Ω(m.AddOrgToConfig(&config.OrgConfig{Org: "org1"})).Should(Succeed())
+			Ω(m.AddOrgToConfig(&config.OrgConfig{Org: "org2"})).Should(Succeed())
+			Ω(m.AddSpaceToConfig(&config.SpaceConfig{Org: "org2", Space: "dev"})).Should(Succeed())
+		})
+		AfterEach(func() {
+			os.RemoveAll(tempDir)
+		})
+
+		It("should list the orgs whose config changed since the last run of the command", func() {
+			stateFile := path.Join(tempDir, "state", "state.json")
+			state, err := config.LoadRunState(stateFile)
+			Ω(err).ShouldNot(HaveOccurred())
+			hashes, err := config.OrgConfigHashes(m)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(hashes).Should(HaveLen(2))
+			Ω(state.ChangedOrgs("update-spaces", hashes)).Should(Equal([]string{"org1", "org2"}))
+
+			state.Update("update-spaces", hashes)
+			Ω(state.Save(stateFile)).Should(Succeed())
+			state, err = config.LoadRunState(stateFile)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(state.ChangedOrgs("update-spaces", hashes)).Should(BeEmpty())
+			Ω(state.ChangedOrgs("update-org-users", hashes)).Should(Equal([]string{"org1", "org2"}))
+
+			spaceConfig, err := m.GetSpaceConfig("org2", "dev")
+			Ω(err).ShouldNot(HaveOccurred())
+			spaceConfig.AllowSSH = true
+			Ω(m.SaveSpaceConfig(spaceConfig)).Should(Succeed())
+			hashes, err = config.OrgConfigHashes(m)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(state.ChangedOrgs("update-spaces", hashes)).Should(Equal([]string{"org2"}))
+		})
+
+		It("should only record the orgs a run processed", func() {
+			state, err := config.LoadRunState(path.Join(tempDir, "state.json"))
+			Ω(err).ShouldNot(HaveOccurred())
+			hashes, err := config.OrgConfigHashes(m)
+			Ω(err).ShouldNot(HaveOccurred())
+			state.UpdateOrgs("update-spaces", hashes, []string{"org1"})
+			Ω(state.ChangedOrgs("update-spaces", hashes)).Should(Equal([]string{"org2"}))
+			state.UpdateOrgs("update-spaces", hashes, []string{"org2"})
+			Ω(state.ChangedOrgs("update-spaces", hashes)).Should(BeEmpty())
+		})
+
+		It("should change every org when cf-mgmt.yml changes", func() {
+			state, err := config.LoadRunState(path.Join(tempDir, "state.json"))
+			Ω(err).ShouldNot(HaveOccurred())
+			hashes, err := config.OrgConfigHashes(m)
+			Ω(err).ShouldNot(HaveOccurred())
+			state.Update("update-spaces", hashes)
+			Ω(m.SaveGlobalConfig(&config.GlobalConfig{EnableUnassignSecurityGroups: true})).Should(Succeed())
+			hashes, err = config.OrgConfigHashes(m)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(state.ChangedOrgs("update-spaces", hashes)).Should(Equal([]string{"org1", "org2"}))
+		})
+
+		It("should change the config hash when the config of any org changes", func() {
+			hash, err := config.ConfigHash(m)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(config.ConfigHash(m)).Should(Equal(hash))
+			spaceConfig, err := m.GetSpaceConfig("org2", "dev")
+			Ω(err).ShouldNot(HaveOccurred())
+			spaceConfig.AllowSSH = true
+			Ω(m.SaveSpaceConfig(spaceConfig)).Should(Succeed())
+			Ω(config.ConfigHash(m)).ShouldNot(Equal(hash))
+		})
+	})
+
 	Context("Default Config Reader", func() {
 		Context("GetASGConfigs", func() {
 			It("should return a single ASG", func() {
@@ -50,6 +808,45 @@ var _ = Describe("CF-Mgmt Config", func() {
 
 			})
 
+			It("should return ASGs generated from named endpoints", func() {
+				m := config.NewManager("./fixtures/asg-endpoints")
+				cfgs, err := m.GetASGConfigs()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(cfgs).Should(HaveLen(1))
+				Expect(cfgs[0].Name).Should(Equal("oracle"))
+				Expect(cfgs[0].Rules).Should(BeEmpty())
+				Expect(cfgs[0].Allow).Should(ConsistOf("oracle-db-prod", "ldap"))
+			})
+
+			Context("with asg files", func() {
+				var tempDir string
+				BeforeEach(func() {
+					var err error
+					tempDir, err = ioutil.TempDir("", "cf-mgmt")
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(os.MkdirAll(path.Join(tempDir, "asgs"), 0755)).Should(Succeed())
+				})
+				AfterEach(func() {
+					os.RemoveAll(tempDir)
+				})
+				write := func(name, contents string) {
+					Ω(ioutil.WriteFile(path.Join(tempDir, "asgs", name), []byte(contents), 0644)).Should(Succeed())
+				}
+
+				It("should require endpoint based ASGs to allow an endpoint", func() {
+					write("oracle.yml", "allow: []\n")
+					_, err := config.NewManager(tempDir).GetASGConfigs()
+					Ω(err).Should(MatchError(fmt.Sprintf("security group [oracle] in %s must allow at least one endpoint", path.Join(tempDir, "asgs", "oracle.yml"))))
+				})
+
+				It("should not allow a json and a yml ASG with the same name", func() {
+					write("oracle.json", `[{"protocol": "tcp","destination": "10.10.1.0/24","ports": "1521"}]`)
+					write("oracle.yml", "allow:\n- oracle-db-prod\n")
+					_, err := config.NewManager(tempDir).GetASGConfigs()
+					Ω(err).Should(MatchError(fmt.Sprintf("security group [oracle] is defined by both %s and %s", path.Join(tempDir, "asgs", "oracle.json"), path.Join(tempDir, "asgs", "oracle.yml"))))
+				})
+			})
+
 			It("can optionally have a ASG name in the spaced config.", func() {
 				m := config.NewManager("./fixtures/asg-defaults")
 
@@ -91,6 +888,119 @@ var _ = Describe("CF-Mgmt Config", func() {
 				Ω(err).Should(HaveOccurred())
 				Ω(c).Should(BeEmpty())
 			})
+
+			Context("quota limits", func() {
+				var tempDir string
+				var m config.Manager
+				BeforeEach(func() {
+					var err error
+					tempDir, err = ioutil.TempDir("", "cf-mgmt")
+					Ω(err).ShouldNot(HaveOccurred())
+					m = config.NewManager(path.Join(tempDir, "config"))
+					Ω(m.CreateConfigIfNotExists("ldap")).Should(Succeed())
+					Ω(m.AddOrgToConfig(&config.OrgConfig{Org: "org1"})).Should(Succeed())
+				})
+				AfterEach(func() {
+					os.RemoveAll(tempDir)
+				})
+				writeOrgConfig := func(contents string) {
+					Ω(ioutil.WriteFile(path.Join(tempDir, "config", "org1", "orgConfig.yml"), []byte(contents), 0644)).Should(Succeed())
+				}
+
+				It("should read unlimited as -1", func() {
+					writeOrgConfig("org: org1\nenable-org-quota: true\nmemory-limit: 10240\ninstance-memory-limit: unlimited\ntotal-routes: Unlimited\ntotal-services: 100\n")
+					org, err := m.GetOrgConfig("org1")
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(org.MemoryLimit).Should(Equal(10240))
+					Ω(org.InstanceMemoryLimit).Should(Equal(config.Unlimited))
+					Ω(org.TotalRoutes).Should(Equal(config.Unlimited))
+					Ω(org.TotalServices).Should(Equal(100))
+					Ω(org.AppTaskLimit).Should(Equal(config.Unlimited))
+				})
+
+				It("should error for an unlimited memory-limit", func() {
+					writeOrgConfig("org: org1\nenable-org-quota: true\nmemory-limit: unlimited\n")
+					_, err := m.GetOrgConfigs()
+					Ω(err).Should(MatchError("memory-limit of org org1 cannot be unlimited, the cloud controller requires a number of megabytes"))
+				})
+
+				It("should error for negative limits other than unlimited", func() {
+					writeOrgConfig("org: org1\nenable-org-quota: true\nmemory-limit: 1024\ntotal-routes: -2\n")
+					_, err := m.GetOrgConfigs()
+					Ω(err).Should(MatchError("total-routes of org org1 must be unlimited or at least 0, not -2"))
+				})
+
+				It("should not validate a disabled quota", func() {
+					writeOrgConfig("org: org1\nmemory-limit: unlimited\n")
+					_, err := m.GetOrgConfigs()
+					Ω(err).ShouldNot(HaveOccurred())
+				})
+
+				It("should read quota alerts", func() {
+					writeOrgConfig("org: org1\nquota-alerts:\n  memory: 80\n  routes: 90\n")
+					org, err := m.GetOrgConfig("org1")
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(org.QuotaAlerts).Should(Equal(&config.QuotaAlerts{Memory: 80, Routes: 90}))
+				})
+
+				It("should error for a quota alert that is not a percentage", func() {
+					writeOrgConfig("org: org1\nquota-alerts:\n  app-instances: 120\n")
+					_, err := m.GetOrgConfigs()
+					Ω(err).Should(MatchError("quota-alerts app-instances of org org1 must be a percentage from 0 to 100, not 120"))
+				})
+			})
+
+			Context("stacks", func() {
+				var tempDir string
+				var m config.Manager
+				BeforeEach(func() {
+					var err error
+					tempDir, err = ioutil.TempDir("", "cf-mgmt")
+					Ω(err).ShouldNot(HaveOccurred())
+					m = config.NewManager(path.Join(tempDir, "config"))
+					Ω(m.CreateConfigIfNotExists("ldap")).Should(Succeed())
+					Ω(m.AddOrgToConfig(&config.OrgConfig{Org: "org1"})).Should(Succeed())
+				})
+				AfterEach(func() {
+					os.RemoveAll(tempDir)
+				})
+				writeOrgConfig := func(contents string) {
+					Ω(ioutil.WriteFile(path.Join(tempDir, "config", "org1", "orgConfig.yml"), []byte(contents), 0644)).Should(Succeed())
+				}
+
+				It("should allow only the default stack without allowed stacks", func() {
+					writeOrgConfig("org: org1\ndefault-stack: cflinuxfs4\n")
+					orgs, err := m.GetOrgConfigs()
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(orgs[0].Stacks()).Should(Equal([]string{"cflinuxfs4"}))
+					Ω(orgs[0].StackAllowed("cflinuxfs4")).Should(BeTrue())
+					Ω(orgs[0].StackAllowed("cflinuxfs3")).Should(BeFalse())
+				})
+
+				It("should allow any stack without a stack policy", func() {
+					writeOrgConfig("org: org1\n")
+					orgs, err := m.GetOrgConfigs()
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(orgs[0].Stacks()).Should(BeEmpty())
+					Ω(orgs[0].StackAllowed("cflinuxfs3")).Should(BeTrue())
+				})
+
+				It("should error for a default stack that is not allowed", func() {
+					writeOrgConfig("org: org1\ndefault-stack: cflinuxfs3\nallowed-stacks: [cflinuxfs4, windows]\n")
+					_, err := m.GetOrgConfigs()
+					Ω(err).Should(MatchError("default-stack cflinuxfs3 of org org1 is not one of its allowed-stacks [cflinuxfs4 windows]"))
+				})
+
+				It("should allow the services and plans of allowed-services", func() {
+					writeOrgConfig("org: org1\nallowed-services: [\"p.mysql:db-small\", p.redis]\n")
+					orgs, err := m.GetOrgConfigs()
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(orgs[0].ServiceAllowed("p.mysql", "db-small")).Should(BeTrue())
+					Ω(orgs[0].ServiceAllowed("p.mysql", "db-large")).Should(BeFalse())
+					Ω(orgs[0].ServiceAllowed("p.redis", "cache")).Should(BeTrue())
+					Ω(orgs[0].ServiceAllowed("p.rabbitmq", "single")).Should(BeFalse())
+				})
+			})
 		})
 
 		Context("GetOrgConfig", func() {
@@ -199,6 +1109,230 @@ var _ = Describe("CF-Mgmt Config", func() {
 				Ω(configs).Should(HaveLen(2))
 			})
 
+			It("should apply the all-spaces roles of the org to each space", func() {
+				tempDir, err := ioutil.TempDir("", "cf-mgmt")
+				Ω(err).ShouldNot(HaveOccurred())
+				defer os.RemoveAll(tempDir)
+				m := config.NewManager(path.Join(tempDir, "config"))
+				Ω(m.CreateConfigIfNotExists("ldap")).Should(Succeed())
+				Ω(m.AddOrgToConfig(&config.OrgConfig{Org: "org1"})).Should(Succeed())
+				Ω(m.AddSpaceToConfig(&config.SpaceConfig{Org: "org1", Space: "space1", Auditor: config.UserMgmt{LDAPGroup: "space1-auditors"}})).Should(Succeed())
+				Ω(m.AddSpaceToConfig(&config.SpaceConfig{Org: "org1", Space: "space2"})).Should(Succeed())
+				Ω(m.SaveOrgConfig(&config.OrgConfig{Org: "org1", AllSpaces: &config.SpaceRoles{
+					Auditor: config.UserMgmt{LDAPGroups: []string{"platform-sre"}, Users: []string{"monitor"}},
+				}})).Should(Succeed())
+
+				configs, err := m.GetSpaceConfigs()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(configs).Should(HaveLen(2))
+				for _, cfg := range configs {
+					Ω(cfg.GetAuditorGroups()).Should(ContainElement("platform-sre"))
+					Ω(cfg.Auditor.Users).Should(ConsistOf("monitor"))
+				}
+				space1, err := m.GetSpaceConfig("org1", "space1")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(space1.GetAuditorGroups()).Should(ConsistOf("space1-auditors", "platform-sre"))
+			})
+
+			Context("group-mappings.yml", func() {
+				var tempDir string
+				var m config.Manager
+				BeforeEach(func() {
+					var err error
+					tempDir, err = ioutil.TempDir("", "cf-mgmt")
+					Ω(err).ShouldNot(HaveOccurred())
+					m = config.NewManager(path.Join(tempDir, "config"))
+					Ω(m.CreateConfigIfNotExists("ldap")).Should(Succeed())
+					Ω(m.AddOrgToConfig(&config.OrgConfig{Org: "org1"})).Should(Succeed())
+					Ω(m.AddSpaceToConfig(&config.SpaceConfig{Org: "org1", Space: "space1"})).Should(Succeed())
+					Ω(m.AddSpaceToConfig(&config.SpaceConfig{Org: "org1", Space: "space2"})).Should(Succeed())
+				})
+				AfterEach(func() {
+					os.RemoveAll(tempDir)
+				})
+
+				writeMappings := func(contents string) {
+					Ω(ioutil.WriteFile(path.Join(tempDir, "config", "group-mappings.yml"), []byte(contents), 0644)).Should(Succeed())
+				}
+
+				It("should add the mapped groups to their targets", func() {
+					writeMappings(`group-mappings:
+- group: team-a
+  targets:
+  - org: org1
+    role: org-auditor
+  - org: org1
+    space: space2
+    role: space-developer
+`)
+					orgConfig, err := m.GetOrgConfig("org1")
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(orgConfig.GetAuditorGroups()).Should(ConsistOf("team-a"))
+					Ω(orgConfig.GetManagerGroups()).Should(BeEmpty())
+					space1, err := m.GetSpaceConfig("org1", "space1")
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(space1.GetDeveloperGroups()).Should(BeEmpty())
+					space2, err := m.GetSpaceConfig("org1", "space2")
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(space2.GetDeveloperGroups()).Should(ConsistOf("team-a"))
+				})
+
+				It("should read the configs without the mapped groups", func() {
+					writeMappings(`group-mappings:
+- group: team-a
+  targets:
+  - org: org1
+    role: org-auditor
+  - org: org1
+    space: space2
+    role: space-developer
+`)
+					orgConfig, err := m.GetRawOrgConfig("org1")
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(orgConfig.GetAuditorGroups()).Should(BeEmpty())
+					space2, err := m.GetRawSpaceConfig("org1", "space2")
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(space2.Space).Should(Equal("space2"))
+					Ω(space2.GetDeveloperGroups()).Should(BeEmpty())
+					_, err = m.GetRawSpaceConfig("org1", "unknown")
+					Ω(err).Should(MatchError("Space [unknown] not found in org [org1] config"))
+				})
+
+				It("should error for space roles without a space", func() {
+					writeMappings(`group-mappings:
+- group: team-a
+  targets:
+  - org: org1
+    role: space-developer
+`)
+					_, err := m.GetOrgConfigs()
+					Ω(err).Should(MatchError("space role space-developer of group [team-a] in group-mappings.yml requires a space"))
+				})
+			})
+
+			Context("org-groups.yml", func() {
+				var tempDir string
+				var m config.Manager
+				BeforeEach(func() {
+					var err error
+					tempDir, err = ioutil.TempDir("", "cf-mgmt")
+					Ω(err).ShouldNot(HaveOccurred())
+					m = config.NewManager(path.Join(tempDir, "config"))
+					Ω(m.CreateConfigIfNotExists("ldap")).Should(Succeed())
+					Ω(m.AddOrgToConfig(&config.OrgConfig{Org: "ledger"})).Should(Succeed())
+					Ω(m.AddOrgToConfig(&config.OrgConfig{
+						Org:               "billing",
+						EnableOrgQuota:    true,
+						MemoryLimit:       2048,
+						DefaultASGProfile: "batch",
+						ASGProfiles:       map[string]config.ASGProfile{"web": {ASGs: []string{"billing-web"}}},
+					})).Should(Succeed())
+					Ω(m.AddOrgToConfig(&config.OrgConfig{Org: "ungrouped"})).Should(Succeed())
+				})
+				AfterEach(func() {
+					os.RemoveAll(tempDir)
+				})
+
+				writeGroups := func(contents string) {
+					Ω(ioutil.WriteFile(path.Join(tempDir, "config", "org-groups.yml"), []byte(contents), 0644)).Should(Succeed())
+				}
+
+				const groups = `org-groups:
+- name: finance
+  quota:
+    memory-limit: 10240
+    total-routes: unlimited
+  asg-profiles:
+    web:
+      named-security-groups: [finance-web]
+    batch:
+      named-security-groups: [finance-batch]
+  default-asg-profile: web
+  org-auditor:
+    ldap_groups: [finance-audit]
+- name: payments
+  parent: finance
+  orgs: [ledger, billing]
+  quota:
+    memory-limit: 4096
+  org-manager:
+    ldap_groups: [payments-admins]
+`
+
+				It("should give member orgs the defaults of their group and its parents", func() {
+					writeGroups(groups)
+					ledger, err := m.GetOrgConfig("ledger")
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(ledger.EnableOrgQuota).Should(BeTrue())
+					Ω(ledger.MemoryLimit).Should(Equal(4096))
+					Ω(ledger.TotalRoutes).Should(Equal(0))
+					Ω(ledger.AppTaskLimit).Should(Equal(config.Unlimited))
+					Ω(ledger.DefaultASGProfile).Should(Equal("web"))
+					Ω(ledger.ASGProfiles["web"].ASGs).Should(ConsistOf("finance-web"))
+					Ω(ledger.GetManagerGroups()).Should(ConsistOf("payments-admins"))
+					Ω(ledger.GetAuditorGroups()).Should(ConsistOf("finance-audit"))
+					Ω(ledger.ConfigFile()).Should(Equal("ledger/orgConfig.yml"))
+					Ω(ledger.Manager.InheritedFrom("payments-admins")).Should(Equal("org-groups.yml"))
+					Ω(ledger.Auditor.InheritedFrom("finance-audit")).Should(Equal("org-groups.yml"))
+				})
+
+				It("should not override what the org configures itself", func() {
+					writeGroups(groups)
+					billing, err := m.GetOrgConfig("billing")
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(billing.MemoryLimit).Should(Equal(2048))
+					Ω(billing.DefaultASGProfile).Should(Equal("batch"))
+					Ω(billing.ASGProfiles["web"].ASGs).Should(ConsistOf("billing-web"))
+					Ω(billing.ASGProfiles["batch"].ASGs).Should(ConsistOf("finance-batch"))
+					ungrouped, err := m.GetOrgConfig("ungrouped")
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(ungrouped.EnableOrgQuota).Should(BeFalse())
+					Ω(ungrouped.GetManagerGroups()).Should(BeEmpty())
+				})
+
+				It("should read the org config without what it inherits", func() {
+					writeGroups(groups)
+					ledger, err := m.GetRawOrgConfig("ledger")
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(ledger.EnableOrgQuota).Should(BeFalse())
+					Ω(ledger.DefaultASGProfile).Should(BeEmpty())
+					Ω(ledger.GetManagerGroups()).Should(BeEmpty())
+					_, err = m.GetRawOrgConfig("unknown")
+					Ω(err).Should(MatchError("Org [unknown] not found in config"))
+				})
+
+				It("should error for an org in two groups", func() {
+					writeGroups(`org-groups:
+- name: finance
+  orgs: [ledger]
+- name: payments
+  orgs: [ledger]
+`)
+					_, err := m.GetOrgConfigs()
+					Ω(err).Should(MatchError("org [ledger] is a member of both org groups [finance] and [payments] in org-groups.yml"))
+				})
+
+				It("should error for groups nested under themselves", func() {
+					writeGroups(`org-groups:
+- name: finance
+  parent: payments
+- name: payments
+  parent: finance
+`)
+					_, err := m.GetOrgConfigs()
+					Ω(err).Should(MatchError("org group [finance] is nested under itself in org-groups.yml"))
+				})
+
+				It("should error for an undefined parent", func() {
+					writeGroups(`org-groups:
+- name: payments
+  parent: finance
+`)
+					_, err := m.GetOrgConfigs()
+					Ω(err).Should(MatchError("parent [finance] of org group [payments] is not defined in org-groups.yml"))
+				})
+			})
+
 			It("should return configs for user info", func() {
 				m := config.NewManager("./fixtures/user_config")
 				configs, err := m.GetSpaceConfigs()
@@ -16,6 +16,7 @@ type AddSpaceToConfigurationCommand struct {
 	EnableSecurityGroup string      `long:"enable-security-group" description:"Enable space level security group definitions" choice:"true" choice:"false"`
 	IsoSegment          string      `long:"isolation-segment" description:"Isolation segment assigned to space"`
 	ASGs                []string    `long:"named-asg" description:"Named asg(s) to assign to space, specify multiple times"`
+	StagingASGs         []string    `long:"named-staging-asg" description:"Named asg(s) to assign to space for staging only, specify multiple times"`
 	Quota               SpaceQuota  `group:"quota"`
 	Developer           UserRoleAdd `group:"developer" namespace:"developer"`
 	Manager             UserRoleAdd `group:"manager" namespace:"manager"`
@@ -46,6 +47,8 @@ func (c *AddSpaceToConfigurationCommand) Execute([]string) error {
 
 	spaceConfig.ASGs = addToSlice(spaceConfig.ASGs, c.ASGs, &errorString)
 	validateASGsExist(asgConfigs, spaceConfig.ASGs, &errorString)
+	spaceConfig.StagingASGs = addToSlice(spaceConfig.StagingASGs, c.StagingASGs, &errorString)
+	validateASGsExist(asgConfigs, spaceConfig.StagingASGs, &errorString)
 	updateSpaceQuotaConfig(spaceConfig, c.Quota, &errorString)
 	c.updateUsers(spaceConfig, &errorString)
 
@@ -6,6 +6,8 @@ type CfMgmtConfigCommand struct {
 	AddOrgToConfigurationCommand     AddOrgToConfigurationCommand     `command:"add-org" description:"Adds specified org to configuration"`
 	AddSpaceToConfigurationCommand   AddSpaceToConfigurationCommand   `command:"add-space" description:"Adds specified space to configuration for org"`
 	GenerateConcoursePipelineCommand GenerateConcoursePipelineCommand `command:"generate-concourse-pipeline" description:"generates a concourse pipline to be used to drive cf-mgmt"`
+	GenerateConfigCommand            GenerateConfigCommand            `command:"generate-config" description:"generates org and space configuration from a csv of teams"`
+	GenerateOrgsCommand              GenerateOrgsCommand              `command:"generate-orgs" description:"generates similarly configured orgs from a template in org-templates.yml"`
 	UpdateOrgConfigurationCommand    UpdateOrgConfigurationCommand    `command:"update-org" description:"updates org configuration"`
 	UpdateSpaceConfigurationCommand  UpdateSpaceConfigurationCommand  `command:"update-space" description:"updates space configuration"`
 	DeleteOrgConfigurationCommand    DeleteOrgConfigurationCommand    `command:"delete-org" description:"deletes org configuration"`
@@ -3,6 +3,7 @@ package configcommands
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/pivotalservices/cf-mgmt/config"
 )
@@ -18,6 +19,7 @@ type UserRole struct {
 	UsersToRemove      []string `long:"user-to-remove" description:"User to remove, specify multiple times"`
 	SamlUsersToRemove  []string `long:"saml-user-to-remove" description:"SAML user to remove, specify multiple times"`
 	LDAPGroupsToRemove []string `long:"ldap-group-to-remove" description:"Group to remove, specify multiple times"`
+	ClientsToRemove    []string `long:"client-to-remove" description:"UAA client to remove, specify multiple times"`
 }
 
 type UserRoleAdd struct {
@@ -25,34 +27,35 @@ type UserRoleAdd struct {
 	Users      []string `long:"user" description:"User to add, specify multiple times"`
 	SamlUsers  []string `long:"saml-user" description:"SAML user to add, specify multiple times"`
 	LDAPGroups []string `long:"ldap-group" description:"Group to add, specify multiple times"`
+	Clients    []string `long:"client" description:"UAA client to add, specify multiple times"`
 }
 
 type OrgQuota struct {
 	EnableOrgQuota          string `long:"enable-org-quota" description:"Enable the Org Quota in the config" choice:"true" choice:"false"`
 	MemoryLimit             string `long:"memory-limit" description:"An Org's memory limit in Megabytes"`
-	InstanceMemoryLimit     string `long:"instance-memory-limit" description:"Global Org Application instance memory limit in Megabytes"`
+	InstanceMemoryLimit     string `long:"instance-memory-limit" description:"Org per-process (application instance) memory limit in Megabytes"`
 	TotalRoutes             string `long:"total-routes" description:"Total Routes capacity for an Org"`
 	TotalServices           string `long:"total-services" description:"Total Services capacity for an Org"`
 	PaidServicesAllowed     string `long:"paid-service-plans-allowed" description:"Allow paid services to appear in an org" choice:"true" choice:"false"`
 	TotalPrivateDomains     string `long:"total-private-domains" description:"Total Private Domain capacity for an Org"`
 	TotalReservedRoutePorts string `long:"total-reserved-route-ports" description:"Total Reserved Route Ports capacity for an Org"`
 	TotalServiceKeys        string `long:"total-service-keys" description:"Total Service Keys capacity for an Org"`
-	AppInstanceLimit        string `long:"app-instance-limit" description:"App Instance Limit an Org"`
-	AppTaskLimit            string `long:"app-task-limit" description:"App Task Limit an Org"`
+	AppInstanceLimit        string `long:"app-instance-limit" description:"Total application instances capacity for an Org, unlimited or -1 for no limit"`
+	AppTaskLimit            string `long:"app-task-limit" description:"Total concurrently running tasks capacity for an Org, unlimited or -1 for no limit"`
 }
 
 type SpaceQuota struct {
 	EnableSpaceQuota        string `long:"enable-space-quota" description:"Enable the Space Quota in the config" choice:"true" choice:"false"`
 	MemoryLimit             string `long:"memory-limit" description:"An Space's memory limit in Megabytes"`
-	InstanceMemoryLimit     string `long:"instance-memory-limit" description:"Space Application instance memory limit in Megabytes"`
+	InstanceMemoryLimit     string `long:"instance-memory-limit" description:"Space per-process (application instance) memory limit in Megabytes"`
 	TotalRoutes             string `long:"total-routes" description:"Total Routes capacity for an Space"`
 	TotalServices           string `long:"total-services" description:"Total Services capacity for an Space"`
 	PaidServicesAllowed     string `long:"paid-service-plans-allowed" description:"Allow paid services to appear in an Space" choice:"true" choice:"false"`
 	TotalPrivateDomains     string `long:"total-private-domains" description:"Total Private Domain capacity for an Space"`
 	TotalReservedRoutePorts string `long:"total-reserved-route-ports" description:"Total Reserved Route Ports capacity for an Space"`
 	TotalServiceKeys        string `long:"total-service-keys" description:"Total Service Keys capacity for an Space"`
-	AppInstanceLimit        string `long:"app-instance-limit" description:"App Instance Limit for a space"`
-	AppTaskLimit            string `long:"app-task-limit" description:"App Task Limit for a space"`
+	AppInstanceLimit        string `long:"app-instance-limit" description:"Total application instances capacity for an Space, unlimited or -1 for no limit"`
+	AppTaskLimit            string `long:"app-task-limit" description:"Total concurrently running tasks capacity for an Space, unlimited or -1 for no limit"`
 }
 
 func updateUsersBasedOnRole(userMgmt *config.UserMgmt, currentLDAPGroups []string, userRole *UserRole, errorString *string) {
@@ -60,6 +63,7 @@ func updateUsersBasedOnRole(userMgmt *config.UserMgmt, currentLDAPGroups []strin
 	userMgmt.Users = removeFromSlice(addToSlice(userMgmt.Users, userRole.Users, errorString), userRole.UsersToRemove)
 	userMgmt.SamlUsers = removeFromSlice(addToSlice(userMgmt.SamlUsers, userRole.SamlUsers, errorString), userRole.SamlUsersToRemove)
 	userMgmt.LDAPUsers = removeFromSlice(addToSlice(userMgmt.LDAPUsers, userRole.LDAPUsers, errorString), userRole.LDAPUsersToRemove)
+	userMgmt.Clients = removeFromSlice(addToSlice(userMgmt.Clients, userRole.Clients, errorString), userRole.ClientsToRemove)
 	userMgmt.LDAPGroup = ""
 }
 
@@ -68,6 +72,7 @@ func addUsersBasedOnRole(userMgmt *config.UserMgmt, currentLDAPGroups []string,
 	userMgmt.Users = addToSlice(userMgmt.Users, userRole.Users, errorString)
 	userMgmt.SamlUsers = addToSlice(userMgmt.SamlUsers, userRole.SamlUsers, errorString)
 	userMgmt.LDAPUsers = addToSlice(userMgmt.LDAPUsers, userRole.LDAPUsers, errorString)
+	userMgmt.Clients = addToSlice(userMgmt.Clients, userRole.Clients, errorString)
 	userMgmt.LDAPGroup = ""
 }
 
@@ -75,6 +80,10 @@ func convertToInt(parameterName string, currentValue *int, proposedValue string,
 	if proposedValue == "" {
 		return
 	}
+	if strings.EqualFold(proposedValue, "unlimited") {
+		*currentValue = config.Unlimited
+		return
+	}
 	i, err := strconv.Atoi(proposedValue)
 	if err != nil {
 		*errorString += fmt.Sprintf("\n--%s must be an integer instead of [%s]", parameterName, proposedValue)
@@ -54,7 +54,7 @@ func (c *GenerateConcoursePipelineCommand) Execute([]string) error {
 }
 
 func createFile(assetName, fileName string) error {
-	bytes, err := generated.Asset(fmt.Sprintf("files/%s", assetName))
+	bytes, err := generated.File(assetName)
 	if err != nil {
 		return err
 	}
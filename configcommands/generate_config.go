@@ -0,0 +1,210 @@
+package configcommands
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+)
+
+// columns of the teams csv
+const (
+	csvTeam      = "team"
+	csvOrg       = "org"
+	csvSpaces    = "spaces"
+	csvADGroups  = "ad-groups"
+	csvQuotaTier = "quota-tier"
+)
+
+type GenerateConfigCommand struct {
+	ConfigManager config.Manager
+	BaseConfigCommand
+	FromCSV string `long:"from-csv" description:"CSV of teams with the columns team, org, spaces, ad-groups and quota-tier" required:"true"`
+}
+
+// csvOrgConfig collects the teams sharing an org
+type csvOrgConfig struct {
+	orgConfig   *config.OrgConfig
+	quotaTier   string
+	spaces      []*config.SpaceConfig
+	spaceByName map[string]*config.SpaceConfig
+}
+
+//Execute - generates the org and space configuration of the teams in a csv
+func (c *GenerateConfigCommand) Execute([]string) error {
+	c.initConfig()
+	file, err := os.Open(c.FromCSV)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	orgs, err := readTeamsCSV(file)
+	if err != nil {
+		return fmt.Errorf("%s: %s", c.FromCSV, err.Error())
+	}
+
+	if err := c.ConfigManager.CreateConfigIfNotExists("ldap"); err != nil {
+		return err
+	}
+	orgList, err := c.ConfigManager.Orgs()
+	if err != nil {
+		return err
+	}
+	orgGroups, err := c.ConfigManager.GetOrgGroups()
+	if err != nil {
+		return err
+	}
+	errorString := ""
+	for _, org := range orgs {
+		if orgList.Contains(org.orgConfig.Org) {
+			errorString += fmt.Sprintf("\n--org [%s] already exists in the configuration", org.orgConfig.Org)
+		}
+		if org.quotaTier != "" && orgGroups.Group(org.quotaTier) == nil {
+			errorString += fmt.Sprintf("\n--quota tier [%s] of org [%s] is not an org group in org-groups.yml", org.quotaTier, org.orgConfig.Org)
+		}
+	}
+	if errorString != "" {
+		return errors.New(errorString)
+	}
+
+	for _, org := range orgs {
+		if err := c.ConfigManager.AddOrgToConfig(org.orgConfig); err != nil {
+			return err
+		}
+		for _, spaceConfig := range org.spaces {
+			if err := c.ConfigManager.AddSpaceToConfig(spaceConfig); err != nil {
+				return err
+			}
+		}
+		if org.quotaTier != "" {
+			group := orgGroups.Group(org.quotaTier)
+			group.Orgs = append(group.Orgs, org.orgConfig.Org)
+		}
+	}
+	if len(orgGroups.Groups) > 0 {
+		if err := c.ConfigManager.SaveOrgGroups(orgGroups); err != nil {
+			return err
+		}
+	}
+	fmt.Println(fmt.Sprintf("The %d orgs of %s have been added", len(orgs), c.FromCSV))
+	return nil
+}
+
+// readTeamsCSV reads one team per row into the configuration of its org. A
+// team without an org gets an org named after the team. The ad groups of a
+// team become auditors of its org and developers and managers of its spaces.
+func readTeamsCSV(reader io.Reader) ([]*csvOrgConfig, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the header: %s", err.Error())
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.NewReplacer(" ", "-", "_", "-").Replace(strings.ToLower(strings.TrimSpace(name)))
+		columns[name] = i
+	}
+	if _, ok := columns[csvTeam]; !ok {
+		return nil, fmt.Errorf("the header has no %s column", csvTeam)
+	}
+
+	var orgs []*csvOrgConfig
+	orgByName := make(map[string]*csvOrgConfig)
+	for line := 2; ; line++ {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		cell := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		team := cell(csvTeam)
+		if team == "" {
+			if strings.TrimSpace(strings.Join(record, "")) == "" {
+				continue
+			}
+			return nil, fmt.Errorf("line %d has no team", line)
+		}
+		orgName := cell(csvOrg)
+		if orgName == "" {
+			orgName = team
+		}
+		org, ok := orgByName[strings.ToLower(orgName)]
+		if !ok {
+			org = &csvOrgConfig{
+				orgConfig: &config.OrgConfig{
+					Org:                        orgName,
+					RemoveUsers:                true,
+					RemovePrivateDomains:       true,
+					RemoveSharedPrivateDomains: true,
+				},
+				spaceByName: make(map[string]*config.SpaceConfig),
+			}
+			orgByName[strings.ToLower(orgName)] = org
+			orgs = append(orgs, org)
+		}
+		if tier := cell(csvQuotaTier); tier != "" {
+			if org.quotaTier != "" && org.quotaTier != tier {
+				return nil, fmt.Errorf("line %d gives org [%s] quota tier [%s] instead of [%s]", line, orgName, tier, org.quotaTier)
+			}
+			org.quotaTier = tier
+		}
+		groups := splitCell(cell(csvADGroups))
+		org.orgConfig.Auditor.LDAPGroups = appendMissing(org.orgConfig.Auditor.LDAPGroups, groups...)
+		for _, spaceName := range splitCell(cell(csvSpaces)) {
+			spaceConfig, ok := org.spaceByName[strings.ToLower(spaceName)]
+			if !ok {
+				spaceConfig = &config.SpaceConfig{Org: orgName, Space: spaceName, RemoveUsers: true}
+				org.spaceByName[strings.ToLower(spaceName)] = spaceConfig
+				org.spaces = append(org.spaces, spaceConfig)
+			}
+			spaceConfig.Developer.LDAPGroups = appendMissing(spaceConfig.Developer.LDAPGroups, groups...)
+			spaceConfig.Manager.LDAPGroups = appendMissing(spaceConfig.Manager.LDAPGroups, groups...)
+		}
+	}
+	if len(orgs) == 0 {
+		return nil, fmt.Errorf("there are no teams")
+	}
+	return orgs, nil
+}
+
+// splitCell splits a cell listing several values, separated by semicolons or
+// by commas within a quoted cell
+func splitCell(cell string) []string {
+	var values []string
+	for _, value := range strings.FieldsFunc(cell, func(r rune) bool { return r == ';' || r == ',' }) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func appendMissing(list []string, values ...string) []string {
+	existing := sliceToMap(list)
+	for _, value := range values {
+		if _, ok := existing[value]; !ok {
+			list = append(list, value)
+			existing[value] = value
+		}
+	}
+	return list
+}
+
+func (c *GenerateConfigCommand) initConfig() {
+	if c.ConfigManager == nil {
+		c.ConfigManager = config.NewManager(c.ConfigDirectory)
+	}
+}
@@ -0,0 +1,113 @@
+package configcommands_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	. "github.com/pivotalservices/cf-mgmt/configcommands"
+)
+
+var _ = Describe("given generate config command", func() {
+	var (
+		tempDir   string
+		configDir string
+		command   GenerateConfigCommand
+	)
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "cf-mgmt")
+		Expect(err).ShouldNot(HaveOccurred())
+		configDir = path.Join(tempDir, "config")
+		command = GenerateConfigCommand{FromCSV: path.Join(tempDir, "teams.csv")}
+		command.ConfigDirectory = configDir
+	})
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	writeCSV := func(contents string) {
+		Expect(ioutil.WriteFile(command.FromCSV, []byte(contents), 0644)).Should(Succeed())
+	}
+
+	It("should add the orgs and spaces of every team", func() {
+		writeCSV(`Team,Org,Spaces,AD Groups,Quota Tier
+payments,finance,dev;prod,payments-devs,
+ledger,finance,"dev,test",ledger-devs;finance-ops,
+search,,dev,search-devs,
+`)
+		Expect(command.Execute(nil)).Should(Succeed())
+		m := config.NewManager(configDir)
+		orgs, err := m.Orgs()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(orgs.Orgs).Should(ConsistOf("finance", "search"))
+
+		finance, err := m.GetOrgConfig("finance")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(finance.RemoveUsers).Should(BeTrue())
+		Expect(finance.GetAuditorGroups()).Should(ConsistOf("payments-devs", "ledger-devs", "finance-ops"))
+
+		spaces, err := m.OrgSpaces("finance")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(spaces.Spaces).Should(Equal([]string{"dev", "prod", "test"}))
+		dev, err := m.GetSpaceConfig("finance", "dev")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(dev.GetDeveloperGroups()).Should(ConsistOf("payments-devs", "ledger-devs", "finance-ops"))
+		Expect(dev.GetManagerGroups()).Should(ConsistOf("payments-devs", "ledger-devs", "finance-ops"))
+		prod, err := m.GetSpaceConfig("finance", "prod")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(prod.GetDeveloperGroups()).Should(ConsistOf("payments-devs"))
+
+		search, err := m.GetSpaceConfig("search", "dev")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(search.GetDeveloperGroups()).Should(ConsistOf("search-devs"))
+	})
+
+	It("should add orgs to the org group of their quota tier", func() {
+		m := config.NewManager(configDir)
+		Expect(m.CreateConfigIfNotExists("ldap")).Should(Succeed())
+		Expect(m.SaveOrgGroups(&config.OrgGroups{Groups: []config.OrgGroup{
+			{Name: "gold", Quota: &config.QuotaTier{MemoryLimit: 10240}},
+		}})).Should(Succeed())
+		writeCSV(`team,org,spaces,ad-groups,quota-tier
+payments,finance,dev,payments-devs,gold
+`)
+		Expect(command.Execute(nil)).Should(Succeed())
+		finance, err := m.GetOrgConfig("finance")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(finance.EnableOrgQuota).Should(BeTrue())
+		Expect(finance.MemoryLimit).Should(Equal(10240))
+	})
+
+	It("should error for a quota tier that is not an org group", func() {
+		writeCSV(`team,org,spaces,ad-groups,quota-tier
+payments,finance,dev,payments-devs,gold
+`)
+		err := command.Execute(nil)
+		Expect(err).Should(MatchError("\n--quota tier [gold] of org [finance] is not an org group in org-groups.yml"))
+	})
+
+	It("should error for orgs already in the configuration", func() {
+		m := config.NewManager(configDir)
+		Expect(m.CreateConfigIfNotExists("ldap")).Should(Succeed())
+		Expect(m.AddOrgToConfig(&config.OrgConfig{Org: "finance"})).Should(Succeed())
+		writeCSV(`team,org
+payments,finance
+`)
+		err := command.Execute(nil)
+		Expect(err).Should(MatchError("\n--org [finance] already exists in the configuration"))
+	})
+
+	It("should error for a csv without a team column", func() {
+		writeCSV(`org,spaces
+finance,dev
+`)
+		err := command.Execute(nil)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("the header has no team column"))
+	})
+})
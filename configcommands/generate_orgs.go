@@ -0,0 +1,64 @@
+package configcommands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+)
+
+type GenerateOrgsCommand struct {
+	ConfigManager config.Manager
+	BaseConfigCommand
+	Template string `long:"template" description:"Name of the org template in org-templates.yml" required:"true"`
+}
+
+//Execute - adds the orgs of an org template, with their spaces, quotas and user, to the configuration
+func (c *GenerateOrgsCommand) Execute([]string) error {
+	c.initConfig()
+	templates, err := c.ConfigManager.GetOrgTemplates()
+	if err != nil {
+		return err
+	}
+	template := templates.Template(c.Template)
+	if template == nil {
+		return fmt.Errorf("org template [%s] is not defined in org-templates.yml", c.Template)
+	}
+	orgConfigs, spaceConfigs, err := template.Generate()
+	if err != nil {
+		return err
+	}
+
+	orgList, err := c.ConfigManager.Orgs()
+	if err != nil {
+		return err
+	}
+	errorString := ""
+	for _, orgConfig := range orgConfigs {
+		if orgList.Contains(orgConfig.Org) {
+			errorString += fmt.Sprintf("\n--org [%s] already exists in the configuration", orgConfig.Org)
+		}
+	}
+	if errorString != "" {
+		return errors.New(errorString)
+	}
+
+	for _, orgConfig := range orgConfigs {
+		if err := c.ConfigManager.AddOrgToConfig(orgConfig); err != nil {
+			return err
+		}
+	}
+	for _, spaceConfig := range spaceConfigs {
+		if err := c.ConfigManager.AddSpaceToConfig(spaceConfig); err != nil {
+			return err
+		}
+	}
+	fmt.Println(fmt.Sprintf("The %d orgs of template [%s] have been added", len(orgConfigs), c.Template))
+	return nil
+}
+
+func (c *GenerateOrgsCommand) initConfig() {
+	if c.ConfigManager == nil {
+		c.ConfigManager = config.NewManager(c.ConfigDirectory)
+	}
+}
@@ -0,0 +1,84 @@
+package configcommands_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	. "github.com/pivotalservices/cf-mgmt/configcommands"
+)
+
+var _ = Describe("given generate orgs command", func() {
+	var (
+		tempDir   string
+		configDir string
+		command   GenerateOrgsCommand
+	)
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "cf-mgmt")
+		Expect(err).ShouldNot(HaveOccurred())
+		configDir = path.Join(tempDir, "config")
+		Expect(config.NewManager(configDir).CreateConfigIfNotExists("uaa")).Should(Succeed())
+		command = GenerateOrgsCommand{Template: "workshop"}
+		command.ConfigDirectory = configDir
+	})
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	writeTemplates := func(contents string) {
+		Expect(ioutil.WriteFile(path.Join(configDir, "org-templates.yml"), []byte(contents), 0644)).Should(Succeed())
+	}
+
+	It("should add an org with its spaces, quotas and user for each name of the range", func() {
+		writeTemplates(`org-templates:
+- name: workshop
+  orgs: student-008..student-011
+  spaces: [dev]
+  user: "{org}@example.com"
+  quota:
+    memory-limit: 4096
+  space-quota:
+    memory-limit: 2048
+`)
+		Expect(command.Execute(nil)).Should(Succeed())
+		m := config.NewManager(configDir)
+		orgs, err := m.Orgs()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(orgs.Orgs).Should(ConsistOf("student-008", "student-009", "student-010", "student-011"))
+
+		org, err := m.GetOrgConfig("student-010")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(org.EnableOrgQuota).Should(BeTrue())
+		Expect(org.MemoryLimit).Should(Equal(4096))
+		Expect(org.Manager.Users).Should(Equal([]string{"student-010@example.com"}))
+
+		space, err := m.GetSpaceConfig("student-010", "dev")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(space.EnableSpaceQuota).Should(BeTrue())
+		Expect(space.MemoryLimit).Should(Equal(2048))
+		Expect(space.Developer.Users).Should(Equal([]string{"student-010@example.com"}))
+	})
+
+	It("should not add any org when one already exists", func() {
+		writeTemplates(`org-templates:
+- name: workshop
+  orgs: student-1..student-3
+`)
+		m := config.NewManager(configDir)
+		Expect(m.AddOrgToConfig(&config.OrgConfig{Org: "student-2"})).Should(Succeed())
+		Expect(command.Execute(nil)).Should(MatchError("\n--org [student-2] already exists in the configuration"))
+		orgs, err := m.Orgs()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(orgs.Orgs).Should(ConsistOf("student-2"))
+	})
+
+	It("should error for an undefined template", func() {
+		Expect(command.Execute(nil)).Should(MatchError("org template [workshop] is not defined in org-templates.yml"))
+	})
+})
@@ -30,7 +30,7 @@ type UpdateOrgConfigurationCommand struct {
 //Execute - updates org configuration`
 func (c *UpdateOrgConfigurationCommand) Execute(args []string) error {
 	c.initConfig()
-	orgConfig, err := c.ConfigManager.GetOrgConfig(c.OrgName)
+	orgConfig, err := c.ConfigManager.GetRawOrgConfig(c.OrgName)
 	if err != nil {
 		return err
 	}
@@ -30,7 +30,7 @@ var _ = Describe("given update orgs config command", func() {
 			mockConfig.OrgSpacesReturns(&config.Spaces{
 				Org: orgName,
 			}, nil)
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{
 				Org: orgName,
 			}, nil)
 			mockConfig.SaveOrgConfigReturns(nil)
@@ -55,7 +55,7 @@ var _ = Describe("given update orgs config command", func() {
 			mockConfig.OrgSpacesReturns(&config.Spaces{
 				Org: orgName,
 			}, nil)
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{
 				Org:            orgName,
 				PrivateDomains: []string{"foo.com", "bar.io"},
 			}, nil)
@@ -81,7 +81,7 @@ var _ = Describe("given update orgs config command", func() {
 			mockConfig.OrgSpacesReturns(&config.Spaces{
 				Org: orgName,
 			}, nil)
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{
 				Org:                  orgName,
 				RemovePrivateDomains: false,
 			}, nil)
@@ -104,7 +104,7 @@ var _ = Describe("given update orgs config command", func() {
 			mockConfig.OrgSpacesReturns(&config.Spaces{
 				Org: orgName,
 			}, nil)
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{
 				Org:                  orgName,
 				RemovePrivateDomains: true,
 			}, nil)
@@ -126,7 +126,7 @@ var _ = Describe("given update orgs config command", func() {
 			mockConfig.OrgSpacesReturns(&config.Spaces{
 				Org: orgName,
 			}, nil)
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{
 				Org:                  orgName,
 				RemovePrivateDomains: true,
 			}, nil)
@@ -142,7 +142,7 @@ var _ = Describe("given update orgs config command", func() {
 			mockConfig.OrgSpacesReturns(&config.Spaces{
 				Org: orgName,
 			}, nil)
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{
 				Org: orgName,
 			}, nil)
 			mockConfig.SaveOrgConfigReturns(nil)
@@ -167,7 +167,7 @@ var _ = Describe("given update orgs config command", func() {
 			mockConfig.OrgSpacesReturns(&config.Spaces{
 				Org: orgName,
 			}, nil)
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{
 				Org:                  orgName,
 				SharedPrivateDomains: []string{"foo.com", "bar.io"},
 			}, nil)
@@ -193,7 +193,7 @@ var _ = Describe("given update orgs config command", func() {
 			mockConfig.OrgSpacesReturns(&config.Spaces{
 				Org: orgName,
 			}, nil)
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{
 				Org: orgName,
 				RemoveSharedPrivateDomains: false,
 			}, nil)
@@ -216,7 +216,7 @@ var _ = Describe("given update orgs config command", func() {
 			mockConfig.OrgSpacesReturns(&config.Spaces{
 				Org: orgName,
 			}, nil)
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{
 				Org: orgName,
 				RemoveSharedPrivateDomains: true,
 			}, nil)
@@ -238,7 +238,7 @@ var _ = Describe("given update orgs config command", func() {
 			mockConfig.OrgSpacesReturns(&config.Spaces{
 				Org: orgName,
 			}, nil)
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{
 				Org: orgName,
 				RemoveSharedPrivateDomains: true,
 			}, nil)
@@ -261,10 +261,11 @@ var _ = Describe("given update orgs config command", func() {
 			configuration.Quota.TotalReservedRoutePorts = "6"
 			configuration.Quota.TotalServiceKeys = "7"
 			configuration.Quota.AppInstanceLimit = "8"
+			configuration.Quota.AppTaskLimit = "9"
 			mockConfig.OrgSpacesReturns(&config.Spaces{
 				Org: orgName,
 			}, nil)
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{
 				Org: orgName,
 			}, nil)
 
@@ -284,6 +285,7 @@ var _ = Describe("given update orgs config command", func() {
 				TotalReservedRoutePorts: 6,
 				TotalServiceKeys:        7,
 				AppInstanceLimit:        8,
+				AppTaskLimit:            9,
 			}))
 			Expect(mockConfig.SaveOrgSpacesCallCount()).To(Equal(1))
 			Expect(mockConfig.SaveOrgSpacesArgsForCall(0)).To(BeEquivalentTo(&config.Spaces{
@@ -297,7 +299,7 @@ var _ = Describe("given update orgs config command", func() {
 			mockConfig.OrgSpacesReturns(&config.Spaces{
 				Org: orgName,
 			}, nil)
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{
 				Org: orgName,
 			}, nil)
 			err := configuration.Execute(nil)
@@ -315,7 +317,7 @@ var _ = Describe("given update orgs config command", func() {
 			mockConfig.OrgSpacesReturns(&config.Spaces{
 				Org: orgName,
 			}, nil)
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{
 				Org: orgName,
 			}, nil)
 
@@ -347,7 +349,7 @@ var _ = Describe("given update orgs config command", func() {
 			mockConfig.OrgSpacesReturns(&config.Spaces{
 				Org: orgName,
 			}, nil)
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{
 				Org: orgName,
 				Manager: config.UserMgmt{
 					Users: []string{"foo", "bar"},
@@ -376,7 +378,7 @@ var _ = Describe("given update orgs config command", func() {
 			mockConfig.OrgSpacesReturns(&config.Spaces{
 				Org: orgName,
 			}, nil)
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{
 				Org: orgName,
 			}, nil)
 
@@ -397,7 +399,7 @@ var _ = Describe("given update orgs config command", func() {
 				Org: orgName,
 			}, nil)
 
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{
 				Org: orgName,
 				Manager: config.UserMgmt{
 					Users: []string{"foo", "bar"},
@@ -438,7 +440,7 @@ var _ = Describe("given update orgs config command", func() {
 			mockConfig.OrgSpacesReturns(&config.Spaces{
 				Org: orgName,
 			}, nil)
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{
 				Org: orgName,
 			}, nil)
 
@@ -470,7 +472,7 @@ var _ = Describe("given update orgs config command", func() {
 			mockConfig.OrgSpacesReturns(&config.Spaces{
 				Org: orgName,
 			}, nil)
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{
 				Org: orgName,
 				Manager: config.UserMgmt{
 					SamlUsers: []string{"foo", "bar"},
@@ -511,7 +513,7 @@ var _ = Describe("given update orgs config command", func() {
 			mockConfig.OrgSpacesReturns(&config.Spaces{
 				Org: orgName,
 			}, nil)
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{
 				Org: orgName,
 			}, nil)
 
@@ -543,7 +545,7 @@ var _ = Describe("given update orgs config command", func() {
 			mockConfig.OrgSpacesReturns(&config.Spaces{
 				Org: orgName,
 			}, nil)
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{
 				Org: orgName,
 				Manager: config.UserMgmt{
 					LDAPUsers: []string{"foo", "bar"},
@@ -584,7 +586,7 @@ var _ = Describe("given update orgs config command", func() {
 			mockConfig.OrgSpacesReturns(&config.Spaces{
 				Org: orgName,
 			}, nil)
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{
 				Org: orgName,
 			}, nil)
 
@@ -616,7 +618,7 @@ var _ = Describe("given update orgs config command", func() {
 			mockConfig.OrgSpacesReturns(&config.Spaces{
 				Org: orgName,
 			}, nil)
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{
 				Org: orgName,
 				Manager: config.UserMgmt{
 					LDAPGroups: []string{"foo", "bar"},
@@ -655,7 +657,7 @@ var _ = Describe("given update orgs config command", func() {
 			Org:                orgName,
 			EnableDeleteSpaces: true,
 		}, nil)
-		mockConfig.GetOrgConfigReturns(&config.OrgConfig{}, nil)
+		mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{}, nil)
 
 		err := configuration.Execute(nil)
 		Expect(mockConfig.SaveOrgConfigCallCount()).To(Equal(1))
@@ -672,7 +674,7 @@ var _ = Describe("given update orgs config command", func() {
 			Org:                orgName,
 			EnableDeleteSpaces: false,
 		}, nil)
-		mockConfig.GetOrgConfigReturns(&config.OrgConfig{}, nil)
+		mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{}, nil)
 
 		err := configuration.Execute(nil)
 		Expect(mockConfig.SaveOrgConfigCallCount()).To(Equal(1))
@@ -691,7 +693,7 @@ var _ = Describe("given update orgs config command", func() {
 			Org:                orgName,
 			EnableDeleteSpaces: false,
 		}, nil)
-		mockConfig.GetOrgConfigReturns(&config.OrgConfig{}, nil)
+		mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{}, nil)
 
 		err := configuration.Execute(nil)
 		Expect(mockConfig.SaveOrgConfigCallCount()).To(Equal(1))
@@ -710,7 +712,7 @@ var _ = Describe("given update orgs config command", func() {
 			Org:                orgName,
 			EnableDeleteSpaces: true,
 		}, nil)
-		mockConfig.GetOrgConfigReturns(&config.OrgConfig{}, nil)
+		mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{}, nil)
 
 		err := configuration.Execute(nil)
 		Expect(mockConfig.SaveOrgConfigCallCount()).To(Equal(1))
@@ -725,20 +727,20 @@ var _ = Describe("given update orgs config command", func() {
 
 	Context("Failures", func() {
 		It("should fail retrieving config", func() {
-			mockConfig.GetOrgConfigReturns(nil, errors.New("error retrieve"))
+			mockConfig.GetRawOrgConfigReturns(nil, errors.New("error retrieve"))
 			err := configuration.Execute(nil)
 			Expect(err).Should(HaveOccurred())
 			Expect(err.Error()).Should(BeEquivalentTo("error retrieve"))
 		})
 		It("should fail retrieving space config", func() {
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{}, nil)
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{}, nil)
 			mockConfig.OrgSpacesReturns(nil, errors.New("error retrieve"))
 			err := configuration.Execute(nil)
 			Expect(err).Should(HaveOccurred())
 			Expect(err.Error()).Should(BeEquivalentTo("error retrieve"))
 		})
 		It("should fail saving config", func() {
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{}, nil)
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{}, nil)
 			mockConfig.OrgSpacesReturns(&config.Spaces{}, nil)
 			mockConfig.SaveOrgConfigReturns(errors.New("error save"))
 
@@ -747,7 +749,7 @@ var _ = Describe("given update orgs config command", func() {
 		})
 
 		It("should fail saving space config", func() {
-			mockConfig.GetOrgConfigReturns(&config.OrgConfig{}, nil)
+			mockConfig.GetRawOrgConfigReturns(&config.OrgConfig{}, nil)
 			mockConfig.OrgSpacesReturns(&config.Spaces{}, nil)
 			mockConfig.SaveOrgConfigReturns(nil)
 			mockConfig.SaveOrgSpacesReturns(errors.New("error save"))
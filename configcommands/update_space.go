@@ -19,6 +19,8 @@ type UpdateSpaceConfigurationCommand struct {
 	ClearIsolationSegment bool       `long:"clear-isolation-segment" description:"Sets the isolation segment to blank"`
 	ASGs                  []string   `long:"named-asg" description:"Named asg(s) to assign to space, specify multiple times"`
 	ASGsToRemove          []string   `long:"named-asg-to-remove" description:"Named asg(s) to remove, specify multiple times"`
+	StagingASGs           []string   `long:"named-staging-asg" description:"Named asg(s) to assign to space for staging only, specify multiple times"`
+	StagingASGsToRemove   []string   `long:"named-staging-asg-to-remove" description:"Named staging asg(s) to remove, specify multiple times"`
 	Quota                 SpaceQuota `group:"quota"`
 	Developer             UserRole   `group:"developer" namespace:"developer"`
 	Manager               UserRole   `group:"manager" namespace:"manager"`
@@ -28,7 +30,7 @@ type UpdateSpaceConfigurationCommand struct {
 //Execute - updates space configuration`
 func (c *UpdateSpaceConfigurationCommand) Execute(args []string) error {
 	c.initConfig()
-	spaceConfig, err := c.ConfigManager.GetSpaceConfig(c.OrgName, c.SpaceName)
+	spaceConfig, err := c.ConfigManager.GetRawSpaceConfig(c.OrgName, c.SpaceName)
 	if err != nil {
 		return err
 	}
@@ -51,6 +53,8 @@ func (c *UpdateSpaceConfigurationCommand) Execute(args []string) error {
 
 	spaceConfig.ASGs = removeFromSlice(addToSlice(spaceConfig.ASGs, c.ASGs, &errorString), c.ASGsToRemove)
 	validateASGsExist(asgConfigs, spaceConfig.ASGs, &errorString)
+	spaceConfig.StagingASGs = removeFromSlice(addToSlice(spaceConfig.StagingASGs, c.StagingASGs, &errorString), c.StagingASGsToRemove)
+	validateASGsExist(asgConfigs, spaceConfig.StagingASGs, &errorString)
 	updateSpaceQuotaConfig(spaceConfig, c.Quota, &errorString)
 	c.updateUsers(spaceConfig, &errorString)
 
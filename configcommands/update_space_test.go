@@ -41,7 +41,8 @@ var _ = Describe("given update orgs config command", func() {
 			configuration.Quota.TotalReservedRoutePorts = "6"
 			configuration.Quota.TotalServiceKeys = "7"
 			configuration.Quota.AppInstanceLimit = "8"
-			mockConfig.GetSpaceConfigReturns(&config.SpaceConfig{
+			configuration.Quota.AppTaskLimit = "9"
+			mockConfig.GetRawSpaceConfigReturns(&config.SpaceConfig{
 				Org:   orgName,
 				Space: spaceName,
 			}, nil)
@@ -62,13 +63,14 @@ var _ = Describe("given update orgs config command", func() {
 				TotalReservedRoutePorts: 6,
 				TotalServiceKeys:        7,
 				AppInstanceLimit:        8,
+				AppTaskLimit:            9,
 			}))
 		})
 
 		It("should fail with non integer value", func() {
 			configuration.Quota.EnableSpaceQuota = "true"
 			configuration.Quota.MemoryLimit = "asdfasfasf"
-			mockConfig.GetSpaceConfigReturns(&config.SpaceConfig{
+			mockConfig.GetRawSpaceConfigReturns(&config.SpaceConfig{
 				Org:   orgName,
 				Space: spaceName,
 			}, nil)
@@ -82,7 +84,7 @@ var _ = Describe("given update orgs config command", func() {
 	Context("Update named asgs", func() {
 		It("should add named asgs to empty list", func() {
 			configuration.ASGs = []string{"hello", "world"}
-			mockConfig.GetSpaceConfigReturns(&config.SpaceConfig{
+			mockConfig.GetRawSpaceConfigReturns(&config.SpaceConfig{
 				Org:   orgName,
 				Space: spaceName,
 			}, nil)
@@ -107,7 +109,7 @@ var _ = Describe("given update orgs config command", func() {
 
 		It("should error when asg definition doesn't exist", func() {
 			configuration.ASGs = []string{"hello"}
-			mockConfig.GetSpaceConfigReturns(&config.SpaceConfig{
+			mockConfig.GetRawSpaceConfigReturns(&config.SpaceConfig{
 				Org:   orgName,
 				Space: spaceName,
 			}, nil)
@@ -125,7 +127,7 @@ var _ = Describe("given update orgs config command", func() {
 
 		It("should not add asgs that already exist", func() {
 			configuration.ASGs = []string{"hello"}
-			mockConfig.GetSpaceConfigReturns(&config.SpaceConfig{
+			mockConfig.GetRawSpaceConfigReturns(&config.SpaceConfig{
 				Org:   orgName,
 				Space: spaceName,
 				ASGs:  []string{"hello", "world"},
@@ -139,7 +141,7 @@ var _ = Describe("given update orgs config command", func() {
 
 		It("should not duplicates", func() {
 			configuration.ASGs = []string{"hello", "hello", "world"}
-			mockConfig.GetSpaceConfigReturns(&config.SpaceConfig{
+			mockConfig.GetRawSpaceConfigReturns(&config.SpaceConfig{
 				Org:   orgName,
 				Space: spaceName,
 			}, nil)
@@ -156,7 +158,7 @@ var _ = Describe("given update orgs config command", func() {
 			configuration.Manager.Users = []string{"foo", "bar"}
 			configuration.Developer.Users = []string{"hello", "world"}
 			configuration.Auditor.Users = []string{"test", "value"}
-			mockConfig.GetSpaceConfigReturns(&config.SpaceConfig{
+			mockConfig.GetRawSpaceConfigReturns(&config.SpaceConfig{
 				Org:   orgName,
 				Space: spaceName,
 			}, nil)
@@ -183,7 +185,7 @@ var _ = Describe("given update orgs config command", func() {
 			configuration.Manager.Users = []string{"bar"}
 			configuration.Developer.Users = []string{"world"}
 			configuration.Auditor.Users = []string{"value"}
-			mockConfig.GetSpaceConfigReturns(&config.SpaceConfig{
+			mockConfig.GetRawSpaceConfigReturns(&config.SpaceConfig{
 				Org:   orgName,
 				Space: spaceName,
 				Manager: config.UserMgmt{
@@ -210,7 +212,7 @@ var _ = Describe("given update orgs config command", func() {
 			configuration.Manager.Users = []string{"bar", "bar", "foo"}
 			configuration.Developer.Users = []string{"world", "world", "hello"}
 			configuration.Auditor.Users = []string{"value", "value", "test"}
-			mockConfig.GetSpaceConfigReturns(&config.SpaceConfig{
+			mockConfig.GetRawSpaceConfigReturns(&config.SpaceConfig{
 				Org:   orgName,
 				Space: spaceName,
 			}, nil)
@@ -228,7 +230,7 @@ var _ = Describe("given update orgs config command", func() {
 			configuration.Developer.UsersToRemove = []string{"world"}
 			configuration.Auditor.UsersToRemove = []string{"value"}
 
-			mockConfig.GetSpaceConfigReturns(&config.SpaceConfig{
+			mockConfig.GetRawSpaceConfigReturns(&config.SpaceConfig{
 				Org:   orgName,
 				Space: spaceName,
 				Manager: config.UserMgmt{
@@ -265,7 +267,7 @@ var _ = Describe("given update orgs config command", func() {
 			configuration.Developer.SamlUsers = []string{"hello", "world"}
 			configuration.Auditor.SamlUsers = []string{"test", "value"}
 
-			mockConfig.GetSpaceConfigReturns(&config.SpaceConfig{
+			mockConfig.GetRawSpaceConfigReturns(&config.SpaceConfig{
 				Org:   orgName,
 				Space: spaceName,
 			}, nil)
@@ -293,7 +295,7 @@ var _ = Describe("given update orgs config command", func() {
 			configuration.Developer.SamlUsersToRemove = []string{"world"}
 			configuration.Auditor.SamlUsersToRemove = []string{"value"}
 
-			mockConfig.GetSpaceConfigReturns(&config.SpaceConfig{
+			mockConfig.GetRawSpaceConfigReturns(&config.SpaceConfig{
 				Org:   orgName,
 				Space: spaceName,
 				Manager: config.UserMgmt{
@@ -330,7 +332,7 @@ var _ = Describe("given update orgs config command", func() {
 			configuration.Developer.LDAPUsers = []string{"hello", "world"}
 			configuration.Auditor.LDAPUsers = []string{"test", "value"}
 
-			mockConfig.GetSpaceConfigReturns(&config.SpaceConfig{
+			mockConfig.GetRawSpaceConfigReturns(&config.SpaceConfig{
 				Org:   orgName,
 				Space: spaceName,
 			}, nil)
@@ -358,7 +360,7 @@ var _ = Describe("given update orgs config command", func() {
 			configuration.Developer.LDAPUsersToRemove = []string{"world"}
 			configuration.Auditor.LDAPUsersToRemove = []string{"value"}
 
-			mockConfig.GetSpaceConfigReturns(&config.SpaceConfig{
+			mockConfig.GetRawSpaceConfigReturns(&config.SpaceConfig{
 				Org:   orgName,
 				Space: spaceName,
 				Manager: config.UserMgmt{
@@ -395,7 +397,7 @@ var _ = Describe("given update orgs config command", func() {
 			configuration.Developer.LDAPGroups = []string{"hello", "world"}
 			configuration.Auditor.LDAPGroups = []string{"test", "value"}
 
-			mockConfig.GetSpaceConfigReturns(&config.SpaceConfig{
+			mockConfig.GetRawSpaceConfigReturns(&config.SpaceConfig{
 				Org:   orgName,
 				Space: spaceName,
 			}, nil)
@@ -424,7 +426,7 @@ var _ = Describe("given update orgs config command", func() {
 			configuration.Developer.LDAPGroupsToRemove = []string{"world"}
 			configuration.Auditor.LDAPGroupsToRemove = []string{"value"}
 
-			mockConfig.GetSpaceConfigReturns(&config.SpaceConfig{
+			mockConfig.GetRawSpaceConfigReturns(&config.SpaceConfig{
 				Org:   orgName,
 				Space: spaceName,
 				Manager: config.UserMgmt{
@@ -458,13 +460,13 @@ var _ = Describe("given update orgs config command", func() {
 	})
 	Context("Failures", func() {
 		It("should fail retrieving config", func() {
-			mockConfig.GetSpaceConfigReturns(nil, errors.New("error retrieve"))
+			mockConfig.GetRawSpaceConfigReturns(nil, errors.New("error retrieve"))
 			err := configuration.Execute(nil)
 			Expect(err).Should(HaveOccurred())
 			Expect(err.Error()).Should(BeEquivalentTo("error retrieve"))
 		})
 		It("should fail saving config", func() {
-			mockConfig.GetSpaceConfigReturns(&config.SpaceConfig{}, nil)
+			mockConfig.GetRawSpaceConfigReturns(&config.SpaceConfig{}, nil)
 			mockConfig.SaveSpaceConfigReturns(errors.New("error save"))
 
 			err := configuration.Execute(nil)
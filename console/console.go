@@ -0,0 +1,138 @@
+// Package console tags the changes cf-mgmt logs with their severity, CREATE,
+// UPDATE or DELETE, colored green, yellow and red so that long dry-run
+// output can be reviewed at a glance.
+package console
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/op/go-logging"
+	"github.com/xchapter7x/lo"
+)
+
+//Severities of changes
+const (
+	Create = "CREATE"
+	Update = "UPDATE"
+	Delete = "DELETE"
+)
+
+const reset = "\x1b[0m"
+
+var colors = map[string]string{
+	Create: "\x1b[32m",
+	Update: "\x1b[33m",
+	Delete: "\x1b[31m",
+}
+
+// severities maps the leading verb of a change message to its severity
+var severities = map[string]string{
+	"add":          Create,
+	"adding":       Create,
+	"create":       Create,
+	"creating":     Create,
+	"share":        Create,
+	"entitle":      Create,
+	"successfully": Create,
+	"assign":       Update,
+	"assigning":    Update,
+	"update":       Update,
+	"updating":     Update,
+	"set":          Update,
+	"setting":      Update,
+	"reset":        Update,
+	"moving":       Update,
+	"delete":       Delete,
+	"deleting":     Delete,
+	"removing":     Delete,
+	"unshare":      Delete,
+	"revoke":       Delete,
+	"unassigning":  Delete,
+	"unassinging":  Delete,
+	"stop":         Delete,
+}
+
+var (
+	mutex sync.RWMutex
+	color = true
+)
+
+//SetColor - turns coloring of tagged messages on or off
+func SetColor(on bool) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	color = on
+}
+
+func colored() bool {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return color
+}
+
+//Severity - the severity of a change message by its leading verb, ignoring a [dry-run] prefix, empty
+//for messages that are not changes
+func Severity(message string) string {
+	message = strings.TrimPrefix(strings.TrimSpace(message), "[dry-run]:")
+	fields := strings.Fields(message)
+	if len(fields) == 0 {
+		return ""
+	}
+	return severities[strings.ToLower(fields[0])]
+}
+
+//Tag - prefixes the message with the severity, coloring both unless color is off. Messages without
+//a severity are returned as they are.
+func Tag(severity, message string) string {
+	if severity == "" {
+		return message
+	}
+	return paint(severity, "["+severity+"] "+message)
+}
+
+func paint(severity, text string) string {
+	if !colored() {
+		return text
+	}
+	return colors[severity] + text + reset
+}
+
+// formatter tags the info messages of changes, which is the level cf-mgmt
+// logs them at, after the header of the base format
+type formatter struct {
+	base logging.Formatter
+}
+
+func (f *formatter) Format(calldepth int, r *logging.Record, w io.Writer) error {
+	severity := ""
+	if r.Level == logging.INFO {
+		severity = Severity(r.Message())
+	}
+	if severity == "" {
+		return f.base.Format(calldepth+1, r, w)
+	}
+	var buffer bytes.Buffer
+	if err := f.base.Format(calldepth+1, r, &buffer); err != nil {
+		return err
+	}
+	line := buffer.String()
+	if i := strings.Index(line, "] "); i >= 0 {
+		line = line[:i+2] + "[" + severity + "] " + line[i+2:]
+	}
+	_, err := io.WriteString(w, paint(severity, line))
+	return err
+}
+
+//Configure - tags the changes logged through lo.G with their severity, colored unless noColor is set
+func Configure(noColor bool) {
+	SetColor(!noColor)
+	level := logging.GetLevel(lo.LOG_MODULE)
+	backend := logging.NewBackendFormatter(logging.NewLogBackend(os.Stderr, "", log.LstdFlags), &formatter{base: logging.GlogFormatter})
+	logging.SetBackend(backend)
+	logging.SetLevel(level, lo.LOG_MODULE)
+}
@@ -0,0 +1,32 @@
+package console_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/console"
+)
+
+var _ = Describe("given console output", func() {
+	AfterEach(func() {
+		console.SetColor(true)
+	})
+
+	It("classifies changes by their leading verb", func() {
+		Expect(console.Severity("create space dev for org test")).Should(Equal(console.Create))
+		Expect(console.Severity("[dry-run]: removing user jdoe from org test with role manager")).Should(Equal(console.Delete))
+		Expect(console.Severity("Updating org quota default")).Should(Equal(console.Update))
+		Expect(console.Severity("Processing org: test")).Should(BeEmpty())
+		Expect(console.Severity("")).Should(BeEmpty())
+	})
+
+	It("colors tagged messages", func() {
+		Expect(console.Tag(console.Create, "org test")).Should(Equal("\x1b[32m[CREATE] org test\x1b[0m"))
+		Expect(console.Tag(console.Delete, "org test")).Should(Equal("\x1b[31m[DELETE] org test\x1b[0m"))
+		Expect(console.Tag("", "org test")).Should(Equal("org test"))
+	})
+
+	It("only tags messages without color", func() {
+		console.SetColor(false)
+		Expect(console.Tag(console.Update, "org test")).Should(Equal("[UPDATE] org test"))
+	})
+})
@@ -0,0 +1,107 @@
+// Package diskcache keeps directory and uaa lookups on disk for a limited
+// time, so that runs in quick succession, such as a plan followed by an
+// apply, do not repeat the same lookups.
+package diskcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pivotalservices/cf-mgmt/stats"
+	"github.com/xchapter7x/lo"
+)
+
+// DefaultTTL is how long a lookup is reused when no ttl is given.
+const DefaultTTL = 10 * time.Minute
+
+type entry struct {
+	Key      string          `json:"key"`
+	StoredAt time.Time       `json:"stored_at"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// Cache stores values as json files under Dir, one directory per namespace.
+// Entries older than TTL are ignored. A nil Cache caches nothing.
+type Cache struct {
+	Dir string
+	TTL time.Duration
+	Now func() time.Time
+}
+
+//New - creates a cache in dir, with the default ttl when ttl is not positive
+func New(dir string, ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Cache{Dir: dir, TTL: ttl, Now: time.Now}
+}
+
+func (c *Cache) path(namespace, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.Dir, namespace, hex.EncodeToString(sum[:])+".json")
+}
+
+//Get - reads the value stored for the key into value, returning whether a value younger than the ttl was found
+func (c *Cache) Get(namespace, key string, value interface{}) bool {
+	if c == nil {
+		return false
+	}
+	data, err := ioutil.ReadFile(c.path(namespace, key))
+	if err != nil {
+		stats.CacheMiss("disk " + namespace)
+		return false
+	}
+	stored := entry{}
+	if err := json.Unmarshal(data, &stored); err != nil || stored.Key != key || c.Now().Sub(stored.StoredAt) > c.TTL {
+		stats.CacheMiss("disk " + namespace)
+		return false
+	}
+	if err := json.Unmarshal(stored.Value, value); err != nil {
+		stats.CacheMiss("disk " + namespace)
+		return false
+	}
+	stats.CacheHit("disk " + namespace)
+	return true
+}
+
+//Put - stores the value for the key. Failing to store is logged, as the value is only looked up again.
+func (c *Cache) Put(namespace, key string, value interface{}) {
+	if c == nil {
+		return
+	}
+	if err := c.put(namespace, key, value); err != nil {
+		lo.G.Debugf("Unable to cache %s lookup in %s: %s", namespace, c.Dir, err)
+	}
+}
+
+func (c *Cache) put(namespace, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	data, err = json.Marshal(entry{Key: key, StoredAt: c.Now(), Value: data})
+	if err != nil {
+		return err
+	}
+	fp := c.path(namespace, key)
+	// lookups include user names and emails, so only the owner can read them
+	if err := os.MkdirAll(filepath.Dir(fp), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fp, data, 0600)
+}
+
+//Invalidate - removes every value of the namespace, such as once the values it holds have changed
+func (c *Cache) Invalidate(namespace string) {
+	if c == nil {
+		return
+	}
+	if err := os.RemoveAll(filepath.Join(c.Dir, namespace)); err != nil {
+		lo.G.Debugf("Unable to invalidate %s lookups in %s: %s", namespace, c.Dir, err)
+	}
+}
@@ -0,0 +1,75 @@
+package diskcache_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/diskcache"
+)
+
+var _ = Describe("given a disk cache", func() {
+	var (
+		dir   string
+		now   time.Time
+		cache *diskcache.Cache
+	)
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "diskcache")
+		Expect(err).ShouldNot(HaveOccurred())
+		now = time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+		cache = diskcache.New(dir, 10*time.Minute)
+		cache.Now = func() time.Time { return now }
+	})
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("returns a stored value within the ttl", func() {
+		cache.Put("groups", "devs", []string{"cn=jdoe"})
+		now = now.Add(9 * time.Minute)
+		var value []string
+		Expect(cache.Get("groups", "devs", &value)).Should(BeTrue())
+		Expect(value).Should(Equal([]string{"cn=jdoe"}))
+	})
+
+	It("ignores a value older than the ttl", func() {
+		cache.Put("groups", "devs", []string{"cn=jdoe"})
+		now = now.Add(11 * time.Minute)
+		var value []string
+		Expect(cache.Get("groups", "devs", &value)).Should(BeFalse())
+	})
+
+	It("misses keys that were not stored", func() {
+		var value []string
+		Expect(cache.Get("groups", "ops", &value)).Should(BeFalse())
+	})
+
+	It("only lets the owner read the values", func() {
+		cache.Put("groups", "devs", []string{"cn=jdoe"})
+		info, err := os.Stat(filepath.Join(dir, "groups"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(info.Mode().Perm()).Should(Equal(os.FileMode(0700)))
+	})
+
+	It("removes the values of an invalidated namespace only", func() {
+		cache.Put("groups", "devs", []string{"cn=jdoe"})
+		cache.Put("users", "jdoe", "cn=jdoe")
+		cache.Invalidate("groups")
+		var groups []string
+		Expect(cache.Get("groups", "devs", &groups)).Should(BeFalse())
+		var user string
+		Expect(cache.Get("users", "jdoe", &user)).Should(BeTrue())
+	})
+
+	It("caches nothing when nil", func() {
+		var nilCache *diskcache.Cache
+		nilCache.Put("groups", "devs", []string{"cn=jdoe"})
+		var value []string
+		Expect(nilCache.Get("groups", "devs", &value)).Should(BeFalse())
+	})
+})
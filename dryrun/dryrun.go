@@ -0,0 +1,119 @@
+// Package dryrun collects the changes the org, space and user managers would
+// make while peeking, so that a --peek run ends with one consolidated plan of
+// the orgs and spaces to create, update or delete and the roles to grant or
+// revoke, instead of only the [dry-run] lines scattered through its log.
+package dryrun
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+//Actions of a Change, the same as those of the changes of a simulated plan
+const (
+	Create = "create"
+	Update = "update"
+	Delete = "delete"
+)
+
+//Kinds of the changes the managers record
+const (
+	Org       = "org"
+	Space     = "space"
+	OrgRole   = "org role"
+	SpaceRole = "space role"
+	OrgUser   = "org user"
+)
+
+// kinds orders the changes of a plan
+var kinds = []string{Org, Space, OrgRole, SpaceRole, OrgUser}
+
+//Change - a change a manager would make, such as a space to create or a role to grant
+type Change struct {
+	Action string `json:"action"`
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	// Detail lists the fields an update changes, or where a granted role comes
+	// from when attribute-roles is set
+	Detail string `json:"detail,omitempty"`
+}
+
+var (
+	mutex     sync.Mutex
+	recording bool
+	changes   []Change
+	// suspended are the changes of the collections a nested Start suspended
+	suspended [][]Change
+)
+
+//Start - starts collecting the changes the managers record. Starting while collecting, such as to plan
+//the changes of a simulated foundation during a dry run, suspends collecting until the matching Stop
+func Start() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if recording {
+		suspended = append(suspended, changes)
+	}
+	recording = true
+	changes = nil
+}
+
+//Stop - stops collecting and returns the changes recorded since Start, grouped by kind and sorted by
+//name within each kind, each change once
+func Stop() []Change {
+	mutex.Lock()
+	defer mutex.Unlock()
+	result := plan(changes)
+	changes = nil
+	recording = len(suspended) > 0
+	if recording {
+		changes = suspended[len(suspended)-1]
+		suspended = suspended[:len(suspended)-1]
+	}
+	return result
+}
+
+//Record - records a change a manager would make, when collecting
+func Record(action, kind, name, detail string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if !recording {
+		return
+	}
+	changes = append(changes, Change{Action: action, Kind: kind, Name: name, Detail: detail})
+}
+
+//RoleName - the name of the change of a role, such as jdoe as developer of finance/dev
+func RoleName(userName, role, orgName, spaceName string) string {
+	if spaceName == "" {
+		return fmt.Sprintf("%s as %s of %s", userName, role, orgName)
+	}
+	return fmt.Sprintf("%s as %s of %s/%s", userName, role, orgName, spaceName)
+}
+
+func plan(recorded []Change) []Change {
+	order := make(map[string]int)
+	for i, kind := range kinds {
+		order[kind] = i
+	}
+	seen := make(map[Change]bool)
+	result := []Change{}
+	for _, change := range recorded {
+		if seen[change] {
+			continue
+		}
+		seen[change] = true
+		result = append(result, change)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return order[result[i].Kind] < order[result[j].Kind]
+		}
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].Action < result[j].Action
+	})
+	return result
+}
@@ -0,0 +1,52 @@
+package dryrun_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/dryrun"
+)
+
+var _ = Describe("given a dry run", func() {
+	AfterEach(func() {
+		dryrun.Stop()
+	})
+
+	It("only collects changes once started", func() {
+		dryrun.Record(dryrun.Create, dryrun.Org, "org1", "")
+		dryrun.Start()
+		dryrun.Record(dryrun.Create, dryrun.Org, "org2", "")
+		Expect(dryrun.Stop()).Should(Equal([]dryrun.Change{{Action: dryrun.Create, Kind: dryrun.Org, Name: "org2"}}))
+		dryrun.Record(dryrun.Create, dryrun.Org, "org3", "")
+		Expect(dryrun.Stop()).Should(BeEmpty())
+	})
+
+	It("suspends collecting while a nested collection runs", func() {
+		dryrun.Start()
+		dryrun.Record(dryrun.Create, dryrun.Org, "org1", "")
+		dryrun.Start()
+		dryrun.Record(dryrun.Create, dryrun.Org, "simulated", "")
+		Expect(dryrun.Stop()).Should(Equal([]dryrun.Change{{Action: dryrun.Create, Kind: dryrun.Org, Name: "simulated"}}))
+		dryrun.Record(dryrun.Create, dryrun.Org, "org2", "")
+		Expect(dryrun.Stop()).Should(Equal([]dryrun.Change{
+			{Action: dryrun.Create, Kind: dryrun.Org, Name: "org1"},
+			{Action: dryrun.Create, Kind: dryrun.Org, Name: "org2"},
+		}))
+	})
+
+	It("groups the plan by kind, sorted by name, each change once", func() {
+		dryrun.Start()
+		dryrun.Record(dryrun.Create, dryrun.SpaceRole, dryrun.RoleName("jdoe", "developer", "org1", "dev"), "")
+		dryrun.Record(dryrun.Delete, dryrun.Space, "org1/old", "")
+		dryrun.Record(dryrun.Create, dryrun.Space, "org1/dev", "")
+		dryrun.Record(dryrun.Create, dryrun.Org, "org1", "")
+		dryrun.Record(dryrun.Create, dryrun.Space, "org1/dev", "")
+		dryrun.Record(dryrun.Delete, dryrun.OrgRole, dryrun.RoleName("asmith", "manager", "org1", ""), "")
+		Expect(dryrun.Stop()).Should(Equal([]dryrun.Change{
+			{Action: dryrun.Create, Kind: dryrun.Org, Name: "org1"},
+			{Action: dryrun.Create, Kind: dryrun.Space, Name: "org1/dev"},
+			{Action: dryrun.Delete, Kind: dryrun.Space, Name: "org1/old"},
+			{Action: dryrun.Delete, Kind: dryrun.OrgRole, Name: "asmith as manager of org1"},
+			{Action: dryrun.Create, Kind: dryrun.SpaceRole, Name: "jdoe as developer of org1/dev"},
+		}))
+	})
+})
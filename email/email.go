@@ -0,0 +1,58 @@
+// Package email sends plain text email through the smtp server of an email
+// section of cf-mgmt.yml.
+package email
+
+import (
+	"fmt"
+	"net/smtp"
+	"os"
+	"strings"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/redact"
+)
+
+// Sender emails users whose username is an email. The smtp password, if the
+// server requires one, is read from the SMTP_PASSWORD environment variable.
+type Sender struct {
+	Config config.EmailDelivery
+	Auth   smtp.Auth
+	// SendMail sends a message, smtp.SendMail unless replaced such as by tests
+	SendMail func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+//NewSender - a sender for the email section named section, with subject as the subject unless the section sets one
+func NewSender(cfg config.EmailDelivery, section, subject string) (*Sender, error) {
+	if cfg.Host == "" || cfg.From == "" {
+		return nil, fmt.Errorf("%s requires smtp-host and from in cf-mgmt.yml", section)
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 25
+	}
+	if cfg.Subject == "" {
+		cfg.Subject = subject
+	}
+	sender := &Sender{Config: cfg, SendMail: smtp.SendMail}
+	if cfg.Username != "" {
+		password := os.Getenv("SMTP_PASSWORD")
+		redact.Secrets(password)
+		sender.Auth = smtp.PlainAuth("", cfg.Username, password, cfg.Host)
+	}
+	return sender, nil
+}
+
+//Send - emails body to the recipients, which must all be emails
+func (s *Sender) Send(to []string, body string) error {
+	if len(to) == 0 {
+		return fmt.Errorf("no recipients to email")
+	}
+	for _, recipient := range to {
+		if !strings.Contains(recipient, "@") {
+			return fmt.Errorf("%s is not an email", recipient)
+		}
+	}
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s",
+		s.Config.From, strings.Join(to, ", "), s.Config.Subject, strings.ReplaceAll(body, "\n", "\r\n"))
+	addr := fmt.Sprintf("%s:%d", s.Config.Host, s.Config.Port)
+	return s.SendMail(addr, s.Auth, s.Config.From, to, []byte(message))
+}
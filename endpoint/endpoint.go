@@ -0,0 +1,117 @@
+// Package endpoint resolves the cloud controller, uaa and login endpoints of
+// a foundation. They are derived from the system domain, as api.<domain> and
+// uaa.<domain>, unless they are configured explicitly for deployments that do
+// not follow that layout, such as sharded cloud controllers or local
+// development foundations.
+package endpoint
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+//Options - endpoints that replace the ones derived from the system domain when set
+type Options struct {
+	// API is the cloud controller endpoint, such as https://api.sys.example.com
+	API string
+	// UAA is the uaa endpoint users, groups and clients are managed on
+	UAA string
+	// Login is the endpoint tokens are requested from, the uaa endpoint when not set
+	Login string
+}
+
+var (
+	mutex   sync.Mutex
+	options Options
+)
+
+//Configure - sets the endpoints used by every cloud controller and uaa client
+func Configure(newOptions Options) error {
+	for name, value := range map[string]string{"api": newOptions.API, "uaa": newOptions.UAA, "login": newOptions.Login} {
+		if value == "" {
+			continue
+		}
+		u, err := url.Parse(value)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("%s endpoint [%s] must be a url such as https://%s.sys.example.com", name, value, name)
+		}
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	options = Options{
+		API:   strings.TrimSuffix(newOptions.API, "/"),
+		UAA:   strings.TrimSuffix(newOptions.UAA, "/"),
+		Login: strings.TrimSuffix(newOptions.Login, "/"),
+	}
+	return nil
+}
+
+func configured() Options {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return options
+}
+
+//API - the cloud controller endpoint of the foundation
+func API(systemDomain string) string {
+	if api := configured().API; api != "" {
+		return api
+	}
+	return fmt.Sprintf("https://api.%s", systemDomain)
+}
+
+//UAA - the uaa endpoint of the foundation
+func UAA(systemDomain string) string {
+	if uaa := configured().UAA; uaa != "" {
+		return uaa
+	}
+	return fmt.Sprintf("https://uaa.%s", systemDomain)
+}
+
+//Login - the endpoint tokens are requested from
+func Login(systemDomain string) string {
+	if login := configured().Login; login != "" {
+		return login
+	}
+	return UAA(systemDomain)
+}
+
+//TokenURL - the url tokens are requested from
+func TokenURL(systemDomain string) string {
+	return Login(systemDomain) + "/oauth/token"
+}
+
+//Transport - sends the token requests of the cloud controller client, which
+//requests tokens from the endpoint the cloud controller advertises, to the
+//configured login endpoint. Other requests are sent unchanged.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	login := configured().Login
+	if login == "" {
+		return base
+	}
+	loginURL, _ := url.Parse(login)
+	return &loginTransport{login: loginURL, base: base}
+}
+
+type loginTransport struct {
+	login *url.URL
+	base  http.RoundTripper
+}
+
+func (t *loginTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if strings.HasSuffix(req.URL.Path, "/oauth/token") {
+		req = req.Clone(req.Context())
+		req.URL.Scheme = t.login.Scheme
+		req.URL.Host = t.login.Host
+		req.URL.Path = strings.TrimSuffix(t.login.Path, "/") + "/oauth/token"
+		req.Host = t.login.Host
+	}
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
@@ -0,0 +1,57 @@
+package endpoint_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/endpoint"
+)
+
+var _ = Describe("given endpoint", func() {
+	AfterEach(func() {
+		Expect(endpoint.Configure(endpoint.Options{})).Should(Succeed())
+	})
+
+	It("derives the endpoints from the system domain", func() {
+		Expect(endpoint.API("sys.example.com")).Should(Equal("https://api.sys.example.com"))
+		Expect(endpoint.UAA("sys.example.com")).Should(Equal("https://uaa.sys.example.com"))
+		Expect(endpoint.TokenURL("sys.example.com")).Should(Equal("https://uaa.sys.example.com/oauth/token"))
+	})
+
+	It("uses the configured endpoints", func() {
+		Expect(endpoint.Configure(endpoint.Options{
+			API:   "https://api.shard1.example.com/",
+			UAA:   "https://uaa.example.com",
+			Login: "https://login.example.com",
+		})).Should(Succeed())
+		Expect(endpoint.API("sys.example.com")).Should(Equal("https://api.shard1.example.com"))
+		Expect(endpoint.UAA("sys.example.com")).Should(Equal("https://uaa.example.com"))
+		Expect(endpoint.TokenURL("sys.example.com")).Should(Equal("https://login.example.com/oauth/token"))
+	})
+
+	It("requests tokens from the uaa endpoint without a login endpoint", func() {
+		Expect(endpoint.Configure(endpoint.Options{UAA: "https://uaa.example.com"})).Should(Succeed())
+		Expect(endpoint.Login("sys.example.com")).Should(Equal("https://uaa.example.com"))
+	})
+
+	It("rejects endpoints that are not urls", func() {
+		err := endpoint.Configure(endpoint.Options{API: "api.example.com"})
+		Expect(err).Should(MatchError("api endpoint [api.example.com] must be a url such as https://api.sys.example.com"))
+	})
+
+	It("sends token requests to the login endpoint", func() {
+		var paths []string
+		login := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+		}))
+		defer login.Close()
+		Expect(endpoint.Configure(endpoint.Options{Login: login.URL + "/login"})).Should(Succeed())
+		client := &http.Client{Transport: endpoint.Transport(http.DefaultTransport)}
+		resp, err := client.Post("http://uaa.invalid/oauth/token", "application/x-www-form-urlencoded", nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		resp.Body.Close()
+		Expect(paths).Should(Equal([]string{"/login/oauth/token"}))
+	})
+})
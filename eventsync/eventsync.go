@@ -0,0 +1,256 @@
+// Package eventsync listens for the events of identity providers and HR
+// systems, such as a user leaving the company or the members of a group
+// changing, and syncs the users of the orgs they affect within minutes
+// instead of waiting for the next scheduled run.
+package eventsync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/xchapter7x/lo"
+)
+
+// UserEvent is a change of a user, such as the user leaving the company, or
+// of the members of a group. It is read from the json of a message, or from
+// the data of a CloudEvent.
+type UserEvent struct {
+	Type string `json:"type,omitempty"`
+	// User is the user name, ldap user id or email of the user
+	User string `json:"user,omitempty"`
+	// Group is the name or dn of the ldap group
+	Group string `json:"group,omitempty"`
+}
+
+//ParseEvent - reads the event of a message, which names a user or a group
+func ParseEvent(data []byte) (UserEvent, error) {
+	envelope := struct {
+		SpecVersion string          `json:"specversion"`
+		Type        string          `json:"type"`
+		Data        json.RawMessage `json:"data"`
+	}{}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return UserEvent{}, fmt.Errorf("event is not json: %s", err)
+	}
+	event := UserEvent{}
+	if envelope.SpecVersion != "" && len(envelope.Data) > 0 {
+		data = envelope.Data
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return UserEvent{}, fmt.Errorf("event is not json: %s", err)
+	}
+	if event.Type == "" {
+		event.Type = envelope.Type
+	}
+	if event.User == "" && event.Group == "" {
+		return UserEvent{}, fmt.Errorf("event names neither a user nor a group")
+	}
+	return event, nil
+}
+
+//AffectedOrgs - the configured orgs whose users the event changes: the orgs the user holds a role in,
+//given as holding, and the orgs whose configuration, or that of their spaces, names the user or group
+func AffectedOrgs(reader config.Reader, event UserEvent, holding []string) ([]string, error) {
+	orgConfigs, err := reader.GetOrgConfigs()
+	if err != nil {
+		return nil, err
+	}
+	spaceConfigs, err := reader.GetSpaceConfigs()
+	if err != nil {
+		return nil, err
+	}
+	affected := make(map[string]bool)
+	for _, org := range holding {
+		affected[strings.ToLower(org)] = true
+	}
+	var orgs []string
+	for i := range orgConfigs {
+		orgConfig := &orgConfigs[i]
+		names := affected[strings.ToLower(orgConfig.Org)] ||
+			namesUserOrGroup(event, orgConfig.GetManagerGroups(), orgConfig.Manager) ||
+			namesUserOrGroup(event, orgConfig.GetBillingManagerGroups(), orgConfig.BillingManager) ||
+			namesUserOrGroup(event, orgConfig.GetAuditorGroups(), orgConfig.Auditor)
+		for j := range spaceConfigs {
+			spaceConfig := &spaceConfigs[j]
+			if names || spaceConfig.Org != orgConfig.Org {
+				continue
+			}
+			names = namesUserOrGroup(event, spaceConfig.GetDeveloperGroups(), spaceConfig.Developer) ||
+				namesUserOrGroup(event, spaceConfig.GetManagerGroups(), spaceConfig.Manager) ||
+				namesUserOrGroup(event, spaceConfig.GetAuditorGroups(), spaceConfig.Auditor)
+		}
+		if names {
+			orgs = append(orgs, orgConfig.Org)
+		}
+	}
+	return orgs, nil
+}
+
+func namesUserOrGroup(event UserEvent, groups []string, users config.UserMgmt) bool {
+	if event.Group != "" {
+		for _, group := range groups {
+			if groupMatches(group, event.Group) {
+				return true
+			}
+		}
+	}
+	if event.User != "" {
+		for _, list := range [][]string{users.Users, users.LDAPUsers, users.SamlUsers} {
+			for _, user := range list {
+				if strings.EqualFold(user, event.User) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// groupMatches compares a configured group with the group of an event, which
+// may be the dn of the group, such as cn=payments,ou=groups,dc=example,dc=com
+func groupMatches(configured, group string) bool {
+	if strings.EqualFold(configured, group) {
+		return true
+	}
+	rdn := strings.SplitN(group, ",", 2)[0]
+	if parts := strings.SplitN(rdn, "=", 2); len(parts) == 2 && strings.Contains(group, ",") {
+		return strings.EqualFold(configured, strings.TrimSpace(parts[1]))
+	}
+	return false
+}
+
+// backoff limits of reconnecting to a source that failed
+const (
+	defaultBackoff = time.Second
+	maxBackoff     = time.Minute
+)
+
+// Listener syncs the users of the orgs affected by the events of a source,
+// collecting the events of a Window so that a burst of them, such as a
+// reorganization, syncs each org once.
+type Listener struct {
+	Source Source
+	Window time.Duration
+	// Backoff is the time before reconnecting to the source after receiving
+	// fails, doubled with each failure in a row up to a minute, a second when
+	// not set
+	Backoff time.Duration
+	// Affected returns the orgs the event affects
+	Affected func(event UserEvent) ([]string, error)
+	// Sync syncs the users of the orgs
+	Sync func(orgs []string) error
+}
+
+//Run - receives events until ctx is done, syncing the orgs they affect at the end of each window. The
+//messages received are committed once the orgs they affect are synced.
+func (l *Listener) Run(ctx context.Context) error {
+	messages := make(chan Message, 100)
+	go l.receive(ctx, messages)
+	pending := make(map[string]bool)
+	var window <-chan time.Time
+	// commit commits the messages processed since the last commit
+	var commit func() error
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case message := <-messages:
+			if message.Commit != nil {
+				commit = message.Commit
+			}
+			for _, org := range l.affectedOrgs(message.Data) {
+				pending[org] = true
+			}
+			if window == nil && len(pending) > 0 {
+				window = time.After(l.Window)
+			}
+			if window == nil {
+				l.commit(commit)
+				commit = nil
+			}
+		case <-window:
+			window = nil
+			orgs := make([]string, 0, len(pending))
+			for org := range pending {
+				orgs = append(orgs, org)
+			}
+			sort.Strings(orgs)
+			pending = make(map[string]bool)
+			if err := l.Sync(orgs); err != nil {
+				lo.G.Errorf("Unable to sync the users of orgs %s, they are synced by the next scheduled run: %s", strings.Join(orgs, ", "), err)
+				continue
+			}
+			l.commit(commit)
+			commit = nil
+		}
+	}
+}
+
+// receive hands the messages of the source to messages until ctx is done,
+// reconnecting with backoff when receiving fails
+func (l *Listener) receive(ctx context.Context, messages chan<- Message) {
+	backoff := l.Backoff
+	if backoff <= 0 {
+		backoff = defaultBackoff
+	}
+	wait := backoff
+	for {
+		err := l.Source.Receive(ctx, func(message Message) {
+			wait = backoff
+			select {
+			case messages <- message:
+			case <-ctx.Done():
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			err = fmt.Errorf("the source stopped sending events")
+		}
+		lo.G.Errorf("Unable to receive events, reconnecting in %s: %s", wait, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		if wait *= 2; wait > maxBackoff {
+			wait = maxBackoff
+		}
+	}
+}
+
+// affectedOrgs returns the orgs the event of a message affects, none for a
+// message that is not an event
+func (l *Listener) affectedOrgs(data []byte) []string {
+	event, err := ParseEvent(data)
+	if err != nil {
+		lo.G.Warningf("Ignoring event: %s", err)
+		return nil
+	}
+	orgs, err := l.Affected(event)
+	if err != nil {
+		lo.G.Errorf("Unable to find the orgs affected by the event of user [%s] and group [%s]: %s", event.User, event.Group, err)
+		return nil
+	}
+	if len(orgs) == 0 {
+		lo.G.Debugf("The event of user [%s] and group [%s] affects no configured org", event.User, event.Group)
+		return nil
+	}
+	lo.G.Noticef("The event of user [%s] and group [%s] affects orgs %s", event.User, event.Group, strings.Join(orgs, ", "))
+	return orgs
+}
+
+func (l *Listener) commit(commit func() error) {
+	if commit == nil {
+		return
+	}
+	if err := commit(); err != nil {
+		lo.G.Errorf("Unable to commit the events received, they are received again after a restart: %s", err)
+	}
+}
@@ -0,0 +1,287 @@
+package eventsync_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/config"
+	configfakes "github.com/pivotalservices/cf-mgmt/config/fakes"
+	"github.com/pivotalservices/cf-mgmt/eventsync"
+)
+
+type fakeSource struct {
+	messages []string
+	// failures is the number of times receiving fails before the messages are sent
+	failures  int
+	receives  int
+	committed chan string
+}
+
+func (s *fakeSource) Receive(ctx context.Context, handle func(message eventsync.Message)) error {
+	s.receives++
+	if s.receives <= s.failures {
+		return fmt.Errorf("connection refused")
+	}
+	for _, message := range s.messages {
+		data := message
+		handle(eventsync.Message{Data: []byte(data), Commit: func() error {
+			if s.committed != nil {
+				s.committed <- data
+			}
+			return nil
+		}})
+	}
+	<-ctx.Done()
+	return nil
+}
+
+var _ = Describe("given user and group events", func() {
+	Context("ParseEvent", func() {
+		It("reads the user and group of a message", func() {
+			event, err := eventsync.ParseEvent([]byte(`{"type":"user.terminated","user":"jdoe"}`))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(event).Should(Equal(eventsync.UserEvent{Type: "user.terminated", User: "jdoe"}))
+		})
+
+		It("reads the data of a cloud event", func() {
+			event, err := eventsync.ParseEvent([]byte(`{"specversion":"1.0","type":"group.changed","data":{"group":"payments-devs"}}`))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(event).Should(Equal(eventsync.UserEvent{Type: "group.changed", Group: "payments-devs"}))
+		})
+
+		It("requires a user or a group", func() {
+			_, err := eventsync.ParseEvent([]byte(`{"type":"user.created"}`))
+			Expect(err).Should(MatchError("event names neither a user nor a group"))
+			_, err = eventsync.ParseEvent([]byte(`not json`))
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("AffectedOrgs", func() {
+		var reader *configfakes.FakeReader
+
+		BeforeEach(func() {
+			reader = new(configfakes.FakeReader)
+			orgs := []config.OrgConfig{{Org: "org1"}, {Org: "org2"}, {Org: "org3"}}
+			orgs[0].Manager.LDAPGroups = []string{"org1-managers"}
+			orgs[2].Auditor.Users = []string{"JDoe"}
+			reader.GetOrgConfigsReturns(orgs, nil)
+			spaces := []config.SpaceConfig{{Org: "org2", Space: "space1"}}
+			spaces[0].Developer.LDAPGroups = []string{"payments-devs"}
+			reader.GetSpaceConfigsReturns(spaces, nil)
+		})
+
+		It("finds the orgs granting roles to a group", func() {
+			orgs, err := eventsync.AffectedOrgs(reader, eventsync.UserEvent{Group: "Payments-Devs"}, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(orgs).Should(Equal([]string{"org2"}))
+		})
+
+		It("matches a group by the cn of its dn", func() {
+			orgs, err := eventsync.AffectedOrgs(reader, eventsync.UserEvent{Group: "cn=org1-managers,ou=groups,dc=example,dc=com"}, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(orgs).Should(Equal([]string{"org1"}))
+		})
+
+		It("finds the orgs listing a user or where the user holds a role", func() {
+			orgs, err := eventsync.AffectedOrgs(reader, eventsync.UserEvent{User: "jdoe"}, []string{"ORG1", "unmanaged"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(orgs).Should(Equal([]string{"org1", "org3"}))
+		})
+	})
+
+	Context("Listener", func() {
+		It("syncs the orgs affected by the events of a window once", func() {
+			source := &fakeSource{messages: []string{
+				`{"user":"jdoe"}`,
+				`not json`,
+				`{"group":"payments-devs"}`,
+				`{"user":"asmith"}`,
+			}}
+			synced := make(chan []string, 1)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			listener := &eventsync.Listener{
+				Source: source,
+				Window: 10 * time.Millisecond,
+				Affected: func(event eventsync.UserEvent) ([]string, error) {
+					if event.Group != "" {
+						return []string{"org2"}, nil
+					}
+					if event.User == "asmith" {
+						return nil, fmt.Errorf("cannot list org users")
+					}
+					return []string{"org3", "org2"}, nil
+				},
+				Sync: func(orgs []string) error {
+					synced <- orgs
+					return nil
+				},
+			}
+			done := make(chan error, 1)
+			go func() { done <- listener.Run(ctx) }()
+			Eventually(synced).Should(Receive(Equal([]string{"org2", "org3"})))
+			cancel()
+			Eventually(done).Should(Receive(BeNil()))
+			Expect(synced).ShouldNot(Receive())
+		})
+
+		It("reconnects when receiving fails and commits the messages once their orgs are synced", func() {
+			source := &fakeSource{messages: []string{`{"user":"jdoe"}`, `{"user":"nobody"}`}, failures: 2, committed: make(chan string, 2)}
+			synced := make(chan []string, 1)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			listener := &eventsync.Listener{
+				Source:  source,
+				Window:  50 * time.Millisecond,
+				Backoff: time.Millisecond,
+				Affected: func(event eventsync.UserEvent) ([]string, error) {
+					if event.User == "jdoe" {
+						return []string{"org1"}, nil
+					}
+					return nil, nil
+				},
+				Sync: func(orgs []string) error {
+					Expect(source.committed).ShouldNot(Receive())
+					synced <- orgs
+					return nil
+				},
+			}
+			go listener.Run(ctx)
+			Eventually(synced).Should(Receive(Equal([]string{"org1"})))
+			Eventually(source.committed).Should(Receive(Equal(`{"user":"nobody"}`)))
+			Expect(source.receives).Should(Equal(3))
+		})
+
+		It("does not commit the messages of orgs that failed to sync", func() {
+			source := &fakeSource{messages: []string{`{"user":"jdoe"}`}, committed: make(chan string, 1)}
+			synced := make(chan []string, 1)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			listener := &eventsync.Listener{
+				Source: source,
+				Window: 10 * time.Millisecond,
+				Affected: func(event eventsync.UserEvent) ([]string, error) {
+					return []string{"org1"}, nil
+				},
+				Sync: func(orgs []string) error {
+					synced <- orgs
+					return fmt.Errorf("uaa is unavailable")
+				},
+			}
+			go listener.Run(ctx)
+			Eventually(synced).Should(Receive())
+			Consistently(source.committed, 50*time.Millisecond).ShouldNot(Receive())
+		})
+	})
+
+	Context("NewSource", func() {
+		It("requires a source it can receive from", func() {
+			_, err := eventsync.NewSource("https://idp.example.com/events", "cf-mgmt", false)
+			Expect(err).Should(MatchError("events source https://idp.example.com/events must be a nats, kafka+http or kafka+https url"))
+			_, err = eventsync.NewSource("nats://nats.example.com:4222", "cf-mgmt", false)
+			Expect(err).Should(HaveOccurred())
+			_, err = eventsync.NewSource("kafka+https://kafka-rest.example.com/hr-events", "cf-mgmt", false)
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("consumes the records of a kafka topic with a rest proxy", func() {
+			var server *httptest.Server
+			deleted := make(chan bool, 1)
+			committed := make(chan string, 1)
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				switch {
+				case r.Method == http.MethodPost && r.URL.Path == "/consumers/cf-mgmt":
+					Expect(string(body)).Should(ContainSubstring(`"auto.offset.reset":"latest"`))
+					Expect(string(body)).Should(ContainSubstring(`"auto.commit.enable":"false"`))
+					json.NewEncoder(w).Encode(map[string]string{"instance_id": "i1", "base_uri": server.URL + "/consumers/cf-mgmt/instances/i1"})
+				case r.Method == http.MethodPost && r.URL.Path == "/consumers/cf-mgmt/instances/i1/subscription":
+					Expect(string(body)).Should(Equal(`{"topics":["hr-events"]}`))
+					w.WriteHeader(http.StatusNoContent)
+				case r.Method == http.MethodGet && r.URL.Path == "/consumers/cf-mgmt/instances/i1/records":
+					Expect(r.Header.Get("Accept")).Should(Equal("application/vnd.kafka.json.v2+json"))
+					w.Write([]byte(`[{"topic":"hr-events","partition":2,"offset":41,"value":{"user":"jdoe"}}]`))
+				case r.Method == http.MethodPost && r.URL.Path == "/consumers/cf-mgmt/instances/i1/offsets":
+					committed <- string(body)
+					w.WriteHeader(http.StatusNoContent)
+				case r.Method == http.MethodDelete && r.URL.Path == "/consumers/cf-mgmt/instances/i1":
+					w.WriteHeader(http.StatusNoContent)
+					deleted <- true
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+			source, err := eventsync.NewSource(strings.Replace(server.URL, "http://", "kafka+http://", 1)+"/topics/hr-events", "cf-mgmt", false)
+			Expect(err).ShouldNot(HaveOccurred())
+			ctx, cancel := context.WithCancel(context.Background())
+			var received []eventsync.Message
+			err = source.Receive(ctx, func(message eventsync.Message) {
+				received = append(received, message)
+				cancel()
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(string(received[0].Data)).Should(Equal(`{"user":"jdoe"}`))
+			Eventually(deleted).Should(Receive())
+			Expect(committed).ShouldNot(Receive())
+			received[0].Commit()
+			Expect(committed).Should(Receive(Equal(`{"offsets":[{"topic":"hr-events","partition":2,"offset":41}]}`)))
+		})
+
+		It("receives the messages of a nats subject as a queue group", func() {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).ShouldNot(HaveOccurred())
+			defer listener.Close()
+			subscribed := make(chan string, 1)
+			go func() {
+				defer GinkgoRecover()
+				conn, err := listener.Accept()
+				Expect(err).ShouldNot(HaveOccurred())
+				defer conn.Close()
+				conn.Write([]byte("INFO {\"server_id\":\"test\"}\r\n"))
+				reader := bufio.NewReader(conn)
+				sub := ""
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					line = strings.TrimRight(line, "\r\n")
+					if strings.HasPrefix(line, "SUB ") {
+						sub = line
+						subscribed <- line
+					}
+					if line == "PING" {
+						// a message can arrive before the PONG of the subscription
+						if sub != "" {
+							conn.Write([]byte("MSG idp.users 1 15\r\n{\"user\":\"jdoe\"}\r\n"))
+						}
+						conn.Write([]byte("PONG\r\n"))
+					}
+				}
+			}()
+			source, err := eventsync.NewSource("nats://"+listener.Addr().String()+"/idp.users", "cf-mgmt", false)
+			Expect(err).ShouldNot(HaveOccurred())
+			ctx, cancel := context.WithCancel(context.Background())
+			var received []string
+			err = source.Receive(ctx, func(message eventsync.Message) {
+				received = append(received, string(message.Data))
+				cancel()
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(<-subscribed).Should(Equal("SUB idp.users cf-mgmt 1"))
+			Expect(received).Should(Equal([]string{`{"user":"jdoe"}`}))
+		})
+	})
+})
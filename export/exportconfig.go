@@ -2,6 +2,7 @@ package export
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/pivotalservices/cf-mgmt/config"
 	"github.com/pivotalservices/cf-mgmt/isosegment"
@@ -13,6 +14,7 @@ import (
 	"github.com/pivotalservices/cf-mgmt/user"
 	"github.com/xchapter7x/lo"
 
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
 	uaaclient "github.com/cloudfoundry-community/go-uaa"
 )
 
@@ -48,11 +50,22 @@ type DefaultImportManager struct {
 	SecurityGroupManager securitygroup.Manager
 	IsoSegmentManager    isosegment.Manager
 	PrivateDomainManager privatedomain.Manager
+	// ProtectedOrgs are the protected_orgs of the orgs.yml replaced by the
+	// export, which are excluded along with DefaultProtectedOrgs
+	ProtectedOrgs []string
 }
 
 //ExportConfig Imports org and space configuration from an existing CF instance
 //Entries part of excludedOrgs and excludedSpaces are not included in the import
 func (im *DefaultImportManager) ExportConfig(excludedOrgs map[string]string, excludedSpaces map[string]string) error {
+	return im.ExportConfigWithFilter(NewFilter(excludedOrgs, excludedSpaces))
+}
+
+//ExportConfigWithFilter Imports the org and space configuration of the orgs and spaces the filter includes
+func (im *DefaultImportManager) ExportConfigWithFilter(filter Filter) error {
+	if err := filter.Validate(); err != nil {
+		return err
+	}
 	//Get all the users from the foundation
 	userIDToUserMap, err := im.UAAMgr.ListUsers()
 	if err != nil {
@@ -106,6 +119,16 @@ func (im *DefaultImportManager) ExportConfig(excludedOrgs map[string]string, exc
 	if err != nil {
 		return err
 	}
+	if len(im.ProtectedOrgs) > 0 {
+		orgsConfig, err := configMgr.Orgs()
+		if err != nil {
+			return err
+		}
+		orgsConfig.ProtectedOrgs = im.ProtectedOrgs
+		if err := configMgr.SaveOrgs(orgsConfig); err != nil {
+			return err
+		}
+	}
 
 	globalConfig, err := configMgr.GetGlobalConfig()
 	if err != nil {
@@ -116,10 +139,14 @@ func (im *DefaultImportManager) ExportConfig(excludedOrgs map[string]string, exc
 
 	for _, org := range orgs {
 		orgName := org.Name
-		if _, ok := excludedOrgs[orgName]; ok {
+		if !filter.IncludesOrg(orgName) {
 			lo.G.Infof("Skipping org: %s as it is ignored from import", orgName)
 			continue
 		}
+		if config.IsProtectedOrg(orgName, im.ProtectedOrgs) {
+			lo.G.Infof("Skipping org: %s as it is protected", orgName)
+			continue
+		}
 
 		lo.G.Infof("Processing org: %s ", orgName)
 		orgConfig := &config.OrgConfig{Org: orgName}
@@ -144,6 +171,7 @@ func (im *DefaultImportManager) ExportConfig(excludedOrgs map[string]string, exc
 				orgConfig.TotalReservedRoutePorts = quota.TotalReservedRoutePorts
 				orgConfig.TotalServiceKeys = quota.TotalServiceKeys
 				orgConfig.AppInstanceLimit = quota.AppInstanceLimit
+				orgConfig.AppTaskLimit = quota.AppTaskLimit
 			}
 		}
 		if org.DefaultIsolationSegmentGuid != "" {
@@ -161,6 +189,7 @@ func (im *DefaultImportManager) ExportConfig(excludedOrgs map[string]string, exc
 		for privatedomain, _ := range privatedomains {
 			orgConfig.SharedPrivateDomains = append(orgConfig.SharedPrivateDomains, privatedomain)
 		}
+		sort.Strings(orgConfig.SharedPrivateDomains)
 
 		privatedomains, err = im.PrivateDomainManager.ListOrgOwnedPrivateDomains(org.Guid)
 		if err != nil {
@@ -169,6 +198,7 @@ func (im *DefaultImportManager) ExportConfig(excludedOrgs map[string]string, exc
 		for privatedomain, _ := range privatedomains {
 			orgConfig.PrivateDomains = append(orgConfig.PrivateDomains, privatedomain)
 		}
+		sort.Strings(orgConfig.PrivateDomains)
 		configMgr.AddOrgToConfig(orgConfig)
 
 		lo.G.Infof("Done creating org %s", orgConfig.Org)
@@ -177,7 +207,7 @@ func (im *DefaultImportManager) ExportConfig(excludedOrgs map[string]string, exc
 		lo.G.Infof("Found %d Spaces for org %s", len(spaces), orgConfig.Org)
 		for _, orgSpace := range spaces {
 			spaceName := orgSpace.Name
-			if _, ok := excludedSpaces[spaceName]; ok {
+			if !filter.IncludesSpace(spaceName) {
 				lo.G.Infof("Skipping space: %s as it is ignored from import", spaceName)
 				continue
 			}
@@ -204,6 +234,7 @@ func (im *DefaultImportManager) ExportConfig(excludedOrgs map[string]string, exc
 					spaceConfig.TotalReservedRoutePorts = quota.TotalReservedRoutePorts
 					spaceConfig.TotalServiceKeys = quota.TotalServiceKeys
 					spaceConfig.AppInstanceLimit = quota.AppInstanceLimit
+					spaceConfig.AppTaskLimit = quota.AppTaskLimit
 				}
 			}
 
@@ -227,6 +258,7 @@ func (im *DefaultImportManager) ExportConfig(excludedOrgs map[string]string, exc
 						spaceConfig.ASGs = append(spaceConfig.ASGs, securityGroupName)
 					}
 				}
+				sort.Strings(spaceConfig.ASGs)
 			}
 
 			configMgr.AddSpaceToConfig(spaceConfig)
@@ -241,7 +273,8 @@ func (im *DefaultImportManager) ExportConfig(excludedOrgs map[string]string, exc
 		}
 	}
 
-	for sgName, sgInfo := range securityGroups {
+	for _, sgName := range sortedKeys(securityGroups) {
+		sgInfo := securityGroups[sgName]
 		lo.G.Infof("Adding security group %s", sgName)
 		if rules, err := im.SecurityGroupManager.GetSecurityGroupRules(sgInfo.Guid); err == nil {
 			lo.G.Infof("Adding rules for %s", sgName)
@@ -251,7 +284,8 @@ func (im *DefaultImportManager) ExportConfig(excludedOrgs map[string]string, exc
 		}
 	}
 
-	for sgName, sgInfo := range defaultSecurityGroups {
+	for _, sgName := range sortedKeys(defaultSecurityGroups) {
+		sgInfo := defaultSecurityGroups[sgName]
 		lo.G.Infof("Adding default security group %s", sgName)
 		if sgInfo.Running {
 			globalConfig.RunningSecurityGroups = append(globalConfig.RunningSecurityGroups, sgName)
@@ -340,4 +374,16 @@ func doAddUsers(cfUsers map[string]string, uaaUsers *[]string, ldapUsers *[]stri
 			lo.G.Infof("CFUser [%s] not found in uaa user list", cfUser)
 		}
 	}
+	sort.Strings(*uaaUsers)
+	sort.Strings(*ldapUsers)
+	sort.Strings(*samlUsers)
+}
+
+func sortedKeys(securityGroups map[string]cfclient.SecGroup) []string {
+	names := make([]string, 0, len(securityGroups))
+	for name := range securityGroups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
@@ -0,0 +1,63 @@
+package export
+
+import (
+	"fmt"
+	"path"
+	"sort"
+)
+
+//Filter - selects the orgs and spaces to export, each entry a name or a glob such as p-*
+type Filter struct {
+	ExcludedOrgs   []string
+	ExcludedSpaces []string
+	// IncludeOnly, when set, limits the export to the matching orgs
+	IncludeOnly []string
+}
+
+//NewFilter - a filter excluding the orgs and spaces named by the keys of the maps
+func NewFilter(excludedOrgs map[string]string, excludedSpaces map[string]string) Filter {
+	return Filter{ExcludedOrgs: mapKeys(excludedOrgs), ExcludedSpaces: mapKeys(excludedSpaces)}
+}
+
+func mapKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+//Validate - fails on a malformed glob
+func (f Filter) Validate() error {
+	for _, patterns := range [][]string{f.ExcludedOrgs, f.ExcludedSpaces, f.IncludeOnly} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid org or space pattern %s: %s", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+//IncludesOrg - whether the org matches include-only, when set, and none of the excluded orgs
+func (f Filter) IncludesOrg(orgName string) bool {
+	if len(f.IncludeOnly) > 0 && !matchesAny(f.IncludeOnly, orgName) {
+		return false
+	}
+	return !matchesAny(f.ExcludedOrgs, orgName)
+}
+
+//IncludesSpace - whether the space matches none of the excluded spaces
+func (f Filter) IncludesSpace(spaceName string) bool {
+	return !matchesAny(f.ExcludedSpaces, spaceName)
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if match, _ := path.Match(pattern, name); match {
+			return true
+		}
+	}
+	return false
+}
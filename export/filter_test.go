@@ -0,0 +1,38 @@
+package export_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/export"
+)
+
+var _ = Describe("Filter", func() {
+	It("excludes orgs and spaces by name or glob", func() {
+		filter := export.Filter{ExcludedOrgs: []string{"p-*", "sandbox"}, ExcludedSpaces: []string{"scratch-*"}}
+		Expect(filter.Validate()).Should(Succeed())
+		Expect(filter.IncludesOrg("p-dataflow")).Should(BeFalse())
+		Expect(filter.IncludesOrg("sandbox")).Should(BeFalse())
+		Expect(filter.IncludesOrg("payments")).Should(BeTrue())
+		Expect(filter.IncludesSpace("scratch-alice")).Should(BeFalse())
+		Expect(filter.IncludesSpace("dev")).Should(BeTrue())
+	})
+
+	It("only includes the orgs matching include-only", func() {
+		filter := export.Filter{IncludeOnly: []string{"payments-*"}, ExcludedOrgs: []string{"payments-sandbox"}}
+		Expect(filter.IncludesOrg("payments-prod")).Should(BeTrue())
+		Expect(filter.IncludesOrg("payments-sandbox")).Should(BeFalse())
+		Expect(filter.IncludesOrg("billing")).Should(BeFalse())
+	})
+
+	It("excludes the orgs and spaces named by the maps", func() {
+		filter := export.NewFilter(map[string]string{"org2": "org2"}, map[string]string{"dev": "dev"})
+		Expect(filter.IncludesOrg("org2")).Should(BeFalse())
+		Expect(filter.IncludesOrg("org1")).Should(BeTrue())
+		Expect(filter.IncludesSpace("dev")).Should(BeFalse())
+	})
+
+	It("rejects a malformed glob", func() {
+		filter := export.Filter{ExcludedOrgs: []string{"p-["}}
+		Expect(filter.Validate()).Should(MatchError("invalid org or space pattern p-[: syntax error in pattern"))
+	})
+})
@@ -9,4 +9,5 @@ const SAML string = "saml"
 //Manager -
 type Manager interface {
 	ExportConfig(excludedOrgs map[string]string, excludedSpaces map[string]string) error
+	ExportConfigWithFilter(filter Filter) error
 }
@@ -92,7 +92,7 @@ func filesCfMgmtSh() (*asset, error) {
 	return a, nil
 }
 
-var _filesCfMgmtYml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\xcc\x4b\x6a\xc4\x30\x0c\x00\xd0\xbd\x4e\x21\xb2\xae\x9b\xbd\x2f\x53\x84\xab\x38\x22\xfe\x21\xc9\xa1\xa1\xf4\xee\x25\xc3\x30\x30\xeb\x07\x2f\x84\x00\xa3\x90\x6f\x5d\x6b\xc4\x22\x6d\xfe\x00\x48\xa5\xcc\x5f\xca\xd6\xa7\x26\x8e\x80\xe8\xd7\xe0\x88\xdf\x3d\x1d\xac\xe1\xc1\x80\xf8\x64\xfc\x55\x1e\xdd\xc4\xbb\x5e\x11\x87\x9c\xdd\xa9\x18\xeb\x29\x89\x6d\x4d\x5b\xa8\xb9\xfa\x07\x3a\xe5\x88\x4b\x21\x67\xf3\xe5\x0f\x40\xda\x98\x6e\x77\x1e\xb0\x51\xe5\x88\xa9\xb7\x4d\x72\xb8\x37\x00\x9d\xed\xb6\x41\xbe\xbf\xc9\x9a\x64\x75\xb2\xe3\x35\x7f\xda\x0e\xff\x01\x00\x00\xff\xff\x0a\x5a\x24\x71\xc6\x00\x00\x00")
+var _filesCfMgmtYml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x5d\x8e\x3d\x6b\x03\x31\x0c\x86\x77\xfd\x0a\x91\xb9\x3e\xef\xde\x3a\xa4\x50\x68\x96\x96\x0e\x9d\x82\x70\x7d\x17\x37\xe7\x0f\x24\x39\xf4\x28\xfd\xef\xf1\x85\x10\xd2\x4e\x02\x3d\xaf\x9e\x57\xc6\x18\xa8\x33\xe9\x58\x38\x39\x9c\x63\x6e\xdf\x00\x31\xd1\x14\xf6\x1c\xa4\x34\xf6\xc1\x01\xa2\x2e\x35\x38\xfc\x2c\xfe\x18\xd8\x5c\x70\x5f\x5e\x31\xfe\x70\xa8\x45\xa2\x16\x5e\x1c\xd6\x78\x2a\x4a\xb3\x04\x3e\x45\x1f\xc4\xfa\xd1\xa4\x29\xe9\x03\x2a\x4d\x0e\x37\xbd\x2a\x88\x6e\x7e\x7b\x49\xae\x4d\x65\x95\x1b\xcc\x94\xba\xc7\x97\x3c\xc6\xc9\xac\x36\x80\xd2\xf4\x3f\xe7\x96\x8d\xb4\x94\x88\x17\x80\x4a\x4c\xe9\x82\xdf\xde\x77\xbb\xc7\xd7\x8f\xfd\xd3\xf3\xcb\xd6\xe1\x30\xd8\xbb\x9c\xbd\xce\xe1\x4b\x4a\x06\xe8\x64\xbd\xa8\xa4\x87\x3f\x75\xd6\x47\xab\x24\xc7\xdb\xbb\x83\x1c\xe0\x0c\x41\xeb\x96\x32\x1b\x01\x00\x00")
 
 func filesCfMgmtYmlBytes() ([]byte, error) {
 	return bindataRead(
@@ -107,12 +107,12 @@ func filesCfMgmtYml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "files/cf-mgmt.yml", size: 198, mode: os.FileMode(420), modTime: time.Unix(1512503607, 0)}
+	info := bindataFileInfo{name: "files/cf-mgmt.yml", size: 283, mode: os.FileMode(420), modTime: time.Unix(1792176462, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _filesPipelineYml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x98\xcd\x6e\xdb\x30\x0c\xc7\xef\x7e\x0a\x3e\x40\xdd\x60\x87\x5d\x7c\x0b\x1a\xb7\x08\x90\x8f\x22\xc9\x36\x0c\x43\x21\xa8\x36\xe3\x6a\x93\x2d\x4f\x92\x3b\x04\x45\xdf\x7d\x70\xdc\xda\x89\xac\xa4\xae\xdd\xae\x40\xe6\x5b\x00\xd1\x24\xf5\xff\x81\xa4\x18\x89\x4a\x64\x32\x40\xe5\x39\x2e\x24\x34\x46\x0f\x02\x91\xac\x59\xe4\x4a\x4c\x85\x03\xa0\x37\x29\x7a\x10\x31\xed\x00\x14\xa6\x9e\x03\x00\x90\x49\xe6\xc1\xc3\x43\xc4\x34\xc9\x2d\x49\x26\xd9\xe3\xe3\xf6\xe4\x56\xd2\x24\xb8\xf3\x20\xa6\x4a\xa3\x2c\xdd\x7e\xfa\x1c\x97\xee\x34\x8b\xb1\xf2\x07\x0f\x2c\xd1\x28\xef\x29\xdf\x5a\x3d\x3a\xce\x4f\x71\xbb\x9b\x90\x44\xaa\xd1\x15\x32\x52\x0e\x40\xca\x69\x92\xa7\xe0\x42\x84\xda\xcc\x16\x40\x4b\x16\x45\x28\x3d\xd0\x32\xc3\xad\x99\xa6\xea\x97\xe9\x04\x60\xcd\xf8\xfe\x5d\x07\x01\x1b\xe4\xa6\x6a\x10\xac\xdd\x38\x8a\xf5\xf9\x26\xe6\x5b\xd3\x94\x4a\x1a\xab\xe2\xda\x00\xcb\xef\xcb\x95\x3f\x25\xa3\xf9\x74\x38\x9e\xe5\x12\xa8\x8d\xd2\x18\x93\x50\xc4\x94\x25\x4f\x1a\x00\x7c\x59\xfa\x0b\x32\x1e\xe5\x06\x99\x42\x49\x58\x58\x1e\x5d\x0f\x97\xcb\x6f\xf3\xc5\xf6\x2c\xa5\x4a\xfd\x11\xb2\x3a\xbc\x98\xcf\x2e\xc7\x57\x64\x34\x5e\x78\x70\x3e\x28\xf2\x7b\x3e\x9a\x8c\xfd\xd9\x8a\x2c\xfd\x8b\x85\xbf\xca\x3f\x0e\x38\xc3\x44\x13\x85\x81\x44\x5d\x7a\x98\xcc\xaf\xc8\xc4\xff\xea\x4f\x72\x13\x2e\x22\xc2\xf1\x1e\x79\x15\xe0\x92\x4c\xaf\xa6\x2b\x72\x31\x9f\x4e\x87\xb3\xd1\xbe\x32\xa6\xe6\x0a\x83\x4c\x32\xbd\x71\x23\x29\xb2\xb4\xbb\xfc\x75\x7f\x3d\x89\x1a\x09\x53\xa4\x12\x0a\x55\x8a\x45\x89\x1b\xe2\x9a\x66\x5c\x77\x87\x93\x0b\xaa\x14\x86\x1e\xfc\xb0\x87\xbe\xd9\x21\xf8\x62\xf0\x9e\x64\x45\xf2\x05\xb1\x4a\xa2\x21\x72\xec\xd0\xda\xb6\x66\x45\x63\x3d\x5c\x7a\xfb\x31\x7a\x4a\x15\xa5\x5d\x65\x2c\xd3\xc6\x4d\x25\xbb\xcf\x7f\x17\x17\x6c\x40\xc8\x2c\xa7\xdc\xf3\x4d\xc3\xb1\x64\x89\xd6\xb3\xb2\x4d\xa9\x9a\x50\x25\x3a\x75\x47\xe5\xdb\x91\x33\x9d\x1c\x07\x79\x2c\x76\xcf\xb1\xe2\x78\x58\xa7\xda\xdb\x23\xa5\x01\xb6\x2c\xba\x33\xc8\xd2\xf0\x99\x63\xae\x48\xb3\x32\x2c\x43\x9e\x34\xb1\xd1\xf0\x9a\xec\xc6\xe0\x21\x4d\x49\x2d\x50\xdb\xc7\x4b\x21\xa1\x39\xe1\xba\xc0\x3c\x84\xee\x35\xd3\xef\xff\x20\xdb\x6e\xfe\x99\xc8\x9e\x4a\xa7\x2d\xb2\xe2\xbb\xe3\xf5\x66\x86\xe8\xa9\x54\x54\xf6\xb5\xb1\x52\x29\x5a\xda\x5b\xa3\x69\x54\x4f\xd6\x34\x4e\x1a\xdf\xfb\xb4\x4b\x8b\x8e\x76\xd4\xbf\x33\xa1\xe9\x3f\x28\xc3\x2a\xd0\x49\xd3\xec\x82\xe9\x49\x21\x3b\xa7\x57\x6f\xe3\x76\x60\x67\x87\x37\xf1\x86\x1c\xfb\xcd\xbc\x31\xd0\x83\x7b\xb9\xf9\x7a\xfc\x80\x87\x8b\x25\x85\x93\xe6\xf8\xae\x6d\xb6\x52\xd1\x86\xb8\x6d\x8b\x7d\x79\xbd\xb7\x05\x39\x69\x8a\xed\xf1\x98\xcd\x95\x29\xc1\xa9\x66\x22\x71\x15\x46\x31\x26\xfa\x4d\xfe\xe0\x2c\x96\xc3\xda\x88\x6c\x58\x93\xd6\x9c\x3e\x94\x67\x43\x2e\x47\xc8\xbe\x16\x99\x45\x83\x6a\x73\xe7\x48\x93\x2c\x6d\xd5\x37\xcd\x4a\x3d\xab\xbf\x90\x3a\xf5\x53\x5b\x6e\x7d\x29\xee\xec\xef\x35\x7d\xfe\x06\x00\x00\xff\xff\xfb\x26\xbe\x10\x1c\x1c\x00\x00")
+var _filesPipelineYml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\x03\x6d\x90\xcd\x6e\x83\x30\x10\x84\xef\x3c\xc5\xbe\x00\x41\x3d\xf4\xc2\x0d\x05\x12\x21\xf1\x13\x01\x6d\xd5\x93\xe5\x10\x87\xba\xb5\xc1\xb2\x4d\x2a\x14\xe5\xdd\x6b\x43\x02\x69\xda\x9b\xed\xf9\x76\x67\x3c\x92\xa8\xae\x97\x35\x51\xbe\xe3\x42\x8b\x39\xf1\xa1\xee\xda\x23\x6d\x5c\x49\x44\xe7\x00\xe8\x41\x98\xb7\x86\x6a\x73\x9e\x50\xdf\x9c\x00\x7a\x49\x7d\x38\x9f\x8d\x80\x2c\x89\xcc\xfd\x72\x19\x95\xbd\xc4\x6d\xfd\xe1\x03\xc7\x4a\x13\x39\xaf\x7d\x7a\xe6\xf3\x3a\x4d\x39\x59\xf6\xc1\x99\xb6\x86\x3c\x61\x36\x52\x17\xc7\xf9\xec\xf6\x77\x81\xb0\x10\x6c\xb0\x38\x91\xd4\x32\x5a\xf6\x76\x5a\x30\xdc\xda\x2c\x2e\x34\x44\x3f\xc6\x36\x4e\x92\x36\x0d\x91\x33\x7e\xc5\xa6\x18\xff\xc9\x1a\xab\xaf\xc5\x0c\xe0\x48\xd9\xef\x36\xbc\x9a\x7a\x16\x52\x5e\x7d\x74\x79\xc3\xf5\x6a\xe0\x6c\x44\x05\x96\x98\xab\xa9\x18\x80\xf2\xbd\xac\xa2\x14\x85\x79\x1a\xc4\x99\x2d\x49\x0d\xa6\x09\x8e\x0e\x1d\xc7\xb4\xbd\xb6\x04\xf0\x52\x46\x05\x8a\x43\x0b\xf4\xe6\x6b\x88\x1e\x66\x69\x17\x94\xe5\x5b\x5e\x8c\x9a\xc0\x4a\x7d\x77\x72\x11\xd7\x79\xb6\x89\xb7\x28\x8c\x0b\x1f\x56\xde\x94\xef\x26\x25\x71\x94\x55\xa8\x8c\xd6\x45\x54\xd9\xe1\x9a\x51\xd2\x6a\xa4\x48\x2d\x89\x9e\x37\x24\x61\xb0\x43\xf7\x1e\xec\x80\x05\xfa\x63\x94\xe4\x5b\x94\x44\xaf\x51\x32\x22\x5d\x83\x18\x39\x11\xb6\xe4\xd8\xa0\x74\x9b\x56\x68\x9d\xa7\x69\x90\x85\xb7\xea\x7e\x00\xe3\x90\x67\x6c\x52\x02\x00\x00")
 
 func filesPipelineYmlBytes() ([]byte, error) {
 	return bindataRead(
@@ -127,7 +127,7 @@ func filesPipelineYml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "files/pipeline.yml", size: 7196, mode: os.FileMode(420), modTime: time.Unix(1539806489, 0)}
+	info := bindataFileInfo{name: "files/pipeline.yml", size: 594, mode: os.FileMode(420), modTime: time.Unix(1792194189, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -1,3 +1,14 @@
 package generated
 
+import (
+	"path"
+	"strings"
+)
+
 //go:generate go-bindata -pkg generated -o ./bindata.go files/
+
+//File - returns the embedded file of files/, the name may use either path separator so that
+//names built with filepath on windows load the same file
+func File(name string) ([]byte, error) {
+	return Asset(path.Join("files", strings.Replace(name, "\\", "/", -1)))
+}
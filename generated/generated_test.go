@@ -0,0 +1,32 @@
+package generated_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotalservices/cf-mgmt/generated"
+)
+
+var _ = Describe("embedded files", func() {
+	It("embeds every file of files/ as it is", func() {
+		files, err := ioutil.ReadDir("files")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(AssetNames()).Should(HaveLen(len(files)), "run go generate ./generated/ after adding or removing files")
+		for _, file := range files {
+			expected, err := ioutil.ReadFile(filepath.Join("files", file.Name()))
+			Expect(err).ShouldNot(HaveOccurred())
+			embedded, err := File(file.Name())
+			Expect(err).ShouldNot(HaveOccurred(), "run go generate ./generated/ after adding %s", file.Name())
+			Expect(string(embedded)).Should(Equal(string(expected)), "run go generate ./generated/ after changing %s", file.Name())
+		}
+	})
+
+	It("loads files named with either path separator", func() {
+		Expect(File("vars.yml")).ShouldNot(BeEmpty())
+		Expect(File(`.\vars.yml`)).ShouldNot(BeEmpty())
+		_, err := File("missing.yml")
+		Expect(err).Should(MatchError("Asset files/missing.yml not found"))
+	})
+})
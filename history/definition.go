@@ -0,0 +1,4 @@
+package history
+
+//go:generate counterfeiter -o fakes/fake_mgr.go types.go Manager
+//go:generate counterfeiter -o fakes/fake_annotator.go types.go Annotator
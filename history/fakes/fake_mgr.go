@@ -0,0 +1,113 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+
+	"github.com/pivotalservices/cf-mgmt/history"
+)
+
+type FakeManager struct {
+	RecordStub        func(run history.Run) error
+	recordMutex       sync.RWMutex
+	recordArgsForCall []struct {
+		run history.Run
+	}
+	recordReturns struct {
+		result1 error
+	}
+	LastStub        func() (*history.Run, *history.Run, error)
+	lastMutex       sync.RWMutex
+	lastArgsForCall []struct{}
+	lastReturns     struct {
+		result1 *history.Run
+		result2 *history.Run
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeManager) Record(run history.Run) error {
+	fake.recordMutex.Lock()
+	fake.recordArgsForCall = append(fake.recordArgsForCall, struct {
+		run history.Run
+	}{run})
+	fake.recordInvocation("Record", []interface{}{run})
+	fake.recordMutex.Unlock()
+	if fake.RecordStub != nil {
+		return fake.RecordStub(run)
+	} else {
+		return fake.recordReturns.result1
+	}
+}
+
+func (fake *FakeManager) RecordCallCount() int {
+	fake.recordMutex.RLock()
+	defer fake.recordMutex.RUnlock()
+	return len(fake.recordArgsForCall)
+}
+
+func (fake *FakeManager) RecordArgsForCall(i int) history.Run {
+	fake.recordMutex.RLock()
+	defer fake.recordMutex.RUnlock()
+	return fake.recordArgsForCall[i].run
+}
+
+func (fake *FakeManager) RecordReturns(result1 error) {
+	fake.RecordStub = nil
+	fake.recordReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) Last() (*history.Run, *history.Run, error) {
+	fake.lastMutex.Lock()
+	fake.lastArgsForCall = append(fake.lastArgsForCall, struct{}{})
+	fake.recordInvocation("Last", []interface{}{})
+	fake.lastMutex.Unlock()
+	if fake.LastStub != nil {
+		return fake.LastStub()
+	} else {
+		return fake.lastReturns.result1, fake.lastReturns.result2, fake.lastReturns.result3
+	}
+}
+
+func (fake *FakeManager) LastCallCount() int {
+	fake.lastMutex.RLock()
+	defer fake.lastMutex.RUnlock()
+	return len(fake.lastArgsForCall)
+}
+
+func (fake *FakeManager) LastReturns(result1 *history.Run, result2 *history.Run, result3 error) {
+	fake.LastStub = nil
+	fake.lastReturns = struct {
+		result1 *history.Run
+		result2 *history.Run
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeManager) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.recordMutex.RLock()
+	defer fake.recordMutex.RUnlock()
+	fake.lastMutex.RLock()
+	defer fake.lastMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeManager) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ history.Manager = new(FakeManager)
@@ -0,0 +1,121 @@
+// Package history keeps a record of the last cf-mgmt run, and the last
+// successful one, as metadata annotations of a designated org of the
+// foundation so operators can see when cf-mgmt last ran against a foundation
+// without access to the pipeline.
+package history
+
+import (
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xchapter7x/lo"
+)
+
+// Annotations of the org of run-history in cf-mgmt.yml holding the run records.
+const (
+	LastRunAnnotation        = "cf-mgmt.io/last-run"
+	LastSuccessfulAnnotation = "cf-mgmt.io/last-successful-run"
+)
+
+// resourceOrganizations is the org resource of the cloud controller v3 api
+const resourceOrganizations = "organizations"
+
+// StatusSucceeded is the status of a run that completed without error.
+const StatusSucceeded = "succeeded"
+
+// Run is the record of a cf-mgmt run.
+type Run struct {
+	Command   string    `json:"command"`
+	Status    string    `json:"status"`
+	Finished  time.Time `json:"finished"`
+	Seconds   int       `json:"seconds"`
+	Version   string    `json:"version,omitempty"`
+	ConfigSHA string    `json:"sha,omitempty"`
+	Changes   int       `json:"changes"`
+	Warnings  int       `json:"warnings"`
+	Errors    int       `json:"errors"`
+}
+
+//NewManager - records the runs as annotations of the org with the guid
+func NewManager(annotator Annotator, orgGUID string, peek bool) Manager {
+	return &DefaultManager{
+		Annotator: annotator,
+		OrgGUID:   orgGUID,
+		Peek:      peek,
+	}
+}
+
+// ConfigSHA returns the short git commit of the config directory, or an
+// empty string when it is not a git checkout.
+func ConfigSHA(configDir string) string {
+	cmd := exec.Command("git", "rev-parse", "--short=12", "HEAD")
+	cmd.Dir = configDir
+	output, err := cmd.Output()
+	if err != nil {
+		lo.G.Debugf("Unable to read git commit of %s: %s", configDir, err)
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+//DefaultManager -
+type DefaultManager struct {
+	Annotator Annotator
+	// OrgGUID is the guid of the org the runs are recorded on
+	OrgGUID string
+	Peek    bool
+}
+
+//Record - stores the run as the last run, and as the last successful run when it succeeded, updating the
+//annotations of the org in place with a single request
+func (m *DefaultManager) Record(run Run) error {
+	if m.Peek {
+		lo.G.Infof("[dry-run]: recording %s run of %s", run.Status, run.Command)
+		return nil
+	}
+	lo.G.Debugf("recording %s run of %s", run.Status, run.Command)
+	data, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	annotations := map[string]string{LastRunAnnotation: string(data)}
+	if run.Status == StatusSucceeded {
+		annotations[LastSuccessfulAnnotation] = string(data)
+	}
+	if err := m.Annotator.SetAnnotations(resourceOrganizations, m.OrgGUID, annotations); err != nil {
+		return errors.Wrap(err, "unable to write cf-mgmt run history")
+	}
+	return nil
+}
+
+//Last - returns the last run and the last successful run, nil when there is no record
+func (m *DefaultManager) Last() (*Run, *Run, error) {
+	annotations, err := m.Annotator.GetAnnotations(resourceOrganizations, m.OrgGUID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unable to read cf-mgmt run history")
+	}
+	last, err := parseRun(annotations, LastRunAnnotation)
+	if err != nil {
+		return nil, nil, err
+	}
+	lastSuccessful, err := parseRun(annotations, LastSuccessfulAnnotation)
+	if err != nil {
+		return nil, nil, err
+	}
+	return last, lastSuccessful, nil
+}
+
+func parseRun(annotations map[string]string, annotation string) (*Run, error) {
+	value, ok := annotations[annotation]
+	if !ok {
+		return nil, nil
+	}
+	run := &Run{}
+	if err := json.Unmarshal([]byte(value), run); err != nil {
+		return nil, errors.Wrapf(err, "unable to read cf-mgmt run history %s", annotation)
+	}
+	return run, nil
+}
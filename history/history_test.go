@@ -0,0 +1,106 @@
+package history_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/history"
+	"github.com/pivotalservices/cf-mgmt/history/fakes"
+)
+
+var _ = Describe("given history manager", func() {
+	var (
+		annotator *fakes.FakeAnnotator
+		manager   *history.DefaultManager
+		run       history.Run
+	)
+
+	BeforeEach(func() {
+		annotator = new(fakes.FakeAnnotator)
+		manager = &history.DefaultManager{Annotator: annotator, OrgGUID: "history-org-guid"}
+		run = history.Run{
+			Command:   "apply",
+			Status:    history.StatusSucceeded,
+			Finished:  time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC),
+			Seconds:   95,
+			Version:   "1.0.1",
+			ConfigSHA: "0123456789ab",
+			Changes:   3,
+		}
+	})
+
+	Context("Record", func() {
+		It("records a successful run as the last and last successful run", func() {
+			err := manager.Record(run)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(annotator.SetAnnotationsCallCount()).Should(Equal(1))
+			resource, guid, annotations := annotator.SetAnnotationsArgsForCall(0)
+			Expect(resource).Should(Equal("organizations"))
+			Expect(guid).Should(Equal("history-org-guid"))
+			Expect(annotations).Should(HaveLen(2))
+			Expect(annotations[history.LastRunAnnotation]).Should(MatchJSON(`{"command":"apply","status":"succeeded","finished":"2018-10-01T12:00:00Z","seconds":95,"version":"1.0.1","sha":"0123456789ab","changes":3,"warnings":0,"errors":0}`))
+			Expect(annotations[history.LastSuccessfulAnnotation]).Should(Equal(annotations[history.LastRunAnnotation]))
+		})
+
+		It("only records a failed run as the last run", func() {
+			run.Status = "failed"
+			err := manager.Record(run)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(annotator.SetAnnotationsCallCount()).Should(Equal(1))
+			_, _, annotations := annotator.SetAnnotationsArgsForCall(0)
+			Expect(annotations).Should(HaveLen(1))
+			Expect(annotations).Should(HaveKey(history.LastRunAnnotation))
+		})
+
+		It("errors when the record cannot be written", func() {
+			annotator.SetAnnotationsReturns(errors.New("cloud controller returned 403"))
+			err := manager.Record(run)
+			Expect(err).Should(MatchError("unable to write cf-mgmt run history: cloud controller returned 403"))
+		})
+
+		It("does not record in peek mode", func() {
+			manager.Peek = true
+			Expect(manager.Record(run)).ShouldNot(HaveOccurred())
+			Expect(annotator.SetAnnotationsCallCount()).Should(Equal(0))
+		})
+	})
+
+	Context("Last", func() {
+		It("returns the recorded runs", func() {
+			annotator.GetAnnotationsReturns(map[string]string{
+				history.LastRunAnnotation:        `{"command":"update-org-users","status":"failed","errors":1}`,
+				history.LastSuccessfulAnnotation: `{"command":"apply","status":"succeeded"}`,
+				"team":                           "platform",
+			}, nil)
+			last, lastSuccessful, err := manager.Last()
+			Expect(err).ShouldNot(HaveOccurred())
+			resource, guid := annotator.GetAnnotationsArgsForCall(0)
+			Expect(resource).Should(Equal("organizations"))
+			Expect(guid).Should(Equal("history-org-guid"))
+			Expect(last.Command).Should(Equal("update-org-users"))
+			Expect(last.Errors).Should(Equal(1))
+			Expect(lastSuccessful.Command).Should(Equal("apply"))
+		})
+
+		It("returns nil when nothing was recorded", func() {
+			last, lastSuccessful, err := manager.Last()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(last).Should(BeNil())
+			Expect(lastSuccessful).Should(BeNil())
+		})
+
+		It("errors when the record cannot be read", func() {
+			annotator.GetAnnotationsReturns(nil, errors.New("cloud controller returned 403"))
+			_, _, err := manager.Last()
+			Expect(err).Should(MatchError("unable to read cf-mgmt run history: cloud controller returned 403"))
+		})
+
+		It("errors when a record is not a run", func() {
+			annotator.GetAnnotationsReturns(map[string]string{history.LastRunAnnotation: "not json"}, nil)
+			_, _, err := manager.Last()
+			Expect(err).Should(MatchError(ContainSubstring("unable to read cf-mgmt run history cf-mgmt.io/last-run")))
+		})
+	})
+})
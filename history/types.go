@@ -0,0 +1,14 @@
+package history
+
+//Manager -
+type Manager interface {
+	Record(run Run) error
+	Last() (*Run, *Run, error)
+}
+
+//Annotator - reads and writes the metadata annotations of the org the runs are recorded on, setting only the
+//annotations given
+type Annotator interface {
+	GetAnnotations(resource, guid string) (map[string]string, error)
+	SetAnnotations(resource, guid string, annotations map[string]string) error
+}
@@ -0,0 +1,41 @@
+// Package hookexec runs the executables of plugins and command hooks, passing
+// them json on stdin and logging their output.
+package hookexec
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/xchapter7x/lo"
+)
+
+// Run runs the command built for ctx with input as json on stdin, logging each
+// line of its output prefixed with name, and stops it after timeout.
+func Run(ctx context.Context, name string, input interface{}, timeout time.Duration, command func(ctx context.Context) *exec.Cmd) error {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := command(ctx)
+	cmd.Stdin = bytes.NewReader(data)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	err = cmd.Run()
+	scanner := bufio.NewScanner(&output)
+	for scanner.Scan() {
+		lo.G.Infof("[%s] %s", name, scanner.Text())
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	return err
+}
@@ -0,0 +1,93 @@
+// Package httpclient provides the transport shared by every cloud controller
+// and uaa request, so that connections are pooled and reused across the tens
+// of thousands of calls of a large run instead of each client opening its own.
+package httpclient
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Defaults used for options that are not set.
+const (
+	DefaultMaxIdleConnsPerHost = 20
+	DefaultIdleConnTimeout     = 90 * time.Second
+)
+
+//Options - tunes the connection pool of the shared transport
+type Options struct {
+	// MaxIdleConnsPerHost is the number of connections kept open to each api
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout closes connections that have been idle this long
+	IdleConnTimeout time.Duration
+	// DisableKeepAlives opens a new connection for every request
+	DisableKeepAlives bool
+	// DisableHTTP2 keeps to HTTP/1.1 when an api supports HTTP/2
+	DisableHTTP2 bool
+}
+
+var (
+	mutex   sync.Mutex
+	shared  *http.Transport
+	options Options
+)
+
+//NewTransport - creates a transport with the options. Certificates are not
+//validated, as cf-mgmt has never validated them for the cloud controller and uaa.
+func NewTransport(options Options) *http.Transport {
+	if options.MaxIdleConnsPerHost <= 0 {
+		options.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	}
+	if options.IdleConnTimeout <= 0 {
+		options.IdleConnTimeout = DefaultIdleConnTimeout
+	}
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSClientConfig:       &tls.Config{InsecureSkipVerify: true},
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		MaxIdleConns:          options.MaxIdleConnsPerHost * 4,
+		MaxIdleConnsPerHost:   options.MaxIdleConnsPerHost,
+		IdleConnTimeout:       options.IdleConnTimeout,
+		DisableKeepAlives:     options.DisableKeepAlives,
+		// a custom tls config disables HTTP/2 unless it is asked for
+		ForceAttemptHTTP2: !options.DisableHTTP2,
+	}
+	if options.DisableHTTP2 {
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return transport
+}
+
+//Configure - replaces the shared transport with one created with the options,
+//unless it already has them so its open connections are kept
+func Configure(newOptions Options) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if shared != nil {
+		if newOptions == options {
+			return
+		}
+		shared.CloseIdleConnections()
+	}
+	options = newOptions
+	shared = NewTransport(options)
+}
+
+//Transport - returns the shared transport, created with the default options
+//when it has not been configured
+func Transport() *http.Transport {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if shared == nil {
+		shared = NewTransport(options)
+	}
+	return shared
+}
@@ -0,0 +1,45 @@
+package httpclient_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/httpclient"
+)
+
+var _ = Describe("given httpclient", func() {
+	It("pools connections by default", func() {
+		transport := httpclient.NewTransport(httpclient.Options{})
+		Expect(transport.MaxIdleConnsPerHost).Should(Equal(httpclient.DefaultMaxIdleConnsPerHost))
+		Expect(transport.IdleConnTimeout).Should(Equal(httpclient.DefaultIdleConnTimeout))
+		Expect(transport.DisableKeepAlives).Should(BeFalse())
+		Expect(transport.ForceAttemptHTTP2).Should(BeTrue())
+		Expect(transport.TLSClientConfig.InsecureSkipVerify).Should(BeTrue())
+	})
+
+	It("applies the options", func() {
+		transport := httpclient.NewTransport(httpclient.Options{
+			MaxIdleConnsPerHost: 50,
+			IdleConnTimeout:     30 * time.Second,
+			DisableKeepAlives:   true,
+			DisableHTTP2:        true,
+		})
+		Expect(transport.MaxIdleConnsPerHost).Should(Equal(50))
+		Expect(transport.IdleConnTimeout).Should(Equal(30 * time.Second))
+		Expect(transport.DisableKeepAlives).Should(BeTrue())
+		Expect(transport.ForceAttemptHTTP2).Should(BeFalse())
+		Expect(transport.TLSNextProto).ShouldNot(BeNil())
+	})
+
+	It("shares one transport until the options change", func() {
+		httpclient.Configure(httpclient.Options{MaxIdleConnsPerHost: 10})
+		transport := httpclient.Transport()
+		Expect(transport.MaxIdleConnsPerHost).Should(Equal(10))
+		httpclient.Configure(httpclient.Options{MaxIdleConnsPerHost: 10})
+		Expect(httpclient.Transport()).Should(BeIdenticalTo(transport))
+		httpclient.Configure(httpclient.Options{MaxIdleConnsPerHost: 30})
+		Expect(httpclient.Transport()).ShouldNot(BeIdenticalTo(transport))
+		Expect(httpclient.Transport().MaxIdleConnsPerHost).Should(Equal(30))
+	})
+})
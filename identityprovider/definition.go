@@ -0,0 +1,3 @@
+package identityprovider
+
+//go:generate counterfeiter -o fakes/fake_mgr.go types.go Manager
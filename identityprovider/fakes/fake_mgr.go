@@ -0,0 +1,66 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+
+	"github.com/pivotalservices/cf-mgmt/identityprovider"
+)
+
+type FakeManager struct {
+	UpdateIdentityProvidersStub        func() error
+	updateIdentityProvidersMutex       sync.RWMutex
+	updateIdentityProvidersArgsForCall []struct{}
+	updateIdentityProvidersReturns     struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeManager) UpdateIdentityProviders() error {
+	fake.updateIdentityProvidersMutex.Lock()
+	fake.updateIdentityProvidersArgsForCall = append(fake.updateIdentityProvidersArgsForCall, struct{}{})
+	fake.recordInvocation("UpdateIdentityProviders", []interface{}{})
+	fake.updateIdentityProvidersMutex.Unlock()
+	if fake.UpdateIdentityProvidersStub != nil {
+		return fake.UpdateIdentityProvidersStub()
+	} else {
+		return fake.updateIdentityProvidersReturns.result1
+	}
+}
+
+func (fake *FakeManager) UpdateIdentityProvidersCallCount() int {
+	fake.updateIdentityProvidersMutex.RLock()
+	defer fake.updateIdentityProvidersMutex.RUnlock()
+	return len(fake.updateIdentityProvidersArgsForCall)
+}
+
+func (fake *FakeManager) UpdateIdentityProvidersReturns(result1 error) {
+	fake.UpdateIdentityProvidersStub = nil
+	fake.updateIdentityProvidersReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.updateIdentityProvidersMutex.RLock()
+	defer fake.updateIdentityProvidersMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeManager) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ identityprovider.Manager = new(FakeManager)
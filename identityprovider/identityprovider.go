@@ -0,0 +1,180 @@
+package identityprovider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/redact"
+	"github.com/pivotalservices/cf-mgmt/uaa"
+	"github.com/xchapter7x/lo"
+)
+
+func NewManager(uaaMgr uaa.Manager, cfg config.Reader) Manager {
+	return &DefaultManager{
+		Cfg:    cfg,
+		UAAMgr: uaaMgr,
+	}
+}
+
+//DefaultManager -
+type DefaultManager struct {
+	Cfg    config.Reader
+	UAAMgr uaa.Manager
+}
+
+// secretKey is the config key of the oidc client secret, which uaa never
+// returns and so is not compared
+const secretKey = "relyingPartySecret"
+
+//UpdateIdentityProviders - creates and updates the saml and oidc identity providers of identity-providers.yml,
+//deleting the providers that are not listed when delete-unmanaged is set
+func (m *DefaultManager) UpdateIdentityProviders() error {
+	configured, err := m.Cfg.GetIdentityProviders()
+	if err != nil {
+		return err
+	}
+	if configured == nil {
+		lo.G.Debug("No identity-providers.yml, identity providers are not managed")
+		return nil
+	}
+	providers, err := m.UAAMgr.ListIdentityProviders()
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]uaa.IdentityProvider)
+	for _, provider := range providers {
+		existing[provider.OriginKey] = provider
+	}
+	managed := make(map[string]bool)
+	for _, providerConfig := range configured.Providers {
+		managed[providerConfig.Origin] = true
+		desired, err := identityProvider(providerConfig)
+		if err != nil {
+			return err
+		}
+		current, ok := existing[providerConfig.Origin]
+		if !ok {
+			if err := m.UAAMgr.CreateIdentityProvider(desired); err != nil {
+				return err
+			}
+			continue
+		}
+		if current.Type != desired.Type {
+			return fmt.Errorf("identity provider [%s] is of type %s, not %s, delete it to change its type", current.OriginKey, current.Type, desired.Type)
+		}
+		if !changed(current, desired) {
+			lo.G.Debugf("Identity provider [%s] is up to date", current.OriginKey)
+			continue
+		}
+		// keys cf-mgmt does not manage, such as the link text of the login page, are kept
+		updated := current
+		updated.Name = desired.Name
+		updated.Active = desired.Active
+		updated.Config = make(map[string]interface{})
+		for key, value := range current.Config {
+			updated.Config[key] = value
+		}
+		for key, value := range desired.Config {
+			updated.Config[key] = value
+		}
+		if err := m.UAAMgr.UpdateIdentityProvider(updated); err != nil {
+			return err
+		}
+	}
+	if !configured.DeleteUnmanaged {
+		return nil
+	}
+	var unmanaged []uaa.IdentityProvider
+	var changes []config.Change
+	for _, provider := range providers {
+		if managed[provider.OriginKey] || (provider.Type != config.IdentityProviderSAML && provider.Type != config.IdentityProviderOIDC) {
+			continue
+		}
+		unmanaged = append(unmanaged, provider)
+		changes = append(changes, config.Change{Kind: config.ChangeDeleteIdentityProvider, Origin: provider.OriginKey})
+	}
+	// deleting a provider deletes the uaa users of its origin with it
+	approvals, err := m.Cfg.GetApprovals()
+	if err != nil {
+		return err
+	}
+	if err := approvals.Check(changes...); err != nil {
+		return err
+	}
+	for _, provider := range unmanaged {
+		if err := m.UAAMgr.DeleteIdentityProvider(provider); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// identityProvider is the uaa identity provider of the configuration
+func identityProvider(providerConfig config.IdentityProvider) (uaa.IdentityProvider, error) {
+	name := providerConfig.Name
+	if name == "" {
+		name = providerConfig.Origin
+	}
+	provider := uaa.IdentityProvider{
+		OriginKey: providerConfig.Origin,
+		Name:      name,
+		Type:      providerConfig.Type,
+		Active:    !providerConfig.Inactive,
+		Config: map[string]interface{}{
+			"emailDomain":       providerConfig.EmailDomains,
+			"attributeMappings": providerConfig.AttributeMappings,
+		},
+	}
+	if providerConfig.Type == config.IdentityProviderSAML {
+		provider.Config["metaDataLocation"] = providerConfig.Metadata
+		if providerConfig.NameID != "" {
+			provider.Config["nameID"] = providerConfig.NameID
+		}
+		return provider, nil
+	}
+	provider.Config["discoveryUrl"] = providerConfig.DiscoveryURL
+	provider.Config["relyingPartyId"] = providerConfig.ClientID
+	provider.Config["scopes"] = providerConfig.Scopes
+	if providerConfig.ClientSecretEnv != "" {
+		secret := os.Getenv(providerConfig.ClientSecretEnv)
+		if secret == "" {
+			return provider, fmt.Errorf("client secret of identity provider [%s] is not set, %s is empty", providerConfig.Origin, providerConfig.ClientSecretEnv)
+		}
+		redact.Secrets(secret)
+		provider.Config[secretKey] = secret
+	}
+	return provider, nil
+}
+
+// changed is true when the name, active flag or a configured key of the
+// provider differ from the desired ones
+func changed(current, desired uaa.IdentityProvider) bool {
+	if current.Name != desired.Name || current.Active != desired.Active {
+		return true
+	}
+	for key, value := range desired.Config {
+		if key == secretKey {
+			continue
+		}
+		if normalized(value) != normalized(current.Config[key]) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalized is the json of a config value, with empty lists and maps the
+// same as no value
+func normalized(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	switch string(data) {
+	case "[]", "{}", `""`:
+		return "null"
+	}
+	return string(data)
+}
@@ -0,0 +1,126 @@
+package identityprovider_test
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/config"
+	configfakes "github.com/pivotalservices/cf-mgmt/config/fakes"
+	. "github.com/pivotalservices/cf-mgmt/identityprovider"
+	"github.com/pivotalservices/cf-mgmt/uaa"
+	uaafakes "github.com/pivotalservices/cf-mgmt/uaa/fakes"
+)
+
+var _ = Describe("given identity provider manager", func() {
+	var (
+		fakeReader *configfakes.FakeReader
+		fakeUaa    *uaafakes.FakeManager
+		manager    DefaultManager
+		okta       config.IdentityProvider
+	)
+
+	BeforeEach(func() {
+		fakeReader = new(configfakes.FakeReader)
+		fakeUaa = new(uaafakes.FakeManager)
+		manager = DefaultManager{Cfg: fakeReader, UAAMgr: fakeUaa}
+		okta = config.IdentityProvider{
+			Origin:            "okta",
+			Name:              "Okta",
+			Type:              config.IdentityProviderSAML,
+			Metadata:          "https://okta/metadata",
+			EmailDomains:      []string{"example.com"},
+			AttributeMappings: map[string]string{"email": "emailAddress"},
+		}
+		fakeReader.GetIdentityProvidersReturns(&config.IdentityProviders{Providers: []config.IdentityProvider{okta}}, nil)
+	})
+
+	Context("UpdateIdentityProviders()", func() {
+		It("does nothing without identity-providers.yml", func() {
+			fakeReader.GetIdentityProvidersReturns(nil, nil)
+			Expect(manager.UpdateIdentityProviders()).Should(Succeed())
+			Expect(fakeUaa.ListIdentityProvidersCallCount()).Should(Equal(0))
+		})
+
+		It("creates a missing provider", func() {
+			fakeUaa.ListIdentityProvidersReturns([]uaa.IdentityProvider{{OriginKey: "uaa", Type: "uaa", Active: true}}, nil)
+			Expect(manager.UpdateIdentityProviders()).Should(Succeed())
+			Expect(fakeUaa.CreateIdentityProviderCallCount()).Should(Equal(1))
+			provider := fakeUaa.CreateIdentityProviderArgsForCall(0)
+			Expect(provider.OriginKey).Should(Equal("okta"))
+			Expect(provider.Active).Should(BeTrue())
+			Expect(provider.Config["metaDataLocation"]).Should(Equal("https://okta/metadata"))
+			Expect(provider.Config["emailDomain"]).Should(Equal([]string{"example.com"}))
+		})
+
+		It("leaves an up to date provider alone", func() {
+			fakeUaa.ListIdentityProvidersReturns([]uaa.IdentityProvider{{ID: "okta-id", OriginKey: "okta", Name: "Okta", Type: "saml", Active: true, Config: map[string]interface{}{
+				"metaDataLocation":  "https://okta/metadata",
+				"emailDomain":       []interface{}{"example.com"},
+				"attributeMappings": map[string]interface{}{"email": "emailAddress"},
+				"linkText":          "Okta",
+			}}}, nil)
+			Expect(manager.UpdateIdentityProviders()).Should(Succeed())
+			Expect(fakeUaa.CreateIdentityProviderCallCount()).Should(Equal(0))
+			Expect(fakeUaa.UpdateIdentityProviderCallCount()).Should(Equal(0))
+		})
+
+		It("updates a changed provider keeping unmanaged settings", func() {
+			fakeUaa.ListIdentityProvidersReturns([]uaa.IdentityProvider{{ID: "okta-id", OriginKey: "okta", Name: "Okta", Type: "saml", Active: true, Config: map[string]interface{}{
+				"metaDataLocation": "https://old/metadata",
+				"linkText":         "Okta",
+			}}}, nil)
+			Expect(manager.UpdateIdentityProviders()).Should(Succeed())
+			Expect(fakeUaa.UpdateIdentityProviderCallCount()).Should(Equal(1))
+			provider := fakeUaa.UpdateIdentityProviderArgsForCall(0)
+			Expect(provider.ID).Should(Equal("okta-id"))
+			Expect(provider.Config["metaDataLocation"]).Should(Equal("https://okta/metadata"))
+			Expect(provider.Config["linkText"]).Should(Equal("Okta"))
+		})
+
+		It("does not change the type of a provider", func() {
+			fakeUaa.ListIdentityProvidersReturns([]uaa.IdentityProvider{{ID: "okta-id", OriginKey: "okta", Type: "oidc1.0"}}, nil)
+			err := manager.UpdateIdentityProviders()
+			Expect(err).Should(MatchError("identity provider [okta] is of type oidc1.0, not saml, delete it to change its type"))
+		})
+
+		It("reads the oidc client secret from the environment", func() {
+			os.Setenv("TEST_CLIENT_SECRET", "client-secret")
+			defer os.Unsetenv("TEST_CLIENT_SECRET")
+			fakeReader.GetIdentityProvidersReturns(&config.IdentityProviders{Providers: []config.IdentityProvider{{
+				Origin:          "azure",
+				Type:            config.IdentityProviderOIDC,
+				DiscoveryURL:    "https://azure/.well-known/openid-configuration",
+				ClientID:        "cf",
+				ClientSecretEnv: "TEST_CLIENT_SECRET",
+			}}}, nil)
+			Expect(manager.UpdateIdentityProviders()).Should(Succeed())
+			provider := fakeUaa.CreateIdentityProviderArgsForCall(0)
+			Expect(provider.Name).Should(Equal("azure"))
+			Expect(provider.Config["relyingPartyId"]).Should(Equal("cf"))
+			Expect(provider.Config["relyingPartySecret"]).Should(Equal("client-secret"))
+		})
+
+		It("deletes unmanaged saml and oidc providers when enabled", func() {
+			fakeReader.GetIdentityProvidersReturns(&config.IdentityProviders{DeleteUnmanaged: true}, nil)
+			fakeUaa.ListIdentityProvidersReturns([]uaa.IdentityProvider{
+				{OriginKey: "uaa", Type: "uaa"},
+				{OriginKey: "ldap", Type: "ldap"},
+				{ID: "old-id", OriginKey: "old-saml", Type: "saml"},
+			}, nil)
+			Expect(manager.UpdateIdentityProviders()).Should(Succeed())
+			Expect(fakeUaa.DeleteIdentityProviderCallCount()).Should(Equal(1))
+			Expect(fakeUaa.DeleteIdentityProviderArgsForCall(0).OriginKey).Should(Equal("old-saml"))
+		})
+
+		It("does not delete unmanaged providers without an approval", func() {
+			fakeReader.GetIdentityProvidersReturns(&config.IdentityProviders{DeleteUnmanaged: true}, nil)
+			fakeReader.GetApprovalsReturns(&config.Approvals{RequireApprovals: true}, nil)
+			fakeUaa.ListIdentityProvidersReturns([]uaa.IdentityProvider{
+				{ID: "old-id", OriginKey: "old-saml", Type: "saml"},
+			}, nil)
+			Expect(manager.UpdateIdentityProviders()).Should(MatchError("approvals.yml has no approval for: delete-identity-provider old-saml"))
+			Expect(fakeUaa.DeleteIdentityProviderCallCount()).Should(Equal(0))
+		})
+	})
+})
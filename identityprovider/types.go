@@ -0,0 +1,6 @@
+package identityprovider
+
+//Manager -
+type Manager interface {
+	UpdateIdentityProviders() error
+}
@@ -1,6 +1,8 @@
 package isosegment
 
 import (
+	"sort"
+
 	cfclient "github.com/cloudfoundry-community/go-cfclient"
 )
 
@@ -30,6 +32,7 @@ func classify(desired []cfclient.IsolationSegment, current []cfclient.IsolationS
 			missing = append(missing, *seg)
 		}
 	}
+	sortByName(missing)
 
 	var extra []cfclient.IsolationSegment
 	for name, seg := range currentSegments {
@@ -37,6 +40,11 @@ func classify(desired []cfclient.IsolationSegment, current []cfclient.IsolationS
 			extra = append(extra, *seg)
 		}
 	}
+	sortByName(extra)
 
 	return classification{missing, extra}
 }
+
+func sortByName(segments []cfclient.IsolationSegment) {
+	sort.Slice(segments, func(i, j int) bool { return segments[i].Name < segments[j].Name })
+}
@@ -141,6 +141,15 @@ type FakeCFClient struct {
 	resetIsolationSegmentForSpaceReturns struct {
 		result1 error
 	}
+	ListAppsByQueryStub        func(query url.Values) ([]go_cfclient.App, error)
+	listAppsByQueryMutex       sync.RWMutex
+	listAppsByQueryArgsForCall []struct {
+		query url.Values
+	}
+	listAppsByQueryReturns struct {
+		result1 []go_cfclient.App
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -645,6 +654,40 @@ func (fake *FakeCFClient) ResetIsolationSegmentForSpaceReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeCFClient) ListAppsByQuery(query url.Values) ([]go_cfclient.App, error) {
+	fake.listAppsByQueryMutex.Lock()
+	fake.listAppsByQueryArgsForCall = append(fake.listAppsByQueryArgsForCall, struct {
+		query url.Values
+	}{query})
+	fake.recordInvocation("ListAppsByQuery", []interface{}{query})
+	fake.listAppsByQueryMutex.Unlock()
+	if fake.ListAppsByQueryStub != nil {
+		return fake.ListAppsByQueryStub(query)
+	} else {
+		return fake.listAppsByQueryReturns.result1, fake.listAppsByQueryReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) ListAppsByQueryCallCount() int {
+	fake.listAppsByQueryMutex.RLock()
+	defer fake.listAppsByQueryMutex.RUnlock()
+	return len(fake.listAppsByQueryArgsForCall)
+}
+
+func (fake *FakeCFClient) ListAppsByQueryArgsForCall(i int) url.Values {
+	fake.listAppsByQueryMutex.RLock()
+	defer fake.listAppsByQueryMutex.RUnlock()
+	return fake.listAppsByQueryArgsForCall[i].query
+}
+
+func (fake *FakeCFClient) ListAppsByQueryReturns(result1 []go_cfclient.App, result2 error) {
+	fake.ListAppsByQueryStub = nil
+	fake.listAppsByQueryReturns = struct {
+		result1 []go_cfclient.App
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeCFClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -678,6 +721,8 @@ func (fake *FakeCFClient) Invocations() map[string][][]interface{} {
 	defer fake.isolationSegmentForSpaceMutex.RUnlock()
 	fake.resetIsolationSegmentForSpaceMutex.RLock()
 	defer fake.resetIsolationSegmentForSpaceMutex.RUnlock()
+	fake.listAppsByQueryMutex.RLock()
+	defer fake.listAppsByQueryMutex.RUnlock()
 	return fake.invocations
 }
 
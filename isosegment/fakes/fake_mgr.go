@@ -0,0 +1,345 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+
+	go_cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pivotalservices/cf-mgmt/isosegment"
+)
+
+type FakeManager struct {
+	ApplyStub        func() error
+	applyMutex       sync.RWMutex
+	applyArgsForCall []struct{}
+	applyReturns     struct {
+		result1 error
+	}
+	CreateStub        func() error
+	createMutex       sync.RWMutex
+	createArgsForCall []struct{}
+	createReturns     struct {
+		result1 error
+	}
+	RemoveStub        func() error
+	removeMutex       sync.RWMutex
+	removeArgsForCall []struct{}
+	removeReturns     struct {
+		result1 error
+	}
+	EntitleStub        func() error
+	entitleMutex       sync.RWMutex
+	entitleArgsForCall []struct{}
+	entitleReturns     struct {
+		result1 error
+	}
+	UnentitleStub        func() error
+	unentitleMutex       sync.RWMutex
+	unentitleArgsForCall []struct{}
+	unentitleReturns     struct {
+		result1 error
+	}
+	UpdateOrgsStub        func() error
+	updateOrgsMutex       sync.RWMutex
+	updateOrgsArgsForCall []struct{}
+	updateOrgsReturns     struct {
+		result1 error
+	}
+	UpdateSpacesStub        func() error
+	updateSpacesMutex       sync.RWMutex
+	updateSpacesArgsForCall []struct{}
+	updateSpacesReturns     struct {
+		result1 error
+	}
+	ListIsolationSegmentsStub        func() ([]go_cfclient.IsolationSegment, error)
+	listIsolationSegmentsMutex       sync.RWMutex
+	listIsolationSegmentsArgsForCall []struct{}
+	listIsolationSegmentsReturns     struct {
+		result1 []go_cfclient.IsolationSegment
+		result2 error
+	}
+	PlacementReportStub        func(cellCapacity map[string]int) ([]isosegment.SegmentPlacement, error)
+	placementReportMutex       sync.RWMutex
+	placementReportArgsForCall []struct {
+		cellCapacity map[string]int
+	}
+	placementReportReturns struct {
+		result1 []isosegment.SegmentPlacement
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeManager) Apply() error {
+	fake.applyMutex.Lock()
+	fake.applyArgsForCall = append(fake.applyArgsForCall, struct{}{})
+	fake.recordInvocation("Apply", []interface{}{})
+	fake.applyMutex.Unlock()
+	if fake.ApplyStub != nil {
+		return fake.ApplyStub()
+	} else {
+		return fake.applyReturns.result1
+	}
+}
+
+func (fake *FakeManager) ApplyCallCount() int {
+	fake.applyMutex.RLock()
+	defer fake.applyMutex.RUnlock()
+	return len(fake.applyArgsForCall)
+}
+
+func (fake *FakeManager) ApplyReturns(result1 error) {
+	fake.ApplyStub = nil
+	fake.applyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) Create() error {
+	fake.createMutex.Lock()
+	fake.createArgsForCall = append(fake.createArgsForCall, struct{}{})
+	fake.recordInvocation("Create", []interface{}{})
+	fake.createMutex.Unlock()
+	if fake.CreateStub != nil {
+		return fake.CreateStub()
+	} else {
+		return fake.createReturns.result1
+	}
+}
+
+func (fake *FakeManager) CreateCallCount() int {
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	return len(fake.createArgsForCall)
+}
+
+func (fake *FakeManager) CreateReturns(result1 error) {
+	fake.CreateStub = nil
+	fake.createReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) Remove() error {
+	fake.removeMutex.Lock()
+	fake.removeArgsForCall = append(fake.removeArgsForCall, struct{}{})
+	fake.recordInvocation("Remove", []interface{}{})
+	fake.removeMutex.Unlock()
+	if fake.RemoveStub != nil {
+		return fake.RemoveStub()
+	} else {
+		return fake.removeReturns.result1
+	}
+}
+
+func (fake *FakeManager) RemoveCallCount() int {
+	fake.removeMutex.RLock()
+	defer fake.removeMutex.RUnlock()
+	return len(fake.removeArgsForCall)
+}
+
+func (fake *FakeManager) RemoveReturns(result1 error) {
+	fake.RemoveStub = nil
+	fake.removeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) Entitle() error {
+	fake.entitleMutex.Lock()
+	fake.entitleArgsForCall = append(fake.entitleArgsForCall, struct{}{})
+	fake.recordInvocation("Entitle", []interface{}{})
+	fake.entitleMutex.Unlock()
+	if fake.EntitleStub != nil {
+		return fake.EntitleStub()
+	} else {
+		return fake.entitleReturns.result1
+	}
+}
+
+func (fake *FakeManager) EntitleCallCount() int {
+	fake.entitleMutex.RLock()
+	defer fake.entitleMutex.RUnlock()
+	return len(fake.entitleArgsForCall)
+}
+
+func (fake *FakeManager) EntitleReturns(result1 error) {
+	fake.EntitleStub = nil
+	fake.entitleReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) Unentitle() error {
+	fake.unentitleMutex.Lock()
+	fake.unentitleArgsForCall = append(fake.unentitleArgsForCall, struct{}{})
+	fake.recordInvocation("Unentitle", []interface{}{})
+	fake.unentitleMutex.Unlock()
+	if fake.UnentitleStub != nil {
+		return fake.UnentitleStub()
+	} else {
+		return fake.unentitleReturns.result1
+	}
+}
+
+func (fake *FakeManager) UnentitleCallCount() int {
+	fake.unentitleMutex.RLock()
+	defer fake.unentitleMutex.RUnlock()
+	return len(fake.unentitleArgsForCall)
+}
+
+func (fake *FakeManager) UnentitleReturns(result1 error) {
+	fake.UnentitleStub = nil
+	fake.unentitleReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) UpdateOrgs() error {
+	fake.updateOrgsMutex.Lock()
+	fake.updateOrgsArgsForCall = append(fake.updateOrgsArgsForCall, struct{}{})
+	fake.recordInvocation("UpdateOrgs", []interface{}{})
+	fake.updateOrgsMutex.Unlock()
+	if fake.UpdateOrgsStub != nil {
+		return fake.UpdateOrgsStub()
+	} else {
+		return fake.updateOrgsReturns.result1
+	}
+}
+
+func (fake *FakeManager) UpdateOrgsCallCount() int {
+	fake.updateOrgsMutex.RLock()
+	defer fake.updateOrgsMutex.RUnlock()
+	return len(fake.updateOrgsArgsForCall)
+}
+
+func (fake *FakeManager) UpdateOrgsReturns(result1 error) {
+	fake.UpdateOrgsStub = nil
+	fake.updateOrgsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) UpdateSpaces() error {
+	fake.updateSpacesMutex.Lock()
+	fake.updateSpacesArgsForCall = append(fake.updateSpacesArgsForCall, struct{}{})
+	fake.recordInvocation("UpdateSpaces", []interface{}{})
+	fake.updateSpacesMutex.Unlock()
+	if fake.UpdateSpacesStub != nil {
+		return fake.UpdateSpacesStub()
+	} else {
+		return fake.updateSpacesReturns.result1
+	}
+}
+
+func (fake *FakeManager) UpdateSpacesCallCount() int {
+	fake.updateSpacesMutex.RLock()
+	defer fake.updateSpacesMutex.RUnlock()
+	return len(fake.updateSpacesArgsForCall)
+}
+
+func (fake *FakeManager) UpdateSpacesReturns(result1 error) {
+	fake.UpdateSpacesStub = nil
+	fake.updateSpacesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) ListIsolationSegments() ([]go_cfclient.IsolationSegment, error) {
+	fake.listIsolationSegmentsMutex.Lock()
+	fake.listIsolationSegmentsArgsForCall = append(fake.listIsolationSegmentsArgsForCall, struct{}{})
+	fake.recordInvocation("ListIsolationSegments", []interface{}{})
+	fake.listIsolationSegmentsMutex.Unlock()
+	if fake.ListIsolationSegmentsStub != nil {
+		return fake.ListIsolationSegmentsStub()
+	} else {
+		return fake.listIsolationSegmentsReturns.result1, fake.listIsolationSegmentsReturns.result2
+	}
+}
+
+func (fake *FakeManager) ListIsolationSegmentsCallCount() int {
+	fake.listIsolationSegmentsMutex.RLock()
+	defer fake.listIsolationSegmentsMutex.RUnlock()
+	return len(fake.listIsolationSegmentsArgsForCall)
+}
+
+func (fake *FakeManager) ListIsolationSegmentsReturns(result1 []go_cfclient.IsolationSegment, result2 error) {
+	fake.ListIsolationSegmentsStub = nil
+	fake.listIsolationSegmentsReturns = struct {
+		result1 []go_cfclient.IsolationSegment
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) PlacementReport(cellCapacity map[string]int) ([]isosegment.SegmentPlacement, error) {
+	fake.placementReportMutex.Lock()
+	fake.placementReportArgsForCall = append(fake.placementReportArgsForCall, struct {
+		cellCapacity map[string]int
+	}{cellCapacity})
+	fake.recordInvocation("PlacementReport", []interface{}{cellCapacity})
+	fake.placementReportMutex.Unlock()
+	if fake.PlacementReportStub != nil {
+		return fake.PlacementReportStub(cellCapacity)
+	} else {
+		return fake.placementReportReturns.result1, fake.placementReportReturns.result2
+	}
+}
+
+func (fake *FakeManager) PlacementReportCallCount() int {
+	fake.placementReportMutex.RLock()
+	defer fake.placementReportMutex.RUnlock()
+	return len(fake.placementReportArgsForCall)
+}
+
+func (fake *FakeManager) PlacementReportArgsForCall(i int) map[string]int {
+	fake.placementReportMutex.RLock()
+	defer fake.placementReportMutex.RUnlock()
+	return fake.placementReportArgsForCall[i].cellCapacity
+}
+
+func (fake *FakeManager) PlacementReportReturns(result1 []isosegment.SegmentPlacement, result2 error) {
+	fake.PlacementReportStub = nil
+	fake.placementReportReturns = struct {
+		result1 []isosegment.SegmentPlacement
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.applyMutex.RLock()
+	defer fake.applyMutex.RUnlock()
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	fake.removeMutex.RLock()
+	defer fake.removeMutex.RUnlock()
+	fake.entitleMutex.RLock()
+	defer fake.entitleMutex.RUnlock()
+	fake.unentitleMutex.RLock()
+	defer fake.unentitleMutex.RUnlock()
+	fake.updateOrgsMutex.RLock()
+	defer fake.updateOrgsMutex.RUnlock()
+	fake.updateSpacesMutex.RLock()
+	defer fake.updateSpacesMutex.RUnlock()
+	fake.listIsolationSegmentsMutex.RLock()
+	defer fake.listIsolationSegmentsMutex.RUnlock()
+	fake.placementReportMutex.RLock()
+	defer fake.placementReportMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeManager) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ isosegment.Manager = new(FakeManager)
@@ -3,6 +3,7 @@ package isosegment
 import (
 	"fmt"
 	"net/url"
+	"sort"
 
 	"github.com/pkg/errors"
 
@@ -167,7 +168,13 @@ func (u *Updater) Unentitle() error {
 		}
 	}
 
-	for orgGUID, segments := range sm {
+	orgGUIDs := make([]string, 0, len(sm))
+	for orgGUID := range sm {
+		orgGUIDs = append(orgGUIDs, orgGUID)
+	}
+	sort.Strings(orgGUIDs)
+	for _, orgGUID := range orgGUIDs {
+		segments := sm[orgGUID]
 		orgIsolationSegments, err := u.Client.ListIsolationSegmentsByQuery(url.Values{
 			"organization_guids": []string{orgGUID},
 		})
@@ -243,7 +250,13 @@ func (u *Updater) Entitle() error {
 		}
 	}
 
-	for orgGUID, desiredSegments := range sm {
+	orgGUIDs := make([]string, 0, len(sm))
+	for orgGUID := range sm {
+		orgGUIDs = append(orgGUIDs, orgGUID)
+	}
+	sort.Strings(orgGUIDs)
+	for _, orgGUID := range orgGUIDs {
+		desiredSegments := sm[orgGUID]
 		orgIsolationSegments, err := u.Client.ListIsolationSegmentsByQuery(url.Values{
 			"organization_guids": []string{orgGUID},
 		})
@@ -335,6 +348,10 @@ func (u *Updater) UpdateSpaces() error {
 	if err != nil {
 		return err
 	}
+	scs, err = space.ExpandSpaceConfigs(u.SpaceManager, scs)
+	if err != nil {
+		return err
+	}
 
 	isolationSegmentMap, err := u.isolationSegmentMap()
 	if err != nil {
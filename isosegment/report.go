@@ -0,0 +1,213 @@
+package isosegment
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pkg/errors"
+)
+
+// SharedSegment names the shared isolation segment, which apps run on when
+// neither their space nor their org has an isolation segment.
+const SharedSegment = "shared"
+
+// SegmentPlacement is an isolation segment with the managed orgs the
+// configuration places on it, through their default_isolation_segment or the
+// isolation_segment of their spaces, the managed orgs entitled to it and the
+// started apps of the managed spaces running on it. ManagedMemoryMB only
+// counts the apps of the managed spaces, CapacityMB is the memory of its
+// cells when given.
+type SegmentPlacement struct {
+	Segment        string   `json:"segment"`
+	Exists         bool     `json:"exists"`
+	ConfiguredOrgs []string `json:"configured_orgs"`
+	EntitledOrgs   []string `json:"entitled_orgs"`
+	Spaces         int      `json:"spaces"`
+	Apps           int      `json:"apps"`
+	Instances      int      `json:"instances"`
+	// ManagedMemoryMB is the memory of the started apps of the managed spaces
+	ManagedMemoryMB int      `json:"managed_memory_mb"`
+	CapacityMB      int      `json:"capacity_mb,omitempty"`
+	Findings        []string `json:"findings"`
+
+	// missingOrgs are the configured orgs that do not exist yet
+	missingOrgs []string
+}
+
+//PlacementReport - correlates the isolation segments of the configuration with the entitlements of the managed orgs
+//and where the started apps of the managed spaces run, and with cellCapacity, the memory in megabytes of the cells
+//of each segment by name, how much of it the apps use
+func (u *Updater) PlacementReport(cellCapacity map[string]int) ([]SegmentPlacement, error) {
+	orgConfigs, err := u.Cfg.GetOrgConfigs()
+	if err != nil {
+		return nil, err
+	}
+	spaceConfigs, err := u.Cfg.GetSpaceConfigs()
+	if err != nil {
+		return nil, err
+	}
+	segments, err := u.Client.ListIsolationSegments()
+	if err != nil {
+		return nil, err
+	}
+	placements := make(map[string]*SegmentPlacement)
+	placement := func(name string) *SegmentPlacement {
+		if _, ok := placements[name]; !ok {
+			placements[name] = &SegmentPlacement{Segment: name, ConfiguredOrgs: []string{}, EntitledOrgs: []string{}}
+		}
+		return placements[name]
+	}
+	placement(SharedSegment).Exists = true
+	names := make(map[string]string)
+	for _, segment := range segments {
+		names[segment.GUID] = segment.Name
+		placement(segment.Name).Exists = true
+	}
+	for _, orgConfig := range orgConfigs {
+		if orgConfig.DefaultIsoSegment != "" {
+			addOrg(&placement(orgConfig.DefaultIsoSegment).ConfiguredOrgs, orgConfig.Org)
+		}
+	}
+	for _, spaceConfig := range spaceConfigs {
+		if spaceConfig.IsoSegment != "" {
+			addOrg(&placement(spaceConfig.IsoSegment).ConfiguredOrgs, spaceConfig.Org)
+		}
+	}
+
+	existing, err := u.OrgManager.ListOrgs()
+	if err != nil {
+		return nil, errors.Wrap(err, "listing orgs for placement report")
+	}
+	orgsByName := make(map[string]cfclient.Org)
+	for _, org := range existing {
+		orgsByName[org.Name] = org
+	}
+	orgs := make(map[string]cfclient.Org)
+	var orgGUIDs []string
+	for _, orgConfig := range orgConfigs {
+		org, ok := orgsByName[orgConfig.Org]
+		if !ok {
+			for _, p := range placements {
+				if contains(p.ConfiguredOrgs, orgConfig.Org) {
+					addOrg(&p.missingOrgs, orgConfig.Org)
+				}
+			}
+			continue
+		}
+		orgs[org.Guid] = org
+		orgGUIDs = append(orgGUIDs, org.Guid)
+		entitled, err := u.Client.ListIsolationSegmentsByQuery(url.Values{"organization_guids": []string{org.Guid}})
+		if err != nil {
+			return nil, err
+		}
+		for _, segment := range entitled {
+			addOrg(&placement(segment.Name).EntitledOrgs, org.Name)
+		}
+	}
+	spaces, err := u.SpaceManager.ListManagedSpaces()
+	if err != nil {
+		return nil, err
+	}
+	segmentOfSpace := make(map[string]string)
+	for _, managed := range spaces {
+		guid := managed.Space.IsolationSegmentGuid
+		if guid == "" {
+			guid = orgs[managed.Space.OrganizationGuid].DefaultIsolationSegmentGuid
+		}
+		name := SharedSegment
+		if guid != "" {
+			name = nameOrGUID(names, guid)
+		}
+		segmentOfSpace[managed.Space.Guid] = name
+		placement(name).Spaces++
+	}
+	for _, orgGUID := range orgGUIDs {
+		apps, err := u.Client.ListAppsByQuery(url.Values{"q": []string{"organization_guid:" + orgGUID}})
+		if err != nil {
+			return nil, err
+		}
+		for _, app := range apps {
+			name, ok := segmentOfSpace[app.SpaceGuid]
+			if !ok || app.State != "STARTED" {
+				continue
+			}
+			p := placement(name)
+			p.Apps++
+			p.Instances += app.Instances
+			p.ManagedMemoryMB += app.Instances * app.Memory
+		}
+	}
+
+	result := []SegmentPlacement{}
+	for name, p := range placements {
+		p.CapacityMB = cellCapacity[name]
+		p.Findings = p.findings()
+		result = append(result, *p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Segment < result[j].Segment })
+	return result, nil
+}
+
+func (p *SegmentPlacement) findings() []string {
+	findings := []string{}
+	if !p.Exists {
+		findings = append(findings, "not created")
+	}
+	if p.Segment != SharedSegment {
+		if p.Exists && len(p.ConfiguredOrgs) == 0 {
+			findings = append(findings, "not configured")
+		}
+		for _, org := range p.ConfiguredOrgs {
+			if contains(p.missingOrgs, org) {
+				findings = append(findings, fmt.Sprintf("org %s not created", org))
+			} else if !contains(p.EntitledOrgs, org) {
+				findings = append(findings, fmt.Sprintf("org %s not entitled", org))
+			}
+		}
+		for _, org := range p.EntitledOrgs {
+			if !contains(p.ConfiguredOrgs, org) {
+				findings = append(findings, fmt.Sprintf("org %s entitled but not configured", org))
+			}
+		}
+		if len(p.EntitledOrgs) > 0 && p.Apps == 0 {
+			findings = append(findings, "entitled but no started apps")
+		}
+	}
+	if p.CapacityMB > 0 && p.ManagedMemoryMB > p.CapacityMB {
+		findings = append(findings, fmt.Sprintf("over capacity, managed apps use %d of %d MB", p.ManagedMemoryMB, p.CapacityMB))
+	}
+	return findings
+}
+
+//Utilization - the percentage of the cell capacity the apps of the managed spaces on the segment use, 0 without a capacity
+func (p SegmentPlacement) Utilization() int {
+	if p.CapacityMB <= 0 {
+		return 0
+	}
+	return p.ManagedMemoryMB * 100 / p.CapacityMB
+}
+
+func addOrg(orgs *[]string, org string) {
+	if !contains(*orgs, org) {
+		*orgs = append(*orgs, org)
+		sort.Strings(*orgs)
+	}
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func nameOrGUID(names map[string]string, guid string) string {
+	if name, ok := names[guid]; ok {
+		return name
+	}
+	return guid
+}
@@ -0,0 +1,105 @@
+package isosegment_test
+
+import (
+	"errors"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/isosegment"
+	"github.com/pivotalservices/cf-mgmt/isosegment/fakes"
+	orgfakes "github.com/pivotalservices/cf-mgmt/organization/fakes"
+	"github.com/pivotalservices/cf-mgmt/space"
+	spacefakes "github.com/pivotalservices/cf-mgmt/space/fakes"
+)
+
+var _ = Describe("Isolation Segment Placement Report", func() {
+	var (
+		u            *isosegment.Updater
+		client       *fakes.FakeCFClient
+		orgManager   *orgfakes.FakeManager
+		spaceManager *spacefakes.FakeManager
+	)
+	BeforeEach(func() {
+		client = new(fakes.FakeCFClient)
+		orgManager = new(orgfakes.FakeManager)
+		spaceManager = new(spacefakes.FakeManager)
+		u = &isosegment.Updater{
+			Cfg:          config.NewManager("./fixtures/0001"),
+			OrgManager:   orgManager,
+			SpaceManager: spaceManager,
+			Client:       client,
+		}
+		client.ListIsolationSegmentsReturns([]cfclient.IsolationSegment{
+			{Name: "default_iso", GUID: "default_iso_guid"},
+			{Name: "iso01", GUID: "iso01_guid"},
+			{Name: "unused", GUID: "unused_guid"},
+		}, nil)
+		client.ListIsolationSegmentsByQueryReturns([]cfclient.IsolationSegment{
+			{Name: "default_iso", GUID: "default_iso_guid"},
+		}, nil)
+		orgManager.ListOrgsReturns([]cfclient.Org{{Name: "org1", Guid: "org1_guid", DefaultIsolationSegmentGuid: "default_iso_guid"}}, nil)
+		spaceManager.ListManagedSpacesReturns([]space.ManagedSpace{
+			{Org: "org1", Space: cfclient.Space{Name: "org1space1", Guid: "space1_guid", OrganizationGuid: "org1_guid"}},
+			{Org: "org1", Space: cfclient.Space{Name: "org1space2", Guid: "space2_guid", OrganizationGuid: "org1_guid", IsolationSegmentGuid: "iso01_guid"}},
+		}, nil)
+		client.ListAppsByQueryReturns([]cfclient.App{
+			{Name: "web", SpaceGuid: "space1_guid", State: "STARTED", Instances: 2, Memory: 512},
+			{Name: "worker", SpaceGuid: "space1_guid", State: "STOPPED", Instances: 1, Memory: 1024},
+			{Name: "api", SpaceGuid: "space2_guid", State: "STARTED", Instances: 1, Memory: 256},
+		}, nil)
+	})
+
+	It("correlates configured segments with entitlements and the started apps on them", func() {
+		report, err := u.PlacementReport(map[string]int{"default_iso": 768})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(report).Should(HaveLen(4))
+
+		Expect(report[0].Segment).Should(Equal("default_iso"))
+		Expect(report[0].ConfiguredOrgs).Should(ConsistOf("org1"))
+		Expect(report[0].EntitledOrgs).Should(ConsistOf("org1"))
+		Expect(report[0].Spaces).Should(Equal(1))
+		Expect(report[0].Apps).Should(Equal(1))
+		Expect(report[0].ManagedMemoryMB).Should(Equal(1024))
+		Expect(report[0].Utilization()).Should(Equal(133))
+		Expect(report[0].Findings).Should(ConsistOf("over capacity, managed apps use 1024 of 768 MB"))
+
+		Expect(report[1].Segment).Should(Equal("iso01"))
+		Expect(report[1].Apps).Should(Equal(1))
+		Expect(report[1].ManagedMemoryMB).Should(Equal(256))
+		Expect(report[1].Findings).Should(ConsistOf("org org1 not entitled"))
+
+		Expect(report[2].Segment).Should(Equal(isosegment.SharedSegment))
+		Expect(report[2].Findings).Should(BeEmpty())
+
+		Expect(report[3].Segment).Should(Equal("unused"))
+		Expect(report[3].Findings).Should(ConsistOf("not configured"))
+	})
+
+	It("reports configured segments that were not created", func() {
+		client.ListIsolationSegmentsReturns([]cfclient.IsolationSegment{{Name: "default_iso", GUID: "default_iso_guid"}}, nil)
+		report, err := u.PlacementReport(nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(report[1].Segment).Should(Equal("iso01"))
+		Expect(report[1].Exists).Should(BeFalse())
+		Expect(report[1].Findings).Should(ContainElement("not created"))
+	})
+
+	It("reports configured orgs that were not created and continues", func() {
+		orgManager.ListOrgsReturns(nil, nil)
+		spaceManager.ListManagedSpacesReturns(nil, nil)
+		report, err := u.PlacementReport(nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(report[0].Segment).Should(Equal("default_iso"))
+		Expect(report[0].Findings).Should(ConsistOf("org org1 not created"))
+		Expect(client.ListIsolationSegmentsByQueryCallCount()).Should(Equal(0))
+		Expect(client.ListAppsByQueryCallCount()).Should(Equal(0))
+	})
+
+	It("fails when apps cannot be listed", func() {
+		client.ListAppsByQueryReturns(nil, errors.New("error"))
+		_, err := u.PlacementReport(nil)
+		Expect(err).Should(HaveOccurred())
+	})
+})
@@ -15,6 +15,7 @@ type Manager interface {
 	UpdateOrgs() error
 	UpdateSpaces() error
 	ListIsolationSegments() ([]cfclient.IsolationSegment, error)
+	PlacementReport(cellCapacity map[string]int) ([]SegmentPlacement, error)
 }
 
 type CFClient interface {
@@ -31,4 +32,5 @@ type CFClient interface {
 	ResetDefaultIsolationSegmentForOrg(orgGUID string) error
 	IsolationSegmentForSpace(spaceGUID, isolationSegmentGUID string) error
 	ResetIsolationSegmentForSpace(spaceGUID string) error
+	ListAppsByQuery(query url.Values) ([]cfclient.App, error)
 }
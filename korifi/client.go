@@ -0,0 +1,176 @@
+// Package korifi adapts the cloud controller calls of the cf-mgmt managers to
+// Korifi, the Cloud Foundry api on Kubernetes. Korifi only implements the v3
+// api, and only part of it, so orgs, spaces, roles and metadata are managed
+// while calls for features Korifi lacks, such as quotas, security groups and
+// isolation segments, fail with a NotSupportedError that apply reports as an
+// unsupported step instead of a failure.
+package korifi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+//NotSupportedError - a call for a feature Korifi does not implement
+type NotSupportedError struct {
+	Feature string
+}
+
+func (e *NotSupportedError) Error() string {
+	return fmt.Sprintf("korifi does not support %s", e.Feature)
+}
+
+func notSupported(feature string) error {
+	return &NotSupportedError{Feature: feature}
+}
+
+//IsNotSupported - whether err, or the error it wraps, is a NotSupportedError
+func IsNotSupported(err error) bool {
+	_, ok := errors.Cause(err).(*NotSupportedError)
+	return ok
+}
+
+//Client - the cloud controller client of a Korifi foundation
+type Client struct {
+	httpClient *http.Client
+	apiAddress string
+}
+
+//NewClient - a client of the Korifi api at apiAddress. httpClient must
+//authenticate the requests, such as with TokenTransport.
+func NewClient(httpClient *http.Client, apiAddress string) *Client {
+	return &Client{httpClient: httpClient, apiAddress: strings.TrimSuffix(apiAddress, "/")}
+}
+
+//TokenTransport - authenticates requests with a Kubernetes bearer token, such
+//as the token of a service account
+func TokenTransport(token string, base http.RoundTripper) http.RoundTripper {
+	return &tokenTransport{token: token, base: base}
+}
+
+type tokenTransport struct {
+	token string
+	base  http.RoundTripper
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+// page is a page of resources of a v3 list
+type page struct {
+	Pagination struct {
+		Next *struct {
+			Href string `json:"href"`
+		} `json:"next"`
+	} `json:"pagination"`
+	Resources json.RawMessage `json:"resources"`
+	Included  struct {
+		Users []v3User `json:"users"`
+	} `json:"included"`
+}
+
+// list gets every page of the v3 resources at path, calling add with the
+// resources and included users of each page
+func (c *Client) list(path string, add func(resources json.RawMessage, users []v3User) error) error {
+	next := c.apiAddress + path
+	for next != "" {
+		result := &page{}
+		if err := c.do(http.MethodGet, next, nil, result); err != nil {
+			return err
+		}
+		if err := add(result.Resources, result.Included.Users); err != nil {
+			return err
+		}
+		next = ""
+		if result.Pagination.Next != nil {
+			next = result.Pagination.Next.Href
+		}
+	}
+	return nil
+}
+
+func (c *Client) get(path string, result interface{}) error {
+	return c.do(http.MethodGet, c.apiAddress+path, nil, result)
+}
+
+func (c *Client) post(path string, body, result interface{}) error {
+	return c.do(http.MethodPost, c.apiAddress+path, body, result)
+}
+
+func (c *Client) patch(path string, body, result interface{}) error {
+	return c.do(http.MethodPatch, c.apiAddress+path, body, result)
+}
+
+func (c *Client) delete(path string) error {
+	return c.do(http.MethodDelete, c.apiAddress+path, nil, nil)
+}
+
+func (c *Client) do(method, url string, body, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("korifi returned %d for %s %s: %s", resp.StatusCode, method, url, string(data))
+	}
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
+
+// relationship is the to-one relationship of a v3 resource
+type relationship struct {
+	Data *struct {
+		GUID string `json:"guid"`
+	} `json:"data"`
+}
+
+func toOne(guid string) relationship {
+	r := relationship{}
+	r.Data = &struct {
+		GUID string `json:"guid"`
+	}{GUID: guid}
+	return r
+}
+
+func (r relationship) guid() string {
+	if r.Data == nil {
+		return ""
+	}
+	return r.Data.GUID
+}
@@ -0,0 +1,169 @@
+package korifi_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/korifi"
+	"github.com/pivotalservices/cf-mgmt/uaa"
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("given korifi client", func() {
+	var (
+		server   *httptest.Server
+		client   *korifi.Client
+		handlers map[string]func(w http.ResponseWriter, r *http.Request)
+		requests []string
+		bodies   []string
+	)
+
+	BeforeEach(func() {
+		handlers = make(map[string]func(w http.ResponseWriter, r *http.Request))
+		requests, bodies = nil, nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Get("Authorization")).Should(Equal("Bearer k8s-token"))
+			requests = append(requests, r.Method+" "+r.URL.Path)
+			body, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			if handler, ok := handlers[r.Method+" "+r.URL.Path]; ok {
+				handler(w, r)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		client = korifi.NewClient(&http.Client{Transport: korifi.TokenTransport("k8s-token", http.DefaultTransport)}, server.URL)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("lists orgs across pages", func() {
+		handlers["GET /v3/organizations"] = func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("page") == "2" {
+				w.Write([]byte(`{"pagination":{"next":null},"resources":[{"guid":"org2-guid","name":"org2"}]}`))
+				return
+			}
+			w.Write([]byte(`{"pagination":{"next":{"href":"` + server.URL + `/v3/organizations?page=2"}},"resources":[{"guid":"org1-guid","name":"org1","suspended":true}]}`))
+		}
+		orgs, err := client.ListOrgs()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(orgs).Should(Equal([]cfclient.Org{
+			cfclient.Org{Guid: "org1-guid", Name: "org1", Status: "suspended"},
+			cfclient.Org{Guid: "org2-guid", Name: "org2", Status: "active"},
+		}))
+	})
+
+	It("lists the spaces of an org", func() {
+		handlers["GET /v3/spaces"] = func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Query().Get("organization_guids")).Should(Equal("org1-guid"))
+			w.Write([]byte(`{"resources":[{"guid":"space1-guid","name":"space1","relationships":{"organization":{"data":{"guid":"org1-guid"}}}}]}`))
+		}
+		spaces, err := client.ListSpacesByQuery(map[string][]string{"q": {"organization_guid:org1-guid"}})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(spaces).Should(Equal([]cfclient.Space{
+			cfclient.Space{Guid: "space1-guid", Name: "space1", OrganizationGuid: "org1-guid"},
+		}))
+	})
+
+	It("creates a space in an org", func() {
+		handlers["POST /v3/spaces"] = func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"guid":"space1-guid","name":"space1","relationships":{"organization":{"data":{"guid":"org1-guid"}}}}`))
+		}
+		space, err := client.CreateSpace(cfclient.SpaceRequest{Name: "space1", OrganizationGuid: "org1-guid"})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(space.Guid).Should(Equal("space1-guid"))
+		Expect(bodies[0]).Should(MatchJSON(`{"name":"space1","relationships":{"organization":{"data":{"guid":"org1-guid"}}}}`))
+	})
+
+	It("ignores allow-ssh of a space instead of failing", func() {
+		handlers["POST /v3/spaces"] = func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"guid":"space1-guid","name":"space1","relationships":{"organization":{"data":{"guid":"org1-guid"}}}}`))
+		}
+		handlers["GET /v3/spaces/space1-guid"] = func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"guid":"space1-guid","name":"space1","relationships":{"organization":{"data":{"guid":"org1-guid"}}}}`))
+		}
+		_, err := client.CreateSpace(cfclient.SpaceRequest{Name: "space1", OrganizationGuid: "org1-guid", AllowSSH: true})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(bodies[0]).Should(MatchJSON(`{"name":"space1","relationships":{"organization":{"data":{"guid":"org1-guid"}}}}`))
+		space, err := client.UpdateSpace("space1-guid", cfclient.SpaceRequest{AllowSSH: true})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(space.Guid).Should(Equal("space1-guid"))
+	})
+
+	Context("roles", func() {
+		BeforeEach(func() {
+			handlers["GET /v3/roles"] = func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Query().Get("types")).Should(Equal("space_developer"))
+				Expect(r.URL.Query().Get("space_guids")).Should(Equal("space1-guid"))
+				Expect(r.URL.Query().Get("include")).Should(Equal("user"))
+				w.Write([]byte(`{"resources":[{"guid":"role-guid","type":"space_developer","relationships":{"user":{"data":{"guid":"alice-guid"}}}}],
+					"included":{"users":[{"guid":"alice-guid","username":"alice"}]}}`))
+			}
+			handlers["POST /v3/roles"] = func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+			}
+			handlers["DELETE /v3/roles/role-guid"] = func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+			}
+		})
+
+		It("lists the users holding a role", func() {
+			users, err := client.ListSpaceDevelopers("space1-guid")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(users).Should(Equal([]cfclient.User{cfclient.User{Guid: "alice-guid", Username: "alice", Active: true}}))
+		})
+
+		It("gives a user a role by name", func() {
+			_, err := client.AssociateSpaceDeveloperByUsername("space1-guid", "bob")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(requests).Should(Equal([]string{"GET /v3/roles", "POST /v3/roles"}))
+			Expect(bodies[1]).Should(MatchJSON(`{"type":"space_developer","relationships":{"user":{"data":{"username":"bob"}},"space":{"data":{"guid":"space1-guid"}}}}`))
+		})
+
+		It("does not give a user a role they hold", func() {
+			_, err := client.AssociateSpaceDeveloperByUsername("space1-guid", "Alice")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(requests).Should(Equal([]string{"GET /v3/roles"}))
+		})
+
+		It("removes a role from a user", func() {
+			err := client.RemoveSpaceDeveloperByUsername("space1-guid", "alice")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(requests).Should(Equal([]string{"GET /v3/roles", "DELETE /v3/roles/role-guid"}))
+		})
+	})
+
+	It("writes annotations", func() {
+		handlers["PATCH /v3/spaces/space1-guid"] = func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{}`))
+		}
+		err := client.SetAnnotations("spaces", "space1-guid", map[string]string{"cf-mgmt.io/egress": "none"})
+		Expect(err).ShouldNot(HaveOccurred())
+		var body map[string]interface{}
+		Expect(json.Unmarshal([]byte(bodies[0]), &body)).Should(Succeed())
+		Expect(body).Should(HaveKey("metadata"))
+	})
+
+	It("fails changes to features korifi lacks", func() {
+		_, err := client.CreateOrgQuota(cfclient.OrgQuotaRequest{Name: "default"})
+		Expect(err).Should(MatchError("korifi does not support org quotas"))
+		Expect(korifi.IsNotSupported(errors.Wrap(err, "creating quota"))).Should(BeTrue())
+		Expect(korifi.IsNotSupported(errors.New("quota exists"))).Should(BeFalse())
+		Expect(requests).Should(BeEmpty())
+	})
+
+	It("takes every user name to be a user", func() {
+		users, err := korifi.NewUAAManager(client).ListUsersByName([]string{"Alice"})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(users).Should(HaveKey(uaa.UserKey{Origin: "uaa", Name: "alice"}))
+	})
+})
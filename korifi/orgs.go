@@ -0,0 +1,196 @@
+package korifi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pivotalservices/cf-mgmt/organization"
+	"github.com/xchapter7x/lo"
+)
+
+type v3Org struct {
+	GUID      string `json:"guid"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"created_at"`
+	Suspended bool   `json:"suspended"`
+}
+
+func (o v3Org) org() cfclient.Org {
+	status := "active"
+	if o.Suspended {
+		status = "suspended"
+	}
+	return cfclient.Org{Guid: o.GUID, Name: o.Name, CreatedAt: o.CreatedAt, Status: status}
+}
+
+type v3Space struct {
+	GUID          string `json:"guid"`
+	Name          string `json:"name"`
+	CreatedAt     string `json:"created_at"`
+	Relationships struct {
+		Organization relationship `json:"organization"`
+	} `json:"relationships"`
+}
+
+func (s v3Space) space() cfclient.Space {
+	return cfclient.Space{
+		Guid:             s.GUID,
+		Name:             s.Name,
+		CreatedAt:        s.CreatedAt,
+		OrganizationGuid: s.Relationships.Organization.guid(),
+	}
+}
+
+//ListOrgs -
+func (c *Client) ListOrgs() ([]cfclient.Org, error) {
+	orgs := []cfclient.Org{}
+	err := c.list("/v3/organizations?per_page=5000", func(resources json.RawMessage, _ []v3User) error {
+		var page []v3Org
+		if err := json.Unmarshal(resources, &page); err != nil {
+			return err
+		}
+		for _, org := range page {
+			orgs = append(orgs, org.org())
+		}
+		return nil
+	})
+	return orgs, err
+}
+
+//ListOrgNamesByLabelSelector - lists the names of the orgs whose labels match selector
+func (c *Client) ListOrgNamesByLabelSelector(selector organization.LabelSelector) ([]string, error) {
+	return organization.ListOrgNamesByLabelSelector(c.httpClient, c.apiAddress, selector)
+}
+
+//GetOrgByGuid -
+func (c *Client) GetOrgByGuid(guid string) (cfclient.Org, error) {
+	org := v3Org{}
+	if err := c.get("/v3/organizations/"+guid, &org); err != nil {
+		return cfclient.Org{}, err
+	}
+	return org.org(), nil
+}
+
+//CreateOrg - creates an org, Korifi orgs have no quota or isolation segment
+func (c *Client) CreateOrg(req cfclient.OrgRequest) (cfclient.Org, error) {
+	if req.QuotaDefinitionGuid != "" {
+		return cfclient.Org{}, notSupported("org quotas")
+	}
+	if req.DefaultIsolationSegmentGuid != "" {
+		return cfclient.Org{}, notSupported("isolation segments")
+	}
+	org := v3Org{}
+	if err := c.post("/v3/organizations", map[string]interface{}{"name": req.Name}, &org); err != nil {
+		return cfclient.Org{}, err
+	}
+	return org.org(), nil
+}
+
+//UpdateOrg - renames an org, Korifi orgs have no quota or isolation segment
+func (c *Client) UpdateOrg(orgGUID string, req cfclient.OrgRequest) (cfclient.Org, error) {
+	if req.QuotaDefinitionGuid != "" {
+		return cfclient.Org{}, notSupported("org quotas")
+	}
+	if req.DefaultIsolationSegmentGuid != "" {
+		return cfclient.Org{}, notSupported("isolation segments")
+	}
+	org := v3Org{}
+	if err := c.patch("/v3/organizations/"+orgGUID, map[string]interface{}{"name": req.Name}, &org); err != nil {
+		return cfclient.Org{}, err
+	}
+	return org.org(), nil
+}
+
+//DeleteOrg - Korifi always deletes an org with its spaces, asynchronously
+func (c *Client) DeleteOrg(guid string, recursive, async bool) error {
+	return c.delete("/v3/organizations/" + guid)
+}
+
+//ListSpacesByQuery - supports the organization_guid filter
+func (c *Client) ListSpacesByQuery(query url.Values) ([]cfclient.Space, error) {
+	v3Query := url.Values{}
+	v3Query.Set("per_page", "5000")
+	for _, q := range query["q"] {
+		if strings.HasPrefix(q, "organization_guid:") {
+			v3Query.Set("organization_guids", strings.TrimPrefix(q, "organization_guid:"))
+		}
+	}
+	spaces := []cfclient.Space{}
+	err := c.list("/v3/spaces?"+v3Query.Encode(), func(resources json.RawMessage, _ []v3User) error {
+		var page []v3Space
+		if err := json.Unmarshal(resources, &page); err != nil {
+			return err
+		}
+		for _, space := range page {
+			spaces = append(spaces, space.space())
+		}
+		return nil
+	})
+	sort.SliceStable(spaces, func(i, j int) bool { return spaces[i].Name < spaces[j].Name })
+	return spaces, err
+}
+
+//GetSpaceByGuid -
+func (c *Client) GetSpaceByGuid(spaceGUID string) (cfclient.Space, error) {
+	space := v3Space{}
+	if err := c.get("/v3/spaces/"+spaceGUID, &space); err != nil {
+		return cfclient.Space{}, err
+	}
+	return space.space(), nil
+}
+
+//CreateSpace - Korifi does not support ssh to apps, so allow-ssh is ignored with a warning
+func (c *Client) CreateSpace(req cfclient.SpaceRequest) (cfclient.Space, error) {
+	if req.AllowSSH {
+		lo.G.Warningf("Korifi does not support ssh to apps, ignoring allow-ssh of space %s", req.Name)
+	}
+	body := map[string]interface{}{
+		"name":          req.Name,
+		"relationships": map[string]interface{}{"organization": toOne(req.OrganizationGuid)},
+	}
+	space := v3Space{}
+	if err := c.post("/v3/spaces", body, &space); err != nil {
+		return cfclient.Space{}, err
+	}
+	return space.space(), nil
+}
+
+//UpdateSpace - the space manager only updates whether ssh is allowed, which
+//Korifi does not support, so allow-ssh is ignored with a warning
+func (c *Client) UpdateSpace(spaceGUID string, req cfclient.SpaceRequest) (cfclient.Space, error) {
+	if req.AllowSSH {
+		lo.G.Warningf("Korifi does not support ssh to apps, ignoring allow-ssh of space %s", spaceGUID)
+	}
+	return c.GetSpaceByGuid(spaceGUID)
+}
+
+//DeleteSpace - Korifi always deletes a space with its apps, asynchronously
+func (c *Client) DeleteSpace(guid string, recursive, async bool) error {
+	return c.delete("/v3/spaces/" + guid)
+}
+
+type v3Metadata struct {
+	Metadata struct {
+		Annotations map[string]string `json:"annotations"`
+	} `json:"metadata"`
+}
+
+//GetAnnotations - the annotations of an org or space
+func (c *Client) GetAnnotations(resource, guid string) (map[string]string, error) {
+	result := &v3Metadata{}
+	if err := c.get(fmt.Sprintf("/v3/%s/%s", resource, guid), result); err != nil {
+		return nil, err
+	}
+	return result.Metadata.Annotations, nil
+}
+
+//SetAnnotations - adds annotations to an org or space, replacing those with the same keys
+func (c *Client) SetAnnotations(resource, guid string, annotations map[string]string) error {
+	update := &v3Metadata{}
+	update.Metadata.Annotations = annotations
+	return c.patch(fmt.Sprintf("/v3/%s/%s", resource, guid), update, nil)
+}
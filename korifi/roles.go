@@ -0,0 +1,332 @@
+package korifi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+)
+
+// role types of the v3 api
+const (
+	orgUser           = "organization_user"
+	orgAuditor        = "organization_auditor"
+	orgManager        = "organization_manager"
+	orgBillingManager = "organization_billing_manager"
+	spaceAuditor      = "space_auditor"
+	spaceDeveloper    = "space_developer"
+	spaceManager      = "space_manager"
+)
+
+type v3User struct {
+	GUID     string `json:"guid"`
+	Username string `json:"username"`
+	Origin   string `json:"origin"`
+}
+
+type v3Role struct {
+	GUID          string `json:"guid"`
+	Type          string `json:"type"`
+	Relationships struct {
+		User relationship `json:"user"`
+	} `json:"relationships"`
+}
+
+// roleUser is a role along with the user holding it
+type roleUser struct {
+	roleGUID string
+	user     cfclient.User
+}
+
+// scope is the org or space of a role
+type scope struct {
+	relationship string
+	guid         string
+}
+
+func orgScope(orgGUID string) scope {
+	return scope{relationship: "organization", guid: orgGUID}
+}
+
+func spaceScope(spaceGUID string) scope {
+	return scope{relationship: "space", guid: spaceGUID}
+}
+
+// listRoles lists the roles of the type in the org or space, every role of the
+// type when guid is empty, along with the users holding them
+func (c *Client) listRoles(roleType string, in scope) ([]roleUser, error) {
+	query := url.Values{}
+	query.Set("types", roleType)
+	query.Set("include", "user")
+	query.Set("per_page", "5000")
+	if in.guid != "" {
+		query.Set(in.relationship+"_guids", in.guid)
+	}
+	var roles []roleUser
+	err := c.list("/v3/roles?"+query.Encode(), func(resources json.RawMessage, users []v3User) error {
+		var page []v3Role
+		if err := json.Unmarshal(resources, &page); err != nil {
+			return err
+		}
+		usernames := make(map[string]string)
+		for _, user := range users {
+			usernames[user.GUID] = user.Username
+		}
+		for _, role := range page {
+			userGUID := role.Relationships.User.guid()
+			username, ok := usernames[userGUID]
+			if !ok {
+				// Kubernetes subjects are their own guid
+				username = userGUID
+			}
+			roles = append(roles, roleUser{roleGUID: role.GUID, user: cfclient.User{Guid: userGUID, Username: username, Active: true}})
+		}
+		return nil
+	})
+	return roles, err
+}
+
+func (c *Client) listRoleUsers(roleType string, in scope) ([]cfclient.User, error) {
+	roles, err := c.listRoles(roleType, in)
+	if err != nil {
+		return nil, err
+	}
+	users := []cfclient.User{}
+	for _, role := range roles {
+		users = append(users, role.user)
+	}
+	return users, nil
+}
+
+// findRole returns the guid of the role held by the user, matched by name or
+// guid, empty when the user does not hold it
+func (c *Client) findRole(roleType string, in scope, user string) (string, error) {
+	roles, err := c.listRoles(roleType, in)
+	if err != nil {
+		return "", err
+	}
+	for _, role := range roles {
+		if strings.EqualFold(role.user.Username, user) || role.user.Guid == user {
+			return role.roleGUID, nil
+		}
+	}
+	return "", nil
+}
+
+// addRole gives the user, by username or guid, the role unless they hold it,
+// as the v3 api rejects a role the user already holds
+func (c *Client) addRole(roleType string, in scope, userKey, user string) error {
+	existing, err := c.findRole(roleType, in, user)
+	if err != nil || existing != "" {
+		return err
+	}
+	body := map[string]interface{}{
+		"type": roleType,
+		"relationships": map[string]interface{}{
+			"user":          map[string]interface{}{"data": map[string]string{userKey: user}},
+			in.relationship: toOne(in.guid),
+		},
+	}
+	return c.post("/v3/roles", body, nil)
+}
+
+func (c *Client) removeRole(roleType string, in scope, user string) error {
+	roleGUID, err := c.findRole(roleType, in, user)
+	if err != nil {
+		return err
+	}
+	if roleGUID == "" {
+		return fmt.Errorf("%s does not have role %s", user, roleType)
+	}
+	return c.delete("/v3/roles/" + roleGUID)
+}
+
+func (c *Client) addOrgRole(roleType, orgGUID, userKey, user string) (cfclient.Org, error) {
+	return cfclient.Org{Guid: orgGUID}, c.addRole(roleType, orgScope(orgGUID), userKey, user)
+}
+
+func (c *Client) addSpaceRole(roleType, spaceGUID, userKey, user string) (cfclient.Space, error) {
+	return cfclient.Space{Guid: spaceGUID}, c.addRole(roleType, spaceScope(spaceGUID), userKey, user)
+}
+
+//ListOrgUsers -
+func (c *Client) ListOrgUsers(orgGUID string) ([]cfclient.User, error) {
+	return c.listRoleUsers(orgUser, orgScope(orgGUID))
+}
+
+//ListOrgAuditors -
+func (c *Client) ListOrgAuditors(orgGUID string) ([]cfclient.User, error) {
+	return c.listRoleUsers(orgAuditor, orgScope(orgGUID))
+}
+
+//ListOrgManagers -
+func (c *Client) ListOrgManagers(orgGUID string) ([]cfclient.User, error) {
+	return c.listRoleUsers(orgManager, orgScope(orgGUID))
+}
+
+//ListOrgBillingManagers -
+func (c *Client) ListOrgBillingManagers(orgGUID string) ([]cfclient.User, error) {
+	return c.listRoleUsers(orgBillingManager, orgScope(orgGUID))
+}
+
+//AssociateOrgUserByUsername -
+func (c *Client) AssociateOrgUserByUsername(orgGUID, userName string) (cfclient.Org, error) {
+	return c.addOrgRole(orgUser, orgGUID, "username", userName)
+}
+
+//AssociateOrgAuditorByUsername -
+func (c *Client) AssociateOrgAuditorByUsername(orgGUID, name string) (cfclient.Org, error) {
+	return c.addOrgRole(orgAuditor, orgGUID, "username", name)
+}
+
+//AssociateOrgManagerByUsername -
+func (c *Client) AssociateOrgManagerByUsername(orgGUID, name string) (cfclient.Org, error) {
+	return c.addOrgRole(orgManager, orgGUID, "username", name)
+}
+
+//AssociateOrgBillingManagerByUsername -
+func (c *Client) AssociateOrgBillingManagerByUsername(orgGUID, name string) (cfclient.Org, error) {
+	return c.addOrgRole(orgBillingManager, orgGUID, "username", name)
+}
+
+//RemoveOrgUserByUsername -
+func (c *Client) RemoveOrgUserByUsername(orgGUID, name string) error {
+	return c.removeRole(orgUser, orgScope(orgGUID), name)
+}
+
+//RemoveOrgAuditorByUsername -
+func (c *Client) RemoveOrgAuditorByUsername(orgGUID, name string) error {
+	return c.removeRole(orgAuditor, orgScope(orgGUID), name)
+}
+
+//RemoveOrgBillingManagerByUsername -
+func (c *Client) RemoveOrgBillingManagerByUsername(orgGUID, name string) error {
+	return c.removeRole(orgBillingManager, orgScope(orgGUID), name)
+}
+
+//RemoveOrgManagerByUsername -
+func (c *Client) RemoveOrgManagerByUsername(orgGUID, name string) error {
+	return c.removeRole(orgManager, orgScope(orgGUID), name)
+}
+
+//AssociateOrgUser -
+func (c *Client) AssociateOrgUser(orgGUID, userGUID string) (cfclient.Org, error) {
+	return c.addOrgRole(orgUser, orgGUID, "guid", userGUID)
+}
+
+//AssociateOrgAuditor -
+func (c *Client) AssociateOrgAuditor(orgGUID, userGUID string) (cfclient.Org, error) {
+	return c.addOrgRole(orgAuditor, orgGUID, "guid", userGUID)
+}
+
+//AssociateOrgManager -
+func (c *Client) AssociateOrgManager(orgGUID, userGUID string) (cfclient.Org, error) {
+	return c.addOrgRole(orgManager, orgGUID, "guid", userGUID)
+}
+
+//AssociateOrgBillingManager -
+func (c *Client) AssociateOrgBillingManager(orgGUID, userGUID string) (cfclient.Org, error) {
+	return c.addOrgRole(orgBillingManager, orgGUID, "guid", userGUID)
+}
+
+//RemoveOrgUser -
+func (c *Client) RemoveOrgUser(orgGUID, userGUID string) error {
+	return c.removeRole(orgUser, orgScope(orgGUID), userGUID)
+}
+
+//RemoveOrgAuditor -
+func (c *Client) RemoveOrgAuditor(orgGUID, userGUID string) error {
+	return c.removeRole(orgAuditor, orgScope(orgGUID), userGUID)
+}
+
+//RemoveOrgManager -
+func (c *Client) RemoveOrgManager(orgGUID, userGUID string) error {
+	return c.removeRole(orgManager, orgScope(orgGUID), userGUID)
+}
+
+//RemoveOrgBillingManager -
+func (c *Client) RemoveOrgBillingManager(orgGUID, userGUID string) error {
+	return c.removeRole(orgBillingManager, orgScope(orgGUID), userGUID)
+}
+
+//ListSpaceAuditors -
+func (c *Client) ListSpaceAuditors(spaceGUID string) ([]cfclient.User, error) {
+	return c.listRoleUsers(spaceAuditor, spaceScope(spaceGUID))
+}
+
+//ListSpaceManagers -
+func (c *Client) ListSpaceManagers(spaceGUID string) ([]cfclient.User, error) {
+	return c.listRoleUsers(spaceManager, spaceScope(spaceGUID))
+}
+
+//ListSpaceDevelopers -
+func (c *Client) ListSpaceDevelopers(spaceGUID string) ([]cfclient.User, error) {
+	return c.listRoleUsers(spaceDeveloper, spaceScope(spaceGUID))
+}
+
+//AssociateSpaceAuditorByUsername -
+func (c *Client) AssociateSpaceAuditorByUsername(spaceGUID, userName string) (cfclient.Space, error) {
+	return c.addSpaceRole(spaceAuditor, spaceGUID, "username", userName)
+}
+
+//AssociateSpaceDeveloperByUsername -
+func (c *Client) AssociateSpaceDeveloperByUsername(spaceGUID, userName string) (cfclient.Space, error) {
+	return c.addSpaceRole(spaceDeveloper, spaceGUID, "username", userName)
+}
+
+//AssociateSpaceManagerByUsername -
+func (c *Client) AssociateSpaceManagerByUsername(spaceGUID, userName string) (cfclient.Space, error) {
+	return c.addSpaceRole(spaceManager, spaceGUID, "username", userName)
+}
+
+//RemoveSpaceAuditorByUsername -
+func (c *Client) RemoveSpaceAuditorByUsername(spaceGUID, userName string) error {
+	return c.removeRole(spaceAuditor, spaceScope(spaceGUID), userName)
+}
+
+//RemoveSpaceDeveloperByUsername -
+func (c *Client) RemoveSpaceDeveloperByUsername(spaceGUID, userName string) error {
+	return c.removeRole(spaceDeveloper, spaceScope(spaceGUID), userName)
+}
+
+//RemoveSpaceManagerByUsername -
+func (c *Client) RemoveSpaceManagerByUsername(spaceGUID, userName string) error {
+	return c.removeRole(spaceManager, spaceScope(spaceGUID), userName)
+}
+
+//AssociateSpaceAuditor -
+func (c *Client) AssociateSpaceAuditor(spaceGUID, userGUID string) (cfclient.Space, error) {
+	return c.addSpaceRole(spaceAuditor, spaceGUID, "guid", userGUID)
+}
+
+//AssociateSpaceDeveloper -
+func (c *Client) AssociateSpaceDeveloper(spaceGUID, userGUID string) (cfclient.Space, error) {
+	return c.addSpaceRole(spaceDeveloper, spaceGUID, "guid", userGUID)
+}
+
+//AssociateSpaceManager -
+func (c *Client) AssociateSpaceManager(spaceGUID, userGUID string) (cfclient.Space, error) {
+	return c.addSpaceRole(spaceManager, spaceGUID, "guid", userGUID)
+}
+
+//RemoveSpaceAuditor -
+func (c *Client) RemoveSpaceAuditor(spaceGUID, userGUID string) error {
+	return c.removeRole(spaceAuditor, spaceScope(spaceGUID), userGUID)
+}
+
+//RemoveSpaceDeveloper -
+func (c *Client) RemoveSpaceDeveloper(spaceGUID, userGUID string) error {
+	return c.removeRole(spaceDeveloper, spaceScope(spaceGUID), userGUID)
+}
+
+//RemoveSpaceManager -
+func (c *Client) RemoveSpaceManager(spaceGUID, userGUID string) error {
+	return c.removeRole(spaceManager, spaceScope(spaceGUID), userGUID)
+}
+
+//DeleteUser - Korifi users are Kubernetes subjects, which cf-mgmt cannot delete
+func (c *Client) DeleteUser(userGUID string) error {
+	return notSupported("deleting users")
+}
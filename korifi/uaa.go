@@ -0,0 +1,149 @@
+package korifi
+
+import (
+	"strings"
+
+	uaaclient "github.com/cloudfoundry-community/go-uaa"
+	"github.com/pivotalservices/cf-mgmt/uaa"
+)
+
+// Korifi has no uaa, its users are the subjects Kubernetes authenticates,
+// which exist without being created, so every user name is taken to be a
+// user of the uaa origin.
+
+//NewUAAManager - the stand-in for uaa of a Korifi foundation, whose users are
+//the users holding a role
+func NewUAAManager(client *Client) uaa.Manager {
+	return &uaaManager{client: client}
+}
+
+type uaaManager struct {
+	client *Client
+}
+
+func kubernetesUser(name string) *uaaclient.User {
+	return &uaaclient.User{ID: name, Username: name, Origin: "uaa", Active: newTrue()}
+}
+
+func newTrue() *bool {
+	active := true
+	return &active
+}
+
+//ListUsers - the users holding an org role
+func (m *uaaManager) ListUsers() (map[string]*uaaclient.User, error) {
+	users := make(map[string]*uaaclient.User)
+	for _, roleType := range []string{orgUser, orgAuditor, orgManager, orgBillingManager} {
+		roles, err := m.client.listRoles(roleType, scope{relationship: "organization"})
+		if err != nil {
+			return nil, err
+		}
+		for _, role := range roles {
+			users[strings.ToLower(role.user.Username)] = kubernetesUser(role.user.Username)
+		}
+	}
+	return users, nil
+}
+
+//ListUsersByName - every name is a user
+func (m *uaaManager) ListUsersByName(names []string) (map[uaa.UserKey]*uaaclient.User, error) {
+	users := make(map[uaa.UserKey]*uaaclient.User)
+	for _, name := range names {
+		user := kubernetesUser(name)
+		users[uaa.UserKey{Origin: user.Origin, Name: strings.ToLower(name)}] = user
+	}
+	return users, nil
+}
+
+//ListAllUsers -
+func (m *uaaManager) ListAllUsers() ([]*uaaclient.User, error) {
+	users, err := m.ListUsers()
+	if err != nil {
+		return nil, err
+	}
+	var result []*uaaclient.User
+	for _, user := range users {
+		result = append(result, user)
+	}
+	return result, nil
+}
+
+//CreateExternalUser - Kubernetes users need not be created
+func (m *uaaManager) CreateExternalUser(userName, userEmail, externalID, origin string) error {
+	return nil
+}
+
+//CreateInternalUser - Kubernetes users need not be created
+func (m *uaaManager) CreateInternalUser(userName, userEmail, password string) (*uaaclient.User, error) {
+	return kubernetesUser(userName), nil
+}
+
+//UpdateUserOrigin -
+func (m *uaaManager) UpdateUserOrigin(user uaaclient.User, userName, externalID, origin string) error {
+	return notSupported("user origins")
+}
+
+//DeleteUser -
+func (m *uaaManager) DeleteUser(user uaaclient.User) error {
+	return notSupported("deleting users")
+}
+
+//GetGroup -
+func (m *uaaManager) GetGroup(name string) (*uaaclient.Group, error) {
+	return nil, notSupported("uaa groups")
+}
+
+//CreateGroup -
+func (m *uaaManager) CreateGroup(name, description string) (*uaaclient.Group, error) {
+	return nil, notSupported("uaa groups")
+}
+
+//AddGroupMember -
+func (m *uaaManager) AddGroupMember(group uaaclient.Group, userID, userName string) error {
+	return notSupported("uaa groups")
+}
+
+//RemoveGroupMember -
+func (m *uaaManager) RemoveGroupMember(group uaaclient.Group, userID, userName string) error {
+	return notSupported("uaa groups")
+}
+
+//ListIdentityProviders - Korifi has no identity providers of its own
+func (m *uaaManager) ListIdentityProviders() ([]uaa.IdentityProvider, error) {
+	return nil, nil
+}
+
+//CreateIdentityProvider -
+func (m *uaaManager) CreateIdentityProvider(provider uaa.IdentityProvider) error {
+	return notSupported("identity providers")
+}
+
+//UpdateIdentityProvider -
+func (m *uaaManager) UpdateIdentityProvider(provider uaa.IdentityProvider) error {
+	return notSupported("identity providers")
+}
+
+//DeleteIdentityProvider -
+func (m *uaaManager) DeleteIdentityProvider(provider uaa.IdentityProvider) error {
+	return notSupported("identity providers")
+}
+
+//GetTokenPolicy -
+func (m *uaaManager) GetTokenPolicy() (*uaa.TokenPolicy, error) {
+	return nil, notSupported("token policies")
+}
+
+//UpdateTokenPolicy -
+func (m *uaaManager) UpdateTokenPolicy(policy uaa.TokenPolicy) error {
+	return notSupported("token policies")
+}
+
+//AddClientSecret -
+func (m *uaaManager) AddClientSecret(clientID, oldSecret, newSecret string) error {
+	return notSupported("client secrets")
+}
+
+//DeleteOldClientSecret -
+func (m *uaaManager) DeleteOldClientSecret(clientID string) error {
+	return notSupported("client secrets")
+}
@@ -0,0 +1,308 @@
+package korifi
+
+import (
+	"encoding/json"
+	"net/url"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+)
+
+// Korifi lists nothing for the features it lacks, so that reports and checks
+// find nothing to manage, and fails every change with a NotSupportedError.
+
+type v3Domain struct {
+	GUID     string `json:"guid"`
+	Name     string `json:"name"`
+	Internal bool   `json:"internal"`
+}
+
+//ListSharedDomains - every Korifi domain is shared with every org
+func (c *Client) ListSharedDomains() ([]cfclient.SharedDomain, error) {
+	domains := []cfclient.SharedDomain{}
+	err := c.list("/v3/domains?per_page=5000", func(resources json.RawMessage, _ []v3User) error {
+		var page []v3Domain
+		if err := json.Unmarshal(resources, &page); err != nil {
+			return err
+		}
+		for _, domain := range page {
+			domains = append(domains, cfclient.SharedDomain{Guid: domain.GUID, Name: domain.Name, Internal: domain.Internal})
+		}
+		return nil
+	})
+	return domains, err
+}
+
+//ListDomains - Korifi has no private domains
+func (c *Client) ListDomains() ([]cfclient.Domain, error) {
+	return []cfclient.Domain{}, nil
+}
+
+//ListOrgPrivateDomains - Korifi has no private domains
+func (c *Client) ListOrgPrivateDomains(orgGUID string) ([]cfclient.Domain, error) {
+	return []cfclient.Domain{}, nil
+}
+
+//CreateDomain -
+func (c *Client) CreateDomain(name, orgGuid string) (*cfclient.Domain, error) {
+	return nil, notSupported("private domains")
+}
+
+//ShareOrgPrivateDomain -
+func (c *Client) ShareOrgPrivateDomain(orgGUID, privateDomainGUID string) (*cfclient.Domain, error) {
+	return nil, notSupported("private domains")
+}
+
+//DeleteDomain -
+func (c *Client) DeleteDomain(guid string) error {
+	return notSupported("private domains")
+}
+
+//UnshareOrgPrivateDomain -
+func (c *Client) UnshareOrgPrivateDomain(orgGUID, privateDomainGUID string) error {
+	return notSupported("private domains")
+}
+
+//ListRoutesByQuery - routes are only listed to enforce the internal route policy
+func (c *Client) ListRoutesByQuery(query url.Values) ([]cfclient.Route, error) {
+	return nil, notSupported("internal routes")
+}
+
+//DeleteRoute -
+func (c *Client) DeleteRoute(guid string) error {
+	return notSupported("internal routes")
+}
+
+//ListAppsByQuery - apps are only listed to enforce the docker and stack policies and for reports
+func (c *Client) ListAppsByQuery(query url.Values) ([]cfclient.App, error) {
+	return nil, notSupported("app policies")
+}
+
+//UpdateApp -
+func (c *Client) UpdateApp(guid string, aur cfclient.AppUpdateResource) (cfclient.UpdateResponse, error) {
+	return cfclient.UpdateResponse{}, notSupported("app policies")
+}
+
+//ListStacks -
+func (c *Client) ListStacks() ([]cfclient.Stack, error) {
+	return nil, notSupported("stacks")
+}
+
+//ListTasksByQuery -
+func (c *Client) ListTasksByQuery(query url.Values) ([]cfclient.Task, error) {
+	return nil, notSupported("task reports")
+}
+
+//ListServiceInstancesByQuery -
+func (c *Client) ListServiceInstancesByQuery(query url.Values) ([]cfclient.ServiceInstance, error) {
+	return nil, notSupported("service reports")
+}
+
+//ListServiceBrokers -
+func (c *Client) ListServiceBrokers() ([]cfclient.ServiceBroker, error) {
+	return nil, notSupported("service reports")
+}
+
+//ListServices -
+func (c *Client) ListServices() ([]cfclient.Service, error) {
+	return nil, notSupported("service reports")
+}
+
+//ListServicePlans -
+func (c *Client) ListServicePlans() ([]cfclient.ServicePlan, error) {
+	return nil, notSupported("service reports")
+}
+
+//ListServicePlanVisibilities -
+func (c *Client) ListServicePlanVisibilities() ([]cfclient.ServicePlanVisibility, error) {
+	return nil, notSupported("service reports")
+}
+
+//ListServiceBindingsByQuery -
+func (c *Client) ListServiceBindingsByQuery(query url.Values) ([]cfclient.ServiceBinding, error) {
+	return nil, notSupported("service reports")
+}
+
+//ListServiceKeysByQuery -
+func (c *Client) ListServiceKeysByQuery(query url.Values) ([]cfclient.ServiceKey, error) {
+	return nil, notSupported("service reports")
+}
+
+//CreateServiceKey -
+func (c *Client) CreateServiceKey(req cfclient.CreateServiceKeyRequest) (cfclient.ServiceKey, error) {
+	return cfclient.ServiceKey{}, notSupported("service key rotation")
+}
+
+//DeleteServiceKey -
+func (c *Client) DeleteServiceKey(guid string) error {
+	return notSupported("service key rotation")
+}
+
+//ListEventsByQuery -
+func (c *Client) ListEventsByQuery(query url.Values) ([]cfclient.Event, error) {
+	return nil, notSupported("audit events")
+}
+
+//ListOrgSpaceQuotas - Korifi has no quotas
+func (c *Client) ListOrgSpaceQuotas(orgGUID string) ([]cfclient.SpaceQuota, error) {
+	return []cfclient.SpaceQuota{}, nil
+}
+
+//UpdateSpaceQuota -
+func (c *Client) UpdateSpaceQuota(spaceQuotaGUID string, spaceQuote cfclient.SpaceQuotaRequest) (*cfclient.SpaceQuota, error) {
+	return nil, notSupported("space quotas")
+}
+
+//AssignSpaceQuota -
+func (c *Client) AssignSpaceQuota(quotaGUID, spaceGUID string) error {
+	return notSupported("space quotas")
+}
+
+//CreateSpaceQuota -
+func (c *Client) CreateSpaceQuota(spaceQuote cfclient.SpaceQuotaRequest) (*cfclient.SpaceQuota, error) {
+	return nil, notSupported("space quotas")
+}
+
+//GetSpaceQuotaByName -
+func (c *Client) GetSpaceQuotaByName(name string) (cfclient.SpaceQuota, error) {
+	return cfclient.SpaceQuota{}, notSupported("space quotas")
+}
+
+//ListOrgQuotas - Korifi has no quotas
+func (c *Client) ListOrgQuotas() ([]cfclient.OrgQuota, error) {
+	return []cfclient.OrgQuota{}, nil
+}
+
+//CreateOrgQuota -
+func (c *Client) CreateOrgQuota(orgQuote cfclient.OrgQuotaRequest) (*cfclient.OrgQuota, error) {
+	return nil, notSupported("org quotas")
+}
+
+//UpdateOrgQuota -
+func (c *Client) UpdateOrgQuota(orgQuotaGUID string, orgQuota cfclient.OrgQuotaRequest) (*cfclient.OrgQuota, error) {
+	return nil, notSupported("org quotas")
+}
+
+//GetOrgQuotaByName -
+func (c *Client) GetOrgQuotaByName(name string) (cfclient.OrgQuota, error) {
+	return cfclient.OrgQuota{}, notSupported("org quotas")
+}
+
+//ListSecGroups - Korifi has no application security groups
+func (c *Client) ListSecGroups() ([]cfclient.SecGroup, error) {
+	return []cfclient.SecGroup{}, nil
+}
+
+//ListSpaceSecGroups - Korifi has no application security groups
+func (c *Client) ListSpaceSecGroups(spaceGUID string) ([]cfclient.SecGroup, error) {
+	return []cfclient.SecGroup{}, nil
+}
+
+//CreateSecGroup -
+func (c *Client) CreateSecGroup(name string, rules []cfclient.SecGroupRule, spaceGuids []string) (*cfclient.SecGroup, error) {
+	return nil, notSupported("security groups")
+}
+
+//UpdateSecGroup -
+func (c *Client) UpdateSecGroup(guid, name string, rules []cfclient.SecGroupRule, spaceGuids []string) (*cfclient.SecGroup, error) {
+	return nil, notSupported("security groups")
+}
+
+//BindSecGroup -
+func (c *Client) BindSecGroup(secGUID, spaceGUID string) error {
+	return notSupported("security groups")
+}
+
+//BindStagingSecGroupToSpace -
+func (c *Client) BindStagingSecGroupToSpace(secGUID, spaceGUID string) error {
+	return notSupported("security groups")
+}
+
+//BindRunningSecGroup -
+func (c *Client) BindRunningSecGroup(secGUID string) error {
+	return notSupported("security groups")
+}
+
+//BindStagingSecGroup -
+func (c *Client) BindStagingSecGroup(secGUID string) error {
+	return notSupported("security groups")
+}
+
+//UnbindRunningSecGroup -
+func (c *Client) UnbindRunningSecGroup(secGUID string) error {
+	return notSupported("security groups")
+}
+
+//UnbindStagingSecGroup -
+func (c *Client) UnbindStagingSecGroup(secGUID string) error {
+	return notSupported("security groups")
+}
+
+//GetSecGroup -
+func (c *Client) GetSecGroup(guid string) (*cfclient.SecGroup, error) {
+	return nil, notSupported("security groups")
+}
+
+//ListIsolationSegments - Korifi has no isolation segments
+func (c *Client) ListIsolationSegments() ([]cfclient.IsolationSegment, error) {
+	return []cfclient.IsolationSegment{}, nil
+}
+
+//ListIsolationSegmentsByQuery - Korifi has no isolation segments
+func (c *Client) ListIsolationSegmentsByQuery(query url.Values) ([]cfclient.IsolationSegment, error) {
+	return []cfclient.IsolationSegment{}, nil
+}
+
+//CreateIsolationSegment -
+func (c *Client) CreateIsolationSegment(name string) (*cfclient.IsolationSegment, error) {
+	return nil, notSupported("isolation segments")
+}
+
+//DeleteIsolationSegmentByGUID -
+func (c *Client) DeleteIsolationSegmentByGUID(guid string) error {
+	return notSupported("isolation segments")
+}
+
+//GetIsolationSegmentByGUID -
+func (c *Client) GetIsolationSegmentByGUID(guid string) (*cfclient.IsolationSegment, error) {
+	return nil, notSupported("isolation segments")
+}
+
+//AddIsolationSegmentToOrg -
+func (c *Client) AddIsolationSegmentToOrg(isolationSegmentGUID, orgGUID string) error {
+	return notSupported("isolation segments")
+}
+
+//RemoveIsolationSegmentFromOrg -
+func (c *Client) RemoveIsolationSegmentFromOrg(isolationSegmentGUID, orgGUID string) error {
+	return notSupported("isolation segments")
+}
+
+//AddIsolationSegmentToSpace -
+func (c *Client) AddIsolationSegmentToSpace(isolationSegmentGUID, spaceGUID string) error {
+	return notSupported("isolation segments")
+}
+
+//RemoveIsolationSegmentFromSpace -
+func (c *Client) RemoveIsolationSegmentFromSpace(isolationSegmentGUID, spaceGUID string) error {
+	return notSupported("isolation segments")
+}
+
+//DefaultIsolationSegmentForOrg -
+func (c *Client) DefaultIsolationSegmentForOrg(orgGUID, isolationSegmentGUID string) error {
+	return notSupported("isolation segments")
+}
+
+//ResetDefaultIsolationSegmentForOrg -
+func (c *Client) ResetDefaultIsolationSegmentForOrg(orgGUID string) error {
+	return notSupported("isolation segments")
+}
+
+//IsolationSegmentForSpace -
+func (c *Client) IsolationSegmentForSpace(spaceGUID, isolationSegmentGUID string) error {
+	return notSupported("isolation segments")
+}
+
+//ResetIsolationSegmentForSpace -
+func (c *Client) ResetIsolationSegmentForSpace(spaceGUID string) error {
+	return notSupported("isolation segments")
+}
@@ -0,0 +1,145 @@
+package ldap
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pivotalservices/cf-mgmt/diskcache"
+)
+
+// defaultConcurrency is how many groups are resolved at a time when ldap.yml
+// does not set group_lookup_concurrency
+const defaultConcurrency = 4
+
+type cachedGroup struct {
+	userDNs []string
+	err     error
+}
+
+// Disk cache namespaces of the lookups
+const (
+	groupsNamespace    = "ldap-groups"
+	usersByDNNamespace = "ldap-users-by-dn"
+	usersByIDNamespace = "ldap-users-by-id"
+)
+
+// CachingManager resolves each group and user once per run, a group often
+// grants roles in many orgs and spaces.
+type CachingManager struct {
+	Manager
+	// Disk, when set, also keeps the lookups that succeeded for later runs
+	Disk      *diskcache.Cache
+	mutex     sync.Mutex
+	groups    map[string]cachedGroup
+	usersByDN map[string]*User
+	usersByID map[string]*User
+}
+
+//NewCachingManager - caches the groups and users looked up by the manager
+func NewCachingManager(manager Manager) *CachingManager {
+	return &CachingManager{
+		Manager:   manager,
+		groups:    make(map[string]cachedGroup),
+		usersByDN: make(map[string]*User),
+		usersByID: make(map[string]*User),
+	}
+}
+
+//GetUserDNs - returns the members of the group, looking the group up only the first time
+func (m *CachingManager) GetUserDNs(groupName string) ([]string, error) {
+	key := strings.ToLower(groupName)
+	m.mutex.Lock()
+	group, ok := m.groups[key]
+	m.mutex.Unlock()
+	if ok {
+		return group.userDNs, group.err
+	}
+	var userDNs []string
+	var err error
+	if !m.Disk.Get(groupsNamespace, key, &userDNs) {
+		if userDNs, err = m.Manager.GetUserDNs(groupName); err == nil {
+			m.Disk.Put(groupsNamespace, key, userDNs)
+		}
+	}
+	m.mutex.Lock()
+	m.groups[key] = cachedGroup{userDNs: userDNs, err: err}
+	m.mutex.Unlock()
+	return userDNs, err
+}
+
+//GetUserByDN - returns a copy of the user, which callers may change, looking it up only the first time
+func (m *CachingManager) GetUserByDN(userDN string) (*User, error) {
+	return m.cachedUser(m.usersByDN, usersByDNNamespace, userDN, m.Manager.GetUserByDN)
+}
+
+//GetUserByID - returns a copy of the user, which callers may change, looking it up only the first time
+func (m *CachingManager) GetUserByID(userID string) (*User, error) {
+	return m.cachedUser(m.usersByID, usersByIDNamespace, userID, m.Manager.GetUserByID)
+}
+
+func (m *CachingManager) cachedUser(users map[string]*User, namespace, name string, lookup func(string) (*User, error)) (*User, error) {
+	key := strings.ToLower(name)
+	m.mutex.Lock()
+	user, ok := users[key]
+	m.mutex.Unlock()
+	if !ok {
+		if !m.Disk.Get(namespace, key, &user) {
+			var err error
+			if user, err = lookup(name); err != nil {
+				return nil, err
+			}
+			m.Disk.Put(namespace, key, user)
+		}
+		m.mutex.Lock()
+		users[key] = user
+		m.mutex.Unlock()
+	}
+	// a user that was not found is cached as nil
+	if user == nil {
+		return nil, nil
+	}
+	userCopy := *user
+	return &userCopy, nil
+}
+
+//ResolveGroups - looks up the groups that are not cached yet, at most concurrency at a time, returning the
+//first error. Each distinct group is looked up once however many times it is listed.
+func (m *CachingManager) ResolveGroups(groupNames []string, concurrency int) error {
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	seen := make(map[string]bool)
+	var toResolve []string
+	m.mutex.Lock()
+	for _, groupName := range groupNames {
+		key := strings.ToLower(groupName)
+		if _, cached := m.groups[key]; cached || seen[key] {
+			continue
+		}
+		seen[key] = true
+		toResolve = append(toResolve, groupName)
+	}
+	m.mutex.Unlock()
+
+	names := make(chan string)
+	errs := make(chan error, len(toResolve))
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(toResolve); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for groupName := range names {
+				if _, err := m.GetUserDNs(groupName); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	for _, groupName := range toResolve {
+		names <- groupName
+	}
+	close(names)
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
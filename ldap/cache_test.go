@@ -0,0 +1,112 @@
+package ldap_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/diskcache"
+	"github.com/pivotalservices/cf-mgmt/ldap"
+	"github.com/pivotalservices/cf-mgmt/ldap/fakes"
+)
+
+var _ = Describe("given a caching ldap manager", func() {
+	var (
+		fakeManager    *fakes.FakeManager
+		cachingManager *ldap.CachingManager
+	)
+	BeforeEach(func() {
+		fakeManager = new(fakes.FakeManager)
+		cachingManager = ldap.NewCachingManager(fakeManager)
+		fakeManager.GetUserDNsStub = func(groupName string) ([]string, error) {
+			return []string{"cn=" + groupName + "-member"}, nil
+		}
+	})
+
+	It("looks up each distinct group once", func() {
+		Expect(cachingManager.ResolveGroups([]string{"devs", "ops", "Devs", "admins", "devs"}, 2)).Should(Succeed())
+		Expect(fakeManager.GetUserDNsCallCount()).Should(Equal(3))
+		userDNs, err := cachingManager.GetUserDNs("DEVS")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(userDNs).Should(Equal([]string{"cn=devs-member"}))
+		Expect(fakeManager.GetUserDNsCallCount()).Should(Equal(3))
+	})
+
+	It("returns the error of a group", func() {
+		fakeManager.GetUserDNsStub = func(groupName string) ([]string, error) {
+			if groupName == "ops" {
+				return nil, errors.New("ldap unavailable")
+			}
+			return nil, nil
+		}
+		Expect(cachingManager.ResolveGroups([]string{"devs", "ops"}, 0)).Should(MatchError("ldap unavailable"))
+	})
+
+	It("looks up each user once and returns copies", func() {
+		fakeManager.GetUserByDNReturns(&ldap.User{UserDN: "cn=jdoe", UserID: "jdoe"}, nil)
+		user, err := cachingManager.GetUserByDN("cn=jdoe")
+		Expect(err).ShouldNot(HaveOccurred())
+		user.UserName = "changed"
+		user, err = cachingManager.GetUserByDN("CN=jdoe")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(user.UserName).Should(BeEmpty())
+		Expect(fakeManager.GetUserByDNCallCount()).Should(Equal(1))
+	})
+
+	It("caches users that are not found", func() {
+		fakeManager.GetUserByIDReturns(nil, nil)
+		for i := 0; i < 2; i++ {
+			user, err := cachingManager.GetUserByID("missing")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(user).Should(BeNil())
+		}
+		Expect(fakeManager.GetUserByIDCallCount()).Should(Equal(1))
+	})
+
+	Context("with a disk cache", func() {
+		var dir string
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "ldapcache")
+			Expect(err).ShouldNot(HaveOccurred())
+			cachingManager.Disk = diskcache.New(dir, time.Minute)
+		})
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("reuses the lookups of an earlier run", func() {
+			fakeManager.GetUserByDNReturns(&ldap.User{UserDN: "cn=jdoe", UserID: "jdoe"}, nil)
+			Expect(cachingManager.ResolveGroups([]string{"devs"}, 1)).Should(Succeed())
+			_, err := cachingManager.GetUserByDN("cn=jdoe")
+			Expect(err).ShouldNot(HaveOccurred())
+
+			nextRun := ldap.NewCachingManager(fakeManager)
+			nextRun.Disk = diskcache.New(dir, time.Minute)
+			userDNs, err := nextRun.GetUserDNs("devs")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(userDNs).Should(Equal([]string{"cn=devs-member"}))
+			user, err := nextRun.GetUserByDN("cn=jdoe")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(user.UserID).Should(Equal("jdoe"))
+			Expect(fakeManager.GetUserDNsCallCount()).Should(Equal(1))
+			Expect(fakeManager.GetUserByDNCallCount()).Should(Equal(1))
+		})
+
+		It("does not keep failed lookups", func() {
+			fakeManager.GetUserDNsReturns(nil, errors.New("ldap unavailable"))
+			fakeManager.GetUserDNsStub = nil
+			_, err := cachingManager.GetUserDNs("devs")
+			Expect(err).Should(HaveOccurred())
+
+			nextRun := ldap.NewCachingManager(fakeManager)
+			nextRun.Disk = diskcache.New(dir, time.Minute)
+			_, err = nextRun.GetUserDNs("devs")
+			Expect(err).Should(HaveOccurred())
+			Expect(fakeManager.GetUserDNsCallCount()).Should(Equal(2))
+		})
+	})
+})
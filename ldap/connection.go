@@ -2,15 +2,28 @@ package ldap
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 
-	l "github.com/go-ldap/ldap"
+	l "github.com/go-ldap/ldap/v3"
+	"github.com/go-ldap/ldap/v3/gssapi"
 	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pkg/errors"
 	"github.com/xchapter7x/lo"
 )
 
+const defaultKerberosConfig = "/etc/krb5.conf"
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
 type Connection interface {
-	Close()
+	Close() error
 	Search(*l.SearchRequest) (*l.SearchResult, error)
 }
 
@@ -19,8 +32,19 @@ func CreateConnection(config *config.LdapConfig) (Connection, error) {
 	lo.G.Debug("Connecting to", ldapURL)
 	var connection *l.Conn
 	var err error
-	if config.TLS {
-		connection, err = l.DialTLS("tcp", ldapURL, &tls.Config{InsecureSkipVerify: true})
+	if config.TLS || config.StartTLS {
+		tlsConfig, err := TLSConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		if config.TLS {
+			connection, err = l.DialTLS("tcp", ldapURL, tlsConfig)
+		} else {
+			connection, err = dialStartTLS(ldapURL, tlsConfig)
+		}
+		if err != nil {
+			return nil, err
+		}
 	} else {
 		connection, err = l.Dial("tcp", ldapURL)
 	}
@@ -28,11 +52,95 @@ func CreateConnection(config *config.LdapConfig) (Connection, error) {
 		return nil, err
 	}
 	if connection != nil {
-		if err = connection.Bind(config.BindDN, config.BindPassword); err != nil {
+		if err = bind(connection, config); err != nil {
 			connection.Close()
-			return nil, fmt.Errorf("cannot bind with %s: %v", config.BindDN, err)
+			return nil, err
 		}
 	}
 	return connection, err
 
 }
+
+func bind(connection *l.Conn, config *config.LdapConfig) error {
+	if config.KerberosKeytab != "" {
+		return kerberosBind(connection, config)
+	}
+	// with a client certificate the directory can authenticate the
+	// connection during the handshake, so there is nothing to bind with
+	if config.BindDN == "" && config.TLSClientCert != "" {
+		return nil
+	}
+	if err := connection.Bind(config.BindDN, config.BindPassword); err != nil {
+		return fmt.Errorf("cannot bind with %s: %v", config.BindDN, err)
+	}
+	return nil
+}
+
+// kerberosBind authenticates with the keytab of a service account using a
+// GSSAPI SASL bind, for directories that do not issue bind passwords.
+func kerberosBind(connection *l.Conn, config *config.LdapConfig) error {
+	krb5Config := config.KerberosConfig
+	if krb5Config == "" {
+		krb5Config = defaultKerberosConfig
+	}
+	spn := config.KerberosSPN
+	if spn == "" {
+		spn = fmt.Sprintf("ldap/%s", config.LdapHost)
+	}
+	lo.G.Debugf("Binding as %s with keytab %s for %s", config.KerberosPrincipal, config.KerberosKeytab, spn)
+	client, err := gssapi.NewClientWithKeytab(config.KerberosPrincipal, config.KerberosRealm, config.KerberosKeytab, krb5Config)
+	if err != nil {
+		return errors.Wrap(err, "unable to create kerberos client")
+	}
+	defer client.Close()
+	if err := connection.GSSAPIBind(client, spn, ""); err != nil {
+		return fmt.Errorf("cannot bind with kerberos principal %s: %v", config.KerberosPrincipal, err)
+	}
+	return nil
+}
+
+func dialStartTLS(ldapURL string, tlsConfig *tls.Config) (*l.Conn, error) {
+	connection, err := l.Dial("tcp", ldapURL)
+	if err != nil {
+		return nil, err
+	}
+	if err = connection.StartTLS(tlsConfig); err != nil {
+		connection.Close()
+		return nil, errors.Wrapf(err, "cannot start tls with %s", ldapURL)
+	}
+	return connection, nil
+}
+
+//TLSConfig - tls settings for ldaps and StartTLS connections. The server certificate is
+//only verified when a CA is configured, in which case it must be issued by that CA
+func TLSConfig(config *config.LdapConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	if config.TLSMinVersion != "" {
+		version, ok := tlsVersions[config.TLSMinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported tls_min_version [%s], must be one of 1.0, 1.1, 1.2, 1.3", config.TLSMinVersion)
+		}
+		tlsConfig.MinVersion = version
+	}
+	if config.TLSCACert != "" {
+		pem, err := ioutil.ReadFile(config.TLSCACert)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read tls_ca_cert")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in tls_ca_cert %s", config.TLSCACert)
+		}
+		tlsConfig.RootCAs = pool
+		tlsConfig.ServerName = config.LdapHost
+		tlsConfig.InsecureSkipVerify = false
+	}
+	if config.TLSClientCert != "" || config.TLSClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(config.TLSClientCert, config.TLSClientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to load tls_client_cert and tls_client_key")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
@@ -4,14 +4,17 @@ package fakes
 import (
 	"sync"
 
-	ldapgo_ldap "github.com/go-ldap/ldap"
+	ldapgo_ldap "github.com/go-ldap/ldap/v3"
 	"github.com/pivotalservices/cf-mgmt/ldap"
 )
 
 type FakeConnection struct {
-	CloseStub         func()
-	closeMutex        sync.RWMutex
-	closeArgsForCall  []struct{}
+	CloseStub        func() error
+	closeMutex       sync.RWMutex
+	closeArgsForCall []struct{}
+	closeReturns     struct {
+		result1 error
+	}
 	SearchStub        func(*ldapgo_ldap.SearchRequest) (*ldapgo_ldap.SearchResult, error)
 	searchMutex       sync.RWMutex
 	searchArgsForCall []struct {
@@ -25,13 +28,15 @@ type FakeConnection struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeConnection) Close() {
+func (fake *FakeConnection) Close() error {
 	fake.closeMutex.Lock()
 	fake.closeArgsForCall = append(fake.closeArgsForCall, struct{}{})
 	fake.recordInvocation("Close", []interface{}{})
 	fake.closeMutex.Unlock()
 	if fake.CloseStub != nil {
-		fake.CloseStub()
+		return fake.CloseStub()
+	} else {
+		return fake.closeReturns.result1
 	}
 }
 
@@ -41,6 +46,13 @@ func (fake *FakeConnection) CloseCallCount() int {
 	return len(fake.closeArgsForCall)
 }
 
+func (fake *FakeConnection) CloseReturns(result1 error) {
+	fake.CloseStub = nil
+	fake.closeReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeConnection) Search(arg1 *ldapgo_ldap.SearchRequest) (*ldapgo_ldap.SearchResult, error) {
 	fake.searchMutex.Lock()
 	fake.searchArgsForCall = append(fake.searchArgsForCall, struct {
@@ -6,8 +6,10 @@ import (
 	"regexp"
 	"strings"
 
-	l "github.com/go-ldap/ldap"
+	l "github.com/go-ldap/ldap/v3"
 	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/redact"
+	"github.com/pivotalservices/cf-mgmt/stats"
 	"github.com/xchapter7x/lo"
 )
 
@@ -52,6 +54,7 @@ func (m *DefaultManager) GetUserDNs(groupName string) ([]string, error) {
 		filter,
 		attributes,
 		nil)
+	stats.Call(stats.LDAP)
 	sr, err := m.Connection.Search(search)
 	if err != nil {
 		lo.G.Error(err)
@@ -106,6 +109,7 @@ func (m *DefaultManager) searchUser(filter, searchBase, userID string) (*User, e
 		attributes,
 		nil)
 
+	stats.Call(stats.LDAP)
 	sr, err := m.Connection.Search(search)
 	if err != nil {
 		lo.G.Error(err)
@@ -115,14 +119,22 @@ func (m *DefaultManager) searchUser(filter, searchBase, userID string) (*User, e
 	if (len(sr.Entries)) == 1 {
 		entry := sr.Entries[0]
 		user := &User{
-			UserDN: entry.DN,
-			Email:  entry.GetAttributeValue(m.Config.UserMailAttribute),
+			UserDN:     entry.DN,
+			Email:      entry.GetAttributeValue(m.Config.UserMailAttribute),
+			Attributes: make(map[string]string),
+		}
+		// attribute names are case insensitive in ldap
+		for _, attribute := range entry.Attributes {
+			if len(attribute.Values) > 0 {
+				user.Attributes[strings.ToLower(attribute.Name)] = attribute.Values[0]
+			}
 		}
 		if userID != "" {
 			user.UserID = userID
 		} else {
 			user.UserID = entry.GetAttributeValue(m.Config.UserNameAttribute)
 		}
+		redact.UserNames(user.UserID, user.Email)
 		return user, nil
 	}
 	lo.G.Errorf("Found %d number of entries for filter %s", len(sr.Entries), filter)
@@ -1,9 +1,10 @@
 package ldap_test
 
 import (
+	"crypto/tls"
 	"errors"
 
-	l "github.com/go-ldap/ldap"
+	l "github.com/go-ldap/ldap/v3"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pivotalservices/cf-mgmt/config"
@@ -231,4 +232,33 @@ var _ = Describe("Ldap", func() {
 			})
 		})
 	})
+
+	Describe("TLSConfig()", func() {
+		It("should skip verification when no ca is configured", func() {
+			tlsConfig, err := ldap.TLSConfig(&config.LdapConfig{LdapHost: "ldap.example.com"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(tlsConfig.InsecureSkipVerify).Should(BeTrue())
+		})
+
+		It("should set the minimum tls version", func() {
+			tlsConfig, err := ldap.TLSConfig(&config.LdapConfig{TLSMinVersion: "1.2"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(tlsConfig.MinVersion).Should(Equal(uint16(tls.VersionTLS12)))
+		})
+
+		It("should return error for an unsupported tls version", func() {
+			_, err := ldap.TLSConfig(&config.LdapConfig{TLSMinVersion: "2.0"})
+			Expect(err).Should(MatchError("unsupported tls_min_version [2.0], must be one of 1.0, 1.1, 1.2, 1.3"))
+		})
+
+		It("should return error when the ca has no certificates", func() {
+			_, err := ldap.TLSConfig(&config.LdapConfig{TLSCACert: "./ldap_test.go"})
+			Expect(err).Should(MatchError("no certificates found in tls_ca_cert ./ldap_test.go"))
+		})
+
+		It("should return error when the client certificate cannot be loaded", func() {
+			_, err := ldap.TLSConfig(&config.LdapConfig{TLSClientCert: "./missing.crt", TLSClientKey: "./missing.key"})
+			Expect(err).Should(HaveOccurred())
+		})
+	})
 })
@@ -23,4 +23,8 @@ type User struct {
 	UserDN string
 	UserID string
 	Email  string
+	// UserName is the cf username selected by a user name mapping, when empty it is derived from the origin
+	UserName string
+	// Attributes holds the first value of each attribute of the ldap entry keyed by lowercase name
+	Attributes map[string]string
 }
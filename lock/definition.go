@@ -0,0 +1,4 @@
+package lock
+
+//go:generate counterfeiter -o fakes/fake_mgr.go types.go Manager
+//go:generate counterfeiter -o fakes/fake_uaa_client.go types.go UAAClient
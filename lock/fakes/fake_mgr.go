@@ -0,0 +1,132 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+
+	"github.com/pivotalservices/cf-mgmt/lock"
+)
+
+type FakeManager struct {
+	AcquireStub        func() error
+	acquireMutex       sync.RWMutex
+	acquireArgsForCall []struct{}
+	acquireReturns     struct {
+		result1 error
+	}
+	RenewStub        func() error
+	renewMutex       sync.RWMutex
+	renewArgsForCall []struct{}
+	renewReturns     struct {
+		result1 error
+	}
+	ReleaseStub        func() error
+	releaseMutex       sync.RWMutex
+	releaseArgsForCall []struct{}
+	releaseReturns     struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeManager) Acquire() error {
+	fake.acquireMutex.Lock()
+	fake.acquireArgsForCall = append(fake.acquireArgsForCall, struct{}{})
+	fake.recordInvocation("Acquire", []interface{}{})
+	fake.acquireMutex.Unlock()
+	if fake.AcquireStub != nil {
+		return fake.AcquireStub()
+	} else {
+		return fake.acquireReturns.result1
+	}
+}
+
+func (fake *FakeManager) AcquireCallCount() int {
+	fake.acquireMutex.RLock()
+	defer fake.acquireMutex.RUnlock()
+	return len(fake.acquireArgsForCall)
+}
+
+func (fake *FakeManager) AcquireReturns(result1 error) {
+	fake.AcquireStub = nil
+	fake.acquireReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) Renew() error {
+	fake.renewMutex.Lock()
+	fake.renewArgsForCall = append(fake.renewArgsForCall, struct{}{})
+	fake.recordInvocation("Renew", []interface{}{})
+	fake.renewMutex.Unlock()
+	if fake.RenewStub != nil {
+		return fake.RenewStub()
+	} else {
+		return fake.renewReturns.result1
+	}
+}
+
+func (fake *FakeManager) RenewCallCount() int {
+	fake.renewMutex.RLock()
+	defer fake.renewMutex.RUnlock()
+	return len(fake.renewArgsForCall)
+}
+
+func (fake *FakeManager) RenewReturns(result1 error) {
+	fake.RenewStub = nil
+	fake.renewReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) Release() error {
+	fake.releaseMutex.Lock()
+	fake.releaseArgsForCall = append(fake.releaseArgsForCall, struct{}{})
+	fake.recordInvocation("Release", []interface{}{})
+	fake.releaseMutex.Unlock()
+	if fake.ReleaseStub != nil {
+		return fake.ReleaseStub()
+	} else {
+		return fake.releaseReturns.result1
+	}
+}
+
+func (fake *FakeManager) ReleaseCallCount() int {
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	return len(fake.releaseArgsForCall)
+}
+
+func (fake *FakeManager) ReleaseReturns(result1 error) {
+	fake.ReleaseStub = nil
+	fake.releaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.acquireMutex.RLock()
+	defer fake.acquireMutex.RUnlock()
+	fake.renewMutex.RLock()
+	defer fake.renewMutex.RUnlock()
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeManager) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ lock.Manager = new(FakeManager)
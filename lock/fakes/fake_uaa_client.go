@@ -0,0 +1,220 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+
+	go_uaa "github.com/cloudfoundry-community/go-uaa"
+	"github.com/pivotalservices/cf-mgmt/lock"
+)
+
+type FakeUAAClient struct {
+	ListAllGroupsStub        func(filter string, sortBy string, attributes string, sortOrder go_uaa.SortOrder) ([]go_uaa.Group, error)
+	listAllGroupsMutex       sync.RWMutex
+	listAllGroupsArgsForCall []struct {
+		filter     string
+		sortBy     string
+		attributes string
+		sortOrder  go_uaa.SortOrder
+	}
+	listAllGroupsReturns struct {
+		result1 []go_uaa.Group
+		result2 error
+	}
+	CreateGroupStub        func(group go_uaa.Group) (*go_uaa.Group, error)
+	createGroupMutex       sync.RWMutex
+	createGroupArgsForCall []struct {
+		group go_uaa.Group
+	}
+	createGroupReturns struct {
+		result1 *go_uaa.Group
+		result2 error
+	}
+	DeleteGroupStub        func(groupID string) (*go_uaa.Group, error)
+	deleteGroupMutex       sync.RWMutex
+	deleteGroupArgsForCall []struct {
+		groupID string
+	}
+	deleteGroupReturns struct {
+		result1 *go_uaa.Group
+		result2 error
+	}
+	UpdateGroupStub        func(group go_uaa.Group) (*go_uaa.Group, error)
+	updateGroupMutex       sync.RWMutex
+	updateGroupArgsForCall []struct {
+		group go_uaa.Group
+	}
+	updateGroupReturns struct {
+		result1 *go_uaa.Group
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeUAAClient) ListAllGroups(filter string, sortBy string, attributes string, sortOrder go_uaa.SortOrder) ([]go_uaa.Group, error) {
+	fake.listAllGroupsMutex.Lock()
+	fake.listAllGroupsArgsForCall = append(fake.listAllGroupsArgsForCall, struct {
+		filter     string
+		sortBy     string
+		attributes string
+		sortOrder  go_uaa.SortOrder
+	}{filter, sortBy, attributes, sortOrder})
+	fake.recordInvocation("ListAllGroups", []interface{}{filter, sortBy, attributes, sortOrder})
+	fake.listAllGroupsMutex.Unlock()
+	if fake.ListAllGroupsStub != nil {
+		return fake.ListAllGroupsStub(filter, sortBy, attributes, sortOrder)
+	} else {
+		return fake.listAllGroupsReturns.result1, fake.listAllGroupsReturns.result2
+	}
+}
+
+func (fake *FakeUAAClient) ListAllGroupsCallCount() int {
+	fake.listAllGroupsMutex.RLock()
+	defer fake.listAllGroupsMutex.RUnlock()
+	return len(fake.listAllGroupsArgsForCall)
+}
+
+func (fake *FakeUAAClient) ListAllGroupsArgsForCall(i int) (string, string, string, go_uaa.SortOrder) {
+	fake.listAllGroupsMutex.RLock()
+	defer fake.listAllGroupsMutex.RUnlock()
+	return fake.listAllGroupsArgsForCall[i].filter, fake.listAllGroupsArgsForCall[i].sortBy, fake.listAllGroupsArgsForCall[i].attributes, fake.listAllGroupsArgsForCall[i].sortOrder
+}
+
+func (fake *FakeUAAClient) ListAllGroupsReturns(result1 []go_uaa.Group, result2 error) {
+	fake.ListAllGroupsStub = nil
+	fake.listAllGroupsReturns = struct {
+		result1 []go_uaa.Group
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeUAAClient) CreateGroup(group go_uaa.Group) (*go_uaa.Group, error) {
+	fake.createGroupMutex.Lock()
+	fake.createGroupArgsForCall = append(fake.createGroupArgsForCall, struct {
+		group go_uaa.Group
+	}{group})
+	fake.recordInvocation("CreateGroup", []interface{}{group})
+	fake.createGroupMutex.Unlock()
+	if fake.CreateGroupStub != nil {
+		return fake.CreateGroupStub(group)
+	} else {
+		return fake.createGroupReturns.result1, fake.createGroupReturns.result2
+	}
+}
+
+func (fake *FakeUAAClient) CreateGroupCallCount() int {
+	fake.createGroupMutex.RLock()
+	defer fake.createGroupMutex.RUnlock()
+	return len(fake.createGroupArgsForCall)
+}
+
+func (fake *FakeUAAClient) CreateGroupArgsForCall(i int) go_uaa.Group {
+	fake.createGroupMutex.RLock()
+	defer fake.createGroupMutex.RUnlock()
+	return fake.createGroupArgsForCall[i].group
+}
+
+func (fake *FakeUAAClient) CreateGroupReturns(result1 *go_uaa.Group, result2 error) {
+	fake.CreateGroupStub = nil
+	fake.createGroupReturns = struct {
+		result1 *go_uaa.Group
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeUAAClient) DeleteGroup(groupID string) (*go_uaa.Group, error) {
+	fake.deleteGroupMutex.Lock()
+	fake.deleteGroupArgsForCall = append(fake.deleteGroupArgsForCall, struct {
+		groupID string
+	}{groupID})
+	fake.recordInvocation("DeleteGroup", []interface{}{groupID})
+	fake.deleteGroupMutex.Unlock()
+	if fake.DeleteGroupStub != nil {
+		return fake.DeleteGroupStub(groupID)
+	} else {
+		return fake.deleteGroupReturns.result1, fake.deleteGroupReturns.result2
+	}
+}
+
+func (fake *FakeUAAClient) DeleteGroupCallCount() int {
+	fake.deleteGroupMutex.RLock()
+	defer fake.deleteGroupMutex.RUnlock()
+	return len(fake.deleteGroupArgsForCall)
+}
+
+func (fake *FakeUAAClient) DeleteGroupArgsForCall(i int) string {
+	fake.deleteGroupMutex.RLock()
+	defer fake.deleteGroupMutex.RUnlock()
+	return fake.deleteGroupArgsForCall[i].groupID
+}
+
+func (fake *FakeUAAClient) DeleteGroupReturns(result1 *go_uaa.Group, result2 error) {
+	fake.DeleteGroupStub = nil
+	fake.deleteGroupReturns = struct {
+		result1 *go_uaa.Group
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeUAAClient) UpdateGroup(group go_uaa.Group) (*go_uaa.Group, error) {
+	fake.updateGroupMutex.Lock()
+	fake.updateGroupArgsForCall = append(fake.updateGroupArgsForCall, struct {
+		group go_uaa.Group
+	}{group})
+	fake.recordInvocation("UpdateGroup", []interface{}{group})
+	fake.updateGroupMutex.Unlock()
+	if fake.UpdateGroupStub != nil {
+		return fake.UpdateGroupStub(group)
+	} else {
+		return fake.updateGroupReturns.result1, fake.updateGroupReturns.result2
+	}
+}
+
+func (fake *FakeUAAClient) UpdateGroupCallCount() int {
+	fake.updateGroupMutex.RLock()
+	defer fake.updateGroupMutex.RUnlock()
+	return len(fake.updateGroupArgsForCall)
+}
+
+func (fake *FakeUAAClient) UpdateGroupArgsForCall(i int) go_uaa.Group {
+	fake.updateGroupMutex.RLock()
+	defer fake.updateGroupMutex.RUnlock()
+	return fake.updateGroupArgsForCall[i].group
+}
+
+func (fake *FakeUAAClient) UpdateGroupReturns(result1 *go_uaa.Group, result2 error) {
+	fake.UpdateGroupStub = nil
+	fake.updateGroupReturns = struct {
+		result1 *go_uaa.Group
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeUAAClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.listAllGroupsMutex.RLock()
+	defer fake.listAllGroupsMutex.RUnlock()
+	fake.createGroupMutex.RLock()
+	defer fake.createGroupMutex.RUnlock()
+	fake.deleteGroupMutex.RLock()
+	defer fake.deleteGroupMutex.RUnlock()
+	fake.updateGroupMutex.RLock()
+	defer fake.updateGroupMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeUAAClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ lock.UAAClient = new(FakeUAAClient)
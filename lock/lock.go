@@ -0,0 +1,177 @@
+// Package lock provides an advisory lock, stored as a UAA group, that keeps
+// concurrent cf-mgmt runs against the same foundation from interleaving.
+package lock
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	uaaclient "github.com/cloudfoundry-community/go-uaa"
+	"github.com/pivotalservices/cf-mgmt/httpclient"
+	"github.com/pivotalservices/cf-mgmt/uaa"
+	"github.com/pkg/errors"
+	"github.com/xchapter7x/lo"
+)
+
+// GroupName is the display name of the UAA group that marks the lock as held.
+const GroupName = "cf-mgmt.lock"
+
+// LeaderGroupName is the display name of the UAA group of the lease held by
+// the replica of cf-mgmt watch that reconciles.
+const LeaderGroupName = "cf-mgmt.leader"
+
+type lockInfo struct {
+	Holder  string    `json:"holder"`
+	Expires time.Time `json:"expires"`
+}
+
+//NewManager -
+func NewManager(sysDomain, clientID, clientSecret, holder string, ttl time.Duration, peek bool) (Manager, error) {
+	client, err := uaa.NewClient(sysDomain, clientID, clientSecret, httpclient.Transport())
+	if err != nil {
+		return nil, err
+	}
+	if holder == "" {
+		holder = DefaultHolder()
+	}
+	return &DefaultManager{
+		Client: client,
+		Holder: holder,
+		TTL:    ttl,
+		Now:    time.Now,
+		Peek:   peek,
+	}, nil
+}
+
+//NewLeaseManager - a lock on the leader group, renewed by the leading replica while it reconciles
+func NewLeaseManager(sysDomain, clientID, clientSecret, holder string, ttl time.Duration) (Manager, error) {
+	manager, err := NewManager(sysDomain, clientID, clientSecret, holder, ttl, false)
+	if err != nil {
+		return nil, err
+	}
+	manager.(*DefaultManager).Group = LeaderGroupName
+	return manager, nil
+}
+
+// DefaultHolder identifies the current process as host/pid.
+func DefaultHolder() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s/%d", hostname, os.Getpid())
+}
+
+//DefaultManager -
+type DefaultManager struct {
+	Client UAAClient
+	Holder string
+	TTL    time.Duration
+	Now    func() time.Time
+	Peek   bool
+	// Group is the display name of the lock group, GroupName when empty
+	Group   string
+	groupID string
+}
+
+func (m *DefaultManager) group() string {
+	if m.Group == "" {
+		return GroupName
+	}
+	return m.Group
+}
+
+//Acquire - creates the lock group, failing when another run holds an unexpired lock
+func (m *DefaultManager) Acquire() error {
+	groups, err := m.Client.ListAllGroups(fmt.Sprintf(`displayName eq "%s"`, m.group()), "", "", "")
+	if err != nil {
+		return errors.Wrap(err, "unable to read cf-mgmt lock")
+	}
+	for _, group := range groups {
+		info := lockInfo{}
+		if err := json.Unmarshal([]byte(group.Description), &info); err == nil && m.Now().Before(info.Expires) {
+			return fmt.Errorf("cf-mgmt lock is held by [%s] until %s", info.Holder, info.Expires.Format(time.RFC3339))
+		}
+		if m.Peek {
+			lo.G.Infof("[dry-run]: removing expired cf-mgmt lock held by [%s]", info.Holder)
+			continue
+		}
+		lo.G.Warningf("removing expired cf-mgmt lock held by [%s]", info.Holder)
+		if _, err := m.Client.DeleteGroup(group.ID); err != nil {
+			return errors.Wrap(err, "unable to remove expired cf-mgmt lock")
+		}
+	}
+
+	if m.Peek {
+		lo.G.Infof("[dry-run]: acquiring cf-mgmt lock for [%s]", m.Holder)
+		return nil
+	}
+	lo.G.Infof("acquiring cf-mgmt lock for [%s]", m.Holder)
+	return m.create()
+}
+
+// create adds the lock group, which fails when another holder created it
+// first as uaa group names are unique
+func (m *DefaultManager) create() error {
+	description, err := json.Marshal(lockInfo{Holder: m.Holder, Expires: m.Now().Add(m.TTL)})
+	if err != nil {
+		return err
+	}
+	group, err := m.Client.CreateGroup(uaaclient.Group{
+		DisplayName: m.group(),
+		Description: string(description),
+	})
+	if err != nil {
+		return errors.Wrap(err, "unable to acquire cf-mgmt lock")
+	}
+	m.groupID = group.ID
+	return nil
+}
+
+//Renew - extends the lock held by Acquire by the ttl, failing when it was lost to another holder. The group is
+//updated in place with the version it was read at, so the update fails when another holder changed it since.
+func (m *DefaultManager) Renew() error {
+	if m.groupID == "" {
+		return errors.New("cf-mgmt lock is not held")
+	}
+	groups, err := m.Client.ListAllGroups(fmt.Sprintf(`displayName eq "%s"`, m.group()), "", "", "")
+	if err != nil {
+		return errors.Wrap(err, "unable to read cf-mgmt lock")
+	}
+	var held *uaaclient.Group
+	for i, group := range groups {
+		info := lockInfo{}
+		if group.ID == m.groupID && json.Unmarshal([]byte(group.Description), &info) == nil && info.Holder == m.Holder {
+			held = &groups[i]
+		}
+	}
+	if held == nil {
+		m.groupID = ""
+		return fmt.Errorf("cf-mgmt lock of [%s] was lost", m.Holder)
+	}
+	description, err := json.Marshal(lockInfo{Holder: m.Holder, Expires: m.Now().Add(m.TTL)})
+	if err != nil {
+		return err
+	}
+	lo.G.Debugf("renewing cf-mgmt lock for [%s]", m.Holder)
+	held.Description = string(description)
+	if _, err := m.Client.UpdateGroup(*held); err != nil {
+		return errors.Wrapf(err, "unable to renew cf-mgmt lock of [%s], it may have been taken by another holder", m.Holder)
+	}
+	return nil
+}
+
+//Release - removes the lock group created by Acquire
+func (m *DefaultManager) Release() error {
+	if m.groupID == "" {
+		return nil
+	}
+	lo.G.Infof("releasing cf-mgmt lock for [%s]", m.Holder)
+	if _, err := m.Client.DeleteGroup(m.groupID); err != nil {
+		return errors.Wrap(err, "unable to release cf-mgmt lock")
+	}
+	m.groupID = ""
+	return nil
+}
@@ -0,0 +1,148 @@
+package lock_test
+
+import (
+	"errors"
+	"time"
+
+	uaaclient "github.com/cloudfoundry-community/go-uaa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/lock"
+	"github.com/pivotalservices/cf-mgmt/lock/fakes"
+)
+
+var _ = Describe("given lock manager", func() {
+	var (
+		client  *fakes.FakeUAAClient
+		manager *lock.DefaultManager
+		now     time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)
+		client = new(fakes.FakeUAAClient)
+		manager = &lock.DefaultManager{
+			Client: client,
+			Holder: "pipeline-a",
+			TTL:    time.Hour,
+			Now:    func() time.Time { return now },
+		}
+	})
+
+	Context("Acquire", func() {
+		It("creates the lock group when not held", func() {
+			client.CreateGroupReturns(&uaaclient.Group{ID: "lock-guid"}, nil)
+			err := manager.Acquire()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(client.CreateGroupCallCount()).Should(Equal(1))
+			group := client.CreateGroupArgsForCall(0)
+			Expect(group.DisplayName).Should(Equal(lock.GroupName))
+			Expect(group.Description).Should(Equal(`{"holder":"pipeline-a","expires":"2018-10-01T13:00:00Z"}`))
+		})
+
+		It("errors when another run holds the lock", func() {
+			client.ListAllGroupsReturns([]uaaclient.Group{
+				uaaclient.Group{ID: "lock-guid", Description: `{"holder":"pipeline-b","expires":"2018-10-01T12:30:00Z"}`},
+			}, nil)
+			err := manager.Acquire()
+			Expect(err).Should(MatchError("cf-mgmt lock is held by [pipeline-b] until 2018-10-01T12:30:00Z"))
+			Expect(client.CreateGroupCallCount()).Should(Equal(0))
+			Expect(client.DeleteGroupCallCount()).Should(Equal(0))
+		})
+
+		It("removes an expired lock before acquiring", func() {
+			client.ListAllGroupsReturns([]uaaclient.Group{
+				uaaclient.Group{ID: "old-guid", Description: `{"holder":"pipeline-b","expires":"2018-10-01T11:30:00Z"}`},
+			}, nil)
+			client.CreateGroupReturns(&uaaclient.Group{ID: "lock-guid"}, nil)
+			err := manager.Acquire()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(client.DeleteGroupCallCount()).Should(Equal(1))
+			Expect(client.DeleteGroupArgsForCall(0)).Should(Equal("old-guid"))
+			Expect(client.CreateGroupCallCount()).Should(Equal(1))
+		})
+
+		It("errors when the lock cannot be created", func() {
+			client.CreateGroupReturns(nil, errors.New("409 conflict"))
+			err := manager.Acquire()
+			Expect(err).Should(HaveOccurred())
+			Expect(manager.Release()).ShouldNot(HaveOccurred())
+			Expect(client.DeleteGroupCallCount()).Should(Equal(0))
+		})
+
+		It("does not create the lock in peek mode", func() {
+			manager.Peek = true
+			err := manager.Acquire()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(client.CreateGroupCallCount()).Should(Equal(0))
+		})
+	})
+
+	Context("Release", func() {
+		It("deletes the acquired lock group", func() {
+			client.CreateGroupReturns(&uaaclient.Group{ID: "lock-guid"}, nil)
+			Expect(manager.Acquire()).ShouldNot(HaveOccurred())
+			Expect(manager.Release()).ShouldNot(HaveOccurred())
+			Expect(client.DeleteGroupCallCount()).Should(Equal(1))
+			Expect(client.DeleteGroupArgsForCall(0)).Should(Equal("lock-guid"))
+		})
+
+		It("does nothing when the lock was not acquired", func() {
+			Expect(manager.Release()).ShouldNot(HaveOccurred())
+			Expect(client.DeleteGroupCallCount()).Should(Equal(0))
+		})
+	})
+
+	Context("Renew", func() {
+		var held uaaclient.Group
+		BeforeEach(func() {
+			client.CreateGroupReturns(&uaaclient.Group{ID: "lock-guid"}, nil)
+			Expect(manager.Acquire()).ShouldNot(HaveOccurred())
+			now = now.Add(30 * time.Minute)
+			held = uaaclient.Group{
+				ID:          "lock-guid",
+				Meta:        &uaaclient.Meta{Version: 4},
+				DisplayName: lock.GroupName,
+				Description: `{"holder":"pipeline-a","expires":"2018-10-01T13:00:00Z"}`,
+			}
+		})
+
+		It("updates the held lock group in place to expire a ttl from now", func() {
+			client.ListAllGroupsReturns([]uaaclient.Group{held}, nil)
+			Expect(manager.Renew()).ShouldNot(HaveOccurred())
+			Expect(client.DeleteGroupCallCount()).Should(Equal(0))
+			Expect(client.CreateGroupCallCount()).Should(Equal(1))
+			Expect(client.UpdateGroupCallCount()).Should(Equal(1))
+			group := client.UpdateGroupArgsForCall(0)
+			Expect(group.ID).Should(Equal("lock-guid"))
+			Expect(group.Meta.Version).Should(Equal(4))
+			Expect(group.Description).Should(Equal(`{"holder":"pipeline-a","expires":"2018-10-01T13:30:00Z"}`))
+			Expect(manager.Release()).ShouldNot(HaveOccurred())
+			Expect(client.DeleteGroupArgsForCall(0)).Should(Equal("lock-guid"))
+		})
+
+		It("errors when another holder took the lock", func() {
+			client.ListAllGroupsReturns([]uaaclient.Group{uaaclient.Group{ID: "other-guid"}}, nil)
+			Expect(manager.Renew()).Should(MatchError("cf-mgmt lock of [pipeline-a] was lost"))
+			Expect(client.UpdateGroupCallCount()).Should(Equal(0))
+			Expect(manager.Release()).ShouldNot(HaveOccurred())
+			Expect(client.DeleteGroupCallCount()).Should(Equal(0))
+		})
+
+		It("errors when another holder changed the lock since it was read", func() {
+			client.ListAllGroupsReturns([]uaaclient.Group{held}, nil)
+			client.UpdateGroupReturns(nil, errors.New("412 precondition failed"))
+			Expect(manager.Renew()).Should(HaveOccurred())
+			Expect(client.CreateGroupCallCount()).Should(Equal(1))
+			Expect(client.DeleteGroupCallCount()).Should(Equal(0))
+		})
+
+		It("uses the group of the lease", func() {
+			manager.Group = lock.LeaderGroupName
+			client.ListAllGroupsReturns([]uaaclient.Group{held}, nil)
+			Expect(manager.Renew()).ShouldNot(HaveOccurred())
+			filter, _, _, _ := client.ListAllGroupsArgsForCall(1)
+			Expect(filter).Should(Equal(`displayName eq "cf-mgmt.leader"`))
+		})
+	})
+})
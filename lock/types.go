@@ -0,0 +1,19 @@
+package lock
+
+import (
+	uaaclient "github.com/cloudfoundry-community/go-uaa"
+)
+
+//Manager -
+type Manager interface {
+	Acquire() error
+	Renew() error
+	Release() error
+}
+
+type UAAClient interface {
+	ListAllGroups(filter string, sortBy string, attributes string, sortOrder uaaclient.SortOrder) ([]uaaclient.Group, error)
+	CreateGroup(group uaaclient.Group) (*uaaclient.Group, error)
+	UpdateGroup(group uaaclient.Group) (*uaaclient.Group, error)
+	DeleteGroup(groupID string) (*uaaclient.Group, error)
+}
@@ -0,0 +1,247 @@
+package organization
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// label selector operators, as documented for the cloud controller v3 api
+const (
+	labelExists    = "exists"
+	labelNotExists = "!exists"
+	labelEquals    = "="
+	labelNotEquals = "!="
+	labelIn        = "in"
+	labelNotIn     = "notin"
+)
+
+var setRequirement = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+
+// labelRequirement is one comma separated requirement of a selector
+type labelRequirement struct {
+	key      string
+	operator string
+	values   []string
+}
+
+//LabelSelector - a cloud controller label selector such as team=payments,env in (dev,test)
+type LabelSelector struct {
+	requirements []labelRequirement
+}
+
+//ParseLabelSelector - parses a label selector in the cloud controller syntax.
+//The shorthand label=team:payments is read as team=payments.
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+	result := LabelSelector{}
+	selector = strings.TrimSpace(selector)
+	if strings.HasPrefix(selector, "label=") && strings.Contains(selector, ":") {
+		selector = strings.TrimPrefix(selector, "label=")
+	}
+	for _, requirement := range splitRequirements(selector) {
+		parsed, err := parseRequirement(requirement)
+		if err != nil {
+			return result, errors.Wrapf(err, "invalid org selector %s", selector)
+		}
+		result.requirements = append(result.requirements, parsed)
+	}
+	if len(result.requirements) == 0 {
+		return result, fmt.Errorf("org selector is empty")
+	}
+	return result, nil
+}
+
+// splitRequirements splits on the commas that are not within a set of values
+func splitRequirements(selector string) []string {
+	var requirements []string
+	depth, start := 0, 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				requirements = append(requirements, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(requirements, selector[start:])
+}
+
+func parseRequirement(requirement string) (labelRequirement, error) {
+	requirement = strings.TrimSpace(requirement)
+	if requirement == "" {
+		return labelRequirement{}, fmt.Errorf("empty requirement")
+	}
+	if match := setRequirement.FindStringSubmatch(requirement); match != nil {
+		var values []string
+		for _, value := range strings.Split(match[3], ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+		if len(values) == 0 {
+			return labelRequirement{}, fmt.Errorf("%s has no values", requirement)
+		}
+		return labelRequirement{key: match[1], operator: match[2], values: values}, nil
+	}
+	if strings.HasPrefix(requirement, "!") {
+		return newRequirement(requirement[1:], labelNotExists, requirement)
+	}
+	if i := strings.Index(requirement, "!="); i > 0 {
+		return newRequirement(requirement[:i], labelNotEquals, requirement, requirement[i+2:])
+	}
+	if i := strings.Index(requirement, "=="); i > 0 {
+		return newRequirement(requirement[:i], labelEquals, requirement, requirement[i+2:])
+	}
+	if i := strings.Index(requirement, "="); i > 0 {
+		return newRequirement(requirement[:i], labelEquals, requirement, requirement[i+1:])
+	}
+	// label keys cannot contain a colon, so team:payments is the shorthand for team=payments
+	if i := strings.Index(requirement, ":"); i > 0 {
+		return newRequirement(requirement[:i], labelEquals, requirement, requirement[i+1:])
+	}
+	return newRequirement(requirement, labelExists, requirement)
+}
+
+func newRequirement(key, operator, requirement string, values ...string) (labelRequirement, error) {
+	key = strings.TrimSpace(key)
+	if key == "" || strings.ContainsAny(key, " =!:(),") {
+		return labelRequirement{}, fmt.Errorf("%s has an invalid key", requirement)
+	}
+	for i, value := range values {
+		values[i] = strings.TrimSpace(value)
+		if values[i] == "" {
+			return labelRequirement{}, fmt.Errorf("%s has no value", requirement)
+		}
+	}
+	return labelRequirement{key: key, operator: operator, values: values}, nil
+}
+
+//String - the selector in the syntax the cloud controller accepts
+func (s LabelSelector) String() string {
+	var requirements []string
+	for _, requirement := range s.requirements {
+		switch requirement.operator {
+		case labelExists:
+			requirements = append(requirements, requirement.key)
+		case labelNotExists:
+			requirements = append(requirements, "!"+requirement.key)
+		case labelIn, labelNotIn:
+			requirements = append(requirements, fmt.Sprintf("%s %s (%s)", requirement.key, requirement.operator, strings.Join(requirement.values, ",")))
+		default:
+			requirements = append(requirements, requirement.key+requirement.operator+requirement.values[0])
+		}
+	}
+	return strings.Join(requirements, ",")
+}
+
+//Matches - whether labels meet every requirement of the selector
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	for _, requirement := range s.requirements {
+		value, ok := labels[requirement.key]
+		switch requirement.operator {
+		case labelExists:
+			if !ok {
+				return false
+			}
+		case labelNotExists:
+			if ok {
+				return false
+			}
+		case labelEquals:
+			if !ok || value != requirement.values[0] {
+				return false
+			}
+		case labelNotEquals:
+			if ok && value == requirement.values[0] {
+				return false
+			}
+		case labelIn:
+			if !ok || !contains(requirement.values, value) {
+				return false
+			}
+		case labelNotIn:
+			if ok && contains(requirement.values, value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+type v3OrgsPage struct {
+	Pagination struct {
+		Next *struct {
+			Href string `json:"href"`
+		} `json:"next"`
+	} `json:"pagination"`
+	Resources []struct {
+		Name string `json:"name"`
+	} `json:"resources"`
+}
+
+//ListOrgNamesByLabelSelector - lists the names of the orgs whose metadata
+//labels match selector. Labels are only available from the v3 api, so the
+//request is made with the authenticated http client of the cloud controller.
+func ListOrgNamesByLabelSelector(httpClient *http.Client, apiAddress string, selector LabelSelector) ([]string, error) {
+	query := url.Values{}
+	query.Set("label_selector", selector.String())
+	query.Set("per_page", "5000")
+	next := fmt.Sprintf("%s/v3/organizations?%s", strings.TrimSuffix(apiAddress, "/"), query.Encode())
+	var names []string
+	for next != "" {
+		page, err := getOrgsPage(httpClient, next)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error listing orgs matching %s", selector)
+		}
+		for _, org := range page.Resources {
+			names = append(names, org.Name)
+		}
+		next = ""
+		if page.Pagination.Next != nil {
+			next = page.Pagination.Next.Href
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func getOrgsPage(httpClient *http.Client, pageURL string) (*v3OrgsPage, error) {
+	resp, err := httpClient.Get(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cloud controller returned %d: %s", resp.StatusCode, string(body))
+	}
+	page := &v3OrgsPage{}
+	if err := json.Unmarshal(body, page); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
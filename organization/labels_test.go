@@ -0,0 +1,91 @@
+package organization_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/organization"
+)
+
+var _ = Describe("given a label selector", func() {
+	parse := func(selector string) organization.LabelSelector {
+		labelSelector, err := organization.ParseLabelSelector(selector)
+		Expect(err).ShouldNot(HaveOccurred())
+		return labelSelector
+	}
+
+	Context("ParseLabelSelector()", func() {
+		It("reads the label= shorthand as equality", func() {
+			Expect(parse("label=team:payments").String()).Should(Equal("team=payments"))
+		})
+
+		It("reads every requirement", func() {
+			Expect(parse("team==payments, env in (dev, test),!legacy,tier!=gold,owner").String()).
+				Should(Equal("team=payments,env in (dev,test),!legacy,tier!=gold,owner"))
+		})
+
+		It("errors for an empty selector", func() {
+			_, err := organization.ParseLabelSelector(" ")
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("errors for a requirement without a value", func() {
+			_, err := organization.ParseLabelSelector("team=")
+			Expect(err).Should(HaveOccurred())
+			_, err = organization.ParseLabelSelector("env notin ()")
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("Matches()", func() {
+		labels := map[string]string{"team": "payments", "env": "dev"}
+
+		It("matches when every requirement is met", func() {
+			Expect(parse("team=payments,env in (dev,test)").Matches(labels)).Should(BeTrue())
+			Expect(parse("!legacy,env notin (prod)").Matches(labels)).Should(BeTrue())
+		})
+
+		It("does not match when a requirement is not met", func() {
+			Expect(parse("team=payments,env=prod").Matches(labels)).Should(BeFalse())
+			Expect(parse("team!=payments").Matches(labels)).Should(BeFalse())
+			Expect(parse("legacy").Matches(labels)).Should(BeFalse())
+			Expect(parse("team").Matches(nil)).Should(BeFalse())
+		})
+	})
+
+	Context("ListOrgNamesByLabelSelector()", func() {
+		var server *httptest.Server
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("lists the orgs of every page", func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).Should(Equal("/v3/organizations"))
+				Expect(r.URL.Query().Get("label_selector")).Should(Equal("team=payments"))
+				if r.URL.Query().Get("page") == "2" {
+					fmt.Fprint(w, `{"pagination":{"next":null},"resources":[{"name":"billing"}]}`)
+					return
+				}
+				fmt.Fprintf(w, `{"pagination":{"next":{"href":"%s%s&page=2"}},"resources":[{"name":"ledger"}]}`, "http://"+r.Host, r.URL.RequestURI())
+			}))
+			names, err := organization.ListOrgNamesByLabelSelector(server.Client(), server.URL, parse("label=team:payments"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(names).Should(Equal([]string{"billing", "ledger"}))
+		})
+
+		It("errors when the cloud controller rejects the selector", func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"errors":[{"detail":"invalid label_selector"}]}`)
+			}))
+			_, err := organization.ListOrgNamesByLabelSelector(server.Client(), server.URL, parse("team"))
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("400"))
+		})
+	})
+})
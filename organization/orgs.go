@@ -2,11 +2,13 @@ package organization
 
 import (
 	"fmt"
-	"regexp"
+	"sort"
 	"strings"
 
 	cfclient "github.com/cloudfoundry-community/go-cfclient"
 	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/dryrun"
+	"github.com/pivotalservices/cf-mgmt/stats"
 	"github.com/xchapter7x/lo"
 )
 
@@ -23,6 +25,9 @@ type DefaultManager struct {
 	Cfg    config.Reader
 	Client CFClient
 	Peek   bool
+	// runCache keeps the orgs listed while an apply runs
+	runCache   bool
+	cachedOrgs []cfclient.Org
 }
 
 func (m *DefaultManager) GetOrgGUID(orgName string) (string, error) {
@@ -75,8 +80,6 @@ func (m *DefaultManager) DeleteOrgs() error {
 	for _, orgName := range orgsConfig.Orgs {
 		configuredOrgs[orgName] = true
 	}
-	protectedOrgs := append(config.DefaultProtectedOrgs, orgsConfig.ProtectedOrgs...)
-
 	orgs, err := m.ListOrgs()
 	if err != nil {
 		return err
@@ -85,7 +88,7 @@ func (m *DefaultManager) DeleteOrgs() error {
 	orgsToDelete := make([]cfclient.Org, 0)
 	for _, org := range orgs {
 		if _, exists := configuredOrgs[org.Name]; !exists {
-			if shouldDeleteOrg(org.Name, protectedOrgs) {
+			if !orgsConfig.IsProtected(org.Name) {
 				orgsToDelete = append(orgsToDelete, org)
 			} else {
 				lo.G.Infof("Protected org [%s] - will not be deleted", org.Name)
@@ -93,6 +96,20 @@ func (m *DefaultManager) DeleteOrgs() error {
 		}
 	}
 
+	sort.Slice(orgsToDelete, func(i, j int) bool { return orgsToDelete[i].Name < orgsToDelete[j].Name })
+
+	approvals, err := m.Cfg.GetApprovals()
+	if err != nil {
+		return err
+	}
+	changes := make([]config.Change, len(orgsToDelete))
+	for i, org := range orgsToDelete {
+		changes[i] = config.Change{Kind: config.ChangeDeleteOrg, Org: org.Name}
+	}
+	if err := approvals.Check(changes...); err != nil {
+		return err
+	}
+
 	for _, org := range orgsToDelete {
 		if err := m.DeleteOrg(org); err != nil {
 			return err
@@ -102,16 +119,6 @@ func (m *DefaultManager) DeleteOrgs() error {
 	return nil
 }
 
-func shouldDeleteOrg(orgName string, protectedOrgs []string) bool {
-	for _, protectedOrgName := range protectedOrgs {
-		match, _ := regexp.MatchString(protectedOrgName, orgName)
-		if match {
-			return false
-		}
-	}
-	return true
-}
-
 func doesOrgExist(orgName string, orgs []cfclient.Org) bool {
 	for _, org := range orgs {
 		if strings.EqualFold(org.Name, orgName) {
@@ -163,11 +170,19 @@ func (m *DefaultManager) FindOrgByGUID(orgGUID string) (cfclient.Org, error) {
 
 //ListOrgs : Returns all orgs in the given foundation
 func (m *DefaultManager) ListOrgs() ([]cfclient.Org, error) {
+	if m.runCache && m.cachedOrgs != nil {
+		stats.CacheHit("orgs")
+		return append([]cfclient.Org{}, m.cachedOrgs...), nil
+	}
 	orgs, err := m.Client.ListOrgs()
 	if err != nil {
 		return nil, err
 	}
 	lo.G.Debug("Total orgs returned :", len(orgs))
+	if m.runCache {
+		stats.CacheMiss("orgs")
+		m.cachedOrgs = append([]cfclient.Org{}, orgs...)
+	}
 	return orgs, nil
 }
 
@@ -183,9 +198,11 @@ func (m *DefaultManager) orgNames(orgs []cfclient.Org) []string {
 func (m *DefaultManager) CreateOrg(orgName string, currentOrgs []string) error {
 	if m.Peek {
 		lo.G.Infof("[dry-run]: create org %s as it doesn't exist in %v", orgName, currentOrgs)
+		dryrun.Record(dryrun.Create, dryrun.Org, orgName, "")
 		return nil
 	}
 	lo.G.Infof("create org %s as it doesn't exist in %v", orgName, currentOrgs)
+	m.cachedOrgs = nil
 	_, err := m.Client.CreateOrg(cfclient.OrgRequest{
 		Name: orgName,
 	})
@@ -195,9 +212,11 @@ func (m *DefaultManager) CreateOrg(orgName string, currentOrgs []string) error {
 func (m *DefaultManager) DeleteOrg(org cfclient.Org) error {
 	if m.Peek {
 		lo.G.Infof("[dry-run]: delete org %s", org.Name)
+		dryrun.Record(dryrun.Delete, dryrun.Org, org.Name, "")
 		return nil
 	}
 	lo.G.Infof("Deleting [%s] org", org.Name)
+	m.cachedOrgs = nil
 	return m.Client.DeleteOrg(org.Guid, true, true)
 }
 
@@ -215,9 +234,21 @@ func (m *DefaultManager) DeleteOrgByName(orgName string) error {
 }
 
 func (m *DefaultManager) UpdateOrg(orgGUID string, orgRequest cfclient.OrgRequest) (cfclient.Org, error) {
+	m.cachedOrgs = nil
 	return m.Client.UpdateOrg(orgGUID, orgRequest)
 }
 
+//StartRunCache - keeps the listed orgs until they change or StopRunCache, so the steps of an apply list them once
+func (m *DefaultManager) StartRunCache() {
+	m.runCache = true
+}
+
+//StopRunCache - lists the orgs from the foundation again every time
+func (m *DefaultManager) StopRunCache() {
+	m.runCache = false
+	m.cachedOrgs = nil
+}
+
 func (m *DefaultManager) GetOrgByGUID(orgGUID string) (cfclient.Org, error) {
 	return m.Client.GetOrgByGuid(orgGUID)
 }
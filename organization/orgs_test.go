@@ -43,6 +43,35 @@ var _ = Describe("given OrgManager", func() {
 			Ω(org.Name).Should(Equal("test"))
 		})
 	})
+	Context("run cache", func() {
+		BeforeEach(func() {
+			fakeClient.ListOrgsReturns([]cfclient.Org{{Name: "test", Guid: "test-guid"}}, nil)
+			orgManager.StartRunCache()
+		})
+		It("should list the orgs once", func() {
+			_, err := orgManager.FindOrg("test")
+			Ω(err).Should(BeNil())
+			_, err = orgManager.FindOrgByGUID("test-guid")
+			Ω(err).Should(BeNil())
+			Ω(fakeClient.ListOrgsCallCount()).Should(Equal(1))
+		})
+		It("should list the orgs again once an org is created", func() {
+			_, err := orgManager.ListOrgs()
+			Ω(err).Should(BeNil())
+			Ω(orgManager.CreateOrg("test2", []string{"test"})).Should(Succeed())
+			_, err = orgManager.ListOrgs()
+			Ω(err).Should(BeNil())
+			Ω(fakeClient.ListOrgsCallCount()).Should(Equal(2))
+		})
+		It("should list the orgs every time once stopped", func() {
+			orgManager.StopRunCache()
+			_, err := orgManager.ListOrgs()
+			Ω(err).Should(BeNil())
+			_, err = orgManager.ListOrgs()
+			Ω(err).Should(BeNil())
+			Ω(fakeClient.ListOrgsCallCount()).Should(Equal(2))
+		})
+	})
 	It("should return an error for unfound org", func() {
 		orgs := []cfclient.Org{}
 		fakeClient.ListOrgsReturns(orgs, nil)
@@ -158,6 +187,28 @@ var _ = Describe("given OrgManager", func() {
 			orgGUID, _, _ := fakeClient.DeleteOrgArgsForCall(0)
 			Expect(orgGUID).Should(Equal("test2-guid"))
 		})
+
+		It("should delete approved orgs", func() {
+			orgManager.Cfg = config.NewManager("./fixtures/config-approvals")
+			fakeClient.ListOrgsReturns([]cfclient.Org{
+				cfclient.Org{Name: "test", Guid: "test-guid"},
+				cfclient.Org{Name: "test2", Guid: "test2-guid"},
+			}, nil)
+			err := orgManager.DeleteOrgs()
+			Ω(err).Should(BeNil())
+			Expect(fakeClient.DeleteOrgCallCount()).Should(Equal(1))
+		})
+
+		It("should not delete any org when one is not approved", func() {
+			orgManager.Cfg = config.NewManager("./fixtures/config-approvals")
+			fakeClient.ListOrgsReturns([]cfclient.Org{
+				cfclient.Org{Name: "test2", Guid: "test2-guid"},
+				cfclient.Org{Name: "test3", Guid: "test3-guid"},
+			}, nil)
+			err := orgManager.DeleteOrgs()
+			Ω(err).Should(MatchError("approvals.yml has no approval for: delete-org test3"))
+			Expect(fakeClient.DeleteOrgCallCount()).Should(Equal(0))
+		})
 	})
 
 	Context("DeleteOrgByName()", func() {
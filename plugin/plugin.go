@@ -0,0 +1,149 @@
+// Package plugin runs the executables operators register, in cf-mgmt.yml, at
+// hooks before and after the steps of apply. Each plugin is passed the hook,
+// the step and, when it asks for it, the plan of changes apply is about to
+// make as json on stdin, so site-specific integrations do not require forking
+// cf-mgmt.
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/hookexec"
+	"github.com/pivotalservices/cf-mgmt/simulator"
+	"github.com/xchapter7x/lo"
+)
+
+// Prefixes of the hooks of a step
+const (
+	Pre  = "pre-"
+	Post = "post-"
+)
+
+// defaultTimeout limits a run of a plugin that sets no timeout-seconds
+const defaultTimeout = 60 * time.Second
+
+// Payload is the json a plugin reads from stdin.
+type Payload struct {
+	Hook         string `json:"hook"`
+	Step         string `json:"step,omitempty"`
+	SystemDomain string `json:"system_domain"`
+	Peek         bool   `json:"peek"`
+	// Plan is the changes apply is about to make, only passed to the plugins
+	// that set plan
+	Plan []simulator.Change `json:"plan,omitempty"`
+	// Result is the outcome of the step, or of apply, passed to post hooks
+	Result *Result `json:"result,omitempty"`
+}
+
+// Result is the outcome of a step passed to its post hook.
+type Result struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Runner runs the plugins registered at each hook. A nil Runner runs none.
+type Runner struct {
+	Plugins []config.Plugin
+	// ConfigDirectory is what relative plugin commands are relative to
+	ConfigDirectory string
+	SystemDomain    string
+	Peek            bool
+	// Plan is passed to the plugins that set plan
+	Plan []simulator.Change
+}
+
+// NewRunner returns the runner of the plugins, nil when there are none,
+// requiring each hook of a plugin to be one of hooks.
+func NewRunner(plugins []config.Plugin, hooks []string, configDirectory, systemDomain string, peek bool) (*Runner, error) {
+	if len(plugins) == 0 {
+		return nil, nil
+	}
+	known := make(map[string]bool)
+	for _, hook := range hooks {
+		known[hook] = true
+	}
+	for _, plugin := range plugins {
+		for _, hook := range plugin.Hooks {
+			if !known[hook] {
+				return nil, fmt.Errorf("plugin [%s] has unknown hook [%s], hooks are %s", plugin.Name, hook, strings.Join(hooks, ", "))
+			}
+		}
+	}
+	return &Runner{Plugins: plugins, ConfigDirectory: configDirectory, SystemDomain: systemDomain, Peek: peek}, nil
+}
+
+// NeedsPlan returns whether any plugin is passed the plan.
+func (r *Runner) NeedsPlan() bool {
+	if r == nil {
+		return false
+	}
+	for _, plugin := range r.Plugins {
+		if plugin.Plan {
+			return true
+		}
+	}
+	return false
+}
+
+// Run runs the plugins registered at the hook, in order, returning the
+// error of the first failing plugin that sets fail-apply. Failures of other
+// plugins are logged.
+func (r *Runner) Run(ctx context.Context, hook, step string, result *Result) error {
+	if r == nil {
+		return nil
+	}
+	for _, plugin := range r.Plugins {
+		if !hasHook(plugin, hook) {
+			continue
+		}
+		payload := Payload{Hook: hook, Step: step, SystemDomain: r.SystemDomain, Peek: r.Peek, Result: result}
+		if plugin.Plan {
+			payload.Plan = r.Plan
+		}
+		if err := r.run(ctx, plugin, payload); err != nil {
+			if plugin.FailApply {
+				return fmt.Errorf("plugin [%s] failed at hook [%s]: %s", plugin.Name, hook, err)
+			}
+			lo.G.Warningf("plugin [%s] failed at hook [%s], continuing: %s", plugin.Name, hook, err)
+		}
+	}
+	return nil
+}
+
+func hasHook(plugin config.Plugin, hook string) bool {
+	for _, pluginHook := range plugin.Hooks {
+		if pluginHook == hook {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Runner) run(ctx context.Context, plugin config.Plugin, payload Payload) error {
+	timeout := defaultTimeout
+	if plugin.TimeoutSeconds > 0 {
+		timeout = time.Duration(plugin.TimeoutSeconds) * time.Second
+	}
+	lo.G.Debugf("running plugin [%s] at hook [%s]", plugin.Name, payload.Hook)
+	return hookexec.Run(ctx, plugin.Name, payload, timeout, func(ctx context.Context) *exec.Cmd {
+		cmd := exec.CommandContext(ctx, r.command(plugin), plugin.Args...)
+		cmd.Env = append(os.Environ(), "CF_MGMT_HOOK="+payload.Hook, "CF_MGMT_STEP="+payload.Step, "CF_MGMT_SYSTEM_DOMAIN="+r.SystemDomain)
+		return cmd
+	})
+}
+
+// command resolves a relative command with a path, such as plugins/notify.sh,
+// against the config directory, and looks other commands up on the PATH
+func (r *Runner) command(plugin config.Plugin) string {
+	if filepath.IsAbs(plugin.Command) || !strings.Contains(plugin.Command, "/") {
+		return plugin.Command
+	}
+	return filepath.Join(config.BaseDirectory(r.ConfigDirectory), plugin.Command)
+}
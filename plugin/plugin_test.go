@@ -0,0 +1,101 @@
+package plugin_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/plugin"
+	"github.com/pivotalservices/cf-mgmt/simulator"
+)
+
+var _ = Describe("given a plugin runner", func() {
+	var (
+		configDir string
+		hooks     = []string{"pre-apply", "pre-org-create", "post-org-create", "post-apply"}
+	)
+
+	BeforeEach(func() {
+		var err error
+		configDir, err = ioutil.TempDir("", "cf-mgmt-plugin")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(os.Mkdir(filepath.Join(configDir, "plugins"), 0755)).Should(Succeed())
+		script := "#!/bin/sh\ncat > \"$(dirname \"$0\")/payload-$CF_MGMT_HOOK.json\"\necho ran $CF_MGMT_HOOK\nexit ${1:-0}\n"
+		Expect(ioutil.WriteFile(filepath.Join(configDir, "plugins", "record.sh"), []byte(script), 0755)).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(configDir)
+	})
+
+	payload := func(hook string) plugin.Payload {
+		data, err := ioutil.ReadFile(filepath.Join(configDir, "plugins", "payload-"+hook+".json"))
+		Expect(err).ShouldNot(HaveOccurred())
+		result := plugin.Payload{}
+		Expect(json.Unmarshal(data, &result)).Should(Succeed())
+		return result
+	}
+
+	It("runs nothing without plugins", func() {
+		runner, err := plugin.NewRunner(nil, hooks, configDir, "sys.example.com", false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(runner).Should(BeNil())
+		Expect(runner.NeedsPlan()).Should(BeFalse())
+		Expect(runner.Run(context.Background(), "pre-apply", "", nil)).Should(Succeed())
+	})
+
+	It("requires the hooks of plugins to exist", func() {
+		_, err := plugin.NewRunner([]config.Plugin{{Name: "record", Command: "plugins/record.sh", Hooks: []string{"pre-org-creation"}}}, hooks, configDir, "sys.example.com", false)
+		Expect(err).Should(MatchError("plugin [record] has unknown hook [pre-org-creation], hooks are pre-apply, pre-org-create, post-org-create, post-apply"))
+	})
+
+	It("passes the plugins of a hook the payload", func() {
+		runner, err := plugin.NewRunner([]config.Plugin{{Name: "record", Command: "plugins/record.sh", Hooks: []string{"post-org-create"}, Plan: true}}, hooks, configDir, "sys.example.com", true)
+		Expect(err).ShouldNot(HaveOccurred())
+		runner.Plan = []simulator.Change{{Action: simulator.ActionCreate, Kind: "org", Name: "payments"}}
+		Expect(runner.NeedsPlan()).Should(BeTrue())
+		Expect(runner.Run(context.Background(), "pre-org-create", "Creating Orgs", nil)).Should(Succeed())
+		Expect(filepath.Join(configDir, "plugins", "payload-pre-org-create.json")).ShouldNot(BeAnExistingFile())
+
+		Expect(runner.Run(context.Background(), "post-org-create", "Creating Orgs", &plugin.Result{Status: "succeeded"})).Should(Succeed())
+		Expect(payload("post-org-create")).Should(Equal(plugin.Payload{
+			Hook:         "post-org-create",
+			Step:         "Creating Orgs",
+			SystemDomain: "sys.example.com",
+			Peek:         true,
+			Plan:         []simulator.Change{{Action: simulator.ActionCreate, Kind: "org", Name: "payments"}},
+			Result:       &plugin.Result{Status: "succeeded"},
+		}))
+	})
+
+	It("only passes the plan to the plugins that set plan", func() {
+		runner, err := plugin.NewRunner([]config.Plugin{{Name: "record", Command: "plugins/record.sh", Hooks: []string{"pre-apply"}}}, hooks, configDir, "sys.example.com", false)
+		Expect(err).ShouldNot(HaveOccurred())
+		runner.Plan = []simulator.Change{{Action: simulator.ActionCreate, Kind: "org", Name: "payments"}}
+		Expect(runner.Run(context.Background(), "pre-apply", "", nil)).Should(Succeed())
+		Expect(payload("pre-apply").Plan).Should(BeEmpty())
+	})
+
+	It("fails when a plugin that sets fail-apply fails", func() {
+		runner, err := plugin.NewRunner([]config.Plugin{
+			{Name: "best-effort", Command: "plugins/record.sh", Args: []string{"3"}, Hooks: []string{"pre-org-create"}},
+			{Name: "gate", Command: filepath.Join(configDir, "plugins", "record.sh"), Args: []string{"2"}, Hooks: []string{"pre-org-create"}, FailApply: true},
+		}, hooks, configDir, "sys.example.com", false)
+		Expect(err).ShouldNot(HaveOccurred())
+		err = runner.Run(context.Background(), "pre-org-create", "Creating Orgs", nil)
+		Expect(err).Should(MatchError("plugin [gate] failed at hook [pre-org-create]: exit status 2"))
+	})
+
+	It("stops plugins that run past their timeout", func() {
+		Expect(ioutil.WriteFile(filepath.Join(configDir, "plugins", "slow.sh"), []byte("#!/bin/sh\nexec sleep 5\n"), 0755)).Should(Succeed())
+		runner, err := plugin.NewRunner([]config.Plugin{{Name: "slow", Command: "plugins/slow.sh", Hooks: []string{"post-apply"}, TimeoutSeconds: 1, FailApply: true}}, hooks, configDir, "sys.example.com", false)
+		Expect(err).ShouldNot(HaveOccurred())
+		err = runner.Run(context.Background(), "post-apply", "", &plugin.Result{Status: "succeeded"})
+		Expect(err).Should(MatchError("plugin [slow] failed at hook [post-apply]: timed out after 1s"))
+	})
+})
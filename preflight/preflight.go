@@ -0,0 +1,262 @@
+// Package preflight verifies the credentials cf-mgmt runs with before any
+// reconciliation begins, so that a wrong secret, an unreachable directory or a
+// missing scope fails a run up front rather than part way through it.
+package preflight
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/ldap"
+	"github.com/xchapter7x/lo"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+//RequiredScopes - the uaa scopes every cf-mgmt command needs to manage orgs and spaces and read users
+var RequiredScopes = []string{"cloud_controller.admin", "scim.read"}
+
+//UserWriteScope - the uaa scope the commands that create, update or delete users and groups need besides RequiredScopes
+const UserWriteScope = "scim.write"
+
+// uaa.none is granted to clients without authorities and grants nothing, and
+// the idps and zones read scopes only read identity providers and zones. The
+// write scopes are verified when the configuration or command needs them,
+// and scim.write is needed by the commands that write users.
+var harmlessScopes = []string{"uaa.none", "idps.read", "zones.read", UserWriteScope}
+
+//Config - the credentials to verify
+type Config struct {
+	// APIAddress is the cloud controller, such as https://api.sys.example.com
+	APIAddress string
+	// UAAAddress is the uaa, such as https://uaa.sys.example.com
+	UAAAddress   string
+	UserID       string
+	Password     string
+	ClientSecret string
+	// Scopes are the scopes needed besides RequiredScopes, such as idps.write
+	// when identity providers are managed or scim.write when the command
+	// writes users
+	Scopes []string
+	// LdapConfig, when ldap is enabled, is bound to with its bind credentials
+	LdapConfig *config.LdapConfig
+	Transport  http.RoundTripper
+}
+
+//Check - the outcome of one verification
+type Check struct {
+	Name    string
+	Skipped string
+	Warning string
+	Err     error
+}
+
+//Result - the outcome of every verification and the scopes the client was granted
+type Result struct {
+	Checks []Check
+	Scopes []string
+}
+
+//Run - verifies the cloud controller token, the uaa client token and its
+//scopes and the ldap bind. Every check runs even when an earlier one fails so
+//that all problems are reported at once.
+func Run(cfg Config) *Result {
+	result := &Result{}
+	httpClient := &http.Client{Transport: cfg.Transport}
+	result.add("cloud controller token", "", checkCloudController(cfg, httpClient))
+
+	scopes, err := clientScopes(cfg, httpClient)
+	result.add("uaa client token", "", err)
+	if err != nil {
+		result.add("uaa scopes", "no uaa client token", nil)
+	} else {
+		result.Scopes = scopes
+		result.add("uaa scopes", "", checkScopes(cfg.UserID, scopes, cfg.Scopes))
+		if extra := ExtraScopes(scopes, cfg.Scopes...); len(extra) > 0 {
+			result.Checks[len(result.Checks)-1].Warning = broaderMessage(cfg.UserID, extra, cfg.Scopes)
+		}
+	}
+
+	if cfg.LdapConfig == nil || !cfg.LdapConfig.Enabled {
+		result.add("ldap bind", "ldap is not enabled", nil)
+	} else {
+		result.add("ldap bind", "", checkLdap(cfg.LdapConfig))
+	}
+	return result
+}
+
+func (r *Result) add(name, skipped string, err error) {
+	r.Checks = append(r.Checks, Check{Name: name, Skipped: skipped, Err: err})
+}
+
+//Failed - whether any check failed
+func (r *Result) Failed() bool {
+	return len(r.failures()) > 0
+}
+
+//Error - an error listing every failed check, nil when all passed
+func (r *Result) Error() error {
+	failures := r.failures()
+	if len(failures) == 0 {
+		return nil
+	}
+	return fmt.Errorf("preflight failed: %s", strings.Join(failures, "; "))
+}
+
+func (r *Result) failures() []string {
+	var failures []string
+	for _, check := range r.Checks {
+		if check.Err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", check.Name, check.Err))
+		}
+	}
+	return failures
+}
+
+//String - formats the result one line per check
+func (r *Result) String() string {
+	var buffer bytes.Buffer
+	for _, check := range r.Checks {
+		switch {
+		case check.Err != nil:
+			fmt.Fprintf(&buffer, "FAIL %s: %s\n", check.Name, check.Err)
+		case check.Skipped != "":
+			fmt.Fprintf(&buffer, "SKIP %s: %s\n", check.Name, check.Skipped)
+		case check.Warning != "":
+			fmt.Fprintf(&buffer, "WARN %s: %s\n", check.Name, check.Warning)
+		default:
+			fmt.Fprintf(&buffer, "OK   %s\n", check.Name)
+		}
+	}
+	return buffer.String()
+}
+
+//VerifyScopes - fails when the uaa client is missing a required scope and
+//warns when it was granted authorities beyond them, so that over-privileged
+//clients stand out in least-privilege audits
+func VerifyScopes(cfg Config) error {
+	scopes, err := clientScopes(cfg, &http.Client{Transport: cfg.Transport})
+	if err != nil {
+		return err
+	}
+	if err := checkScopes(cfg.UserID, scopes, cfg.Scopes); err != nil {
+		return err
+	}
+	if extra := ExtraScopes(scopes, cfg.Scopes...); len(extra) > 0 {
+		lo.G.Warning(broaderMessage(cfg.UserID, extra, cfg.Scopes))
+	}
+	return nil
+}
+
+//ExtraScopes - the granted scopes cf-mgmt does not need, besides the scopes the configuration needs
+func ExtraScopes(scopes []string, configScopes ...string) []string {
+	needed := make(map[string]bool)
+	for _, scope := range requiredScopes(configScopes) {
+		needed[scope] = true
+	}
+	for _, scope := range harmlessScopes {
+		needed[scope] = true
+	}
+	var extra []string
+	for _, scope := range scopes {
+		if !needed[scope] {
+			extra = append(extra, scope)
+		}
+	}
+	return extra
+}
+
+func broaderMessage(clientID string, extra, configScopes []string) string {
+	return fmt.Sprintf("client %s has authorities cf-mgmt does not need: %s, only %s are required", clientID, strings.Join(extra, ","), strings.Join(requiredScopes(configScopes), ","))
+}
+
+// requiredScopes are RequiredScopes and the scopes the configuration needs
+func requiredScopes(configScopes []string) []string {
+	required := append([]string{}, RequiredScopes...)
+	for _, scope := range configScopes {
+		if !contains(required, scope) {
+			required = append(required, scope)
+		}
+	}
+	return required
+}
+
+func contains(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func checkCloudController(cfg Config, httpClient *http.Client) error {
+	c := &cfclient.Config{
+		ApiAddress:        cfg.APIAddress,
+		SkipSslValidation: true,
+		HttpClient:        httpClient,
+	}
+	if cfg.Password != "" {
+		c.Username, c.Password = cfg.UserID, cfg.Password
+	} else {
+		c.ClientID, c.ClientSecret = cfg.UserID, cfg.ClientSecret
+	}
+	client, err := cfclient.NewClient(c)
+	if err != nil {
+		return fmt.Errorf("unable to connect to %s: %v", cfg.APIAddress, err)
+	}
+	if _, err := client.GetToken(); err != nil {
+		return fmt.Errorf("unable to get a token for %s: %v", cfg.UserID, err)
+	}
+	return nil
+}
+
+func clientScopes(cfg Config, httpClient *http.Client) ([]string, error) {
+	credentials := &clientcredentials.Config{
+		ClientID:     cfg.UserID,
+		ClientSecret: cfg.ClientSecret,
+		TokenURL:     strings.TrimSuffix(cfg.UAAAddress, "/") + "/oauth/token",
+	}
+	token, err := credentials.Token(context.WithValue(context.Background(), oauth2.HTTPClient, httpClient))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get a token for client %s: %v", cfg.UserID, err)
+	}
+	scope, ok := token.Extra("scope").(string)
+	if !ok {
+		return nil, fmt.Errorf("uaa did not return the scopes of client %s", cfg.UserID)
+	}
+	scopes := strings.Fields(scope)
+	sort.Strings(scopes)
+	return scopes, nil
+}
+
+func checkScopes(clientID string, scopes, configScopes []string) error {
+	granted := make(map[string]bool)
+	for _, scope := range scopes {
+		granted[scope] = true
+	}
+	var missing []string
+	for _, scope := range requiredScopes(configScopes) {
+		if !granted[scope] {
+			missing = append(missing, scope)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("client %s is missing the authorities %s", clientID, strings.Join(missing, ","))
+	}
+	return nil
+}
+
+func checkLdap(ldapConfig *config.LdapConfig) error {
+	connection, err := ldap.CreateConnection(ldapConfig)
+	if err != nil {
+		return fmt.Errorf("unable to bind to %s:%d: %v", ldapConfig.LdapHost, ldapConfig.LdapPort, err)
+	}
+	return connection.Close()
+}
@@ -0,0 +1,131 @@
+package preflight_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/httpclient"
+	. "github.com/pivotalservices/cf-mgmt/preflight"
+)
+
+var _ = Describe("Preflight", func() {
+	var (
+		server *httptest.Server
+		scope  string
+		cfg    Config
+	)
+	BeforeEach(func() {
+		scope = "cloud_controller.admin scim.read scim.write"
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/v2/info":
+				json.NewEncoder(w).Encode(map[string]string{"authorization_endpoint": server.URL, "token_endpoint": server.URL})
+			case "/oauth/token":
+				r.ParseForm()
+				clientID, secret, _ := r.BasicAuth()
+				if clientID == "" {
+					clientID, secret = r.Form.Get("client_id"), r.Form.Get("client_secret")
+				}
+				if clientID != "cf-mgmt" || secret != "secret" {
+					w.WriteHeader(http.StatusUnauthorized)
+					w.Write([]byte(`{"error":"unauthorized"}`))
+					return
+				}
+				json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "token", "token_type": "bearer", "expires_in": 3600, "scope": scope})
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		cfg = Config{
+			APIAddress:   server.URL,
+			UAAAddress:   server.URL,
+			UserID:       "cf-mgmt",
+			ClientSecret: "secret",
+			Transport:    httpclient.NewTransport(httpclient.Options{}),
+		}
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("passes with valid credentials and the required scopes", func() {
+		result := Run(cfg)
+		Expect(result.Failed()).Should(BeFalse())
+		Expect(result.Error()).ShouldNot(HaveOccurred())
+		Expect(result.Scopes).Should(Equal([]string{"cloud_controller.admin", "scim.read", "scim.write"}))
+		Expect(result.String()).Should(Equal("OK   cloud controller token\nOK   uaa client token\nOK   uaa scopes\nSKIP ldap bind: ldap is not enabled\n"))
+	})
+
+	It("fails when a required scope is missing", func() {
+		scope = "cloud_controller.admin scim.write"
+		result := Run(cfg)
+		Expect(result.Failed()).Should(BeTrue())
+		Expect(result.Error()).Should(MatchError("preflight failed: uaa scopes: client cf-mgmt is missing the authorities scim.read"))
+	})
+
+	It("only requires scim.write of commands that write users", func() {
+		scope = "cloud_controller.admin scim.read"
+		Expect(Run(cfg).Failed()).Should(BeFalse())
+		cfg.Scopes = []string{UserWriteScope}
+		result := Run(cfg)
+		Expect(result.Failed()).Should(BeTrue())
+		Expect(result.Error()).Should(MatchError("preflight failed: uaa scopes: client cf-mgmt is missing the authorities scim.write"))
+	})
+
+	It("warns when the client has authorities beyond the required scopes", func() {
+		scope = "cloud_controller.admin scim.read scim.write uaa.admin"
+		result := Run(cfg)
+		Expect(result.Failed()).Should(BeFalse())
+		Expect(result.String()).Should(ContainSubstring("WARN uaa scopes: client cf-mgmt has authorities cf-mgmt does not need: uaa.admin, only cloud_controller.admin,scim.read are required\n"))
+	})
+
+	Context("VerifyScopes", func() {
+		It("succeeds with exactly the required scopes", func() {
+			Expect(VerifyScopes(cfg)).Should(Succeed())
+		})
+		It("succeeds with broader scopes", func() {
+			scope = "cloud_controller.admin scim.read scim.write clients.admin"
+			Expect(VerifyScopes(cfg)).Should(Succeed())
+		})
+		It("fails when a required scope is missing", func() {
+			scope = "scim.read scim.write"
+			Expect(VerifyScopes(cfg)).Should(MatchError("client cf-mgmt is missing the authorities cloud_controller.admin"))
+		})
+		It("fails when a scope the configuration needs is missing", func() {
+			cfg.Scopes = []string{"idps.read", "idps.write"}
+			Expect(VerifyScopes(cfg)).Should(MatchError("client cf-mgmt is missing the authorities idps.read,idps.write"))
+		})
+	})
+
+	Context("ExtraScopes", func() {
+		It("ignores required and harmless scopes", func() {
+			Expect(ExtraScopes([]string{"cloud_controller.admin", "doppler.firehose", "scim.read", "uaa.none"})).Should(Equal([]string{"doppler.firehose"}))
+		})
+		It("lists write scopes the configuration does not need", func() {
+			granted := []string{"cloud_controller.admin", "clients.secret", "idps.write", "scim.read", "zones.write"}
+			Expect(ExtraScopes(granted)).Should(Equal([]string{"clients.secret", "idps.write", "zones.write"}))
+			Expect(ExtraScopes(granted, "idps.write", "zones.write")).Should(Equal([]string{"clients.secret"}))
+		})
+	})
+
+	It("reports every failed check when the secret is wrong", func() {
+		cfg.ClientSecret = "wrong"
+		result := Run(cfg)
+		Expect(result.Failed()).Should(BeTrue())
+		Expect(result.Checks[0].Err).Should(HaveOccurred())
+		Expect(result.Checks[1].Err).Should(HaveOccurred())
+		Expect(result.Checks[2].Skipped).Should(Equal("no uaa client token"))
+	})
+
+	It("fails when ldap cannot be bound to", func() {
+		cfg.LdapConfig = &config.LdapConfig{Enabled: true, LdapHost: "127.0.0.1", LdapPort: 1}
+		result := Run(cfg)
+		Expect(result.Checks[3].Name).Should(Equal("ldap bind"))
+		Expect(result.Checks[3].Err).Should(HaveOccurred())
+	})
+})
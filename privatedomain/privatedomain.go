@@ -3,6 +3,7 @@ package privatedomain
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -70,9 +71,9 @@ func (m *DefaultManager) CreatePrivateDomains() error {
 			if err != nil {
 				return err
 			}
-			for existingPrivateDomain, privateDomain := range orgPrivateDomains {
+			for _, existingPrivateDomain := range sortedDomainNames(orgPrivateDomains) {
 				if _, ok := privateDomainMap[existingPrivateDomain]; !ok {
-					err = m.DeletePrivateDomain(privateDomain)
+					err = m.DeletePrivateDomain(orgPrivateDomains[existingPrivateDomain])
 					if err != nil {
 						return err
 					}
@@ -86,6 +87,9 @@ func (m *DefaultManager) CreatePrivateDomains() error {
 	return nil
 }
 
+//SharePrivateDomains - shares private domains with the orgs that list them in shared-private-domains and with
+//the orgs named in the private-domain-sharing of the owning org. The owning org governs the domains it declares
+//sharing for, so they are unshared from any other org, including orgs not managed by cf-mgmt.
 func (m *DefaultManager) SharePrivateDomains() error {
 	orgConfigs, err := m.Cfg.GetOrgConfigs()
 	if err != nil {
@@ -96,6 +100,16 @@ func (m *DefaultManager) SharePrivateDomains() error {
 	if err != nil {
 		return err
 	}
+	sharing, err := sharingMatrix(orgConfigs)
+	if err != nil {
+		return err
+	}
+	if len(sharing) > 0 {
+		orgConfigs, err = m.withUnconfiguredOrgs(orgConfigs)
+		if err != nil {
+			return err
+		}
+	}
 	for _, orgConfig := range orgConfigs {
 		org, err := m.OrgMgr.FindOrg(orgConfig.Org)
 		if err != nil {
@@ -108,7 +122,10 @@ func (m *DefaultManager) SharePrivateDomains() error {
 
 		lo.G.Debugf("Org %s Shared Domains %+v", orgConfig.Org, reflect.ValueOf(orgSharedPrivateDomains).MapKeys())
 
-		for _, privateDomainName := range orgConfig.SharedPrivateDomains {
+		for _, privateDomainName := range sharedDomainNames(orgConfig, sharing) {
+			if sharedWith, ok := sharing[privateDomainName]; ok && !sharedWith[orgConfig.Org] {
+				return fmt.Errorf("Private Domain [%s] is not shared with org [%s] in the private-domain-sharing of its owning org", privateDomainName, orgConfig.Org)
+			}
 			if _, ok := orgSharedPrivateDomains[privateDomainName]; !ok {
 				if privateDomain, ok := privateDomains[privateDomainName]; ok {
 					err = m.SharePrivateDomain(&org, privateDomain)
@@ -124,22 +141,87 @@ func (m *DefaultManager) SharePrivateDomains() error {
 			}
 		}
 
-		if orgConfig.RemoveSharedPrivateDomains {
-			lo.G.Debugf("Org %s Shared Domains to be removed %+v", orgConfig.Org, reflect.ValueOf(orgSharedPrivateDomains).MapKeys())
-			for _, privateDomain := range orgSharedPrivateDomains {
-				err = m.RemoveSharedPrivateDomain(&org, privateDomain)
-				if err != nil {
-					return err
-				}
-			}
-		} else {
+		if !orgConfig.RemoveSharedPrivateDomains {
 			lo.G.Debugf("Shared private domains will not be removed for org [%s], must set enable-remove-shared-private-domains: true in orgConfig.yml", orgConfig.Org)
 		}
+		for _, privateDomainName := range sortedDomainNames(orgSharedPrivateDomains) {
+			if _, governed := sharing[privateDomainName]; !governed && !orgConfig.RemoveSharedPrivateDomains {
+				continue
+			}
+			err = m.RemoveSharedPrivateDomain(&org, orgSharedPrivateDomains[privateDomainName])
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
+// sharingMatrix is the orgs each private domain is shared with, by the
+// private-domain-sharing of the org owning the domain
+func sharingMatrix(orgConfigs []config.OrgConfig) (map[string]map[string]bool, error) {
+	sharing := make(map[string]map[string]bool)
+	for _, orgConfig := range orgConfigs {
+		owned := make(map[string]bool)
+		for _, privateDomain := range orgConfig.PrivateDomains {
+			owned[privateDomain] = true
+		}
+		for privateDomain, orgNames := range orgConfig.PrivateDomainSharing {
+			if !owned[privateDomain] {
+				return nil, fmt.Errorf("Private Domain [%s] in the private-domain-sharing of org [%s] must be one of its private-domains", privateDomain, orgConfig.Org)
+			}
+			sharing[privateDomain] = make(map[string]bool)
+			for _, orgName := range orgNames {
+				if orgName == orgConfig.Org {
+					return nil, fmt.Errorf("Private Domain [%s] cannot be shared with its owning org [%s]", privateDomain, orgConfig.Org)
+				}
+				sharing[privateDomain][orgName] = true
+			}
+		}
+	}
+	return sharing, nil
+}
+
+// withUnconfiguredOrgs adds the orgs of the foundation without configuration,
+// so that governed domains are shared with and unshared from them too
+func (m *DefaultManager) withUnconfiguredOrgs(orgConfigs []config.OrgConfig) ([]config.OrgConfig, error) {
+	orgs, err := m.OrgMgr.ListOrgs()
+	if err != nil {
+		return nil, err
+	}
+	configured := make(map[string]bool)
+	for _, orgConfig := range orgConfigs {
+		configured[orgConfig.Org] = true
+	}
+	for _, org := range orgs {
+		if !configured[org.Name] {
+			orgConfigs = append(orgConfigs, config.OrgConfig{Org: org.Name})
+		}
+	}
+	return orgConfigs, nil
+}
+
+// sharedDomainNames are the domains the org lists in shared-private-domains
+// and the domains other orgs share with it, sorted
+func sharedDomainNames(orgConfig config.OrgConfig, sharing map[string]map[string]bool) []string {
+	names := make(map[string]bool)
+	for _, privateDomain := range orgConfig.SharedPrivateDomains {
+		names[privateDomain] = true
+	}
+	for privateDomain, sharedWith := range sharing {
+		if sharedWith[orgConfig.Org] {
+			names[privateDomain] = true
+		}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
 func (m *DefaultManager) ListAllPrivateDomains() (map[string]cfclient.Domain, error) {
 	domains, err := m.Client.ListDomains()
 	if err != nil {
@@ -229,3 +311,12 @@ func (m *DefaultManager) RemoveSharedPrivateDomain(org *cfclient.Org, domain cfc
 	lo.G.Infof("Unshare private domain %s for org %s", domain.Name, org.Name)
 	return m.Client.UnshareOrgPrivateDomain(org.Guid, domain.Guid)
 }
+
+func sortedDomainNames(domains map[string]cfclient.Domain) []string {
+	names := make([]string, 0, len(domains))
+	for name := range domains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -326,6 +326,91 @@ var _ = Describe("given UserSpaces", func() {
 				Expect(domainGUID).Should(Equal("test.com-guid"))
 			})
 
+			Context("with a private domain sharing matrix", func() {
+				BeforeEach(func() {
+					fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+						config.OrgConfig{
+							Org:                  "test",
+							PrivateDomains:       []string{"test.com"},
+							PrivateDomainSharing: map[string][]string{"test.com": []string{"test2", "unmanaged"}},
+						},
+						config.OrgConfig{
+							Org: "test2",
+						},
+					}, nil)
+					orgFake.ListOrgsReturns([]cfclient.Org{
+						cfclient.Org{Name: "test", Guid: "test-guid"},
+						cfclient.Org{Name: "test2", Guid: "test2-guid"},
+						cfclient.Org{Name: "unmanaged", Guid: "unmanaged-guid"},
+						cfclient.Org{Name: "other", Guid: "other-guid"},
+					}, nil)
+					orgFake.FindOrgStub = func(orgName string) (cfclient.Org, error) {
+						return cfclient.Org{Name: orgName, Guid: orgName + "-guid"}, nil
+					}
+					client.ListDomainsReturns([]cfclient.Domain{
+						cfclient.Domain{Name: "test.com", Guid: "test.com-guid", OwningOrganizationGuid: "test-guid"},
+					}, nil)
+				})
+
+				It("should share the domain with the listed orgs, including orgs without configuration", func() {
+					err := manager.SharePrivateDomains()
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(client.ShareOrgPrivateDomainCallCount()).Should(Equal(2))
+					orgGUID, domainGUID := client.ShareOrgPrivateDomainArgsForCall(0)
+					Expect(orgGUID).Should(Equal("test2-guid"))
+					Expect(domainGUID).Should(Equal("test.com-guid"))
+					orgGUID, _ = client.ShareOrgPrivateDomainArgsForCall(1)
+					Expect(orgGUID).Should(Equal("unmanaged-guid"))
+					Expect(client.UnshareOrgPrivateDomainCallCount()).Should(Equal(0))
+				})
+
+				It("should unshare the domain from orgs that are not listed", func() {
+					client.ListOrgPrivateDomainsStub = func(orgGUID string) ([]cfclient.Domain, error) {
+						if orgGUID == "test-guid" {
+							return nil, nil
+						}
+						return []cfclient.Domain{
+							cfclient.Domain{Name: "test.com", Guid: "test.com-guid", OwningOrganizationGuid: "test-guid"},
+						}, nil
+					}
+					err := manager.SharePrivateDomains()
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(client.ShareOrgPrivateDomainCallCount()).Should(Equal(0))
+					Expect(client.UnshareOrgPrivateDomainCallCount()).Should(Equal(1))
+					orgGUID, domainGUID := client.UnshareOrgPrivateDomainArgsForCall(0)
+					Expect(orgGUID).Should(Equal("other-guid"))
+					Expect(domainGUID).Should(Equal("test.com-guid"))
+				})
+
+				It("should error when an org not listed asks for the domain", func() {
+					fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+						config.OrgConfig{
+							Org:                  "test",
+							PrivateDomains:       []string{"test.com"},
+							PrivateDomainSharing: map[string][]string{"test.com": []string{"test2"}},
+						},
+						config.OrgConfig{
+							Org:                  "test3",
+							SharedPrivateDomains: []string{"test.com"},
+						},
+					}, nil)
+					err := manager.SharePrivateDomains()
+					Expect(err).Should(MatchError("Private Domain [test.com] is not shared with org [test3] in the private-domain-sharing of its owning org"))
+				})
+
+				It("should error when the org does not own the domain", func() {
+					fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+						config.OrgConfig{
+							Org:                  "test2",
+							PrivateDomainSharing: map[string][]string{"test.com": []string{"test3"}},
+						},
+					}, nil)
+					err := manager.SharePrivateDomains()
+					Expect(err).Should(MatchError("Private Domain [test.com] in the private-domain-sharing of org [test2] must be one of its private-domains"))
+					Expect(client.ShareOrgPrivateDomainCallCount()).Should(Equal(0))
+				})
+			})
+
 			It("should error getting org config", func() {
 				fakeReader.GetOrgConfigsReturns(nil, errors.New("error"))
 				err := manager.SharePrivateDomains()
@@ -0,0 +1,161 @@
+package quota
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+)
+
+// changes collects field level differences between a quota on the foundation
+// and the configured quota, named after the config properties.
+type changes []string
+
+func (c *changes) memory(field string, from, to int) {
+	if from != to {
+		*c = append(*c, fmt.Sprintf("%s %s -> %s", field, formatMemory(from), formatMemory(to)))
+	}
+}
+
+func (c *changes) limit(field string, from, to int) {
+	if from != to {
+		*c = append(*c, fmt.Sprintf("%s %s -> %s", field, formatLimit(from), formatLimit(to)))
+	}
+}
+
+func (c *changes) flag(field string, from, to bool) {
+	if from != to {
+		*c = append(*c, fmt.Sprintf("%s %t -> %t", field, from, to))
+	}
+}
+
+func (c *changes) text(field, from, to string) {
+	if from != to {
+		*c = append(*c, fmt.Sprintf("%s %q -> %q", field, from, to))
+	}
+}
+
+// String formats the changes as a parenthesized suffix for log messages.
+func (c changes) String() string {
+	if len(c) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(c, ", ") + ")"
+}
+
+// formatMemory formats a limit in megabytes, using gigabytes when it is a whole number of them.
+func formatMemory(mb int) string {
+	if mb < 0 {
+		return "unlimited"
+	}
+	if mb >= 1024 && mb%1024 == 0 {
+		return fmt.Sprintf("%dG", mb/1024)
+	}
+	return fmt.Sprintf("%dM", mb)
+}
+
+func formatLimit(limit int) string {
+	if limit < 0 {
+		return "unlimited"
+	}
+	return strconv.Itoa(limit)
+}
+
+func orgQuotaChanges(quota cfclient.OrgQuota, newQuota cfclient.OrgQuotaRequest) changes {
+	c := changes{}
+	c.memory("memory-limit", quota.MemoryLimit, newQuota.MemoryLimit)
+	c.memory("instance-memory-limit", quota.InstanceMemoryLimit, newQuota.InstanceMemoryLimit)
+	c.limit("total-routes", quota.TotalRoutes, newQuota.TotalRoutes)
+	c.limit("total-services", quota.TotalServices, newQuota.TotalServices)
+	c.flag("paid-service-plans-allowed", quota.NonBasicServicesAllowed, newQuota.NonBasicServicesAllowed)
+	c.limit("total_private_domains", quota.TotalPrivateDomains, newQuota.TotalPrivateDomains)
+	c.limit("total_reserved_route_ports", quota.TotalReservedRoutePorts, newQuota.TotalReservedRoutePorts)
+	c.limit("total_service_keys", quota.TotalServiceKeys, newQuota.TotalServiceKeys)
+	c.limit("app_instance_limit", quota.AppInstanceLimit, newQuota.AppInstanceLimit)
+	c.limit("app_task_limit", quota.AppTaskLimit, newQuota.AppTaskLimit)
+	return c
+}
+
+func spaceQuotaChanges(quota cfclient.SpaceQuota, newQuota cfclient.SpaceQuotaRequest) changes {
+	c := changes{}
+	c.text("organization", quota.OrganizationGuid, newQuota.OrganizationGuid)
+	c.memory("memory-limit", quota.MemoryLimit, newQuota.MemoryLimit)
+	c.memory("instance-memory-limit", quota.InstanceMemoryLimit, newQuota.InstanceMemoryLimit)
+	c.limit("total-routes", quota.TotalRoutes, newQuota.TotalRoutes)
+	c.limit("total-services", quota.TotalServices, newQuota.TotalServices)
+	c.flag("paid-service-plans-allowed", quota.NonBasicServicesAllowed, newQuota.NonBasicServicesAllowed)
+	c.limit("total_reserved_route_ports", quota.TotalReservedRoutePorts, newQuota.TotalReservedRoutePorts)
+	c.limit("total_service_keys", quota.TotalServiceKeys, newQuota.TotalServiceKeys)
+	c.limit("app_instance_limit", quota.AppInstanceLimit, newQuota.AppInstanceLimit)
+	c.limit("app_task_limit", quota.AppTaskLimit, newQuota.AppTaskLimit)
+	return c
+}
+
+// shrinks keeps the limits the configured quota lowers at their current
+// value, collecting the lowered limits as changes, so that a quota can grow
+// outside the maintenance window of its org while shrinking waits for it.
+type shrinks struct {
+	changes
+}
+
+func (s *shrinks) memory(field string, from int, to *int) {
+	if kept := raise(from, *to); kept != *to {
+		s.changes.memory(field, from, *to)
+		*to = kept
+	}
+}
+
+func (s *shrinks) limit(field string, from int, to *int) {
+	if kept := raise(from, *to); kept != *to {
+		s.changes.limit(field, from, *to)
+		*to = kept
+	}
+}
+
+func (s *shrinks) flag(field string, from bool, to *bool) {
+	if from && !*to {
+		s.changes.flag(field, from, *to)
+		*to = true
+	}
+}
+
+// raise returns the larger of two limits where a negative limit is unlimited.
+func raise(from, to int) int {
+	if from < 0 || to < 0 {
+		return -1
+	}
+	if from > to {
+		return from
+	}
+	return to
+}
+
+func deferOrgQuotaShrinks(quota cfclient.OrgQuota, newQuota *cfclient.OrgQuotaRequest) changes {
+	s := shrinks{changes: changes{}}
+	s.memory("memory-limit", quota.MemoryLimit, &newQuota.MemoryLimit)
+	s.memory("instance-memory-limit", quota.InstanceMemoryLimit, &newQuota.InstanceMemoryLimit)
+	s.limit("total-routes", quota.TotalRoutes, &newQuota.TotalRoutes)
+	s.limit("total-services", quota.TotalServices, &newQuota.TotalServices)
+	s.flag("paid-service-plans-allowed", quota.NonBasicServicesAllowed, &newQuota.NonBasicServicesAllowed)
+	s.limit("total_private_domains", quota.TotalPrivateDomains, &newQuota.TotalPrivateDomains)
+	s.limit("total_reserved_route_ports", quota.TotalReservedRoutePorts, &newQuota.TotalReservedRoutePorts)
+	s.limit("total_service_keys", quota.TotalServiceKeys, &newQuota.TotalServiceKeys)
+	s.limit("app_instance_limit", quota.AppInstanceLimit, &newQuota.AppInstanceLimit)
+	s.limit("app_task_limit", quota.AppTaskLimit, &newQuota.AppTaskLimit)
+	return s.changes
+}
+
+func deferSpaceQuotaShrinks(quota cfclient.SpaceQuota, newQuota *cfclient.SpaceQuotaRequest) changes {
+	s := shrinks{changes: changes{}}
+	s.memory("memory-limit", quota.MemoryLimit, &newQuota.MemoryLimit)
+	s.memory("instance-memory-limit", quota.InstanceMemoryLimit, &newQuota.InstanceMemoryLimit)
+	s.limit("total-routes", quota.TotalRoutes, &newQuota.TotalRoutes)
+	s.limit("total-services", quota.TotalServices, &newQuota.TotalServices)
+	s.flag("paid-service-plans-allowed", quota.NonBasicServicesAllowed, &newQuota.NonBasicServicesAllowed)
+	s.limit("total_reserved_route_ports", quota.TotalReservedRoutePorts, &newQuota.TotalReservedRoutePorts)
+	s.limit("total_service_keys", quota.TotalServiceKeys, &newQuota.TotalServiceKeys)
+	s.limit("app_instance_limit", quota.AppInstanceLimit, &newQuota.AppInstanceLimit)
+	s.limit("app_task_limit", quota.AppTaskLimit, &newQuota.AppTaskLimit)
+	return s.changes
+}
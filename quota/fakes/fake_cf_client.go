@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"net/url"
 	"sync"
 
 	go_cfclient "github.com/cloudfoundry-community/go-cfclient"
@@ -90,6 +91,33 @@ type FakeCFClient struct {
 		result1 go_cfclient.OrgQuota
 		result2 error
 	}
+	ListAppsByQueryStub        func(query url.Values) ([]go_cfclient.App, error)
+	listAppsByQueryMutex       sync.RWMutex
+	listAppsByQueryArgsForCall []struct {
+		query url.Values
+	}
+	listAppsByQueryReturns struct {
+		result1 []go_cfclient.App
+		result2 error
+	}
+	ListRoutesByQueryStub        func(query url.Values) ([]go_cfclient.Route, error)
+	listRoutesByQueryMutex       sync.RWMutex
+	listRoutesByQueryArgsForCall []struct {
+		query url.Values
+	}
+	listRoutesByQueryReturns struct {
+		result1 []go_cfclient.Route
+		result2 error
+	}
+	ListOrgManagersStub        func(orgGUID string) ([]go_cfclient.User, error)
+	listOrgManagersMutex       sync.RWMutex
+	listOrgManagersArgsForCall []struct {
+		orgGUID string
+	}
+	listOrgManagersReturns struct {
+		result1 []go_cfclient.User
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -394,6 +422,108 @@ func (fake *FakeCFClient) GetOrgQuotaByNameReturns(result1 go_cfclient.OrgQuota,
 	}{result1, result2}
 }
 
+func (fake *FakeCFClient) ListAppsByQuery(query url.Values) ([]go_cfclient.App, error) {
+	fake.listAppsByQueryMutex.Lock()
+	fake.listAppsByQueryArgsForCall = append(fake.listAppsByQueryArgsForCall, struct {
+		query url.Values
+	}{query})
+	fake.recordInvocation("ListAppsByQuery", []interface{}{query})
+	fake.listAppsByQueryMutex.Unlock()
+	if fake.ListAppsByQueryStub != nil {
+		return fake.ListAppsByQueryStub(query)
+	} else {
+		return fake.listAppsByQueryReturns.result1, fake.listAppsByQueryReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) ListAppsByQueryCallCount() int {
+	fake.listAppsByQueryMutex.RLock()
+	defer fake.listAppsByQueryMutex.RUnlock()
+	return len(fake.listAppsByQueryArgsForCall)
+}
+
+func (fake *FakeCFClient) ListAppsByQueryArgsForCall(i int) url.Values {
+	fake.listAppsByQueryMutex.RLock()
+	defer fake.listAppsByQueryMutex.RUnlock()
+	return fake.listAppsByQueryArgsForCall[i].query
+}
+
+func (fake *FakeCFClient) ListAppsByQueryReturns(result1 []go_cfclient.App, result2 error) {
+	fake.ListAppsByQueryStub = nil
+	fake.listAppsByQueryReturns = struct {
+		result1 []go_cfclient.App
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) ListRoutesByQuery(query url.Values) ([]go_cfclient.Route, error) {
+	fake.listRoutesByQueryMutex.Lock()
+	fake.listRoutesByQueryArgsForCall = append(fake.listRoutesByQueryArgsForCall, struct {
+		query url.Values
+	}{query})
+	fake.recordInvocation("ListRoutesByQuery", []interface{}{query})
+	fake.listRoutesByQueryMutex.Unlock()
+	if fake.ListRoutesByQueryStub != nil {
+		return fake.ListRoutesByQueryStub(query)
+	} else {
+		return fake.listRoutesByQueryReturns.result1, fake.listRoutesByQueryReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) ListRoutesByQueryCallCount() int {
+	fake.listRoutesByQueryMutex.RLock()
+	defer fake.listRoutesByQueryMutex.RUnlock()
+	return len(fake.listRoutesByQueryArgsForCall)
+}
+
+func (fake *FakeCFClient) ListRoutesByQueryArgsForCall(i int) url.Values {
+	fake.listRoutesByQueryMutex.RLock()
+	defer fake.listRoutesByQueryMutex.RUnlock()
+	return fake.listRoutesByQueryArgsForCall[i].query
+}
+
+func (fake *FakeCFClient) ListRoutesByQueryReturns(result1 []go_cfclient.Route, result2 error) {
+	fake.ListRoutesByQueryStub = nil
+	fake.listRoutesByQueryReturns = struct {
+		result1 []go_cfclient.Route
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) ListOrgManagers(orgGUID string) ([]go_cfclient.User, error) {
+	fake.listOrgManagersMutex.Lock()
+	fake.listOrgManagersArgsForCall = append(fake.listOrgManagersArgsForCall, struct {
+		orgGUID string
+	}{orgGUID})
+	fake.recordInvocation("ListOrgManagers", []interface{}{orgGUID})
+	fake.listOrgManagersMutex.Unlock()
+	if fake.ListOrgManagersStub != nil {
+		return fake.ListOrgManagersStub(orgGUID)
+	} else {
+		return fake.listOrgManagersReturns.result1, fake.listOrgManagersReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) ListOrgManagersCallCount() int {
+	fake.listOrgManagersMutex.RLock()
+	defer fake.listOrgManagersMutex.RUnlock()
+	return len(fake.listOrgManagersArgsForCall)
+}
+
+func (fake *FakeCFClient) ListOrgManagersArgsForCall(i int) string {
+	fake.listOrgManagersMutex.RLock()
+	defer fake.listOrgManagersMutex.RUnlock()
+	return fake.listOrgManagersArgsForCall[i].orgGUID
+}
+
+func (fake *FakeCFClient) ListOrgManagersReturns(result1 []go_cfclient.User, result2 error) {
+	fake.ListOrgManagersStub = nil
+	fake.listOrgManagersReturns = struct {
+		result1 []go_cfclient.User
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeCFClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -415,6 +545,12 @@ func (fake *FakeCFClient) Invocations() map[string][][]interface{} {
 	defer fake.updateOrgQuotaMutex.RUnlock()
 	fake.getOrgQuotaByNameMutex.RLock()
 	defer fake.getOrgQuotaByNameMutex.RUnlock()
+	fake.listAppsByQueryMutex.RLock()
+	defer fake.listAppsByQueryMutex.RUnlock()
+	fake.listRoutesByQueryMutex.RLock()
+	defer fake.listRoutesByQueryMutex.RUnlock()
+	fake.listOrgManagersMutex.RLock()
+	defer fake.listOrgManagersMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	go_cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pivotalservices/cf-mgmt/config"
 	"github.com/pivotalservices/cf-mgmt/quota"
 )
 
@@ -39,6 +40,29 @@ type FakeManager struct {
 		result1 go_cfclient.OrgQuota
 		result2 error
 	}
+	QuotaUtilizationStub        func() ([]quota.Utilization, error)
+	quotaUtilizationMutex       sync.RWMutex
+	quotaUtilizationArgsForCall []struct{}
+	quotaUtilizationReturns     struct {
+		result1 []quota.Utilization
+		result2 error
+	}
+	NotifyBreachesStub        func(utilizations []quota.Utilization) (int, error)
+	notifyBreachesMutex       sync.RWMutex
+	notifyBreachesArgsForCall []struct {
+		utilizations []quota.Utilization
+	}
+	notifyBreachesReturns struct {
+		result1 int
+		result2 error
+	}
+	SpaceQuotaOvercommitsStub        func() ([]config.QuotaOvercommit, error)
+	spaceQuotaOvercommitsMutex       sync.RWMutex
+	spaceQuotaOvercommitsArgsForCall []struct{}
+	spaceQuotaOvercommitsReturns     struct {
+		result1 []config.QuotaOvercommit
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -161,6 +185,97 @@ func (fake *FakeManager) OrgQuotaByNameReturns(result1 go_cfclient.OrgQuota, res
 	}{result1, result2}
 }
 
+func (fake *FakeManager) QuotaUtilization() ([]quota.Utilization, error) {
+	fake.quotaUtilizationMutex.Lock()
+	fake.quotaUtilizationArgsForCall = append(fake.quotaUtilizationArgsForCall, struct{}{})
+	fake.recordInvocation("QuotaUtilization", []interface{}{})
+	fake.quotaUtilizationMutex.Unlock()
+	if fake.QuotaUtilizationStub != nil {
+		return fake.QuotaUtilizationStub()
+	} else {
+		return fake.quotaUtilizationReturns.result1, fake.quotaUtilizationReturns.result2
+	}
+}
+
+func (fake *FakeManager) QuotaUtilizationCallCount() int {
+	fake.quotaUtilizationMutex.RLock()
+	defer fake.quotaUtilizationMutex.RUnlock()
+	return len(fake.quotaUtilizationArgsForCall)
+}
+
+func (fake *FakeManager) QuotaUtilizationReturns(result1 []quota.Utilization, result2 error) {
+	fake.QuotaUtilizationStub = nil
+	fake.quotaUtilizationReturns = struct {
+		result1 []quota.Utilization
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) NotifyBreaches(utilizations []quota.Utilization) (int, error) {
+	var utilizationsCopy []quota.Utilization
+	if utilizations != nil {
+		utilizationsCopy = make([]quota.Utilization, len(utilizations))
+		copy(utilizationsCopy, utilizations)
+	}
+	fake.notifyBreachesMutex.Lock()
+	fake.notifyBreachesArgsForCall = append(fake.notifyBreachesArgsForCall, struct {
+		utilizations []quota.Utilization
+	}{utilizationsCopy})
+	fake.recordInvocation("NotifyBreaches", []interface{}{utilizationsCopy})
+	fake.notifyBreachesMutex.Unlock()
+	if fake.NotifyBreachesStub != nil {
+		return fake.NotifyBreachesStub(utilizations)
+	} else {
+		return fake.notifyBreachesReturns.result1, fake.notifyBreachesReturns.result2
+	}
+}
+
+func (fake *FakeManager) NotifyBreachesCallCount() int {
+	fake.notifyBreachesMutex.RLock()
+	defer fake.notifyBreachesMutex.RUnlock()
+	return len(fake.notifyBreachesArgsForCall)
+}
+
+func (fake *FakeManager) NotifyBreachesArgsForCall(i int) []quota.Utilization {
+	fake.notifyBreachesMutex.RLock()
+	defer fake.notifyBreachesMutex.RUnlock()
+	return fake.notifyBreachesArgsForCall[i].utilizations
+}
+
+func (fake *FakeManager) NotifyBreachesReturns(result1 int, result2 error) {
+	fake.NotifyBreachesStub = nil
+	fake.notifyBreachesReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) SpaceQuotaOvercommits() ([]config.QuotaOvercommit, error) {
+	fake.spaceQuotaOvercommitsMutex.Lock()
+	fake.spaceQuotaOvercommitsArgsForCall = append(fake.spaceQuotaOvercommitsArgsForCall, struct{}{})
+	fake.recordInvocation("SpaceQuotaOvercommits", []interface{}{})
+	fake.spaceQuotaOvercommitsMutex.Unlock()
+	if fake.SpaceQuotaOvercommitsStub != nil {
+		return fake.SpaceQuotaOvercommitsStub()
+	} else {
+		return fake.spaceQuotaOvercommitsReturns.result1, fake.spaceQuotaOvercommitsReturns.result2
+	}
+}
+
+func (fake *FakeManager) SpaceQuotaOvercommitsCallCount() int {
+	fake.spaceQuotaOvercommitsMutex.RLock()
+	defer fake.spaceQuotaOvercommitsMutex.RUnlock()
+	return len(fake.spaceQuotaOvercommitsArgsForCall)
+}
+
+func (fake *FakeManager) SpaceQuotaOvercommitsReturns(result1 []config.QuotaOvercommit, result2 error) {
+	fake.SpaceQuotaOvercommitsStub = nil
+	fake.spaceQuotaOvercommitsReturns = struct {
+		result1 []config.QuotaOvercommit
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeManager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -172,6 +287,12 @@ func (fake *FakeManager) Invocations() map[string][][]interface{} {
 	defer fake.createOrgQuotasMutex.RUnlock()
 	fake.orgQuotaByNameMutex.RLock()
 	defer fake.orgQuotaByNameMutex.RUnlock()
+	fake.quotaUtilizationMutex.RLock()
+	defer fake.quotaUtilizationMutex.RUnlock()
+	fake.notifyBreachesMutex.RLock()
+	defer fake.notifyBreachesMutex.RUnlock()
+	fake.spaceQuotaOvercommitsMutex.RLock()
+	defer fake.spaceQuotaOvercommitsMutex.RUnlock()
 	return fake.invocations
 }
 
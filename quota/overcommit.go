@@ -0,0 +1,101 @@
+package quota
+
+import (
+	"sort"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/xchapter7x/lo"
+)
+
+//SpaceQuotaOvercommits - the limits of the quota of each existing org that the quotas assigned to its spaces add
+//up to more than, beyond the space-quota-tolerance of cf-mgmt.yml, reporting drift such as space quotas
+//created or resized outside of cf-mgmt
+func (m *DefaultManager) SpaceQuotaOvercommits() ([]config.QuotaOvercommit, error) {
+	globalConfig, err := m.Cfg.GetGlobalConfig()
+	if err != nil {
+		return nil, err
+	}
+	tolerance := 0
+	if globalConfig != nil {
+		tolerance = globalConfig.SpaceQuotaTolerance
+	}
+	orgs, err := m.OrgMgr.ListOrgs()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(orgs, func(i, j int) bool {
+		return orgs[i].Name < orgs[j].Name
+	})
+	orgQuotas, err := m.Client.ListOrgQuotas()
+	if err != nil {
+		return nil, err
+	}
+	quotasByGUID := make(map[string]cfclient.OrgQuota)
+	for _, orgQuota := range orgQuotas {
+		quotasByGUID[orgQuota.Guid] = orgQuota
+	}
+
+	overcommits := []config.QuotaOvercommit{}
+	for _, org := range orgs {
+		orgQuota, ok := quotasByGUID[org.QuotaDefinitionGuid]
+		if !ok {
+			lo.G.Debugf("Quota of org %s was not found, skipping its space quotas", org.Name)
+			continue
+		}
+		spaceLimits, err := m.spaceQuotaLimits(org)
+		if err != nil {
+			return nil, err
+		}
+		overcommits = append(overcommits, config.Overcommits(org.Name, orgQuotaLimits(orgQuota), spaceLimits, tolerance)...)
+	}
+	return overcommits, nil
+}
+
+// spaceQuotaLimits are the limits of the space quotas assigned to the spaces
+// of org, by space name
+func (m *DefaultManager) spaceQuotaLimits(org cfclient.Org) (map[string]config.QuotaLimits, error) {
+	spaces, err := m.SpaceMgr.ListSpaces(org.Guid)
+	if err != nil {
+		return nil, err
+	}
+	spaceQuotas, err := m.Client.ListOrgSpaceQuotas(org.Guid)
+	if err != nil {
+		return nil, err
+	}
+	quotasByGUID := make(map[string]cfclient.SpaceQuota)
+	for _, spaceQuota := range spaceQuotas {
+		quotasByGUID[spaceQuota.Guid] = spaceQuota
+	}
+	spaceLimits := make(map[string]config.QuotaLimits)
+	for _, space := range spaces {
+		if spaceQuota, ok := quotasByGUID[space.QuotaDefinitionGuid]; ok {
+			spaceLimits[space.Name] = spaceQuotaLimits(spaceQuota)
+		}
+	}
+	return spaceLimits, nil
+}
+
+func orgQuotaLimits(quota cfclient.OrgQuota) config.QuotaLimits {
+	return config.QuotaLimits{
+		"memory-limit":               quota.MemoryLimit,
+		"total-routes":               quota.TotalRoutes,
+		"total-services":             quota.TotalServices,
+		"total_reserved_route_ports": quota.TotalReservedRoutePorts,
+		"total_service_keys":         quota.TotalServiceKeys,
+		"app_instance_limit":         quota.AppInstanceLimit,
+		"app_task_limit":             quota.AppTaskLimit,
+	}
+}
+
+func spaceQuotaLimits(quota cfclient.SpaceQuota) config.QuotaLimits {
+	return config.QuotaLimits{
+		"memory-limit":               quota.MemoryLimit,
+		"total-routes":               quota.TotalRoutes,
+		"total-services":             quota.TotalServices,
+		"total_reserved_route_ports": quota.TotalReservedRoutePorts,
+		"total_service_keys":         quota.TotalServiceKeys,
+		"app_instance_limit":         quota.AppInstanceLimit,
+		"app_task_limit":             quota.AppTaskLimit,
+	}
+}
@@ -0,0 +1,85 @@
+package quota_test
+
+import (
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/config"
+	configfakes "github.com/pivotalservices/cf-mgmt/config/fakes"
+	orgfakes "github.com/pivotalservices/cf-mgmt/organization/fakes"
+	"github.com/pivotalservices/cf-mgmt/quota"
+	quotafakes "github.com/pivotalservices/cf-mgmt/quota/fakes"
+	spacefakes "github.com/pivotalservices/cf-mgmt/space/fakes"
+)
+
+var _ = Describe("Space Quota Overcommits", func() {
+	var (
+		fakeReader   *configfakes.FakeReader
+		fakeOrgMgr   *orgfakes.FakeManager
+		fakeSpaceMgr *spacefakes.FakeManager
+		fakeClient   *quotafakes.FakeCFClient
+		quotaMgr     quota.DefaultManager
+	)
+
+	BeforeEach(func() {
+		fakeReader = new(configfakes.FakeReader)
+		fakeOrgMgr = new(orgfakes.FakeManager)
+		fakeSpaceMgr = new(spacefakes.FakeManager)
+		fakeClient = new(quotafakes.FakeCFClient)
+		quotaMgr = quota.DefaultManager{
+			Cfg:      fakeReader,
+			Client:   fakeClient,
+			OrgMgr:   fakeOrgMgr,
+			SpaceMgr: fakeSpaceMgr,
+		}
+		fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{}, nil)
+		fakeOrgMgr.ListOrgsReturns([]cfclient.Org{
+			{Name: "payments", Guid: "payments-guid", QuotaDefinitionGuid: "payments-quota-guid"},
+			{Name: "no-quota", Guid: "no-quota-guid"},
+		}, nil)
+		fakeClient.ListOrgQuotasReturns([]cfclient.OrgQuota{
+			{Name: "payments", Guid: "payments-quota-guid", MemoryLimit: 10240, TotalRoutes: 100, AppInstanceLimit: -1},
+		}, nil)
+		fakeSpaceMgr.ListSpacesReturns([]cfclient.Space{
+			{Name: "prod", QuotaDefinitionGuid: "prod-quota-guid"},
+			{Name: "dev", QuotaDefinitionGuid: "dev-quota-guid"},
+			{Name: "sandbox"},
+		}, nil)
+		fakeClient.ListOrgSpaceQuotasReturns([]cfclient.SpaceQuota{
+			{Name: "prod", Guid: "prod-quota-guid", MemoryLimit: 8192, TotalRoutes: 50, AppInstanceLimit: 40},
+			{Name: "dev", Guid: "dev-quota-guid", MemoryLimit: 4096, TotalRoutes: 50, AppInstanceLimit: 40},
+		}, nil)
+	})
+
+	It("reports the limits of org quotas that the assigned space quotas add up to more than", func() {
+		overcommits, err := quotaMgr.SpaceQuotaOvercommits()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(overcommits).Should(Equal([]config.QuotaOvercommit{
+			{Org: "payments", Limit: "memory-limit", OrgLimit: 10240, SpaceTotal: 12288, Spaces: []string{"dev", "prod"}},
+		}))
+		Expect(fakeSpaceMgr.ListSpacesCallCount()).Should(Equal(1))
+		Expect(fakeClient.ListOrgSpaceQuotasArgsForCall(0)).Should(Equal("payments-guid"))
+	})
+
+	It("allows the space-quota-tolerance of cf-mgmt.yml beyond the org quota", func() {
+		fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{SpaceQuotaTolerance: 20}, nil)
+		overcommits, err := quotaMgr.SpaceQuotaOvercommits()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(overcommits).Should(BeEmpty())
+	})
+
+	It("counts a named space quota shared by spaces once for each space", func() {
+		fakeSpaceMgr.ListSpacesReturns([]cfclient.Space{
+			{Name: "prod", QuotaDefinitionGuid: "medium-quota-guid"},
+			{Name: "dev", QuotaDefinitionGuid: "medium-quota-guid"},
+		}, nil)
+		fakeClient.ListOrgSpaceQuotasReturns([]cfclient.SpaceQuota{
+			{Name: "medium", Guid: "medium-quota-guid", MemoryLimit: 6144, TotalRoutes: 10},
+		}, nil)
+		overcommits, err := quotaMgr.SpaceQuotaOvercommits()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(overcommits).Should(Equal([]config.QuotaOvercommit{
+			{Org: "payments", Limit: "memory-limit", OrgLimit: 10240, SpaceTotal: 12288, Spaces: []string{"dev", "prod"}},
+		}))
+	})
+})
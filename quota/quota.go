@@ -1,8 +1,11 @@
 package quota
 
 import (
+	"time"
+
 	cfclient "github.com/cloudfoundry-community/go-cfclient"
 	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/email"
 	"github.com/pivotalservices/cf-mgmt/organization"
 	"github.com/pivotalservices/cf-mgmt/space"
 	"github.com/xchapter7x/lo"
@@ -29,6 +32,8 @@ type DefaultManager struct {
 	SpaceMgr space.Manager
 	OrgMgr   organization.Manager
 	Peek     bool
+	// Sender emails the quota alerts, created from quota-notifications when nil
+	Sender *email.Sender
 }
 
 //CreateSpaceQuotas -
@@ -37,6 +42,22 @@ func (m *DefaultManager) CreateSpaceQuotas() error {
 	if err != nil {
 		return err
 	}
+	spaceConfigs, err = space.ExpandSpaceConfigs(m.SpaceMgr, spaceConfigs)
+	if err != nil {
+		return err
+	}
+	orgConfigs, err := m.Cfg.GetOrgConfigs()
+	if err != nil {
+		return err
+	}
+	deferred, err := config.OrgsOutsideMaintenanceWindow(orgConfigs, time.Now())
+	if err != nil {
+		return err
+	}
+	approvals, err := m.Cfg.GetApprovals()
+	if err != nil {
+		return err
+	}
 	for _, input := range spaceConfigs {
 		if !input.EnableSpaceQuota {
 			continue
@@ -65,8 +86,17 @@ func (m *DefaultManager) CreateSpaceQuotas() error {
 		var spaceQuota cfclient.SpaceQuota
 		var ok bool
 		if spaceQuota, ok = quotas[space.Name]; ok {
-			if m.hasSpaceQuotaChanged(spaceQuota, quota) {
-				if err := m.UpdateSpaceQuota(spaceQuota.Guid, quota); err != nil {
+			if deferred[input.Org] {
+				if shrinks := deferSpaceQuotaShrinks(spaceQuota, &quota); len(shrinks) > 0 {
+					lo.G.Infof("Deferring shrinking space quota %s%s until the maintenance window of org %s", quota.Name, shrinks, input.Org)
+				}
+			} else if kept := quota; len(deferSpaceQuotaShrinks(spaceQuota, &kept)) > 0 {
+				if err := approvals.Check(config.Change{Kind: config.ChangeShrinkQuota, Org: input.Org, Space: input.Space}); err != nil {
+					return err
+				}
+			}
+			if changes := spaceQuotaChanges(spaceQuota, quota); len(changes) > 0 {
+				if err := m.updateSpaceQuota(spaceQuota.Guid, quota, changes); err != nil {
 					return err
 				}
 			}
@@ -87,28 +117,6 @@ func (m *DefaultManager) CreateSpaceQuotas() error {
 	return nil
 }
 
-func (m *DefaultManager) hasSpaceQuotaChanged(quota cfclient.SpaceQuota, newQuota cfclient.SpaceQuotaRequest) bool {
-	quoteRequest := cfclient.SpaceQuotaRequest{
-		Name:                    quota.Name,
-		OrganizationGuid:        quota.OrganizationGuid,
-		MemoryLimit:             quota.MemoryLimit,
-		InstanceMemoryLimit:     quota.InstanceMemoryLimit,
-		TotalRoutes:             quota.TotalRoutes,
-		TotalServices:           quota.TotalServices,
-		NonBasicServicesAllowed: quota.NonBasicServicesAllowed,
-		TotalReservedRoutePorts: quota.TotalReservedRoutePorts,
-		TotalServiceKeys:        quota.TotalServiceKeys,
-		AppInstanceLimit:        quota.AppInstanceLimit,
-		AppTaskLimit:            quota.AppTaskLimit,
-	}
-	if quoteRequest == newQuota {
-		return false
-	} else {
-		lo.G.Debugf("Quota has changed from %v to %v", quoteRequest, newQuota)
-		return true
-	}
-}
-
 func (m *DefaultManager) ListAllSpaceQuotasForOrg(orgGUID string) (map[string]cfclient.SpaceQuota, error) {
 	quotas := make(map[string]cfclient.SpaceQuota)
 	spaceQuotas, err := m.Client.ListOrgSpaceQuotas(orgGUID)
@@ -123,11 +131,15 @@ func (m *DefaultManager) ListAllSpaceQuotasForOrg(orgGUID string) (map[string]cf
 }
 
 func (m *DefaultManager) UpdateSpaceQuota(quotaGUID string, quota cfclient.SpaceQuotaRequest) error {
+	return m.updateSpaceQuota(quotaGUID, quota, nil)
+}
+
+func (m *DefaultManager) updateSpaceQuota(quotaGUID string, quota cfclient.SpaceQuotaRequest, changes changes) error {
 	if m.Peek {
-		lo.G.Infof("[dry-run]: update space quota %s", quota.Name)
+		lo.G.Infof("[dry-run]: update space quota %s%s", quota.Name, changes)
 		return nil
 	}
-	lo.G.Infof("Updating space quota %s", quota.Name)
+	lo.G.Infof("Updating space quota %s%s", quota.Name, changes)
 	_, err := m.Client.UpdateSpaceQuota(quotaGUID, quota)
 	return err
 }
@@ -169,6 +181,14 @@ func (m *DefaultManager) CreateOrgQuotas() error {
 	if err != nil {
 		return err
 	}
+	deferred, err := config.OrgsOutsideMaintenanceWindow(orgs, time.Now())
+	if err != nil {
+		return err
+	}
+	approvals, err := m.Cfg.GetApprovals()
+	if err != nil {
+		return err
+	}
 
 	for _, input := range orgs {
 		if !input.EnableOrgQuota {
@@ -196,8 +216,17 @@ func (m *DefaultManager) CreateOrgQuotas() error {
 		var orgQuota cfclient.OrgQuota
 		var ok bool
 		if orgQuota, ok = quotas[quotaName]; ok {
-			if m.hasOrgQuotaChanged(orgQuota, quota) {
-				if err = m.UpdateOrgQuota(orgQuota.Guid, quota); err != nil {
+			if deferred[input.Org] {
+				if shrinks := deferOrgQuotaShrinks(orgQuota, &quota); len(shrinks) > 0 {
+					lo.G.Infof("Deferring shrinking org quota %s%s until the maintenance window of the org", quota.Name, shrinks)
+				}
+			} else if kept := quota; len(deferOrgQuotaShrinks(orgQuota, &kept)) > 0 {
+				if err := approvals.Check(config.Change{Kind: config.ChangeShrinkQuota, Org: input.Org}); err != nil {
+					return err
+				}
+			}
+			if changes := orgQuotaChanges(orgQuota, quota); len(changes) > 0 {
+				if err = m.updateOrgQuota(orgQuota.Guid, quota, changes); err != nil {
 					return err
 				}
 			}
@@ -217,28 +246,6 @@ func (m *DefaultManager) CreateOrgQuotas() error {
 	return nil
 }
 
-func (m *DefaultManager) hasOrgQuotaChanged(quota cfclient.OrgQuota, newQuota cfclient.OrgQuotaRequest) bool {
-	quoteRequest := cfclient.OrgQuotaRequest{
-		Name:                    quota.Name,
-		TotalPrivateDomains:     quota.TotalPrivateDomains,
-		MemoryLimit:             quota.MemoryLimit,
-		InstanceMemoryLimit:     quota.InstanceMemoryLimit,
-		TotalRoutes:             quota.TotalRoutes,
-		TotalServices:           quota.TotalServices,
-		NonBasicServicesAllowed: quota.NonBasicServicesAllowed,
-		TotalReservedRoutePorts: quota.TotalReservedRoutePorts,
-		TotalServiceKeys:        quota.TotalServiceKeys,
-		AppInstanceLimit:        quota.AppInstanceLimit,
-		AppTaskLimit:            quota.AppTaskLimit,
-	}
-	if quoteRequest == newQuota {
-		return false
-	} else {
-		lo.G.Debugf("Quota has changed from %v to %v", quoteRequest, newQuota)
-		return true
-	}
-}
-
 func (m *DefaultManager) ListAllOrgQuotas() (map[string]cfclient.OrgQuota, error) {
 	quotas := make(map[string]cfclient.OrgQuota)
 	orgQutotas, err := m.Client.ListOrgQuotas()
@@ -267,11 +274,15 @@ func (m *DefaultManager) CreateOrgQuota(quota cfclient.OrgQuotaRequest) (*cfclie
 }
 
 func (m *DefaultManager) UpdateOrgQuota(quotaGUID string, quota cfclient.OrgQuotaRequest) error {
+	return m.updateOrgQuota(quotaGUID, quota, nil)
+}
+
+func (m *DefaultManager) updateOrgQuota(quotaGUID string, quota cfclient.OrgQuotaRequest, changes changes) error {
 	if m.Peek {
-		lo.G.Infof("[dry-run]: update org quota %s", quota.Name)
+		lo.G.Infof("[dry-run]: update org quota %s%s", quota.Name, changes)
 		return nil
 	}
-	lo.G.Infof("Updating org quota %s", quota.Name)
+	lo.G.Infof("Updating org quota %s%s", quota.Name, changes)
 	_, err := m.Client.UpdateOrgQuota(quotaGUID, quota)
 	return err
 }
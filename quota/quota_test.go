@@ -2,6 +2,7 @@ package quota_test
 
 import (
 	"errors"
+	"fmt"
 
 	cfclient "github.com/cloudfoundry-community/go-cfclient"
 	. "github.com/onsi/ginkgo"
@@ -12,6 +13,7 @@ import (
 	"github.com/pivotalservices/cf-mgmt/quota"
 	quotafakes "github.com/pivotalservices/cf-mgmt/quota/fakes"
 	spacefakes "github.com/pivotalservices/cf-mgmt/space/fakes"
+	"github.com/xchapter7x/lo"
 )
 
 var _ = Describe("given QuotaManager", func() {
@@ -308,6 +310,84 @@ var _ = Describe("given QuotaManager", func() {
 			Expect(fakeOrgMgr.UpdateOrgCallCount()).Should(Equal(0))
 		})
 
+		It("should log the changed quota fields", func() {
+			logger := lo.G
+			infos := &infoLogger{Logger: logger}
+			lo.G = infos
+			defer func() { lo.G = logger }()
+			fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+				config.OrgConfig{
+					EnableOrgQuota:          true,
+					Org:                     "org1",
+					MemoryLimit:             20480,
+					InstanceMemoryLimit:     -1,
+					TotalRoutes:             100,
+					PaidServicePlansAllowed: true,
+				},
+			}, nil)
+			fakeOrgMgr.FindOrgReturns(cfclient.Org{Name: "org1", Guid: "org-guid", QuotaDefinitionGuid: "org-quota-guid"}, nil)
+			fakeClient.ListOrgQuotasReturns([]cfclient.OrgQuota{
+				cfclient.OrgQuota{
+					Name:                "org1",
+					Guid:                "org-quota-guid",
+					MemoryLimit:         10240,
+					InstanceMemoryLimit: 512,
+					TotalRoutes:         100,
+				},
+			}, nil)
+			err := quotaMgr.CreateOrgQuotas()
+			Expect(err).Should(BeNil())
+			Expect(fakeClient.UpdateOrgQuotaCallCount()).Should(Equal(1))
+			Expect(infos.messages).Should(ConsistOf("Updating org quota org1 (memory-limit 10G -> 20G, instance-memory-limit 512M -> unlimited, paid-service-plans-allowed false -> true)"))
+		})
+
+		It("should defer shrinking a quota outside the maintenance window", func() {
+			fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+				config.OrgConfig{
+					EnableOrgQuota:      true,
+					Org:                 "org1",
+					MaintenanceWindow:   "0 0 30 2 *",
+					MemoryLimit:         20480,
+					InstanceMemoryLimit: 1024,
+					TotalRoutes:         50,
+				},
+			}, nil)
+			fakeOrgMgr.FindOrgReturns(cfclient.Org{Name: "org1", Guid: "org-guid", QuotaDefinitionGuid: "org-quota-guid"}, nil)
+			fakeClient.ListOrgQuotasReturns([]cfclient.OrgQuota{
+				cfclient.OrgQuota{
+					Name:                    "org1",
+					Guid:                    "org-quota-guid",
+					MemoryLimit:             10240,
+					InstanceMemoryLimit:     -1,
+					TotalRoutes:             100,
+					NonBasicServicesAllowed: true,
+				},
+			}, nil)
+			err := quotaMgr.CreateOrgQuotas()
+			Expect(err).Should(BeNil())
+			Expect(fakeClient.UpdateOrgQuotaCallCount()).Should(Equal(1))
+			_, quotaRequest := fakeClient.UpdateOrgQuotaArgsForCall(0)
+			Expect(quotaRequest.MemoryLimit).Should(Equal(20480))
+			Expect(quotaRequest.InstanceMemoryLimit).Should(Equal(-1))
+			Expect(quotaRequest.TotalRoutes).Should(Equal(100))
+			Expect(quotaRequest.NonBasicServicesAllowed).Should(BeTrue())
+		})
+
+		It("should require an approval to shrink a quota", func() {
+			fakeReader.GetApprovalsReturns(&config.Approvals{RequireApprovals: true}, nil)
+			fakeOrgMgr.FindOrgReturns(cfclient.Org{Name: "org1", Guid: "org-guid", QuotaDefinitionGuid: "org-quota-guid"}, nil)
+			fakeClient.ListOrgQuotasReturns([]cfclient.OrgQuota{
+				cfclient.OrgQuota{
+					Name:        "org1",
+					Guid:        "org-quota-guid",
+					TotalRoutes: 100,
+				},
+			}, nil)
+			err := quotaMgr.CreateOrgQuotas()
+			Expect(err).Should(MatchError("approvals.yml has no approval for: shrink-quota org1"))
+			Expect(fakeClient.UpdateOrgQuotaCallCount()).Should(Equal(0))
+		})
+
 		It("should not update a quota or assign it", func() {
 			fakeOrgMgr.FindOrgReturns(cfclient.Org{Name: "org1", Guid: "org-guid", QuotaDefinitionGuid: "org-quota-guid"}, nil)
 			fakeClient.ListOrgQuotasReturns([]cfclient.OrgQuota{
@@ -489,3 +569,12 @@ var _ = Describe("given QuotaManager", func() {
 	})*/
 
 })
+
+type infoLogger struct {
+	lo.Logger
+	messages []string
+}
+
+func (l *infoLogger) Infof(format string, args ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, args...))
+}
@@ -1,7 +1,10 @@
 package quota
 
 import (
+	"net/url"
+
 	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pivotalservices/cf-mgmt/config"
 )
 
 type Manager interface {
@@ -9,6 +12,9 @@ type Manager interface {
 	SpaceQuotaByName(name string) (cfclient.SpaceQuota, error)
 	CreateOrgQuotas() error
 	OrgQuotaByName(name string) (cfclient.OrgQuota, error)
+	QuotaUtilization() ([]Utilization, error)
+	NotifyBreaches(utilizations []Utilization) (int, error)
+	SpaceQuotaOvercommits() ([]config.QuotaOvercommit, error)
 }
 
 type CFClient interface {
@@ -21,4 +27,7 @@ type CFClient interface {
 	CreateOrgQuota(orgQuote cfclient.OrgQuotaRequest) (*cfclient.OrgQuota, error)
 	UpdateOrgQuota(orgQuotaGUID string, orgQuota cfclient.OrgQuotaRequest) (*cfclient.OrgQuota, error)
 	GetOrgQuotaByName(name string) (cfclient.OrgQuota, error)
+	ListAppsByQuery(query url.Values) ([]cfclient.App, error)
+	ListRoutesByQuery(query url.Values) ([]cfclient.Route, error)
+	ListOrgManagers(orgGUID string) ([]cfclient.User, error)
 }
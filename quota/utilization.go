@@ -0,0 +1,208 @@
+package quota
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/email"
+	"github.com/xchapter7x/lo"
+)
+
+// Resources of an org quota that quota-alerts set thresholds for.
+const (
+	ResourceMemory       = "memory"
+	ResourceAppInstances = "app-instances"
+	ResourceRoutes       = "routes"
+)
+
+// appStarted is the state of the apps that use memory and instances of a quota
+const appStarted = "STARTED"
+
+// Utilization is the live usage of a resource of the quota of an org against
+// the threshold of its quota-alerts. Memory is in megabytes.
+type Utilization struct {
+	Org       string `json:"org"`
+	Quota     string `json:"quota"`
+	Resource  string `json:"resource"`
+	Used      int    `json:"used"`
+	Limit     int    `json:"limit"`
+	Percent   int    `json:"percent"`
+	Threshold int    `json:"threshold"`
+	Breached  bool   `json:"breached"`
+}
+
+//QuotaUtilization - the usage of the quota of each existing org that sets quota-alerts, for every resource with
+//a threshold. Limits that are unlimited or 0 are reported without being checked.
+func (m *DefaultManager) QuotaUtilization() ([]Utilization, error) {
+	orgConfigs, err := m.Cfg.GetOrgConfigs()
+	if err != nil {
+		return nil, err
+	}
+	orgs, err := m.OrgMgr.ListOrgs()
+	if err != nil {
+		return nil, err
+	}
+	orgsByName := make(map[string]cfclient.Org)
+	for _, org := range orgs {
+		orgsByName[org.Name] = org
+	}
+	orgQuotas, err := m.Client.ListOrgQuotas()
+	if err != nil {
+		return nil, err
+	}
+	quotasByGUID := make(map[string]cfclient.OrgQuota)
+	for _, orgQuota := range orgQuotas {
+		quotasByGUID[orgQuota.Guid] = orgQuota
+	}
+
+	utilizations := []Utilization{}
+	for _, orgConfig := range orgConfigs {
+		if orgConfig.QuotaAlerts == nil {
+			continue
+		}
+		org, ok := orgsByName[orgConfig.Org]
+		if !ok {
+			lo.G.Warningf("Org %s does not exist, skipping its quota alerts", orgConfig.Org)
+			continue
+		}
+		orgQuota, ok := quotasByGUID[org.QuotaDefinitionGuid]
+		if !ok {
+			lo.G.Warningf("Quota of org %s was not found, skipping its quota alerts", orgConfig.Org)
+			continue
+		}
+		orgUtilizations, err := m.orgUtilization(org, orgQuota, *orgConfig.QuotaAlerts)
+		if err != nil {
+			return nil, err
+		}
+		utilizations = append(utilizations, orgUtilizations...)
+	}
+	sort.SliceStable(utilizations, func(i, j int) bool {
+		return utilizations[i].Org < utilizations[j].Org
+	})
+	return utilizations, nil
+}
+
+func (m *DefaultManager) orgUtilization(org cfclient.Org, orgQuota cfclient.OrgQuota, alerts config.QuotaAlerts) ([]Utilization, error) {
+	query := url.Values{"q": []string{"organization_guid:" + org.Guid}}
+	memory, instances := 0, 0
+	if alerts.Memory > 0 || alerts.AppInstances > 0 {
+		apps, err := m.Client.ListAppsByQuery(query)
+		if err != nil {
+			return nil, err
+		}
+		for _, app := range apps {
+			if app.State == appStarted {
+				memory += app.Memory * app.Instances
+				instances += app.Instances
+			}
+		}
+	}
+	routes := 0
+	if alerts.Routes > 0 {
+		orgRoutes, err := m.Client.ListRoutesByQuery(query)
+		if err != nil {
+			return nil, err
+		}
+		routes = len(orgRoutes)
+	}
+
+	var utilizations []Utilization
+	add := func(resource string, used, limit, threshold int) {
+		if threshold <= 0 {
+			return
+		}
+		utilization := Utilization{Org: org.Name, Quota: orgQuota.Name, Resource: resource, Used: used, Limit: limit, Threshold: threshold}
+		if limit > 0 {
+			utilization.Percent = used * 100 / limit
+			utilization.Breached = utilization.Percent >= threshold
+		}
+		utilizations = append(utilizations, utilization)
+	}
+	add(ResourceMemory, memory, orgQuota.MemoryLimit, alerts.Memory)
+	add(ResourceAppInstances, instances, orgQuota.AppInstanceLimit, alerts.AppInstances)
+	add(ResourceRoutes, routes, orgQuota.TotalRoutes, alerts.Routes)
+	return utilizations, nil
+}
+
+//NotifyBreaches - emails the org managers that have an email of each org with a breached threshold, as configured
+//by quota-notifications in cf-mgmt.yml, returning the number of orgs notified
+func (m *DefaultManager) NotifyBreaches(utilizations []Utilization) (int, error) {
+	breaches := make(map[string][]Utilization)
+	var orgNames []string
+	for _, utilization := range utilizations {
+		if !utilization.Breached {
+			continue
+		}
+		if _, ok := breaches[utilization.Org]; !ok {
+			orgNames = append(orgNames, utilization.Org)
+		}
+		breaches[utilization.Org] = append(breaches[utilization.Org], utilization)
+	}
+	if len(orgNames) == 0 {
+		return 0, nil
+	}
+	globalConfig, err := m.Cfg.GetGlobalConfig()
+	if err != nil {
+		return 0, err
+	}
+	if globalConfig == nil || globalConfig.QuotaNotifications == nil {
+		return 0, fmt.Errorf("notifying org managers requires quota-notifications in cf-mgmt.yml")
+	}
+	sender := m.Sender
+	if sender == nil {
+		if sender, err = email.NewSender(*globalConfig.QuotaNotifications, "quota-notifications", "Cloud Foundry org quota alert"); err != nil {
+			return 0, err
+		}
+	}
+
+	notified := 0
+	for _, orgName := range orgNames {
+		org, err := m.OrgMgr.FindOrg(orgName)
+		if err != nil {
+			return notified, err
+		}
+		managers, err := m.Client.ListOrgManagers(org.Guid)
+		if err != nil {
+			return notified, err
+		}
+		var recipients []string
+		for _, manager := range managers {
+			if strings.Contains(manager.Username, "@") {
+				recipients = append(recipients, manager.Username)
+			}
+		}
+		if len(recipients) == 0 {
+			lo.G.Warningf("Org %s breached its quota alerts but has no org manager with an email to notify", orgName)
+			continue
+		}
+		if m.Peek {
+			lo.G.Infof("[dry-run]: notifying %s of the quota alerts of org %s", strings.Join(recipients, ", "), orgName)
+			continue
+		}
+		if err := sender.Send(recipients, breachMessage(orgName, breaches[orgName])); err != nil {
+			return notified, fmt.Errorf("unable to notify the org managers of org %s: %s", orgName, err)
+		}
+		lo.G.Infof("Notified %s of the quota alerts of org %s", strings.Join(recipients, ", "), orgName)
+		notified++
+	}
+	return notified, nil
+}
+
+func breachMessage(orgName string, breaches []Utilization) string {
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "Org %s is using more of its quota %s than its alert thresholds:\n\n", orgName, breaches[0].Quota)
+	for _, breach := range breaches {
+		used, limit := formatLimit(breach.Used), formatLimit(breach.Limit)
+		if breach.Resource == ResourceMemory {
+			used, limit = formatMemory(breach.Used), formatMemory(breach.Limit)
+		}
+		fmt.Fprintf(&buffer, "- %s: %s of %s (%d%%, alert at %d%%)\n", breach.Resource, used, limit, breach.Percent, breach.Threshold)
+	}
+	fmt.Fprintf(&buffer, "\nFree up capacity or request a larger quota before the org reaches its limits.\n")
+	return buffer.String()
+}
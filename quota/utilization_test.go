@@ -0,0 +1,143 @@
+package quota_test
+
+import (
+	"net/smtp"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/config"
+	configfakes "github.com/pivotalservices/cf-mgmt/config/fakes"
+	"github.com/pivotalservices/cf-mgmt/email"
+	orgfakes "github.com/pivotalservices/cf-mgmt/organization/fakes"
+	"github.com/pivotalservices/cf-mgmt/quota"
+	quotafakes "github.com/pivotalservices/cf-mgmt/quota/fakes"
+)
+
+var _ = Describe("Quota Utilization", func() {
+	var (
+		fakeReader *configfakes.FakeReader
+		fakeOrgMgr *orgfakes.FakeManager
+		fakeClient *quotafakes.FakeCFClient
+		quotaMgr   quota.DefaultManager
+	)
+
+	BeforeEach(func() {
+		fakeReader = new(configfakes.FakeReader)
+		fakeOrgMgr = new(orgfakes.FakeManager)
+		fakeClient = new(quotafakes.FakeCFClient)
+		quotaMgr = quota.DefaultManager{
+			Cfg:    fakeReader,
+			Client: fakeClient,
+			OrgMgr: fakeOrgMgr,
+		}
+		fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+			{Org: "payments", QuotaAlerts: &config.QuotaAlerts{Memory: 80, AppInstances: 90, Routes: 50}},
+			{Org: "no-alerts"},
+			{Org: "missing", QuotaAlerts: &config.QuotaAlerts{Memory: 80}},
+		}, nil)
+		fakeOrgMgr.ListOrgsReturns([]cfclient.Org{
+			{Name: "payments", Guid: "payments-guid", QuotaDefinitionGuid: "payments-quota-guid"},
+			{Name: "no-alerts", Guid: "no-alerts-guid", QuotaDefinitionGuid: "payments-quota-guid"},
+		}, nil)
+		fakeClient.ListOrgQuotasReturns([]cfclient.OrgQuota{
+			{Name: "payments", Guid: "payments-quota-guid", MemoryLimit: 10240, AppInstanceLimit: -1, TotalRoutes: 10},
+		}, nil)
+		fakeClient.ListAppsByQueryReturns([]cfclient.App{
+			{Name: "api", State: "STARTED", Memory: 1024, Instances: 8},
+			{Name: "batch", State: "STOPPED", Memory: 4096, Instances: 2},
+		}, nil)
+		fakeClient.ListRoutesByQueryReturns([]cfclient.Route{{Guid: "route-1"}, {Guid: "route-2"}, {Guid: "route-3"}}, nil)
+	})
+
+	Context("QuotaUtilization()", func() {
+		It("reports the usage of the started apps and routes of the orgs with quota alerts", func() {
+			utilizations, err := quotaMgr.QuotaUtilization()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(utilizations).Should(Equal([]quota.Utilization{
+				{Org: "payments", Quota: "payments", Resource: quota.ResourceMemory, Used: 8192, Limit: 10240, Percent: 80, Threshold: 80, Breached: true},
+				{Org: "payments", Quota: "payments", Resource: quota.ResourceAppInstances, Used: 8, Limit: -1, Threshold: 90},
+				{Org: "payments", Quota: "payments", Resource: quota.ResourceRoutes, Used: 3, Limit: 10, Percent: 30, Threshold: 50},
+			}))
+			Expect(fakeClient.ListAppsByQueryCallCount()).Should(Equal(1))
+			Expect(fakeClient.ListAppsByQueryArgsForCall(0).Get("q")).Should(Equal("organization_guid:payments-guid"))
+		})
+
+		It("does not list the routes without a routes threshold", func() {
+			fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+				{Org: "payments", QuotaAlerts: &config.QuotaAlerts{Memory: 90}},
+			}, nil)
+			utilizations, err := quotaMgr.QuotaUtilization()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(utilizations).Should(HaveLen(1))
+			Expect(utilizations[0].Breached).Should(BeFalse())
+			Expect(fakeClient.ListRoutesByQueryCallCount()).Should(Equal(0))
+		})
+	})
+
+	Context("NotifyBreaches()", func() {
+		var sent []string
+
+		BeforeEach(func() {
+			sent = nil
+			fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{
+				QuotaNotifications: &config.EmailDelivery{Host: "smtp.example.com", From: "cf-mgmt@example.com"},
+			}, nil)
+			fakeOrgMgr.FindOrgReturns(cfclient.Org{Name: "payments", Guid: "payments-guid"}, nil)
+			fakeClient.ListOrgManagersReturns([]cfclient.User{{Username: "alice@example.com"}, {Username: "bob"}}, nil)
+			sender, err := email.NewSender(config.EmailDelivery{Host: "smtp.example.com", From: "cf-mgmt@example.com"}, "quota-notifications", "Cloud Foundry org quota alert")
+			Expect(err).ShouldNot(HaveOccurred())
+			sender.SendMail = func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+				Expect(addr).Should(Equal("smtp.example.com:25"))
+				sent = append(sent, to...)
+				sent = append(sent, string(msg))
+				return nil
+			}
+			quotaMgr.Sender = sender
+		})
+
+		It("emails the org managers with an email of the orgs with a breach", func() {
+			notified, err := quotaMgr.NotifyBreaches([]quota.Utilization{
+				{Org: "payments", Quota: "payments", Resource: quota.ResourceMemory, Used: 8192, Limit: 10240, Percent: 80, Threshold: 80, Breached: true},
+				{Org: "payments", Quota: "payments", Resource: quota.ResourceRoutes, Used: 3, Limit: 10, Percent: 30, Threshold: 50},
+				{Org: "quiet", Quota: "quiet", Resource: quota.ResourceRoutes, Used: 1, Limit: 10, Percent: 10, Threshold: 50},
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(notified).Should(Equal(1))
+			Expect(fakeOrgMgr.FindOrgArgsForCall(0)).Should(Equal("payments"))
+			Expect(sent).Should(HaveLen(2))
+			Expect(sent[0]).Should(Equal("alice@example.com"))
+			Expect(sent[1]).Should(ContainSubstring("Subject: Cloud Foundry org quota alert"))
+			Expect(sent[1]).Should(ContainSubstring("- memory: 8G of 10G (80%, alert at 80%)"))
+			Expect(sent[1]).ShouldNot(ContainSubstring("routes"))
+		})
+
+		It("does not email without a breach", func() {
+			notified, err := quotaMgr.NotifyBreaches([]quota.Utilization{
+				{Org: "payments", Resource: quota.ResourceRoutes, Used: 3, Limit: 10, Percent: 30, Threshold: 50},
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(notified).Should(Equal(0))
+			Expect(fakeReader.GetGlobalConfigCallCount()).Should(Equal(0))
+		})
+
+		It("does not email when peeking", func() {
+			quotaMgr.Peek = true
+			notified, err := quotaMgr.NotifyBreaches([]quota.Utilization{
+				{Org: "payments", Resource: quota.ResourceMemory, Used: 900, Limit: 1000, Percent: 90, Threshold: 80, Breached: true},
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(notified).Should(Equal(0))
+			Expect(sent).Should(BeEmpty())
+		})
+
+		It("requires quota-notifications", func() {
+			quotaMgr.Sender = nil
+			fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{}, nil)
+			_, err := quotaMgr.NotifyBreaches([]quota.Utilization{
+				{Org: "payments", Resource: quota.ResourceMemory, Used: 900, Limit: 1000, Percent: 90, Threshold: 80, Breached: true},
+			})
+			Expect(err).Should(MatchError("notifying org managers requires quota-notifications in cf-mgmt.yml"))
+		})
+	})
+})
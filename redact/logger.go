@@ -0,0 +1,74 @@
+package redact
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/op/go-logging"
+	"github.com/xchapter7x/lo"
+)
+
+// logger formats every message itself so it can be redacted before it
+// reaches the wrapped logger.
+type logger struct {
+	lo.Logger
+}
+
+//NewLogger - wraps a logger so that its messages are redacted
+func NewLogger(wrapped lo.Logger) lo.Logger {
+	return &logger{Logger: wrapped}
+}
+
+//WithLogger - runs fn with lo.G redacting its messages
+func WithLogger(fn func() error) error {
+	wrapped := lo.G
+	lo.G = NewLogger(wrapped)
+	// keep the file and line of the caller of lo.G in log messages
+	if goLogger, ok := wrapped.(*logging.Logger); ok {
+		goLogger.ExtraCalldepth++
+		defer func() { goLogger.ExtraCalldepth-- }()
+	}
+	defer func() { lo.G = wrapped }()
+	return fn()
+}
+
+func sprint(args []interface{}) string {
+	return String(strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+}
+
+func sprintf(format string, args []interface{}) string {
+	return String(fmt.Sprintf(format, args...))
+}
+
+func (l *logger) Critical(args ...interface{}) { l.Logger.Critical(sprint(args)) }
+func (l *logger) Criticalf(format string, args ...interface{}) {
+	l.Logger.Critical(sprintf(format, args))
+}
+func (l *logger) Debug(args ...interface{}) { l.Logger.Debug(sprint(args)) }
+func (l *logger) Debugf(format string, args ...interface{}) {
+	l.Logger.Debug(sprintf(format, args))
+}
+func (l *logger) Error(args ...interface{}) { l.Logger.Error(sprint(args)) }
+func (l *logger) Errorf(format string, args ...interface{}) {
+	l.Logger.Error(sprintf(format, args))
+}
+func (l *logger) Fatal(args ...interface{}) { l.Logger.Fatal(sprint(args)) }
+func (l *logger) Fatalf(format string, args ...interface{}) {
+	l.Logger.Fatal(sprintf(format, args))
+}
+func (l *logger) Info(args ...interface{}) { l.Logger.Info(sprint(args)) }
+func (l *logger) Infof(format string, args ...interface{}) {
+	l.Logger.Info(sprintf(format, args))
+}
+func (l *logger) Notice(args ...interface{}) { l.Logger.Notice(sprint(args)) }
+func (l *logger) Noticef(format string, args ...interface{}) {
+	l.Logger.Notice(sprintf(format, args))
+}
+func (l *logger) Panic(args ...interface{}) { l.Logger.Panic(sprint(args)) }
+func (l *logger) Panicf(format string, args ...interface{}) {
+	l.Logger.Panic(sprintf(format, args))
+}
+func (l *logger) Warning(args ...interface{}) { l.Logger.Warning(sprint(args)) }
+func (l *logger) Warningf(format string, args ...interface{}) {
+	l.Logger.Warning(sprintf(format, args))
+}
@@ -0,0 +1,131 @@
+// Package redact keeps secrets, and optionally usernames and emails, out of
+// the messages cf-mgmt logs and reports.
+package redact
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Modes for usernames and emails, secrets are always redacted.
+const (
+	ModeNone   = ""
+	ModeRedact = "redact"
+	ModeHash   = "hash"
+)
+
+// Redacted replaces secrets, and usernames and emails in redact mode.
+const Redacted = "REDACTED"
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	dnPattern    = regexp.MustCompile(`(?i)\b(uid|cn)=[^,\])]+(,\s*[a-z]+=[^,\])]+)*`)
+)
+
+var (
+	mutex     sync.RWMutex
+	mode      string
+	secrets   []string
+	userNames = make(map[string]bool)
+)
+
+//SetMode - sets how usernames and emails are logged, none leaves them as is
+func SetMode(newMode string) error {
+	switch newMode {
+	case ModeNone, ModeRedact, ModeHash:
+	default:
+		return fmt.Errorf("redact mode [%s] must be %s or %s", newMode, ModeRedact, ModeHash)
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	mode = newMode
+	return nil
+}
+
+//Secrets - registers passwords, client secrets and keys that are never logged
+func Secrets(values ...string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		secrets = append(secrets, value)
+	}
+	// longest first so a secret containing another is replaced whole
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+}
+
+//UserNames - registers usernames that are redacted or hashed unless the mode is none
+func UserNames(names ...string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	for _, name := range names {
+		if name != "" {
+			userNames[strings.ToLower(name)] = true
+		}
+	}
+}
+
+//String - redacts the secrets, and the usernames, emails and ldap dns for the mode, in a message
+func String(message string) string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	for _, secret := range secrets {
+		message = strings.Replace(message, secret, Redacted, -1)
+	}
+	if mode == ModeNone {
+		return message
+	}
+	message = dnPattern.ReplaceAllStringFunc(message, identifier)
+	message = emailPattern.ReplaceAllStringFunc(message, identifier)
+	return replaceUserNames(message)
+}
+
+func identifier(value string) string {
+	if mode == ModeHash {
+		sum := sha256.Sum256([]byte(strings.ToLower(value)))
+		return "user-" + hex.EncodeToString(sum[:])[:10]
+	}
+	return Redacted
+}
+
+func isSeparator(r rune) bool {
+	return strings.ContainsRune(" \t\r\n,;:[](){}<>\"'`=/", r)
+}
+
+// replaceUserNames replaces the registered usernames found between separators.
+func replaceUserNames(message string) string {
+	if len(userNames) == 0 {
+		return message
+	}
+	var result bytes.Buffer
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		token := message[start:end]
+		if userNames[strings.ToLower(token)] {
+			token = identifier(token)
+		}
+		result.WriteString(token)
+		start = -1
+	}
+	for i, r := range message {
+		if isSeparator(r) {
+			flush(i)
+			result.WriteRune(r)
+		} else if start < 0 {
+			start = i
+		}
+	}
+	flush(len(message))
+	return result.String()
+}
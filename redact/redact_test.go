@@ -0,0 +1,62 @@
+package redact_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/redact"
+	"github.com/xchapter7x/lo"
+	"github.com/xchapter7x/lo/lofakes"
+)
+
+var _ = Describe("given redact", func() {
+	BeforeEach(func() {
+		redact.Secrets("s3cr3t")
+		redact.UserNames("jdoe", "Admin")
+	})
+
+	AfterEach(func() {
+		Expect(redact.SetMode(redact.ModeNone)).ShouldNot(HaveOccurred())
+	})
+
+	It("always redacts secrets", func() {
+		Expect(redact.String("bind with s3cr3t as jdoe")).Should(Equal("bind with REDACTED as jdoe"))
+	})
+
+	It("redacts usernames, emails and dns", func() {
+		Expect(redact.SetMode(redact.ModeRedact)).ShouldNot(HaveOccurred())
+		Expect(redact.String("adding jdoe to role auditor")).Should(Equal("adding REDACTED to role auditor"))
+		Expect(redact.String("User[admin] not found, jdoe2 kept")).Should(Equal("User[REDACTED] not found, jdoe2 kept"))
+		Expect(redact.String("created j.doe@example.com")).Should(Equal("created REDACTED"))
+		Expect(redact.String("user cn=John Doe,ou=users,dc=example]")).Should(Equal("user REDACTED]"))
+	})
+
+	It("hashes usernames consistently", func() {
+		Expect(redact.SetMode(redact.ModeHash)).ShouldNot(HaveOccurred())
+		hashed := redact.String("jdoe and JDOE")
+		parts := strings.Split(hashed, " and ")
+		Expect(parts[0]).Should(HavePrefix("user-"))
+		Expect(parts[0]).Should(Equal(parts[1]))
+	})
+
+	It("rejects unknown modes", func() {
+		Expect(redact.SetMode("mask")).Should(MatchError("redact mode [mask] must be redact or hash"))
+	})
+
+	It("redacts messages logged while running", func() {
+		Expect(redact.SetMode(redact.ModeRedact)).ShouldNot(HaveOccurred())
+		logger := lo.G
+		fakeLogger := new(lofakes.FakeLogger)
+		lo.G = fakeLogger
+		defer func() { lo.G = logger }()
+		err := redact.WithLogger(func() error {
+			lo.G.Infof("adding %s with password %s", "jdoe", "s3cr3t")
+			return nil
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(lo.G).Should(Equal(fakeLogger))
+		Expect(fakeLogger.InfoCallCount()).Should(Equal(1))
+		Expect(fakeLogger.InfoArgsForCall(0)).Should(Equal([]interface{}{"adding REDACTED with password REDACTED"}))
+	})
+})
@@ -0,0 +1,158 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"net/url"
+	"sync"
+
+	go_cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pivotalservices/cf-mgmt/route"
+)
+
+type FakeCFClient struct {
+	ListSharedDomainsStub        func() ([]go_cfclient.SharedDomain, error)
+	listSharedDomainsMutex       sync.RWMutex
+	listSharedDomainsArgsForCall []struct{}
+	listSharedDomainsReturns     struct {
+		result1 []go_cfclient.SharedDomain
+		result2 error
+	}
+	ListRoutesByQueryStub        func(query url.Values) ([]go_cfclient.Route, error)
+	listRoutesByQueryMutex       sync.RWMutex
+	listRoutesByQueryArgsForCall []struct {
+		query url.Values
+	}
+	listRoutesByQueryReturns struct {
+		result1 []go_cfclient.Route
+		result2 error
+	}
+	DeleteRouteStub        func(guid string) error
+	deleteRouteMutex       sync.RWMutex
+	deleteRouteArgsForCall []struct {
+		guid string
+	}
+	deleteRouteReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeCFClient) ListSharedDomains() ([]go_cfclient.SharedDomain, error) {
+	fake.listSharedDomainsMutex.Lock()
+	fake.listSharedDomainsArgsForCall = append(fake.listSharedDomainsArgsForCall, struct{}{})
+	fake.recordInvocation("ListSharedDomains", []interface{}{})
+	fake.listSharedDomainsMutex.Unlock()
+	if fake.ListSharedDomainsStub != nil {
+		return fake.ListSharedDomainsStub()
+	} else {
+		return fake.listSharedDomainsReturns.result1, fake.listSharedDomainsReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) ListSharedDomainsCallCount() int {
+	fake.listSharedDomainsMutex.RLock()
+	defer fake.listSharedDomainsMutex.RUnlock()
+	return len(fake.listSharedDomainsArgsForCall)
+}
+
+func (fake *FakeCFClient) ListSharedDomainsReturns(result1 []go_cfclient.SharedDomain, result2 error) {
+	fake.ListSharedDomainsStub = nil
+	fake.listSharedDomainsReturns = struct {
+		result1 []go_cfclient.SharedDomain
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) ListRoutesByQuery(query url.Values) ([]go_cfclient.Route, error) {
+	fake.listRoutesByQueryMutex.Lock()
+	fake.listRoutesByQueryArgsForCall = append(fake.listRoutesByQueryArgsForCall, struct {
+		query url.Values
+	}{query})
+	fake.recordInvocation("ListRoutesByQuery", []interface{}{query})
+	fake.listRoutesByQueryMutex.Unlock()
+	if fake.ListRoutesByQueryStub != nil {
+		return fake.ListRoutesByQueryStub(query)
+	} else {
+		return fake.listRoutesByQueryReturns.result1, fake.listRoutesByQueryReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) ListRoutesByQueryCallCount() int {
+	fake.listRoutesByQueryMutex.RLock()
+	defer fake.listRoutesByQueryMutex.RUnlock()
+	return len(fake.listRoutesByQueryArgsForCall)
+}
+
+func (fake *FakeCFClient) ListRoutesByQueryArgsForCall(i int) url.Values {
+	fake.listRoutesByQueryMutex.RLock()
+	defer fake.listRoutesByQueryMutex.RUnlock()
+	return fake.listRoutesByQueryArgsForCall[i].query
+}
+
+func (fake *FakeCFClient) ListRoutesByQueryReturns(result1 []go_cfclient.Route, result2 error) {
+	fake.ListRoutesByQueryStub = nil
+	fake.listRoutesByQueryReturns = struct {
+		result1 []go_cfclient.Route
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) DeleteRoute(guid string) error {
+	fake.deleteRouteMutex.Lock()
+	fake.deleteRouteArgsForCall = append(fake.deleteRouteArgsForCall, struct {
+		guid string
+	}{guid})
+	fake.recordInvocation("DeleteRoute", []interface{}{guid})
+	fake.deleteRouteMutex.Unlock()
+	if fake.DeleteRouteStub != nil {
+		return fake.DeleteRouteStub(guid)
+	} else {
+		return fake.deleteRouteReturns.result1
+	}
+}
+
+func (fake *FakeCFClient) DeleteRouteCallCount() int {
+	fake.deleteRouteMutex.RLock()
+	defer fake.deleteRouteMutex.RUnlock()
+	return len(fake.deleteRouteArgsForCall)
+}
+
+func (fake *FakeCFClient) DeleteRouteArgsForCall(i int) string {
+	fake.deleteRouteMutex.RLock()
+	defer fake.deleteRouteMutex.RUnlock()
+	return fake.deleteRouteArgsForCall[i].guid
+}
+
+func (fake *FakeCFClient) DeleteRouteReturns(result1 error) {
+	fake.DeleteRouteStub = nil
+	fake.deleteRouteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCFClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.listSharedDomainsMutex.RLock()
+	defer fake.listSharedDomainsMutex.RUnlock()
+	fake.listRoutesByQueryMutex.RLock()
+	defer fake.listRoutesByQueryMutex.RUnlock()
+	fake.deleteRouteMutex.RLock()
+	defer fake.deleteRouteMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeCFClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ route.CFClient = new(FakeCFClient)
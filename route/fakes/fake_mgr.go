@@ -0,0 +1,101 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+
+	"github.com/pivotalservices/cf-mgmt/route"
+)
+
+type FakeManager struct {
+	InternalRouteViolationsStub        func() ([]route.InternalRouteViolation, error)
+	internalRouteViolationsMutex       sync.RWMutex
+	internalRouteViolationsArgsForCall []struct{}
+	internalRouteViolationsReturns     struct {
+		result1 []route.InternalRouteViolation
+		result2 error
+	}
+	EnforceInternalRoutesStub        func() error
+	enforceInternalRoutesMutex       sync.RWMutex
+	enforceInternalRoutesArgsForCall []struct{}
+	enforceInternalRoutesReturns     struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeManager) InternalRouteViolations() ([]route.InternalRouteViolation, error) {
+	fake.internalRouteViolationsMutex.Lock()
+	fake.internalRouteViolationsArgsForCall = append(fake.internalRouteViolationsArgsForCall, struct{}{})
+	fake.recordInvocation("InternalRouteViolations", []interface{}{})
+	fake.internalRouteViolationsMutex.Unlock()
+	if fake.InternalRouteViolationsStub != nil {
+		return fake.InternalRouteViolationsStub()
+	} else {
+		return fake.internalRouteViolationsReturns.result1, fake.internalRouteViolationsReturns.result2
+	}
+}
+
+func (fake *FakeManager) InternalRouteViolationsCallCount() int {
+	fake.internalRouteViolationsMutex.RLock()
+	defer fake.internalRouteViolationsMutex.RUnlock()
+	return len(fake.internalRouteViolationsArgsForCall)
+}
+
+func (fake *FakeManager) InternalRouteViolationsReturns(result1 []route.InternalRouteViolation, result2 error) {
+	fake.InternalRouteViolationsStub = nil
+	fake.internalRouteViolationsReturns = struct {
+		result1 []route.InternalRouteViolation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) EnforceInternalRoutes() error {
+	fake.enforceInternalRoutesMutex.Lock()
+	fake.enforceInternalRoutesArgsForCall = append(fake.enforceInternalRoutesArgsForCall, struct{}{})
+	fake.recordInvocation("EnforceInternalRoutes", []interface{}{})
+	fake.enforceInternalRoutesMutex.Unlock()
+	if fake.EnforceInternalRoutesStub != nil {
+		return fake.EnforceInternalRoutesStub()
+	} else {
+		return fake.enforceInternalRoutesReturns.result1
+	}
+}
+
+func (fake *FakeManager) EnforceInternalRoutesCallCount() int {
+	fake.enforceInternalRoutesMutex.RLock()
+	defer fake.enforceInternalRoutesMutex.RUnlock()
+	return len(fake.enforceInternalRoutesArgsForCall)
+}
+
+func (fake *FakeManager) EnforceInternalRoutesReturns(result1 error) {
+	fake.EnforceInternalRoutesStub = nil
+	fake.enforceInternalRoutesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.internalRouteViolationsMutex.RLock()
+	defer fake.internalRouteViolationsMutex.RUnlock()
+	fake.enforceInternalRoutesMutex.RLock()
+	defer fake.enforceInternalRoutesMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeManager) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ route.Manager = new(FakeManager)
@@ -0,0 +1,167 @@
+package route
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/organization"
+	"github.com/pivotalservices/cf-mgmt/space"
+	"github.com/xchapter7x/lo"
+)
+
+func NewManager(client CFClient, orgMgr organization.Manager, spaceMgr space.Manager, cfg config.Reader, peek bool) Manager {
+	return &DefaultManager{
+		Cfg:      cfg,
+		OrgMgr:   orgMgr,
+		SpaceMgr: spaceMgr,
+		Client:   client,
+		Peek:     peek,
+	}
+}
+
+//DefaultManager -
+type DefaultManager struct {
+	Cfg      config.Reader
+	OrgMgr   organization.Manager
+	SpaceMgr space.Manager
+	Client   CFClient
+	Peek     bool
+}
+
+// InternalRouteViolation is a route on an internal domain, used for container
+// to container networking, in a managed space that does not allow them.
+type InternalRouteViolation struct {
+	Org   string `json:"org"`
+	Space string `json:"space"`
+	Route string `json:"route"`
+	GUID  string `json:"guid"`
+}
+
+type managedSpace struct {
+	org, space string
+	allowed    bool
+}
+
+//InternalRouteViolations - lists the routes on internal domains, such as apps.internal, of the spaces of
+//managed orgs that do not set allow-internal-routes, including the spaces that are not in the configuration
+func (m *DefaultManager) InternalRouteViolations() ([]InternalRouteViolation, error) {
+	domains, err := m.Client.ListSharedDomains()
+	if err != nil {
+		return nil, err
+	}
+	internalDomains := make(map[string]string)
+	for _, domain := range domains {
+		if domain.Internal {
+			internalDomains[domain.Guid] = domain.Name
+		}
+	}
+	if len(internalDomains) == 0 {
+		lo.G.Debug("No internal domains, skipping internal routes")
+		return nil, nil
+	}
+
+	spaces, err := m.managedSpaces()
+	if err != nil {
+		return nil, err
+	}
+	violations := []InternalRouteViolation{}
+	for domainGUID, domainName := range internalDomains {
+		routes, err := m.Client.ListRoutesByQuery(url.Values{"q": []string{"domain_guid:" + domainGUID}})
+		if err != nil {
+			return nil, err
+		}
+		for _, route := range routes {
+			managed, ok := spaces[route.SpaceGuid]
+			if !ok || managed.allowed {
+				continue
+			}
+			violations = append(violations, InternalRouteViolation{
+				Org:   managed.org,
+				Space: managed.space,
+				Route: fmt.Sprintf("%s.%s%s", route.Host, domainName, route.Path),
+				GUID:  route.Guid,
+			})
+		}
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		a, b := violations[i], violations[j]
+		if a.Org != b.Org {
+			return a.Org < b.Org
+		}
+		if a.Space != b.Space {
+			return a.Space < b.Space
+		}
+		return a.Route < b.Route
+	})
+	return violations, nil
+}
+
+// managedSpaces are the existing spaces of the managed orgs by guid, whether
+// they are in the configuration or not
+func (m *DefaultManager) managedSpaces() (map[string]managedSpace, error) {
+	spaceConfigs, err := m.Cfg.GetSpaceConfigs()
+	if err != nil {
+		return nil, err
+	}
+	spaceConfigs, err = space.ExpandSpaceConfigs(m.SpaceMgr, spaceConfigs)
+	if err != nil {
+		return nil, err
+	}
+	allowed := make(map[string]bool)
+	for _, input := range spaceConfigs {
+		allowed[input.Org+"/"+input.Space] = input.AllowInternalRoutes
+	}
+	orgSpaces, err := m.Cfg.Spaces()
+	if err != nil {
+		return nil, err
+	}
+	spaces := make(map[string]managedSpace)
+	for _, input := range orgSpaces {
+		org, err := m.OrgMgr.FindOrg(input.Org)
+		if err != nil {
+			return nil, err
+		}
+		existing, err := m.SpaceMgr.ListSpaces(org.Guid)
+		if err != nil {
+			return nil, err
+		}
+		for _, space := range existing {
+			spaces[space.Guid] = managedSpace{org: input.Org, space: space.Name, allowed: allowed[input.Org+"/"+space.Name]}
+		}
+	}
+	return spaces, nil
+}
+
+//EnforceInternalRoutes - deletes the routes of the internal route violations when enforce-internal-routes is
+//set in cf-mgmt.yml, otherwise they are only logged
+func (m *DefaultManager) EnforceInternalRoutes() error {
+	globalConfig, err := m.Cfg.GetGlobalConfig()
+	if err != nil {
+		return err
+	}
+	violations, err := m.InternalRouteViolations()
+	if err != nil {
+		return err
+	}
+	for _, violation := range violations {
+		if !globalConfig.EnforceInternalRoutes {
+			lo.G.Warningf("Internal route %s of space %s in org %s is not allowed, set allow-internal-routes: true in its spaceConfig.yml or enforce-internal-routes: true in cf-mgmt.yml to delete it", violation.Route, violation.Space, violation.Org)
+			continue
+		}
+		if err := m.DeleteRoute(violation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *DefaultManager) DeleteRoute(violation InternalRouteViolation) error {
+	if m.Peek {
+		lo.G.Infof("[dry-run]: delete internal route %s of space %s in org %s", violation.Route, violation.Space, violation.Org)
+		return nil
+	}
+	lo.G.Infof("Delete internal route %s of space %s in org %s", violation.Route, violation.Space, violation.Org)
+	return m.Client.DeleteRoute(violation.GUID)
+}
@@ -0,0 +1,123 @@
+package route_test
+
+import (
+	"errors"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/config"
+	configfakes "github.com/pivotalservices/cf-mgmt/config/fakes"
+	orgfakes "github.com/pivotalservices/cf-mgmt/organization/fakes"
+	"github.com/pivotalservices/cf-mgmt/route"
+	routefakes "github.com/pivotalservices/cf-mgmt/route/fakes"
+	spacefakes "github.com/pivotalservices/cf-mgmt/space/fakes"
+)
+
+var _ = Describe("given Route Manager", func() {
+	var (
+		fakeReader   *configfakes.FakeReader
+		fakeClient   *routefakes.FakeCFClient
+		fakeOrgMgr   *orgfakes.FakeManager
+		fakeSpaceMgr *spacefakes.FakeManager
+		routeMgr     *route.DefaultManager
+	)
+
+	BeforeEach(func() {
+		fakeReader = new(configfakes.FakeReader)
+		fakeClient = new(routefakes.FakeCFClient)
+		fakeOrgMgr = new(orgfakes.FakeManager)
+		fakeSpaceMgr = new(spacefakes.FakeManager)
+		routeMgr = &route.DefaultManager{
+			Cfg:      fakeReader,
+			Client:   fakeClient,
+			OrgMgr:   fakeOrgMgr,
+			SpaceMgr: fakeSpaceMgr,
+		}
+		fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{}, nil)
+		fakeReader.GetSpaceConfigsReturns([]config.SpaceConfig{
+			config.SpaceConfig{Org: "org1", Space: "backend", AllowInternalRoutes: true},
+			config.SpaceConfig{Org: "org1", Space: "frontend"},
+		}, nil)
+		fakeReader.SpacesReturns([]config.Spaces{
+			config.Spaces{Org: "org1", Spaces: []string{"backend", "frontend"}},
+		}, nil)
+		fakeOrgMgr.FindOrgReturns(cfclient.Org{Name: "org1", Guid: "org1-guid"}, nil)
+		fakeSpaceMgr.ListSpacesReturns([]cfclient.Space{
+			cfclient.Space{Name: "backend", Guid: "backend-guid"},
+			cfclient.Space{Name: "frontend", Guid: "frontend-guid"},
+			cfclient.Space{Name: "scratch", Guid: "scratch-guid"},
+		}, nil)
+		fakeClient.ListSharedDomainsReturns([]cfclient.SharedDomain{
+			cfclient.SharedDomain{Name: "apps.example.com", Guid: "apps-guid"},
+			cfclient.SharedDomain{Name: "apps.internal", Guid: "internal-guid", Internal: true},
+		}, nil)
+		fakeClient.ListRoutesByQueryReturns([]cfclient.Route{
+			cfclient.Route{Guid: "route1", Host: "api", SpaceGuid: "backend-guid"},
+			cfclient.Route{Guid: "route2", Host: "web", SpaceGuid: "frontend-guid"},
+			cfclient.Route{Guid: "route3", Host: "tmp", Path: "/debug", SpaceGuid: "scratch-guid"},
+			cfclient.Route{Guid: "route4", Host: "other", SpaceGuid: "unmanaged-org-space-guid"},
+		}, nil)
+	})
+
+	Context("InternalRouteViolations", func() {
+		It("reports the internal routes of managed spaces that do not allow them", func() {
+			violations, err := routeMgr.InternalRouteViolations()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(violations).Should(Equal([]route.InternalRouteViolation{
+				route.InternalRouteViolation{Org: "org1", Space: "frontend", Route: "web.apps.internal", GUID: "route2"},
+				route.InternalRouteViolation{Org: "org1", Space: "scratch", Route: "tmp.apps.internal/debug", GUID: "route3"},
+			}))
+			Expect(fakeClient.ListRoutesByQueryCallCount()).Should(Equal(1))
+			Expect(fakeClient.ListRoutesByQueryArgsForCall(0).Get("q")).Should(Equal("domain_guid:internal-guid"))
+		})
+
+		It("does not list routes without internal domains", func() {
+			fakeClient.ListSharedDomainsReturns([]cfclient.SharedDomain{
+				cfclient.SharedDomain{Name: "apps.example.com", Guid: "apps-guid"},
+			}, nil)
+			violations, err := routeMgr.InternalRouteViolations()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(violations).Should(BeEmpty())
+			Expect(fakeClient.ListRoutesByQueryCallCount()).Should(Equal(0))
+		})
+
+		It("ignores configured spaces that do not exist yet", func() {
+			fakeSpaceMgr.ListSpacesReturns([]cfclient.Space{
+				cfclient.Space{Name: "frontend", Guid: "frontend-guid"},
+			}, nil)
+			violations, err := routeMgr.InternalRouteViolations()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(violations).Should(HaveLen(1))
+			Expect(fakeSpaceMgr.FindSpaceCallCount()).Should(Equal(0))
+		})
+
+		It("errors listing routes", func() {
+			fakeClient.ListRoutesByQueryReturns(nil, errors.New("error"))
+			_, err := routeMgr.InternalRouteViolations()
+			Expect(err).Should(MatchError("error"))
+		})
+	})
+
+	Context("EnforceInternalRoutes", func() {
+		It("only reports violations unless enforced", func() {
+			Expect(routeMgr.EnforceInternalRoutes()).Should(Succeed())
+			Expect(fakeClient.DeleteRouteCallCount()).Should(Equal(0))
+		})
+
+		It("deletes the routes of violations when enforced", func() {
+			fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{EnforceInternalRoutes: true}, nil)
+			Expect(routeMgr.EnforceInternalRoutes()).Should(Succeed())
+			Expect(fakeClient.DeleteRouteCallCount()).Should(Equal(2))
+			Expect(fakeClient.DeleteRouteArgsForCall(0)).Should(Equal("route2"))
+			Expect(fakeClient.DeleteRouteArgsForCall(1)).Should(Equal("route3"))
+		})
+
+		It("does not delete routes in peek mode", func() {
+			routeMgr.Peek = true
+			fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{EnforceInternalRoutes: true}, nil)
+			Expect(routeMgr.EnforceInternalRoutes()).Should(Succeed())
+			Expect(fakeClient.DeleteRouteCallCount()).Should(Equal(0))
+		})
+	})
+})
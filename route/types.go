@@ -0,0 +1,19 @@
+package route
+
+import (
+	"net/url"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+)
+
+//Manager -
+type Manager interface {
+	InternalRouteViolations() ([]InternalRouteViolation, error)
+	EnforceInternalRoutes() error
+}
+
+type CFClient interface {
+	ListSharedDomains() ([]cfclient.SharedDomain, error)
+	ListRoutesByQuery(query url.Values) ([]cfclient.Route, error)
+	DeleteRoute(guid string) error
+}
@@ -0,0 +1,128 @@
+package secretstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pivotalservices/cf-mgmt/redact"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+//CredHub - writes credentials to credhub, authenticating with its auth server as a client
+type CredHub struct {
+	url      string
+	clientID string
+	secret   string
+	// client authenticates with the credhub auth server once the first credential is written
+	client     *http.Client
+	baseClient *http.Client
+}
+
+//NewCredHub -
+func NewCredHub(url, clientID, clientSecret string, skipSSLValidation bool) (*CredHub, error) {
+	if url == "" || clientID == "" || clientSecret == "" {
+		return nil, fmt.Errorf("credhub requires a url, client id and client secret")
+	}
+	redact.Secrets(clientSecret)
+	return &CredHub{
+		url:        strings.TrimSuffix(url, "/"),
+		clientID:   clientID,
+		secret:     clientSecret,
+		baseClient: newHTTPClient(skipSSLValidation),
+	}, nil
+}
+
+func (c *CredHub) authenticate() error {
+	if c.client != nil {
+		return nil
+	}
+	authServer, err := c.authServer()
+	if err != nil {
+		return err
+	}
+	credentials := &clientcredentials.Config{
+		ClientID:     c.clientID,
+		ClientSecret: c.secret,
+		TokenURL:     authServer + "/oauth/token",
+	}
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, c.baseClient)
+	c.client = credentials.Client(ctx)
+	return nil
+}
+
+func (c *CredHub) authServer() (string, error) {
+	resp, err := c.baseClient.Get(c.url + "/info")
+	if err != nil {
+		return "", errors.Wrap(err, "unable to reach credhub")
+	}
+	defer resp.Body.Close()
+	info := struct {
+		AuthServer struct {
+			URL string `json:"url"`
+		} `json:"auth-server"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return "", errors.Wrap(err, "unable to read credhub info")
+	}
+	return strings.TrimSuffix(info.AuthServer.URL, "/"), nil
+}
+
+//SetPassword - writes a password credential, overwriting the current value of the name
+func (c *CredHub) SetPassword(name, value string) error {
+	return c.set(name, "password", value)
+}
+
+//SetJSON - writes a json credential, such as the credentials of a service key, overwriting the current value of the name
+func (c *CredHub) SetJSON(name string, value interface{}) error {
+	return c.set(name, "json", value)
+}
+
+func (c *CredHub) set(name, credentialType string, value interface{}) error {
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]interface{}{"name": name, "type": credentialType, "value": value})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPut, c.url+"/api/v1/data", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("credhub returned %d: %s", resp.StatusCode, string(message))
+	}
+	return nil
+}
+
+//Password - returns a store writing the password credential of the name
+func (c *CredHub) Password(name string) Store {
+	return &credHubPassword{credHub: c, name: name}
+}
+
+type credHubPassword struct {
+	credHub *CredHub
+	name    string
+}
+
+func (p *credHubPassword) Put(value string) error {
+	return p.credHub.SetPassword(p.name, value)
+}
+
+func (p *credHubPassword) String() string {
+	return "credhub " + p.name
+}
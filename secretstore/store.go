@@ -0,0 +1,36 @@
+package secretstore
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"net/http"
+)
+
+//Store - a place a secret is kept, such as a credhub credential or a vault secret
+type Store interface {
+	Put(value string) error
+	String() string
+}
+
+func newHTTPClient(skipSSLValidation bool) *http.Client {
+	client := &http.Client{}
+	if skipSSLValidation {
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+	return client
+}
+
+//File - returns a store writing the secret to a file only its owner can read
+func File(path string) Store {
+	return fileStore(path)
+}
+
+type fileStore string
+
+func (f fileStore) Put(value string) error {
+	return ioutil.WriteFile(string(f), []byte(value), 0600)
+}
+
+func (f fileStore) String() string {
+	return "file " + string(f)
+}
@@ -0,0 +1,125 @@
+package secretstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pivotalservices/cf-mgmt/redact"
+)
+
+//Vault - writes secrets to a version 2 key/value secrets engine of vault
+type Vault struct {
+	addr   string
+	token  string
+	client *http.Client
+}
+
+//NewVault -
+func NewVault(addr, token string, skipSSLValidation bool) (*Vault, error) {
+	if addr == "" || token == "" {
+		return nil, fmt.Errorf("vault requires an address and a token")
+	}
+	redact.Secrets(token)
+	return &Vault{
+		addr:   strings.TrimSuffix(addr, "/"),
+		token:  token,
+		client: newHTTPClient(skipSSLValidation),
+	}, nil
+}
+
+//Write - writes the data as a new version of the secret at path, which starts with the mount of the
+//secrets engine, such as secret/cf-mgmt. The keys of the secret not in data are kept, as a new version
+//replaces all the keys of the secret, and the version read is checked and set so that a concurrent
+//write is not lost.
+func (v *Vault) Write(path string, data map[string]string) error {
+	parts := strings.SplitN(strings.Trim(path, "/"), "/", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("vault path [%s] must be the mount of the secrets engine followed by the secret, such as secret/cf-mgmt", path)
+	}
+	url := fmt.Sprintf("%s/v1/%s/data/%s", v.addr, parts[0], parts[1])
+	secret, version, err := v.read(url)
+	if err != nil {
+		return err
+	}
+	for key, value := range data {
+		secret[key] = value
+	}
+	body, err := json.Marshal(map[string]interface{}{"options": map[string]int{"cas": version}, "data": secret})
+	if err != nil {
+		return err
+	}
+	resp, err := v.do(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		message, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("vault returned %d: %s", resp.StatusCode, string(message))
+	}
+	return nil
+}
+
+// read returns the keys of the current version of the secret at url and
+// that version, 0 when there is no secret yet. A deleted current version has
+// no keys but keeps its version.
+func (v *Vault) read(url string) (map[string]interface{}, int, error) {
+	resp, err := v.do(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		message, _ := ioutil.ReadAll(resp.Body)
+		return nil, 0, fmt.Errorf("vault returned %d reading the secret: %s", resp.StatusCode, string(message))
+	}
+	var secret struct {
+		Data struct {
+			Data     map[string]interface{} `json:"data"`
+			Metadata struct {
+				Version int `json:"version"`
+			} `json:"metadata"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, 0, err
+	}
+	if secret.Data.Data == nil {
+		secret.Data.Data = make(map[string]interface{})
+	}
+	return secret.Data.Data, secret.Data.Metadata.Version, nil
+}
+
+func (v *Vault) do(method, url string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", v.token)
+	return v.client.Do(req)
+}
+
+//Secret - returns a store writing the key of the secret at path
+func (v *Vault) Secret(path, key string) Store {
+	return &vaultSecret{vault: v, path: path, key: key}
+}
+
+type vaultSecret struct {
+	vault *Vault
+	path  string
+	key   string
+}
+
+func (s *vaultSecret) Put(value string) error {
+	return s.vault.Write(s.path, map[string]string{s.key: value})
+}
+
+func (s *vaultSecret) String() string {
+	return fmt.Sprintf("vault %s#%s", s.path, s.key)
+}
@@ -0,0 +1,82 @@
+package secretstore_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotalservices/cf-mgmt/secretstore"
+)
+
+var _ = Describe("given vault", func() {
+	var (
+		server  *httptest.Server
+		path    string
+		token   string
+		written map[string]interface{}
+		status  int
+		current string
+	)
+
+	BeforeEach(func() {
+		status = http.StatusOK
+		current = ""
+		written = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			token = r.Header.Get("X-Vault-Token")
+			if r.Method == http.MethodGet {
+				if current == "" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				fmt.Fprint(w, current)
+				return
+			}
+			json.NewDecoder(r.Body).Decode(&written)
+			w.WriteHeader(status)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("writes a new version of the key/value secret", func() {
+		vault, err := NewVault(server.URL, "vault-token", false)
+		Expect(err).ShouldNot(HaveOccurred())
+		store := vault.Secret("secret/cf-mgmt/prod", "client-secret")
+		Expect(store.Put("new-secret")).Should(Succeed())
+		Expect(path).Should(Equal("/v1/secret/data/cf-mgmt/prod"))
+		Expect(token).Should(Equal("vault-token"))
+		Expect(written).Should(Equal(map[string]interface{}{
+			"options": map[string]interface{}{"cas": float64(0)},
+			"data":    map[string]interface{}{"client-secret": "new-secret"},
+		}))
+		Expect(store.String()).Should(Equal("vault secret/cf-mgmt/prod#client-secret"))
+	})
+
+	It("keeps the other keys of the secret and checks the version read", func() {
+		current = `{"data": {"data": {"client-secret": "old-secret", "ldap-password": "ldap"}, "metadata": {"version": 3}}}`
+		vault, _ := NewVault(server.URL, "vault-token", false)
+		Expect(vault.Write("secret/cf-mgmt", map[string]string{"client-secret": "new-secret"})).Should(Succeed())
+		Expect(written).Should(Equal(map[string]interface{}{
+			"options": map[string]interface{}{"cas": float64(3)},
+			"data":    map[string]interface{}{"client-secret": "new-secret", "ldap-password": "ldap"},
+		}))
+	})
+
+	It("errors when vault refuses the write", func() {
+		status = http.StatusForbidden
+		vault, _ := NewVault(server.URL, "vault-token", false)
+		Expect(vault.Write("secret/cf-mgmt", map[string]string{"client-secret": "new-secret"})).Should(MatchError("vault returned 403: "))
+	})
+
+	It("requires the mount in the path", func() {
+		vault, _ := NewVault(server.URL, "vault-token", false)
+		Expect(vault.Write("cf-mgmt", nil)).Should(MatchError("vault path [cf-mgmt] must be the mount of the secrets engine followed by the secret, such as secret/cf-mgmt"))
+	})
+})
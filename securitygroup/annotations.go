@@ -0,0 +1,249 @@
+package securitygroup
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pivotalservices/cf-mgmt/space"
+	"github.com/pkg/errors"
+	"github.com/xchapter7x/lo"
+)
+
+// EgressAnnotation is the metadata annotation of the managed orgs and spaces
+// that summarizes the egress their apps are allowed, which developers can read
+// with cf curl /v3/spaces/<guid>.
+const EgressAnnotation = "cf-mgmt.io/egress"
+
+// maxAnnotationLength is the longest annotation value the cloud controller accepts
+const maxAnnotationLength = 5000
+
+const truncatedSuffix = " ... (truncated, run cf-mgmt egress-report for every rule)"
+
+// Resources that can be annotated, as named by the cloud controller v3 api.
+const (
+	ResourceOrganizations = "organizations"
+	ResourceSpaces        = "spaces"
+)
+
+//Annotator - reads and writes the metadata annotations of orgs and spaces,
+//which are only available from the v3 api
+type Annotator interface {
+	GetAnnotations(resource, guid string) (map[string]string, error)
+	SetAnnotations(resource, guid string, annotations map[string]string) error
+}
+
+//NewAnnotator - annotates with the authenticated http client of the cloud controller
+func NewAnnotator(httpClient *http.Client, apiAddress string) Annotator {
+	return &v3Annotator{httpClient: httpClient, apiAddress: strings.TrimSuffix(apiAddress, "/")}
+}
+
+type v3Annotator struct {
+	httpClient *http.Client
+	apiAddress string
+}
+
+type v3Metadata struct {
+	Metadata struct {
+		Annotations map[string]string `json:"annotations"`
+	} `json:"metadata"`
+}
+
+func (a *v3Annotator) GetAnnotations(resource, guid string) (map[string]string, error) {
+	resp, err := a.httpClient.Get(fmt.Sprintf("%s/v3/%s/%s", a.apiAddress, resource, guid))
+	if err != nil {
+		return nil, err
+	}
+	body, err := readResponse(resp)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error getting annotations of %s %s", resource, guid)
+	}
+	result := &v3Metadata{}
+	if err := json.Unmarshal(body, result); err != nil {
+		return nil, err
+	}
+	return result.Metadata.Annotations, nil
+}
+
+func (a *v3Annotator) SetAnnotations(resource, guid string, annotations map[string]string) error {
+	update := &v3Metadata{}
+	update.Metadata.Annotations = annotations
+	data, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/v3/%s/%s", a.apiAddress, resource, guid), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	if _, err := readResponse(resp); err != nil {
+		return errors.Wrapf(err, "Error setting annotations of %s %s", resource, guid)
+	}
+	return nil
+}
+
+func readResponse(resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cloud controller returned %d: %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
+
+//AnnotateEgress - when egress-annotations is set in cf-mgmt.yml, writes a summary of the egress allowed
+//to each managed space as its cf-mgmt.io/egress annotation, and of the egress allowed to every managed
+//space of an org as the annotation of the org
+func (m *DefaultManager) AnnotateEgress() error {
+	globalConfig, err := m.Cfg.GetGlobalConfig()
+	if err != nil {
+		return err
+	}
+	if !globalConfig.EgressAnnotations {
+		return nil
+	}
+	if m.Annotator == nil {
+		return fmt.Errorf("egress-annotations is not supported by this cloud controller client")
+	}
+	spaceConfigs, err := m.Cfg.GetSpaceConfigs()
+	if err != nil {
+		return err
+	}
+	spaceConfigs, err = space.ExpandSpaceConfigs(m.SpaceManager, spaceConfigs)
+	if err != nil {
+		return err
+	}
+	report, err := m.EgressReport()
+	if err != nil {
+		return err
+	}
+	spaceRules := make(map[string][]EgressRule)
+	for _, rule := range report {
+		spaceRules[rule.Org+"/"+rule.Space] = append(spaceRules[rule.Org+"/"+rule.Space], rule)
+	}
+	// every managed space is annotated, with none when it is allowed no egress
+	var orgNames []string
+	orgSpaces := make(map[string][]string)
+	for _, spaceConfig := range spaceConfigs {
+		if _, ok := orgSpaces[spaceConfig.Org]; !ok {
+			orgNames = append(orgNames, spaceConfig.Org)
+		}
+		orgSpaces[spaceConfig.Org] = append(orgSpaces[spaceConfig.Org], spaceConfig.Space)
+	}
+	for _, orgName := range orgNames {
+		var orgGUID string
+		var common []EgressRule
+		for i, spaceName := range orgSpaces[orgName] {
+			space, err := m.SpaceManager.FindSpace(orgName, spaceName)
+			if err != nil {
+				return err
+			}
+			orgGUID = space.OrganizationGuid
+			rules := spaceRules[orgName+"/"+spaceName]
+			if i == 0 {
+				common = rules
+			} else {
+				common = commonRules(common, rules)
+			}
+			if m.Peek && strings.Contains(space.Guid, "dry-run-space-guid") {
+				lo.G.Infof("[dry-run]: annotating egress of space %s in org %s once it is created", spaceName, orgName)
+				continue
+			}
+			if err := m.annotate(ResourceSpaces, space.Guid, fmt.Sprintf("space %s in org %s", spaceName, orgName), EgressSummary(rules)); err != nil {
+				return err
+			}
+		}
+		if m.Peek && strings.Contains(orgGUID, "dry-run-org-guid") {
+			lo.G.Infof("[dry-run]: annotating egress of org %s once it is created", orgName)
+			continue
+		}
+		if err := m.annotate(ResourceOrganizations, orgGUID, fmt.Sprintf("org %s", orgName), EgressSummary(common)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *DefaultManager) annotate(resource, guid, description, summary string) error {
+	annotations, err := m.Annotator.GetAnnotations(resource, guid)
+	if err != nil {
+		return err
+	}
+	if annotations[EgressAnnotation] == summary {
+		return nil
+	}
+	if m.Peek {
+		lo.G.Infof("[dry-run]: updating egress annotation of %s to %s", description, summary)
+		return nil
+	}
+	lo.G.Infof("Updating egress annotation of %s to %s", description, summary)
+	return m.Annotator.SetAnnotations(resource, guid, map[string]string{EgressAnnotation: summary})
+}
+
+//EgressSummary - a compact summary of egress rules such as
+//running: tcp 10.0.11.0/24:80,443, icmp 0.0.0.0/0; staging: tcp 0.0.0.0/0:443
+func EgressSummary(rules []EgressRule) string {
+	var lifecycles []string
+	destinations := make(map[string][]string)
+	for _, rule := range rules {
+		if _, ok := destinations[rule.Lifecycle]; !ok {
+			lifecycles = append(lifecycles, rule.Lifecycle)
+		}
+		destination := fmt.Sprintf("%s %s", rule.Protocol, rule.Destination)
+		if rule.Ports != "" {
+			destination = fmt.Sprintf("%s:%s", destination, rule.Ports)
+		}
+		if !containsString(destinations[rule.Lifecycle], destination) {
+			destinations[rule.Lifecycle] = append(destinations[rule.Lifecycle], destination)
+		}
+	}
+	sort.Strings(lifecycles)
+	var parts []string
+	for _, lifecycle := range lifecycles {
+		parts = append(parts, fmt.Sprintf("%s: %s", lifecycle, strings.Join(destinations[lifecycle], ", ")))
+	}
+	summary := strings.Join(parts, "; ")
+	if summary == "" {
+		summary = "none"
+	}
+	if len(summary) > maxAnnotationLength {
+		summary = summary[:maxAnnotationLength-len(truncatedSuffix)] + truncatedSuffix
+	}
+	return summary
+}
+
+// commonRules returns the rules of a that also allow the same egress in b
+func commonRules(a, b []EgressRule) []EgressRule {
+	var result []EgressRule
+	for _, rule := range a {
+		for _, other := range b {
+			if rule.Lifecycle == other.Lifecycle && rule.Protocol == other.Protocol &&
+				rule.Destination == other.Destination && rule.Ports == other.Ports {
+				result = append(result, rule)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
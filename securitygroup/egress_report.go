@@ -0,0 +1,91 @@
+package securitygroup
+
+import (
+	"sort"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pivotalservices/cf-mgmt/space"
+)
+
+// EgressRule describes a single destination a managed space can reach and
+// the security group binding that allows it.
+type EgressRule struct {
+	Org           string `json:"org"`
+	Space         string `json:"space"`
+	SecurityGroup string `json:"security_group"`
+	Lifecycle     string `json:"lifecycle"`
+	Protocol      string `json:"protocol"`
+	Destination   string `json:"destination"`
+	Ports         string `json:"ports,omitempty"`
+}
+
+//EgressReport - lists the egress allowed for every managed space by the
+//security groups bound to it, including running and staging defaults
+func (m *DefaultManager) EgressReport() ([]EgressRule, error) {
+	spaceConfigs, err := m.Cfg.GetSpaceConfigs()
+	if err != nil {
+		return nil, err
+	}
+	spaceConfigs, err = space.ExpandSpaceConfigs(m.SpaceManager, spaceConfigs)
+	if err != nil {
+		return nil, err
+	}
+	sgs, err := m.ListSecurityGroups()
+	if err != nil {
+		return nil, err
+	}
+	var report []EgressRule
+	for _, input := range spaceConfigs {
+		space, err := m.SpaceManager.FindSpace(input.Org, input.Space)
+		if err != nil {
+			return nil, err
+		}
+		for _, sg := range sgs {
+			if sg.Running || containsSpace(sg.SpacesData, space.Guid) {
+				report = append(report, egressRules(input.Org, input.Space, "running", sg)...)
+			}
+			if sg.Staging || containsSpace(sg.StagingSpacesData, space.Guid) {
+				report = append(report, egressRules(input.Org, input.Space, "staging", sg)...)
+			}
+		}
+	}
+	sort.SliceStable(report, func(i, j int) bool {
+		a, b := report[i], report[j]
+		if a.Org != b.Org {
+			return a.Org < b.Org
+		}
+		if a.Space != b.Space {
+			return a.Space < b.Space
+		}
+		if a.Lifecycle != b.Lifecycle {
+			return a.Lifecycle < b.Lifecycle
+		}
+		return a.SecurityGroup < b.SecurityGroup
+	})
+	return report, nil
+}
+
+func containsSpace(spaces []cfclient.SpaceResource, spaceGUID string) bool {
+	for _, space := range spaces {
+		if space.Entity.Guid == spaceGUID || space.Meta.Guid == spaceGUID {
+			return true
+		}
+	}
+	return false
+}
+
+func egressRules(orgName, spaceName, lifecycle string, sg cfclient.SecGroup) []EgressRule {
+	var rules []EgressRule
+	for _, rule := range sg.Rules {
+		rules = append(rules, EgressRule{
+			Org:           orgName,
+			Space:         spaceName,
+			SecurityGroup: sg.Name,
+			Lifecycle:     lifecycle,
+			Protocol:      rule.Protocol,
+			Destination:   rule.Destination,
+			Ports:         rule.Ports,
+		})
+	}
+	return rules
+}
@@ -0,0 +1,123 @@
+package securitygroup
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/stats"
+	"github.com/xchapter7x/lo"
+)
+
+// DefaultEndpointTTL is how long a resolved endpoint host is reused before it
+// is looked up again when asg-endpoint-ttl is not configured.
+const DefaultEndpointTTL = 5 * time.Minute
+
+type resolvedHost struct {
+	cidrs   []string
+	expires time.Time
+}
+
+// EndpointResolver resolves endpoint hosts to CIDRs, caching each host for
+// the configured TTL so a long running process picks up address changes.
+type EndpointResolver struct {
+	LookupHost func(host string) ([]string, error)
+	Now        func() time.Time
+	TTL        time.Duration
+	cache      map[string]resolvedHost
+}
+
+// NewEndpointResolver creates a resolver backed by DNS.
+func NewEndpointResolver(ttl time.Duration) *EndpointResolver {
+	return &EndpointResolver{
+		LookupHost: net.LookupHost,
+		Now:        time.Now,
+		TTL:        ttl,
+	}
+}
+
+// Resolve returns the sorted IPv4 CIDRs for a host.  Hosts that are already
+// an IP address or CIDR are returned as is.
+func (r *EndpointResolver) Resolve(host string) ([]string, error) {
+	if _, _, err := net.ParseCIDR(host); err == nil {
+		return []string{host}, nil
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return []string{fmt.Sprintf("%s/32", ip.String())}, nil
+	}
+	if r.cache == nil {
+		r.cache = make(map[string]resolvedHost)
+	}
+	if cached, ok := r.cache[host]; ok && r.Now().Before(cached.expires) {
+		stats.CacheHit("dns")
+		return cached.cidrs, nil
+	}
+	stats.CacheMiss("dns")
+	addrs, err := r.LookupHost(host)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to resolve host [%s]: %s", host, err)
+	}
+	var cidrs []string
+	for _, addr := range addrs {
+		ip := net.ParseIP(addr)
+		if ip == nil || ip.To4() == nil {
+			continue
+		}
+		cidrs = append(cidrs, fmt.Sprintf("%s/32", ip.To4().String()))
+	}
+	if len(cidrs) == 0 {
+		return nil, fmt.Errorf("Host [%s] did not resolve to any IPv4 addresses", host)
+	}
+	sort.Strings(cidrs)
+	lo.G.Debugf("Resolved host %s to %v", host, cidrs)
+	r.cache[host] = resolvedHost{cidrs: cidrs, expires: r.Now().Add(r.TTL)}
+	return cidrs, nil
+}
+
+// GenerateRules builds the json rules for a security group that allows egress
+// to each of the named endpoints.
+func (r *EndpointResolver) GenerateRules(allow []string, endpoints []config.ASGEndpoint) (string, error) {
+	endpointMap := make(map[string]config.ASGEndpoint)
+	for _, endpoint := range endpoints {
+		endpointMap[endpoint.Name] = endpoint
+	}
+	rules := []cfclient.SecGroupRule{}
+	for _, name := range allow {
+		endpoint, ok := endpointMap[name]
+		if !ok {
+			return "", fmt.Errorf("Endpoint [%s] is not defined in asg-endpoints", name)
+		}
+		protocol := endpoint.Protocol
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		seen := make(map[string]bool)
+		for _, host := range endpoint.Hosts {
+			cidrs, err := r.Resolve(host)
+			if err != nil {
+				return "", err
+			}
+			for _, cidr := range cidrs {
+				if seen[cidr] {
+					continue
+				}
+				seen[cidr] = true
+				rules = append(rules, cfclient.SecGroupRule{
+					Protocol:    protocol,
+					Ports:       endpoint.Ports,
+					Destination: cidr,
+					Description: endpoint.Description,
+				})
+			}
+		}
+	}
+	bytes, err := json.Marshal(rules)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
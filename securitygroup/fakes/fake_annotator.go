@@ -0,0 +1,127 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+
+	"github.com/pivotalservices/cf-mgmt/securitygroup"
+)
+
+type FakeAnnotator struct {
+	GetAnnotationsStub        func(resource string, guid string) (map[string]string, error)
+	getAnnotationsMutex       sync.RWMutex
+	getAnnotationsArgsForCall []struct {
+		resource string
+		guid     string
+	}
+	getAnnotationsReturns struct {
+		result1 map[string]string
+		result2 error
+	}
+	SetAnnotationsStub        func(resource string, guid string, annotations map[string]string) error
+	setAnnotationsMutex       sync.RWMutex
+	setAnnotationsArgsForCall []struct {
+		resource    string
+		guid        string
+		annotations map[string]string
+	}
+	setAnnotationsReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeAnnotator) GetAnnotations(resource string, guid string) (map[string]string, error) {
+	fake.getAnnotationsMutex.Lock()
+	fake.getAnnotationsArgsForCall = append(fake.getAnnotationsArgsForCall, struct {
+		resource string
+		guid     string
+	}{resource, guid})
+	fake.recordInvocation("GetAnnotations", []interface{}{resource, guid})
+	fake.getAnnotationsMutex.Unlock()
+	if fake.GetAnnotationsStub != nil {
+		return fake.GetAnnotationsStub(resource, guid)
+	} else {
+		return fake.getAnnotationsReturns.result1, fake.getAnnotationsReturns.result2
+	}
+}
+
+func (fake *FakeAnnotator) GetAnnotationsCallCount() int {
+	fake.getAnnotationsMutex.RLock()
+	defer fake.getAnnotationsMutex.RUnlock()
+	return len(fake.getAnnotationsArgsForCall)
+}
+
+func (fake *FakeAnnotator) GetAnnotationsArgsForCall(i int) (string, string) {
+	fake.getAnnotationsMutex.RLock()
+	defer fake.getAnnotationsMutex.RUnlock()
+	return fake.getAnnotationsArgsForCall[i].resource, fake.getAnnotationsArgsForCall[i].guid
+}
+
+func (fake *FakeAnnotator) GetAnnotationsReturns(result1 map[string]string, result2 error) {
+	fake.GetAnnotationsStub = nil
+	fake.getAnnotationsReturns = struct {
+		result1 map[string]string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAnnotator) SetAnnotations(resource string, guid string, annotations map[string]string) error {
+	fake.setAnnotationsMutex.Lock()
+	fake.setAnnotationsArgsForCall = append(fake.setAnnotationsArgsForCall, struct {
+		resource    string
+		guid        string
+		annotations map[string]string
+	}{resource, guid, annotations})
+	fake.recordInvocation("SetAnnotations", []interface{}{resource, guid, annotations})
+	fake.setAnnotationsMutex.Unlock()
+	if fake.SetAnnotationsStub != nil {
+		return fake.SetAnnotationsStub(resource, guid, annotations)
+	} else {
+		return fake.setAnnotationsReturns.result1
+	}
+}
+
+func (fake *FakeAnnotator) SetAnnotationsCallCount() int {
+	fake.setAnnotationsMutex.RLock()
+	defer fake.setAnnotationsMutex.RUnlock()
+	return len(fake.setAnnotationsArgsForCall)
+}
+
+func (fake *FakeAnnotator) SetAnnotationsArgsForCall(i int) (string, string, map[string]string) {
+	fake.setAnnotationsMutex.RLock()
+	defer fake.setAnnotationsMutex.RUnlock()
+	return fake.setAnnotationsArgsForCall[i].resource, fake.setAnnotationsArgsForCall[i].guid, fake.setAnnotationsArgsForCall[i].annotations
+}
+
+func (fake *FakeAnnotator) SetAnnotationsReturns(result1 error) {
+	fake.SetAnnotationsStub = nil
+	fake.setAnnotationsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAnnotator) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getAnnotationsMutex.RLock()
+	defer fake.getAnnotationsMutex.RUnlock()
+	fake.setAnnotationsMutex.RLock()
+	defer fake.setAnnotationsMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeAnnotator) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ securitygroup.Annotator = new(FakeAnnotator)
@@ -48,6 +48,15 @@ type FakeCFClient struct {
 	bindSecGroupReturns struct {
 		result1 error
 	}
+	BindStagingSecGroupToSpaceStub        func(secGUID, spaceGUID string) error
+	bindStagingSecGroupToSpaceMutex       sync.RWMutex
+	bindStagingSecGroupToSpaceArgsForCall []struct {
+		secGUID   string
+		spaceGUID string
+	}
+	bindStagingSecGroupToSpaceReturns struct {
+		result1 error
+	}
 	BindRunningSecGroupStub        func(secGUID string) error
 	bindRunningSecGroupMutex       sync.RWMutex
 	bindRunningSecGroupArgsForCall []struct {
@@ -255,6 +264,40 @@ func (fake *FakeCFClient) BindSecGroupReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeCFClient) BindStagingSecGroupToSpace(secGUID string, spaceGUID string) error {
+	fake.bindStagingSecGroupToSpaceMutex.Lock()
+	fake.bindStagingSecGroupToSpaceArgsForCall = append(fake.bindStagingSecGroupToSpaceArgsForCall, struct {
+		secGUID   string
+		spaceGUID string
+	}{secGUID, spaceGUID})
+	fake.recordInvocation("BindStagingSecGroupToSpace", []interface{}{secGUID, spaceGUID})
+	fake.bindStagingSecGroupToSpaceMutex.Unlock()
+	if fake.BindStagingSecGroupToSpaceStub != nil {
+		return fake.BindStagingSecGroupToSpaceStub(secGUID, spaceGUID)
+	} else {
+		return fake.bindStagingSecGroupToSpaceReturns.result1
+	}
+}
+
+func (fake *FakeCFClient) BindStagingSecGroupToSpaceCallCount() int {
+	fake.bindStagingSecGroupToSpaceMutex.RLock()
+	defer fake.bindStagingSecGroupToSpaceMutex.RUnlock()
+	return len(fake.bindStagingSecGroupToSpaceArgsForCall)
+}
+
+func (fake *FakeCFClient) BindStagingSecGroupToSpaceArgsForCall(i int) (string, string) {
+	fake.bindStagingSecGroupToSpaceMutex.RLock()
+	defer fake.bindStagingSecGroupToSpaceMutex.RUnlock()
+	return fake.bindStagingSecGroupToSpaceArgsForCall[i].secGUID, fake.bindStagingSecGroupToSpaceArgsForCall[i].spaceGUID
+}
+
+func (fake *FakeCFClient) BindStagingSecGroupToSpaceReturns(result1 error) {
+	fake.BindStagingSecGroupToSpaceStub = nil
+	fake.bindStagingSecGroupToSpaceReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeCFClient) BindRunningSecGroup(secGUID string) error {
 	fake.bindRunningSecGroupMutex.Lock()
 	fake.bindRunningSecGroupArgsForCall = append(fake.bindRunningSecGroupArgsForCall, struct {
@@ -466,6 +509,8 @@ func (fake *FakeCFClient) Invocations() map[string][][]interface{} {
 	defer fake.updateSecGroupMutex.RUnlock()
 	fake.bindSecGroupMutex.RLock()
 	defer fake.bindSecGroupMutex.RUnlock()
+	fake.bindStagingSecGroupToSpaceMutex.RLock()
+	defer fake.bindStagingSecGroupToSpaceMutex.RUnlock()
 	fake.bindRunningSecGroupMutex.RLock()
 	defer fake.bindRunningSecGroupMutex.RUnlock()
 	fake.bindStagingSecGroupMutex.RLock()
@@ -59,6 +59,19 @@ type FakeManager struct {
 	assignDefaultSecurityGroupsReturns     struct {
 		result1 error
 	}
+	EgressReportStub        func() ([]securitygroup.EgressRule, error)
+	egressReportMutex       sync.RWMutex
+	egressReportArgsForCall []struct{}
+	egressReportReturns     struct {
+		result1 []securitygroup.EgressRule
+		result2 error
+	}
+	AnnotateEgressStub        func() error
+	annotateEgressMutex       sync.RWMutex
+	annotateEgressArgsForCall []struct{}
+	annotateEgressReturns     struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -258,6 +271,57 @@ func (fake *FakeManager) AssignDefaultSecurityGroupsReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeManager) EgressReport() ([]securitygroup.EgressRule, error) {
+	fake.egressReportMutex.Lock()
+	fake.egressReportArgsForCall = append(fake.egressReportArgsForCall, struct{}{})
+	fake.recordInvocation("EgressReport", []interface{}{})
+	fake.egressReportMutex.Unlock()
+	if fake.EgressReportStub != nil {
+		return fake.EgressReportStub()
+	} else {
+		return fake.egressReportReturns.result1, fake.egressReportReturns.result2
+	}
+}
+
+func (fake *FakeManager) EgressReportCallCount() int {
+	fake.egressReportMutex.RLock()
+	defer fake.egressReportMutex.RUnlock()
+	return len(fake.egressReportArgsForCall)
+}
+
+func (fake *FakeManager) EgressReportReturns(result1 []securitygroup.EgressRule, result2 error) {
+	fake.EgressReportStub = nil
+	fake.egressReportReturns = struct {
+		result1 []securitygroup.EgressRule
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) AnnotateEgress() error {
+	fake.annotateEgressMutex.Lock()
+	fake.annotateEgressArgsForCall = append(fake.annotateEgressArgsForCall, struct{}{})
+	fake.recordInvocation("AnnotateEgress", []interface{}{})
+	fake.annotateEgressMutex.Unlock()
+	if fake.AnnotateEgressStub != nil {
+		return fake.AnnotateEgressStub()
+	} else {
+		return fake.annotateEgressReturns.result1
+	}
+}
+
+func (fake *FakeManager) AnnotateEgressCallCount() int {
+	fake.annotateEgressMutex.RLock()
+	defer fake.annotateEgressMutex.RUnlock()
+	return len(fake.annotateEgressArgsForCall)
+}
+
+func (fake *FakeManager) AnnotateEgressReturns(result1 error) {
+	fake.AnnotateEgressStub = nil
+	fake.annotateEgressReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeManager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -275,6 +339,10 @@ func (fake *FakeManager) Invocations() map[string][][]interface{} {
 	defer fake.createGlobalSecurityGroupsMutex.RUnlock()
 	fake.assignDefaultSecurityGroupsMutex.RLock()
 	defer fake.assignDefaultSecurityGroupsMutex.RUnlock()
+	fake.egressReportMutex.RLock()
+	defer fake.egressReportMutex.RUnlock()
+	fake.annotateEgressMutex.RLock()
+	defer fake.annotateEgressMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -3,7 +3,9 @@ package securitygroup
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	cfclient "github.com/cloudfoundry-community/go-cfclient"
 	"github.com/pivotalservices/cf-mgmt/config"
@@ -27,6 +29,9 @@ type DefaultManager struct {
 	SpaceManager space.Manager
 	Client       CFClient
 	Peek         bool
+	Resolver     *EndpointResolver
+	// Annotator writes the egress annotations of orgs and spaces
+	Annotator Annotator
 }
 
 //CreateApplicationSecurityGroups -
@@ -35,6 +40,18 @@ func (m *DefaultManager) CreateApplicationSecurityGroups() error {
 	if err != nil {
 		return err
 	}
+	spaceConfigs, err = space.ExpandSpaceConfigs(m.SpaceManager, spaceConfigs)
+	if err != nil {
+		return err
+	}
+	orgConfigs, err := m.Cfg.GetOrgConfigs()
+	if err != nil {
+		return err
+	}
+	orgConfigMap := make(map[string]config.OrgConfig)
+	for _, orgConfig := range orgConfigs {
+		orgConfigMap[orgConfig.Org] = orgConfig
+	}
 	sgs, err := m.ListNonDefaultSecurityGroups()
 	if err != nil {
 		return err
@@ -45,10 +62,15 @@ func (m *DefaultManager) CreateApplicationSecurityGroups() error {
 		if err != nil {
 			return err
 		}
+		orgConfig := orgConfigMap[input.Org]
+		asgs, stagingASGs, err := orgConfig.SpaceASGs(&input)
+		if err != nil {
+			return err
+		}
 
 		// iterate through and assign named security groups to the space - ensuring that they are up to date is
 		// done elsewhere.
-		for _, securityGroupName := range input.ASGs {
+		for _, securityGroupName := range asgs {
 			if sgInfo, ok := sgs[securityGroupName]; ok {
 				err := m.AssignSecurityGroupToSpace(space, sgInfo)
 				if err != nil {
@@ -59,6 +81,18 @@ func (m *DefaultManager) CreateApplicationSecurityGroups() error {
 			}
 		}
 
+		// named staging security groups only apply while staging applications in the space
+		for _, securityGroupName := range stagingASGs {
+			if sgInfo, ok := sgs[securityGroupName]; ok {
+				err := m.AssignStagingSecurityGroupToSpace(space, sgInfo)
+				if err != nil {
+					return err
+				}
+			} else {
+				return fmt.Errorf("Staging security group [%s] does not exist", securityGroupName)
+			}
+		}
+
 		if input.EnableSecurityGroup {
 			sgName := fmt.Sprintf("%s-%s", input.Org, input.Space)
 			var sgInfo cfclient.SecGroup
@@ -139,6 +173,18 @@ func (m *DefaultManager) AssignDefaultSecurityGroups() error {
 		return err
 	}
 
+	// conflicting groups are rejected before any group is assigned
+	for _, runningGroup := range globalConfig.RemoveRunningSecurityGroups {
+		if m.contains(globalConfig.RunningSecurityGroups, runningGroup) {
+			return fmt.Errorf("Security group [%s] is configured in both running-security-groups and remove-running-security-groups", runningGroup)
+		}
+	}
+	for _, stagingGroup := range globalConfig.RemoveStagingSecurityGroups {
+		if m.contains(globalConfig.StagingSecurityGroups, stagingGroup) {
+			return fmt.Errorf("Security group [%s] is configured in both staging-security-groups and remove-staging-security-groups", stagingGroup)
+		}
+	}
+
 	for _, runningGroup := range globalConfig.RunningSecurityGroups {
 		if group, ok := sgs[runningGroup]; ok {
 			if !group.Running {
@@ -165,8 +211,27 @@ func (m *DefaultManager) AssignDefaultSecurityGroups() error {
 		}
 	}
 
+	for _, runningGroup := range globalConfig.RemoveRunningSecurityGroups {
+		if group, ok := sgs[runningGroup]; ok && group.Running {
+			err = m.UnassignRunningSecurityGroup(group)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, stagingGroup := range globalConfig.RemoveStagingSecurityGroups {
+		if group, ok := sgs[stagingGroup]; ok && group.Staging {
+			err = m.UnassignStagingSecurityGroup(group)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	if globalConfig.EnableUnassignSecurityGroups {
-		for groupName, group := range sgs {
+		for _, groupName := range sortedGroupNames(sgs) {
+			group := sgs[groupName]
 			if group.Running && !m.contains(globalConfig.RunningSecurityGroups, groupName) {
 				err = m.UnassignRunningSecurityGroup(group)
 				if err != nil {
@@ -198,6 +263,13 @@ func (m *DefaultManager) contains(list []string, groupName string) bool {
 func (m *DefaultManager) processSecurityGroups(securityGroupConfigs []config.ASGConfig, sgs map[string]cfclient.SecGroup) error {
 	for _, input := range securityGroupConfigs {
 		sgName := input.Name
+		if len(input.Allow) > 0 {
+			rules, err := m.generateRules(input.Allow)
+			if err != nil {
+				return err
+			}
+			input.Rules = rules
+		}
 
 		// For every named security group
 		// Check if it's a new group or Update
@@ -221,6 +293,21 @@ func (m *DefaultManager) processSecurityGroups(securityGroupConfigs []config.ASG
 	return nil
 }
 
+func (m *DefaultManager) generateRules(allow []string) (string, error) {
+	globalConfig, err := m.Cfg.GetGlobalConfig()
+	if err != nil {
+		return "", err
+	}
+	if m.Resolver == nil {
+		ttl := DefaultEndpointTTL
+		if globalConfig.ASGEndpointTTL > 0 {
+			ttl = time.Duration(globalConfig.ASGEndpointTTL) * time.Second
+		}
+		m.Resolver = NewEndpointResolver(ttl)
+	}
+	return m.Resolver.GenerateRules(allow, globalConfig.ASGEndpoints)
+}
+
 func (m *DefaultManager) hasSecurityGroupChanged(sgInfo cfclient.SecGroup, rules string) (bool, error) {
 	jsonBytes, err := json.Marshal(sgInfo.Rules)
 	if err != nil {
@@ -256,6 +343,20 @@ func (m *DefaultManager) AssignSecurityGroupToSpace(space cfclient.Space, secGro
 	return m.Client.BindSecGroup(secGroup.Guid, space.Guid)
 }
 
+func (m *DefaultManager) AssignStagingSecurityGroupToSpace(space cfclient.Space, secGroup cfclient.SecGroup) error {
+	for _, configuredSpace := range secGroup.StagingSpacesData {
+		if configuredSpace.Entity.Guid == space.Guid {
+			return nil
+		}
+	}
+	if m.Peek {
+		lo.G.Infof("[dry-run]: assigning staging security group %s to space %s", secGroup.Name, space.Name)
+		return nil
+	}
+	lo.G.Infof("assigning staging security group %s to space %s", secGroup.Name, space.Name)
+	return m.Client.BindStagingSecGroupToSpace(secGroup.Guid, space.Guid)
+}
+
 func (m *DefaultManager) CreateSecurityGroup(sgName, contents string) (*cfclient.SecGroup, error) {
 	if m.Peek {
 		lo.G.Infof("[dry-run]: creating securityGroup %s with contents %s", sgName, contents)
@@ -367,3 +468,12 @@ func (m *DefaultManager) ListSpaceSecurityGroups(spaceGUID string) (map[string]s
 	}
 	return names, nil
 }
+
+func sortedGroupNames(groups map[string]cfclient.SecGroup) []string {
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -3,6 +3,8 @@ package securitygroup_test
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"time"
 
 	cfclient "github.com/cloudfoundry-community/go-cfclient"
 	. "github.com/onsi/ginkgo"
@@ -111,9 +113,9 @@ var _ = Describe("given Security Group Manager", func() {
 		BeforeEach(func() {
 			spaceConfigs := []config.SpaceConfig{
 				config.SpaceConfig{
-					EnableSecurityGroup: true,
-					Space:               "space1",
-					Org:                 "org1",
+					EnableSecurityGroup:   true,
+					Space:                 "space1",
+					Org:                   "org1",
 					SecurityGroupContents: asg_config,
 				},
 				config.SpaceConfig{
@@ -176,6 +178,117 @@ var _ = Describe("given Security Group Manager", func() {
 			Expect(fakeClient.BindSecGroupCallCount()).Should(Equal(1))
 		})
 
+		It("Should assign staging group to space", func() {
+			spaceConfigs := []config.SpaceConfig{
+				config.SpaceConfig{
+					EnableSecurityGroup: false,
+					Space:               "space1",
+					Org:                 "org1",
+					StagingASGs:         []string{"dns"},
+				},
+			}
+			fakeReader.GetSpaceConfigsReturns(spaceConfigs, nil)
+			fakeClient.ListSecGroupsReturns([]cfclient.SecGroup{
+				cfclient.SecGroup{
+					Name: "dns",
+					Guid: "dns-guid",
+				},
+			}, nil)
+			err := securityMgr.CreateApplicationSecurityGroups()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fakeClient.BindSecGroupCallCount()).Should(Equal(0))
+			Expect(fakeClient.BindStagingSecGroupToSpaceCallCount()).Should(Equal(1))
+			sgGUID, spaceGUID := fakeClient.BindStagingSecGroupToSpaceArgsForCall(0)
+			Expect(sgGUID).Should(Equal("dns-guid"))
+			Expect(spaceGUID).Should(Equal("space1-guid"))
+		})
+
+		It("Should not assign staging group to space already bound", func() {
+			spaceConfigs := []config.SpaceConfig{
+				config.SpaceConfig{
+					EnableSecurityGroup: false,
+					Space:               "space1",
+					Org:                 "org1",
+					StagingASGs:         []string{"dns"},
+				},
+			}
+			fakeReader.GetSpaceConfigsReturns(spaceConfigs, nil)
+			fakeClient.ListSecGroupsReturns([]cfclient.SecGroup{
+				cfclient.SecGroup{
+					Name: "dns",
+					Guid: "dns-guid",
+					StagingSpacesData: []cfclient.SpaceResource{
+						cfclient.SpaceResource{
+							Entity: cfclient.Space{Guid: "space1-guid"},
+						},
+					},
+				},
+			}, nil)
+			err := securityMgr.CreateApplicationSecurityGroups()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fakeClient.BindStagingSecGroupToSpaceCallCount()).Should(Equal(0))
+		})
+
+		It("Should error when staging group doesn't exist", func() {
+			spaceConfigs := []config.SpaceConfig{
+				config.SpaceConfig{
+					EnableSecurityGroup: false,
+					Space:               "space1",
+					Org:                 "org1",
+					StagingASGs:         []string{"dns"},
+				},
+			}
+			fakeReader.GetSpaceConfigsReturns(spaceConfigs, nil)
+			fakeClient.ListSecGroupsReturns([]cfclient.SecGroup{}, nil)
+			err := securityMgr.CreateApplicationSecurityGroups()
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(Equal("Staging security group [dns] does not exist"))
+		})
+
+		It("Should assign the org default asg profile to space", func() {
+			spaceConfigs := []config.SpaceConfig{
+				config.SpaceConfig{
+					Space: "space1",
+					Org:   "org1",
+				},
+			}
+			fakeReader.GetSpaceConfigsReturns(spaceConfigs, nil)
+			fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+				config.OrgConfig{
+					Org:               "org1",
+					DefaultASGProfile: "web",
+					ASGProfiles: map[string]config.ASGProfile{
+						"web": config.ASGProfile{ASGs: []string{"dns"}, StagingASGs: []string{"dns"}},
+					},
+				},
+			}, nil)
+			fakeClient.ListSecGroupsReturns([]cfclient.SecGroup{
+				cfclient.SecGroup{
+					Name: "dns",
+					Guid: "dns-guid",
+				},
+			}, nil)
+			err := securityMgr.CreateApplicationSecurityGroups()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fakeClient.BindSecGroupCallCount()).Should(Equal(1))
+			Expect(fakeClient.BindStagingSecGroupToSpaceCallCount()).Should(Equal(1))
+		})
+
+		It("Should error when asg profile is not defined", func() {
+			spaceConfigs := []config.SpaceConfig{
+				config.SpaceConfig{
+					Space:      "space1",
+					Org:        "org1",
+					ASGProfile: "web",
+				},
+			}
+			fakeReader.GetSpaceConfigsReturns(spaceConfigs, nil)
+			err := securityMgr.CreateApplicationSecurityGroups()
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(Equal("ASG profile [web] is not defined in org [org1]"))
+			Expect(fakeClient.BindSecGroupCallCount()).Should(Equal(0))
+		})
+
 		It("Should error when group doesn't exist", func() {
 			spaceConfigs := []config.SpaceConfig{
 				config.SpaceConfig{
@@ -312,6 +425,58 @@ var _ = Describe("given Security Group Manager", func() {
 			Expect(fakeClient.CreateSecGroupCallCount()).Should(Equal(1))
 		})
 
+		It("should create 1 asg from named endpoints", func() {
+			asgConfigs := []config.ASGConfig{
+				config.ASGConfig{
+					Name:  "oracle",
+					Allow: []string{"oracle-db-prod"},
+				},
+			}
+			fakeReader.GetASGConfigsReturns(asgConfigs, nil)
+			fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{
+				ASGEndpoints: []config.ASGEndpoint{
+					config.ASGEndpoint{
+						Name:  "oracle-db-prod",
+						Hosts: []string{"oracle.example.com", "10.0.1.0/24"},
+						Ports: "1521",
+					},
+				},
+			}, nil)
+			securityMgr.Resolver = &securitygroup.EndpointResolver{
+				LookupHost: func(host string) ([]string, error) {
+					Expect(host).Should(Equal("oracle.example.com"))
+					return []string{"10.0.0.6", "10.0.0.5", "fe80::1"}, nil
+				},
+				Now: time.Now,
+				TTL: time.Minute,
+			}
+			err := securityMgr.CreateGlobalSecurityGroups()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fakeClient.CreateSecGroupCallCount()).Should(Equal(1))
+			name, rules, _ := fakeClient.CreateSecGroupArgsForCall(0)
+			Expect(name).Should(Equal("oracle"))
+			Expect(rules).Should(Equal([]cfclient.SecGroupRule{
+				cfclient.SecGroupRule{Protocol: "tcp", Ports: "1521", Destination: "10.0.0.5/32"},
+				cfclient.SecGroupRule{Protocol: "tcp", Ports: "1521", Destination: "10.0.0.6/32"},
+				cfclient.SecGroupRule{Protocol: "tcp", Ports: "1521", Destination: "10.0.1.0/24"},
+			}))
+		})
+
+		It("should error when named endpoint is not defined", func() {
+			asgConfigs := []config.ASGConfig{
+				config.ASGConfig{
+					Name:  "oracle",
+					Allow: []string{"oracle-db-prod"},
+				},
+			}
+			fakeReader.GetASGConfigsReturns(asgConfigs, nil)
+			fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{}, nil)
+			err := securityMgr.CreateGlobalSecurityGroups()
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(Equal("Endpoint [oracle-db-prod] is not defined in asg-endpoints"))
+			Expect(fakeClient.CreateSecGroupCallCount()).Should(Equal(0))
+		})
+
 		It("should update 1 asg from asg config", func() {
 			asgConfigs := []config.ASGConfig{
 				config.ASGConfig{
@@ -547,6 +712,93 @@ var _ = Describe("given Security Group Manager", func() {
 			Expect(err).ShouldNot(HaveOccurred())
 			Expect(fakeClient.UnbindStagingSecGroupCallCount()).Should(Equal(1))
 		})
+
+		It("should remove configured running and staging security groups", func() {
+			fakeClient.ListSecGroupsReturns([]cfclient.SecGroup{
+				cfclient.SecGroup{
+					Name:    "all_open",
+					Guid:    "all_open-guid",
+					Running: true,
+					Staging: true,
+				},
+				cfclient.SecGroup{
+					Name:    "dns",
+					Guid:    "dns-guid",
+					Running: true,
+				},
+			}, nil)
+			fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{
+				RunningSecurityGroups:       []string{"dns"},
+				RemoveRunningSecurityGroups: []string{"all_open", "missing"},
+				RemoveStagingSecurityGroups: []string{"all_open"},
+			}, nil)
+			err := securityMgr.AssignDefaultSecurityGroups()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fakeClient.UnbindRunningSecGroupCallCount()).Should(Equal(1))
+			Expect(fakeClient.UnbindRunningSecGroupArgsForCall(0)).Should(Equal("all_open-guid"))
+			Expect(fakeClient.UnbindStagingSecGroupCallCount()).Should(Equal(1))
+			Expect(fakeClient.UnbindStagingSecGroupArgsForCall(0)).Should(Equal("all_open-guid"))
+		})
+
+		It("should not remove security groups in peek mode", func() {
+			securityMgr.Peek = true
+			fakeClient.ListSecGroupsReturns([]cfclient.SecGroup{
+				cfclient.SecGroup{
+					Name:    "all_open",
+					Guid:    "all_open-guid",
+					Running: true,
+					Staging: true,
+				},
+			}, nil)
+			fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{
+				RemoveRunningSecurityGroups: []string{"all_open"},
+				RemoveStagingSecurityGroups: []string{"all_open"},
+			}, nil)
+			err := securityMgr.AssignDefaultSecurityGroups()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fakeClient.UnbindRunningSecGroupCallCount()).Should(Equal(0))
+			Expect(fakeClient.UnbindStagingSecGroupCallCount()).Should(Equal(0))
+		})
+
+		It("should error when group is configured to be assigned and removed", func() {
+			fakeClient.ListSecGroupsReturns([]cfclient.SecGroup{
+				cfclient.SecGroup{
+					Name:    "all_open",
+					Guid:    "all_open-guid",
+					Running: true,
+				},
+			}, nil)
+			fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{
+				RunningSecurityGroups:       []string{"all_open"},
+				RemoveRunningSecurityGroups: []string{"all_open"},
+			}, nil)
+			err := securityMgr.AssignDefaultSecurityGroups()
+			Expect(err).Should(HaveOccurred())
+			Expect(fakeClient.UnbindRunningSecGroupCallCount()).Should(Equal(0))
+		})
+
+		It("should error before assigning any group when a group is configured to be assigned and removed", func() {
+			fakeClient.ListSecGroupsReturns([]cfclient.SecGroup{
+				cfclient.SecGroup{
+					Name: "dns",
+					Guid: "dns-guid",
+				},
+				cfclient.SecGroup{
+					Name:    "all_open",
+					Guid:    "all_open-guid",
+					Staging: true,
+				},
+			}, nil)
+			fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{
+				RunningSecurityGroups:       []string{"dns"},
+				StagingSecurityGroups:       []string{"all_open"},
+				RemoveStagingSecurityGroups: []string{"all_open"},
+			}, nil)
+			err := securityMgr.AssignDefaultSecurityGroups()
+			Expect(err).Should(MatchError("Security group [all_open] is configured in both staging-security-groups and remove-staging-security-groups"))
+			Expect(fakeClient.BindRunningSecGroupCallCount()).Should(Equal(0))
+			Expect(fakeClient.UnbindStagingSecGroupCallCount()).Should(Equal(0))
+		})
 	})
 
 	Context("ListSpaceSecurityGroups", func() {
@@ -674,4 +926,152 @@ var _ = Describe("given Security Group Manager", func() {
 			Expect(fakeClient.BindRunningSecGroupCallCount()).Should(Equal(0))
 		})
 	})
+
+	Context("EgressReport", func() {
+		BeforeEach(func() {
+			fakeReader.GetSpaceConfigsReturns([]config.SpaceConfig{
+				config.SpaceConfig{Org: "org1", Space: "space1"},
+			}, nil)
+			fakeSpaceMgr.FindSpaceReturns(cfclient.Space{Name: "space1", Guid: "space1-guid"}, nil)
+		})
+
+		It("Should report rules from bound and default groups", func() {
+			fakeClient.ListSecGroupsReturns([]cfclient.SecGroup{
+				cfclient.SecGroup{
+					Name:  "dns",
+					Rules: []cfclient.SecGroupRule{cfclient.SecGroupRule{Protocol: "udp", Destination: "10.0.0.2", Ports: "53"}},
+					SpacesData: []cfclient.SpaceResource{
+						cfclient.SpaceResource{Entity: cfclient.Space{Guid: "space1-guid"}},
+					},
+				},
+				cfclient.SecGroup{
+					Name:    "public",
+					Staging: true,
+					Rules:   []cfclient.SecGroupRule{cfclient.SecGroupRule{Protocol: "all", Destination: "0.0.0.0-9.255.255.255"}},
+				},
+				cfclient.SecGroup{
+					Name:  "other",
+					Rules: []cfclient.SecGroupRule{cfclient.SecGroupRule{Protocol: "tcp", Destination: "10.0.0.3"}},
+				},
+			}, nil)
+			report, err := securityMgr.EgressReport()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(report).Should(Equal([]securitygroup.EgressRule{
+				securitygroup.EgressRule{Org: "org1", Space: "space1", SecurityGroup: "dns", Lifecycle: "running", Protocol: "udp", Destination: "10.0.0.2", Ports: "53"},
+				securitygroup.EgressRule{Org: "org1", Space: "space1", SecurityGroup: "public", Lifecycle: "staging", Protocol: "all", Destination: "0.0.0.0-9.255.255.255"},
+			}))
+		})
+
+		It("Should error listing security groups", func() {
+			fakeClient.ListSecGroupsReturns(nil, errors.New("error"))
+			_, err := securityMgr.EgressReport()
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("AnnotateEgress", func() {
+		var fakeAnnotator *securitygroupfakes.FakeAnnotator
+
+		BeforeEach(func() {
+			fakeAnnotator = new(securitygroupfakes.FakeAnnotator)
+			securityMgr.Annotator = fakeAnnotator
+			fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{EgressAnnotations: true}, nil)
+			fakeReader.GetSpaceConfigsReturns([]config.SpaceConfig{
+				config.SpaceConfig{Org: "org1", Space: "space1"},
+				config.SpaceConfig{Org: "org1", Space: "space2"},
+			}, nil)
+			fakeSpaceMgr.FindSpaceStub = func(orgName, spaceName string) (cfclient.Space, error) {
+				return cfclient.Space{Name: spaceName, Guid: spaceName + "-guid", OrganizationGuid: "org1-guid"}, nil
+			}
+			fakeClient.ListSecGroupsReturns([]cfclient.SecGroup{
+				cfclient.SecGroup{
+					Name:  "dns",
+					Rules: []cfclient.SecGroupRule{cfclient.SecGroupRule{Protocol: "udp", Destination: "10.0.0.2", Ports: "53"}},
+					SpacesData: []cfclient.SpaceResource{
+						cfclient.SpaceResource{Entity: cfclient.Space{Guid: "space1-guid"}},
+					},
+				},
+				cfclient.SecGroup{
+					Name:    "public",
+					Running: true,
+					Staging: true,
+					Rules:   []cfclient.SecGroupRule{cfclient.SecGroupRule{Protocol: "tcp", Destination: "0.0.0.0/0", Ports: "443"}},
+				},
+			}, nil)
+		})
+
+		It("Should annotate spaces and their org with a summary of their egress", func() {
+			err := securityMgr.AnnotateEgress()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fakeAnnotator.SetAnnotationsCallCount()).Should(Equal(3))
+			resource, guid, annotations := fakeAnnotator.SetAnnotationsArgsForCall(0)
+			Expect(resource).Should(Equal(securitygroup.ResourceSpaces))
+			Expect(guid).Should(Equal("space1-guid"))
+			Expect(annotations).Should(Equal(map[string]string{
+				securitygroup.EgressAnnotation: "running: udp 10.0.0.2:53, tcp 0.0.0.0/0:443; staging: tcp 0.0.0.0/0:443",
+			}))
+			resource, guid, annotations = fakeAnnotator.SetAnnotationsArgsForCall(2)
+			Expect(resource).Should(Equal(securitygroup.ResourceOrganizations))
+			Expect(guid).Should(Equal("org1-guid"))
+			Expect(annotations).Should(Equal(map[string]string{
+				securitygroup.EgressAnnotation: "running: tcp 0.0.0.0/0:443; staging: tcp 0.0.0.0/0:443",
+			}))
+		})
+
+		It("Should not update an annotation that is current", func() {
+			fakeAnnotator.GetAnnotationsReturns(map[string]string{
+				securitygroup.EgressAnnotation: "running: tcp 0.0.0.0/0:443; staging: tcp 0.0.0.0/0:443",
+			}, nil)
+			err := securityMgr.AnnotateEgress()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fakeAnnotator.SetAnnotationsCallCount()).Should(Equal(1))
+			_, guid, _ := fakeAnnotator.SetAnnotationsArgsForCall(0)
+			Expect(guid).Should(Equal("space1-guid"))
+		})
+
+		It("Should not annotate with peek", func() {
+			securityMgr.Peek = true
+			err := securityMgr.AnnotateEgress()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fakeAnnotator.SetAnnotationsCallCount()).Should(Equal(0))
+		})
+
+		It("Should annotate spaces allowed no egress with none", func() {
+			fakeClient.ListSecGroupsReturns(nil, nil)
+			err := securityMgr.AnnotateEgress()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fakeAnnotator.SetAnnotationsCallCount()).Should(Equal(3))
+			for i := 0; i < 3; i++ {
+				_, _, annotations := fakeAnnotator.SetAnnotationsArgsForCall(i)
+				Expect(annotations).Should(Equal(map[string]string{securitygroup.EgressAnnotation: "none"}))
+			}
+		})
+
+		It("Should not read the annotations of spaces and orgs a peek would create", func() {
+			securityMgr.Peek = true
+			fakeSpaceMgr.FindSpaceStub = func(orgName, spaceName string) (cfclient.Space, error) {
+				return cfclient.Space{Name: spaceName, Guid: spaceName + "-dry-run-space-guid", OrganizationGuid: orgName + "-dry-run-org-guid"}, nil
+			}
+			err := securityMgr.AnnotateEgress()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fakeAnnotator.GetAnnotationsCallCount()).Should(Equal(0))
+		})
+
+		It("Should do nothing unless egress-annotations is set", func() {
+			fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{}, nil)
+			err := securityMgr.AnnotateEgress()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fakeAnnotator.GetAnnotationsCallCount()).Should(Equal(0))
+		})
+
+		It("Should truncate summaries the cloud controller would reject", func() {
+			var rules []securitygroup.EgressRule
+			for i := 0; i < 500; i++ {
+				rules = append(rules, securitygroup.EgressRule{Lifecycle: "running", Protocol: "tcp", Destination: fmt.Sprintf("10.0.%d.%d", i/256, i%256), Ports: "443"})
+			}
+			summary := securitygroup.EgressSummary(rules)
+			Expect(len(summary)).Should(Equal(5000))
+			Expect(summary).Should(HaveSuffix("(truncated, run cf-mgmt egress-report for every rule)"))
+		})
+	})
 })
@@ -12,6 +12,8 @@ type Manager interface {
 	CreateApplicationSecurityGroups() error
 	CreateGlobalSecurityGroups() error
 	AssignDefaultSecurityGroups() error
+	EgressReport() ([]EgressRule, error)
+	AnnotateEgress() error
 }
 
 type CFClient interface {
@@ -19,6 +21,7 @@ type CFClient interface {
 	CreateSecGroup(name string, rules []cfclient.SecGroupRule, spaceGuids []string) (*cfclient.SecGroup, error)
 	UpdateSecGroup(guid, name string, rules []cfclient.SecGroupRule, spaceGuids []string) (*cfclient.SecGroup, error)
 	BindSecGroup(secGUID, spaceGUID string) error
+	BindStagingSecGroupToSpace(secGUID, spaceGUID string) error
 	BindRunningSecGroup(secGUID string) error
 	BindStagingSecGroup(secGUID string) error
 	UnbindRunningSecGroup(secGUID string) error
@@ -0,0 +1,418 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"net/url"
+	"sync"
+
+	go_cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pivotalservices/cf-mgmt/service"
+)
+
+type FakeCFClient struct {
+	ListServiceInstancesByQueryStub        func(query url.Values) ([]go_cfclient.ServiceInstance, error)
+	listServiceInstancesByQueryMutex       sync.RWMutex
+	listServiceInstancesByQueryArgsForCall []struct {
+		query url.Values
+	}
+	listServiceInstancesByQueryReturns struct {
+		result1 []go_cfclient.ServiceInstance
+		result2 error
+	}
+	ListServicesStub        func() ([]go_cfclient.Service, error)
+	listServicesMutex       sync.RWMutex
+	listServicesArgsForCall []struct{}
+	listServicesReturns     struct {
+		result1 []go_cfclient.Service
+		result2 error
+	}
+	ListServicePlansStub        func() ([]go_cfclient.ServicePlan, error)
+	listServicePlansMutex       sync.RWMutex
+	listServicePlansArgsForCall []struct{}
+	listServicePlansReturns     struct {
+		result1 []go_cfclient.ServicePlan
+		result2 error
+	}
+	ListServiceBindingsByQueryStub        func(query url.Values) ([]go_cfclient.ServiceBinding, error)
+	listServiceBindingsByQueryMutex       sync.RWMutex
+	listServiceBindingsByQueryArgsForCall []struct {
+		query url.Values
+	}
+	listServiceBindingsByQueryReturns struct {
+		result1 []go_cfclient.ServiceBinding
+		result2 error
+	}
+	ListServiceKeysByQueryStub        func(query url.Values) ([]go_cfclient.ServiceKey, error)
+	listServiceKeysByQueryMutex       sync.RWMutex
+	listServiceKeysByQueryArgsForCall []struct {
+		query url.Values
+	}
+	listServiceKeysByQueryReturns struct {
+		result1 []go_cfclient.ServiceKey
+		result2 error
+	}
+	ListAppsByQueryStub        func(query url.Values) ([]go_cfclient.App, error)
+	listAppsByQueryMutex       sync.RWMutex
+	listAppsByQueryArgsForCall []struct {
+		query url.Values
+	}
+	listAppsByQueryReturns struct {
+		result1 []go_cfclient.App
+		result2 error
+	}
+	ListEventsByQueryStub        func(query url.Values) ([]go_cfclient.Event, error)
+	listEventsByQueryMutex       sync.RWMutex
+	listEventsByQueryArgsForCall []struct {
+		query url.Values
+	}
+	listEventsByQueryReturns struct {
+		result1 []go_cfclient.Event
+		result2 error
+	}
+	CreateServiceKeyStub        func(req go_cfclient.CreateServiceKeyRequest) (go_cfclient.ServiceKey, error)
+	createServiceKeyMutex       sync.RWMutex
+	createServiceKeyArgsForCall []struct {
+		req go_cfclient.CreateServiceKeyRequest
+	}
+	createServiceKeyReturns struct {
+		result1 go_cfclient.ServiceKey
+		result2 error
+	}
+	DeleteServiceKeyStub        func(guid string) error
+	deleteServiceKeyMutex       sync.RWMutex
+	deleteServiceKeyArgsForCall []struct {
+		guid string
+	}
+	deleteServiceKeyReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeCFClient) ListServiceInstancesByQuery(query url.Values) ([]go_cfclient.ServiceInstance, error) {
+	fake.listServiceInstancesByQueryMutex.Lock()
+	fake.listServiceInstancesByQueryArgsForCall = append(fake.listServiceInstancesByQueryArgsForCall, struct {
+		query url.Values
+	}{query})
+	fake.recordInvocation("ListServiceInstancesByQuery", []interface{}{query})
+	fake.listServiceInstancesByQueryMutex.Unlock()
+	if fake.ListServiceInstancesByQueryStub != nil {
+		return fake.ListServiceInstancesByQueryStub(query)
+	} else {
+		return fake.listServiceInstancesByQueryReturns.result1, fake.listServiceInstancesByQueryReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) ListServiceInstancesByQueryCallCount() int {
+	fake.listServiceInstancesByQueryMutex.RLock()
+	defer fake.listServiceInstancesByQueryMutex.RUnlock()
+	return len(fake.listServiceInstancesByQueryArgsForCall)
+}
+
+func (fake *FakeCFClient) ListServiceInstancesByQueryArgsForCall(i int) url.Values {
+	fake.listServiceInstancesByQueryMutex.RLock()
+	defer fake.listServiceInstancesByQueryMutex.RUnlock()
+	return fake.listServiceInstancesByQueryArgsForCall[i].query
+}
+
+func (fake *FakeCFClient) ListServiceInstancesByQueryReturns(result1 []go_cfclient.ServiceInstance, result2 error) {
+	fake.ListServiceInstancesByQueryStub = nil
+	fake.listServiceInstancesByQueryReturns = struct {
+		result1 []go_cfclient.ServiceInstance
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) ListServices() ([]go_cfclient.Service, error) {
+	fake.listServicesMutex.Lock()
+	fake.listServicesArgsForCall = append(fake.listServicesArgsForCall, struct{}{})
+	fake.recordInvocation("ListServices", []interface{}{})
+	fake.listServicesMutex.Unlock()
+	if fake.ListServicesStub != nil {
+		return fake.ListServicesStub()
+	} else {
+		return fake.listServicesReturns.result1, fake.listServicesReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) ListServicesCallCount() int {
+	fake.listServicesMutex.RLock()
+	defer fake.listServicesMutex.RUnlock()
+	return len(fake.listServicesArgsForCall)
+}
+
+func (fake *FakeCFClient) ListServicesReturns(result1 []go_cfclient.Service, result2 error) {
+	fake.ListServicesStub = nil
+	fake.listServicesReturns = struct {
+		result1 []go_cfclient.Service
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) ListServicePlans() ([]go_cfclient.ServicePlan, error) {
+	fake.listServicePlansMutex.Lock()
+	fake.listServicePlansArgsForCall = append(fake.listServicePlansArgsForCall, struct{}{})
+	fake.recordInvocation("ListServicePlans", []interface{}{})
+	fake.listServicePlansMutex.Unlock()
+	if fake.ListServicePlansStub != nil {
+		return fake.ListServicePlansStub()
+	} else {
+		return fake.listServicePlansReturns.result1, fake.listServicePlansReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) ListServicePlansCallCount() int {
+	fake.listServicePlansMutex.RLock()
+	defer fake.listServicePlansMutex.RUnlock()
+	return len(fake.listServicePlansArgsForCall)
+}
+
+func (fake *FakeCFClient) ListServicePlansReturns(result1 []go_cfclient.ServicePlan, result2 error) {
+	fake.ListServicePlansStub = nil
+	fake.listServicePlansReturns = struct {
+		result1 []go_cfclient.ServicePlan
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) ListServiceBindingsByQuery(query url.Values) ([]go_cfclient.ServiceBinding, error) {
+	fake.listServiceBindingsByQueryMutex.Lock()
+	fake.listServiceBindingsByQueryArgsForCall = append(fake.listServiceBindingsByQueryArgsForCall, struct {
+		query url.Values
+	}{query})
+	fake.recordInvocation("ListServiceBindingsByQuery", []interface{}{query})
+	fake.listServiceBindingsByQueryMutex.Unlock()
+	if fake.ListServiceBindingsByQueryStub != nil {
+		return fake.ListServiceBindingsByQueryStub(query)
+	} else {
+		return fake.listServiceBindingsByQueryReturns.result1, fake.listServiceBindingsByQueryReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) ListServiceBindingsByQueryCallCount() int {
+	fake.listServiceBindingsByQueryMutex.RLock()
+	defer fake.listServiceBindingsByQueryMutex.RUnlock()
+	return len(fake.listServiceBindingsByQueryArgsForCall)
+}
+
+func (fake *FakeCFClient) ListServiceBindingsByQueryArgsForCall(i int) url.Values {
+	fake.listServiceBindingsByQueryMutex.RLock()
+	defer fake.listServiceBindingsByQueryMutex.RUnlock()
+	return fake.listServiceBindingsByQueryArgsForCall[i].query
+}
+
+func (fake *FakeCFClient) ListServiceBindingsByQueryReturns(result1 []go_cfclient.ServiceBinding, result2 error) {
+	fake.ListServiceBindingsByQueryStub = nil
+	fake.listServiceBindingsByQueryReturns = struct {
+		result1 []go_cfclient.ServiceBinding
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) ListServiceKeysByQuery(query url.Values) ([]go_cfclient.ServiceKey, error) {
+	fake.listServiceKeysByQueryMutex.Lock()
+	fake.listServiceKeysByQueryArgsForCall = append(fake.listServiceKeysByQueryArgsForCall, struct {
+		query url.Values
+	}{query})
+	fake.recordInvocation("ListServiceKeysByQuery", []interface{}{query})
+	fake.listServiceKeysByQueryMutex.Unlock()
+	if fake.ListServiceKeysByQueryStub != nil {
+		return fake.ListServiceKeysByQueryStub(query)
+	} else {
+		return fake.listServiceKeysByQueryReturns.result1, fake.listServiceKeysByQueryReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) ListServiceKeysByQueryCallCount() int {
+	fake.listServiceKeysByQueryMutex.RLock()
+	defer fake.listServiceKeysByQueryMutex.RUnlock()
+	return len(fake.listServiceKeysByQueryArgsForCall)
+}
+
+func (fake *FakeCFClient) ListServiceKeysByQueryArgsForCall(i int) url.Values {
+	fake.listServiceKeysByQueryMutex.RLock()
+	defer fake.listServiceKeysByQueryMutex.RUnlock()
+	return fake.listServiceKeysByQueryArgsForCall[i].query
+}
+
+func (fake *FakeCFClient) ListServiceKeysByQueryReturns(result1 []go_cfclient.ServiceKey, result2 error) {
+	fake.ListServiceKeysByQueryStub = nil
+	fake.listServiceKeysByQueryReturns = struct {
+		result1 []go_cfclient.ServiceKey
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) ListAppsByQuery(query url.Values) ([]go_cfclient.App, error) {
+	fake.listAppsByQueryMutex.Lock()
+	fake.listAppsByQueryArgsForCall = append(fake.listAppsByQueryArgsForCall, struct {
+		query url.Values
+	}{query})
+	fake.recordInvocation("ListAppsByQuery", []interface{}{query})
+	fake.listAppsByQueryMutex.Unlock()
+	if fake.ListAppsByQueryStub != nil {
+		return fake.ListAppsByQueryStub(query)
+	} else {
+		return fake.listAppsByQueryReturns.result1, fake.listAppsByQueryReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) ListAppsByQueryCallCount() int {
+	fake.listAppsByQueryMutex.RLock()
+	defer fake.listAppsByQueryMutex.RUnlock()
+	return len(fake.listAppsByQueryArgsForCall)
+}
+
+func (fake *FakeCFClient) ListAppsByQueryArgsForCall(i int) url.Values {
+	fake.listAppsByQueryMutex.RLock()
+	defer fake.listAppsByQueryMutex.RUnlock()
+	return fake.listAppsByQueryArgsForCall[i].query
+}
+
+func (fake *FakeCFClient) ListAppsByQueryReturns(result1 []go_cfclient.App, result2 error) {
+	fake.ListAppsByQueryStub = nil
+	fake.listAppsByQueryReturns = struct {
+		result1 []go_cfclient.App
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) ListEventsByQuery(query url.Values) ([]go_cfclient.Event, error) {
+	fake.listEventsByQueryMutex.Lock()
+	fake.listEventsByQueryArgsForCall = append(fake.listEventsByQueryArgsForCall, struct {
+		query url.Values
+	}{query})
+	fake.recordInvocation("ListEventsByQuery", []interface{}{query})
+	fake.listEventsByQueryMutex.Unlock()
+	if fake.ListEventsByQueryStub != nil {
+		return fake.ListEventsByQueryStub(query)
+	} else {
+		return fake.listEventsByQueryReturns.result1, fake.listEventsByQueryReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) ListEventsByQueryCallCount() int {
+	fake.listEventsByQueryMutex.RLock()
+	defer fake.listEventsByQueryMutex.RUnlock()
+	return len(fake.listEventsByQueryArgsForCall)
+}
+
+func (fake *FakeCFClient) ListEventsByQueryArgsForCall(i int) url.Values {
+	fake.listEventsByQueryMutex.RLock()
+	defer fake.listEventsByQueryMutex.RUnlock()
+	return fake.listEventsByQueryArgsForCall[i].query
+}
+
+func (fake *FakeCFClient) ListEventsByQueryReturns(result1 []go_cfclient.Event, result2 error) {
+	fake.ListEventsByQueryStub = nil
+	fake.listEventsByQueryReturns = struct {
+		result1 []go_cfclient.Event
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) CreateServiceKey(req go_cfclient.CreateServiceKeyRequest) (go_cfclient.ServiceKey, error) {
+	fake.createServiceKeyMutex.Lock()
+	fake.createServiceKeyArgsForCall = append(fake.createServiceKeyArgsForCall, struct {
+		req go_cfclient.CreateServiceKeyRequest
+	}{req})
+	fake.recordInvocation("CreateServiceKey", []interface{}{req})
+	fake.createServiceKeyMutex.Unlock()
+	if fake.CreateServiceKeyStub != nil {
+		return fake.CreateServiceKeyStub(req)
+	} else {
+		return fake.createServiceKeyReturns.result1, fake.createServiceKeyReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) CreateServiceKeyCallCount() int {
+	fake.createServiceKeyMutex.RLock()
+	defer fake.createServiceKeyMutex.RUnlock()
+	return len(fake.createServiceKeyArgsForCall)
+}
+
+func (fake *FakeCFClient) CreateServiceKeyArgsForCall(i int) go_cfclient.CreateServiceKeyRequest {
+	fake.createServiceKeyMutex.RLock()
+	defer fake.createServiceKeyMutex.RUnlock()
+	return fake.createServiceKeyArgsForCall[i].req
+}
+
+func (fake *FakeCFClient) CreateServiceKeyReturns(result1 go_cfclient.ServiceKey, result2 error) {
+	fake.CreateServiceKeyStub = nil
+	fake.createServiceKeyReturns = struct {
+		result1 go_cfclient.ServiceKey
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) DeleteServiceKey(guid string) error {
+	fake.deleteServiceKeyMutex.Lock()
+	fake.deleteServiceKeyArgsForCall = append(fake.deleteServiceKeyArgsForCall, struct {
+		guid string
+	}{guid})
+	fake.recordInvocation("DeleteServiceKey", []interface{}{guid})
+	fake.deleteServiceKeyMutex.Unlock()
+	if fake.DeleteServiceKeyStub != nil {
+		return fake.DeleteServiceKeyStub(guid)
+	} else {
+		return fake.deleteServiceKeyReturns.result1
+	}
+}
+
+func (fake *FakeCFClient) DeleteServiceKeyCallCount() int {
+	fake.deleteServiceKeyMutex.RLock()
+	defer fake.deleteServiceKeyMutex.RUnlock()
+	return len(fake.deleteServiceKeyArgsForCall)
+}
+
+func (fake *FakeCFClient) DeleteServiceKeyArgsForCall(i int) string {
+	fake.deleteServiceKeyMutex.RLock()
+	defer fake.deleteServiceKeyMutex.RUnlock()
+	return fake.deleteServiceKeyArgsForCall[i].guid
+}
+
+func (fake *FakeCFClient) DeleteServiceKeyReturns(result1 error) {
+	fake.DeleteServiceKeyStub = nil
+	fake.deleteServiceKeyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCFClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.listServiceInstancesByQueryMutex.RLock()
+	defer fake.listServiceInstancesByQueryMutex.RUnlock()
+	fake.listServicesMutex.RLock()
+	defer fake.listServicesMutex.RUnlock()
+	fake.listServicePlansMutex.RLock()
+	defer fake.listServicePlansMutex.RUnlock()
+	fake.listServiceBindingsByQueryMutex.RLock()
+	defer fake.listServiceBindingsByQueryMutex.RUnlock()
+	fake.listServiceKeysByQueryMutex.RLock()
+	defer fake.listServiceKeysByQueryMutex.RUnlock()
+	fake.listAppsByQueryMutex.RLock()
+	defer fake.listAppsByQueryMutex.RUnlock()
+	fake.listEventsByQueryMutex.RLock()
+	defer fake.listEventsByQueryMutex.RUnlock()
+	fake.createServiceKeyMutex.RLock()
+	defer fake.createServiceKeyMutex.RUnlock()
+	fake.deleteServiceKeyMutex.RLock()
+	defer fake.deleteServiceKeyMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeCFClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ service.CFClient = new(FakeCFClient)
@@ -0,0 +1,151 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/service"
+)
+
+type FakeManager struct {
+	ServiceViolationsStub        func() ([]service.Violation, error)
+	serviceViolationsMutex       sync.RWMutex
+	serviceViolationsArgsForCall []struct{}
+	serviceViolationsReturns     struct {
+		result1 []service.Violation
+		result2 error
+	}
+	ServiceInventoryStub        func() ([]service.InventoryItem, error)
+	serviceInventoryMutex       sync.RWMutex
+	serviceInventoryArgsForCall []struct{}
+	serviceInventoryReturns     struct {
+		result1 []service.InventoryItem
+		result2 error
+	}
+	RotateServiceKeysStub        func(policy config.ServiceKeyRotation, credentials service.CredentialWriter) ([]service.KeyRotation, error)
+	rotateServiceKeysMutex       sync.RWMutex
+	rotateServiceKeysArgsForCall []struct {
+		policy      config.ServiceKeyRotation
+		credentials service.CredentialWriter
+	}
+	rotateServiceKeysReturns struct {
+		result1 []service.KeyRotation
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeManager) ServiceViolations() ([]service.Violation, error) {
+	fake.serviceViolationsMutex.Lock()
+	fake.serviceViolationsArgsForCall = append(fake.serviceViolationsArgsForCall, struct{}{})
+	fake.recordInvocation("ServiceViolations", []interface{}{})
+	fake.serviceViolationsMutex.Unlock()
+	if fake.ServiceViolationsStub != nil {
+		return fake.ServiceViolationsStub()
+	} else {
+		return fake.serviceViolationsReturns.result1, fake.serviceViolationsReturns.result2
+	}
+}
+
+func (fake *FakeManager) ServiceViolationsCallCount() int {
+	fake.serviceViolationsMutex.RLock()
+	defer fake.serviceViolationsMutex.RUnlock()
+	return len(fake.serviceViolationsArgsForCall)
+}
+
+func (fake *FakeManager) ServiceViolationsReturns(result1 []service.Violation, result2 error) {
+	fake.ServiceViolationsStub = nil
+	fake.serviceViolationsReturns = struct {
+		result1 []service.Violation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) ServiceInventory() ([]service.InventoryItem, error) {
+	fake.serviceInventoryMutex.Lock()
+	fake.serviceInventoryArgsForCall = append(fake.serviceInventoryArgsForCall, struct{}{})
+	fake.recordInvocation("ServiceInventory", []interface{}{})
+	fake.serviceInventoryMutex.Unlock()
+	if fake.ServiceInventoryStub != nil {
+		return fake.ServiceInventoryStub()
+	} else {
+		return fake.serviceInventoryReturns.result1, fake.serviceInventoryReturns.result2
+	}
+}
+
+func (fake *FakeManager) ServiceInventoryCallCount() int {
+	fake.serviceInventoryMutex.RLock()
+	defer fake.serviceInventoryMutex.RUnlock()
+	return len(fake.serviceInventoryArgsForCall)
+}
+
+func (fake *FakeManager) ServiceInventoryReturns(result1 []service.InventoryItem, result2 error) {
+	fake.ServiceInventoryStub = nil
+	fake.serviceInventoryReturns = struct {
+		result1 []service.InventoryItem
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) RotateServiceKeys(policy config.ServiceKeyRotation, credentials service.CredentialWriter) ([]service.KeyRotation, error) {
+	fake.rotateServiceKeysMutex.Lock()
+	fake.rotateServiceKeysArgsForCall = append(fake.rotateServiceKeysArgsForCall, struct {
+		policy      config.ServiceKeyRotation
+		credentials service.CredentialWriter
+	}{policy, credentials})
+	fake.recordInvocation("RotateServiceKeys", []interface{}{policy, credentials})
+	fake.rotateServiceKeysMutex.Unlock()
+	if fake.RotateServiceKeysStub != nil {
+		return fake.RotateServiceKeysStub(policy, credentials)
+	} else {
+		return fake.rotateServiceKeysReturns.result1, fake.rotateServiceKeysReturns.result2
+	}
+}
+
+func (fake *FakeManager) RotateServiceKeysCallCount() int {
+	fake.rotateServiceKeysMutex.RLock()
+	defer fake.rotateServiceKeysMutex.RUnlock()
+	return len(fake.rotateServiceKeysArgsForCall)
+}
+
+func (fake *FakeManager) RotateServiceKeysArgsForCall(i int) (config.ServiceKeyRotation, service.CredentialWriter) {
+	fake.rotateServiceKeysMutex.RLock()
+	defer fake.rotateServiceKeysMutex.RUnlock()
+	return fake.rotateServiceKeysArgsForCall[i].policy, fake.rotateServiceKeysArgsForCall[i].credentials
+}
+
+func (fake *FakeManager) RotateServiceKeysReturns(result1 []service.KeyRotation, result2 error) {
+	fake.RotateServiceKeysStub = nil
+	fake.rotateServiceKeysReturns = struct {
+		result1 []service.KeyRotation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.serviceViolationsMutex.RLock()
+	defer fake.serviceViolationsMutex.RUnlock()
+	fake.serviceInventoryMutex.RLock()
+	defer fake.serviceInventoryMutex.RUnlock()
+	fake.rotateServiceKeysMutex.RLock()
+	defer fake.rotateServiceKeysMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeManager) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ service.Manager = new(FakeManager)
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/xchapter7x/lo"
+)
+
+// Kinds of the items of an inventory.
+const (
+	KindInstance = "instance"
+	KindBinding  = "binding"
+	KindKey      = "key"
+)
+
+// createEventTypes are the audit events recording who created an item of an
+// inventory
+var createEventTypes = []string{
+	"audit.service_instance.create",
+	"audit.service_binding.create",
+	"audit.service_key.create",
+}
+
+// guidsPerQuery is how many guids a filter with IN holds, to keep urls short
+const guidsPerQuery = 50
+
+// InventoryItem is a service instance of a managed space, or a binding or key
+// of one. Name is the name of the instance, the app of a binding or the name
+// of a key, and CreatedBy is empty once the audit event of its creation has
+// expired.
+type InventoryItem struct {
+	Org       string `json:"org"`
+	Space     string `json:"space"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Instance  string `json:"instance"`
+	Service   string `json:"service"`
+	Plan      string `json:"plan"`
+	GUID      string `json:"guid"`
+	CreatedAt string `json:"created_at"`
+	CreatedBy string `json:"created_by"`
+	AgeDays   int    `json:"age_days"`
+}
+
+//ServiceInventory - lists the service instances of every space of the managed orgs, including spaces not in the
+//configuration, each followed by its bindings and keys, with who created them and how many days ago
+func (m *DefaultManager) ServiceInventory() ([]InventoryItem, error) {
+	spaces, err := m.SpaceMgr.ListManagedSpaces()
+	if err != nil {
+		return nil, err
+	}
+	serviceNames, planNames, err := m.marketplaceNames()
+	if err != nil {
+		return nil, err
+	}
+	var orgGUIDs []string
+	orgs := make(map[string][]spaceInventory)
+	for _, managed := range spaces {
+		guid := managed.Space.OrganizationGuid
+		if _, ok := orgs[guid]; !ok {
+			orgGUIDs = append(orgGUIDs, guid)
+		}
+		orgs[guid] = append(orgs[guid], spaceInventory{org: managed.Org, space: managed.Space})
+	}
+
+	items := []InventoryItem{}
+	for _, orgGUID := range orgGUIDs {
+		instances, err := m.Client.ListServiceInstancesByQuery(url.Values{"q": []string{"organization_guid:" + orgGUID}})
+		if err != nil {
+			return nil, err
+		}
+		spacesByGUID := make(map[string]spaceInventory)
+		for _, space := range orgs[orgGUID] {
+			spacesByGUID[space.space.Guid] = space
+		}
+		var instanceGUIDs []string
+		for _, instance := range instances {
+			if _, ok := spacesByGUID[instance.SpaceGuid]; ok {
+				instanceGUIDs = append(instanceGUIDs, instance.Guid)
+			}
+		}
+		if len(instanceGUIDs) == 0 {
+			continue
+		}
+		bindings, keys, err := m.bindingsAndKeys(instanceGUIDs)
+		if err != nil {
+			return nil, err
+		}
+		appNames, err := m.appNames(orgGUID)
+		if err != nil {
+			return nil, err
+		}
+		creators, err := m.creators(orgGUID)
+		if err != nil {
+			return nil, err
+		}
+		item := func(space spaceInventory, kind, name, guid, createdAt string) InventoryItem {
+			return InventoryItem{
+				Org:       space.org,
+				Space:     space.space.Name,
+				Kind:      kind,
+				Name:      name,
+				GUID:      guid,
+				CreatedAt: createdAt,
+				CreatedBy: creators[guid],
+				AgeDays:   ageDays(createdAt, m.Now()),
+			}
+		}
+		for _, instance := range instances {
+			space, ok := spacesByGUID[instance.SpaceGuid]
+			if !ok {
+				continue
+			}
+			service, plan := nameOrGUID(serviceNames, instance.ServiceGuid), nameOrGUID(planNames, instance.ServicePlanGuid)
+			instanceItems := []InventoryItem{item(space, KindInstance, instance.Name, instance.Guid, instance.CreatedAt)}
+			for _, binding := range bindings[instance.Guid] {
+				instanceItems = append(instanceItems, item(space, KindBinding, nameOrGUID(appNames, binding.AppGuid), binding.Guid, binding.CreatedAt))
+			}
+			for _, key := range keys[instance.Guid] {
+				instanceItems = append(instanceItems, item(space, KindKey, key.Name, key.Guid, key.CreatedAt))
+			}
+			for i := range instanceItems {
+				instanceItems[i].Instance, instanceItems[i].Service, instanceItems[i].Plan = instance.Name, service, plan
+			}
+			items = append(items, instanceItems...)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Org != items[j].Org {
+			return items[i].Org < items[j].Org
+		}
+		if items[i].Space != items[j].Space {
+			return items[i].Space < items[j].Space
+		}
+		return items[i].Instance < items[j].Instance
+	})
+	return items, nil
+}
+
+type spaceInventory struct {
+	org   string
+	space cfclient.Space
+}
+
+// bindingsAndKeys are the bindings and keys of the instances, by instance guid
+func (m *DefaultManager) bindingsAndKeys(instanceGUIDs []string) (map[string][]cfclient.ServiceBinding, map[string][]cfclient.ServiceKey, error) {
+	bindings := make(map[string][]cfclient.ServiceBinding)
+	keys := make(map[string][]cfclient.ServiceKey)
+	for start := 0; start < len(instanceGUIDs); start += guidsPerQuery {
+		end := start + guidsPerQuery
+		if end > len(instanceGUIDs) {
+			end = len(instanceGUIDs)
+		}
+		query := url.Values{"q": []string{"service_instance_guid IN " + strings.Join(instanceGUIDs[start:end], ",")}}
+		instanceBindings, err := m.Client.ListServiceBindingsByQuery(query)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, binding := range instanceBindings {
+			bindings[binding.ServiceInstanceGuid] = append(bindings[binding.ServiceInstanceGuid], binding)
+		}
+		instanceKeys, err := m.Client.ListServiceKeysByQuery(query)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, key := range instanceKeys {
+			keys[key.ServiceInstanceGuid] = append(keys[key.ServiceInstanceGuid], key)
+		}
+	}
+	return bindings, keys, nil
+}
+
+// appNames are the names of the apps of an org by guid
+func (m *DefaultManager) appNames(orgGUID string) (map[string]string, error) {
+	apps, err := m.Client.ListAppsByQuery(url.Values{"q": []string{"organization_guid:" + orgGUID}})
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string)
+	for _, app := range apps {
+		names[app.Guid] = app.Name
+	}
+	return names, nil
+}
+
+// creators are the users, or clients, that created the instances, bindings
+// and keys of an org by guid, as long as the cloud controller keeps the audit
+// events of their creation
+func (m *DefaultManager) creators(orgGUID string) (map[string]string, error) {
+	events, err := m.Client.ListEventsByQuery(url.Values{"q": []string{
+		"organization_guid:" + orgGUID,
+		"type IN " + strings.Join(createEventTypes, ","),
+	}})
+	if err != nil {
+		return nil, err
+	}
+	creators := make(map[string]string)
+	for _, event := range events {
+		if !isCreateEvent(event.Type) {
+			continue
+		}
+		actor := event.ActorUsername
+		if actor == "" {
+			actor = event.ActorName
+		}
+		creators[event.Actee] = actor
+	}
+	return creators, nil
+}
+
+func isCreateEvent(eventType string) bool {
+	for _, createEventType := range createEventTypes {
+		if eventType == createEventType {
+			return true
+		}
+	}
+	return false
+}
+
+func ageDays(createdAt string, now time.Time) int {
+	created, err := time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		lo.G.Debugf("Unable to read creation time %s", createdAt)
+		return 0
+	}
+	return int(now.Sub(created).Hours() / 24)
+}
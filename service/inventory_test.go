@@ -0,0 +1,77 @@
+package service_test
+
+import (
+	"time"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/service"
+	servicefakes "github.com/pivotalservices/cf-mgmt/service/fakes"
+	"github.com/pivotalservices/cf-mgmt/space"
+	spacefakes "github.com/pivotalservices/cf-mgmt/space/fakes"
+)
+
+var _ = Describe("Service Inventory", func() {
+	var (
+		fakeSpaceMgr *spacefakes.FakeManager
+		fakeClient   *servicefakes.FakeCFClient
+		manager      service.DefaultManager
+	)
+
+	BeforeEach(func() {
+		fakeSpaceMgr = new(spacefakes.FakeManager)
+		fakeClient = new(servicefakes.FakeCFClient)
+		manager = service.DefaultManager{
+			SpaceMgr: fakeSpaceMgr,
+			Client:   fakeClient,
+			Now: func() time.Time {
+				return time.Date(2020, 3, 31, 12, 0, 0, 0, time.UTC)
+			},
+		}
+		fakeSpaceMgr.ListManagedSpacesReturns([]space.ManagedSpace{
+			{Org: "payments", Space: cfclient.Space{Name: "prod", Guid: "prod-guid", OrganizationGuid: "payments-guid"}},
+		}, nil)
+		fakeClient.ListServicesReturns([]cfclient.Service{{Label: "p.mysql", Guid: "mysql-guid"}}, nil)
+		fakeClient.ListServicePlansReturns([]cfclient.ServicePlan{{Name: "db-small", Guid: "db-small-guid", ServiceGuid: "mysql-guid"}}, nil)
+		fakeClient.ListServiceInstancesByQueryReturns([]cfclient.ServiceInstance{
+			{Name: "orders-db", Guid: "orders-db-guid", SpaceGuid: "prod-guid", ServiceGuid: "mysql-guid", ServicePlanGuid: "db-small-guid", CreatedAt: "2020-01-01T00:00:00Z"},
+			{Name: "unmanaged-db", Guid: "unmanaged-db-guid", SpaceGuid: "other-guid", ServiceGuid: "mysql-guid", ServicePlanGuid: "db-small-guid"},
+		}, nil)
+		fakeClient.ListServiceBindingsByQueryReturns([]cfclient.ServiceBinding{
+			{Guid: "binding-guid", AppGuid: "orders-guid", ServiceInstanceGuid: "orders-db-guid", CreatedAt: "2020-03-01T00:00:00Z"},
+		}, nil)
+		fakeClient.ListServiceKeysByQueryReturns([]cfclient.ServiceKey{
+			{Name: "reporting", Guid: "key-guid", ServiceInstanceGuid: "orders-db-guid", CreatedAt: "2020-03-30T00:00:00Z"},
+		}, nil)
+		fakeClient.ListAppsByQueryReturns([]cfclient.App{{Name: "orders", Guid: "orders-guid"}}, nil)
+		fakeClient.ListEventsByQueryReturns([]cfclient.Event{
+			{Type: "audit.service_instance.create", Actee: "orders-db-guid", ActorUsername: "alice@example.com"},
+			{Type: "audit.service_instance.update", Actee: "orders-db-guid", ActorUsername: "bob@example.com"},
+			{Type: "audit.service_key.create", Actee: "key-guid", ActorName: "reporting-client"},
+		}, nil)
+	})
+
+	It("lists the instances of the managed spaces with their bindings and keys", func() {
+		items, err := manager.ServiceInventory()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(items).Should(Equal([]service.InventoryItem{
+			{Org: "payments", Space: "prod", Kind: service.KindInstance, Name: "orders-db", Instance: "orders-db", Service: "p.mysql", Plan: "db-small",
+				GUID: "orders-db-guid", CreatedAt: "2020-01-01T00:00:00Z", CreatedBy: "alice@example.com", AgeDays: 90},
+			{Org: "payments", Space: "prod", Kind: service.KindBinding, Name: "orders", Instance: "orders-db", Service: "p.mysql", Plan: "db-small",
+				GUID: "binding-guid", CreatedAt: "2020-03-01T00:00:00Z", AgeDays: 30},
+			{Org: "payments", Space: "prod", Kind: service.KindKey, Name: "reporting", Instance: "orders-db", Service: "p.mysql", Plan: "db-small",
+				GUID: "key-guid", CreatedAt: "2020-03-30T00:00:00Z", CreatedBy: "reporting-client", AgeDays: 1},
+		}))
+		Expect(fakeClient.ListServiceBindingsByQueryArgsForCall(0).Get("q")).Should(Equal("service_instance_guid IN orders-db-guid"))
+		Expect(fakeClient.ListEventsByQueryArgsForCall(0)["q"]).Should(ContainElement("organization_guid:payments-guid"))
+	})
+
+	It("does not list bindings and keys of orgs without instances", func() {
+		fakeClient.ListServiceInstancesByQueryReturns(nil, nil)
+		items, err := manager.ServiceInventory()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(items).Should(BeEmpty())
+		Expect(fakeClient.ListServiceBindingsByQueryCallCount()).Should(Equal(0))
+	})
+})
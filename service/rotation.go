@@ -0,0 +1,132 @@
+package service
+
+import (
+	"fmt"
+	"path"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pkg/errors"
+	"github.com/xchapter7x/lo"
+)
+
+// Statuses of the service keys older than the max-age-days of the rotation
+// policy.
+const (
+	RotationRotated  = "rotated"
+	RotationDryRun   = "dry run"
+	RotationOptedOut = "not opted in"
+	RotationFailed   = "failed"
+)
+
+// defaultCredHubPath is what the credentials of rotated keys are written
+// under when the policy sets no credhub path
+const defaultCredHubPath = "/cf-mgmt/service-keys"
+
+// KeyRotation is a service key of a managed space older than the max-age-days
+// of the rotation policy. CredHubName is the credential the credentials of
+// the recreated key are written to.
+type KeyRotation struct {
+	Org         string `json:"org"`
+	Space       string `json:"space"`
+	Instance    string `json:"instance"`
+	Key         string `json:"key"`
+	AgeDays     int    `json:"age_days"`
+	Status      string `json:"status"`
+	CredHubName string `json:"credhub_name,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+//RotateServiceKeys - lists the service keys of the managed spaces older than the max-age-days of the policy,
+//recreating those of the spaces that set rotate-service-keys and writing the credentials of each new key to
+//credentials. A key that fails to rotate is listed as failed and the remaining keys are still rotated.
+func (m *DefaultManager) RotateServiceKeys(policy config.ServiceKeyRotation, credentials CredentialWriter) ([]KeyRotation, error) {
+	items, err := m.ServiceInventory()
+	if err != nil {
+		return nil, err
+	}
+	spaceConfigs, err := m.Cfg.GetSpaceConfigs()
+	if err != nil {
+		return nil, err
+	}
+	optedIn := make(map[string]bool)
+	for _, spaceConfig := range spaceConfigs {
+		if spaceConfig.RotateServiceKeys {
+			optedIn[spaceConfig.Org+"/"+spaceConfig.Space] = true
+		}
+	}
+	instanceGUIDs := make(map[string]string)
+	for _, item := range items {
+		if item.Kind == KindInstance {
+			instanceGUIDs[item.Org+"/"+item.Space+"/"+item.Instance] = item.GUID
+		}
+	}
+	credHubPath := policy.CredHub.Path
+	if credHubPath == "" {
+		credHubPath = defaultCredHubPath
+	}
+
+	rotations := []KeyRotation{}
+	for _, item := range items {
+		if item.Kind != KindKey || item.AgeDays <= policy.MaxAgeDays {
+			continue
+		}
+		rotation := KeyRotation{Org: item.Org, Space: item.Space, Instance: item.Instance, Key: item.Name, AgeDays: item.AgeDays}
+		if !optedIn[item.Org+"/"+item.Space] {
+			rotation.Status = RotationOptedOut
+			rotations = append(rotations, rotation)
+			continue
+		}
+		rotation.CredHubName = path.Join("/", credHubPath, item.Org, item.Space, item.Instance, item.Name)
+		rotation.Status = RotationRotated
+		if m.Peek {
+			lo.G.Infof("[dry-run]: recreating service key %s of instance %s in org/space %s/%s, %d days old, and writing its credentials to credhub as %s",
+				item.Name, item.Instance, item.Org, item.Space, item.AgeDays, rotation.CredHubName)
+			rotation.Status = RotationDryRun
+		} else if err := m.rotateKey(instanceGUIDs[item.Org+"/"+item.Space+"/"+item.Instance], item, rotation.CredHubName, credentials); err != nil {
+			lo.G.Errorf("Unable to rotate service key %s of instance %s in org/space %s/%s: %s", item.Name, item.Instance, item.Org, item.Space, err)
+			rotation.Status = RotationFailed
+			rotation.Error = err.Error()
+		}
+		rotations = append(rotations, rotation)
+	}
+	return rotations, nil
+}
+
+// rotateKey replaces the key with a key of a temporary name before
+// replacing that with a key of the same name, as the names of the keys of an
+// instance are unique, so that the key is only deleted once its replacement
+// has been created and its credentials written to the credhub credential name
+func (m *DefaultManager) rotateKey(instanceGUID string, item InventoryItem, name string, credentials CredentialWriter) error {
+	tempName := fmt.Sprintf("%s-rotating-%d", item.Name, m.Now().Unix())
+	temp, err := m.replaceKey(instanceGUID, item.GUID, tempName, name, credentials)
+	if err != nil {
+		return err
+	}
+	if _, err := m.replaceKey(instanceGUID, temp.Guid, item.Name, name, credentials); err != nil {
+		return errors.Wrapf(err, "the key was replaced by %s, whose credentials were written to credhub as %s, but could not be recreated", tempName, name)
+	}
+	lo.G.Infof("Recreated service key %s of instance %s in org/space %s/%s, %d days old, its credentials written to credhub as %s",
+		item.Name, item.Instance, item.Org, item.Space, item.AgeDays, name)
+	return nil
+}
+
+// replaceKey creates a key named keyName, writes its credentials to the
+// credhub credential name and only then deletes the key of oldGUID. A key that
+// was created but whose credentials could not be written is deleted again.
+func (m *DefaultManager) replaceKey(instanceGUID, oldGUID, keyName, name string, credentials CredentialWriter) (cfclient.ServiceKey, error) {
+	key, err := m.Client.CreateServiceKey(cfclient.CreateServiceKeyRequest{Name: keyName, ServiceInstanceGuid: instanceGUID})
+	if err != nil {
+		return key, errors.Wrapf(err, "the key %s could not be created", keyName)
+	}
+	if err := credentials.SetJSON(name, key.Credentials); err != nil {
+		if deleteErr := m.Client.DeleteServiceKey(key.Guid); deleteErr != nil {
+			lo.G.Errorf("Unable to delete service key %s: %s", keyName, deleteErr)
+		}
+		return key, errors.Wrapf(err, "the credentials of the key %s could not be written to credhub as %s", keyName, name)
+	}
+	if err := m.Client.DeleteServiceKey(oldGUID); err != nil {
+		return key, errors.Wrapf(err, "the key %s was created and its credentials written to credhub as %s but the key it replaces could not be deleted", keyName, name)
+	}
+	return key, nil
+}
@@ -0,0 +1,133 @@
+package service_test
+
+import (
+	"errors"
+	"time"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/config"
+	configfakes "github.com/pivotalservices/cf-mgmt/config/fakes"
+	"github.com/pivotalservices/cf-mgmt/service"
+	servicefakes "github.com/pivotalservices/cf-mgmt/service/fakes"
+	"github.com/pivotalservices/cf-mgmt/space"
+	spacefakes "github.com/pivotalservices/cf-mgmt/space/fakes"
+)
+
+type credentialWriter map[string]interface{}
+
+func (w credentialWriter) SetJSON(name string, value interface{}) error {
+	w[name] = value
+	return nil
+}
+
+type failingCredentialWriter struct{}
+
+func (failingCredentialWriter) SetJSON(name string, value interface{}) error {
+	return errors.New("credhub unavailable")
+}
+
+var _ = Describe("Service Key Rotation", func() {
+	var (
+		fakeReader   *configfakes.FakeReader
+		fakeSpaceMgr *spacefakes.FakeManager
+		fakeClient   *servicefakes.FakeCFClient
+		manager      service.DefaultManager
+		credentials  credentialWriter
+		policy       config.ServiceKeyRotation
+	)
+
+	BeforeEach(func() {
+		fakeReader = new(configfakes.FakeReader)
+		fakeSpaceMgr = new(spacefakes.FakeManager)
+		fakeClient = new(servicefakes.FakeCFClient)
+		manager = service.DefaultManager{
+			Cfg:      fakeReader,
+			SpaceMgr: fakeSpaceMgr,
+			Client:   fakeClient,
+			Now: func() time.Time {
+				return time.Date(2020, 3, 31, 12, 0, 0, 0, time.UTC)
+			},
+		}
+		credentials = credentialWriter{}
+		policy = config.ServiceKeyRotation{MaxAgeDays: 60}
+		fakeReader.GetSpaceConfigsReturns([]config.SpaceConfig{
+			{Org: "payments", Space: "prod", RotateServiceKeys: true},
+			{Org: "payments", Space: "dev"},
+		}, nil)
+		fakeSpaceMgr.ListManagedSpacesReturns([]space.ManagedSpace{
+			{Org: "payments", Space: cfclient.Space{Name: "prod", Guid: "prod-guid", OrganizationGuid: "payments-guid"}},
+			{Org: "payments", Space: cfclient.Space{Name: "dev", Guid: "dev-guid", OrganizationGuid: "payments-guid"}},
+		}, nil)
+		fakeClient.ListServiceInstancesByQueryReturns([]cfclient.ServiceInstance{
+			{Name: "orders-db", Guid: "orders-db-guid", SpaceGuid: "prod-guid", CreatedAt: "2019-01-01T00:00:00Z"},
+			{Name: "test-db", Guid: "test-db-guid", SpaceGuid: "dev-guid", CreatedAt: "2019-01-01T00:00:00Z"},
+		}, nil)
+		fakeClient.ListServiceKeysByQueryReturns([]cfclient.ServiceKey{
+			{Name: "reporting", Guid: "reporting-guid", ServiceInstanceGuid: "orders-db-guid", CreatedAt: "2020-01-01T00:00:00Z"},
+			{Name: "recent", Guid: "recent-guid", ServiceInstanceGuid: "orders-db-guid", CreatedAt: "2020-03-30T00:00:00Z"},
+			{Name: "debug", Guid: "debug-guid", ServiceInstanceGuid: "test-db-guid", CreatedAt: "2020-01-01T00:00:00Z"},
+		}, nil)
+		fakeClient.CreateServiceKeyStub = func(req cfclient.CreateServiceKeyRequest) (cfclient.ServiceKey, error) {
+			return cfclient.ServiceKey{
+				Name:        req.Name,
+				Guid:        req.Name + "-guid",
+				Credentials: map[string]interface{}{"password": req.Name},
+			}, nil
+		}
+	})
+
+	It("recreates the old keys of the spaces that opted in and writes their credentials to credhub", func() {
+		rotations, err := manager.RotateServiceKeys(policy, credentials)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(rotations).Should(Equal([]service.KeyRotation{
+			{Org: "payments", Space: "dev", Instance: "test-db", Key: "debug", AgeDays: 90, Status: service.RotationOptedOut},
+			{Org: "payments", Space: "prod", Instance: "orders-db", Key: "reporting", AgeDays: 90, Status: service.RotationRotated,
+				CredHubName: "/cf-mgmt/service-keys/payments/prod/orders-db/reporting"},
+		}))
+		Expect(fakeClient.CreateServiceKeyCallCount()).Should(Equal(2))
+		Expect(fakeClient.CreateServiceKeyArgsForCall(0)).Should(Equal(cfclient.CreateServiceKeyRequest{Name: "reporting-rotating-1585656000", ServiceInstanceGuid: "orders-db-guid"}))
+		Expect(fakeClient.CreateServiceKeyArgsForCall(1)).Should(Equal(cfclient.CreateServiceKeyRequest{Name: "reporting", ServiceInstanceGuid: "orders-db-guid"}))
+		Expect(fakeClient.DeleteServiceKeyCallCount()).Should(Equal(2))
+		Expect(fakeClient.DeleteServiceKeyArgsForCall(0)).Should(Equal("reporting-guid"))
+		Expect(fakeClient.DeleteServiceKeyArgsForCall(1)).Should(Equal("reporting-rotating-1585656000-guid"))
+		Expect(credentials).Should(HaveKeyWithValue("/cf-mgmt/service-keys/payments/prod/orders-db/reporting", map[string]interface{}{"password": "reporting"}))
+	})
+
+	It("writes credentials under the credhub path of the policy", func() {
+		policy.CredHub.Path = "/foundation-1/keys/"
+		rotations, err := manager.RotateServiceKeys(policy, credentials)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(rotations[1].CredHubName).Should(Equal("/foundation-1/keys/payments/prod/orders-db/reporting"))
+	})
+
+	It("only lists the keys with peek", func() {
+		manager.Peek = true
+		rotations, err := manager.RotateServiceKeys(policy, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(rotations[1].Status).Should(Equal(service.RotationDryRun))
+		Expect(fakeClient.DeleteServiceKeyCallCount()).Should(Equal(0))
+		Expect(fakeClient.CreateServiceKeyCallCount()).Should(Equal(0))
+	})
+
+	It("lists a key that could not be recreated as failed and keeps it", func() {
+		fakeClient.CreateServiceKeyStub = nil
+		fakeClient.CreateServiceKeyReturns(cfclient.ServiceKey{}, errors.New("broker unavailable"))
+		rotations, err := manager.RotateServiceKeys(policy, credentials)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(rotations[1].Status).Should(Equal(service.RotationFailed))
+		Expect(rotations[1].Error).Should(ContainSubstring("broker unavailable"))
+		Expect(credentials).Should(BeEmpty())
+		Expect(fakeClient.DeleteServiceKeyCallCount()).Should(Equal(0))
+	})
+
+	It("keeps the key when the credentials of its replacement cannot be written to credhub", func() {
+		rotations, err := manager.RotateServiceKeys(policy, failingCredentialWriter{})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(rotations[1].Status).Should(Equal(service.RotationFailed))
+		Expect(rotations[1].Error).Should(ContainSubstring("credhub unavailable"))
+		Expect(fakeClient.DeleteServiceKeyCallCount()).Should(Equal(1))
+		Expect(fakeClient.DeleteServiceKeyArgsForCall(0)).Should(Equal("reporting-rotating-1585656000-guid"))
+	})
+})
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/space"
+)
+
+func NewManager(client CFClient, spaceMgr space.Manager, cfg config.Reader, peek bool) Manager {
+	return &DefaultManager{
+		Cfg:      cfg,
+		SpaceMgr: spaceMgr,
+		Client:   client,
+		Now:      time.Now,
+		Peek:     peek,
+	}
+}
+
+//DefaultManager -
+type DefaultManager struct {
+	Cfg      config.Reader
+	SpaceMgr space.Manager
+	Client   CFClient
+	// Now is the time ages are counted to
+	Now  func() time.Time
+	Peek bool
+}
+
+// Violation is a service instance of a managed space whose service or plan
+// is not one of the allowed-services of its org.
+type Violation struct {
+	Org      string `json:"org"`
+	Space    string `json:"space"`
+	Instance string `json:"instance"`
+	Service  string `json:"service"`
+	Plan     string `json:"plan"`
+	GUID     string `json:"guid"`
+}
+
+//ServiceViolations - lists the service instances of the spaces of the orgs with allowed-services, including
+//spaces not in the configuration, whose service or plan the org does not allow
+func (m *DefaultManager) ServiceViolations() ([]Violation, error) {
+	orgConfigs, err := m.Cfg.GetOrgConfigs()
+	if err != nil {
+		return nil, err
+	}
+	policies := make(map[string]config.OrgConfig)
+	for _, orgConfig := range orgConfigs {
+		if len(orgConfig.AllowedServices) > 0 {
+			policies[orgConfig.Org] = orgConfig
+		}
+	}
+	violations := []Violation{}
+	if len(policies) == 0 {
+		return violations, nil
+	}
+	spaces, err := m.SpaceMgr.ListManagedSpaces()
+	if err != nil {
+		return nil, err
+	}
+	serviceNames, planNames, err := m.marketplaceNames()
+	if err != nil {
+		return nil, err
+	}
+	spacesByGUID := make(map[string]space.ManagedSpace)
+	var orgGUIDs []string
+	for _, managed := range spaces {
+		if _, ok := policies[managed.Org]; !ok {
+			continue
+		}
+		if !containsGUID(orgGUIDs, managed.Space.OrganizationGuid) {
+			orgGUIDs = append(orgGUIDs, managed.Space.OrganizationGuid)
+		}
+		spacesByGUID[managed.Space.Guid] = managed
+	}
+	for _, orgGUID := range orgGUIDs {
+		instances, err := m.Client.ListServiceInstancesByQuery(url.Values{"q": []string{"organization_guid:" + orgGUID}})
+		if err != nil {
+			return nil, err
+		}
+		for _, instance := range instances {
+			managed, ok := spacesByGUID[instance.SpaceGuid]
+			if !ok {
+				continue
+			}
+			serviceName, planName := nameOrGUID(serviceNames, instance.ServiceGuid), nameOrGUID(planNames, instance.ServicePlanGuid)
+			policy := policies[managed.Org]
+			if policy.ServiceAllowed(serviceName, planName) {
+				continue
+			}
+			violations = append(violations, Violation{
+				Org:      managed.Org,
+				Space:    managed.Space.Name,
+				Instance: instance.Name,
+				Service:  serviceName,
+				Plan:     planName,
+				GUID:     instance.Guid,
+			})
+		}
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		if violations[i].Org != violations[j].Org {
+			return violations[i].Org < violations[j].Org
+		}
+		if violations[i].Space != violations[j].Space {
+			return violations[i].Space < violations[j].Space
+		}
+		return violations[i].Instance < violations[j].Instance
+	})
+	return violations, nil
+}
+
+// marketplaceNames are the names of the services and of the plans by guid
+func (m *DefaultManager) marketplaceNames() (map[string]string, map[string]string, error) {
+	services, err := m.Client.ListServices()
+	if err != nil {
+		return nil, nil, err
+	}
+	serviceNames := make(map[string]string)
+	for _, service := range services {
+		serviceNames[service.Guid] = service.Label
+	}
+	plans, err := m.Client.ListServicePlans()
+	if err != nil {
+		return nil, nil, err
+	}
+	planNames := make(map[string]string)
+	for _, plan := range plans {
+		planNames[plan.Guid] = plan.Name
+	}
+	return serviceNames, planNames, nil
+}
+
+func nameOrGUID(names map[string]string, guid string) string {
+	if name, ok := names[guid]; ok {
+		return name
+	}
+	return guid
+}
+
+func containsGUID(guids []string, guid string) bool {
+	for _, g := range guids {
+		if g == guid {
+			return true
+		}
+	}
+	return false
+}
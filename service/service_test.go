@@ -0,0 +1,89 @@
+package service_test
+
+import (
+	"errors"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/config"
+	configfakes "github.com/pivotalservices/cf-mgmt/config/fakes"
+	"github.com/pivotalservices/cf-mgmt/service"
+	servicefakes "github.com/pivotalservices/cf-mgmt/service/fakes"
+	"github.com/pivotalservices/cf-mgmt/space"
+	spacefakes "github.com/pivotalservices/cf-mgmt/space/fakes"
+)
+
+var _ = Describe("given ServiceManager", func() {
+	var (
+		fakeReader   *configfakes.FakeReader
+		fakeSpaceMgr *spacefakes.FakeManager
+		fakeClient   *servicefakes.FakeCFClient
+		manager      service.DefaultManager
+	)
+
+	BeforeEach(func() {
+		fakeReader = new(configfakes.FakeReader)
+		fakeSpaceMgr = new(spacefakes.FakeManager)
+		fakeClient = new(servicefakes.FakeCFClient)
+		manager = service.DefaultManager{
+			Cfg:      fakeReader,
+			SpaceMgr: fakeSpaceMgr,
+			Client:   fakeClient,
+		}
+		fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+			{Org: "payments", AllowedServices: []string{"p.mysql:db-small", "p.redis"}},
+			{Org: "sandbox"},
+		}, nil)
+		fakeSpaceMgr.ListManagedSpacesReturns([]space.ManagedSpace{
+			{Org: "payments", Space: cfclient.Space{Name: "prod", Guid: "prod-guid", OrganizationGuid: "payments-guid"}},
+			{Org: "payments", Space: cfclient.Space{Name: "dev", Guid: "dev-guid", OrganizationGuid: "payments-guid"}},
+			{Org: "sandbox", Space: cfclient.Space{Name: "play", Guid: "play-guid", OrganizationGuid: "sandbox-guid"}},
+		}, nil)
+		fakeClient.ListServicesReturns([]cfclient.Service{
+			{Label: "p.mysql", Guid: "mysql-guid"},
+			{Label: "p.redis", Guid: "redis-guid"},
+			{Label: "p.rabbitmq", Guid: "rabbitmq-guid"},
+		}, nil)
+		fakeClient.ListServicePlansReturns([]cfclient.ServicePlan{
+			{Name: "db-small", Guid: "db-small-guid", ServiceGuid: "mysql-guid"},
+			{Name: "db-large", Guid: "db-large-guid", ServiceGuid: "mysql-guid"},
+			{Name: "cache", Guid: "cache-guid", ServiceGuid: "redis-guid"},
+			{Name: "single", Guid: "single-guid", ServiceGuid: "rabbitmq-guid"},
+		}, nil)
+		fakeClient.ListServiceInstancesByQueryReturns([]cfclient.ServiceInstance{
+			{Name: "orders-db", Guid: "orders-db-guid", SpaceGuid: "prod-guid", ServiceGuid: "mysql-guid", ServicePlanGuid: "db-small-guid"},
+			{Name: "reports-db", Guid: "reports-db-guid", SpaceGuid: "prod-guid", ServiceGuid: "mysql-guid", ServicePlanGuid: "db-large-guid"},
+			{Name: "sessions", Guid: "sessions-guid", SpaceGuid: "dev-guid", ServiceGuid: "redis-guid", ServicePlanGuid: "cache-guid"},
+			{Name: "events", Guid: "events-guid", SpaceGuid: "dev-guid", ServiceGuid: "rabbitmq-guid", ServicePlanGuid: "single-guid"},
+		}, nil)
+	})
+
+	Context("ServiceViolations()", func() {
+		It("lists the instances of services and plans the org does not allow", func() {
+			violations, err := manager.ServiceViolations()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(violations).Should(Equal([]service.Violation{
+				{Org: "payments", Space: "dev", Instance: "events", Service: "p.rabbitmq", Plan: "single", GUID: "events-guid"},
+				{Org: "payments", Space: "prod", Instance: "reports-db", Service: "p.mysql", Plan: "db-large", GUID: "reports-db-guid"},
+			}))
+			Expect(fakeClient.ListServiceInstancesByQueryCallCount()).Should(Equal(1))
+			Expect(fakeClient.ListServiceInstancesByQueryArgsForCall(0).Get("q")).Should(Equal("organization_guid:payments-guid"))
+		})
+
+		It("does not list anything without allowed-services", func() {
+			fakeReader.GetOrgConfigsReturns([]config.OrgConfig{{Org: "payments"}}, nil)
+			violations, err := manager.ServiceViolations()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(violations).Should(BeEmpty())
+			Expect(fakeSpaceMgr.ListManagedSpacesCallCount()).Should(Equal(0))
+			Expect(fakeClient.ListServicesCallCount()).Should(Equal(0))
+		})
+
+		It("returns the error listing the service instances", func() {
+			fakeClient.ListServiceInstancesByQueryReturns(nil, errors.New("api down"))
+			_, err := manager.ServiceViolations()
+			Expect(err).Should(MatchError("api down"))
+		})
+	})
+})
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"net/url"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pivotalservices/cf-mgmt/config"
+)
+
+//Manager -
+type Manager interface {
+	ServiceViolations() ([]Violation, error)
+	ServiceInventory() ([]InventoryItem, error)
+	RotateServiceKeys(policy config.ServiceKeyRotation, credentials CredentialWriter) ([]KeyRotation, error)
+}
+
+//CredentialWriter - where the credentials of rotated service keys are written, such as credhub
+type CredentialWriter interface {
+	SetJSON(name string, value interface{}) error
+}
+
+type CFClient interface {
+	ListServiceInstancesByQuery(query url.Values) ([]cfclient.ServiceInstance, error)
+	ListServices() ([]cfclient.Service, error)
+	ListServicePlans() ([]cfclient.ServicePlan, error)
+	ListServiceBindingsByQuery(query url.Values) ([]cfclient.ServiceBinding, error)
+	ListServiceKeysByQuery(query url.Values) ([]cfclient.ServiceKey, error)
+	CreateServiceKey(req cfclient.CreateServiceKeyRequest) (cfclient.ServiceKey, error)
+	DeleteServiceKey(guid string) error
+	ListAppsByQuery(query url.Values) ([]cfclient.App, error)
+	ListEventsByQuery(query url.Values) ([]cfclient.Event, error)
+}
@@ -0,0 +1,93 @@
+package simulator
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+)
+
+func (f *Foundation) app(guid string) (*cfclient.App, error) {
+	for i := range f.state.Apps {
+		if f.state.Apps[i].Guid == guid {
+			return &f.state.Apps[i], nil
+		}
+	}
+	return nil, notFound("app", guid)
+}
+
+//ListAppsByQuery - lists the apps, filtered by an organization_guid or space_guid query
+func (f *Foundation) ListAppsByQuery(query url.Values) ([]cfclient.App, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	orgGUID, spaceGUID := "", ""
+	for _, q := range query["q"] {
+		if strings.HasPrefix(q, "organization_guid:") {
+			orgGUID = strings.TrimPrefix(q, "organization_guid:")
+		}
+		if strings.HasPrefix(q, "space_guid:") {
+			spaceGUID = strings.TrimPrefix(q, "space_guid:")
+		}
+	}
+	apps := []cfclient.App{}
+	for _, app := range f.state.Apps {
+		if spaceGUID != "" && app.SpaceGuid != spaceGUID {
+			continue
+		}
+		if orgGUID != "" {
+			space, err := f.space(app.SpaceGuid)
+			if err != nil || space.OrganizationGuid != orgGUID {
+				continue
+			}
+		}
+		apps = append(apps, app)
+	}
+	return apps, nil
+}
+
+//ListStacks - lists the stacks of the foundation
+func (f *Foundation) ListStacks() ([]cfclient.Stack, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]cfclient.Stack{}, f.state.Stacks...), nil
+}
+
+//ListTasksByQuery - lists the tasks of the spaces of a space_guids query, latest first
+func (f *Foundation) ListTasksByQuery(query url.Values) ([]cfclient.Task, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	tasks := []cfclient.Task{}
+	for _, guids := range query["space_guids"] {
+		for _, guid := range strings.Split(guids, ",") {
+			tasks = append(tasks, f.state.Tasks[guid]...)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].CreatedAt.After(tasks[j].CreatedAt)
+	})
+	return tasks, nil
+}
+
+//UpdateApp - updates the state and stack of an app
+func (f *Foundation) UpdateApp(guid string, aur cfclient.AppUpdateResource) (cfclient.UpdateResponse, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	app, err := f.app(guid)
+	if err != nil {
+		return cfclient.UpdateResponse{}, err
+	}
+	if aur.State != "" {
+		app.State = string(aur.State)
+	}
+	if aur.StackGuid != "" {
+		app.StackGuid = aur.StackGuid
+	}
+	response := cfclient.UpdateResponse{Metadata: cfclient.Meta{Guid: app.Guid}}
+	response.Entity.Name = app.Name
+	response.Entity.SpaceGuid = app.SpaceGuid
+	response.Entity.StackGuid = app.StackGuid
+	response.Entity.State = app.State
+	response.Entity.DockerImage = app.DockerImage
+	return response, nil
+}
@@ -0,0 +1,104 @@
+package simulator
+
+import (
+	"fmt"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+)
+
+func (f *Foundation) domain(guid string) (*cfclient.Domain, error) {
+	for i := range f.state.Domains {
+		if f.state.Domains[i].Guid == guid {
+			return &f.state.Domains[i], nil
+		}
+	}
+	return nil, notFound("private domain", guid)
+}
+
+//ListDomains - lists all private domains
+func (f *Foundation) ListDomains() ([]cfclient.Domain, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]cfclient.Domain{}, f.state.Domains...), nil
+}
+
+//CreateDomain -
+func (f *Foundation) CreateDomain(name, orgGuid string) (*cfclient.Domain, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, err := f.org(orgGuid); err != nil {
+		return nil, err
+	}
+	for _, domain := range f.state.Domains {
+		if domain.Name == name {
+			return nil, fmt.Errorf("domain name [%s] is taken", name)
+		}
+	}
+	domain := cfclient.Domain{
+		Guid:                   f.newGUID("domain"),
+		Name:                   name,
+		OwningOrganizationGuid: orgGuid,
+	}
+	f.state.Domains = append(f.state.Domains, domain)
+	return &domain, nil
+}
+
+//DeleteDomain -
+func (f *Foundation) DeleteDomain(guid string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, err := f.domain(guid); err != nil {
+		return err
+	}
+	domains := []cfclient.Domain{}
+	for _, domain := range f.state.Domains {
+		if domain.Guid != guid {
+			domains = append(domains, domain)
+		}
+	}
+	f.state.Domains = domains
+	for orgGUID, domainGUIDs := range f.state.SharedDomains {
+		f.state.SharedDomains[orgGUID] = remove(domainGUIDs, guid)
+	}
+	return nil
+}
+
+//ListOrgPrivateDomains - lists the private domains owned by or shared with the org
+func (f *Foundation) ListOrgPrivateDomains(orgGUID string) ([]cfclient.Domain, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	domains := []cfclient.Domain{}
+	for _, domain := range f.state.Domains {
+		if domain.OwningOrganizationGuid == orgGUID || contains(f.state.SharedDomains[orgGUID], domain.Guid) {
+			domains = append(domains, domain)
+		}
+	}
+	return domains, nil
+}
+
+//ShareOrgPrivateDomain -
+func (f *Foundation) ShareOrgPrivateDomain(orgGUID, privateDomainGUID string) (*cfclient.Domain, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, err := f.org(orgGUID); err != nil {
+		return nil, err
+	}
+	domain, err := f.domain(privateDomainGUID)
+	if err != nil {
+		return nil, err
+	}
+	f.state.SharedDomains[orgGUID] = appendUnique(f.state.SharedDomains[orgGUID], privateDomainGUID)
+	result := *domain
+	return &result, nil
+}
+
+//UnshareOrgPrivateDomain -
+func (f *Foundation) UnshareOrgPrivateDomain(orgGUID, privateDomainGUID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if !contains(f.state.SharedDomains[orgGUID], privateDomainGUID) {
+		return fmt.Errorf("private domain [%s] is not shared with org [%s]", privateDomainGUID, orgGUID)
+	}
+	f.state.SharedDomains[orgGUID] = remove(f.state.SharedDomains[orgGUID], privateDomainGUID)
+	return nil
+}
@@ -0,0 +1,35 @@
+package simulator
+
+import (
+	"net/url"
+	"strings"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+)
+
+//ListEventsByQuery - lists the audit events, filtered by organization_guid and timestamp>= queries
+func (f *Foundation) ListEventsByQuery(query url.Values) ([]cfclient.Event, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	orgGUID, since := "", ""
+	for _, q := range query["q"] {
+		if strings.HasPrefix(q, "organization_guid:") {
+			orgGUID = strings.TrimPrefix(q, "organization_guid:")
+		}
+		if strings.HasPrefix(q, "timestamp>=") {
+			since = strings.TrimPrefix(q, "timestamp>=")
+		}
+	}
+	events := []cfclient.Event{}
+	for _, event := range f.state.Events {
+		if orgGUID != "" && event.OrganizationGUID != orgGUID {
+			continue
+		}
+		// timestamps are RFC3339 in UTC, which sort as strings
+		if since != "" && event.CreatedAt < since {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
@@ -0,0 +1,203 @@
+package simulator
+
+import (
+	"net/url"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/uaa"
+	"github.com/pkg/errors"
+	"github.com/xchapter7x/lo"
+)
+
+// ExportClient is the cloud controller calls made to export a snapshot of a
+// foundation, satisfied by *cfclient.Client.
+type ExportClient interface {
+	ListOrgs() ([]cfclient.Org, error)
+	ListSpacesByQuery(query url.Values) ([]cfclient.Space, error)
+	ListOrgQuotas() ([]cfclient.OrgQuota, error)
+	ListOrgSpaceQuotas(orgGUID string) ([]cfclient.SpaceQuota, error)
+	ListDomains() ([]cfclient.Domain, error)
+	ListSharedDomains() ([]cfclient.SharedDomain, error)
+	ListRoutesByQuery(query url.Values) ([]cfclient.Route, error)
+	ListAppsByQuery(query url.Values) ([]cfclient.App, error)
+	ListStacks() ([]cfclient.Stack, error)
+	MarketplaceClient
+	ListServiceInstancesByQuery(query url.Values) ([]cfclient.ServiceInstance, error)
+	ListOrgPrivateDomains(orgGUID string) ([]cfclient.Domain, error)
+	ListSecGroups() ([]cfclient.SecGroup, error)
+	ListIsolationSegments() ([]cfclient.IsolationSegment, error)
+	ListIsolationSegmentsByQuery(query url.Values) ([]cfclient.IsolationSegment, error)
+	ListOrgUsers(orgGUID string) ([]cfclient.User, error)
+	ListOrgAuditors(orgGUID string) ([]cfclient.User, error)
+	ListOrgManagers(orgGUID string) ([]cfclient.User, error)
+	ListOrgBillingManagers(orgGUID string) ([]cfclient.User, error)
+	ListSpaceAuditors(spaceGUID string) ([]cfclient.User, error)
+	ListSpaceManagers(spaceGUID string) ([]cfclient.User, error)
+	ListSpaceDevelopers(spaceGUID string) ([]cfclient.User, error)
+}
+
+type roleLister func(guid string) ([]cfclient.User, error)
+
+//Export - reads the state of a foundation into a snapshot, which commands can later run against
+//with --simulate or plan --from-snapshot. The uaa groups of the role groups and the routes on
+//internal domains are exported, other uaa groups, routes, org labels, tasks and audit events are not.
+func Export(client ExportClient, uaaMgr uaa.Manager, roleGroups []config.RoleGroup) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		SharedDomains: make(map[string][]string),
+		Entitlements:  make(map[string][]string),
+		OrgRoles:      make(map[string]Roles),
+		SpaceRoles:    make(map[string]Roles),
+	}
+	var err error
+	if snapshot.Orgs, err = client.ListOrgs(); err != nil {
+		return nil, errors.Wrap(err, "unable to list orgs")
+	}
+	if snapshot.Spaces, err = client.ListSpacesByQuery(url.Values{}); err != nil {
+		return nil, errors.Wrap(err, "unable to list spaces")
+	}
+	if snapshot.OrgQuotas, err = client.ListOrgQuotas(); err != nil {
+		return nil, errors.Wrap(err, "unable to list org quotas")
+	}
+	if snapshot.Domains, err = client.ListDomains(); err != nil {
+		return nil, errors.Wrap(err, "unable to list domains")
+	}
+	if snapshot.PlatformDomains, err = client.ListSharedDomains(); err != nil {
+		return nil, errors.Wrap(err, "unable to list shared domains")
+	}
+	for _, domain := range snapshot.PlatformDomains {
+		if !domain.Internal {
+			continue
+		}
+		routes, err := client.ListRoutesByQuery(url.Values{"q": []string{"domain_guid:" + domain.Guid}})
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to list routes of domain %s", domain.Name)
+		}
+		snapshot.Routes = append(snapshot.Routes, routes...)
+	}
+	if snapshot.Apps, err = client.ListAppsByQuery(url.Values{}); err != nil {
+		return nil, errors.Wrap(err, "unable to list apps")
+	}
+	if snapshot.Stacks, err = client.ListStacks(); err != nil {
+		return nil, errors.Wrap(err, "unable to list stacks")
+	}
+	if err = exportMarketplace(client, snapshot); err != nil {
+		return nil, err
+	}
+	if snapshot.ServiceInstances, err = client.ListServiceInstancesByQuery(url.Values{}); err != nil {
+		return nil, errors.Wrap(err, "unable to list service instances")
+	}
+	if snapshot.SecurityGroups, err = client.ListSecGroups(); err != nil {
+		return nil, errors.Wrap(err, "unable to list security groups")
+	}
+	if snapshot.IsolationSegments, err = client.ListIsolationSegments(); err != nil {
+		return nil, errors.Wrap(err, "unable to list isolation segments")
+	}
+
+	users := make(map[string]cfclient.User)
+	exportRoles := func(roles map[string]Roles, guid string, listers map[string]roleLister) error {
+		roles[guid] = make(Roles)
+		for role, list := range listers {
+			roleUsers, err := list(guid)
+			if err != nil {
+				return errors.Wrapf(err, "unable to list %s of %s", role, guid)
+			}
+			for _, user := range roleUsers {
+				users[user.Guid] = user
+				roles[guid][role] = append(roles[guid][role], user.Guid)
+			}
+		}
+		return nil
+	}
+	orgRoles := map[string]roleLister{
+		RoleUsers:           client.ListOrgUsers,
+		RoleManagers:        client.ListOrgManagers,
+		RoleBillingManagers: client.ListOrgBillingManagers,
+		RoleAuditors:        client.ListOrgAuditors,
+	}
+	spaceRoles := map[string]roleLister{
+		RoleManagers:   client.ListSpaceManagers,
+		RoleDevelopers: client.ListSpaceDevelopers,
+		RoleAuditors:   client.ListSpaceAuditors,
+	}
+	for _, org := range snapshot.Orgs {
+		if err := exportRoles(snapshot.OrgRoles, org.Guid, orgRoles); err != nil {
+			return nil, err
+		}
+		spaceQuotas, err := client.ListOrgSpaceQuotas(org.Guid)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to list space quotas of org %s", org.Name)
+		}
+		snapshot.SpaceQuotas = append(snapshot.SpaceQuotas, spaceQuotas...)
+		domains, err := client.ListOrgPrivateDomains(org.Guid)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to list private domains of org %s", org.Name)
+		}
+		for _, domain := range domains {
+			if domain.OwningOrganizationGuid != org.Guid {
+				snapshot.SharedDomains[org.Guid] = append(snapshot.SharedDomains[org.Guid], domain.Guid)
+			}
+		}
+		segments, err := client.ListIsolationSegmentsByQuery(url.Values{"organization_guids": []string{org.Guid}})
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to list isolation segments of org %s", org.Name)
+		}
+		for _, segment := range segments {
+			snapshot.Entitlements[segment.GUID] = append(snapshot.Entitlements[segment.GUID], org.Guid)
+		}
+	}
+	for _, space := range snapshot.Spaces {
+		if err := exportRoles(snapshot.SpaceRoles, space.Guid, spaceRoles); err != nil {
+			return nil, err
+		}
+	}
+	for _, user := range users {
+		snapshot.Users = append(snapshot.Users, user)
+	}
+
+	uaaUsers, err := uaaMgr.ListAllUsers()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list uaa users")
+	}
+	for _, user := range uaaUsers {
+		snapshot.UAAUsers = append(snapshot.UAAUsers, *user)
+	}
+	for _, name := range roleGroupNames(snapshot, roleGroups) {
+		group, err := uaaMgr.GetGroup(name)
+		if err != nil {
+			return nil, err
+		}
+		if group != nil {
+			snapshot.UAAGroups = append(snapshot.UAAGroups, *group)
+		}
+	}
+	if snapshot.UAAIdentityProviders, err = uaaMgr.ListIdentityProviders(); err != nil {
+		lo.G.Warningf("identity providers are not exported: %s", err)
+	}
+	if snapshot.UAATokenPolicy, err = uaaMgr.GetTokenPolicy(); err != nil {
+		lo.G.Warningf("token policy is not exported: %s", err)
+	}
+	return snapshot, nil
+}
+
+// roleGroupNames are the names of the groups of the role groups for every org
+// and space of the snapshot
+func roleGroupNames(snapshot *Snapshot, roleGroups []config.RoleGroup) []string {
+	orgNames := make(map[string]string)
+	for _, org := range snapshot.Orgs {
+		orgNames[org.Guid] = org.Name
+	}
+	var names []string
+	for _, roleGroup := range roleGroups {
+		if !roleGroup.IsSpaceRole() {
+			for _, org := range snapshot.Orgs {
+				names = append(names, roleGroup.GroupName(org.Name, ""))
+			}
+			continue
+		}
+		for _, space := range snapshot.Spaces {
+			names = append(names, roleGroup.GroupName(orgNames[space.OrganizationGuid], space.Name))
+		}
+	}
+	return names
+}
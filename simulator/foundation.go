@@ -0,0 +1,388 @@
+// Package simulator provides an in-memory foundation that implements every
+// cloud controller and uaa call made by cf-mgmt, so commands and integration
+// tests can run against a snapshot instead of a live foundation.
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pivotalservices/cf-mgmt/organization"
+)
+
+//Foundation - in-memory cloud controller and uaa seeded from a Snapshot
+type Foundation struct {
+	mutex  sync.Mutex
+	state  *Snapshot
+	lastID int
+}
+
+//NewFoundation - creates a foundation seeded with a copy of snapshot
+func NewFoundation(snapshot *Snapshot) (*Foundation, error) {
+	if snapshot == nil {
+		snapshot = &Snapshot{}
+	}
+	state, err := copySnapshot(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if state.SharedDomains == nil {
+		state.SharedDomains = make(map[string][]string)
+	}
+	if state.Entitlements == nil {
+		state.Entitlements = make(map[string][]string)
+	}
+	if state.OrgRoles == nil {
+		state.OrgRoles = make(map[string]Roles)
+	}
+	if state.SpaceRoles == nil {
+		state.SpaceRoles = make(map[string]Roles)
+	}
+	return &Foundation{state: state}, nil
+}
+
+//Snapshot - returns a copy of the current state of the foundation
+func (f *Foundation) Snapshot() (*Snapshot, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return copySnapshot(f.state)
+}
+
+func copySnapshot(snapshot *Snapshot) (*Snapshot, error) {
+	bytes, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	result := &Snapshot{}
+	if err = json.Unmarshal(bytes, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (f *Foundation) newGUID(kind string) string {
+	f.lastID++
+	return fmt.Sprintf("simulated-%s-%d", kind, f.lastID)
+}
+
+func notFound(kind, guid string) error {
+	return fmt.Errorf("%s [%s] not found", kind, guid)
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, item := range list {
+		if item == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
+
+func remove(list []string, value string) []string {
+	result := []string{}
+	for _, item := range list {
+		if item != value {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+//ListOrgs -
+func (f *Foundation) ListOrgs() ([]cfclient.Org, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]cfclient.Org{}, f.state.Orgs...), nil
+}
+
+//ListOrgNamesByLabelSelector - lists the names of the orgs whose labels match selector
+func (f *Foundation) ListOrgNamesByLabelSelector(selector organization.LabelSelector) ([]string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	var names []string
+	for _, org := range f.state.Orgs {
+		if selector.Matches(f.state.OrgLabels[org.Guid]) {
+			names = append(names, org.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+//GetAnnotations - the annotations of an org or space
+func (f *Foundation) GetAnnotations(resource, guid string) (map[string]string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	annotations := make(map[string]string)
+	for key, value := range f.state.Annotations[guid] {
+		annotations[key] = value
+	}
+	return annotations, nil
+}
+
+//SetAnnotations - adds annotations to an org or space, replacing those with the same keys
+func (f *Foundation) SetAnnotations(resource, guid string, annotations map[string]string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.state.Annotations == nil {
+		f.state.Annotations = make(map[string]map[string]string)
+	}
+	if f.state.Annotations[guid] == nil {
+		f.state.Annotations[guid] = make(map[string]string)
+	}
+	for key, value := range annotations {
+		f.state.Annotations[guid][key] = value
+	}
+	return nil
+}
+
+func (f *Foundation) org(guid string) (*cfclient.Org, error) {
+	for i := range f.state.Orgs {
+		if f.state.Orgs[i].Guid == guid {
+			return &f.state.Orgs[i], nil
+		}
+	}
+	return nil, notFound("org", guid)
+}
+
+//GetOrgByGuid -
+func (f *Foundation) GetOrgByGuid(guid string) (cfclient.Org, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	org, err := f.org(guid)
+	if err != nil {
+		return cfclient.Org{}, err
+	}
+	return *org, nil
+}
+
+//CreateOrg -
+func (f *Foundation) CreateOrg(req cfclient.OrgRequest) (cfclient.Org, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for _, org := range f.state.Orgs {
+		if org.Name == req.Name {
+			return cfclient.Org{}, fmt.Errorf("org name [%s] is taken", req.Name)
+		}
+	}
+	org := cfclient.Org{
+		Guid:                        f.newGUID("org"),
+		Name:                        req.Name,
+		Status:                      "active",
+		QuotaDefinitionGuid:         req.QuotaDefinitionGuid,
+		DefaultIsolationSegmentGuid: req.DefaultIsolationSegmentGuid,
+	}
+	f.state.Orgs = append(f.state.Orgs, org)
+	return org, nil
+}
+
+//UpdateOrg - only fields set on the request are changed
+func (f *Foundation) UpdateOrg(orgGUID string, req cfclient.OrgRequest) (cfclient.Org, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	org, err := f.org(orgGUID)
+	if err != nil {
+		return cfclient.Org{}, err
+	}
+	if req.Name != "" {
+		org.Name = req.Name
+	}
+	if req.Status != "" {
+		org.Status = req.Status
+	}
+	if req.QuotaDefinitionGuid != "" {
+		org.QuotaDefinitionGuid = req.QuotaDefinitionGuid
+	}
+	if req.DefaultIsolationSegmentGuid != "" {
+		org.DefaultIsolationSegmentGuid = req.DefaultIsolationSegmentGuid
+	}
+	return *org, nil
+}
+
+//DeleteOrg - recursive also deletes the org spaces, otherwise the org must be empty
+func (f *Foundation) DeleteOrg(guid string, recursive, async bool) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, err := f.org(guid); err != nil {
+		return err
+	}
+	spaceGUIDs := []string{}
+	for _, space := range f.state.Spaces {
+		if space.OrganizationGuid == guid {
+			spaceGUIDs = append(spaceGUIDs, space.Guid)
+		}
+	}
+	if len(spaceGUIDs) > 0 && !recursive {
+		return fmt.Errorf("org [%s] has spaces and recursive was not set", guid)
+	}
+	for _, spaceGUID := range spaceGUIDs {
+		f.deleteSpace(spaceGUID)
+	}
+	orgs := []cfclient.Org{}
+	for _, org := range f.state.Orgs {
+		if org.Guid != guid {
+			orgs = append(orgs, org)
+		}
+	}
+	f.state.Orgs = orgs
+	delete(f.state.OrgRoles, guid)
+	delete(f.state.SharedDomains, guid)
+	for segmentGUID, orgGUIDs := range f.state.Entitlements {
+		f.state.Entitlements[segmentGUID] = remove(orgGUIDs, guid)
+	}
+	return nil
+}
+
+func (f *Foundation) space(guid string) (*cfclient.Space, error) {
+	for i := range f.state.Spaces {
+		if f.state.Spaces[i].Guid == guid {
+			return &f.state.Spaces[i], nil
+		}
+	}
+	return nil, notFound("space", guid)
+}
+
+//GetSpaceByGuid -
+func (f *Foundation) GetSpaceByGuid(spaceGUID string) (cfclient.Space, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	space, err := f.space(spaceGUID)
+	if err != nil {
+		return cfclient.Space{}, err
+	}
+	return *space, nil
+}
+
+//ListSpacesByQuery - supports the organization_guid filter
+func (f *Foundation) ListSpacesByQuery(query url.Values) ([]cfclient.Space, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	orgGUID := ""
+	for _, q := range query["q"] {
+		if strings.HasPrefix(q, "organization_guid:") {
+			orgGUID = strings.TrimPrefix(q, "organization_guid:")
+		}
+	}
+	spaces := []cfclient.Space{}
+	for _, space := range f.state.Spaces {
+		if orgGUID == "" || space.OrganizationGuid == orgGUID {
+			spaces = append(spaces, space)
+		}
+	}
+	return spaces, nil
+}
+
+//CreateSpace -
+func (f *Foundation) CreateSpace(req cfclient.SpaceRequest) (cfclient.Space, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	org, err := f.org(req.OrganizationGuid)
+	if err != nil {
+		return cfclient.Space{}, err
+	}
+	for _, space := range f.state.Spaces {
+		if space.OrganizationGuid == req.OrganizationGuid && space.Name == req.Name {
+			return cfclient.Space{}, fmt.Errorf("space name [%s] is taken in org [%s]", req.Name, org.Name)
+		}
+	}
+	space := cfclient.Space{
+		Guid:                 f.newGUID("space"),
+		Name:                 req.Name,
+		OrganizationGuid:     req.OrganizationGuid,
+		QuotaDefinitionGuid:  req.SpaceQuotaDefGuid,
+		IsolationSegmentGuid: req.IsolationSegmentGuid,
+		AllowSSH:             req.AllowSSH,
+	}
+	f.state.Spaces = append(f.state.Spaces, space)
+	roles := Roles{}
+	roles[RoleDevelopers] = append([]string{}, req.DeveloperGuid...)
+	roles[RoleManagers] = append([]string{}, req.ManagerGuid...)
+	roles[RoleAuditors] = append([]string{}, req.AuditorGuid...)
+	f.state.SpaceRoles[space.Guid] = roles
+	for _, secGUID := range req.SecurityGroupGuids {
+		if err := f.bindSecGroup(secGUID, space.Guid, false); err != nil {
+			return cfclient.Space{}, err
+		}
+	}
+	return space, nil
+}
+
+//UpdateSpace - allow ssh is always set, other fields only when set on the request
+func (f *Foundation) UpdateSpace(spaceGUID string, req cfclient.SpaceRequest) (cfclient.Space, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	space, err := f.space(spaceGUID)
+	if err != nil {
+		return cfclient.Space{}, err
+	}
+	if req.Name != "" {
+		space.Name = req.Name
+	}
+	if req.OrganizationGuid != "" {
+		space.OrganizationGuid = req.OrganizationGuid
+	}
+	if req.SpaceQuotaDefGuid != "" {
+		space.QuotaDefinitionGuid = req.SpaceQuotaDefGuid
+	}
+	if req.IsolationSegmentGuid != "" {
+		space.IsolationSegmentGuid = req.IsolationSegmentGuid
+	}
+	space.AllowSSH = req.AllowSSH
+	return *space, nil
+}
+
+//DeleteSpace -
+func (f *Foundation) DeleteSpace(guid string, recursive, async bool) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, err := f.space(guid); err != nil {
+		return err
+	}
+	f.deleteSpace(guid)
+	return nil
+}
+
+func (f *Foundation) deleteSpace(guid string) {
+	spaces := []cfclient.Space{}
+	for _, space := range f.state.Spaces {
+		if space.Guid != guid {
+			spaces = append(spaces, space)
+		}
+	}
+	f.state.Spaces = spaces
+	delete(f.state.SpaceRoles, guid)
+	routes := []cfclient.Route{}
+	for _, route := range f.state.Routes {
+		if route.SpaceGuid != guid {
+			routes = append(routes, route)
+		}
+	}
+	f.state.Routes = routes
+	apps := []cfclient.App{}
+	for _, app := range f.state.Apps {
+		if app.SpaceGuid != guid {
+			apps = append(apps, app)
+		}
+	}
+	f.state.Apps = apps
+	delete(f.state.Tasks, guid)
+	for i := range f.state.SecurityGroups {
+		sg := &f.state.SecurityGroups[i]
+		sg.SpacesData = removeSpaceResource(sg.SpacesData, guid)
+		sg.StagingSpacesData = removeSpaceResource(sg.StagingSpacesData, guid)
+	}
+}
@@ -0,0 +1,242 @@
+package simulator_test
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/cfmgmt"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/simulator"
+)
+
+var _ cfmgmt.CFClient = &simulator.Foundation{}
+
+func snapshotOf(foundation *simulator.Foundation) *simulator.Snapshot {
+	snapshot, err := foundation.Snapshot()
+	Expect(err).ShouldNot(HaveOccurred())
+	return snapshot
+}
+
+var _ = Describe("given simulated foundation", func() {
+	var (
+		snapshot   *simulator.Snapshot
+		foundation *simulator.Foundation
+	)
+
+	BeforeEach(func() {
+		var err error
+		snapshot, err = simulator.LoadSnapshot("./fixtures/snapshot.json")
+		Expect(err).ShouldNot(HaveOccurred())
+		foundation, err = simulator.NewFoundation(snapshot)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("LoadSnapshot", func() {
+		It("reads entities and relationships", func() {
+			Expect(snapshot.Orgs).Should(HaveLen(1))
+			Expect(snapshot.SecurityGroups[0].SpacesData[0].Meta.Guid).Should(Equal("space-guid"))
+			Expect(snapshot.OrgRoles["org-guid"][simulator.RoleManagers]).Should(ConsistOf("user-1-guid"))
+			Expect(snapshot.UAAUsers[1].Username).Should(Equal("user-2"))
+		})
+
+		It("errors when the file does not exist", func() {
+			_, err := simulator.LoadSnapshot("./fixtures/missing.json")
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("orgs and spaces", func() {
+		It("does not modify the seed snapshot", func() {
+			_, err := foundation.CreateOrg(cfclient.OrgRequest{Name: "new-org"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(snapshot.Orgs).Should(HaveLen(1))
+			Expect(snapshotOf(foundation).Orgs).Should(HaveLen(2))
+		})
+
+		It("rejects duplicate org names", func() {
+			_, err := foundation.CreateOrg(cfclient.OrgRequest{Name: "test"})
+			Expect(err).Should(MatchError("org name [test] is taken"))
+		})
+
+		It("filters spaces by org", func() {
+			org, err := foundation.CreateOrg(cfclient.OrgRequest{Name: "new-org"})
+			Expect(err).ShouldNot(HaveOccurred())
+			_, err = foundation.CreateSpace(cfclient.SpaceRequest{Name: "dev", OrganizationGuid: org.Guid})
+			Expect(err).ShouldNot(HaveOccurred())
+			spaces, err := foundation.ListSpacesByQuery(url.Values{"q": []string{"organization_guid:" + org.Guid}})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(spaces).Should(HaveLen(1))
+			Expect(spaces[0].Name).Should(Equal("dev"))
+		})
+
+		It("deletes spaces and their bindings with the org", func() {
+			err := foundation.DeleteOrg("org-guid", false, false)
+			Expect(err).Should(HaveOccurred())
+			err = foundation.DeleteOrg("org-guid", true, false)
+			Expect(err).ShouldNot(HaveOccurred())
+			state := snapshotOf(foundation)
+			Expect(state.Orgs).Should(BeEmpty())
+			Expect(state.Spaces).Should(BeEmpty())
+			Expect(state.SecurityGroups[0].SpacesData).Should(BeEmpty())
+		})
+	})
+
+	Context("users", func() {
+		It("creates the cloud controller user from the uaa user", func() {
+			_, err := foundation.AssociateOrgAuditorByUsername("org-guid", "user-2")
+			Expect(err).ShouldNot(HaveOccurred())
+			auditors, err := foundation.ListOrgAuditors("org-guid")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(auditors).Should(ConsistOf(cfclient.User{Guid: "user-2-guid", Username: "user-2", Active: true}))
+		})
+
+		It("errors for users unknown to uaa", func() {
+			_, err := foundation.AssociateOrgAuditorByUsername("org-guid", "unknown")
+			Expect(err).Should(MatchError("user [unknown] not found"))
+		})
+
+		It("removes roles by guid", func() {
+			err := foundation.RemoveOrgManager("org-guid", "user-1-guid")
+			Expect(err).ShouldNot(HaveOccurred())
+			managers, err := foundation.ListOrgManagers("org-guid")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(managers).Should(BeEmpty())
+		})
+
+		It("lists uaa users through the uaa manager", func() {
+			users, err := foundation.UAAManager(false).ListUsers()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(users["user-1"].ID).Should(Equal("user-1-guid"))
+			Expect(users["user-2"].ID).Should(Equal("user-2-guid"))
+		})
+
+		It("looks up uaa users by name through the uaa manager", func() {
+			users, err := foundation.UAAManager(false).ListUsersByName([]string{"USER-2", "missing"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(users).Should(HaveLen(1))
+			for key, user := range users {
+				Expect(key.Name).Should(Equal("user-2"))
+				Expect(user.ID).Should(Equal("user-2-guid"))
+			}
+		})
+
+		It("deletes uaa users with their roles", func() {
+			users, err := foundation.UAAManager(false).ListUsers()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(foundation.UAAManager(false).DeleteUser(*users["user-1"])).ShouldNot(HaveOccurred())
+			managers, err := foundation.ListOrgManagers("org-guid")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(managers).Should(BeEmpty())
+			Expect(snapshotOf(foundation).UAAUsers).Should(HaveLen(1))
+		})
+	})
+
+	Context("isolation segments", func() {
+		It("requires an entitlement before assigning a space", func() {
+			segment, err := foundation.CreateIsolationSegment("dedicated")
+			Expect(err).ShouldNot(HaveOccurred())
+			err = foundation.IsolationSegmentForSpace("space-guid", segment.GUID)
+			Expect(err).Should(HaveOccurred())
+			Expect(foundation.AddIsolationSegmentToOrg(segment.GUID, "org-guid")).ShouldNot(HaveOccurred())
+			Expect(foundation.IsolationSegmentForSpace("space-guid", segment.GUID)).ShouldNot(HaveOccurred())
+			space, err := foundation.GetSpaceByGuid("space-guid")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(space.IsolationSegmentGuid).Should(Equal(segment.GUID))
+		})
+
+		It("filters segments by entitled org", func() {
+			_, err := foundation.CreateIsolationSegment("dedicated")
+			Expect(err).ShouldNot(HaveOccurred())
+			segments, err := foundation.ListIsolationSegmentsByQuery(url.Values{"organization_guids": []string{"org-guid"}})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(segments).Should(ConsistOf(cfclient.IsolationSegment{GUID: "iso-guid", Name: "shared"}))
+		})
+	})
+
+	Context("running cf-mgmt", func() {
+		var configDir string
+
+		BeforeEach(func() {
+			var err error
+			configDir, err = ioutil.TempDir("", "simulator")
+			Expect(err).ShouldNot(HaveOccurred())
+			configDir = path.Join(configDir, "config")
+			cfg := config.NewManager(configDir)
+			Expect(cfg.CreateConfigIfNotExists("uaa")).ShouldNot(HaveOccurred())
+			Expect(cfg.AddOrgToConfig(&config.OrgConfig{
+				Org:     "test",
+				Auditor: config.UserMgmt{Users: []string{"user-2"}},
+			})).ShouldNot(HaveOccurred())
+			Expect(cfg.AddSpaceToConfig(&config.SpaceConfig{
+				Org:       "test",
+				Space:     "dev",
+				AllowSSH:  true,
+				Developer: config.UserMgmt{Users: []string{"user-2"}},
+			})).ShouldNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(path.Dir(configDir))
+		})
+
+		It("applies the configuration to the foundation", func() {
+			mgmt, err := cfmgmt.NewWithClient(cfmgmt.Config{ConfigDirectory: configDir}, foundation, foundation.UAAManager(false))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(mgmt.UserManager.InitializeLdap("")).ShouldNot(HaveOccurred())
+			Expect(mgmt.OrgManager.CreateOrgs()).ShouldNot(HaveOccurred())
+			Expect(mgmt.UserManager.UpdateOrgUsers()).ShouldNot(HaveOccurred())
+			Expect(mgmt.SpaceManager.CreateSpaces()).ShouldNot(HaveOccurred())
+			Expect(mgmt.SpaceManager.UpdateSpaces()).ShouldNot(HaveOccurred())
+			Expect(mgmt.UserManager.UpdateSpaceUsers()).ShouldNot(HaveOccurred())
+
+			spaces, err := foundation.ListSpacesByQuery(url.Values{"q": []string{"organization_guid:org-guid"}})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(spaces).Should(HaveLen(2))
+			Expect(spaces[1].Name).Should(Equal("dev"))
+			Expect(spaces[1].AllowSSH).Should(BeTrue())
+
+			auditors, err := foundation.ListOrgAuditors("org-guid")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(auditors).Should(HaveLen(1))
+			developers, err := foundation.ListSpaceDevelopers(spaces[1].Guid)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(developers).Should(HaveLen(1))
+			Expect(developers[0].Username).Should(Equal("user-2"))
+		})
+
+		It("leaves the foundation unchanged when peeking", func() {
+			mgmt, err := cfmgmt.NewWithClient(cfmgmt.Config{ConfigDirectory: configDir, Peek: true}, foundation, foundation.UAAManager(true))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(mgmt.SpaceManager.CreateSpaces()).ShouldNot(HaveOccurred())
+			Expect(snapshotOf(foundation).Spaces).Should(HaveLen(1))
+		})
+
+		It("only updates the orgs matching the org selector", func() {
+			cfg := config.NewManager(configDir)
+			Expect(cfg.AddOrgToConfig(&config.OrgConfig{Org: "other"})).ShouldNot(HaveOccurred())
+			Expect(cfg.AddSpaceToConfig(&config.SpaceConfig{Org: "other", Space: "dev"})).ShouldNot(HaveOccurred())
+			snapshot.OrgLabels = map[string]map[string]string{"org-guid": {"team": "payments"}}
+			var err error
+			foundation, err = simulator.NewFoundation(snapshot)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			mgmt, err := cfmgmt.NewWithClient(cfmgmt.Config{ConfigDirectory: configDir, OrgSelector: "label=team:payments"}, foundation, foundation.UAAManager(false))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(mgmt.OrgManager.CreateOrgs()).ShouldNot(HaveOccurred())
+			Expect(mgmt.SpaceManager.CreateSpaces()).ShouldNot(HaveOccurred())
+
+			Expect(snapshotOf(foundation).Orgs).Should(HaveLen(1))
+			Expect(snapshotOf(foundation).Spaces).Should(HaveLen(2))
+		})
+
+		It("errors when the org selector is invalid", func() {
+			_, err := cfmgmt.NewWithClient(cfmgmt.Config{ConfigDirectory: configDir, OrgSelector: "team in ()"}, foundation, foundation.UAAManager(false))
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,84 @@
+package simulator
+
+import (
+	"fmt"
+	"strings"
+
+	uaaclient "github.com/cloudfoundry-community/go-uaa"
+)
+
+//ListAllGroups - lists uaa groups, filters other than displayName eq are ignored
+func (f *Foundation) ListAllGroups(filter string, sortBy string, attributes string, sortOrder uaaclient.SortOrder) ([]uaaclient.Group, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	groups := []uaaclient.Group{}
+	for _, group := range f.state.UAAGroups {
+		if filter == "" || filter == fmt.Sprintf(`displayName eq "%s"`, group.DisplayName) {
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+//CreateGroup - creates a uaa group
+func (f *Foundation) CreateGroup(group uaaclient.Group) (*uaaclient.Group, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for _, existing := range f.state.UAAGroups {
+		if strings.EqualFold(existing.DisplayName, group.DisplayName) {
+			return nil, fmt.Errorf("group [%s] already exists", group.DisplayName)
+		}
+	}
+	group.ID = f.newGUID("group")
+	f.state.UAAGroups = append(f.state.UAAGroups, group)
+	return &group, nil
+}
+
+//AddGroupMember - adds a member to a uaa group
+func (f *Foundation) AddGroupMember(groupID string, memberID string, entityType string, origin string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	group, err := f.group(groupID)
+	if err != nil {
+		return err
+	}
+	for _, member := range group.Members {
+		if member.Value == memberID {
+			return fmt.Errorf("member [%s] already in group [%s]", memberID, group.DisplayName)
+		}
+	}
+	if entityType == "" {
+		entityType = "USER"
+	}
+	if origin == "" {
+		origin = "uaa"
+	}
+	group.Members = append(group.Members, uaaclient.GroupMember{Origin: origin, Type: entityType, Value: memberID})
+	return nil
+}
+
+//RemoveGroupMember - removes a member from a uaa group
+func (f *Foundation) RemoveGroupMember(groupID string, memberID string, entityType string, origin string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	group, err := f.group(groupID)
+	if err != nil {
+		return err
+	}
+	for i, member := range group.Members {
+		if member.Value == memberID {
+			group.Members = append(group.Members[:i], group.Members[i+1:]...)
+			return nil
+		}
+	}
+	return notFound("member", memberID)
+}
+
+func (f *Foundation) group(groupID string) (*uaaclient.Group, error) {
+	for i := range f.state.UAAGroups {
+		if f.state.UAAGroups[i].ID == groupID {
+			return &f.state.UAAGroups[i], nil
+		}
+	}
+	return nil, notFound("group", groupID)
+}
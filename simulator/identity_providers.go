@@ -0,0 +1,112 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pivotalservices/cf-mgmt/uaa"
+)
+
+//Curl - answers the /identity-providers and /identity-zones/uaa requests, the only uaa requests
+//cf-mgmt curls. The internal uaa provider is listed unless the snapshot defines one.
+func (f *Foundation) Curl(path string, method string, data string, headers []string) (string, string, error) {
+	path = strings.TrimSuffix(path, "?rawConfig=true")
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if path == "/identity-zones/uaa" {
+		return f.identityZone(method, data)
+	}
+	if !strings.HasPrefix(path, "/identity-providers") {
+		return "", "", fmt.Errorf("%s %s is not simulated", method, path)
+	}
+	id := strings.TrimPrefix(strings.TrimPrefix(path, "/identity-providers"), "/")
+	switch {
+	case method == "GET" && id == "":
+		return f.listIdentityProviders()
+	case method == "POST" && id == "":
+		provider := uaa.IdentityProvider{}
+		if err := json.Unmarshal([]byte(data), &provider); err != nil {
+			return "", "", err
+		}
+		provider.ID = f.newGUID("identity-provider")
+		f.state.UAAIdentityProviders = append(f.state.UAAIdentityProviders, provider)
+		return identityProviderResponse("201 Created", provider)
+	case method == "PUT" && id != "":
+		for i, existing := range f.state.UAAIdentityProviders {
+			if existing.ID == id {
+				provider := uaa.IdentityProvider{}
+				if err := json.Unmarshal([]byte(data), &provider); err != nil {
+					return "", "", err
+				}
+				provider.ID = id
+				f.state.UAAIdentityProviders[i] = provider
+				return identityProviderResponse("200 OK", provider)
+			}
+		}
+		return "HTTP/1.1 404 Not Found\r\n", "", nil
+	case method == "DELETE" && id != "":
+		for i, existing := range f.state.UAAIdentityProviders {
+			if existing.ID == id {
+				f.state.UAAIdentityProviders = append(f.state.UAAIdentityProviders[:i], f.state.UAAIdentityProviders[i+1:]...)
+				return identityProviderResponse("200 OK", existing)
+			}
+		}
+		return "HTTP/1.1 404 Not Found\r\n", "", nil
+	}
+	return "", "", fmt.Errorf("%s %s is not simulated", method, path)
+}
+
+func (f *Foundation) listIdentityProviders() (string, string, error) {
+	providers := append([]uaa.IdentityProvider{}, f.state.UAAIdentityProviders...)
+	internal := false
+	for _, provider := range providers {
+		internal = internal || provider.OriginKey == "uaa"
+	}
+	if !internal {
+		providers = append(providers, uaa.IdentityProvider{OriginKey: "uaa", Name: "uaa", Type: "uaa", Active: true})
+	}
+	return identityProviderResponse("200 OK", providers)
+}
+
+func identityProviderResponse(status string, body interface{}) (string, string, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", "", err
+	}
+	return "HTTP/1.1 " + status + "\r\nContent-Type: application/json\r\n", string(data), nil
+}
+
+// identityZone answers GET and PUT of the default zone, of which only the
+// token policy is simulated. Signing keys are not kept, like uaa never
+// returns them.
+func (f *Foundation) identityZone(method, data string) (string, string, error) {
+	type zoneConfig struct {
+		TokenPolicy uaa.TokenPolicy `json:"tokenPolicy"`
+	}
+	type zone struct {
+		ID     string     `json:"id"`
+		Name   string     `json:"name"`
+		Config zoneConfig `json:"config"`
+	}
+	switch method {
+	case "GET":
+		policy := uaa.TokenPolicy{AccessTokenValidity: -1, RefreshTokenValidity: -1}
+		if f.state.UAATokenPolicy != nil {
+			policy = *f.state.UAATokenPolicy
+		}
+		return identityProviderResponse("200 OK", zone{ID: "uaa", Name: "uaa", Config: zoneConfig{TokenPolicy: policy}})
+	case "PUT":
+		updated := zone{}
+		if err := json.Unmarshal([]byte(data), &updated); err != nil {
+			return "", "", err
+		}
+		policy := updated.Config.TokenPolicy
+		for id := range policy.Keys {
+			policy.Keys[id] = uaa.TokenKey{}
+		}
+		f.state.UAATokenPolicy = &policy
+		return identityProviderResponse("200 OK", updated)
+	}
+	return "", "", fmt.Errorf("%s /identity-zones/uaa is not simulated", method)
+}
@@ -0,0 +1,186 @@
+package simulator
+
+import (
+	"fmt"
+	"net/url"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+)
+
+func (f *Foundation) isolationSegment(guid string) (*cfclient.IsolationSegment, error) {
+	for i := range f.state.IsolationSegments {
+		if f.state.IsolationSegments[i].GUID == guid {
+			return &f.state.IsolationSegments[i], nil
+		}
+	}
+	return nil, notFound("isolation segment", guid)
+}
+
+//ListIsolationSegments -
+func (f *Foundation) ListIsolationSegments() ([]cfclient.IsolationSegment, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]cfclient.IsolationSegment{}, f.state.IsolationSegments...), nil
+}
+
+//ListIsolationSegmentsByQuery - supports the organization_guids and names filters
+func (f *Foundation) ListIsolationSegmentsByQuery(query url.Values) ([]cfclient.IsolationSegment, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	segments := []cfclient.IsolationSegment{}
+	for _, segment := range f.state.IsolationSegments {
+		if names, ok := query["names"]; ok && !contains(names, segment.Name) {
+			continue
+		}
+		if orgGUIDs, ok := query["organization_guids"]; ok && !f.entitledAny(segment.GUID, orgGUIDs) {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	return segments, nil
+}
+
+func (f *Foundation) entitledAny(segmentGUID string, orgGUIDs []string) bool {
+	for _, orgGUID := range orgGUIDs {
+		if contains(f.state.Entitlements[segmentGUID], orgGUID) {
+			return true
+		}
+	}
+	return false
+}
+
+//GetIsolationSegmentByGUID -
+func (f *Foundation) GetIsolationSegmentByGUID(guid string) (*cfclient.IsolationSegment, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	segment, err := f.isolationSegment(guid)
+	if err != nil {
+		return nil, err
+	}
+	result := *segment
+	return &result, nil
+}
+
+//CreateIsolationSegment -
+func (f *Foundation) CreateIsolationSegment(name string) (*cfclient.IsolationSegment, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for _, segment := range f.state.IsolationSegments {
+		if segment.Name == name {
+			return nil, fmt.Errorf("isolation segment name [%s] is taken", name)
+		}
+	}
+	segment := cfclient.IsolationSegment{GUID: f.newGUID("isolation-segment"), Name: name}
+	f.state.IsolationSegments = append(f.state.IsolationSegments, segment)
+	return &segment, nil
+}
+
+//DeleteIsolationSegmentByGUID - fails while orgs are entitled to the segment
+func (f *Foundation) DeleteIsolationSegmentByGUID(guid string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, err := f.isolationSegment(guid); err != nil {
+		return err
+	}
+	if len(f.state.Entitlements[guid]) > 0 {
+		return fmt.Errorf("isolation segment [%s] is still entitled to orgs %v", guid, f.state.Entitlements[guid])
+	}
+	segments := []cfclient.IsolationSegment{}
+	for _, segment := range f.state.IsolationSegments {
+		if segment.GUID != guid {
+			segments = append(segments, segment)
+		}
+	}
+	f.state.IsolationSegments = segments
+	delete(f.state.Entitlements, guid)
+	return nil
+}
+
+//AddIsolationSegmentToOrg - entitles the org to the segment
+func (f *Foundation) AddIsolationSegmentToOrg(isolationSegmentGUID, orgGUID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, err := f.isolationSegment(isolationSegmentGUID); err != nil {
+		return err
+	}
+	if _, err := f.org(orgGUID); err != nil {
+		return err
+	}
+	f.state.Entitlements[isolationSegmentGUID] = appendUnique(f.state.Entitlements[isolationSegmentGUID], orgGUID)
+	return nil
+}
+
+//RemoveIsolationSegmentFromOrg - revokes the org entitlement to the segment
+func (f *Foundation) RemoveIsolationSegmentFromOrg(isolationSegmentGUID, orgGUID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, err := f.isolationSegment(isolationSegmentGUID); err != nil {
+		return err
+	}
+	f.state.Entitlements[isolationSegmentGUID] = remove(f.state.Entitlements[isolationSegmentGUID], orgGUID)
+	return nil
+}
+
+//AddIsolationSegmentToSpace -
+func (f *Foundation) AddIsolationSegmentToSpace(isolationSegmentGUID, spaceGUID string) error {
+	return f.IsolationSegmentForSpace(spaceGUID, isolationSegmentGUID)
+}
+
+//RemoveIsolationSegmentFromSpace -
+func (f *Foundation) RemoveIsolationSegmentFromSpace(isolationSegmentGUID, spaceGUID string) error {
+	return f.ResetIsolationSegmentForSpace(spaceGUID)
+}
+
+//DefaultIsolationSegmentForOrg - the org must be entitled to the segment
+func (f *Foundation) DefaultIsolationSegmentForOrg(orgGUID, isolationSegmentGUID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	org, err := f.org(orgGUID)
+	if err != nil {
+		return err
+	}
+	if !contains(f.state.Entitlements[isolationSegmentGUID], orgGUID) {
+		return fmt.Errorf("org [%s] is not entitled to isolation segment [%s]", org.Name, isolationSegmentGUID)
+	}
+	org.DefaultIsolationSegmentGuid = isolationSegmentGUID
+	return nil
+}
+
+//ResetDefaultIsolationSegmentForOrg -
+func (f *Foundation) ResetDefaultIsolationSegmentForOrg(orgGUID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	org, err := f.org(orgGUID)
+	if err != nil {
+		return err
+	}
+	org.DefaultIsolationSegmentGuid = ""
+	return nil
+}
+
+//IsolationSegmentForSpace - the space org must be entitled to the segment
+func (f *Foundation) IsolationSegmentForSpace(spaceGUID, isolationSegmentGUID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	space, err := f.space(spaceGUID)
+	if err != nil {
+		return err
+	}
+	if !contains(f.state.Entitlements[isolationSegmentGUID], space.OrganizationGuid) {
+		return fmt.Errorf("org of space [%s] is not entitled to isolation segment [%s]", space.Name, isolationSegmentGUID)
+	}
+	space.IsolationSegmentGuid = isolationSegmentGUID
+	return nil
+}
+
+//ResetIsolationSegmentForSpace -
+func (f *Foundation) ResetIsolationSegmentForSpace(spaceGUID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	space, err := f.space(spaceGUID)
+	if err != nil {
+		return err
+	}
+	space.IsolationSegmentGuid = ""
+	return nil
+}
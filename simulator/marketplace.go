@@ -0,0 +1,125 @@
+package simulator
+
+import (
+	"fmt"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pkg/errors"
+)
+
+// MarketplaceClient is the cloud controller calls made to export the
+// marketplace of a foundation, satisfied by *cfclient.Client.
+type MarketplaceClient interface {
+	ListServiceBrokers() ([]cfclient.ServiceBroker, error)
+	ListServices() ([]cfclient.Service, error)
+	ListServicePlans() ([]cfclient.ServicePlan, error)
+	ListServicePlanVisibilities() ([]cfclient.ServicePlanVisibility, error)
+	ListOrgs() ([]cfclient.Org, error)
+}
+
+type serviceBrokerState struct {
+	URL   string `json:"broker_url"`
+	Space string `json:"space,omitempty"`
+}
+
+type serviceState struct {
+	Broker      string `json:"broker"`
+	Description string `json:"description"`
+	Active      bool   `json:"active"`
+	Bindable    bool   `json:"bindable"`
+}
+
+type servicePlanState struct {
+	Free   bool `json:"free"`
+	Public bool `json:"public"`
+	Active bool `json:"active"`
+}
+
+//ExportMarketplace - reads the service brokers, services, plans and plan visibilities of a foundation
+//into a snapshot holding only them and the orgs the plans are visible to
+func ExportMarketplace(client MarketplaceClient) (*Snapshot, error) {
+	snapshot := &Snapshot{}
+	if err := exportMarketplace(client, snapshot); err != nil {
+		return nil, err
+	}
+	orgs, err := client.ListOrgs()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list orgs")
+	}
+	visible := make(map[string]bool)
+	for _, visibility := range snapshot.ServicePlanVisibilities {
+		visible[visibility.OrganizationGuid] = true
+	}
+	for _, org := range orgs {
+		if visible[org.Guid] {
+			snapshot.Orgs = append(snapshot.Orgs, org)
+		}
+	}
+	return snapshot, nil
+}
+
+func exportMarketplace(client MarketplaceClient, snapshot *Snapshot) error {
+	var err error
+	if snapshot.ServiceBrokers, err = client.ListServiceBrokers(); err != nil {
+		return errors.Wrap(err, "unable to list service brokers")
+	}
+	if snapshot.Services, err = client.ListServices(); err != nil {
+		return errors.Wrap(err, "unable to list services")
+	}
+	if snapshot.ServicePlans, err = client.ListServicePlans(); err != nil {
+		return errors.Wrap(err, "unable to list service plans")
+	}
+	if snapshot.ServicePlanVisibilities, err = client.ListServicePlanVisibilities(); err != nil {
+		return errors.Wrap(err, "unable to list service plan visibilities")
+	}
+	return nil
+}
+
+// the marketplace is keyed by name rather than guid, so that the snapshots of
+// different foundations can be compared
+
+func (n *snapshotNames) serviceBrokerEntries(s *Snapshot) map[string]planEntry {
+	entries := make(map[string]planEntry)
+	for _, broker := range s.ServiceBrokers {
+		entries[broker.Name] = planEntry{name: broker.Name, state: serviceBrokerState{
+			URL:   broker.BrokerURL,
+			Space: n.spaces[broker.SpaceGUID],
+		}}
+	}
+	return entries
+}
+
+func (n *snapshotNames) serviceEntries(s *Snapshot) map[string]planEntry {
+	entries := make(map[string]planEntry)
+	for _, service := range s.Services {
+		entries[service.Label] = planEntry{name: service.Label, state: serviceState{
+			Broker:      nameOf(n.serviceBrokers, service.ServiceBrokerGuid),
+			Description: service.Description,
+			Active:      service.Active,
+			Bindable:    service.Bindable,
+		}}
+	}
+	return entries
+}
+
+func (n *snapshotNames) servicePlanEntries(s *Snapshot) map[string]planEntry {
+	entries := make(map[string]planEntry)
+	for _, plan := range s.ServicePlans {
+		name := n.servicePlans[plan.Guid]
+		entries[name] = planEntry{name: name, state: servicePlanState{
+			Free:   plan.Free,
+			Public: plan.Public,
+			Active: plan.Active,
+		}}
+	}
+	return entries
+}
+
+func (n *snapshotNames) servicePlanVisibilityEntries(s *Snapshot) map[string]planEntry {
+	entries := make(map[string]planEntry)
+	for _, visibility := range s.ServicePlanVisibilities {
+		name := fmt.Sprintf("%s for %s", nameOf(n.servicePlans, visibility.ServicePlanGuid), nameOf(n.orgs, visibility.OrganizationGuid))
+		entries[name] = planEntry{name: name}
+	}
+	return entries
+}
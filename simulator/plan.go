@@ -0,0 +1,479 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pivotalservices/cf-mgmt/config"
+)
+
+//Actions of a Change
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Change is a single difference between two snapshots, such as an org that
+// was created or a role that was granted.
+type Change struct {
+	Action string `json:"action"`
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	// Detail lists the fields an update changed, or where a granted role comes
+	// from when attribute-roles is set
+	Detail string `json:"detail,omitempty"`
+}
+
+//String - the action, kind and name, followed by the detail of an update
+func (c Change) String() string {
+	if c.Detail != "" {
+		return fmt.Sprintf("%s %s %s: %s", c.Action, c.Kind, c.Name, c.Detail)
+	}
+	return fmt.Sprintf("%s %s %s", c.Action, c.Kind, c.Name)
+}
+
+// planEntry is an entity of a snapshot, shown with name and compared by state
+type planEntry struct {
+	name  string
+	state interface{}
+	// org is the guid of the org of an entity that belongs to one
+	org string
+}
+
+type orgState struct {
+	Status           string `json:"status"`
+	Quota            string `json:"quota"`
+	IsolationSegment string `json:"default_isolation_segment"`
+}
+
+type spaceState struct {
+	AllowSSH         bool   `json:"allow_ssh"`
+	Quota            string `json:"quota"`
+	IsolationSegment string `json:"isolation_segment"`
+}
+
+type secGroupState struct {
+	Rules   []cfclient.SecGroupRule `json:"rules"`
+	Running bool                    `json:"running_default"`
+	Staging bool                    `json:"staging_default"`
+}
+
+type uaaUserState struct {
+	UserName   string `json:"user_name"`
+	Origin     string `json:"origin"`
+	ExternalID string `json:"external_id"`
+}
+
+type identityProviderState struct {
+	Name   string                 `json:"name"`
+	Type   string                 `json:"type"`
+	Active bool                   `json:"active"`
+	Config map[string]interface{} `json:"config"`
+}
+
+type appState struct {
+	State string `json:"state"`
+}
+
+// snapshotNames resolves the guids of a snapshot to the names changes are shown with
+type snapshotNames struct {
+	orgs, spaces, orgQuotas, spaceQuotas, segments, domains, users map[string]string
+	serviceBrokers, services, servicePlans                         map[string]string
+	// spaceOrgs are the guids of the orgs of the spaces
+	spaceOrgs map[string]string
+}
+
+func newSnapshotNames(snapshots ...*Snapshot) *snapshotNames {
+	n := &snapshotNames{
+		orgs:           make(map[string]string),
+		spaces:         make(map[string]string),
+		orgQuotas:      make(map[string]string),
+		spaceQuotas:    make(map[string]string),
+		segments:       make(map[string]string),
+		domains:        make(map[string]string),
+		users:          make(map[string]string),
+		serviceBrokers: make(map[string]string),
+		services:       make(map[string]string),
+		servicePlans:   make(map[string]string),
+		spaceOrgs:      make(map[string]string),
+	}
+	for _, snapshot := range snapshots {
+		for _, org := range snapshot.Orgs {
+			n.orgs[org.Guid] = org.Name
+		}
+		for _, space := range snapshot.Spaces {
+			n.spaces[space.Guid] = space.Name
+			n.spaceOrgs[space.Guid] = space.OrganizationGuid
+		}
+		for _, quota := range snapshot.OrgQuotas {
+			n.orgQuotas[quota.Guid] = quota.Name
+		}
+		for _, quota := range snapshot.SpaceQuotas {
+			n.spaceQuotas[quota.Guid] = quota.Name
+		}
+		for _, segment := range snapshot.IsolationSegments {
+			n.segments[segment.GUID] = segment.Name
+		}
+		for _, domain := range snapshot.Domains {
+			n.domains[domain.Guid] = domain.Name
+		}
+		for _, domain := range snapshot.PlatformDomains {
+			n.domains[domain.Guid] = domain.Name
+		}
+		for _, user := range snapshot.Users {
+			n.users[user.Guid] = user.Username
+		}
+		for _, user := range snapshot.UAAUsers {
+			n.users[user.ID] = user.Username
+		}
+		for _, broker := range snapshot.ServiceBrokers {
+			n.serviceBrokers[broker.Guid] = broker.Name
+		}
+		for _, service := range snapshot.Services {
+			n.services[service.Guid] = service.Label
+		}
+	}
+	// spaces are shown with their org
+	for _, snapshot := range snapshots {
+		for _, space := range snapshot.Spaces {
+			n.spaces[space.Guid] = n.orgs[space.OrganizationGuid] + "/" + space.Name
+		}
+		for _, quota := range snapshot.SpaceQuotas {
+			n.spaceQuotas[quota.Guid] = n.orgs[quota.OrganizationGuid] + "/" + quota.Name
+		}
+		// plans are shown with their service
+		for _, plan := range snapshot.ServicePlans {
+			n.servicePlans[plan.Guid] = nameOf(n.services, plan.ServiceGuid) + "/" + plan.Name
+		}
+	}
+	return n
+}
+
+func nameOf(names map[string]string, guid string) string {
+	if name, ok := names[guid]; ok {
+		return name
+	}
+	return guid
+}
+
+//Diff - the changes that turn before into after, such as applying the configuration to a
+//snapshot, grouped by kind and sorted by name within each kind
+func Diff(before, after *Snapshot) ([]Change, error) {
+	return diff(newSnapshotNames(before, after), before, after, func(string) bool { return false })
+}
+
+//DiffUnprotected - the changes that turn before into after, like Diff, without the changes of the
+//protected orgs, which cf-mgmt excludes, and of their spaces
+func DiffUnprotected(before, after *Snapshot, protectedOrgs []string) ([]Change, error) {
+	names := newSnapshotNames(before, after)
+	return diff(names, before, after, func(orgGUID string) bool {
+		name, ok := names.orgs[orgGUID]
+		return ok && config.IsProtectedOrg(name, protectedOrgs)
+	})
+}
+
+func diff(names *snapshotNames, before, after *Snapshot, excluded func(orgGUID string) bool) ([]Change, error) {
+	changes := []Change{}
+	for _, kind := range []struct {
+		kind    string
+		entries func(*Snapshot) map[string]planEntry
+	}{
+		{"org", names.orgEntries},
+		{"space", names.spaceEntries},
+		{"org quota", names.orgQuotaEntries},
+		{"space quota", names.spaceQuotaEntries},
+		{"security group", names.secGroupEntries},
+		{"space security group", names.spaceSecGroupEntries},
+		{"private domain", names.domainEntries},
+		{"shared domain", names.sharedDomainEntries},
+		{"route", names.routeEntries},
+		{"app", names.appEntries},
+		{"isolation segment", names.segmentEntries},
+		{"isolation segment entitlement", names.entitlementEntries},
+		{"uaa user", names.uaaUserEntries},
+		{"uaa group member", names.groupMemberEntries},
+		{"uaa identity provider", names.identityProviderEntries},
+		{"uaa token policy", names.tokenPolicyEntries},
+		{"org role", names.orgRoleEntries},
+		{"space role", names.spaceRoleEntries},
+		{"service broker", names.serviceBrokerEntries},
+		{"service", names.serviceEntries},
+		{"service plan", names.servicePlanEntries},
+		{"service plan visibility", names.servicePlanVisibilityEntries},
+	} {
+		kindChanges, err := diffEntries(kind.kind, kind.entries(before), kind.entries(after), excluded)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, kindChanges...)
+	}
+	return changes, nil
+}
+
+func diffEntries(kind string, before, after map[string]planEntry, excluded func(orgGUID string) bool) ([]Change, error) {
+	changes := []Change{}
+	for key, entry := range after {
+		if excluded(entry.org) {
+			continue
+		}
+		existing, ok := before[key]
+		if !ok {
+			changes = append(changes, Change{Action: ActionCreate, Kind: kind, Name: entry.name})
+			continue
+		}
+		detail, err := stateChanges(existing.state, entry.state)
+		if err != nil {
+			return nil, err
+		}
+		if detail != "" {
+			changes = append(changes, Change{Action: ActionUpdate, Kind: kind, Name: entry.name, Detail: detail})
+		}
+	}
+	for key, entry := range before {
+		if _, ok := after[key]; !ok && !excluded(entry.org) {
+			changes = append(changes, Change{Action: ActionDelete, Kind: kind, Name: entry.name})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Name != changes[j].Name {
+			return changes[i].Name < changes[j].Name
+		}
+		return changes[i].Action < changes[j].Action
+	})
+	return changes, nil
+}
+
+// stateChanges lists the json fields that differ, empty when the states are equal
+func stateChanges(before, after interface{}) (string, error) {
+	if before == nil || after == nil {
+		return "", nil
+	}
+	beforeFields, err := jsonFields(before)
+	if err != nil {
+		return "", err
+	}
+	afterFields, err := jsonFields(after)
+	if err != nil {
+		return "", err
+	}
+	var fields []string
+	for field, value := range afterFields {
+		if beforeFields[field] != value {
+			fields = append(fields, fmt.Sprintf("%s %s -> %s", field, beforeFields[field], value))
+		}
+	}
+	sort.Strings(fields)
+	return strings.Join(fields, ", "), nil
+}
+
+func jsonFields(state interface{}) (map[string]string, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	raw := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	fields := make(map[string]string)
+	for field, value := range raw {
+		fields[field] = string(value)
+	}
+	return fields, nil
+}
+
+func (n *snapshotNames) orgEntries(s *Snapshot) map[string]planEntry {
+	entries := make(map[string]planEntry)
+	for _, org := range s.Orgs {
+		entries[org.Guid] = planEntry{name: org.Name, state: orgState{
+			Status:           org.Status,
+			Quota:            nameOf(n.orgQuotas, org.QuotaDefinitionGuid),
+			IsolationSegment: nameOf(n.segments, org.DefaultIsolationSegmentGuid),
+		}, org: org.Guid}
+	}
+	return entries
+}
+
+func (n *snapshotNames) spaceEntries(s *Snapshot) map[string]planEntry {
+	entries := make(map[string]planEntry)
+	for _, space := range s.Spaces {
+		entries[space.Guid] = planEntry{name: n.spaces[space.Guid], state: spaceState{
+			AllowSSH:         space.AllowSSH,
+			Quota:            nameOf(n.spaceQuotas, space.QuotaDefinitionGuid),
+			IsolationSegment: nameOf(n.segments, space.IsolationSegmentGuid),
+		}, org: space.OrganizationGuid}
+	}
+	return entries
+}
+
+func (n *snapshotNames) orgQuotaEntries(s *Snapshot) map[string]planEntry {
+	entries := make(map[string]planEntry)
+	for _, quota := range s.OrgQuotas {
+		name := quota.Name
+		quota.Guid, quota.CreatedAt, quota.UpdatedAt = "", "", ""
+		entries[name] = planEntry{name: name, state: quota}
+	}
+	return entries
+}
+
+func (n *snapshotNames) spaceQuotaEntries(s *Snapshot) map[string]planEntry {
+	entries := make(map[string]planEntry)
+	for _, quota := range s.SpaceQuotas {
+		name, orgGUID := n.spaceQuotas[quota.Guid], quota.OrganizationGuid
+		quota.Guid, quota.CreatedAt, quota.UpdatedAt = "", "", ""
+		entries[name] = planEntry{name: name, state: quota, org: orgGUID}
+	}
+	return entries
+}
+
+func (n *snapshotNames) secGroupEntries(s *Snapshot) map[string]planEntry {
+	entries := make(map[string]planEntry)
+	for _, sg := range s.SecurityGroups {
+		entries[sg.Name] = planEntry{name: sg.Name, state: secGroupState{Rules: sg.Rules, Running: sg.Running, Staging: sg.Staging}}
+	}
+	return entries
+}
+
+func (n *snapshotNames) spaceSecGroupEntries(s *Snapshot) map[string]planEntry {
+	entries := make(map[string]planEntry)
+	for _, sg := range s.SecurityGroups {
+		for _, resource := range sg.SpacesData {
+			spaceGUID := spaceResourceGUID(resource)
+			entries[sg.Guid+"/"+spaceGUID] = planEntry{name: fmt.Sprintf("%s to %s", sg.Name, nameOf(n.spaces, spaceGUID)), org: n.spaceOrgs[spaceGUID]}
+		}
+		for _, resource := range sg.StagingSpacesData {
+			spaceGUID := spaceResourceGUID(resource)
+			entries[sg.Guid+"/staging/"+spaceGUID] = planEntry{name: fmt.Sprintf("%s to %s for staging", sg.Name, nameOf(n.spaces, spaceGUID)), org: n.spaceOrgs[spaceGUID]}
+		}
+	}
+	return entries
+}
+
+func (n *snapshotNames) domainEntries(s *Snapshot) map[string]planEntry {
+	entries := make(map[string]planEntry)
+	for _, domain := range s.Domains {
+		if domain.OwningOrganizationGuid == "" {
+			continue
+		}
+		entries[domain.Guid] = planEntry{name: fmt.Sprintf("%s of %s", domain.Name, nameOf(n.orgs, domain.OwningOrganizationGuid)), org: domain.OwningOrganizationGuid}
+	}
+	return entries
+}
+
+func (n *snapshotNames) sharedDomainEntries(s *Snapshot) map[string]planEntry {
+	entries := make(map[string]planEntry)
+	for orgGUID, domainGUIDs := range s.SharedDomains {
+		for _, domainGUID := range domainGUIDs {
+			entries[orgGUID+"/"+domainGUID] = planEntry{name: fmt.Sprintf("%s with %s", nameOf(n.domains, domainGUID), nameOf(n.orgs, orgGUID)), org: orgGUID}
+		}
+	}
+	return entries
+}
+
+func (n *snapshotNames) routeEntries(s *Snapshot) map[string]planEntry {
+	entries := make(map[string]planEntry)
+	for _, route := range s.Routes {
+		entries[route.Guid] = planEntry{name: fmt.Sprintf("%s.%s%s in %s", route.Host, nameOf(n.domains, route.DomainGuid), route.Path, nameOf(n.spaces, route.SpaceGuid)), org: n.spaceOrgs[route.SpaceGuid]}
+	}
+	return entries
+}
+
+func (n *snapshotNames) appEntries(s *Snapshot) map[string]planEntry {
+	entries := make(map[string]planEntry)
+	for _, app := range s.Apps {
+		entries[app.Guid] = planEntry{name: nameOf(n.spaces, app.SpaceGuid) + "/" + app.Name, state: appState{State: app.State}, org: n.spaceOrgs[app.SpaceGuid]}
+	}
+	return entries
+}
+
+func (n *snapshotNames) segmentEntries(s *Snapshot) map[string]planEntry {
+	entries := make(map[string]planEntry)
+	for _, segment := range s.IsolationSegments {
+		entries[segment.GUID] = planEntry{name: segment.Name}
+	}
+	return entries
+}
+
+func (n *snapshotNames) entitlementEntries(s *Snapshot) map[string]planEntry {
+	entries := make(map[string]planEntry)
+	for segmentGUID, orgGUIDs := range s.Entitlements {
+		for _, orgGUID := range orgGUIDs {
+			entries[segmentGUID+"/"+orgGUID] = planEntry{name: fmt.Sprintf("%s for %s", nameOf(n.segments, segmentGUID), nameOf(n.orgs, orgGUID)), org: orgGUID}
+		}
+	}
+	return entries
+}
+
+func (n *snapshotNames) uaaUserEntries(s *Snapshot) map[string]planEntry {
+	entries := make(map[string]planEntry)
+	for _, user := range s.UAAUsers {
+		entries[user.ID] = planEntry{name: fmt.Sprintf("%s (%s)", user.Username, user.Origin), state: uaaUserState{
+			UserName:   user.Username,
+			Origin:     user.Origin,
+			ExternalID: user.ExternalID,
+		}}
+	}
+	return entries
+}
+
+func (n *snapshotNames) identityProviderEntries(s *Snapshot) map[string]planEntry {
+	entries := make(map[string]planEntry)
+	for _, provider := range s.UAAIdentityProviders {
+		// the oidc client secret is never returned by uaa
+		config := make(map[string]interface{})
+		for key, value := range provider.Config {
+			if key != "relyingPartySecret" {
+				config[key] = value
+			}
+		}
+		entries[provider.OriginKey] = planEntry{name: provider.OriginKey, state: identityProviderState{
+			Name:   provider.Name,
+			Type:   provider.Type,
+			Active: provider.Active,
+			Config: config,
+		}}
+	}
+	return entries
+}
+
+func (n *snapshotNames) tokenPolicyEntries(s *Snapshot) map[string]planEntry {
+	entries := make(map[string]planEntry)
+	if s.UAATokenPolicy != nil {
+		entries["uaa"] = planEntry{name: "of identity zone uaa", state: *s.UAATokenPolicy}
+	}
+	return entries
+}
+
+func (n *snapshotNames) groupMemberEntries(s *Snapshot) map[string]planEntry {
+	entries := make(map[string]planEntry)
+	for _, group := range s.UAAGroups {
+		for _, member := range group.Members {
+			entries[group.ID+"/"+member.Value] = planEntry{name: fmt.Sprintf("%s in %s", nameOf(n.users, member.Value), group.DisplayName)}
+		}
+	}
+	return entries
+}
+
+func (n *snapshotNames) orgRoleEntries(s *Snapshot) map[string]planEntry {
+	return roleEntries(s.OrgRoles, n.orgs, n.users, func(orgGUID string) string { return orgGUID })
+}
+
+func (n *snapshotNames) spaceRoleEntries(s *Snapshot) map[string]planEntry {
+	return roleEntries(s.SpaceRoles, n.spaces, n.users, func(spaceGUID string) string { return n.spaceOrgs[spaceGUID] })
+}
+
+func roleEntries(roles map[string]Roles, entities, users map[string]string, orgOf func(guid string) string) map[string]planEntry {
+	entries := make(map[string]planEntry)
+	for guid, entityRoles := range roles {
+		for role, userGUIDs := range entityRoles {
+			for _, userGUID := range userGUIDs {
+				entries[guid+"/"+role+"/"+userGUID] = planEntry{name: fmt.Sprintf("%s as %s of %s", nameOf(users, userGUID), strings.TrimSuffix(role, "s"), nameOf(entities, guid)), org: orgOf(guid)}
+			}
+		}
+	}
+	return entries
+}
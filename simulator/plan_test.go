@@ -0,0 +1,133 @@
+package simulator_test
+
+import (
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/simulator"
+)
+
+var _ = Describe("given a snapshot", func() {
+	var (
+		snapshot   *simulator.Snapshot
+		foundation *simulator.Foundation
+	)
+
+	BeforeEach(func() {
+		var err error
+		snapshot, err = simulator.LoadSnapshot("./fixtures/snapshot.json")
+		Expect(err).ShouldNot(HaveOccurred())
+		foundation, err = simulator.NewFoundation(snapshot)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("Diff", func() {
+		It("has no changes for the same snapshot", func() {
+			Expect(simulator.Diff(snapshot, snapshotOf(foundation))).Should(BeEmpty())
+		})
+
+		It("lists created, updated and deleted entities by name", func() {
+			org, err := foundation.CreateOrg(cfclient.OrgRequest{Name: "new-org"})
+			Expect(err).ShouldNot(HaveOccurred())
+			_, err = foundation.CreateSpace(cfclient.SpaceRequest{Name: "dev", OrganizationGuid: org.Guid})
+			Expect(err).ShouldNot(HaveOccurred())
+			_, err = foundation.UpdateSpace("space-guid", cfclient.SpaceRequest{Name: "old-space", AllowSSH: true})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(foundation.RemoveOrgManager("org-guid", "user-1-guid")).Should(Succeed())
+			_, err = foundation.AssociateOrgAuditorByUsername(org.Guid, "user-2")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(foundation.DeleteRoute("route-guid")).Should(Succeed())
+			_, err = foundation.UpdateApp("app-guid", cfclient.AppUpdateResource{State: "STOPPED"})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(simulator.Diff(snapshot, snapshotOf(foundation))).Should(Equal([]simulator.Change{
+				{Action: simulator.ActionCreate, Kind: "org", Name: "new-org"},
+				{Action: simulator.ActionCreate, Kind: "space", Name: "new-org/dev"},
+				{Action: simulator.ActionUpdate, Kind: "space", Name: "test/old-space", Detail: "allow_ssh false -> true"},
+				{Action: simulator.ActionDelete, Kind: "route", Name: "api.apps.internal in test/old-space"},
+				{Action: simulator.ActionUpdate, Kind: "app", Name: "test/old-space/worker", Detail: `state "STARTED" -> "STOPPED"`},
+				{Action: simulator.ActionDelete, Kind: "org role", Name: "user-1 as manager of test"},
+				{Action: simulator.ActionCreate, Kind: "org role", Name: "user-2 as auditor of new-org"},
+			}))
+		})
+
+		It("leaves out the changes of protected orgs and their spaces", func() {
+			org, err := foundation.CreateOrg(cfclient.OrgRequest{Name: "new-org"})
+			Expect(err).ShouldNot(HaveOccurred())
+			_, err = foundation.UpdateSpace("space-guid", cfclient.SpaceRequest{Name: "old-space", AllowSSH: true})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(foundation.RemoveOrgManager("org-guid", "user-1-guid")).Should(Succeed())
+			Expect(foundation.DeleteRoute("route-guid")).Should(Succeed())
+			_, err = foundation.AssociateOrgAuditorByUsername(org.Guid, "user-2")
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(simulator.DiffUnprotected(snapshot, snapshotOf(foundation), []string{"te.*"})).Should(Equal([]simulator.Change{
+				{Action: simulator.ActionCreate, Kind: "org", Name: "new-org"},
+				{Action: simulator.ActionCreate, Kind: "org role", Name: "user-2 as auditor of new-org"},
+			}))
+		})
+	})
+
+	Context("Diff of marketplaces", func() {
+		marketplace := func(orgGUID, brokerURL string, public bool) *simulator.Snapshot {
+			return &simulator.Snapshot{
+				Orgs:           []cfclient.Org{{Guid: orgGUID, Name: "test"}},
+				ServiceBrokers: []cfclient.ServiceBroker{{Guid: orgGUID + "-broker", Name: "mysql-broker", BrokerURL: brokerURL}},
+				Services:       []cfclient.Service{{Guid: orgGUID + "-service", Label: "p.mysql", ServiceBrokerGuid: orgGUID + "-broker"}},
+				ServicePlans: []cfclient.ServicePlan{
+					{Guid: orgGUID + "-small", Name: "db-small", ServiceGuid: orgGUID + "-service", Public: public},
+				},
+				ServicePlanVisibilities: []cfclient.ServicePlanVisibility{
+					{Guid: orgGUID + "-visibility", ServicePlanGuid: orgGUID + "-small", OrganizationGuid: orgGUID},
+				},
+			}
+		}
+
+		It("compares the marketplaces of foundations by name", func() {
+			Expect(simulator.Diff(marketplace("org-a", "https://mysql", false), marketplace("org-b", "https://mysql", false))).Should(Equal([]simulator.Change{
+				{Action: simulator.ActionCreate, Kind: "org", Name: "test"},
+				{Action: simulator.ActionDelete, Kind: "org", Name: "test"},
+			}))
+		})
+
+		It("lists broker and plan drift", func() {
+			before, after := marketplace("org-a", "https://mysql", false), marketplace("org-a", "https://mysql-v2", true)
+			after.ServicePlanVisibilities = nil
+			Expect(simulator.Diff(before, after)).Should(Equal([]simulator.Change{
+				{Action: simulator.ActionUpdate, Kind: "service broker", Name: "mysql-broker", Detail: `broker_url "https://mysql" -> "https://mysql-v2"`},
+				{Action: simulator.ActionUpdate, Kind: "service plan", Name: "p.mysql/db-small", Detail: "public false -> true"},
+				{Action: simulator.ActionDelete, Kind: "service plan visibility", Name: "p.mysql/db-small for test"},
+			}))
+		})
+
+		It("exports the marketplace and the orgs plans are visible to", func() {
+			foundation, err := simulator.NewFoundation(marketplace("org-guid", "https://mysql", false))
+			Expect(err).ShouldNot(HaveOccurred())
+			exported, err := simulator.ExportMarketplace(foundation)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(exported.ServiceBrokers).Should(HaveLen(1))
+			Expect(exported.ServicePlanVisibilities).Should(HaveLen(1))
+			Expect(exported.Orgs).Should(HaveLen(1))
+			Expect(exported.Spaces).Should(BeEmpty())
+		})
+	})
+
+	Context("Export", func() {
+		It("exports the state of the foundation", func() {
+			exported, err := simulator.Export(foundation, foundation.UAAManager(false), []config.RoleGroup{
+				{Role: config.RoleOrgManager, Group: "{org}-managers"},
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(exported.Orgs).Should(HaveLen(1))
+			Expect(exported.Spaces).Should(HaveLen(1))
+			Expect(exported.UAAUsers).Should(HaveLen(2))
+			Expect(exported.Entitlements["iso-guid"]).Should(ConsistOf("org-guid"))
+			Expect(exported.Routes).Should(HaveLen(1))
+			Expect(exported.Apps).Should(HaveLen(1))
+			Expect(exported.Stacks).Should(HaveLen(2))
+			Expect(exported.OrgRoles["org-guid"][simulator.RoleManagers]).Should(ConsistOf("user-1-guid"))
+			Expect(simulator.Diff(snapshot, exported)).Should(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,165 @@
+package simulator
+
+import (
+	"fmt"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+)
+
+//ListOrgQuotas -
+func (f *Foundation) ListOrgQuotas() ([]cfclient.OrgQuota, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]cfclient.OrgQuota{}, f.state.OrgQuotas...), nil
+}
+
+//GetOrgQuotaByName -
+func (f *Foundation) GetOrgQuotaByName(name string) (cfclient.OrgQuota, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for _, quota := range f.state.OrgQuotas {
+		if quota.Name == name {
+			return quota, nil
+		}
+	}
+	return cfclient.OrgQuota{}, fmt.Errorf("Unable to find org quota %s", name)
+}
+
+//CreateOrgQuota -
+func (f *Foundation) CreateOrgQuota(req cfclient.OrgQuotaRequest) (*cfclient.OrgQuota, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for _, quota := range f.state.OrgQuotas {
+		if quota.Name == req.Name {
+			return nil, fmt.Errorf("org quota name [%s] is taken", req.Name)
+		}
+	}
+	quota := orgQuota(f.newGUID("org-quota"), req)
+	f.state.OrgQuotas = append(f.state.OrgQuotas, quota)
+	return &quota, nil
+}
+
+//UpdateOrgQuota -
+func (f *Foundation) UpdateOrgQuota(orgQuotaGUID string, req cfclient.OrgQuotaRequest) (*cfclient.OrgQuota, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for i := range f.state.OrgQuotas {
+		if f.state.OrgQuotas[i].Guid == orgQuotaGUID {
+			f.state.OrgQuotas[i] = orgQuota(orgQuotaGUID, req)
+			quota := f.state.OrgQuotas[i]
+			return &quota, nil
+		}
+	}
+	return nil, notFound("org quota", orgQuotaGUID)
+}
+
+func orgQuota(guid string, req cfclient.OrgQuotaRequest) cfclient.OrgQuota {
+	return cfclient.OrgQuota{
+		Guid:                    guid,
+		Name:                    req.Name,
+		NonBasicServicesAllowed: req.NonBasicServicesAllowed,
+		TotalServices:           req.TotalServices,
+		TotalRoutes:             req.TotalRoutes,
+		TotalPrivateDomains:     req.TotalPrivateDomains,
+		MemoryLimit:             req.MemoryLimit,
+		TrialDBAllowed:          req.TrialDBAllowed,
+		InstanceMemoryLimit:     req.InstanceMemoryLimit,
+		AppInstanceLimit:        req.AppInstanceLimit,
+		AppTaskLimit:            req.AppTaskLimit,
+		TotalServiceKeys:        req.TotalServiceKeys,
+		TotalReservedRoutePorts: req.TotalReservedRoutePorts,
+	}
+}
+
+//ListOrgSpaceQuotas -
+func (f *Foundation) ListOrgSpaceQuotas(orgGUID string) ([]cfclient.SpaceQuota, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	quotas := []cfclient.SpaceQuota{}
+	for _, quota := range f.state.SpaceQuotas {
+		if quota.OrganizationGuid == orgGUID {
+			quotas = append(quotas, quota)
+		}
+	}
+	return quotas, nil
+}
+
+//GetSpaceQuotaByName -
+func (f *Foundation) GetSpaceQuotaByName(name string) (cfclient.SpaceQuota, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for _, quota := range f.state.SpaceQuotas {
+		if quota.Name == name {
+			return quota, nil
+		}
+	}
+	return cfclient.SpaceQuota{}, fmt.Errorf("Unable to find space quota %s", name)
+}
+
+//CreateSpaceQuota -
+func (f *Foundation) CreateSpaceQuota(req cfclient.SpaceQuotaRequest) (*cfclient.SpaceQuota, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, err := f.org(req.OrganizationGuid); err != nil {
+		return nil, err
+	}
+	for _, quota := range f.state.SpaceQuotas {
+		if quota.OrganizationGuid == req.OrganizationGuid && quota.Name == req.Name {
+			return nil, fmt.Errorf("space quota name [%s] is taken", req.Name)
+		}
+	}
+	quota := spaceQuota(f.newGUID("space-quota"), req)
+	f.state.SpaceQuotas = append(f.state.SpaceQuotas, quota)
+	return &quota, nil
+}
+
+//UpdateSpaceQuota -
+func (f *Foundation) UpdateSpaceQuota(spaceQuotaGUID string, req cfclient.SpaceQuotaRequest) (*cfclient.SpaceQuota, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for i := range f.state.SpaceQuotas {
+		if f.state.SpaceQuotas[i].Guid == spaceQuotaGUID {
+			if req.OrganizationGuid == "" {
+				req.OrganizationGuid = f.state.SpaceQuotas[i].OrganizationGuid
+			}
+			f.state.SpaceQuotas[i] = spaceQuota(spaceQuotaGUID, req)
+			quota := f.state.SpaceQuotas[i]
+			return &quota, nil
+		}
+	}
+	return nil, notFound("space quota", spaceQuotaGUID)
+}
+
+//AssignSpaceQuota -
+func (f *Foundation) AssignSpaceQuota(quotaGUID, spaceGUID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	space, err := f.space(spaceGUID)
+	if err != nil {
+		return err
+	}
+	for _, quota := range f.state.SpaceQuotas {
+		if quota.Guid == quotaGUID {
+			space.QuotaDefinitionGuid = quotaGUID
+			return nil
+		}
+	}
+	return notFound("space quota", quotaGUID)
+}
+
+func spaceQuota(guid string, req cfclient.SpaceQuotaRequest) cfclient.SpaceQuota {
+	return cfclient.SpaceQuota{
+		Guid:                    guid,
+		Name:                    req.Name,
+		OrganizationGuid:        req.OrganizationGuid,
+		NonBasicServicesAllowed: req.NonBasicServicesAllowed,
+		TotalServices:           req.TotalServices,
+		TotalRoutes:             req.TotalRoutes,
+		MemoryLimit:             req.MemoryLimit,
+		InstanceMemoryLimit:     req.InstanceMemoryLimit,
+		AppInstanceLimit:        req.AppInstanceLimit,
+		AppTaskLimit:            req.AppTaskLimit,
+		TotalServiceKeys:        req.TotalServiceKeys,
+		TotalReservedRoutePorts: req.TotalReservedRoutePorts,
+	}
+}
@@ -0,0 +1,64 @@
+package simulator
+
+import (
+	"net/url"
+	"strings"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+)
+
+//ListSharedDomains - lists the shared domains of the foundation
+func (f *Foundation) ListSharedDomains() ([]cfclient.SharedDomain, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]cfclient.SharedDomain{}, f.state.PlatformDomains...), nil
+}
+
+//ListRoutesByQuery - lists the routes, filtered by a domain_guid, space_guid or organization_guid query
+func (f *Foundation) ListRoutesByQuery(query url.Values) ([]cfclient.Route, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	domainGUID, spaceGUID, orgGUID := "", "", ""
+	for _, q := range query["q"] {
+		if strings.HasPrefix(q, "organization_guid:") {
+			orgGUID = strings.TrimPrefix(q, "organization_guid:")
+		}
+		if strings.HasPrefix(q, "domain_guid:") {
+			domainGUID = strings.TrimPrefix(q, "domain_guid:")
+		}
+		if strings.HasPrefix(q, "space_guid:") {
+			spaceGUID = strings.TrimPrefix(q, "space_guid:")
+		}
+	}
+	routes := []cfclient.Route{}
+	for _, route := range f.state.Routes {
+		if (domainGUID != "" && route.DomainGuid != domainGUID) || (spaceGUID != "" && route.SpaceGuid != spaceGUID) {
+			continue
+		}
+		if orgGUID != "" {
+			space, err := f.space(route.SpaceGuid)
+			if err != nil || space.OrganizationGuid != orgGUID {
+				continue
+			}
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+//DeleteRoute -
+func (f *Foundation) DeleteRoute(guid string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	routes := []cfclient.Route{}
+	for _, route := range f.state.Routes {
+		if route.Guid != guid {
+			routes = append(routes, route)
+		}
+	}
+	if len(routes) == len(f.state.Routes) {
+		return notFound("route", guid)
+	}
+	f.state.Routes = routes
+	return nil
+}
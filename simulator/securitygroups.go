@@ -0,0 +1,190 @@
+package simulator
+
+import (
+	"fmt"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+)
+
+func (f *Foundation) secGroup(guid string) (*cfclient.SecGroup, error) {
+	for i := range f.state.SecurityGroups {
+		if f.state.SecurityGroups[i].Guid == guid {
+			return &f.state.SecurityGroups[i], nil
+		}
+	}
+	return nil, notFound("security group", guid)
+}
+
+func spaceResourceGUID(resource cfclient.SpaceResource) string {
+	if resource.Meta.Guid != "" {
+		return resource.Meta.Guid
+	}
+	return resource.Entity.Guid
+}
+
+func containsSpaceResource(resources []cfclient.SpaceResource, spaceGUID string) bool {
+	for _, resource := range resources {
+		if spaceResourceGUID(resource) == spaceGUID {
+			return true
+		}
+	}
+	return false
+}
+
+func removeSpaceResource(resources []cfclient.SpaceResource, spaceGUID string) []cfclient.SpaceResource {
+	result := []cfclient.SpaceResource{}
+	for _, resource := range resources {
+		if spaceResourceGUID(resource) != spaceGUID {
+			result = append(result, resource)
+		}
+	}
+	return result
+}
+
+func (f *Foundation) bindSecGroup(secGUID, spaceGUID string, staging bool) error {
+	sg, err := f.secGroup(secGUID)
+	if err != nil {
+		return err
+	}
+	space, err := f.space(spaceGUID)
+	if err != nil {
+		return err
+	}
+	resource := cfclient.SpaceResource{Meta: cfclient.Meta{Guid: space.Guid}, Entity: *space}
+	if staging {
+		if !containsSpaceResource(sg.StagingSpacesData, spaceGUID) {
+			sg.StagingSpacesData = append(sg.StagingSpacesData, resource)
+		}
+	} else if !containsSpaceResource(sg.SpacesData, spaceGUID) {
+		sg.SpacesData = append(sg.SpacesData, resource)
+	}
+	return nil
+}
+
+//ListSecGroups -
+func (f *Foundation) ListSecGroups() ([]cfclient.SecGroup, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]cfclient.SecGroup{}, f.state.SecurityGroups...), nil
+}
+
+//GetSecGroup -
+func (f *Foundation) GetSecGroup(guid string) (*cfclient.SecGroup, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	sg, err := f.secGroup(guid)
+	if err != nil {
+		return nil, err
+	}
+	result := *sg
+	return &result, nil
+}
+
+//ListSpaceSecGroups - lists the security groups bound to the space for running apps
+func (f *Foundation) ListSpaceSecGroups(spaceGUID string) ([]cfclient.SecGroup, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	secGroups := []cfclient.SecGroup{}
+	for _, sg := range f.state.SecurityGroups {
+		if containsSpaceResource(sg.SpacesData, spaceGUID) {
+			secGroups = append(secGroups, sg)
+		}
+	}
+	return secGroups, nil
+}
+
+//CreateSecGroup -
+func (f *Foundation) CreateSecGroup(name string, rules []cfclient.SecGroupRule, spaceGuids []string) (*cfclient.SecGroup, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for _, sg := range f.state.SecurityGroups {
+		if sg.Name == name {
+			return nil, fmt.Errorf("security group name [%s] is taken", name)
+		}
+	}
+	sg := cfclient.SecGroup{
+		Guid:  f.newGUID("security-group"),
+		Name:  name,
+		Rules: rules,
+	}
+	f.state.SecurityGroups = append(f.state.SecurityGroups, sg)
+	for _, spaceGUID := range spaceGuids {
+		if err := f.bindSecGroup(sg.Guid, spaceGUID, false); err != nil {
+			return nil, err
+		}
+	}
+	created, _ := f.secGroup(sg.Guid)
+	result := *created
+	return &result, nil
+}
+
+//UpdateSecGroup - replaces the name, rules and, when given, the bound spaces
+func (f *Foundation) UpdateSecGroup(guid, name string, rules []cfclient.SecGroupRule, spaceGuids []string) (*cfclient.SecGroup, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	sg, err := f.secGroup(guid)
+	if err != nil {
+		return nil, err
+	}
+	sg.Name = name
+	sg.Rules = rules
+	if spaceGuids != nil {
+		sg.SpacesData = []cfclient.SpaceResource{}
+		for _, spaceGUID := range spaceGuids {
+			if err := f.bindSecGroup(guid, spaceGUID, false); err != nil {
+				return nil, err
+			}
+		}
+	}
+	result := *sg
+	return &result, nil
+}
+
+//BindSecGroup -
+func (f *Foundation) BindSecGroup(secGUID, spaceGUID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.bindSecGroup(secGUID, spaceGUID, false)
+}
+
+//BindStagingSecGroupToSpace -
+func (f *Foundation) BindStagingSecGroupToSpace(secGUID, spaceGUID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.bindSecGroup(secGUID, spaceGUID, true)
+}
+
+func (f *Foundation) setDefault(secGUID string, running, value bool) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	sg, err := f.secGroup(secGUID)
+	if err != nil {
+		return err
+	}
+	if running {
+		sg.Running = value
+	} else {
+		sg.Staging = value
+	}
+	return nil
+}
+
+//BindRunningSecGroup -
+func (f *Foundation) BindRunningSecGroup(secGUID string) error {
+	return f.setDefault(secGUID, true, true)
+}
+
+//BindStagingSecGroup -
+func (f *Foundation) BindStagingSecGroup(secGUID string) error {
+	return f.setDefault(secGUID, false, true)
+}
+
+//UnbindRunningSecGroup -
+func (f *Foundation) UnbindRunningSecGroup(secGUID string) error {
+	return f.setDefault(secGUID, true, false)
+}
+
+//UnbindStagingSecGroup -
+func (f *Foundation) UnbindStagingSecGroup(secGUID string) error {
+	return f.setDefault(secGUID, false, false)
+}
@@ -0,0 +1,154 @@
+package simulator
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+)
+
+//ListServiceBrokers - lists the service brokers of the marketplace
+func (f *Foundation) ListServiceBrokers() ([]cfclient.ServiceBroker, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]cfclient.ServiceBroker{}, f.state.ServiceBrokers...), nil
+}
+
+//ListServices - lists the services of the marketplace
+func (f *Foundation) ListServices() ([]cfclient.Service, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]cfclient.Service{}, f.state.Services...), nil
+}
+
+//ListServicePlans - lists the plans of the services of the marketplace
+func (f *Foundation) ListServicePlans() ([]cfclient.ServicePlan, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]cfclient.ServicePlan{}, f.state.ServicePlans...), nil
+}
+
+//ListServicePlanVisibilities - lists the orgs the plans that are not public are visible to
+func (f *Foundation) ListServicePlanVisibilities() ([]cfclient.ServicePlanVisibility, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]cfclient.ServicePlanVisibility{}, f.state.ServicePlanVisibilities...), nil
+}
+
+//ListServiceInstancesByQuery - lists the service instances, filtered by an organization_guid or space_guid query
+func (f *Foundation) ListServiceInstancesByQuery(query url.Values) ([]cfclient.ServiceInstance, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	orgGUID, spaceGUID := "", ""
+	for _, q := range query["q"] {
+		if strings.HasPrefix(q, "organization_guid:") {
+			orgGUID = strings.TrimPrefix(q, "organization_guid:")
+		}
+		if strings.HasPrefix(q, "space_guid:") {
+			spaceGUID = strings.TrimPrefix(q, "space_guid:")
+		}
+	}
+	instances := []cfclient.ServiceInstance{}
+	for _, instance := range f.state.ServiceInstances {
+		if spaceGUID != "" && instance.SpaceGuid != spaceGUID {
+			continue
+		}
+		if orgGUID != "" {
+			space, err := f.space(instance.SpaceGuid)
+			if err != nil || space.OrganizationGuid != orgGUID {
+				continue
+			}
+		}
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
+
+// serviceInstanceGUIDs are the guids of a service_instance_guid or
+// service_instance_guid IN query, nil without one
+func serviceInstanceGUIDs(query url.Values) []string {
+	for _, q := range query["q"] {
+		if strings.HasPrefix(q, "service_instance_guid:") {
+			return []string{strings.TrimPrefix(q, "service_instance_guid:")}
+		}
+		if strings.HasPrefix(q, "service_instance_guid IN ") {
+			return strings.Split(strings.TrimPrefix(q, "service_instance_guid IN "), ",")
+		}
+	}
+	return nil
+}
+
+//ListServiceBindingsByQuery - lists the service bindings, filtered by a service_instance_guid query
+func (f *Foundation) ListServiceBindingsByQuery(query url.Values) ([]cfclient.ServiceBinding, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	instanceGUIDs := serviceInstanceGUIDs(query)
+	bindings := []cfclient.ServiceBinding{}
+	for _, binding := range f.state.ServiceBindings {
+		if instanceGUIDs == nil || contains(instanceGUIDs, binding.ServiceInstanceGuid) {
+			bindings = append(bindings, binding)
+		}
+	}
+	return bindings, nil
+}
+
+//ListServiceKeysByQuery - lists the service keys, filtered by a service_instance_guid query
+func (f *Foundation) ListServiceKeysByQuery(query url.Values) ([]cfclient.ServiceKey, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	instanceGUIDs := serviceInstanceGUIDs(query)
+	keys := []cfclient.ServiceKey{}
+	for _, key := range f.state.ServiceKeys {
+		if instanceGUIDs == nil || contains(instanceGUIDs, key.ServiceInstanceGuid) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+//CreateServiceKey - creates a key of a service instance with simulated credentials
+func (f *Foundation) CreateServiceKey(req cfclient.CreateServiceKeyRequest) (cfclient.ServiceKey, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	found := false
+	for _, instance := range f.state.ServiceInstances {
+		found = found || instance.Guid == req.ServiceInstanceGuid
+	}
+	if !found {
+		return cfclient.ServiceKey{}, notFound("service instance", req.ServiceInstanceGuid)
+	}
+	for _, key := range f.state.ServiceKeys {
+		if key.ServiceInstanceGuid == req.ServiceInstanceGuid && key.Name == req.Name {
+			return cfclient.ServiceKey{}, fmt.Errorf("service key name [%s] is taken for service instance [%s]", req.Name, req.ServiceInstanceGuid)
+		}
+	}
+	guid := f.newGUID("service-key")
+	key := cfclient.ServiceKey{
+		Name:                req.Name,
+		Guid:                guid,
+		CreatedAt:           time.Now().UTC().Format(time.RFC3339),
+		ServiceInstanceGuid: req.ServiceInstanceGuid,
+		Credentials:         map[string]interface{}{"username": guid, "password": "simulated"},
+	}
+	f.state.ServiceKeys = append(f.state.ServiceKeys, key)
+	return key, nil
+}
+
+//DeleteServiceKey -
+func (f *Foundation) DeleteServiceKey(guid string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	keys := []cfclient.ServiceKey{}
+	for _, key := range f.state.ServiceKeys {
+		if key.Guid != guid {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == len(f.state.ServiceKeys) {
+		return notFound("service key", guid)
+	}
+	f.state.ServiceKeys = keys
+	return nil
+}
@@ -0,0 +1,90 @@
+package simulator
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	uaaclient "github.com/cloudfoundry-community/go-uaa"
+	"github.com/pivotalservices/cf-mgmt/uaa"
+	"github.com/pkg/errors"
+)
+
+//Role names used as keys of Roles
+const (
+	RoleUsers           = "users"
+	RoleManagers        = "managers"
+	RoleBillingManagers = "billing_managers"
+	RoleAuditors        = "auditors"
+	RoleDevelopers      = "developers"
+)
+
+// Roles maps a role name to the guids of the users holding it.
+type Roles map[string][]string
+
+// Snapshot is the state of a foundation. Entities use the same json
+// representation as the cloud controller and uaa clients.
+type Snapshot struct {
+	Orgs           []cfclient.Org        `json:"orgs"`
+	Spaces         []cfclient.Space      `json:"spaces"`
+	Users          []cfclient.User       `json:"users"`
+	OrgQuotas      []cfclient.OrgQuota   `json:"org_quotas"`
+	SpaceQuotas    []cfclient.SpaceQuota `json:"space_quotas"`
+	Domains        []cfclient.Domain     `json:"domains"`
+	SecurityGroups []cfclient.SecGroup   `json:"security_groups"`
+	// SharedDomains is keyed by org guid and lists the private domains shared with that org.
+	SharedDomains map[string][]string `json:"shared_domains"`
+	// PlatformDomains are the shared domains of the foundation, such as apps.internal.
+	PlatformDomains []cfclient.SharedDomain `json:"platform_domains,omitempty"`
+	Routes          []cfclient.Route        `json:"routes,omitempty"`
+	Apps            []cfclient.App          `json:"apps,omitempty"`
+	Stacks          []cfclient.Stack        `json:"stacks,omitempty"`
+	// ServiceBrokers, Services, ServicePlans and ServicePlanVisibilities are
+	// the marketplace, ServiceInstances the service instances of the spaces,
+	// with their ServiceBindings and ServiceKeys.
+	ServiceBrokers          []cfclient.ServiceBroker         `json:"service_brokers,omitempty"`
+	Services                []cfclient.Service               `json:"services,omitempty"`
+	ServicePlans            []cfclient.ServicePlan           `json:"service_plans,omitempty"`
+	ServicePlanVisibilities []cfclient.ServicePlanVisibility `json:"service_plan_visibilities,omitempty"`
+	ServiceInstances        []cfclient.ServiceInstance       `json:"service_instances,omitempty"`
+	ServiceBindings         []cfclient.ServiceBinding        `json:"service_bindings,omitempty"`
+	ServiceKeys             []cfclient.ServiceKey            `json:"service_keys,omitempty"`
+	// Tasks is keyed by space guid and lists the tasks run in that space.
+	Tasks             map[string][]cfclient.Task  `json:"tasks,omitempty"`
+	Events            []cfclient.Event            `json:"events,omitempty"`
+	IsolationSegments []cfclient.IsolationSegment `json:"isolation_segments"`
+	// Entitlements is keyed by isolation segment guid and lists the entitled org guids.
+	Entitlements         map[string][]string    `json:"isolation_segment_entitlements"`
+	OrgRoles             map[string]Roles       `json:"org_roles"`
+	SpaceRoles           map[string]Roles       `json:"space_roles"`
+	UAAUsers             []uaaclient.User       `json:"uaa_users"`
+	UAAGroups            []uaaclient.Group      `json:"uaa_groups,omitempty"`
+	UAAIdentityProviders []uaa.IdentityProvider `json:"uaa_identity_providers,omitempty"`
+	UAATokenPolicy       *uaa.TokenPolicy       `json:"uaa_token_policy,omitempty"`
+	// OrgLabels is keyed by org guid and holds the metadata labels of that org.
+	OrgLabels map[string]map[string]string `json:"org_labels,omitempty"`
+	// Annotations is keyed by org or space guid and holds the metadata annotations of that org or space.
+	Annotations map[string]map[string]string `json:"annotations,omitempty"`
+}
+
+//LoadSnapshot - reads a json snapshot file
+func LoadSnapshot(path string) (*Snapshot, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read snapshot %s", path)
+	}
+	snapshot := &Snapshot{}
+	if err = json.Unmarshal(bytes, snapshot); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse snapshot %s", path)
+	}
+	return snapshot, nil
+}
+
+//WriteSnapshot - writes the snapshot as indented json
+func WriteSnapshot(path string, snapshot *Snapshot) error {
+	bytes, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, bytes, 0644)
+}
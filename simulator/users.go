@@ -0,0 +1,478 @@
+package simulator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	uaaclient "github.com/cloudfoundry-community/go-uaa"
+	"github.com/pivotalservices/cf-mgmt/uaa"
+)
+
+//UAAManager - uaa manager backed by the simulated uaa users
+func (f *Foundation) UAAManager(peek bool) uaa.Manager {
+	return &uaa.DefaultUAAManager{Client: uaaUsers{f}, Peek: peek}
+}
+
+// uaaUsers is the uaa client of the foundation, DeleteUser deletes the uaa
+// user where the cloud controller DeleteUser of the foundation does not.
+type uaaUsers struct {
+	*Foundation
+}
+
+//DeleteUser - deletes a uaa user and its cloud controller user
+func (u uaaUsers) DeleteUser(userID string) (*uaaclient.User, error) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	var deleted *uaaclient.User
+	uaaUsers := []uaaclient.User{}
+	for i, existing := range u.state.UAAUsers {
+		if existing.ID == userID {
+			deleted = &u.state.UAAUsers[i]
+			continue
+		}
+		uaaUsers = append(uaaUsers, existing)
+	}
+	if deleted == nil {
+		return nil, notFound("user", userID)
+	}
+	u.state.UAAUsers = uaaUsers
+	u.deleteUser(userID)
+	return deleted, nil
+}
+
+//DeleteUser - deletes the cloud controller user and its roles, the uaa user is kept
+func (f *Foundation) DeleteUser(userGUID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, err := f.cfUser(userGUID); err != nil {
+		return err
+	}
+	f.deleteUser(userGUID)
+	return nil
+}
+
+func (f *Foundation) deleteUser(userGUID string) {
+	users := []cfclient.User{}
+	for _, user := range f.state.Users {
+		if user.Guid != userGUID {
+			users = append(users, user)
+		}
+	}
+	f.state.Users = users
+	for _, roles := range []map[string]Roles{f.state.OrgRoles, f.state.SpaceRoles} {
+		for _, role := range roles {
+			for name, userGUIDs := range role {
+				role[name] = remove(userGUIDs, userGUID)
+			}
+		}
+	}
+}
+
+// filterClause matches the userName, externalId and origin eq clauses of a scim filter
+var filterClause = regexp.MustCompile(`(userName|externalId|origin) eq "((?:[^"\\]|\\.)*)"`)
+
+// filterUnescaper unescapes the values of scim filter clauses
+var filterUnescaper = strings.NewReplacer(`\"`, `"`, `\\`, `\`)
+
+//ListUsers - lists a page of uaa users, filters other than userName, externalId and origin eq
+//clauses and attributes are ignored
+func (f *Foundation) ListUsers(filter string, sortBy string, attributes string, sortOrder uaaclient.SortOrder, startIndex int, itemsPerPage int) ([]uaaclient.User, uaaclient.Page, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	names := make(map[string]bool)
+	origins := make(map[string]bool)
+	for _, clause := range filterClause.FindAllStringSubmatch(filter, -1) {
+		value := filterUnescaper.Replace(clause[2])
+		if clause[1] == "origin" {
+			origins[value] = true
+		} else {
+			names[strings.ToLower(value)] = true
+		}
+	}
+	users := []uaaclient.User{}
+	for _, user := range f.state.UAAUsers {
+		if len(origins) > 0 && !origins[user.Origin] {
+			continue
+		}
+		if len(names) > 0 && !names[strings.ToLower(user.Username)] && !names[strings.ToLower(user.ExternalID)] {
+			continue
+		}
+		users = append(users, user)
+	}
+	page := uaaclient.Page{StartIndex: startIndex, TotalResults: len(users)}
+	from, to := startIndex-1, startIndex-1+itemsPerPage
+	if from > len(users) {
+		from = len(users)
+	}
+	if to > len(users) {
+		to = len(users)
+	}
+	page.ItemsPerPage = to - from
+	return users[from:to], page, nil
+}
+
+//GetUser - gets a uaa user by id
+func (f *Foundation) GetUser(userID string) (*uaaclient.User, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for _, user := range f.state.UAAUsers {
+		if user.ID == userID {
+			return &user, nil
+		}
+	}
+	return nil, notFound("user", userID)
+}
+
+//CreateUser - creates a uaa user
+func (f *Foundation) CreateUser(user uaaclient.User) (*uaaclient.User, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for _, existing := range f.state.UAAUsers {
+		if strings.EqualFold(existing.Username, user.Username) && existing.Origin == user.Origin {
+			return nil, fmt.Errorf("user [%s] already exists", user.Username)
+		}
+	}
+	user.ID = f.newGUID("user")
+	f.state.UAAUsers = append(f.state.UAAUsers, user)
+	return &user, nil
+}
+
+//UpdateUser - updates a uaa user
+func (f *Foundation) UpdateUser(user uaaclient.User) (*uaaclient.User, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	index := -1
+	for i, existing := range f.state.UAAUsers {
+		if existing.ID == user.ID {
+			index = i
+		} else if strings.EqualFold(existing.Username, user.Username) && existing.Origin == user.Origin {
+			return nil, fmt.Errorf("user [%s] already exists", user.Username)
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("user [%s] not found", user.ID)
+	}
+	f.state.UAAUsers[index] = user
+	return &user, nil
+}
+
+// cfUser returns the cloud controller user, creating it from the uaa user
+// with the same id the first time it is referenced like the cloud controller does.
+func (f *Foundation) cfUser(guid string) (cfclient.User, error) {
+	for _, user := range f.state.Users {
+		if user.Guid == guid {
+			return user, nil
+		}
+	}
+	for _, uaaUser := range f.state.UAAUsers {
+		if uaaUser.ID == guid {
+			user := cfclient.User{Guid: guid, Username: uaaUser.Username, Active: true}
+			f.state.Users = append(f.state.Users, user)
+			return user, nil
+		}
+	}
+	return cfclient.User{}, notFound("user", guid)
+}
+
+func (f *Foundation) userGUIDByName(userName string) (string, error) {
+	for _, user := range f.state.Users {
+		if strings.EqualFold(user.Username, userName) {
+			return user.Guid, nil
+		}
+	}
+	for _, uaaUser := range f.state.UAAUsers {
+		if strings.EqualFold(uaaUser.Username, userName) {
+			if _, err := f.cfUser(uaaUser.ID); err != nil {
+				return "", err
+			}
+			return uaaUser.ID, nil
+		}
+	}
+	return "", fmt.Errorf("user [%s] not found", userName)
+}
+
+func (f *Foundation) roleUsers(roles map[string]Roles, guid, role string) []cfclient.User {
+	users := []cfclient.User{}
+	for _, userGUID := range roles[guid][role] {
+		if user, err := f.cfUser(userGUID); err == nil {
+			users = append(users, user)
+		}
+	}
+	return users
+}
+
+func grant(roles map[string]Roles, guid, role, userGUID string) {
+	if roles[guid] == nil {
+		roles[guid] = Roles{}
+	}
+	roles[guid][role] = appendUnique(roles[guid][role], userGUID)
+}
+
+func revoke(roles map[string]Roles, guid, role, userGUID string) {
+	if roles[guid] != nil {
+		roles[guid][role] = remove(roles[guid][role], userGUID)
+	}
+}
+
+func (f *Foundation) listOrgRole(orgGUID, role string) ([]cfclient.User, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, err := f.org(orgGUID); err != nil {
+		return nil, err
+	}
+	return f.roleUsers(f.state.OrgRoles, orgGUID, role), nil
+}
+
+func (f *Foundation) associateOrg(orgGUID, userGUID, userName, role string) (cfclient.Org, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	org, err := f.org(orgGUID)
+	if err != nil {
+		return cfclient.Org{}, err
+	}
+	if userName != "" {
+		if userGUID, err = f.userGUIDByName(userName); err != nil {
+			return cfclient.Org{}, err
+		}
+	} else if _, err = f.cfUser(userGUID); err != nil {
+		return cfclient.Org{}, err
+	}
+	grant(f.state.OrgRoles, orgGUID, role, userGUID)
+	return *org, nil
+}
+
+func (f *Foundation) removeOrg(orgGUID, userGUID, userName, role string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, err := f.org(orgGUID); err != nil {
+		return err
+	}
+	if userName != "" {
+		var err error
+		if userGUID, err = f.userGUIDByName(userName); err != nil {
+			return err
+		}
+	}
+	revoke(f.state.OrgRoles, orgGUID, role, userGUID)
+	return nil
+}
+
+func (f *Foundation) listSpaceRole(spaceGUID, role string) ([]cfclient.User, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, err := f.space(spaceGUID); err != nil {
+		return nil, err
+	}
+	return f.roleUsers(f.state.SpaceRoles, spaceGUID, role), nil
+}
+
+func (f *Foundation) associateSpace(spaceGUID, userGUID, userName, role string) (cfclient.Space, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	space, err := f.space(spaceGUID)
+	if err != nil {
+		return cfclient.Space{}, err
+	}
+	if userName != "" {
+		if userGUID, err = f.userGUIDByName(userName); err != nil {
+			return cfclient.Space{}, err
+		}
+	} else if _, err = f.cfUser(userGUID); err != nil {
+		return cfclient.Space{}, err
+	}
+	grant(f.state.SpaceRoles, spaceGUID, role, userGUID)
+	return *space, nil
+}
+
+func (f *Foundation) removeSpace(spaceGUID, userGUID, userName, role string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, err := f.space(spaceGUID); err != nil {
+		return err
+	}
+	if userName != "" {
+		var err error
+		if userGUID, err = f.userGUIDByName(userName); err != nil {
+			return err
+		}
+	}
+	revoke(f.state.SpaceRoles, spaceGUID, role, userGUID)
+	return nil
+}
+
+//ListOrgUsers -
+func (f *Foundation) ListOrgUsers(orgGUID string) ([]cfclient.User, error) {
+	return f.listOrgRole(orgGUID, RoleUsers)
+}
+
+//ListOrgAuditors -
+func (f *Foundation) ListOrgAuditors(orgGUID string) ([]cfclient.User, error) {
+	return f.listOrgRole(orgGUID, RoleAuditors)
+}
+
+//ListOrgManagers -
+func (f *Foundation) ListOrgManagers(orgGUID string) ([]cfclient.User, error) {
+	return f.listOrgRole(orgGUID, RoleManagers)
+}
+
+//ListOrgBillingManagers -
+func (f *Foundation) ListOrgBillingManagers(orgGUID string) ([]cfclient.User, error) {
+	return f.listOrgRole(orgGUID, RoleBillingManagers)
+}
+
+//AssociateOrgUserByUsername -
+func (f *Foundation) AssociateOrgUserByUsername(orgGUID, userName string) (cfclient.Org, error) {
+	return f.associateOrg(orgGUID, "", userName, RoleUsers)
+}
+
+//AssociateOrgAuditorByUsername -
+func (f *Foundation) AssociateOrgAuditorByUsername(orgGUID, name string) (cfclient.Org, error) {
+	return f.associateOrg(orgGUID, "", name, RoleAuditors)
+}
+
+//AssociateOrgManagerByUsername -
+func (f *Foundation) AssociateOrgManagerByUsername(orgGUID, name string) (cfclient.Org, error) {
+	return f.associateOrg(orgGUID, "", name, RoleManagers)
+}
+
+//AssociateOrgBillingManagerByUsername -
+func (f *Foundation) AssociateOrgBillingManagerByUsername(orgGUID, name string) (cfclient.Org, error) {
+	return f.associateOrg(orgGUID, "", name, RoleBillingManagers)
+}
+
+//RemoveOrgUserByUsername -
+func (f *Foundation) RemoveOrgUserByUsername(orgGUID, name string) error {
+	return f.removeOrg(orgGUID, "", name, RoleUsers)
+}
+
+//RemoveOrgAuditorByUsername -
+func (f *Foundation) RemoveOrgAuditorByUsername(orgGUID, name string) error {
+	return f.removeOrg(orgGUID, "", name, RoleAuditors)
+}
+
+//RemoveOrgBillingManagerByUsername -
+func (f *Foundation) RemoveOrgBillingManagerByUsername(orgGUID, name string) error {
+	return f.removeOrg(orgGUID, "", name, RoleBillingManagers)
+}
+
+//RemoveOrgManagerByUsername -
+func (f *Foundation) RemoveOrgManagerByUsername(orgGUID, name string) error {
+	return f.removeOrg(orgGUID, "", name, RoleManagers)
+}
+
+//AssociateOrgUser -
+func (f *Foundation) AssociateOrgUser(orgGUID, userGUID string) (cfclient.Org, error) {
+	return f.associateOrg(orgGUID, userGUID, "", RoleUsers)
+}
+
+//AssociateOrgAuditor -
+func (f *Foundation) AssociateOrgAuditor(orgGUID, userGUID string) (cfclient.Org, error) {
+	return f.associateOrg(orgGUID, userGUID, "", RoleAuditors)
+}
+
+//AssociateOrgManager -
+func (f *Foundation) AssociateOrgManager(orgGUID, userGUID string) (cfclient.Org, error) {
+	return f.associateOrg(orgGUID, userGUID, "", RoleManagers)
+}
+
+//AssociateOrgBillingManager -
+func (f *Foundation) AssociateOrgBillingManager(orgGUID, userGUID string) (cfclient.Org, error) {
+	return f.associateOrg(orgGUID, userGUID, "", RoleBillingManagers)
+}
+
+//RemoveOrgUser -
+func (f *Foundation) RemoveOrgUser(orgGUID, userGUID string) error {
+	return f.removeOrg(orgGUID, userGUID, "", RoleUsers)
+}
+
+//RemoveOrgAuditor -
+func (f *Foundation) RemoveOrgAuditor(orgGUID, userGUID string) error {
+	return f.removeOrg(orgGUID, userGUID, "", RoleAuditors)
+}
+
+//RemoveOrgManager -
+func (f *Foundation) RemoveOrgManager(orgGUID, userGUID string) error {
+	return f.removeOrg(orgGUID, userGUID, "", RoleManagers)
+}
+
+//RemoveOrgBillingManager -
+func (f *Foundation) RemoveOrgBillingManager(orgGUID, userGUID string) error {
+	return f.removeOrg(orgGUID, userGUID, "", RoleBillingManagers)
+}
+
+//ListSpaceAuditors -
+func (f *Foundation) ListSpaceAuditors(spaceGUID string) ([]cfclient.User, error) {
+	return f.listSpaceRole(spaceGUID, RoleAuditors)
+}
+
+//ListSpaceManagers -
+func (f *Foundation) ListSpaceManagers(spaceGUID string) ([]cfclient.User, error) {
+	return f.listSpaceRole(spaceGUID, RoleManagers)
+}
+
+//ListSpaceDevelopers -
+func (f *Foundation) ListSpaceDevelopers(spaceGUID string) ([]cfclient.User, error) {
+	return f.listSpaceRole(spaceGUID, RoleDevelopers)
+}
+
+//AssociateSpaceAuditorByUsername -
+func (f *Foundation) AssociateSpaceAuditorByUsername(spaceGUID, userName string) (cfclient.Space, error) {
+	return f.associateSpace(spaceGUID, "", userName, RoleAuditors)
+}
+
+//AssociateSpaceDeveloperByUsername -
+func (f *Foundation) AssociateSpaceDeveloperByUsername(spaceGUID, userName string) (cfclient.Space, error) {
+	return f.associateSpace(spaceGUID, "", userName, RoleDevelopers)
+}
+
+//AssociateSpaceManagerByUsername -
+func (f *Foundation) AssociateSpaceManagerByUsername(spaceGUID, userName string) (cfclient.Space, error) {
+	return f.associateSpace(spaceGUID, "", userName, RoleManagers)
+}
+
+//RemoveSpaceAuditorByUsername -
+func (f *Foundation) RemoveSpaceAuditorByUsername(spaceGUID, userName string) error {
+	return f.removeSpace(spaceGUID, "", userName, RoleAuditors)
+}
+
+//RemoveSpaceDeveloperByUsername -
+func (f *Foundation) RemoveSpaceDeveloperByUsername(spaceGUID, userName string) error {
+	return f.removeSpace(spaceGUID, "", userName, RoleDevelopers)
+}
+
+//RemoveSpaceManagerByUsername -
+func (f *Foundation) RemoveSpaceManagerByUsername(spaceGUID, userName string) error {
+	return f.removeSpace(spaceGUID, "", userName, RoleManagers)
+}
+
+//AssociateSpaceAuditor -
+func (f *Foundation) AssociateSpaceAuditor(spaceGUID, userGUID string) (cfclient.Space, error) {
+	return f.associateSpace(spaceGUID, userGUID, "", RoleAuditors)
+}
+
+//AssociateSpaceDeveloper -
+func (f *Foundation) AssociateSpaceDeveloper(spaceGUID, userGUID string) (cfclient.Space, error) {
+	return f.associateSpace(spaceGUID, userGUID, "", RoleDevelopers)
+}
+
+//AssociateSpaceManager -
+func (f *Foundation) AssociateSpaceManager(spaceGUID, userGUID string) (cfclient.Space, error) {
+	return f.associateSpace(spaceGUID, userGUID, "", RoleManagers)
+}
+
+//RemoveSpaceAuditor -
+func (f *Foundation) RemoveSpaceAuditor(spaceGUID, userGUID string) error {
+	return f.removeSpace(spaceGUID, userGUID, "", RoleAuditors)
+}
+
+//RemoveSpaceDeveloper -
+func (f *Foundation) RemoveSpaceDeveloper(spaceGUID, userGUID string) error {
+	return f.removeSpace(spaceGUID, userGUID, "", RoleDevelopers)
+}
+
+//RemoveSpaceManager -
+func (f *Foundation) RemoveSpaceManager(spaceGUID, userGUID string) error {
+	return f.removeSpace(spaceGUID, userGUID, "", RoleManagers)
+}
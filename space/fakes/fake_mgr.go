@@ -46,6 +46,49 @@ type FakeManager struct {
 		result1 []go_cfclient.Space
 		result2 error
 	}
+	MatchSpacesStub        func(orgName, pattern string) ([]go_cfclient.Space, error)
+	matchSpacesMutex       sync.RWMutex
+	matchSpacesArgsForCall []struct {
+		orgName string
+		pattern string
+	}
+	matchSpacesReturns struct {
+		result1 []go_cfclient.Space
+		result2 error
+	}
+	ListUnmanagedSpacesStub        func() ([]space.UnmanagedSpace, error)
+	listUnmanagedSpacesMutex       sync.RWMutex
+	listUnmanagedSpacesArgsForCall []struct{}
+	listUnmanagedSpacesReturns     struct {
+		result1 []space.UnmanagedSpace
+		result2 error
+	}
+	ListManagedSpacesStub        func() ([]space.ManagedSpace, error)
+	listManagedSpacesMutex       sync.RWMutex
+	listManagedSpacesArgsForCall []struct{}
+	listManagedSpacesReturns     struct {
+		result1 []space.ManagedSpace
+		result2 error
+	}
+	RecycleSpacesStub        func(force bool) ([]string, error)
+	recycleSpacesMutex       sync.RWMutex
+	recycleSpacesArgsForCall []struct {
+		force bool
+	}
+	recycleSpacesReturns struct {
+		result1 []string
+		result2 error
+	}
+	CreateSpaceStub        func(spaceName string, orgName string, orgGUID string) error
+	createSpaceMutex       sync.RWMutex
+	createSpaceArgsForCall []struct {
+		spaceName string
+		orgName   string
+		orgGUID   string
+	}
+	createSpaceReturns struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -194,6 +237,162 @@ func (fake *FakeManager) ListSpacesReturns(result1 []go_cfclient.Space, result2
 	}{result1, result2}
 }
 
+func (fake *FakeManager) MatchSpaces(orgName string, pattern string) ([]go_cfclient.Space, error) {
+	fake.matchSpacesMutex.Lock()
+	fake.matchSpacesArgsForCall = append(fake.matchSpacesArgsForCall, struct {
+		orgName string
+		pattern string
+	}{orgName, pattern})
+	fake.recordInvocation("MatchSpaces", []interface{}{orgName, pattern})
+	fake.matchSpacesMutex.Unlock()
+	if fake.MatchSpacesStub != nil {
+		return fake.MatchSpacesStub(orgName, pattern)
+	} else {
+		return fake.matchSpacesReturns.result1, fake.matchSpacesReturns.result2
+	}
+}
+
+func (fake *FakeManager) MatchSpacesCallCount() int {
+	fake.matchSpacesMutex.RLock()
+	defer fake.matchSpacesMutex.RUnlock()
+	return len(fake.matchSpacesArgsForCall)
+}
+
+func (fake *FakeManager) MatchSpacesArgsForCall(i int) (string, string) {
+	fake.matchSpacesMutex.RLock()
+	defer fake.matchSpacesMutex.RUnlock()
+	return fake.matchSpacesArgsForCall[i].orgName, fake.matchSpacesArgsForCall[i].pattern
+}
+
+func (fake *FakeManager) MatchSpacesReturns(result1 []go_cfclient.Space, result2 error) {
+	fake.MatchSpacesStub = nil
+	fake.matchSpacesReturns = struct {
+		result1 []go_cfclient.Space
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) ListUnmanagedSpaces() ([]space.UnmanagedSpace, error) {
+	fake.listUnmanagedSpacesMutex.Lock()
+	fake.listUnmanagedSpacesArgsForCall = append(fake.listUnmanagedSpacesArgsForCall, struct{}{})
+	fake.recordInvocation("ListUnmanagedSpaces", []interface{}{})
+	fake.listUnmanagedSpacesMutex.Unlock()
+	if fake.ListUnmanagedSpacesStub != nil {
+		return fake.ListUnmanagedSpacesStub()
+	} else {
+		return fake.listUnmanagedSpacesReturns.result1, fake.listUnmanagedSpacesReturns.result2
+	}
+}
+
+func (fake *FakeManager) ListUnmanagedSpacesCallCount() int {
+	fake.listUnmanagedSpacesMutex.RLock()
+	defer fake.listUnmanagedSpacesMutex.RUnlock()
+	return len(fake.listUnmanagedSpacesArgsForCall)
+}
+
+func (fake *FakeManager) ListUnmanagedSpacesReturns(result1 []space.UnmanagedSpace, result2 error) {
+	fake.ListUnmanagedSpacesStub = nil
+	fake.listUnmanagedSpacesReturns = struct {
+		result1 []space.UnmanagedSpace
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) ListManagedSpaces() ([]space.ManagedSpace, error) {
+	fake.listManagedSpacesMutex.Lock()
+	fake.listManagedSpacesArgsForCall = append(fake.listManagedSpacesArgsForCall, struct{}{})
+	fake.recordInvocation("ListManagedSpaces", []interface{}{})
+	fake.listManagedSpacesMutex.Unlock()
+	if fake.ListManagedSpacesStub != nil {
+		return fake.ListManagedSpacesStub()
+	} else {
+		return fake.listManagedSpacesReturns.result1, fake.listManagedSpacesReturns.result2
+	}
+}
+
+func (fake *FakeManager) ListManagedSpacesCallCount() int {
+	fake.listManagedSpacesMutex.RLock()
+	defer fake.listManagedSpacesMutex.RUnlock()
+	return len(fake.listManagedSpacesArgsForCall)
+}
+
+func (fake *FakeManager) ListManagedSpacesReturns(result1 []space.ManagedSpace, result2 error) {
+	fake.ListManagedSpacesStub = nil
+	fake.listManagedSpacesReturns = struct {
+		result1 []space.ManagedSpace
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) RecycleSpaces(force bool) ([]string, error) {
+	fake.recycleSpacesMutex.Lock()
+	fake.recycleSpacesArgsForCall = append(fake.recycleSpacesArgsForCall, struct {
+		force bool
+	}{force})
+	fake.recordInvocation("RecycleSpaces", []interface{}{force})
+	fake.recycleSpacesMutex.Unlock()
+	if fake.RecycleSpacesStub != nil {
+		return fake.RecycleSpacesStub(force)
+	} else {
+		return fake.recycleSpacesReturns.result1, fake.recycleSpacesReturns.result2
+	}
+}
+
+func (fake *FakeManager) RecycleSpacesCallCount() int {
+	fake.recycleSpacesMutex.RLock()
+	defer fake.recycleSpacesMutex.RUnlock()
+	return len(fake.recycleSpacesArgsForCall)
+}
+
+func (fake *FakeManager) RecycleSpacesArgsForCall(i int) bool {
+	fake.recycleSpacesMutex.RLock()
+	defer fake.recycleSpacesMutex.RUnlock()
+	return fake.recycleSpacesArgsForCall[i].force
+}
+
+func (fake *FakeManager) RecycleSpacesReturns(result1 []string, result2 error) {
+	fake.RecycleSpacesStub = nil
+	fake.recycleSpacesReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) CreateSpace(spaceName string, orgName string, orgGUID string) error {
+	fake.createSpaceMutex.Lock()
+	fake.createSpaceArgsForCall = append(fake.createSpaceArgsForCall, struct {
+		spaceName string
+		orgName   string
+		orgGUID   string
+	}{spaceName, orgName, orgGUID})
+	fake.recordInvocation("CreateSpace", []interface{}{spaceName, orgName, orgGUID})
+	fake.createSpaceMutex.Unlock()
+	if fake.CreateSpaceStub != nil {
+		return fake.CreateSpaceStub(spaceName, orgName, orgGUID)
+	} else {
+		return fake.createSpaceReturns.result1
+	}
+}
+
+func (fake *FakeManager) CreateSpaceCallCount() int {
+	fake.createSpaceMutex.RLock()
+	defer fake.createSpaceMutex.RUnlock()
+	return len(fake.createSpaceArgsForCall)
+}
+
+func (fake *FakeManager) CreateSpaceArgsForCall(i int) (string, string, string) {
+	fake.createSpaceMutex.RLock()
+	defer fake.createSpaceMutex.RUnlock()
+	return fake.createSpaceArgsForCall[i].spaceName, fake.createSpaceArgsForCall[i].orgName, fake.createSpaceArgsForCall[i].orgGUID
+}
+
+func (fake *FakeManager) CreateSpaceReturns(result1 error) {
+	fake.CreateSpaceStub = nil
+	fake.createSpaceReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeManager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -207,6 +406,16 @@ func (fake *FakeManager) Invocations() map[string][][]interface{} {
 	defer fake.deleteSpacesMutex.RUnlock()
 	fake.listSpacesMutex.RLock()
 	defer fake.listSpacesMutex.RUnlock()
+	fake.matchSpacesMutex.RLock()
+	defer fake.matchSpacesMutex.RUnlock()
+	fake.listUnmanagedSpacesMutex.RLock()
+	defer fake.listUnmanagedSpacesMutex.RUnlock()
+	fake.listManagedSpacesMutex.RLock()
+	defer fake.listManagedSpacesMutex.RUnlock()
+	fake.recycleSpacesMutex.RLock()
+	defer fake.recycleSpacesMutex.RUnlock()
+	fake.createSpaceMutex.RLock()
+	defer fake.createSpaceMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -0,0 +1,107 @@
+package space
+
+import (
+	"path"
+	"strings"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pkg/errors"
+)
+
+//MatchSpaces - lists the existing spaces of an org whose name matches a glob pattern
+func (m *DefaultManager) MatchSpaces(orgName, pattern string) ([]cfclient.Space, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, errors.Wrapf(err, "invalid space pattern [%s] in org [%s]", pattern, orgName)
+	}
+	orgGUID, err := m.OrgMgr.GetOrgGUID(orgName)
+	if err != nil {
+		return nil, err
+	}
+	spaces, err := m.ListSpaces(orgGUID)
+	if err != nil {
+		return nil, err
+	}
+	var matched []cfclient.Space
+	for _, space := range spaces {
+		if match, _ := path.Match(pattern, space.Name); match {
+			matched = append(matched, space)
+		}
+	}
+	return matched, nil
+}
+
+//ExpandSpaceConfigs - replaces every space config whose space is a glob pattern with a
+//copy for each existing space of the org it matches. A space with its own config keeps
+//it and a space matched by several patterns gets the config of the first of them.
+func ExpandSpaceConfigs(mgr Manager, spaceConfigs []config.SpaceConfig) ([]config.SpaceConfig, error) {
+	configured := make(map[string]bool)
+	for _, spaceConfig := range spaceConfigs {
+		if !spaceConfig.IsPattern() {
+			configured[spaceKey(spaceConfig.Org, spaceConfig.Space)] = true
+		}
+	}
+	var result []config.SpaceConfig
+	for _, spaceConfig := range spaceConfigs {
+		if !spaceConfig.IsPattern() {
+			result = append(result, spaceConfig)
+			continue
+		}
+		spaces, err := mgr.MatchSpaces(spaceConfig.Org, spaceConfig.Space)
+		if err != nil {
+			return nil, err
+		}
+		for _, space := range spaces {
+			key := spaceKey(spaceConfig.Org, space.Name)
+			if configured[key] {
+				continue
+			}
+			configured[key] = true
+			expanded := spaceConfig
+			expanded.Space = space.Name
+			expanded.Pattern = spaceConfig.Space
+			result = append(result, expanded)
+		}
+	}
+	return result, nil
+}
+
+//ListManagedSpaces - lists the existing spaces of every configured org, with the config of the space, or of
+//the space pattern matching it, so that policies can also cover the spaces that are not in the configuration
+func (m *DefaultManager) ListManagedSpaces() ([]ManagedSpace, error) {
+	spaceConfigs, err := m.Cfg.GetSpaceConfigs()
+	if err != nil {
+		return nil, err
+	}
+	spaceConfigs, err = ExpandSpaceConfigs(m, spaceConfigs)
+	if err != nil {
+		return nil, err
+	}
+	configs := make(map[string]*config.SpaceConfig)
+	for i := range spaceConfigs {
+		configs[spaceKey(spaceConfigs[i].Org, spaceConfigs[i].Space)] = &spaceConfigs[i]
+	}
+	orgSpaces, err := m.Cfg.Spaces()
+	if err != nil {
+		return nil, err
+	}
+	var result []ManagedSpace
+	for _, input := range orgSpaces {
+		orgGUID, err := m.OrgMgr.GetOrgGUID(input.Org)
+		if err != nil {
+			return nil, err
+		}
+		spaces, err := m.ListSpaces(orgGUID)
+		if err != nil {
+			return nil, err
+		}
+		for _, space := range spaces {
+			result = append(result, ManagedSpace{Org: input.Org, Space: space, Config: configs[spaceKey(input.Org, space.Name)]})
+		}
+	}
+	return result, nil
+}
+
+func spaceKey(orgName, spaceName string) string {
+	return strings.ToLower(orgName + "/" + spaceName)
+}
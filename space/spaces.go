@@ -3,10 +3,13 @@ package space
 import (
 	"fmt"
 	"net/url"
+	"sort"
 	"strings"
+	"time"
 
 	cfclient "github.com/cloudfoundry-community/go-cfclient"
 	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/dryrun"
 	"github.com/pivotalservices/cf-mgmt/organization"
 	"github.com/pivotalservices/cf-mgmt/uaa"
 	"github.com/xchapter7x/lo"
@@ -37,6 +40,7 @@ type DefaultManager struct {
 func (m *DefaultManager) UpdateSpaceSSH(sshAllowed bool, space cfclient.Space, orgName string) error {
 	if m.Peek {
 		lo.G.Infof("[dry-run]: setting sshAllowed to %v for org/space %s/%s", sshAllowed, orgName, space.Name)
+		dryrun.Record(dryrun.Update, dryrun.Space, orgName+"/"+space.Name, fmt.Sprintf("allow ssh %v", sshAllowed))
 		return nil
 	}
 	lo.G.Infof("setting sshAllowed to %v for org/space %s/%s", sshAllowed, orgName, space.Name)
@@ -54,6 +58,10 @@ func (m *DefaultManager) UpdateSpaces() error {
 	if err != nil {
 		return err
 	}
+	spaceConfigs, err = ExpandSpaceConfigs(m, spaceConfigs)
+	if err != nil {
+		return err
+	}
 	for _, input := range spaceConfigs {
 		space, err := m.FindSpace(input.Org, input.Space)
 		if err != nil {
@@ -108,6 +116,7 @@ func (m *DefaultManager) FindSpace(orgName, spaceName string) (cfclient.Space, e
 func (m *DefaultManager) CreateSpace(spaceName, orgName, orgGUID string) error {
 	if m.Peek {
 		lo.G.Infof("[dry-run]: create space %s for org %s", spaceName, orgName)
+		dryrun.Record(dryrun.Create, dryrun.Space, orgName+"/"+spaceName, "")
 		return nil
 	}
 	lo.G.Infof("create space %s for org %s", spaceName, orgName)
@@ -137,6 +146,10 @@ func (m *DefaultManager) CreateSpaces() error {
 			continue
 		}
 		for _, spaceName := range input.Spaces {
+			if config.IsSpacePattern(spaceName) {
+				lo.G.Debugf("[%s] is a space pattern, matching spaces are not created", spaceName)
+				continue
+			}
 			if m.doesSpaceExist(spaces, spaceName) {
 				lo.G.Debugf("[%s] space already exists", spaceName)
 				continue
@@ -166,6 +179,18 @@ func (m *DefaultManager) DeleteSpaces() error {
 	if err != nil {
 		return err
 	}
+	orgConfigs, err := m.Cfg.GetOrgConfigs()
+	if err != nil {
+		return err
+	}
+	deferred, err := config.OrgsOutsideMaintenanceWindow(orgConfigs, time.Now())
+	if err != nil {
+		return err
+	}
+	approvals, err := m.Cfg.GetApprovals()
+	if err != nil {
+		return err
+	}
 	for _, input := range configSpaceList {
 
 		if !input.EnableDeleteSpaces {
@@ -173,25 +198,24 @@ func (m *DefaultManager) DeleteSpaces() error {
 			continue //Skip all orgs that have not opted-in
 		}
 
-		configuredSpaces := make(map[string]bool)
-		for _, spaceName := range input.Spaces {
-			configuredSpaces[spaceName] = true
-		}
-
-		org, err := m.OrgMgr.FindOrg(input.Org)
-		if err != nil {
-			return err
-		}
-		spaces, err := m.ListSpaces(org.Guid)
+		spacesToDelete, err := m.unmanagedSpaces(input)
 		if err != nil {
 			return err
 		}
 
-		spacesToDelete := make([]cfclient.Space, 0)
-		for _, space := range spaces {
-			if _, exists := configuredSpaces[space.Name]; !exists {
-				spacesToDelete = append(spacesToDelete, space)
+		if deferred[input.Org] {
+			for _, space := range spacesToDelete {
+				lo.G.Infof("Deferring deletion of space %s in org %s until the maintenance window of the org", space.Name, input.Org)
 			}
+			continue
+		}
+
+		changes := make([]config.Change, len(spacesToDelete))
+		for i, space := range spacesToDelete {
+			changes[i] = config.Change{Kind: config.ChangeDeleteSpace, Org: input.Org, Space: space.Name}
+		}
+		if err := approvals.Check(changes...); err != nil {
+			return err
 		}
 
 		for _, space := range spacesToDelete {
@@ -205,10 +229,116 @@ func (m *DefaultManager) DeleteSpaces() error {
 	return nil
 }
 
+//ListUnmanagedSpaces - lists the spaces of configured orgs that exist on the foundation but not in the configuration
+func (m *DefaultManager) ListUnmanagedSpaces() ([]UnmanagedSpace, error) {
+	configSpaceList, err := m.Cfg.Spaces()
+	if err != nil {
+		return nil, err
+	}
+	var result []UnmanagedSpace
+	for _, input := range configSpaceList {
+		spaces, err := m.unmanagedSpaces(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, space := range spaces {
+			result = append(result, UnmanagedSpace{Org: input.Org, Space: space})
+		}
+	}
+	return result, nil
+}
+
+func (m *DefaultManager) unmanagedSpaces(input config.Spaces) ([]cfclient.Space, error) {
+	configuredSpaces := make(map[string]bool)
+	for _, spaceName := range input.Spaces {
+		configuredSpaces[spaceName] = true
+	}
+
+	org, err := m.OrgMgr.FindOrg(input.Org)
+	if err != nil {
+		return nil, err
+	}
+	spaces, err := m.ListSpaces(org.Guid)
+	if err != nil {
+		return nil, err
+	}
+
+	unmanaged := make([]cfclient.Space, 0)
+	for _, space := range spaces {
+		if _, exists := configuredSpaces[space.Name]; !exists && !input.MatchesPattern(space.Name) {
+			unmanaged = append(unmanaged, space)
+		}
+	}
+	sort.Slice(unmanaged, func(i, j int) bool { return unmanaged[i].Name < unmanaged[j].Name })
+	return unmanaged, nil
+}
+
+//RecycleSpaces - deletes and recreates the ephemeral spaces whose recycle-schedule fired since they were
+//created, or every ephemeral space with force, returning the recycled spaces as org/space
+func (m *DefaultManager) RecycleSpaces(force bool) ([]string, error) {
+	spaceConfigs, err := m.Cfg.GetSpaceConfigs()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var recycled []string
+	for _, input := range spaceConfigs {
+		if !input.Ephemeral {
+			continue
+		}
+		if input.IsPattern() {
+			lo.G.Warningf("Space pattern [%s] of org %s cannot be ephemeral, matching spaces are not recreated", input.Space, input.Org)
+			continue
+		}
+		space, err := m.FindSpace(input.Org, input.Space)
+		// with peek, FindSpace returns a placeholder without a creation time
+		// for spaces that do not exist
+		if err != nil || space.CreatedAt == "" {
+			lo.G.Debugf("Ephemeral space %s of org %s does not exist yet", input.Space, input.Org)
+			continue
+		}
+		due := force
+		if !due {
+			createdAt, err := time.Parse(time.RFC3339, space.CreatedAt)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read when space %s of org %s was created: %v", input.Space, input.Org, err)
+			}
+			if due, err = input.RecycleDue(createdAt, now); err != nil {
+				return nil, err
+			}
+		}
+		if !due {
+			lo.G.Debugf("Ephemeral space %s of org %s is not due to be recycled", input.Space, input.Org)
+			continue
+		}
+		if err := m.recycleSpace(space, input.Org); err != nil {
+			return nil, err
+		}
+		recycled = append(recycled, input.Org+"/"+input.Space)
+	}
+	return recycled, nil
+}
+
+// recycleSpace deletes the space and everything in it, waiting for the
+// deletion to finish so that the space can be created again right away
+func (m *DefaultManager) recycleSpace(space cfclient.Space, orgName string) error {
+	if m.Peek {
+		lo.G.Infof("[dry-run]: deleting ephemeral space %s of org %s to recreate it", space.Name, orgName)
+		dryrun.Record(dryrun.Delete, dryrun.Space, orgName+"/"+space.Name, "")
+	} else {
+		lo.G.Infof("deleting ephemeral space %s of org %s to recreate it", space.Name, orgName)
+		if err := m.Client.DeleteSpace(space.Guid, true, false); err != nil {
+			return err
+		}
+	}
+	return m.CreateSpace(space.Name, orgName, space.OrganizationGuid)
+}
+
 //DeleteSpace - deletes a space based on GUID
 func (m *DefaultManager) DeleteSpace(space cfclient.Space, orgName string) error {
 	if m.Peek {
 		lo.G.Infof("[dry-run]: delete space with %s from org %s", space.Name, orgName)
+		dryrun.Record(dryrun.Delete, dryrun.Space, orgName+"/"+space.Name, "")
 		return nil
 	}
 	lo.G.Infof("delete space with %s from org %s", space.Name, orgName)
@@ -3,11 +3,13 @@ package space_test
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	cfclient "github.com/cloudfoundry-community/go-cfclient"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pivotalservices/cf-mgmt/config"
+	configfakes "github.com/pivotalservices/cf-mgmt/config/fakes"
 
 	orgfakes "github.com/pivotalservices/cf-mgmt/organization/fakes"
 	"github.com/pivotalservices/cf-mgmt/space"
@@ -201,6 +203,117 @@ var _ = Describe("given SpaceManager", func() {
 
 	})
 
+	Context("ExpandSpaceConfigs()", func() {
+		BeforeEach(func() {
+			fakeOrgMgr.GetOrgGUIDReturns("testOrgGUID", nil)
+			fakeClient.ListSpacesByQueryReturns([]cfclient.Space{
+				{Name: "team-a"},
+				{Name: "team-b"},
+				{Name: "sandbox"},
+			}, nil)
+		})
+
+		It("should match spaces by glob pattern", func() {
+			spaces, err := spaceManager.MatchSpaces("testOrg", "team-*")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(spaces).Should(HaveLen(2))
+		})
+
+		It("should error for an invalid pattern", func() {
+			_, err := spaceManager.MatchSpaces("testOrg", "team-[")
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("should copy the pattern config to every matched space without its own config", func() {
+			spaceConfigs, err := space.ExpandSpaceConfigs(&spaceManager, []config.SpaceConfig{
+				{Org: "testOrg", Space: "team-*", AllowSSH: true},
+				{Org: "testOrg", Space: "team-b"},
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(spaceConfigs).Should(ConsistOf(
+				config.SpaceConfig{Org: "testOrg", Space: "team-b"},
+				config.SpaceConfig{Org: "testOrg", Space: "team-a", AllowSSH: true, Pattern: "team-*"},
+			))
+		})
+
+		It("should list the existing spaces of configured orgs with their config", func() {
+			fakeReader := new(configfakes.FakeReader)
+			fakeReader.GetSpaceConfigsReturns([]config.SpaceConfig{
+				{Org: "testOrg", Space: "team-*", AllowSSH: true},
+			}, nil)
+			fakeReader.SpacesReturns([]config.Spaces{
+				{Org: "testOrg", Spaces: []string{"team-*"}},
+			}, nil)
+			spaceManager.Cfg = fakeReader
+			spaces, err := spaceManager.ListManagedSpaces()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(spaces).Should(HaveLen(3))
+			Expect(spaces[0].Org).Should(Equal("testOrg"))
+			Expect(spaces[0].Config.Space).Should(Equal("team-a"))
+			Expect(spaces[0].Config.AllowSSH).Should(BeTrue())
+			Expect(spaces[2].Space.Name).Should(Equal("sandbox"))
+			Expect(spaces[2].Config).Should(BeNil())
+		})
+	})
+
+	Context("RecycleSpaces()", func() {
+		BeforeEach(func() {
+			fakeReader := new(configfakes.FakeReader)
+			fakeReader.GetSpaceConfigsReturns([]config.SpaceConfig{
+				config.SpaceConfig{Org: "test", Space: "space1"},
+				config.SpaceConfig{Org: "test", Space: "sandbox", Ephemeral: true},
+			}, nil)
+			spaceManager.Cfg = fakeReader
+			fakeOrgMgr.GetOrgGUIDReturns("test-org-guid", nil)
+		})
+
+		It("should delete and create the ephemeral spaces that are due", func() {
+			fakeClient.ListSpacesByQueryReturns([]cfclient.Space{
+				cfclient.Space{Name: "space1", Guid: "space1-guid", OrganizationGuid: "test-org-guid", CreatedAt: "2018-01-01T00:00:00Z"},
+				cfclient.Space{Name: "sandbox", Guid: "sandbox-guid", OrganizationGuid: "test-org-guid", CreatedAt: "2018-01-01T00:00:00Z"},
+			}, nil)
+			recycled, err := spaceManager.RecycleSpaces(false)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(recycled).Should(Equal([]string{"test/sandbox"}))
+			Expect(fakeClient.DeleteSpaceCallCount()).Should(Equal(1))
+			spaceGUID, recursive, async := fakeClient.DeleteSpaceArgsForCall(0)
+			Expect(spaceGUID).Should(Equal("sandbox-guid"))
+			Expect(recursive).Should(BeTrue())
+			Expect(async).Should(BeFalse())
+			Expect(fakeClient.CreateSpaceCallCount()).Should(Equal(1))
+			request := fakeClient.CreateSpaceArgsForCall(0)
+			Expect(request.Name).Should(Equal("sandbox"))
+			Expect(request.OrganizationGuid).Should(Equal("test-org-guid"))
+		})
+
+		It("should not recycle spaces created since the schedule fired unless forced", func() {
+			fakeClient.ListSpacesByQueryReturns([]cfclient.Space{
+				cfclient.Space{Name: "sandbox", Guid: "sandbox-guid", OrganizationGuid: "test-org-guid", CreatedAt: time.Now().Format(time.RFC3339)},
+			}, nil)
+			recycled, err := spaceManager.RecycleSpaces(false)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(recycled).Should(BeEmpty())
+			Expect(fakeClient.DeleteSpaceCallCount()).Should(Equal(0))
+
+			recycled, err = spaceManager.RecycleSpaces(true)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(recycled).Should(Equal([]string{"test/sandbox"}))
+			Expect(fakeClient.DeleteSpaceCallCount()).Should(Equal(1))
+		})
+
+		It("should not delete with peek", func() {
+			spaceManager.Peek = true
+			fakeClient.ListSpacesByQueryReturns([]cfclient.Space{
+				cfclient.Space{Name: "sandbox", Guid: "sandbox-guid", OrganizationGuid: "test-org-guid", CreatedAt: "2018-01-01T00:00:00Z"},
+			}, nil)
+			recycled, err := spaceManager.RecycleSpaces(false)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(recycled).Should(Equal([]string{"test/sandbox"}))
+			Expect(fakeClient.DeleteSpaceCallCount()).Should(Equal(0))
+			Expect(fakeClient.CreateSpaceCallCount()).Should(Equal(0))
+		})
+	})
+
 	Context("DeleteSpaces()", func() {
 		BeforeEach(func() {
 			spaceManager.Cfg = config.NewManager("./fixtures/config-delete")
@@ -235,6 +348,44 @@ var _ = Describe("given SpaceManager", func() {
 			Expect(async).Should(Equal(true))
 		})
 
+		It("should defer deleting outside the maintenance window", func() {
+			fakeReader := new(configfakes.FakeReader)
+			fakeReader.SpacesReturns([]config.Spaces{
+				config.Spaces{Org: "test2", Spaces: []string{"space1"}, EnableDeleteSpaces: true},
+			}, nil)
+			fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+				config.OrgConfig{Org: "test2", MaintenanceWindow: "0 0 30 2 *"},
+			}, nil)
+			spaceManager.Cfg = fakeReader
+			fakeOrgMgr.FindOrgReturns(cfclient.Org{
+				Name: "test2",
+				Guid: "test2-org-guid",
+			}, nil)
+			fakeClient.ListSpacesByQueryReturns([]cfclient.Space{
+				cfclient.Space{Name: "space1", Guid: "space1-guid"},
+				cfclient.Space{Name: "space2", Guid: "space2-guid"},
+			}, nil)
+			Expect(spaceManager.DeleteSpaces()).Should(Succeed())
+			Expect(fakeClient.DeleteSpaceCallCount()).Should(Equal(0))
+		})
+
+		It("should list spaces not in the configuration", func() {
+			fakeOrgMgr.FindOrgReturns(cfclient.Org{
+				Name: "test2",
+				Guid: "test2-org-guid",
+			}, nil)
+			fakeClient.ListSpacesByQueryReturns([]cfclient.Space{
+				cfclient.Space{Name: "space1", Guid: "space1-guid"},
+				cfclient.Space{Name: "space3", Guid: "space3-guid"},
+			}, nil)
+			unmanaged, err := spaceManager.ListUnmanagedSpaces()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(unmanaged).Should(HaveLen(2))
+			Expect(unmanaged[0].Org).Should(Equal("test"))
+			Expect(unmanaged[0].Space.Name).Should(Equal("space3"))
+			Expect(fakeClient.DeleteSpaceCallCount()).Should(Equal(0))
+		})
+
 		It("should error", func() {
 			spaces := []cfclient.Space{
 				cfclient.Space{
@@ -4,15 +4,35 @@ import (
 	"net/url"
 
 	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pivotalservices/cf-mgmt/config"
 )
 
 //Manager -
 type Manager interface {
 	FindSpace(orgName, spaceName string) (cfclient.Space, error)
+	CreateSpace(spaceName, orgName, orgGUID string) error
 	CreateSpaces() error
 	UpdateSpaces() (err error)
 	DeleteSpaces() (err error)
 	ListSpaces(orgGUID string) ([]cfclient.Space, error)
+	MatchSpaces(orgName, pattern string) ([]cfclient.Space, error)
+	ListUnmanagedSpaces() ([]UnmanagedSpace, error)
+	ListManagedSpaces() ([]ManagedSpace, error)
+	RecycleSpaces(force bool) ([]string, error)
+}
+
+//UnmanagedSpace - a space of a configured org that is not in the configuration
+type UnmanagedSpace struct {
+	Org   string
+	Space cfclient.Space
+}
+
+//ManagedSpace - an existing space of a configured org with its configuration, which is nil for a space
+//that is not in the configuration
+type ManagedSpace struct {
+	Org    string
+	Space  cfclient.Space
+	Config *config.SpaceConfig
 }
 
 type CFClient interface {
@@ -0,0 +1,171 @@
+// Package stats counts the api calls cf-mgmt makes, the hits of its caches and
+// the time spent in each phase of a command, so that long runs show where
+// their time goes.
+package stats
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Apis whose calls are counted.
+const (
+	CloudController = "cc"
+	UAA             = "uaa"
+	LDAP            = "ldap"
+)
+
+//Stats - the api calls, cache hits and phase durations of a command
+type Stats struct {
+	Calls  map[string]int        `json:"calls"`
+	Caches map[string]CacheStats `json:"caches"`
+	Phases []Phase               `json:"phases"`
+}
+
+//CacheStats - how often a cache answered a lookup
+type CacheStats struct {
+	Hits    int     `json:"hits"`
+	Misses  int     `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+//Phase - how long a phase of a command took
+type Phase struct {
+	Name            string  `json:"name"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+var (
+	mutex   sync.Mutex
+	current = newStats()
+)
+
+func newStats() *Stats {
+	return &Stats{
+		Calls:  make(map[string]int),
+		Caches: make(map[string]CacheStats),
+		Phases: []Phase{},
+	}
+}
+
+//Reset - starts counting from zero, for the next command
+func Reset() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	current = newStats()
+}
+
+//Call - counts a call to an api
+func Call(api string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	current.Calls[api]++
+}
+
+//CacheHit - counts a lookup answered by a cache
+func CacheHit(cache string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	c := current.Caches[cache]
+	c.Hits++
+	current.Caches[cache] = c
+}
+
+//CacheMiss - counts a lookup a cache could not answer
+func CacheMiss(cache string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	c := current.Caches[cache]
+	c.Misses++
+	current.Caches[cache] = c
+}
+
+//RecordPhase - records how long a phase took
+func RecordPhase(name string, duration time.Duration) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	current.Phases = append(current.Phases, Phase{Name: name, DurationSeconds: duration.Seconds()})
+}
+
+//Time - starts timing a phase, which is recorded when the returned func is called
+func Time(name string) func() {
+	start := time.Now()
+	return func() {
+		RecordPhase(name, time.Since(start))
+	}
+}
+
+//Snapshot - returns a copy of the statistics counted so far
+func Snapshot() *Stats {
+	mutex.Lock()
+	defer mutex.Unlock()
+	snapshot := newStats()
+	for api, calls := range current.Calls {
+		snapshot.Calls[api] = calls
+	}
+	for cache, c := range current.Caches {
+		if lookups := c.Hits + c.Misses; lookups > 0 {
+			c.HitRate = float64(c.Hits) / float64(lookups)
+		}
+		snapshot.Caches[cache] = c
+	}
+	snapshot.Phases = append(snapshot.Phases, current.Phases...)
+	return snapshot
+}
+
+//Empty - whether nothing was counted, such as for commands that make no api calls
+func (s *Stats) Empty() bool {
+	return len(s.Calls) == 0 && len(s.Caches) == 0 && len(s.Phases) == 0
+}
+
+//String - formats the statistics one line per api, cache and phase
+func (s *Stats) String() string {
+	var buffer bytes.Buffer
+	for _, api := range sortedKeys(s.Calls) {
+		fmt.Fprintf(&buffer, "%-6s %d calls\n", api, s.Calls[api])
+	}
+	caches := make([]string, 0, len(s.Caches))
+	for cache := range s.Caches {
+		caches = append(caches, cache)
+	}
+	sort.Strings(caches)
+	for _, cache := range caches {
+		c := s.Caches[cache]
+		fmt.Fprintf(&buffer, "cache %s: %d hits, %d misses (%.0f%% hit rate)\n", cache, c.Hits, c.Misses, c.HitRate*100)
+	}
+	for _, phase := range s.Phases {
+		fmt.Fprintf(&buffer, "%8.1fs %s\n", phase.DurationSeconds, phase.Name)
+	}
+	return buffer.String()
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+//Transport - returns a transport that counts the requests made through base as calls to api
+func Transport(api string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &countingTransport{api: api, base: base}
+}
+
+type countingTransport struct {
+	api  string
+	base http.RoundTripper
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	Call(t.api)
+	return t.base.RoundTrip(req)
+}
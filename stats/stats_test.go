@@ -0,0 +1,67 @@
+package stats_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/stats"
+)
+
+var _ = Describe("given stats", func() {
+	BeforeEach(func() {
+		stats.Reset()
+	})
+
+	It("counts api calls", func() {
+		stats.Call(stats.LDAP)
+		stats.Call(stats.LDAP)
+		stats.Call(stats.UAA)
+		Expect(stats.Snapshot().Calls).Should(Equal(map[string]int{stats.LDAP: 2, stats.UAA: 1}))
+	})
+
+	It("counts the requests made through a transport", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+		client := &http.Client{Transport: stats.Transport(stats.CloudController, nil)}
+		for i := 0; i < 3; i++ {
+			resp, err := client.Get(server.URL)
+			Expect(err).ShouldNot(HaveOccurred())
+			resp.Body.Close()
+		}
+		Expect(stats.Snapshot().Calls[stats.CloudController]).Should(Equal(3))
+	})
+
+	It("computes cache hit rates", func() {
+		stats.CacheMiss("dns")
+		stats.CacheHit("dns")
+		stats.CacheHit("dns")
+		stats.CacheHit("dns")
+		Expect(stats.Snapshot().Caches["dns"]).Should(Equal(stats.CacheStats{Hits: 3, Misses: 1, HitRate: 0.75}))
+	})
+
+	It("records phases in order", func() {
+		stats.RecordPhase("Creating Orgs", 2*time.Second)
+		stats.RecordPhase("Update Org Users", 500*time.Millisecond)
+		Expect(stats.Snapshot().Phases).Should(Equal([]stats.Phase{
+			{Name: "Creating Orgs", DurationSeconds: 2},
+			{Name: "Update Org Users", DurationSeconds: 0.5},
+		}))
+	})
+
+	It("formats the statistics", func() {
+		stats.Call(stats.UAA)
+		stats.Call(stats.CloudController)
+		stats.CacheHit("dns")
+		stats.RecordPhase("Creating Orgs", 1500*time.Millisecond)
+		Expect(stats.Snapshot().String()).Should(Equal("cc     1 calls\nuaa    1 calls\ncache dns: 1 hits, 0 misses (100% hit rate)\n     1.5s Creating Orgs\n"))
+	})
+
+	It("is empty after a reset", func() {
+		stats.Call(stats.UAA)
+		stats.Reset()
+		Expect(stats.Snapshot().Empty()).Should(BeTrue())
+	})
+})
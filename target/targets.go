@@ -0,0 +1,160 @@
+package target
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Target is a foundation cf-mgmt runs against. Secrets are references rather
+// than values, env:NAME reads the environment variable and file:PATH the
+// file, so that the targets file holds no secret unless it is encrypted.
+type Target struct {
+	Name            string `yaml:"name"`
+	SystemDomain    string `yaml:"system-domain"`
+	UserID          string `yaml:"user-id"`
+	ConfigDirectory string `yaml:"config-dir,omitempty"`
+	ClientSecret    string `yaml:"client-secret,omitempty"`
+	Password        string `yaml:"password,omitempty"`
+	LdapPassword    string `yaml:"ldap-password,omitempty"`
+}
+
+// Targets are the foundations of the targets file and the one in use.
+type Targets struct {
+	Current string   `yaml:"current,omitempty"`
+	Targets []Target `yaml:"targets"`
+	// encrypted is whether the file is encrypted, which allows secrets that
+	// are values rather than references
+	encrypted bool
+}
+
+// References of secrets of a target.
+const (
+	EnvReference  = "env:"
+	FileReference = "file:"
+)
+
+// KeyEnv is the environment variable holding the key of an encrypted targets file.
+const KeyEnv = "CF_MGMT_TARGETS_KEY"
+
+// DefaultFile returns the targets file, CF_MGMT_TARGETS or .cf-mgmt/targets.yml
+// in the home directory.
+func DefaultFile() string {
+	if file := os.Getenv("CF_MGMT_TARGETS"); file != "" {
+		return file
+	}
+	return filepath.Join(os.Getenv("HOME"), ".cf-mgmt", "targets.yml")
+}
+
+// Load reads the targets file, which has no targets when it does not exist.
+// An encrypted file is decrypted with key, and with a key the file is
+// encrypted once saved.
+func Load(file, key string) (*Targets, error) {
+	targets := &Targets{encrypted: key != ""}
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return targets, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if isEncrypted(data) {
+		if key == "" {
+			return nil, fmt.Errorf("targets file %s is encrypted, set %s to its key", file, KeyEnv)
+		}
+		if data, err = decrypt(data, key); err != nil {
+			return nil, fmt.Errorf("unable to decrypt targets file %s: %v", file, err)
+		}
+	}
+	if err := yaml.Unmarshal(data, targets); err != nil {
+		return nil, fmt.Errorf("unable to parse targets file %s: %v", file, err)
+	}
+	return targets, nil
+}
+
+// Save writes the targets file, readable only by its owner.
+func (t *Targets) Save(file, key string) error {
+	data, err := yaml.Marshal(t)
+	if err != nil {
+		return err
+	}
+	if t.encrypted {
+		if data, err = encrypt(data, key); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, data, 0600)
+}
+
+// Add adds the target, replacing the target of the same name.
+func (t *Targets) Add(target Target) error {
+	if target.Name == "" || target.SystemDomain == "" || target.UserID == "" {
+		return fmt.Errorf("a target requires a name, system-domain and user-id")
+	}
+	for _, secret := range []string{target.ClientSecret, target.Password, target.LdapPassword} {
+		if secret != "" && !isReference(secret) && !t.encrypted {
+			return fmt.Errorf("secrets of target %s must be %sNAME or %sPATH references unless the targets file is encrypted with %s", target.Name, EnvReference, FileReference, KeyEnv)
+		}
+	}
+	for i := range t.Targets {
+		if t.Targets[i].Name == target.Name {
+			t.Targets[i] = target
+			return nil
+		}
+	}
+	t.Targets = append(t.Targets, target)
+	sort.Slice(t.Targets, func(i, j int) bool { return t.Targets[i].Name < t.Targets[j].Name })
+	return nil
+}
+
+// Use makes the named target the one in use.
+func (t *Targets) Use(name string) error {
+	if t.Get(name) == nil {
+		return fmt.Errorf("target %s is not in the targets file", name)
+	}
+	t.Current = name
+	return nil
+}
+
+// Get returns the named target, nil when there is no such target.
+func (t *Targets) Get(name string) *Target {
+	for i := range t.Targets {
+		if t.Targets[i].Name == name {
+			return &t.Targets[i]
+		}
+	}
+	return nil
+}
+
+// Resolve returns the secret a reference refers to, a secret that is not a
+// reference is the value of the secret.
+func Resolve(secret string) (string, error) {
+	switch {
+	case strings.HasPrefix(secret, EnvReference):
+		name := strings.TrimPrefix(secret, EnvReference)
+		value := os.Getenv(name)
+		if value == "" {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return value, nil
+	case strings.HasPrefix(secret, FileReference):
+		data, err := ioutil.ReadFile(strings.TrimPrefix(secret, FileReference))
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return secret, nil
+}
+
+func isReference(secret string) bool {
+	return strings.HasPrefix(secret, EnvReference) || strings.HasPrefix(secret, FileReference)
+}
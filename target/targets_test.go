@@ -0,0 +1,93 @@
+package target_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotalservices/cf-mgmt/target"
+)
+
+var _ = Describe("given targets", func() {
+	var (
+		dir  string
+		file string
+		prod Target
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "cf-mgmt-targets")
+		Expect(err).ShouldNot(HaveOccurred())
+		file = filepath.Join(dir, "targets.yml")
+		prod = Target{Name: "prod", SystemDomain: "sys.prod.example.com", UserID: "cf-mgmt", ClientSecret: "env:PROD_CLIENT_SECRET"}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("has no targets without a targets file", func() {
+		targets, err := Load(file, "")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(targets.Targets).Should(BeEmpty())
+	})
+
+	It("saves and loads targets", func() {
+		targets, _ := Load(file, "")
+		Expect(targets.Add(prod)).Should(Succeed())
+		Expect(targets.Add(Target{Name: "dev", SystemDomain: "sys.dev.example.com", UserID: "cf-mgmt"})).Should(Succeed())
+		Expect(targets.Use("prod")).Should(Succeed())
+		Expect(targets.Save(file, "")).Should(Succeed())
+
+		loaded, err := Load(file, "")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(loaded.Current).Should(Equal("prod"))
+		Expect(loaded.Targets).Should(HaveLen(2))
+		Expect(loaded.Targets[0].Name).Should(Equal("dev"))
+		Expect(*loaded.Get("prod")).Should(Equal(prod))
+	})
+
+	It("requires references to secrets unless encrypted", func() {
+		targets, _ := Load(file, "")
+		prod.ClientSecret = "plain-secret"
+		Expect(targets.Add(prod)).Should(MatchError("secrets of target prod must be env:NAME or file:PATH references unless the targets file is encrypted with CF_MGMT_TARGETS_KEY"))
+	})
+
+	It("encrypts the targets file with a key", func() {
+		targets, _ := Load(file, "the-key")
+		prod.ClientSecret = "plain-secret"
+		Expect(targets.Add(prod)).Should(Succeed())
+		Expect(targets.Save(file, "the-key")).Should(Succeed())
+
+		data, err := ioutil.ReadFile(file)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(data)).ShouldNot(ContainSubstring("plain-secret"))
+
+		_, err = Load(file, "")
+		Expect(err).Should(MatchError("targets file " + file + " is encrypted, set CF_MGMT_TARGETS_KEY to its key"))
+		_, err = Load(file, "wrong-key")
+		Expect(err).Should(MatchError("unable to decrypt targets file " + file + ": the key is wrong or the file was modified"))
+		loaded, err := Load(file, "the-key")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(loaded.Get("prod").ClientSecret).Should(Equal("plain-secret"))
+	})
+
+	It("errors using a target that does not exist", func() {
+		targets, _ := Load(file, "")
+		Expect(targets.Use("prod")).Should(MatchError("target prod is not in the targets file"))
+	})
+
+	It("resolves references to secrets", func() {
+		os.Setenv("PROD_CLIENT_SECRET", "from-env")
+		defer os.Unsetenv("PROD_CLIENT_SECRET")
+		Expect(ioutil.WriteFile(filepath.Join(dir, "secret"), []byte("from-file\n"), 0600)).Should(Succeed())
+		Expect(Resolve("env:PROD_CLIENT_SECRET")).Should(Equal("from-env"))
+		Expect(Resolve("file:" + filepath.Join(dir, "secret"))).Should(Equal("from-file"))
+		Expect(Resolve("plain-secret")).Should(Equal("plain-secret"))
+		_, err := Resolve("env:UNSET_CLIENT_SECRET")
+		Expect(err).Should(MatchError("environment variable UNSET_CLIENT_SECRET is not set"))
+	})
+})
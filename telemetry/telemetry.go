@@ -0,0 +1,174 @@
+// Package telemetry reports anonymous usage of cf-mgmt, when operators opt in,
+// so that maintainers learn which commands and configuration features are used.
+package telemetry
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+)
+
+// Report is what is sent of a run. It holds no names, domains, users or
+// error messages, only the command, the category of its error and the
+// configuration features it ran with.
+type Report struct {
+	Command         string   `json:"command"`
+	Version         string   `json:"version"`
+	OS              string   `json:"os"`
+	Arch            string   `json:"arch"`
+	Status          string   `json:"status"`
+	ErrorCategory   string   `json:"error_category,omitempty"`
+	DurationSeconds int      `json:"duration_seconds"`
+	Changes         int      `json:"changes"`
+	Orgs            string   `json:"orgs,omitempty"`
+	Features        []string `json:"features"`
+}
+
+// NewReport returns the report of a run of the command.
+func NewReport(command, version string, duration time.Duration, changes int, err error) Report {
+	report := Report{
+		Command:         command,
+		Version:         version,
+		OS:              runtime.GOOS,
+		Arch:            runtime.GOARCH,
+		Status:          "succeeded",
+		DurationSeconds: int(duration.Seconds()),
+		Changes:         changes,
+		Features:        []string{},
+	}
+	if err != nil {
+		report.Status = "failed"
+		report.ErrorCategory = Categorize(err)
+	}
+	return report
+}
+
+// Categories of errors.
+const (
+	CategoryAuthentication = "authentication"
+	CategoryNetwork        = "network"
+	CategoryTimeout        = "timeout"
+	CategoryConfig         = "config"
+	CategoryLdap           = "ldap"
+	CategoryOther          = "other"
+)
+
+// categories are matched against the lower cased error message in order
+var categories = []struct {
+	category string
+	markers  []string
+}{
+	{CategoryTimeout, []string{"timeout", "deadline exceeded", "timed out"}},
+	{CategoryAuthentication, []string{"401", "403", "unauthorized", "forbidden", "insufficient_scope", "invalid_client", "must set system-domain"}},
+	{CategoryLdap, []string{"ldap"}},
+	{CategoryNetwork, []string{"connection refused", "no such host", "dial tcp", "connection reset", "eof"}},
+	{CategoryConfig, []string{".yml", "yaml", "config"}},
+}
+
+// Categorize returns the category of an error, so that errors are reported
+// without their message.
+func Categorize(err error) string {
+	message := strings.ToLower(err.Error())
+	for _, c := range categories {
+		for _, marker := range c.markers {
+			if strings.Contains(message, marker) {
+				return c.category
+			}
+		}
+	}
+	return CategoryOther
+}
+
+// AddFeatures adds the configuration features of the configuration, and the
+// number of orgs as a range, to the report.
+func (r *Report) AddFeatures(cfg config.Reader) {
+	if orgConfigs, err := cfg.GetOrgConfigs(); err == nil {
+		r.Orgs = orgRange(len(orgConfigs))
+		for _, orgConfig := range orgConfigs {
+			if orgConfig.QuotaAlerts != nil {
+				r.add("quota-alerts")
+			}
+			if len(orgConfig.AllowedServices) > 0 {
+				r.add("allowed-services")
+			}
+		}
+	}
+	if globalConfig, err := cfg.GetGlobalConfig(); err == nil {
+		r.addIf(len(globalConfig.RoleGroups) > 0, "role-groups")
+		r.addIf(globalConfig.CreateInternalUsers != nil, "create-internal-users")
+		r.addIf(len(globalConfig.ASGEndpoints) > 0, "asg-endpoints")
+		r.addIf(globalConfig.EnforceInternalRoutes, "enforce-internal-routes")
+		r.addIf(globalConfig.EnforceDockerPolicy, "enforce-docker-policy")
+		r.addIf(globalConfig.EnforceStackPolicy, "enforce-stack-policy")
+		r.addIf(globalConfig.QuotaNotifications != nil, "quota-notifications")
+		r.addIf(globalConfig.TokenPolicy != nil, "token-policy")
+	}
+	if ldapConfig, err := cfg.LdapConfig(""); err == nil {
+		r.addIf(ldapConfig.Enabled, "ldap")
+	}
+	if approvals, err := cfg.GetApprovals(); err == nil {
+		r.addIf(approvals != nil && approvals.RequireApprovals, "approvals")
+	}
+	if providers, err := cfg.GetIdentityProviders(); err == nil {
+		r.addIf(providers != nil, "identity-providers")
+	}
+	if migration, err := cfg.GetOriginMigration(); err == nil {
+		r.addIf(migration != nil, "origin-migration")
+	}
+}
+
+func (r *Report) addIf(used bool, feature string) {
+	if used {
+		r.add(feature)
+	}
+}
+
+func (r *Report) add(feature string) {
+	for _, f := range r.Features {
+		if f == feature {
+			return
+		}
+	}
+	r.Features = append(r.Features, feature)
+}
+
+// orgRange reports the number of orgs as a range, so that it does not
+// identify a foundation
+func orgRange(orgs int) string {
+	for _, limit := range []int{10, 100, 1000} {
+		if orgs <= limit {
+			return fmt.Sprintf("<=%d", limit)
+		}
+	}
+	return ">1000"
+}
+
+// Send posts the report to the endpoint, giving up after a few seconds so
+// that telemetry never holds up a run. The certificate of the endpoint is
+// verified unless skipSSLValidation is set.
+func Send(endpoint string, report Report, skipSSLValidation bool) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: 5 * time.Second}
+	if skipSSLValidation {
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+	resp, err := client.Post(endpoint, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint returned %d", resp.StatusCode)
+	}
+	return nil
+}
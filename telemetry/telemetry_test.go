@@ -0,0 +1,74 @@
+package telemetry_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/config"
+	configfakes "github.com/pivotalservices/cf-mgmt/config/fakes"
+	. "github.com/pivotalservices/cf-mgmt/telemetry"
+)
+
+var _ = Describe("Telemetry", func() {
+	Context("Categorize", func() {
+		It("categorizes errors without their message", func() {
+			Expect(Categorize(errors.New("unable to get a token: 401 Unauthorized"))).Should(Equal(CategoryAuthentication))
+			Expect(Categorize(errors.New("dial tcp 10.0.0.1:443: connection refused"))).Should(Equal(CategoryNetwork))
+			Expect(Categorize(errors.New("context deadline exceeded"))).Should(Equal(CategoryTimeout))
+			Expect(Categorize(errors.New("cannot bind to ldap server"))).Should(Equal(CategoryLdap))
+			Expect(Categorize(errors.New("unable to parse config/org1/orgConfig.yml"))).Should(Equal(CategoryConfig))
+			Expect(Categorize(errors.New("something else"))).Should(Equal(CategoryOther))
+		})
+	})
+
+	Context("Report", func() {
+		It("reports a failed run by category", func() {
+			report := NewReport("apply", "1.2.3", 90*time.Second, 4, errors.New("403 Forbidden for user jdoe"))
+			Expect(report.Status).Should(Equal("failed"))
+			Expect(report.ErrorCategory).Should(Equal(CategoryAuthentication))
+			Expect(report.DurationSeconds).Should(Equal(90))
+			data, err := json.Marshal(report)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(string(data)).ShouldNot(ContainSubstring("jdoe"))
+		})
+
+		It("adds the configuration features used", func() {
+			reader := new(configfakes.FakeReader)
+			reader.GetOrgConfigsReturns([]config.OrgConfig{{Org: "org1", AllowedServices: []string{"p-mysql"}}, {Org: "org2"}}, nil)
+			reader.GetGlobalConfigReturns(&config.GlobalConfig{EnforceDockerPolicy: true, RoleGroups: []config.RoleGroup{{}}}, nil)
+			reader.LdapConfigReturns(&config.LdapConfig{Enabled: true}, nil)
+			report := NewReport("apply", "1.2.3", time.Second, 0, nil)
+			report.AddFeatures(reader)
+			Expect(report.Status).Should(Equal("succeeded"))
+			Expect(report.Orgs).Should(Equal("<=10"))
+			Expect(report.Features).Should(ConsistOf("allowed-services", "role-groups", "enforce-docker-policy", "ldap"))
+		})
+	})
+
+	Context("Send", func() {
+		It("posts the report to the endpoint", func() {
+			var received Report
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&received)
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+			Expect(Send(server.URL, NewReport("plan", "1.2.3", time.Second, 0, nil), false)).Should(Succeed())
+			Expect(received.Command).Should(Equal("plan"))
+		})
+
+		It("verifies the certificate of the endpoint unless skipping ssl validation", func() {
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+			Expect(Send(server.URL, NewReport("plan", "1.2.3", time.Second, 0, nil), false)).ShouldNot(Succeed())
+			Expect(Send(server.URL, NewReport("plan", "1.2.3", time.Second, 0, nil), true)).Should(Succeed())
+		})
+	})
+})
@@ -0,0 +1,66 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+
+	"github.com/pivotalservices/cf-mgmt/tokenpolicy"
+)
+
+type FakeManager struct {
+	UpdateTokenPolicyStub        func() error
+	updateTokenPolicyMutex       sync.RWMutex
+	updateTokenPolicyArgsForCall []struct{}
+	updateTokenPolicyReturns     struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeManager) UpdateTokenPolicy() error {
+	fake.updateTokenPolicyMutex.Lock()
+	fake.updateTokenPolicyArgsForCall = append(fake.updateTokenPolicyArgsForCall, struct{}{})
+	fake.recordInvocation("UpdateTokenPolicy", []interface{}{})
+	fake.updateTokenPolicyMutex.Unlock()
+	if fake.UpdateTokenPolicyStub != nil {
+		return fake.UpdateTokenPolicyStub()
+	} else {
+		return fake.updateTokenPolicyReturns.result1
+	}
+}
+
+func (fake *FakeManager) UpdateTokenPolicyCallCount() int {
+	fake.updateTokenPolicyMutex.RLock()
+	defer fake.updateTokenPolicyMutex.RUnlock()
+	return len(fake.updateTokenPolicyArgsForCall)
+}
+
+func (fake *FakeManager) UpdateTokenPolicyReturns(result1 error) {
+	fake.UpdateTokenPolicyStub = nil
+	fake.updateTokenPolicyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.updateTokenPolicyMutex.RLock()
+	defer fake.updateTokenPolicyMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeManager) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ tokenpolicy.Manager = new(FakeManager)
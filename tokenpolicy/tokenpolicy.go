@@ -0,0 +1,98 @@
+package tokenpolicy
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/redact"
+	"github.com/pivotalservices/cf-mgmt/uaa"
+	"github.com/xchapter7x/lo"
+)
+
+func NewManager(uaaMgr uaa.Manager, cfg config.Reader) Manager {
+	return &DefaultManager{
+		Cfg:    cfg,
+		UAAMgr: uaaMgr,
+	}
+}
+
+//DefaultManager -
+type DefaultManager struct {
+	Cfg    config.Reader
+	UAAMgr uaa.Manager
+}
+
+//UpdateTokenPolicy - updates the token validities, revocation, active key and signing keys of the default
+//identity zone to the token-policy of cf-mgmt.yml, leaving the settings it does not set as they are
+func (m *DefaultManager) UpdateTokenPolicy() error {
+	globalConfig, err := m.Cfg.GetGlobalConfig()
+	if err != nil {
+		return err
+	}
+	desired := globalConfig.TokenPolicy
+	if desired == nil {
+		lo.G.Debug("No token-policy in cf-mgmt.yml, the token policy is not managed")
+		return nil
+	}
+	current, err := m.UAAMgr.GetTokenPolicy()
+	if err != nil {
+		return err
+	}
+	updated := *current
+	var changes []string
+	if desired.AccessTokenValidity != 0 && desired.AccessTokenValidity != current.AccessTokenValidity {
+		changes = append(changes, fmt.Sprintf("access token validity %d -> %d", current.AccessTokenValidity, desired.AccessTokenValidity))
+		updated.AccessTokenValidity = desired.AccessTokenValidity
+	}
+	if desired.RefreshTokenValidity != 0 && desired.RefreshTokenValidity != current.RefreshTokenValidity {
+		changes = append(changes, fmt.Sprintf("refresh token validity %d -> %d", current.RefreshTokenValidity, desired.RefreshTokenValidity))
+		updated.RefreshTokenValidity = desired.RefreshTokenValidity
+	}
+	if desired.JwtRevocable != nil && *desired.JwtRevocable != current.JwtRevocable {
+		changes = append(changes, fmt.Sprintf("jwt revocable %t -> %t", current.JwtRevocable, *desired.JwtRevocable))
+		updated.JwtRevocable = *desired.JwtRevocable
+	}
+	if desired.RefreshTokenUnique != nil && *desired.RefreshTokenUnique != current.RefreshTokenUnique {
+		changes = append(changes, fmt.Sprintf("refresh token unique %t -> %t", current.RefreshTokenUnique, *desired.RefreshTokenUnique))
+		updated.RefreshTokenUnique = *desired.RefreshTokenUnique
+	}
+	if desired.ActiveKeyID != "" && desired.ActiveKeyID != current.ActiveKeyID {
+		changes = append(changes, fmt.Sprintf("active key %s -> %s", current.ActiveKeyID, desired.ActiveKeyID))
+		updated.ActiveKeyID = desired.ActiveKeyID
+	}
+	// signing keys are never returned, so keys are compared by id and sent
+	// with every update
+	updated.Keys = nil
+	if len(desired.Keys) > 0 {
+		updated.Keys = make(map[string]uaa.TokenKey)
+		for _, key := range desired.Keys {
+			signingKey := os.Getenv(key.SigningKeyEnv)
+			if signingKey == "" {
+				return fmt.Errorf("signing key [%s] of token-policy is not set, %s is empty", key.ID, key.SigningKeyEnv)
+			}
+			redact.Secrets(signingKey)
+			updated.Keys[key.ID] = uaa.TokenKey{SigningKey: signingKey}
+		}
+		if before, after := keyIDs(current.Keys), keyIDs(updated.Keys); before != after {
+			changes = append(changes, fmt.Sprintf("keys [%s] -> [%s]", before, after))
+		}
+	}
+	if len(changes) == 0 {
+		lo.G.Debug("Token policy of identity zone uaa is up to date")
+		return nil
+	}
+	lo.G.Infof("Token policy of identity zone uaa changes: %s", strings.Join(changes, ", "))
+	return m.UAAMgr.UpdateTokenPolicy(updated)
+}
+
+func keyIDs(keys map[string]uaa.TokenKey) string {
+	var ids []string
+	for id := range keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ", ")
+}
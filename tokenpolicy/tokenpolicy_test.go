@@ -0,0 +1,88 @@
+package tokenpolicy_test
+
+import (
+	"errors"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/config"
+	configfakes "github.com/pivotalservices/cf-mgmt/config/fakes"
+	. "github.com/pivotalservices/cf-mgmt/tokenpolicy"
+	"github.com/pivotalservices/cf-mgmt/uaa"
+	uaafakes "github.com/pivotalservices/cf-mgmt/uaa/fakes"
+)
+
+var _ = Describe("given token policy manager", func() {
+	var (
+		fakeReader *configfakes.FakeReader
+		fakeUaa    *uaafakes.FakeManager
+		manager    DefaultManager
+		revocable  bool
+	)
+
+	BeforeEach(func() {
+		fakeReader = new(configfakes.FakeReader)
+		fakeUaa = new(uaafakes.FakeManager)
+		manager = DefaultManager{Cfg: fakeReader, UAAMgr: fakeUaa}
+		revocable = true
+		fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{TokenPolicy: &config.TokenPolicy{
+			AccessTokenValidity: 3600,
+			JwtRevocable:        &revocable,
+		}}, nil)
+		fakeUaa.GetTokenPolicyReturns(&uaa.TokenPolicy{AccessTokenValidity: 43200, RefreshTokenValidity: 2592000, ActiveKeyID: "key-1", Keys: map[string]uaa.TokenKey{"key-1": {}}}, nil)
+	})
+
+	Context("UpdateTokenPolicy()", func() {
+		It("does nothing without token-policy", func() {
+			fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{}, nil)
+			Expect(manager.UpdateTokenPolicy()).Should(Succeed())
+			Expect(fakeUaa.GetTokenPolicyCallCount()).Should(Equal(0))
+		})
+
+		It("updates the settings that differ, leaving the others alone", func() {
+			Expect(manager.UpdateTokenPolicy()).Should(Succeed())
+			Expect(fakeUaa.UpdateTokenPolicyCallCount()).Should(Equal(1))
+			policy := fakeUaa.UpdateTokenPolicyArgsForCall(0)
+			Expect(policy.AccessTokenValidity).Should(Equal(3600))
+			Expect(policy.RefreshTokenValidity).Should(Equal(2592000))
+			Expect(policy.JwtRevocable).Should(BeTrue())
+			Expect(policy.ActiveKeyID).Should(Equal("key-1"))
+			Expect(policy.Keys).Should(BeNil())
+		})
+
+		It("leaves an up to date policy alone", func() {
+			fakeUaa.GetTokenPolicyReturns(&uaa.TokenPolicy{AccessTokenValidity: 3600, JwtRevocable: true}, nil)
+			Expect(manager.UpdateTokenPolicy()).Should(Succeed())
+			Expect(fakeUaa.UpdateTokenPolicyCallCount()).Should(Equal(0))
+		})
+
+		It("rotates the signing keys", func() {
+			os.Setenv("TOKEN_KEY_2", "new-key")
+			defer os.Unsetenv("TOKEN_KEY_2")
+			fakeUaa.GetTokenPolicyReturns(&uaa.TokenPolicy{AccessTokenValidity: 3600, JwtRevocable: true, ActiveKeyID: "key-1", Keys: map[string]uaa.TokenKey{"key-1": {}}}, nil)
+			fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{TokenPolicy: &config.TokenPolicy{
+				ActiveKeyID: "key-2",
+				Keys:        []config.TokenKey{{ID: "key-2", SigningKeyEnv: "TOKEN_KEY_2"}},
+			}}, nil)
+			Expect(manager.UpdateTokenPolicy()).Should(Succeed())
+			policy := fakeUaa.UpdateTokenPolicyArgsForCall(0)
+			Expect(policy.ActiveKeyID).Should(Equal("key-2"))
+			Expect(policy.Keys).Should(Equal(map[string]uaa.TokenKey{"key-2": {SigningKey: "new-key"}}))
+		})
+
+		It("errors when a signing key is not set", func() {
+			fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{TokenPolicy: &config.TokenPolicy{
+				ActiveKeyID: "key-2",
+				Keys:        []config.TokenKey{{ID: "key-2", SigningKeyEnv: "TOKEN_KEY_UNSET"}},
+			}}, nil)
+			Expect(manager.UpdateTokenPolicy()).Should(MatchError("signing key [key-2] of token-policy is not set, TOKEN_KEY_UNSET is empty"))
+			Expect(fakeUaa.UpdateTokenPolicyCallCount()).Should(Equal(0))
+		})
+
+		It("returns the error of getting the policy", func() {
+			fakeUaa.GetTokenPolicyReturns(nil, errors.New("forbidden"))
+			Expect(manager.UpdateTokenPolicy()).Should(MatchError("forbidden"))
+		})
+	})
+})
@@ -0,0 +1,6 @@
+package tokenpolicy
+
+//Manager -
+type Manager interface {
+	UpdateTokenPolicy() error
+}
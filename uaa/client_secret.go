@@ -0,0 +1,78 @@
+package uaa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pivotalservices/cf-mgmt/httpclient"
+	"github.com/xchapter7x/lo"
+	"golang.org/x/oauth2"
+)
+
+// uaa keeps at most two secrets per client, ADD adds the new secret next to
+// the current one and DELETE deletes the older one, so that a secret can be
+// rotated without the client being unable to authenticate in between
+const (
+	secretChangeAdd    = "ADD"
+	secretChangeDelete = "DELETE"
+)
+
+//AddClientSecret - adds a second secret to a client, which requires the clients.secret scope for
+//the client's own secret
+func (m *DefaultUAAManager) AddClientSecret(clientID, oldSecret, newSecret string) error {
+	if m.Peek {
+		lo.G.Infof("[dry-run]: updating client %s with a new secret", clientID)
+		return nil
+	}
+	lo.G.Infof("updating client %s with a new secret", clientID)
+	return m.changeClientSecret(clientID, map[string]string{
+		"clientId":   clientID,
+		"oldSecret":  oldSecret,
+		"secret":     newSecret,
+		"changeMode": secretChangeAdd,
+	})
+}
+
+//DeleteOldClientSecret - deletes the older of the two secrets of a client, keeping the secret added last
+func (m *DefaultUAAManager) DeleteOldClientSecret(clientID string) error {
+	if m.Peek {
+		lo.G.Infof("[dry-run]: deleting the old secret of client %s", clientID)
+		return nil
+	}
+	lo.G.Infof("deleting the old secret of client %s", clientID)
+	return m.changeClientSecret(clientID, map[string]string{
+		"clientId":   clientID,
+		"changeMode": secretChangeDelete,
+	})
+}
+
+func (m *DefaultUAAManager) changeClientSecret(clientID string, change map[string]string) error {
+	data, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	headers, body, err := m.Client.Curl(fmt.Sprintf("/oauth/clients/%s/secret", clientID), "PUT", string(data), []string{"Accept: application/json", "Content-Type: application/json"})
+	if err != nil {
+		return fmt.Errorf("unable to change the secret of client %s: %v", clientID, err)
+	}
+	if status := statusLine(headers); !strings.Contains(status, " 200 ") {
+		return fmt.Errorf("unable to change the secret of client %s, the client needs the clients.secret scope: %s %s", clientID, status, strings.TrimSpace(body))
+	}
+	return nil
+}
+
+//VerifyClientSecret - requests a token of the client with the secret, to verify the secret before relying on it
+func VerifyClientSecret(sysDomain, clientID, clientSecret string) error {
+	_, credentials, err := clientCredentials(sysDomain, clientID, clientSecret)
+	if err != nil {
+		return err
+	}
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: httpclient.Transport()})
+	if _, err := credentials.Token(ctx); err != nil {
+		return fmt.Errorf("unable to get a token of client %s: %v", clientID, err)
+	}
+	return nil
+}
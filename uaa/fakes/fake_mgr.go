@@ -16,6 +16,22 @@ type FakeManager struct {
 		result1 map[string]*go_uaa.User
 		result2 error
 	}
+	ListUsersByNameStub        func(names []string) (map[uaa.UserKey]*go_uaa.User, error)
+	listUsersByNameMutex       sync.RWMutex
+	listUsersByNameArgsForCall []struct {
+		names []string
+	}
+	listUsersByNameReturns struct {
+		result1 map[uaa.UserKey]*go_uaa.User
+		result2 error
+	}
+	ListAllUsersStub        func() ([]*go_uaa.User, error)
+	listAllUsersMutex       sync.RWMutex
+	listAllUsersArgsForCall []struct{}
+	listAllUsersReturns     struct {
+		result1 []*go_uaa.User
+		result2 error
+	}
 	CreateExternalUserStub        func(userName, userEmail, externalID, origin string) (err error)
 	createExternalUserMutex       sync.RWMutex
 	createExternalUserArgsForCall []struct {
@@ -27,6 +43,139 @@ type FakeManager struct {
 	createExternalUserReturns struct {
 		result1 error
 	}
+	CreateInternalUserStub        func(userName, userEmail, password string) (*go_uaa.User, error)
+	createInternalUserMutex       sync.RWMutex
+	createInternalUserArgsForCall []struct {
+		userName  string
+		userEmail string
+		password  string
+	}
+	createInternalUserReturns struct {
+		result1 *go_uaa.User
+		result2 error
+	}
+	UpdateUserOriginStub        func(user go_uaa.User, userName, externalID, origin string) error
+	updateUserOriginMutex       sync.RWMutex
+	updateUserOriginArgsForCall []struct {
+		user       go_uaa.User
+		userName   string
+		externalID string
+		origin     string
+	}
+	updateUserOriginReturns struct {
+		result1 error
+	}
+	DeleteUserStub        func(user go_uaa.User) error
+	deleteUserMutex       sync.RWMutex
+	deleteUserArgsForCall []struct {
+		user go_uaa.User
+	}
+	deleteUserReturns struct {
+		result1 error
+	}
+	GetGroupStub        func(name string) (*go_uaa.Group, error)
+	getGroupMutex       sync.RWMutex
+	getGroupArgsForCall []struct {
+		name string
+	}
+	getGroupReturns struct {
+		result1 *go_uaa.Group
+		result2 error
+	}
+	CreateGroupStub        func(name, description string) (*go_uaa.Group, error)
+	createGroupMutex       sync.RWMutex
+	createGroupArgsForCall []struct {
+		name        string
+		description string
+	}
+	createGroupReturns struct {
+		result1 *go_uaa.Group
+		result2 error
+	}
+	AddGroupMemberStub        func(group go_uaa.Group, userID, userName string) error
+	addGroupMemberMutex       sync.RWMutex
+	addGroupMemberArgsForCall []struct {
+		group    go_uaa.Group
+		userID   string
+		userName string
+	}
+	addGroupMemberReturns struct {
+		result1 error
+	}
+	RemoveGroupMemberStub        func(group go_uaa.Group, userID, userName string) error
+	removeGroupMemberMutex       sync.RWMutex
+	removeGroupMemberArgsForCall []struct {
+		group    go_uaa.Group
+		userID   string
+		userName string
+	}
+	removeGroupMemberReturns struct {
+		result1 error
+	}
+	ListIdentityProvidersStub        func() ([]uaa.IdentityProvider, error)
+	listIdentityProvidersMutex       sync.RWMutex
+	listIdentityProvidersArgsForCall []struct{}
+	listIdentityProvidersReturns     struct {
+		result1 []uaa.IdentityProvider
+		result2 error
+	}
+	CreateIdentityProviderStub        func(provider uaa.IdentityProvider) error
+	createIdentityProviderMutex       sync.RWMutex
+	createIdentityProviderArgsForCall []struct {
+		provider uaa.IdentityProvider
+	}
+	createIdentityProviderReturns struct {
+		result1 error
+	}
+	UpdateIdentityProviderStub        func(provider uaa.IdentityProvider) error
+	updateIdentityProviderMutex       sync.RWMutex
+	updateIdentityProviderArgsForCall []struct {
+		provider uaa.IdentityProvider
+	}
+	updateIdentityProviderReturns struct {
+		result1 error
+	}
+	DeleteIdentityProviderStub        func(provider uaa.IdentityProvider) error
+	deleteIdentityProviderMutex       sync.RWMutex
+	deleteIdentityProviderArgsForCall []struct {
+		provider uaa.IdentityProvider
+	}
+	deleteIdentityProviderReturns struct {
+		result1 error
+	}
+	GetTokenPolicyStub        func() (*uaa.TokenPolicy, error)
+	getTokenPolicyMutex       sync.RWMutex
+	getTokenPolicyArgsForCall []struct{}
+	getTokenPolicyReturns     struct {
+		result1 *uaa.TokenPolicy
+		result2 error
+	}
+	UpdateTokenPolicyStub        func(policy uaa.TokenPolicy) error
+	updateTokenPolicyMutex       sync.RWMutex
+	updateTokenPolicyArgsForCall []struct {
+		policy uaa.TokenPolicy
+	}
+	updateTokenPolicyReturns struct {
+		result1 error
+	}
+	AddClientSecretStub        func(clientID string, oldSecret string, newSecret string) error
+	addClientSecretMutex       sync.RWMutex
+	addClientSecretArgsForCall []struct {
+		clientID  string
+		oldSecret string
+		newSecret string
+	}
+	addClientSecretReturns struct {
+		result1 error
+	}
+	DeleteOldClientSecretStub        func(clientID string) error
+	deleteOldClientSecretMutex       sync.RWMutex
+	deleteOldClientSecretArgsForCall []struct {
+		clientID string
+	}
+	deleteOldClientSecretReturns struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -57,6 +206,71 @@ func (fake *FakeManager) ListUsersReturns(result1 map[string]*go_uaa.User, resul
 	}{result1, result2}
 }
 
+func (fake *FakeManager) ListUsersByName(names []string) (map[uaa.UserKey]*go_uaa.User, error) {
+	var namesCopy []string
+	if names != nil {
+		namesCopy = make([]string, len(names))
+		copy(namesCopy, names)
+	}
+	fake.listUsersByNameMutex.Lock()
+	fake.listUsersByNameArgsForCall = append(fake.listUsersByNameArgsForCall, struct {
+		names []string
+	}{namesCopy})
+	fake.recordInvocation("ListUsersByName", []interface{}{namesCopy})
+	fake.listUsersByNameMutex.Unlock()
+	if fake.ListUsersByNameStub != nil {
+		return fake.ListUsersByNameStub(names)
+	} else {
+		return fake.listUsersByNameReturns.result1, fake.listUsersByNameReturns.result2
+	}
+}
+
+func (fake *FakeManager) ListUsersByNameCallCount() int {
+	fake.listUsersByNameMutex.RLock()
+	defer fake.listUsersByNameMutex.RUnlock()
+	return len(fake.listUsersByNameArgsForCall)
+}
+
+func (fake *FakeManager) ListUsersByNameArgsForCall(i int) []string {
+	fake.listUsersByNameMutex.RLock()
+	defer fake.listUsersByNameMutex.RUnlock()
+	return fake.listUsersByNameArgsForCall[i].names
+}
+
+func (fake *FakeManager) ListUsersByNameReturns(result1 map[uaa.UserKey]*go_uaa.User, result2 error) {
+	fake.ListUsersByNameStub = nil
+	fake.listUsersByNameReturns = struct {
+		result1 map[uaa.UserKey]*go_uaa.User
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) ListAllUsers() ([]*go_uaa.User, error) {
+	fake.listAllUsersMutex.Lock()
+	fake.listAllUsersArgsForCall = append(fake.listAllUsersArgsForCall, struct{}{})
+	fake.recordInvocation("ListAllUsers", []interface{}{})
+	fake.listAllUsersMutex.Unlock()
+	if fake.ListAllUsersStub != nil {
+		return fake.ListAllUsersStub()
+	} else {
+		return fake.listAllUsersReturns.result1, fake.listAllUsersReturns.result2
+	}
+}
+
+func (fake *FakeManager) ListAllUsersCallCount() int {
+	fake.listAllUsersMutex.RLock()
+	defer fake.listAllUsersMutex.RUnlock()
+	return len(fake.listAllUsersArgsForCall)
+}
+
+func (fake *FakeManager) ListAllUsersReturns(result1 []*go_uaa.User, result2 error) {
+	fake.ListAllUsersStub = nil
+	fake.listAllUsersReturns = struct {
+		result1 []*go_uaa.User
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeManager) CreateExternalUser(userName string, userEmail string, externalID string, origin string) (err error) {
 	fake.createExternalUserMutex.Lock()
 	fake.createExternalUserArgsForCall = append(fake.createExternalUserArgsForCall, struct {
@@ -93,13 +307,543 @@ func (fake *FakeManager) CreateExternalUserReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeManager) CreateInternalUser(userName string, userEmail string, password string) (*go_uaa.User, error) {
+	fake.createInternalUserMutex.Lock()
+	fake.createInternalUserArgsForCall = append(fake.createInternalUserArgsForCall, struct {
+		userName  string
+		userEmail string
+		password  string
+	}{userName, userEmail, password})
+	fake.recordInvocation("CreateInternalUser", []interface{}{userName, userEmail, password})
+	fake.createInternalUserMutex.Unlock()
+	if fake.CreateInternalUserStub != nil {
+		return fake.CreateInternalUserStub(userName, userEmail, password)
+	} else {
+		return fake.createInternalUserReturns.result1, fake.createInternalUserReturns.result2
+	}
+}
+
+func (fake *FakeManager) CreateInternalUserCallCount() int {
+	fake.createInternalUserMutex.RLock()
+	defer fake.createInternalUserMutex.RUnlock()
+	return len(fake.createInternalUserArgsForCall)
+}
+
+func (fake *FakeManager) CreateInternalUserArgsForCall(i int) (string, string, string) {
+	fake.createInternalUserMutex.RLock()
+	defer fake.createInternalUserMutex.RUnlock()
+	return fake.createInternalUserArgsForCall[i].userName, fake.createInternalUserArgsForCall[i].userEmail, fake.createInternalUserArgsForCall[i].password
+}
+
+func (fake *FakeManager) CreateInternalUserReturns(result1 *go_uaa.User, result2 error) {
+	fake.CreateInternalUserStub = nil
+	fake.createInternalUserReturns = struct {
+		result1 *go_uaa.User
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) UpdateUserOrigin(user go_uaa.User, userName string, externalID string, origin string) error {
+	fake.updateUserOriginMutex.Lock()
+	fake.updateUserOriginArgsForCall = append(fake.updateUserOriginArgsForCall, struct {
+		user       go_uaa.User
+		userName   string
+		externalID string
+		origin     string
+	}{user, userName, externalID, origin})
+	fake.recordInvocation("UpdateUserOrigin", []interface{}{user, userName, externalID, origin})
+	fake.updateUserOriginMutex.Unlock()
+	if fake.UpdateUserOriginStub != nil {
+		return fake.UpdateUserOriginStub(user, userName, externalID, origin)
+	} else {
+		return fake.updateUserOriginReturns.result1
+	}
+}
+
+func (fake *FakeManager) UpdateUserOriginCallCount() int {
+	fake.updateUserOriginMutex.RLock()
+	defer fake.updateUserOriginMutex.RUnlock()
+	return len(fake.updateUserOriginArgsForCall)
+}
+
+func (fake *FakeManager) UpdateUserOriginArgsForCall(i int) (go_uaa.User, string, string, string) {
+	fake.updateUserOriginMutex.RLock()
+	defer fake.updateUserOriginMutex.RUnlock()
+	return fake.updateUserOriginArgsForCall[i].user, fake.updateUserOriginArgsForCall[i].userName, fake.updateUserOriginArgsForCall[i].externalID, fake.updateUserOriginArgsForCall[i].origin
+}
+
+func (fake *FakeManager) UpdateUserOriginReturns(result1 error) {
+	fake.UpdateUserOriginStub = nil
+	fake.updateUserOriginReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) DeleteUser(user go_uaa.User) error {
+	fake.deleteUserMutex.Lock()
+	fake.deleteUserArgsForCall = append(fake.deleteUserArgsForCall, struct {
+		user go_uaa.User
+	}{user})
+	fake.recordInvocation("DeleteUser", []interface{}{user})
+	fake.deleteUserMutex.Unlock()
+	if fake.DeleteUserStub != nil {
+		return fake.DeleteUserStub(user)
+	} else {
+		return fake.deleteUserReturns.result1
+	}
+}
+
+func (fake *FakeManager) DeleteUserCallCount() int {
+	fake.deleteUserMutex.RLock()
+	defer fake.deleteUserMutex.RUnlock()
+	return len(fake.deleteUserArgsForCall)
+}
+
+func (fake *FakeManager) DeleteUserArgsForCall(i int) go_uaa.User {
+	fake.deleteUserMutex.RLock()
+	defer fake.deleteUserMutex.RUnlock()
+	return fake.deleteUserArgsForCall[i].user
+}
+
+func (fake *FakeManager) DeleteUserReturns(result1 error) {
+	fake.DeleteUserStub = nil
+	fake.deleteUserReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) GetGroup(name string) (*go_uaa.Group, error) {
+	fake.getGroupMutex.Lock()
+	fake.getGroupArgsForCall = append(fake.getGroupArgsForCall, struct {
+		name string
+	}{name})
+	fake.recordInvocation("GetGroup", []interface{}{name})
+	fake.getGroupMutex.Unlock()
+	if fake.GetGroupStub != nil {
+		return fake.GetGroupStub(name)
+	} else {
+		return fake.getGroupReturns.result1, fake.getGroupReturns.result2
+	}
+}
+
+func (fake *FakeManager) GetGroupCallCount() int {
+	fake.getGroupMutex.RLock()
+	defer fake.getGroupMutex.RUnlock()
+	return len(fake.getGroupArgsForCall)
+}
+
+func (fake *FakeManager) GetGroupArgsForCall(i int) string {
+	fake.getGroupMutex.RLock()
+	defer fake.getGroupMutex.RUnlock()
+	return fake.getGroupArgsForCall[i].name
+}
+
+func (fake *FakeManager) GetGroupReturns(result1 *go_uaa.Group, result2 error) {
+	fake.GetGroupStub = nil
+	fake.getGroupReturns = struct {
+		result1 *go_uaa.Group
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) CreateGroup(name string, description string) (*go_uaa.Group, error) {
+	fake.createGroupMutex.Lock()
+	fake.createGroupArgsForCall = append(fake.createGroupArgsForCall, struct {
+		name        string
+		description string
+	}{name, description})
+	fake.recordInvocation("CreateGroup", []interface{}{name, description})
+	fake.createGroupMutex.Unlock()
+	if fake.CreateGroupStub != nil {
+		return fake.CreateGroupStub(name, description)
+	} else {
+		return fake.createGroupReturns.result1, fake.createGroupReturns.result2
+	}
+}
+
+func (fake *FakeManager) CreateGroupCallCount() int {
+	fake.createGroupMutex.RLock()
+	defer fake.createGroupMutex.RUnlock()
+	return len(fake.createGroupArgsForCall)
+}
+
+func (fake *FakeManager) CreateGroupArgsForCall(i int) (string, string) {
+	fake.createGroupMutex.RLock()
+	defer fake.createGroupMutex.RUnlock()
+	return fake.createGroupArgsForCall[i].name, fake.createGroupArgsForCall[i].description
+}
+
+func (fake *FakeManager) CreateGroupReturns(result1 *go_uaa.Group, result2 error) {
+	fake.CreateGroupStub = nil
+	fake.createGroupReturns = struct {
+		result1 *go_uaa.Group
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) AddGroupMember(group go_uaa.Group, userID string, userName string) error {
+	fake.addGroupMemberMutex.Lock()
+	fake.addGroupMemberArgsForCall = append(fake.addGroupMemberArgsForCall, struct {
+		group    go_uaa.Group
+		userID   string
+		userName string
+	}{group, userID, userName})
+	fake.recordInvocation("AddGroupMember", []interface{}{group, userID, userName})
+	fake.addGroupMemberMutex.Unlock()
+	if fake.AddGroupMemberStub != nil {
+		return fake.AddGroupMemberStub(group, userID, userName)
+	} else {
+		return fake.addGroupMemberReturns.result1
+	}
+}
+
+func (fake *FakeManager) AddGroupMemberCallCount() int {
+	fake.addGroupMemberMutex.RLock()
+	defer fake.addGroupMemberMutex.RUnlock()
+	return len(fake.addGroupMemberArgsForCall)
+}
+
+func (fake *FakeManager) AddGroupMemberArgsForCall(i int) (go_uaa.Group, string, string) {
+	fake.addGroupMemberMutex.RLock()
+	defer fake.addGroupMemberMutex.RUnlock()
+	return fake.addGroupMemberArgsForCall[i].group, fake.addGroupMemberArgsForCall[i].userID, fake.addGroupMemberArgsForCall[i].userName
+}
+
+func (fake *FakeManager) AddGroupMemberReturns(result1 error) {
+	fake.AddGroupMemberStub = nil
+	fake.addGroupMemberReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) RemoveGroupMember(group go_uaa.Group, userID string, userName string) error {
+	fake.removeGroupMemberMutex.Lock()
+	fake.removeGroupMemberArgsForCall = append(fake.removeGroupMemberArgsForCall, struct {
+		group    go_uaa.Group
+		userID   string
+		userName string
+	}{group, userID, userName})
+	fake.recordInvocation("RemoveGroupMember", []interface{}{group, userID, userName})
+	fake.removeGroupMemberMutex.Unlock()
+	if fake.RemoveGroupMemberStub != nil {
+		return fake.RemoveGroupMemberStub(group, userID, userName)
+	} else {
+		return fake.removeGroupMemberReturns.result1
+	}
+}
+
+func (fake *FakeManager) RemoveGroupMemberCallCount() int {
+	fake.removeGroupMemberMutex.RLock()
+	defer fake.removeGroupMemberMutex.RUnlock()
+	return len(fake.removeGroupMemberArgsForCall)
+}
+
+func (fake *FakeManager) RemoveGroupMemberArgsForCall(i int) (go_uaa.Group, string, string) {
+	fake.removeGroupMemberMutex.RLock()
+	defer fake.removeGroupMemberMutex.RUnlock()
+	return fake.removeGroupMemberArgsForCall[i].group, fake.removeGroupMemberArgsForCall[i].userID, fake.removeGroupMemberArgsForCall[i].userName
+}
+
+func (fake *FakeManager) RemoveGroupMemberReturns(result1 error) {
+	fake.RemoveGroupMemberStub = nil
+	fake.removeGroupMemberReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) ListIdentityProviders() ([]uaa.IdentityProvider, error) {
+	fake.listIdentityProvidersMutex.Lock()
+	fake.listIdentityProvidersArgsForCall = append(fake.listIdentityProvidersArgsForCall, struct{}{})
+	fake.recordInvocation("ListIdentityProviders", []interface{}{})
+	fake.listIdentityProvidersMutex.Unlock()
+	if fake.ListIdentityProvidersStub != nil {
+		return fake.ListIdentityProvidersStub()
+	} else {
+		return fake.listIdentityProvidersReturns.result1, fake.listIdentityProvidersReturns.result2
+	}
+}
+
+func (fake *FakeManager) ListIdentityProvidersCallCount() int {
+	fake.listIdentityProvidersMutex.RLock()
+	defer fake.listIdentityProvidersMutex.RUnlock()
+	return len(fake.listIdentityProvidersArgsForCall)
+}
+
+func (fake *FakeManager) ListIdentityProvidersReturns(result1 []uaa.IdentityProvider, result2 error) {
+	fake.ListIdentityProvidersStub = nil
+	fake.listIdentityProvidersReturns = struct {
+		result1 []uaa.IdentityProvider
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) CreateIdentityProvider(provider uaa.IdentityProvider) error {
+	fake.createIdentityProviderMutex.Lock()
+	fake.createIdentityProviderArgsForCall = append(fake.createIdentityProviderArgsForCall, struct {
+		provider uaa.IdentityProvider
+	}{provider})
+	fake.recordInvocation("CreateIdentityProvider", []interface{}{provider})
+	fake.createIdentityProviderMutex.Unlock()
+	if fake.CreateIdentityProviderStub != nil {
+		return fake.CreateIdentityProviderStub(provider)
+	} else {
+		return fake.createIdentityProviderReturns.result1
+	}
+}
+
+func (fake *FakeManager) CreateIdentityProviderCallCount() int {
+	fake.createIdentityProviderMutex.RLock()
+	defer fake.createIdentityProviderMutex.RUnlock()
+	return len(fake.createIdentityProviderArgsForCall)
+}
+
+func (fake *FakeManager) CreateIdentityProviderArgsForCall(i int) uaa.IdentityProvider {
+	fake.createIdentityProviderMutex.RLock()
+	defer fake.createIdentityProviderMutex.RUnlock()
+	return fake.createIdentityProviderArgsForCall[i].provider
+}
+
+func (fake *FakeManager) CreateIdentityProviderReturns(result1 error) {
+	fake.CreateIdentityProviderStub = nil
+	fake.createIdentityProviderReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) UpdateIdentityProvider(provider uaa.IdentityProvider) error {
+	fake.updateIdentityProviderMutex.Lock()
+	fake.updateIdentityProviderArgsForCall = append(fake.updateIdentityProviderArgsForCall, struct {
+		provider uaa.IdentityProvider
+	}{provider})
+	fake.recordInvocation("UpdateIdentityProvider", []interface{}{provider})
+	fake.updateIdentityProviderMutex.Unlock()
+	if fake.UpdateIdentityProviderStub != nil {
+		return fake.UpdateIdentityProviderStub(provider)
+	} else {
+		return fake.updateIdentityProviderReturns.result1
+	}
+}
+
+func (fake *FakeManager) UpdateIdentityProviderCallCount() int {
+	fake.updateIdentityProviderMutex.RLock()
+	defer fake.updateIdentityProviderMutex.RUnlock()
+	return len(fake.updateIdentityProviderArgsForCall)
+}
+
+func (fake *FakeManager) UpdateIdentityProviderArgsForCall(i int) uaa.IdentityProvider {
+	fake.updateIdentityProviderMutex.RLock()
+	defer fake.updateIdentityProviderMutex.RUnlock()
+	return fake.updateIdentityProviderArgsForCall[i].provider
+}
+
+func (fake *FakeManager) UpdateIdentityProviderReturns(result1 error) {
+	fake.UpdateIdentityProviderStub = nil
+	fake.updateIdentityProviderReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) DeleteIdentityProvider(provider uaa.IdentityProvider) error {
+	fake.deleteIdentityProviderMutex.Lock()
+	fake.deleteIdentityProviderArgsForCall = append(fake.deleteIdentityProviderArgsForCall, struct {
+		provider uaa.IdentityProvider
+	}{provider})
+	fake.recordInvocation("DeleteIdentityProvider", []interface{}{provider})
+	fake.deleteIdentityProviderMutex.Unlock()
+	if fake.DeleteIdentityProviderStub != nil {
+		return fake.DeleteIdentityProviderStub(provider)
+	} else {
+		return fake.deleteIdentityProviderReturns.result1
+	}
+}
+
+func (fake *FakeManager) DeleteIdentityProviderCallCount() int {
+	fake.deleteIdentityProviderMutex.RLock()
+	defer fake.deleteIdentityProviderMutex.RUnlock()
+	return len(fake.deleteIdentityProviderArgsForCall)
+}
+
+func (fake *FakeManager) DeleteIdentityProviderArgsForCall(i int) uaa.IdentityProvider {
+	fake.deleteIdentityProviderMutex.RLock()
+	defer fake.deleteIdentityProviderMutex.RUnlock()
+	return fake.deleteIdentityProviderArgsForCall[i].provider
+}
+
+func (fake *FakeManager) DeleteIdentityProviderReturns(result1 error) {
+	fake.DeleteIdentityProviderStub = nil
+	fake.deleteIdentityProviderReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) GetTokenPolicy() (*uaa.TokenPolicy, error) {
+	fake.getTokenPolicyMutex.Lock()
+	fake.getTokenPolicyArgsForCall = append(fake.getTokenPolicyArgsForCall, struct{}{})
+	fake.recordInvocation("GetTokenPolicy", []interface{}{})
+	fake.getTokenPolicyMutex.Unlock()
+	if fake.GetTokenPolicyStub != nil {
+		return fake.GetTokenPolicyStub()
+	} else {
+		return fake.getTokenPolicyReturns.result1, fake.getTokenPolicyReturns.result2
+	}
+}
+
+func (fake *FakeManager) GetTokenPolicyCallCount() int {
+	fake.getTokenPolicyMutex.RLock()
+	defer fake.getTokenPolicyMutex.RUnlock()
+	return len(fake.getTokenPolicyArgsForCall)
+}
+
+func (fake *FakeManager) GetTokenPolicyReturns(result1 *uaa.TokenPolicy, result2 error) {
+	fake.GetTokenPolicyStub = nil
+	fake.getTokenPolicyReturns = struct {
+		result1 *uaa.TokenPolicy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) UpdateTokenPolicy(policy uaa.TokenPolicy) error {
+	fake.updateTokenPolicyMutex.Lock()
+	fake.updateTokenPolicyArgsForCall = append(fake.updateTokenPolicyArgsForCall, struct {
+		policy uaa.TokenPolicy
+	}{policy})
+	fake.recordInvocation("UpdateTokenPolicy", []interface{}{policy})
+	fake.updateTokenPolicyMutex.Unlock()
+	if fake.UpdateTokenPolicyStub != nil {
+		return fake.UpdateTokenPolicyStub(policy)
+	} else {
+		return fake.updateTokenPolicyReturns.result1
+	}
+}
+
+func (fake *FakeManager) UpdateTokenPolicyCallCount() int {
+	fake.updateTokenPolicyMutex.RLock()
+	defer fake.updateTokenPolicyMutex.RUnlock()
+	return len(fake.updateTokenPolicyArgsForCall)
+}
+
+func (fake *FakeManager) UpdateTokenPolicyArgsForCall(i int) uaa.TokenPolicy {
+	fake.updateTokenPolicyMutex.RLock()
+	defer fake.updateTokenPolicyMutex.RUnlock()
+	return fake.updateTokenPolicyArgsForCall[i].policy
+}
+
+func (fake *FakeManager) UpdateTokenPolicyReturns(result1 error) {
+	fake.UpdateTokenPolicyStub = nil
+	fake.updateTokenPolicyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) AddClientSecret(clientID string, oldSecret string, newSecret string) error {
+	fake.addClientSecretMutex.Lock()
+	fake.addClientSecretArgsForCall = append(fake.addClientSecretArgsForCall, struct {
+		clientID  string
+		oldSecret string
+		newSecret string
+	}{clientID, oldSecret, newSecret})
+	fake.recordInvocation("AddClientSecret", []interface{}{clientID, oldSecret, newSecret})
+	fake.addClientSecretMutex.Unlock()
+	if fake.AddClientSecretStub != nil {
+		return fake.AddClientSecretStub(clientID, oldSecret, newSecret)
+	} else {
+		return fake.addClientSecretReturns.result1
+	}
+}
+
+func (fake *FakeManager) AddClientSecretCallCount() int {
+	fake.addClientSecretMutex.RLock()
+	defer fake.addClientSecretMutex.RUnlock()
+	return len(fake.addClientSecretArgsForCall)
+}
+
+func (fake *FakeManager) AddClientSecretArgsForCall(i int) (string, string, string) {
+	fake.addClientSecretMutex.RLock()
+	defer fake.addClientSecretMutex.RUnlock()
+	return fake.addClientSecretArgsForCall[i].clientID, fake.addClientSecretArgsForCall[i].oldSecret, fake.addClientSecretArgsForCall[i].newSecret
+}
+
+func (fake *FakeManager) AddClientSecretReturns(result1 error) {
+	fake.AddClientSecretStub = nil
+	fake.addClientSecretReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) DeleteOldClientSecret(clientID string) error {
+	fake.deleteOldClientSecretMutex.Lock()
+	fake.deleteOldClientSecretArgsForCall = append(fake.deleteOldClientSecretArgsForCall, struct {
+		clientID string
+	}{clientID})
+	fake.recordInvocation("DeleteOldClientSecret", []interface{}{clientID})
+	fake.deleteOldClientSecretMutex.Unlock()
+	if fake.DeleteOldClientSecretStub != nil {
+		return fake.DeleteOldClientSecretStub(clientID)
+	} else {
+		return fake.deleteOldClientSecretReturns.result1
+	}
+}
+
+func (fake *FakeManager) DeleteOldClientSecretCallCount() int {
+	fake.deleteOldClientSecretMutex.RLock()
+	defer fake.deleteOldClientSecretMutex.RUnlock()
+	return len(fake.deleteOldClientSecretArgsForCall)
+}
+
+func (fake *FakeManager) DeleteOldClientSecretArgsForCall(i int) string {
+	fake.deleteOldClientSecretMutex.RLock()
+	defer fake.deleteOldClientSecretMutex.RUnlock()
+	return fake.deleteOldClientSecretArgsForCall[i].clientID
+}
+
+func (fake *FakeManager) DeleteOldClientSecretReturns(result1 error) {
+	fake.DeleteOldClientSecretStub = nil
+	fake.deleteOldClientSecretReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeManager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.listUsersMutex.RLock()
 	defer fake.listUsersMutex.RUnlock()
+	fake.listUsersByNameMutex.RLock()
+	defer fake.listUsersByNameMutex.RUnlock()
+	fake.listAllUsersMutex.RLock()
+	defer fake.listAllUsersMutex.RUnlock()
 	fake.createExternalUserMutex.RLock()
 	defer fake.createExternalUserMutex.RUnlock()
+	fake.createInternalUserMutex.RLock()
+	defer fake.createInternalUserMutex.RUnlock()
+	fake.updateUserOriginMutex.RLock()
+	defer fake.updateUserOriginMutex.RUnlock()
+	fake.deleteUserMutex.RLock()
+	defer fake.deleteUserMutex.RUnlock()
+	fake.getGroupMutex.RLock()
+	defer fake.getGroupMutex.RUnlock()
+	fake.createGroupMutex.RLock()
+	defer fake.createGroupMutex.RUnlock()
+	fake.addGroupMemberMutex.RLock()
+	defer fake.addGroupMemberMutex.RUnlock()
+	fake.removeGroupMemberMutex.RLock()
+	defer fake.removeGroupMemberMutex.RUnlock()
+	fake.listIdentityProvidersMutex.RLock()
+	defer fake.listIdentityProvidersMutex.RUnlock()
+	fake.createIdentityProviderMutex.RLock()
+	defer fake.createIdentityProviderMutex.RUnlock()
+	fake.updateIdentityProviderMutex.RLock()
+	defer fake.updateIdentityProviderMutex.RUnlock()
+	fake.deleteIdentityProviderMutex.RLock()
+	defer fake.deleteIdentityProviderMutex.RUnlock()
+	fake.getTokenPolicyMutex.RLock()
+	defer fake.getTokenPolicyMutex.RUnlock()
+	fake.updateTokenPolicyMutex.RLock()
+	defer fake.updateTokenPolicyMutex.RUnlock()
+	fake.addClientSecretMutex.RLock()
+	defer fake.addClientSecretMutex.RUnlock()
+	fake.deleteOldClientSecretMutex.RLock()
+	defer fake.deleteOldClientSecretMutex.RUnlock()
 	return fake.invocations
 }
 
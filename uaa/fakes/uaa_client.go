@@ -17,18 +17,104 @@ type FakeUaa struct {
 		result1 *go_uaa.User
 		result2 error
 	}
-	ListAllUsersStub        func(filter string, sortBy string, attributes string, sortOrder go_uaa.SortOrder) ([]go_uaa.User, error)
-	listAllUsersMutex       sync.RWMutex
-	listAllUsersArgsForCall []struct {
+	ListUsersStub        func(filter string, sortBy string, attributes string, sortOrder go_uaa.SortOrder, startIndex int, itemsPerPage int) ([]go_uaa.User, go_uaa.Page, error)
+	listUsersMutex       sync.RWMutex
+	listUsersArgsForCall []struct {
+		filter       string
+		sortBy       string
+		attributes   string
+		sortOrder    go_uaa.SortOrder
+		startIndex   int
+		itemsPerPage int
+	}
+	listUsersReturns struct {
+		result1 []go_uaa.User
+		result2 go_uaa.Page
+		result3 error
+	}
+	GetUserStub        func(userID string) (*go_uaa.User, error)
+	getUserMutex       sync.RWMutex
+	getUserArgsForCall []struct {
+		userID string
+	}
+	getUserReturns struct {
+		result1 *go_uaa.User
+		result2 error
+	}
+	UpdateUserStub        func(user go_uaa.User) (*go_uaa.User, error)
+	updateUserMutex       sync.RWMutex
+	updateUserArgsForCall []struct {
+		user go_uaa.User
+	}
+	updateUserReturns struct {
+		result1 *go_uaa.User
+		result2 error
+	}
+	DeleteUserStub        func(userID string) (*go_uaa.User, error)
+	deleteUserMutex       sync.RWMutex
+	deleteUserArgsForCall []struct {
+		userID string
+	}
+	deleteUserReturns struct {
+		result1 *go_uaa.User
+		result2 error
+	}
+	ListAllGroupsStub        func(filter string, sortBy string, attributes string, sortOrder go_uaa.SortOrder) ([]go_uaa.Group, error)
+	listAllGroupsMutex       sync.RWMutex
+	listAllGroupsArgsForCall []struct {
 		filter     string
 		sortBy     string
 		attributes string
 		sortOrder  go_uaa.SortOrder
 	}
-	listAllUsersReturns struct {
-		result1 []go_uaa.User
+	listAllGroupsReturns struct {
+		result1 []go_uaa.Group
+		result2 error
+	}
+	CreateGroupStub        func(group go_uaa.Group) (*go_uaa.Group, error)
+	createGroupMutex       sync.RWMutex
+	createGroupArgsForCall []struct {
+		group go_uaa.Group
+	}
+	createGroupReturns struct {
+		result1 *go_uaa.Group
 		result2 error
 	}
+	AddGroupMemberStub        func(groupID string, memberID string, entityType string, origin string) error
+	addGroupMemberMutex       sync.RWMutex
+	addGroupMemberArgsForCall []struct {
+		groupID    string
+		memberID   string
+		entityType string
+		origin     string
+	}
+	addGroupMemberReturns struct {
+		result1 error
+	}
+	RemoveGroupMemberStub        func(groupID string, memberID string, entityType string, origin string) error
+	removeGroupMemberMutex       sync.RWMutex
+	removeGroupMemberArgsForCall []struct {
+		groupID    string
+		memberID   string
+		entityType string
+		origin     string
+	}
+	removeGroupMemberReturns struct {
+		result1 error
+	}
+	CurlStub        func(path string, method string, data string, headers []string) (string, string, error)
+	curlMutex       sync.RWMutex
+	curlArgsForCall []struct {
+		path    string
+		method  string
+		data    string
+		headers []string
+	}
+	curlReturns struct {
+		result1 string
+		result2 string
+		result3 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -67,50 +153,357 @@ func (fake *FakeUaa) CreateUserReturns(result1 *go_uaa.User, result2 error) {
 	}{result1, result2}
 }
 
-func (fake *FakeUaa) ListAllUsers(filter string, sortBy string, attributes string, sortOrder go_uaa.SortOrder) ([]go_uaa.User, error) {
-	fake.listAllUsersMutex.Lock()
-	fake.listAllUsersArgsForCall = append(fake.listAllUsersArgsForCall, struct {
+func (fake *FakeUaa) ListUsers(filter string, sortBy string, attributes string, sortOrder go_uaa.SortOrder, startIndex int, itemsPerPage int) ([]go_uaa.User, go_uaa.Page, error) {
+	fake.listUsersMutex.Lock()
+	fake.listUsersArgsForCall = append(fake.listUsersArgsForCall, struct {
+		filter       string
+		sortBy       string
+		attributes   string
+		sortOrder    go_uaa.SortOrder
+		startIndex   int
+		itemsPerPage int
+	}{filter, sortBy, attributes, sortOrder, startIndex, itemsPerPage})
+	fake.recordInvocation("ListUsers", []interface{}{filter, sortBy, attributes, sortOrder, startIndex, itemsPerPage})
+	fake.listUsersMutex.Unlock()
+	if fake.ListUsersStub != nil {
+		return fake.ListUsersStub(filter, sortBy, attributes, sortOrder, startIndex, itemsPerPage)
+	} else {
+		return fake.listUsersReturns.result1, fake.listUsersReturns.result2, fake.listUsersReturns.result3
+	}
+}
+
+func (fake *FakeUaa) ListUsersCallCount() int {
+	fake.listUsersMutex.RLock()
+	defer fake.listUsersMutex.RUnlock()
+	return len(fake.listUsersArgsForCall)
+}
+
+func (fake *FakeUaa) ListUsersArgsForCall(i int) (string, string, string, go_uaa.SortOrder, int, int) {
+	fake.listUsersMutex.RLock()
+	defer fake.listUsersMutex.RUnlock()
+	return fake.listUsersArgsForCall[i].filter, fake.listUsersArgsForCall[i].sortBy, fake.listUsersArgsForCall[i].attributes, fake.listUsersArgsForCall[i].sortOrder, fake.listUsersArgsForCall[i].startIndex, fake.listUsersArgsForCall[i].itemsPerPage
+}
+
+func (fake *FakeUaa) ListUsersReturns(result1 []go_uaa.User, result2 go_uaa.Page, result3 error) {
+	fake.ListUsersStub = nil
+	fake.listUsersReturns = struct {
+		result1 []go_uaa.User
+		result2 go_uaa.Page
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeUaa) GetUser(userID string) (*go_uaa.User, error) {
+	fake.getUserMutex.Lock()
+	fake.getUserArgsForCall = append(fake.getUserArgsForCall, struct {
+		userID string
+	}{userID})
+	fake.recordInvocation("GetUser", []interface{}{userID})
+	fake.getUserMutex.Unlock()
+	if fake.GetUserStub != nil {
+		return fake.GetUserStub(userID)
+	} else {
+		return fake.getUserReturns.result1, fake.getUserReturns.result2
+	}
+}
+
+func (fake *FakeUaa) GetUserCallCount() int {
+	fake.getUserMutex.RLock()
+	defer fake.getUserMutex.RUnlock()
+	return len(fake.getUserArgsForCall)
+}
+
+func (fake *FakeUaa) GetUserArgsForCall(i int) string {
+	fake.getUserMutex.RLock()
+	defer fake.getUserMutex.RUnlock()
+	return fake.getUserArgsForCall[i].userID
+}
+
+func (fake *FakeUaa) GetUserReturns(result1 *go_uaa.User, result2 error) {
+	fake.GetUserStub = nil
+	fake.getUserReturns = struct {
+		result1 *go_uaa.User
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeUaa) UpdateUser(user go_uaa.User) (*go_uaa.User, error) {
+	fake.updateUserMutex.Lock()
+	fake.updateUserArgsForCall = append(fake.updateUserArgsForCall, struct {
+		user go_uaa.User
+	}{user})
+	fake.recordInvocation("UpdateUser", []interface{}{user})
+	fake.updateUserMutex.Unlock()
+	if fake.UpdateUserStub != nil {
+		return fake.UpdateUserStub(user)
+	} else {
+		return fake.updateUserReturns.result1, fake.updateUserReturns.result2
+	}
+}
+
+func (fake *FakeUaa) UpdateUserCallCount() int {
+	fake.updateUserMutex.RLock()
+	defer fake.updateUserMutex.RUnlock()
+	return len(fake.updateUserArgsForCall)
+}
+
+func (fake *FakeUaa) UpdateUserArgsForCall(i int) go_uaa.User {
+	fake.updateUserMutex.RLock()
+	defer fake.updateUserMutex.RUnlock()
+	return fake.updateUserArgsForCall[i].user
+}
+
+func (fake *FakeUaa) UpdateUserReturns(result1 *go_uaa.User, result2 error) {
+	fake.UpdateUserStub = nil
+	fake.updateUserReturns = struct {
+		result1 *go_uaa.User
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeUaa) DeleteUser(userID string) (*go_uaa.User, error) {
+	fake.deleteUserMutex.Lock()
+	fake.deleteUserArgsForCall = append(fake.deleteUserArgsForCall, struct {
+		userID string
+	}{userID})
+	fake.recordInvocation("DeleteUser", []interface{}{userID})
+	fake.deleteUserMutex.Unlock()
+	if fake.DeleteUserStub != nil {
+		return fake.DeleteUserStub(userID)
+	} else {
+		return fake.deleteUserReturns.result1, fake.deleteUserReturns.result2
+	}
+}
+
+func (fake *FakeUaa) DeleteUserCallCount() int {
+	fake.deleteUserMutex.RLock()
+	defer fake.deleteUserMutex.RUnlock()
+	return len(fake.deleteUserArgsForCall)
+}
+
+func (fake *FakeUaa) DeleteUserArgsForCall(i int) string {
+	fake.deleteUserMutex.RLock()
+	defer fake.deleteUserMutex.RUnlock()
+	return fake.deleteUserArgsForCall[i].userID
+}
+
+func (fake *FakeUaa) DeleteUserReturns(result1 *go_uaa.User, result2 error) {
+	fake.DeleteUserStub = nil
+	fake.deleteUserReturns = struct {
+		result1 *go_uaa.User
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeUaa) ListAllGroups(filter string, sortBy string, attributes string, sortOrder go_uaa.SortOrder) ([]go_uaa.Group, error) {
+	fake.listAllGroupsMutex.Lock()
+	fake.listAllGroupsArgsForCall = append(fake.listAllGroupsArgsForCall, struct {
 		filter     string
 		sortBy     string
 		attributes string
 		sortOrder  go_uaa.SortOrder
 	}{filter, sortBy, attributes, sortOrder})
-	fake.recordInvocation("ListAllUsers", []interface{}{filter, sortBy, attributes, sortOrder})
-	fake.listAllUsersMutex.Unlock()
-	if fake.ListAllUsersStub != nil {
-		return fake.ListAllUsersStub(filter, sortBy, attributes, sortOrder)
+	fake.recordInvocation("ListAllGroups", []interface{}{filter, sortBy, attributes, sortOrder})
+	fake.listAllGroupsMutex.Unlock()
+	if fake.ListAllGroupsStub != nil {
+		return fake.ListAllGroupsStub(filter, sortBy, attributes, sortOrder)
 	} else {
-		return fake.listAllUsersReturns.result1, fake.listAllUsersReturns.result2
+		return fake.listAllGroupsReturns.result1, fake.listAllGroupsReturns.result2
 	}
 }
 
-func (fake *FakeUaa) ListAllUsersCallCount() int {
-	fake.listAllUsersMutex.RLock()
-	defer fake.listAllUsersMutex.RUnlock()
-	return len(fake.listAllUsersArgsForCall)
+func (fake *FakeUaa) ListAllGroupsCallCount() int {
+	fake.listAllGroupsMutex.RLock()
+	defer fake.listAllGroupsMutex.RUnlock()
+	return len(fake.listAllGroupsArgsForCall)
 }
 
-func (fake *FakeUaa) ListAllUsersArgsForCall(i int) (string, string, string, go_uaa.SortOrder) {
-	fake.listAllUsersMutex.RLock()
-	defer fake.listAllUsersMutex.RUnlock()
-	return fake.listAllUsersArgsForCall[i].filter, fake.listAllUsersArgsForCall[i].sortBy, fake.listAllUsersArgsForCall[i].attributes, fake.listAllUsersArgsForCall[i].sortOrder
+func (fake *FakeUaa) ListAllGroupsArgsForCall(i int) (string, string, string, go_uaa.SortOrder) {
+	fake.listAllGroupsMutex.RLock()
+	defer fake.listAllGroupsMutex.RUnlock()
+	return fake.listAllGroupsArgsForCall[i].filter, fake.listAllGroupsArgsForCall[i].sortBy, fake.listAllGroupsArgsForCall[i].attributes, fake.listAllGroupsArgsForCall[i].sortOrder
 }
 
-func (fake *FakeUaa) ListAllUsersReturns(result1 []go_uaa.User, result2 error) {
-	fake.ListAllUsersStub = nil
-	fake.listAllUsersReturns = struct {
-		result1 []go_uaa.User
+func (fake *FakeUaa) ListAllGroupsReturns(result1 []go_uaa.Group, result2 error) {
+	fake.ListAllGroupsStub = nil
+	fake.listAllGroupsReturns = struct {
+		result1 []go_uaa.Group
 		result2 error
 	}{result1, result2}
 }
 
+func (fake *FakeUaa) CreateGroup(group go_uaa.Group) (*go_uaa.Group, error) {
+	fake.createGroupMutex.Lock()
+	fake.createGroupArgsForCall = append(fake.createGroupArgsForCall, struct {
+		group go_uaa.Group
+	}{group})
+	fake.recordInvocation("CreateGroup", []interface{}{group})
+	fake.createGroupMutex.Unlock()
+	if fake.CreateGroupStub != nil {
+		return fake.CreateGroupStub(group)
+	} else {
+		return fake.createGroupReturns.result1, fake.createGroupReturns.result2
+	}
+}
+
+func (fake *FakeUaa) CreateGroupCallCount() int {
+	fake.createGroupMutex.RLock()
+	defer fake.createGroupMutex.RUnlock()
+	return len(fake.createGroupArgsForCall)
+}
+
+func (fake *FakeUaa) CreateGroupArgsForCall(i int) go_uaa.Group {
+	fake.createGroupMutex.RLock()
+	defer fake.createGroupMutex.RUnlock()
+	return fake.createGroupArgsForCall[i].group
+}
+
+func (fake *FakeUaa) CreateGroupReturns(result1 *go_uaa.Group, result2 error) {
+	fake.CreateGroupStub = nil
+	fake.createGroupReturns = struct {
+		result1 *go_uaa.Group
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeUaa) AddGroupMember(groupID string, memberID string, entityType string, origin string) error {
+	fake.addGroupMemberMutex.Lock()
+	fake.addGroupMemberArgsForCall = append(fake.addGroupMemberArgsForCall, struct {
+		groupID    string
+		memberID   string
+		entityType string
+		origin     string
+	}{groupID, memberID, entityType, origin})
+	fake.recordInvocation("AddGroupMember", []interface{}{groupID, memberID, entityType, origin})
+	fake.addGroupMemberMutex.Unlock()
+	if fake.AddGroupMemberStub != nil {
+		return fake.AddGroupMemberStub(groupID, memberID, entityType, origin)
+	} else {
+		return fake.addGroupMemberReturns.result1
+	}
+}
+
+func (fake *FakeUaa) AddGroupMemberCallCount() int {
+	fake.addGroupMemberMutex.RLock()
+	defer fake.addGroupMemberMutex.RUnlock()
+	return len(fake.addGroupMemberArgsForCall)
+}
+
+func (fake *FakeUaa) AddGroupMemberArgsForCall(i int) (string, string, string, string) {
+	fake.addGroupMemberMutex.RLock()
+	defer fake.addGroupMemberMutex.RUnlock()
+	return fake.addGroupMemberArgsForCall[i].groupID, fake.addGroupMemberArgsForCall[i].memberID, fake.addGroupMemberArgsForCall[i].entityType, fake.addGroupMemberArgsForCall[i].origin
+}
+
+func (fake *FakeUaa) AddGroupMemberReturns(result1 error) {
+	fake.AddGroupMemberStub = nil
+	fake.addGroupMemberReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeUaa) RemoveGroupMember(groupID string, memberID string, entityType string, origin string) error {
+	fake.removeGroupMemberMutex.Lock()
+	fake.removeGroupMemberArgsForCall = append(fake.removeGroupMemberArgsForCall, struct {
+		groupID    string
+		memberID   string
+		entityType string
+		origin     string
+	}{groupID, memberID, entityType, origin})
+	fake.recordInvocation("RemoveGroupMember", []interface{}{groupID, memberID, entityType, origin})
+	fake.removeGroupMemberMutex.Unlock()
+	if fake.RemoveGroupMemberStub != nil {
+		return fake.RemoveGroupMemberStub(groupID, memberID, entityType, origin)
+	} else {
+		return fake.removeGroupMemberReturns.result1
+	}
+}
+
+func (fake *FakeUaa) RemoveGroupMemberCallCount() int {
+	fake.removeGroupMemberMutex.RLock()
+	defer fake.removeGroupMemberMutex.RUnlock()
+	return len(fake.removeGroupMemberArgsForCall)
+}
+
+func (fake *FakeUaa) RemoveGroupMemberArgsForCall(i int) (string, string, string, string) {
+	fake.removeGroupMemberMutex.RLock()
+	defer fake.removeGroupMemberMutex.RUnlock()
+	return fake.removeGroupMemberArgsForCall[i].groupID, fake.removeGroupMemberArgsForCall[i].memberID, fake.removeGroupMemberArgsForCall[i].entityType, fake.removeGroupMemberArgsForCall[i].origin
+}
+
+func (fake *FakeUaa) RemoveGroupMemberReturns(result1 error) {
+	fake.RemoveGroupMemberStub = nil
+	fake.removeGroupMemberReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeUaa) Curl(path string, method string, data string, headers []string) (string, string, error) {
+	var headersCopy []string
+	if headers != nil {
+		headersCopy = make([]string, len(headers))
+		copy(headersCopy, headers)
+	}
+	fake.curlMutex.Lock()
+	fake.curlArgsForCall = append(fake.curlArgsForCall, struct {
+		path    string
+		method  string
+		data    string
+		headers []string
+	}{path, method, data, headersCopy})
+	fake.recordInvocation("Curl", []interface{}{path, method, data, headersCopy})
+	fake.curlMutex.Unlock()
+	if fake.CurlStub != nil {
+		return fake.CurlStub(path, method, data, headers)
+	} else {
+		return fake.curlReturns.result1, fake.curlReturns.result2, fake.curlReturns.result3
+	}
+}
+
+func (fake *FakeUaa) CurlCallCount() int {
+	fake.curlMutex.RLock()
+	defer fake.curlMutex.RUnlock()
+	return len(fake.curlArgsForCall)
+}
+
+func (fake *FakeUaa) CurlArgsForCall(i int) (string, string, string, []string) {
+	fake.curlMutex.RLock()
+	defer fake.curlMutex.RUnlock()
+	return fake.curlArgsForCall[i].path, fake.curlArgsForCall[i].method, fake.curlArgsForCall[i].data, fake.curlArgsForCall[i].headers
+}
+
+func (fake *FakeUaa) CurlReturns(result1 string, result2 string, result3 error) {
+	fake.CurlStub = nil
+	fake.curlReturns = struct {
+		result1 string
+		result2 string
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeUaa) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.createUserMutex.RLock()
 	defer fake.createUserMutex.RUnlock()
-	fake.listAllUsersMutex.RLock()
-	defer fake.listAllUsersMutex.RUnlock()
+	fake.listUsersMutex.RLock()
+	defer fake.listUsersMutex.RUnlock()
+	fake.getUserMutex.RLock()
+	defer fake.getUserMutex.RUnlock()
+	fake.updateUserMutex.RLock()
+	defer fake.updateUserMutex.RUnlock()
+	fake.deleteUserMutex.RLock()
+	defer fake.deleteUserMutex.RUnlock()
+	fake.listAllGroupsMutex.RLock()
+	defer fake.listAllGroupsMutex.RUnlock()
+	fake.createGroupMutex.RLock()
+	defer fake.createGroupMutex.RUnlock()
+	fake.addGroupMemberMutex.RLock()
+	defer fake.addGroupMemberMutex.RUnlock()
+	fake.removeGroupMemberMutex.RLock()
+	defer fake.removeGroupMemberMutex.RUnlock()
+	fake.curlMutex.RLock()
+	defer fake.curlMutex.RUnlock()
 	return fake.invocations
 }
 
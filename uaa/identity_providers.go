@@ -0,0 +1,97 @@
+package uaa
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/xchapter7x/lo"
+)
+
+//IdentityProvider - a uaa identity provider, users log in through the provider of their origin
+type IdentityProvider struct {
+	ID        string `json:"id,omitempty"`
+	OriginKey string `json:"originKey"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Active    bool   `json:"active"`
+	// Config is the configuration of the provider, such as the saml metadata or
+	// the oidc discovery url, keyed as in the uaa api
+	Config map[string]interface{} `json:"config,omitempty"`
+}
+
+//ListIdentityProviders - lists the identity providers of the uaa, which requires the idps.read scope
+func (m *DefaultUAAManager) ListIdentityProviders() ([]IdentityProvider, error) {
+	headers, body, err := m.Client.Curl("/identity-providers?rawConfig=true", "GET", "", []string{"Accept: application/json"})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list identity providers: %v", err)
+	}
+	if status := statusLine(headers); !strings.Contains(status, " 200 ") {
+		return nil, fmt.Errorf("unable to list identity providers, the client needs the idps.read scope: %s", status)
+	}
+	var providers []IdentityProvider
+	if err := json.Unmarshal([]byte(body), &providers); err != nil {
+		return nil, fmt.Errorf("unable to parse identity providers: %v", err)
+	}
+	return providers, nil
+}
+
+//CreateIdentityProvider - creates an identity provider, which requires the idps.write scope
+func (m *DefaultUAAManager) CreateIdentityProvider(provider IdentityProvider) error {
+	if m.Peek {
+		lo.G.Infof("[dry-run]: creating identity provider [%s] of type %s", provider.OriginKey, provider.Type)
+		return nil
+	}
+	lo.G.Infof("creating identity provider [%s] of type %s", provider.OriginKey, provider.Type)
+	provider.ID = ""
+	return m.writeIdentityProvider("/identity-providers?rawConfig=true", "POST", " 201 ", provider)
+}
+
+//UpdateIdentityProvider - replaces the name, active flag and configuration of an identity provider, which
+//requires the idps.write scope
+func (m *DefaultUAAManager) UpdateIdentityProvider(provider IdentityProvider) error {
+	if m.Peek {
+		lo.G.Infof("[dry-run]: updating identity provider [%s]", provider.OriginKey)
+		return nil
+	}
+	lo.G.Infof("updating identity provider [%s]", provider.OriginKey)
+	return m.writeIdentityProvider(fmt.Sprintf("/identity-providers/%s?rawConfig=true", provider.ID), "PUT", " 200 ", provider)
+}
+
+//DeleteIdentityProvider - deletes an identity provider and with it the users of its origin, which requires
+//the idps.write scope
+func (m *DefaultUAAManager) DeleteIdentityProvider(provider IdentityProvider) error {
+	if m.Peek {
+		lo.G.Infof("[dry-run]: deleting identity provider [%s]", provider.OriginKey)
+		return nil
+	}
+	lo.G.Infof("deleting identity provider [%s]", provider.OriginKey)
+	m.Cache.Invalidate(usersNamespace)
+	headers, _, err := m.Client.Curl(fmt.Sprintf("/identity-providers/%s", provider.ID), "DELETE", "", []string{"Accept: application/json"})
+	if err != nil {
+		return fmt.Errorf("unable to delete identity provider [%s]: %v", provider.OriginKey, err)
+	}
+	if status := statusLine(headers); !strings.Contains(status, " 200 ") {
+		return fmt.Errorf("unable to delete identity provider [%s], the client needs the idps.write scope: %s", provider.OriginKey, status)
+	}
+	return nil
+}
+
+func (m *DefaultUAAManager) writeIdentityProvider(path, method, expectedStatus string, provider IdentityProvider) error {
+	data, err := json.Marshal(provider)
+	if err != nil {
+		return err
+	}
+	headers, body, err := m.Client.Curl(path, method, string(data), []string{"Accept: application/json", "Content-Type: application/json"})
+	if err != nil {
+		return fmt.Errorf("unable to write identity provider [%s]: %v", provider.OriginKey, err)
+	}
+	if status := statusLine(headers); !strings.Contains(status, expectedStatus) {
+		return fmt.Errorf("unable to write identity provider [%s], the client needs the idps.write scope: %s %s", provider.OriginKey, status, strings.TrimSpace(body))
+	}
+	return nil
+}
+
+func statusLine(headers string) string {
+	return strings.TrimSpace(strings.SplitN(headers, "\n", 2)[0])
+}
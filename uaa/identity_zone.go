@@ -0,0 +1,108 @@
+package uaa
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/xchapter7x/lo"
+)
+
+// zonePath is the default identity zone, the zone cf-mgmt's client lives in
+const zonePath = "/identity-zones/uaa"
+
+//TokenPolicy - the token policy of an identity zone
+type TokenPolicy struct {
+	AccessTokenValidity  int    `json:"accessTokenValidity"`
+	RefreshTokenValidity int    `json:"refreshTokenValidity"`
+	JwtRevocable         bool   `json:"jwtRevocable"`
+	RefreshTokenUnique   bool   `json:"refreshTokenUnique"`
+	ActiveKeyID          string `json:"activeKeyId,omitempty"`
+	// Keys are keyed by key id, uaa does not return their signing keys
+	Keys map[string]TokenKey `json:"keys,omitempty"`
+}
+
+//TokenKey - a token signing key of an identity zone
+type TokenKey struct {
+	SigningKey string `json:"signingKey,omitempty"`
+}
+
+//GetTokenPolicy - returns the token policy of the default identity zone, which requires the zones.read scope
+func (m *DefaultUAAManager) GetTokenPolicy() (*TokenPolicy, error) {
+	zone, err := m.getZone()
+	if err != nil {
+		return nil, err
+	}
+	zoneConfig := struct {
+		Config struct {
+			TokenPolicy TokenPolicy `json:"tokenPolicy"`
+		} `json:"config"`
+	}{}
+	data, err := json.Marshal(zone)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &zoneConfig); err != nil {
+		return nil, fmt.Errorf("unable to parse the token policy of identity zone uaa: %v", err)
+	}
+	return &zoneConfig.Config.TokenPolicy, nil
+}
+
+//UpdateTokenPolicy - replaces the token policy of the default identity zone, keeping its other settings,
+//which requires the zones.write scope
+func (m *DefaultUAAManager) UpdateTokenPolicy(policy TokenPolicy) error {
+	if m.Peek {
+		lo.G.Infof("[dry-run]: updating token policy of identity zone uaa")
+		return nil
+	}
+	lo.G.Infof("updating token policy of identity zone uaa")
+	zone, err := m.getZone()
+	if err != nil {
+		return err
+	}
+	zoneConfig, _ := zone["config"].(map[string]interface{})
+	if zoneConfig == nil {
+		zoneConfig = make(map[string]interface{})
+		zone["config"] = zoneConfig
+	}
+	tokenPolicy, _ := zoneConfig["tokenPolicy"].(map[string]interface{})
+	if tokenPolicy == nil {
+		tokenPolicy = make(map[string]interface{})
+		zoneConfig["tokenPolicy"] = tokenPolicy
+	}
+	// settings cf-mgmt does not manage, such as the refresh token format, are kept
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &tokenPolicy); err != nil {
+		return err
+	}
+	data, err = json.Marshal(zone)
+	if err != nil {
+		return err
+	}
+	headers, body, err := m.Client.Curl(zonePath, "PUT", string(data), []string{"Accept: application/json", "Content-Type: application/json"})
+	if err != nil {
+		return fmt.Errorf("unable to update identity zone uaa: %v", err)
+	}
+	if status := statusLine(headers); !strings.Contains(status, " 200 ") {
+		return fmt.Errorf("unable to update identity zone uaa, the client needs the zones.write scope: %s %s", status, strings.TrimSpace(body))
+	}
+	return nil
+}
+
+func (m *DefaultUAAManager) getZone() (map[string]interface{}, error) {
+	headers, body, err := m.Client.Curl(zonePath, "GET", "", []string{"Accept: application/json"})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get identity zone uaa: %v", err)
+	}
+	if status := statusLine(headers); !strings.Contains(status, " 200 ") {
+		return nil, fmt.Errorf("unable to get identity zone uaa, the client needs the zones.read scope: %s", status)
+	}
+	zone := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(body), &zone); err != nil {
+		return nil, fmt.Errorf("unable to parse identity zone uaa: %v", err)
+	}
+	return zone, nil
+}
@@ -1,10 +1,23 @@
 package uaa
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
+	"github.com/pivotalservices/cf-mgmt/diskcache"
+	"github.com/pivotalservices/cf-mgmt/endpoint"
+	"github.com/pivotalservices/cf-mgmt/httpclient"
+	"github.com/pivotalservices/cf-mgmt/redact"
 	"github.com/xchapter7x/lo"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 
 	uaaclient "github.com/cloudfoundry-community/go-uaa"
 )
@@ -12,14 +25,44 @@ import (
 //go:generate counterfeiter -o fakes/uaa_client.go uaa.go uaa
 type uaa interface {
 	CreateUser(user uaaclient.User) (*uaaclient.User, error)
-	ListAllUsers(filter string, sortBy string, attributes string, sortOrder uaaclient.SortOrder) ([]uaaclient.User, error)
+	ListUsers(filter string, sortBy string, attributes string, sortOrder uaaclient.SortOrder, startIndex int, itemsPerPage int) ([]uaaclient.User, uaaclient.Page, error)
+	GetUser(userID string) (*uaaclient.User, error)
+	UpdateUser(user uaaclient.User) (*uaaclient.User, error)
+	DeleteUser(userID string) (*uaaclient.User, error)
+	ListAllGroups(filter string, sortBy string, attributes string, sortOrder uaaclient.SortOrder) ([]uaaclient.Group, error)
+	CreateGroup(group uaaclient.Group) (*uaaclient.Group, error)
+	AddGroupMember(groupID string, memberID string, entityType string, origin string) error
+	RemoveGroupMember(groupID string, memberID string, entityType string, origin string) error
+	Curl(path string, method string, data string, headers []string) (string, string, error)
 }
 
 //Manager -
 type Manager interface {
 	//Returns a map keyed and valued by user id. User id is converted to lowercase
 	ListUsers() (map[string]*uaaclient.User, error)
+	//Returns the users with one of the user names or external ids, keyed by origin and lowercase name
+	ListUsersByName(names []string) (map[UserKey]*uaaclient.User, error)
+	//Returns every user, including users of different origins sharing a user name
+	ListAllUsers() ([]*uaaclient.User, error)
 	CreateExternalUser(userName, userEmail, externalID, origin string) (err error)
+	CreateInternalUser(userName, userEmail, password string) (*uaaclient.User, error)
+	UpdateUserOrigin(user uaaclient.User, userName, externalID, origin string) error
+	DeleteUser(user uaaclient.User) error
+	//Returns the group with the display name, nil when there is no such group
+	GetGroup(name string) (*uaaclient.Group, error)
+	CreateGroup(name, description string) (*uaaclient.Group, error)
+	AddGroupMember(group uaaclient.Group, userID, userName string) error
+	RemoveGroupMember(group uaaclient.Group, userID, userName string) error
+	ListIdentityProviders() ([]IdentityProvider, error)
+	CreateIdentityProvider(provider IdentityProvider) error
+	UpdateIdentityProvider(provider IdentityProvider) error
+	DeleteIdentityProvider(provider IdentityProvider) error
+	GetTokenPolicy() (*TokenPolicy, error)
+	UpdateTokenPolicy(policy TokenPolicy) error
+	//Adds a second secret to a client, which authenticates with either until the old one is deleted
+	AddClientSecret(clientID, oldSecret, newSecret string) error
+	//Deletes the older of the two secrets of a client
+	DeleteOldClientSecret(clientID string) error
 }
 
 //Token -
@@ -31,21 +74,134 @@ type Token struct {
 type DefaultUAAManager struct {
 	Peek   bool
 	Client uaa
+	// UserOrigins, when set, limits ListUsers to the users of these origins
+	UserOrigins []string
+	// Cache, when set, keeps listed users on disk for runs in quick succession
+	Cache *diskcache.Cache
 }
 
+// usersNamespace is the disk cache namespace of listed users, it is
+// invalidated whenever cf-mgmt changes a user.
+const usersNamespace = "uaa-users"
+
+// userAttributes are the only attributes of users cf-mgmt reads, listing just
+// these keeps the users of large foundations small in memory.
+const userAttributes = "id,userName,externalId,origin,emails"
+
+// Modes of looking up uaa users, either listing every user up front or only
+// the users referenced by the configuration as they are needed.
+const (
+	LookupAll      = "all"
+	LookupTargeted = "targeted"
+)
+
+//UserKey - the origin and lowercase user name or external id of a looked up
+//user, users of different origins may share a user name
+type UserKey struct {
+	Origin string
+	Name   string
+}
+
+// namesPerLookup is how many names a single filtered request looks up, which
+// keeps the filter well within url length limits.
+const namesPerLookup = 25
+
+// usersPerPage is the page size users are listed in, each page is added to the
+// user map and released before the next one is requested.
+const usersPerPage = 500
+
 //NewDefaultUAAManager -
 func NewDefaultUAAManager(sysDomain, clientID, clientSecret string, peek bool) (Manager, error) {
-	target := fmt.Sprintf("https://uaa.%s", sysDomain)
-	client, err := uaaclient.NewWithClientCredentials(target, "", clientID, clientSecret, uaaclient.OpaqueToken, true)
+	return NewDefaultUAAManagerWithTransport(sysDomain, clientID, clientSecret, nil, peek)
+}
+
+//NewDefaultUAAManagerWithTransport - wrap, when set, decorates the transport used for authenticated uaa requests
+func NewDefaultUAAManagerWithTransport(sysDomain, clientID, clientSecret string, wrap func(http.RoundTripper) http.RoundTripper, peek bool) (Manager, error) {
+	client, err := NewClient(sysDomain, clientID, clientSecret, httpclient.Transport())
 	if err != nil {
 		return nil, err
 	}
+	if wrap != nil {
+		client.AuthenticatedClient.Transport = wrap(client.AuthenticatedClient.Transport)
+	}
 	return &DefaultUAAManager{
 		Client: client,
 		Peek:   peek,
 	}, nil
 }
 
+//NewClient - creates a uaa client that authenticates with client credentials, making its token
+//and api requests through transport so they reuse its connections. Updates send the version of the
+//resource they update in If-Match
+func NewClient(sysDomain, clientID, clientSecret string, transport http.RoundTripper) (*uaaclient.API, error) {
+	target, credentials, err := clientCredentials(sysDomain, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+	httpClient := &http.Client{Transport: &ifMatchTransport{base: transport}}
+	return &uaaclient.API{
+		UnauthenticatedClient: httpClient,
+		AuthenticatedClient:   credentials.Client(context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)),
+		TargetURL:             target,
+		SkipSSLValidation:     true,
+	}, nil
+}
+
+func clientCredentials(sysDomain, clientID, clientSecret string) (*url.URL, *clientcredentials.Config, error) {
+	target, err := uaaclient.BuildTargetURL(endpoint.UAA(sysDomain))
+	if err != nil {
+		return nil, nil, err
+	}
+	return target, &clientcredentials.Config{
+		ClientID:       clientID,
+		ClientSecret:   clientSecret,
+		TokenURL:       endpoint.TokenURL(sysDomain),
+		EndpointParams: url.Values{"token_format": []string{uaaclient.OpaqueToken.String()}},
+	}, nil
+}
+
+// ifMatchTransport sets the If-Match header uaa requires to update a user or
+// a group, which the uaa client does not send, to the version in the meta of the
+// resource being updated, so an update of a resource changed since it was
+// read fails instead of overwriting the change.
+type ifMatchTransport struct {
+	base http.RoundTripper
+}
+
+func (t *ifMatchTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodPut && req.Header.Get("If-Match") == "" && req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		if version, ok := metaVersion(body); ok {
+			req.Header.Set("If-Match", strconv.Itoa(version))
+		}
+	}
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+// metaVersion returns the version in the meta of a uaa resource, which the
+// uaa client omits when it is 0
+func metaVersion(body []byte) (int, bool) {
+	resource := struct {
+		Meta *struct {
+			Version int `json:"version"`
+		} `json:"meta"`
+	}{}
+	if err := json.Unmarshal(body, &resource); err != nil || resource.Meta == nil {
+		return 0, false
+	}
+	return resource.Meta.Version, true
+}
+
 //CreateExternalUser -
 func (m *DefaultUAAManager) CreateExternalUser(userName, userEmail, externalID, origin string) error {
 	if userName == "" || userEmail == "" || externalID == "" {
@@ -56,6 +212,7 @@ func (m *DefaultUAAManager) CreateExternalUser(userName, userEmail, externalID,
 		return nil
 	}
 
+	m.Cache.Invalidate(usersNamespace)
 	m.Client.CreateUser(uaaclient.User{
 		Username:   userName,
 		ExternalID: externalID,
@@ -70,20 +227,257 @@ func (m *DefaultUAAManager) CreateExternalUser(userName, userEmail, externalID,
 	return nil
 }
 
+//CreateInternalUser - creates a user in the uaa origin with the given password
+func (m *DefaultUAAManager) CreateInternalUser(userName, userEmail, password string) (*uaaclient.User, error) {
+	if m.Peek {
+		lo.G.Infof("[dry-run]: successfully added user [%s]", userName)
+		return &uaaclient.User{ID: "dry-run-user-guid", Username: userName, Origin: "uaa"}, nil
+	}
+	m.Cache.Invalidate(usersNamespace)
+	user, err := m.Client.CreateUser(uaaclient.User{
+		Username: userName,
+		Password: password,
+		Origin:   "uaa",
+		Emails: []uaaclient.Email{
+			uaaclient.Email{
+				Value: userEmail,
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create user [%s]: %s", userName, err.Error())
+	}
+	lo.G.Infof("successfully added user [%s]", userName)
+	return user, nil
+}
+
+//UpdateUserOrigin - moves a user to another origin in place, keeping its id and so its cf roles
+func (m *DefaultUAAManager) UpdateUserOrigin(user uaaclient.User, userName, externalID, origin string) error {
+	if m.Peek {
+		lo.G.Infof("[dry-run]: moving user [%s] from origin %s to %s as [%s]", user.Username, user.Origin, origin, userName)
+		return nil
+	}
+	lo.G.Infof("moving user [%s] from origin %s to %s as [%s]", user.Username, user.Origin, origin, userName)
+	m.Cache.Invalidate(usersNamespace)
+	// listed users only have the attributes cf-mgmt reads, update the full user so no others are lost
+	fullUser, err := m.Client.GetUser(user.ID)
+	if err != nil {
+		return fmt.Errorf("unable to get user [%s]: %v", user.ID, err)
+	}
+	user = *fullUser
+	fromOrigin := user.Origin
+	user.Username = userName
+	user.ExternalID = externalID
+	user.Origin = origin
+	if _, err := m.Client.UpdateUser(user); err != nil {
+		return fmt.Errorf("unable to move user [%s] from origin %s to %s: %v", user.ID, fromOrigin, origin, err)
+	}
+	return nil
+}
+
+//DeleteUser - deletes a user from uaa
+func (m *DefaultUAAManager) DeleteUser(user uaaclient.User) error {
+	if m.Peek {
+		lo.G.Infof("[dry-run]: deleting user [%s] from origin %s", user.Username, user.Origin)
+		return nil
+	}
+	lo.G.Infof("deleting user [%s] from origin %s", user.Username, user.Origin)
+	m.Cache.Invalidate(usersNamespace)
+	if _, err := m.Client.DeleteUser(user.ID); err != nil {
+		return fmt.Errorf("unable to delete user [%s] from origin %s: %v", user.ID, user.Origin, err)
+	}
+	return nil
+}
+
+//GetGroup - returns the group with the display name and its members, nil when there is no such group
+func (m *DefaultUAAManager) GetGroup(name string) (*uaaclient.Group, error) {
+	groups, err := m.Client.ListAllGroups(fmt.Sprintf(`displayName eq "%s"`, filterEscaper.Replace(name)), "", "", "")
+	if err != nil {
+		return nil, fmt.Errorf("unable to get group [%s]: %v", name, err)
+	}
+	if len(groups) == 0 {
+		return nil, nil
+	}
+	return &groups[0], nil
+}
+
+//CreateGroup - creates a group without members
+func (m *DefaultUAAManager) CreateGroup(name, description string) (*uaaclient.Group, error) {
+	if m.Peek {
+		lo.G.Infof("[dry-run]: creating group [%s]", name)
+		return &uaaclient.Group{ID: "dry-run-group-guid", DisplayName: name}, nil
+	}
+	lo.G.Infof("creating group [%s]", name)
+	group, err := m.Client.CreateGroup(uaaclient.Group{DisplayName: name, Description: description})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create group [%s]: %v", name, err)
+	}
+	return group, nil
+}
+
+//AddGroupMember - adds a user to a group
+func (m *DefaultUAAManager) AddGroupMember(group uaaclient.Group, userID, userName string) error {
+	if m.Peek {
+		lo.G.Infof("[dry-run]: adding user [%s] to group [%s]", userName, group.DisplayName)
+		return nil
+	}
+	lo.G.Infof("adding user [%s] to group [%s]", userName, group.DisplayName)
+	if err := m.Client.AddGroupMember(group.ID, userID, "USER", ""); err != nil {
+		return fmt.Errorf("unable to add user [%s] to group [%s]: %v", userID, group.DisplayName, err)
+	}
+	return nil
+}
+
+//RemoveGroupMember - removes a user from a group
+func (m *DefaultUAAManager) RemoveGroupMember(group uaaclient.Group, userID, userName string) error {
+	if m.Peek {
+		lo.G.Infof("[dry-run]: removing user [%s] from group [%s]", userName, group.DisplayName)
+		return nil
+	}
+	lo.G.Infof("removing user [%s] from group [%s]", userName, group.DisplayName)
+	if err := m.Client.RemoveGroupMember(group.ID, userID, "USER", ""); err != nil {
+		return fmt.Errorf("unable to remove user [%s] from group [%s]: %v", userID, group.DisplayName, err)
+	}
+	return nil
+}
+
 //ListUsers - Returns a map containing username as key and user guid as value
 func (m *DefaultUAAManager) ListUsers() (map[string]*uaaclient.User, error) {
 	userMap := make(map[string]*uaaclient.User)
-	lo.G.Debug("Getting users from Cloud Foundry")
-	users, err := m.Client.ListAllUsers("", "", "", "")
+	cacheKey := "all:" + originFilter(m.UserOrigins)
+	var cached []uaaclient.User
+	if m.Cache.Get(usersNamespace, cacheKey, &cached) {
+		lo.G.Debugf("Using %d users cached in %s", len(cached), m.Cache.Dir)
+		for i := range cached {
+			addUser(userMap, &cached[i])
+		}
+		return userMap, nil
+	}
+	err := m.listUserPages(func(users []uaaclient.User) {
+		for i := range users {
+			addUser(userMap, &users[i])
+			if m.Cache != nil {
+				cached = append(cached, users[i])
+			}
+		}
+	})
 	if err != nil {
 		return nil, err
 	}
-	lo.G.Debugf("Found %d users in the CF instance", len(users))
-	for _, user := range users {
-		userMap[strings.ToLower(user.Username)] = &user
-		if user.ExternalID != "" {
-			userMap[strings.ToLower(user.ExternalID)] = &user
+	m.Cache.Put(usersNamespace, cacheKey, cached)
+	return userMap, nil
+}
+
+//ListAllUsers - lists every user once, unlike ListUsers which keeps one of the users sharing a user name
+func (m *DefaultUAAManager) ListAllUsers() ([]*uaaclient.User, error) {
+	var userList []*uaaclient.User
+	err := m.listUserPages(func(users []uaaclient.User) {
+		for i := range users {
+			redact.UserNames(users[i].Username)
+			for _, email := range users[i].Emails {
+				redact.UserNames(email.Value)
+			}
+			userList = append(userList, &users[i])
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return userList, nil
+}
+
+// listUserPages hands each page of users, of the origins when limited, to add
+func (m *DefaultUAAManager) listUserPages(add func(users []uaaclient.User)) error {
+	filter := originFilter(m.UserOrigins)
+	if filter != "" {
+		lo.G.Debugf("Getting users of origins %v from Cloud Foundry", m.UserOrigins)
+	} else {
+		lo.G.Debug("Getting users from Cloud Foundry")
+	}
+	count := 0
+	startIndex := 1
+	for {
+		users, page, err := m.Client.ListUsers(filter, "", userAttributes, "", startIndex, usersPerPage)
+		if err != nil {
+			return err
+		}
+		add(users)
+		count += len(users)
+		if len(users) == 0 || page.StartIndex+page.ItemsPerPage > page.TotalResults {
+			break
+		}
+		startIndex = page.StartIndex + page.ItemsPerPage
+	}
+	lo.G.Debugf("Found %d users in the CF instance", count)
+	return nil
+}
+
+//ListUsersByName - looks up the users with one of the user names or external ids
+func (m *DefaultUAAManager) ListUsersByName(names []string) (map[UserKey]*uaaclient.User, error) {
+	userMap := make(map[UserKey]*uaaclient.User)
+	for start := 0; start < len(names); start += namesPerLookup {
+		end := start + namesPerLookup
+		if end > len(names) {
+			end = len(names)
+		}
+		filter := nameFilter(names[start:end])
+		if origins := originFilter(m.UserOrigins); origins != "" {
+			filter = fmt.Sprintf("(%s) and (%s)", filter, origins)
+		}
+		var users []uaaclient.User
+		if !m.Cache.Get(usersNamespace, filter, &users) {
+			var err error
+			users, _, err = m.Client.ListUsers(filter, "", userAttributes, "", 1, usersPerPage)
+			if err != nil {
+				return nil, err
+			}
+			m.Cache.Put(usersNamespace, filter, users)
+		}
+		for i := range users {
+			addUserByOrigin(userMap, &users[i])
 		}
 	}
 	return userMap, nil
 }
+
+// filterEscaper escapes values in scim filters
+var filterEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+func nameFilter(names []string) string {
+	var filters []string
+	for _, name := range names {
+		value := filterEscaper.Replace(name)
+		filters = append(filters, fmt.Sprintf(`userName eq "%s" or externalId eq "%s"`, value, value))
+	}
+	return strings.Join(filters, " or ")
+}
+
+func addUser(userMap map[string]*uaaclient.User, user *uaaclient.User) {
+	userMap[strings.ToLower(user.Username)] = user
+	redact.UserNames(user.Username)
+	for _, email := range user.Emails {
+		redact.UserNames(email.Value)
+	}
+	if user.ExternalID != "" {
+		userMap[strings.ToLower(user.ExternalID)] = user
+	}
+}
+
+func addUserByOrigin(userMap map[UserKey]*uaaclient.User, user *uaaclient.User) {
+	userMap[UserKey{Origin: user.Origin, Name: strings.ToLower(user.Username)}] = user
+	redact.UserNames(user.Username)
+	for _, email := range user.Emails {
+		redact.UserNames(email.Value)
+	}
+	if user.ExternalID != "" {
+		userMap[UserKey{Origin: user.Origin, Name: strings.ToLower(user.ExternalID)}] = user
+	}
+}
+
+func originFilter(origins []string) string {
+	var filters []string
+	for _, origin := range origins {
+		filters = append(filters, fmt.Sprintf(`origin eq "%s"`, origin))
+	}
+	return strings.Join(filters, " or ")
+}
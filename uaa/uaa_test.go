@@ -2,11 +2,16 @@ package uaa_test
 
 import (
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
 
 	uaaclient "github.com/cloudfoundry-community/go-uaa"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/pivotalservices/cf-mgmt/diskcache"
 	. "github.com/pivotalservices/cf-mgmt/uaa"
 
 	"github.com/pivotalservices/cf-mgmt/uaa/fakes"
@@ -27,7 +32,7 @@ var _ = Describe("given uaa manager", func() {
 	Context("ListUsers()", func() {
 
 		It("should return list of users", func() {
-			fakeuaa.ListAllUsersReturns([]uaaclient.User{
+			fakeuaa.ListUsersReturns([]uaaclient.User{
 				uaaclient.User{Username: "foo4"},
 				uaaclient.User{Username: "admin"},
 				uaaclient.User{Username: "user"},
@@ -37,7 +42,7 @@ var _ = Describe("given uaa manager", func() {
 				uaaclient.User{Username: "foo2"},
 				uaaclient.User{Username: "foo3"},
 				uaaclient.User{Username: "cn=admin"},
-			}, nil)
+			}, uaaclient.Page{StartIndex: 1, ItemsPerPage: 9, TotalResults: 9}, nil)
 			users, err := manager.ListUsers()
 			Ω(err).ShouldNot(HaveOccurred())
 			keys := make([]string, 0, len(users))
@@ -46,13 +51,78 @@ var _ = Describe("given uaa manager", func() {
 			}
 			Ω(len(users)).Should(Equal(9))
 			Ω(keys).Should(ConsistOf("foo4", "admin", "user", "cwashburn", "foo", "foo1", "foo2", "foo3", "cn=admin"))
+			Ω(fakeuaa.ListUsersCallCount()).Should(Equal(1))
+			filter, _, attributes, _, startIndex, _ := fakeuaa.ListUsersArgsForCall(0)
+			Ω(filter).Should(BeEmpty())
+			Ω(attributes).Should(Equal("id,userName,externalId,origin,emails"))
+			Ω(startIndex).Should(Equal(1))
+		})
+		It("should list users a page at a time", func() {
+			fakeuaa.ListUsersStub = func(filter, sortBy, attributes string, sortOrder uaaclient.SortOrder, startIndex, itemsPerPage int) ([]uaaclient.User, uaaclient.Page, error) {
+				if startIndex == 1 {
+					return []uaaclient.User{{Username: "foo"}, {Username: "bar"}}, uaaclient.Page{StartIndex: 1, ItemsPerPage: 2, TotalResults: 3}, nil
+				}
+				return []uaaclient.User{{Username: "baz"}}, uaaclient.Page{StartIndex: 3, ItemsPerPage: 1, TotalResults: 3}, nil
+			}
+			users, err := manager.ListUsers()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(users).Should(HaveLen(3))
+			Ω(fakeuaa.ListUsersCallCount()).Should(Equal(2))
+			_, _, _, _, startIndex, _ := fakeuaa.ListUsersArgsForCall(1)
+			Ω(startIndex).Should(Equal(3))
+		})
+		It("should only list users of the user origins", func() {
+			manager.UserOrigins = []string{"uaa", "ldap"}
+			fakeuaa.ListUsersReturns(nil, uaaclient.Page{StartIndex: 1}, nil)
+			_, err := manager.ListUsers()
+			Ω(err).ShouldNot(HaveOccurred())
+			filter, _, _, _, _, _ := fakeuaa.ListUsersArgsForCall(0)
+			Ω(filter).Should(Equal(`origin eq "uaa" or origin eq "ldap"`))
 		})
 		It("should return an error", func() {
-			fakeuaa.ListAllUsersReturns(nil, errors.New("Got an error"))
+			fakeuaa.ListUsersReturns(nil, uaaclient.Page{}, errors.New("Got an error"))
 			_, err := manager.ListUsers()
 			Ω(err).Should(HaveOccurred())
 		})
 	})
+	Context("ListUsersByName()", func() {
+		It("should look up users by user name or external id", func() {
+			fakeuaa.ListUsersReturns([]uaaclient.User{
+				{Username: "jdoe", ExternalID: `cn=jdoe,ou="people"`, Origin: "ldap"},
+				{Username: "jdoe", Origin: "uaa"},
+			}, uaaclient.Page{StartIndex: 1, ItemsPerPage: 2, TotalResults: 2}, nil)
+			users, err := manager.ListUsersByName([]string{"jdoe", `cn=jdoe,ou="people"`})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(users).Should(HaveLen(3))
+			Ω(users).Should(HaveKey(UserKey{Origin: "ldap", Name: "jdoe"}))
+			Ω(users).Should(HaveKey(UserKey{Origin: "ldap", Name: `cn=jdoe,ou="people"`}))
+			Ω(users).Should(HaveKey(UserKey{Origin: "uaa", Name: "jdoe"}))
+			filter, _, _, _, _, _ := fakeuaa.ListUsersArgsForCall(0)
+			Ω(filter).Should(Equal(`userName eq "jdoe" or externalId eq "jdoe" or userName eq "cn=jdoe,ou=\"people\"" or externalId eq "cn=jdoe,ou=\"people\""`))
+		})
+		It("should look up names in batches", func() {
+			var names []string
+			for i := 0; i < 30; i++ {
+				names = append(names, fmt.Sprintf("user%d", i))
+			}
+			_, err := manager.ListUsersByName(names)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(fakeuaa.ListUsersCallCount()).Should(Equal(2))
+		})
+	})
+	Context("UpdateUserOrigin()", func() {
+		It("should update the full user", func() {
+			active := true
+			fakeuaa.GetUserReturns(&uaaclient.User{ID: "user-id", Username: "jdoe", Origin: "ldap", Active: &active}, nil)
+			err := manager.UpdateUserOrigin(uaaclient.User{ID: "user-id", Username: "jdoe", Origin: "ldap"}, "jdoe@example.com", "jdoe@example.com", "saml")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(fakeuaa.GetUserArgsForCall(0)).Should(Equal("user-id"))
+			updated := fakeuaa.UpdateUserArgsForCall(0)
+			Ω(updated.Username).Should(Equal("jdoe@example.com"))
+			Ω(updated.Origin).Should(Equal("saml"))
+			Ω(updated.Active).Should(Equal(&active))
+		})
+	})
 	Context("CreateLdapUser()", func() {
 
 		It("should successfully create user", func() {
@@ -126,4 +196,170 @@ var _ = Describe("given uaa manager", func() {
 			Ω(err).ShouldNot(HaveOccurred())
 		})
 	})
+	Context("ListIdentityProviders()", func() {
+		It("should return the identity providers", func() {
+			fakeuaa.CurlReturns("HTTP/1.1 200 OK\nContent-Type: application/json", `[{"originKey":"uaa","name":"uaa","type":"uaa","active":true},{"originKey":"okta","type":"saml","active":false}]`, nil)
+			providers, err := manager.ListIdentityProviders()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(providers).Should(Equal([]IdentityProvider{
+				{OriginKey: "uaa", Name: "uaa", Type: "uaa", Active: true},
+				{OriginKey: "okta", Type: "saml"},
+			}))
+			path, method, _, _ := fakeuaa.CurlArgsForCall(0)
+			Ω(path).Should(Equal("/identity-providers?rawConfig=true"))
+			Ω(method).Should(Equal("GET"))
+		})
+		It("should return an error without the idps.read scope", func() {
+			fakeuaa.CurlReturns("HTTP/1.1 403 Forbidden", `{"error":"insufficient_scope"}`, nil)
+			_, err := manager.ListIdentityProviders()
+			Ω(err).Should(MatchError("unable to list identity providers, the client needs the idps.read scope: HTTP/1.1 403 Forbidden"))
+		})
+	})
+	Context("CreateIdentityProvider()", func() {
+		provider := IdentityProvider{OriginKey: "okta", Name: "Okta", Type: "saml", Active: true, Config: map[string]interface{}{"metaDataLocation": "https://okta/metadata"}}
+		It("should post the provider with its raw config", func() {
+			fakeuaa.CurlReturns("HTTP/1.1 201 Created\nContent-Type: application/json", `{}`, nil)
+			Ω(manager.CreateIdentityProvider(provider)).Should(Succeed())
+			path, method, data, _ := fakeuaa.CurlArgsForCall(0)
+			Ω(path).Should(Equal("/identity-providers?rawConfig=true"))
+			Ω(method).Should(Equal("POST"))
+			Ω(data).Should(MatchJSON(`{"originKey":"okta","name":"Okta","type":"saml","active":true,"config":{"metaDataLocation":"https://okta/metadata"}}`))
+		})
+		It("should return an error without the idps.write scope", func() {
+			fakeuaa.CurlReturns("HTTP/1.1 403 Forbidden", `{"error":"insufficient_scope"}`, nil)
+			err := manager.CreateIdentityProvider(provider)
+			Ω(err).Should(MatchError(`unable to write identity provider [okta], the client needs the idps.write scope: HTTP/1.1 403 Forbidden {"error":"insufficient_scope"}`))
+		})
+		It("should not create the provider with peek", func() {
+			manager.Peek = true
+			Ω(manager.CreateIdentityProvider(provider)).Should(Succeed())
+			Ω(fakeuaa.CurlCallCount()).Should(Equal(0))
+		})
+	})
+	Context("DeleteIdentityProvider()", func() {
+		It("should delete the provider by id", func() {
+			fakeuaa.CurlReturns("HTTP/1.1 200 OK", `{}`, nil)
+			Ω(manager.DeleteIdentityProvider(IdentityProvider{ID: "okta-id", OriginKey: "okta"})).Should(Succeed())
+			path, method, _, _ := fakeuaa.CurlArgsForCall(0)
+			Ω(path).Should(Equal("/identity-providers/okta-id"))
+			Ω(method).Should(Equal("DELETE"))
+		})
+	})
+	Context("GetTokenPolicy()", func() {
+		It("should return the token policy of the default zone", func() {
+			fakeuaa.CurlReturns("HTTP/1.1 200 OK\nContent-Type: application/json", `{"id":"uaa","config":{"tokenPolicy":{"accessTokenValidity":43200,"refreshTokenValidity":2592000,"jwtRevocable":true,"activeKeyId":"key-1","keys":{"key-1":{}}}}}`, nil)
+			policy, err := manager.GetTokenPolicy()
+			Ω(err).ShouldNot(HaveOccurred())
+			path, method, _, _ := fakeuaa.CurlArgsForCall(0)
+			Ω(path).Should(Equal("/identity-zones/uaa"))
+			Ω(method).Should(Equal("GET"))
+			Ω(policy.AccessTokenValidity).Should(Equal(43200))
+			Ω(policy.JwtRevocable).Should(BeTrue())
+			Ω(policy.Keys).Should(HaveKey("key-1"))
+		})
+		It("should return an error without the zones.read scope", func() {
+			fakeuaa.CurlReturns("HTTP/1.1 403 Forbidden", `{"error":"insufficient_scope"}`, nil)
+			_, err := manager.GetTokenPolicy()
+			Ω(err).Should(MatchError("unable to get identity zone uaa, the client needs the zones.read scope: HTTP/1.1 403 Forbidden"))
+		})
+	})
+	Context("UpdateTokenPolicy()", func() {
+		It("should put the zone keeping its other settings", func() {
+			fakeuaa.CurlStub = func(path, method, data string, headers []string) (string, string, error) {
+				return "HTTP/1.1 200 OK\nContent-Type: application/json", `{"id":"uaa","name":"uaa","config":{"tokenPolicy":{"accessTokenValidity":43200,"refreshTokenFormat":"jwt"},"links":{}}}`, nil
+			}
+			Ω(manager.UpdateTokenPolicy(TokenPolicy{AccessTokenValidity: 3600, RefreshTokenValidity: 86400, ActiveKeyID: "key-2", Keys: map[string]TokenKey{"key-2": {SigningKey: "secret"}}})).Should(Succeed())
+			Ω(fakeuaa.CurlCallCount()).Should(Equal(2))
+			path, method, data, _ := fakeuaa.CurlArgsForCall(1)
+			Ω(path).Should(Equal("/identity-zones/uaa"))
+			Ω(method).Should(Equal("PUT"))
+			Ω(data).Should(MatchJSON(`{"id":"uaa","name":"uaa","config":{"tokenPolicy":{"accessTokenValidity":3600,"refreshTokenValidity":86400,"jwtRevocable":false,"refreshTokenUnique":false,"refreshTokenFormat":"jwt","activeKeyId":"key-2","keys":{"key-2":{"signingKey":"secret"}}},"links":{}}}`))
+		})
+		It("should not update the zone with peek", func() {
+			manager.Peek = true
+			Ω(manager.UpdateTokenPolicy(TokenPolicy{AccessTokenValidity: 3600})).Should(Succeed())
+			Ω(fakeuaa.CurlCallCount()).Should(Equal(0))
+		})
+	})
+	Context("AddClientSecret()", func() {
+		It("should add the new secret next to the current one", func() {
+			fakeuaa.CurlReturns("HTTP/1.1 200 OK\nContent-Type: application/json", `{"status":"ok"}`, nil)
+			Ω(manager.AddClientSecret("cf-mgmt", "current", "new")).Should(Succeed())
+			path, method, data, _ := fakeuaa.CurlArgsForCall(0)
+			Ω(path).Should(Equal("/oauth/clients/cf-mgmt/secret"))
+			Ω(method).Should(Equal("PUT"))
+			Ω(data).Should(MatchJSON(`{"clientId":"cf-mgmt","oldSecret":"current","secret":"new","changeMode":"ADD"}`))
+		})
+		It("should return an error without the clients.secret scope", func() {
+			fakeuaa.CurlReturns("HTTP/1.1 403 Forbidden", `{"error":"insufficient_scope"}`, nil)
+			err := manager.AddClientSecret("cf-mgmt", "current", "new")
+			Ω(err).Should(MatchError(`unable to change the secret of client cf-mgmt, the client needs the clients.secret scope: HTTP/1.1 403 Forbidden {"error":"insufficient_scope"}`))
+		})
+	})
+	Context("DeleteOldClientSecret()", func() {
+		It("should delete the older secret", func() {
+			fakeuaa.CurlReturns("HTTP/1.1 200 OK\nContent-Type: application/json", `{"status":"ok"}`, nil)
+			Ω(manager.DeleteOldClientSecret("cf-mgmt")).Should(Succeed())
+			_, _, data, _ := fakeuaa.CurlArgsForCall(0)
+			Ω(data).Should(MatchJSON(`{"clientId":"cf-mgmt","changeMode":"DELETE"}`))
+		})
+		It("should not change the client with peek", func() {
+			manager.Peek = true
+			Ω(manager.DeleteOldClientSecret("cf-mgmt")).Should(Succeed())
+			Ω(fakeuaa.CurlCallCount()).Should(Equal(0))
+		})
+	})
+	Context("ListAllUsers()", func() {
+		It("should return users sharing a user name", func() {
+			fakeuaa.ListUsersReturns([]uaaclient.User{
+				{ID: "ldap-guid", Username: "jdoe", Origin: "ldap"},
+				{ID: "saml-guid", Username: "jdoe", Origin: "saml"},
+			}, uaaclient.Page{StartIndex: 1, ItemsPerPage: 500, TotalResults: 2}, nil)
+			users, err := manager.ListAllUsers()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(users).Should(HaveLen(2))
+			Ω(users[0].ID).Should(Equal("ldap-guid"))
+			Ω(users[1].ID).Should(Equal("saml-guid"))
+		})
+	})
+	Context("with a disk cache", func() {
+		var dir string
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "uaacache")
+			Ω(err).ShouldNot(HaveOccurred())
+			manager.Cache = diskcache.New(dir, time.Minute)
+			fakeuaa.ListUsersReturns([]uaaclient.User{
+				{ID: "jdoe-guid", Username: "jdoe", ExternalID: "cn=jdoe", Origin: "ldap"},
+			}, uaaclient.Page{StartIndex: 1, ItemsPerPage: 500, TotalResults: 1}, nil)
+		})
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+		It("should list users once for runs in quick succession", func() {
+			_, err := manager.ListUsers()
+			Ω(err).ShouldNot(HaveOccurred())
+			users, err := manager.ListUsers()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(users).Should(HaveKey("jdoe"))
+			Ω(users).Should(HaveKey("cn=jdoe"))
+			Ω(fakeuaa.ListUsersCallCount()).Should(Equal(1))
+		})
+		It("should list users again once a user is deleted", func() {
+			_, err := manager.ListUsers()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(manager.DeleteUser(uaaclient.User{ID: "jdoe-guid", Username: "jdoe", Origin: "ldap"})).Should(Succeed())
+			_, err = manager.ListUsers()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(fakeuaa.ListUsersCallCount()).Should(Equal(2))
+		})
+		It("should look up each name once", func() {
+			_, err := manager.ListUsersByName([]string{"jdoe"})
+			Ω(err).ShouldNot(HaveOccurred())
+			users, err := manager.ListUsersByName([]string{"jdoe"})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(users).Should(HaveKey(UserKey{Origin: "ldap", Name: "jdoe"}))
+			Ω(fakeuaa.ListUsersCallCount()).Should(Equal(1))
+		})
+	})
 })
@@ -0,0 +1,91 @@
+package user
+
+import (
+	"sort"
+	"strings"
+)
+
+// AdminGroups are the uaa groups granting admin-equivalent access to the
+// foundation, checked by ListUndeclaredAdmins along with admin-groups of
+// cf-mgmt.yml.
+var AdminGroups = []string{
+	"cloud_controller.admin",
+	"cloud_controller.admin_read_only",
+	"cloud_controller.global_auditor",
+	"uaa.admin",
+	"scim.write",
+	"clients.admin",
+	"clients.write",
+	"zones.write",
+	"network.admin",
+	"doppler.firehose",
+	"routing.router_groups.write",
+}
+
+//AdminAccess - a member of an admin-equivalent uaa group that is not declared in the configuration. Members
+//that are nested groups have the type GROUP and the group id as user name
+type AdminAccess struct {
+	Group    string
+	UserName string
+	Origin   string
+	Type     string
+}
+
+//ListUndeclaredAdmins - lists the members of admin-equivalent uaa groups that are not in admin-users of
+//cf-mgmt.yml, surfacing access granted outside of cf-mgmt
+func (m *DefaultManager) ListUndeclaredAdmins() ([]AdminAccess, error) {
+	globalConfig, err := m.Cfg.GetGlobalConfig()
+	if err != nil {
+		return nil, err
+	}
+	declared := make(map[string]bool)
+	for _, userName := range globalConfig.AdminUsers {
+		declared[strings.ToLower(userName)] = true
+	}
+	users, err := m.UAAMgr.ListAllUsers()
+	if err != nil {
+		return nil, err
+	}
+	userNames := make(map[string]string)
+	for _, user := range users {
+		userNames[user.ID] = user.Username
+	}
+
+	var undeclared []AdminAccess
+	checked := make(map[string]bool)
+	groupNames := append(append([]string{}, AdminGroups...), globalConfig.AdminGroups...)
+	for _, groupName := range groupNames {
+		if checked[groupName] {
+			continue
+		}
+		checked[groupName] = true
+		group, err := m.UAAMgr.GetGroup(groupName)
+		if err != nil {
+			return nil, err
+		}
+		if group == nil {
+			continue
+		}
+		for _, member := range group.Members {
+			if member.Type != "" && member.Type != "USER" {
+				undeclared = append(undeclared, AdminAccess{Group: groupName, UserName: member.Value, Origin: member.Origin, Type: member.Type})
+				continue
+			}
+			userName, ok := userNames[member.Value]
+			if !ok {
+				userName = member.Value
+			}
+			if declared[strings.ToLower(userName)] {
+				continue
+			}
+			undeclared = append(undeclared, AdminAccess{Group: groupName, UserName: userName, Origin: member.Origin, Type: "USER"})
+		}
+	}
+	sort.SliceStable(undeclared, func(i, j int) bool {
+		if undeclared[i].UserName != undeclared[j].UserName {
+			return undeclared[i].UserName < undeclared[j].UserName
+		}
+		return undeclared[i].Group < undeclared[j].Group
+	})
+	return undeclared, nil
+}
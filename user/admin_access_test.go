@@ -0,0 +1,61 @@
+package user_test
+
+import (
+	uaaclient "github.com/cloudfoundry-community/go-uaa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/config"
+	configfakes "github.com/pivotalservices/cf-mgmt/config/fakes"
+	uaafakes "github.com/pivotalservices/cf-mgmt/uaa/fakes"
+	. "github.com/pivotalservices/cf-mgmt/user"
+)
+
+var _ = Describe("given ListUndeclaredAdmins", func() {
+	var (
+		userManager *DefaultManager
+		uaaFake     *uaafakes.FakeManager
+		fakeReader  *configfakes.FakeReader
+	)
+	BeforeEach(func() {
+		uaaFake = new(uaafakes.FakeManager)
+		fakeReader = new(configfakes.FakeReader)
+		userManager = &DefaultManager{
+			Cfg:    fakeReader,
+			UAAMgr: uaaFake,
+		}
+		fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{
+			AdminUsers:  []string{"Admin"},
+			AdminGroups: []string{"credhub.write"},
+		}, nil)
+		uaaFake.ListAllUsersReturns([]*uaaclient.User{
+			{ID: "admin-guid", Username: "admin", Origin: "uaa"},
+			{ID: "bob-guid", Username: "bob", Origin: "ldap"},
+		}, nil)
+		uaaFake.GetGroupStub = func(name string) (*uaaclient.Group, error) {
+			switch name {
+			case "cloud_controller.admin":
+				return &uaaclient.Group{DisplayName: name, Members: []uaaclient.GroupMember{
+					{Type: "USER", Origin: "uaa", Value: "admin-guid"},
+					{Type: "USER", Origin: "ldap", Value: "bob-guid"},
+					{Type: "GROUP", Origin: "uaa", Value: "ops-group-guid"},
+				}}, nil
+			case "credhub.write":
+				return &uaaclient.Group{DisplayName: name, Members: []uaaclient.GroupMember{
+					{Type: "USER", Origin: "ldap", Value: "bob-guid"},
+				}}, nil
+			}
+			return nil, nil
+		}
+	})
+
+	It("lists the members of admin groups that are not admin-users", func() {
+		undeclared, err := userManager.ListUndeclaredAdmins()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(undeclared).Should(Equal([]AdminAccess{
+			{Group: "cloud_controller.admin", UserName: "bob", Origin: "ldap", Type: "USER"},
+			{Group: "credhub.write", UserName: "bob", Origin: "ldap", Type: "USER"},
+			{Group: "cloud_controller.admin", UserName: "ops-group-guid", Origin: "uaa", Type: "GROUP"},
+		}))
+		Expect(uaaFake.GetGroupCallCount()).Should(Equal(len(AdminGroups) + 1))
+	})
+})
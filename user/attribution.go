@@ -0,0 +1,60 @@
+package user
+
+import (
+	"fmt"
+	"strings"
+)
+
+// initializeAttribution reads whether attribute-roles of cf-mgmt.yml asks for
+// the source of granted roles to be logged
+func (m *DefaultManager) initializeAttribution() error {
+	globalConfig, err := m.Cfg.GetGlobalConfig()
+	if err != nil {
+		return err
+	}
+	m.attributeRoles = globalConfig != nil && globalConfig.AttributeRoles
+	return nil
+}
+
+// from returns the input of the user, client or ldap group named name, which
+// source, such as an ldap group, adds to the role, attributed to the config
+// file that grants the role to name
+func (input UpdateUsersInput) from(source, name string) UpdateUsersInput {
+	input.Source = source
+	if input.InheritedFrom != nil {
+		if file := input.InheritedFrom(name); file != "" {
+			input.ConfigFile = file
+		}
+	}
+	return input
+}
+
+// attribution returns where the user being added to a role comes from, to be
+// appended to the message of the change, or nothing unless attribute-roles is
+// set
+func (m *DefaultManager) attribution(input UpdateUsersInput) string {
+	if source := m.source(input); source != "" {
+		return fmt.Sprintf(" (%s)", source)
+	}
+	return ""
+}
+
+// source returns where the user being added to a role comes from, such as
+// from ldap group devs in org1/orgConfig.yml, recorded with the change in the
+// plan of a dry run, or nothing unless attribute-roles is set
+func (m *DefaultManager) source(input UpdateUsersInput) string {
+	if !m.attributeRoles {
+		return ""
+	}
+	var parts []string
+	if input.Source != "" {
+		parts = append(parts, input.Source)
+	}
+	if input.ConfigFile != "" {
+		parts = append(parts, input.ConfigFile)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "from " + strings.Join(parts, " in ")
+}
@@ -0,0 +1,94 @@
+package user
+
+import (
+	"fmt"
+	"strings"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/xchapter7x/lo"
+)
+
+// SyncClients adds the configured UAA clients to the role.  Cloud controller
+// uses the client id as the user guid for a client, so clients are associated
+// by guid rather than by username.
+func (m *DefaultManager) SyncClients(roleUsers map[string]string, updateUsersInput UpdateUsersInput) error {
+	for _, clientID := range updateUsersInput.Clients {
+		lowerClientID := strings.ToLower(clientID)
+		if _, ok := roleUsers[lowerClientID]; !ok {
+			if err := updateUsersInput.AddClient(updateUsersInput.from("clients", clientID), clientID); err != nil {
+				return err
+			}
+		} else {
+			delete(roleUsers, lowerClientID)
+		}
+	}
+	return nil
+}
+
+// clientRole grants and revokes a role of an org or space to a client, by the
+// guid of the org or space and the client id
+type clientRole struct {
+	associateOrg   func(orgGUID, clientID string) (cfclient.Org, error)
+	associateSpace func(spaceGUID, clientID string) (cfclient.Space, error)
+	remove         func(guid, clientID string) error
+}
+
+// clientRoles returns the org and space roles clients can be granted, keyed by
+// org or space and the role of UpdateUsersInput
+func (m *DefaultManager) clientRoles() map[string]clientRole {
+	return map[string]clientRole{
+		"org auditor":         {associateOrg: m.Client.AssociateOrgAuditor, remove: m.Client.RemoveOrgAuditor},
+		"org billing manager": {associateOrg: m.Client.AssociateOrgBillingManager, remove: m.Client.RemoveOrgBillingManager},
+		"org manager":         {associateOrg: m.Client.AssociateOrgManager, remove: m.Client.RemoveOrgManager},
+		"space auditor":       {associateSpace: m.Client.AssociateSpaceAuditor, remove: m.Client.RemoveSpaceAuditor},
+		"space developer":     {associateSpace: m.Client.AssociateSpaceDeveloper, remove: m.Client.RemoveSpaceDeveloper},
+		"space manager":       {associateSpace: m.Client.AssociateSpaceManager, remove: m.Client.RemoveSpaceManager},
+	}
+}
+
+//AssociateClient - adds the client to the role of the org or space of the input, making it a user of the org first
+func (m *DefaultManager) AssociateClient(input UpdateUsersInput, clientID string) error {
+	return m.changeClientRole(input, clientID, true)
+}
+
+//RemoveClient - removes the client from the role of the org or space of the input
+func (m *DefaultManager) RemoveClient(input UpdateUsersInput, clientID string) error {
+	return m.changeClientRole(input, clientID, false)
+}
+
+// changeClientRole adds the client to the role of the input when add is set,
+// and removes it otherwise
+func (m *DefaultManager) changeClientRole(input UpdateUsersInput, clientID string, add bool) error {
+	scope, guid, target := "org", input.OrgGUID, fmt.Sprintf("org %s", input.OrgName)
+	if input.SpaceGUID != "" {
+		scope, guid, target = "space", input.SpaceGUID, fmt.Sprintf("org/space %s/%s", input.OrgName, input.SpaceName)
+	}
+	role, ok := m.clientRoles()[scope+" "+input.Role]
+	if !ok {
+		return fmt.Errorf("clients cannot be granted %s role %s", scope, input.Role)
+	}
+	dryRun := ""
+	if m.Peek {
+		dryRun = "[dry-run]: "
+	}
+	if !add {
+		lo.G.Infof("%sremoving client %s from %s with role %s", dryRun, clientID, target, input.Role)
+		if m.Peek {
+			return nil
+		}
+		return role.remove(guid, clientID)
+	}
+	if err := m.AddClientToOrg(clientID, input); err != nil {
+		return err
+	}
+	lo.G.Infof("%sadding client %s to role %s for %s%s", dryRun, clientID, input.Role, target, m.attribution(input))
+	if m.Peek {
+		return nil
+	}
+	if role.associateSpace != nil {
+		_, err := role.associateSpace(guid, clientID)
+		return err
+	}
+	_, err := role.associateOrg(guid, clientID)
+	return err
+}
@@ -0,0 +1,58 @@
+package user
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xchapter7x/lo"
+)
+
+// creationFailures collects the saml users that could not be created, which
+// never get the roles they are configured for.
+type creationFailures struct {
+	failOnErrors bool
+	userNames    []string
+}
+
+// initializeCreationFailures starts collecting the saml users that could not
+// be created, failing the run at the end with fail-on-user-creation-errors in
+// cf-mgmt.yml.
+func (m *DefaultManager) initializeCreationFailures() error {
+	globalConfig, err := m.Cfg.GetGlobalConfig()
+	if err != nil {
+		return err
+	}
+	m.creationFailures = &creationFailures{}
+	if globalConfig != nil {
+		m.creationFailures.failOnErrors = globalConfig.FailOnUserCreationErrors
+	}
+	return nil
+}
+
+// creationFailed reports a user that could not be created as a warning, so
+// that it is part of the run summary, and records it.
+func (m *DefaultManager) creationFailed(userName string, input UpdateUsersInput, err error) {
+	if input.SpaceName == "" {
+		lo.G.Warningf("Unable to create user %s for org %s, it does not get its roles: %s", userName, input.OrgName, err.Error())
+	} else {
+		lo.G.Warningf("Unable to create user %s for org/space %s/%s, it does not get its roles: %s", userName, input.OrgName, input.SpaceName, err.Error())
+	}
+	if m.creationFailures == nil {
+		return
+	}
+	for _, failed := range m.creationFailures.userNames {
+		if strings.EqualFold(failed, userName) {
+			return
+		}
+	}
+	m.creationFailures.userNames = append(m.creationFailures.userNames, userName)
+}
+
+// err returns an error listing the users that could not be created when the
+// run should fail on them.
+func (f *creationFailures) err() error {
+	if f == nil || !f.failOnErrors || len(f.userNames) == 0 {
+		return nil
+	}
+	return fmt.Errorf("unable to create %d users: %s", len(f.userNames), strings.Join(f.userNames, ", "))
+}
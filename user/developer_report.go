@@ -0,0 +1,53 @@
+package user
+
+import (
+	"sort"
+	"strings"
+)
+
+// DeveloperCount is the number of distinct users holding space developer in
+// the spaces of an org.
+type DeveloperCount struct {
+	Org        string `json:"org"`
+	Developers int    `json:"developers"`
+}
+
+// DeveloperReport counts the distinct space developers of the foundation,
+// for license true-ups that are priced per developer.
+type DeveloperReport struct {
+	Orgs []DeveloperCount `json:"orgs"`
+	// Total counts each user once however many orgs it is a developer in
+	Total int `json:"total"`
+}
+
+//DeveloperReport - counts the distinct users holding space developer in every org of the foundation,
+//not only the orgs in the configuration, sorted by org
+func (m *DefaultManager) DeveloperReport() (*DeveloperReport, error) {
+	orgs, err := m.OrgMgr.ListOrgs()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(orgs, func(i, j int) bool { return strings.ToLower(orgs[i].Name) < strings.ToLower(orgs[j].Name) })
+	report := &DeveloperReport{Orgs: []DeveloperCount{}}
+	developers := make(map[string]bool)
+	for _, org := range orgs {
+		spaces, err := m.SpaceMgr.ListSpaces(org.Guid)
+		if err != nil {
+			return nil, err
+		}
+		orgDevelopers := make(map[string]bool)
+		for _, space := range spaces {
+			users, err := m.Client.ListSpaceDevelopers(space.Guid)
+			if err != nil {
+				return nil, err
+			}
+			for _, user := range users {
+				orgDevelopers[user.Guid] = true
+				developers[user.Guid] = true
+			}
+		}
+		report.Orgs = append(report.Orgs, DeveloperCount{Org: org.Name, Developers: len(orgDevelopers)})
+	}
+	report.Total = len(developers)
+	return report, nil
+}
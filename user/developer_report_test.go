@@ -0,0 +1,58 @@
+package user_test
+
+import (
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	orgfakes "github.com/pivotalservices/cf-mgmt/organization/fakes"
+	spacefakes "github.com/pivotalservices/cf-mgmt/space/fakes"
+	. "github.com/pivotalservices/cf-mgmt/user"
+	"github.com/pivotalservices/cf-mgmt/user/fakes"
+)
+
+var _ = Describe("given DeveloperReport", func() {
+	var (
+		userManager *DefaultManager
+		client      *fakes.FakeCFClient
+		spaceFake   *spacefakes.FakeManager
+		orgFake     *orgfakes.FakeManager
+	)
+	BeforeEach(func() {
+		client = new(fakes.FakeCFClient)
+		spaceFake = new(spacefakes.FakeManager)
+		orgFake = new(orgfakes.FakeManager)
+		userManager = &DefaultManager{
+			Client:   client,
+			SpaceMgr: spaceFake,
+			OrgMgr:   orgFake,
+		}
+	})
+
+	It("counts each developer once per org and once across the foundation", func() {
+		orgFake.ListOrgsReturns([]cfclient.Org{{Guid: "org2-guid", Name: "org2"}, {Guid: "org1-guid", Name: "Org1"}}, nil)
+		spaceFake.ListSpacesStub = func(orgGUID string) ([]cfclient.Space, error) {
+			if orgGUID == "org1-guid" {
+				return []cfclient.Space{{Guid: "dev-guid"}, {Guid: "prod-guid"}}, nil
+			}
+			return []cfclient.Space{{Guid: "other-guid"}}, nil
+		}
+		client.ListSpaceDevelopersStub = func(spaceGUID string) ([]cfclient.User, error) {
+			switch spaceGUID {
+			case "dev-guid":
+				return []cfclient.User{{Guid: "alice-guid"}, {Guid: "bob-guid"}}, nil
+			case "prod-guid":
+				return []cfclient.User{{Guid: "alice-guid"}}, nil
+			}
+			return []cfclient.User{{Guid: "bob-guid"}, {Guid: "carol-guid"}}, nil
+		}
+		report, err := userManager.DeveloperReport()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(report).Should(Equal(&DeveloperReport{
+			Orgs: []DeveloperCount{
+				{Org: "Org1", Developers: 2},
+				{Org: "org2", Developers: 2},
+			},
+			Total: 3,
+		}))
+	})
+})
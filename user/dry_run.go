@@ -0,0 +1,42 @@
+package user
+
+import "github.com/pivotalservices/cf-mgmt/dryrun"
+
+// recordRoleChanges records the users and clients a dry run, or a plan, would
+// add to or remove from the role of the input in the plan of the run, along
+// with where the users added come from
+func (m *DefaultManager) recordRoleChanges(input UpdateUsersInput) UpdateUsersInput {
+	if input.Role == "" {
+		return input
+	}
+	kind := dryrun.OrgRole
+	if input.SpaceName != "" {
+		kind = dryrun.SpaceRole
+	}
+	record := func(action string, change func(UpdateUsersInput, string) error, prefix string) func(UpdateUsersInput, string) error {
+		return func(input UpdateUsersInput, name string) error {
+			if err := change(input, name); err != nil {
+				return err
+			}
+			detail := ""
+			if action == dryrun.Create {
+				detail = m.source(input)
+			}
+			dryrun.Record(action, kind, dryrun.RoleName(prefix+name, input.Role, input.OrgName, input.SpaceName), detail)
+			return nil
+		}
+	}
+	if input.AddUser != nil {
+		input.AddUser = record(dryrun.Create, input.AddUser, "")
+	}
+	if input.RemoveUser != nil {
+		input.RemoveUser = record(dryrun.Delete, input.RemoveUser, "")
+	}
+	if input.AddClient != nil {
+		input.AddClient = record(dryrun.Create, input.AddClient, "client ")
+	}
+	if input.RemoveClient != nil {
+		input.RemoveClient = record(dryrun.Delete, input.RemoveClient, "client ")
+	}
+	return input
+}
@@ -0,0 +1,159 @@
+package user
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	uaaclient "github.com/cloudfoundry-community/go-uaa"
+	"github.com/xchapter7x/lo"
+)
+
+//DuplicateUsers - uaa users of different origins sharing an email, such as the
+//shadow user created when a user logs in through another identity provider
+type DuplicateUsers struct {
+	Email string
+	// Preferred is the user of the preferred origin, nil when the email has no
+	// single user in that origin and the users cannot be consolidated
+	Preferred  *uaaclient.User
+	Duplicates []*uaaclient.User
+}
+
+//DedupeUAAUsers - reports the users of different origins sharing an email. With consolidate the roles
+//of the duplicates are given to the user of the preferred origin and the duplicates are deleted. The
+//admin user, the user cf-mgmt runs as and the exclude-users of every org and space are left alone.
+func (m *DefaultManager) DedupeUAAUsers(preferredOrigin string, consolidate bool, clientUser string) ([]DuplicateUsers, error) {
+	protectedUsers, err := m.protectedUsers(clientUser)
+	if err != nil {
+		return nil, err
+	}
+	allUsers, err := m.UAAMgr.ListAllUsers()
+	if err != nil {
+		return nil, err
+	}
+	var uaaUsers []*uaaclient.User
+	for _, uaaUser := range allUsers {
+		if isExcluded(protectedUsers, uaaUser.Username) {
+			lo.G.Debugf("Not deduplicating user %s as it is protected", uaaUser.Username)
+			continue
+		}
+		uaaUsers = append(uaaUsers, uaaUser)
+	}
+	duplicates := findDuplicateUsers(uaaUsers, preferredOrigin)
+	if !consolidate || len(duplicates) == 0 {
+		return duplicates, nil
+	}
+
+	bindings, err := m.roleBindings()
+	if err != nil {
+		return nil, err
+	}
+	for _, duplicate := range duplicates {
+		if duplicate.Preferred == nil {
+			lo.G.Warningf("users with email %s are kept as there is no single user of origin %s to consolidate them onto", duplicate.Email, preferredOrigin)
+			continue
+		}
+		for _, uaaUser := range duplicate.Duplicates {
+			for _, binding := range bindings[uaaUser.ID] {
+				if err := m.copyRole(binding, duplicate.Preferred); err != nil {
+					return nil, err
+				}
+			}
+			if !m.Peek {
+				// users that never logged in or got a role have no cloud controller user
+				if err := m.Client.DeleteUser(uaaUser.ID); err != nil {
+					lo.G.Warningf("unable to delete cloud controller user [%s]: %s", uaaUser.Username, err.Error())
+				}
+			}
+			if err := m.UAAMgr.DeleteUser(*uaaUser); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return duplicates, nil
+}
+
+// protectedUsers are the users dedupe-uaa-users never deletes, admin, the user
+// cf-mgmt runs as and the exclude-users of every org and space
+func (m *DefaultManager) protectedUsers(clientUser string) ([]string, error) {
+	protectedUsers := []string{"admin"}
+	if clientUser != "" {
+		protectedUsers = append(protectedUsers, clientUser)
+	}
+	orgConfigs, err := m.Cfg.GetOrgConfigs()
+	if err != nil {
+		return nil, err
+	}
+	for _, orgConfig := range orgConfigs {
+		protectedUsers = append(protectedUsers, orgConfig.ExcludeUsers...)
+	}
+	spaceConfigs, err := m.Cfg.GetSpaceConfigs()
+	if err != nil {
+		return nil, err
+	}
+	for _, spaceConfig := range spaceConfigs {
+		protectedUsers = append(protectedUsers, spaceConfig.ExcludeUsers...)
+	}
+	return protectedUsers, nil
+}
+
+// findDuplicateUsers groups the users by email, returning the emails of users
+// in more than one origin sorted by email
+func findDuplicateUsers(uaaUsers []*uaaclient.User, preferredOrigin string) []DuplicateUsers {
+	byEmail := make(map[string][]*uaaclient.User)
+	seen := make(map[string]bool)
+	for _, uaaUser := range uaaUsers {
+		email := strings.ToLower(Email(uaaUser))
+		if email == "" || seen[uaaUser.ID] {
+			continue
+		}
+		seen[uaaUser.ID] = true
+		byEmail[email] = append(byEmail[email], uaaUser)
+	}
+
+	var duplicates []DuplicateUsers
+	for email, users := range byEmail {
+		origins := make(map[string]bool)
+		for _, uaaUser := range users {
+			origins[uaaUser.Origin] = true
+		}
+		if len(origins) < 2 {
+			continue
+		}
+		sort.Slice(users, func(i, j int) bool {
+			if users[i].Origin != users[j].Origin {
+				return users[i].Origin < users[j].Origin
+			}
+			return users[i].Username < users[j].Username
+		})
+		duplicate := DuplicateUsers{Email: email}
+		var preferred []*uaaclient.User
+		for _, uaaUser := range users {
+			if uaaUser.Origin == preferredOrigin {
+				preferred = append(preferred, uaaUser)
+			} else {
+				duplicate.Duplicates = append(duplicate.Duplicates, uaaUser)
+			}
+		}
+		if len(preferred) == 1 {
+			duplicate.Preferred = preferred[0]
+		} else {
+			duplicate.Duplicates = users
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+	sort.Slice(duplicates, func(i, j int) bool { return duplicates[i].Email < duplicates[j].Email })
+	return duplicates
+}
+
+//String - the email followed by the users, the preferred user first
+func (d DuplicateUsers) String() string {
+	var users []string
+	if d.Preferred != nil {
+		users = append(users, fmt.Sprintf("%s (%s, preferred)", d.Preferred.Username, d.Preferred.Origin))
+	}
+	for _, uaaUser := range d.Duplicates {
+		users = append(users, fmt.Sprintf("%s (%s)", uaaUser.Username, uaaUser.Origin))
+	}
+	return fmt.Sprintf("%s: %s", d.Email, strings.Join(users, ", "))
+}
@@ -0,0 +1,118 @@
+package user_test
+
+import (
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	uaaclient "github.com/cloudfoundry-community/go-uaa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/config"
+	configfakes "github.com/pivotalservices/cf-mgmt/config/fakes"
+	orgfakes "github.com/pivotalservices/cf-mgmt/organization/fakes"
+	spacefakes "github.com/pivotalservices/cf-mgmt/space/fakes"
+	uaafakes "github.com/pivotalservices/cf-mgmt/uaa/fakes"
+	. "github.com/pivotalservices/cf-mgmt/user"
+	"github.com/pivotalservices/cf-mgmt/user/fakes"
+)
+
+var _ = Describe("given DedupeUAAUsers", func() {
+	var (
+		userManager *DefaultManager
+		client      *fakes.FakeCFClient
+		uaaFake     *uaafakes.FakeManager
+		spaceFake   *spacefakes.FakeManager
+		orgFake     *orgfakes.FakeManager
+		fakeReader  *configfakes.FakeReader
+		samlUser    *uaaclient.User
+		ldapUser    *uaaclient.User
+	)
+	BeforeEach(func() {
+		client = new(fakes.FakeCFClient)
+		uaaFake = new(uaafakes.FakeManager)
+		spaceFake = new(spacefakes.FakeManager)
+		orgFake = new(orgfakes.FakeManager)
+		fakeReader = new(configfakes.FakeReader)
+		userManager = &DefaultManager{
+			Client:   client,
+			Cfg:      fakeReader,
+			UAAMgr:   uaaFake,
+			SpaceMgr: spaceFake,
+			OrgMgr:   orgFake,
+		}
+		samlUser = &uaaclient.User{ID: "saml-guid", Username: "jdoe@example.com", Origin: "saml", Emails: []uaaclient.Email{{Value: "jdoe@example.com"}}}
+		ldapUser = &uaaclient.User{ID: "ldap-guid", Username: "jdoe", Origin: "ldap", Emails: []uaaclient.Email{{Value: "JDoe@example.com"}}}
+		uaaFake.ListAllUsersReturns([]*uaaclient.User{
+			samlUser,
+			ldapUser,
+			{ID: "admin-guid", Username: "admin", Origin: "uaa", Emails: []uaaclient.Email{{Value: "admin@example.com"}}},
+			{ID: "other-guid", Username: "other", Origin: "uaa"},
+		}, nil)
+		orgFake.ListOrgsReturns([]cfclient.Org{{Guid: "org-guid", Name: "org"}}, nil)
+		client.ListOrgUsersReturns([]cfclient.User{{Guid: "ldap-guid"}}, nil)
+		spaceFake.ListSpacesReturns([]cfclient.Space{{Guid: "space-guid", Name: "space"}}, nil)
+		client.ListSpaceDevelopersReturns([]cfclient.User{{Guid: "ldap-guid"}, {Guid: "admin-guid"}}, nil)
+	})
+
+	It("reports users of different origins sharing an email", func() {
+		duplicates, err := userManager.DedupeUAAUsers("saml", false, "cf-mgmt")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(duplicates).Should(Equal([]DuplicateUsers{
+			{Email: "jdoe@example.com", Preferred: samlUser, Duplicates: []*uaaclient.User{ldapUser}},
+		}))
+		Expect(duplicates[0].String()).Should(Equal("jdoe@example.com: jdoe@example.com (saml, preferred), jdoe (ldap)"))
+		Expect(orgFake.ListOrgsCallCount()).Should(Equal(0))
+		Expect(uaaFake.DeleteUserCallCount()).Should(Equal(0))
+	})
+
+	It("gives the roles of the duplicates to the preferred user and deletes them", func() {
+		_, err := userManager.DedupeUAAUsers("saml", true, "cf-mgmt")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(client.AssociateOrgUserCallCount()).Should(Equal(1))
+		orgGUID, userGUID := client.AssociateOrgUserArgsForCall(0)
+		Expect(orgGUID).Should(Equal("org-guid"))
+		Expect(userGUID).Should(Equal("saml-guid"))
+		Expect(client.AssociateSpaceDeveloperCallCount()).Should(Equal(1))
+		spaceGUID, userGUID := client.AssociateSpaceDeveloperArgsForCall(0)
+		Expect(spaceGUID).Should(Equal("space-guid"))
+		Expect(userGUID).Should(Equal("saml-guid"))
+		Expect(client.DeleteUserCallCount()).Should(Equal(1))
+		Expect(client.DeleteUserArgsForCall(0)).Should(Equal("ldap-guid"))
+		Expect(uaaFake.DeleteUserCallCount()).Should(Equal(1))
+		Expect(uaaFake.DeleteUserArgsForCall(0).ID).Should(Equal("ldap-guid"))
+	})
+
+	It("does not consolidate users without a user of the preferred origin", func() {
+		duplicates, err := userManager.DedupeUAAUsers("okta", true, "cf-mgmt")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(duplicates).Should(HaveLen(1))
+		Expect(duplicates[0].Preferred).Should(BeNil())
+		Expect(duplicates[0].Duplicates).Should(Equal([]*uaaclient.User{ldapUser, samlUser}))
+		Expect(client.AssociateOrgUserCallCount()).Should(Equal(0))
+		Expect(uaaFake.DeleteUserCallCount()).Should(Equal(0))
+	})
+
+	It("leaves admin, the user cf-mgmt runs as and excluded users alone", func() {
+		fakeReader.GetOrgConfigsReturns([]config.OrgConfig{{Org: "org", ExcludeUsers: []string{"Break-Glass"}}}, nil)
+		fakeReader.GetSpaceConfigsReturns([]config.SpaceConfig{{Org: "org", Space: "space", ExcludeUsers: []string{"smoke-tests"}}}, nil)
+		uaaFake.ListAllUsersReturns([]*uaaclient.User{
+			{ID: "admin-guid", Username: "admin", Origin: "uaa", Emails: []uaaclient.Email{{Value: "ops@example.com"}}},
+			{ID: "admin-saml-guid", Username: "ops@example.com", Origin: "saml", Emails: []uaaclient.Email{{Value: "ops@example.com"}}},
+			{ID: "client-guid", Username: "cf-mgmt", Origin: "uaa", Emails: []uaaclient.Email{{Value: "cf-mgmt@example.com"}}},
+			{ID: "client-saml-guid", Username: "cf-mgmt@example.com", Origin: "saml", Emails: []uaaclient.Email{{Value: "cf-mgmt@example.com"}}},
+			{ID: "break-glass-guid", Username: "break-glass", Origin: "uaa", Emails: []uaaclient.Email{{Value: "sre@example.com"}}},
+			{ID: "smoke-tests-guid", Username: "smoke-tests", Origin: "ldap", Emails: []uaaclient.Email{{Value: "sre@example.com"}}},
+			{ID: "sre-guid", Username: "sre@example.com", Origin: "saml", Emails: []uaaclient.Email{{Value: "sre@example.com"}}},
+		}, nil)
+		duplicates, err := userManager.DedupeUAAUsers("saml", true, "cf-mgmt")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(duplicates).Should(BeEmpty())
+		Expect(uaaFake.DeleteUserCallCount()).Should(Equal(0))
+	})
+
+	It("does not change roles or delete users when peeking", func() {
+		userManager.Peek = true
+		_, err := userManager.DedupeUAAUsers("saml", true, "cf-mgmt")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(client.AssociateOrgUserCallCount()).Should(Equal(0))
+		Expect(client.DeleteUserCallCount()).Should(Equal(0))
+	})
+})
@@ -0,0 +1,114 @@
+package user
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//ExternalUserProblem - a saml or ldap user of the configuration, or the origin
+//of those users, that cannot log in as configured
+type ExternalUserProblem struct {
+	UserName string
+	Origin   string
+	Problem  string
+}
+
+//ListExternalUserProblems - checks that the origin of saml and ldap users is an
+//active uaa identity provider and that configured users that already exist in
+//uaa are of that origin. A user of another origin gets an orphan shadow user
+//of the configured origin that it never logs in with. Ldap users are checked
+//by their configured id, so they are skipped when user names are mapped.
+func (m *DefaultManager) ListExternalUserProblems() ([]ExternalUserProblem, error) {
+	if m.LdapConfig == nil {
+		return nil, fmt.Errorf("ldap configuration is not initialized")
+	}
+	origin := m.LdapConfig.Origin
+	orgConfigs, err := m.Cfg.GetOrgConfigs()
+	if err != nil {
+		return nil, err
+	}
+	spaceConfigs, err := m.Cfg.GetSpaceConfigs()
+	if err != nil {
+		return nil, err
+	}
+	checkLdapUsers := m.LdapConfig.UserNameMapping == nil && len(m.LdapConfig.GroupUserNameMappings) == 0
+	names := make(map[string]string)
+	addNames := func(samlUsers, ldapUsers []string) {
+		for _, userName := range samlUsers {
+			names[strings.ToLower(userName)] = userName
+		}
+		if checkLdapUsers {
+			for _, userName := range ldapUsers {
+				names[strings.ToLower(userName)] = userName
+			}
+		}
+	}
+	for _, orgConfig := range orgConfigs {
+		addNames(orgConfig.Manager.SamlUsers, orgConfig.Manager.LDAPUsers)
+		addNames(orgConfig.BillingManager.SamlUsers, orgConfig.BillingManager.LDAPUsers)
+		addNames(orgConfig.Auditor.SamlUsers, orgConfig.Auditor.LDAPUsers)
+	}
+	for _, spaceConfig := range spaceConfigs {
+		addNames(spaceConfig.Developer.SamlUsers, spaceConfig.Developer.LDAPUsers)
+		addNames(spaceConfig.Manager.SamlUsers, spaceConfig.Manager.LDAPUsers)
+		addNames(spaceConfig.Auditor.SamlUsers, spaceConfig.Auditor.LDAPUsers)
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	var problems []ExternalUserProblem
+	providers, err := m.UAAMgr.ListIdentityProviders()
+	if err != nil {
+		return nil, err
+	}
+	active := false
+	for _, provider := range providers {
+		if provider.OriginKey == origin {
+			active = provider.Active
+		}
+	}
+	if !active {
+		problems = append(problems, ExternalUserProblem{
+			Origin:  origin,
+			Problem: fmt.Sprintf("origin %s of ldap.yml is not an active uaa identity provider, its users cannot log in", origin),
+		})
+	}
+
+	var userNames []string
+	for _, userName := range names {
+		userNames = append(userNames, userName)
+	}
+	sort.Strings(userNames)
+	uaaUsers, err := m.UAAMgr.ListUsersByName(userNames)
+	if err != nil {
+		return nil, err
+	}
+	userOrigins := make(map[string]map[string]bool)
+	for key := range uaaUsers {
+		if userOrigins[key.Name] == nil {
+			userOrigins[key.Name] = make(map[string]bool)
+		}
+		userOrigins[key.Name][key.Origin] = true
+	}
+	for _, userName := range userNames {
+		var otherOrigins []string
+		for userOrigin := range userOrigins[strings.ToLower(userName)] {
+			if strings.EqualFold(userOrigin, origin) {
+				otherOrigins = nil
+				break
+			}
+			otherOrigins = append(otherOrigins, userOrigin)
+		}
+		sort.Strings(otherOrigins)
+		for _, userOrigin := range otherOrigins {
+			problems = append(problems, ExternalUserProblem{
+				UserName: userName,
+				Origin:   userOrigin,
+				Problem:  fmt.Sprintf("exists in origin %s, an orphan shadow user of origin %s would be created", userOrigin, origin),
+			})
+		}
+	}
+	return problems, nil
+}
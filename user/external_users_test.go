@@ -0,0 +1,82 @@
+package user_test
+
+import (
+	uaaclient "github.com/cloudfoundry-community/go-uaa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/config"
+	configfakes "github.com/pivotalservices/cf-mgmt/config/fakes"
+	"github.com/pivotalservices/cf-mgmt/uaa"
+	uaafakes "github.com/pivotalservices/cf-mgmt/uaa/fakes"
+	. "github.com/pivotalservices/cf-mgmt/user"
+)
+
+var _ = Describe("given ListExternalUserProblems", func() {
+	var (
+		userManager *DefaultManager
+		uaaFake     *uaafakes.FakeManager
+		fakeReader  *configfakes.FakeReader
+	)
+	BeforeEach(func() {
+		uaaFake = new(uaafakes.FakeManager)
+		fakeReader = new(configfakes.FakeReader)
+		userManager = &DefaultManager{
+			Cfg:        fakeReader,
+			UAAMgr:     uaaFake,
+			LdapConfig: &config.LdapConfig{Origin: "okta"},
+		}
+		fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+			{Org: "org1", Manager: config.UserMgmt{SamlUsers: []string{"Alice@example.com"}, Users: []string{"admin"}}},
+		}, nil)
+		fakeReader.GetSpaceConfigsReturns([]config.SpaceConfig{
+			{Org: "org1", Space: "dev", Developer: config.UserMgmt{SamlUsers: []string{"bob@example.com", "carol@example.com"}}},
+		}, nil)
+		uaaFake.ListIdentityProvidersReturns([]uaa.IdentityProvider{
+			{OriginKey: "uaa", Active: true},
+			{OriginKey: "okta", Active: true},
+		}, nil)
+	})
+
+	It("reports users that exist in another origin", func() {
+		uaaFake.ListUsersByNameReturns(map[uaa.UserKey]*uaaclient.User{
+			{Origin: "okta", Name: "alice@example.com"}: {ID: "alice-guid", Username: "alice@example.com", Origin: "okta"},
+			{Origin: "uaa", Name: "bob@example.com"}:    {ID: "bob-guid", Username: "bob@example.com", Origin: "uaa"},
+		}, nil)
+		problems, err := userManager.ListExternalUserProblems()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(problems).Should(Equal([]ExternalUserProblem{
+			{UserName: "bob@example.com", Origin: "uaa", Problem: "exists in origin uaa, an orphan shadow user of origin okta would be created"},
+		}))
+		Expect(uaaFake.ListUsersByNameArgsForCall(0)).Should(Equal([]string{"Alice@example.com", "bob@example.com", "carol@example.com"}))
+	})
+
+	It("does not report users that also exist in the origin", func() {
+		uaaFake.ListUsersByNameReturns(map[uaa.UserKey]*uaaclient.User{
+			{Origin: "uaa", Name: "alice@example.com"}:  {ID: "alice-uaa-guid", Username: "alice@example.com", Origin: "uaa"},
+			{Origin: "okta", Name: "alice@example.com"}: {ID: "alice-guid", Username: "alice@example.com", Origin: "okta"},
+		}, nil)
+		problems, err := userManager.ListExternalUserProblems()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(problems).Should(BeEmpty())
+	})
+
+	It("reports an origin that is not an active identity provider", func() {
+		uaaFake.ListIdentityProvidersReturns([]uaa.IdentityProvider{
+			{OriginKey: "uaa", Active: true},
+			{OriginKey: "okta", Active: false},
+		}, nil)
+		problems, err := userManager.ListExternalUserProblems()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(problems).Should(HaveLen(1))
+		Expect(problems[0].Problem).Should(Equal("origin okta of ldap.yml is not an active uaa identity provider, its users cannot log in"))
+	})
+
+	It("does not check anything without external users", func() {
+		fakeReader.GetOrgConfigsReturns(nil, nil)
+		fakeReader.GetSpaceConfigsReturns(nil, nil)
+		problems, err := userManager.ListExternalUserProblems()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(problems).Should(BeEmpty())
+		Expect(uaaFake.ListIdentityProvidersCallCount()).Should(Equal(0))
+	})
+})
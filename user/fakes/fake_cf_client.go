@@ -215,6 +215,147 @@ type FakeCFClient struct {
 		result1 []go_cfclient.Space
 		result2 error
 	}
+	AssociateOrgUserStub        func(orgGUID, userGUID string) (go_cfclient.Org, error)
+	associateOrgUserMutex       sync.RWMutex
+	associateOrgUserArgsForCall []struct {
+		orgGUID  string
+		userGUID string
+	}
+	associateOrgUserReturns struct {
+		result1 go_cfclient.Org
+		result2 error
+	}
+	AssociateOrgAuditorStub        func(orgGUID, userGUID string) (go_cfclient.Org, error)
+	associateOrgAuditorMutex       sync.RWMutex
+	associateOrgAuditorArgsForCall []struct {
+		orgGUID  string
+		userGUID string
+	}
+	associateOrgAuditorReturns struct {
+		result1 go_cfclient.Org
+		result2 error
+	}
+	AssociateOrgManagerStub        func(orgGUID, userGUID string) (go_cfclient.Org, error)
+	associateOrgManagerMutex       sync.RWMutex
+	associateOrgManagerArgsForCall []struct {
+		orgGUID  string
+		userGUID string
+	}
+	associateOrgManagerReturns struct {
+		result1 go_cfclient.Org
+		result2 error
+	}
+	AssociateOrgBillingManagerStub        func(orgGUID, userGUID string) (go_cfclient.Org, error)
+	associateOrgBillingManagerMutex       sync.RWMutex
+	associateOrgBillingManagerArgsForCall []struct {
+		orgGUID  string
+		userGUID string
+	}
+	associateOrgBillingManagerReturns struct {
+		result1 go_cfclient.Org
+		result2 error
+	}
+	AssociateSpaceAuditorStub        func(spaceGUID, userGUID string) (go_cfclient.Space, error)
+	associateSpaceAuditorMutex       sync.RWMutex
+	associateSpaceAuditorArgsForCall []struct {
+		spaceGUID string
+		userGUID  string
+	}
+	associateSpaceAuditorReturns struct {
+		result1 go_cfclient.Space
+		result2 error
+	}
+	AssociateSpaceDeveloperStub        func(spaceGUID, userGUID string) (go_cfclient.Space, error)
+	associateSpaceDeveloperMutex       sync.RWMutex
+	associateSpaceDeveloperArgsForCall []struct {
+		spaceGUID string
+		userGUID  string
+	}
+	associateSpaceDeveloperReturns struct {
+		result1 go_cfclient.Space
+		result2 error
+	}
+	AssociateSpaceManagerStub        func(spaceGUID, userGUID string) (go_cfclient.Space, error)
+	associateSpaceManagerMutex       sync.RWMutex
+	associateSpaceManagerArgsForCall []struct {
+		spaceGUID string
+		userGUID  string
+	}
+	associateSpaceManagerReturns struct {
+		result1 go_cfclient.Space
+		result2 error
+	}
+	RemoveOrgUserStub        func(orgGUID, userGUID string) error
+	removeOrgUserMutex       sync.RWMutex
+	removeOrgUserArgsForCall []struct {
+		orgGUID  string
+		userGUID string
+	}
+	removeOrgUserReturns struct {
+		result1 error
+	}
+	RemoveOrgAuditorStub        func(orgGUID, userGUID string) error
+	removeOrgAuditorMutex       sync.RWMutex
+	removeOrgAuditorArgsForCall []struct {
+		orgGUID  string
+		userGUID string
+	}
+	removeOrgAuditorReturns struct {
+		result1 error
+	}
+	RemoveOrgManagerStub        func(orgGUID, userGUID string) error
+	removeOrgManagerMutex       sync.RWMutex
+	removeOrgManagerArgsForCall []struct {
+		orgGUID  string
+		userGUID string
+	}
+	removeOrgManagerReturns struct {
+		result1 error
+	}
+	RemoveOrgBillingManagerStub        func(orgGUID, userGUID string) error
+	removeOrgBillingManagerMutex       sync.RWMutex
+	removeOrgBillingManagerArgsForCall []struct {
+		orgGUID  string
+		userGUID string
+	}
+	removeOrgBillingManagerReturns struct {
+		result1 error
+	}
+	RemoveSpaceAuditorStub        func(spaceGUID, userGUID string) error
+	removeSpaceAuditorMutex       sync.RWMutex
+	removeSpaceAuditorArgsForCall []struct {
+		spaceGUID string
+		userGUID  string
+	}
+	removeSpaceAuditorReturns struct {
+		result1 error
+	}
+	RemoveSpaceDeveloperStub        func(spaceGUID, userGUID string) error
+	removeSpaceDeveloperMutex       sync.RWMutex
+	removeSpaceDeveloperArgsForCall []struct {
+		spaceGUID string
+		userGUID  string
+	}
+	removeSpaceDeveloperReturns struct {
+		result1 error
+	}
+	RemoveSpaceManagerStub        func(spaceGUID, userGUID string) error
+	removeSpaceManagerMutex       sync.RWMutex
+	removeSpaceManagerArgsForCall []struct {
+		spaceGUID string
+		userGUID  string
+	}
+	removeSpaceManagerReturns struct {
+		result1 error
+	}
+	DeleteUserStub        func(userGUID string) error
+	deleteUserMutex       sync.RWMutex
+	deleteUserArgsForCall []struct {
+		userGUID string
+	}
+	deleteUserReturns struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -974,6 +1115,522 @@ func (fake *FakeCFClient) ListSpacesByQueryReturns(result1 []go_cfclient.Space,
 	}{result1, result2}
 }
 
+func (fake *FakeCFClient) AssociateOrgUser(orgGUID string, userGUID string) (go_cfclient.Org, error) {
+	fake.associateOrgUserMutex.Lock()
+	fake.associateOrgUserArgsForCall = append(fake.associateOrgUserArgsForCall, struct {
+		orgGUID  string
+		userGUID string
+	}{orgGUID, userGUID})
+	fake.recordInvocation("AssociateOrgUser", []interface{}{orgGUID, userGUID})
+	fake.associateOrgUserMutex.Unlock()
+	if fake.AssociateOrgUserStub != nil {
+		return fake.AssociateOrgUserStub(orgGUID, userGUID)
+	} else {
+		return fake.associateOrgUserReturns.result1, fake.associateOrgUserReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) AssociateOrgUserCallCount() int {
+	fake.associateOrgUserMutex.RLock()
+	defer fake.associateOrgUserMutex.RUnlock()
+	return len(fake.associateOrgUserArgsForCall)
+}
+
+func (fake *FakeCFClient) AssociateOrgUserArgsForCall(i int) (string, string) {
+	fake.associateOrgUserMutex.RLock()
+	defer fake.associateOrgUserMutex.RUnlock()
+	return fake.associateOrgUserArgsForCall[i].orgGUID, fake.associateOrgUserArgsForCall[i].userGUID
+}
+
+func (fake *FakeCFClient) AssociateOrgUserReturns(result1 go_cfclient.Org, result2 error) {
+	fake.AssociateOrgUserStub = nil
+	fake.associateOrgUserReturns = struct {
+		result1 go_cfclient.Org
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) AssociateOrgAuditor(orgGUID string, userGUID string) (go_cfclient.Org, error) {
+	fake.associateOrgAuditorMutex.Lock()
+	fake.associateOrgAuditorArgsForCall = append(fake.associateOrgAuditorArgsForCall, struct {
+		orgGUID  string
+		userGUID string
+	}{orgGUID, userGUID})
+	fake.recordInvocation("AssociateOrgAuditor", []interface{}{orgGUID, userGUID})
+	fake.associateOrgAuditorMutex.Unlock()
+	if fake.AssociateOrgAuditorStub != nil {
+		return fake.AssociateOrgAuditorStub(orgGUID, userGUID)
+	} else {
+		return fake.associateOrgAuditorReturns.result1, fake.associateOrgAuditorReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) AssociateOrgAuditorCallCount() int {
+	fake.associateOrgAuditorMutex.RLock()
+	defer fake.associateOrgAuditorMutex.RUnlock()
+	return len(fake.associateOrgAuditorArgsForCall)
+}
+
+func (fake *FakeCFClient) AssociateOrgAuditorArgsForCall(i int) (string, string) {
+	fake.associateOrgAuditorMutex.RLock()
+	defer fake.associateOrgAuditorMutex.RUnlock()
+	return fake.associateOrgAuditorArgsForCall[i].orgGUID, fake.associateOrgAuditorArgsForCall[i].userGUID
+}
+
+func (fake *FakeCFClient) AssociateOrgAuditorReturns(result1 go_cfclient.Org, result2 error) {
+	fake.AssociateOrgAuditorStub = nil
+	fake.associateOrgAuditorReturns = struct {
+		result1 go_cfclient.Org
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) AssociateOrgManager(orgGUID string, userGUID string) (go_cfclient.Org, error) {
+	fake.associateOrgManagerMutex.Lock()
+	fake.associateOrgManagerArgsForCall = append(fake.associateOrgManagerArgsForCall, struct {
+		orgGUID  string
+		userGUID string
+	}{orgGUID, userGUID})
+	fake.recordInvocation("AssociateOrgManager", []interface{}{orgGUID, userGUID})
+	fake.associateOrgManagerMutex.Unlock()
+	if fake.AssociateOrgManagerStub != nil {
+		return fake.AssociateOrgManagerStub(orgGUID, userGUID)
+	} else {
+		return fake.associateOrgManagerReturns.result1, fake.associateOrgManagerReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) AssociateOrgManagerCallCount() int {
+	fake.associateOrgManagerMutex.RLock()
+	defer fake.associateOrgManagerMutex.RUnlock()
+	return len(fake.associateOrgManagerArgsForCall)
+}
+
+func (fake *FakeCFClient) AssociateOrgManagerArgsForCall(i int) (string, string) {
+	fake.associateOrgManagerMutex.RLock()
+	defer fake.associateOrgManagerMutex.RUnlock()
+	return fake.associateOrgManagerArgsForCall[i].orgGUID, fake.associateOrgManagerArgsForCall[i].userGUID
+}
+
+func (fake *FakeCFClient) AssociateOrgManagerReturns(result1 go_cfclient.Org, result2 error) {
+	fake.AssociateOrgManagerStub = nil
+	fake.associateOrgManagerReturns = struct {
+		result1 go_cfclient.Org
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) AssociateOrgBillingManager(orgGUID string, userGUID string) (go_cfclient.Org, error) {
+	fake.associateOrgBillingManagerMutex.Lock()
+	fake.associateOrgBillingManagerArgsForCall = append(fake.associateOrgBillingManagerArgsForCall, struct {
+		orgGUID  string
+		userGUID string
+	}{orgGUID, userGUID})
+	fake.recordInvocation("AssociateOrgBillingManager", []interface{}{orgGUID, userGUID})
+	fake.associateOrgBillingManagerMutex.Unlock()
+	if fake.AssociateOrgBillingManagerStub != nil {
+		return fake.AssociateOrgBillingManagerStub(orgGUID, userGUID)
+	} else {
+		return fake.associateOrgBillingManagerReturns.result1, fake.associateOrgBillingManagerReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) AssociateOrgBillingManagerCallCount() int {
+	fake.associateOrgBillingManagerMutex.RLock()
+	defer fake.associateOrgBillingManagerMutex.RUnlock()
+	return len(fake.associateOrgBillingManagerArgsForCall)
+}
+
+func (fake *FakeCFClient) AssociateOrgBillingManagerArgsForCall(i int) (string, string) {
+	fake.associateOrgBillingManagerMutex.RLock()
+	defer fake.associateOrgBillingManagerMutex.RUnlock()
+	return fake.associateOrgBillingManagerArgsForCall[i].orgGUID, fake.associateOrgBillingManagerArgsForCall[i].userGUID
+}
+
+func (fake *FakeCFClient) AssociateOrgBillingManagerReturns(result1 go_cfclient.Org, result2 error) {
+	fake.AssociateOrgBillingManagerStub = nil
+	fake.associateOrgBillingManagerReturns = struct {
+		result1 go_cfclient.Org
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) AssociateSpaceAuditor(spaceGUID string, userGUID string) (go_cfclient.Space, error) {
+	fake.associateSpaceAuditorMutex.Lock()
+	fake.associateSpaceAuditorArgsForCall = append(fake.associateSpaceAuditorArgsForCall, struct {
+		spaceGUID string
+		userGUID  string
+	}{spaceGUID, userGUID})
+	fake.recordInvocation("AssociateSpaceAuditor", []interface{}{spaceGUID, userGUID})
+	fake.associateSpaceAuditorMutex.Unlock()
+	if fake.AssociateSpaceAuditorStub != nil {
+		return fake.AssociateSpaceAuditorStub(spaceGUID, userGUID)
+	} else {
+		return fake.associateSpaceAuditorReturns.result1, fake.associateSpaceAuditorReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) AssociateSpaceAuditorCallCount() int {
+	fake.associateSpaceAuditorMutex.RLock()
+	defer fake.associateSpaceAuditorMutex.RUnlock()
+	return len(fake.associateSpaceAuditorArgsForCall)
+}
+
+func (fake *FakeCFClient) AssociateSpaceAuditorArgsForCall(i int) (string, string) {
+	fake.associateSpaceAuditorMutex.RLock()
+	defer fake.associateSpaceAuditorMutex.RUnlock()
+	return fake.associateSpaceAuditorArgsForCall[i].spaceGUID, fake.associateSpaceAuditorArgsForCall[i].userGUID
+}
+
+func (fake *FakeCFClient) AssociateSpaceAuditorReturns(result1 go_cfclient.Space, result2 error) {
+	fake.AssociateSpaceAuditorStub = nil
+	fake.associateSpaceAuditorReturns = struct {
+		result1 go_cfclient.Space
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) AssociateSpaceDeveloper(spaceGUID string, userGUID string) (go_cfclient.Space, error) {
+	fake.associateSpaceDeveloperMutex.Lock()
+	fake.associateSpaceDeveloperArgsForCall = append(fake.associateSpaceDeveloperArgsForCall, struct {
+		spaceGUID string
+		userGUID  string
+	}{spaceGUID, userGUID})
+	fake.recordInvocation("AssociateSpaceDeveloper", []interface{}{spaceGUID, userGUID})
+	fake.associateSpaceDeveloperMutex.Unlock()
+	if fake.AssociateSpaceDeveloperStub != nil {
+		return fake.AssociateSpaceDeveloperStub(spaceGUID, userGUID)
+	} else {
+		return fake.associateSpaceDeveloperReturns.result1, fake.associateSpaceDeveloperReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) AssociateSpaceDeveloperCallCount() int {
+	fake.associateSpaceDeveloperMutex.RLock()
+	defer fake.associateSpaceDeveloperMutex.RUnlock()
+	return len(fake.associateSpaceDeveloperArgsForCall)
+}
+
+func (fake *FakeCFClient) AssociateSpaceDeveloperArgsForCall(i int) (string, string) {
+	fake.associateSpaceDeveloperMutex.RLock()
+	defer fake.associateSpaceDeveloperMutex.RUnlock()
+	return fake.associateSpaceDeveloperArgsForCall[i].spaceGUID, fake.associateSpaceDeveloperArgsForCall[i].userGUID
+}
+
+func (fake *FakeCFClient) AssociateSpaceDeveloperReturns(result1 go_cfclient.Space, result2 error) {
+	fake.AssociateSpaceDeveloperStub = nil
+	fake.associateSpaceDeveloperReturns = struct {
+		result1 go_cfclient.Space
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) AssociateSpaceManager(spaceGUID string, userGUID string) (go_cfclient.Space, error) {
+	fake.associateSpaceManagerMutex.Lock()
+	fake.associateSpaceManagerArgsForCall = append(fake.associateSpaceManagerArgsForCall, struct {
+		spaceGUID string
+		userGUID  string
+	}{spaceGUID, userGUID})
+	fake.recordInvocation("AssociateSpaceManager", []interface{}{spaceGUID, userGUID})
+	fake.associateSpaceManagerMutex.Unlock()
+	if fake.AssociateSpaceManagerStub != nil {
+		return fake.AssociateSpaceManagerStub(spaceGUID, userGUID)
+	} else {
+		return fake.associateSpaceManagerReturns.result1, fake.associateSpaceManagerReturns.result2
+	}
+}
+
+func (fake *FakeCFClient) AssociateSpaceManagerCallCount() int {
+	fake.associateSpaceManagerMutex.RLock()
+	defer fake.associateSpaceManagerMutex.RUnlock()
+	return len(fake.associateSpaceManagerArgsForCall)
+}
+
+func (fake *FakeCFClient) AssociateSpaceManagerArgsForCall(i int) (string, string) {
+	fake.associateSpaceManagerMutex.RLock()
+	defer fake.associateSpaceManagerMutex.RUnlock()
+	return fake.associateSpaceManagerArgsForCall[i].spaceGUID, fake.associateSpaceManagerArgsForCall[i].userGUID
+}
+
+func (fake *FakeCFClient) AssociateSpaceManagerReturns(result1 go_cfclient.Space, result2 error) {
+	fake.AssociateSpaceManagerStub = nil
+	fake.associateSpaceManagerReturns = struct {
+		result1 go_cfclient.Space
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) RemoveOrgUser(orgGUID string, userGUID string) error {
+	fake.removeOrgUserMutex.Lock()
+	fake.removeOrgUserArgsForCall = append(fake.removeOrgUserArgsForCall, struct {
+		orgGUID  string
+		userGUID string
+	}{orgGUID, userGUID})
+	fake.recordInvocation("RemoveOrgUser", []interface{}{orgGUID, userGUID})
+	fake.removeOrgUserMutex.Unlock()
+	if fake.RemoveOrgUserStub != nil {
+		return fake.RemoveOrgUserStub(orgGUID, userGUID)
+	} else {
+		return fake.removeOrgUserReturns.result1
+	}
+}
+
+func (fake *FakeCFClient) RemoveOrgUserCallCount() int {
+	fake.removeOrgUserMutex.RLock()
+	defer fake.removeOrgUserMutex.RUnlock()
+	return len(fake.removeOrgUserArgsForCall)
+}
+
+func (fake *FakeCFClient) RemoveOrgUserArgsForCall(i int) (string, string) {
+	fake.removeOrgUserMutex.RLock()
+	defer fake.removeOrgUserMutex.RUnlock()
+	return fake.removeOrgUserArgsForCall[i].orgGUID, fake.removeOrgUserArgsForCall[i].userGUID
+}
+
+func (fake *FakeCFClient) RemoveOrgUserReturns(result1 error) {
+	fake.RemoveOrgUserStub = nil
+	fake.removeOrgUserReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCFClient) RemoveOrgAuditor(orgGUID string, userGUID string) error {
+	fake.removeOrgAuditorMutex.Lock()
+	fake.removeOrgAuditorArgsForCall = append(fake.removeOrgAuditorArgsForCall, struct {
+		orgGUID  string
+		userGUID string
+	}{orgGUID, userGUID})
+	fake.recordInvocation("RemoveOrgAuditor", []interface{}{orgGUID, userGUID})
+	fake.removeOrgAuditorMutex.Unlock()
+	if fake.RemoveOrgAuditorStub != nil {
+		return fake.RemoveOrgAuditorStub(orgGUID, userGUID)
+	} else {
+		return fake.removeOrgAuditorReturns.result1
+	}
+}
+
+func (fake *FakeCFClient) RemoveOrgAuditorCallCount() int {
+	fake.removeOrgAuditorMutex.RLock()
+	defer fake.removeOrgAuditorMutex.RUnlock()
+	return len(fake.removeOrgAuditorArgsForCall)
+}
+
+func (fake *FakeCFClient) RemoveOrgAuditorArgsForCall(i int) (string, string) {
+	fake.removeOrgAuditorMutex.RLock()
+	defer fake.removeOrgAuditorMutex.RUnlock()
+	return fake.removeOrgAuditorArgsForCall[i].orgGUID, fake.removeOrgAuditorArgsForCall[i].userGUID
+}
+
+func (fake *FakeCFClient) RemoveOrgAuditorReturns(result1 error) {
+	fake.RemoveOrgAuditorStub = nil
+	fake.removeOrgAuditorReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCFClient) RemoveOrgManager(orgGUID string, userGUID string) error {
+	fake.removeOrgManagerMutex.Lock()
+	fake.removeOrgManagerArgsForCall = append(fake.removeOrgManagerArgsForCall, struct {
+		orgGUID  string
+		userGUID string
+	}{orgGUID, userGUID})
+	fake.recordInvocation("RemoveOrgManager", []interface{}{orgGUID, userGUID})
+	fake.removeOrgManagerMutex.Unlock()
+	if fake.RemoveOrgManagerStub != nil {
+		return fake.RemoveOrgManagerStub(orgGUID, userGUID)
+	} else {
+		return fake.removeOrgManagerReturns.result1
+	}
+}
+
+func (fake *FakeCFClient) RemoveOrgManagerCallCount() int {
+	fake.removeOrgManagerMutex.RLock()
+	defer fake.removeOrgManagerMutex.RUnlock()
+	return len(fake.removeOrgManagerArgsForCall)
+}
+
+func (fake *FakeCFClient) RemoveOrgManagerArgsForCall(i int) (string, string) {
+	fake.removeOrgManagerMutex.RLock()
+	defer fake.removeOrgManagerMutex.RUnlock()
+	return fake.removeOrgManagerArgsForCall[i].orgGUID, fake.removeOrgManagerArgsForCall[i].userGUID
+}
+
+func (fake *FakeCFClient) RemoveOrgManagerReturns(result1 error) {
+	fake.RemoveOrgManagerStub = nil
+	fake.removeOrgManagerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCFClient) RemoveOrgBillingManager(orgGUID string, userGUID string) error {
+	fake.removeOrgBillingManagerMutex.Lock()
+	fake.removeOrgBillingManagerArgsForCall = append(fake.removeOrgBillingManagerArgsForCall, struct {
+		orgGUID  string
+		userGUID string
+	}{orgGUID, userGUID})
+	fake.recordInvocation("RemoveOrgBillingManager", []interface{}{orgGUID, userGUID})
+	fake.removeOrgBillingManagerMutex.Unlock()
+	if fake.RemoveOrgBillingManagerStub != nil {
+		return fake.RemoveOrgBillingManagerStub(orgGUID, userGUID)
+	} else {
+		return fake.removeOrgBillingManagerReturns.result1
+	}
+}
+
+func (fake *FakeCFClient) RemoveOrgBillingManagerCallCount() int {
+	fake.removeOrgBillingManagerMutex.RLock()
+	defer fake.removeOrgBillingManagerMutex.RUnlock()
+	return len(fake.removeOrgBillingManagerArgsForCall)
+}
+
+func (fake *FakeCFClient) RemoveOrgBillingManagerArgsForCall(i int) (string, string) {
+	fake.removeOrgBillingManagerMutex.RLock()
+	defer fake.removeOrgBillingManagerMutex.RUnlock()
+	return fake.removeOrgBillingManagerArgsForCall[i].orgGUID, fake.removeOrgBillingManagerArgsForCall[i].userGUID
+}
+
+func (fake *FakeCFClient) RemoveOrgBillingManagerReturns(result1 error) {
+	fake.RemoveOrgBillingManagerStub = nil
+	fake.removeOrgBillingManagerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCFClient) RemoveSpaceAuditor(spaceGUID string, userGUID string) error {
+	fake.removeSpaceAuditorMutex.Lock()
+	fake.removeSpaceAuditorArgsForCall = append(fake.removeSpaceAuditorArgsForCall, struct {
+		spaceGUID string
+		userGUID  string
+	}{spaceGUID, userGUID})
+	fake.recordInvocation("RemoveSpaceAuditor", []interface{}{spaceGUID, userGUID})
+	fake.removeSpaceAuditorMutex.Unlock()
+	if fake.RemoveSpaceAuditorStub != nil {
+		return fake.RemoveSpaceAuditorStub(spaceGUID, userGUID)
+	} else {
+		return fake.removeSpaceAuditorReturns.result1
+	}
+}
+
+func (fake *FakeCFClient) RemoveSpaceAuditorCallCount() int {
+	fake.removeSpaceAuditorMutex.RLock()
+	defer fake.removeSpaceAuditorMutex.RUnlock()
+	return len(fake.removeSpaceAuditorArgsForCall)
+}
+
+func (fake *FakeCFClient) RemoveSpaceAuditorArgsForCall(i int) (string, string) {
+	fake.removeSpaceAuditorMutex.RLock()
+	defer fake.removeSpaceAuditorMutex.RUnlock()
+	return fake.removeSpaceAuditorArgsForCall[i].spaceGUID, fake.removeSpaceAuditorArgsForCall[i].userGUID
+}
+
+func (fake *FakeCFClient) RemoveSpaceAuditorReturns(result1 error) {
+	fake.RemoveSpaceAuditorStub = nil
+	fake.removeSpaceAuditorReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCFClient) RemoveSpaceDeveloper(spaceGUID string, userGUID string) error {
+	fake.removeSpaceDeveloperMutex.Lock()
+	fake.removeSpaceDeveloperArgsForCall = append(fake.removeSpaceDeveloperArgsForCall, struct {
+		spaceGUID string
+		userGUID  string
+	}{spaceGUID, userGUID})
+	fake.recordInvocation("RemoveSpaceDeveloper", []interface{}{spaceGUID, userGUID})
+	fake.removeSpaceDeveloperMutex.Unlock()
+	if fake.RemoveSpaceDeveloperStub != nil {
+		return fake.RemoveSpaceDeveloperStub(spaceGUID, userGUID)
+	} else {
+		return fake.removeSpaceDeveloperReturns.result1
+	}
+}
+
+func (fake *FakeCFClient) RemoveSpaceDeveloperCallCount() int {
+	fake.removeSpaceDeveloperMutex.RLock()
+	defer fake.removeSpaceDeveloperMutex.RUnlock()
+	return len(fake.removeSpaceDeveloperArgsForCall)
+}
+
+func (fake *FakeCFClient) RemoveSpaceDeveloperArgsForCall(i int) (string, string) {
+	fake.removeSpaceDeveloperMutex.RLock()
+	defer fake.removeSpaceDeveloperMutex.RUnlock()
+	return fake.removeSpaceDeveloperArgsForCall[i].spaceGUID, fake.removeSpaceDeveloperArgsForCall[i].userGUID
+}
+
+func (fake *FakeCFClient) RemoveSpaceDeveloperReturns(result1 error) {
+	fake.RemoveSpaceDeveloperStub = nil
+	fake.removeSpaceDeveloperReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCFClient) RemoveSpaceManager(spaceGUID string, userGUID string) error {
+	fake.removeSpaceManagerMutex.Lock()
+	fake.removeSpaceManagerArgsForCall = append(fake.removeSpaceManagerArgsForCall, struct {
+		spaceGUID string
+		userGUID  string
+	}{spaceGUID, userGUID})
+	fake.recordInvocation("RemoveSpaceManager", []interface{}{spaceGUID, userGUID})
+	fake.removeSpaceManagerMutex.Unlock()
+	if fake.RemoveSpaceManagerStub != nil {
+		return fake.RemoveSpaceManagerStub(spaceGUID, userGUID)
+	} else {
+		return fake.removeSpaceManagerReturns.result1
+	}
+}
+
+func (fake *FakeCFClient) RemoveSpaceManagerCallCount() int {
+	fake.removeSpaceManagerMutex.RLock()
+	defer fake.removeSpaceManagerMutex.RUnlock()
+	return len(fake.removeSpaceManagerArgsForCall)
+}
+
+func (fake *FakeCFClient) RemoveSpaceManagerArgsForCall(i int) (string, string) {
+	fake.removeSpaceManagerMutex.RLock()
+	defer fake.removeSpaceManagerMutex.RUnlock()
+	return fake.removeSpaceManagerArgsForCall[i].spaceGUID, fake.removeSpaceManagerArgsForCall[i].userGUID
+}
+
+func (fake *FakeCFClient) RemoveSpaceManagerReturns(result1 error) {
+	fake.RemoveSpaceManagerStub = nil
+	fake.removeSpaceManagerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCFClient) DeleteUser(userGUID string) error {
+	fake.deleteUserMutex.Lock()
+	fake.deleteUserArgsForCall = append(fake.deleteUserArgsForCall, struct {
+		userGUID string
+	}{userGUID})
+	fake.recordInvocation("DeleteUser", []interface{}{userGUID})
+	fake.deleteUserMutex.Unlock()
+	if fake.DeleteUserStub != nil {
+		return fake.DeleteUserStub(userGUID)
+	} else {
+		return fake.deleteUserReturns.result1
+	}
+}
+
+func (fake *FakeCFClient) DeleteUserCallCount() int {
+	fake.deleteUserMutex.RLock()
+	defer fake.deleteUserMutex.RUnlock()
+	return len(fake.deleteUserArgsForCall)
+}
+
+func (fake *FakeCFClient) DeleteUserArgsForCall(i int) string {
+	fake.deleteUserMutex.RLock()
+	defer fake.deleteUserMutex.RUnlock()
+	return fake.deleteUserArgsForCall[i].userGUID
+}
+
+func (fake *FakeCFClient) DeleteUserReturns(result1 error) {
+	fake.DeleteUserStub = nil
+	fake.deleteUserReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeCFClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -1021,6 +1678,36 @@ func (fake *FakeCFClient) Invocations() map[string][][]interface{} {
 	defer fake.listOrgUsersMutex.RUnlock()
 	fake.listSpacesByQueryMutex.RLock()
 	defer fake.listSpacesByQueryMutex.RUnlock()
+	fake.associateOrgUserMutex.RLock()
+	defer fake.associateOrgUserMutex.RUnlock()
+	fake.associateOrgAuditorMutex.RLock()
+	defer fake.associateOrgAuditorMutex.RUnlock()
+	fake.associateOrgManagerMutex.RLock()
+	defer fake.associateOrgManagerMutex.RUnlock()
+	fake.associateOrgBillingManagerMutex.RLock()
+	defer fake.associateOrgBillingManagerMutex.RUnlock()
+	fake.associateSpaceAuditorMutex.RLock()
+	defer fake.associateSpaceAuditorMutex.RUnlock()
+	fake.associateSpaceDeveloperMutex.RLock()
+	defer fake.associateSpaceDeveloperMutex.RUnlock()
+	fake.associateSpaceManagerMutex.RLock()
+	defer fake.associateSpaceManagerMutex.RUnlock()
+	fake.removeOrgUserMutex.RLock()
+	defer fake.removeOrgUserMutex.RUnlock()
+	fake.removeOrgAuditorMutex.RLock()
+	defer fake.removeOrgAuditorMutex.RUnlock()
+	fake.removeOrgManagerMutex.RLock()
+	defer fake.removeOrgManagerMutex.RUnlock()
+	fake.removeOrgBillingManagerMutex.RLock()
+	defer fake.removeOrgBillingManagerMutex.RUnlock()
+	fake.removeSpaceAuditorMutex.RLock()
+	defer fake.removeSpaceAuditorMutex.RUnlock()
+	fake.removeSpaceDeveloperMutex.RLock()
+	defer fake.removeSpaceDeveloperMutex.RUnlock()
+	fake.removeSpaceManagerMutex.RLock()
+	defer fake.removeSpaceManagerMutex.RUnlock()
+	fake.deleteUserMutex.RLock()
+	defer fake.deleteUserMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -40,6 +40,56 @@ type FakeManager struct {
 	cleanupOrgUsersReturns     struct {
 		result1 error
 	}
+	MigrateUserOriginStub        func() error
+	migrateUserOriginMutex       sync.RWMutex
+	migrateUserOriginArgsForCall []struct{}
+	migrateUserOriginReturns     struct {
+		result1 error
+	}
+	CleanupOriginUsersStub        func() error
+	cleanupOriginUsersMutex       sync.RWMutex
+	cleanupOriginUsersArgsForCall []struct{}
+	cleanupOriginUsersReturns     struct {
+		result1 error
+	}
+	DedupeUAAUsersStub        func(preferredOrigin string, consolidate bool, clientUser string) ([]user.DuplicateUsers, error)
+	dedupeUAAUsersMutex       sync.RWMutex
+	dedupeUAAUsersArgsForCall []struct {
+		preferredOrigin string
+		consolidate     bool
+		clientUser      string
+	}
+	dedupeUAAUsersReturns struct {
+		result1 []user.DuplicateUsers
+		result2 error
+	}
+	ListMissingUsersStub        func() ([]user.MissingUser, error)
+	listMissingUsersMutex       sync.RWMutex
+	listMissingUsersArgsForCall []struct{}
+	listMissingUsersReturns     struct {
+		result1 []user.MissingUser
+		result2 error
+	}
+	ListExternalUserProblemsStub        func() ([]user.ExternalUserProblem, error)
+	listExternalUserProblemsMutex       sync.RWMutex
+	listExternalUserProblemsArgsForCall []struct{}
+	listExternalUserProblemsReturns     struct {
+		result1 []user.ExternalUserProblem
+		result2 error
+	}
+	DeveloperReportStub        func() (*user.DeveloperReport, error)
+	developerReportMutex       sync.RWMutex
+	developerReportArgsForCall []struct{}
+	developerReportReturns     struct {
+		result1 *user.DeveloperReport
+		result2 error
+	}
+	UpdateRoleGroupsStub        func() error
+	updateRoleGroupsMutex       sync.RWMutex
+	updateRoleGroupsArgsForCall []struct{}
+	updateRoleGroupsReturns     struct {
+		result1 error
+	}
 	ListSpaceAuditorsStub        func(spaceGUID string) (map[string]string, error)
 	listSpaceAuditorsMutex       sync.RWMutex
 	listSpaceAuditorsArgsForCall []struct {
@@ -94,6 +144,27 @@ type FakeManager struct {
 		result1 map[string]string
 		result2 error
 	}
+	CreatePersonalSpacesStub        func() error
+	createPersonalSpacesMutex       sync.RWMutex
+	createPersonalSpacesArgsForCall []struct{}
+	createPersonalSpacesReturns     struct {
+		result1 error
+	}
+	ListUndeclaredAdminsStub        func() ([]user.AdminAccess, error)
+	listUndeclaredAdminsMutex       sync.RWMutex
+	listUndeclaredAdminsArgsForCall []struct{}
+	listUndeclaredAdminsReturns     struct {
+		result1 []user.AdminAccess
+		result2 error
+	}
+	UpdateGroupUsersStub        func(group string) error
+	updateGroupUsersMutex       sync.RWMutex
+	updateGroupUsersArgsForCall []struct {
+		group string
+	}
+	updateGroupUsersReturns struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -231,6 +302,195 @@ func (fake *FakeManager) CleanupOrgUsersReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeManager) MigrateUserOrigin() error {
+	fake.migrateUserOriginMutex.Lock()
+	fake.migrateUserOriginArgsForCall = append(fake.migrateUserOriginArgsForCall, struct{}{})
+	fake.recordInvocation("MigrateUserOrigin", []interface{}{})
+	fake.migrateUserOriginMutex.Unlock()
+	if fake.MigrateUserOriginStub != nil {
+		return fake.MigrateUserOriginStub()
+	} else {
+		return fake.migrateUserOriginReturns.result1
+	}
+}
+
+func (fake *FakeManager) MigrateUserOriginCallCount() int {
+	fake.migrateUserOriginMutex.RLock()
+	defer fake.migrateUserOriginMutex.RUnlock()
+	return len(fake.migrateUserOriginArgsForCall)
+}
+
+func (fake *FakeManager) MigrateUserOriginReturns(result1 error) {
+	fake.MigrateUserOriginStub = nil
+	fake.migrateUserOriginReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) CleanupOriginUsers() error {
+	fake.cleanupOriginUsersMutex.Lock()
+	fake.cleanupOriginUsersArgsForCall = append(fake.cleanupOriginUsersArgsForCall, struct{}{})
+	fake.recordInvocation("CleanupOriginUsers", []interface{}{})
+	fake.cleanupOriginUsersMutex.Unlock()
+	if fake.CleanupOriginUsersStub != nil {
+		return fake.CleanupOriginUsersStub()
+	} else {
+		return fake.cleanupOriginUsersReturns.result1
+	}
+}
+
+func (fake *FakeManager) CleanupOriginUsersCallCount() int {
+	fake.cleanupOriginUsersMutex.RLock()
+	defer fake.cleanupOriginUsersMutex.RUnlock()
+	return len(fake.cleanupOriginUsersArgsForCall)
+}
+
+func (fake *FakeManager) CleanupOriginUsersReturns(result1 error) {
+	fake.CleanupOriginUsersStub = nil
+	fake.cleanupOriginUsersReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) DedupeUAAUsers(preferredOrigin string, consolidate bool, clientUser string) ([]user.DuplicateUsers, error) {
+	fake.dedupeUAAUsersMutex.Lock()
+	fake.dedupeUAAUsersArgsForCall = append(fake.dedupeUAAUsersArgsForCall, struct {
+		preferredOrigin string
+		consolidate     bool
+		clientUser      string
+	}{preferredOrigin, consolidate, clientUser})
+	fake.recordInvocation("DedupeUAAUsers", []interface{}{preferredOrigin, consolidate, clientUser})
+	fake.dedupeUAAUsersMutex.Unlock()
+	if fake.DedupeUAAUsersStub != nil {
+		return fake.DedupeUAAUsersStub(preferredOrigin, consolidate, clientUser)
+	} else {
+		return fake.dedupeUAAUsersReturns.result1, fake.dedupeUAAUsersReturns.result2
+	}
+}
+
+func (fake *FakeManager) DedupeUAAUsersCallCount() int {
+	fake.dedupeUAAUsersMutex.RLock()
+	defer fake.dedupeUAAUsersMutex.RUnlock()
+	return len(fake.dedupeUAAUsersArgsForCall)
+}
+
+func (fake *FakeManager) DedupeUAAUsersArgsForCall(i int) (string, bool, string) {
+	fake.dedupeUAAUsersMutex.RLock()
+	defer fake.dedupeUAAUsersMutex.RUnlock()
+	return fake.dedupeUAAUsersArgsForCall[i].preferredOrigin, fake.dedupeUAAUsersArgsForCall[i].consolidate, fake.dedupeUAAUsersArgsForCall[i].clientUser
+}
+
+func (fake *FakeManager) DedupeUAAUsersReturns(result1 []user.DuplicateUsers, result2 error) {
+	fake.DedupeUAAUsersStub = nil
+	fake.dedupeUAAUsersReturns = struct {
+		result1 []user.DuplicateUsers
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) ListMissingUsers() ([]user.MissingUser, error) {
+	fake.listMissingUsersMutex.Lock()
+	fake.listMissingUsersArgsForCall = append(fake.listMissingUsersArgsForCall, struct{}{})
+	fake.recordInvocation("ListMissingUsers", []interface{}{})
+	fake.listMissingUsersMutex.Unlock()
+	if fake.ListMissingUsersStub != nil {
+		return fake.ListMissingUsersStub()
+	} else {
+		return fake.listMissingUsersReturns.result1, fake.listMissingUsersReturns.result2
+	}
+}
+
+func (fake *FakeManager) ListMissingUsersCallCount() int {
+	fake.listMissingUsersMutex.RLock()
+	defer fake.listMissingUsersMutex.RUnlock()
+	return len(fake.listMissingUsersArgsForCall)
+}
+
+func (fake *FakeManager) ListMissingUsersReturns(result1 []user.MissingUser, result2 error) {
+	fake.ListMissingUsersStub = nil
+	fake.listMissingUsersReturns = struct {
+		result1 []user.MissingUser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) ListExternalUserProblems() ([]user.ExternalUserProblem, error) {
+	fake.listExternalUserProblemsMutex.Lock()
+	fake.listExternalUserProblemsArgsForCall = append(fake.listExternalUserProblemsArgsForCall, struct{}{})
+	fake.recordInvocation("ListExternalUserProblems", []interface{}{})
+	fake.listExternalUserProblemsMutex.Unlock()
+	if fake.ListExternalUserProblemsStub != nil {
+		return fake.ListExternalUserProblemsStub()
+	} else {
+		return fake.listExternalUserProblemsReturns.result1, fake.listExternalUserProblemsReturns.result2
+	}
+}
+
+func (fake *FakeManager) ListExternalUserProblemsCallCount() int {
+	fake.listExternalUserProblemsMutex.RLock()
+	defer fake.listExternalUserProblemsMutex.RUnlock()
+	return len(fake.listExternalUserProblemsArgsForCall)
+}
+
+func (fake *FakeManager) ListExternalUserProblemsReturns(result1 []user.ExternalUserProblem, result2 error) {
+	fake.ListExternalUserProblemsStub = nil
+	fake.listExternalUserProblemsReturns = struct {
+		result1 []user.ExternalUserProblem
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) DeveloperReport() (*user.DeveloperReport, error) {
+	fake.developerReportMutex.Lock()
+	fake.developerReportArgsForCall = append(fake.developerReportArgsForCall, struct{}{})
+	fake.recordInvocation("DeveloperReport", []interface{}{})
+	fake.developerReportMutex.Unlock()
+	if fake.DeveloperReportStub != nil {
+		return fake.DeveloperReportStub()
+	} else {
+		return fake.developerReportReturns.result1, fake.developerReportReturns.result2
+	}
+}
+
+func (fake *FakeManager) DeveloperReportCallCount() int {
+	fake.developerReportMutex.RLock()
+	defer fake.developerReportMutex.RUnlock()
+	return len(fake.developerReportArgsForCall)
+}
+
+func (fake *FakeManager) DeveloperReportReturns(result1 *user.DeveloperReport, result2 error) {
+	fake.DeveloperReportStub = nil
+	fake.developerReportReturns = struct {
+		result1 *user.DeveloperReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) UpdateRoleGroups() error {
+	fake.updateRoleGroupsMutex.Lock()
+	fake.updateRoleGroupsArgsForCall = append(fake.updateRoleGroupsArgsForCall, struct{}{})
+	fake.recordInvocation("UpdateRoleGroups", []interface{}{})
+	fake.updateRoleGroupsMutex.Unlock()
+	if fake.UpdateRoleGroupsStub != nil {
+		return fake.UpdateRoleGroupsStub()
+	} else {
+		return fake.updateRoleGroupsReturns.result1
+	}
+}
+
+func (fake *FakeManager) UpdateRoleGroupsCallCount() int {
+	fake.updateRoleGroupsMutex.RLock()
+	defer fake.updateRoleGroupsMutex.RUnlock()
+	return len(fake.updateRoleGroupsArgsForCall)
+}
+
+func (fake *FakeManager) UpdateRoleGroupsReturns(result1 error) {
+	fake.UpdateRoleGroupsStub = nil
+	fake.updateRoleGroupsReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeManager) ListSpaceAuditors(spaceGUID string) (map[string]string, error) {
 	fake.listSpaceAuditorsMutex.Lock()
 	fake.listSpaceAuditorsArgsForCall = append(fake.listSpaceAuditorsArgsForCall, struct {
@@ -435,6 +695,90 @@ func (fake *FakeManager) ListOrgManagersReturns(result1 map[string]string, resul
 	}{result1, result2}
 }
 
+func (fake *FakeManager) CreatePersonalSpaces() error {
+	fake.createPersonalSpacesMutex.Lock()
+	fake.createPersonalSpacesArgsForCall = append(fake.createPersonalSpacesArgsForCall, struct{}{})
+	fake.recordInvocation("CreatePersonalSpaces", []interface{}{})
+	fake.createPersonalSpacesMutex.Unlock()
+	if fake.CreatePersonalSpacesStub != nil {
+		return fake.CreatePersonalSpacesStub()
+	} else {
+		return fake.createPersonalSpacesReturns.result1
+	}
+}
+
+func (fake *FakeManager) CreatePersonalSpacesCallCount() int {
+	fake.createPersonalSpacesMutex.RLock()
+	defer fake.createPersonalSpacesMutex.RUnlock()
+	return len(fake.createPersonalSpacesArgsForCall)
+}
+
+func (fake *FakeManager) CreatePersonalSpacesReturns(result1 error) {
+	fake.CreatePersonalSpacesStub = nil
+	fake.createPersonalSpacesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManager) ListUndeclaredAdmins() ([]user.AdminAccess, error) {
+	fake.listUndeclaredAdminsMutex.Lock()
+	fake.listUndeclaredAdminsArgsForCall = append(fake.listUndeclaredAdminsArgsForCall, struct{}{})
+	fake.recordInvocation("ListUndeclaredAdmins", []interface{}{})
+	fake.listUndeclaredAdminsMutex.Unlock()
+	if fake.ListUndeclaredAdminsStub != nil {
+		return fake.ListUndeclaredAdminsStub()
+	} else {
+		return fake.listUndeclaredAdminsReturns.result1, fake.listUndeclaredAdminsReturns.result2
+	}
+}
+
+func (fake *FakeManager) ListUndeclaredAdminsCallCount() int {
+	fake.listUndeclaredAdminsMutex.RLock()
+	defer fake.listUndeclaredAdminsMutex.RUnlock()
+	return len(fake.listUndeclaredAdminsArgsForCall)
+}
+
+func (fake *FakeManager) ListUndeclaredAdminsReturns(result1 []user.AdminAccess, result2 error) {
+	fake.ListUndeclaredAdminsStub = nil
+	fake.listUndeclaredAdminsReturns = struct {
+		result1 []user.AdminAccess
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManager) UpdateGroupUsers(group string) error {
+	fake.updateGroupUsersMutex.Lock()
+	fake.updateGroupUsersArgsForCall = append(fake.updateGroupUsersArgsForCall, struct {
+		group string
+	}{group})
+	fake.recordInvocation("UpdateGroupUsers", []interface{}{group})
+	fake.updateGroupUsersMutex.Unlock()
+	if fake.UpdateGroupUsersStub != nil {
+		return fake.UpdateGroupUsersStub(group)
+	} else {
+		return fake.updateGroupUsersReturns.result1
+	}
+}
+
+func (fake *FakeManager) UpdateGroupUsersCallCount() int {
+	fake.updateGroupUsersMutex.RLock()
+	defer fake.updateGroupUsersMutex.RUnlock()
+	return len(fake.updateGroupUsersArgsForCall)
+}
+
+func (fake *FakeManager) UpdateGroupUsersArgsForCall(i int) string {
+	fake.updateGroupUsersMutex.RLock()
+	defer fake.updateGroupUsersMutex.RUnlock()
+	return fake.updateGroupUsersArgsForCall[i].group
+}
+
+func (fake *FakeManager) UpdateGroupUsersReturns(result1 error) {
+	fake.UpdateGroupUsersStub = nil
+	fake.updateGroupUsersReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeManager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -448,6 +792,20 @@ func (fake *FakeManager) Invocations() map[string][][]interface{} {
 	defer fake.updateOrgUsersMutex.RUnlock()
 	fake.cleanupOrgUsersMutex.RLock()
 	defer fake.cleanupOrgUsersMutex.RUnlock()
+	fake.migrateUserOriginMutex.RLock()
+	defer fake.migrateUserOriginMutex.RUnlock()
+	fake.cleanupOriginUsersMutex.RLock()
+	defer fake.cleanupOriginUsersMutex.RUnlock()
+	fake.dedupeUAAUsersMutex.RLock()
+	defer fake.dedupeUAAUsersMutex.RUnlock()
+	fake.listMissingUsersMutex.RLock()
+	defer fake.listMissingUsersMutex.RUnlock()
+	fake.listExternalUserProblemsMutex.RLock()
+	defer fake.listExternalUserProblemsMutex.RUnlock()
+	fake.developerReportMutex.RLock()
+	defer fake.developerReportMutex.RUnlock()
+	fake.updateRoleGroupsMutex.RLock()
+	defer fake.updateRoleGroupsMutex.RUnlock()
 	fake.listSpaceAuditorsMutex.RLock()
 	defer fake.listSpaceAuditorsMutex.RUnlock()
 	fake.listSpaceDevelopersMutex.RLock()
@@ -460,6 +818,12 @@ func (fake *FakeManager) Invocations() map[string][][]interface{} {
 	defer fake.listOrgBillingManagersMutex.RUnlock()
 	fake.listOrgManagersMutex.RLock()
 	defer fake.listOrgManagersMutex.RUnlock()
+	fake.createPersonalSpacesMutex.RLock()
+	defer fake.createPersonalSpacesMutex.RUnlock()
+	fake.listUndeclaredAdminsMutex.RLock()
+	defer fake.listUndeclaredAdminsMutex.RUnlock()
+	fake.updateGroupUsersMutex.RLock()
+	defer fake.updateGroupUsersMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -0,0 +1,56 @@
+package user
+
+import (
+	"strings"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/xchapter7x/lo"
+)
+
+//UpdateGroupUsers - syncs only the org and space roles granted to the ldap group, such as right after
+//a known change of its members. Each of these roles is synced in full, with its other users and groups.
+func (m *DefaultManager) UpdateGroupUsers(group string) error {
+	m.group = group
+	defer func() { m.group = "" }()
+	if err := m.UpdateOrgUsers(); err != nil {
+		return err
+	}
+	return m.UpdateSpaceUsers()
+}
+
+func hasGroup(groupNames []string, group string) bool {
+	for _, groupName := range groupNames {
+		if strings.EqualFold(groupName, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// orgConfigsOfGroup are the org configurations granting a role to the group
+func (m *DefaultManager) orgConfigsOfGroup(orgConfigs []config.OrgConfig) []config.OrgConfig {
+	var selected []config.OrgConfig
+	for _, orgConfig := range orgConfigs {
+		if hasGroup(orgConfig.GetManagerGroups(), m.group) ||
+			hasGroup(orgConfig.GetBillingManagerGroups(), m.group) ||
+			hasGroup(orgConfig.GetAuditorGroups(), m.group) {
+			selected = append(selected, orgConfig)
+		}
+	}
+	lo.G.Debugf("%d orgs grant a role to ldap group %s", len(selected), m.group)
+	return selected
+}
+
+// spaceConfigsOfGroup are the space configurations granting a role to the group
+func (m *DefaultManager) spaceConfigsOfGroup(spaceConfigs []config.SpaceConfig) []config.SpaceConfig {
+	var selected []config.SpaceConfig
+	for _, spaceConfig := range spaceConfigs {
+		if hasGroup(spaceConfig.GetDeveloperGroups(), m.group) ||
+			hasGroup(spaceConfig.GetManagerGroups(), m.group) ||
+			hasGroup(spaceConfig.GetAuditorGroups(), m.group) {
+			selected = append(selected, spaceConfig)
+		}
+	}
+	lo.G.Debugf("%d spaces grant a role to ldap group %s", len(selected), m.group)
+	return selected
+}
@@ -0,0 +1,133 @@
+package user
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	uaaclient "github.com/cloudfoundry-community/go-uaa"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/email"
+	"github.com/pivotalservices/cf-mgmt/redact"
+	"github.com/pivotalservices/cf-mgmt/secretstore"
+	"github.com/pkg/errors"
+	"github.com/xchapter7x/lo"
+)
+
+// passwordDelivery hands the generated password of a created user to the user.
+type passwordDelivery interface {
+	deliver(userName, password string) error
+}
+
+// initializeInternalUsers enables creating missing internal users when
+// cf-mgmt.yml has create-internal-users.
+func (m *DefaultManager) initializeInternalUsers() error {
+	m.delivery, m.creation = nil, nil
+	globalConfig, err := m.Cfg.GetGlobalConfig()
+	if err != nil {
+		return err
+	}
+	if globalConfig == nil || globalConfig.CreateInternalUsers == nil {
+		return nil
+	}
+	creation := globalConfig.CreateInternalUsers
+	m.creation = creation
+	switch creation.Delivery {
+	case config.DeliverCredHub:
+		m.delivery, err = newCredHubDelivery(creation.CredHub)
+	case config.DeliverEmail:
+		m.delivery, err = newEmailDelivery(creation.Email)
+	default:
+		err = fmt.Errorf("delivery [%s] of create-internal-users in cf-mgmt.yml must be %s or %s", creation.Delivery, config.DeliverCredHub, config.DeliverEmail)
+	}
+	return err
+}
+
+// createInternalUser creates a missing internal user with a generated
+// password, which is delivered before the user is created so that it is
+// never lost.
+func (m *DefaultManager) createInternalUser(userName string, uaaUsers map[string]*uaaclient.User) error {
+	password, err := generatePassword()
+	if err != nil {
+		return err
+	}
+	redact.Secrets(password)
+	if m.Peek {
+		lo.G.Infof("[dry-run]: delivering the password of user %s", userName)
+	} else if err := m.delivery.deliver(userName, password); err != nil {
+		return errors.Wrapf(err, "unable to deliver the password of user %s", userName)
+	}
+	email := userName
+	if !strings.Contains(userName, "@") {
+		email = m.creation.PlaceholderEmail(userName)
+	}
+	user, err := m.UAAMgr.CreateInternalUser(userName, email, password)
+	if err != nil {
+		return err
+	}
+	uaaUsers[strings.ToLower(userName)] = user
+	return nil
+}
+
+func generatePassword() (string, error) {
+	bytes := make([]byte, 24)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+type credHubDelivery struct {
+	credHub *secretstore.CredHub
+	path    string
+}
+
+func newCredHubDelivery(cfg config.CredHubDelivery) (*credHubDelivery, error) {
+	secret := os.Getenv("CREDHUB_CLIENT_SECRET")
+	if cfg.URL == "" || cfg.ClientID == "" || secret == "" {
+		return nil, fmt.Errorf("credhub delivery requires url and client-id in cf-mgmt.yml and CREDHUB_CLIENT_SECRET")
+	}
+	credHub, err := secretstore.NewCredHub(cfg.URL, cfg.ClientID, secret, cfg.SkipSSLValidation)
+	if err != nil {
+		return nil, err
+	}
+	return &credHubDelivery{
+		credHub: credHub,
+		path:    "/" + strings.Trim(cfg.Path, "/"),
+	}, nil
+}
+
+func (d *credHubDelivery) deliver(userName, password string) error {
+	name := strings.TrimSuffix(d.path, "/") + "/" + userName
+	if err := d.credHub.SetPassword(name, password); err != nil {
+		return err
+	}
+	lo.G.Infof("password of user %s written to credhub as %s", userName, name)
+	return nil
+}
+
+type emailDelivery struct {
+	sender *email.Sender
+}
+
+func newEmailDelivery(cfg config.EmailDelivery) (*emailDelivery, error) {
+	sender, err := email.NewSender(cfg, "email delivery", "Your Cloud Foundry account")
+	if err != nil {
+		return nil, err
+	}
+	return &emailDelivery{sender: sender}, nil
+}
+
+func (d *emailDelivery) deliver(userName, password string) error {
+	if !strings.Contains(userName, "@") {
+		return fmt.Errorf("user %s has no email to deliver the password to", userName)
+	}
+	body := fmt.Sprintf("A Cloud Foundry account was created for you.\n\nUsername: %s\nPassword: %s\n\nPlease change the password after logging in.\n", userName, password)
+	if err := d.sender.Send([]string{userName}, body); err != nil {
+		return err
+	}
+	lo.G.Infof("password of user %s emailed", userName)
+	return nil
+}
@@ -0,0 +1,121 @@
+package user_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	uaaclient "github.com/cloudfoundry-community/go-uaa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/config"
+	configfakes "github.com/pivotalservices/cf-mgmt/config/fakes"
+	orgfakes "github.com/pivotalservices/cf-mgmt/organization/fakes"
+	uaafakes "github.com/pivotalservices/cf-mgmt/uaa/fakes"
+	. "github.com/pivotalservices/cf-mgmt/user"
+	"github.com/pivotalservices/cf-mgmt/user/fakes"
+)
+
+var _ = Describe("given create-internal-users", func() {
+	var (
+		userManager *DefaultManager
+		client      *fakes.FakeCFClient
+		uaaFake     *uaafakes.FakeManager
+		fakeReader  *configfakes.FakeReader
+		orgFake     *orgfakes.FakeManager
+		credhub     *httptest.Server
+		credentials map[string]string
+	)
+	BeforeEach(func() {
+		client = new(fakes.FakeCFClient)
+		uaaFake = new(uaafakes.FakeManager)
+		fakeReader = new(configfakes.FakeReader)
+		orgFake = new(orgfakes.FakeManager)
+		userManager = &DefaultManager{
+			Client:     client,
+			Cfg:        fakeReader,
+			UAAMgr:     uaaFake,
+			OrgMgr:     orgFake,
+			LdapConfig: &config.LdapConfig{Enabled: false},
+		}
+		credentials = make(map[string]string)
+		credhub = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/info":
+				json.NewEncoder(w).Encode(map[string]interface{}{"auth-server": map[string]string{"url": credhub.URL}})
+			case "/oauth/token":
+				json.NewEncoder(w).Encode(map[string]string{"access_token": "token", "token_type": "bearer"})
+			case "/api/v1/data":
+				Expect(r.Method).Should(Equal(http.MethodPut))
+				Expect(r.Header.Get("Authorization")).Should(Equal("Bearer token"))
+				credential := map[string]string{}
+				Expect(json.NewDecoder(r.Body).Decode(&credential)).Should(Succeed())
+				credentials[credential["name"]] = credential["value"]
+				w.Write([]byte(`{}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		os.Setenv("CREDHUB_CLIENT_SECRET", "credhub-secret")
+		fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{
+			CreateInternalUsers: &config.UserCreation{
+				Delivery: config.DeliverCredHub,
+				CredHub:  config.CredHubDelivery{URL: credhub.URL, ClientID: "cf-mgmt", Path: "/cf-mgmt/users/"},
+			},
+		}, nil)
+		fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+			{Org: "test-org", Manager: config.UserMgmt{Users: []string{"new-user"}}},
+		}, nil)
+		orgFake.FindOrgReturns(cfclient.Org{Name: "test-org", Guid: "test-org-guid"}, nil)
+		uaaFake.ListUsersReturns(map[string]*uaaclient.User{}, nil)
+		uaaFake.CreateInternalUserReturns(&uaaclient.User{ID: "new-user-guid", Username: "new-user", Origin: "uaa"}, nil)
+	})
+	AfterEach(func() {
+		credhub.Close()
+		os.Unsetenv("CREDHUB_CLIENT_SECRET")
+	})
+
+	It("creates missing internal users with the password written to credhub", func() {
+		Expect(userManager.UpdateOrgUsers()).Should(Succeed())
+		Expect(uaaFake.CreateInternalUserCallCount()).Should(Equal(1))
+		userName, email, password := uaaFake.CreateInternalUserArgsForCall(0)
+		Expect(userName).Should(Equal("new-user"))
+		Expect(email).Should(Equal("new-user@" + config.DefaultPlaceholderEmailDomain))
+		Expect(password).Should(HaveLen(32))
+		Expect(credentials).Should(Equal(map[string]string{"/cf-mgmt/users/new-user": password}))
+		Expect(client.AssociateOrgManagerByUsernameCallCount()).Should(Equal(1))
+	})
+
+	It("gives users whose username is not an email the configured placeholder email", func() {
+		fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{
+			CreateInternalUsers: &config.UserCreation{
+				Delivery:               config.DeliverCredHub,
+				CredHub:                config.CredHubDelivery{URL: credhub.URL, ClientID: "cf-mgmt", Path: "/cf-mgmt/users/"},
+				PlaceholderEmailDomain: "users.example.com",
+			},
+		}, nil)
+		Expect(userManager.UpdateOrgUsers()).Should(Succeed())
+		_, email, _ := uaaFake.CreateInternalUserArgsForCall(0)
+		Expect(email).Should(Equal("new-user@users.example.com"))
+	})
+
+	It("does not create the user when the password can't be delivered", func() {
+		credhub.Close()
+		Expect(userManager.UpdateOrgUsers()).ShouldNot(Succeed())
+		Expect(uaaFake.CreateInternalUserCallCount()).Should(Equal(0))
+	})
+
+	It("only previews creating users when peeking", func() {
+		userManager.Peek = true
+		Expect(userManager.UpdateOrgUsers()).Should(Succeed())
+		Expect(credentials).Should(BeEmpty())
+	})
+
+	It("errors for missing users when not enabled", func() {
+		fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{}, nil)
+		Expect(userManager.UpdateOrgUsers()).Should(MatchError(ContainSubstring("must add internal user first")))
+	})
+})
@@ -5,23 +5,33 @@ import (
 	"strings"
 
 	uaaclient "github.com/cloudfoundry-community/go-uaa"
+	"github.com/pivotalservices/cf-mgmt/config"
 	"github.com/pivotalservices/cf-mgmt/ldap"
+	"github.com/pivotalservices/cf-mgmt/redact"
 	"github.com/xchapter7x/lo"
 )
 
 func (m *DefaultManager) SyncLdapUsers(roleUsers map[string]string, uaaUsers map[string]*uaaclient.User, updateUsersInput UpdateUsersInput) error {
 	if m.LdapConfig.Enabled {
-		ldapUsers, err := m.GetLDAPUsers(uaaUsers, updateUsersInput)
+		ldapUsers, sources, err := m.getLDAPUsers(uaaUsers, updateUsersInput)
 		if err != nil {
 			return err
 		}
 		lo.G.Debugf("LdapUsers: %+v", ldapUsers)
-		for _, inputUser := range ldapUsers {
+		userIDs := make([]string, len(ldapUsers))
+		for i, inputUser := range ldapUsers {
+			userIDs[i] = m.UpdateUserInfo(inputUser).UserID
+		}
+		if err := m.lookupUAAUsers(uaaUsers, userIDs); err != nil {
+			return err
+		}
+		for i, inputUser := range ldapUsers {
 			userToUse := m.UpdateUserInfo(inputUser)
 			userID := userToUse.UserID
-			if _, ok := roleUsers[userID]; !ok {
+			lowerUserID := strings.ToLower(userID)
+			if _, ok := roleUsers[lowerUserID]; !ok {
 				lo.G.Debugf("User[%s] not found in: %v", userID, roleUsers)
-				if _, userExists := uaaUsers[userID]; !userExists {
+				if _, userExists := uaaUsers[lowerUserID]; !userExists {
 					lo.G.Debug("User", userID, "doesn't exist in cloud foundry, so creating user")
 					if err := m.UAAMgr.CreateExternalUser(userID, userToUse.Email, userToUse.UserDN, m.LdapConfig.Origin); err != nil {
 						lo.G.Errorf("Unable to create user %s with error %s", userID, err.Error())
@@ -33,73 +43,147 @@ func (m *DefaultManager) SyncLdapUsers(roleUsers map[string]string, uaaUsers map
 							Origin:     m.LdapConfig.Origin,
 							Emails:     []uaaclient.Email{uaaclient.Email{Value: userToUse.Email}},
 						}
-						uaaUsers[userID] = uaaUser
+						uaaUsers[lowerUserID] = uaaUser
 						uaaUsers[userToUse.UserDN] = uaaUser
 					}
 				}
-				if err := updateUsersInput.AddUser(updateUsersInput, userID); err != nil {
+				if err := updateUsersInput.AddUser(updateUsersInput.from(sources[i].source, sources[i].name), userID); err != nil {
 					return err
 				}
 			} else {
-				delete(roleUsers, userID)
+				delete(roleUsers, lowerUserID)
 			}
 		}
+	} else if m.ldapSkipped {
+		return m.keepLdapUsers(roleUsers, uaaUsers)
 	} else {
 		lo.G.Debug("Skipping LDAP sync as LDAP is disabled (enable by updating config/ldap.yml)")
 	}
 	return nil
 }
 
+// keepLdapUsers leaves the users of the ldap origin in their roles when ldap
+// is not bound, as whether ldap still grants them the role is not known
+func (m *DefaultManager) keepLdapUsers(roleUsers map[string]string, uaaUsers map[string]*uaaclient.User) error {
+	userNames := sortedUserNames(roleUsers)
+	if err := m.lookupUAAUsers(uaaUsers, userNames); err != nil {
+		return err
+	}
+	for _, userName := range userNames {
+		if uaaUser, ok := uaaUsers[userName]; ok && strings.EqualFold(uaaUser.Origin, m.LdapConfig.Origin) {
+			delete(roleUsers, userName)
+		}
+	}
+	return nil
+}
+
 func (m *DefaultManager) GetLDAPUsers(uaaUsers map[string]*uaaclient.User, updateUsersInput UpdateUsersInput) ([]ldap.User, error) {
+	ldapUsers, _, err := m.getLDAPUsers(uaaUsers, updateUsersInput)
+	return ldapUsers, err
+}
+
+// ldapSource is where an ldap user comes from, its ldap group or ldap_users,
+// and the name of the group or user the role is granted to
+type ldapSource struct {
+	source string
+	name   string
+}
+
+// getLDAPUsers returns the ldap users of the input along with where each of
+// them comes from
+func (m *DefaultManager) getLDAPUsers(uaaUsers map[string]*uaaclient.User, updateUsersInput UpdateUsersInput) ([]ldap.User, []ldapSource, error) {
 	var ldapUsers []ldap.User
+	var sources []ldapSource
 	for _, groupName := range updateUsersInput.LdapGroupNames {
+		mapping := m.LdapConfig.UserNameMappingForGroup(groupName)
 		userDNList, err := m.LdapMgr.GetUserDNs(groupName)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+		if err := m.lookupUAAUsers(uaaUsers, userDNList); err != nil {
+			return nil, nil, err
 		}
 		for _, userDN := range userDNList {
 			if uaaUser, ok := uaaUsers[strings.ToLower(userDN)]; ok {
 				lo.G.Debugf("UserDN [%s] found in UAA, skipping ldap lookup", userDN)
-				ldapUsers = append(ldapUsers, ldap.User{
-					UserID: uaaUser.Username,
-					UserDN: userDN,
-					Email:  Email(uaaUser),
-				})
+				ldapUsers = append(ldapUsers, existingLdapUser(uaaUser.Username, userDN, uaaUser, mapping))
+				sources = append(sources, ldapSource{source: "ldap group " + groupName, name: groupName})
 			} else {
 				user, err := m.LdapMgr.GetUserByDN(userDN)
 				if err != nil {
-					return nil, err
+					return nil, nil, err
 				}
 				if user != nil {
+					if err := mapUserName(user, mapping); err != nil {
+						return nil, nil, err
+					}
 					ldapUsers = append(ldapUsers, *user)
+					sources = append(sources, ldapSource{source: "ldap group " + groupName, name: groupName})
 				}
 			}
 		}
 	}
+	mapping := m.LdapConfig.UserNameMapping
+	if err := m.lookupUAAUsers(uaaUsers, updateUsersInput.LdapUsers); err != nil {
+		return nil, nil, err
+	}
 	for _, userID := range updateUsersInput.LdapUsers {
 		if uaaUser, ok := uaaUsers[strings.ToLower(userID)]; ok {
 			lo.G.Debugf("UserID [%s] found in UAA, skipping ldap lookup", userID)
-			ldapUsers = append(ldapUsers, ldap.User{
-				UserID: userID,
-				UserDN: uaaUser.ExternalID,
-				Email:  Email(uaaUser),
-			})
+			ldapUsers = append(ldapUsers, existingLdapUser(userID, uaaUser.ExternalID, uaaUser, mapping))
+			sources = append(sources, ldapSource{source: "ldap_users", name: userID})
 		} else {
 			user, err := m.LdapMgr.GetUserByID(userID)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			if user != nil {
+				if err := mapUserName(user, mapping); err != nil {
+					return nil, nil, err
+				}
 				ldapUsers = append(ldapUsers, *user)
+				sources = append(sources, ldapSource{source: "ldap_users", name: userID})
 			}
 		}
 	}
-	return ldapUsers, nil
+	return ldapUsers, sources, nil
+}
+
+// existingLdapUser is used for users already in uaa, which were created with
+// the mapped username when a user name mapping applies.
+func existingLdapUser(userID, userDN string, uaaUser *uaaclient.User, mapping *config.UserNameMapping) ldap.User {
+	user := ldap.User{
+		UserID: userID,
+		UserDN: userDN,
+		Email:  Email(uaaUser),
+	}
+	if mapping != nil {
+		user.UserName = uaaUser.Username
+	}
+	return user
+}
+
+// mapUserName sets the username of the user from the attribute selected by the mapping.
+func mapUserName(user *ldap.User, mapping *config.UserNameMapping) error {
+	if mapping == nil {
+		return nil
+	}
+	value, ok := user.Attributes[strings.ToLower(mapping.Attribute)]
+	if !ok || value == "" {
+		return fmt.Errorf("ldap user [%s] has no value for user name attribute [%s]", user.UserDN, mapping.Attribute)
+	}
+	userName, err := mapping.UserName(value)
+	if err != nil {
+		return err
+	}
+	user.UserName = userName
+	redact.UserNames(userName)
+	return nil
 }
 
 func Email(u *uaaclient.User) string {
 	for _, email := range u.Emails {
-		if *email.Primary {
+		if email.Primary != nil && *email.Primary {
 			return email.Value
 		}
 	}
@@ -121,6 +205,9 @@ func (m *DefaultManager) UpdateUserInfo(user ldap.User) ldap.User {
 			email = fmt.Sprintf("%s@user.from.ldap.cf", userID)
 		}
 	}
+	if user.UserName != "" {
+		userID = user.UserName
+	}
 
 	return ldap.User{
 		UserID: userID,
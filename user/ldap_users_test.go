@@ -2,6 +2,7 @@ package user_test
 
 import (
 	"errors"
+	"strings"
 
 	cfclient "github.com/cloudfoundry-community/go-cfclient"
 	uaaclient "github.com/cloudfoundry-community/go-uaa"
@@ -55,6 +56,30 @@ var _ = Describe("given UserSpaces", func() {
 					Enabled: true,
 				}
 			})
+			It("Should leave the ldap users of the role as they are when ldap is skipped", func() {
+				fakeReader.LdapConfigReturns(&config.LdapConfig{Origin: "ldap", Enabled: true}, nil)
+				userManager.SkipLdap = true
+				Expect(userManager.InitializeLdap("")).Should(Succeed())
+				Expect(userManager.LdapConfig.Enabled).Should(BeFalse())
+
+				roleUsers := map[string]string{"test_ldap": "ldap-guid", "test_uaa": "uaa-guid"}
+				uaaUsers := map[string]*uaaclient.User{
+					"test_ldap": {Username: "test_ldap", Origin: "ldap"},
+					"test_uaa":  {Username: "test_uaa", Origin: "uaa"},
+				}
+				updateUsersInput := UpdateUsersInput{
+					LdapUsers: []string{"test_ldap", "new_ldap"},
+					SpaceGUID: "space_guid",
+					OrgGUID:   "org_guid",
+					AddUser:   userManager.AssociateSpaceAuditor,
+				}
+				err := userManager.SyncLdapUsers(roleUsers, uaaUsers, updateUsersInput)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(roleUsers).Should(Equal(map[string]string{"test_uaa": "uaa-guid"}))
+				Expect(ldapFake.GetUserByIDCallCount()).Should(Equal(0))
+				Expect(client.AssociateSpaceAuditorByUsernameCallCount()).Should(Equal(0))
+			})
+
 			It("Should add ldap user to role", func() {
 				roleUsers := make(map[string]string)
 				uaaUsers := make(map[string]*uaaclient.User)
@@ -88,6 +113,46 @@ var _ = Describe("given UserSpaces", func() {
 				Expect(userName).Should(Equal("test_ldap"))
 			})
 
+			It("Should pass where each user comes from to the role", func() {
+				uaaUsers := make(map[string]*uaaclient.User)
+				uaaUsers["test_ldap"] = &uaaclient.User{Username: "test_ldap"}
+				uaaUsers["inherited_member"] = &uaaclient.User{Username: "inherited_member"}
+				uaaUsers["group_member"] = &uaaclient.User{Username: "group_member"}
+				var sources []string
+				updateUsersInput := UpdateUsersInput{
+					LdapUsers:      []string{"test_ldap"},
+					LdapGroupNames: []string{"test_group", "inherited_group"},
+					ConfigFile:     "org/space/spaceConfig.yml",
+					InheritedFrom: func(name string) string {
+						if name == "inherited_group" {
+							return "org-groups.yml"
+						}
+						return ""
+					},
+					AddUser: func(input UpdateUsersInput, userName string) error {
+						sources = append(sources, userName+" from "+input.Source+" in "+input.ConfigFile)
+						return nil
+					},
+				}
+				ldapFake.GetUserDNsStub = func(groupName string) ([]string, error) {
+					if groupName == "inherited_group" {
+						return []string{"cn=inherited_member"}, nil
+					}
+					return []string{"cn=group_member"}, nil
+				}
+				ldapFake.GetUserByDNStub = func(userDN string) (*ldap.User, error) {
+					return &ldap.User{UserDN: userDN, UserID: strings.TrimPrefix(userDN, "cn=")}, nil
+				}
+				ldapFake.GetUserByIDReturns(&ldap.User{UserDN: "cn=test_ldap", UserID: "test_ldap"}, nil)
+
+				Expect(userManager.SyncLdapUsers(make(map[string]string), uaaUsers, updateUsersInput)).Should(Succeed())
+				Expect(sources).Should(Equal([]string{
+					"group_member from ldap group test_group in org/space/spaceConfig.yml",
+					"inherited_member from ldap group inherited_group in org-groups.yml",
+					"test_ldap from ldap_users in org/space/spaceConfig.yml",
+				}))
+			})
+
 			It("Should add ldap group member to role", func() {
 				roleUsers := make(map[string]string)
 				uaaUsers := make(map[string]*uaaclient.User)
@@ -173,6 +238,49 @@ var _ = Describe("given UserSpaces", func() {
 				Expect(origin).Should(Equal("ldap"))
 			})
 
+			It("Should create external user with the group user name mapping", func() {
+				userManager.LdapConfig.UserNameMapping = &config.UserNameMapping{Attribute: "mail"}
+				userManager.LdapConfig.GroupUserNameMappings = map[string]config.UserNameMapping{
+					"test_group": config.UserNameMapping{Attribute: "userPrincipalName", Transforms: []string{"strip-domain", "lowercase"}},
+				}
+				roleUsers := make(map[string]string)
+				uaaUsers := make(map[string]*uaaclient.User)
+				updateUsersInput := UpdateUsersInput{
+					LdapGroupNames: []string{"test_group"},
+					SpaceGUID:      "space_guid",
+					OrgGUID:        "org_guid",
+					AddUser:        userManager.AssociateSpaceAuditor,
+				}
+				ldapFake.GetUserDNsReturns([]string{"cn=ldap_test_dn"}, nil)
+				ldapFake.GetUserByDNReturns(
+					&ldap.User{
+						UserDN:     "ldap_test_dn",
+						UserID:     "test_ldap",
+						Email:      "test@test.com",
+						Attributes: map[string]string{"userprincipalname": "Test.User@Corp.Example.com"},
+					},
+					nil)
+				err := userManager.SyncLdapUsers(roleUsers, uaaUsers, updateUsersInput)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(uaaUsers).Should(HaveKey("test.user"))
+				arg1, _, arg3, _ := uaaFake.CreateExternalUserArgsForCall(0)
+				Expect(arg1).Should(Equal("test.user"))
+				Expect(arg3).Should(Equal("ldap_test_dn"))
+			})
+
+			It("Should return error when the mapped attribute is missing", func() {
+				userManager.LdapConfig.UserNameMapping = &config.UserNameMapping{Attribute: "sAMAccountName"}
+				updateUsersInput := UpdateUsersInput{
+					LdapUsers: []string{"test_ldap"},
+					SpaceGUID: "space_guid",
+					OrgGUID:   "org_guid",
+					AddUser:   userManager.AssociateSpaceAuditor,
+				}
+				ldapFake.GetUserByIDReturns(&ldap.User{UserDN: "ldap_test_dn", UserID: "test_ldap"}, nil)
+				err := userManager.SyncLdapUsers(make(map[string]string), make(map[string]*uaaclient.User), updateUsersInput)
+				Expect(err).Should(MatchError("ldap user [ldap_test_dn] has no value for user name attribute [sAMAccountName]"))
+			})
+
 			It("Should not error when create external user errors", func() {
 				roleUsers := make(map[string]string)
 				uaaUsers := make(map[string]*uaaclient.User)
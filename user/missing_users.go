@@ -0,0 +1,70 @@
+package user
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+)
+
+//MissingUser - an internal users entry of the configuration that does not exist in uaa
+type MissingUser struct {
+	UserName string
+	Org      string
+	Space    string
+	Role     string
+}
+
+//ListMissingUsers - lists the internal users of org and space roles that do not exist in uaa, which
+//update-org-users and update-space-users would fail on until the users are created
+func (m *DefaultManager) ListMissingUsers() ([]MissingUser, error) {
+	uaaUsers, err := m.listUAAUsers()
+	if err != nil {
+		return nil, err
+	}
+	orgConfigs, err := m.Cfg.GetOrgConfigs()
+	if err != nil {
+		return nil, err
+	}
+	spaceConfigs, err := m.Cfg.GetSpaceConfigs()
+	if err != nil {
+		return nil, err
+	}
+	var userNames []string
+	for _, orgConfig := range orgConfigs {
+		userNames = append(userNames, orgConfig.Manager.Users...)
+		userNames = append(userNames, orgConfig.BillingManager.Users...)
+		userNames = append(userNames, orgConfig.Auditor.Users...)
+	}
+	for _, spaceConfig := range spaceConfigs {
+		userNames = append(userNames, spaceConfig.Developer.Users...)
+		userNames = append(userNames, spaceConfig.Manager.Users...)
+		userNames = append(userNames, spaceConfig.Auditor.Users...)
+	}
+	if err := m.lookupUAAUsers(uaaUsers, userNames); err != nil {
+		return nil, err
+	}
+
+	var missing []MissingUser
+	addMissing := func(org, space, role string, userNames []string) {
+		for _, userName := range userNames {
+			if _, ok := uaaUsers[strings.ToLower(userName)]; !ok {
+				missing = append(missing, MissingUser{UserName: userName, Org: org, Space: space, Role: role})
+			}
+		}
+	}
+	for _, orgConfig := range orgConfigs {
+		addMissing(orgConfig.Org, "", config.RoleOrgManager, orgConfig.Manager.Users)
+		addMissing(orgConfig.Org, "", config.RoleOrgBillingManager, orgConfig.BillingManager.Users)
+		addMissing(orgConfig.Org, "", config.RoleOrgAuditor, orgConfig.Auditor.Users)
+	}
+	for _, spaceConfig := range spaceConfigs {
+		addMissing(spaceConfig.Org, spaceConfig.Space, config.RoleSpaceDeveloper, spaceConfig.Developer.Users)
+		addMissing(spaceConfig.Org, spaceConfig.Space, config.RoleSpaceManager, spaceConfig.Manager.Users)
+		addMissing(spaceConfig.Org, spaceConfig.Space, config.RoleSpaceAuditor, spaceConfig.Auditor.Users)
+	}
+	sort.SliceStable(missing, func(i, j int) bool {
+		return strings.ToLower(missing[i].UserName) < strings.ToLower(missing[j].UserName)
+	})
+	return missing, nil
+}
@@ -0,0 +1,73 @@
+package user_test
+
+import (
+	uaaclient "github.com/cloudfoundry-community/go-uaa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/config"
+	configfakes "github.com/pivotalservices/cf-mgmt/config/fakes"
+	"github.com/pivotalservices/cf-mgmt/uaa"
+	uaafakes "github.com/pivotalservices/cf-mgmt/uaa/fakes"
+	. "github.com/pivotalservices/cf-mgmt/user"
+)
+
+var _ = Describe("given ListMissingUsers", func() {
+	var (
+		userManager *DefaultManager
+		uaaFake     *uaafakes.FakeManager
+		fakeReader  *configfakes.FakeReader
+	)
+	BeforeEach(func() {
+		uaaFake = new(uaafakes.FakeManager)
+		fakeReader = new(configfakes.FakeReader)
+		userManager = &DefaultManager{
+			Cfg:    fakeReader,
+			UAAMgr: uaaFake,
+		}
+		uaaFake.ListUsersReturns(map[string]*uaaclient.User{
+			"admin": {ID: "admin-guid", Username: "admin", Origin: "uaa"},
+		}, nil)
+	})
+
+	It("lists the internal users that don't exist in uaa", func() {
+		fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+			{Org: "org1", Manager: config.UserMgmt{Users: []string{"Admin", "zed"}}},
+		}, nil)
+		fakeReader.GetSpaceConfigsReturns([]config.SpaceConfig{
+			{Org: "org1", Space: "dev", Developer: config.UserMgmt{Users: []string{"bob"}, LDAPUsers: []string{"ldap-user"}}},
+		}, nil)
+		missing, err := userManager.ListMissingUsers()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(missing).Should(Equal([]MissingUser{
+			{UserName: "bob", Org: "org1", Space: "dev", Role: config.RoleSpaceDeveloper},
+			{UserName: "zed", Org: "org1", Role: config.RoleOrgManager},
+		}))
+	})
+
+	It("only looks up the configured users in targeted mode", func() {
+		userManager.UAALookupMode = uaa.LookupTargeted
+		fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+			{Org: "org1", Manager: config.UserMgmt{Users: []string{"Admin", "zed"}}},
+		}, nil)
+		fakeReader.GetSpaceConfigsReturns([]config.SpaceConfig{
+			{Org: "org1", Space: "dev", Developer: config.UserMgmt{Users: []string{"admin"}}},
+		}, nil)
+		uaaFake.ListUsersByNameReturns(map[uaa.UserKey]*uaaclient.User{
+			{Origin: "uaa", Name: "admin"}: {ID: "admin-guid", Username: "admin", Origin: "uaa"},
+		}, nil)
+		missing, err := userManager.ListMissingUsers()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(missing).Should(Equal([]MissingUser{
+			{UserName: "zed", Org: "org1", Role: config.RoleOrgManager},
+		}))
+		Expect(uaaFake.ListUsersCallCount()).Should(Equal(0))
+		Expect(uaaFake.ListUsersByNameCallCount()).Should(Equal(1))
+		Expect(uaaFake.ListUsersByNameArgsForCall(0)).Should(Equal([]string{"Admin", "zed"}))
+	})
+
+	It("returns none when every user exists", func() {
+		missing, err := userManager.ListMissingUsers()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(missing).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,145 @@
+package user
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	uaaclient "github.com/cloudfoundry-community/go-uaa"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/xchapter7x/lo"
+)
+
+// originCutover keeps the users of the new origin of an identity provider
+// migration in the same roles as their user in the old origin, so users can
+// log in with either identity provider until the old one is retired.
+type originCutover struct {
+	migration *config.OriginMigration
+	// counterparts are the users in the new origin keyed by the guid of their user in the old origin
+	counterparts map[string]*uaaclient.User
+}
+
+func newOriginCutover(migration *config.OriginMigration, uaaUsers map[string]*uaaclient.User) *originCutover {
+	toUsers := make(map[string]*uaaclient.User)
+	for _, uaaUser := range uaaUsers {
+		if uaaUser.Origin == migration.ToOrigin {
+			toUsers[strings.ToLower(uaaUser.Username)] = uaaUser
+		}
+	}
+	counterparts := make(map[string]*uaaclient.User)
+	for _, uaaUser := range uaaUsers {
+		if uaaUser.Origin != migration.FromOrigin {
+			continue
+		}
+		userName := migration.NewUserName(uaaUser.Username, Email(uaaUser))
+		if counterpart, ok := toUsers[strings.ToLower(userName)]; ok {
+			counterparts[uaaUser.ID] = counterpart
+		}
+	}
+	return &originCutover{migration: migration, counterparts: counterparts}
+}
+
+// initializeCutover enables cutover for the users being synced when
+// origin-migration.yml has cutover set.
+func (m *DefaultManager) initializeCutover(uaaUsers map[string]*uaaclient.User) error {
+	m.cutover = nil
+	migration, err := m.Cfg.GetOriginMigration()
+	if err != nil {
+		return err
+	}
+	if migration == nil || !migration.Cutover {
+		return nil
+	}
+	m.cutover = newOriginCutover(migration, uaaUsers)
+	lo.G.Debugf("Keeping %d users of origin %s in the roles of their user in origin %s", len(m.cutover.counterparts), migration.ToOrigin, migration.FromOrigin)
+	return nil
+}
+
+// syncCounterparts gives the role to the user in the new origin of every user
+// in the old origin that keeps or gets the role, and stops those users from
+// being removed. roleUsers are the users that would otherwise be removed.
+func (c *originCutover) syncCounterparts(inRole, roleUsers map[string]string, added []string, uaaUsers map[string]*uaaclient.User, updateUsersInput UpdateUsersInput) error {
+	roleGUIDs := make(map[string]bool)
+	for _, guid := range inRole {
+		roleGUIDs[guid] = true
+	}
+	var userNames []string
+	for userName := range inRole {
+		if _, removing := roleUsers[userName]; !removing {
+			userNames = append(userNames, userName)
+		}
+	}
+	userNames = append(userNames, added...)
+	sort.Strings(userNames)
+
+	for _, userName := range userNames {
+		uaaUser, ok := uaaUsers[strings.ToLower(userName)]
+		if !ok || uaaUser.Origin != c.migration.FromOrigin {
+			continue
+		}
+		counterpart, ok := c.counterparts[uaaUser.ID]
+		if !ok {
+			continue
+		}
+		if roleGUIDs[counterpart.ID] {
+			for roleUser, guid := range roleUsers {
+				if guid == counterpart.ID {
+					delete(roleUsers, roleUser)
+				}
+			}
+			continue
+		}
+		lo.G.Debugf("Giving user [%s] in origin %s the role of [%s] in origin %s", counterpart.Username, c.migration.ToOrigin, uaaUser.Username, c.migration.FromOrigin)
+		if err := updateUsersInput.AddClient(updateUsersInput, counterpart.ID); err != nil {
+			return err
+		}
+		roleGUIDs[counterpart.ID] = true
+	}
+	return nil
+}
+
+//CleanupOriginUsers - deletes the users of the old origin of origin-migration.yml that have a user
+//in the new origin, ending a cutover once the old identity provider is retired
+func (m *DefaultManager) CleanupOriginUsers() error {
+	migration, err := m.Cfg.GetOriginMigration()
+	if err != nil {
+		return err
+	}
+	if migration == nil {
+		return fmt.Errorf("origin-migration.yml not found in config directory")
+	}
+	uaaUsers, err := m.UAAMgr.ListUsers()
+	if err != nil {
+		return err
+	}
+	cutover := newOriginCutover(migration, uaaUsers)
+
+	var fromUsers []*uaaclient.User
+	seen := make(map[string]bool)
+	for _, uaaUser := range uaaUsers {
+		if uaaUser.Origin == migration.FromOrigin && !seen[uaaUser.ID] {
+			seen[uaaUser.ID] = true
+			fromUsers = append(fromUsers, uaaUser)
+		}
+	}
+	sort.Slice(fromUsers, func(i, j int) bool { return fromUsers[i].Username < fromUsers[j].Username })
+
+	for _, uaaUser := range fromUsers {
+		counterpart, ok := cutover.counterparts[uaaUser.ID]
+		if !ok {
+			lo.G.Warningf("user [%s] in origin %s has no user in origin %s and is kept", uaaUser.Username, migration.FromOrigin, migration.ToOrigin)
+			continue
+		}
+		lo.G.Debugf("user [%s] in origin %s replaces [%s] in origin %s", counterpart.Username, migration.ToOrigin, uaaUser.Username, migration.FromOrigin)
+		if !m.Peek {
+			// users that never logged in or got a role have no cloud controller user
+			if err := m.Client.DeleteUser(uaaUser.ID); err != nil {
+				lo.G.Warningf("unable to delete cloud controller user [%s]: %s", uaaUser.Username, err.Error())
+			}
+		}
+		if err := m.UAAMgr.DeleteUser(*uaaUser); err != nil {
+			return err
+		}
+	}
+	return nil
+}
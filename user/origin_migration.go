@@ -0,0 +1,175 @@
+package user
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	uaaclient "github.com/cloudfoundry-community/go-uaa"
+	"github.com/pkg/errors"
+	"github.com/xchapter7x/lo"
+)
+
+// roleBinding is a role of a user in an org or space that can be given to
+// another user by guid.
+type roleBinding struct {
+	description string
+	associate   func(userGUID string) error
+}
+
+//MigrateUserOrigin - moves the uaa users of one origin to another as described in origin-migration.yml.
+//Users are updated in place so their cf roles are kept, unless a user already exists in the new
+//origin, in which case the roles of the old user are given to the existing user.
+func (m *DefaultManager) MigrateUserOrigin() error {
+	migration, err := m.Cfg.GetOriginMigration()
+	if err != nil {
+		return err
+	}
+	if migration == nil {
+		return fmt.Errorf("origin-migration.yml not found in config directory")
+	}
+	uaaUsers, err := m.UAAMgr.ListUsers()
+	if err != nil {
+		return err
+	}
+
+	var toMigrate []*uaaclient.User
+	existing := make(map[string]*uaaclient.User)
+	seen := make(map[string]bool)
+	for _, uaaUser := range uaaUsers {
+		if seen[uaaUser.ID] {
+			continue
+		}
+		seen[uaaUser.ID] = true
+		switch uaaUser.Origin {
+		case migration.FromOrigin:
+			toMigrate = append(toMigrate, uaaUser)
+		case migration.ToOrigin:
+			existing[strings.ToLower(uaaUser.Username)] = uaaUser
+		}
+	}
+	sort.Slice(toMigrate, func(i, j int) bool { return toMigrate[i].Username < toMigrate[j].Username })
+	lo.G.Debugf("Found %d users in origin %s to move to %s", len(toMigrate), migration.FromOrigin, migration.ToOrigin)
+
+	var bindings map[string][]roleBinding
+	for _, uaaUser := range toMigrate {
+		userName := migration.NewUserName(uaaUser.Username, Email(uaaUser))
+		target, ok := existing[strings.ToLower(userName)]
+		if !ok {
+			externalID, err := m.externalID(userName, migration.ToOrigin)
+			if err != nil {
+				return err
+			}
+			if err := m.UAAMgr.UpdateUserOrigin(*uaaUser, userName, externalID, migration.ToOrigin); err != nil {
+				return err
+			}
+			continue
+		}
+		if bindings == nil {
+			if bindings, err = m.roleBindings(); err != nil {
+				return err
+			}
+		}
+		lo.G.Warningf("user [%s] already exists in origin %s, giving it the roles of [%s] which is left in origin %s", target.Username, migration.ToOrigin, uaaUser.Username, migration.FromOrigin)
+		for _, binding := range bindings[uaaUser.ID] {
+			if err := m.copyRole(binding, target); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// externalID is the id the identity provider of origin knows a user by, the
+// dn of the user for the ldap origin and the username, which cf-mgmt uses as
+// the saml name id, for other origins
+func (m *DefaultManager) externalID(userName, origin string) (string, error) {
+	if m.LdapConfig == nil || !m.LdapConfig.Enabled || origin != m.LdapConfig.Origin {
+		return userName, nil
+	}
+	ldapUser, err := m.LdapMgr.GetUserByID(userName)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to look up user [%s] in ldap", userName)
+	}
+	if ldapUser == nil {
+		return "", fmt.Errorf("user [%s] not found in ldap, unable to move it to origin %s", userName, origin)
+	}
+	return ldapUser.UserDN, nil
+}
+
+func (m *DefaultManager) copyRole(binding roleBinding, target *uaaclient.User) error {
+	if m.Peek {
+		lo.G.Infof("[dry-run]: adding %s to %s", target.Username, binding.description)
+		return nil
+	}
+	lo.G.Infof("adding %s to %s", target.Username, binding.description)
+	return errors.Wrapf(binding.associate(target.ID), "unable to add %s to %s", target.Username, binding.description)
+}
+
+// roleBindings lists the org and space roles of every user on the foundation keyed by user guid.
+func (m *DefaultManager) roleBindings() (map[string][]roleBinding, error) {
+	bindings := make(map[string][]roleBinding)
+	add := func(users []cfclient.User, description string, associate func(userGUID string) error) {
+		for _, user := range users {
+			bindings[user.Guid] = append(bindings[user.Guid], roleBinding{description: description, associate: associate})
+		}
+	}
+	orgs, err := m.OrgMgr.ListOrgs()
+	if err != nil {
+		return nil, err
+	}
+	for _, org := range orgs {
+		orgGUID := org.Guid
+		orgRoles := []struct {
+			role      string
+			list      func(string) ([]cfclient.User, error)
+			associate func(string, string) (cfclient.Org, error)
+		}{
+			{"user", m.Client.ListOrgUsers, m.Client.AssociateOrgUser},
+			{"auditor", m.Client.ListOrgAuditors, m.Client.AssociateOrgAuditor},
+			{"billing manager", m.Client.ListOrgBillingManagers, m.Client.AssociateOrgBillingManager},
+			{"manager", m.Client.ListOrgManagers, m.Client.AssociateOrgManager},
+		}
+		for _, orgRole := range orgRoles {
+			users, err := orgRole.list(orgGUID)
+			if err != nil {
+				return nil, err
+			}
+			associate := orgRole.associate
+			add(users, fmt.Sprintf("role %s for org %s", orgRole.role, org.Name), func(userGUID string) error {
+				_, err := associate(orgGUID, userGUID)
+				return err
+			})
+		}
+
+		spaces, err := m.SpaceMgr.ListSpaces(orgGUID)
+		if err != nil {
+			return nil, err
+		}
+		for _, space := range spaces {
+			spaceGUID := space.Guid
+			spaceRoles := []struct {
+				role      string
+				list      func(string) ([]cfclient.User, error)
+				associate func(string, string) (cfclient.Space, error)
+			}{
+				{"auditor", m.Client.ListSpaceAuditors, m.Client.AssociateSpaceAuditor},
+				{"developer", m.Client.ListSpaceDevelopers, m.Client.AssociateSpaceDeveloper},
+				{"manager", m.Client.ListSpaceManagers, m.Client.AssociateSpaceManager},
+			}
+			for _, spaceRole := range spaceRoles {
+				users, err := spaceRole.list(spaceGUID)
+				if err != nil {
+					return nil, err
+				}
+				associate := spaceRole.associate
+				add(users, fmt.Sprintf("role %s for org/space %s/%s", spaceRole.role, org.Name, space.Name), func(userGUID string) error {
+					_, err := associate(spaceGUID, userGUID)
+					return err
+				})
+			}
+		}
+	}
+	return bindings, nil
+}
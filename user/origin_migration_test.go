@@ -0,0 +1,198 @@
+package user_test
+
+import (
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	uaaclient "github.com/cloudfoundry-community/go-uaa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/config"
+	configfakes "github.com/pivotalservices/cf-mgmt/config/fakes"
+	"github.com/pivotalservices/cf-mgmt/ldap"
+	ldapfakes "github.com/pivotalservices/cf-mgmt/ldap/fakes"
+	orgfakes "github.com/pivotalservices/cf-mgmt/organization/fakes"
+	spacefakes "github.com/pivotalservices/cf-mgmt/space/fakes"
+	uaafakes "github.com/pivotalservices/cf-mgmt/uaa/fakes"
+	. "github.com/pivotalservices/cf-mgmt/user"
+	"github.com/pivotalservices/cf-mgmt/user/fakes"
+)
+
+var _ = Describe("given MigrateUserOrigin", func() {
+	var (
+		userManager *DefaultManager
+		client      *fakes.FakeCFClient
+		uaaFake     *uaafakes.FakeManager
+		fakeReader  *configfakes.FakeReader
+		spaceFake   *spacefakes.FakeManager
+		orgFake     *orgfakes.FakeManager
+		uaaUsers    map[string]*uaaclient.User
+	)
+	BeforeEach(func() {
+		client = new(fakes.FakeCFClient)
+		uaaFake = new(uaafakes.FakeManager)
+		fakeReader = new(configfakes.FakeReader)
+		spaceFake = new(spacefakes.FakeManager)
+		orgFake = new(orgfakes.FakeManager)
+		userManager = &DefaultManager{
+			Client:   client,
+			Cfg:      fakeReader,
+			UAAMgr:   uaaFake,
+			SpaceMgr: spaceFake,
+			OrgMgr:   orgFake,
+		}
+		fakeReader.GetOriginMigrationReturns(&config.OriginMigration{
+			FromOrigin: "ldap",
+			ToOrigin:   "saml",
+			UserName:   config.MigrateEmailUserName,
+		}, nil)
+		ldapUser := &uaaclient.User{ID: "ldap-guid", Username: "jdoe", Origin: "ldap", Emails: []uaaclient.Email{{Value: "jdoe@example.com"}}}
+		uaaUsers = map[string]*uaaclient.User{
+			"jdoe":        ldapUser,
+			"cn=jdoe,o=x": ldapUser,
+			"admin":       {ID: "admin-guid", Username: "admin", Origin: "uaa"},
+		}
+		uaaFake.ListUsersReturns(uaaUsers, nil)
+	})
+
+	It("moves users to the new origin", func() {
+		err := userManager.MigrateUserOrigin()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(uaaFake.UpdateUserOriginCallCount()).Should(Equal(1))
+		user, userName, externalID, origin := uaaFake.UpdateUserOriginArgsForCall(0)
+		Expect(user.ID).Should(Equal("ldap-guid"))
+		Expect(userName).Should(Equal("jdoe@example.com"))
+		Expect(externalID).Should(Equal("jdoe@example.com"))
+		Expect(origin).Should(Equal("saml"))
+		Expect(orgFake.ListOrgsCallCount()).Should(Equal(0))
+	})
+
+	It("sets the external id to the dn of the user when moving users to ldap", func() {
+		fakeReader.GetOriginMigrationReturns(&config.OriginMigration{FromOrigin: "saml", ToOrigin: "ldap", UserName: config.MigrateKeepUserName}, nil)
+		uaaFake.ListUsersReturns(map[string]*uaaclient.User{
+			"jdoe": {ID: "saml-guid", Username: "jdoe", Origin: "saml"},
+		}, nil)
+		ldapFake := new(ldapfakes.FakeManager)
+		ldapFake.GetUserByIDReturns(&ldap.User{UserDN: "cn=jdoe,ou=users,dc=example", UserID: "jdoe"}, nil)
+		userManager.LdapMgr = ldapFake
+		userManager.LdapConfig = &config.LdapConfig{Enabled: true, Origin: "ldap"}
+
+		err := userManager.MigrateUserOrigin()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ldapFake.GetUserByIDArgsForCall(0)).Should(Equal("jdoe"))
+		_, userName, externalID, origin := uaaFake.UpdateUserOriginArgsForCall(0)
+		Expect(userName).Should(Equal("jdoe"))
+		Expect(externalID).Should(Equal("cn=jdoe,ou=users,dc=example"))
+		Expect(origin).Should(Equal("ldap"))
+	})
+
+	It("fails to move a user to ldap that is not in ldap", func() {
+		fakeReader.GetOriginMigrationReturns(&config.OriginMigration{FromOrigin: "saml", ToOrigin: "ldap", UserName: config.MigrateKeepUserName}, nil)
+		uaaFake.ListUsersReturns(map[string]*uaaclient.User{
+			"jdoe": {ID: "saml-guid", Username: "jdoe", Origin: "saml"},
+		}, nil)
+		userManager.LdapMgr = new(ldapfakes.FakeManager)
+		userManager.LdapConfig = &config.LdapConfig{Enabled: true, Origin: "ldap"}
+
+		err := userManager.MigrateUserOrigin()
+		Expect(err).Should(HaveOccurred())
+		Expect(uaaFake.UpdateUserOriginCallCount()).Should(Equal(0))
+	})
+
+	It("gives the roles of the old user to a user that exists in the new origin", func() {
+		uaaUsers["jdoe@example.com"] = &uaaclient.User{ID: "saml-guid", Username: "jdoe@example.com", Origin: "saml"}
+		orgFake.ListOrgsReturns([]cfclient.Org{{Guid: "org-guid", Name: "org"}}, nil)
+		client.ListOrgUsersReturns([]cfclient.User{{Guid: "ldap-guid"}}, nil)
+		spaceFake.ListSpacesReturns([]cfclient.Space{{Guid: "space-guid", Name: "space"}}, nil)
+		client.ListSpaceDevelopersReturns([]cfclient.User{{Guid: "ldap-guid"}, {Guid: "admin-guid"}}, nil)
+
+		err := userManager.MigrateUserOrigin()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(uaaFake.UpdateUserOriginCallCount()).Should(Equal(0))
+		Expect(client.AssociateOrgUserCallCount()).Should(Equal(1))
+		orgGUID, userGUID := client.AssociateOrgUserArgsForCall(0)
+		Expect(orgGUID).Should(Equal("org-guid"))
+		Expect(userGUID).Should(Equal("saml-guid"))
+		Expect(client.AssociateSpaceDeveloperCallCount()).Should(Equal(1))
+		spaceGUID, userGUID := client.AssociateSpaceDeveloperArgsForCall(0)
+		Expect(spaceGUID).Should(Equal("space-guid"))
+		Expect(userGUID).Should(Equal("saml-guid"))
+		Expect(client.AssociateOrgManagerCallCount()).Should(Equal(0))
+	})
+
+	It("does not copy roles when peeking", func() {
+		userManager.Peek = true
+		uaaUsers["jdoe@example.com"] = &uaaclient.User{ID: "saml-guid", Username: "jdoe@example.com", Origin: "saml"}
+		orgFake.ListOrgsReturns([]cfclient.Org{{Guid: "org-guid", Name: "org"}}, nil)
+		client.ListOrgUsersReturns([]cfclient.User{{Guid: "ldap-guid"}}, nil)
+
+		err := userManager.MigrateUserOrigin()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(client.AssociateOrgUserCallCount()).Should(Equal(0))
+	})
+
+	Context("cutover", func() {
+		var samlUser *uaaclient.User
+		BeforeEach(func() {
+			userManager.LdapConfig = &config.LdapConfig{Origin: "saml"}
+			samlUser = &uaaclient.User{ID: "saml-guid", Username: "jdoe@example.com", Origin: "saml"}
+			uaaUsers["jdoe@example.com"] = samlUser
+			fakeReader.GetOriginMigrationReturns(&config.OriginMigration{
+				FromOrigin: "ldap",
+				ToOrigin:   "saml",
+				UserName:   config.MigrateEmailUserName,
+				Cutover:    true,
+			}, nil)
+			fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+				{
+					Org:         "test-org",
+					Manager:     config.UserMgmt{Users: []string{"jdoe"}},
+					RemoveUsers: true,
+				},
+			}, nil)
+			orgFake.FindOrgReturns(cfclient.Org{Name: "test-org", Guid: "test-org-guid"}, nil)
+		})
+
+		It("gives the user in the new origin the role of the user in the old origin", func() {
+			err := userManager.UpdateOrgUsers()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(client.AssociateOrgManagerByUsernameCallCount()).Should(Equal(1))
+			_, userName := client.AssociateOrgManagerByUsernameArgsForCall(0)
+			Expect(userName).Should(Equal("jdoe"))
+			Expect(client.AssociateOrgManagerCallCount()).Should(Equal(1))
+			orgGUID, userGUID := client.AssociateOrgManagerArgsForCall(0)
+			Expect(orgGUID).Should(Equal("test-org-guid"))
+			Expect(userGUID).Should(Equal("saml-guid"))
+		})
+
+		It("keeps the user in the new origin in the role", func() {
+			client.ListOrgManagersReturns([]cfclient.User{
+				{Username: "jdoe", Guid: "ldap-guid"},
+				{Username: "jdoe@example.com", Guid: "saml-guid"},
+			}, nil)
+			err := userManager.UpdateOrgUsers()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(client.AssociateOrgManagerCallCount()).Should(Equal(0))
+			Expect(client.RemoveOrgManagerByUsernameCallCount()).Should(Equal(0))
+		})
+
+		It("removes the user in the new origin with the user in the old origin", func() {
+			fakeReader.GetOrgConfigsReturns([]config.OrgConfig{{Org: "test-org", RemoveUsers: true}}, nil)
+			client.ListOrgManagersReturns([]cfclient.User{
+				{Username: "jdoe", Guid: "ldap-guid"},
+				{Username: "jdoe@example.com", Guid: "saml-guid"},
+			}, nil)
+			err := userManager.UpdateOrgUsers()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(client.RemoveOrgManagerByUsernameCallCount()).Should(Equal(2))
+		})
+
+		It("deletes the users of the old origin on cleanup", func() {
+			uaaUsers["other"] = &uaaclient.User{ID: "other-guid", Username: "other", Origin: "ldap"}
+			err := userManager.CleanupOriginUsers()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(client.DeleteUserCallCount()).Should(Equal(1))
+			Expect(client.DeleteUserArgsForCall(0)).Should(Equal("ldap-guid"))
+			Expect(uaaFake.DeleteUserCallCount()).Should(Equal(1))
+			Expect(uaaFake.DeleteUserArgsForCall(0).ID).Should(Equal("ldap-guid"))
+		})
+	})
+})
@@ -0,0 +1,125 @@
+package user
+
+import (
+	"fmt"
+	"strings"
+
+	uaaclient "github.com/cloudfoundry-community/go-uaa"
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/ldap"
+	"github.com/pkg/errors"
+	"github.com/xchapter7x/lo"
+)
+
+//CreatePersonalSpaces - creates a space named after each member of the personal-spaces ldap group of an org,
+//with the member as its space developer
+func (m *DefaultManager) CreatePersonalSpaces() error {
+	orgConfigs, err := m.Cfg.GetOrgConfigs()
+	if err != nil {
+		return err
+	}
+	var personalOrgs []config.OrgConfig
+	for _, orgConfig := range orgConfigs {
+		if orgConfig.PersonalSpaces != nil {
+			personalOrgs = append(personalOrgs, orgConfig)
+		}
+	}
+	if len(personalOrgs) == 0 {
+		return nil
+	}
+	if m.LdapConfig == nil || !m.LdapConfig.Enabled {
+		return fmt.Errorf("personal-spaces of org [%s] require ldap to be enabled in ldap.yml", personalOrgs[0].Org)
+	}
+	uaaUsers, err := m.listUAAUsers()
+	if err != nil {
+		return err
+	}
+	for _, orgConfig := range personalOrgs {
+		if err := m.createPersonalSpaces(orgConfig, uaaUsers); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Error creating personal spaces of org %s", orgConfig.Org))
+		}
+	}
+	return nil
+}
+
+func (m *DefaultManager) createPersonalSpaces(orgConfig config.OrgConfig, uaaUsers map[string]*uaaclient.User) error {
+	spaces, err := m.Cfg.OrgSpaces(orgConfig.Org)
+	if err != nil {
+		return err
+	}
+	// delete-spaces would delete every personal space, as none of them is in spaces.yml
+	if spaces.EnableDeleteSpaces {
+		return fmt.Errorf("enable-delete-spaces must be false in spaces.yml of org [%s], which has personal-spaces", orgConfig.Org)
+	}
+	orgGUID, err := m.OrgMgr.GetOrgGUID(orgConfig.Org)
+	if err != nil {
+		return err
+	}
+	existing, err := m.SpaceMgr.ListSpaces(orgGUID)
+	if err != nil {
+		return err
+	}
+	spaceNames := make(map[string]bool)
+	for _, space := range existing {
+		spaceNames[space.Name] = true
+	}
+
+	members, err := m.GetLDAPUsers(uaaUsers, UpdateUsersInput{LdapGroupNames: []string{orgConfig.PersonalSpaces.LDAPGroup}})
+	if err != nil {
+		return err
+	}
+	for _, member := range members {
+		member = m.UpdateUserInfo(member)
+		spaceName := orgConfig.PersonalSpaces.SpaceName(member.UserID)
+		if !spaceNames[spaceName] {
+			if err := m.SpaceMgr.CreateSpace(spaceName, orgConfig.Org, orgGUID); err != nil {
+				return err
+			}
+			spaceNames[spaceName] = true
+		}
+		space, err := m.SpaceMgr.FindSpace(orgConfig.Org, spaceName)
+		if err != nil {
+			return err
+		}
+		if err := m.addPersonalSpaceDeveloper(member, uaaUsers, UpdateUsersInput{
+			SpaceName: space.Name,
+			SpaceGUID: space.Guid,
+			OrgName:   orgConfig.Org,
+			OrgGUID:   orgGUID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addPersonalSpaceDeveloper makes the member developer of their space,
+// creating the member in uaa when they have never logged in
+func (m *DefaultManager) addPersonalSpaceDeveloper(member ldap.User, uaaUsers map[string]*uaaclient.User, input UpdateUsersInput) error {
+	developers, err := m.ListSpaceDevelopers(input.SpaceGUID)
+	if err != nil {
+		return err
+	}
+	userID := strings.ToLower(member.UserID)
+	if _, ok := developers[userID]; ok {
+		return nil
+	}
+	if err := m.lookupUAAUsers(uaaUsers, []string{member.UserID}); err != nil {
+		return err
+	}
+	if _, ok := uaaUsers[userID]; !ok {
+		if err := m.UAAMgr.CreateExternalUser(member.UserID, member.Email, member.UserDN, m.LdapConfig.Origin); err != nil {
+			return err
+		}
+		uaaUser := &uaaclient.User{
+			Username:   member.UserID,
+			ExternalID: member.UserDN,
+			Origin:     m.LdapConfig.Origin,
+			Emails:     []uaaclient.Email{uaaclient.Email{Value: member.Email}},
+		}
+		uaaUsers[userID] = uaaUser
+		uaaUsers[member.UserDN] = uaaUser
+	}
+	lo.G.Debugf("Personal space %s of org %s belongs to %s", input.SpaceName, input.OrgName, member.UserID)
+	return m.AssociateSpaceDeveloper(input, member.UserID)
+}
@@ -0,0 +1,110 @@
+package user_test
+
+import (
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	uaaclient "github.com/cloudfoundry-community/go-uaa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/config"
+	configfakes "github.com/pivotalservices/cf-mgmt/config/fakes"
+	ldap "github.com/pivotalservices/cf-mgmt/ldap"
+	ldapfakes "github.com/pivotalservices/cf-mgmt/ldap/fakes"
+	orgfakes "github.com/pivotalservices/cf-mgmt/organization/fakes"
+	spacefakes "github.com/pivotalservices/cf-mgmt/space/fakes"
+	uaafakes "github.com/pivotalservices/cf-mgmt/uaa/fakes"
+	. "github.com/pivotalservices/cf-mgmt/user"
+	"github.com/pivotalservices/cf-mgmt/user/fakes"
+)
+
+var _ = Describe("given CreatePersonalSpaces", func() {
+	var (
+		userManager *DefaultManager
+		client      *fakes.FakeCFClient
+		ldapFake    *ldapfakes.FakeManager
+		uaaFake     *uaafakes.FakeManager
+		fakeReader  *configfakes.FakeReader
+		orgFake     *orgfakes.FakeManager
+		spaceFake   *spacefakes.FakeManager
+	)
+	BeforeEach(func() {
+		client = new(fakes.FakeCFClient)
+		ldapFake = new(ldapfakes.FakeManager)
+		uaaFake = new(uaafakes.FakeManager)
+		fakeReader = new(configfakes.FakeReader)
+		orgFake = new(orgfakes.FakeManager)
+		spaceFake = new(spacefakes.FakeManager)
+		userManager = &DefaultManager{
+			Client:     client,
+			Cfg:        fakeReader,
+			UAAMgr:     uaaFake,
+			LdapMgr:    ldapFake,
+			OrgMgr:     orgFake,
+			SpaceMgr:   spaceFake,
+			LdapConfig: &config.LdapConfig{Origin: "ldap", Enabled: true},
+		}
+		fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+			{Org: "org1"},
+			{Org: "sandbox", PersonalSpaces: &config.PersonalSpaces{LDAPGroup: "developers", SpacePrefix: "sandbox-"}},
+		}, nil)
+		fakeReader.OrgSpacesReturns(&config.Spaces{Org: "sandbox"}, nil)
+		orgFake.GetOrgGUIDReturns("sandbox-guid", nil)
+		spaceFake.ListSpacesReturns([]cfclient.Space{{Name: "sandbox-alice", Guid: "alice-space-guid"}}, nil)
+		spaceFake.FindSpaceStub = func(orgName, spaceName string) (cfclient.Space, error) {
+			return cfclient.Space{Name: spaceName, Guid: spaceName + "-guid"}, nil
+		}
+		uaaFake.ListUsersReturns(map[string]*uaaclient.User{
+			"alice":    {Username: "alice", ExternalID: "cn=alice", Origin: "ldap"},
+			"cn=alice": {Username: "alice", ExternalID: "cn=alice", Origin: "ldap"},
+		}, nil)
+		ldapFake.GetUserDNsReturns([]string{"cn=alice", "cn=bob"}, nil)
+		ldapFake.GetUserByDNReturns(&ldap.User{UserID: "Bob", UserDN: "cn=bob", Email: "bob@example.com"}, nil)
+	})
+
+	It("does nothing without personal spaces", func() {
+		fakeReader.GetOrgConfigsReturns([]config.OrgConfig{{Org: "org1"}}, nil)
+		Expect(userManager.CreatePersonalSpaces()).Should(Succeed())
+		Expect(uaaFake.ListUsersCallCount()).Should(Equal(0))
+	})
+
+	It("creates the missing spaces with their member as developer", func() {
+		client.ListSpaceDevelopersStub = func(spaceGUID string) ([]cfclient.User, error) {
+			if spaceGUID == "sandbox-alice-guid" {
+				return []cfclient.User{{Username: "alice"}}, nil
+			}
+			return nil, nil
+		}
+		Expect(userManager.CreatePersonalSpaces()).Should(Succeed())
+		Expect(ldapFake.GetUserDNsArgsForCall(0)).Should(Equal("developers"))
+		Expect(spaceFake.CreateSpaceCallCount()).Should(Equal(1))
+		spaceName, orgName, orgGUID := spaceFake.CreateSpaceArgsForCall(0)
+		Expect(spaceName).Should(Equal("sandbox-bob"))
+		Expect(orgName).Should(Equal("sandbox"))
+		Expect(orgGUID).Should(Equal("sandbox-guid"))
+
+		Expect(uaaFake.CreateExternalUserCallCount()).Should(Equal(1))
+		userName, email, externalID, origin := uaaFake.CreateExternalUserArgsForCall(0)
+		Expect(userName).Should(Equal("bob"))
+		Expect(email).Should(Equal("bob@example.com"))
+		Expect(externalID).Should(Equal("cn=bob"))
+		Expect(origin).Should(Equal("ldap"))
+
+		Expect(client.AssociateSpaceDeveloperByUsernameCallCount()).Should(Equal(1))
+		spaceGUID, developer := client.AssociateSpaceDeveloperByUsernameArgsForCall(0)
+		Expect(spaceGUID).Should(Equal("sandbox-bob-guid"))
+		Expect(developer).Should(Equal("bob"))
+		orgGUID, orgUser := client.AssociateOrgUserByUsernameArgsForCall(0)
+		Expect(orgGUID).Should(Equal("sandbox-guid"))
+		Expect(orgUser).Should(Equal("bob"))
+	})
+
+	It("errors when delete-spaces would delete the personal spaces", func() {
+		fakeReader.OrgSpacesReturns(&config.Spaces{Org: "sandbox", EnableDeleteSpaces: true}, nil)
+		Expect(userManager.CreatePersonalSpaces()).Should(MatchError("Error creating personal spaces of org sandbox: enable-delete-spaces must be false in spaces.yml of org [sandbox], which has personal-spaces"))
+		Expect(spaceFake.CreateSpaceCallCount()).Should(Equal(0))
+	})
+
+	It("errors without ldap", func() {
+		userManager.LdapConfig = &config.LdapConfig{}
+		Expect(userManager.CreatePersonalSpaces()).Should(MatchError("personal-spaces of org [sandbox] require ldap to be enabled in ldap.yml"))
+	})
+})
@@ -0,0 +1,149 @@
+package user
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pkg/errors"
+)
+
+// roleGroupDescription marks the groups cf-mgmt keeps in sync with a role.
+const roleGroupDescription = "managed by cf-mgmt from the %s role of %s"
+
+//UpdateRoleGroups - makes the members of the uaa groups in role-groups of
+//cf-mgmt.yml the users that hold the role in each org and space
+func (m *DefaultManager) UpdateRoleGroups() error {
+	globalConfig, err := m.Cfg.GetGlobalConfig()
+	if err != nil {
+		return err
+	}
+	var orgRoleGroups, spaceRoleGroups []config.RoleGroup
+	for _, roleGroup := range globalConfig.RoleGroups {
+		if roleGroup.IsSpaceRole() {
+			spaceRoleGroups = append(spaceRoleGroups, roleGroup)
+		} else {
+			orgRoleGroups = append(orgRoleGroups, roleGroup)
+		}
+	}
+
+	if len(orgRoleGroups) > 0 {
+		orgConfigs, err := m.Cfg.GetOrgConfigs()
+		if err != nil {
+			return err
+		}
+		for _, orgConfig := range orgConfigs {
+			org, err := m.OrgMgr.FindOrg(orgConfig.Org)
+			if err != nil {
+				return err
+			}
+			for _, roleGroup := range orgRoleGroups {
+				roleUsers, err := m.listOrgRole(roleGroup.Role, org.Guid)
+				if err != nil {
+					return errors.Wrapf(err, "Error listing %s users of org %s", roleGroup.Role, orgConfig.Org)
+				}
+				owner := fmt.Sprintf("org %s", orgConfig.Org)
+				if err := m.syncRoleGroup(roleGroup.GroupName(orgConfig.Org, ""), fmt.Sprintf(roleGroupDescription, roleGroup.Role, owner), roleUsers); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if len(spaceRoleGroups) > 0 {
+		spaceConfigs, err := m.Cfg.GetSpaceConfigs()
+		if err != nil {
+			return err
+		}
+		for _, spaceConfig := range spaceConfigs {
+			space, err := m.SpaceMgr.FindSpace(spaceConfig.Org, spaceConfig.Space)
+			if err != nil {
+				return err
+			}
+			for _, roleGroup := range spaceRoleGroups {
+				roleUsers, err := m.listSpaceRole(roleGroup.Role, space.Guid)
+				if err != nil {
+					return errors.Wrapf(err, "Error listing %s users of org/space %s/%s", roleGroup.Role, spaceConfig.Org, spaceConfig.Space)
+				}
+				owner := fmt.Sprintf("org/space %s/%s", spaceConfig.Org, spaceConfig.Space)
+				if err := m.syncRoleGroup(roleGroup.GroupName(spaceConfig.Org, spaceConfig.Space), fmt.Sprintf(roleGroupDescription, roleGroup.Role, owner), roleUsers); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (m *DefaultManager) listOrgRole(role, orgGUID string) (map[string]string, error) {
+	switch role {
+	case config.RoleOrgManager:
+		return m.ListOrgManagers(orgGUID)
+	case config.RoleOrgBillingManager:
+		return m.ListOrgBillingManagers(orgGUID)
+	default:
+		return m.ListOrgAuditors(orgGUID)
+	}
+}
+
+func (m *DefaultManager) listSpaceRole(role, spaceGUID string) (map[string]string, error) {
+	switch role {
+	case config.RoleSpaceManager:
+		return m.ListSpaceManagers(spaceGUID)
+	case config.RoleSpaceDeveloper:
+		return m.ListSpaceDevelopers(spaceGUID)
+	default:
+		return m.ListSpaceAuditors(spaceGUID)
+	}
+}
+
+// syncRoleGroup creates the group when it does not exist, adds the users of
+// the role that are not members and removes the members without the role.
+// UAA clients holding the role are skipped as only users can be members.
+func (m *DefaultManager) syncRoleGroup(name, description string, roleUsers map[string]string) error {
+	group, err := m.UAAMgr.GetGroup(name)
+	if err != nil {
+		return err
+	}
+	if group == nil {
+		if group, err = m.UAAMgr.CreateGroup(name, description); err != nil {
+			return err
+		}
+	}
+	members := make(map[string]bool)
+	for _, member := range group.Members {
+		if member.Type == "" || member.Type == "USER" {
+			members[member.Value] = true
+		}
+	}
+
+	userIDs := make(map[string]string)
+	for _, userName := range sortedUserNames(roleUsers) {
+		userID := roleUsers[userName]
+		// clients are keyed by their guid, which is the client id
+		if strings.EqualFold(userName, userID) {
+			continue
+		}
+		userIDs[userID] = userName
+		if !members[userID] {
+			if err := m.UAAMgr.AddGroupMember(*group, userID, userName); err != nil {
+				return err
+			}
+		}
+	}
+	var removed []string
+	for userID := range members {
+		if _, ok := userIDs[userID]; !ok {
+			removed = append(removed, userID)
+		}
+	}
+	sort.Strings(removed)
+	for _, userID := range removed {
+		// a member without the role is only known by its id
+		if err := m.UAAMgr.RemoveGroupMember(*group, userID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package user_test
+
+import (
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	uaaclient "github.com/cloudfoundry-community/go-uaa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/config"
+	configfakes "github.com/pivotalservices/cf-mgmt/config/fakes"
+	orgfakes "github.com/pivotalservices/cf-mgmt/organization/fakes"
+	spacefakes "github.com/pivotalservices/cf-mgmt/space/fakes"
+	uaafakes "github.com/pivotalservices/cf-mgmt/uaa/fakes"
+	. "github.com/pivotalservices/cf-mgmt/user"
+	"github.com/pivotalservices/cf-mgmt/user/fakes"
+)
+
+var _ = Describe("given UpdateRoleGroups", func() {
+	var (
+		userManager *DefaultManager
+		client      *fakes.FakeCFClient
+		uaaFake     *uaafakes.FakeManager
+		fakeReader  *configfakes.FakeReader
+		orgFake     *orgfakes.FakeManager
+		spaceFake   *spacefakes.FakeManager
+	)
+	BeforeEach(func() {
+		client = new(fakes.FakeCFClient)
+		uaaFake = new(uaafakes.FakeManager)
+		fakeReader = new(configfakes.FakeReader)
+		orgFake = new(orgfakes.FakeManager)
+		spaceFake = new(spacefakes.FakeManager)
+		userManager = &DefaultManager{
+			Client:   client,
+			Cfg:      fakeReader,
+			UAAMgr:   uaaFake,
+			OrgMgr:   orgFake,
+			SpaceMgr: spaceFake,
+		}
+		fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{}, nil)
+		fakeReader.GetOrgConfigsReturns([]config.OrgConfig{{Org: "org1"}}, nil)
+		fakeReader.GetSpaceConfigsReturns([]config.SpaceConfig{{Org: "org1", Space: "dev"}}, nil)
+		orgFake.FindOrgReturns(cfclient.Org{Name: "org1", Guid: "org1-guid"}, nil)
+		spaceFake.FindSpaceReturns(cfclient.Space{Name: "dev", Guid: "dev-guid"}, nil)
+	})
+
+	It("does nothing without role-groups", func() {
+		Expect(userManager.UpdateRoleGroups()).Should(Succeed())
+		Expect(fakeReader.GetOrgConfigsCallCount()).Should(Equal(0))
+		Expect(uaaFake.GetGroupCallCount()).Should(Equal(0))
+	})
+
+	It("creates the group of an org role with the users of the role", func() {
+		fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{RoleGroups: []config.RoleGroup{
+			{Role: config.RoleOrgAuditor, Group: "grafana.org.{org}.viewer"},
+		}}, nil)
+		client.ListOrgAuditorsReturns([]cfclient.User{
+			{Username: "bob", Guid: "bob-guid"},
+			{Guid: "concourse-deployer"},
+		}, nil)
+		uaaFake.CreateGroupReturns(&uaaclient.Group{ID: "group-guid", DisplayName: "grafana.org.org1.viewer"}, nil)
+		Expect(userManager.UpdateRoleGroups()).Should(Succeed())
+		Expect(uaaFake.GetGroupArgsForCall(0)).Should(Equal("grafana.org.org1.viewer"))
+		name, description := uaaFake.CreateGroupArgsForCall(0)
+		Expect(name).Should(Equal("grafana.org.org1.viewer"))
+		Expect(description).Should(Equal("managed by cf-mgmt from the org-auditor role of org org1"))
+		Expect(uaaFake.AddGroupMemberCallCount()).Should(Equal(1))
+		group, userID, userName := uaaFake.AddGroupMemberArgsForCall(0)
+		Expect(group.ID).Should(Equal("group-guid"))
+		Expect(userID).Should(Equal("bob-guid"))
+		Expect(userName).Should(Equal("bob"))
+		Expect(fakeReader.GetSpaceConfigsCallCount()).Should(Equal(0))
+	})
+
+	It("adds and removes members of a space role group", func() {
+		fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{RoleGroups: []config.RoleGroup{
+			{Role: config.RoleSpaceDeveloper, Group: "grafana.space.{org}.{space}.editor"},
+		}}, nil)
+		client.ListSpaceDevelopersReturns([]cfclient.User{
+			{Username: "alice", Guid: "alice-guid"},
+			{Username: "bob", Guid: "bob-guid"},
+		}, nil)
+		uaaFake.GetGroupReturns(&uaaclient.Group{ID: "group-guid", DisplayName: "grafana.space.org1.dev.editor", Members: []uaaclient.GroupMember{
+			{Type: "USER", Value: "bob-guid"},
+			{Type: "USER", Value: "carol-guid"},
+			{Type: "GROUP", Value: "nested-group-guid"},
+		}}, nil)
+		Expect(userManager.UpdateRoleGroups()).Should(Succeed())
+		Expect(client.ListSpaceDevelopersArgsForCall(0)).Should(Equal("dev-guid"))
+		Expect(uaaFake.CreateGroupCallCount()).Should(Equal(0))
+		Expect(uaaFake.AddGroupMemberCallCount()).Should(Equal(1))
+		_, userID, _ := uaaFake.AddGroupMemberArgsForCall(0)
+		Expect(userID).Should(Equal("alice-guid"))
+		Expect(uaaFake.RemoveGroupMemberCallCount()).Should(Equal(1))
+		_, userID, _ = uaaFake.RemoveGroupMemberArgsForCall(0)
+		Expect(userID).Should(Equal("carol-guid"))
+	})
+})
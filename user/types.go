@@ -11,12 +11,26 @@ type UpdateUsersInput struct {
 	SpaceGUID                                   string
 	OrgGUID                                     string
 	LdapUsers, Users, LdapGroupNames, SamlUsers []string
+	Clients                                     []string
 	SpaceName                                   string
 	OrgName                                     string
 	RemoveUsers                                 bool
+	RemovalDeferred                             bool
+	ExcludeUsers                                []string
 	ListUsers                                   func(updateUserInput UpdateUsersInput) (map[string]string, error)
 	AddUser                                     func(updateUserInput UpdateUsersInput, userName string) error
 	RemoveUser                                  func(updateUserInput UpdateUsersInput, userName string) error
+	AddClient                                   func(updateUserInput UpdateUsersInput, clientID string) error
+	RemoveClient                                func(updateUserInput UpdateUsersInput, clientID string) error
+	// ConfigFile is the configuration file of the org or space, and Source
+	// where the user being added comes from, such as an ldap group
+	ConfigFile string
+	Source     string
+	// InheritedFrom returns the config file granting the role to a user,
+	// client or ldap group the org or space inherits, such as org-groups.yml
+	InheritedFrom func(name string) string
+	// Role is the role synced, such as developer, named in the plan of a dry run
+	Role string
 }
 
 // Manager - interface type encapsulating Update space users behavior
@@ -25,7 +39,17 @@ type Manager interface {
 	DeinitializeLdap() error
 	UpdateSpaceUsers() error
 	UpdateOrgUsers() error
+	UpdateGroupUsers(group string) error
 	CleanupOrgUsers() error
+	MigrateUserOrigin() error
+	CleanupOriginUsers() error
+	DedupeUAAUsers(preferredOrigin string, consolidate bool, clientUser string) ([]DuplicateUsers, error)
+	ListMissingUsers() ([]MissingUser, error)
+	ListUndeclaredAdmins() ([]AdminAccess, error)
+	ListExternalUserProblems() ([]ExternalUserProblem, error)
+	DeveloperReport() (*DeveloperReport, error)
+	UpdateRoleGroups() error
+	CreatePersonalSpaces() error
 	ListSpaceAuditors(spaceGUID string) (map[string]string, error)
 	ListSpaceDevelopers(spaceGUID string) (map[string]string, error)
 	ListSpaceManagers(spaceGUID string) (map[string]string, error)
@@ -59,4 +83,20 @@ type CFClient interface {
 
 	ListOrgUsers(orgGUID string) ([]cfclient.User, error)
 	ListSpacesByQuery(query url.Values) ([]cfclient.Space, error)
+
+	AssociateOrgUser(orgGUID, userGUID string) (cfclient.Org, error)
+	AssociateOrgAuditor(orgGUID, userGUID string) (cfclient.Org, error)
+	AssociateOrgManager(orgGUID, userGUID string) (cfclient.Org, error)
+	AssociateOrgBillingManager(orgGUID, userGUID string) (cfclient.Org, error)
+	AssociateSpaceAuditor(spaceGUID, userGUID string) (cfclient.Space, error)
+	AssociateSpaceDeveloper(spaceGUID, userGUID string) (cfclient.Space, error)
+	AssociateSpaceManager(spaceGUID, userGUID string) (cfclient.Space, error)
+	RemoveOrgUser(orgGUID, userGUID string) error
+	RemoveOrgAuditor(orgGUID, userGUID string) error
+	RemoveOrgManager(orgGUID, userGUID string) error
+	RemoveOrgBillingManager(orgGUID, userGUID string) error
+	RemoveSpaceAuditor(spaceGUID, userGUID string) error
+	RemoveSpaceDeveloper(spaceGUID, userGUID string) error
+	RemoveSpaceManager(spaceGUID, userGUID string) error
+	DeleteUser(userGUID string) error
 }
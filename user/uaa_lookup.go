@@ -0,0 +1,87 @@
+package user
+
+import (
+	"strings"
+
+	uaaclient "github.com/cloudfoundry-community/go-uaa"
+	"github.com/pivotalservices/cf-mgmt/stats"
+	"github.com/pivotalservices/cf-mgmt/uaa"
+	"github.com/xchapter7x/lo"
+)
+
+// listUAAUsers returns the uaa users kept for the steps of an apply, or lists them.
+func (m *DefaultManager) listUAAUsers() (map[string]*uaaclient.User, error) {
+	if m.runCache && m.cachedUAAUsers != nil {
+		stats.CacheHit("uaa users")
+		return m.cachedUAAUsers, nil
+	}
+	uaaUsers, err := m.listUAAUsersFromUAA()
+	if err != nil {
+		return nil, err
+	}
+	if m.runCache {
+		stats.CacheMiss("uaa users")
+		m.cachedUAAUsers = uaaUsers
+	}
+	return uaaUsers, nil
+}
+
+//StartRunCache - shares the uaa users, along with the users created and looked up, between the steps of an apply
+func (m *DefaultManager) StartRunCache() {
+	m.runCache = true
+}
+
+//StopRunCache - lists the uaa users again for every update
+func (m *DefaultManager) StopRunCache() {
+	m.runCache = false
+	m.cachedUAAUsers = nil
+}
+
+// listUAAUsersFromUAA lists every uaa user up front, or in targeted mode starts with
+// no users and looks up the users referenced by the configuration as they are
+// synced. An origin cutover pairs up every user of two origins, so it always
+// lists every user.
+func (m *DefaultManager) listUAAUsersFromUAA() (map[string]*uaaclient.User, error) {
+	m.lookedUp = nil
+	if m.UAALookupMode != uaa.LookupTargeted {
+		return m.UAAMgr.ListUsers()
+	}
+	migration, err := m.Cfg.GetOriginMigration()
+	if err != nil {
+		return nil, err
+	}
+	if migration != nil && migration.Cutover {
+		lo.G.Debug("Listing every uaa user as origin-migration.yml has cutover set")
+		return m.UAAMgr.ListUsers()
+	}
+	m.lookedUp = make(map[string]bool)
+	return make(map[string]*uaaclient.User), nil
+}
+
+// lookupUAAUsers adds the users with the names to uaaUsers in targeted mode,
+// names that were already looked up are not looked up again.
+func (m *DefaultManager) lookupUAAUsers(uaaUsers map[string]*uaaclient.User, names []string) error {
+	if m.lookedUp == nil {
+		return nil
+	}
+	var toLookup []string
+	for _, name := range names {
+		lowerName := strings.ToLower(name)
+		if _, ok := uaaUsers[lowerName]; ok || m.lookedUp[lowerName] {
+			continue
+		}
+		m.lookedUp[lowerName] = true
+		toLookup = append(toLookup, name)
+	}
+	if len(toLookup) == 0 {
+		return nil
+	}
+	users, err := m.UAAMgr.ListUsersByName(toLookup)
+	if err != nil {
+		return err
+	}
+	for key, user := range users {
+		uaaUsers[key.Name] = user
+	}
+	return nil
+}
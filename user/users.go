@@ -3,10 +3,14 @@ package user
 import (
 	"fmt"
 	"net/url"
+	"sort"
 	"strings"
+	"time"
 
 	cfclient "github.com/cloudfoundry-community/go-cfclient"
 	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/diskcache"
+	"github.com/pivotalservices/cf-mgmt/dryrun"
 	"github.com/pivotalservices/cf-mgmt/ldap"
 	"github.com/pivotalservices/cf-mgmt/organization"
 	"github.com/pivotalservices/cf-mgmt/space"
@@ -45,6 +49,32 @@ type DefaultManager struct {
 	Peek       bool
 	LdapMgr    ldap.Manager
 	LdapConfig *config.LdapConfig
+	// UAALookupMode is uaa.LookupTargeted to only look up the uaa users referenced by the configuration
+	UAALookupMode string
+	// DiskCache, when set, keeps ldap lookups on disk for runs in quick succession
+	DiskCache *diskcache.Cache
+	// SkipLdap leaves ldap unbound, for plans computed without a network path
+	// to the ldap server, so the ldap users of roles are neither added nor removed
+	SkipLdap  bool
+	cutover   *originCutover
+	approvals *config.Approvals
+	delivery  passwordDelivery
+	// creation is create-internal-users of cf-mgmt.yml, when set
+	creation *config.UserCreation
+	// creationFailures are the saml users that could not be created this run
+	creationFailures *creationFailures
+	// lookedUp are the names looked up in targeted mode
+	lookedUp map[string]bool
+	// runCache keeps the uaa users, and the users added to them, while an apply runs
+	runCache       bool
+	cachedUAAUsers map[string]*uaaclient.User
+	// attributeRoles logs where the users added to roles come from
+	attributeRoles bool
+	// group, when set, limits syncing to the roles granted to this ldap group
+	group string
+
+	// ldapSkipped is set when ldap.yml enables ldap and SkipLdap left it unbound
+	ldapSkipped bool
 }
 
 func (m *DefaultManager) RemoveSpaceAuditor(input UpdateUsersInput, userName string) error {
@@ -127,22 +157,32 @@ func (m *DefaultManager) listSpaceManagers(input UpdateUsersInput) (map[string]s
 func (m *DefaultManager) userListToMap(users []cfclient.User) map[string]string {
 	userMap := make(map[string]string)
 	for _, user := range users {
-		userMap[strings.ToLower(user.Username)] = user.Guid
+		userMap[userKey(user)] = user.Guid
 	}
 	return userMap
 }
 
+// userKey returns the lowercased key used to track a user in a role.  UAA
+// clients have no username in cloud controller, so they are keyed by their
+// guid, which is the client id.
+func userKey(user cfclient.User) string {
+	if user.Username == "" {
+		return strings.ToLower(user.Guid)
+	}
+	return strings.ToLower(user.Username)
+}
+
 func (m *DefaultManager) AssociateSpaceAuditor(input UpdateUsersInput, userName string) error {
 	err := m.AddUserToOrg(userName, input)
 	if err != nil {
 		return err
 	}
 	if m.Peek {
-		lo.G.Infof("[dry-run]: adding %s to role %s for org/space %s/%s", userName, "auditor", input.OrgName, input.SpaceName)
+		lo.G.Infof("[dry-run]: adding %s to role %s for org/space %s/%s%s", userName, "auditor", input.OrgName, input.SpaceName, m.attribution(input))
 		return nil
 	}
 
-	lo.G.Infof("adding %s to role %s for org/space %s/%s", userName, "auditor", input.OrgName, input.SpaceName)
+	lo.G.Infof("adding %s to role %s for org/space %s/%s%s", userName, "auditor", input.OrgName, input.SpaceName, m.attribution(input))
 	_, err = m.Client.AssociateSpaceAuditorByUsername(input.SpaceGUID, userName)
 	return err
 }
@@ -152,10 +192,10 @@ func (m *DefaultManager) AssociateSpaceDeveloper(input UpdateUsersInput, userNam
 		return err
 	}
 	if m.Peek {
-		lo.G.Infof("[dry-run]: adding %s to role %s for org/space %s/%s", userName, "developer", input.OrgName, input.SpaceName)
+		lo.G.Infof("[dry-run]: adding %s to role %s for org/space %s/%s%s", userName, "developer", input.OrgName, input.SpaceName, m.attribution(input))
 		return nil
 	}
-	lo.G.Infof("adding %s to role %s for org/space %s/%s", userName, "developer", input.OrgName, input.SpaceName)
+	lo.G.Infof("adding %s to role %s for org/space %s/%s%s", userName, "developer", input.OrgName, input.SpaceName, m.attribution(input))
 	_, err = m.Client.AssociateSpaceDeveloperByUsername(input.SpaceGUID, userName)
 	return err
 }
@@ -165,11 +205,11 @@ func (m *DefaultManager) AssociateSpaceManager(input UpdateUsersInput, userName
 		return err
 	}
 	if m.Peek {
-		lo.G.Infof("[dry-run]: adding %s to role %s for org/space %s/%s", userName, "manager", input.OrgName, input.SpaceName)
+		lo.G.Infof("[dry-run]: adding %s to role %s for org/space %s/%s%s", userName, "manager", input.OrgName, input.SpaceName, m.attribution(input))
 		return nil
 	}
 
-	lo.G.Infof("adding %s to role %s for org/space %s/%s", userName, "manager", input.OrgName, input.SpaceName)
+	lo.G.Infof("adding %s to role %s for org/space %s/%s%s", userName, "manager", input.OrgName, input.SpaceName, m.attribution(input))
 	_, err = m.Client.AssociateSpaceManagerByUsername(input.SpaceGUID, userName)
 	return err
 }
@@ -182,6 +222,14 @@ func (m *DefaultManager) AddUserToOrg(userName string, input UpdateUsersInput) e
 	return err
 }
 
+func (m *DefaultManager) AddClientToOrg(clientID string, input UpdateUsersInput) error {
+	if m.Peek {
+		return nil
+	}
+	_, err := m.Client.AssociateOrgUser(input.OrgGUID, clientID)
+	return err
+}
+
 func (m *DefaultManager) RemoveOrgAuditor(input UpdateUsersInput, userName string) error {
 	if m.Peek {
 		lo.G.Infof("[dry-run]: removing user %s from org %s with role %s", userName, input.OrgName, "auditor")
@@ -266,11 +314,11 @@ func (m *DefaultManager) AssociateOrgAuditor(input UpdateUsersInput, userName st
 		return err
 	}
 	if m.Peek {
-		lo.G.Infof("[dry-run]: Add User %s to role %s for org %s", userName, "auditor", input.OrgName)
+		lo.G.Infof("[dry-run]: Add User %s to role %s for org %s%s", userName, "auditor", input.OrgName, m.attribution(input))
 		return nil
 	}
 
-	lo.G.Infof("Add User %s to role %s for org %s", userName, "auditor", input.OrgName)
+	lo.G.Infof("Add User %s to role %s for org %s%s", userName, "auditor", input.OrgName, m.attribution(input))
 	_, err = m.Client.AssociateOrgAuditorByUsername(input.OrgGUID, userName)
 	return err
 }
@@ -280,11 +328,11 @@ func (m *DefaultManager) AssociateOrgBillingManager(input UpdateUsersInput, user
 		return err
 	}
 	if m.Peek {
-		lo.G.Infof("[dry-run]: Add User %s to role %s for org %s", userName, "billing manager", input.OrgName)
+		lo.G.Infof("[dry-run]: Add User %s to role %s for org %s%s", userName, "billing manager", input.OrgName, m.attribution(input))
 		return nil
 	}
 
-	lo.G.Infof("Add User %s to role %s for org %s", userName, "billing manager", input.OrgName)
+	lo.G.Infof("Add User %s to role %s for org %s%s", userName, "billing manager", input.OrgName, m.attribution(input))
 	_, err = m.Client.AssociateOrgBillingManagerByUsername(input.OrgGUID, userName)
 	return err
 }
@@ -295,90 +343,162 @@ func (m *DefaultManager) AssociateOrgManager(input UpdateUsersInput, userName st
 		return err
 	}
 	if m.Peek {
-		lo.G.Infof("[dry-run]: Add User %s to role %s for org %s", userName, "manager", input.OrgName)
+		lo.G.Infof("[dry-run]: Add User %s to role %s for org %s%s", userName, "manager", input.OrgName, m.attribution(input))
 		return nil
 	}
 
-	lo.G.Infof("Add User %s to role %s for org %s", userName, "manager", input.OrgName)
+	lo.G.Infof("Add User %s to role %s for org %s%s", userName, "manager", input.OrgName, m.attribution(input))
 	_, err = m.Client.AssociateOrgManagerByUsername(input.OrgGUID, userName)
 	return err
 }
 
 //UpdateSpaceUsers -
 func (m *DefaultManager) UpdateSpaceUsers() error {
-	uaaUsers, err := m.UAAMgr.ListUsers()
+	uaaUsers, err := m.listUAAUsers()
 	if err != nil {
 		return err
 	}
+	if err := m.initializeCutover(uaaUsers); err != nil {
+		return err
+	}
+	if err := m.initializeInternalUsers(); err != nil {
+		return err
+	}
+	if err := m.initializeAttribution(); err != nil {
+		return err
+	}
+	if err := m.initializeCreationFailures(); err != nil {
+		return err
+	}
 
 	spaceConfigs, err := m.Cfg.GetSpaceConfigs()
 	if err != nil {
 		return err
 	}
+	spaceConfigs, err = space.ExpandSpaceConfigs(m.SpaceMgr, spaceConfigs)
+	if err != nil {
+		return err
+	}
+	if m.group != "" {
+		spaceConfigs = m.spaceConfigsOfGroup(spaceConfigs)
+	}
+
+	orgConfigs, err := m.Cfg.GetOrgConfigs()
+	if err != nil {
+		return err
+	}
+	deferred, err := config.OrgsOutsideMaintenanceWindow(orgConfigs, time.Now())
+	if err != nil {
+		return err
+	}
+	if m.approvals, err = m.Cfg.GetApprovals(); err != nil {
+		return err
+	}
 
+	var groupNames []string
 	for _, input := range spaceConfigs {
-		if err := m.updateSpaceUsers(&input, uaaUsers); err != nil {
+		groupNames = append(groupNames, input.GetDeveloperGroups()...)
+		groupNames = append(groupNames, input.GetManagerGroups()...)
+		groupNames = append(groupNames, input.GetAuditorGroups()...)
+	}
+	if err := m.resolveLdapGroups(groupNames); err != nil {
+		return err
+	}
+
+	orgExcludeUsers := make(map[string][]string)
+	for _, orgConfig := range orgConfigs {
+		orgExcludeUsers[orgConfig.Org] = orgConfig.ExcludeUsers
+	}
+
+	for _, input := range spaceConfigs {
+		// users excluded in the org are never removed from its spaces either
+		input.ExcludeUsers = append(input.ExcludeUsers, orgExcludeUsers[input.Org]...)
+		if err := m.updateSpaceUsers(&input, uaaUsers, deferred[input.Org]); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return m.creationFailures.err()
 }
 
-func (m *DefaultManager) updateSpaceUsers(input *config.SpaceConfig, uaaUsers map[string]*uaaclient.User) error {
+func (m *DefaultManager) updateSpaceUsers(input *config.SpaceConfig, uaaUsers map[string]*uaaclient.User, removalDeferred bool) error {
 	space, err := m.SpaceMgr.FindSpace(input.Org, input.Space)
 	if err != nil {
 		return errors.Wrap(err, fmt.Sprintf("Error finding space for org %s, space %s", input.Org, input.Space))
 	}
 
 	if err = m.SyncUsers(uaaUsers, UpdateUsersInput{
-		SpaceName:      space.Name,
-		SpaceGUID:      space.Guid,
-		OrgName:        input.Org,
-		OrgGUID:        space.OrganizationGuid,
-		LdapGroupNames: input.GetDeveloperGroups(),
-		LdapUsers:      input.Developer.LDAPUsers,
-		Users:          input.Developer.Users,
-		SamlUsers:      input.Developer.SamlUsers,
-		RemoveUsers:    input.RemoveUsers,
-		ListUsers:      m.listSpaceDevelopers,
-		RemoveUser:     m.RemoveSpaceDeveloper,
-		AddUser:        m.AssociateSpaceDeveloper,
+		SpaceName:       space.Name,
+		SpaceGUID:       space.Guid,
+		OrgName:         input.Org,
+		OrgGUID:         space.OrganizationGuid,
+		ConfigFile:      input.ConfigFile(),
+		InheritedFrom:   input.Developer.InheritedFrom,
+		LdapGroupNames:  input.GetDeveloperGroups(),
+		LdapUsers:       input.Developer.LDAPUsers,
+		Users:           input.Developer.Users,
+		SamlUsers:       input.Developer.SamlUsers,
+		Clients:         input.Developer.Clients,
+		RemoveUsers:     input.RemoveUsers,
+		RemovalDeferred: removalDeferred,
+		ExcludeUsers:    input.ExcludeUsers,
+		Role:            "developer",
+		ListUsers:       m.listSpaceDevelopers,
+		RemoveUser:      m.RemoveSpaceDeveloper,
+		AddUser:         m.AssociateSpaceDeveloper,
+		RemoveClient:    m.RemoveClient,
+		AddClient:       m.AssociateClient,
 	}); err != nil {
 		return errors.Wrap(err, fmt.Sprintf("Error syncing users for org %s, space %s, role %s", input.Org, input.Space, "developer"))
 	}
 
 	if err = m.SyncUsers(uaaUsers,
 		UpdateUsersInput{
-			SpaceName:      space.Name,
-			SpaceGUID:      space.Guid,
-			OrgGUID:        space.OrganizationGuid,
-			OrgName:        input.Org,
-			LdapGroupNames: input.GetManagerGroups(),
-			LdapUsers:      input.Manager.LDAPUsers,
-			Users:          input.Manager.Users,
-			SamlUsers:      input.Manager.SamlUsers,
-			RemoveUsers:    input.RemoveUsers,
-			ListUsers:      m.listSpaceManagers,
-			RemoveUser:     m.RemoveSpaceManager,
-			AddUser:        m.AssociateSpaceManager,
+			SpaceName:       space.Name,
+			SpaceGUID:       space.Guid,
+			OrgGUID:         space.OrganizationGuid,
+			ConfigFile:      input.ConfigFile(),
+			InheritedFrom:   input.Manager.InheritedFrom,
+			OrgName:         input.Org,
+			LdapGroupNames:  input.GetManagerGroups(),
+			LdapUsers:       input.Manager.LDAPUsers,
+			Users:           input.Manager.Users,
+			SamlUsers:       input.Manager.SamlUsers,
+			Clients:         input.Manager.Clients,
+			RemoveUsers:     input.RemoveUsers,
+			RemovalDeferred: removalDeferred,
+			ExcludeUsers:    input.ExcludeUsers,
+			Role:            "manager",
+			ListUsers:       m.listSpaceManagers,
+			RemoveUser:      m.RemoveSpaceManager,
+			AddUser:         m.AssociateSpaceManager,
+			RemoveClient:    m.RemoveClient,
+			AddClient:       m.AssociateClient,
 		}); err != nil {
 		return errors.Wrap(err, fmt.Sprintf("Error syncing users for org %s, space %s, role %s", input.Org, input.Space, "manager"))
 	}
 	if err = m.SyncUsers(uaaUsers,
 		UpdateUsersInput{
-			SpaceName:      space.Name,
-			SpaceGUID:      space.Guid,
-			OrgGUID:        space.OrganizationGuid,
-			OrgName:        input.Org,
-			LdapGroupNames: input.GetAuditorGroups(),
-			LdapUsers:      input.Auditor.LDAPUsers,
-			Users:          input.Auditor.Users,
-			SamlUsers:      input.Auditor.SamlUsers,
-			RemoveUsers:    input.RemoveUsers,
-			ListUsers:      m.listSpaceAuditors,
-			RemoveUser:     m.RemoveSpaceAuditor,
-			AddUser:        m.AssociateSpaceAuditor,
+			SpaceName:       space.Name,
+			SpaceGUID:       space.Guid,
+			OrgGUID:         space.OrganizationGuid,
+			ConfigFile:      input.ConfigFile(),
+			InheritedFrom:   input.Auditor.InheritedFrom,
+			OrgName:         input.Org,
+			LdapGroupNames:  input.GetAuditorGroups(),
+			LdapUsers:       input.Auditor.LDAPUsers,
+			Users:           input.Auditor.Users,
+			SamlUsers:       input.Auditor.SamlUsers,
+			Clients:         input.Auditor.Clients,
+			RemoveUsers:     input.RemoveUsers,
+			RemovalDeferred: removalDeferred,
+			ExcludeUsers:    input.ExcludeUsers,
+			Role:            "auditor",
+			ListUsers:       m.listSpaceAuditors,
+			RemoveUser:      m.RemoveSpaceAuditor,
+			AddUser:         m.AssociateSpaceAuditor,
+			RemoveClient:    m.RemoveClient,
+			AddClient:       m.AssociateClient,
 		}); err != nil {
 		return errors.Wrap(err, fmt.Sprintf("Error syncing users for org %s, space %s, role %s", input.Org, input.Space, "auditor"))
 	}
@@ -387,24 +507,57 @@ func (m *DefaultManager) updateSpaceUsers(input *config.SpaceConfig, uaaUsers ma
 
 //UpdateOrgUsers -
 func (m *DefaultManager) UpdateOrgUsers() error {
-	uaacUsers, err := m.UAAMgr.ListUsers()
+	uaacUsers, err := m.listUAAUsers()
 	if err != nil {
 		return err
 	}
+	if err := m.initializeCutover(uaacUsers); err != nil {
+		return err
+	}
+	if err := m.initializeInternalUsers(); err != nil {
+		return err
+	}
+	if err := m.initializeAttribution(); err != nil {
+		return err
+	}
+	if err := m.initializeCreationFailures(); err != nil {
+		return err
+	}
 
 	orgConfigs, err := m.Cfg.GetOrgConfigs()
 	if err != nil {
 		return err
 	}
+	if m.group != "" {
+		orgConfigs = m.orgConfigsOfGroup(orgConfigs)
+	}
+
+	deferred, err := config.OrgsOutsideMaintenanceWindow(orgConfigs, time.Now())
+	if err != nil {
+		return err
+	}
+	if m.approvals, err = m.Cfg.GetApprovals(); err != nil {
+		return err
+	}
+
+	var groupNames []string
+	for _, input := range orgConfigs {
+		groupNames = append(groupNames, input.GetBillingManagerGroups()...)
+		groupNames = append(groupNames, input.GetManagerGroups()...)
+		groupNames = append(groupNames, input.GetAuditorGroups()...)
+	}
+	if err := m.resolveLdapGroups(groupNames); err != nil {
+		return err
+	}
 
 	for _, input := range orgConfigs {
-		if err := m.updateOrgUsers(&input, uaacUsers); err != nil {
+		if err := m.updateOrgUsers(&input, uaacUsers, deferred[input.Org]); err != nil {
 			return err
 		}
 
 	}
 
-	return nil
+	return m.creationFailures.err()
 }
 
 //CleanupOrgUsers -
@@ -413,16 +566,23 @@ func (m *DefaultManager) CleanupOrgUsers() error {
 	if err != nil {
 		return err
 	}
+	deferred, err := config.OrgsOutsideMaintenanceWindow(orgConfigs, time.Now())
+	if err != nil {
+		return err
+	}
+	if m.approvals, err = m.Cfg.GetApprovals(); err != nil {
+		return err
+	}
 
 	for _, input := range orgConfigs {
-		if err := m.cleanupOrgUsers(&input); err != nil {
+		if err := m.cleanupOrgUsers(&input, deferred[input.Org]); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (m *DefaultManager) cleanupOrgUsers(input *config.OrgConfig) error {
+func (m *DefaultManager) cleanupOrgUsers(input *config.OrgConfig, removalDeferred bool) error {
 	org, err := m.OrgMgr.FindOrg(input.Org)
 	if err != nil {
 		return err
@@ -439,25 +599,63 @@ func (m *DefaultManager) cleanupOrgUsers(input *config.OrgConfig) error {
 
 	lo.G.Debugf("Users In Roles %+v", usersInRoles)
 
+	var usersToRemove []cfclient.User
+	var changes []config.Change
 	for _, orgUser := range orgUsers {
-		if _, ok := usersInRoles[strings.ToLower(orgUser.Username)]; !ok {
-			if m.Peek {
-				lo.G.Infof("[dry-run]: Removing User %s from org %s", orgUser.Username, input.Org)
-				continue
-			}
+		if isExcluded(input.ExcludeUsers, userKey(orgUser)) {
+			continue
+		}
+		if _, ok := usersInRoles[userKey(orgUser)]; !ok {
+			usersToRemove = append(usersToRemove, orgUser)
+			changes = append(changes, config.Change{Kind: config.ChangeRemoveUser, Org: input.Org, User: userKey(orgUser)})
+		}
+	}
+	if removalDeferred {
+		for _, orgUser := range usersToRemove {
+			lo.G.Infof("Deferring removal of %s from org %s until the maintenance window of the org", userKey(orgUser), input.Org)
+		}
+		return nil
+	}
+	if err := m.approvals.Check(changes...); err != nil {
+		return err
+	}
 
-			lo.G.Infof("Removing User %s from org %s", orgUser.Username, input.Org)
-			err := m.Client.RemoveOrgUserByUsername(org.Guid, orgUser.Username)
-			if err != nil {
-				return errors.Wrap(err, fmt.Sprintf("Error removing user %s from org %s", orgUser.Username, input.Org))
+	for _, orgUser := range usersToRemove {
+		if orgUser.Username == "" {
+			if err := m.removeOrgClient(org, orgUser.Guid); err != nil {
+				return err
 			}
+			continue
+		}
+		if m.Peek {
+			lo.G.Infof("[dry-run]: Removing User %s from org %s", orgUser.Username, input.Org)
+			dryrun.Record(dryrun.Delete, dryrun.OrgUser, orgUser.Username+" of "+input.Org, "")
+			continue
 		}
 
+		lo.G.Infof("Removing User %s from org %s", orgUser.Username, input.Org)
+		err := m.Client.RemoveOrgUserByUsername(org.Guid, orgUser.Username)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Error removing user %s from org %s", orgUser.Username, input.Org))
+		}
 	}
 
 	return nil
 }
 
+func (m *DefaultManager) removeOrgClient(org cfclient.Org, clientID string) error {
+	if m.Peek {
+		lo.G.Infof("[dry-run]: Removing client %s from org %s", clientID, org.Name)
+		dryrun.Record(dryrun.Delete, dryrun.OrgUser, "client "+clientID+" of "+org.Name, "")
+		return nil
+	}
+	lo.G.Infof("Removing client %s from org %s", clientID, org.Name)
+	if err := m.Client.RemoveOrgUser(org.Guid, clientID); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Error removing client %s from org %s", clientID, org.Name))
+	}
+	return nil
+}
+
 func (m *DefaultManager) usersInOrgRoles(orgName, orgGUID string) (map[string]string, error) {
 	userMap := make(map[string]string)
 
@@ -523,7 +721,7 @@ func (m *DefaultManager) listSpaces(orgGUID string) ([]cfclient.Space, error) {
 
 }
 
-func (m *DefaultManager) updateOrgUsers(input *config.OrgConfig, uaacUsers map[string]*uaaclient.User) error {
+func (m *DefaultManager) updateOrgUsers(input *config.OrgConfig, uaacUsers map[string]*uaaclient.User, removalDeferred bool) error {
 	org, err := m.OrgMgr.FindOrg(input.Org)
 	if err != nil {
 		return err
@@ -531,16 +729,24 @@ func (m *DefaultManager) updateOrgUsers(input *config.OrgConfig, uaacUsers map[s
 
 	err = m.SyncUsers(
 		uaacUsers, UpdateUsersInput{
-			OrgName:        org.Name,
-			OrgGUID:        org.Guid,
-			LdapGroupNames: input.GetBillingManagerGroups(),
-			LdapUsers:      input.BillingManager.LDAPUsers,
-			Users:          input.BillingManager.Users,
-			SamlUsers:      input.BillingManager.SamlUsers,
-			RemoveUsers:    input.RemoveUsers,
-			ListUsers:      m.listOrgBillingManagers,
-			RemoveUser:     m.RemoveOrgBillingManager,
-			AddUser:        m.AssociateOrgBillingManager,
+			OrgName:         org.Name,
+			OrgGUID:         org.Guid,
+			ConfigFile:      input.ConfigFile(),
+			InheritedFrom:   input.BillingManager.InheritedFrom,
+			LdapGroupNames:  input.GetBillingManagerGroups(),
+			LdapUsers:       input.BillingManager.LDAPUsers,
+			Users:           input.BillingManager.Users,
+			SamlUsers:       input.BillingManager.SamlUsers,
+			Clients:         input.BillingManager.Clients,
+			RemoveUsers:     input.RemoveUsers,
+			RemovalDeferred: removalDeferred,
+			ExcludeUsers:    input.ExcludeUsers,
+			Role:            "billing manager",
+			ListUsers:       m.listOrgBillingManagers,
+			RemoveUser:      m.RemoveOrgBillingManager,
+			AddUser:         m.AssociateOrgBillingManager,
+			RemoveClient:    m.RemoveClient,
+			AddClient:       m.AssociateClient,
 		})
 	if err != nil {
 		return errors.Wrap(err, fmt.Sprintf("Error syncing users for org %s role %s", input.Org, "billing_managers"))
@@ -548,16 +754,24 @@ func (m *DefaultManager) updateOrgUsers(input *config.OrgConfig, uaacUsers map[s
 
 	err = m.SyncUsers(
 		uaacUsers, UpdateUsersInput{
-			OrgName:        org.Name,
-			OrgGUID:        org.Guid,
-			LdapGroupNames: input.GetAuditorGroups(),
-			LdapUsers:      input.Auditor.LDAPUsers,
-			Users:          input.Auditor.Users,
-			SamlUsers:      input.Auditor.SamlUsers,
-			RemoveUsers:    input.RemoveUsers,
-			ListUsers:      m.listOrgAuditors,
-			RemoveUser:     m.RemoveOrgAuditor,
-			AddUser:        m.AssociateOrgAuditor,
+			OrgName:         org.Name,
+			OrgGUID:         org.Guid,
+			ConfigFile:      input.ConfigFile(),
+			InheritedFrom:   input.Auditor.InheritedFrom,
+			LdapGroupNames:  input.GetAuditorGroups(),
+			LdapUsers:       input.Auditor.LDAPUsers,
+			Users:           input.Auditor.Users,
+			SamlUsers:       input.Auditor.SamlUsers,
+			Clients:         input.Auditor.Clients,
+			RemoveUsers:     input.RemoveUsers,
+			RemovalDeferred: removalDeferred,
+			ExcludeUsers:    input.ExcludeUsers,
+			Role:            "auditor",
+			ListUsers:       m.listOrgAuditors,
+			RemoveUser:      m.RemoveOrgAuditor,
+			AddUser:         m.AssociateOrgAuditor,
+			RemoveClient:    m.RemoveClient,
+			AddClient:       m.AssociateClient,
 		})
 	if err != nil {
 		return errors.Wrap(err, fmt.Sprintf("Error syncing users for org %s role %s", input.Org, "org-auditors"))
@@ -565,16 +779,24 @@ func (m *DefaultManager) updateOrgUsers(input *config.OrgConfig, uaacUsers map[s
 
 	err = m.SyncUsers(
 		uaacUsers, UpdateUsersInput{
-			OrgName:        org.Name,
-			OrgGUID:        org.Guid,
-			LdapGroupNames: input.GetManagerGroups(),
-			LdapUsers:      input.Manager.LDAPUsers,
-			Users:          input.Manager.Users,
-			SamlUsers:      input.Manager.SamlUsers,
-			RemoveUsers:    input.RemoveUsers,
-			ListUsers:      m.listOrgManagers,
-			RemoveUser:     m.RemoveOrgManager,
-			AddUser:        m.AssociateOrgManager,
+			OrgName:         org.Name,
+			OrgGUID:         org.Guid,
+			ConfigFile:      input.ConfigFile(),
+			InheritedFrom:   input.Manager.InheritedFrom,
+			LdapGroupNames:  input.GetManagerGroups(),
+			LdapUsers:       input.Manager.LDAPUsers,
+			Users:           input.Manager.Users,
+			SamlUsers:       input.Manager.SamlUsers,
+			Clients:         input.Manager.Clients,
+			RemoveUsers:     input.RemoveUsers,
+			RemovalDeferred: removalDeferred,
+			ExcludeUsers:    input.ExcludeUsers,
+			Role:            "manager",
+			ListUsers:       m.listOrgManagers,
+			RemoveUser:      m.RemoveOrgManager,
+			AddUser:         m.AssociateOrgManager,
+			RemoveClient:    m.RemoveClient,
+			AddClient:       m.AssociateClient,
 		})
 
 	if err != nil {
@@ -586,10 +808,24 @@ func (m *DefaultManager) updateOrgUsers(input *config.OrgConfig, uaacUsers map[s
 
 //SyncUsers
 func (m *DefaultManager) SyncUsers(uaaUsers map[string]*uaaclient.User, updateUsersInput UpdateUsersInput) error {
+	if m.group != "" && !hasGroup(updateUsersInput.LdapGroupNames, m.group) {
+		return nil
+	}
+	updateUsersInput = m.recordRoleChanges(updateUsersInput)
 	roleUsers, err := updateUsersInput.ListUsers(updateUsersInput)
 	if err != nil {
 		return err
 	}
+	inRole := make(map[string]string)
+	var added []string
+	if m.cutover != nil {
+		m.appendToMap(inRole, roleUsers)
+		addUser := updateUsersInput.AddUser
+		updateUsersInput.AddUser = func(input UpdateUsersInput, userName string) error {
+			added = append(added, userName)
+			return addUser(input, userName)
+		}
+	}
 
 	if err := m.SyncLdapUsers(roleUsers, uaaUsers, updateUsersInput); err != nil {
 		return err
@@ -600,6 +836,14 @@ func (m *DefaultManager) SyncUsers(uaaUsers map[string]*uaaclient.User, updateUs
 	if err := m.SyncSamlUsers(roleUsers, uaaUsers, updateUsersInput); err != nil {
 		return err
 	}
+	if err := m.SyncClients(roleUsers, updateUsersInput); err != nil {
+		return err
+	}
+	if m.cutover != nil {
+		if err := m.cutover.syncCounterparts(inRole, roleUsers, added, uaaUsers, updateUsersInput); err != nil {
+			return err
+		}
+	}
 	if err := m.RemoveUsers(roleUsers, updateUsersInput); err != nil {
 		return err
 	}
@@ -607,13 +851,21 @@ func (m *DefaultManager) SyncUsers(uaaUsers map[string]*uaaclient.User, updateUs
 }
 
 func (m *DefaultManager) SyncInternalUsers(roleUsers map[string]string, uaaUsers map[string]*uaaclient.User, updateUsersInput UpdateUsersInput) error {
+	if err := m.lookupUAAUsers(uaaUsers, updateUsersInput.Users); err != nil {
+		return err
+	}
 	for _, userID := range updateUsersInput.Users {
 		lowerUserID := strings.ToLower(userID)
 		if _, userExists := uaaUsers[lowerUserID]; !userExists {
-			return fmt.Errorf("user %s doesn't exist in cloud foundry, so must add internal user first", lowerUserID)
+			if m.delivery == nil {
+				return fmt.Errorf("user %s doesn't exist in cloud foundry, so must add internal user first", lowerUserID)
+			}
+			if err := m.createInternalUser(userID, uaaUsers); err != nil {
+				return err
+			}
 		}
 		if _, ok := roleUsers[lowerUserID]; !ok {
-			if err := updateUsersInput.AddUser(updateUsersInput, userID); err != nil {
+			if err := updateUsersInput.AddUser(updateUsersInput.from("users", userID), userID); err != nil {
 				return err
 			}
 		} else {
@@ -624,12 +876,15 @@ func (m *DefaultManager) SyncInternalUsers(roleUsers map[string]string, uaaUsers
 }
 
 func (m *DefaultManager) SyncSamlUsers(roleUsers map[string]string, uaaUsers map[string]*uaaclient.User, updateUsersInput UpdateUsersInput) error {
+	if err := m.lookupUAAUsers(uaaUsers, updateUsersInput.SamlUsers); err != nil {
+		return err
+	}
 	for _, userEmail := range updateUsersInput.SamlUsers {
 		lowerUserEmail := strings.ToLower(userEmail)
 		if _, userExists := uaaUsers[lowerUserEmail]; !userExists {
 			lo.G.Debug("User", userEmail, "doesn't exist in cloud foundry, so creating user")
 			if err := m.UAAMgr.CreateExternalUser(userEmail, userEmail, userEmail, m.LdapConfig.Origin); err != nil {
-				lo.G.Error("Unable to create user", userEmail)
+				m.creationFailed(userEmail, updateUsersInput, err)
 				continue
 			} else {
 				uaaUsers[userEmail] = &uaaclient.User{
@@ -641,7 +896,7 @@ func (m *DefaultManager) SyncSamlUsers(roleUsers map[string]string, uaaUsers map
 			}
 		}
 		if _, ok := roleUsers[lowerUserEmail]; !ok {
-			if err := updateUsersInput.AddUser(updateUsersInput, userEmail); err != nil {
+			if err := updateUsersInput.AddUser(updateUsersInput.from("saml_users", userEmail), userEmail); err != nil {
 				return err
 			}
 		} else {
@@ -652,8 +907,37 @@ func (m *DefaultManager) SyncSamlUsers(roleUsers map[string]string, uaaUsers map
 }
 
 func (m *DefaultManager) RemoveUsers(roleUsers map[string]string, updateUsersInput UpdateUsersInput) error {
-	if updateUsersInput.RemoveUsers {
-		for roleUser, _ := range roleUsers {
+	for _, roleUser := range sortedUserNames(roleUsers) {
+		if isExcluded(updateUsersInput.ExcludeUsers, roleUser) {
+			lo.G.Debugf("Not removing user %s excluded by exclude-users of org/space %s/%s", roleUser, updateUsersInput.OrgName, updateUsersInput.SpaceName)
+			delete(roleUsers, roleUser)
+		}
+	}
+	if updateUsersInput.RemoveUsers && updateUsersInput.RemovalDeferred {
+		for _, roleUser := range sortedUserNames(roleUsers) {
+			if updateUsersInput.SpaceName == "" {
+				lo.G.Infof("Deferring removal of %s from org %s until the maintenance window of the org", roleUser, updateUsersInput.OrgName)
+			} else {
+				lo.G.Infof("Deferring removal of %s from org/space %s/%s until the maintenance window of the org", roleUser, updateUsersInput.OrgName, updateUsersInput.SpaceName)
+			}
+		}
+	} else if updateUsersInput.RemoveUsers {
+		userNames := sortedUserNames(roleUsers)
+		changes := make([]config.Change, len(userNames))
+		for i, roleUser := range userNames {
+			changes[i] = config.Change{Kind: config.ChangeRemoveUser, Org: updateUsersInput.OrgName, Space: updateUsersInput.SpaceName, User: roleUser}
+		}
+		if err := m.approvals.Check(changes...); err != nil {
+			return err
+		}
+		for _, roleUser := range userNames {
+			guid := roleUsers[roleUser]
+			if updateUsersInput.RemoveClient != nil && roleUser == strings.ToLower(guid) {
+				if err := updateUsersInput.RemoveClient(updateUsersInput, guid); err != nil {
+					return err
+				}
+				continue
+			}
 			if err := updateUsersInput.RemoveUser(updateUsersInput, roleUser); err != nil {
 				return err
 			}
@@ -668,22 +952,69 @@ func (m *DefaultManager) RemoveUsers(roleUsers map[string]string, updateUsersInp
 	return nil
 }
 
+// sortedUserNames returns the user names of a role in order, so that users
+// are removed in the same order on every run.
+func sortedUserNames(roleUsers map[string]string) []string {
+	userNames := make([]string, 0, len(roleUsers))
+	for userName := range roleUsers {
+		userNames = append(userNames, userName)
+	}
+	sort.Strings(userNames)
+	return userNames
+}
+
+// isExcluded returns whether a user is listed in exclude-users, such as
+// emergency admin or smoke test accounts that cf-mgmt never removes.
+func isExcluded(excludeUsers []string, userName string) bool {
+	for _, excludeUser := range excludeUsers {
+		if strings.EqualFold(excludeUser, userName) {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *DefaultManager) InitializeLdap(ldapBindPassword string) error {
 	ldapConfig, err := m.Cfg.LdapConfig(ldapBindPassword)
 	if err != nil {
 		return err
 	}
 	m.LdapConfig = ldapConfig
+	m.ldapSkipped = m.SkipLdap && m.LdapConfig.Enabled
+	if m.ldapSkipped {
+		lo.G.Warning("Not binding to ldap, the ldap users of roles are left as they are")
+		m.LdapConfig.Enabled = false
+	}
 	if m.LdapConfig.Enabled {
 		ldapMgr, err := ldap.NewManager(ldapConfig)
 		if err != nil {
 			return err
 		}
-		m.LdapMgr = ldapMgr
+		cachingMgr := ldap.NewCachingManager(ldapMgr)
+		cachingMgr.Disk = m.DiskCache
+		m.LdapMgr = cachingMgr
 	}
 	return nil
 }
 
+// groupResolver looks up the members of many ldap groups at once
+type groupResolver interface {
+	ResolveGroups(groupNames []string, concurrency int) error
+}
+
+// resolveLdapGroups looks up every distinct ldap group of the configuration
+// in parallel up front, rather than one at a time as each role is synced.
+func (m *DefaultManager) resolveLdapGroups(groupNames []string) error {
+	if m.LdapConfig == nil || !m.LdapConfig.Enabled || len(groupNames) == 0 {
+		return nil
+	}
+	resolver, ok := m.LdapMgr.(groupResolver)
+	if !ok {
+		return nil
+	}
+	return resolver.ResolveGroups(groupNames, m.LdapConfig.GroupLookupConcurrency)
+}
+
 func (m *DefaultManager) DeinitializeLdap() error {
 	if m.LdapMgr != nil {
 		m.LdapMgr.Close()
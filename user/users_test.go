@@ -9,6 +9,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/pivotalservices/cf-mgmt/config"
 	configfakes "github.com/pivotalservices/cf-mgmt/config/fakes"
+	"github.com/pivotalservices/cf-mgmt/dryrun"
 	ldap "github.com/pivotalservices/cf-mgmt/ldap"
 	ldapfakes "github.com/pivotalservices/cf-mgmt/ldap/fakes"
 	orgfakes "github.com/pivotalservices/cf-mgmt/organization/fakes"
@@ -372,6 +373,102 @@ var _ = Describe("given UserSpaces", func() {
 			})
 		})
 
+		Context("SyncClients", func() {
+			It("Should add client to role", func() {
+				roleUsers := make(map[string]string)
+				updateUsersInput := UpdateUsersInput{
+					Clients:   []string{"deployer"},
+					SpaceGUID: "space_guid",
+					OrgGUID:   "org_guid",
+					Role:      "developer",
+					AddClient: userManager.AssociateClient,
+				}
+				err := userManager.SyncClients(roleUsers, updateUsersInput)
+				Expect(err).ShouldNot(HaveOccurred())
+				orgGUID, clientID := client.AssociateOrgUserArgsForCall(0)
+				Expect(orgGUID).Should(Equal("org_guid"))
+				Expect(clientID).Should(Equal("deployer"))
+
+				spaceGUID, clientID := client.AssociateSpaceDeveloperArgsForCall(0)
+				Expect(spaceGUID).Should(Equal("space_guid"))
+				Expect(clientID).Should(Equal("deployer"))
+			})
+
+			It("Should not add existing client to role", func() {
+				roleUsers := make(map[string]string)
+				roleUsers["deployer"] = "deployer"
+				updateUsersInput := UpdateUsersInput{
+					Clients:   []string{"deployer"},
+					SpaceGUID: "space_guid",
+					OrgGUID:   "org_guid",
+					Role:      "developer",
+					AddClient: userManager.AssociateClient,
+				}
+				err := userManager.SyncClients(roleUsers, updateUsersInput)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(roleUsers).ShouldNot(HaveKey("deployer"))
+				Expect(client.AssociateOrgUserCallCount()).Should(Equal(0))
+				Expect(client.AssociateSpaceDeveloperCallCount()).Should(Equal(0))
+			})
+
+			It("Should return error", func() {
+				roleUsers := make(map[string]string)
+				updateUsersInput := UpdateUsersInput{
+					Clients:   []string{"deployer"},
+					SpaceGUID: "space_guid",
+					OrgGUID:   "org_guid",
+					Role:      "developer",
+					AddClient: userManager.AssociateClient,
+				}
+				client.AssociateOrgUserReturns(cfclient.Org{}, errors.New("error"))
+				err := userManager.SyncClients(roleUsers, updateUsersInput)
+				Expect(err).Should(HaveOccurred())
+				Expect(client.AssociateOrgUserCallCount()).Should(Equal(1))
+				Expect(client.AssociateSpaceDeveloperCallCount()).Should(Equal(0))
+			})
+
+			It("Should add client to org role", func() {
+				roleUsers := make(map[string]string)
+				updateUsersInput := UpdateUsersInput{
+					Clients:   []string{"deployer"},
+					OrgGUID:   "org_guid",
+					Role:      "billing manager",
+					AddClient: userManager.AssociateClient,
+				}
+				err := userManager.SyncClients(roleUsers, updateUsersInput)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(client.AssociateOrgUserCallCount()).Should(Equal(1))
+				orgGUID, clientID := client.AssociateOrgBillingManagerArgsForCall(0)
+				Expect(orgGUID).Should(Equal("org_guid"))
+				Expect(clientID).Should(Equal("deployer"))
+			})
+
+			It("Should return error for a role clients cannot be granted", func() {
+				roleUsers := make(map[string]string)
+				updateUsersInput := UpdateUsersInput{
+					Clients:   []string{"deployer"},
+					SpaceGUID: "space_guid",
+					OrgGUID:   "org_guid",
+					Role:      "billing manager",
+					AddClient: userManager.AssociateClient,
+				}
+				err := userManager.SyncClients(roleUsers, updateUsersInput)
+				Expect(err).Should(MatchError("clients cannot be granted space role billing manager"))
+				Expect(client.AssociateOrgUserCallCount()).Should(Equal(0))
+			})
+
+			It("Should key clients listed in a role by guid", func() {
+				client.ListSpaceDevelopersReturns([]cfclient.User{
+					cfclient.User{Username: "hello", Guid: "world"},
+					cfclient.User{Guid: "Deployer"},
+				}, nil)
+				users, err := userManager.ListSpaceDevelopers("foo")
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(users).Should(HaveKeyWithValue("hello", "world"))
+				Expect(users).Should(HaveKeyWithValue("deployer", "Deployer"))
+			})
+		})
+
 		Context("Remove Users", func() {
 			It("Should remove users", func() {
 				roleUsers := make(map[string]string)
@@ -392,6 +489,51 @@ var _ = Describe("given UserSpaces", func() {
 				Expect(userName).Should(Equal("test"))
 			})
 
+			It("Should remove users in order", func() {
+				roleUsers := make(map[string]string)
+				for _, userName := range []string{"zed", "amy", "mia", "bob", "kim"} {
+					roleUsers[userName] = userName
+				}
+				updateUsersInput := UpdateUsersInput{
+					RemoveUsers: true,
+					SpaceGUID:   "space_guid",
+					OrgGUID:     "org_guid",
+					RemoveUser:  userManager.RemoveSpaceAuditor,
+				}
+
+				err := userManager.RemoveUsers(roleUsers, updateUsersInput)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(client.RemoveSpaceAuditorByUsernameCallCount()).Should(Equal(5))
+				var removed []string
+				for i := 0; i < 5; i++ {
+					_, userName := client.RemoveSpaceAuditorByUsernameArgsForCall(i)
+					removed = append(removed, userName)
+				}
+				Expect(removed).Should(Equal([]string{"amy", "bob", "kim", "mia", "zed"}))
+			})
+
+			It("Should remove clients by guid", func() {
+				roleUsers := make(map[string]string)
+				roleUsers["deployer"] = "Deployer"
+				updateUsersInput := UpdateUsersInput{
+					RemoveUsers:  true,
+					SpaceGUID:    "space_guid",
+					OrgGUID:      "org_guid",
+					Role:         "auditor",
+					RemoveUser:   userManager.RemoveSpaceAuditor,
+					RemoveClient: userManager.RemoveClient,
+				}
+
+				err := userManager.RemoveUsers(roleUsers, updateUsersInput)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(client.RemoveSpaceAuditorByUsernameCallCount()).Should(Equal(0))
+				Expect(client.RemoveSpaceAuditorCallCount()).Should(Equal(1))
+
+				spaceGUID, clientID := client.RemoveSpaceAuditorArgsForCall(0)
+				Expect(spaceGUID).Should(Equal("space_guid"))
+				Expect(clientID).Should(Equal("Deployer"))
+			})
+
 			It("Should not remove users", func() {
 				roleUsers := make(map[string]string)
 				roleUsers["test"] = "test"
@@ -407,6 +549,41 @@ var _ = Describe("given UserSpaces", func() {
 				Expect(client.RemoveSpaceAuditorByUsernameCallCount()).Should(Equal(0))
 			})
 
+			It("Should not remove excluded users", func() {
+				roleUsers := make(map[string]string)
+				roleUsers["test"] = "test"
+				roleUsers["break-glass-admin"] = "admin-guid"
+				updateUsersInput := UpdateUsersInput{
+					RemoveUsers:  true,
+					ExcludeUsers: []string{"Break-Glass-Admin"},
+					SpaceGUID:    "space_guid",
+					OrgGUID:      "org_guid",
+					RemoveUser:   userManager.RemoveSpaceAuditor,
+				}
+
+				err := userManager.RemoveUsers(roleUsers, updateUsersInput)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(client.RemoveSpaceAuditorByUsernameCallCount()).Should(Equal(1))
+				_, userName := client.RemoveSpaceAuditorByUsernameArgsForCall(0)
+				Expect(userName).Should(Equal("test"))
+			})
+
+			It("Should defer removing users outside the maintenance window", func() {
+				roleUsers := make(map[string]string)
+				roleUsers["test"] = "test"
+				updateUsersInput := UpdateUsersInput{
+					RemoveUsers:     true,
+					RemovalDeferred: true,
+					SpaceGUID:       "space_guid",
+					OrgGUID:         "org_guid",
+					RemoveUser:      userManager.RemoveSpaceAuditor,
+				}
+
+				err := userManager.RemoveUsers(roleUsers, updateUsersInput)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(client.RemoveSpaceAuditorByUsernameCallCount()).Should(Equal(0))
+			})
+
 			It("Should return error", func() {
 				roleUsers := make(map[string]string)
 				roleUsers["test"] = "test"
@@ -982,6 +1159,177 @@ var _ = Describe("given UserSpaces", func() {
 				err := userManager.UpdateOrgUsers()
 				Expect(err).ShouldNot(HaveOccurred())
 			})
+
+			It("Should share the uaa users with the next update while the run cache is started", func() {
+				uaaFake.ListUsersReturns(make(map[string]*uaaclient.User), nil)
+				fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+					config.OrgConfig{Org: "test-org", Manager: config.UserMgmt{SamlUsers: []string{"test@test.com"}}},
+				}, nil)
+				fakeReader.GetSpaceConfigsReturns([]config.SpaceConfig{
+					config.SpaceConfig{Org: "test-org", Space: "test-space", Developer: config.UserMgmt{SamlUsers: []string{"test@test.com"}}},
+				}, nil)
+				orgFake.FindOrgReturns(cfclient.Org{Name: "test-org", Guid: "test-org-guid"}, nil)
+				spaceFake.FindSpaceReturns(cfclient.Space{Name: "test-space", OrganizationGuid: "test-org-guid", Guid: "test-space-guid"}, nil)
+				userManager.LdapConfig = &config.LdapConfig{Origin: "saml_origin"}
+				userManager.StartRunCache()
+				defer userManager.StopRunCache()
+				Expect(userManager.UpdateOrgUsers()).Should(Succeed())
+				Expect(userManager.UpdateSpaceUsers()).Should(Succeed())
+				Expect(uaaFake.ListUsersCallCount()).Should(Equal(1))
+				Expect(uaaFake.CreateExternalUserCallCount()).Should(Equal(1))
+			})
+
+			It("Should fail after updating every org when saml users cannot be created", func() {
+				uaaFake.ListUsersReturns(make(map[string]*uaaclient.User), nil)
+				uaaFake.CreateExternalUserReturns(errors.New("error"))
+				fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+					config.OrgConfig{Org: "test-org", Manager: config.UserMgmt{SamlUsers: []string{"test@test.com"}}},
+					config.OrgConfig{Org: "other-org", Auditor: config.UserMgmt{SamlUsers: []string{"other@test.com"}}},
+				}, nil)
+				fakeReader.GetGlobalConfigReturns(&config.GlobalConfig{FailOnUserCreationErrors: true}, nil)
+				orgFake.FindOrgReturns(cfclient.Org{Name: "test-org", Guid: "test-org-guid"}, nil)
+				userManager.LdapConfig = &config.LdapConfig{Origin: "saml_origin"}
+				err := userManager.UpdateOrgUsers()
+				Expect(err).Should(MatchError("unable to create 2 users: test@test.com, other@test.com"))
+				Expect(orgFake.FindOrgCallCount()).Should(Equal(2))
+			})
+
+			It("Should not fail when saml users cannot be created without fail-on-user-creation-errors", func() {
+				uaaFake.ListUsersReturns(make(map[string]*uaaclient.User), nil)
+				uaaFake.CreateExternalUserReturns(errors.New("error"))
+				fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+					config.OrgConfig{Org: "test-org", Manager: config.UserMgmt{SamlUsers: []string{"test@test.com"}}},
+				}, nil)
+				orgFake.FindOrgReturns(cfclient.Org{Name: "test-org", Guid: "test-org-guid"}, nil)
+				userManager.LdapConfig = &config.LdapConfig{Origin: "saml_origin"}
+				err := userManager.UpdateOrgUsers()
+				Expect(err).ShouldNot(HaveOccurred())
+			})
+
+			It("Should not remove users without an approval", func() {
+				uaaFake.ListUsersReturns(make(map[string]*uaaclient.User), nil)
+				fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+					config.OrgConfig{
+						Org:         "test-org",
+						RemoveUsers: true,
+					},
+				}, nil)
+				fakeReader.GetApprovalsReturns(&config.Approvals{
+					RequireApprovals: true,
+					Approvals: []config.Approval{
+						config.Approval{Ticket: "CHG-1", Approver: "ops", Change: config.ChangeRemoveUser, Org: "test-org", User: "hello"},
+					},
+				}, nil)
+				orgFake.FindOrgReturns(cfclient.Org{
+					Name: "test-org",
+					Guid: "test-org-guid",
+				}, nil)
+				client.ListOrgBillingManagersReturns(userList, nil)
+				userManager.LdapConfig = &config.LdapConfig{Enabled: false}
+				err := userManager.UpdateOrgUsers()
+				Expect(err).Should(MatchError("Error syncing users for org test-org role billing_managers: approvals.yml has no approval for: remove-user [hello2] in test-org"))
+				Expect(client.RemoveOrgBillingManagerByUsernameCallCount()).Should(Equal(0))
+			})
+		})
+
+		Context("CleanupOrgUsers", func() {
+			BeforeEach(func() {
+				fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+					config.OrgConfig{Org: "test-org"},
+				}, nil)
+				fakeReader.GetApprovalsReturns(&config.Approvals{
+					RequireApprovals: true,
+					Approvals: []config.Approval{
+						config.Approval{Ticket: "CHG-1", Approver: "ops", Change: config.ChangeRemoveUser, Org: "test-org", User: "hello"},
+					},
+				}, nil)
+				orgFake.FindOrgReturns(cfclient.Org{Name: "test-org", Guid: "test-org-guid"}, nil)
+			})
+
+			It("Should remove approved users without a role", func() {
+				client.ListOrgUsersReturns([]cfclient.User{{Username: "hello", Guid: "hello-guid"}}, nil)
+				err := userManager.CleanupOrgUsers()
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(client.RemoveOrgUserByUsernameCallCount()).Should(Equal(1))
+			})
+
+			It("Should not remove users without an approval", func() {
+				client.ListOrgUsersReturns([]cfclient.User{{Username: "hello", Guid: "hello-guid"}, {Username: "hello2", Guid: "hello2-guid"}}, nil)
+				err := userManager.CleanupOrgUsers()
+				Expect(err).Should(MatchError("approvals.yml has no approval for: remove-user [hello2] in test-org"))
+				Expect(client.RemoveOrgUserByUsernameCallCount()).Should(Equal(0))
+			})
+
+			It("Should defer removing users outside the maintenance window of the org", func() {
+				fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+					config.OrgConfig{Org: "test-org", MaintenanceWindow: "0 2 30 feb *"},
+				}, nil)
+				client.ListOrgUsersReturns([]cfclient.User{{Username: "hello", Guid: "hello-guid"}, {Username: "hello2", Guid: "hello2-guid"}}, nil)
+				err := userManager.CleanupOrgUsers()
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(client.RemoveOrgUserByUsernameCallCount()).Should(Equal(0))
+			})
+
+			It("Should fail a dry run of removing users without an approval", func() {
+				userManager.Peek = true
+				client.ListOrgUsersReturns([]cfclient.User{{Username: "hello2", Guid: "hello2-guid"}}, nil)
+				err := userManager.CleanupOrgUsers()
+				Expect(err).Should(MatchError("approvals.yml has no approval for: remove-user [hello2] in test-org"))
+			})
+		})
+
+		Context("UpdateGroupUsers", func() {
+			It("Should only sync the roles granted to the group", func() {
+				uaaFake.ListUsersReturns(make(map[string]*uaaclient.User), nil)
+				fakeReader.GetOrgConfigsReturns([]config.OrgConfig{
+					config.OrgConfig{Org: "org1", Manager: config.UserMgmt{LDAPGroups: []string{"devs"}}},
+					config.OrgConfig{Org: "org2", Auditor: config.UserMgmt{LDAPGroups: []string{"auditors"}}},
+				}, nil)
+				fakeReader.GetSpaceConfigsReturns([]config.SpaceConfig{
+					config.SpaceConfig{Org: "org2", Space: "space1", Developer: config.UserMgmt{LDAPGroups: []string{"DEVS"}}},
+					config.SpaceConfig{Org: "org2", Space: "space2", Manager: config.UserMgmt{LDAPGroups: []string{"auditors"}}},
+				}, nil)
+				orgFake.FindOrgReturns(cfclient.Org{Name: "org1", Guid: "org1-guid"}, nil)
+				spaceFake.FindSpaceReturns(cfclient.Space{Name: "space1", OrganizationGuid: "org2-guid", Guid: "space1-guid"}, nil)
+				userManager.LdapConfig = &config.LdapConfig{Enabled: false}
+				Expect(userManager.UpdateGroupUsers("devs")).Should(Succeed())
+				Expect(orgFake.FindOrgCallCount()).Should(Equal(1))
+				Expect(orgFake.FindOrgArgsForCall(0)).Should(Equal("org1"))
+				Expect(client.ListOrgManagersCallCount()).Should(Equal(1))
+				Expect(client.ListOrgAuditorsCallCount()).Should(Equal(0))
+				Expect(client.ListOrgBillingManagersCallCount()).Should(Equal(0))
+				Expect(spaceFake.FindSpaceCallCount()).Should(Equal(1))
+				org, space := spaceFake.FindSpaceArgsForCall(0)
+				Expect(org).Should(Equal("org2"))
+				Expect(space).Should(Equal("space1"))
+				Expect(client.ListSpaceDevelopersCallCount()).Should(Equal(1))
+				Expect(client.ListSpaceManagersCallCount()).Should(Equal(0))
+				Expect(client.ListSpaceAuditorsCallCount()).Should(Equal(0))
+			})
+		})
+
+		Context("Dry run", func() {
+			It("Should record the role changes in the plan of the dry run", func() {
+				uaaFake.ListUsersReturns(map[string]*uaaclient.User{
+					"jdoe": &uaaclient.User{Username: "jdoe", Origin: "uaa"},
+				}, nil)
+				fakeReader.GetSpaceConfigsReturns([]config.SpaceConfig{
+					config.SpaceConfig{Org: "org1", Space: "dev", RemoveUsers: true, Developer: config.UserMgmt{Users: []string{"jdoe"}}},
+				}, nil)
+				spaceFake.FindSpaceReturns(cfclient.Space{Name: "dev", OrganizationGuid: "org1-guid", Guid: "dev-guid"}, nil)
+				client.ListSpaceDevelopersReturns([]cfclient.User{cfclient.User{Username: "asmith", Guid: "asmith-guid"}}, nil)
+				userManager.LdapConfig = &config.LdapConfig{Enabled: false}
+				userManager.Peek = true
+				dryrun.Start()
+				defer dryrun.Stop()
+				Expect(userManager.UpdateSpaceUsers()).Should(Succeed())
+				Expect(client.AssociateSpaceDeveloperByUsernameCallCount()).Should(Equal(0))
+				Expect(client.RemoveSpaceDeveloperByUsernameCallCount()).Should(Equal(0))
+				Expect(dryrun.Stop()).Should(Equal([]dryrun.Change{
+					{Action: dryrun.Delete, Kind: dryrun.SpaceRole, Name: "asmith as developer of org1/dev"},
+					{Action: dryrun.Create, Kind: dryrun.SpaceRole, Name: "jdoe as developer of org1/dev"},
+				}))
+			})
 		})
 	})
 })
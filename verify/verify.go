@@ -0,0 +1,238 @@
+// Package verify spot-checks a foundation after reconciliation, asserting
+// that a sample of users can reach their spaces and that spaces have ssh
+// access as expected, so pipelines can fail a run that applied cleanly but
+// did not produce the intended access.
+package verify
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pivotalservices/cf-mgmt/config"
+	"github.com/pivotalservices/cf-mgmt/organization"
+	"github.com/pivotalservices/cf-mgmt/space"
+	"github.com/pivotalservices/cf-mgmt/user"
+)
+
+//Checks - the assertions of verify.yml
+type Checks struct {
+	SpaceAccess []SpaceAccess `yaml:"space-access"`
+	SSH         []SSH         `yaml:"ssh"`
+}
+
+//SpaceAccess - asserts that a user can, or with denied cannot, list a space.
+//A user can list a space with any role in it or as a manager of its org, or
+//only with role when it is set.
+type SpaceAccess struct {
+	User   string `yaml:"user"`
+	Org    string `yaml:"org"`
+	Space  string `yaml:"space"`
+	Role   string `yaml:"role,omitempty"`
+	Denied bool   `yaml:"denied,omitempty"`
+}
+
+//SSH - asserts whether ssh is allowed in the spaces matching the org and
+//space glob patterns
+type SSH struct {
+	Org     string `yaml:"org"`
+	Space   string `yaml:"space"`
+	Allowed bool   `yaml:"allowed"`
+}
+
+//Result - the outcome of one assertion
+type Result struct {
+	Name string
+	Err  error
+}
+
+//Results - the outcome of every assertion
+type Results []Result
+
+//LoadChecks - reads the assertions from a yaml file
+func LoadChecks(path string) (*Checks, error) {
+	checks := &Checks{}
+	if err := config.LoadFile(path, checks); err != nil {
+		return nil, fmt.Errorf("unable to read %s: %s", path, err.Error())
+	}
+	for _, access := range checks.SpaceAccess {
+		if access.User == "" || access.Org == "" || access.Space == "" {
+			return nil, fmt.Errorf("space-access in %s requires user, org and space", path)
+		}
+		switch access.Role {
+		case "", config.RoleSpaceDeveloper, config.RoleSpaceManager, config.RoleSpaceAuditor, config.RoleOrgManager:
+		default:
+			return nil, fmt.Errorf("role [%s] of space-access in %s is not one of %s, %s, %s or %s", access.Role, path,
+				config.RoleSpaceDeveloper, config.RoleSpaceManager, config.RoleSpaceAuditor, config.RoleOrgManager)
+		}
+	}
+	for _, ssh := range checks.SSH {
+		if ssh.Org == "" || ssh.Space == "" {
+			return nil, fmt.Errorf("ssh in %s requires org and space", path)
+		}
+	}
+	return checks, nil
+}
+
+//Verifier - runs the assertions against the foundation
+type Verifier struct {
+	OrgMgr   organization.Manager
+	SpaceMgr space.Manager
+	UserMgr  user.Manager
+}
+
+//Run - runs every assertion, continuing past failures so that all of them are reported
+func (v *Verifier) Run(checks *Checks) Results {
+	var results Results
+	for _, access := range checks.SpaceAccess {
+		results = append(results, Result{Name: access.String(), Err: v.verifySpaceAccess(access)})
+	}
+	for _, ssh := range checks.SSH {
+		results = append(results, Result{Name: ssh.String(), Err: v.verifySSH(ssh)})
+	}
+	return results
+}
+
+func (a SpaceAccess) String() string {
+	verb := "can"
+	if a.Denied {
+		verb = "cannot"
+	}
+	if a.Role != "" {
+		return fmt.Sprintf("%s %s be %s of %s/%s", a.User, verb, a.Role, a.Org, a.Space)
+	}
+	return fmt.Sprintf("%s %s list %s/%s", a.User, verb, a.Org, a.Space)
+}
+
+func (s SSH) String() string {
+	verb := "allowed"
+	if !s.Allowed {
+		verb = "disabled"
+	}
+	return fmt.Sprintf("ssh is %s in %s/%s", verb, s.Org, s.Space)
+}
+
+func (v *Verifier) verifySpaceAccess(access SpaceAccess) error {
+	theSpace, err := v.SpaceMgr.FindSpace(access.Org, access.Space)
+	if err != nil {
+		return err
+	}
+	roles, err := v.roles(access, theSpace.Guid, theSpace.OrganizationGuid)
+	if err != nil {
+		return err
+	}
+	if access.Denied && len(roles) > 0 {
+		return fmt.Errorf("%s holds %s", access.User, strings.Join(roles, ", "))
+	}
+	if !access.Denied && len(roles) == 0 {
+		if access.Role != "" {
+			return fmt.Errorf("%s is not %s", access.User, access.Role)
+		}
+		return fmt.Errorf("%s has no role in the space and is not an org manager", access.User)
+	}
+	return nil
+}
+
+// roles lists the roles of the user that let it list the space, only role when it is set
+func (v *Verifier) roles(access SpaceAccess, spaceGUID, orgGUID string) ([]string, error) {
+	listers := []struct {
+		role string
+		list func(guid string) (map[string]string, error)
+		guid string
+	}{
+		{config.RoleSpaceDeveloper, v.UserMgr.ListSpaceDevelopers, spaceGUID},
+		{config.RoleSpaceManager, v.UserMgr.ListSpaceManagers, spaceGUID},
+		{config.RoleSpaceAuditor, v.UserMgr.ListSpaceAuditors, spaceGUID},
+		{config.RoleOrgManager, v.UserMgr.ListOrgManagers, orgGUID},
+	}
+	var roles []string
+	for _, lister := range listers {
+		if access.Role != "" && access.Role != lister.role {
+			continue
+		}
+		users, err := lister.list(lister.guid)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := users[strings.ToLower(access.User)]; ok {
+			roles = append(roles, lister.role)
+		}
+	}
+	return roles, nil
+}
+
+func (v *Verifier) verifySSH(ssh SSH) error {
+	orgs, err := v.OrgMgr.ListOrgs()
+	if err != nil {
+		return err
+	}
+	matched := 0
+	var wrong []string
+	for _, org := range orgs {
+		if ok, _ := path.Match(ssh.Org, org.Name); !ok {
+			continue
+		}
+		spaces, err := v.SpaceMgr.ListSpaces(org.Guid)
+		if err != nil {
+			return err
+		}
+		for _, theSpace := range spaces {
+			if ok, _ := path.Match(ssh.Space, theSpace.Name); !ok {
+				continue
+			}
+			matched++
+			if theSpace.AllowSSH != ssh.Allowed {
+				wrong = append(wrong, fmt.Sprintf("%s/%s", org.Name, theSpace.Name))
+			}
+		}
+	}
+	if matched == 0 {
+		return fmt.Errorf("no spaces match %s/%s", ssh.Org, ssh.Space)
+	}
+	if len(wrong) > 0 {
+		verb := "disabled"
+		if !ssh.Allowed {
+			verb = "allowed"
+		}
+		return fmt.Errorf("ssh is %s in %s", verb, strings.Join(wrong, ", "))
+	}
+	return nil
+}
+
+//Failed - whether any assertion failed
+func (r Results) Failed() bool {
+	for _, result := range r {
+		if result.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+//Error - an error counting the failed assertions, nil when all passed
+func (r Results) Error() error {
+	failed := 0
+	for _, result := range r {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	return fmt.Errorf("verify failed: %d of %d checks failed", failed, len(r))
+}
+
+//String - formats the results one line per assertion
+func (r Results) String() string {
+	var buffer bytes.Buffer
+	for _, result := range r {
+		if result.Err != nil {
+			fmt.Fprintf(&buffer, "FAIL %s: %s\n", result.Name, result.Err)
+		} else {
+			fmt.Fprintf(&buffer, "OK   %s\n", result.Name)
+		}
+	}
+	return buffer.String()
+}
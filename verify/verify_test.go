@@ -0,0 +1,124 @@
+package verify_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	uaaclient "github.com/cloudfoundry-community/go-uaa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/cfmgmt"
+	"github.com/pivotalservices/cf-mgmt/simulator"
+	"github.com/pivotalservices/cf-mgmt/verify"
+)
+
+var _ = Describe("given a verifier", func() {
+	var verifier *verify.Verifier
+
+	BeforeEach(func() {
+		foundation, err := simulator.NewFoundation(&simulator.Snapshot{
+			Orgs: []cfclient.Org{{Guid: "org-guid", Name: "payments"}},
+			Spaces: []cfclient.Space{
+				{Guid: "dev-guid", Name: "dev", OrganizationGuid: "org-guid", AllowSSH: true},
+				{Guid: "prod-guid", Name: "prod", OrganizationGuid: "org-guid"},
+				{Guid: "prod-eu-guid", Name: "prod-eu", OrganizationGuid: "org-guid", AllowSSH: true},
+			},
+			Users: []cfclient.User{
+				{Guid: "alice-guid", Username: "alice"},
+				{Guid: "bob-guid", Username: "bob"},
+			},
+			OrgRoles: map[string]simulator.Roles{
+				"org-guid": {simulator.RoleUsers: {"alice-guid", "bob-guid"}, simulator.RoleManagers: {"bob-guid"}},
+			},
+			SpaceRoles: map[string]simulator.Roles{
+				"dev-guid": {simulator.RoleDevelopers: {"alice-guid"}},
+			},
+			UAAUsers: []uaaclient.User{
+				{ID: "alice-guid", Username: "alice", Origin: "uaa"},
+				{ID: "bob-guid", Username: "bob", Origin: "uaa"},
+			},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		mgmt, err := cfmgmt.NewWithClient(cfmgmt.Config{ConfigDirectory: "./fixtures/missing"}, foundation, foundation.UAAManager(false))
+		Expect(err).ShouldNot(HaveOccurred())
+		verifier = &verify.Verifier{OrgMgr: mgmt.OrgManager, SpaceMgr: mgmt.SpaceManager, UserMgr: mgmt.UserManager}
+	})
+
+	It("passes the checks the foundation meets", func() {
+		results := verifier.Run(&verify.Checks{
+			SpaceAccess: []verify.SpaceAccess{
+				{User: "Alice", Org: "payments", Space: "dev", Role: "space-developer"},
+				{User: "bob", Org: "payments", Space: "prod"},
+				{User: "alice", Org: "payments", Space: "prod", Denied: true},
+			},
+			SSH: []verify.SSH{
+				{Org: "payments", Space: "dev", Allowed: true},
+			},
+		})
+		Expect(results.Error()).ShouldNot(HaveOccurred())
+		Expect(results.String()).Should(ContainSubstring("OK   alice cannot list payments/prod"))
+	})
+
+	It("reports every check the foundation does not meet", func() {
+		results := verifier.Run(&verify.Checks{
+			SpaceAccess: []verify.SpaceAccess{
+				{User: "alice", Org: "payments", Space: "prod"},
+				{User: "bob", Org: "payments", Space: "dev", Role: "space-manager"},
+				{User: "alice", Org: "payments", Space: "missing"},
+			},
+			SSH: []verify.SSH{
+				{Org: "*", Space: "prod*"},
+				{Org: "payments", Space: "staging"},
+			},
+		})
+		Expect(results.Failed()).Should(BeTrue())
+		Expect(results.Error()).Should(MatchError("verify failed: 5 of 5 checks failed"))
+		Expect(results[0].Err).Should(MatchError("alice has no role in the space and is not an org manager"))
+		Expect(results[1].Err).Should(MatchError("bob is not space-manager"))
+		Expect(results[3].Err).Should(MatchError("ssh is allowed in payments/prod-eu"))
+		Expect(results[4].Err).Should(MatchError("no spaces match payments/staging"))
+	})
+
+	Context("LoadChecks", func() {
+		var tempDir string
+		BeforeEach(func() {
+			var err error
+			tempDir, err = ioutil.TempDir("", "verify")
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+		AfterEach(func() {
+			os.RemoveAll(tempDir)
+		})
+
+		It("reads the checks", func() {
+			file := path.Join(tempDir, "verify.yml")
+			Expect(ioutil.WriteFile(file, []byte(`space-access:
+- user: alice
+  org: payments
+  space: dev
+ssh:
+- org: "*"
+  space: prod*
+  allowed: false
+`), 0644)).Should(Succeed())
+			checks, err := verify.LoadChecks(file)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(checks.SpaceAccess).Should(HaveLen(1))
+			Expect(checks.SSH[0].Space).Should(Equal("prod*"))
+		})
+
+		It("errors for an unknown role", func() {
+			file := path.Join(tempDir, "verify.yml")
+			Expect(ioutil.WriteFile(file, []byte(`space-access:
+- user: alice
+  org: payments
+  space: dev
+  role: admin
+`), 0644)).Should(Succeed())
+			_, err := verify.LoadChecks(file)
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,106 @@
+package watch
+
+import (
+	"context"
+	"time"
+
+	"github.com/xchapter7x/lo"
+)
+
+// Lease is held by the one replica of the controller that reconciles, such
+// as the lock on the leader group. It expires unless renewed.
+type Lease interface {
+	Acquire() error
+	Renew() error
+	Release() error
+}
+
+type leaseLostKey struct{}
+
+//LeaseLost - closed once the lease of the replica reconciling with ctx is lost, so that the requests in flight of
+//its apply can be aborted rather than only stopping after them, nil without a lease
+func LeaseLost(ctx context.Context) <-chan struct{} {
+	lost, _ := ctx.Value(leaseLostKey{}).(chan struct{})
+	return lost
+}
+
+// lead is whether this replica holds the lease, renewing it or acquiring it
+// when another replica let it expire
+func (c *Controller) lead() bool {
+	if c.Lease == nil {
+		return true
+	}
+	c.mutex.RLock()
+	leading := c.leading
+	c.mutex.RUnlock()
+	if leading {
+		err := c.Lease.Renew()
+		if err == nil {
+			return true
+		}
+		lo.G.Warningf("lost the lease, standing by: %s", err)
+	}
+	err := c.Lease.Acquire()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.leading = err == nil
+	if err != nil {
+		c.standby = err.Error()
+		lo.G.Debugf("standing by: %s", err)
+		return false
+	}
+	c.standby = ""
+	if !leading {
+		lo.G.Notice("acquired the lease, reconciling as the leader")
+	}
+	return true
+}
+
+// reconcileAsLeader renews the lease while the reconciliation runs,
+// cancelling it when the lease is lost so two replicas never apply at once
+func (c *Controller) reconcileAsLeader(ctx context.Context) {
+	if c.Lease == nil {
+		c.Reconcile(ctx)
+		return
+	}
+	lost := make(chan struct{})
+	leaseCtx, cancel := context.WithCancel(context.WithValue(ctx, leaseLostKey{}, lost))
+	done := make(chan struct{})
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(c.RenewInterval):
+			}
+			if err := c.Lease.Renew(); err != nil {
+				lo.G.Errorf("lost the lease, stopping the reconciliation: %s", err)
+				c.mutex.Lock()
+				c.leading = false
+				c.mutex.Unlock()
+				close(lost)
+				cancel()
+				return
+			}
+		}
+	}()
+	c.Reconcile(leaseCtx)
+	close(done)
+	<-renewed
+	cancel()
+}
+
+// resign releases the lease so that another replica takes over right away
+func (c *Controller) resign() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.Lease == nil || !c.leading {
+		return
+	}
+	c.leading = false
+	if err := c.Lease.Release(); err != nil {
+		lo.G.Error(err)
+	}
+}
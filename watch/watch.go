@@ -0,0 +1,182 @@
+// Package watch runs cf-mgmt as a long running controller that reconciles
+// the foundation with the configuration on an interval, instead of relying
+// on a pipeline to run it.
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/xchapter7x/lo"
+)
+
+// Run is the result of a reconciliation: the changes the drift detection
+// found, and whether they were applied.
+type Run struct {
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+	Drift    int       `json:"drift"`
+	Applied  bool      `json:"applied"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Controller detects drift and applies the configuration every Interval.
+type Controller struct {
+	Interval time.Duration
+	// DetectDrift returns the number of changes an apply would make
+	DetectDrift func(ctx context.Context) (int, error)
+	// Apply reconciles the foundation with the configuration
+	Apply func(ctx context.Context) error
+	Now   func() time.Time
+	// Lease, when set, makes only the replica holding it reconcile, renewing
+	// it every RenewInterval while it does
+	Lease         Lease
+	RenewInterval time.Duration
+
+	mutex          sync.RWMutex
+	last           *Run
+	lastSuccessful *Run
+	reconciling    *time.Time
+	next           time.Time
+	leading        bool
+	standby        string
+}
+
+// Status is what the controller is doing, served on /status.
+type Status struct {
+	// Reconciling is when the current reconciliation started, if one is running
+	Reconciling       *time.Time `json:"reconciling,omitempty"`
+	LastRun           *Run       `json:"last_run"`
+	LastSuccessfulRun *Run       `json:"last_successful_run"`
+	NextRun           *time.Time `json:"next_run,omitempty"`
+	// Standby is why this replica does not reconcile, such as the lease being
+	// held by another replica
+	Standby string `json:"standby,omitempty"`
+}
+
+//Run - reconciles right away and then every interval until ctx is cancelled
+func (c *Controller) Run(ctx context.Context) error {
+	if c.Interval <= 0 {
+		return fmt.Errorf("interval must be positive, not %s", c.Interval)
+	}
+	defer c.resign()
+	for {
+		if c.lead() {
+			c.reconcileAsLeader(ctx)
+		}
+		c.mutex.Lock()
+		c.next = c.now().Add(c.Interval)
+		c.mutex.Unlock()
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(c.Interval):
+		}
+	}
+}
+
+//Reconcile - detects drift and applies the configuration when there is any
+func (c *Controller) Reconcile(ctx context.Context) Run {
+	run := Run{Started: c.now()}
+	c.mutex.Lock()
+	c.reconciling = &run.Started
+	c.mutex.Unlock()
+	drift, err := c.DetectDrift(ctx)
+	if err == nil {
+		run.Drift = drift
+		if drift == 0 {
+			lo.G.Debug("no drift detected, nothing to apply")
+		} else {
+			lo.G.Infof("applying %d changes of drift", drift)
+			err = c.Apply(ctx)
+			run.Applied = err == nil
+		}
+	}
+	if err != nil {
+		lo.G.Errorf("reconciliation failed: %s", err)
+		run.Error = err.Error()
+	}
+	run.Finished = c.now()
+	c.mutex.Lock()
+	c.last = &run
+	if run.Error == "" {
+		c.lastSuccessful = &run
+	}
+	c.reconciling = nil
+	c.mutex.Unlock()
+	return run
+}
+
+//Last - the last reconciliation, nil before the first one finished
+func (c *Controller) Last() *Run {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return copyRun(c.last)
+}
+
+//Status - the current and next reconciliation, and the result of the last ones
+func (c *Controller) Status() Status {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	status := Status{
+		Reconciling:       c.reconciling,
+		LastRun:           copyRun(c.last),
+		LastSuccessfulRun: copyRun(c.lastSuccessful),
+		Standby:           c.standby,
+	}
+	if c.reconciling == nil && !c.next.IsZero() {
+		next := c.next
+		status.NextRun = &next
+	}
+	return status
+}
+
+func copyRun(run *Run) *Run {
+	if run == nil {
+		return nil
+	}
+	copied := *run
+	return &copied
+}
+
+func (c *Controller) now() time.Time {
+	if c.Now == nil {
+		return time.Now().UTC()
+	}
+	return c.Now()
+}
+
+//Handler - serves /healthz, which answers ok while the controller process is up, /readyz, which answers ok
+//once the last reconciliation succeeded or while standing by, and /status with the Status as json
+func (c *Controller) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		last, status := c.Last(), c.Status()
+		switch {
+		case status.Standby != "":
+			fmt.Fprintf(w, "standing by: %s\n", status.Standby)
+		case last == nil:
+			http.Error(w, "waiting for the first reconciliation", http.StatusServiceUnavailable)
+		case last.Error != "":
+			http.Error(w, "last reconciliation failed: "+last.Error, http.StatusServiceUnavailable)
+		default:
+			fmt.Fprintln(w, "ok")
+		}
+	})
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(c.Status()); err != nil {
+			lo.G.Errorf("Unable to write status: %s", err)
+		}
+	})
+	return mux
+}
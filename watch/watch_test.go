@@ -0,0 +1,232 @@
+package watch_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotalservices/cf-mgmt/watch"
+)
+
+var _ = Describe("Controller", func() {
+	var (
+		controller *watch.Controller
+		drift      int
+		driftErr   error
+		applied    int
+		applyErr   error
+		now        time.Time
+	)
+
+	BeforeEach(func() {
+		drift, driftErr, applied, applyErr = 0, nil, 0, nil
+		now = time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+		controller = &watch.Controller{
+			Interval: time.Millisecond,
+			DetectDrift: func(context.Context) (int, error) {
+				return drift, driftErr
+			},
+			Apply: func(context.Context) error {
+				applied++
+				return applyErr
+			},
+			Now: func() time.Time {
+				return now
+			},
+		}
+	})
+
+	Context("Reconcile", func() {
+		It("does not apply without drift", func() {
+			run := controller.Reconcile(context.Background())
+			Expect(run).Should(Equal(watch.Run{Started: now, Finished: now}))
+			Expect(applied).Should(Equal(0))
+		})
+
+		It("applies the drift", func() {
+			drift = 3
+			run := controller.Reconcile(context.Background())
+			Expect(run.Drift).Should(Equal(3))
+			Expect(run.Applied).Should(BeTrue())
+			Expect(applied).Should(Equal(1))
+			Expect(controller.Last()).Should(Equal(&run))
+		})
+
+		It("records a failed apply", func() {
+			drift, applyErr = 1, errors.New("token expired")
+			run := controller.Reconcile(context.Background())
+			Expect(run.Applied).Should(BeFalse())
+			Expect(run.Error).Should(Equal("token expired"))
+		})
+
+		It("records a failed drift detection without applying", func() {
+			driftErr = errors.New("api down")
+			run := controller.Reconcile(context.Background())
+			Expect(run.Error).Should(Equal("api down"))
+			Expect(applied).Should(Equal(0))
+		})
+	})
+
+	Context("Run", func() {
+		It("reconciles every interval until cancelled", func() {
+			drift = 1
+			ctx, cancel := context.WithCancel(context.Background())
+			controller.Apply = func(context.Context) error {
+				applied++
+				if applied == 3 {
+					cancel()
+				}
+				return nil
+			}
+			Expect(controller.Run(ctx)).Should(Succeed())
+			Expect(applied).Should(Equal(3))
+		})
+
+		Context("with a lease", func() {
+			var lease *fakeLease
+
+			BeforeEach(func() {
+				lease = &fakeLease{}
+				controller.Lease = lease
+				controller.RenewInterval = time.Millisecond
+			})
+
+			It("stands by while another replica holds the lease", func() {
+				lease.acquireErr = errors.New("cf-mgmt lock is held by [other/1]")
+				ctx, cancel := context.WithCancel(context.Background())
+				controller.DetectDrift = func(context.Context) (int, error) {
+					Fail("a standby replica reconciled")
+					return 0, nil
+				}
+				controller.Now = func() time.Time {
+					if lease.acquired == 2 {
+						cancel()
+					}
+					return now
+				}
+				Expect(controller.Run(ctx)).Should(Succeed())
+				Expect(controller.Last()).Should(BeNil())
+				Expect(controller.Status().Standby).Should(Equal("cf-mgmt lock is held by [other/1]"))
+				Expect(lease.released).Should(Equal(0))
+			})
+
+			It("renews the lease it leads with and releases it on exit", func() {
+				drift = 1
+				ctx, cancel := context.WithCancel(context.Background())
+				controller.Apply = func(context.Context) error {
+					applied++
+					if applied == 2 {
+						cancel()
+					}
+					return nil
+				}
+				Expect(controller.Run(ctx)).Should(Succeed())
+				Expect(lease.acquired).Should(Equal(1))
+				Expect(lease.renewed).Should(BeNumerically(">=", 1))
+				Expect(lease.released).Should(Equal(1))
+			})
+
+			It("stops reconciling once the lease is lost", func() {
+				drift = 1
+				lease.renewErr = errors.New("cf-mgmt lock of [this/1] was lost")
+				ctx, cancel := context.WithCancel(context.Background())
+				controller.Apply = func(leaseCtx context.Context) error {
+					<-watch.LeaseLost(leaseCtx)
+					<-leaseCtx.Done()
+					cancel()
+					return leaseCtx.Err()
+				}
+				Expect(controller.Run(ctx)).Should(Succeed())
+				Expect(controller.Last().Error).Should(Equal("context canceled"))
+				Expect(lease.released).Should(Equal(0))
+			})
+		})
+
+		It("requires an interval", func() {
+			controller.Interval = 0
+			Expect(controller.Run(context.Background())).Should(MatchError("interval must be positive, not 0s"))
+		})
+	})
+
+	Context("Handler", func() {
+		get := func(path string) *httptest.ResponseRecorder {
+			recorder := httptest.NewRecorder()
+			controller.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+			return recorder
+		}
+
+		It("serves healthz", func() {
+			recorder := get("/healthz")
+			Expect(recorder.Code).Should(Equal(http.StatusOK))
+			Expect(recorder.Body.String()).Should(Equal("ok\n"))
+		})
+
+		It("is not ready before the first reconciliation", func() {
+			recorder := get("/readyz")
+			Expect(recorder.Code).Should(Equal(http.StatusServiceUnavailable))
+			Expect(recorder.Body.String()).Should(Equal("waiting for the first reconciliation\n"))
+		})
+
+		It("is ready once a reconciliation succeeded", func() {
+			controller.Reconcile(context.Background())
+			Expect(get("/readyz").Code).Should(Equal(http.StatusOK))
+		})
+
+		It("is not ready after a failed reconciliation", func() {
+			driftErr = errors.New("api down")
+			controller.Reconcile(context.Background())
+			recorder := get("/readyz")
+			Expect(recorder.Code).Should(Equal(http.StatusServiceUnavailable))
+			Expect(recorder.Body.String()).Should(Equal("last reconciliation failed: api down\n"))
+		})
+
+		It("serves the last runs and the next one", func() {
+			controller.Reconcile(context.Background())
+			driftErr = errors.New("api down")
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			Expect(controller.Run(ctx)).Should(Succeed())
+			recorder := get("/status")
+			Expect(recorder.Code).Should(Equal(http.StatusOK))
+			status := watch.Status{}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &status)).Should(Succeed())
+			Expect(status.LastRun.Error).Should(Equal("api down"))
+			Expect(status.LastSuccessfulRun.Error).Should(BeEmpty())
+			Expect(*status.NextRun).Should(Equal(now.Add(time.Millisecond)))
+			Expect(status.Reconciling).Should(BeNil())
+		})
+	})
+})
+
+type fakeLease struct {
+	acquired, renewed, released int
+	acquireErr, renewErr        error
+	mutex                       sync.Mutex
+}
+
+func (l *fakeLease) Acquire() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.acquired++
+	return l.acquireErr
+}
+
+func (l *fakeLease) Renew() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.renewed++
+	return l.renewErr
+}
+
+func (l *fakeLease) Release() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.released++
+	return nil
+}